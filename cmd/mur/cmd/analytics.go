@@ -3,14 +3,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/core/analytics"
+	murstats "github.com/mur-run/mur-core/internal/stats"
 )
 
 var analyticsCmd = &cobra.Command{
@@ -66,7 +67,7 @@ func init() {
 
 func getTracker() *analytics.Tracker {
 	home, _ := os.UserHomeDir()
-	return analytics.NewTracker(filepath.Join(home, ".mur"))
+	return analytics.NewTracker(config.DataDir(home))
 }
 
 func runAnalyticsSummary(_ *cobra.Command, _ []string) error {
@@ -203,7 +204,7 @@ func runAnalyticsCold(_ *cobra.Command, _ []string) error {
 	for _, s := range stats {
 		lastUsed := "never"
 		if !s.LastUsed.IsZero() {
-			lastUsed = s.LastUsed.Format("2006-01-02")
+			lastUsed = s.LastUsed.In(murstats.DisplayLocation()).Format("2006-01-02")
 		}
 		fmt.Printf("  • %-35s last: %s\n", truncateName(s.PatternName, 35), lastUsed)
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 var autoSyncCmd = &cobra.Command{
@@ -195,7 +197,7 @@ const macOSPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 func installMacOSLaunchAgent(intervalMinutes int) error {
 	home, _ := os.UserHomeDir()
 	plistPath := filepath.Join(home, "Library", "LaunchAgents", "run.mur.sync.plist")
-	logPath := filepath.Join(home, ".mur", "sync.log")
+	logPath := filepath.Join(config.StateDir(home), "sync.log")
 
 	// Find mur binary path
 	murPath, err := exec.LookPath("mur")
@@ -248,8 +250,9 @@ func installMacOSLaunchAgent(intervalMinutes int) error {
 	}
 
 	// Unload if already loaded, then load
-	_ = exec.Command("launchctl", "unload", plistPath).Run()
-	if err := exec.Command("launchctl", "load", plistPath).Run(); err != nil {
+	ctx := context.Background()
+	_, _ = execx.Run(ctx, "launchctl", "unload", plistPath)
+	if _, err := execx.Run(ctx, "launchctl", "load", plistPath); err != nil {
 		return fmt.Errorf("failed to load launch agent: %w", err)
 	}
 
@@ -265,7 +268,7 @@ func uninstallMacOSLaunchAgent() error {
 	home, _ := os.UserHomeDir()
 	plistPath := filepath.Join(home, "Library", "LaunchAgents", "run.mur.sync.plist")
 
-	_ = exec.Command("launchctl", "unload", plistPath).Run()
+	_, _ = execx.Run(context.Background(), "launchctl", "unload", plistPath)
 	_ = os.Remove(plistPath)
 
 	fmt.Println("✅ Auto-sync disabled (macOS LaunchAgent removed)")
@@ -281,8 +284,8 @@ func checkMacOSLaunchAgent() {
 		fmt.Printf("  Path: %s\n", plistPath)
 
 		// Check if loaded
-		output, _ := exec.Command("launchctl", "list", "run.mur.sync").Output()
-		if len(output) > 0 {
+		res, _ := execx.Run(context.Background(), "launchctl", "list", "run.mur.sync")
+		if len(res.Stdout) > 0 {
 			fmt.Println("  Status: Running")
 		}
 	} else {
@@ -346,9 +349,10 @@ func installLinuxSystemdTimer(intervalMinutes int) error {
 	serviceFile.Close()
 
 	// Enable and start timer
-	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
-	_ = exec.Command("systemctl", "--user", "enable", "mur-sync.timer").Run()
-	_ = exec.Command("systemctl", "--user", "start", "mur-sync.timer").Run()
+	ctx := context.Background()
+	_, _ = execx.Run(ctx, "systemctl", "--user", "daemon-reload")
+	_, _ = execx.Run(ctx, "systemctl", "--user", "enable", "mur-sync.timer")
+	_, _ = execx.Run(ctx, "systemctl", "--user", "start", "mur-sync.timer")
 
 	fmt.Println("✅ Auto-sync enabled (systemd user timer)")
 	fmt.Printf("   Interval: Every %d minutes\n", intervalMinutes)
@@ -363,19 +367,20 @@ func uninstallLinuxSystemdTimer() error {
 	timerPath := filepath.Join(systemdDir, "mur-sync.timer")
 	servicePath := filepath.Join(systemdDir, "mur-sync.service")
 
-	_ = exec.Command("systemctl", "--user", "stop", "mur-sync.timer").Run()
-	_ = exec.Command("systemctl", "--user", "disable", "mur-sync.timer").Run()
+	ctx := context.Background()
+	_, _ = execx.Run(ctx, "systemctl", "--user", "stop", "mur-sync.timer")
+	_, _ = execx.Run(ctx, "systemctl", "--user", "disable", "mur-sync.timer")
 	os.Remove(timerPath)
 	os.Remove(servicePath)
-	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	_, _ = execx.Run(ctx, "systemctl", "--user", "daemon-reload")
 
 	fmt.Println("✅ Auto-sync disabled (systemd timer removed)")
 	return nil
 }
 
 func checkLinuxSystemdTimer() {
-	output, err := exec.Command("systemctl", "--user", "is-active", "mur-sync.timer").Output()
-	status := strings.TrimSpace(string(output))
+	res, err := execx.Run(context.Background(), "systemctl", "--user", "is-active", "mur-sync.timer")
+	status := strings.TrimSpace(res.Stdout)
 
 	if err == nil && status == "active" {
 		fmt.Println("systemd timer: ✅ Active")
@@ -398,10 +403,11 @@ func installWindowsTaskScheduler(intervalMinutes int) error {
 	taskName := "MUR_Sync"
 
 	// Delete existing task if any
-	_ = exec.Command("schtasks", "/delete", "/tn", taskName, "/f").Run()
+	ctx := context.Background()
+	_, _ = execx.Run(ctx, "schtasks", "/delete", "/tn", taskName, "/f")
 
 	// Create new task
-	cmd := exec.Command("schtasks", "/create",
+	res, err := execx.Run(ctx, "schtasks", "/create",
 		"/tn", taskName,
 		"/tr", fmt.Sprintf(`"%s" sync --quiet`, murPath),
 		"/sc", "minute",
@@ -409,9 +415,8 @@ func installWindowsTaskScheduler(intervalMinutes int) error {
 		"/ru", os.Getenv("USERNAME"),
 		"/f",
 	)
-
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to create task: %s", output)
+	if err != nil {
+		return fmt.Errorf("failed to create task: %s", res.Output())
 	}
 
 	fmt.Println("✅ Auto-sync enabled (Windows Task Scheduler)")
@@ -423,7 +428,7 @@ func installWindowsTaskScheduler(intervalMinutes int) error {
 
 func uninstallWindowsTaskScheduler() error {
 	taskName := "MUR_Sync"
-	_ = exec.Command("schtasks", "/delete", "/tn", taskName, "/f").Run()
+	_, _ = execx.Run(context.Background(), "schtasks", "/delete", "/tn", taskName, "/f")
 
 	fmt.Println("✅ Auto-sync disabled (Windows task removed)")
 	return nil
@@ -431,9 +436,9 @@ func uninstallWindowsTaskScheduler() error {
 
 func checkWindowsTaskScheduler() {
 	taskName := "MUR_Sync"
-	output, err := exec.Command("schtasks", "/query", "/tn", taskName).CombinedOutput()
+	res, err := execx.Run(context.Background(), "schtasks", "/query", "/tn", taskName)
 
-	if err == nil && strings.Contains(string(output), taskName) {
+	if err == nil && strings.Contains(res.Output(), taskName) {
 		fmt.Println("Task Scheduler: ✅ Task exists")
 	} else {
 		fmt.Println("Task Scheduler: ❌ Task not found")
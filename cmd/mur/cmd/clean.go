@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -49,7 +50,7 @@ func runClean(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	if _, err := os.Stat(murDir); os.IsNotExist(err) {
 		fmt.Println("Nothing to clean - ~/.mur doesn't exist")
 		return nil
@@ -156,7 +157,7 @@ func runClean(cmd *cobra.Command, args []string) error {
 		name string
 		path string
 	}{
-		{"Claude skills", filepath.Join(home, ".claude", "skills", "mur")},
+		{"Claude skills", filepath.Join(config.ClaudeDir(home), "skills", "mur")},
 		{"Gemini skills", filepath.Join(home, ".gemini", "skills", "mur")},
 		{"Continue rules", filepath.Join(home, ".continue", "rules", "mur")},
 		{"Cursor rules", filepath.Join(home, ".cursor", "rules", "mur")},
@@ -269,7 +270,7 @@ func formatSize(bytes int64) string {
 func countOldPatternDirs(home string) int {
 	count := 0
 	skillsDirs := []string{
-		filepath.Join(home, ".claude", "skills"),
+		filepath.Join(config.ClaudeDir(home), "skills"),
 		filepath.Join(home, ".gemini", "skills"),
 		filepath.Join(home, ".augment", "skills"),
 		filepath.Join(home, ".opencode", "skills"),
@@ -7,11 +7,11 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"github.com/mur-run/mur-core/internal/cloud"
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/output"
 )
 
 var cloudCmd = &cobra.Command{
@@ -31,12 +31,16 @@ var cloudTeamsCmd = &cobra.Command{
 	Use:   "teams",
 	Short: "List your teams",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		out := newPrinter(cmd)
 		client, err := getCloudClient(cmd)
 		if err != nil {
 			return err
 		}
 
 		if !client.AuthStore().IsLoggedIn() {
+			if out.JSON() {
+				return fmt.Errorf("not logged in; run 'mur login' first")
+			}
 			fmt.Println("Not logged in. Run 'mur login' first.")
 			return nil
 		}
@@ -46,6 +50,25 @@ var cloudTeamsCmd = &cobra.Command{
 			return fmt.Errorf("failed to list teams: %w", err)
 		}
 
+		// Get active team from config
+		cfg, _ := config.Load()
+		activeTeam := ""
+		if cfg != nil {
+			activeTeam = cfg.Server.Team
+		}
+
+		if out.JSON() {
+			type teamJSON struct {
+				cloud.Team
+				Active bool `json:"active"`
+			}
+			result := make([]teamJSON, 0, len(teams))
+			for _, t := range teams {
+				result = append(result, teamJSON{Team: t, Active: t.Slug == activeTeam || t.ID == activeTeam})
+			}
+			return out.WriteJSON(result)
+		}
+
 		if len(teams) == 0 {
 			fmt.Println("No teams found.")
 			fmt.Println("")
@@ -54,13 +77,6 @@ var cloudTeamsCmd = &cobra.Command{
 			return nil
 		}
 
-		// Get active team from config
-		cfg, _ := config.Load()
-		activeTeam := ""
-		if cfg != nil {
-			activeTeam = cfg.Server.Team
-		}
-
 		fmt.Println("Your Teams")
 		fmt.Println("==========")
 		fmt.Println("")
@@ -147,15 +163,30 @@ var cloudSyncCmd = &cobra.Command{
 	Short: "Sync patterns with server",
 	Long: `Bidirectional sync between local patterns and mur-server.
 
+Only patterns changed since the last sync are pushed. A pattern changed
+both here and on the server is merged field by field (name, description,
+content, tags, applies) against the version both last agreed on; only
+when both changed the same field do you choose, or pass --force-local or
+--force-server.
+
 Examples:
   mur cloud sync              # Sync with active team
   mur cloud sync --team=slug  # Sync with specific team
-  mur cloud sync --dry-run    # Show what would sync`,
+  mur cloud sync --dry-run    # Show what would sync
+
+With --porcelain, prints one line per step (counts are what would change
+with --dry-run):
+  version<TAB>local<TAB>server
+  pull<TAB>created<TAB>updated<TAB>deleted
+  merge<TAB>patterns
+  push<TAB>patterns
+  conflict<TAB>pattern   (then fails; rerun with --force-local or --force-server)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		teamSlug, _ := cmd.Flags().GetString("team")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		forceLocal, _ := cmd.Flags().GetBool("force-local")
 		forceServer, _ := cmd.Flags().GetBool("force-server")
+		out := newPrinter(cmd)
 
 		client, err := getCloudClient(cmd)
 		if err != nil {
@@ -163,7 +194,7 @@ Examples:
 		}
 
 		if !client.AuthStore().IsLoggedIn() {
-			fmt.Println("Not logged in. Run 'mur login' first.")
+			out.Warnf("Not logged in. Run 'mur login' first.\n")
 			return nil
 		}
 
@@ -199,32 +230,30 @@ Examples:
 
 		// Check team subscription status
 		if !team.CanSync {
-			fmt.Println("❌ Team subscription expired")
-			fmt.Println("")
-			fmt.Println("Cloud sync is disabled because the team subscription has expired.")
-			fmt.Println("Contact your team owner to renew the subscription.")
-			fmt.Println("")
-			fmt.Println("You can still use local patterns and sync to CLIs.")
+			out.Println("❌ Team subscription expired")
+			out.Println("")
+			out.Println("Cloud sync is disabled because the team subscription has expired.")
+			out.Println("Contact your team owner to renew the subscription.")
+			out.Println("")
+			out.Println("You can still use local patterns and sync to CLIs.")
 			return fmt.Errorf("team subscription expired - sync disabled")
 		}
 
 		teamID := team.ID
-		fmt.Printf("Syncing with team: %s\n", teamSlug)
-		fmt.Println("")
+		out.Printf("Syncing with team: %s\n", teamSlug)
+		out.Println("")
 
-		// Load local patterns
+		// Team policy goes first so enforced settings hold for this sync
+		syncTeamPolicy(out, client, teamID, dryRun)
+
+		// Load local patterns and what the last sync left behind
 		store, err := pattern.DefaultStore()
 		if err != nil {
 			return fmt.Errorf("failed to load patterns: %w", err)
 		}
-
-		localPatterns, err := store.List()
-		if err != nil {
-			return fmt.Errorf("failed to list local patterns: %w", err)
-		}
-
-		// Get local version (stored in a sync state file)
-		localVersion := getLocalSyncVersion(teamSlug)
+		state, statePath := loadSyncState()
+		ts := state.Team(teamSlug)
+		localVersion := ts.Version
 
 		// Check sync status
 		status, err := client.GetSyncStatus(teamID, localVersion)
@@ -232,160 +261,57 @@ Examples:
 			return fmt.Errorf("failed to get sync status: %w", err)
 		}
 
-		fmt.Printf("Local version:  %d\n", localVersion)
-		fmt.Printf("Server version: %d\n", status.ServerVersion)
-		fmt.Println("")
+		out.Printf("Local version:  %d\n", localVersion)
+		out.Printf("Server version: %d\n", status.ServerVersion)
+		out.Println("")
+		out.Record("version", fmt.Sprint(localVersion), fmt.Sprint(status.ServerVersion))
 
-		// Pull changes from server
+		// Pull changes from server, merging them with local edits
+		skipped := make(map[string]bool)
 		if status.HasUpdates {
-			fmt.Println("⬇️  Pulling from server...")
+			out.Println("⬇️  Pulling from server...")
 
-			pullResp, err := client.Pull(teamID, localVersion)
+			counts, conflicts, err := pullPaged(out, client, teamID, store, state, statePath, ts, localVersion, dryRun, false)
 			if err != nil {
-				return fmt.Errorf("failed to pull: %w", err)
+				return err
 			}
 
-			created, updated, deleted := 0, 0, 0
-			for _, p := range pullResp.Patterns {
-				exists := store.Exists(p.Name)
-
-				if dryRun {
-					if p.Deleted {
-						fmt.Printf("  Would delete: %s\n", p.Name)
-						deleted++
-					} else if exists {
-						fmt.Printf("  Would update: %s\n", p.Name)
-						updated++
-					} else {
-						fmt.Printf("  Would create: %s\n", p.Name)
-						created++
-					}
-					continue
-				}
+			out.Printf("  %s %d created, %d updated, %d merged, %d deleted\n", out.Green("✓"), counts.created, counts.updated, counts.merged, counts.deleted)
+			out.Record("pull", fmt.Sprint(counts.created), fmt.Sprint(counts.updated), fmt.Sprint(counts.deleted))
+			out.Record("merge", fmt.Sprint(counts.merged))
+			out.Println("")
+			printPulledChanges(out, counts.changes)
 
-				if p.Deleted {
-					// Delete local pattern
-					if err := store.Delete(p.Name); err == nil {
-						deleted++
-					}
-				} else {
-					// Create or update
-					localP := convertCloudPattern(&p)
-					if exists {
-						if err := store.Update(localP); err == nil {
-							updated++
-						}
-					} else {
-						if err := store.Create(localP); err == nil {
-							created++
-						}
-					}
+			if len(conflicts) > 0 && !dryRun {
+				skipped, err = resolveSyncConflicts(out, store, ts, conflicts, forceLocal, forceServer)
+				_ = state.Save(statePath)
+				if err != nil {
+					return err
 				}
 			}
-
-			if !dryRun {
-				saveLocalSyncVersion(teamSlug, pullResp.Version)
-			}
-
-			fmt.Printf("  ✓ %d created, %d updated, %d deleted\n", created, updated, deleted)
-			fmt.Println("")
 		} else {
-			fmt.Println("⬇️  No updates from server")
-			fmt.Println("")
+			out.Println("⬇️  No updates from server")
+			out.Println("")
+			out.Record("pull", "0", "0", "0")
+			out.Record("merge", "0")
 		}
 
-		// Push local changes
-		fmt.Println("⬆️  Pushing to server...")
-
-		changes := make([]cloud.SyncChange, 0) // Initialize as empty slice, not nil
-		for i := range localPatterns {
-			// For now, push all as creates/updates
-			// A proper implementation would track local changes
-			cloudP := convertLocalPattern(&localPatterns[i])
-			changes = append(changes, cloud.SyncChange{
-				Action:  "create", // Server will handle upsert
-				Pattern: cloudP,
-			})
+		// Push local changes since the last sync
+		out.Println("⬆️  Pushing to server...")
+		err = pushLocalChanges(out, client, teamID, store, ts, skipped, dryRun, forceLocal, forceServer)
+		if !dryRun {
+			_ = state.Save(statePath)
+		}
+		if err != nil {
+			return err
 		}
 
-		if len(changes) == 0 {
-			fmt.Println("  No local changes to push")
-		} else if dryRun {
-			fmt.Printf("  Would push %d patterns\n", len(changes))
-		} else {
-			pushReq := cloud.PushRequest{
-				BaseVersion: localVersion,
-				Changes:     changes,
-			}
-
-			pushResp, err := client.Push(teamID, pushReq)
-			if err != nil {
-				return fmt.Errorf("failed to push: %w", err)
-			}
-
-			if !pushResp.OK {
-				if forceLocal {
-					fmt.Printf("  ⚠️  %d conflict(s) detected — forcing local versions...\n", len(pushResp.Conflicts))
-					forcePushReq := cloud.PushRequest{
-						BaseVersion: localVersion,
-						Changes:     changes,
-						ForceLocal:  true,
-					}
-					forceResp, err := client.Push(teamID, forcePushReq)
-					if err != nil {
-						return fmt.Errorf("force push failed: %w", err)
-					}
-					if forceResp.OK {
-						saveLocalSyncVersion(teamSlug, forceResp.Version)
-						fmt.Printf("  ✓ %d patterns force-pushed\n", len(changes))
-					} else {
-						return fmt.Errorf("force push rejected by server")
-					}
-				} else if forceServer {
-					// Accept server versions - pull them
-					fmt.Println("  --force-server: Accepting server versions...")
-					// Pull and overwrite local
-				} else {
-					// Interactive conflict resolution
-					resolutions, err := ResolveConflictsInteractive(pushResp.Conflicts)
-					if err != nil {
-						return fmt.Errorf("conflict resolution cancelled: %w", err)
-					}
-
-					keepServer, keepLocal, skipped := ApplyResolutions(resolutions)
-					fmt.Printf("\n📊 Resolution summary: %d server, %d local, %d skipped\n", keepServer, keepLocal, skipped)
-
-					// Apply resolutions
-					if keepServer > 0 {
-						// Pull server versions for patterns marked as "keep server"
-						fmt.Println("Applying server versions...")
-						for _, c := range pushResp.Conflicts {
-							if resolutions[c.PatternName] == ResolutionKeepServer && c.ServerVersion != nil {
-								localP := convertCloudPattern(c.ServerVersion)
-								if store.Exists(localP.Name) {
-									_ = store.Update(localP)
-								} else {
-									_ = store.Create(localP)
-								}
-							}
-						}
-					}
-
-					if keepLocal > 0 {
-						// Need to force push local versions
-						fmt.Println("Note: Keeping local versions requires --force-local flag")
-						fmt.Println("Run: mur cloud sync --force-local")
-					}
-				}
-				return nil
-			}
-
-			saveLocalSyncVersion(teamSlug, pushResp.Version)
-			fmt.Printf("  ✓ %d patterns pushed\n", len(changes))
+		if !dryRun {
+			reportCoverageAfterSync(out, client, teamID, teamSlug)
 		}
 
-		fmt.Println("")
-		fmt.Println("✅ Sync complete")
+		out.Println("")
+		out.Println("✅ Sync complete")
 
 		return nil
 	},
@@ -433,43 +359,471 @@ func resolveActiveTeam(cfg *config.Config, client *cloud.Client) (string, error)
 	return "", fmt.Errorf("multiple teams found. Select one with: mur cloud select <team-slug>")
 }
 
-func getLocalSyncVersion(teamSlug string) int64 {
+// syncStatePath returns where the sync state is kept.
+func syncStatePath() string {
 	home, _ := os.UserHomeDir()
-	path := filepath.Join(home, ".mur", "sync-state.yaml")
+	return filepath.Join(config.StateDir(home), "sync-state.yaml")
+}
 
-	data, err := os.ReadFile(path)
+// loadSyncState reads the sync state and returns it with its path. An
+// unreadable state starts afresh: every pattern is then pushed as new,
+// which the server treats as an upsert.
+func loadSyncState() (*cloud.SyncState, string) {
+	path := syncStatePath()
+	state, err := cloud.LoadSyncState(path)
 	if err != nil {
-		return 0
+		fmt.Fprintf(os.Stderr, "⚠ %v; starting sync state afresh\n", err)
+		state = &cloud.SyncState{Teams: make(map[string]*cloud.TeamSyncState)}
+	}
+	return state, path
+}
+
+func getLocalSyncVersion(teamSlug string) int64 {
+	state, _ := loadSyncState()
+	if ts, ok := state.Teams[teamSlug]; ok && ts != nil {
+		return ts.Version
 	}
+	return 0
+}
+
+// pullCounts tallies what applying a pull did.
+type pullCounts struct {
+	created, updated, merged, deleted int
+	changes                           []pulledChanges // changelog entries new to us
+}
 
-	var state map[string]int64
-	if err := yaml.Unmarshal(data, &state); err != nil {
-		return 0
+// pullPaged pulls the changes since a version from the server a page at
+// a time, applying each page and checkpointing it in the sync state
+// before fetching the next, so an interrupted pull resumes after the last
+// page applied instead of starting over. The team version moves to the
+// server's once the last page is in. Conflicts are returned for the
+// caller to resolve; those of pages before an interruption are left to
+// the next push, which merges them against the server's version.
+func pullPaged(out *output.Printer, client *cloud.Client, teamID string, store *pattern.Store, state *cloud.SyncState, statePath string, ts *cloud.TeamSyncState, since int64, dryRun, overwrite bool) (pullCounts, []cloud.Conflict, error) {
+	var counts pullCounts
+	var conflicts []cloud.Conflict
+	cursor, applied := ts.ResumeCursor(since)
+	if cursor != "" {
+		out.Printf("  Resuming interrupted pull (%d patterns already applied)\n", applied)
 	}
+	for {
+		page, err := client.PullPage(teamID, since, cursor, cloud.DefaultPullPageSize)
+		if err != nil {
+			return counts, conflicts, fmt.Errorf("failed to pull: %w", err)
+		}
 
-	return state[teamSlug]
+		c, pageConflicts := applyPull(out, store, ts, page.Patterns, dryRun, overwrite)
+		counts.created += c.created
+		counts.updated += c.updated
+		counts.merged += c.merged
+		counts.deleted += c.deleted
+		counts.changes = append(counts.changes, c.changes...)
+		conflicts = append(conflicts, pageConflicts...)
+
+		next := page.NextCursor
+		if next == cursor {
+			next = "" // A server ignoring the cursor sends everything at once
+		}
+		if !dryRun {
+			ts.Checkpoint(since, next, len(page.Patterns), page.Version)
+			if err := state.Save(statePath); err != nil {
+				return counts, conflicts, fmt.Errorf("failed to save sync state: %w", err)
+			}
+		}
+		if next == "" {
+			return counts, conflicts, nil
+		}
+		cursor = next
+	}
 }
 
-func saveLocalSyncVersion(teamSlug string, version int64) {
-	home, _ := os.UserHomeDir()
-	path := filepath.Join(home, ".mur", "sync-state.yaml")
+// applyPull applies patterns pulled from the server. Patterns unchanged
+// locally since the last sync take the server's version; patterns changed
+// on both sides are merged field by field, and the ones both sides
+// changed in the same field are returned as conflicts, left as they are.
+// With overwrite the server's version always wins.
+func applyPull(out *output.Printer, store *pattern.Store, ts *cloud.TeamSyncState, pulled []cloud.Pattern, dryRun, overwrite bool) (pullCounts, []cloud.Conflict) {
+	var counts pullCounts
+	var candidates []cloud.Conflict
+	kept := keptDeleted(store)
+	for i := range pulled {
+		p := &pulled[i]
+		local := localPatternFor(store, p)
+		if !p.Deleted && kept[p.Name] {
+			out.Printf("  Kept deleted: %s (in trash)\n", p.Name)
+			continue
+		}
 
-	state := make(map[string]int64)
+		var localP *cloud.Pattern
+		if local != nil {
+			localP = convertLocalPattern(local)
+			if !p.Deleted {
+				if unseen := cloud.UnseenChanges(localP.Changelog, p.Changelog); len(unseen) > 0 {
+					counts.changes = append(counts.changes, pulledChanges{name: p.Name, entries: unseen})
+				}
+			}
+		}
+
+		if p.Deleted {
+			switch {
+			case local == nil:
+				if !dryRun {
+					ts.DropBase(p)
+				}
+			case !overwrite && ts.Base(p) != nil && ts.Changed(localP):
+				// Edited here since the last sync: keep it, and push it again
+				out.Printf("  Kept edited: %s (deleted on the server)\n", local.Name)
+				if !dryRun {
+					ts.DropBase(p)
+				}
+			case dryRun:
+				out.Printf("  Would delete: %s\n", p.Name)
+				counts.deleted++
+			default:
+				if err := store.DeleteRemote(local.Name, "deleted on the team server"); err == nil {
+					ts.DropBase(p)
+					counts.deleted++
+				}
+			}
+			continue
+		}
 
-	// Load existing state
-	data, err := os.ReadFile(path)
-	if err == nil {
-		_ = yaml.Unmarshal(data, &state)
+		switch {
+		case local == nil:
+			if dryRun {
+				out.Printf("  Would create: %s\n", p.Name)
+				counts.created++
+			} else if err := applyPulledPattern(store, p, nil); err == nil {
+				ts.SetBase(p, p.Version)
+				counts.created++
+			}
+		case cloud.PatternHash(localP) == cloud.PatternHash(p):
+			// Already the same on both sides
+			if !dryRun {
+				ts.SetBase(p, p.Version)
+			}
+		case overwrite || !ts.Changed(localP):
+			if dryRun {
+				out.Printf("  Would update: %s\n", p.Name)
+				counts.updated++
+			} else if err := applyPulledPattern(store, p, local); err == nil {
+				ts.SetBase(p, p.Version)
+				counts.updated++
+			}
+		default:
+			candidates = append(candidates, cloud.Conflict{
+				PatternID:     p.ID,
+				PatternName:   p.Name,
+				ServerVersion: p,
+				ClientVersion: localP,
+			})
+		}
+	}
+
+	merged, conflicts := mergeConflicts(out, store, ts, candidates, dryRun)
+	counts.merged = merged
+	return counts, conflicts
+}
+
+// mergeConflicts 3-way merges patterns changed both locally and on the
+// server since the last sync, against the version both last agreed on.
+// Merged patterns are saved locally, to be pushed as updates of the
+// server's version. It returns how many merged and the true conflicts.
+func mergeConflicts(out *output.Printer, store *pattern.Store, ts *cloud.TeamSyncState, conflicts []cloud.Conflict, dryRun bool) (int, []cloud.Conflict) {
+	merged := 0
+	var rest []cloud.Conflict
+	for _, c := range conflicts {
+		server := c.ServerVersion
+		if server == nil {
+			rest = append(rest, c)
+			continue
+		}
+		local := localPatternFor(store, server)
+		if local == nil {
+			rest = append(rest, c)
+			continue
+		}
+		localP := convertLocalPattern(local)
+		if c.ClientVersion == nil {
+			c.ClientVersion = localP
+		}
+
+		// Both sides already agree, e.g. the first sync of a pattern
+		// created on each: nothing to merge
+		if cloud.PatternHash(localP) == cloud.PatternHash(server) {
+			if !dryRun {
+				ts.SetBase(server, server.Version)
+			}
+			continue
+		}
+
+		m, fields := cloud.Merge3(ts.Base(server), localP, server)
+		if m == nil {
+			out.Printf("  Conflict: %s (%s changed on both sides)\n", c.PatternName, strings.Join(fields, ", "))
+			rest = append(rest, c)
+			continue
+		}
+		if dryRun {
+			out.Printf("  Would merge: %s\n", c.PatternName)
+			merged++
+			continue
+		}
+		if err := applyPulledPattern(store, m, local); err != nil {
+			rest = append(rest, c)
+			continue
+		}
+		ts.SetBase(server, server.Version)
+		out.Printf("  Merged: %s\n", c.PatternName)
+		merged++
 	}
+	return merged, rest
+}
 
-	state[teamSlug] = version
+// resolveSyncConflicts settles conflicts a merge couldn't: with
+// forceServer the server's version wins, with forceLocal the local one
+// (pushed as an update of the server's), otherwise the user chooses. It
+// returns the names of the patterns left unresolved, which aren't pushed.
+func resolveSyncConflicts(out *output.Printer, store *pattern.Store, ts *cloud.TeamSyncState, conflicts []cloud.Conflict, forceLocal, forceServer bool) (map[string]bool, error) {
+	resolutions := make(map[string]ConflictResolution)
+	switch {
+	case forceServer:
+		out.Printf("  --force-server: accepting server versions of %d pattern(s)\n", len(conflicts))
+		for _, c := range conflicts {
+			resolutions[c.PatternName] = ResolutionKeepServer
+		}
+	case forceLocal:
+		out.Printf("  --force-local: keeping local versions of %d pattern(s)\n", len(conflicts))
+		for _, c := range conflicts {
+			resolutions[c.PatternName] = ResolutionKeepLocal
+		}
+	case out.Porcelain():
+		// Scripts can't answer the resolution prompts
+		for _, c := range conflicts {
+			out.Record("conflict", c.PatternName)
+		}
+		return nil, fmt.Errorf("%d conflict(s); rerun with --force-local or --force-server", len(conflicts))
+	default:
+		var err error
+		resolutions, err = ResolveConflictsInteractive(conflicts)
+		if err != nil {
+			return nil, fmt.Errorf("conflict resolution cancelled: %w", err)
+		}
+		keepServer, keepLocal, skipped := ApplyResolutions(resolutions)
+		out.Printf("\n📊 Resolution summary: %d server, %d local, %d skipped\n", keepServer, keepLocal, skipped)
+	}
 
-	data, _ = yaml.Marshal(state)
-	_ = os.WriteFile(path, data, 0644)
+	skipped := make(map[string]bool)
+	for _, c := range conflicts {
+		server := c.ServerVersion
+		if server == nil {
+			skipped[c.PatternName] = true
+			continue
+		}
+		switch resolutions[c.PatternName] {
+		case ResolutionKeepServer:
+			if err := applyPulledPattern(store, server, localPatternFor(store, server)); err == nil {
+				ts.SetBase(server, server.Version)
+			}
+		case ResolutionKeepLocal:
+			ts.SetBase(server, server.Version)
+		default:
+			skipped[c.PatternName] = true
+		}
+	}
+	return skipped, nil
+}
+
+// localChanges returns the changeset for the local patterns changed since
+// the last sync, less skipped ones, plus pending deletions and their
+// names.
+func localChanges(store *pattern.Store, ts *cloud.TeamSyncState, skipped map[string]bool) ([]cloud.SyncChange, []string, error) {
+	localPatterns, err := store.List()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list local patterns: %w", err)
+	}
+	var local []*cloud.Pattern
+	for i := range localPatterns {
+		if !skipped[localPatterns[i].Name] {
+			local = append(local, convertLocalPattern(&localPatterns[i]))
+		}
+	}
+	changes := ts.Changes(local)
+	deletions, deletedNames := deletionChanges(store)
+	return append(changes, deletions...), deletedNames, nil
+}
+
+// pushLocalChanges pushes the local changes since the last sync. If the
+// server changed the same patterns meanwhile, they are merged and pushed
+// again; only true conflicts need --force-local, --force-server, or the
+// resolver.
+func pushLocalChanges(out *output.Printer, client *cloud.Client, teamID string, store *pattern.Store, ts *cloud.TeamSyncState, skipped map[string]bool, dryRun, forceLocal, forceServer bool) error {
+	if skipped == nil {
+		skipped = make(map[string]bool)
+	}
+	changes, deletedNames, err := localChanges(store, ts, skipped)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		out.Println("  No local changes to push")
+		out.Record("push", "0")
+		return nil
+	}
+	if dryRun {
+		for _, c := range changes {
+			out.Printf("  Would %s: %s\n", c.Action, c.Pattern.Name)
+		}
+		out.Printf("  Would push %d patterns\n", len(changes))
+		out.Record("push", fmt.Sprint(len(changes)))
+		return nil
+	}
+
+	logged := stampChangelog(changes, ts)
+	pushResp, err := client.Push(teamID, cloud.PushRequest{BaseVersion: ts.Version, Changes: changes})
+	if err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	if !pushResp.OK && !forceLocal {
+		// Changed on the server since the pull: merge, settle the rest, and push again
+		out.Printf("  ⚠️  %d pattern(s) changed on the server meanwhile — merging...\n", len(pushResp.Conflicts))
+		_, conflicts := mergeConflicts(out, store, ts, pushResp.Conflicts, false)
+		if len(conflicts) > 0 {
+			more, err := resolveSyncConflicts(out, store, ts, conflicts, false, forceServer)
+			if err != nil {
+				return err
+			}
+			for name := range more {
+				skipped[name] = true
+			}
+		}
+		if changes, deletedNames, err = localChanges(store, ts, skipped); err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			out.Println("  No local changes left to push")
+			out.Record("push", "0")
+			return nil
+		}
+		logged = stampChangelog(changes, ts)
+		if pushResp, err = client.Push(teamID, cloud.PushRequest{BaseVersion: ts.Version, Changes: changes}); err != nil {
+			return fmt.Errorf("failed to push: %w", err)
+		}
+	}
+
+	if !pushResp.OK {
+		if !forceLocal {
+			for _, c := range pushResp.Conflicts {
+				out.Record("conflict", c.PatternName)
+			}
+			return fmt.Errorf("%d conflict(s) remain; rerun with --force-local or --force-server", len(pushResp.Conflicts))
+		}
+		out.Printf("  ⚠️  %d conflict(s) detected — forcing local versions...\n", len(pushResp.Conflicts))
+		pushResp, err = client.Push(teamID, cloud.PushRequest{BaseVersion: ts.Version, Changes: changes, ForceLocal: true})
+		if err != nil {
+			return fmt.Errorf("force push failed: %w", err)
+		}
+		if !pushResp.OK {
+			return fmt.Errorf("force push rejected by server")
+		}
+	}
+
+	for _, c := range changes {
+		if c.Action == "delete" {
+			ts.DropBase(c.Pattern)
+		} else {
+			ts.SetBase(c.Pattern, pushResp.Version)
+		}
+	}
+	ts.Version = pushResp.Version
+	for name, e := range logged {
+		_ = store.AddChange(name, pattern.ChangeEntry(e))
+	}
+	_ = store.MarkPropagated(pattern.PropagateCloud, deletedNames)
+	out.Printf("  %s %d patterns pushed\n", out.Green("✓"), len(changes))
+	out.Record("push", fmt.Sprint(len(changes)))
+	return nil
+}
+
+// deletionChanges returns delete changes for purged patterns whose
+// deletion hasn't reached the server yet, and their names. Patterns still
+// in the trash aren't deleted remotely.
+func deletionChanges(store *pattern.Store) ([]cloud.SyncChange, []string) {
+	pending, err := store.PendingDeletions(pattern.PropagateCloud)
+	if err != nil {
+		return nil, nil
+	}
+	var changes []cloud.SyncChange
+	var names []string
+	for _, t := range pending {
+		changes = append(changes, cloud.SyncChange{
+			Action:  "delete",
+			ID:      t.PatternID,
+			Pattern: &cloud.Pattern{ID: t.PatternID, Name: t.Name, Deleted: true},
+		})
+		names = append(names, t.Name)
+	}
+	return changes, names
+}
+
+// keptDeleted returns the names a pull must not bring back: patterns
+// deleted locally that are in the trash, and purged ones whose deletion
+// hasn't been pushed yet.
+func keptDeleted(store *pattern.Store) map[string]bool {
+	kept := make(map[string]bool)
+	trash, _ := store.Trash()
+	for _, e := range trash {
+		if !e.Remote {
+			kept[e.Name] = true
+		}
+	}
+	pending, _ := store.PendingDeletions(pattern.PropagateCloud)
+	for _, t := range pending {
+		kept[t.Name] = true
+	}
+	return kept
+}
+
+// localPatternFor returns the local copy of a pulled pattern: the pattern
+// with its ID, or, for patterns pushed before IDs were sent, its name.
+func localPatternFor(store *pattern.Store, p *cloud.Pattern) *pattern.Pattern {
+	if p.ID != "" {
+		if local, err := store.GetByID(p.ID); err == nil {
+			return local
+		}
+	}
+	if local, err := store.Get(p.Name); err == nil {
+		return local
+	}
+	return nil
+}
+
+// applyPulledPattern creates or updates the local copy of a pulled
+// pattern. A pattern renamed on the server is renamed locally too; if its
+// new name is taken here, it keeps its local name.
+func applyPulledPattern(store *pattern.Store, p *cloud.Pattern, local *pattern.Pattern) error {
+	localP := convertCloudPattern(p)
+	if local == nil {
+		return store.Create(localP)
+	}
+	if local.ID != "" {
+		localP.ID = local.ID
+	}
+	if len(localP.Changelog) == 0 {
+		// Servers without changelogs send none
+		localP.Changelog = local.Changelog
+	}
+	if local.Name != localP.Name {
+		if _, err := store.Rename(local.Name, localP.Name); err != nil {
+			localP.Name = local.Name
+		}
+	}
+	return store.Update(localP)
 }
 
 func convertCloudPattern(p *cloud.Pattern) *pattern.Pattern {
 	local := &pattern.Pattern{
+		ID:          p.ID,
 		Name:        p.Name,
 		Description: p.Description,
 		Content:     p.Content,
@@ -483,6 +837,9 @@ func convertCloudPattern(p *cloud.Pattern) *pattern.Pattern {
 	}
 	local.Version = p.PatternVersion
 	local.EmbeddingHash = p.EmbeddingHash
+	for _, e := range p.Changelog {
+		local.Changelog = append(local.Changelog, pattern.ChangeEntry(e))
+	}
 
 	// Convert tags
 	if p.Tags != nil {
@@ -518,6 +875,7 @@ func convertCloudPattern(p *cloud.Pattern) *pattern.Pattern {
 
 func convertLocalPattern(p *pattern.Pattern) *cloud.Pattern {
 	cp := &cloud.Pattern{
+		ID:          p.ID,
 		Name:        p.Name,
 		Description: p.Description,
 		Content:     strings.TrimSpace(p.Content),
@@ -531,6 +889,9 @@ func convertLocalPattern(p *pattern.Pattern) *cloud.Pattern {
 	if cp.SchemaVersion == 0 {
 		cp.SchemaVersion = 2
 	}
+	for _, e := range p.Changelog {
+		cp.Changelog = append(cp.Changelog, cloud.ChangelogEntry(e))
+	}
 
 	// Convert tags
 	if len(p.Tags.Confirmed) > 0 {
@@ -610,76 +971,24 @@ Examples:
 			return fmt.Errorf("team not found: %s", teamSlug)
 		}
 
-		fmt.Printf("Pushing to team: %s\n", teamSlug)
-		fmt.Println("")
+		out := newPrinter(cmd)
+		out.Printf("Pushing to team: %s\n", teamSlug)
+		out.Println("")
 
-		// Load local patterns
 		store, err := pattern.DefaultStore()
 		if err != nil {
 			return fmt.Errorf("failed to load patterns: %w", err)
 		}
+		state, statePath := loadSyncState()
+		ts := state.Team(teamSlug)
 
-		localPatterns, err := store.List()
-		if err != nil {
-			return fmt.Errorf("failed to list local patterns: %w", err)
-		}
-
-		localVersion := getLocalSyncVersion(teamSlug)
-
-		changes := make([]cloud.SyncChange, 0)
-		for i := range localPatterns {
-			cloudP := convertLocalPattern(&localPatterns[i])
-			changes = append(changes, cloud.SyncChange{
-				Action:  "create",
-				Pattern: cloudP,
-			})
-		}
-
-		if len(changes) == 0 {
-			fmt.Println("No patterns to push")
-			return nil
-		}
-
-		if dryRun {
-			fmt.Printf("Would push %d patterns\n", len(changes))
-			return nil
-		}
-
-		pushReq := cloud.PushRequest{
-			BaseVersion: localVersion,
-			Changes:     changes,
-		}
-
-		pushResp, err := client.Push(teamID, pushReq)
-		if err != nil {
-			return fmt.Errorf("failed to push: %w", err)
-		}
-
-		if !pushResp.OK {
-			if force {
-				fmt.Println("--force: Force push not yet implemented")
-				fmt.Println("Use 'mur cloud sync --force-local' instead")
-			} else {
-				// Interactive conflict resolution
-				resolutions, err := ResolveConflictsInteractive(pushResp.Conflicts)
-				if err != nil {
-					return fmt.Errorf("conflict resolution cancelled: %w", err)
-				}
-
-				keepServer, keepLocal, skipped := ApplyResolutions(resolutions)
-				fmt.Printf("\n📊 Resolution: %d server, %d local, %d skipped\n", keepServer, keepLocal, skipped)
-
-				if keepLocal > 0 {
-					fmt.Println("Note: Use --force to push local versions")
-				}
-			}
-			return nil
+		// Only what changed since the last sync; patterns the server also
+		// changed are merged
+		err = pushLocalChanges(out, client, teamID, store, ts, nil, dryRun, force, false)
+		if !dryRun {
+			_ = state.Save(statePath)
 		}
-
-		saveLocalSyncVersion(teamSlug, pushResp.Version)
-		fmt.Printf("✅ Pushed %d patterns\n", len(changes))
-
-		return nil
+		return err
 	},
 }
 
@@ -739,16 +1048,19 @@ Examples:
 			return fmt.Errorf("team not found: %s", teamSlug)
 		}
 
-		fmt.Printf("Pulling from team: %s\n", teamSlug)
-		fmt.Println("")
+		out := newPrinter(cmd)
+		out.Printf("Pulling from team: %s\n", teamSlug)
+		out.Println("")
 
 		// Load local store
 		store, err := pattern.DefaultStore()
 		if err != nil {
 			return fmt.Errorf("failed to load patterns: %w", err)
 		}
+		state, statePath := loadSyncState()
+		ts := state.Team(teamSlug)
 
-		localVersion := getLocalSyncVersion(teamSlug)
+		localVersion := ts.Version
 		if force {
 			localVersion = 0 // Pull everything
 		}
@@ -760,56 +1072,36 @@ Examples:
 		}
 
 		if !status.HasUpdates && !force {
-			fmt.Println("Already up to date")
+			out.Println("Already up to date")
 			return nil
 		}
 
-		pullResp, err := client.Pull(teamID, localVersion)
+		counts, conflicts, err := pullPaged(out, client, teamID, store, state, statePath, ts, localVersion, dryRun, force)
 		if err != nil {
-			return fmt.Errorf("failed to pull: %w", err)
+			return err
 		}
-
-		created, updated, deleted := 0, 0, 0
-		for _, p := range pullResp.Patterns {
-			exists := store.Exists(p.Name)
-
-			if dryRun {
-				if p.Deleted {
-					fmt.Printf("  Would delete: %s\n", p.Name)
-					deleted++
-				} else if exists {
-					fmt.Printf("  Would update: %s\n", p.Name)
-					updated++
-				} else {
-					fmt.Printf("  Would create: %s\n", p.Name)
-					created++
-				}
-				continue
-			}
-
-			if p.Deleted {
-				if err := store.Delete(p.Name); err == nil {
-					deleted++
-				}
-			} else {
-				localP := convertCloudPattern(&p)
-				if exists {
-					if err := store.Update(localP); err == nil {
-						updated++
-					}
-				} else {
-					if err := store.Create(localP); err == nil {
-						created++
-					}
-				}
-			}
+		if dryRun {
+			out.Printf("✅ %d created, %d updated, %d merged, %d deleted\n", counts.created, counts.updated, counts.merged, counts.deleted)
+			return nil
 		}
 
-		if !dryRun {
-			saveLocalSyncVersion(teamSlug, pullResp.Version)
+		if len(conflicts) > 0 {
+			_, err = resolveSyncConflicts(out, store, ts, conflicts, false, false)
 		}
+		_ = state.Save(statePath)
+		if err != nil {
+			return err
+		}
+		reportCoverageAfterSync(out, client, teamID, teamSlug)
 
-		fmt.Printf("✅ %d created, %d updated, %d deleted\n", created, updated, deleted)
+		out.Printf("✅ %d created, %d updated, %d merged, %d deleted\n", counts.created, counts.updated, counts.merged, counts.deleted)
+		if len(counts.changes) > 0 {
+			out.Println("")
+			printPulledChanges(out, counts.changes)
+		}
+		if len(conflicts) > 0 {
+			out.Println("Local versions you kept are pushed on the next 'mur cloud sync'")
+		}
 
 		return nil
 	},
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/cloud"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/session"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+// pulledChanges are the changelog entries of a pulled pattern that
+// weren't here before the pull.
+type pulledChanges struct {
+	name    string
+	entries []cloud.ChangelogEntry
+}
+
+// stampChangelog adds a changelog entry to each pattern created or
+// updated in changes: what changed since the last sync, who is pushing
+// it and, with server.changelog_llm, a one-line note on why. It returns
+// the entries by pattern name, to be kept locally once the push is in.
+func stampChangelog(changes []cloud.SyncChange, ts *cloud.TeamSyncState) map[string]cloud.ChangelogEntry {
+	author := changelogAuthor()
+	var llm session.LLMProvider
+	if cfg, err := config.Load(); err == nil && cfg.Server.ChangelogLLM {
+		llm, _ = session.NewLLMProviderFromConfig(cfg)
+	}
+
+	logged := make(map[string]cloud.ChangelogEntry)
+	now := time.Now().UTC()
+	for _, c := range changes {
+		if c.Action == "delete" || c.Pattern == nil {
+			continue
+		}
+		base := ts.Base(c.Pattern)
+		e := cloud.ChangelogEntry{At: now, Author: author, Summary: cloud.DiffSummary(base, c.Pattern)}
+		if llm != nil && base != nil {
+			e.Note = changelogNote(llm, base, c.Pattern)
+		}
+		c.Pattern.Changelog = append(c.Pattern.Changelog, e)
+		logged[c.Pattern.Name] = e
+	}
+	return logged
+}
+
+// changelogAuthor names the logged-in user for changelog entries.
+func changelogAuthor() string {
+	auth, err := cloud.NewAuthStore()
+	if err != nil {
+		return ""
+	}
+	data, err := auth.Load()
+	if err != nil || data == nil || data.User == nil {
+		return ""
+	}
+	if data.User.Name != "" {
+		return data.User.Name
+	}
+	return data.User.Email
+}
+
+// changelogNote asks the LLM for a one-line explanation of an update.
+// Failures leave the entry without a note.
+func changelogNote(llm session.LLMProvider, base *cloud.SyncBase, p *cloud.Pattern) string {
+	if base.Content == p.Content && base.Description == p.Description {
+		return ""
+	}
+	prompt := fmt.Sprintf(`In one line of at most 15 words, say what this edit to the coding pattern %q changes and why it matters. Reply with the line only.
+
+Before:
+%s
+%s
+
+After:
+%s
+%s`, p.Name, base.Description, truncate(base.Content, 2000), p.Description, truncate(p.Content, 2000))
+	resp, err := llm.Complete(prompt)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(resp), "\n")
+	return truncate(strings.Trim(line, `"`), 160)
+}
+
+// printPulledChanges shows what changed in pulled patterns since the
+// last sync, from their changelogs.
+func printPulledChanges(out *output.Printer, changes []pulledChanges) {
+	if len(changes) == 0 {
+		return
+	}
+	out.Println("Changed since you last synced:")
+	for _, c := range changes {
+		out.Printf("  %s\n", c.name)
+		for _, e := range c.entries {
+			line := fmt.Sprintf("    %s  %s", e.At.In(stats.DisplayLocation()).Format("2006-01-02"), e.Summary)
+			if e.Author != "" {
+				line += " (" + e.Author + ")"
+			}
+			if e.Note != "" {
+				line += " — " + e.Note
+			}
+			out.Println(line)
+			out.Record("changed", c.name, e.Author, e.Summary, e.Note)
+		}
+	}
+	out.Println("")
+}
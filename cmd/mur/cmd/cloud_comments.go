@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/cloud"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var cloudCommentsCmd = &cobra.Command{
+	Use:   "comments <pattern>",
+	Short: "Show the discussion on a team pattern",
+	Long: `Show the comments on a team pattern, with replies under the comment
+they answer, so the reasoning behind a pattern stays with it instead of
+in a chat thread.
+
+The pattern is a name or ID; local patterns are looked up by their ID,
+which is the same on the server once synced.
+
+Examples:
+  mur cloud comments retry-with-backoff
+  mur cloud comment retry-with-backoff -m "Also cap the total wait"
+  mur cloud comment retry-with-backoff --reply 42 -m "Done in v3"
+  mur learn get retry-with-backoff --comments`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCloudComments,
+}
+
+var cloudCommentCmd = &cobra.Command{
+	Use:   "comment <pattern> -m <message>",
+	Short: "Comment on a team pattern",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCloudComment,
+}
+
+func init() {
+	cloudCmd.AddCommand(cloudCommentsCmd)
+	cloudCmd.AddCommand(cloudCommentCmd)
+	cloudCommentsCmd.Flags().String("team", "", "Team slug (default: active team)")
+	cloudCommentCmd.Flags().String("team", "", "Team slug (default: active team)")
+	cloudCommentCmd.Flags().StringP("message", "m", "", "Comment text")
+	cloudCommentCmd.Flags().String("reply", "", "ID of the comment to reply to")
+	_ = cloudCommentCmd.MarkFlagRequired("message")
+}
+
+// commentsClient returns a logged-in client and the team for the comment
+// commands.
+func commentsClient(cmd *cobra.Command) (*cloud.Client, string, error) {
+	client, err := getCloudClient(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	if !client.AuthStore().IsLoggedIn() {
+		return nil, "", fmt.Errorf("not logged in. Run 'mur login' first")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	teamID, _, err := cloudTeam(cmd, client, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, teamID, nil
+}
+
+// commentPatternID returns the ID to address a pattern's comments by: the
+// local pattern's ID, or nameOrID itself for patterns not found locally or
+// without an ID (the server also accepts names).
+func commentPatternID(nameOrID string) (id, name string) {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nameOrID, nameOrID
+	}
+	p, err := store.Resolve(nameOrID)
+	if err != nil {
+		return nameOrID, nameOrID
+	}
+	if p.ID == "" {
+		return p.Name, p.Name
+	}
+	return p.ID, p.Name
+}
+
+func runCloudComments(cmd *cobra.Command, args []string) error {
+	client, teamID, err := commentsClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, name := commentPatternID(args[0])
+	comments, err := client.ListComments(teamID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	if p := newPrinter(cmd); p.JSON() {
+		return p.WriteJSON(cloud.Threads(comments))
+	}
+	if len(comments) == 0 {
+		fmt.Printf("No comments on %s yet.\n", name)
+		fmt.Printf("Start one with: mur cloud comment %s -m \"...\"\n", name)
+		return nil
+	}
+	fmt.Printf("💬 %s (%d comments)\n\n", name, len(comments))
+	printCommentThreads(cloud.Threads(comments), 1)
+	return nil
+}
+
+func runCloudComment(cmd *cobra.Command, args []string) error {
+	message, _ := cmd.Flags().GetString("message")
+	reply, _ := cmd.Flags().GetString("reply")
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("comment is empty")
+	}
+
+	client, teamID, err := commentsClient(cmd)
+	if err != nil {
+		return err
+	}
+	id, name := commentPatternID(args[0])
+	comment, err := client.PostComment(teamID, id, message, reply)
+	if err != nil {
+		return fmt.Errorf("failed to post comment: %w", err)
+	}
+
+	if p := newPrinter(cmd); p.JSON() {
+		return p.WriteJSON(comment)
+	}
+	if reply != "" {
+		fmt.Printf("✓ Replied to comment %s on %s\n", reply, name)
+	} else {
+		fmt.Printf("✓ Commented on %s (comment %s)\n", name, comment.ID)
+	}
+	return nil
+}
+
+// printCommentThreads prints threads with each level of replies indented
+// further.
+func printCommentThreads(threads []*cloud.CommentThread, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, t := range threads {
+		fmt.Printf("%s%s · %s · #%s\n", indent, t.Author, formatTimeAgo(t.CreatedAt), t.ID)
+		for _, line := range strings.Split(strings.TrimSpace(t.Body), "\n") {
+			fmt.Printf("%s  %s\n", indent, line)
+		}
+		fmt.Println()
+		printCommentThreads(t.Replies, depth+1)
+	}
+}
+
+// patternComments returns the comments on a synced team pattern, for
+// 'mur learn get --comments'.
+func patternComments(cmd *cobra.Command, nameOrID string) ([]cloud.Comment, error) {
+	client, teamID, err := commentsClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := commentPatternID(nameOrID)
+	return client.ListComments(teamID, id)
+}
+
+// printPatternComments prints comments below a pattern shown by 'mur
+// learn get'.
+func printPatternComments(comments []cloud.Comment) {
+	fmt.Println()
+	if len(comments) == 0 {
+		fmt.Println("Comments: none")
+		return
+	}
+	fmt.Printf("Comments (%d):\n", len(comments))
+	fmt.Println("--------")
+	printCommentThreads(cloud.Threads(comments), 0)
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/cloud"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/output"
+)
+
+var cloudCoverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Show which team patterns each member has",
+	Long: `Show how far the team's patterns have propagated: for each member,
+which team patterns they have current, which are stale (different content
+locally), and which they don't have.
+
+Members opt in with 'mur cloud coverage share'. Their client then reports
+the name and a content hash of each team pattern it has after every cloud
+sync or pull; pattern content and patterns the team doesn't have are never
+sent. Only team admins see other members' coverage.
+
+Examples:
+  mur cloud coverage                   # Team report
+  mur cloud coverage --member alice    # One member's stale and missing patterns
+  mur cloud coverage share             # Opt in and report now
+  mur cloud coverage unshare           # Opt out and delete your report`,
+	RunE: runCloudCoverage,
+}
+
+var cloudCoverageShareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Opt in to coverage reporting and report now",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCoverageSharing(cmd, true)
+	},
+}
+
+var cloudCoverageUnshareCmd = &cobra.Command{
+	Use:   "unshare",
+	Short: "Opt out of coverage reporting and delete your report",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCoverageSharing(cmd, false)
+	},
+}
+
+func init() {
+	cloudCmd.AddCommand(cloudCoverageCmd)
+	cloudCoverageCmd.AddCommand(cloudCoverageShareCmd)
+	cloudCoverageCmd.AddCommand(cloudCoverageUnshareCmd)
+	cloudCoverageCmd.PersistentFlags().String("team", "", "Team slug (default: active team)")
+	cloudCoverageCmd.Flags().String("member", "", "Show one member's stale and missing patterns")
+	cloudCoverageCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// cloudTeam resolves the team for commands with a --team flag, defaulting
+// to the active team.
+func cloudTeam(cmd *cobra.Command, client *cloud.Client, cfg *config.Config) (id, slug string, err error) {
+	slug, _ = cmd.Flags().GetString("team")
+	if slug == "" {
+		if slug, err = resolveActiveTeam(cfg, client); err != nil {
+			return "", "", err
+		}
+	}
+	id, err = client.ResolveTeamID(slug)
+	return id, slug, err
+}
+
+func runCloudCoverage(cmd *cobra.Command, args []string) error {
+	member, _ := cmd.Flags().GetString("member")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	client, err := getCloudClient(cmd)
+	if err != nil {
+		return err
+	}
+	if !client.AuthStore().IsLoggedIn() {
+		fmt.Println("Not logged in. Run 'mur login' first.")
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	teamID, teamSlug, err := cloudTeam(cmd, client, cfg)
+	if err != nil {
+		return err
+	}
+
+	tc, err := client.GetTeamCoverage(teamID)
+	if err != nil {
+		return fmt.Errorf("failed to get coverage: %w", err)
+	}
+	report := cloud.ComputeCoverage(tc)
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("📊 Pattern coverage for team %s (%d patterns)\n", teamSlug, len(tc.Patterns))
+	fmt.Println()
+
+	if len(tc.Members) == 0 {
+		// Not an admin, or nobody shares yet: show this machine's coverage
+		fmt.Println("No member reports visible. Only team admins see other members,")
+		fmt.Println("and members appear once they run 'mur cloud coverage share'.")
+		fmt.Println()
+		own, err := localCoverage(tc)
+		if err != nil {
+			return err
+		}
+		printMemberCoverage(own)
+		return nil
+	}
+
+	if member != "" {
+		for _, m := range report.Members {
+			if strings.EqualFold(m.Member, member) {
+				printMemberCoverage(m)
+				return nil
+			}
+		}
+		return fmt.Errorf("no coverage report from %s", member)
+	}
+
+	fmt.Printf("  %-24s %8s %7s %7s %7s  %s\n", "Member", "Coverage", "Current", "Stale", "Missing", "Reported")
+	for _, m := range report.Members {
+		fmt.Printf("  %-24s %7.0f%% %7d %7d %7d  %s\n", truncateStr(m.Member, 24), m.Percent(),
+			len(m.Current), len(m.Stale), len(m.Missing), m.ReportedAt.Local().Format("2006-01-02"))
+	}
+
+	var lagging []cloud.PatternCoverage
+	for _, p := range report.Patterns {
+		if p.Stale+p.Missing > 0 {
+			lagging = append(lagging, p)
+		}
+	}
+	if len(lagging) > 0 {
+		fmt.Println()
+		fmt.Println("Least propagated:")
+		if len(lagging) > 10 {
+			lagging = lagging[:10]
+		}
+		for _, p := range lagging {
+			fmt.Printf("  %-36s %d/%d current", truncateStr(p.Name, 36), p.Current, len(report.Members))
+			if p.Stale > 0 {
+				fmt.Printf(", %d stale", p.Stale)
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func printMemberCoverage(m cloud.MemberCoverage) {
+	total := len(m.Current) + len(m.Stale) + len(m.Missing)
+	fmt.Printf("%s: %d/%d current (%.0f%%)\n", m.Member, len(m.Current), total, m.Percent())
+	if len(m.Stale) > 0 {
+		fmt.Printf("\n  Stale (%d):\n", len(m.Stale))
+		for _, name := range m.Stale {
+			fmt.Printf("    • %s\n", name)
+		}
+	}
+	if len(m.Missing) > 0 {
+		fmt.Printf("\n  Missing (%d):\n", len(m.Missing))
+		for _, name := range m.Missing {
+			fmt.Printf("    • %s\n", name)
+		}
+	}
+	if len(m.Stale)+len(m.Missing) > 0 {
+		fmt.Println()
+		fmt.Println("  Run 'mur cloud pull --force' to catch up")
+	}
+}
+
+// localInventory returns the local store's patterns as name → content.
+func localInventory() (map[string]string, error) {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	local := make(map[string]string, len(patterns))
+	for _, p := range patterns {
+		local[p.Name] = p.Content
+	}
+	return local, nil
+}
+
+// localCoverage computes this machine's coverage of the team patterns.
+func localCoverage(tc *cloud.TeamCoverage) (cloud.MemberCoverage, error) {
+	local, err := localInventory()
+	if err != nil {
+		return cloud.MemberCoverage{}, err
+	}
+	own := &cloud.TeamCoverage{
+		Patterns: tc.Patterns,
+		Members: []cloud.MemberInventory{{
+			Member:    "This machine",
+			Inventory: cloud.Inventory{Patterns: cloud.TeamInventory(local, tc.Patterns)},
+		}},
+	}
+	return cloud.ComputeCoverage(own).Members[0], nil
+}
+
+// reportCoverage uploads this client's inventory of team patterns. It
+// returns how many team patterns were reported.
+func reportCoverage(client *cloud.Client, teamID, teamSlug string) (int, error) {
+	tc, err := client.GetTeamCoverage(teamID)
+	if err != nil {
+		return 0, err
+	}
+	local, err := localInventory()
+	if err != nil {
+		return 0, err
+	}
+	inv := cloud.Inventory{
+		Patterns:      cloud.TeamInventory(local, tc.Patterns),
+		SyncedVersion: getLocalSyncVersion(teamSlug),
+		ReportedAt:    time.Now(),
+	}
+	if err := client.UploadInventory(teamID, inv); err != nil {
+		return 0, err
+	}
+	return len(inv.Patterns), nil
+}
+
+// reportCoverageAfterSync reports coverage after a cloud sync or pull when
+// the user opted in. Servers without coverage support are skipped quietly.
+func reportCoverageAfterSync(out *output.Printer, client *cloud.Client, teamID, teamSlug string) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Server.ShareCoverage {
+		return
+	}
+	_, err = reportCoverage(client, teamID, teamSlug)
+	var unsupported *cloud.UnsupportedError
+	if err != nil && !errors.As(err, &unsupported) {
+		out.Warnf("⚠ Could not report coverage: %v\n", err)
+	}
+}
+
+func setCoverageSharing(cmd *cobra.Command, share bool) error {
+	client, err := getCloudClient(cmd)
+	if err != nil {
+		return err
+	}
+	if !client.AuthStore().IsLoggedIn() {
+		fmt.Println("Not logged in. Run 'mur login' first.")
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	teamID, teamSlug, err := cloudTeam(cmd, client, cfg)
+	if err != nil {
+		return err
+	}
+
+	if share {
+		n, err := reportCoverage(client, teamID, teamSlug)
+		if err != nil {
+			return fmt.Errorf("failed to report coverage: %w", err)
+		}
+		cfg.Server.ShareCoverage = true
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Reported %d team patterns to %s (names and content hashes only)\n", n, teamSlug)
+		fmt.Println("  Coverage is reported after every 'mur cloud sync' and 'mur cloud pull'")
+		return nil
+	}
+
+	if err := client.DeleteInventory(teamID); err != nil {
+		return fmt.Errorf("failed to delete coverage report: %w", err)
+	}
+	cfg.Server.ShareCoverage = false
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Stopped sharing coverage with %s and deleted your report\n", teamSlug)
+	return nil
+}
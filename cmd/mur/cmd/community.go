@@ -6,12 +6,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mur-run/mur-core/internal/cache"
 	"github.com/mur-run/mur-core/internal/cloud"
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/kit"
 	"github.com/mur-run/mur-core/internal/security"
 )
 
@@ -25,8 +29,22 @@ var communityCmd = &cobra.Command{
 var communitySearchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search community patterns",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runCommunitySearch,
+	Long: `Search community patterns by keyword on the server.
+
+With --semantic, mur also downloads the published embedding snapshot of
+public community patterns (for your configured search model), indexes it
+locally, and ranks patterns by meaning. Semantic results are merged with
+server keyword results, and keep working offline once the snapshot is cached.
+
+Server results are filtered to your tech_stack (see 'mur config
+detect-stack'); --any-stack searches all of them.
+
+Examples:
+  mur community search "retry"
+  mur community search --semantic "requests keep timing out under load"
+  mur community search --semantic --refresh-index "swift actor isolation"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommunitySearch,
 }
 
 var communityCopyCmd = &cobra.Command{
@@ -72,6 +90,9 @@ Examples:
 
 var (
 	communityLimit     int
+	communitySemantic  bool
+	communityRefresh   bool
+	communityAnyStack  bool
 	communityTeamID    string
 	shareCategory      string
 	shareTags          string
@@ -91,6 +112,9 @@ func init() {
 
 	communityCmd.PersistentFlags().IntVarP(&communityLimit, "limit", "n", 10, "Number of results")
 	communityCopyCmd.Flags().StringVarP(&communityTeamID, "team", "t", "", "Target team ID")
+	communitySearchCmd.Flags().BoolVar(&communitySemantic, "semantic", false, "Rank by meaning using the local community embedding index")
+	communitySearchCmd.Flags().BoolVar(&communityRefresh, "refresh-index", false, "Re-download the community embedding snapshot")
+	communitySearchCmd.Flags().BoolVar(&communityAnyStack, "any-stack", false, "Don't filter server results by your tech_stack")
 
 	// Share command flags
 	communityShareCmd.Flags().StringVarP(&shareCategory, "category", "c", "", "Pattern category (e.g., 'Error Handling', 'Testing')")
@@ -146,12 +170,16 @@ func runCommunity(cmd *cobra.Command, args []string) error {
 func runCommunitySearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
+	if communitySemantic {
+		return runCommunitySemanticSearch(query)
+	}
+
 	client, err := cloud.NewClient("")
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.SearchCommunity(query, communityLimit)
+	resp, err := client.SearchCommunityWithTech(query, communityTechStack(), communityLimit)
 	if err != nil {
 		return fmt.Errorf("failed to search: %w", err)
 	}
@@ -185,6 +213,179 @@ func runCommunitySearch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// communityTechStack returns the tech_stack to filter community search
+// by, or nil with --any-stack.
+func communityTechStack() []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return communityStack(cfg)
+}
+
+func communityStack(cfg *config.Config) []string {
+	if communityAnyStack {
+		return nil
+	}
+	return cfg.GetTechStack()
+}
+
+// communityIndexMaxAge is how long a downloaded embedding snapshot is used
+// before mur fetches a newer one.
+const communityIndexMaxAge = 7 * 24 * time.Hour
+
+func runCommunitySemanticSearch(query string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	embedder, err := embed.NewSearchEmbedder(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Server client is optional: semantic search works offline from the cached index
+	client, err := cloud.NewClient("")
+	if err != nil {
+		client = nil
+	}
+
+	idx, err := loadCommunityIndex(client, cfg.Search.Model)
+	if err != nil {
+		fmt.Printf("⚠ Community index unavailable: %v\n", err)
+	}
+
+	var semantic []cache.CommunityMatch
+	if idx != nil {
+		vec, err := embedder.Embed(embed.PrepareQuery(query, embedder))
+		if err != nil {
+			return fmt.Errorf("failed to embed query: %w", err)
+		}
+		for _, m := range idx.Search(vec, communityLimit) {
+			if m.Score >= cfg.Search.MinScore {
+				semantic = append(semantic, m)
+			}
+		}
+	}
+
+	var keyword []cloud.CommunityPattern
+	if client != nil {
+		if resp, err := client.SearchCommunityWithTech(query, communityStack(cfg), communityLimit); err == nil {
+			keyword = resp.Patterns
+		}
+	}
+
+	fmt.Printf("🔍 Semantic search results for \"%s\"\n", query)
+	fmt.Println(strings.Repeat("━", 50))
+	fmt.Println()
+
+	// Merge: semantic hits first, then keyword-only server hits
+	seen := make(map[string]bool)
+	shown := 0
+	for _, m := range semantic {
+		if shown >= communityLimit {
+			break
+		}
+		seen[m.Entry.ID] = true
+		shown++
+		printCommunityResult(m.Entry.Name, m.Entry.Author, m.Entry.Description, m.Entry.CopyCount, fmt.Sprintf("🧠 %.0f%%", m.Score*100))
+	}
+	for _, p := range keyword {
+		if shown >= communityLimit {
+			break
+		}
+		if seen[p.ID] {
+			continue
+		}
+		author := p.AuthorName
+		if p.AuthorLogin != "" {
+			author = "@" + p.AuthorLogin
+		}
+		shown++
+		printCommunityResult(p.Name, author, p.Description, p.CopyCount, "🔤 keyword")
+	}
+
+	if shown == 0 {
+		fmt.Println("  No patterns found.")
+	}
+	if idx != nil {
+		fmt.Printf("\nIndex: %d patterns, snapshot %s\n", len(idx.Entries), idx.GeneratedAt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// loadCommunityIndex returns the local community embedding index, downloading
+// a fresh snapshot when it is missing, stale, built for another model, or
+// --refresh-index is set. A stale index is still used if the download fails.
+func loadCommunityIndex(client *cloud.Client, model string) (*cache.CommunityIndex, error) {
+	path, err := cache.CommunityIndexPath()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := cache.LoadCommunityIndex(path)
+	if err != nil {
+		idx = nil
+	}
+	if idx != nil && idx.Model == model && !idx.Stale(communityIndexMaxAge) && !communityRefresh {
+		return idx, nil
+	}
+
+	if client == nil {
+		if idx != nil && idx.Model == model {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("no cached snapshot for model %s and server unavailable", model)
+	}
+
+	fmt.Println("⬇ Downloading community embedding snapshot...")
+	snap, err := client.GetCommunityEmbeddings(model)
+	if err != nil {
+		if idx != nil && idx.Model == model {
+			fmt.Printf("⚠ Download failed, using cached snapshot: %v\n", err)
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+
+	fresh := &cache.CommunityIndex{
+		Model:       snap.Model,
+		Dimension:   snap.Dimension,
+		GeneratedAt: snap.GeneratedAt,
+		FetchedAt:   time.Now(),
+	}
+	for _, p := range snap.Patterns {
+		author := p.AuthorName
+		if p.AuthorLogin != "" {
+			author = "@" + p.AuthorLogin
+		}
+		fresh.Entries = append(fresh.Entries, cache.CommunityIndexEntry{
+			ID:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Author:      author,
+			CopyCount:   p.CopyCount,
+			Embedding:   p.Embedding,
+		})
+	}
+	if err := fresh.Save(path); err != nil {
+		fmt.Printf("⚠ Could not cache community index: %v\n", err)
+	}
+	fmt.Printf("✓ Indexed %d community patterns\n\n", len(fresh.Entries))
+	return fresh, nil
+}
+
+func printCommunityResult(name, author, description string, copies int, source string) {
+	fmt.Printf("  • %s (⭐ %d) by %s  [%s]\n", name, copies, author, source)
+	if description != "" {
+		if len(description) > 60 {
+			description = description[:57] + "..."
+		}
+		fmt.Printf("    %s\n", description)
+	}
+}
+
 func runCommunityRecent(cmd *cobra.Command, args []string) error {
 	client, err := cloud.NewClient("")
 	if err != nil {
@@ -429,7 +630,7 @@ func runCommunityShare(cmd *cobra.Command, args []string) error {
 	}
 
 	// PII scanning and redaction
-	piiScanner := security.NewPIIScanner(cfg.Privacy)
+	piiScanner := security.NewPIIScanner(kit.Privacy(cfg.Privacy))
 	contentToScan := targetPattern.Name + "\n" + targetPattern.Description + "\n" + targetPattern.Content
 	cleaned, findings := piiScanner.ScanAndRedact(contentToScan)
 
@@ -463,7 +664,7 @@ func runCommunityShare(cmd *cobra.Command, args []string) error {
 			if sa.CacheResults {
 				home, _ := os.UserHomeDir()
 				if home != "" {
-					cacheDir = filepath.Join(home, ".mur", "cache", "anonymization")
+					cacheDir = filepath.Join(config.CacheDir(home), "cache", "anonymization")
 				}
 			}
 			anonymizer := security.NewSemanticAnonymizer(llmClient, cacheDir)
@@ -579,7 +780,7 @@ func runCommunityShare(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✓ Submitted \"%s\" for community review\n", targetPattern.Name)
 	fmt.Println()
 	fmt.Println("  Your pattern will be visible to everyone once approved.")
-	fmt.Println("  You'll be notified when it's published.")
+	fmt.Println("  Track its review with 'mur community mine'.")
 
 	return nil
 }
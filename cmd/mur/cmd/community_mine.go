@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/cloud"
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+var communityMineCmd = &cobra.Command{
+	Use:   "mine",
+	Short: "List your community submissions and their review status",
+	Long: `List the patterns you've submitted to the community, with their review
+status (pending, approved, rejected, withdrawn) and any reviewer notes.
+
+Rejected patterns can be fixed and sent back: edit the pattern, run
+'mur sync' to push the change to your team, then 'mur community resubmit'.
+
+Examples:
+  mur community mine
+  mur community mine --status rejected
+  mur community mine --json`,
+	RunE: runCommunityMine,
+}
+
+var communityWithdrawCmd = &cobra.Command{
+	Use:   "withdraw <submission-id>",
+	Short: "Withdraw a community submission",
+	Long: `Withdraw one of your community submissions. A pending submission leaves
+the review queue; an approved pattern is unpublished. Withdrawn submissions
+can be resubmitted later.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommunityWithdraw,
+}
+
+var communityResubmitCmd = &cobra.Command{
+	Use:   "resubmit <submission-id>",
+	Short: "Send a rejected or withdrawn submission back for review",
+	Long: `Send a rejected or withdrawn submission back for review. The current
+version of the pattern in your team is submitted, so edit it and run
+'mur sync' first. Use --note to reply to the reviewer.
+
+Examples:
+  mur community resubmit sub_8f2c --note "Removed the internal hostnames"
+  mur community resubmit sub_8f2c --tags "api,retry" --category "Error Handling"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCommunityResubmit,
+}
+
+var (
+	mineStatus       string
+	mineJSON         bool
+	withdrawYes      bool
+	resubmitNote     string
+	resubmitCategory string
+	resubmitTags     string
+	resubmitDesc     string
+	resubmitForce    bool
+)
+
+func init() {
+	communityCmd.AddCommand(communityMineCmd)
+	communityCmd.AddCommand(communityWithdrawCmd)
+	communityCmd.AddCommand(communityResubmitCmd)
+
+	communityMineCmd.Flags().StringVar(&mineStatus, "status", "", "Only show submissions with this status (pending, approved, rejected, withdrawn)")
+	communityMineCmd.Flags().BoolVar(&mineJSON, "json", false, "Output as JSON")
+
+	communityWithdrawCmd.Flags().BoolVarP(&withdrawYes, "yes", "y", false, "Don't ask for confirmation")
+
+	communityResubmitCmd.Flags().StringVar(&resubmitNote, "note", "", "Reply to the reviewer")
+	communityResubmitCmd.Flags().StringVarP(&resubmitCategory, "category", "c", "", "Change the pattern category")
+	communityResubmitCmd.Flags().StringVarP(&resubmitTags, "tags", "t", "", "Replace the tags (comma-separated)")
+	communityResubmitCmd.Flags().StringVarP(&resubmitDesc, "description", "d", "", "Override the pattern description")
+	communityResubmitCmd.Flags().BoolVar(&resubmitForce, "force", false, "Resubmit even if the pattern hasn't changed since the review")
+}
+
+// communityAuthorClient returns a client for the configured server,
+// requiring a login.
+func communityAuthorClient() (*config.Config, *cloud.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	client, err := cloud.NewClient(cfg.Server.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !client.AuthStore().IsLoggedIn() {
+		return nil, nil, fmt.Errorf("not logged in. Run 'mur login' first")
+	}
+	return cfg, client, nil
+}
+
+func runCommunityMine(cmd *cobra.Command, args []string) error {
+	switch mineStatus {
+	case "", cloud.SubmissionPending, cloud.SubmissionApproved, cloud.SubmissionRejected, cloud.SubmissionWithdrawn:
+	default:
+		return fmt.Errorf("unknown status %q (use pending, approved, rejected, or withdrawn)", mineStatus)
+	}
+
+	_, client, err := communityAuthorClient()
+	if err != nil {
+		return err
+	}
+
+	subs, err := client.ListMySubmissions(mineStatus)
+	if err != nil {
+		return fmt.Errorf("failed to list submissions: %w", err)
+	}
+
+	if mineJSON {
+		if subs == nil {
+			subs = []cloud.Submission{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(subs)
+	}
+
+	fmt.Println("📬 My Community Submissions")
+	fmt.Println(strings.Repeat("━", 50))
+	fmt.Println()
+
+	if len(subs) == 0 {
+		if mineStatus != "" {
+			fmt.Printf("  No %s submissions.\n", mineStatus)
+		} else {
+			fmt.Println("  No submissions yet. Share a pattern with 'mur community share <name>'.")
+		}
+		return nil
+	}
+
+	rejected := false
+	for _, s := range subs {
+		fmt.Printf("  %s %-10s %s", submissionIcon(s.Status), s.Status, s.Name)
+		if s.Revision > 1 {
+			fmt.Printf(" (revision %d)", s.Revision)
+		}
+		fmt.Println()
+		fmt.Printf("     %s · submitted %s", s.ID, s.SubmittedAt.Local().Format("2006-01-02"))
+		if s.ReviewedAt != nil {
+			fmt.Printf(" · reviewed %s", s.ReviewedAt.Local().Format("2006-01-02"))
+		}
+		fmt.Println()
+		if s.ReviewerNotes != "" {
+			reviewer := "Reviewer"
+			if s.Reviewer != "" {
+				reviewer = "@" + strings.TrimPrefix(s.Reviewer, "@")
+			}
+			fmt.Printf("     💬 %s: %s\n", reviewer, s.ReviewerNotes)
+		}
+		if s.Status == cloud.SubmissionRejected {
+			rejected = true
+		}
+	}
+
+	fmt.Println()
+	if rejected {
+		fmt.Println("Fix a rejected pattern, run 'mur sync', then 'mur community resubmit <id>'")
+	}
+	fmt.Println("Use 'mur community withdraw <id>' to withdraw a submission")
+	return nil
+}
+
+func submissionIcon(status string) string {
+	switch status {
+	case cloud.SubmissionPending:
+		return "⏳"
+	case cloud.SubmissionApproved:
+		return "✓"
+	case cloud.SubmissionRejected:
+		return "✗"
+	case cloud.SubmissionWithdrawn:
+		return "↩"
+	}
+	return "•"
+}
+
+func runCommunityWithdraw(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	_, client, err := communityAuthorClient()
+	if err != nil {
+		return err
+	}
+
+	sub, err := client.GetSubmission(id)
+	if err != nil {
+		return fmt.Errorf("failed to get submission: %w", err)
+	}
+	if sub.Status == cloud.SubmissionWithdrawn {
+		fmt.Printf("\"%s\" is already withdrawn.\n", sub.Name)
+		return nil
+	}
+
+	if !withdrawYes {
+		if sub.Status == cloud.SubmissionApproved {
+			fmt.Printf("\"%s\" is published; withdrawing removes it from the community.\n", sub.Name)
+		}
+		fmt.Printf("Withdraw \"%s\"? [y/N] ", sub.Name)
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Withdraw cancelled.")
+			return nil
+		}
+	}
+
+	if err := client.WithdrawSubmission(id); err != nil {
+		return fmt.Errorf("failed to withdraw submission: %w", err)
+	}
+
+	fmt.Printf("✓ Withdrew \"%s\"\n", sub.Name)
+	fmt.Printf("  Resubmit it later with 'mur community resubmit %s'\n", id)
+	return nil
+}
+
+func runCommunityResubmit(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, client, err := communityAuthorClient()
+	if err != nil {
+		return err
+	}
+
+	sub, err := client.GetSubmission(id)
+	if err != nil {
+		return fmt.Errorf("failed to get submission: %w", err)
+	}
+	if !sub.CanResubmit() {
+		return fmt.Errorf("\"%s\" is %s; only rejected or withdrawn submissions can be resubmitted", sub.Name, sub.Status)
+	}
+
+	// The server submits the team's current copy, so check it was edited
+	teamSlug := cfg.Server.Team
+	if teamSlug == "" {
+		return fmt.Errorf("no team configured. Run 'mur cloud select <team>' first")
+	}
+	teamID, err := client.ResolveTeamID(teamSlug)
+	if err != nil {
+		return fmt.Errorf("failed to resolve team: %w", err)
+	}
+	pullResp, err := client.Pull(teamID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get patterns: %w", err)
+	}
+	var teamPattern *cloud.Pattern
+	for i, p := range pullResp.Patterns {
+		if p.ID == sub.PatternID && !p.Deleted {
+			teamPattern = &pullResp.Patterns[i]
+			break
+		}
+	}
+	if teamPattern == nil {
+		return fmt.Errorf("pattern \"%s\" is no longer in team %s", sub.Name, teamSlug)
+	}
+	if sub.Status == cloud.SubmissionRejected && sub.ReviewedAt != nil &&
+		!teamPattern.UpdatedAt.After(*sub.ReviewedAt) && !resubmitForce {
+		fmt.Printf("⚠ \"%s\" hasn't changed since it was rejected.\n", sub.Name)
+		if sub.ReviewerNotes != "" {
+			fmt.Printf("  💬 Reviewer: %s\n", sub.ReviewerNotes)
+		}
+		fmt.Println("  Edit the pattern and run 'mur sync' first, or pass --force.")
+		return nil
+	}
+
+	var tags []string
+	for _, t := range strings.Split(resubmitTags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	updated, err := client.ResubmitSubmission(id, &cloud.ResubmitRequest{
+		Note:        resubmitNote,
+		Category:    resubmitCategory,
+		Tags:        tags,
+		Description: resubmitDesc,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resubmit: %w", err)
+	}
+
+	fmt.Printf("✓ Resubmitted \"%s\" for community review", updated.Name)
+	if updated.Revision > 1 {
+		fmt.Printf(" (revision %d)", updated.Revision)
+	}
+	fmt.Println()
+	fmt.Println("  Check its status with 'mur community mine'")
+	return nil
+}
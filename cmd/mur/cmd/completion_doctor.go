@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	murhooks "github.com/mur-run/mur-core/internal/hooks"
+)
+
+var completionDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that hooks can run mur outside your interactive shell",
+	Long: `Check that the hooks AI tools run can find mur.
+
+Hooks run in a non-interactive shell that doesn't read ~/.zshrc or
+~/.bashrc, and an AI tool started from the Dock or a desktop launcher may
+give them only ` + murhooks.MinimalPath + `. So hooks run mur by
+absolute path. This checks:
+
+  - where mur is, and whether it's on that minimal PATH
+  - every mur command in installed hook scripts and tool configs: the
+    binary must exist and be the mur you're running (after 'brew
+    upgrade' an old Cellar path no longer does)
+
+--fix rewrites the hooks that fail to run the current binary, keeping
+everything else in those files.
+
+Examples:
+  mur completion doctor
+  mur completion doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: runCompletionDoctor,
+}
+
+var completionDoctorFix bool
+
+func init() {
+	// Adding a subcommand needs cobra's completion command to exist now,
+	// not when Execute would create it.
+	rootCmd.InitDefaultCompletionCmd()
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(completionDoctorCmd)
+		}
+	}
+	completionDoctorCmd.Flags().BoolVar(&completionDoctorFix, "fix", false, "Point broken hooks at the current mur binary")
+}
+
+func runCompletionDoctor(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	out := newPrinter(cmd)
+	bin := murhooks.MurBinary()
+	home, _ := os.UserHomeDir()
+	short := func(p string) string {
+		if home != "" && strings.HasPrefix(p, home+string(filepath.Separator)) {
+			return "~" + p[len(home):]
+		}
+		return p
+	}
+
+	if filepath.IsAbs(bin) {
+		out.Printf("%s mur binary       %s\n", out.Green("✓"), bin)
+	} else {
+		out.Printf("%s mur binary       not found; reinstall mur or put it on PATH\n", out.Red("✗"))
+	}
+	out.Record("binary", bin)
+	if path, err := exec.LookPath("mur"); err != nil {
+		out.Printf("%s shell PATH       mur isn't on this shell's PATH either\n", out.Yellow("!"))
+	} else if path != bin {
+		out.Printf("  shell PATH       %s\n", out.Dim(path))
+	}
+	if murhooks.OnMinimalPath() {
+		out.Printf("%s minimal PATH     mur is found without an absolute path\n", out.Green("✓"))
+	} else {
+		out.Printf("%s minimal PATH     not found in %s; hooks need absolute paths\n", out.Yellow("!"), murhooks.MinimalPath)
+	}
+	out.Println()
+
+	refs := murhooks.CheckBinaryRefs()
+	if len(refs) == 0 {
+		out.Println("No installed hooks run mur (install with: mur init --hooks)")
+		return nil
+	}
+	broken := 0
+	for _, r := range refs {
+		status := "ok"
+		if r.Problem != "" {
+			status = "broken"
+			broken++
+		}
+		out.Record("hook", r.File, r.Path, status, r.Problem)
+		if r.Problem == "" {
+			out.Printf("%s %s\n    %s\n", out.Green("✓"), short(r.File), out.Dim(r.Path))
+			continue
+		}
+		out.Printf("%s %s\n    %s: %s\n", out.Red("✗"), short(r.File), r.Path, r.Problem)
+	}
+	out.Println()
+
+	if broken == 0 {
+		out.Println(out.Green("All hooks run " + bin))
+		return nil
+	}
+	if !completionDoctorFix {
+		out.Printf("%d hook command(s) won't run mur. Fix with: mur completion doctor --fix\n", broken)
+		cmd.SilenceErrors = true
+		return &exitError{1, fmt.Errorf("%d broken hook command(s)", broken)}
+	}
+	changed, err := murhooks.RepairBinaryRefs(bin)
+	for _, f := range changed {
+		out.Printf("%s Rewrote %s\n", out.Green("✓"), short(f))
+	}
+	if err != nil {
+		return err
+	}
+	out.Printf("Hooks now run %s\n", bin)
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -21,7 +22,9 @@ Examples:
   mur config edit         # Edit in $EDITOR
   mur config path         # Show config file path
   mur config get <key>    # Get a specific value
-  mur config set <k> <v>  # Set a value`,
+  mur config set <k> <v>  # Set a value
+  mur config repair       # Recover a config.yaml that no longer parses
+  mur config policy show  # Settings enforced by your team`,
 	RunE: runConfigShow,
 }
 
@@ -51,6 +54,25 @@ var configSetCmd = &cobra.Command{
 	RunE:  runConfigSet,
 }
 
+var configRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Recover a config file that no longer parses",
+	Long: `Recover a config.yaml that mur can no longer parse.
+
+The broken file is backed up next to it (config.yaml.broken-<time>).
+Every section, or failing that every key within a section, that still
+parses and has the right type is kept; anything else is dropped and
+listed with its line number. Sections still missing afterwards are
+regenerated from defaults.
+
+Examples:
+  mur config repair            # Repair and report what was kept
+  mur config repair --dry-run  # Only report what would be kept`,
+	RunE: runConfigRepair,
+}
+
+var configRepairDryRun bool
+
 var configResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset config to defaults",
@@ -64,6 +86,9 @@ func init() {
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configResetCmd)
+	configCmd.AddCommand(configRepairCmd)
+
+	configRepairCmd.Flags().BoolVar(&configRepairDryRun, "dry-run", false, "Report what would be recovered without changing anything")
 }
 
 func configPath() (string, error) {
@@ -71,7 +96,7 @@ func configPath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".mur", "config.yaml"), nil
+	return filepath.Join(config.ConfigDir(home), "config.yaml"), nil
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
@@ -94,6 +119,12 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Print(string(content))
 
+	if cfg, err := config.Load(); err == nil && cfg.Policy() != nil {
+		enforced, _ := cfg.Policy().Keys()
+		fmt.Println()
+		fmt.Printf("# 🔒 %d settings enforced by team policy (see 'mur config policy show')\n", len(enforced))
+	}
+
 	return nil
 }
 
@@ -146,6 +177,15 @@ func runConfigPath(cmd *cobra.Command, args []string) error {
 func runConfigGet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 
+	if cfg, err := config.Load(); err == nil {
+		if p := cfg.Policy(); p != nil {
+			if v, ok := p.Enforce[key]; ok {
+				fmt.Printf("%s  # enforced by team policy\n", policyValue(v))
+				return nil
+			}
+		}
+	}
+
 	path, err := configPath()
 	if err != nil {
 		return err
@@ -161,7 +201,7 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 
 	var config map[string]interface{}
 	if err := yaml.Unmarshal(content, &config); err != nil {
-		return fmt.Errorf("invalid config: %w", err)
+		return fmt.Errorf("invalid config: %w (run 'mur config repair' to recover it)", err)
 	}
 
 	// Simple dot notation support
@@ -185,6 +225,10 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 	value := args[1]
 
+	if cfg, err := config.Load(); err == nil && cfg.Locked(key) {
+		return fmt.Errorf("%s is locked by team policy (see 'mur config policy show')", key)
+	}
+
 	path, err := configPath()
 	if err != nil {
 		return err
@@ -201,7 +245,7 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 	} else {
 		if err := yaml.Unmarshal(content, &config); err != nil {
-			return fmt.Errorf("invalid config: %w", err)
+			return fmt.Errorf("invalid config: %w (run 'mur config repair' to recover it)", err)
 		}
 	}
 
@@ -250,6 +294,47 @@ func runConfigReset(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigRepair(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	report, err := config.Repair(path, configRepairDryRun)
+	if err != nil {
+		return err
+	}
+	if report.Healthy {
+		fmt.Printf("✓ %s parses fine; nothing to repair.\n", path)
+		return nil
+	}
+
+	if len(report.Recovered) > 0 {
+		fmt.Printf("✓ Recovered (%d):\n", len(report.Recovered))
+		for _, key := range report.Recovered {
+			fmt.Printf("    %s\n", key)
+		}
+	}
+	if len(report.Dropped) > 0 {
+		fmt.Printf("✗ Dropped (%d):\n", len(report.Dropped))
+		for _, d := range report.Dropped {
+			fmt.Printf("    line %d: %s — %s\n", d.Line, d.Key, d.Reason)
+		}
+	}
+	if len(report.Regenerated) > 0 {
+		fmt.Printf("📄 Regenerated from defaults: %s\n", strings.Join(report.Regenerated, ", "))
+	}
+	fmt.Println()
+
+	if configRepairDryRun {
+		fmt.Println("Dry run: nothing was changed. Run without --dry-run to repair.")
+		return nil
+	}
+	fmt.Printf("✓ Repaired %s\n", path)
+	fmt.Printf("  Original backed up to %s\n", report.Backup)
+	return nil
+}
+
 func createDefaultConfig(path string) error {
 	defaultConfig := `# mur configuration
 # See: https://github.com/mur-run/mur-core
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/cloud"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/output"
+)
+
+var configPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Team policy (managed settings)",
+	Long: `Show the team policy applied on top of your config.
+
+Team leads can enforce settings (e.g. semantic anonymization on, community
+sharing off) across members. The policy is signed by the server, pulled on
+'mur cloud sync', and stored next to config.yaml. Enforced keys override
+your config and cannot be changed with 'mur config set'; default keys only
+apply where your config leaves them unset.
+
+Examples:
+  mur config policy show          # What the policy sets and your local values
+  mur config policy show --json`,
+	RunE: runConfigPolicyShow,
+}
+
+var configPolicyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the team policy and what it overrides",
+	RunE:  runConfigPolicyShow,
+}
+
+func init() {
+	configCmd.AddCommand(configPolicyCmd)
+	configPolicyCmd.AddCommand(configPolicyShowCmd)
+	for _, c := range []*cobra.Command{configPolicyCmd, configPolicyShowCmd} {
+		c.Flags().Bool("json", false, "Output as JSON")
+	}
+}
+
+func runConfigPolicyShow(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	p, err := config.LoadPolicy()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if p == nil {
+		if asJSON {
+			fmt.Println("null")
+			return nil
+		}
+		fmt.Println("No team policy.")
+		fmt.Println("Policies are pulled from your team on 'mur cloud sync'.")
+		return nil
+	}
+
+	verifyErr := p.Verify(cfg.Server.PermissionKey)
+
+	if asJSON {
+		out := struct {
+			*config.Policy
+			Verified bool   `json:"verified"`
+			Error    string `json:"error,omitempty"`
+		}{Policy: p, Verified: verifyErr == nil}
+		if verifyErr != nil {
+			out.Error = verifyErr.Error()
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println("🔒 Team Policy")
+	fmt.Println("==============")
+	fmt.Printf("Team:      %s (version %d)\n", p.Team, p.Version)
+	if p.IssuedBy != "" {
+		fmt.Printf("Issued by: %s on %s\n", p.IssuedBy, p.IssuedAt.Format("2006-01-02"))
+	}
+	if verifyErr != nil {
+		fmt.Printf("Signature: ✗ %v\n", verifyErr)
+		fmt.Println()
+		fmt.Println("⚠ The policy is not applied. Run 'mur cloud sync' to fetch a valid one.")
+		return nil
+	}
+	fmt.Println("Signature: ✓ verified")
+
+	enforced, defaults := p.Keys()
+	if len(enforced) > 0 {
+		fmt.Println()
+		fmt.Println("Enforced (locked):")
+		for _, key := range enforced {
+			local, set, _ := cfg.PolicyLocal(key)
+			note := "unset locally"
+			if set {
+				note = "local: " + policyValue(local)
+				if policyValue(local) == policyValue(p.Enforce[key]) {
+					note = "matches local"
+				}
+			}
+			fmt.Printf("  %s = %s  (%s)\n", key, policyValue(p.Enforce[key]), note)
+		}
+	}
+	if len(defaults) > 0 {
+		fmt.Println()
+		fmt.Println("Defaults (where unset locally):")
+		for _, key := range defaults {
+			note := "overridden by local config"
+			if _, _, applied := cfg.PolicyLocal(key); applied {
+				note = "applied"
+			}
+			fmt.Printf("  %s = %s  (%s)\n", key, policyValue(p.Defaults[key]), note)
+		}
+	}
+	return nil
+}
+
+// policyValue renders a policy or config value on one line.
+func policyValue(v any) string {
+	if v == nil {
+		return "null"
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := strings.TrimSpace(string(out))
+	if strings.Contains(s, "\n") {
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return s
+}
+
+// syncTeamPolicy fetches the team's settings policy and stores it if its
+// signature verifies. A team without a policy clears any stored one.
+func syncTeamPolicy(out *output.Printer, client *cloud.Client, teamID string, dryRun bool) {
+	resp, err := client.GetTeamPolicy(teamID)
+	var unsupported *cloud.UnsupportedError
+	if errors.As(err, &unsupported) {
+		// Self-hosted servers without policies have nothing to apply
+		return
+	}
+	if err != nil {
+		out.Warnf("⚠ Could not fetch team policy: %v\n\n", err)
+		return
+	}
+
+	if resp.Policy == nil {
+		if existing, _ := config.LoadPolicy(); existing != nil && !dryRun {
+			if err := config.RemovePolicy(); err != nil {
+				out.Warnf("⚠ Could not remove team policy: %v\n\n", err)
+				return
+			}
+			out.Println("🔓 Team policy removed")
+			out.Println()
+		}
+		return
+	}
+
+	if dryRun {
+		out.Printf("Would apply team policy v%d\n\n", resp.Policy.Version)
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		out.Warnf("⚠ Team policy not applied: %v\n\n", err)
+		return
+	}
+	if err := pinSigningKey(cfg, resp.SigningKey); err != nil {
+		out.Warnf("⚠ Team policy not applied: %v\n\n", err)
+		return
+	}
+	if err := resp.Policy.Verify(cfg.Server.PermissionKey); err != nil {
+		out.Warnf("⚠ Team policy not applied: %v\n\n", err)
+		return
+	}
+	if err := config.SavePolicy(resp.Policy); err != nil {
+		out.Warnf("⚠ Could not save team policy: %v\n\n", err)
+		return
+	}
+
+	enforced, defaults := resp.Policy.Keys()
+	out.Printf("🔒 Team policy v%d applied (%d enforced, %d defaults; see 'mur config policy show')\n\n",
+		resp.Policy.Version, len(enforced), len(defaults))
+}
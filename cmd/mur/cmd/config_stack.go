@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+var configDetectStackCmd = &cobra.Command{
+	Use:   "detect-stack [dir...]",
+	Short: "Detect your tech stack from your repos and save it as tech_stack",
+	Long: `Scan your code directories for projects and the languages, frameworks,
+and tools they use (go.mod, package.json, Dockerfile, ...), propose a
+tech stack, and save the ones you pick as tech_stack.
+
+tech_stack filters community search to patterns for your stack, and
+ranks your own patterns for those languages higher when you work
+outside a project.
+
+Without directories, scans a code directory in your home (~/code, ~/src,
+~/projects, ...), the current directory, and projects Claude Code has
+been used in.
+
+Examples:
+  mur config detect-stack              # Scan and choose
+  mur config detect-stack ~/work -d 3  # Scan ~/work, 3 levels deep
+  mur config detect-stack --yes        # Save everything found`,
+	RunE: runConfigDetectStack,
+}
+
+var (
+	detectStackDepth int
+	detectStackYes   bool
+)
+
+func init() {
+	configCmd.AddCommand(configDetectStackCmd)
+	configDetectStackCmd.Flags().IntVarP(&detectStackDepth, "depth", "d", 2, "How many directory levels to search for projects")
+	configDetectStackCmd.Flags().BoolVarP(&detectStackYes, "yes", "y", false, "Save everything detected without asking")
+}
+
+func runConfigDetectStack(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	dirs := args
+	if len(dirs) == 0 {
+		dirs = defaultStackDirs()
+	}
+	items := inject.DetectStack(dirs, detectStackDepth)
+
+	p := newPrinter(cmd)
+	if p.JSON() {
+		return p.WriteJSON(items)
+	}
+	if len(items) == 0 {
+		fmt.Printf("No projects found in %s\n", strings.Join(dirs, ", "))
+		fmt.Println("Try: mur config detect-stack <your code directory>")
+		return nil
+	}
+
+	stack, err := chooseTechStack(items, cfg.GetTechStack(), detectStackYes)
+	if err != nil {
+		return err
+	}
+	return saveTechStack(cfg, stack)
+}
+
+// defaultStackDirs returns where to look for projects when no directory
+// is given.
+func defaultStackDirs() []string {
+	var dirs []string
+	if dir := defaultCodeDir(); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, cwd)
+	}
+	return append(dirs, learn.KnownProjectDirs()...)
+}
+
+// defaultCodeDir returns the first common code directory in the home
+// directory that exists, or "".
+func defaultCodeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"code", "src", "projects", "dev", "workspace", "repos", "git", "Developer"} {
+		dir := filepath.Join(home, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// chooseTechStack shows what was detected and asks which to keep; the
+// current tech_stack and everything found are preselected. With yes, it
+// keeps everything.
+func chooseTechStack(items []inject.StackItem, current []string, yes bool) ([]string, error) {
+	fmt.Println("🔎 Detected tech stack:")
+	var options, defaults []string
+	for _, it := range items {
+		repos := "repo"
+		if it.Repos != 1 {
+			repos = "repos"
+		}
+		fmt.Printf("  %-12s %d %s\n", it.Name, it.Repos, repos)
+		options = append(options, it.Name)
+		defaults = append(defaults, it.Name)
+	}
+	for _, tech := range current {
+		if !contains(options, tech) {
+			options = append(options, tech)
+			defaults = append(defaults, tech)
+		}
+	}
+	fmt.Println()
+
+	if yes {
+		return defaults, nil
+	}
+	var chosen []string
+	prompt := &survey.MultiSelect{
+		Message: "Which should be in your tech stack?",
+		Options: options,
+		Default: defaults,
+	}
+	if err := survey.AskOne(prompt, &chosen); err != nil {
+		return nil, err
+	}
+	return chosen, nil
+}
+
+func saveTechStack(cfg *config.Config, stack []string) error {
+	if len(stack) == 0 {
+		fmt.Println("tech_stack unchanged")
+		return nil
+	}
+	cfg.TechStack = stack
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("cannot save config: %w", err)
+	}
+	fmt.Printf("✓ tech_stack: %s\n", strings.Join(stack, ", "))
+	return nil
+}
+
+// offerStackDetection is the init wizard step that fills tech_stack from
+// the repos in a code directory the user picks.
+func offerStackDetection() {
+	dir := defaultCodeDir()
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	fmt.Println()
+	detect := true
+	confirm := &survey.Confirm{
+		Message: "Detect your tech stack from your existing repos? (filters community patterns)",
+		Default: true,
+	}
+	if err := survey.AskOne(confirm, &detect); err != nil || !detect {
+		fmt.Println("  Detect it later with 'mur config detect-stack'")
+		return
+	}
+	prompt := &survey.Input{Message: "Where is your code?", Default: dir}
+	if err := survey.AskOne(prompt, &dir); err != nil || strings.TrimSpace(dir) == "" {
+		return
+	}
+	if strings.HasPrefix(dir, "~/") {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir[2:])
+	}
+
+	items := inject.DetectStack([]string{dir}, 2)
+	if len(items) == 0 {
+		fmt.Printf("  No projects found in %s; detect later with 'mur config detect-stack <dir>'\n", dir)
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("  ⚠ Warning: %v\n", err)
+		return
+	}
+	stack, err := chooseTechStack(items, cfg.GetTechStack(), false)
+	if err != nil {
+		return
+	}
+	if err := saveTechStack(cfg, stack); err != nil {
+		fmt.Printf("  ⚠ Warning: %v\n", err)
+	}
+}
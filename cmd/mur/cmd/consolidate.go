@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,27 +13,58 @@ import (
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/consolidate"
 	"github.com/mur-run/mur-core/internal/core/analytics"
+	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/inject"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/learning"
+	"github.com/mur-run/mur-core/internal/notify"
 )
 
 var consolidateCmd = &cobra.Command{
 	Use:   "consolidate",
-	Short: "Consolidate patterns: score health, detect duplicates, resolve conflicts",
+	Short: "Consolidate patterns: score health, decay confidence, merge duplicates, resolve conflicts",
 	Long: `Analyze all patterns for health, duplicates, and conflicts.
 
 Default mode is --dry-run which shows what would happen without making changes.
-Use --auto to apply safe actions (archive stale patterns, keep-best merges).
-Use --interactive to step through each proposal.`,
+Use --auto to apply safe actions:
+  - decay each pattern's confidence by consolidation.decay_half_life_days
+    since it was last used
+  - archive stale, unused patterns once past consolidation.grace_period_days
+    (with consolidation.auto_archive)
+  - merge near-duplicates by consolidation.auto_merge: keep-best archives
+    all but the healthiest, llm-merge also merges their content into it
+Pinned patterns are never decayed or archived. Each --auto run records its
+report under ~/.mur/reports/.
+Use --interactive to step through each proposal.
+
+Use --pr to propose the --auto actions to the team instead: mur commits them
+to the mur/consolidation branch of the learning repo and opens a PR with a
+summary, so merges and archives only reach everyone once the PR is merged.
+Nothing is changed locally. With consolidation.team_pr: true, --auto does
+the same, so a scheduled "mur consolidate --auto" becomes a weekly PR.
+PR runs happen at most once per consolidation.schedule period unless
+--force is given.
+
+Examples:
+  mur consolidate                  # Preview
+  mur consolidate --auto           # Apply locally
+  mur consolidate --pr --dry-run   # Preview the team PR
+  mur consolidate --pr             # Open or update the team PR`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		autoFlag, _ := cmd.Flags().GetBool("auto")
 		interactiveFlag, _ := cmd.Flags().GetBool("interactive")
 		forceFlag, _ := cmd.Flags().GetBool("force")
+		prFlag, _ := cmd.Flags().GetBool("pr")
+		dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
 
 		mode := consolidate.ModeDryRun
-		if autoFlag {
+		switch {
+		case dryRunFlag:
+			// --dry-run wins over --auto
+		case autoFlag:
 			mode = consolidate.ModeAuto
-		} else if interactiveFlag {
+		case interactiveFlag:
 			mode = consolidate.ModeInteractive
 		}
 
@@ -41,12 +74,25 @@ Use --interactive to step through each proposal.`,
 			return fmt.Errorf("load config: %w", err)
 		}
 
+		teamPR := prFlag || (autoFlag && cfg.Consolidation.TeamPR)
+		if teamPR {
+			// Changes go to the team repo, never the local store
+			mode = consolidate.ModeDryRun
+			if !dryRunFlag && !forceFlag {
+				if due, last := consolidationPRDue(cfg.Consolidation.Period(), time.Now()); !due {
+					fmt.Printf("Consolidation PR already opened %s (schedule: %s); use --force to run now\n",
+						last.Format("2006-01-02"), cfg.Consolidation.Schedule)
+					return nil
+				}
+			}
+		}
+
 		home, err := os.UserHomeDir()
 		if err != nil {
 			return fmt.Errorf("home dir: %w", err)
 		}
 
-		murDir := filepath.Join(home, ".mur")
+		murDir := config.DataDir(home)
 		patternsDir := filepath.Join(murDir, "patterns")
 		trackingDir := filepath.Join(murDir, "tracking")
 
@@ -86,6 +132,30 @@ Use --interactive to step through each proposal.`,
 			analyticsTracker,
 		)
 
+		// Find duplicates by the pattern index, which tracks renames and
+		// content changes
+		if embed.HasIndex() {
+			if idx, err := embed.NewPatternIndexer(cfg); err == nil {
+				vectors := make(map[string]embed.Vector)
+				for _, p := range mc.Patterns.Active() {
+					if v, ok := idx.Vector(*p); ok {
+						vectors[p.ID] = v
+					}
+				}
+				c.WithVectors(vectors)
+			}
+		}
+		if mode == consolidate.ModeAuto && cfg.Consolidation.AutoMerge == "llm-merge" {
+			opts, _, err := resolveLLMOptions(cfg, "", "")
+			if err == nil && llmUnavailable(opts) == "" {
+				c.WithMerger(func(keep *pattern.Pattern, dups []*pattern.Pattern) (string, error) {
+					return learn.MergeWithLLM(keep, dups, opts)
+				})
+			} else {
+				fmt.Fprintln(os.Stderr, "warning: no LLM available; llm-merge duplicates are only reported")
+			}
+		}
+
 		report, err := c.Run(mode, forceFlag)
 		if err != nil {
 			return fmt.Errorf("consolidation failed: %w", err)
@@ -97,14 +167,135 @@ Use --interactive to step through each proposal.`,
 			nameMap[p.ID] = p.Name
 		}
 
+		if teamPR {
+			return runConsolidatePR(cfg.Consolidation, report, nameMap, dryRunFlag)
+		}
+
 		fmt.Print(consolidate.FormatReport(report, nameMap))
+		if mode != consolidate.ModeAuto {
+			return nil
+		}
+		path, err := consolidate.SaveReport(filepath.Join(murDir, "reports"), report, nameMap)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not record report: %v\n", err)
+		} else {
+			fmt.Printf("\nReport saved to %s\n", path)
+		}
+		if report.ActionsApplied > 0 {
+			resyncAITools(cfg)
+		}
 		return nil
 	},
 }
 
+// runConsolidatePR proposes the report's auto actions as a PR against the
+// team learning repo.
+func runConsolidatePR(cfg config.ConsolidationConfig, report *consolidate.ConsolidationReport, nameMap map[string]string, dryRun bool) error {
+	changes := consolidate.TeamChanges(cfg, report, nameMap)
+	if len(changes) == 0 {
+		fmt.Println("✓ Nothing to consolidate")
+		return nil
+	}
+
+	now := time.Now()
+	title := fmt.Sprintf("Consolidate patterns (%s)", now.Format("2006-01-02"))
+	pr, err := learning.OpenConsolidationPR(title, func(patternsDir string) (string, int, error) {
+		applied, err := consolidate.ApplyTeamChanges(patternsDir, changes, now)
+		if err != nil {
+			return "", 0, err
+		}
+		return consolidate.FormatPRBody(report, applied), len(applied), nil
+	}, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if pr.Changed == 0 {
+		fmt.Printf("✓ None of the %d proposed changes touch team patterns\n", len(changes))
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("[dry-run] Would open a PR against %s with %d changes:\n\n", pr.Base, pr.Changed)
+		fmt.Print(pr.Body)
+		return nil
+	}
+
+	verb := "Opened"
+	if pr.Updated {
+		verb = "Updated"
+	}
+	fmt.Printf("✓ %s consolidation PR (%d changes): %s\n", verb, pr.Changed, pr.URL)
+
+	if err := saveConsolidationPRState(now, pr.URL); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not record consolidation run: %v\n", err)
+	}
+	if cfg.NotifyOnRun && notify.IsConfigured() {
+		opts := notify.Options{
+			PatternName: fmt.Sprintf("consolidation (%d changes)", pr.Changed),
+			PRURL:       pr.URL,
+		}
+		if err := notify.Notify(notify.EventPRCreated, opts); err != nil {
+			fmt.Printf("  ⚠ Notification failed: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// consolidationPRState records the last consolidation PR run.
+type consolidationPRState struct {
+	LastRun time.Time `json:"last_run"`
+	URL     string    `json:"url,omitempty"`
+}
+
+func consolidationPRStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.StateDir(home), "consolidation-pr.json"), nil
+}
+
+// consolidationPRDue reports whether a schedule period has passed since
+// the last consolidation PR, and when that was.
+func consolidationPRDue(period time.Duration, now time.Time) (bool, time.Time) {
+	path, err := consolidationPRStatePath()
+	if err != nil {
+		return true, time.Time{}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true, time.Time{}
+	}
+	var state consolidationPRState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return true, time.Time{}
+	}
+
+	// An hour of slack so a cron job firing at the same time each
+	// period isn't skipped because the last run took a while.
+	return now.Sub(state.LastRun) >= period-time.Hour, state.LastRun
+}
+
+func saveConsolidationPRState(now time.Time, url string) error {
+	path, err := consolidationPRStatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(consolidationPRState{LastRun: now, URL: url}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
 func init() {
 	consolidateCmd.Flags().Bool("auto", false, "apply safe actions automatically")
 	consolidateCmd.Flags().Bool("interactive", false, "step through each proposal")
-	consolidateCmd.Flags().Bool("force", false, "skip minimum patterns check")
+	consolidateCmd.Flags().Bool("force", false, "skip minimum patterns check (and the schedule for --pr)")
+	consolidateCmd.Flags().Bool("pr", false, "propose the auto actions as a PR in the team learning repo")
+	consolidateCmd.Flags().Bool("dry-run", false, "show what would happen (default; with --pr, print the PR without pushing)")
 	rootCmd.AddCommand(consolidateCmd)
 }
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +14,7 @@ import (
 	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/inject"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
 	"github.com/mur-run/mur-core/internal/session"
 )
 
@@ -26,7 +30,41 @@ context-aware patterns into prompts.
 Examples:
   mur context                    # Detect context from cwd
   mur context --prompt "fix bug" # Also consider prompt
-  mur context --max 3            # Limit to 3 patterns`,
+  mur context --max 3            # Limit to 3 patterns (plus pinned)
+  mur context --budget 1500      # At most ~1500 tokens of context
+  mur context --format xml       # XML-tagged sections
+  mur context --target cursor    # Format configured for Cursor
+  mur context --profile oncall   # Use the oncall context profile
+  mur context --copy             # Copy to the clipboard to paste into a chat
+  mur context --tmux chat:1.0    # Paste into a tmux pane
+
+Pinned patterns (mur learn pin) are always included, up to
+context.pinned_budget (default 3), and don't count towards --max.
+
+--budget (default search.max_inject_tokens) caps the context at about
+that many tokens. Over it, long patterns are cut to a summary, starting
+with the least relevant, then the least relevant patterns are left out,
+pinned ones last. Without a budget each pattern's content is cut at 500
+characters instead.
+
+Context profiles (context.profiles in config) tailor what is injected to
+an activity: which tags are included or left out, the budgets, and extra
+pinned patterns. The profile is --profile, else $MUR_PROFILE (set it in a
+hook), else the one chosen for today with 'mur profile use', else
+context.profile.
+
+Formats are rendered from templates; put <format>.tmpl in
+~/.mur/templates/context/ to override a built-in format or add your own.
+
+Tools without hooks (web chats, IDE chat panels) can still get context:
+--copy puts it on the clipboard and --tmux <target> pastes it into a tmux
+pane, without pressing Enter. Either way the block starts with a short
+preamble for pasting at the start of a chat, and uses the "paste" target's
+format (context.targets.paste, default markdown).
+
+Every injection records which patterns were considered and why they were
+or weren't included. 'mur context --explain-last' prints the most recent
+one; the dashboard (mur serve) has the same detail under Injections.`,
 	RunE: runContext,
 }
 
@@ -35,100 +73,344 @@ func init() {
 	contextCmd.Flags().StringP("prompt", "p", "", "Prompt to consider for matching")
 	contextCmd.Flags().Int("max", 5, "Maximum patterns to output")
 	contextCmd.Flags().Bool("compact", false, "Compact output (names only)")
+	contextCmd.Flags().Int("budget", 0, "Maximum tokens of context (default: search.max_inject_tokens, 0 for no limit)")
+	contextCmd.Flags().String("format", "", "Output format: text, markdown, xml, claude-skill, or a custom template name")
+	contextCmd.Flags().String("profile", "", "Context profile from context.profiles (default: $MUR_PROFILE, then mur profile use, then context.profile)")
+	contextCmd.Flags().String("target", "", "Injection target (e.g. claude, cursor); selects context.targets.<target> from config")
+	contextCmd.Flags().Bool("copy", false, "Copy the context to the clipboard for pasting into a chat")
+	contextCmd.Flags().String("tmux", "", "Paste the context into a tmux pane (target such as session:window.pane)")
+	contextCmd.Flags().Bool("explain-last", false, "Explain why the most recent injection chose its patterns")
+	contextCmd.Flags().Int("last", 1, "With --explain-last, how many recent injections to explain")
+	contextCmd.Flags().Bool("json", false, "With --explain-last, output as JSON")
 }
 
 func runContext(cmd *cobra.Command, args []string) error {
+	if explain, _ := cmd.Flags().GetBool("explain-last"); explain {
+		n, _ := cmd.Flags().GetInt("last")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return explainLastInjections(n, asJSON)
+	}
+
 	// Suppress pattern injection during active recording to avoid
 	// polluting the session transcript with injected patterns that
 	// alter LLM behavior and make workflows non-reproducible.
+	copyOut, _ := cmd.Flags().GetBool("copy")
+	tmuxTarget, _ := cmd.Flags().GetString("tmux")
+	paste := copyOut || tmuxTarget != ""
+
 	if active, _ := session.IsRecording(); active {
+		if paste {
+			return fmt.Errorf("pattern injection is paused while a session is being recorded")
+		}
 		return nil
 	}
 
 	prompt, _ := cmd.Flags().GetString("prompt")
 	maxPatterns, _ := cmd.Flags().GetInt("max")
 	compact, _ := cmd.Flags().GetBool("compact")
-
-	// Get working directory
-	workDir, err := os.Getwd()
-	if err != nil {
-		return err
+	formatFlag, _ := cmd.Flags().GetString("format")
+	target, _ := cmd.Flags().GetString("target")
+	profileName, _ := cmd.Flags().GetString("profile")
+	budgetFlag, _ := cmd.Flags().GetInt("budget")
+	if paste && target == "" {
+		target = inject.TargetPaste
 	}
 
-	// Initialize pattern store
+	// Initialize pattern store, with the patterns of the repository in
+	// .mur/patterns/ if it has any
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
-	store := pattern.NewStore(patternsDir)
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
+	cwd, _ := os.Getwd()
+	store := pattern.NewStore(patternsDir).WithProject(projectPatternsDir(cwd))
 
 	// Check if we have any patterns
 	patterns, err := store.List()
 	if err != nil || len(patterns) == 0 {
 		// No patterns, output nothing
+		if paste {
+			fmt.Fprintln(os.Stderr, "No patterns yet; nothing to paste.")
+		}
 		return nil
 	}
 
-	// Create injector
-	injector := inject.NewInjector(store)
-
-	// Try to enable semantic search
-	embedCfg := embed.DefaultConfig()
-	_ = injector.WithSemanticSearch(embedCfg) // Non-fatal if fails
-
-	// Get context-aware patterns
-	// Use empty prompt if not provided - we'll match based on project context
-	queryPrompt := prompt
-	if queryPrompt == "" {
-		queryPrompt = "general development task"
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
 	}
 
-	result, err := injector.Inject(queryPrompt, workDir)
+	maxSet := cmd.Flags().Changed("max")
+	result, err := selectContext(cfg, store, prompt, profileName, maxPatterns, maxSet)
 	if err != nil {
+		if profileName != "" || paste {
+			return err
+		}
 		return nil // Silent fail, don't break the hook
 	}
 
+	budget := inject.TokenBudget(cfg, budgetFlag)
+	record := func(fit inject.BudgetFit) {
+		if ex := result.Explanation; ex != nil {
+			ex.Command = "context"
+			ex.Target = target
+			ex.Session = os.Getenv("MUR_SESSION_ID")
+			ex.SetBudget(budget, fit)
+			_ = inject.RecordExplanation(ex) // Non-fatal, don't break the hook
+		}
+	}
+
 	if len(result.Patterns) == 0 {
+		record(inject.BudgetFit{})
+		if paste {
+			fmt.Fprintln(os.Stderr, "No relevant patterns for this context; nothing to paste.")
+		}
 		return nil
 	}
 
-	// Limit patterns
-	if len(result.Patterns) > maxPatterns {
-		result.Patterns = result.Patterns[:maxPatterns]
+	format := inject.ResolveFormat(cfg, formatFlag, target)
+	out, fit, err := renderContext(format, compact, budget, result.Context, result.Patterns)
+	record(fit)
+	if err != nil {
+		return err
 	}
-
-	if compact {
-		// Just output pattern names
-		var names []string
-		for _, p := range result.Patterns {
-			names = append(names, p.Name)
+	if out == "" {
+		if paste {
+			fmt.Fprintf(os.Stderr, "No pattern fits in %d tokens; nothing to paste.\n", budget)
 		}
-		fmt.Println("[mur] Relevant patterns:", strings.Join(names, ", "))
+		return nil
+	}
+	injected := len(result.Patterns) - len(fit.Dropped)
+	if !paste {
+		fmt.Print(out)
 		return nil
 	}
 
-	// Output patterns in a format suitable for prompt injection
-	fmt.Println()
-	fmt.Println("─── Relevant Patterns (mur) ───")
-	if result.Context != nil && result.Context.ProjectType != "" {
-		fmt.Printf("Project: %s (%s)\n", result.Context.ProjectName, result.Context.ProjectType)
+	out = inject.PastePreamble + strings.TrimSpace(out) + "\n"
+	if tmuxTarget != "" {
+		if err := pasteToTmux(tmuxTarget, out); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Pasted %d pattern(s) into tmux pane %s\n", injected, tmuxTarget)
 	}
-	fmt.Println()
+	if copyOut {
+		if err := copyToClipboard(out); err != nil {
+			// Like mur copy: print it to copy by hand
+			fmt.Print(out)
+			fmt.Fprintf(os.Stderr, "\n(Clipboard not available, printed to stdout: %v)\n", err)
+			return nil
+		}
+		fmt.Printf("✓ Copied %d pattern(s) to the clipboard (%d lines)\n", injected, countLines(out))
+	}
+	return nil
+}
+
+// pasteToTmux pastes text into a tmux pane through a buffer. Unlike
+// send-keys, a paste doesn't turn newlines into Enter, so a chat prompt
+// isn't submitted line by line; -p uses bracketed paste when the pane's
+// program asks for it.
+func pasteToTmux(target, text string) error {
+	if err := execx.CheckArg(target); err != nil {
+		return fmt.Errorf("invalid tmux target: %w", err)
+	}
+	load := execx.Command("tmux", "load-buffer", "-b", "mur-context", "-")
+	load.Stdin = strings.NewReader(text)
+	if _, err := load.Run(context.Background()); err != nil {
+		return err
+	}
+	if _, err := execx.Run(context.Background(), "tmux", "paste-buffer", "-p", "-d", "-b", "mur-context", "-t", target); err != nil {
+		return fmt.Errorf("cannot paste into tmux pane %s: %w", target, err)
+	}
+	return nil
+}
 
-	for _, p := range result.Patterns {
-		fmt.Printf("## %s\n", p.Name)
-		if p.Description != "" {
-			fmt.Printf("*%s*\n", p.Description)
+// selectContext picks the patterns to inject for prompt in the current
+// directory, limited to maxPatterns (or the profile's max unless maxSet).
+// Only an unknown profileName is an error; other profile problems are
+// reported as warnings so hooks keep working.
+func selectContext(cfg *config.Config, store *pattern.Store, prompt, profileName string, maxPatterns int, maxSet bool) (*inject.InjectionResult, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := inject.ResolveProfile(cfg, profileName)
+	if err != nil {
+		if profileName != "" {
+			return nil, err
 		}
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err) // Don't break the hook
+	}
+	if profile != nil && profile.Max > 0 && !maxSet {
+		maxPatterns = profile.Max
+	}
 
-		// Truncate content for prompt injection
+	// Create injector
+	injector := inject.NewInjector(store)
+	injector.WithPinnedBudget(profile.PinnedBudget(cfg))
+	injector.WithProfile(profile)
+	injector.WithTechStack(cfg.GetTechStack())
+
+	// Try to enable semantic search. Without an index it would only start
+	// indexing in the background, which a short-lived hook never finishes.
+	if embed.HasIndex() {
+		embedCfg := embed.SearchConfig(cfg)
+		_ = injector.WithSemanticSearch(embedCfg) // Non-fatal if fails
+	}
+
+	// Use a generic prompt if none was given - we'll match based on project context
+	if prompt == "" {
+		prompt = "general development task"
+	}
+
+	result, err := injector.Inject(prompt, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit patterns; pinned ones don't count towards --max
+	result.Limit(maxPatterns)
+	return result, nil
+}
+
+// renderContext formats patterns for injection the way hooks receive them,
+// fitted into budget tokens (see inject.FitBudget) if it is set.
+func renderContext(format string, compact bool, budget int, pc *inject.ProjectContext, patterns []*pattern.Pattern) (string, inject.BudgetFit, error) {
+	data := inject.FormatData{Compact: compact}
+	if pc != nil {
+		data.Project = pc.ProjectName
+		data.ProjectType = pc.ProjectType
+	}
+	for _, p := range patterns {
+		// Without a budget, truncate content for prompt injection
 		content := p.Content
-		if len(content) > 500 {
+		if budget <= 0 && len(content) > 500 {
 			content = content[:500] + "\n...(truncated)"
 		}
-		fmt.Println(content)
-		fmt.Println()
+		data.Patterns = append(data.Patterns, inject.FormatPattern{
+			Name:        p.Name,
+			Description: p.Description,
+			Content:     content,
+			Pinned:      p.Pinned,
+		})
 	}
-	fmt.Println("────────────────────────────────")
-	fmt.Println()
+	return inject.FitBudget(data, budget, func(d inject.FormatData) (string, error) {
+		return inject.Render(format, d)
+	})
+}
 
+// explainLastInjections prints the n most recent injection explanations.
+func explainLastInjections(n int, asJSON bool) error {
+	if n < 1 {
+		n = 1
+	}
+	explanations, err := inject.RecentExplanations(n)
+	if err != nil {
+		return fmt.Errorf("failed to read injection log: %w", err)
+	}
+
+	if asJSON {
+		if explanations == nil {
+			explanations = []inject.Explanation{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanations)
+	}
+
+	if len(explanations) == 0 {
+		fmt.Println("No injections recorded yet.")
+		fmt.Println("They are recorded each time a hook runs 'mur context'.")
+		return nil
+	}
+
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Println()
+		}
+		printExplanation(e)
+	}
 	return nil
 }
+
+func printExplanation(e inject.Explanation) {
+	fmt.Printf("🔎 Injection at %s (%s", e.Time.Local().Format("2006-01-02 15:04:05"), e.Command)
+	if e.Target != "" {
+		fmt.Printf(", target %s", e.Target)
+	}
+	if e.Profile != "" {
+		fmt.Printf(", profile %s", e.Profile)
+	}
+	fmt.Println(")")
+
+	if e.Session != "" {
+		fmt.Printf("   Session:  %s\n", e.Session)
+	}
+	if e.Dir != "" {
+		fmt.Printf("   Dir:      %s\n", e.Dir)
+	}
+	project := e.Project.Name
+	if e.Project.Type != "" {
+		project += " (" + e.Project.Type + ")"
+	}
+	if tags := append(append([]string{}, e.Project.Languages...), e.Project.Frameworks...); len(tags) > 0 {
+		project += " · " + strings.Join(tags, ", ")
+	}
+	if project != "" {
+		fmt.Printf("   Project:  %s\n", project)
+	}
+	if e.Project.Remote != "" {
+		fmt.Printf("   Remote:   %s\n", e.Project.Remote)
+	}
+	if e.Prompt != "" {
+		fmt.Printf("   Prompt:   %q\n", truncate(e.Prompt, 70))
+	}
+	fmt.Printf("   Scoring:  %s, up to %d relevant + %d pinned\n", e.Method, e.Max, e.Pinned)
+	if e.Budget > 0 {
+		fmt.Printf("   Budget:   ~%d of %d tokens\n", e.Tokens, e.Budget)
+	}
+
+	var injected, skipped []inject.Decision
+	for _, d := range e.Patterns {
+		if d.Injected {
+			injected = append(injected, d)
+		} else {
+			skipped = append(skipped, d)
+		}
+	}
+
+	fmt.Println()
+	if len(injected) == 0 {
+		fmt.Println("Injected: nothing")
+	} else {
+		fmt.Println("Injected:")
+		for _, d := range injected {
+			icon := "✓"
+			if d.Pinned {
+				icon = "📌"
+			}
+			fmt.Printf("  %s %s\n", icon, explainDecision(d))
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Println("Left out:")
+		for _, d := range skipped {
+			fmt.Printf("  ✗ %s\n", explainDecision(d))
+		}
+	}
+}
+
+func explainDecision(d inject.Decision) string {
+	line := fmt.Sprintf("%-28s", d.Name)
+	if d.Score > 0 {
+		line += fmt.Sprintf("  %.2f", d.Score)
+	} else {
+		line += "      "
+	}
+	line += "  " + d.Reason
+	if d.Summarized {
+		line += " (summarized)"
+	}
+	if len(d.Matched) > 0 {
+		line += " — matched " + strings.Join(d.Matched, ", ")
+	}
+	return line
+}
@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
 var copyCmd = &cobra.Command{
@@ -39,8 +45,11 @@ func runCopy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternPath := filepath.Join(home, ".mur", "patterns", patternName+".yaml")
+	patternPath := filepath.Join(config.DataDir(home), "patterns", patternName+".yaml")
 	content, err := os.ReadFile(patternPath)
+	if os.IsNotExist(err) {
+		content, err = pattern.ReadFile(strings.TrimSuffix(patternPath, ".yaml") + pattern.CompressedExt)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("pattern not found: %s", patternName)
@@ -134,42 +143,27 @@ func countLines(s string) int {
 }
 
 func copyToClipboard(text string) error {
-	var cmd *exec.Cmd
+	var cmd *execx.Cmd
 
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("pbcopy")
+		cmd = execx.Command("pbcopy")
 	case "linux":
 		// Try xclip first, then xsel
 		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.Command("xclip", "-selection", "clipboard")
+			cmd = execx.Command("xclip", "-selection", "clipboard")
 		} else if _, err := exec.LookPath("xsel"); err == nil {
-			cmd = exec.Command("xsel", "--clipboard", "--input")
+			cmd = execx.Command("xsel", "--clipboard", "--input")
 		} else {
 			return fmt.Errorf("no clipboard tool found (install xclip or xsel)")
 		}
 	case "windows":
-		cmd = exec.Command("clip")
+		cmd = execx.Command("clip")
 	default:
 		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
 
-	pipe, err := cmd.StdinPipe()
-	if err != nil {
-		return err
-	}
-
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	if _, err := pipe.Write([]byte(text)); err != nil {
-		return err
-	}
-
-	if err := pipe.Close(); err != nil {
-		return err
-	}
-
-	return cmd.Wait()
+	cmd.Stdin = strings.NewReader(text)
+	_, err := cmd.Run(context.Background())
+	return err
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -53,7 +54,7 @@ func crossLearnScanExecute(cmd *cobra.Command, args []string) error {
 	interactive, _ := cmd.Flags().GetBool("interactive")
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	learner := learn.NewCrossCLILearner(store)
@@ -131,7 +132,7 @@ func crossLearnScanExecute(cmd *cobra.Command, args []string) error {
 
 func interactiveAcceptCrossLearn(store *pattern.Store, suggestions []suggest.Suggestion) error {
 	home, _ := os.UserHomeDir()
-	suggestDir := filepath.Join(home, ".mur", "suggestions")
+	suggestDir := filepath.Join(config.DataDir(home), "suggestions")
 	extractor := suggest.NewExtractor(store, suggestDir, suggest.DefaultExtractorConfig())
 
 	return interactiveAccept(extractor, suggestions)
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/heartbeat"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run and monitor mur background processes",
+	Long: `Run mur's scheduled background work, and monitor it and the dashboard
+server.
+
+Each process writes a heartbeat file under the mur state directory:
+'mur sync' after every run, 'mur daemon run' every minute, and
+'mur serve' every 30 seconds.
+
+Commands:
+  mur daemon run        Run sync, consolidation, and learning-repo sync on schedule
+  mur daemon install    Install 'mur daemon run' as a launchd/systemd service
+  mur daemon uninstall  Stop and remove it
+  mur daemon health     Check heartbeats (exit 0 healthy, 1 unhealthy, 3 unknown)
+  mur daemon init       Generate systemd/launchd units for mur serve with auto-restart`,
+}
+
+var daemonHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check health of background processes",
+	Long: `Check the heartbeats of the scheduled sync, the daemon, and the
+dashboard server.
+
+Exit codes (suitable for monitoring and supervisor health checks):
+  0  healthy
+  1  unhealthy: last run failed, heartbeat is stale, or process stopped
+  3  unknown: no heartbeat recorded
+
+Without --process, processes that never ran or were stopped cleanly are
+ignored, and the exit code is 3 only if nothing has run at all.
+
+Examples:
+  mur daemon health
+  mur daemon health --process sync
+  mur daemon health --json`,
+	RunE: runDaemonHealth,
+}
+
+var daemonInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate supervisor units with auto-restart",
+	Long: `Write example systemd and launchd definitions that keep 'mur serve'
+running and restart it on failure, plus a systemd timer that runs
+'mur daemon health' so failures show up in the journal.
+
+The files are written to the output directory for review; nothing is
+installed or loaded.
+
+Examples:
+  mur daemon init
+  mur daemon init --port 3000 --output ./units`,
+	RunE: runDaemonInit,
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.AddCommand(daemonHealthCmd)
+	daemonCmd.AddCommand(daemonInitCmd)
+
+	daemonHealthCmd.Flags().String("process", "", "Only check one process: sync, serve, or daemon")
+	daemonHealthCmd.Flags().Bool("json", false, "Output as JSON")
+
+	daemonInitCmd.Flags().StringP("output", "o", "", "Output directory (default: <mur config dir>/daemon)")
+	daemonInitCmd.Flags().IntP("port", "p", 8742, "Port for mur serve")
+}
+
+func runDaemonHealth(cmd *cobra.Command, args []string) error {
+	process, _ := cmd.Flags().GetString("process")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	now := time.Now()
+	var results []heartbeat.Health
+	var code int
+	switch process {
+	case "":
+		results = heartbeat.CheckAll(now)
+		code = heartbeat.Overall(results)
+	case heartbeat.ProcessSync, heartbeat.ProcessServe, heartbeat.ProcessDaemon:
+		h := heartbeat.Check(process, now)
+		results = []heartbeat.Health{h}
+		code = h.ExitCode()
+	default:
+		return fmt.Errorf("unknown process %q (use: sync, serve, daemon)", process)
+	}
+
+	if asJSON {
+		data, _ := json.MarshalIndent(map[string]any{
+			"exit_code": code,
+			"processes": results,
+		}, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, h := range results {
+			icon := "✓"
+			switch h.State {
+			case heartbeat.StateUnhealthy:
+				icon = "✗"
+			case heartbeat.StateStopped, heartbeat.StateUnknown:
+				icon = "-"
+			}
+			fmt.Printf("%s %-6s %-9s %s\n", icon, h.Process, h.State, h.Reason)
+			if h.Beat != nil && h.Beat.Addr != "" && h.State == heartbeat.StateHealthy {
+				fmt.Printf("         %s (pid %d)\n", h.Beat.Addr, h.Beat.PID)
+			}
+		}
+	}
+
+	if code != heartbeat.ExitHealthy {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &exitError{code, fmt.Errorf("background processes are not healthy")}
+	}
+	return nil
+}
+
+const systemdServeUnit = `[Unit]
+Description=mur dashboard server
+After=network.target
+
+[Service]
+ExecStart={{.MurPath}} serve --no-browser --port {{.Port}}
+Restart=on-failure
+RestartSec=5
+# 78 (EX_CONFIG) means misconfiguration; restarting will not help
+RestartPreventExitStatus=78
+
+[Install]
+WantedBy=default.target
+`
+
+const systemdHealthService = `[Unit]
+Description=mur health check
+
+[Service]
+Type=oneshot
+ExecStart={{.MurPath}} daemon health
+`
+
+const systemdHealthTimer = `[Unit]
+Description=mur health check timer
+
+[Timer]
+OnBootSec=5min
+OnUnitActiveSec=15min
+
+[Install]
+WantedBy=timers.target
+`
+
+const launchdServePlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>run.mur.serve</string>
+    <key>ProgramArguments</key>
+    <array>
+        <string>{{.MurPath}}</string>
+        <string>serve</string>
+        <string>--no-browser</string>
+        <string>--port</string>
+        <string>{{.Port}}</string>
+    </array>
+    <key>RunAtLoad</key>
+    <true/>
+    <!-- Restart only when mur serve exits with a non-zero code -->
+    <key>KeepAlive</key>
+    <dict>
+        <key>SuccessfulExit</key>
+        <false/>
+    </dict>
+    <key>ThrottleInterval</key>
+    <integer>10</integer>
+    <key>StandardOutPath</key>
+    <string>{{.LogPath}}</string>
+    <key>StandardErrorPath</key>
+    <string>{{.LogPath}}</string>
+</dict>
+</plist>
+`
+
+func runDaemonInit(cmd *cobra.Command, args []string) error {
+	outDir, _ := cmd.Flags().GetString("output")
+	port, _ := cmd.Flags().GetInt("port")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	if outDir == "" {
+		outDir = filepath.Join(config.ConfigDir(home), "daemon")
+	}
+
+	murPath := murExecutable()
+
+	data := struct {
+		MurPath string
+		Port    int
+		LogPath string
+	}{murPath, port, filepath.Join(config.StateDir(home), "serve.log")}
+
+	files := []struct{ name, tmpl string }{
+		{"mur-serve.service", systemdServeUnit},
+		{"mur-health.service", systemdHealthService},
+		{"mur-health.timer", systemdHealthTimer},
+		{"run.mur.serve.plist", launchdServePlist},
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	for _, f := range files {
+		tmpl := template.Must(template.New(f.name).Parse(f.tmpl))
+		out, err := os.Create(filepath.Join(outDir, f.name))
+		if err != nil {
+			return err
+		}
+		err = tmpl.Execute(out, data)
+		out.Close()
+		if err != nil {
+			return fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+
+	fmt.Printf("✅ Supervisor units written to %s\n\n", outDir)
+	fmt.Println("systemd (Linux):")
+	fmt.Printf("  cp %s/mur-*.service %s/mur-health.timer ~/.config/systemd/user/\n", outDir, outDir)
+	fmt.Println("  systemctl --user daemon-reload")
+	fmt.Println("  systemctl --user enable --now mur-serve.service mur-health.timer")
+	fmt.Println()
+	fmt.Println("launchd (macOS):")
+	fmt.Printf("  cp %s/run.mur.serve.plist ~/Library/LaunchAgents/\n", outDir)
+	fmt.Println("  launchctl load ~/Library/LaunchAgents/run.mur.serve.plist")
+	return nil
+}
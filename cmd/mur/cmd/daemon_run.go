@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/daemon"
+	"github.com/mur-run/mur-core/internal/execx"
+	"github.com/mur-run/mur-core/internal/heartbeat"
+	"github.com/mur-run/mur-core/internal/learning"
+)
+
+// daemonTick is how often the daemon checks for due jobs.
+const daemonTick = time.Minute
+
+// daemonJobTimeout bounds a single job run.
+const daemonJobTimeout = 30 * time.Minute
+
+var daemonRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run scheduled sync, consolidation, and learning-repo sync",
+	Long: `Run in the foreground and do mur's background work on schedule:
+
+  sync            'mur sync' every sync.interval_minutes (default 30),
+                  while sync.auto is on
+  consolidate     'mur consolidate --auto' per consolidation.schedule,
+                  while consolidation is enabled
+  learning-repo   'mur learn repo-sync' every sync.interval_minutes,
+                  once a learning repo is set up (mur learn init)
+
+The config is re-read every minute, so changes apply without a restart.
+When each job last ran is kept in the state directory, so a restart
+doesn't run everything again. Every run is logged as a JSON line to
+logs/daemon.log in the state directory, and the daemon writes a heartbeat
+for 'mur daemon health'.
+
+Usually started by launchd or systemd; see 'mur daemon install'.
+
+Examples:
+  mur daemon run
+  mur daemon run --once   # Run what is due now and exit`,
+	RunE: runDaemonRun,
+}
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install and start the daemon as a launchd agent or systemd user service",
+	Long: `Install 'mur daemon run' as a launchd agent (macOS) or systemd user
+service (Linux) that starts at login and restarts if it fails, and start
+it. This turns on sync.auto, and replaces the timer 'mur sync auto
+enable' installs, so sync doesn't run twice.
+
+Examples:
+  mur daemon install
+  mur daemon install --dry-run   # Print the unit without installing`,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the daemon service",
+	RunE:  runDaemonUninstall,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonRunCmd)
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+
+	daemonRunCmd.Flags().Bool("once", false, "Run the jobs that are due and exit")
+	daemonInstallCmd.Flags().Bool("dry-run", false, "Print the service definition without installing it")
+}
+
+func runDaemonRun(cmd *cobra.Command, args []string) error {
+	once, _ := cmd.Flags().GetBool("once")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	logger, closer, err := daemon.NewLogger(daemon.LogPath(home))
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	statePath := daemon.StatePath(home)
+	state, err := daemon.LoadState(statePath)
+	if err != nil {
+		logger.Warn("state unreadable, starting fresh", "error", err)
+		state = &daemon.State{LastRun: make(map[string]time.Time)}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	beat := func(status string) {
+		_ = heartbeat.Write(heartbeat.Beat{
+			Process:  heartbeat.ProcessDaemon,
+			Status:   status,
+			Interval: int(daemonTick / time.Second),
+		})
+	}
+
+	if !once {
+		logger.Info("daemon started", "pid", os.Getpid())
+		fmt.Printf("mur daemon running (log: %s)\n", daemon.LogPath(home))
+	}
+	ticker := time.NewTicker(daemonTick)
+	defer ticker.Stop()
+	for {
+		beat(heartbeat.StatusOK)
+		runDueJobs(ctx, logger, state, statePath)
+		if once {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			logger.Info("daemon stopped")
+			beat(heartbeat.StatusStopped)
+			return nil
+		}
+	}
+}
+
+// runDueJobs runs the jobs that are due, one at a time, and records
+// each run. A failed job counts as run, so it is retried on its next
+// interval rather than every minute.
+func runDueJobs(ctx context.Context, logger *slog.Logger, state *daemon.State, statePath string) {
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("config unreadable, skipping jobs", "error", err)
+		return
+	}
+	mur := murExecutable()
+
+	for _, job := range state.Due(daemon.Jobs(cfg, learning.IsInitialized()), time.Now()) {
+		if ctx.Err() != nil {
+			return
+		}
+		start := time.Now()
+		output, err := runDaemonJob(ctx, mur, job)
+		state.Ran(job.Name, start)
+		if err := state.Save(statePath); err != nil {
+			logger.Warn("cannot save state", "error", err)
+		}
+
+		attrs := []any{"job", job.Name, "duration_ms", time.Since(start).Milliseconds()}
+		if err != nil {
+			logger.Error("job failed", append(attrs, "error", err, "output", output)...)
+		} else {
+			logger.Info("job ran", attrs...)
+		}
+	}
+}
+
+// runDaemonJob runs a job as a mur subprocess and returns the end of its
+// output.
+func runDaemonJob(ctx context.Context, mur string, job daemon.Job) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, daemonJobTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	c := exec.CommandContext(ctx, mur, job.Args...)
+	c.Stdout = &buf
+	c.Stderr = &buf
+	err := c.Run()
+
+	output := strings.TrimSpace(buf.String())
+	if len(output) > 2000 {
+		output = "..." + output[len(output)-2000:]
+	}
+	return output, err
+}
+
+// murExecutable returns the path of the mur binary for services and
+// subprocesses: the one on PATH, else this one.
+func murExecutable() string {
+	if p, err := exec.LookPath("mur"); err == nil {
+		return p
+	}
+	if self, err := os.Executable(); err == nil {
+		return self
+	}
+	return "mur"
+}
+
+const launchdDaemonPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>run.mur.daemon</string>
+    <key>ProgramArguments</key>
+    <array>
+        <string>{{.MurPath}}</string>
+        <string>daemon</string>
+        <string>run</string>
+    </array>
+    <key>RunAtLoad</key>
+    <true/>
+    <key>KeepAlive</key>
+    <dict>
+        <key>SuccessfulExit</key>
+        <false/>
+    </dict>
+    <key>ThrottleInterval</key>
+    <integer>30</integer>
+    <key>StandardOutPath</key>
+    <string>{{.LogPath}}</string>
+    <key>StandardErrorPath</key>
+    <string>{{.LogPath}}</string>
+</dict>
+</plist>
+`
+
+const systemdDaemonUnit = `[Unit]
+Description=mur background sync and consolidation
+After=network-online.target
+
+[Service]
+ExecStart={{.MurPath}} daemon run
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=default.target
+`
+
+// daemonUnit returns where the daemon's service definition goes on this
+// platform and its contents.
+func daemonUnit(home string) (string, string, error) {
+	data := struct{ MurPath, LogPath string }{
+		murExecutable(),
+		filepath.Join(filepath.Dir(daemon.LogPath(home)), "daemon.out"),
+	}
+	var path, tmpl string
+	switch runtime.GOOS {
+	case "darwin":
+		path = filepath.Join(home, "Library", "LaunchAgents", "run.mur.daemon.plist")
+		tmpl = launchdDaemonPlist
+	case "linux":
+		path = filepath.Join(home, ".config", "systemd", "user", "mur-daemon.service")
+		tmpl = systemdDaemonUnit
+	default:
+		return "", "", fmt.Errorf("mur daemon install supports macOS and Linux; on %s, start 'mur daemon run' at login yourself", runtime.GOOS)
+	}
+	var buf bytes.Buffer
+	if err := template.Must(template.New("unit").Parse(tmpl)).Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return path, buf.String(), nil
+}
+
+func runDaemonInstall(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path, unit, err := daemonUnit(home)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("# %s\n%s", path, unit)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if !cfg.Sync.Auto {
+		cfg.Sync.Auto = true
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+	}
+	if replaced := removeAutoSyncTimer(home); replaced != "" {
+		fmt.Printf("  Replaced the auto-sync timer (%s)\n", replaced)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	ctx := cmd.Context()
+	if runtime.GOOS == "darwin" {
+		_, _ = execx.Run(ctx, "launchctl", "unload", path)
+		if _, err := execx.Run(ctx, "launchctl", "load", path); err != nil {
+			return fmt.Errorf("launchctl load: %w", err)
+		}
+	} else {
+		_, _ = execx.Run(ctx, "systemctl", "--user", "daemon-reload")
+		if _, err := execx.Run(ctx, "systemctl", "--user", "enable", "--now", "mur-daemon.service"); err != nil {
+			return fmt.Errorf("systemctl enable: %w", err)
+		}
+	}
+
+	fmt.Println("✅ mur daemon installed and started")
+	fmt.Printf("   Service: %s\n", path)
+	fmt.Printf("   Log:     %s\n", daemon.LogPath(home))
+	fmt.Println("   Check it with 'mur daemon health'")
+	return nil
+}
+
+func runDaemonUninstall(cmd *cobra.Command, args []string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path, _, err := daemonUnit(home)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Println("mur daemon is not installed")
+		return nil
+	}
+
+	ctx := cmd.Context()
+	if runtime.GOOS == "darwin" {
+		_, _ = execx.Run(ctx, "launchctl", "unload", path)
+	} else {
+		_, _ = execx.Run(ctx, "systemctl", "--user", "disable", "--now", "mur-daemon.service")
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if runtime.GOOS == "linux" {
+		_, _ = execx.Run(ctx, "systemctl", "--user", "daemon-reload")
+	}
+
+	fmt.Println("✅ mur daemon stopped and removed")
+	return nil
+}
+
+// removeAutoSyncTimer removes the scheduler 'mur sync auto enable'
+// installs, if any, and returns its path.
+func removeAutoSyncTimer(home string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		plist := filepath.Join(home, "Library", "LaunchAgents", "run.mur.sync.plist")
+		if _, err := os.Stat(plist); err != nil {
+			return ""
+		}
+		_, _ = execx.Run(context.Background(), "launchctl", "unload", plist)
+		_ = os.Remove(plist)
+		return plist
+	case "linux":
+		dir := filepath.Join(home, ".config", "systemd", "user")
+		timer := filepath.Join(dir, "mur-sync.timer")
+		if _, err := os.Stat(timer); err != nil {
+			return ""
+		}
+		_, _ = execx.Run(context.Background(), "systemctl", "--user", "disable", "--now", "mur-sync.timer")
+		_ = os.Remove(timer)
+		_ = os.Remove(filepath.Join(dir, "mur-sync.service"))
+		return timer
+	}
+	return ""
+}
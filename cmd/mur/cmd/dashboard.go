@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"html/template"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/server"
 	"github.com/mur-run/mur-core/internal/stats"
 )
 
@@ -47,24 +49,16 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 	patterns, err := store.List()
 	if err != nil {
 		return fmt.Errorf("failed to load patterns: %w", err)
 	}
 
-	data := buildStaticDashboardData(patterns)
+	data := buildStaticDashboardData(patterns, time.Time{})
 
-	funcMap := template.FuncMap{
-		"mul":    func(a, b float64) float64 { return a * b },
-		"sub":    func(a, b float64) float64 { return a - b },
-		"gt0":    func(a float64) bool { return a > 0 },
-		"lt":     func(a, b float64) bool { return a < b },
-		"printf": fmt.Sprintf,
-	}
-
-	tmpl := template.Must(template.New("dashboard").Funcs(funcMap).Parse(staticDashboardHTML))
+	tmpl := template.Must(template.New("dashboard").Funcs(staticDashboardFuncs()).Parse(staticDashboardHTML))
 
 	// Determine output
 	var output *os.File
@@ -101,10 +95,30 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
-	data := DashboardData{
-		Patterns:    make([]PatternView, 0, len(patterns)),
-		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+// staticDashboardFuncs returns the template helpers used by staticDashboardHTML.
+func staticDashboardFuncs() template.FuncMap {
+	return template.FuncMap{
+		"mul":    func(a, b float64) float64 { return a * b },
+		"sub":    func(a, b float64) float64 { return a - b },
+		"gt0":    func(a float64) bool { return a > 0 },
+		"lt":     func(a, b float64) bool { return a < b },
+		"printf": fmt.Sprintf,
+		"sub1":   func(n int) int { return n - 1 },
+		"pct": func(n, max int) int {
+			if max == 0 {
+				return 0
+			}
+			return n * 100 / max
+		},
+	}
+}
+
+// buildStaticDashboardData collects pattern and usage data for the static
+// dashboard. A non-zero since limits usage stats to that period.
+func buildStaticDashboardData(patterns []pattern.Pattern, since time.Time) server.DashboardData {
+	data := server.DashboardData{
+		Patterns:    make([]server.PatternView, 0, len(patterns)),
+		GeneratedAt: time.Now().In(stats.DisplayLocation()).Format("2006-01-02 15:04:05"),
 		Version:     Version,
 	}
 
@@ -112,7 +126,7 @@ func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
 	effectiveCount := 0
 
 	for _, p := range patterns {
-		view := patternToView(&p)
+		view := server.PatternToView(&p)
 		data.Patterns = append(data.Patterns, view)
 		data.TotalUsage += view.UsageCount
 
@@ -124,6 +138,10 @@ func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
 		if view.Status == "active" || view.Status == "" {
 			data.ActivePatterns++
 		}
+
+		if !since.IsZero() && p.Lifecycle.Created.After(since) {
+			data.NewPatterns++
+		}
 	}
 
 	data.TotalPatterns = len(patterns)
@@ -132,7 +150,7 @@ func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
 	}
 
 	// Sort for top patterns
-	sorted := make([]PatternView, len(data.Patterns))
+	sorted := make([]server.PatternView, len(data.Patterns))
 	copy(sorted, data.Patterns)
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].Effectiveness > sorted[j].Effectiveness
@@ -143,20 +161,38 @@ func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
 	data.TopPatterns = sorted
 
 	// Load usage stats
-	records, _ := stats.Query(stats.QueryFilter{})
+	records, _ := stats.Query(stats.QueryFilter{StartTime: since})
 	if len(records) > 0 {
 		summary := stats.Summarize(records)
 		data.TotalRuns = summary.TotalRuns
 		data.EstimatedCost = summary.EstimatedCost
 		data.EstimatedSaved = summary.EstimatedSaved
+		data.AutoRouteStats = server.AutoRouteView{
+			Total:     summary.AutoRouteStats.Total,
+			ToFree:    summary.AutoRouteStats.ToFree,
+			ToPaid:    summary.AutoRouteStats.ToPaid,
+			FreeRatio: summary.AutoRouteStats.FreeRatio,
+		}
+
+		// Trend covers the requested period (capped at a year), or the last week
+		days := 7
+		if !since.IsZero() {
+			days = int(time.Since(since).Hours()/24) + 1
+			if days > 365 {
+				days = 365
+			}
+		}
 
-		for _, d := range summary.DailyTrend {
+		for _, d := range stats.DailyTrend(records, days) {
 			date, _ := time.Parse("2006-01-02", d.Date)
-			data.DailyTrend = append(data.DailyTrend, DailyPoint{
+			data.DailyTrend = append(data.DailyTrend, server.DailyPoint{
 				Date:  d.Date,
 				Day:   date.Format("Mon"),
 				Count: d.Count,
 			})
+			if d.Count > data.TrendMax {
+				data.TrendMax = d.Count
+			}
 		}
 
 		for name, ts := range summary.ByTool {
@@ -164,7 +200,7 @@ func buildStaticDashboardData(patterns []pattern.Pattern) DashboardData {
 			if name == "gemini" || name == "auggie" {
 				tier = "free"
 			}
-			data.ToolBreakdown = append(data.ToolBreakdown, ToolUsage{
+			data.ToolBreakdown = append(data.ToolBreakdown, server.ToolUsage{
 				Name:       name,
 				Count:      ts.Count,
 				Percentage: float64(ts.Count) / float64(summary.TotalRuns) * 100,
@@ -253,6 +289,11 @@ const staticDashboardHTML = `<!DOCTYPE html>
             gap: 0.5rem;
         }
         
+        .compare { width: 100%; border-collapse: collapse; font-size: 0.875rem; }
+        .compare th { text-align: right; color: var(--text2); font-weight: normal; padding: 0.25rem 0.5rem; }
+        .compare td { text-align: right; padding: 0.25rem 0.5rem; border-top: 1px solid var(--border); }
+        .compare th:first-child, .compare td:first-child { text-align: left; }
+
         .patterns { display: grid; gap: 1rem; }
         .pattern {
             background: var(--bg2);
@@ -311,6 +352,21 @@ const staticDashboardHTML = `<!DOCTYPE html>
         }
         .bar-fill.free { background: linear-gradient(90deg, var(--green), #22d3ee); }
         .bar-value { width: 50px; text-align: right; font-size: 0.875rem; color: var(--text2); }
+
+        .trend { display: flex; align-items: flex-end; gap: 2px; height: 120px; }
+        .trend-bar {
+            flex: 1;
+            min-height: 2px;
+            background: linear-gradient(180deg, var(--accent), #818cf8);
+            border-radius: 2px 2px 0 0;
+        }
+        .trend-labels {
+            display: flex;
+            justify-content: space-between;
+            margin-top: 0.5rem;
+            font-size: 0.75rem;
+            color: var(--muted);
+        }
         
         footer {
             text-align: center;
@@ -325,7 +381,7 @@ const staticDashboardHTML = `<!DOCTYPE html>
     <div class="container">
         <header>
             <div class="logo">mur<span>.report</span></div>
-            <div class="meta">Generated: {{.GeneratedAt}}</div>
+            <div class="meta">{{if .Period}}Period: {{.Period}} · {{end}}Generated: {{.GeneratedAt}}</div>
         </header>
         
         <div class="grid grid-4">
@@ -351,6 +407,58 @@ const staticDashboardHTML = `<!DOCTYPE html>
             </div>
         </div>
         
+        {{if .Period}}
+        <div class="grid grid-4">
+            <div class="card">
+                <div class="card-title">New Patterns</div>
+                <div class="stat">{{.NewPatterns}}</div>
+                <div class="stat-sub">in period</div>
+            </div>
+            <div class="card">
+                <div class="card-title">Runs</div>
+                <div class="stat">{{.TotalRuns}}</div>
+                <div class="stat-sub">in period</div>
+            </div>
+            <div class="card">
+                <div class="card-title">Cost</div>
+                <div class="stat yellow">${{printf "%.2f" .EstimatedCost}}</div>
+                <div class="stat-sub">estimated</div>
+            </div>
+            <div class="card">
+                <div class="card-title">Auto-Routed</div>
+                <div class="stat green">{{printf "%.0f" .AutoRouteStats.FreeRatio}}%</div>
+                <div class="stat-sub">{{.AutoRouteStats.ToFree}} of {{.AutoRouteStats.Total}} to free tools</div>
+            </div>
+        </div>
+        {{end}}
+
+        {{with .Comparison}}
+        <div class="card" style="margin-bottom: 2rem;">
+            <div class="card-title">Compared with {{.Baseline.Label}}</div>
+            <table class="compare">
+                <tr><th>Metric</th><th>This period</th><th>{{.Baseline.Label}}</th><th>Change</th></tr>
+                {{range .Deltas}}
+                <tr><td>{{.Metric}}</td><td>{{.Value .Current}}</td><td>{{.Value .Baseline}}</td><td>{{.ChangeString}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+
+        {{if .TrendMax}}
+        <div class="card" style="margin-bottom: 2rem;">
+            <h2>📈 Usage Trend</h2>
+            <div class="trend">
+                {{range .DailyTrend}}
+                <div class="trend-bar" style="height: {{pct .Count $.TrendMax}}%;" title="{{.Date}}: {{.Count}}"></div>
+                {{end}}
+            </div>
+            <div class="trend-labels">
+                <span>{{(index .DailyTrend 0).Date}}</span>
+                <span>{{(index .DailyTrend (len .DailyTrend | sub1)).Date}}</span>
+            </div>
+        </div>
+        {{end}}
+
         {{if .ToolBreakdown}}
         <div class="card" style="margin-bottom: 2rem;">
             <h2>🔧 Tool Usage</h2>
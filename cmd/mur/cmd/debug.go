@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/timing"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshooting tools",
+}
+
+var debugTimingsCmd = &cobra.Command{
+	Use:   "timings [--profile <dir>] <command> [args...]",
+	Short: "Run a command and report where its time went",
+	Long: `Run a mur command and print a breakdown of its startup and IO phases
+(config load, pattern listing, semantic search setup, embedding queries,
+network requests) to stderr, with the time and memory each allocated.
+
+Hook commands like 'mur context' run on every prompt and should finish in
+well under 30ms. Setting MUR_TIMINGS=1 prints the same report for any
+command, e.g. from inside a hook. Either way the run is added to the
+timings log that 'mur debug profile report' summarizes.
+
+--profile <dir> (before the command) also writes Go pprof profiles of the
+run to dir: cpu.pprof and heap.pprof, for 'go tool pprof'.
+
+Examples:
+  mur debug timings context --prompt "fix the flaky test"
+  mur debug timings search --inject "deploy to staging"
+  mur debug timings --profile /tmp/mur-prof sync`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profileDir, args, err := splitProfileFlag(args)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+
+		if profileDir != "" {
+			stop, err := startProfiles(profileDir)
+			if err != nil {
+				return err
+			}
+			defer stop()
+		}
+
+		timing.Enable()
+		defer timing.Track("command")()
+
+		// The wrapped command reports its own errors and usage.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+
+		rootCmd.SetArgs(args)
+		c, err := rootCmd.ExecuteC()
+		if c != nil {
+			timedCommand = c.CommandPath()
+		}
+		return err
+	},
+}
+
+// splitProfileFlag takes a leading --profile <dir> or --profile=<dir> off
+// the arguments of 'mur debug timings'.
+func splitProfileFlag(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", args, nil
+	}
+	if dir, ok := strings.CutPrefix(args[0], "--profile="); ok {
+		return dir, args[1:], nil
+	}
+	if args[0] != "--profile" {
+		return "", args, nil
+	}
+	if len(args) < 2 {
+		return "", nil, fmt.Errorf("--profile needs a directory")
+	}
+	return args[1], args[2:], nil
+}
+
+// startProfiles starts a CPU profile in dir. The returned function stops
+// it and writes a heap profile next to it.
+func startProfiles(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create profile directory: %w", err)
+	}
+	cpu, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, fmt.Errorf("cannot start CPU profile: %w", err)
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		cpu.Close()
+
+		heapPath := filepath.Join(dir, "heap.pprof")
+		heap, err := os.Create(heapPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Cannot write heap profile: %v\n", err)
+			return
+		}
+		defer heap.Close()
+		runtime.GC() // up-to-date live heap
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Cannot write heap profile: %v\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "📈 Profiles written to %s (go tool pprof %s)\n", dir, heapPath)
+	}, nil
+}
+
+var debugProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Summarize recorded command timings",
+}
+
+var debugProfileReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the slowest commands and phases from the timings log",
+	Long: `Summarize the timings log: every run of 'mur debug timings' and every
+command run with MUR_TIMINGS=1 (the latest 500). Shows how long each
+command takes, and the phases that took the most time overall, with their
+mean, 95th percentile and worst duration and mean memory allocated.
+
+Examples:
+  mur debug profile report
+  mur debug profile report --command "mur context" --top 5
+  mur debug profile report --since 168h --json`,
+	RunE: runDebugProfileReport,
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugTimingsCmd)
+	debugCmd.AddCommand(debugProfileCmd)
+	debugProfileCmd.AddCommand(debugProfileReportCmd)
+
+	debugProfileReportCmd.Flags().Int("top", 10, "Phases to show")
+	debugProfileReportCmd.Flags().String("command", "", "Only runs of this command (e.g. \"mur context\")")
+	debugProfileReportCmd.Flags().String("since", "", "Only runs since a date or duration (e.g. 2026-10-01, 24h)")
+	debugProfileReportCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runDebugProfileReport(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	command, _ := cmd.Flags().GetString("command")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	path, err := timingsLogPath()
+	if err != nil {
+		return err
+	}
+	all, err := timing.LoadRuns(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read timings log: %w", err)
+	}
+	since := parseTimeOrDuration(sinceStr)
+	var runs []timing.Run
+	for _, r := range all {
+		if command != "" && r.Command != command && r.Command != "mur "+command {
+			continue
+		}
+		if !since.IsZero() && r.At.Before(since) {
+			continue
+		}
+		runs = append(runs, r)
+	}
+
+	commands := timing.SummarizeCommands(runs)
+	phases := timing.Summarize(runs)
+	if top > 0 && len(phases) > top {
+		phases = phases[:top]
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(map[string]any{
+			"runs":     len(runs),
+			"commands": commands,
+			"phases":   phases,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No timed runs recorded yet.")
+		fmt.Println("Time a command with 'mur debug timings <command>' or MUR_TIMINGS=1.")
+		return nil
+	}
+
+	fmt.Printf("⏱  Timings from %d runs since %s\n\n", len(runs), runs[0].At.Format("2006-01-02 15:04"))
+	fmt.Println("Commands")
+	printPhaseStats(commands)
+	fmt.Println()
+	fmt.Println("Slowest phases (by total time)")
+	printPhaseStats(phases)
+	return nil
+}
+
+func printPhaseStats(stats []timing.PhaseStats) {
+	fmt.Printf("   %5s  %9s  %9s  %9s  %8s  %s\n", "runs", "mean", "p95", "max", "alloc", "name")
+	for _, s := range stats {
+		fmt.Printf("   %5d  %9s  %9s  %9s  %7dK  %s\n",
+			s.Count, fmtMillis(s.Mean), fmtMillis(s.P95), fmtMillis(s.Max), (s.Allocs+512)/1024, s.Name)
+	}
+}
+
+func fmtMillis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
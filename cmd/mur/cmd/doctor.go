@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,7 +14,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
 	murhooks "github.com/mur-run/mur-core/internal/hooks"
+	"github.com/mur-run/mur-core/internal/netpolicy"
 	"github.com/mur-run/mur-core/internal/sysinfo"
 )
 
@@ -27,6 +32,7 @@ Checks:
   - Hook configurations
   - Pattern validity
   - Sync status
+  - Network policy (network.mode) enforcement
 
 Examples:
   mur doctor         # Run all checks
@@ -63,7 +69,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	var fixable []checkResult
 
 	// Check 1: .mur directory
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	if info, err := os.Stat(murDir); err != nil || !info.IsDir() {
 		checks = append(checks, checkResult{
 			name:    "~/.mur directory",
@@ -95,7 +101,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		files, _ := os.ReadDir(patternsDir)
 		yamlCount := 0
 		for _, f := range files {
-			if strings.HasSuffix(f.Name(), ".yaml") {
+			if pattern.IsPatternFile(f.Name()) {
 				yamlCount++
 			}
 		}
@@ -106,6 +112,34 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	// Check 2b: config file parses
+	configFile := filepath.Join(config.ConfigDir(home), "config.yaml")
+	if _, err := config.Load(); err != nil {
+		var parseErr *config.ParseError
+		if errors.As(err, &parseErr) {
+			checks = append(checks, checkResult{
+				name:    "config.yaml",
+				status:  "error",
+				message: "Cannot be parsed (run: mur config repair)",
+				fix: func() error {
+					_, err := config.Repair(configFile, false)
+					return err
+				},
+			})
+		} else {
+			checks = append(checks, checkResult{
+				name:    "config.yaml",
+				status:  "error",
+				message: err.Error(),
+			})
+		}
+	} else {
+		checks = append(checks, checkResult{
+			name:   "config.yaml",
+			status: "ok",
+		})
+	}
+
 	// Check 3: AI CLIs
 	clis := []struct {
 		name   string
@@ -176,7 +210,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check 4: Claude hooks (in settings.json)
-	claudeSettingsPath := filepath.Join(home, ".claude", "settings.json")
+	claudeSettingsPath := filepath.Join(config.ClaudeDir(home), "settings.json")
 	if _, err := os.Stat(claudeSettingsPath); err == nil {
 		content, _ := os.ReadFile(claudeSettingsPath)
 		if strings.Contains(string(content), "mur") || strings.Contains(string(content), "on-prompt.sh") {
@@ -251,12 +285,75 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	// Check 5d: Windsurf hooks and Zed tasks, when those editors are set up
+	if murhooks.WindsurfInstalled() {
+		if installed, _ := murhooks.CheckWindsurfHooks(); installed {
+			checks = append(checks, checkResult{
+				name:   "Windsurf hooks",
+				status: "ok",
+			})
+		} else {
+			checks = append(checks, checkResult{
+				name:    "Windsurf hooks",
+				status:  "warn",
+				message: "Not installed (run: mur hooks install windsurf)",
+			})
+		}
+	}
+	if murhooks.ZedInstalled() {
+		if installed, _ := murhooks.CheckZedHooks(); installed {
+			checks = append(checks, checkResult{
+				name:   "Zed tasks",
+				status: "ok",
+			})
+		} else {
+			checks = append(checks, checkResult{
+				name:    "Zed tasks",
+				status:  "warn",
+				message: "Not installed (run: mur hooks install zed)",
+			})
+		}
+	}
+
+	// Check 5e: hooks run a mur binary that exists
+	if !murhooks.OnMinimalPath() {
+		checks = append(checks, checkResult{
+			name:    "mur in hook PATH",
+			status:  "info",
+			message: "Not on " + murhooks.MinimalPath + "; hooks need absolute paths",
+		})
+	}
+	var brokenRefs int
+	binaryRefs := murhooks.CheckBinaryRefs()
+	for _, r := range binaryRefs {
+		if r.Problem != "" {
+			brokenRefs++
+		}
+	}
+	if brokenRefs == 0 && len(binaryRefs) > 0 {
+		checks = append(checks, checkResult{
+			name:    "Hook binary paths",
+			status:  "ok",
+			message: binaryRefs[0].Path,
+		})
+	} else if brokenRefs > 0 {
+		checks = append(checks, checkResult{
+			name:    "Hook binary paths",
+			status:  "error",
+			message: fmt.Sprintf("%d hook command(s) won't run mur (see: mur completion doctor)", brokenRefs),
+			fix: func() error {
+				_, err := murhooks.RepairBinaryRefs(murhooks.MurBinary())
+				return err
+			},
+		})
+	}
+
 	// Check 6: Sync targets
 	syncTargets := []struct {
 		name string
 		path string
 	}{
-		{"Claude skills", filepath.Join(home, ".claude", "skills", "mur")},
+		{"Claude skills", filepath.Join(config.ClaudeDir(home), "skills", "mur")},
 		{"Gemini skills", filepath.Join(home, ".gemini", "skills", "mur")},
 		{"Codex instructions", filepath.Join(home, ".codex", "instructions.md")},
 		{"Continue rules", filepath.Join(home, ".continue", "rules", "mur")},
@@ -425,6 +522,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Check 11: network policy, and that every HTTP client is held to it
+	checks = append(checks, checkNetworkPolicy(cfg)...)
+
 	// Print results
 	errorCount := 0
 	warnCount := 0
@@ -500,3 +600,89 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// checkNetworkPolicy reports network.mode and verifies that requests can't
+// get past it. Git remotes (learning.repo, team.repo, the repos mur syncs
+// with git) are reached by git, which execx only runs for remotes the
+// policy allows, so a remote outside it is an error: that sync can't work.
+func checkNetworkPolicy(cfg *config.Config) []checkResult {
+	p, err := netpolicy.Current()
+	if err != nil {
+		return []checkResult{{name: "Network policy", status: "error", message: fmt.Sprintf("%v (all connections blocked)", err)}}
+	}
+	if err := netpolicy.Verify(); err != nil {
+		return []checkResult{{name: "Network policy", status: "error", message: fmt.Sprintf("Not enforced: %v", err)}}
+	}
+
+	var checks []checkResult
+	switch p.Mode {
+	case netpolicy.ModeOffline:
+		checks = append(checks, checkResult{name: "Network policy", status: "ok", message: "offline: only localhost; all HTTP clients enforce it"})
+	case netpolicy.ModeAllowlist:
+		checks = append(checks, checkResult{name: "Network policy", status: "ok", message: fmt.Sprintf("allowlist: localhost and %d entries; all HTTP clients enforce it", len(p.Allow))})
+	default:
+		return []checkResult{{name: "Network policy", status: "info", message: "normal (set network.mode to restrict)"}}
+	}
+
+	remotes := gitRemotes(cfg)
+	var blocked []string
+	for _, r := range remotes {
+		if err := execx.CheckRemote(netpolicy.WithPolicy(context.Background(), p), r.url); err != nil {
+			blocked = append(blocked, fmt.Sprintf("%s (%s)", r.name, r.url))
+		}
+	}
+	switch {
+	case len(blocked) > 0:
+		checks = append(checks, checkResult{
+			name:    "Git remotes",
+			status:  "error",
+			message: fmt.Sprintf("outside network.mode, git can't reach %s", strings.Join(blocked, ", ")),
+		})
+	case len(remotes) > 0:
+		checks = append(checks, checkResult{
+			name:    "Git remotes",
+			status:  "ok",
+			message: fmt.Sprintf("%d allowed by network.mode", len(remotes)),
+		})
+	}
+	return checks
+}
+
+// gitRemote is a remote mur reaches with git.
+type gitRemote struct {
+	name string
+	url  string
+}
+
+// gitRemotes returns the configured learning and team repos and the
+// remotes of the repos mur syncs with git.
+func gitRemotes(cfg *config.Config) []gitRemote {
+	var remotes []gitRemote
+	if cfg != nil && cfg.Learning.Repo != "" {
+		remotes = append(remotes, gitRemote{"learning.repo", cfg.Learning.Repo})
+	}
+	if cfg != nil && cfg.Team.Repo != "" {
+		remotes = append(remotes, gitRemote{"team.repo", cfg.Team.Repo})
+	}
+	home, _ := os.UserHomeDir()
+	for _, name := range []string{"repo", "learning-repo", "team"} {
+		dir := filepath.Join(config.DataDir(home), name)
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			continue
+		}
+		res, err := execx.Run(context.Background(), "git", "-C", dir, "remote", "-v")
+		if err != nil {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(res.Stdout, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || seen[fields[1]] {
+				continue
+			}
+			seen[fields[1]] = true
+			remotes = append(remotes, gitRemote{fmt.Sprintf("~/.mur/%s %s", name, fields[0]), fields[1]})
+		}
+	}
+	return remotes
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
 var editCmd = &cobra.Command{
@@ -35,29 +40,26 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternPath := filepath.Join(home, ".mur", "patterns", patternName+".yaml")
+	patternPath := filepath.Join(config.DataDir(home), "patterns", patternName+".yaml")
 
 	// Check if pattern exists
 	if _, err := os.Stat(patternPath); os.IsNotExist(err) {
-		return fmt.Errorf("pattern not found: %s\nUse 'mur learn list' to see available patterns", patternName)
-	}
-
-	// Get editor
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		// Try common editors
-		for _, e := range []string{"vim", "nano", "vi"} {
-			if _, err := exec.LookPath(e); err == nil {
-				editor = e
-				break
-			}
+		zstPath := strings.TrimSuffix(patternPath, ".yaml") + pattern.CompressedExt
+		if _, err := os.Stat(zstPath); err != nil {
+			return fmt.Errorf("pattern not found: %s\nUse 'mur learn list' to see available patterns", patternName)
+		}
+		// Editors can't open compressed patterns; store it plain until the
+		// next 'mur migrate compress'.
+		if err := decompressPatternFile(zstPath, patternPath); err != nil {
+			return err
 		}
 	}
-	if editor == "" {
-		return fmt.Errorf("no editor found. Set $EDITOR environment variable")
+
+	before, _ := os.ReadFile(patternPath)
+
+	editor, err := findEditor()
+	if err != nil {
+		return err
 	}
 
 	// Open editor
@@ -70,6 +72,13 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("editor exited with error: %w", err)
 	}
 
+	// Keep the version before the edit; see 'mur learn history'
+	if after, err := os.ReadFile(patternPath); err == nil && before != nil && !bytes.Equal(before, after) {
+		if _, err := pattern.NewStore(filepath.Dir(patternPath)).SaveRevision(patternName, before, "edit"); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not keep the previous version: %v\n", err)
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("✅ Pattern saved:", patternName)
 	fmt.Println("   Run 'mur lint", patternName+"' to validate")
@@ -77,3 +86,36 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// findEditor returns $EDITOR or $VISUAL, falling back to vim/nano/vi.
+func findEditor() (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		// Try common editors
+		for _, e := range []string{"vim", "nano", "vi"} {
+			if _, err := exec.LookPath(e); err == nil {
+				editor = e
+				break
+			}
+		}
+	}
+	if editor == "" {
+		return "", fmt.Errorf("no editor found. Set $EDITOR environment variable")
+	}
+	return editor, nil
+}
+
+// decompressPatternFile replaces a .yaml.zst pattern with a plain one.
+func decompressPatternFile(zstPath, plainPath string) error {
+	data, err := pattern.ReadFile(zstPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(plainPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write pattern: %w", err)
+	}
+	return os.Remove(zstPath)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -21,13 +25,14 @@ finding relevant patterns even when keywords don't match exactly.
 
 Requires an embedding provider:
   - ollama (default, local): ollama pull nomic-embed-text
-  - openai: Set OPENAI_API_KEY
+  - openai: set search.provider to openai and OPENAI_API_KEY
 
 Examples:
   mur embed index             # Index all patterns
   mur embed status            # Show embedding status
   mur embed search "query"    # Test semantic search
-  mur embed rehash            # Rebuild all embeddings`,
+  mur embed rehash            # Rebuild all embeddings
+  mur embed migrate --to openai  # Switch the index to another provider`,
 }
 
 var embedIndexCmd = &cobra.Command{
@@ -55,22 +60,38 @@ var embedRehashCmd = &cobra.Command{
 	RunE:  embedRehashExecute,
 }
 
-func getEmbedConfig() embed.Config {
-	cfg := embed.DefaultConfig()
+// getEmbedConfig returns the search.provider settings, so these commands
+// embed with the same model as the index.
+var embedMigrateCmd = &cobra.Command{
+	Use:   "migrate --to <provider>",
+	Short: "Re-embed all patterns with another provider or model",
+	Long: `Re-embed all patterns with another embedding provider or model.
 
-	// Check for OpenAI key
-	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
-		cfg.Provider = "openai"
-		cfg.Model = "text-embedding-3-small"
-		cfg.APIKey = key
-	}
+Vectors from different models can't be compared, so search refuses an
+index built with another model than search.provider/search.model. migrate
+re-embeds every pattern with the new model in batches, swaps the index in
+and updates search.provider and search.model. The old index is kept so
+--rollback can restore it.
+
+Examples:
+  mur embed migrate --to openai --dry-run   # Show the cost estimate only
+  mur embed migrate --to openai
+  mur embed migrate --to ollama --model nomic-embed-text
+  mur embed migrate --rollback`,
+	RunE: embedMigrateExecute,
+}
 
-	return cfg
+func getEmbedConfig() embed.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		return embed.DefaultConfig()
+	}
+	return embed.SearchConfig(cfg)
 }
 
 func embedIndexExecute(cmd *cobra.Command, args []string) error {
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := getEmbedConfig()
@@ -94,7 +115,7 @@ func embedIndexExecute(cmd *cobra.Command, args []string) error {
 
 func embedStatusExecute(cmd *cobra.Command, args []string) error {
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := getEmbedConfig()
@@ -128,7 +149,7 @@ func embedSearchExecute(cmd *cobra.Command, args []string) error {
 	topK, _ := cmd.Flags().GetInt("top")
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := getEmbedConfig()
@@ -164,7 +185,7 @@ func embedSearchExecute(cmd *cobra.Command, args []string) error {
 
 func embedRehashExecute(cmd *cobra.Command, args []string) error {
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := getEmbedConfig()
@@ -186,6 +207,141 @@ func embedRehashExecute(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func embedMigrateExecute(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+	model, _ := cmd.Flags().GetString("model")
+	batch, _ := cmd.Flags().GetInt("batch")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+	rollback, _ := cmd.Flags().GetBool("rollback")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if rollback {
+		return embedMigrateRollback(cfg)
+	}
+	if to == "" {
+		return fmt.Errorf("--to <provider> is required (ollama, openai, voyage, google)")
+	}
+	if model == "" {
+		if to == cfg.Search.Provider {
+			model = cfg.Search.Model
+		} else if model = embed.DefaultModels[to]; model == "" {
+			return fmt.Errorf("unknown embedding provider %q, or pass --model", to)
+		}
+	}
+
+	target := *cfg
+	target.Search.Provider = to
+	target.Search.Model = model
+	if to == "ollama" {
+		if !embed.IsOllamaRunning(target.Search.OllamaURL) {
+			return fmt.Errorf("ollama is not running at %s\nStart with: ollama serve", target.Search.OllamaURL)
+		}
+		if !embed.HasOllamaModel(target.Search.OllamaURL, model) {
+			return fmt.Errorf("model %s not found\nInstall with: ollama pull %s", model, model)
+		}
+	}
+
+	indexer, err := embed.NewPatternIndexer(&target)
+	if err != nil {
+		return fmt.Errorf("cannot create indexer: %w", err)
+	}
+	plan, err := indexer.PlanMigration()
+	if err != nil {
+		return err
+	}
+	if indexer.Check() == nil && len(plan.From) > 0 && plan.From[0].Model == plan.To {
+		fmt.Printf("✓ Index already uses %s\n", plan.To)
+		return nil
+	}
+
+	from := "an empty index"
+	if len(plan.From) > 0 {
+		parts := make([]string, len(plan.From))
+		for i, m := range plan.From {
+			parts[i] = m.String()
+		}
+		from = strings.Join(parts, " + ")
+	}
+	fmt.Printf("🔄 Migrating embeddings: %s → %s\n", from, plan.To)
+	fmt.Printf("  Patterns: %d (~%d tokens)\n", plan.Patterns, plan.Tokens)
+	switch {
+	case !plan.CostKnown:
+		fmt.Println("  Estimated cost: unknown for this model")
+	case strings.HasPrefix(plan.To, "ollama/"):
+		fmt.Println("  Estimated cost: free (local)")
+	default:
+		fmt.Printf("  Estimated cost: ~$%.4f\n", plan.Cost)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !yes {
+		fmt.Print("Re-embed all patterns? [y/N] ")
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	start := time.Now()
+	m, err := indexer.Migrate(batch, func(done, total int) {
+		fmt.Printf("\r  %s %d/%d", progressBar(done, total, 30), done, total)
+	})
+	fmt.Println()
+	if err != nil {
+		return fmt.Errorf("migration failed, index unchanged: %w", err)
+	}
+
+	cfg.Search.Provider = to
+	cfg.Search.Model = model
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("index migrated, but cannot update search settings: %w", err)
+	}
+
+	fmt.Printf("\n✅ Re-embedded %d patterns with %s (%d dims) in %.1fs\n", m.Patterns, m.To, m.Dimension, time.Since(start).Seconds())
+	fmt.Printf("  search.provider: %s, search.model: %s\n", to, model)
+	if len(m.From) > 0 {
+		fmt.Println("  Undo with: mur embed migrate --rollback")
+	}
+	return nil
+}
+
+// embedMigrateRollback restores the index replaced by the last migration
+// and the search settings it was built with.
+func embedMigrateRollback(cfg *config.Config) error {
+	m, err := embed.RollbackMigration(cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Restored the index from before %s\n", m.At.Local().Format("2006-01-02 15:04"))
+
+	if len(m.From) != 1 {
+		fmt.Println("  The restored index mixes models; set search.provider and search.model, then run 'mur embed migrate'")
+		return nil
+	}
+	provider, model, _ := strings.Cut(m.From[0].Model, "/")
+	if provider == "" {
+		return nil
+	}
+	cfg.Search.Provider = provider
+	if model != "" {
+		cfg.Search.Model = model
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("cannot restore search settings: %w", err)
+	}
+	fmt.Printf("  search.provider: %s, search.model: %s\n", cfg.Search.Provider, cfg.Search.Model)
+	return nil
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -200,6 +356,13 @@ func init() {
 	embedCmd.AddCommand(embedStatusCmd)
 	embedCmd.AddCommand(embedSearchCmd)
 	embedCmd.AddCommand(embedRehashCmd)
+	embedCmd.AddCommand(embedMigrateCmd)
 
 	embedSearchCmd.Flags().Int("top", 5, "Number of results to return")
+	embedMigrateCmd.Flags().String("to", "", "Provider to re-embed with (ollama, openai, voyage, google)")
+	embedMigrateCmd.Flags().String("model", "", "Model to re-embed with (default: the provider's default)")
+	embedMigrateCmd.Flags().Int("batch", 32, "Patterns per embedding request")
+	embedMigrateCmd.Flags().Bool("dry-run", false, "Show the cost estimate without re-embedding")
+	embedMigrateCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	embedMigrateCmd.Flags().Bool("rollback", false, "Restore the index and settings from before the last migration")
 }
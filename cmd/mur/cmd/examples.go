@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -355,7 +356,7 @@ func runExamplesInstall(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	if err := os.MkdirAll(patternsDir, 0755); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,13 +10,22 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/mur-run/mur-core/internal/core/export"
+	"github.com/mur-run/mur-core/internal/core/inject"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/server"
 )
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export patterns to various formats",
-	Long: `Export patterns to JSON, YAML, or Markdown format.
+	Long: `Export patterns to JSON, YAML, Markdown, or NDJSON format.
+
+NDJSON writes one pattern per line with its full metadata and usage
+aggregates, for loading into BI tools and warehouses. It always includes
+archived patterns, orders them by ID, and pages with --limit: the cursor
+for the next page is printed to stderr. 'mur serve' offers the same export
+at /api/v1/export/patterns.ndjson.
 
 Examples:
   mur export                           # Export all active patterns as YAML
@@ -23,7 +33,9 @@ Examples:
   mur export --format md               # Export as Markdown
   mur export --tag backend             # Export patterns with 'backend' tag
   mur export --min-effectiveness 0.7   # Export high-effectiveness patterns
-  mur export -o patterns.json          # Export to file`,
+  mur export -o patterns.json          # Export to file
+  mur export -f ndjson --fields name,usage --since 2026-01-01
+  mur export -f ndjson --limit 1000 --cursor <cursor>`,
 	RunE: runExport,
 }
 
@@ -33,17 +45,25 @@ var (
 	exportTag              string
 	exportMinEffectiveness float64
 	exportIncludeArchived  bool
+	exportFields           string
+	exportCursor           string
+	exportLimit            int
+	exportSince            string
 )
 
 func init() {
 	exportCmd.Hidden = true // Use 'mur sync' instead
 	rootCmd.AddCommand(exportCmd)
 
-	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "yaml", "Output format: yaml, json, md")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "yaml", "Output format: yaml, json, md, ndjson")
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default: stdout)")
 	exportCmd.Flags().StringVarP(&exportTag, "tag", "t", "", "Filter by tag")
 	exportCmd.Flags().Float64Var(&exportMinEffectiveness, "min-effectiveness", 0.0, "Minimum effectiveness score (0.0-1.0)")
 	exportCmd.Flags().BoolVar(&exportIncludeArchived, "include-archived", false, "Include archived patterns")
+	exportCmd.Flags().StringVar(&exportFields, "fields", "", "NDJSON: comma-separated fields to include (id is always included)")
+	exportCmd.Flags().StringVar(&exportCursor, "cursor", "", "NDJSON: resume after a previous page")
+	exportCmd.Flags().IntVar(&exportLimit, "limit", 0, "NDJSON: most patterns per page (0 = all)")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "NDJSON: only patterns updated or used since a date (2006-01-02 or RFC 3339)")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -52,6 +72,10 @@ func runExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot access pattern store: %w", err)
 	}
 
+	if strings.ToLower(exportFormat) == "ndjson" {
+		return runExportNDJSON(store)
+	}
+
 	// Get patterns
 	var patterns []pattern.Pattern
 	if exportTag != "" {
@@ -118,6 +142,59 @@ func runExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runExportNDJSON(store *pattern.Store) error {
+	opts := export.Options{
+		Fields: export.ParseFields(exportFields),
+		Cursor: exportCursor,
+		Limit:  exportLimit,
+	}
+	if exportSince != "" {
+		since, err := server.ParseExportSince(exportSince)
+		if err != nil {
+			return err
+		}
+		opts.Since = since
+	}
+
+	tracker, err := inject.DefaultTracker()
+	if err != nil {
+		return fmt.Errorf("cannot access usage tracker: %w", err)
+	}
+	records, err := export.Load(store, tracker)
+	if err != nil {
+		return err
+	}
+	page, err := export.Select(records, opts)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("cannot write to file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	if err := page.Write(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("cannot write export: %w", err)
+	}
+
+	if exportOutput != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d patterns to %s\n", len(page.Records), exportOutput)
+	}
+	if page.Next != "" {
+		fmt.Fprintf(os.Stderr, "Next page: --cursor %s\n", page.Next)
+	}
+	return nil
+}
+
 func formatMarkdown(patterns []pattern.Pattern) string {
 	var sb strings.Builder
 
@@ -2,14 +2,20 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/analytics"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
 )
 
 var feedbackCmd = &cobra.Command{
@@ -22,25 +28,47 @@ Ratings:
   not_helpful  - The pattern wasn't useful
   skip         - Skip rating this pattern
 
-Without arguments, shows an interactive selection of recently used patterns.`,
+Without arguments, shows an interactive selection of recently used patterns.
+
+With --outcome, record how a pattern fared instead: success (it was used
+and the work succeeded), failure (used, but the work failed), or ignored
+(injected but not used). Outcomes move the pattern's effectiveness, which
+ranks it for injection and sync, by an exponentially weighted average.
+
+The Claude Code stop hook records outcomes automatically ('mur feedback
+--hook'): for each prompt patterns were injected into, it checks the
+transcript for signs of each pattern in the work that followed, and
+whether that work ended with a failing command or was corrected in the
+next prompt.`,
 	Example: `  mur feedback                              # Interactive mode
   mur feedback swift-testing helpful        # Quick feedback
-  mur feedback go-error-handling not_helpful`,
+  mur feedback go-error-handling not_helpful
+  mur feedback go-error-handling --outcome success`,
 	Args: cobra.MaximumNArgs(2),
 	RunE: runFeedback,
 }
 
 func init() {
 	rootCmd.AddCommand(feedbackCmd)
+	feedbackCmd.Flags().String("outcome", "", "Record an outcome: success, failure, or ignored")
+	feedbackCmd.Flags().Bool("hook", false, "Judge this session's injected patterns from the stop hook's JSON on stdin")
+	_ = feedbackCmd.Flags().MarkHidden("hook")
 }
 
 func runFeedback(cmd *cobra.Command, args []string) error {
+	if hook, _ := cmd.Flags().GetBool("hook"); hook {
+		return runFeedbackHook(os.Stdin)
+	}
+	if outcome, _ := cmd.Flags().GetString("outcome"); outcome != "" {
+		return runFeedbackOutcome(args, outcome)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	dataDir := filepath.Join(home, ".mur")
+	dataDir := config.DataDir(home)
 	store, err := analytics.NewStore(dataDir)
 	if err != nil {
 		return fmt.Errorf("failed to open analytics store: %w", err)
@@ -218,3 +246,117 @@ func normalizeRating(input string) string {
 		return ""
 	}
 }
+
+// runFeedbackOutcome applies an outcome to a pattern's effectiveness.
+func runFeedbackOutcome(args []string, outcomeFlag string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("--outcome needs exactly one pattern name")
+	}
+	outcome, err := inject.ParseOutcome(outcomeFlag)
+	if err != nil {
+		return err
+	}
+	tracker, err := inject.DefaultTracker()
+	if err != nil {
+		return err
+	}
+	rec, err := tracker.RecordOutcome(inject.OutcomeRecord{PatternName: args[0], Outcome: outcome, Source: "cli"})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %s: %s (effectiveness %.0f%% → %.0f%%)\n", rec.PatternName, outcome, rec.Before*100, rec.After*100)
+	return nil
+}
+
+// hookInput is the part of a stop hook's JSON input feedback needs.
+type hookInput struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+}
+
+// maxTurnDistance is how far apart an injection and the prompt it was
+// made for can be recorded.
+const maxTurnDistance = 2 * time.Minute
+
+// runFeedbackHook judges the injections of the session described by the
+// stop hook input on stdin that haven't been judged yet. Each injected
+// pattern gets an outcome from the turn the injection was made for.
+func runFeedbackHook(stdin *os.File) error {
+	var in hookInput
+	if err := json.NewDecoder(stdin).Decode(&in); err != nil || in.SessionID == "" {
+		return fmt.Errorf("expected stop hook JSON with a session_id on stdin")
+	}
+	ref := in.TranscriptPath
+	if ref == "" {
+		ref = in.SessionID
+	}
+	sess, err := learn.LoadSession(ref)
+	if err != nil {
+		return err
+	}
+	turns := sess.Turns()
+
+	explanations, err := inject.RecentExplanations(200)
+	if err != nil {
+		return err
+	}
+	tracker, err := inject.DefaultTracker()
+	if err != nil {
+		return err
+	}
+	judged, err := tracker.JudgedInjections(in.SessionID)
+	if err != nil {
+		return err
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	// Oldest first, so outcomes apply in the order they happened
+	sort.SliceStable(explanations, func(i, j int) bool { return explanations[i].Time.Before(explanations[j].Time) })
+	for _, ex := range explanations {
+		if ex.Session != in.SessionID || ex.Command != "context" || judged[ex.Time.UTC()] {
+			continue
+		}
+		turn := turnFor(turns, ex.Time)
+		if turn == nil {
+			continue // the prompt isn't in the transcript yet
+		}
+		for _, d := range ex.Injected() {
+			p, err := store.Get(d.Name)
+			if err != nil {
+				continue // deleted since
+			}
+			rec, err := tracker.RecordOutcome(inject.OutcomeRecord{
+				PatternName: p.Name,
+				Outcome:     inject.JudgeOutcome(p, turn.Work, turn.Failed),
+				Source:      "hook",
+				Session:     in.SessionID,
+				InjectedAt:  ex.Time.UTC(),
+			})
+			if err != nil {
+				continue
+			}
+			fmt.Printf("%s: %s (%.0f%% → %.0f%%)\n", rec.PatternName, rec.Outcome, rec.Before*100, rec.After*100)
+		}
+	}
+	return nil
+}
+
+// turnFor returns the turn whose prompt is closest to an injection at t,
+// or nil if none is within maxTurnDistance.
+func turnFor(turns []learn.Turn, t time.Time) *learn.Turn {
+	var best *learn.Turn
+	bestDist := maxTurnDistance
+	for i := range turns {
+		dist := turns[i].Start.Sub(t)
+		if dist < 0 {
+			dist = -dist
+		}
+		if dist <= bestDist {
+			best, bestDist = &turns[i], dist
+		}
+	}
+	return best
+}
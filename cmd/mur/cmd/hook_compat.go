@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	murhooks "github.com/mur-run/mur-core/internal/hooks"
+	murlog "github.com/mur-run/mur-core/internal/log"
+)
+
+// executeSafeMode runs the command invoked by a hook installed by another
+// mur release, e.g. after 'brew upgrade mur' under a running AI session.
+// The hook may pass flags this binary doesn't know, and any failure would
+// surface inside the AI tool, so unknown flags are ignored, errors are
+// reported in one line without usage, and the hook always succeeds. The
+// upgrade hint is shown once per pair of versions.
+func executeSafeMode(m *murhooks.Mismatch) error {
+	if hint := m.HintOnce(); hint != "" {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", hint)
+	}
+
+	relaxFlags(rootCmd)
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ mur (safe mode): %v\n", err)
+		murlog.For("cli").Error("command failed in safe mode", "err", err, "hook_version", m.Stamp, "version", m.Running)
+	}
+	return nil
+}
+
+// relaxFlags makes cmd and its subcommands ignore unknown flags.
+func relaxFlags(cmd *cobra.Command) {
+	cmd.FParseErrWhitelist.UnknownFlags = true
+	for _, c := range cmd.Commands() {
+		relaxFlags(c)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	murhooks "github.com/mur-run/mur-core/internal/hooks"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Show and install hooks for AI tools",
+	Long: `Show and install the hooks that run mur's learn/inject loop in each
+AI tool: patterns are injected when you submit a prompt (UserPromptSubmit)
+and learned from the session when the agent stops (Stop).
+
+Each tool names these events differently; 'mur hooks status' shows how
+mur's events map onto each tool's own. Zed's agent has no lifecycle
+events, so for Zed mur installs tasks you run from the command palette.
+
+Examples:
+  mur hooks status
+  mur hooks install windsurf
+  mur hooks install zed`,
+}
+
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which tools have mur hooks installed",
+	RunE:  runHooksStatus,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install <windsurf|zed>",
+	Short: "Install mur hooks for a tool",
+	Long: `Install mur hooks for a single tool. Hooks for the other tools are
+installed by 'mur init --hooks'.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"windsurf", "zed"},
+	RunE:      runHooksInstall,
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksStatusCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+}
+
+func runHooksStatus(cmd *cobra.Command, args []string) error {
+	out := newPrinter(cmd)
+	for _, s := range murhooks.Status() {
+		state := "not installed"
+		switch {
+		case s.Installed:
+			state = "installed"
+		case !s.Detected:
+			state = "not detected"
+		}
+		out.Record("hooks", s.Tool, state, s.Path, s.Events.Describe())
+
+		mark := out.Dim("-")
+		switch {
+		case s.Installed:
+			mark = out.Green("✓")
+		case s.Detected:
+			mark = out.Yellow("✗")
+		}
+		out.Printf("%s %-15s %s\n", mark, s.Tool, state)
+		if s.Detected || s.Installed {
+			out.Printf("    %s\n", out.Dim(s.Path))
+		}
+
+		var unsupported []string
+		for _, e := range murhooks.Events {
+			name, ok := s.Events.Name(e)
+			if !ok {
+				unsupported = append(unsupported, string(e))
+				continue
+			}
+			if s.Events.Manual {
+				name = fmt.Sprintf("task %q", name)
+			}
+			out.Printf("    %-17s → %s\n", e, name)
+		}
+		if len(unsupported) > 0 {
+			out.Printf("    %s\n", out.Dim("unsupported: "+strings.Join(unsupported, ", ")))
+		}
+	}
+	out.Println()
+
+	// Hooks run mur by absolute path; a moved binary breaks them
+	var broken []murhooks.BinaryRef
+	refs := murhooks.CheckBinaryRefs()
+	for _, r := range refs {
+		if r.Problem != "" {
+			broken = append(broken, r)
+		}
+	}
+	switch {
+	case len(broken) > 0:
+		out.Printf("%s %d hook command(s) won't run mur:\n", out.Red("✗"), len(broken))
+		for _, r := range broken {
+			out.Printf("    %s: %s %s\n", r.File, r.Path, out.Dim("("+r.Problem+")"))
+		}
+		out.Println("  Fix with: mur completion doctor --fix")
+		out.Println()
+	case len(refs) > 0:
+		out.Printf("%s Hooks run %s\n\n", out.Green("✓"), refs[0].Path)
+	}
+
+	out.Println("Install with: mur init --hooks (all tools) or mur hooks install <windsurf|zed>")
+	return nil
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	switch strings.ToLower(args[0]) {
+	case "windsurf":
+		return murhooks.InstallWindsurfHooks()
+	case "zed":
+		return murhooks.InstallZedHooks()
+	}
+	return fmt.Errorf("unknown tool %q (supported: windsurf, zed)", args[0])
+}
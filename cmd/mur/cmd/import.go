@@ -117,7 +117,7 @@ func runImportGist(cmd *cobra.Command, args []string) error {
 	}
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	if err := store.Create(p); err != nil {
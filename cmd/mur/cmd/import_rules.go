@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/sysinfo"
+)
+
+var importRulesCmd = &cobra.Command{
+	Use:   "rules [dir...]",
+	Short: "Import conventions from CLAUDE.md, .cursorrules, and similar files",
+	Long: `Import the conventions you already keep in AI instruction files
+(CLAUDE.md, AGENTS.md, .cursorrules, .windsurfrules, .cursor/rules/,
+.github/copilot-instructions.md) as candidate patterns.
+
+Without arguments mur looks in the current directory, every project you've
+used Claude Code in, and ~/.claude/CLAUDE.md. With directories, it looks in
+each one and --depth levels below (e.g. a folder of checkouts).
+
+Each file is split into one candidate per rule using your configured LLM
+(or one per heading with --llm none). Candidates that duplicate existing
+patterns, or each other across projects, are skipped. The rest are staged
+for review; nothing is added until you accept it with 'mur import review'.
+
+Examples:
+  mur import rules                   # current dir + known projects
+  mur import rules ~/code --depth 2  # every project under ~/code
+  mur import rules --llm none        # split by heading, no LLM
+  mur import rules --dry-run`,
+	RunE: runImportRules,
+}
+
+var importReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review staged patterns from 'mur import rules'",
+	Long: `Accept or reject patterns staged by 'mur import rules'. Accepted
+patterns join your pattern store; rejected ones are discarded.
+
+Examples:
+  mur import review               # one by one: accept, reject, skip
+  mur import review --list
+  mur import review --accept-all`,
+	RunE: runImportReview,
+}
+
+var (
+	importRulesDepth  int
+	importRulesGlobal bool
+	importRulesLLM    string
+	importRulesModel  string
+	importRulesDryRun bool
+	reviewList        bool
+	reviewAcceptAll   bool
+	reviewRejectAll   bool
+)
+
+func init() {
+	importCmd.AddCommand(importRulesCmd)
+	importCmd.AddCommand(importReviewCmd)
+
+	importRulesCmd.Flags().IntVar(&importRulesDepth, "depth", 1, "How many directory levels below each given dir to search")
+	importRulesCmd.Flags().BoolVar(&importRulesGlobal, "global", true, "Include ~/.claude/CLAUDE.md")
+	importRulesCmd.Flags().StringVar(&importRulesLLM, "llm", "", "LLM to split rules with: ollama, claude, openai, gemini, or none (default: from config)")
+	importRulesCmd.Flags().StringVar(&importRulesModel, "llm-model", "", "LLM model override")
+	importRulesCmd.Flags().BoolVar(&importRulesDryRun, "dry-run", false, "Show candidates without staging them")
+
+	importReviewCmd.Flags().BoolVar(&reviewList, "list", false, "List staged patterns")
+	importReviewCmd.Flags().BoolVar(&reviewAcceptAll, "accept-all", false, "Accept every staged pattern")
+	importReviewCmd.Flags().BoolVar(&reviewRejectAll, "reject-all", false, "Reject every staged pattern")
+}
+
+// findRuleFiles returns the rule files in dirs, or with none given, in the
+// current directory and known projects.
+func findRuleFiles(dirs []string, depth int, global bool) []learn.RuleFile {
+	if len(dirs) == 0 {
+		depth = 0
+		if cwd, err := os.Getwd(); err == nil {
+			dirs = append(dirs, cwd)
+		}
+		dirs = append(dirs, learn.KnownProjectDirs()...)
+	}
+	files := learn.FindRuleFiles(dirs, depth)
+	if global {
+		home, _ := os.UserHomeDir()
+		files = append(files, learn.GlobalRuleFiles(home)...)
+	}
+	return files
+}
+
+func runImportRules(cmd *cobra.Command, args []string) error {
+	files := findRuleFiles(args, importRulesDepth, importRulesGlobal)
+	if len(files) == 0 {
+		fmt.Println("No CLAUDE.md, .cursorrules, or similar files found.")
+		return nil
+	}
+	return importRuleFiles(files, importRulesLLM, importRulesModel, importRulesDryRun)
+}
+
+// importRuleFiles splits files into candidates, drops duplicates, and
+// stages the rest for review.
+func importRuleFiles(files []learn.RuleFile, provider, model string, dryRun bool) error {
+	opts, useLLM, err := ruleSplitOptions(provider, model)
+	if err != nil {
+		return err
+	}
+	if useLLM {
+		fmt.Printf("Splitting %d files into patterns with %s...\n\n", len(files), opts.Provider)
+	} else {
+		fmt.Printf("Splitting %d files into patterns by heading...\n\n", len(files))
+	}
+
+	existing := make(map[string]string)
+	if patterns, err := listAllPatterns(); err == nil {
+		for _, p := range patterns {
+			existing[p.Name] = p.Content
+		}
+	}
+	if staged, err := learn.ListStaged(); err == nil {
+		for _, sp := range staged {
+			existing[sp.Name] = sp.Content
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	staged, skipped := 0, 0
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			fmt.Printf("  ⚠ %s: %v\n", f.Path, err)
+			continue
+		}
+
+		var candidates []learn.RuleCandidate
+		if useLLM {
+			candidates, err = learn.SplitRulesWithLLM(f, string(data), opts)
+			if err != nil {
+				fmt.Printf("  ⚠ %s: %v (splitting by heading instead)\n", shortenHome(f.Path, home), err)
+			}
+		}
+		if !useLLM || err != nil {
+			candidates = learn.CandidatesFromSections(f, learn.SplitRules(string(data), f.Kind))
+		}
+
+		fresh, dups := learn.DedupeCandidates(candidates, existing)
+		for _, c := range fresh {
+			existing[c.Pattern.Name] = c.Pattern.Content
+		}
+		skipped += len(dups)
+
+		fmt.Printf("📄 %s: %d candidates", shortenHome(f.Path, home), len(fresh))
+		if len(dups) > 0 {
+			fmt.Printf(", %d duplicates skipped", len(dups))
+		}
+		fmt.Println()
+		for _, c := range fresh {
+			fmt.Printf("   + %s\n", c.Pattern.Name)
+			if dryRun {
+				continue
+			}
+			if err := learn.Stage(c); err != nil {
+				fmt.Printf("     ✗ %v\n", err)
+				continue
+			}
+			staged++
+		}
+		for _, c := range dups {
+			fmt.Printf("   = %s (same as %s)\n", c.Pattern.Name, c.DuplicateOf)
+		}
+	}
+
+	fmt.Println()
+	if dryRun {
+		fmt.Println("Dry run: nothing staged.")
+		return nil
+	}
+	fmt.Printf("✓ Staged %d patterns for review", staged)
+	if skipped > 0 {
+		fmt.Printf(" (%d duplicates skipped)", skipped)
+	}
+	fmt.Println()
+	if staged > 0 {
+		fmt.Println("  Review them with 'mur import review'")
+	}
+	return nil
+}
+
+// ruleSplitOptions resolves the LLM used to split rule files. The bool is
+// false when rules should be split by heading instead: --llm none, or no
+// LLM configured or reachable.
+func ruleSplitOptions(provider, model string) (learn.LLMExtractOptions, bool, error) {
+	if strings.EqualFold(provider, "none") {
+		return learn.LLMExtractOptions{}, false, nil
+	}
+	cfg, _ := config.Load()
+	opts, configured, err := resolveLLMOptions(cfg, provider, model)
+	if err != nil {
+		return opts, false, err
+	}
+
+	if missing := llmUnavailable(opts); missing != "" {
+		if configured {
+			fmt.Printf("⚠ %s; splitting by heading instead\n", missing)
+		}
+		return opts, false, nil
+	}
+	return opts, true, nil
+}
+
+// llmUnavailable says why the LLM in opts can't be used, or returns "".
+func llmUnavailable(opts learn.LLMExtractOptions) string {
+	switch opts.Provider {
+	case learn.LLMOllama:
+		if !sysinfo.OllamaRunning(opts.OllamaURL) {
+			return "Ollama is not running"
+		}
+	case learn.LLMClaude:
+		if opts.ClaudeKey == "" {
+			return "ANTHROPIC_API_KEY not set"
+		}
+	case learn.LLMOpenAI:
+		if opts.OpenAIKey == "" {
+			return "OPENAI_API_KEY not set"
+		}
+	case learn.LLMGemini:
+		if opts.GeminiKey == "" {
+			return "GEMINI_API_KEY not set"
+		}
+	}
+	return ""
+}
+
+func shortenHome(path, home string) string {
+	if home != "" {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
+
+// offerRuleImport is run at the end of interactive init: it offers to
+// import existing rule files so the store doesn't start empty.
+func offerRuleImport() {
+	files := findRuleFiles(nil, 0, true)
+	if len(files) == 0 {
+		return
+	}
+
+	kinds := make(map[string]bool)
+	var names []string
+	for _, f := range files {
+		if !kinds[f.Kind] {
+			kinds[f.Kind] = true
+			names = append(names, f.Kind)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("📚 Found %d existing AI instruction files (%s)\n", len(files), strings.Join(names, ", "))
+
+	importRules := true
+	prompt := &survey.Confirm{
+		Message: "Import their conventions as patterns? (you review each one first)",
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &importRules); err != nil || !importRules {
+		fmt.Println("  Import them later with 'mur import rules'")
+		return
+	}
+	fmt.Println()
+	if err := importRuleFiles(files, "", "", false); err != nil {
+		fmt.Printf("  ⚠ Warning: %v\n", err)
+	}
+}
+
+func runImportReview(cmd *cobra.Command, args []string) error {
+	staged, err := learn.ListStaged()
+	if err != nil {
+		return err
+	}
+	if len(staged) == 0 {
+		fmt.Println("No staged patterns. Import some with 'mur import rules' or 'mur learn cross'.")
+		return nil
+	}
+
+	home, _ := os.UserHomeDir()
+	origin := func(sp learn.StagedPattern) string {
+		o := shortenHome(sp.Origin, home)
+		if sp.OriginLine > 0 {
+			o += fmt.Sprintf(":%d", sp.OriginLine)
+		}
+		return o
+	}
+
+	switch {
+	case reviewList:
+		fmt.Printf("%d staged patterns:\n\n", len(staged))
+		for _, sp := range staged {
+			fmt.Printf("  %-40s  %s\n", sp.Name, origin(sp))
+		}
+		return nil
+	case reviewAcceptAll || reviewRejectAll:
+		done := 0
+		for _, sp := range staged {
+			if reviewAcceptAll {
+				err = learn.AcceptStaged(sp.Name)
+			} else {
+				err = learn.RejectStaged(sp.Name)
+			}
+			if err != nil {
+				fmt.Printf("  ✗ %s: %v\n", sp.Name, err)
+				continue
+			}
+			done++
+		}
+		if reviewAcceptAll {
+			fmt.Printf("✓ Accepted %d patterns\n", done)
+			fmt.Println("  Run 'mur sync' to push them to your CLIs")
+		} else {
+			fmt.Printf("✓ Rejected %d patterns\n", done)
+		}
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	accepted, rejected := 0, 0
+	for i, sp := range staged {
+		fmt.Printf("[%d/%d] %s\n", i+1, len(staged), sp.Name)
+		fmt.Printf("  from %s\n", origin(sp))
+		fmt.Println(strings.Repeat("─", 50))
+		lines := strings.Split(sp.Content, "\n")
+		if len(lines) > 12 {
+			lines = append(lines[:12], "...")
+		}
+		for _, l := range lines {
+			fmt.Printf("  %s\n", l)
+		}
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Print("[a]ccept, [r]eject, [s]kip, [q]uit? ")
+
+		answer, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "accept", "y", "yes":
+			if err := learn.AcceptStaged(sp.Name); err != nil {
+				fmt.Printf("  ✗ %v\n", err)
+			} else {
+				fmt.Println("  ✓ Accepted")
+				accepted++
+				recordStagedReview(sp, true)
+			}
+		case "r", "reject", "n", "no":
+			if err := learn.RejectStaged(sp.Name); err != nil {
+				fmt.Printf("  ✗ %v\n", err)
+			} else {
+				fmt.Println("  ✗ Rejected")
+				rejected++
+				recordStagedReview(sp, false)
+			}
+		case "q", "quit":
+			fmt.Println()
+			fmt.Printf("Accepted %d, rejected %d, %d still staged\n", accepted, rejected, len(staged)-accepted-rejected)
+			return nil
+		default:
+			fmt.Println("  Skipped")
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Accepted %d, rejected %d, %d still staged\n", accepted, rejected, len(staged)-accepted-rejected)
+	if accepted > 0 {
+		fmt.Println("Run 'mur sync' to push accepted patterns to your CLIs")
+	}
+	return nil
+}
+
+// recordStagedReview notes a staged pattern accepted or rejected one by one,
+// which calibrates auto-accept thresholds for imported patterns.
+func recordStagedReview(sp learn.StagedPattern, accepted bool) {
+	_ = learn.RecordReview(learn.ReviewDecision{
+		Provider:   learn.ReviewImport,
+		Category:   sp.Category,
+		Confidence: sp.Confidence,
+		Accepted:   accepted,
+	})
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -27,7 +28,8 @@ Setup:
 Examples:
   mur index status           # Show index status
   mur index rebuild          # Rebuild all embeddings
-  mur index pattern <name>   # Index a single pattern`,
+  mur index pattern <name>   # Index a single pattern
+  mur index expansions export expansions.json`,
 }
 
 var indexStatusCmd = &cobra.Command{
@@ -39,7 +41,19 @@ var indexStatusCmd = &cobra.Command{
 var indexRebuildCmd = &cobra.Command{
 	Use:   "rebuild",
 	Short: "Rebuild all embeddings",
-	RunE:  runIndexRebuild,
+	Long: `Rebuild all embeddings.
+
+With --expand, an LLM generates likely search queries for each pattern and
+they're embedded with it. Expansions are cached by pattern hash and model,
+so only new or edited patterns are sent to the LLM on later rebuilds.
+
+  --expand            expand every pattern without a cached expansion for
+                      its current content and the model in use
+  --expand-only-new   expand only patterns that were never expanded;
+                      edited patterns keep their previous queries
+  --no-expand         never call the LLM; embed with cached expansions,
+                      e.g. ones imported with 'mur index expansions import'`,
+	RunE: runIndexRebuild,
 }
 
 var indexPatternCmd = &cobra.Command{
@@ -49,14 +63,51 @@ var indexPatternCmd = &cobra.Command{
 	RunE:  runIndexPattern,
 }
 
-var indexExpand bool
+var indexExpansionsCmd = &cobra.Command{
+	Use:   "expansions",
+	Short: "Share the query expansion cache between machines",
+	Long: `Share the query expansion cache between machines.
+
+Expansions are keyed by pattern hash and model, so a cache exported on one
+machine is valid on any other with the same patterns. Commit the export to
+your team repo or copy it over, import it, then embed it without an LLM:
+
+  mur index expansions export expansions.json
+  mur index expansions import expansions.json
+  mur index rebuild --no-expand`,
+}
+
+var indexExpansionsExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Write the expansion cache to a file (default: stdout)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runIndexExpansionsExport,
+}
+
+var indexExpansionsImportCmd = &cobra.Command{
+	Use:   "import <file>...",
+	Short: "Merge expansion caches exported elsewhere",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runIndexExpansionsImport,
+}
+
+var (
+	indexExpand        bool
+	indexExpandOnlyNew bool
+	indexNoExpand      bool
+)
 
 func init() {
 	rootCmd.AddCommand(indexCmd)
 	indexCmd.AddCommand(indexStatusCmd)
 	indexCmd.AddCommand(indexRebuildCmd)
 	indexCmd.AddCommand(indexPatternCmd)
+	indexCmd.AddCommand(indexExpansionsCmd)
+	indexExpansionsCmd.AddCommand(indexExpansionsExportCmd)
+	indexExpansionsCmd.AddCommand(indexExpansionsImportCmd)
 	indexRebuildCmd.Flags().BoolVar(&indexExpand, "expand", false, "Generate search queries per pattern using LLM (slower but better search)")
+	indexRebuildCmd.Flags().BoolVar(&indexExpandOnlyNew, "expand-only-new", false, "Only send never-expanded patterns to the LLM")
+	indexRebuildCmd.Flags().BoolVar(&indexNoExpand, "no-expand", false, "Embed cached expansions without calling the LLM")
 }
 
 func runIndexStatus(cmd *cobra.Command, args []string) error {
@@ -109,6 +160,13 @@ func runIndexStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println("  Patterns: 0")
 	}
 
+	for _, m := range status.IndexModels {
+		fmt.Printf("  Vectors:  %d from %s\n", m.Count, m)
+	}
+	if status.Mismatch != nil {
+		fmt.Printf("  ⚠ %v\n", status.Mismatch)
+	}
+
 	if !status.LastUpdated.IsZero() {
 		fmt.Printf("  Updated:  %s\n", status.LastUpdated.Format("2006-01-02 15:04"))
 	}
@@ -131,6 +189,9 @@ func runIndexRebuild(cmd *cobra.Command, args []string) error {
 	if !cfg.Search.IsEnabled() {
 		return fmt.Errorf("semantic search is disabled, enable with: mur config set search.enabled true")
 	}
+	if flags := boolCount(indexExpand, indexExpandOnlyNew, indexNoExpand); flags > 1 {
+		return fmt.Errorf("use only one of --expand, --expand-only-new and --no-expand")
+	}
 
 	fmt.Println("🔄 Rebuilding pattern index...")
 	fmt.Println()
@@ -162,30 +223,19 @@ func runIndexRebuild(cmd *cobra.Command, args []string) error {
 	start := time.Now()
 	var lastProgress int
 
-	if indexExpand {
-		// Expansion currently requires Ollama for LLM generation
-		llmProvider := cfg.Learning.LLM.Provider
-		if llmProvider != "" && llmProvider != "ollama" {
-			return fmt.Errorf("--expand currently requires Ollama for LLM generation (configured: %s). Cloud LLM expansion coming soon", llmProvider)
-		}
-
-		// Determine LLM model for expansion (use learn.model or fallback)
+	if mode, ok := indexExpansionMode(); ok {
 		llmModel := cfg.Learning.LLM.Model
-		if llmModel == "" {
-			// Try to find an available model on ollama
-			for _, candidate := range []string{"llama3.2:3b", "qwen2.5:3b", "gemma2:2b", "deepseek-r1:8b"} {
-				if embed.HasOllamaModel(cfg.Search.OllamaURL, candidate) {
-					llmModel = candidate
-					break
-				}
-			}
-			if llmModel == "" {
-				return fmt.Errorf("no LLM model available for expansion. Install one: ollama pull llama3.2:3b")
+		if mode == embed.ExpandCachedOnly {
+			fmt.Println("  📝 Using cached expansions (no LLM calls)...")
+			fmt.Println()
+		} else {
+			if llmModel, err = expansionLLMModel(cfg); err != nil {
+				return err
 			}
+			fmt.Printf("  📝 Expanding with LLM (%s)...\n\n", llmModel)
 		}
-		fmt.Printf("  📝 Expanding with LLM (%s)...\n\n", llmModel)
 
-		err = indexer.RebuildWithExpansion(cfg.Search.OllamaURL, llmModel, func(current, total int, phase string) {
+		err = indexer.RebuildWithExpansion(cfg.Search.OllamaURL, llmModel, mode, func(current, total int, phase string) {
 			pct := current * 100 / total
 			if pct != lastProgress || current == total {
 				lastProgress = pct
@@ -220,6 +270,109 @@ func runIndexRebuild(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// indexExpansionMode returns the expansion mode the rebuild flags ask for,
+// or false for a plain rebuild.
+func indexExpansionMode() (embed.ExpansionMode, bool) {
+	switch {
+	case indexNoExpand:
+		return embed.ExpandCachedOnly, true
+	case indexExpandOnlyNew:
+		return embed.ExpandOnlyNew, true
+	case indexExpand:
+		return embed.ExpandMissing, true
+	}
+	return 0, false
+}
+
+// expansionLLMModel returns the Ollama model to expand queries with.
+func expansionLLMModel(cfg *config.Config) (string, error) {
+	// Expansion currently requires Ollama for LLM generation
+	llmProvider := cfg.Learning.LLM.Provider
+	if llmProvider != "" && llmProvider != "ollama" {
+		return "", fmt.Errorf("--expand currently requires Ollama for LLM generation (configured: %s). Cloud LLM expansion coming soon", llmProvider)
+	}
+
+	// Determine LLM model for expansion (use learn.model or fallback)
+	if cfg.Learning.LLM.Model != "" {
+		return cfg.Learning.LLM.Model, nil
+	}
+	// Try to find an available model on ollama
+	for _, candidate := range []string{"llama3.2:3b", "qwen2.5:3b", "gemma2:2b", "deepseek-r1:8b"} {
+		if embed.HasOllamaModel(cfg.Search.OllamaURL, candidate) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no LLM model available for expansion. Install one: ollama pull llama3.2:3b")
+}
+
+func boolCount(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+func runIndexExpansionsExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	indexer, err := embed.NewPatternIndexer(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create indexer: %w", err)
+	}
+
+	eq := indexer.Expansions()
+	if len(args) == 0 {
+		data, err := json.MarshalIndent(eq, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := eq.WriteFile(args[0]); err != nil {
+		return fmt.Errorf("cannot write %s: %w", args[0], err)
+	}
+	fmt.Printf("✓ Exported %d expansions to %s\n", eq.Len(), args[0])
+	return nil
+}
+
+func runIndexExpansionsImport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	indexer, err := embed.NewPatternIndexer(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create indexer: %w", err)
+	}
+
+	eq := indexer.Expansions()
+	added := 0
+	for _, path := range args {
+		other, err := embed.ReadExpandedQueries(path)
+		if err != nil {
+			return fmt.Errorf("cannot import %s: %w", path, err)
+		}
+		n := eq.Merge(other)
+		fmt.Printf("✓ %s: %d of %d expansions new\n", path, n, other.Len())
+		added += n
+	}
+	if added == 0 {
+		return nil
+	}
+	if err := eq.Save(); err != nil {
+		return fmt.Errorf("cannot save expansions: %w", err)
+	}
+	fmt.Println()
+	fmt.Println("Embed them without an LLM: mur index rebuild --no-expand")
+	return nil
+}
+
 func runIndexPattern(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -63,7 +64,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 
 	// --hooks implies --non-interactive
 	if initHooks {
@@ -138,6 +139,16 @@ func runInteractiveInit(home, murDir string) error {
 		}
 	}
 
+	// JetBrains IDEs aren't CLIs; their assistants only get patterns on request
+	syncJetBrains := false
+	jetbrainsPrompt := &survey.Confirm{
+		Message: "Sync patterns to JetBrains IDEs (AI Assistant rules, Junie guidelines)?",
+		Default: sync.JetBrainsInstalled(),
+	}
+	if err := survey.AskOne(jetbrainsPrompt, &syncJetBrains); err != nil {
+		return err
+	}
+
 	// Ask for default CLI
 	defaultCLI := ""
 	if len(selectedCLIs) > 0 {
@@ -181,6 +192,16 @@ func runInteractiveInit(home, murDir string) error {
 	}
 	fmt.Println("✓ Created config.yaml")
 
+	if syncJetBrains {
+		if cfg, err := config.Load(); err == nil {
+			cfg.Sync.Enable = append(cfg.Sync.Enable, sync.JetBrains)
+			_ = cfg.Save()
+		}
+	}
+
+	// Fill tech_stack from the user's existing repos
+	offerStackDetection()
+
 	// Install hooks if requested
 	if installHooks {
 		if err := installClaudeHooks(home, murDir); err != nil {
@@ -208,6 +229,9 @@ func runInteractiveInit(home, murDir string) error {
 		}
 	}
 
+	// Seed patterns from existing CLAUDE.md / .cursorrules files
+	offerRuleImport()
+
 	// Final message
 	fmt.Println()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -292,6 +316,13 @@ func runNonInteractiveInit(home, murDir string) error {
 		}
 	}
 
+	if files := findRuleFiles(nil, 0, true); len(files) > 0 {
+		fmt.Printf("💡 Found %d CLAUDE.md/.cursorrules-style files; import them with 'mur import rules'\n", len(files))
+	}
+	if cfg, err := config.Load(); err == nil && sync.JetBrainsInstalled() && !slices.Contains(cfg.Sync.Enable, sync.JetBrains) {
+		fmt.Println("💡 Found a JetBrains IDE; add jetbrains to sync.enable in config.yaml to sync patterns to AI Assistant and Junie")
+	}
+
 	fmt.Println()
 	if initHooks {
 		fmt.Println("You're all set! Use claude or gemini directly — patterns auto-inject.")
@@ -990,14 +1021,22 @@ func installClaudeHooks(home, murDir string) error {
 
 	hooksDir := filepath.Join(murDir, "hooks")
 
+	// Hooks don't get the PATH of an interactive shell, so they run mur
+	// by absolute path
+	murBin := murhooks.MurBinary()
+
 	// Create on-prompt.sh - injects context-aware patterns (version-managed)
 	promptScriptPath := filepath.Join(hooksDir, "on-prompt.sh")
 	if murhooks.ShouldUpgradeHook(promptScriptPath, initForce) {
 		promptScript := fmt.Sprintf(`#!/bin/bash
 # mur-managed-hook v%d
+%s
 # Inject context-aware patterns based on current project
-mur context --compact 2>/dev/null || true
-`, murhooks.CurrentHookVersion)
+# (the session id lets 'mur context --explain-last' group injections)
+INPUT=$(cat /dev/stdin 2>/dev/null || echo '{}')
+export MUR_SESSION_ID=$(echo "$INPUT" | jq -r '.session_id // empty' 2>/dev/null)
+%s context --compact 2>/dev/null || true
+`, murhooks.CurrentHookVersion, murhooks.StampLine(), murBin)
 		if err := os.WriteFile(promptScriptPath, []byte(promptScript), 0755); err != nil {
 			return err
 		}
@@ -1009,14 +1048,14 @@ mur context --compact 2>/dev/null || true
 	// Create on-prompt-reminder.md (only if missing, no version tracking needed)
 	reminderPath := filepath.Join(hooksDir, "on-prompt-reminder.md")
 	if _, err := os.Stat(reminderPath); os.IsNotExist(err) || initForce {
-		reminderContent := `[ContinuousLearning] If during this task you discover something non-obvious (a debugging technique, a workaround, a pattern), save it:
+		reminderContent := fmt.Sprintf(`[ContinuousLearning] If during this task you discover something non-obvious (a debugging technique, a workaround, a pattern), save it:
 
-  mur learn add --name "pattern-name" --content "description"
+  %s learn add --name "pattern-name" --content "description"
 
 Or create a file in ~/.mur/patterns/
 
 Only save if: it required discovery, it helps future tasks, and it's verified.
-`
+`, murBin)
 		if err := os.WriteFile(reminderPath, []byte(reminderContent), 0644); err != nil {
 			return err
 		}
@@ -1027,15 +1066,22 @@ Only save if: it required discovery, it helps future tasks, and it's verified.
 	if murhooks.ShouldUpgradeHook(stopScriptPath, initForce) {
 		stopScript := fmt.Sprintf(`#!/bin/bash
 # mur-managed-hook v%d
+%s
+# Read hook input from stdin (Claude Code passes JSON)
+INPUT=$(cat /dev/stdin 2>/dev/null || echo '{}')
+
 # Lightweight sync (blocking, fast)
-mur sync --quiet 2>/dev/null || true
+%[3]s sync --quiet --coalesce 2>/dev/null || true
 
 # LLM extract in background (non-blocking)
-(mur learn extract --llm --auto --accept-all --quiet 2>/dev/null &) || true
+(%[3]s learn extract --llm --auto --accept-all --quiet --coalesce 2>/dev/null &) || true
+
+# Judge how this session's injected patterns fared (updates effectiveness)
+(echo "$INPUT" | %[3]s feedback --hook >/dev/null 2>&1 &) || true
 
 # Load user customizations if they exist
 [ -f ~/.mur/hooks/on-stop.local.sh ] && source ~/.mur/hooks/on-stop.local.sh
-`, murhooks.CurrentHookVersion)
+`, murhooks.CurrentHookVersion, murhooks.StampLine(), murBin)
 		if err := os.WriteFile(stopScriptPath, []byte(stopScript), 0755); err != nil {
 			return err
 		}
@@ -1045,7 +1091,7 @@ mur sync --quiet 2>/dev/null || true
 	}
 
 	// Update Claude settings (merge, not overwrite)
-	claudeSettingsPath := filepath.Join(home, ".claude", "settings.json")
+	claudeSettingsPath := filepath.Join(config.ClaudeDir(home), "settings.json")
 
 	// Build UserPromptSubmit hooks
 	promptHooks := []map[string]interface{}{
@@ -1059,7 +1105,7 @@ mur sync --quiet 2>/dev/null || true
 	if searchEnabled {
 		promptHooks = append(promptHooks, map[string]interface{}{
 			"type":    "command",
-			"command": `mur search --inject "$PROMPT" 2>/dev/null || true`,
+			"command": murBin + ` search --inject "$PROMPT" 2>/dev/null || true`,
 		})
 		fmt.Println("  + Added semantic search hook (auto-inject enabled)")
 	}
@@ -1085,7 +1131,7 @@ mur sync --quiet 2>/dev/null || true
 	if data, err := os.ReadFile(claudeSettingsPath); err == nil {
 		_ = json.Unmarshal(data, &settings)
 	} else {
-		_ = os.MkdirAll(filepath.Join(home, ".claude"), 0755)
+		_ = os.MkdirAll(config.ClaudeDir(home), 0755)
 		settings = make(map[string]interface{})
 	}
 
@@ -1113,9 +1159,32 @@ mur sync --quiet 2>/dev/null || true
 		fmt.Printf("  ⚠ Gemini hooks: %v\n", err)
 	}
 
+	checkHookBinary(murBin)
 	return nil
 }
 
+// checkHookBinary tells whether hooks could find mur without an absolute
+// path, and points kept hooks that run a moved or missing mur (e.g. an old
+// Homebrew Cellar path) at murBin.
+func checkHookBinary(murBin string) {
+	if !murhooks.OnMinimalPath() {
+		fmt.Printf("  ℹ mur isn't on the PATH hooks may get (%s); hooks run %s\n", murhooks.MinimalPath, murBin)
+	}
+	for _, r := range murhooks.CheckBinaryRefs() {
+		if r.Problem == "" {
+			continue
+		}
+		changed, err := murhooks.RepairBinaryRefs(murBin)
+		if err != nil {
+			fmt.Printf("  ⚠ Cannot repair hooks: %v (see: mur completion doctor)\n", err)
+		}
+		for _, f := range changed {
+			fmt.Printf("  + Pointed %s at %s\n", filepath.Base(f), murBin)
+		}
+		return
+	}
+}
+
 func installGeminiHooks(home, promptScriptPath, stopScriptPath string) error {
 	geminiSettingsPath := filepath.Join(home, ".gemini", "settings.json")
 
@@ -1213,6 +1282,20 @@ func installGeminiHooks(home, promptScriptPath, stopScriptPath string) error {
 		fmt.Println("✓ Installed OpenClaw hooks")
 	}
 
+	// Install Windsurf (Cascade) hooks
+	if murhooks.WindsurfInstalled() {
+		if err := murhooks.InstallWindsurfHooks(); err != nil {
+			fmt.Printf("  ⚠ Windsurf hooks: %v\n", err)
+		}
+	}
+
+	// Install Zed tasks (Zed has no agent hooks)
+	if murhooks.ZedInstalled() {
+		if err := murhooks.InstallZedHooks(); err != nil {
+			fmt.Printf("  ⚠ Zed tasks: %v\n", err)
+		}
+	}
+
 	return nil
 }
 
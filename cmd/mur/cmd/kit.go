@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/kit"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/security"
+)
+
+var kitCmd = &cobra.Command{
+	Use:   "kit",
+	Short: "Share your learning setup as a starter kit",
+	Long: `Bundle your learning setup into a starter kit others can install:
+context profiles, context templates, guardrails (privacy redactions),
+hook matchers, and patterns, with a versioned manifest.
+
+Installed kits live in ~/.mur/kits/<name>/ and never change your config.
+Their profiles and templates are namespaced as <kit>/<name>, so they can't
+clash with yours; their guardrails add to your redactions; their patterns
+are staged for 'mur import review'; their hooks run only if installed
+with --allow-hooks.
+
+Examples:
+  mur kit export go-backend --version 1.0.0
+  mur kit export oncall --profile oncall --guardrails --pattern incident-runbook
+  mur kit install https://example.com/go-backend.kit.yaml
+  mur kit list
+  mur kit remove go-backend`,
+}
+
+var kitExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export your setup as a kit file",
+	Long: `Export your setup as <name>.kit.yaml.
+
+By default the kit includes every context profile and custom context
+template. Guardrails, hooks, and patterns are only included when asked
+for, since they may be specific to you: --guardrails shares your redact
+terms themselves.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKitExport,
+}
+
+var kitInstallCmd = &cobra.Command{
+	Use:   "install <url|file>",
+	Short: "Install a starter kit",
+	Long: `Install a starter kit from a file or an http(s) URL.
+
+Installing a newer version of a kit replaces the old one; reinstalling the
+same version or downgrading needs --force. Patterns whose names you already
+use are skipped, so nothing of yours is overwritten.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKitInstall,
+}
+
+var kitListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed kits",
+	RunE:  runKitList,
+}
+
+var kitRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Uninstall a kit",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runKitRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(kitCmd)
+	kitCmd.AddCommand(kitExportCmd)
+	kitCmd.AddCommand(kitInstallCmd)
+	kitCmd.AddCommand(kitListCmd)
+	kitCmd.AddCommand(kitRemoveCmd)
+
+	kitExportCmd.Flags().StringP("output", "o", "", "Output file (default: <name>.kit.yaml)")
+	kitExportCmd.Flags().String("version", "1.0.0", "Kit version")
+	kitExportCmd.Flags().String("description", "", "What the kit is for")
+	kitExportCmd.Flags().String("author", "", "Kit author")
+	kitExportCmd.Flags().StringSlice("profile", nil, "Context profiles to include (default: all)")
+	kitExportCmd.Flags().StringSlice("template", nil, "Context templates to include (default: all)")
+	kitExportCmd.Flags().Bool("no-profiles", false, "Include no context profiles")
+	kitExportCmd.Flags().Bool("no-templates", false, "Include no context templates")
+	kitExportCmd.Flags().Bool("guardrails", false, "Include privacy.redact_terms and privacy.replacements")
+	kitExportCmd.Flags().Bool("hooks", false, "Include configured hooks")
+	kitExportCmd.Flags().StringSlice("pattern", nil, "Patterns to include")
+
+	kitInstallCmd.Flags().Bool("allow-hooks", false, "Enable the kit's hooks (they run commands in your AI tools)")
+	kitInstallCmd.Flags().Bool("force", false, "Reinstall the same version or downgrade")
+	kitInstallCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+}
+
+func runKitExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	version, _ := cmd.Flags().GetString("version")
+	description, _ := cmd.Flags().GetString("description")
+	author, _ := cmd.Flags().GetString("author")
+	profiles, _ := cmd.Flags().GetStringSlice("profile")
+	templates, _ := cmd.Flags().GetStringSlice("template")
+	noProfiles, _ := cmd.Flags().GetBool("no-profiles")
+	noTemplates, _ := cmd.Flags().GetBool("no-templates")
+	guardrails, _ := cmd.Flags().GetBool("guardrails")
+	hooks, _ := cmd.Flags().GetBool("hooks")
+	patternNames, _ := cmd.Flags().GetStringSlice("pattern")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// Unset means all; --no-* means none.
+	switch {
+	case noProfiles:
+		profiles = []string{}
+	case !cmd.Flags().Changed("profile"):
+		profiles = nil
+	}
+	switch {
+	case noTemplates:
+		templates = []string{}
+	case !cmd.Flags().Changed("template"):
+		templates = nil
+	}
+	templatesDir, _ := inject.TemplatesDir()
+
+	// Patterns leave the machine, so they get the same redaction as
+	// community sharing.
+	pii := security.NewPIIScanner(kit.Privacy(cfg.Privacy))
+	var patterns []kit.Pattern
+	redacted := 0
+	for _, name := range patternNames {
+		p, err := learn.Get(name)
+		if err != nil {
+			return err
+		}
+		content, findings := pii.ScanAndRedact(p.Content)
+		desc, descFindings := pii.ScanAndRedact(p.Description)
+		redacted += len(findings) + len(descFindings)
+		patterns = append(patterns, kit.Pattern{
+			Name:        p.Name,
+			Description: desc,
+			Content:     content,
+			Domain:      p.Domain,
+			Category:    p.Category,
+			Tags:        p.Tags,
+		})
+	}
+
+	k, err := kit.Build(cfg, kit.ExportOptions{
+		Manifest: kit.Manifest{
+			Name:        args[0],
+			Version:     version,
+			Description: description,
+			Author:      author,
+			MurVersion:  Version,
+		},
+		Profiles:     profiles,
+		Templates:    templates,
+		TemplatesDir: templatesDir,
+		Guardrails:   guardrails,
+		Hooks:        hooks,
+		Patterns:     patterns,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := k.Marshal()
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = args[0] + kit.FileExt
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("cannot write kit: %w", err)
+	}
+
+	fmt.Printf("📦 Exported %s %s to %s\n", k.Manifest.Name, k.Manifest.Version, output)
+	fmt.Printf("   %s\n", strings.Join(k.Contents(), ", "))
+	if redacted > 0 {
+		fmt.Printf("   🔒 Redacted %d PII matches in patterns\n", redacted)
+	}
+	if guardrails && len(cfg.Privacy.RedactTerms) > 0 {
+		fmt.Println("⚠ The kit contains your redact terms; check it before sharing.")
+	}
+	return nil
+}
+
+func runKitInstall(cmd *cobra.Command, args []string) error {
+	allowHooks, _ := cmd.Flags().GetBool("allow-hooks")
+	force, _ := cmd.Flags().GetBool("force")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	data, err := kit.Fetch(args[0])
+	if err != nil {
+		return err
+	}
+	k, err := kit.Parse(data)
+	if err != nil {
+		return err
+	}
+	m := k.Manifest
+
+	fmt.Printf("📦 %s %s", m.Name, m.Version)
+	if m.Author != "" {
+		fmt.Printf(" by %s", m.Author)
+	}
+	fmt.Println()
+	if m.Description != "" {
+		fmt.Printf("   %s\n", m.Description)
+	}
+	fmt.Printf("   %s\n", strings.Join(k.Contents(), ", "))
+	prev, _ := kit.Get(m.Name)
+	if prev != nil {
+		fmt.Printf("   Installed: %s\n", prev.Manifest.Version)
+	}
+	if cmds := k.HookCommands(); len(cmds) > 0 {
+		if allowHooks {
+			fmt.Println("\n⚠ These hooks will run in your AI tools after 'mur sync':")
+		} else {
+			fmt.Println("\nHooks (not enabled; install with --allow-hooks to run them):")
+		}
+		for _, c := range cmds {
+			fmt.Printf("   %s\n", c)
+		}
+	}
+	fmt.Println()
+
+	if !yes {
+		fmt.Print("Install? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	// Patterns from a previous version of the kit may be restaged; any
+	// other name in use is left alone.
+	ownStaged := make(map[string]bool)
+	if prev != nil {
+		for _, name := range prev.Staged {
+			ownStaged[name] = true
+		}
+	}
+	var candidates []learn.RuleCandidate
+	var skipped []string
+	for _, p := range k.Patterns {
+		if _, err := learn.Get(p.Name); err == nil || (learn.IsStaged(p.Name) && !ownStaged[p.Name]) {
+			skipped = append(skipped, p.Name)
+			continue
+		}
+		candidates = append(candidates, learn.RuleCandidate{
+			Pattern: learn.Pattern{
+				Name:        p.Name,
+				Description: p.Description,
+				Content:     p.Content,
+				Domain:      p.Domain,
+				Category:    p.Category,
+				Tags:        p.Tags,
+			},
+			Origin: fmt.Sprintf("kit %s@%s", m.Name, m.Version),
+		})
+	}
+	var staged []string
+	for _, c := range candidates {
+		staged = append(staged, c.Pattern.Name)
+	}
+
+	inst, err := kit.Install(k, kit.InstallOptions{Source: args[0], AllowHooks: allowHooks, Force: force, Staged: staged})
+	if err != nil {
+		return err
+	}
+	for _, c := range candidates {
+		if err := learn.Stage(c); err != nil {
+			fmt.Printf("  ✗ %s: %v\n", c.Pattern.Name, err)
+		}
+	}
+
+	if prev != nil && prev.Manifest.Version != m.Version {
+		fmt.Printf("✓ Upgraded %s %s → %s\n", m.Name, prev.Manifest.Version, m.Version)
+	} else {
+		fmt.Printf("✓ Installed %s %s\n", m.Name, m.Version)
+	}
+	for _, name := range k.ProfileNames() {
+		fmt.Printf("  Profile:  %s/%s  (mur profile use %s/%s)\n", m.Name, name, m.Name, name)
+	}
+	for _, name := range k.TemplateNames() {
+		fmt.Printf("  Template: %s/%s  (mur context --format %s/%s)\n", m.Name, name, m.Name, name)
+	}
+	if n := len(k.Guardrails.RedactTerms) + len(k.Guardrails.Replacements); n > 0 {
+		fmt.Printf("  Guardrails: %d redactions added\n", n)
+	}
+	if inst.HooksEnabled {
+		fmt.Println("  Hooks: enabled; run 'mur sync' to install them")
+	}
+	if len(staged) > 0 {
+		fmt.Printf("  Patterns: %d staged; review with 'mur import review'\n", len(staged))
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("  ⚠ Skipped %d patterns whose names are taken: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+	return nil
+}
+
+func runKitList(cmd *cobra.Command, args []string) error {
+	kits, err := kit.List()
+	if err != nil {
+		return err
+	}
+	if len(kits) == 0 {
+		fmt.Println("No kits installed. Install one with 'mur kit install <url|file>'.")
+		return nil
+	}
+	fmt.Println("Installed kits:")
+	for _, ik := range kits {
+		fmt.Printf("  %-24s %-10s %s\n", ik.Manifest.Name, ik.Manifest.Version, strings.Join(ik.Kit.Contents(), ", "))
+		if ik.Manifest.Description != "" {
+			fmt.Printf("      %s\n", ik.Manifest.Description)
+		}
+		if kit.HookCount(ik.Kit.Hooks) > 0 && !ik.HooksEnabled {
+			fmt.Println("      hooks not enabled")
+		}
+	}
+	return nil
+}
+
+func runKitRemove(cmd *cobra.Command, args []string) error {
+	ik, err := kit.Remove(args[0])
+	if err != nil {
+		return err
+	}
+	discarded := 0
+	for _, name := range ik.Staged {
+		if learn.IsStaged(name) && learn.RejectStaged(name) == nil {
+			discarded++
+		}
+	}
+	fmt.Printf("🗑️ Removed %s %s\n", ik.Manifest.Name, ik.Manifest.Version)
+	if discarded > 0 {
+		fmt.Printf("   Discarded %d patterns still staged for review\n", discarded)
+	}
+	if ik.HooksEnabled {
+		fmt.Println("   Its hooks stay in your AI tools until 'mur sync' rewrites them")
+	}
+	return nil
+}
@@ -3,19 +3,28 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/async"
+	"github.com/mur-run/mur-core/internal/cloud"
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
 	"github.com/mur-run/mur-core/internal/learn"
 	"github.com/mur-run/mur-core/internal/learning"
 	"github.com/mur-run/mur-core/internal/notify"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/sync"
 	"github.com/mur-run/mur-core/internal/sysinfo"
 )
 
@@ -25,60 +34,30 @@ var learnCmd = &cobra.Command{
 	Long:  `List, add, sync, and manage patterns in your knowledge base.`,
 }
 
-var learnListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all patterns",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		patterns, err := learn.List()
-		if err != nil {
-			return fmt.Errorf("failed to list patterns: %w", err)
-		}
-
-		domain, _ := cmd.Flags().GetString("domain")
-		category, _ := cmd.Flags().GetString("category")
-
-		fmt.Println("Learned Patterns")
-		fmt.Println("================")
-		fmt.Println("")
-
-		count := 0
-		for _, p := range patterns {
-			// Filter by domain
-			if domain != "" && p.Domain != domain {
-				continue
-			}
-			// Filter by category
-			if category != "" && p.Category != category {
-				continue
-			}
-
-			fmt.Printf("  %-20s  [%s/%s]  %.0f%%\n", p.Name, p.Domain, p.Category, p.Confidence*100)
-			if p.Description != "" {
-				fmt.Printf("    %s\n", truncate(p.Description, 60))
-			}
-			count++
-		}
-
-		fmt.Println("")
-		fmt.Printf("Total: %d patterns\n", count)
-
-		return nil
-	},
-}
-
 var learnAddCmd = &cobra.Command{
 	Use:   "add <name>",
 	Short: "Add a new pattern",
 	Long: `Add a new pattern interactively or from stdin.
 
+With --scope project the pattern is added to the current repository's
+.mur/patterns/ instead of ~/.mur/patterns/. Commit it with the code: it is
+only used inside that repository, where 'mur context' and 'mur search'
+merge it with your global patterns, and 'mur sync' writes it into the
+repository's own rules (e.g. .cursor/rules/).
+
 Examples:
   mur learn add my-pattern              # Interactive mode
-  cat pattern.yaml | mur learn add my-pattern --stdin  # From stdin`,
+  cat pattern.yaml | mur learn add my-pattern --stdin  # From stdin
+  mur learn add api-errors --scope project  # For this repository only`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
 		fromStdin, _ := cmd.Flags().GetBool("stdin")
+		scope, _ := cmd.Flags().GetString("scope")
+		if scope != "global" && scope != pattern.ScopeProject {
+			return fmt.Errorf("unknown scope %q (use global or project)", scope)
+		}
 
 		var p learn.Pattern
 		p.Name = name
@@ -146,11 +125,19 @@ Examples:
 			p.Content = strings.Join(contentLines, "\n")
 		}
 
-		if err := learn.Add(p); err != nil {
-			return fmt.Errorf("failed to add pattern: %w", err)
+		if scope == pattern.ScopeProject {
+			dir, err := addProjectPattern(p)
+			if err != nil {
+				return fmt.Errorf("failed to add pattern: %w", err)
+			}
+			fmt.Printf("\n✓ Pattern '%s' added to %s\n", name, dir)
+			fmt.Println("  Commit it to share it with everyone working on the project")
+		} else {
+			if err := learn.Add(p); err != nil {
+				return fmt.Errorf("failed to add pattern: %w", err)
+			}
+			fmt.Printf("\n✓ Pattern '%s' added successfully\n", name)
 		}
-
-		fmt.Printf("\n✓ Pattern '%s' added successfully\n", name)
 		fmt.Println("  Run 'mur learn sync' to sync to AI tools")
 
 		// Send notification
@@ -170,42 +157,232 @@ Examples:
 	},
 }
 
+// addProjectPattern adds p to the patterns of the repository the current
+// directory is in, creating its .mur/patterns/ at the project root if
+// needed, and returns the directory.
+func addProjectPattern(p learn.Pattern) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	dir := pattern.FindProjectDir(cwd)
+	if dir == "" {
+		dir = filepath.Join(inject.DetectProject(cwd).RootDir, pattern.ProjectPatternsDir)
+	}
+
+	v2 := pattern.FromV1(pattern.V1Pattern{
+		Name:        p.Name,
+		Description: p.Description,
+		Content:     p.Content,
+		Domain:      p.Domain,
+		Category:    p.Category,
+		Confidence:  p.Confidence,
+	})
+	if err := pattern.NewStore(dir).Create(v2); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 var learnGetCmd = &cobra.Command{
-	Use:   "get <name>",
+	Use:   "get [name|id]",
 	Short: "Show a pattern",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show a pattern, by name or by its stable ID.
+
+With --render, print exactly what a tool will see instead: the bytes sync
+would write to the tool's file (merged into the file's current content for
+shared files like Codex's instructions.md), or with --inject, the context a
+hook would inject for the pattern. Without a name, --render shows what
+would be injected for the current directory. Notes go to stderr, so the
+output can be diffed against the real file.
+
+Examples:
+  mur learn get retry-with-backoff
+  mur learn get retry-with-backoff --comments
+  mur learn get retry-with-backoff --render cursor
+  mur learn get retry-with-backoff --render claude --inject
+  mur learn get --render claude --prompt "fix flaky test"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if target, _ := cmd.Flags().GetString("render"); target != "" {
+			return renderForTarget(cmd, args, target)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("requires a pattern name, or --render to preview the current context")
+		}
 		name := args[0]
 
+		// A pattern's ID works in place of its name
+		id := ""
+		var sp *pattern.Pattern
+		if store, err := pattern.DefaultStore(); err == nil {
+			if sp, err = store.Resolve(name); err == nil {
+				name, id = sp.Name, sp.ID
+			}
+		}
+
 		p, err := learn.Get(name)
 		if err != nil {
 			return err
 		}
 
+		// Domain, category and confidence as 'mur learn list' and --where see them
+		view := sp
+		if view == nil || view.SchemaVersion < pattern.SchemaVersion {
+			view = fromLearnPattern(*p)
+		}
+		domain, category, confidence := view.GetPrimaryDomain(), view.GetCategory(), view.Learning.OriginalConfidence
+
+		// Comments are best effort: the pattern is shown either way
+		var comments []cloud.Comment
+		showComments, _ := cmd.Flags().GetBool("comments")
+		if showComments {
+			if comments, err = patternComments(cmd, name); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ Cannot show comments: %v\n", err)
+				showComments = false
+			}
+		}
+
+		if out := newPrinter(cmd); out.JSON() {
+			return out.WriteJSON(struct {
+				ID          string                 `json:"id,omitempty"`
+				Name        string                 `json:"name"`
+				Description string                 `json:"description"`
+				Domain      string                 `json:"domain"`
+				Category    string                 `json:"category"`
+				Tags        []string               `json:"tags"`
+				Confidence  float64                `json:"confidence"`
+				TeamShared  bool                   `json:"team_shared"`
+				CreatedAt   string                 `json:"created_at"`
+				UpdatedAt   string                 `json:"updated_at"`
+				Content     string                 `json:"content"`
+				Comments    []*cloud.CommentThread `json:"comments,omitempty"`
+			}{id, p.Name, p.Description, domain, category, append([]string{}, p.Tags...),
+				confidence, p.TeamShared, p.CreatedAt, p.UpdatedAt, p.Content, cloud.Threads(comments)})
+		}
+
 		fmt.Printf("Name:        %s\n", p.Name)
+		if id != "" {
+			fmt.Printf("ID:          %s\n", id)
+		}
 		fmt.Printf("Description: %s\n", p.Description)
-		fmt.Printf("Domain:      %s\n", p.Domain)
-		fmt.Printf("Category:    %s\n", p.Category)
-		fmt.Printf("Confidence:  %.0f%%\n", p.Confidence*100)
+		fmt.Printf("Domain:      %s\n", domain)
+		fmt.Printf("Category:    %s\n", category)
+		fmt.Printf("Confidence:  %.0f%%\n", confidence*100)
 		fmt.Printf("Created:     %s\n", p.CreatedAt)
 		fmt.Printf("Updated:     %s\n", p.UpdatedAt)
 		fmt.Println("")
 		fmt.Println("Content:")
 		fmt.Println("--------")
 		fmt.Println(p.Content)
+		if showComments {
+			printPatternComments(comments)
+		}
 
 		return nil
 	},
 }
 
+// renderForTarget prints what target will see: for a named pattern the
+// synced file (or with --inject, the injected context), and without one
+// the context injected for the current directory.
+func renderForTarget(cmd *cobra.Command, args []string, target string) error {
+	injectFlag, _ := cmd.Flags().GetBool("inject")
+	prompt, _ := cmd.Flags().GetString("prompt")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	format := inject.ResolveFormat(cfg, "", target)
+
+	if len(args) == 0 {
+		result, err := selectContext(cfg, store, prompt, "", 5, false)
+		if err != nil {
+			return fmt.Errorf("failed to select patterns: %w", err)
+		}
+		if len(result.Patterns) == 0 {
+			fmt.Fprintln(os.Stderr, "No patterns would be injected here.")
+			return nil
+		}
+		out, _, err := renderContext(format, true, inject.TokenBudget(cfg, 0), result.Context, result.Patterns)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📝 Injected for %s (%s format, %d patterns):\n", target, format, len(result.Patterns))
+		fmt.Print(out)
+		return nil
+	}
+
+	p, err := store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	if injectFlag {
+		out, _, err := renderContext(format, true, inject.TokenBudget(cfg, 0), nil, []*pattern.Pattern{p})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📝 Injected for %s (%s format):\n", target, format)
+		fmt.Print(out)
+		return nil
+	}
+
+	r, err := sync.RenderPatterns(cfg, target, []pattern.Pattern{*p})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "📄 %s: %s\n", r.Target, r.Path)
+	if len(r.Excluded) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ %s is project-scoped; sync leaves it out and only injection delivers it\n", p.Name)
+	} else if !r.Included {
+		fmt.Fprintf(os.Stderr, "ℹ %s only gets the mur-index skill in directory format; add --inject to see what hooks inject\n", r.Target)
+	}
+	fmt.Print(r.Content)
+	return nil
+}
+
 var learnDeleteCmd = &cobra.Command{
 	Use:   "delete <name>",
-	Short: "Delete a pattern",
-	Args:  cobra.ExactArgs(1),
+	Short: "Delete a pattern (moves it to the trash)",
+	Long: `Delete a pattern. It goes to the trash and can be restored with
+'mur learn trash restore' until storage.trash_days (default 30) have
+passed. --purge deletes it permanently right away; sync then deletes it
+remotely too.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
 		force, _ := cmd.Flags().GetBool("force")
+		purge, _ := cmd.Flags().GetBool("purge")
+
+		if purge {
+			if !force {
+				fmt.Printf("Permanently delete pattern '%s'? [y/N] ", name)
+				reader := bufio.NewReader(os.Stdin)
+				confirm, _ := reader.ReadString('\n')
+				confirm = strings.TrimSpace(strings.ToLower(confirm))
+				if confirm != "y" && confirm != "yes" {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+			store, err := pattern.DefaultStore()
+			if err != nil {
+				return err
+			}
+			if err := store.Purge(name); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Pattern '%s' permanently deleted\n", name)
+			fmt.Println("  Run 'mur learn sync' to update AI tools")
+			return nil
+		}
 
 		if !force {
 			fmt.Printf("Delete pattern '%s'? [y/N] ", name)
@@ -222,7 +399,8 @@ var learnDeleteCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("✓ Pattern '%s' deleted\n", name)
+		fmt.Printf("✓ Pattern '%s' moved to the trash\n", name)
+		fmt.Printf("  Restore with: mur learn trash restore %s\n", name)
 		fmt.Println("  Run 'mur learn sync' to update AI tools")
 
 		return nil
@@ -277,15 +455,46 @@ Examples:
   mur learn extract --auto --dry-run     # Preview without saving
   mur learn extract --auto --verbose     # Auto mode with output
   mur learn extract --auto --no-strict   # Auto mode without quality filter
+  mur learn extract --auto --full        # Rescan whole sessions, not just new messages
   mur learn extract --llm                # Use LLM (default from config)
   mur learn extract --llm ollama         # Use local Ollama
   mur learn extract --llm --since 2h     # Only from last 2 hours
   mur learn extract --llm --since "2024-01-01T10:00:00Z" --until "2024-01-01T12:00:00Z"
+  mur learn extract --watch              # Extract from active sessions as they grow
+  mur learn extract --watch --llm --watch-messages 40 --watch-idle 5m
+  mur learn extract --status             # Show auto-accept thresholds and rejection counts
+
+--auto keeps a watermark per session file (extract-state.json in mur's
+state directory) and only reads messages added since the last run, so
+sessions aren't mined for the same patterns again. --full ignores it and
+rescans; --since/--until select messages by time instead.
 
 When --auto is specified, these defaults apply:
   --quiet       (use --verbose to override)
   --strict      (use --no-strict to override)
-  --accept-all  (use --interactive to override)`,
+  --accept-all  (use --interactive to override)
+
+--accept-all saves patterns at or above a confidence threshold calibrated
+from the patterns you accepted and rejected when reviewing, per provider
+and category, so that learning.target_precision (default 90%) of them are
+ones you'd have accepted. Until there are enough reviews it is 0.6;
+--min-confidence sets it explicitly.
+
+Patterns you reject when reviewing are remembered by topic: LLM extraction
+lists recently rejected ones in its prompt and drops suggestions that
+still repeat them (same name words, in any order).
+
+LLM extraction works on learning.llm.concurrency sessions at once (default
+4, or 1 with Ollama), within per-provider learning.llm.rate_limits, and
+retries rate-limited requests with backoff. Results are shown in order.
+
+With --porcelain (--auto or --llm only), prints one line per pattern and
+a summary:
+  pattern<TAB>name<TAB>category<TAB>confidence<TAB>saved|skipped|failed|found
+  summary<TAB>extracted<TAB>saved
+  deferred<TAB>provider<TAB>reason     (on battery or a metered network)
+
+(found: --dry-run, not saved)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// --async: re-exec as detached background process
 		asyncMode, _ := cmd.Flags().GetBool("async")
@@ -293,84 +502,143 @@ When --auto is specified, these defaults apply:
 			return async.RunBackground(os.Args[1:])
 		}
 
-		// --timeout: wrap in context with deadline
-		timeoutStr, _ := cmd.Flags().GetString("timeout")
-		var ctx context.Context
-		var cancel context.CancelFunc
-		if timeoutStr != "" {
-			d, err := time.ParseDuration(timeoutStr)
-			if err != nil {
-				return fmt.Errorf("invalid --timeout value %q: %w", timeoutStr, err)
-			}
-			ctx, cancel = context.WithTimeout(context.Background(), d)
-			defer cancel()
-		} else {
-			// Default timeout for extract: 2 minutes
-			ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
+		// --coalesce: overlapping calls share one more pass over all
+		// pending sessions instead of each extracting
+		if coalesce, _ := cmd.Flags().GetBool("coalesce"); coalesce {
+			return runCoalesced(cmd, "extract", extractQuiet(cmd), func() error { return runLearnExtract(cmd, args) })
 		}
 
-		sessionID, _ := cmd.Flags().GetString("session")
-		auto, _ := cmd.Flags().GetBool("auto")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
-		llm, _ := cmd.Flags().GetString("llm")
-		llmModel, _ := cmd.Flags().GetString("llm-model")
-
-		// Get explicit flag values
-		acceptAll, _ := cmd.Flags().GetBool("accept-all")
-		quiet, _ := cmd.Flags().GetBool("quiet")
-		strict, _ := cmd.Flags().GetBool("strict")
-		verbose, _ := cmd.Flags().GetBool("verbose")
-		noStrict, _ := cmd.Flags().GetBool("no-strict")
-		interactive, _ := cmd.Flags().GetBool("interactive")
-
-		// When --auto is specified, apply sensible defaults
-		if auto {
-			// Default to quiet unless --verbose is specified
-			if !cmd.Flags().Changed("quiet") && !verbose {
-				quiet = true
-			}
-			if verbose {
-				quiet = false
-			}
+		return runLearnExtract(cmd, args)
+	},
+}
 
-			// Default to strict unless --no-strict is specified
-			if !cmd.Flags().Changed("strict") && !noStrict {
-				strict = true
-			}
-			if noStrict {
-				strict = false
-			}
+// runLearnExtract runs one extraction as the flags of cmd ask.
+func runLearnExtract(cmd *cobra.Command, args []string) error {
+	// --timeout: wrap in context with deadline
+	timeoutStr, _ := cmd.Flags().GetString("timeout")
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeoutStr != "" {
+		d, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return fmt.Errorf("invalid --timeout value %q: %w", timeoutStr, err)
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), d)
+		defer cancel()
+	} else {
+		// Default timeout for extract: 2 minutes
+		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+	}
 
-			// Default to accept-all unless --interactive is specified
-			if !cmd.Flags().Changed("accept-all") && !interactive {
-				acceptAll = true
-			}
-			if interactive {
-				acceptAll = false
-			}
+	if status, _ := cmd.Flags().GetBool("status"); status {
+		return runExtractStatus()
+	}
+
+	sessionID, _ := cmd.Flags().GetString("session")
+	auto, _ := cmd.Flags().GetBool("auto")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+	llm, _ := cmd.Flags().GetString("llm")
+	llmModel, _ := cmd.Flags().GetString("llm-model")
+
+	// Get explicit flag values
+	acceptAll, _ := cmd.Flags().GetBool("accept-all")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	strict, _ := cmd.Flags().GetBool("strict")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	noStrict, _ := cmd.Flags().GetBool("no-strict")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	full, _ := cmd.Flags().GetBool("full")
+
+	out := newPrinter(cmd)
+	if out.Porcelain() {
+		quiet, verbose = true, false
+	}
+
+	// When --auto is specified, apply sensible defaults
+	if auto {
+		// Default to quiet unless --verbose is specified
+		if !cmd.Flags().Changed("quiet") && !verbose {
+			quiet = true
+		}
+		if verbose && !out.Porcelain() {
+			quiet = false
 		}
 
-		sinceStr, _ := cmd.Flags().GetString("since")
-		untilStr, _ := cmd.Flags().GetString("until")
+		// Default to strict unless --no-strict is specified
+		if !cmd.Flags().Changed("strict") && !noStrict {
+			strict = true
+		}
+		if noStrict {
+			strict = false
+		}
 
-		// LLM mode
-		if llm != "" {
-			return runExtractLLM(ctx, sessionID, llm, llmModel, dryRun, acceptAll, quiet, strict, minConfidence, sinceStr, untilStr)
+		// Default to accept-all unless --interactive is specified
+		if !cmd.Flags().Changed("accept-all") && !interactive {
+			acceptAll = true
+		}
+		if interactive {
+			acceptAll = false
 		}
+	}
+
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
 
-		if auto {
-			return runExtractAuto(ctx, dryRun, acceptAll, quiet, minConfidence, sinceStr, untilStr)
+	if out.Porcelain() {
+		if llm == "" && !auto {
+			return fmt.Errorf("--porcelain needs --auto or --llm")
 		}
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			return fmt.Errorf("--porcelain doesn't support --watch")
+		}
+		if !acceptAll && !dryRun {
+			return fmt.Errorf("--porcelain can't prompt for each pattern; use --accept-all or --dry-run")
+		}
+	}
 
-		if sessionID != "" {
-			return runExtractSession(ctx, sessionID, dryRun, acceptAll, minConfidence)
+	// Watch mode: runs until interrupted, so the --timeout deadline does not apply
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		watchOpts := learn.DefaultWatchOptions()
+		if cmd.Flags().Changed("watch-messages") {
+			watchOpts.MinMessages, _ = cmd.Flags().GetInt("watch-messages")
 		}
+		if cmd.Flags().Changed("watch-idle") {
+			watchOpts.IdleAfter, _ = cmd.Flags().GetDuration("watch-idle")
+		}
+		if cmd.Flags().Changed("watch-interval") {
+			watchOpts.Interval, _ = cmd.Flags().GetDuration("watch-interval")
+		}
+		return runExtractWatch(llm, llmModel, dryRun, verbose, strict, minConfidence, watchOpts)
+	}
 
-		// Interactive mode: list sessions and let user choose
-		return runExtractInteractive(ctx, dryRun)
-	},
+	// LLM mode
+	if llm != "" {
+		return runExtractLLM(ctx, out, sessionID, llm, llmModel, auto, dryRun, acceptAll, quiet, strict, full, minConfidence, sinceStr, untilStr)
+	}
+
+	if auto {
+		return runExtractAuto(ctx, out, dryRun, acceptAll, quiet, full, minConfidence, sinceStr, untilStr)
+	}
+
+	if sessionID != "" {
+		return runExtractSession(ctx, sessionID, dryRun, acceptAll, minConfidence)
+	}
+
+	// Interactive mode: list sessions and let user choose
+	return runExtractInteractive(ctx, dryRun)
+}
+
+// extractQuiet reports whether extraction runs without progress output:
+// with --quiet or --porcelain, or under --auto unless --verbose is given.
+func extractQuiet(cmd *cobra.Command) bool {
+	if newPrinter(cmd).Quiet() {
+		return true
+	}
+	auto, _ := cmd.Flags().GetBool("auto")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return auto && !verbose && !cmd.Flags().Changed("quiet")
 }
 
 var learnInitRepoCmd = &cobra.Command{
@@ -381,16 +649,36 @@ var learnInitRepoCmd = &cobra.Command{
 Each machine uses its own branch (based on hostname by default).
 Shared patterns can be pulled from the main branch.
 
+The clone is shallow: history is fetched only when a merge or push needs
+it, or with 'mur learn deepen'. Repos that group patterns by domain in
+patterns/<domain>/ are checked out sparsely: only the domains in
+learning.domains, else tech_stack, plus patterns/general/, with other
+domains' files never downloaded. Patterns directly in patterns/ are always
+checked out. --domains picks the domains (and keeps them in
+learning.domains); --full clones everything as before.
+
 Examples:
   mur learn init git@github.com:user/learning-patterns.git
-  mur learn init https://github.com/user/learning-patterns.git`,
+  mur learn init https://github.com/user/learning-patterns.git
+  mur learn init --domains go,docker git@github.com:org/patterns.git
+  mur learn init --full git@github.com:user/learning-patterns.git`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repoURL := args[0]
 
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		opts := learning.CloneOptions{Domains: learning.SparseDomains(cfg)}
+		opts.Full, _ = cmd.Flags().GetBool("full")
+		if cmd.Flags().Changed("domains") {
+			opts.Domains, _ = cmd.Flags().GetStringSlice("domains")
+		}
+
 		fmt.Printf("Initializing learning repo: %s\n", repoURL)
 
-		if err := learning.InitRepo(repoURL); err != nil {
+		if err := learning.InitRepo(repoURL, opts); err != nil {
 			return fmt.Errorf("init failed: %w", err)
 		}
 
@@ -398,12 +686,41 @@ Examples:
 		fmt.Println("")
 		fmt.Println("✓ Learning repo initialized")
 		fmt.Printf("  Branch: %s\n", branch)
+		if dir, err := learning.RepoDir(); err == nil {
+			if domains := learning.CheckedOutDomains(dir); domains != nil {
+				fmt.Printf("  Domains: %s (sparse checkout)\n", strings.Join(domains, ", "))
+			}
+			if learning.IsShallow(dir) {
+				fmt.Println("  History: latest commit only ('mur learn deepen' fetches the rest)")
+			}
+		}
 		fmt.Println("  Run 'mur learn push' to sync patterns")
 
 		return nil
 	},
 }
 
+var learnDeepenCmd = &cobra.Command{
+	Use:   "deepen",
+	Short: "Fetch the full history of a shallow learning repo",
+	Long: `Fetch the history 'mur learn init' left out of the learning repo clone.
+
+Pulls and pushes do this by themselves when they need it; run it ahead
+of time, e.g. with --async in the background, to browse the history with
+git. With a sparse checkout only commits are fetched, not other domains'
+pattern files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if asyncMode, _ := cmd.Flags().GetBool("async"); asyncMode {
+			return async.RunBackground(os.Args[1:])
+		}
+		if err := learning.Deepen(); err != nil {
+			return err
+		}
+		fmt.Println("✓ Learning repo has its full history")
+		return nil
+	},
+}
+
 var learnPushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Push patterns to learning repo",
@@ -462,16 +779,29 @@ var learnAutoMergeCmd = &cobra.Command{
 The threshold is configured in ~/.mur/config.yaml under learning.merge_threshold
 (default: 0.8).
 
+Open pattern PRs can be merged once they pass a review gate configured under
+learning.merge_policy (require_ci, min_approvals, method). Run with --merge
+from cron or CI to poll the forge and merge every PR that satisfies it.
+
 Examples:
   mur learn auto-merge              # Create PRs for patterns >= 80% confidence
   mur learn auto-merge --dry-run    # Preview without creating PRs
-  mur learn auto-merge --threshold 0.9  # Use custom threshold`,
+  mur learn auto-merge --threshold 0.9  # Use custom threshold
+  mur learn auto-merge --status     # Show open pattern PRs and review state
+  mur learn auto-merge --merge      # Merge PRs that pass the merge policy
+  mur learn auto-merge --merge --require-ci --min-approvals 2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !learning.IsInitialized() {
 			return fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
 		}
 
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		status, _ := cmd.Flags().GetBool("status")
+		merge, _ := cmd.Flags().GetBool("merge")
+		if status || merge {
+			return runAutoMergeReview(cmd, merge, dryRun)
+		}
+
 		threshold, _ := cmd.Flags().GetFloat64("threshold")
 
 		// Override threshold in config if specified
@@ -544,29 +874,152 @@ Examples:
 	},
 }
 
+// runAutoMergeReview shows open pattern PRs against the merge policy and,
+// when merge is set, merges the ones that satisfy it.
+func runAutoMergeReview(cmd *cobra.Command, merge, dryRun bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	policy := learning.MergePolicyFromConfig(cfg)
+	if cmd.Flags().Changed("require-ci") {
+		policy.RequireCI, _ = cmd.Flags().GetBool("require-ci")
+	}
+	if cmd.Flags().Changed("min-approvals") {
+		policy.MinApprovals, _ = cmd.Flags().GetInt("min-approvals")
+	}
+
+	var decisions []learning.MergeDecision
+	if merge {
+		decisions, err = learning.MergeReadyPRs(policy, dryRun)
+	} else {
+		decisions, err = learning.PatternPRStatus(policy)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(decisions) == 0 {
+		fmt.Println("No open pattern PRs.")
+		return nil
+	}
+
+	if policy.Enabled() {
+		fmt.Printf("Merge policy: CI required=%v, min approvals=%d, method=%s\n\n", policy.RequireCI, policy.MinApprovals, policy.Method)
+	} else {
+		fmt.Println("Merge policy: none (set learning.merge_policy to enable merging)")
+		fmt.Println("")
+	}
+
+	fmt.Printf("  %-6s %-40s %-9s %-9s %s\n", "PR", "TITLE", "APPROVED", "CI", "STATUS")
+	merged := 0
+	for _, d := range decisions {
+		state := d.Reason
+		switch {
+		case d.Error != nil:
+			state = "✗ " + d.Error.Error()
+		case d.Merged:
+			state = "✓ merged"
+			merged++
+		case d.Ready && merge && dryRun:
+			state = "would merge"
+		case d.Ready:
+			state = "✓ ready"
+		}
+		fmt.Printf("  #%-5d %-40s %-9d %-9s %s\n",
+			d.PR.Number, truncate(d.PR.Title, 40), d.PR.Approvals, d.PR.Checks, state)
+	}
+
+	if merge {
+		fmt.Println("")
+		if dryRun {
+			fmt.Println("(dry-run mode, no PRs merged)")
+		} else {
+			fmt.Printf("PRs merged: %d of %d open\n", merged, len(decisions))
+		}
+	}
+	return nil
+}
+
 var learnPullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pull shared patterns from main branch",
 	Long: `Pull shared patterns from the main branch of the learning repo.
 
 This imports patterns that others have shared without overwriting
-your local patterns.`,
+your local patterns. Use --branch (or learning.pull_branches) to also
+import from your other machines' branches.
+
+Patterns whose content matches a local pattern under a different name
+are detected via the content-hash registry kept in the repo and handled
+by --dedupe (or learning.dedupe):
+  skip   don't import the duplicate
+  link   don't import it, record its name as an alias (default)
+  merge  fold its tags and description into the local pattern, then link
+  off    import duplicates as separate patterns
+
+Examples:
+  mur learn pull
+  mur learn pull --branch work-laptop
+  mur learn pull --branch work-laptop --dedupe merge`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !learning.IsInitialized() {
 			return fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
 		}
 
-		fmt.Println("Pulling patterns from main branch...")
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		dedupe, _ := cmd.Flags().GetString("dedupe")
+		mode, err := learning.ResolveDedupeMode(cfg, dedupe)
+		if err != nil {
+			return err
+		}
+		branches := cfg.Learning.PullBranches
+		if cmd.Flags().Changed("branch") {
+			branches, _ = cmd.Flags().GetStringSlice("branch")
+		}
+
+		if len(branches) > 0 {
+			fmt.Printf("Pulling patterns from main and %s...\n", strings.Join(branches, ", "))
+		} else {
+			fmt.Println("Pulling patterns from main branch...")
+		}
 
-		if err := learning.Pull(); err != nil {
+		result, err := learning.Pull(learning.PullOptions{Branches: branches, Dedupe: mode})
+		if err != nil {
 			return fmt.Errorf("pull failed: %w", err)
 		}
 
 		fmt.Println("✓ Patterns pulled")
+		printPullResult(result)
 		return nil
 	},
 }
 
+// printPullResult reports imported and deduplicated patterns.
+func printPullResult(result *learning.PullResult) {
+	if result == nil {
+		return
+	}
+	fmt.Printf("  Imported: %d\n", len(result.Imported))
+	if len(result.Deduped) == 0 {
+		return
+	}
+	fmt.Printf("  Deduped:  %d\n", len(result.Deduped))
+	for _, d := range result.Deduped {
+		switch d.Action {
+		case learning.DedupeSkip:
+			fmt.Printf("    %s (%s) skipped, same as %s\n", d.Name, d.Source, d.DuplicateOf)
+		case learning.DedupeMerge:
+			fmt.Printf("    %s (%s) merged into %s\n", d.Name, d.Source, d.DuplicateOf)
+		default:
+			fmt.Printf("    %s (%s) linked to %s\n", d.Name, d.Source, d.DuplicateOf)
+		}
+	}
+}
+
 var learnSyncRepoCmd = &cobra.Command{
 	Use:   "repo-sync",
 	Short: "Sync patterns with learning repo (push + pull)",
@@ -581,19 +1034,21 @@ shared patterns from main (if pull_from_main is enabled).`,
 
 		fmt.Println("Syncing with learning repo...")
 
-		if err := learning.Sync(); err != nil {
+		result, err := learning.Sync()
+		if err != nil {
 			return fmt.Errorf("sync failed: %w", err)
 		}
 
 		fmt.Println("✓ Sync complete")
+		printPullResult(result)
 		return nil
 	},
 }
 
-func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfidence float64, sinceStr, untilStr string) error {
-	if minConfidence == 0 {
-		minConfidence = 0.6 // Default threshold for auto-accept
-	}
+func runExtractAuto(ctx context.Context, out *output.Printer, dryRun, acceptAll, quiet, full bool, minConfidence float64, sinceStr, untilStr string) error {
+	cfg, _ := config.Load()
+	thresholds := learn.LoadThresholds(cfg, minConfidence)
+	rejections, _ := learn.LoadRejections()
 
 	if !quiet {
 		fmt.Println("Scanning recent sessions...")
@@ -609,12 +1064,19 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 	sessions = filterSessionsByTime(sessions, sinceStr, untilStr)
 
 	if len(sessions) == 0 {
+		out.Record("summary", "0", "0")
 		if !quiet {
 			fmt.Println("No recent sessions found.")
 		}
 		return nil
 	}
 
+	// Only what sessions gained since the last run
+	state := loadExtractState(sinceStr == "" && untilStr == "", full)
+	if state != nil && !dryRun {
+		defer func() { _ = state.Save() }()
+	}
+
 	totalExtracted := 0
 	savedCount := 0
 	skippedCount := 0
@@ -623,10 +1085,22 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 		if err := ctx.Err(); err != nil {
 			return fmt.Errorf("timeout exceeded: %w", err)
 		}
-		patterns, err := learn.ExtractFromSession(session.Path)
+		window, err := learn.LoadSession(session.Path)
 		if err != nil {
 			continue
 		}
+		if state != nil {
+			if window = state.Unprocessed(window); len(window.Messages) == 0 {
+				continue
+			}
+		}
+		patterns, err := learn.ExtractFromSessionData(window)
+		if err != nil {
+			continue
+		}
+		if state != nil {
+			state.Mark(window)
+		}
 
 		if len(patterns) == 0 {
 			continue
@@ -645,6 +1119,7 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 			}
 
 			if dryRun {
+				recordExtracted(out, ep, "found")
 				if !quiet {
 					fmt.Println("")
 				}
@@ -653,26 +1128,32 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 
 			// Accept all mode: auto-save if confidence >= threshold
 			if acceptAll {
-				if ep.Confidence >= minConfidence {
+				threshold := thresholds.For(learn.ReviewKeyword, ep.Pattern.Category).Threshold
+				if ep.Confidence >= threshold {
 					if err := learn.Add(ep.Pattern); err != nil {
 						if !quiet {
-							fmt.Printf("  ✗ Failed to save: %v\n", err)
+							fmt.Printf("  %s Failed to save: %v\n", out.Red("✗"), err)
 						}
+						recordExtracted(out, ep, "failed")
 					} else {
 						if !quiet {
-							fmt.Printf("  ✓ Auto-saved '%s' (%.0f%% confidence)\n", ep.Pattern.Name, ep.Confidence*100)
+							fmt.Printf("  %s Auto-saved '%s' (%.0f%% confidence)\n", out.Green("✓"), ep.Pattern.Name, ep.Confidence*100)
 						}
+						recordExtracted(out, ep, "saved")
 						savedCount++
 					}
 				} else {
 					skippedCount++
 					if !quiet {
-						fmt.Printf("  ⊘ Skipped (%.0f%% < %.0f%% threshold)\n", ep.Confidence*100, minConfidence*100)
+						fmt.Printf("  %s Skipped (%.0f%% < %.0f%% threshold)\n", out.Yellow("⊘"), ep.Confidence*100, threshold*100)
 					}
+					recordExtracted(out, ep, "skipped")
 				}
 			} else {
 				// Interactive mode
-				if confirmSave(ep.Pattern.Name) {
+				accepted := confirmSave(ep.Pattern.Name)
+				recordReview(learn.ReviewKeyword, ep, accepted, rejections)
+				if accepted {
 					if err := learn.Add(ep.Pattern); err != nil {
 						fmt.Printf("  ✗ Failed to save: %v\n", err)
 					} else {
@@ -688,6 +1169,7 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 		}
 	}
 
+	out.Record("summary", fmt.Sprint(totalExtracted), fmt.Sprint(savedCount))
 	if !quiet {
 		if totalExtracted == 0 {
 			fmt.Println("No patterns found in recent sessions.")
@@ -729,76 +1211,17 @@ func runExtractAuto(ctx context.Context, dryRun, acceptAll, quiet bool, minConfi
 	return nil
 }
 
-func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRun, acceptAll, quiet, strict bool, minConfidence float64, sinceStr, untilStr string) error {
+func runExtractLLM(ctx context.Context, out *output.Printer, sessionID, provider, model string, auto, dryRun, acceptAll, quiet, strict, full bool, minConfidence float64, sinceStr, untilStr string) error {
 	// Setup quality config for strict mode
 	qualityCfg := learn.DefaultExtractionConfig()
 
-	// Setup LLM options
-	opts := learn.DefaultLLMOptions()
-	configuredProvider := false
-
 	// Load config for defaults
 	cfg, _ := config.Load()
-	if cfg != nil && cfg.Learning.LLM.Provider != "" {
-		configuredProvider = true
-		// Use config defaults
-		switch strings.ToLower(cfg.Learning.LLM.Provider) {
-		case "ollama":
-			opts.Provider = learn.LLMOllama
-		case "claude":
-			opts.Provider = learn.LLMClaude
-		case "openai":
-			opts.Provider = learn.LLMOpenAI
-		case "gemini":
-			opts.Provider = learn.LLMGemini
-		}
-		if cfg.Learning.LLM.Model != "" {
-			opts.Model = cfg.Learning.LLM.Model
-		}
-		if cfg.Learning.LLM.OllamaURL != "" {
-			opts.OllamaURL = cfg.Learning.LLM.OllamaURL
-		}
-		if cfg.Learning.LLM.OpenAIURL != "" {
-			opts.OpenAIURL = cfg.Learning.LLM.OpenAIURL
-		}
-		// Support custom API key env var
-		if cfg.Learning.LLM.APIKeyEnv != "" {
-			key := os.Getenv(cfg.Learning.LLM.APIKeyEnv)
-			if key != "" {
-				switch opts.Provider {
-				case learn.LLMOpenAI:
-					opts.OpenAIKey = key
-				case learn.LLMGemini:
-					opts.GeminiKey = key
-				case learn.LLMClaude:
-					opts.ClaudeKey = key
-				}
-			}
-		}
-	}
-
-	// Command line flags override config
-	switch strings.ToLower(provider) {
-	case "ollama":
-		opts.Provider = learn.LLMOllama
-		configuredProvider = true
-	case "claude":
-		opts.Provider = learn.LLMClaude
-		configuredProvider = true
-	case "openai":
-		opts.Provider = learn.LLMOpenAI
-		configuredProvider = true
-	case "gemini":
-		opts.Provider = learn.LLMGemini
-		configuredProvider = true
-	case "", "default":
-		// Use config default (already set above), or auto-detect
-	default:
-		return fmt.Errorf("unknown LLM provider: %s (use 'ollama', 'claude', 'openai', or 'gemini')", provider)
-	}
 
-	if model != "" {
-		opts.Model = model
+	// Setup LLM options
+	opts, configuredProvider, err := resolveLLMOptions(cfg, provider, model)
+	if err != nil {
+		return err
 	}
 
 	// Auto-detect: if no provider configured, try Ollama
@@ -815,7 +1238,7 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 			fmt.Fprintln(os.Stderr, "⚠️  No LLM available (Ollama not running, no API keys)")
 			fmt.Fprintln(os.Stderr, "   Falling back to keyword extraction (lower quality)")
 			// Call keyword-based extraction instead
-			return runExtractAuto(ctx, dryRun, acceptAll, quiet, minConfidence, sinceStr, untilStr)
+			return runExtractAuto(ctx, out, dryRun, acceptAll, quiet, full, minConfidence, sinceStr, untilStr)
 		}
 	}
 
@@ -825,7 +1248,7 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 		if !sysinfo.OllamaRunning(opts.OllamaURL) {
 			// Always warn (even in quiet mode)
 			fmt.Fprintln(os.Stderr, "⚠️  Ollama not available, falling back to keyword extraction")
-			return runExtractAuto(ctx, dryRun, acceptAll, quiet, minConfidence, sinceStr, untilStr)
+			return runExtractAuto(ctx, out, dryRun, acceptAll, quiet, full, minConfidence, sinceStr, untilStr)
 		}
 	case learn.LLMClaude:
 		if opts.ClaudeKey == "" {
@@ -841,9 +1264,35 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 		}
 	}
 
-	if minConfidence == 0 {
-		minConfidence = 0.6
+	// Hook-triggered runs hold cloud extraction back on battery or metered
+	// networks; the scheduled sync catches up. Local Ollama still runs.
+	deferReason := ""
+	if auto && !dryRun {
+		deferReason = learn.DeferReason(cfg)
 	}
+	if deferReason != "" && opts.Provider != learn.LLMOllama {
+		err := learn.Defer(learn.DeferredExtraction{
+			DeferredAt: time.Now(),
+			Reason:     deferReason,
+			Provider:   string(opts.Provider),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to defer extraction: %w", err)
+		}
+		out.Record("deferred", string(opts.Provider), deferReason)
+		if !quiet {
+			fmt.Printf("⏸ Deferred %s extraction (%s); the next 'mur sync' catches up\n", opts.Provider, deferReason)
+		}
+		return nil
+	}
+
+	thresholds := learn.LoadThresholds(cfg, minConfidence)
+
+	// Patterns rejected before are listed in the prompt and filtered out
+	// of what the LLM suggests.
+	rejections, _ := learn.LoadRejections()
+	opts.Rejected = rejections
+	_, filteredBefore := rejections.Counts()
 
 	// Get sessions to process
 	var sessions []*learn.Session
@@ -904,9 +1353,29 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 		sessions = filtered
 	}
 
+	// --auto reads only what sessions gained since the last run
+	state := loadExtractState(auto && sessionID == "" && sinceStr == "" && untilStr == "", full)
+	if state != nil {
+		if !dryRun {
+			defer func() { _ = state.Save() }()
+		}
+		var fresh []*learn.Session
+		for _, s := range sessions {
+			if window := state.Unprocessed(s); len(window.Messages) > 0 {
+				fresh = append(fresh, window)
+			}
+		}
+		sessions = fresh
+	}
+
 	if len(sessions) == 0 {
+		out.Record("summary", "0", "0")
 		if !quiet {
-			fmt.Println("No sessions found.")
+			if state != nil {
+				fmt.Println("No new messages since the last run (--full rescans).")
+			} else {
+				fmt.Println("No sessions found.")
+			}
 		}
 		return nil
 	}
@@ -948,7 +1417,11 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 				}
 			}
 		}
+		po.Rejected = rejections
 		premiumOpts = &po
+		if deferReason != "" && po.Provider != learn.LLMOllama {
+			premiumOpts = nil // Stay local until the conditions clear
+		}
 	}
 
 	if !quiet {
@@ -965,22 +1438,9 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 	consecutiveErrors := 0
 	var lastError string
 
+	// Strict mode: pre-filter sessions by quality
+	queue := sessions[:0]
 	for _, session := range sessions {
-		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("timeout exceeded: %w", err)
-		}
-		// Stop if we get too many consecutive errors (likely config issue)
-		if consecutiveErrors >= 3 {
-			errMsg := fmt.Sprintf("LLM Error: %s", lastError)
-			fmt.Fprintln(os.Stderr, "⛔ Stopping: 3 consecutive extraction failures")
-			fmt.Fprintf(os.Stderr, "   Last error: %s\n", lastError)
-			fmt.Fprintln(os.Stderr, "   Check your LLM configuration in ~/.mur/config.yaml")
-			// Send system notification
-			_ = notify.NotifyCritical("mur: Extraction Failed", errMsg)
-			break
-		}
-
-		// Strict mode: pre-filter sessions by quality
 		if strict {
 			quality := learn.AnalyzeSessionQuality(session)
 			shouldExtract, reason := learn.ShouldExtract(quality, qualityCfg)
@@ -992,27 +1452,63 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 				continue
 			}
 		}
+		queue = append(queue, session)
+	}
 
-		// Check if this session should use premium model
-		useOpts := opts
-		usePremium := false
-		if premiumOpts != nil && cfg.Learning.LLM.Routing != nil {
-			routing := cfg.Learning.LLM.Routing
-			// Check message count
-			if routing.MinMessages > 0 && len(session.Messages) >= routing.MinMessages {
-				usePremium = true
-			}
-			// Check project patterns
-			for _, proj := range routing.Projects {
-				if strings.Contains(strings.ToLower(session.Project), strings.ToLower(proj)) {
-					usePremium = true
-					break
-				}
-			}
-			if usePremium {
-				useOpts = *premiumOpts
+	// Check if a session should use the premium model
+	usesPremium := func(session *learn.Session) bool {
+		if premiumOpts == nil || cfg.Learning.LLM.Routing == nil {
+			return false
+		}
+		routing := cfg.Learning.LLM.Routing
+		// Check message count
+		if routing.MinMessages > 0 && len(session.Messages) >= routing.MinMessages {
+			return true
+		}
+		// Check project patterns
+		for _, proj := range routing.Projects {
+			if strings.Contains(strings.ToLower(session.Project), strings.ToLower(proj)) {
+				return true
 			}
 		}
+		return false
+	}
+
+	// Sessions are extracted by a pool of workers sharing one rate limiter
+	// per provider; results are handled below in session order.
+	limiters := make(map[learn.LLMProvider]*learn.RateLimiter)
+	limiterFor := func(p learn.LLMProvider) *learn.RateLimiter {
+		if limiters[p] == nil {
+			limiters[p] = learn.NewRateLimiter(extractionRateLimit(cfg, p))
+		}
+		return limiters[p]
+	}
+	opts.Limiter = limiterFor(opts.Provider)
+	if premiumOpts != nil {
+		premiumOpts.Limiter = limiterFor(premiumOpts.Provider)
+	}
+
+	extract := func(session *learn.Session) ([]learn.ExtractedPattern, error) {
+		if !usesPremium(session) {
+			return learn.ExtractWithLLM(session, opts)
+		}
+		patterns, err := learn.ExtractWithLLM(session, *premiumOpts)
+		if err != nil {
+			// If premium failed, fallback to default model
+			fmt.Fprintf(os.Stderr, "⚠️  Premium model failed for %s: %v\n", session.ShortID(), err)
+			fmt.Fprintf(os.Stderr, "   ↪ Falling back to %s...\n", opts.Provider)
+			patterns, err = learn.ExtractWithLLM(session, opts)
+		}
+		return patterns, err
+	}
+
+	learn.ExtractBatch(ctx, queue, extractionConcurrency(cfg, opts.Provider), extract, func(r learn.BatchResult) bool {
+		session, patterns := r.Session, r.Patterns
+		provider := opts.Provider
+		usePremium := usesPremium(session)
+		if usePremium {
+			provider = premiumOpts.Provider
+		}
 
 		if !quiet {
 			if usePremium {
@@ -1022,30 +1518,32 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 			}
 		}
 
-		patterns, err := learn.ExtractWithLLM(session, useOpts)
-		if err != nil {
-			// If premium failed, fallback to default model
-			if usePremium {
-				fmt.Fprintf(os.Stderr, "⚠️  Premium model failed for %s: %v\n", session.ShortID(), err)
-				if !quiet {
-					fmt.Printf("   ↪ Falling back to %s...\n", opts.Provider)
-				}
-				patterns, err = learn.ExtractWithLLM(session, opts)
+		if r.Err != nil {
+			// Track consecutive errors
+			consecutiveErrors++
+			lastError = r.Err.Error()
+			// Only print first error of each type
+			if consecutiveErrors == 1 {
+				fmt.Fprintf(os.Stderr, "⚠️  Extraction failed: %v\n", r.Err)
 			}
-			if err != nil {
-				// Track consecutive errors
-				consecutiveErrors++
-				lastError = err.Error()
-				// Only print first error of each type
-				if consecutiveErrors == 1 {
-					fmt.Fprintf(os.Stderr, "⚠️  Extraction failed: %v\n", err)
-				}
-				continue
+			// Stop if we get too many consecutive errors (likely config issue)
+			if consecutiveErrors >= 3 {
+				errMsg := fmt.Sprintf("LLM Error: %s", lastError)
+				fmt.Fprintln(os.Stderr, "⛔ Stopping: 3 consecutive extraction failures")
+				fmt.Fprintf(os.Stderr, "   Last error: %s\n", lastError)
+				fmt.Fprintln(os.Stderr, "   Check your LLM configuration in ~/.mur/config.yaml")
+				// Send system notification
+				_ = notify.NotifyCritical("mur: Extraction Failed", errMsg)
+				return false
 			}
+			return true
 		}
 
 		// Reset consecutive error counter on success
 		consecutiveErrors = 0
+		if state != nil {
+			state.Mark(session)
+		}
 
 		// Strict mode: filter patterns by quality
 		if strict {
@@ -1056,7 +1554,7 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 			if !quiet {
 				fmt.Println("   No patterns found")
 			}
-			continue
+			return true
 		}
 
 		if !quiet {
@@ -1068,28 +1566,36 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 
 			if !quiet {
 				fmt.Printf("   • [%s] %s (%.0f%%)\n", ep.Pattern.Category, ep.Pattern.Name, ep.Confidence*100)
+				printCodeCheck(ep, "     ")
 			}
 
 			if dryRun {
+				recordExtracted(out, ep, "found")
 				continue
 			}
 
 			if acceptAll {
-				if ep.Confidence >= minConfidence {
+				if ep.Confidence >= thresholds.For(string(provider), ep.Pattern.Category).Threshold {
 					if err := learn.Add(ep.Pattern); err != nil {
 						if !quiet {
-							fmt.Printf("     ✗ Failed to save: %v\n", err)
+							fmt.Printf("     %s Failed to save: %v\n", out.Red("✗"), err)
 						}
+						recordExtracted(out, ep, "failed")
 					} else {
 						if !quiet {
-							fmt.Printf("     ✓ Saved\n")
+							fmt.Printf("     %s Saved\n", out.Green("✓"))
 						}
+						recordExtracted(out, ep, "saved")
 						savedCount++
 					}
+				} else {
+					recordExtracted(out, ep, "skipped")
 				}
 			} else {
 				// Interactive mode
-				if confirmSave(ep.Pattern.Name) {
+				accepted := confirmSave(ep.Pattern.Name)
+				recordReview(string(provider), ep, accepted, rejections)
+				if accepted {
 					if err := learn.Add(ep.Pattern); err != nil {
 						fmt.Printf("     ✗ Failed to save: %v\n", err)
 					} else {
@@ -1103,8 +1609,24 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 		if !quiet {
 			fmt.Println()
 		}
+		return true
+	})
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("timeout exceeded: %w", err)
+	}
+
+	// A full run covers every recent session, including deferred ones
+	if auto && !dryRun && consecutiveErrors < 3 {
+		_ = learn.ClearDeferred()
+	}
+
+	_, filteredAfter := rejections.Counts()
+	repeats := filteredAfter - filteredBefore
+	if repeats > 0 && !dryRun {
+		_ = rejections.Save()
 	}
 
+	out.Record("summary", fmt.Sprint(totalExtracted), fmt.Sprint(savedCount))
 	if !quiet {
 		if dryRun {
 			fmt.Printf("Found %d patterns (dry-run, not saved)\n", totalExtracted)
@@ -1114,17 +1636,208 @@ func runExtractLLM(ctx context.Context, sessionID, provider, model string, dryRu
 		if strict && skippedSessions > 0 {
 			fmt.Printf("Skipped %d low-quality sessions (strict mode)\n", skippedSessions)
 		}
+		if repeats > 0 {
+			fmt.Printf("Dropped %d repeats of previously rejected patterns\n", repeats)
+		}
 	}
 
 	// Send notification for successful extraction
 	if !dryRun && savedCount > 0 {
-		_ = notify.NotifySuccess(fmt.Sprintf("%d new patterns extracted", savedCount))
+		_ = notify.NotifySuccess(fmt.Sprintf("%d new patterns extracted", savedCount),
+			notify.TerminalAction("Open review", fmt.Sprintf("mur learn list --sort 'created desc' -n %d", savedCount)))
 	}
 
 	return nil
 }
 
+// extractionConcurrency returns how many sessions to extract at once:
+// learning.llm.concurrency, or the provider's default.
+func extractionConcurrency(cfg *config.Config, provider learn.LLMProvider) int {
+	if cfg != nil && cfg.Learning.LLM.Concurrency > 0 {
+		return cfg.Learning.LLM.Concurrency
+	}
+	return learn.DefaultConcurrency(provider)
+}
+
+// extractionRateLimit returns the requests per minute extraction may send
+// to provider: learning.llm.rate_limits, or the provider's default.
+func extractionRateLimit(cfg *config.Config, provider learn.LLMProvider) int {
+	if cfg != nil {
+		if rpm, ok := cfg.Learning.LLM.RateLimits[string(provider)]; ok {
+			return rpm
+		}
+	}
+	return learn.DefaultRateLimit(provider)
+}
+
+// resolveLLMOptions builds extraction options from config, with the
+// provider and model flags taking precedence. The bool reports whether a
+// provider was explicitly configured (as opposed to the built-in default).
+func resolveLLMOptions(cfg *config.Config, provider, model string) (learn.LLMExtractOptions, bool, error) {
+	opts := learn.DefaultLLMOptions()
+	configuredProvider := false
+
+	if cfg != nil && cfg.Learning.LLM.Provider != "" {
+		configuredProvider = true
+		// Use config defaults
+		switch strings.ToLower(cfg.Learning.LLM.Provider) {
+		case "ollama":
+			opts.Provider = learn.LLMOllama
+		case "claude":
+			opts.Provider = learn.LLMClaude
+		case "openai":
+			opts.Provider = learn.LLMOpenAI
+		case "gemini":
+			opts.Provider = learn.LLMGemini
+		}
+		if cfg.Learning.LLM.Model != "" {
+			opts.Model = cfg.Learning.LLM.Model
+		}
+		if cfg.Learning.LLM.OllamaURL != "" {
+			opts.OllamaURL = cfg.Learning.LLM.OllamaURL
+		}
+		if cfg.Learning.LLM.OpenAIURL != "" {
+			opts.OpenAIURL = cfg.Learning.LLM.OpenAIURL
+		}
+		// Support custom API key env var
+		if cfg.Learning.LLM.APIKeyEnv != "" {
+			key := os.Getenv(cfg.Learning.LLM.APIKeyEnv)
+			if key != "" {
+				switch opts.Provider {
+				case learn.LLMOpenAI:
+					opts.OpenAIKey = key
+				case learn.LLMGemini:
+					opts.GeminiKey = key
+				case learn.LLMClaude:
+					opts.ClaudeKey = key
+				}
+			}
+		}
+	}
+
+	// Command line flags override config
+	switch strings.ToLower(provider) {
+	case "ollama":
+		opts.Provider = learn.LLMOllama
+		configuredProvider = true
+	case "claude":
+		opts.Provider = learn.LLMClaude
+		configuredProvider = true
+	case "openai":
+		opts.Provider = learn.LLMOpenAI
+		configuredProvider = true
+	case "gemini":
+		opts.Provider = learn.LLMGemini
+		configuredProvider = true
+	case "", "default":
+		// Use config default (already set above), or auto-detect
+	default:
+		return opts, false, fmt.Errorf("unknown LLM provider: %s (use 'ollama', 'claude', 'openai', or 'gemini')", provider)
+	}
+
+	if model != "" {
+		opts.Model = model
+	}
+
+	return opts, configuredProvider, nil
+}
+
+// runExtractWatch tails active sessions and extracts patterns whenever a
+// session crosses the new-message or idle threshold. Patterns above
+// minConfidence are saved automatically; there is no interactive prompt.
+func runExtractWatch(provider, model string, dryRun, verbose, strict bool, minConfidence float64, watchOpts learn.WatchOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, _ := config.Load()
+	thresholds := learn.LoadThresholds(cfg, minConfidence)
+	reviewProvider := learn.ReviewKeyword
+
+	qualityCfg := learn.DefaultExtractionConfig()
+	var extract learn.WatchExtractFunc
+	if provider != "" {
+		opts, _, err := resolveLLMOptions(cfg, provider, model)
+		if err != nil {
+			return err
+		}
+		reviewProvider = string(opts.Provider)
+		rejections, _ := learn.LoadRejections()
+		opts.Rejected = rejections
+		extract = func(s *learn.Session) ([]learn.ExtractedPattern, error) {
+			_, before := rejections.Counts()
+			patterns, err := learn.ExtractWithLLM(s, opts)
+			if _, after := rejections.Counts(); after > before && !dryRun {
+				_ = rejections.Save()
+			}
+			return patterns, err
+		}
+	}
+
+	watcher := learn.NewSessionWatcher(watchOpts, extract)
+
+	fmt.Printf("Watching active sessions (every %s, after %d new messages or %s idle)\n",
+		watchOpts.Interval, watchOpts.MinMessages, watchOpts.IdleAfter)
+	if dryRun {
+		fmt.Println("Dry-run: patterns will not be saved")
+	}
+	fmt.Println("Press Ctrl+C to stop.")
+	fmt.Println()
+
+	savedCount := 0
+	err := watcher.Run(ctx, func(b learn.WatchBatch) {
+		if b.Err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Extraction failed for %s: %v\n", b.Session.ShortID(), b.Err)
+			return
+		}
+
+		patterns := b.Patterns
+		if strict {
+			patterns = learn.FilterPatterns(patterns, qualityCfg)
+		}
+		if len(patterns) == 0 {
+			if verbose {
+				fmt.Printf("%s 📝 %s (%s): %d messages, no patterns (%s)\n",
+					time.Now().Format("15:04:05"), b.Session.ShortID(), b.Session.Project, len(b.Session.Messages), b.Reason)
+			}
+			return
+		}
+
+		fmt.Printf("%s 📝 %s (%s): %d messages, %d patterns (%s)\n",
+			time.Now().Format("15:04:05"), b.Session.ShortID(), b.Session.Project, len(b.Session.Messages), len(patterns), b.Reason)
+
+		for _, ep := range patterns {
+			fmt.Printf("   • [%s] %s (%.0f%%)\n", ep.Pattern.Category, ep.Pattern.Name, ep.Confidence*100)
+			printCodeCheck(ep, "     ")
+			if dryRun || ep.Confidence < thresholds.For(reviewProvider, ep.Pattern.Category).Threshold {
+				continue
+			}
+			if err := learn.Add(ep.Pattern); err != nil {
+				fmt.Printf("     ✗ Failed to save: %v\n", err)
+				continue
+			}
+			fmt.Printf("     ✓ Saved\n")
+			savedCount++
+		}
+	})
+
+	fmt.Println()
+	fmt.Printf("Stopped watching. Saved %d patterns.\n", savedCount)
+	if !dryRun && savedCount > 0 {
+		_ = notify.NotifySuccess(fmt.Sprintf("%d new patterns extracted", savedCount),
+			notify.TerminalAction("Open review", fmt.Sprintf("mur learn list --sort 'created desc' -n %d", savedCount)))
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
 func runExtractSession(_ context.Context, sessionID string, dryRun, acceptAll bool, minConfidence float64) error {
+	cfg, _ := config.Load()
+	thresholds := learn.LoadThresholds(cfg, minConfidence)
+	rejections, _ := learn.LoadRejections()
+
 	session, err := learn.LoadSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to load session: %w", err)
@@ -1149,6 +1862,7 @@ func runExtractSession(_ context.Context, sessionID string, dryRun, acceptAll bo
 
 	saved := 0
 	skipped := 0
+	var lowest float64
 
 	for i, ep := range patterns {
 		fmt.Printf("%d. ", i+1)
@@ -1159,15 +1873,20 @@ func runExtractSession(_ context.Context, sessionID string, dryRun, acceptAll bo
 
 			if acceptAll {
 				// Auto-accept if confidence meets threshold
-				if ep.Confidence >= minConfidence {
+				threshold := thresholds.For(learn.ReviewKeyword, ep.Pattern.Category).Threshold
+				if ep.Confidence >= threshold {
 					shouldSave = true
 				} else {
-					fmt.Printf("   Skipped (confidence %.0f%% < %.0f%%)\n", ep.Confidence*100, minConfidence*100)
+					fmt.Printf("   Skipped (confidence %.0f%% < %.0f%%)\n", ep.Confidence*100, threshold*100)
 					skipped++
+					if lowest == 0 || threshold < lowest {
+						lowest = threshold
+					}
 				}
 			} else {
 				// Interactive mode
 				shouldSave = confirmSave(ep.Pattern.Name)
+				recordReview(learn.ReviewKeyword, ep, shouldSave, rejections)
 			}
 
 			if shouldSave {
@@ -1185,7 +1904,11 @@ func runExtractSession(_ context.Context, sessionID string, dryRun, acceptAll bo
 	if dryRun {
 		fmt.Println("(dry-run mode, patterns not saved)")
 	} else if acceptAll {
-		fmt.Printf("Saved %d patterns, skipped %d (below %.0f%% confidence)\n", saved, skipped, minConfidence*100)
+		if skipped > 0 {
+			fmt.Printf("Saved %d patterns, skipped %d (below %.0f%%+ confidence)\n", saved, skipped, lowest*100)
+		} else {
+			fmt.Printf("Saved %d patterns\n", saved)
+		}
 	}
 
 	return nil
@@ -1240,7 +1963,7 @@ func runExtractInteractive(ctx context.Context, dryRun bool) error {
 	fmt.Println("")
 
 	// In interactive mode, don't auto-accept (user chose to interact)
-	return runExtractSession(ctx, selected.ID, dryRun, false, 0.6)
+	return runExtractSession(ctx, selected.ID, dryRun, false, 0)
 }
 
 func displayExtractedPattern(ep learn.ExtractedPattern) {
@@ -1248,11 +1971,130 @@ func displayExtractedPattern(ep learn.ExtractedPattern) {
 		ep.Pattern.Category,
 		ep.Pattern.Name,
 		ep.Confidence*100)
-	fmt.Printf("   Source: session %s\n", ep.Source)
+	if ref := ep.Pattern.Source; ref != nil && ref.Line > 0 {
+		fmt.Printf("   Source: session %s, line %d\n", ep.Source, ref.Line)
+	} else {
+		fmt.Printf("   Source: session %s\n", ep.Source)
+	}
 	fmt.Printf("   Domain: %s\n", ep.Pattern.Domain)
 	if len(ep.Evidence) > 0 {
 		fmt.Printf("   Preview: %s\n", truncate(ep.Evidence[0], 80))
 	}
+	printCodeCheck(ep, "   ")
+}
+
+// printCodeCheck reports whether the pattern's code blocks parsed.
+func printCodeCheck(ep learn.ExtractedPattern, indent string) {
+	if ep.Pattern.Validated == nil {
+		return
+	}
+	if *ep.Pattern.Validated {
+		fmt.Printf("%sCode: ✓ parses\n", indent)
+		return
+	}
+	for _, p := range ep.CodeProblems {
+		fmt.Printf("%s⚠ Code does not parse: %s\n", indent, p)
+	}
+}
+
+// recordReview notes an interactive accept or reject of an extracted
+// pattern, which calibrates --accept-all thresholds. Rejected patterns
+// join rejections, so LLM extraction stops proposing them.
+func recordReview(provider string, ep learn.ExtractedPattern, accepted bool, rejections *learn.Rejections) {
+	_ = learn.RecordReview(learn.ReviewDecision{
+		Provider:   provider,
+		Category:   ep.Pattern.Category,
+		Confidence: ep.Confidence,
+		Accepted:   accepted,
+	})
+	if !accepted && rejections != nil {
+		rejections.Add(ep.Pattern)
+		_ = rejections.Save()
+	}
+}
+
+// runExtractStatus reports the auto-accept thresholds --accept-all uses,
+// the extraction watermark, and the rejected patterns extraction steers
+// away from.
+func runExtractStatus() error {
+	cfg, _ := config.Load()
+	thresholds := learn.LoadThresholds(cfg, 0)
+	calibrations := thresholds.All()
+
+	fmt.Println("📊 Auto-accept calibration")
+	fmt.Printf("   Target precision: %.0f%% (learning.target_precision)\n\n", thresholds.Target()*100)
+	if len(calibrations) == 0 {
+		fmt.Printf("No review history yet; --accept-all uses %.0f%%.\n", learn.DefaultMinConfidence*100)
+		fmt.Println("Accept or reject patterns interactively (mur learn extract, mur import review) to calibrate.")
+	} else {
+		fmt.Printf("   %-22s %-12s %9s %9s %10s\n", "PROVIDER", "CATEGORY", "REVIEWED", "ACCEPTED", "THRESHOLD")
+		for _, c := range calibrations {
+			category := c.Category
+			if category == "" {
+				category = "(all)"
+			}
+			threshold := fmt.Sprintf("%.0f%%", c.Threshold*100)
+			if !c.Calibrated {
+				threshold += " (default)"
+			} else if c.Precision == 0 {
+				threshold += " (max)"
+			}
+			fmt.Printf("   %-22s %-12s %9d %8.0f%% %10s\n", c.Provider, category, c.Samples,
+				float64(c.Accepted)/float64(max(c.Samples, 1))*100, threshold)
+		}
+		fmt.Println()
+		fmt.Println("Categories without enough reviews of their own use their provider's threshold.")
+	}
+
+	if state, err := learn.LoadExtractState(); err == nil {
+		fmt.Println()
+		fmt.Println("📍 Extraction watermark")
+		fmt.Printf("   Sessions tracked:  %d (--auto reads only messages added since; --full rescans)\n", state.Len())
+	}
+
+	rejections, err := learn.LoadRejections()
+	if err != nil {
+		return err
+	}
+	rejected, filtered := rejections.Counts()
+	fmt.Println()
+	fmt.Println("🚫 Rejected patterns")
+	if rejections.Len() == 0 {
+		fmt.Println("   None yet. Patterns you reject interactively are listed in the")
+		fmt.Println("   extraction prompt and filtered out when the LLM suggests them again.")
+		return nil
+	}
+	fmt.Printf("   Topics:            %d (rejected %d times)\n", rejections.Len(), rejected)
+	fmt.Printf("   Repeats filtered:  %d\n", filtered)
+	if path, err := learn.RejectionsPath(); err == nil {
+		fmt.Printf("   List:              %s\n", path)
+	}
+	return nil
+}
+
+// loadExtractState returns the extraction watermark, or nil unless use is
+// set. With full the watermark starts over: every session is read from the
+// start, then marked again.
+func loadExtractState(use, full bool) *learn.ExtractState {
+	if !use {
+		return nil
+	}
+	state, err := learn.LoadExtractState()
+	if state == nil {
+		return nil
+	}
+	if err != nil && !full {
+		fmt.Fprintf(os.Stderr, "⚠️  %v; rescanning sessions\n", err)
+	}
+	if full || err != nil {
+		state.Reset()
+	}
+	return state
+}
+
+// recordExtracted writes a porcelain record for an extracted pattern.
+func recordExtracted(out *output.Printer, ep learn.ExtractedPattern, result string) {
+	out.Record("pattern", ep.Pattern.Name, ep.Pattern.Category, fmt.Sprintf("%.2f", ep.Confidence), result)
 }
 
 func confirmSave(name string) bool {
@@ -1265,24 +2107,28 @@ func confirmSave(name string) bool {
 
 func init() {
 	rootCmd.AddCommand(learnCmd)
-	learnCmd.AddCommand(learnListCmd)
 	learnCmd.AddCommand(learnAddCmd)
 	learnCmd.AddCommand(learnGetCmd)
 	learnCmd.AddCommand(learnDeleteCmd)
 	learnCmd.AddCommand(learnSyncCmd)
 	learnCmd.AddCommand(learnExtractCmd)
 	learnCmd.AddCommand(learnInitRepoCmd)
+	learnCmd.AddCommand(learnDeepenCmd)
 	learnCmd.AddCommand(learnPushCmd)
 	learnCmd.AddCommand(learnPullCmd)
 	learnCmd.AddCommand(learnSyncRepoCmd)
 	learnCmd.AddCommand(learnAutoMergeCmd)
 
-	learnListCmd.Flags().StringP("domain", "d", "", "Filter by domain")
-	learnListCmd.Flags().StringP("category", "c", "", "Filter by category")
-
 	learnAddCmd.Flags().Bool("stdin", false, "Read content from stdin")
+	learnAddCmd.Flags().String("scope", "global", "Where to add it: global, or project for the current repository's .mur/patterns/")
+
+	learnGetCmd.Flags().String("render", "", "Print exactly what a tool sees (e.g. claude, cursor, codex)")
+	learnGetCmd.Flags().Bool("inject", false, "With --render, show the injected context instead of the synced file")
+	learnGetCmd.Flags().Bool("comments", false, "Also show the team's comments on a synced team pattern")
+	learnGetCmd.Flags().String("prompt", "", "With --render and no name, the prompt to select patterns for")
 
 	learnDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation")
+	learnDeleteCmd.Flags().Bool("purge", false, "Delete permanently instead of moving to the trash")
 
 	learnSyncCmd.Flags().Bool("cleanup", false, "Remove orphaned synced patterns")
 
@@ -1290,25 +2136,40 @@ func init() {
 	learnExtractCmd.Flags().Bool("auto", false, "Automatically scan recent sessions (implies --quiet --strict --accept-all)")
 	learnExtractCmd.Flags().Bool("dry-run", false, "Show what would be extracted without saving")
 	learnExtractCmd.Flags().Bool("accept-all", false, "Auto-save patterns above confidence threshold")
-	learnExtractCmd.Flags().Bool("quiet", false, "Silent mode (for hooks, minimal output)")
 	learnExtractCmd.Flags().Bool("strict", false, "Enable strict quality filtering (skip Q&A sessions, validate patterns)")
-	learnExtractCmd.Flags().BoolP("verbose", "V", false, "Show detailed output (overrides --quiet in auto mode)")
 	learnExtractCmd.Flags().Bool("no-strict", false, "Disable strict quality filtering in auto mode")
 	learnExtractCmd.Flags().BoolP("interactive", "i", false, "Prompt for each pattern in auto mode (overrides --accept-all)")
-	learnExtractCmd.Flags().Float64("min-confidence", 0.6, "Minimum confidence for auto-accept (default: 0.6)")
+	learnExtractCmd.Flags().Float64("min-confidence", 0, "Minimum confidence for auto-accept (default: calibrated from review history, else 0.6)")
+	learnExtractCmd.Flags().Bool("full", false, "With --auto, rescan whole sessions instead of only messages added since the last run")
+	learnExtractCmd.Flags().Bool("status", false, "Show the auto-accept thresholds calibrated from review history, and rejected-pattern counts")
 	learnExtractCmd.Flags().StringP("llm", "l", "", "LLM provider: ollama, claude, openai, gemini (default from config)")
 	learnExtractCmd.Flags().Lookup("llm").NoOptDefVal = "default" // --llm without value uses config default
 	learnExtractCmd.Flags().String("llm-model", "", "LLM model (default from config)")
 	learnExtractCmd.Flags().Bool("async", false, "Run in background (detached process, parent exits immediately)")
+	learnExtractCmd.Flags().Bool("coalesce", false, "Fold into an extraction already running (as hooks do); see hooks.concurrency")
 	learnExtractCmd.Flags().String("timeout", "", "Timeout duration (e.g. '30s', '2m'). Default: 2m")
 	learnExtractCmd.Flags().String("since", "", "Only process sessions/messages after this time (ISO 8601 or duration like 1h, 30m)")
 	learnExtractCmd.Flags().String("until", "", "Only process sessions/messages before this time (ISO 8601 or duration like 1h, 30m)")
+	learnExtractCmd.Flags().Bool("watch", false, "Watch active sessions and extract incrementally as they grow (Ctrl+C to stop)")
+	learnExtractCmd.Flags().Int("watch-messages", 20, "In watch mode, extract after this many new messages")
+	learnExtractCmd.Flags().Duration("watch-idle", 2*time.Minute, "In watch mode, extract pending messages after this much idle time")
+	learnExtractCmd.Flags().Duration("watch-interval", 10*time.Second, "In watch mode, how often to poll session files")
+
+	learnInitRepoCmd.Flags().Bool("full", false, "Clone the whole history and every domain")
+	learnInitRepoCmd.Flags().StringSlice("domains", nil, "Domains to check out from patterns/<domain>/ (default: learning.domains, else tech_stack)")
+	learnDeepenCmd.Flags().Bool("async", false, "Run in background (detached process, parent exits immediately)")
 
+	learnPullCmd.Flags().StringSlice("branch", nil, "Also import patterns from another machine's branch (repeatable)")
+	learnPullCmd.Flags().String("dedupe", "", "How to handle patterns equal to a local one: skip, link, merge, off")
 	learnPushCmd.Flags().Bool("auto-merge", false, "Check and create PRs for high-confidence patterns after push")
 	learnPushCmd.Flags().Bool("dry-run", false, "Preview auto-merge without creating PRs")
 
 	learnAutoMergeCmd.Flags().Bool("dry-run", false, "Preview without creating PRs")
 	learnAutoMergeCmd.Flags().Float64("threshold", 0, "Override confidence threshold (default: from config or 0.8)")
+	learnAutoMergeCmd.Flags().Bool("status", false, "Show open pattern PRs with approvals and CI state")
+	learnAutoMergeCmd.Flags().Bool("merge", false, "Merge open pattern PRs that satisfy the merge policy")
+	learnAutoMergeCmd.Flags().Bool("require-ci", false, "Require all CI checks to pass (overrides config)")
+	learnAutoMergeCmd.Flags().Int("min-approvals", 0, "Minimum approving reviews (overrides config)")
 }
 
 // parseTimeOrDuration parses a time string as ISO 8601, date, or a Go duration
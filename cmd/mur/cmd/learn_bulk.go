@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var learnBulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Apply changes to many patterns at once",
+	Long: `Select patterns with filter expressions and update, tag, archive,
+delete, or export all of them in one go.
+
+Filters (repeatable, all must match):
+  name=<glob>            domain=go            category=lesson
+  tag=api                status=active        trust=team
+  confidence>=0.8        effectiveness<0.3    usage=0
+  last_used>30d          age<2w
+
+--where takes the same conditions joined with and, or, and not, as in
+'mur learn list --where' (e.g. "tag:docker and last_used>90d").
+
+Every change is snapshotted first; 'mur learn bulk --undo' restores the
+most recent snapshot.
+
+Examples:
+  mur learn bulk --filter domain=go --set status=archived --dry-run
+  mur learn bulk --filter "last_used>90d" --filter usage=0 --archive
+  mur learn bulk --filter tag=legacy --remove-tag legacy --add-tag python
+  mur learn bulk --filter "confidence<0.3" --delete
+  mur learn bulk --where "tag:legacy or usage=0 and age>12w" --archive
+  mur learn bulk --filter domain=swift --export ./swift-patterns
+  mur learn bulk --undo`,
+	RunE: runLearnBulk,
+}
+
+func init() {
+	learnCmd.AddCommand(learnBulkCmd)
+	learnBulkCmd.Flags().StringArray("filter", nil, "Filter expression, e.g. domain=go or confidence>=0.8 (repeatable)")
+	learnBulkCmd.Flags().String("where", "", `Filter expression, e.g. "tag:docker and last_used>90d"`)
+	learnBulkCmd.Flags().StringArray("set", nil, "Set a field: status, trust, confidence, effectiveness, pinned, shared (e.g. status=archived)")
+	learnBulkCmd.Flags().StringSlice("add-tag", nil, "Add confirmed tags")
+	learnBulkCmd.Flags().StringSlice("remove-tag", nil, "Remove tags")
+	learnBulkCmd.Flags().Bool("archive", false, "Archive matched patterns (same as --set status=archived)")
+	learnBulkCmd.Flags().Bool("delete", false, "Delete matched patterns")
+	learnBulkCmd.Flags().String("export", "", "Export matched patterns as YAML to this directory")
+	learnBulkCmd.Flags().Bool("all", false, "Allow running without filters (matches every pattern)")
+	learnBulkCmd.Flags().Bool("dry-run", false, "Preview matches and changes without applying them")
+	learnBulkCmd.Flags().BoolP("force", "f", false, "Skip confirmation")
+	learnBulkCmd.Flags().Bool("undo", false, "Restore patterns from the latest bulk snapshot")
+	learnBulkCmd.Flags().String("snapshot", "", "Snapshot ID to restore with --undo (default: latest)")
+}
+
+func runLearnBulk(cmd *cobra.Command, args []string) error {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	if undo, _ := cmd.Flags().GetBool("undo"); undo {
+		id, _ := cmd.Flags().GetString("snapshot")
+		restored, err := store.UndoBulk(id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Restored %d pattern(s) from snapshot\n", restored)
+		return nil
+	}
+
+	filterExprs, _ := cmd.Flags().GetStringArray("filter")
+	where, _ := cmd.Flags().GetString("where")
+	setExprs, _ := cmd.Flags().GetStringArray("set")
+	addTags, _ := cmd.Flags().GetStringSlice("add-tag")
+	removeTags, _ := cmd.Flags().GetStringSlice("remove-tag")
+	archive, _ := cmd.Flags().GetBool("archive")
+	del, _ := cmd.Flags().GetBool("delete")
+	exportDir, _ := cmd.Flags().GetString("export")
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if len(filterExprs) == 0 && strings.TrimSpace(where) == "" && !all {
+		return fmt.Errorf("no filters given; pass --filter, --where, or --all to select every pattern")
+	}
+
+	opts := pattern.BulkOptions{
+		Set:        make(map[string]string),
+		AddTags:    addTags,
+		RemoveTags: removeTags,
+		Delete:     del,
+		ExportDir:  exportDir,
+		DryRun:     dryRun,
+	}
+	for _, expr := range filterExprs {
+		f, err := pattern.ParseFilter(expr)
+		if err != nil {
+			return err
+		}
+		opts.Filters = append(opts.Filters, f)
+	}
+	if opts.Where, err = pattern.ParseWhere(where); err != nil {
+		return err
+	}
+	for _, expr := range setExprs {
+		field, value, ok := strings.Cut(expr, "=")
+		if !ok {
+			return fmt.Errorf("invalid --set %q (expected field=value)", expr)
+		}
+		opts.Set[strings.TrimSpace(field)] = strings.TrimSpace(value)
+	}
+	if archive {
+		opts.Set["status"] = string(pattern.StatusArchived)
+	}
+	if del && (len(opts.Set) > 0 || len(addTags) > 0 || len(removeTags) > 0) {
+		return fmt.Errorf("--delete cannot be combined with --set, --archive, or tag changes")
+	}
+	if !opts.Mutates() && exportDir == "" {
+		return fmt.Errorf("no action given (use --set, --archive, --add-tag, --remove-tag, --delete, or --export)")
+	}
+
+	// Preview first, then confirm destructive runs
+	preview := opts
+	preview.DryRun = true
+	planned, err := store.Bulk(preview)
+	if err != nil {
+		return err
+	}
+	if planned.Matched == 0 {
+		fmt.Println("No patterns match the filters.")
+		return nil
+	}
+
+	fmt.Printf("Matched %d pattern(s)\n\n", planned.Matched)
+	for _, c := range planned.Changes {
+		fmt.Printf("  %-30s %s\n", c.Pattern, strings.Join(c.Changes, ", "))
+	}
+	if exportDir != "" {
+		fmt.Printf("  → export %d pattern(s) to %s\n", planned.Exported, exportDir)
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println("(dry-run mode, no changes made)")
+		return nil
+	}
+
+	if del && !force {
+		fmt.Printf("Delete %d pattern(s)? [y/N] ", planned.Matched)
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	result, err := store.Bulk(opts)
+	if err != nil {
+		return err
+	}
+
+	if result.Exported > 0 {
+		fmt.Printf("✓ Exported %d pattern(s) to %s\n", result.Exported, exportDir)
+	}
+	if len(result.Changes) > 0 {
+		fmt.Printf("✓ Updated %d pattern(s)\n", len(result.Changes))
+		fmt.Printf("  Undo with: mur learn bulk --undo --snapshot %s\n", result.SnapshotID)
+		fmt.Println("  Run 'mur learn sync' to update AI tools")
+	} else if opts.Mutates() {
+		fmt.Println("✓ Nothing to change")
+	}
+	return nil
+}
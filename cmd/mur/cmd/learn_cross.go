@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+var learnCrossCmd = &cobra.Command{
+	Use:   "cross",
+	Short: "Learn patterns from other AI CLIs' session histories",
+	Long: `Mine the session histories of other AI CLIs (Gemini CLI, Aider, Codex,
+Continue, Auggie, OpenClaw, Claude Code) for patterns, and queue what's
+found for review with 'mur import review'.
+
+Patterns that already exist or are already queued are skipped, so running
+it again only queues what's new. The report shows how much each source
+yielded.
+
+Examples:
+  mur learn cross                         # All sources
+  mur learn cross --source gemini         # Only Gemini CLI
+  mur learn cross --source aider --since 7d
+  mur learn cross --dry-run               # Report without queueing`,
+	RunE: runLearnCross,
+}
+
+func init() {
+	learnCmd.AddCommand(learnCrossCmd)
+	learnCrossCmd.Flags().StringP("source", "s", "all", "Source to learn from: "+strings.Join(learn.SourceKeys(), ", ")+", or all")
+	learnCrossCmd.Flags().String("since", "", "Only sessions from this recent period, e.g. 7d, 2w, 36h")
+	learnCrossCmd.Flags().Bool("dry-run", false, "Report what would be queued without queueing it")
+}
+
+// crossYield is what one source produced.
+type crossYield struct {
+	result  learn.LearnResult
+	queued  int
+	known   int // already a pattern or already queued
+	invalid int
+}
+
+func runLearnCross(cmd *cobra.Command, args []string) error {
+	source, _ := cmd.Flags().GetString("source")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	learner := learn.NewCrossCLILearner(store)
+	if sinceFlag != "" {
+		age, err := pattern.ParseAge(sinceFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		learner.Since(time.Now().Add(-age))
+	}
+
+	var results []learn.LearnResult
+	if source == "" || strings.EqualFold(source, "all") {
+		fmt.Println("🔍 Learning from all CLI session histories...")
+		results, _ = learner.LearnFromAll()
+	} else {
+		result, err := learner.LearnFromSource(source)
+		if result == nil {
+			return fmt.Errorf("%w (use %s, or all)", err, strings.Join(learn.SourceKeys(), ", "))
+		}
+		fmt.Printf("🔍 Learning from %s sessions...\n", result.Source)
+		results = []learn.LearnResult{*result}
+	}
+	fmt.Println()
+
+	sources := make(map[string]string)
+	for _, s := range learn.DefaultCLISources() {
+		sources[s.Name] = s.SessionDir
+	}
+
+	var yields []crossYield
+	queued := 0
+	for _, r := range results {
+		y := crossYield{result: r}
+		queuedNames := make(map[string]bool)
+		for _, s := range r.Suggestions {
+			c := learn.SuggestionCandidate(s, sources[r.Source])
+			name := c.Pattern.Name
+			switch {
+			case name == "":
+				y.invalid++
+			case store.Exists(name) || learn.IsStaged(name) || queuedNames[name]:
+				y.known++
+			case dryRun:
+				queuedNames[name] = true
+				y.queued++
+			default:
+				if err := learn.Stage(c); err != nil {
+					y.invalid++
+					continue
+				}
+				queuedNames[name] = true
+				y.queued++
+			}
+		}
+		queued += y.queued
+		yields = append(yields, y)
+	}
+
+	printCrossYields(yields)
+
+	fmt.Println()
+	switch {
+	case queued == 0:
+		fmt.Println("No new patterns found.")
+	case dryRun:
+		fmt.Printf("🔍 Dry run - %d patterns would be queued for review\n", queued)
+	default:
+		fmt.Printf("✓ Queued %d patterns for review\n", queued)
+		fmt.Println("  Review them with 'mur import review'")
+	}
+	return nil
+}
+
+func printCrossYields(yields []crossYield) {
+	fmt.Printf("  %-14s %6s %8s %6s %7s\n", "Source", "Files", "Entries", "Found", "Queued")
+	for _, y := range yields {
+		r := y.result
+		if errors.Is(r.Error, learn.ErrNoHistory) {
+			fmt.Printf("  %-14s no history\n", r.Source)
+			continue
+		}
+		if r.Error != nil {
+			fmt.Printf("  %-14s ⚠ %v\n", r.Source, r.Error)
+			continue
+		}
+		fmt.Printf("  %-14s %6d %8d %6d %7d", r.Source, r.FilesRead, r.Entries, len(r.Suggestions), y.queued)
+		var notes []string
+		if y.known > 0 {
+			notes = append(notes, fmt.Sprintf("%d known", y.known))
+		}
+		if y.invalid > 0 {
+			notes = append(notes, fmt.Sprintf("%d unusable", y.invalid))
+		}
+		if len(notes) > 0 {
+			fmt.Printf("  (%s)", strings.Join(notes, ", "))
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/consolidate"
+	"github.com/mur-run/mur-core/internal/core/analytics"
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/output"
+)
+
+var learnDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find near-duplicate patterns and archive the extras",
+	Long: `Group active patterns whose embeddings are at least
+consolidation.merge_threshold similar (default 0.85) and resolve each
+group: keep one and archive the rest, or have the LLM merge them into the
+one kept. Archived patterns record what they duplicate, and the kept
+pattern lists them as related.
+
+For each group, pick the pattern to keep by number (Enter keeps the
+healthiest), m to merge with the LLM, s to skip, or q to stop. With --auto
+every group is resolved by --strategy without asking:
+
+  keep-best   keep the healthiest pattern (usage, effectiveness, freshness)
+  merge-llm   keep the healthiest, with content merged from the whole group
+
+Patterns need embeddings; run 'mur index rebuild' first.
+
+Examples:
+  mur learn dedupe
+  mur learn dedupe --dry-run --threshold 0.9
+  mur learn dedupe --auto
+  mur learn dedupe --auto --strategy merge-llm --llm claude`,
+	Args: cobra.NoArgs,
+	RunE: runLearnDedupe,
+}
+
+func init() {
+	learnCmd.AddCommand(learnDedupeCmd)
+	learnDedupeCmd.Flags().Bool("auto", false, "Resolve every group by --strategy without asking")
+	learnDedupeCmd.Flags().String("strategy", "keep-best", "With --auto: keep-best or merge-llm")
+	learnDedupeCmd.Flags().Float64("threshold", 0, "Similarity to count as duplicates (default: consolidation.merge_threshold)")
+	learnDedupeCmd.Flags().Bool("dry-run", false, "Only list the groups")
+	learnDedupeCmd.Flags().Bool("no-sync", false, "Don't sync AI tools afterwards")
+	learnDedupeCmd.Flags().String("llm", "", "LLM provider for merging: ollama, claude, openai, gemini (default from config)")
+	learnDedupeCmd.Flags().String("llm-model", "", "LLM model (default from config)")
+}
+
+// dedupeGroup is a group of near-duplicates as listed by --json.
+type dedupeGroup struct {
+	Similarity float64        `json:"similarity"`
+	Keep       string         `json:"keep"`
+	Patterns   []dedupeMember `json:"patterns"`
+}
+
+type dedupeMember struct {
+	Name   string  `json:"name"`
+	Health float64 `json:"health"`
+	Uses   int     `json:"uses"`
+}
+
+func runLearnDedupe(cmd *cobra.Command, args []string) error {
+	auto, _ := cmd.Flags().GetBool("auto")
+	strategyFlag, _ := cmd.Flags().GetString("strategy")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noSync, _ := cmd.Flags().GetBool("no-sync")
+	provider, _ := cmd.Flags().GetString("llm")
+	model, _ := cmd.Flags().GetString("llm-model")
+	out := newPrinter(cmd)
+	cmd.SilenceUsage = true
+
+	var strategy consolidate.MergeStrategy
+	switch strategyFlag {
+	case "keep-best":
+		strategy = consolidate.StrategyKeepBest
+	case "merge-llm", "llm-merge":
+		strategy = consolidate.StrategyLLMMerge
+	default:
+		return fmt.Errorf("unknown strategy %q (use keep-best or merge-llm)", strategyFlag)
+	}
+	if auto && dryRun {
+		return fmt.Errorf("--auto and --dry-run cannot be used together")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cmd.Flags().Changed("threshold") {
+		threshold = cfg.Consolidation.MergeThreshold
+	}
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %g", threshold)
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	if !embed.HasIndex() {
+		return fmt.Errorf("no embeddings yet; run 'mur index rebuild' first")
+	}
+	idx, err := embed.NewPatternIndexer(cfg)
+	if err != nil {
+		return err
+	}
+
+	active, err := store.GetActive()
+	if err != nil {
+		return fmt.Errorf("cannot load patterns: %w", err)
+	}
+	patterns := make([]*pattern.Pattern, len(active))
+	vectors := make([]embed.Vector, len(active))
+	unindexed := 0
+	for i := range active {
+		patterns[i] = &active[i]
+		if v, ok := idx.Vector(active[i]); ok {
+			vectors[i] = v
+		} else {
+			unindexed++
+		}
+	}
+
+	scores := dedupeHealthScores(cfg, store, patterns)
+	detector := consolidate.NewDuplicateDetector(nil, threshold, consolidate.StrategyKeepBest)
+	detector.WithHealthScores(scores)
+	groups := detector.DetectVectors(patterns, vectors)
+
+	health := make(map[string]float64, len(scores))
+	for _, hs := range scores {
+		health[hs.PatternID] = hs.Overall
+	}
+	if out.JSON() {
+		list := make([]dedupeGroup, 0, len(groups))
+		for _, g := range groups {
+			keep, dups := splitKeep(g)
+			dg := dedupeGroup{Similarity: g.Similarity, Keep: keep.Name}
+			for _, p := range append([]*pattern.Pattern{keep}, dups...) {
+				dg.Patterns = append(dg.Patterns, dedupeMember{Name: p.Name, Health: health[p.ID], Uses: p.Learning.UsageCount})
+			}
+			list = append(list, dg)
+		}
+		return out.WriteJSON(list)
+	}
+
+	if unindexed > 0 {
+		out.Printf("%s %d pattern(s) have no embedding and were skipped (run 'mur index rebuild')\n", out.Yellow("⚠"), unindexed)
+	}
+	if len(groups) == 0 {
+		out.Printf("%s No near-duplicates among %d patterns (similarity ≥ %.2f)\n", out.Green("✓"), len(patterns)-unindexed, threshold)
+		return nil
+	}
+	out.Printf("Found %d group(s) of near-duplicates (similarity ≥ %.2f)\n", len(groups), threshold)
+
+	var llmOpts *learn.LLMExtractOptions
+	mergeLLM := func() (*learn.LLMExtractOptions, error) {
+		if llmOpts == nil {
+			opts, _, err := resolveLLMOptions(cfg, provider, model)
+			if err != nil {
+				return nil, err
+			}
+			if missing := llmUnavailable(opts); missing != "" {
+				return nil, fmt.Errorf("cannot merge: %s", missing)
+			}
+			llmOpts = &opts
+		}
+		return llmOpts, nil
+	}
+	if auto && strategy == consolidate.StrategyLLMMerge {
+		if _, err := mergeLLM(); err != nil {
+			return err
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	archived, merged := 0, 0
+	for i, g := range groups {
+		keep, dups := splitKeep(g)
+		members := append([]*pattern.Pattern{keep}, dups...)
+		out.Println()
+		out.Printf("[%d/%d] %.0f%% similar\n", i+1, len(groups), g.Similarity*100)
+		for n, p := range members {
+			out.Record("duplicate", strconv.Itoa(i+1), p.Name, fmt.Sprintf("%.2f", g.Similarity), strconv.FormatBool(n == 0))
+			best := ""
+			if n == 0 {
+				best = out.Green("  ← healthiest")
+			}
+			out.Printf("  %d) %-36s %s%s\n", n+1, p.Name, out.Dim(fmt.Sprintf("health %.2f, used %d×", health[p.ID], p.Learning.UsageCount)), best)
+			if summary := patternSummaryLine(*p); summary != "" {
+				out.Printf("     %s\n", out.Dim(summary))
+			}
+		}
+		if dryRun {
+			continue
+		}
+
+		merge := auto && strategy == consolidate.StrategyLLMMerge
+		if !auto {
+			out.Printf("Keep [1-%d, Enter=1], m=merge with LLM, s=skip, q=quit: ", len(members))
+			answer, err := reader.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if err != nil && answer == "" {
+				answer = "q" // stdin closed: don't archive anything unasked
+			}
+			switch n, convErr := strconv.Atoi(answer); {
+			case answer == "" || answer == "1":
+			case answer == "m":
+				merge = true
+			case answer == "s":
+				out.Println("  Skipped")
+				continue
+			case answer == "q":
+				return finishDedupe(out, cfg, archived, merged, noSync)
+			case convErr == nil && n >= 2 && n <= len(members):
+				keep = members[n-1]
+				dups = append(append([]*pattern.Pattern{}, members[:n-1]...), members[n:]...)
+			default:
+				out.Println("  Skipped: no such choice")
+				continue
+			}
+		}
+
+		if merge {
+			opts, err := mergeLLM()
+			if err != nil {
+				return err
+			}
+			content, err := learn.MergeWithLLM(keep, dups, *opts)
+			if err != nil {
+				out.Printf("  %s %v\n", out.Red("✗"), err)
+				continue
+			}
+			keep.Content = content
+		}
+		names, err := consolidate.ArchiveDuplicates(store, keep, dups, time.Now().UTC())
+		archived += len(names)
+		if err != nil {
+			out.Printf("  %s %v\n", out.Red("✗"), err)
+			continue
+		}
+		if merge {
+			merged++
+			if err := idx.IndexPattern(*keep); err == nil {
+				_ = idx.SaveCache()
+			}
+			out.Printf("  %s Merged into %s; archived %s\n", out.Green("✓"), keep.Name, strings.Join(names, ", "))
+		} else {
+			out.Printf("  %s Kept %s; archived %s\n", out.Green("✓"), keep.Name, strings.Join(names, ", "))
+		}
+	}
+
+	if dryRun {
+		out.Println()
+		out.Println("(dry-run mode, no changes made)")
+		return nil
+	}
+	return finishDedupe(out, cfg, archived, merged, noSync)
+}
+
+// splitKeep returns the pattern a merge proposal keeps and the others.
+func splitKeep(g consolidate.MergeProposal) (*pattern.Pattern, []*pattern.Pattern) {
+	keep := g.Patterns[0]
+	for _, p := range g.Patterns {
+		if p.ID == g.KeepID {
+			keep = p
+			break
+		}
+	}
+	var dups []*pattern.Pattern
+	for _, p := range g.Patterns {
+		if p != keep {
+			dups = append(dups, p)
+		}
+	}
+	return keep, dups
+}
+
+// dedupeHealthScores scores patterns the way 'mur consolidate' does, to
+// pick which of a group to keep.
+func dedupeHealthScores(cfg *config.Config, store *pattern.Store, patterns []*pattern.Pattern) []consolidate.HealthScore {
+	var effectiveness []inject.EffectivenessStats
+	var usage []analytics.PatternStats
+	if home, err := os.UserHomeDir(); err == nil {
+		murDir := config.DataDir(home)
+		effectiveness, _ = inject.NewTracker(store, filepath.Join(murDir, "tracking")).GetStats()
+		usage, _ = analytics.NewTracker(murDir).GetPatternStats()
+	}
+	return consolidate.NewHealthScorer(cfg.Consolidation, nil, effectiveness, usage).ScoreAll(patterns)
+}
+
+func finishDedupe(out *output.Printer, cfg *config.Config, archived, merged int, noSync bool) error {
+	out.Println()
+	if archived == 0 {
+		out.Println("No patterns archived")
+		return nil
+	}
+	out.Printf("%s Archived %d duplicate(s)", out.Green("✓"), archived)
+	if merged > 0 {
+		out.Printf(", merged %d group(s)", merged)
+	}
+	out.Println()
+	out.Println("  Restore one with: mur learn bulk --filter name=<name> --set status=active")
+	if !noSync {
+		resyncAITools(cfg)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+var learnEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Edit a pattern in your editor, with validation",
+	Long: `Open a pattern in $EDITOR (or $VISUAL, vim, nano) and check it before
+saving: the YAML must parse, the name can't change (use 'mur learn rename'),
+content can't be empty, domain and category must be known values and
+confidence must be between 0 and 1. If the edit doesn't pass, you can
+re-open the editor or discard it.
+
+The version it replaces is kept (see 'mur learn history'). When the content
+changed, the pattern is re-embedded for semantic search, and AI tools are
+re-synced.
+
+Examples:
+  mur learn edit go-error-handling
+  EDITOR="code --wait" mur learn edit go-error-handling
+  mur learn edit go-error-handling --no-sync`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLearnEdit,
+}
+
+func init() {
+	learnCmd.AddCommand(learnEditCmd)
+	learnEditCmd.Flags().Bool("no-sync", false, "Don't sync AI tools after editing")
+}
+
+func runLearnEdit(cmd *cobra.Command, args []string) error {
+	noSync, _ := cmd.Flags().GetBool("no-sync")
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	name := historyName(store, args[0])
+
+	before, err := learn.ReadPatternFile(name)
+	if err != nil {
+		return fmt.Errorf("%w\nUse 'mur learn list' to see available patterns", err)
+	}
+	editor, err := findEditor()
+	if err != nil {
+		return err
+	}
+
+	// Edit a copy, so the pattern is only replaced once the edit is valid
+	f, err := os.CreateTemp("", "mur-edit-"+name+"-*.yaml")
+	if err != nil {
+		return fmt.Errorf("cannot create temp file: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, err = f.Write(before)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("cannot write temp file: %w", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var after []byte
+	for {
+		// The editor may be a command with arguments, like "code --wait"
+		fields := strings.Fields(editor)
+		editorCmd := exec.Command(fields[0], append(fields[1:], tmp)...)
+		editorCmd.Stdin = os.Stdin
+		editorCmd.Stdout = os.Stdout
+		editorCmd.Stderr = os.Stderr
+		if err := editorCmd.Run(); err != nil {
+			return fmt.Errorf("editor exited with error: %w", err)
+		}
+
+		after, err = os.ReadFile(tmp)
+		if err != nil {
+			return fmt.Errorf("cannot read edited pattern: %w", err)
+		}
+		if bytes.Equal(before, after) {
+			fmt.Printf("No changes to '%s'.\n", name)
+			return nil
+		}
+
+		problems := learn.EditProblems(name, before, after)
+		if len(problems) == 0 {
+			break
+		}
+		fmt.Println()
+		fmt.Println("❌ The edited pattern isn't valid:")
+		for _, p := range problems {
+			fmt.Printf("   • %s\n", p)
+		}
+		fmt.Print("   Re-open the editor? [Y/n] ")
+		input, _ := reader.ReadString('\n')
+		if input = strings.TrimSpace(strings.ToLower(input)); input == "n" || input == "no" {
+			fmt.Printf("Edit discarded; '%s' is unchanged.\n", name)
+			return nil
+		}
+	}
+
+	contentChanged, err := learn.SaveEdited(name, before, after)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("✅ Saved %s\n", name)
+	fmt.Printf("   The previous version is kept; see 'mur learn history %s'\n", name)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if contentChanged && embed.HasIndex() {
+		if err := reindexPattern(cfg, store, name); err != nil {
+			fmt.Printf("   ⚠ Cannot update search index: %v (run 'mur index rebuild')\n", err)
+		} else {
+			fmt.Println("   Re-embedded for semantic search")
+		}
+	}
+	if !noSync {
+		resyncAITools(cfg)
+	}
+	return nil
+}
+
+// reindexPattern embeds a pattern's new content and saves the index.
+func reindexPattern(cfg *config.Config, store *pattern.Store, name string) error {
+	p, err := store.Get(name)
+	if err != nil {
+		return err
+	}
+	idx, err := embed.NewPatternIndexer(cfg)
+	if err != nil {
+		return err
+	}
+	if err := idx.IndexPattern(*p); err != nil {
+		return err
+	}
+	return idx.SaveCache()
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var learnHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "List earlier versions of a pattern",
+	Long: `List the earlier versions of a pattern. Whenever a pattern's content or
+description is replaced, by re-extraction, 'mur edit', the dashboard or a
+sync, the old version is kept in ~/.mur/patterns/.history/<name>/ (the
+latest 20). Restore one with 'mur learn rollback'.
+
+Examples:
+  mur learn history go-error-handling
+  mur learn history go-error-handling --show 3   # Print revision 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLearnHistory,
+}
+
+var learnRollbackCmd = &cobra.Command{
+	Use:   "rollback <name> --to <rev>",
+	Short: "Restore an earlier version of a pattern",
+	Long: `Restore an earlier version of a pattern, as listed by 'mur learn
+history'. The version it replaces is kept as a new revision, so a rollback
+can be undone the same way.
+
+Examples:
+  mur learn rollback go-error-handling --to 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLearnRollback,
+}
+
+func init() {
+	learnCmd.AddCommand(learnHistoryCmd)
+	learnCmd.AddCommand(learnRollbackCmd)
+
+	learnHistoryCmd.Flags().Int("show", 0, "Print the YAML of a revision")
+	learnRollbackCmd.Flags().Int("to", 0, "Revision to restore")
+	_ = learnRollbackCmd.MarkFlagRequired("to")
+}
+
+// historyName resolves a pattern ID to its name; anything else, including
+// the name of a deleted pattern, is taken as the name.
+func historyName(store *pattern.Store, ref string) string {
+	if p, err := store.Resolve(ref); err == nil {
+		return p.Name
+	}
+	return ref
+}
+
+func runLearnHistory(cmd *cobra.Command, args []string) error {
+	show, _ := cmd.Flags().GetInt("show")
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	name := historyName(store, args[0])
+
+	if show > 0 {
+		data, err := store.RevisionData(name, show)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	revs, err := store.History(name)
+	if err != nil {
+		return err
+	}
+	if len(revs) == 0 {
+		fmt.Printf("No earlier versions of '%s'.\n", name)
+		return nil
+	}
+
+	fmt.Printf("📜 History of %s\n\n", name)
+	if p, err := store.Get(name); err == nil {
+		updated := "                "
+		if !p.Lifecycle.Updated.IsZero() {
+			updated = p.Lifecycle.Updated.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("   current  %s  %s\n", updated, truncate(p.Description, 60))
+	}
+	for _, r := range revs {
+		fmt.Printf("   rev %-4d %s  %s  (replaced by %s, %d bytes)\n",
+			r.Rev, r.SavedAt.Local().Format("2006-01-02 15:04"), truncate(r.Description, 60), r.Reason, r.Size)
+	}
+	fmt.Println()
+	fmt.Printf("  Show one: mur learn history %s --show <rev>\n", name)
+	fmt.Printf("  Restore:  mur learn rollback %s --to <rev>\n", name)
+	return nil
+}
+
+func runLearnRollback(cmd *cobra.Command, args []string) error {
+	rev, _ := cmd.Flags().GetInt("to")
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	name := historyName(store, args[0])
+
+	if _, err := store.Rollback(name, rev); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Rolled back '%s' to revision %d\n", name, rev)
+	if revs, err := store.History(name); err == nil && len(revs) > 0 {
+		fmt.Printf("  The replaced version is revision %d\n", revs[0].Rev)
+	}
+	fmt.Println("  Run 'mur learn sync' to update AI tools")
+	return nil
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+var learnListCmd = &cobra.Command{
+	Use:   "list [asc|desc]",
+	Short: "List all patterns",
+	Long: `List patterns, optionally filtered, sorted, and limited.
+
+--where takes conditions joined with and, or, and not (parentheses group):
+  name=<glob>            domain=go            category=lesson
+  tag:docker             status=active        trust=team
+  confidence>0.7         effectiveness<0.3    usage=0
+  last_used<30d          age>2w
+
+last_used<30d means used within the last 30 days. The same expressions
+work with 'mur learn bulk --where' and the dashboard's /api/patterns?where=.
+
+Sort fields: ` + strings.Join(pattern.SortFields, ", ") + `
+
+--tree groups patterns by domain, then category, then tag (or the levels
+given with --group-by), with a pattern count, mean effectiveness and total
+uses per group. A group at the last level lists at most --collapse patterns
+in --sort order; 0 lists them all.
+
+With --porcelain, prints one line per pattern:
+  pattern<TAB>name<TAB>domain<TAB>status<TAB>effectiveness<TAB>usage<TAB>last_used
+or, with --tree, one line per group:
+  group<TAB>path<TAB>patterns<TAB>effectiveness<TAB>uses
+With --json, prints an array of patterns, or with --tree an array of
+groups with nested groups and patterns.
+
+Examples:
+  mur learn list --where "confidence>0.7 and tag:docker and last_used<30d"
+  mur learn list --sort effectiveness desc --limit 20
+  mur learn list --where "tag:go or tag:rust" --sort -usage
+  mur learn list --tree
+  mur learn list --tree --group-by category,tag --collapse 0`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLearnList,
+}
+
+func init() {
+	learnCmd.AddCommand(learnListCmd)
+	learnListCmd.Flags().StringP("domain", "d", "", "Filter by domain")
+	learnListCmd.Flags().StringP("category", "c", "", "Filter by category")
+	learnListCmd.Flags().StringP("where", "w", "", `Filter expression, e.g. "confidence>0.7 and tag:docker"`)
+	learnListCmd.Flags().String("sort", "", "Sort by field, e.g. 'effectiveness desc' or -usage")
+	learnListCmd.Flags().IntP("limit", "n", 0, "Show at most this many patterns")
+	learnListCmd.Flags().Bool("tree", false, "Group patterns by domain, category and tag")
+	learnListCmd.Flags().String("group-by", "", "Group levels for --tree, e.g. category,tag (default domain,category,tag)")
+	learnListCmd.Flags().Int("collapse", 10, "With --tree, list at most this many patterns per group (0 = all)")
+}
+
+func runLearnList(cmd *cobra.Command, args []string) error {
+	domain, _ := cmd.Flags().GetString("domain")
+	category, _ := cmd.Flags().GetString("category")
+	where, _ := cmd.Flags().GetString("where")
+	sortSpec, _ := cmd.Flags().GetString("sort")
+	limit, _ := cmd.Flags().GetInt("limit")
+	tree, _ := cmd.Flags().GetBool("tree")
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	collapse, _ := cmd.Flags().GetInt("collapse")
+
+	levels, err := pattern.ParseGroupLevels(groupBy)
+	if err != nil {
+		return err
+	}
+	if groupBy != "" {
+		tree = true
+	}
+
+	// Allow `--sort effectiveness desc` as well as `--sort "effectiveness desc"`
+	if len(args) == 1 {
+		dir := strings.ToLower(args[0])
+		if sortSpec == "" || (dir != "asc" && dir != "desc") {
+			return fmt.Errorf("unexpected argument %q (only asc or desc may follow --sort)", args[0])
+		}
+		sortSpec += " " + dir
+	}
+
+	q, err := pattern.ParseQuery(where, sortSpec, limit)
+	if err != nil {
+		return err
+	}
+	if domain != "" {
+		q.Where = pattern.And(q.Where, pattern.Filter{Field: "domain", Op: "=", Value: domain})
+	}
+	if category != "" {
+		q.Where = pattern.And(q.Where, pattern.Filter{Field: "category", Op: "=", Value: category})
+	}
+	if q.Sort == "" {
+		q.Sort = "name"
+	}
+
+	patterns, err := listAllPatterns()
+	if err != nil {
+		return fmt.Errorf("failed to list patterns: %w", err)
+	}
+	patterns, err = q.Apply(patterns, time.Now())
+	if err != nil {
+		return err
+	}
+
+	out := newPrinter(cmd)
+	if out.JSON() {
+		if tree {
+			return out.WriteJSON(newPatternGroupsJSON(pattern.GroupPatterns(patterns, levels)))
+		}
+		result := make([]patternSummaryJSON, 0, len(patterns))
+		for i := range patterns {
+			result = append(result, newPatternSummaryJSON(&patterns[i]))
+		}
+		return out.WriteJSON(result)
+	}
+	if tree {
+		printPatternTree(out, pattern.GroupPatterns(patterns, levels), levels, collapse)
+		return nil
+	}
+	if out.Porcelain() {
+		for _, p := range patterns {
+			lastUsed := ""
+			if p.Learning.LastUsed != nil {
+				lastUsed = p.Learning.LastUsed.UTC().Format(time.RFC3339)
+			}
+			status := string(p.Lifecycle.Status)
+			if status == "" {
+				status = string(pattern.StatusActive)
+			}
+			out.Record("pattern", p.Name, p.GetPrimaryDomain(), status,
+				fmt.Sprintf("%.2f", p.Learning.Effectiveness), fmt.Sprint(p.Learning.UsageCount), lastUsed)
+		}
+		return nil
+	}
+
+	out.Println(out.Bold("Learned Patterns"))
+	out.Println("================")
+	out.Println("")
+
+	for _, p := range patterns {
+		out.Printf("  %-20s  [%s/%s]  %.0f%%", p.Name, p.GetPrimaryDomain(), p.GetCategory(), p.Learning.OriginalConfidence*100)
+		if q.Sort == "effectiveness" {
+			out.Printf("  %.0f%% effective", p.Learning.Effectiveness*100)
+		}
+		if q.Sort == "usage" || q.Sort == "last_used" {
+			out.Printf("  used %d×", p.Learning.UsageCount)
+			if p.Learning.LastUsed != nil {
+				out.Printf(", last %s", p.Learning.LastUsed.In(stats.DisplayLocation()).Format("2006-01-02"))
+			}
+		}
+		out.Println()
+		if p.Description != "" {
+			out.Printf("    %s\n", out.Dim(truncate(p.Description, 60)))
+		}
+	}
+
+	out.Println("")
+	out.Printf("Total: %d patterns\n", len(patterns))
+
+	return nil
+}
+
+// patternSummaryJSON is a pattern in 'mur learn list --json'.
+type patternSummaryJSON struct {
+	ID            string     `json:"id,omitempty"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Domain        string     `json:"domain"`
+	Category      string     `json:"category"`
+	Tags          []string   `json:"tags"`
+	Status        string     `json:"status"`
+	Effectiveness float64    `json:"effectiveness"`
+	UsageCount    int        `json:"usage_count"`
+	LastUsed      *time.Time `json:"last_used"`
+}
+
+func newPatternSummaryJSON(p *pattern.Pattern) patternSummaryJSON {
+	status := string(p.Lifecycle.Status)
+	if status == "" {
+		status = string(pattern.StatusActive)
+	}
+	tags := append([]string{}, p.Tags.Confirmed...)
+	for _, ts := range p.Tags.Inferred {
+		tags = append(tags, ts.Tag)
+	}
+	return patternSummaryJSON{
+		ID:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		Domain:        p.GetPrimaryDomain(),
+		Category:      p.GetCategory(),
+		Tags:          tags,
+		Status:        status,
+		Effectiveness: p.Learning.Effectiveness,
+		UsageCount:    p.Learning.UsageCount,
+		LastUsed:      p.Learning.LastUsed,
+	}
+}
+
+// patternGroupJSON is a group in 'mur learn list --tree --json'.
+type patternGroupJSON struct {
+	Level         string               `json:"level"`
+	Name          string               `json:"name"`
+	Count         int                  `json:"count"`
+	Effectiveness float64              `json:"effectiveness"`
+	Uses          int                  `json:"uses"`
+	Groups        []patternGroupJSON   `json:"groups,omitempty"`
+	Patterns      []patternSummaryJSON `json:"patterns,omitempty"`
+}
+
+func newPatternGroupsJSON(groups []pattern.Group) []patternGroupJSON {
+	result := make([]patternGroupJSON, 0, len(groups))
+	for _, g := range groups {
+		j := patternGroupJSON{
+			Level:         g.Level,
+			Name:          g.Name,
+			Count:         g.Count,
+			Effectiveness: g.Effectiveness,
+			Uses:          g.Uses,
+		}
+		if len(g.Groups) > 0 {
+			j.Groups = newPatternGroupsJSON(g.Groups)
+		}
+		for i := range g.Patterns {
+			j.Patterns = append(j.Patterns, newPatternSummaryJSON(&g.Patterns[i]))
+		}
+		result = append(result, j)
+	}
+	return result
+}
+
+// printPatternTree prints grouped patterns as a tree, or one porcelain
+// record per group.
+func printPatternTree(out *output.Printer, groups []pattern.Group, levels []string, collapse int) {
+	if out.Porcelain() {
+		var walk func(groups []pattern.Group, path string)
+		walk = func(groups []pattern.Group, path string) {
+			for _, g := range groups {
+				out.Record("group", path+g.Name, fmt.Sprint(g.Count), fmt.Sprintf("%.2f", g.Effectiveness), fmt.Sprint(g.Uses))
+				walk(g.Groups, path+g.Name+"/")
+			}
+		}
+		walk(groups, "")
+		return
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += g.Count
+	}
+	out.Println(out.Bold("Learned Patterns by " + strings.Join(levels, " › ")))
+	out.Println("")
+
+	var walk func(groups []pattern.Group, indent string)
+	walk = func(groups []pattern.Group, indent string) {
+		for i, g := range groups {
+			branch, next := "├── ", "│   "
+			if i == len(groups)-1 {
+				branch, next = "└── ", "    "
+			}
+			out.Printf("%s%s  %s\n", indent+branch, out.Bold(g.Name), groupSummary(out, g))
+			printGroupPatterns(out, g, indent+next, collapse)
+			walk(g.Groups, indent+next)
+		}
+	}
+	for _, g := range groups {
+		out.Printf("%s  %s\n", out.Bold(g.Name), groupSummary(out, g))
+		printGroupPatterns(out, g, "", collapse)
+		walk(g.Groups, "")
+		out.Println("")
+	}
+	out.Printf("Total: %d patterns in %d groups\n", total, len(groups))
+}
+
+func groupSummary(out *output.Printer, g pattern.Group) string {
+	noun := "patterns"
+	if g.Count == 1 {
+		noun = "pattern"
+	}
+	return out.Dim(fmt.Sprintf("%d %s · %.0f%% effective · %d uses", g.Count, noun, g.Effectiveness*100, g.Uses))
+}
+
+// printGroupPatterns lists the patterns of a last-level group, at most
+// collapse of them.
+func printGroupPatterns(out *output.Printer, g pattern.Group, indent string, collapse int) {
+	shown := g.Patterns
+	if collapse > 0 && len(shown) > collapse {
+		shown = shown[:collapse]
+	}
+	for i, p := range shown {
+		leaf := "├── "
+		if i == len(shown)-1 && len(shown) == len(g.Patterns) {
+			leaf = "└── "
+		}
+		out.Printf("%s%-30s  %.0f%%\n", indent+leaf, p.Name, p.Learning.Effectiveness*100)
+	}
+	if more := len(g.Patterns) - len(shown); more > 0 {
+		out.Printf("%s└── %s\n", indent, out.Dim(fmt.Sprintf("… %d more", more)))
+	}
+}
+
+// listAllPatterns returns every pattern in the v2 schema. Files still in
+// the v1 schema (as written by 'mur learn add') are converted in memory.
+func listAllPatterns() ([]pattern.Pattern, error) {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	v1, _ := learn.List()
+	legacy := make(map[string]*pattern.Pattern, len(v1))
+	for _, lp := range v1 {
+		legacy[lp.Name] = fromLearnPattern(lp)
+	}
+
+	for i := range patterns {
+		// A v1 file without tags also decodes as a (mostly empty) v2 pattern
+		if p, ok := legacy[patterns[i].Name]; ok && patterns[i].SchemaVersion < pattern.SchemaVersion {
+			patterns[i] = *p
+		}
+		delete(legacy, patterns[i].Name)
+	}
+	for _, lp := range v1 {
+		if p, ok := legacy[lp.Name]; ok {
+			patterns = append(patterns, *p)
+		}
+	}
+	return patterns, nil
+}
+
+// fromLearnPattern converts a pattern read in the v1 schema to the v2 one,
+// which is where list, get and the query language take its domain,
+// category and confidence from.
+func fromLearnPattern(lp learn.Pattern) *pattern.Pattern {
+	p := pattern.FromV1(pattern.V1Pattern{
+		Name:        lp.Name,
+		Description: lp.Description,
+		Content:     lp.Content,
+		Domain:      lp.Domain,
+		Category:    lp.Category,
+		Confidence:  lp.Confidence,
+		TeamShared:  lp.TeamShared,
+		Validated:   lp.Validated,
+		Source:      lp.Source,
+		CreatedAt:   lp.CreatedAt,
+		UpdatedAt:   lp.UpdatedAt,
+	})
+	p.Tags.Confirmed = lp.Tags
+	return p
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var learnPinCmd = &cobra.Command{
+	Use:   "pin [name...]",
+	Short: "Always inject a pattern, regardless of relevance",
+	Long: `Pin must-follow patterns (e.g. a security review checklist) so that
+'mur context' and 'mur search --inject' always include them.
+
+Pinned patterns don't count towards the relevance limit. At most
+context.pinned_budget of them (default 3) are injected at once; when
+more are pinned, the most relevant ones win.
+
+Examples:
+  mur learn pin security-review-checklist
+  mur learn pin --list
+  mur learn unpin security-review-checklist`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, _ := cmd.Flags().GetBool("list")
+		if list || len(args) == 0 {
+			return listPinnedPatterns()
+		}
+		return setPinned(args, true)
+	},
+}
+
+var learnUnpinCmd = &cobra.Command{
+	Use:   "unpin <name...>",
+	Short: "Stop always injecting a pattern",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPinned(args, false)
+	},
+}
+
+func init() {
+	learnCmd.AddCommand(learnPinCmd)
+	learnCmd.AddCommand(learnUnpinCmd)
+	learnPinCmd.Flags().Bool("list", false, "List pinned patterns")
+}
+
+func setPinned(names []string, pinned bool) error {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := store.SetPinned(name, pinned); err != nil {
+			return err
+		}
+		if pinned {
+			fmt.Printf("📌 Pinned '%s'\n", name)
+		} else {
+			fmt.Printf("✓ Unpinned '%s'\n", name)
+		}
+	}
+
+	if pinned {
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		all, _ := store.GetPinned()
+		if budget := inject.PinnedBudget(cfg); len(all) > budget {
+			fmt.Printf("⚠ %d patterns pinned but context.pinned_budget is %d; only the most relevant are injected\n", len(all), budget)
+		}
+	}
+	return nil
+}
+
+func listPinnedPatterns() error {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	pinned, err := store.GetPinned()
+	if err != nil {
+		return err
+	}
+
+	if len(pinned) == 0 {
+		fmt.Println("No pinned patterns.")
+		fmt.Println("\nPin one with: mur learn pin <name>")
+		return nil
+	}
+
+	fmt.Println("Pinned Patterns")
+	fmt.Println("===============")
+	fmt.Println("")
+	for _, p := range pinned {
+		fmt.Printf("  📌 %s\n", p.Name)
+		if p.Description != "" {
+			fmt.Printf("    %s\n", truncate(p.Description, 60))
+		}
+	}
+	fmt.Println("")
+	fmt.Printf("Total: %d pinned\n", len(pinned))
+	return nil
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/sync"
+)
+
+var learnRenameCmd = &cobra.Command{
+	Use:   "rename <name> <new-name>",
+	Short: "Rename a pattern and update everything that refers to it",
+	Long: `Rename a pattern. Relations in other patterns, context profile pins,
+the search index and synced AI tools are updated with it.
+
+Examples:
+  mur learn rename debugging-solution-3f2a launchd-open-file-limit`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		store, err := pattern.DefaultStore()
+		if err != nil {
+			return err
+		}
+		if err := renamePattern(store, cfg, args[0], args[1]); err != nil {
+			return err
+		}
+		if !noSync {
+			resyncAITools(cfg)
+		}
+		return nil
+	},
+}
+
+var learnSuggestNameCmd = &cobra.Command{
+	Use:   "suggest-name [name...]",
+	Short: "Suggest better names for badly named patterns",
+	Long: `Ask an LLM for concise kebab-case names for patterns with names like
+"debugging-solution-3f2a", then rename them.
+
+Without arguments every pattern with a poor name is considered: one that
+ends in a hash or counter, or is made only of words like "debugging" and
+"solution". Name patterns to consider them whatever they're called.
+
+For each pattern, pick a suggestion by number, type a name of your own, or
+press Enter to keep the current one. With --apply the first suggestion is
+used without asking.
+
+Examples:
+  mur learn suggest-name                    # Review poorly named patterns
+  mur learn suggest-name --dry-run          # Only show suggestions
+  mur learn suggest-name --apply            # Rename all of them
+  mur learn suggest-name my-pattern --llm claude`,
+	RunE: runLearnSuggestName,
+}
+
+func init() {
+	learnCmd.AddCommand(learnRenameCmd)
+	learnCmd.AddCommand(learnSuggestNameCmd)
+	learnRenameCmd.Flags().Bool("no-sync", false, "Don't sync AI tools after renaming")
+	learnSuggestNameCmd.Flags().Bool("apply", false, "Rename to the first suggestion without asking")
+	learnSuggestNameCmd.Flags().Bool("dry-run", false, "Show suggestions without renaming")
+	learnSuggestNameCmd.Flags().Bool("no-sync", false, "Don't sync AI tools after renaming")
+	learnSuggestNameCmd.Flags().String("llm", "", "LLM provider: ollama, claude, openai, gemini (default from config)")
+	learnSuggestNameCmd.Flags().String("llm-model", "", "LLM model (default from config)")
+}
+
+func runLearnSuggestName(cmd *cobra.Command, args []string) error {
+	apply, _ := cmd.Flags().GetBool("apply")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noSync, _ := cmd.Flags().GetBool("no-sync")
+	provider, _ := cmd.Flags().GetString("llm")
+	model, _ := cmd.Flags().GetString("llm-model")
+
+	if apply && dryRun {
+		return fmt.Errorf("--apply and --dry-run cannot be used together")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	var candidates []pattern.Pattern
+	if len(args) > 0 {
+		for _, name := range args {
+			p, err := store.Get(name)
+			if err != nil {
+				return err
+			}
+			candidates = append(candidates, *p)
+		}
+	} else {
+		patterns, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, p := range patterns {
+			if learn.IsPoorName(p.Name) {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Println("✓ No poorly named patterns")
+		return nil
+	}
+
+	opts, _, err := resolveLLMOptions(cfg, provider, model)
+	if err != nil {
+		return err
+	}
+	if missing := llmUnavailable(opts); missing != "" {
+		return fmt.Errorf("cannot suggest names: %s", missing)
+	}
+
+	fmt.Printf("📝 %d patterns to name\n", len(candidates))
+	reader := bufio.NewReader(os.Stdin)
+	renamed, taken := 0, make(map[string]bool)
+	isTaken := func(name string) bool { return taken[name] || store.Exists(name) }
+
+	for _, p := range candidates {
+		fmt.Println()
+		fmt.Printf("%s\n", p.Name)
+		if summary := patternSummaryLine(p); summary != "" {
+			fmt.Printf("   %s\n", summary)
+		}
+
+		names, err := learn.SuggestNamesWithLLM(&p, opts, isTaken)
+		if err != nil {
+			fmt.Printf("   ⚠ %v\n", err)
+			continue
+		}
+		for i, name := range names {
+			fmt.Printf("   %d) %s\n", i+1, name)
+		}
+
+		if dryRun {
+			continue
+		}
+		to := names[0]
+		if !apply {
+			fmt.Printf("   Rename to [1-%d, a new name, Enter to keep, q to quit]: ", len(names))
+			answer, _ := reader.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			switch n, err := strconv.Atoi(answer); {
+			case answer == "":
+				continue
+			case answer == "q":
+				return finishSuggestName(cfg, renamed, noSync)
+			case err == nil && n >= 1 && n <= len(names):
+				to = names[n-1]
+			case err == nil:
+				fmt.Println("   Skipped: no such suggestion")
+				continue
+			default:
+				to = answer
+			}
+		}
+
+		if err := renamePattern(store, cfg, p.Name, to); err != nil {
+			fmt.Printf("   ✗ %v\n", err)
+			continue
+		}
+		taken[to] = true
+		renamed++
+	}
+
+	if dryRun {
+		fmt.Println()
+		fmt.Println("🔍 Dry run - no changes made")
+		return nil
+	}
+	return finishSuggestName(cfg, renamed, noSync)
+}
+
+func finishSuggestName(cfg *config.Config, renamed int, noSync bool) error {
+	fmt.Println()
+	fmt.Printf("✓ Renamed %d patterns\n", renamed)
+	if renamed > 0 && !noSync {
+		resyncAITools(cfg)
+	}
+	return nil
+}
+
+// patternSummaryLine is a one-line preview of what a pattern is about.
+func patternSummaryLine(p pattern.Pattern) string {
+	text := p.Description
+	if text == "" {
+		for _, line := range strings.Split(p.Content, "\n") {
+			if line = strings.TrimSpace(strings.TrimLeft(line, "#")); line != "" {
+				text = line
+				break
+			}
+		}
+	}
+	if len(text) > 100 {
+		text = text[:97] + "..."
+	}
+	return text
+}
+
+// renamePattern renames a pattern in the store, then moves what refers to
+// it outside the store: context profile pins and the search index.
+func renamePattern(store *pattern.Store, cfg *config.Config, from, to string) error {
+	result, err := store.Rename(from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   ✓ Renamed %s → %s\n", from, to)
+	if len(result.Referrers) > 0 {
+		fmt.Printf("     Updated relations in %s\n", strings.Join(result.Referrers, ", "))
+	}
+
+	if profiles := renameProfilePins(cfg, from, to); len(profiles) > 0 {
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("     ⚠ Cannot update profile pins: %v\n", err)
+		} else {
+			fmt.Printf("     Updated pins in profiles %s\n", strings.Join(profiles, ", "))
+		}
+	}
+
+	if embed.HasIndex() {
+		idx, err := embed.NewPatternIndexer(cfg)
+		if err == nil {
+			err = idx.RenamePattern(*result.Previous, *result.Renamed)
+		}
+		if err != nil {
+			fmt.Printf("     ⚠ Cannot update search index: %v (run 'mur index rebuild')\n", err)
+		}
+	}
+	return nil
+}
+
+// renameProfilePins renames the pattern in context profile pins and
+// returns the profiles that pinned it.
+func renameProfilePins(cfg *config.Config, from, to string) []string {
+	var changed []string
+	for _, name := range inject.ProfileNames(cfg) {
+		p := cfg.Context.Profiles[name]
+		found := false
+		for i, pin := range p.Pinned {
+			if pin == from {
+				p.Pinned[i] = to
+				found = true
+			}
+		}
+		if found {
+			cfg.Context.Profiles[name] = p
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// resyncAITools re-syncs AI tools after patterns were renamed or edited.
+func resyncAITools(cfg *config.Config) {
+	results, err := sync.SyncPatternsWithFormat(context.Background(), cfg)
+	if err != nil {
+		fmt.Printf("⚠ Sync failed: %v (run 'mur sync')\n", err)
+		return
+	}
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("⚠ Sync failed for %d of %d AI tools (run 'mur sync')\n", failed, len(results))
+		return
+	}
+	fmt.Printf("✓ Synced %d AI tools\n", len(results))
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/heartbeat"
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+var learnSourceCmd = &cobra.Command{
+	Use:   "source <name>",
+	Short: "Show the session excerpt a pattern was extracted from",
+	Long: `Show the conversation a pattern was extracted from.
+
+Extraction records the transcript file, line and byte offset of the source
+message. If the transcript has since been rewritten the message is found
+again by its timestamp; if it is gone, the excerpt saved with the pattern
+is shown instead.
+
+Examples:
+  mur learn source fix-go-test-undefined
+  mur learn source fix-go-test-undefined --context 5
+  mur learn source fix-go-test-undefined --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLearnSource,
+}
+
+func init() {
+	learnCmd.AddCommand(learnSourceCmd)
+	learnSourceCmd.Flags().IntP("context", "C", 2, "Messages to show before and after the excerpt")
+	learnSourceCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runLearnSource(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	context, _ := cmd.Flags().GetInt("context")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	ref, err := patternSource(name)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		fmt.Printf("No source recorded for '%s'.\n", name)
+		fmt.Println("Only patterns extracted from sessions link back to them.")
+		return nil
+	}
+
+	ex, err := learn.ReadSource(ref, context)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		out := struct {
+			Ref *pattern.SourceRef `json:"source"`
+			*learn.SourceExcerpt
+		}{ref, ex}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("📎 Source of '%s'\n", name)
+	fmt.Println()
+	fmt.Printf("   Session:    %s\n", ref.Session)
+	path := ex.Path
+	if path == "" {
+		path = ref.Path
+	}
+	if ref.Line > 0 {
+		path += fmt.Sprintf(":%d", ref.Line)
+		if ref.EndLine > ref.Line {
+			path += fmt.Sprintf("-%d", ref.EndLine)
+		}
+	}
+	fmt.Printf("   Transcript: %s\n", path)
+	if !ref.Timestamp.IsZero() {
+		fmt.Printf("   When:       %s\n", ref.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+	link, running := sourceDashboardLink(name)
+	if running {
+		fmt.Printf("   Dashboard:  %s\n", link)
+	} else {
+		fmt.Printf("   Dashboard:  %s (start with 'mur serve')\n", link)
+	}
+	if ex.Note != "" {
+		fmt.Printf("   Note:       %s\n", ex.Note)
+	}
+	fmt.Println()
+
+	if !ex.Found {
+		if ref.Excerpt == "" {
+			return nil
+		}
+		fmt.Println("Saved excerpt:")
+		for _, line := range strings.Split(ref.Excerpt, "\n") {
+			fmt.Println("   " + line)
+		}
+		return nil
+	}
+
+	for _, e := range ex.Entries {
+		marker := " "
+		if e.Match {
+			marker = "▶"
+		}
+		who := e.Role
+		if e.Subagent {
+			who += " (subagent)"
+		}
+		fmt.Printf("%s line %d · %s\n", marker, e.Line, who)
+		text := e.Text
+		if len(text) > 500 {
+			text = text[:500] + "\n...(truncated)"
+		}
+		for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+			fmt.Println("    " + line)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// patternSource returns the source reference of a pattern in either the
+// v2 schema (learning.source) or the v1 one (source).
+func patternSource(name string) (*pattern.SourceRef, error) {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	p, err := store.Get(name)
+	if err == nil {
+		return p.Learning.Source, nil
+	}
+	v1, v1Err := learn.Get(name)
+	if v1Err != nil {
+		return nil, err
+	}
+	return v1.Source, nil
+}
+
+// sourceDashboardLink returns the dashboard page for a pattern's source,
+// and whether the dashboard is running.
+func sourceDashboardLink(name string) (string, bool) {
+	addr := "http://localhost:8742"
+	h := heartbeat.Check(heartbeat.ProcessServe, time.Now())
+	running := h.State == heartbeat.StateHealthy
+	if running && h.Beat.Addr != "" {
+		addr = h.Beat.Addr
+	}
+	return addr + "/source/" + url.PathEscape(name), running
+}
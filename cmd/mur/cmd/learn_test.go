@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// TestLearnExtractCoalesceRepeats runs 'learn extract --coalesce' twice in
+// one process while another extraction holds the only slot: both calls
+// must queue, so the first mustn't have cleared --coalesce for the second.
+func TestLearnExtractCoalesceRepeats(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	locks := filepath.Join(config.StateDir(home), "locks")
+	if err := os.MkdirAll(locks, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(locks, "extract.0.lock"), []byte(fmt.Sprintln(os.Getpid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := learnExtractCmd.ParseFlags([]string{"--coalesce", "--status", "--quiet"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, name := range []string{"coalesce", "status", "quiet"} {
+			_ = learnExtractCmd.Flags().Set(name, "false")
+		}
+	})
+
+	pending := filepath.Join(locks, "extract.pending")
+	for i := 1; i <= 2; i++ {
+		if err := learnExtractCmd.RunE(learnExtractCmd, nil); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if coalesce, _ := learnExtractCmd.Flags().GetBool("coalesce"); !coalesce {
+			t.Fatalf("call %d cleared --coalesce", i)
+		}
+		if err := os.Remove(pending); err != nil {
+			t.Fatalf("call %d didn't queue: %v", i, err)
+		}
+	}
+}
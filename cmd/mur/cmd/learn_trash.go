@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var learnTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore, or empty deleted patterns",
+	Long: `Deleted patterns go to the trash (~/.mur/patterns/.trash/) and can be
+restored until storage.trash_days (default 30) have passed; 'mur sync'
+then purges them. Cloud and repo sync propagate a deletion only once the
+pattern is purged, so a wrong delete never reaches your team.
+
+Examples:
+  mur learn trash                     # List deleted patterns
+  mur learn trash restore go-errors   # Put one back
+  mur learn trash empty               # Purge everything now
+  mur learn trash empty --older-than 7d`,
+	RunE: runTrashList,
+}
+
+var learnTrashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deleted patterns",
+	RunE:  runTrashList,
+}
+
+var learnTrashRestoreCmd = &cobra.Command{
+	Use:   "restore <name...>",
+	Short: "Restore deleted patterns",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := pattern.DefaultStore()
+		if err != nil {
+			return err
+		}
+		for _, name := range args {
+			if _, err := store.Restore(name); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Restored '%s'\n", name)
+		}
+		fmt.Println("  Run 'mur learn sync' to update AI tools")
+		return nil
+	},
+}
+
+var learnTrashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete trashed patterns",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderStr, _ := cmd.Flags().GetString("older-than")
+		force, _ := cmd.Flags().GetBool("force")
+
+		var olderThan time.Duration
+		if olderStr != "" {
+			d, err := pattern.ParseAge(olderStr)
+			if err != nil {
+				return err
+			}
+			olderThan = d
+		}
+
+		store, err := pattern.DefaultStore()
+		if err != nil {
+			return err
+		}
+		trash, err := store.Trash()
+		if err != nil {
+			return err
+		}
+		matched := 0
+		for _, e := range trash {
+			if olderThan == 0 || time.Since(e.DeletedAt) >= olderThan {
+				matched++
+			}
+		}
+		if matched == 0 {
+			fmt.Println("Nothing to purge")
+			return nil
+		}
+
+		if !force {
+			fmt.Printf("Permanently delete %d pattern(s)? Sync will delete them remotely too. [y/N] ", matched)
+			reader := bufio.NewReader(os.Stdin)
+			confirm, _ := reader.ReadString('\n')
+			confirm = strings.TrimSpace(strings.ToLower(confirm))
+			if confirm != "y" && confirm != "yes" {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+
+		purged, err := store.EmptyTrash(olderThan, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🗑️ Purged %d pattern(s)\n", len(purged))
+		return nil
+	},
+}
+
+func init() {
+	learnCmd.AddCommand(learnTrashCmd)
+	learnTrashCmd.AddCommand(learnTrashListCmd)
+	learnTrashCmd.AddCommand(learnTrashRestoreCmd)
+	learnTrashCmd.AddCommand(learnTrashEmptyCmd)
+	learnTrashEmptyCmd.Flags().String("older-than", "", "Only purge patterns deleted longer ago than this (e.g. 7d)")
+	learnTrashEmptyCmd.Flags().BoolP("force", "f", false, "Skip confirmation")
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	trash, err := store.Trash()
+	if err != nil {
+		return err
+	}
+	if len(trash) == 0 {
+		fmt.Println("Trash is empty")
+		return nil
+	}
+
+	retention := store.TrashRetention()
+	fmt.Printf("🗑️ %d deleted pattern(s)\n\n", len(trash))
+	for _, e := range trash {
+		line := fmt.Sprintf("  %-30s deleted %s", e.Name, e.DeletedAt.Local().Format("2006-01-02 15:04"))
+		if retention > 0 {
+			line += fmt.Sprintf(", purged after %s", e.ExpiresAt(retention).Local().Format("2006-01-02"))
+		}
+		if e.Reason != "" {
+			line += " (" + e.Reason + ")"
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	fmt.Println("Restore with: mur learn trash restore <name>")
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"time"
@@ -76,7 +77,7 @@ var lifecycleCleanupCmd = &cobra.Command{
 
 func getLifecycleManager() (*pattern.LifecycleManager, error) {
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := pattern.DefaultLifecycleConfig()
@@ -87,7 +88,7 @@ func lifecycleEvaluateExecute(cmd *cobra.Command, args []string) error {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := pattern.DefaultLifecycleConfig()
@@ -231,7 +232,7 @@ func lifecycleListExecute(cmd *cobra.Command, args []string) error {
 	status, _ := cmd.Flags().GetString("status")
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	patterns, err := store.List()
@@ -275,7 +276,7 @@ func lifecycleCleanupExecute(cmd *cobra.Command, args []string) error {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := pattern.DefaultLifecycleConfig()
@@ -308,7 +309,8 @@ func lifecycleCleanupExecute(cmd *cobra.Command, args []string) error {
 	if deleted == 0 {
 		fmt.Println("✓ No patterns to clean up")
 	} else {
-		fmt.Printf("🗑️ Deleted %d archived patterns older than %d days\n", deleted, days)
+		fmt.Printf("🗑️ Moved %d archived patterns older than %d days to the trash\n", deleted, days)
+		fmt.Println("  Restore with: mur learn trash restore <name>")
 	}
 
 	return nil
@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
@@ -16,7 +19,7 @@ var lintCmd = &cobra.Command{
 
   - Required fields (name, content)
   - Schema version compatibility
-  - Content length limits
+  - Content length limits (storage.max_content_bytes)
   - Tags configuration
   - Security issues (prompt injection detection)
   - Hash integrity verification
@@ -29,13 +32,17 @@ Examples:
   mur lint my-pattern
 
   # Show only errors (no warnings/info)
-  mur lint --errors-only`,
+  mur lint --errors-only
+
+  # List the largest patterns against the content limit
+  mur lint --sizes`,
 	RunE: runLint,
 }
 
 var (
 	lintErrorsOnly bool
 	lintJSON       bool
+	lintSizes      int
 )
 
 func init() {
@@ -43,6 +50,8 @@ func init() {
 	rootCmd.AddCommand(lintCmd)
 	lintCmd.Flags().BoolVar(&lintErrorsOnly, "errors-only", false, "Show only errors, hide warnings and info")
 	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "Output in JSON format")
+	lintCmd.Flags().IntVar(&lintSizes, "sizes", 0, "List the N largest patterns and their share of the content limit")
+	lintCmd.Flags().Lookup("sizes").NoOptDefVal = "20"
 }
 
 func runLint(cmd *cobra.Command, args []string) error {
@@ -51,7 +60,13 @@ func runLint(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	linter := pattern.NewLinter()
+	cfg, _ := config.Load()
+	limit, _ := pattern.ContentLimit(cfg)
+	linter := pattern.NewLinter().WithMaxContent(limit)
+
+	if lintSizes > 0 {
+		return lintSizeReport(cmd, store, limit, lintSizes)
+	}
 
 	if len(args) > 0 {
 		// Lint specific pattern
@@ -159,3 +174,43 @@ func printIssues(name string, issues []pattern.LintIssue) {
 	}
 	fmt.Println()
 }
+
+// lintSizeReport lists the n largest patterns by content size, with the
+// share of limit they use and the size of any split-off examples.
+func lintSizeReport(cmd *cobra.Command, store *pattern.Store, limit, n int) error {
+	out := newPrinter(cmd)
+	patterns, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(patterns) == 0 {
+		out.Println("No patterns found")
+		return nil
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		return len(patterns[i].Content) > len(patterns[j].Content)
+	})
+	if n > len(patterns) {
+		n = len(patterns)
+	}
+
+	if limit > 0 {
+		out.Printf("Largest patterns (limit %d bytes, storage.max_content_bytes)\n\n", limit)
+	} else {
+		out.Printf("Largest patterns (no content limit)\n\n")
+	}
+	for _, p := range patterns[:n] {
+		size := len(p.Content)
+		share := "-"
+		if limit > 0 {
+			share = fmt.Sprintf("%3.0f%%", 100*float64(size)/float64(limit))
+		}
+		line := fmt.Sprintf("  %8d  %5s  %s", size, share, p.Name)
+		if info, err := os.Stat(filepath.Join(store.ResourcesDir(p.Name), pattern.ExamplesFile)); err == nil {
+			line += fmt.Sprintf("  (+%d bytes in %s)", info.Size(), pattern.ExamplesFile)
+		}
+		out.Println(line)
+	}
+	return nil
+}
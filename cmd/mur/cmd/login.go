@@ -283,6 +283,11 @@ var whoamiCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Logged in as %s (%s)\n", user.Name, user.Email)
+		if serverURL != "" && serverURL != cloud.DefaultServerURL {
+			if info, err := client.ServerInfo(); err == nil {
+				fmt.Printf("Server: %s (version %s)\n", serverURL, info.Version)
+			}
+		}
 		return nil
 	},
 }
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	murlog "github.com/mur-run/mur-core/internal/log"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show recent entries from mur's log",
+	Long: `Show recent entries from mur's log, ~/.mur/logs/mur.log.
+
+Every mur command logs there, including the ones hooks run in the
+background, where errors would otherwise go unseen. Set how much is
+logged with --log-level on any command, $MUR_LOG_LEVEL, or logging.level
+in config (default info; debug adds each command run and more detail).
+
+Examples:
+  mur logs                     # Last 50 entries
+  mur logs -n 200 --level error
+  mur logs --component sync
+  mur logs --follow            # Keep printing new entries
+  mur logs --path              # Print the log file's path`,
+	RunE: runLogs,
+}
+
+var (
+	logsLines     int
+	logsFollow    bool
+	logsLevel     string
+	logsComponent string
+	logsPath      bool
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "Number of entries to show")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new entries")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "debug", "Lowest level shown: debug, info, warn, error")
+	logsCmd.Flags().StringVar(&logsComponent, "component", "", "Only entries from this part of mur, e.g. sync or hooks")
+	logsCmd.Flags().BoolVar(&logsPath, "path", false, "Print the log file's path and exit")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	out := newPrinter(cmd)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path := murlog.Path(home)
+	if logsPath {
+		out.Println(path)
+		return nil
+	}
+
+	level, err := murlog.ParseLevel(logsLevel)
+	if err != nil {
+		return err
+	}
+	filter := murlog.Filter{Level: level, Component: logsComponent}
+
+	show := func(e murlog.Entry) {
+		if out.JSON() {
+			// One compact object per line while following
+			data, _ := json.Marshal(logEntryJSON(e))
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return
+		}
+		line := e.String()
+		switch {
+		case e.Level >= slog.LevelError:
+			line = out.Red(line)
+		case e.Level >= slog.LevelWarn:
+			line = out.Yellow(line)
+		case e.Level < slog.LevelInfo:
+			line = out.Dim(line)
+		}
+		out.Println(line)
+	}
+
+	entries, err := murlog.Tail(path, logsLines, filter)
+	if err != nil {
+		return fmt.Errorf("cannot read log: %w", err)
+	}
+	if out.JSON() && !logsFollow {
+		list := make([]map[string]any, len(entries))
+		for i, e := range entries {
+			list[i] = logEntryJSON(e)
+		}
+		return out.WriteJSON(list)
+	}
+	if len(entries) == 0 && !logsFollow {
+		out.Printf("No log entries in %s\n", path)
+		return nil
+	}
+	for _, e := range entries {
+		show(e)
+	}
+
+	if !logsFollow {
+		return nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return murlog.Follow(ctx, path, filter, show)
+}
+
+// logEntryJSON is the shape of an entry with --json.
+func logEntryJSON(e murlog.Entry) map[string]any {
+	m := map[string]any{
+		"time":  e.Time,
+		"level": e.Level.String(),
+		"msg":   e.Msg,
+	}
+	if e.Component != "" {
+		m["component"] = e.Component
+	}
+	if e.Command != "" {
+		m["cmd"] = e.Command
+	}
+	if len(e.Attrs) > 0 {
+		m["attrs"] = e.Attrs
+	}
+	return m
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/mcp"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Model Context Protocol server for AI tools",
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve patterns to MCP clients over stdio",
+	Long: `Run an MCP (Model Context Protocol) server on stdin/stdout, so Claude
+Code and other MCP clients can query your patterns natively instead of
+relying on prompt-injection hooks.
+
+Tools:
+  search_patterns   Hybrid keyword + semantic search (keyword only without
+                    an embedding index)
+  get_pattern       Full content of a pattern by name or ID
+  add_pattern       Save a new pattern (refused if it contains secrets or
+                    looks like a prompt injection; not with --read-only)
+  list_workflows    Saved workflows
+
+Each active pattern is also a resource, mur://patterns/<name>.
+
+Examples:
+  claude mcp add mur -- mur mcp serve
+  claude mcp add mur -- mur mcp serve --read-only`,
+	Args: cobra.NoArgs,
+	RunE: runMCPServe,
+}
+
+var mcpReadOnly bool
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.AddCommand(mcpServeCmd)
+	mcpServeCmd.Flags().BoolVar(&mcpReadOnly, "read-only", false, "Don't offer the add_pattern tool")
+}
+
+func runMCPServe(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	opts := mcp.Options{Version: Version, ReadOnly: mcpReadOnly}
+	if cfg.Search.IsEnabled() {
+		if indexer, err := embed.NewPatternIndexer(cfg); err == nil {
+			opts.Search = func(query string, limit int) ([]embed.PatternMatch, error) {
+				// Without semantic results, the keyword ones still help
+				matches, err := indexer.HybridSearch(query, limit)
+				if err != nil && matches == nil {
+					return nil, err
+				}
+				return matches, nil
+			}
+		}
+	}
+
+	// stdout carries the protocol; anything else goes to stderr
+	if err := mcp.NewServer(store, opts).Serve(os.Stdin, os.Stdout); err != nil {
+		return fmt.Errorf("mcp: %w", err)
+	}
+	return nil
+}
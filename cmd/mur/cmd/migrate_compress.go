@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var migrateCompressCmd = &cobra.Command{
+	Use:   "compress",
+	Short: "Compress large patterns with zstd and report the space saved",
+	Long: `Rewrite patterns larger than a threshold as zstd-compressed .yaml.zst
+files and turn on storage.compress, so patterns saved later are compressed
+too. mur reads compressed patterns transparently; patterns synced from a
+repo are left alone.
+
+Examples:
+  mur migrate compress --report       # Show pattern storage use
+  mur migrate compress --dry-run      # Show what compressing would save
+  mur migrate compress                # Compress patterns over 8 KB
+  mur migrate compress --threshold 4096
+  mur migrate compress --decompress   # Back to plain .yaml files`,
+	RunE: runMigrateCompress,
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateCompressCmd)
+	migrateCompressCmd.Flags().Int("threshold", 0, "Compress patterns larger than this many bytes (default: storage.compress_threshold or 8192)")
+	migrateCompressCmd.Flags().Bool("decompress", false, "Decompress all patterns and turn storage.compress off")
+	migrateCompressCmd.Flags().Bool("report", false, "Only report storage use")
+	migrateCompressCmd.Flags().Bool("dry-run", false, "Show what would change without making changes")
+}
+
+func runMigrateCompress(cmd *cobra.Command, args []string) error {
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	decompress, _ := cmd.Flags().GetBool("decompress")
+	reportOnly, _ := cmd.Flags().GetBool("report")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if decompress && cmd.Flags().Changed("threshold") {
+		return fmt.Errorf("--decompress and --threshold cannot be used together")
+	}
+	if threshold < 0 {
+		return fmt.Errorf("--threshold must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	if reportOnly {
+		report, err := store.Usage()
+		if err != nil {
+			return err
+		}
+		printStorageReport(report)
+		return nil
+	}
+
+	if threshold == 0 && !decompress {
+		threshold = cfg.Storage.CompressThreshold
+		if threshold <= 0 {
+			threshold = pattern.DefaultCompressThreshold
+		}
+	}
+	if decompress {
+		threshold = 0
+	}
+
+	before, err := store.Usage()
+	if err != nil {
+		return err
+	}
+	after, err := store.Recompress(threshold, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if decompress {
+		fmt.Printf("📦 Decompressing %d of %d patterns\n\n", after.Changed, after.Files)
+	} else {
+		fmt.Printf("📦 Compressing patterns over %s: %d of %d changed\n\n", formatSize(int64(threshold)), after.Changed, after.Files)
+	}
+	fmt.Printf("   Before: %s on disk\n", formatSize(before.DiskBytes))
+	fmt.Printf("   After:  %s on disk", formatSize(after.DiskBytes))
+	if diff := before.DiskBytes - after.DiskBytes; diff > 0 {
+		fmt.Printf(" (%s saved)", formatSize(diff))
+	} else if diff < 0 {
+		fmt.Printf(" (%s more)", formatSize(-diff))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println("🔍 Dry run - no changes made")
+		return nil
+	}
+
+	if cfg.Storage.Compress != !decompress || (!decompress && cfg.Storage.CompressThreshold != threshold) {
+		// Keep a threshold when turning compression off: Save merges into
+		// the existing file, so an empty storage section would leave
+		// compress: true behind.
+		switch {
+		case !decompress:
+			cfg.Storage.CompressThreshold = threshold
+		case cfg.Storage.CompressThreshold == 0:
+			cfg.Storage.CompressThreshold = pattern.DefaultCompressThreshold
+		}
+		cfg.Storage.Compress = !decompress
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+	}
+	if decompress {
+		fmt.Println("✓ Patterns stored as plain YAML (storage.compress off)")
+	} else {
+		fmt.Printf("✓ Patterns over %s stored compressed (storage.compress on)\n", formatSize(int64(threshold)))
+	}
+	return nil
+}
+
+func printStorageReport(r *pattern.StorageReport) {
+	if r.Files == 0 {
+		fmt.Println("No patterns stored.")
+		return
+	}
+	fmt.Printf("📦 %d patterns, %d compressed\n\n", r.Files, r.Compressed)
+	fmt.Printf("   YAML:    %s\n", formatSize(r.RawBytes))
+	fmt.Printf("   On disk: %s", formatSize(r.DiskBytes))
+	if r.Saved() > 0 {
+		fmt.Printf(" (%s saved, %.0f%%)", formatSize(r.Saved()), 100*float64(r.Saved())/float64(r.RawBytes))
+	}
+	fmt.Println()
+
+	fmt.Println()
+	fmt.Println("Largest patterns:")
+	for _, e := range r.Largest {
+		mark := ""
+		if e.Compressed {
+			mark = fmt.Sprintf(" → %s (zst)", formatSize(e.DiskBytes))
+		}
+		fmt.Printf("   %-40s %8s%s\n", e.Name, formatSize(e.RawBytes), mark)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/hooks"
+)
+
+var migrateDirsCmd = &cobra.Command{
+	Use:   "dirs",
+	Short: "Relocate mur data to XDG base directories or a custom MUR_HOME",
+	Long: `Move existing mur data out of its current location into a new layout
+and regenerate hook scripts so they point at the new paths.
+
+Targets:
+  xdg     Split into $XDG_CONFIG_HOME/mur, $XDG_DATA_HOME/mur,
+          $XDG_CACHE_HOME/mur and $XDG_STATE_HOME/mur
+  <path>  Keep everything in a single directory; set MUR_HOME=<path>
+          in your shell profile afterwards
+
+Nothing is overwritten: the migration aborts if any target already exists.
+
+Examples:
+  mur migrate dirs --dry-run          # Show what would move
+  mur migrate dirs                    # Move ~/.mur to XDG directories
+  mur migrate dirs --to ~/work/mur    # Move everything to a custom MUR_HOME`,
+	RunE: runMigrateDirs,
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateDirsCmd)
+	migrateDirsCmd.Flags().String("to", "xdg", "Target layout: 'xdg' or a directory path")
+	migrateDirsCmd.Flags().Bool("dry-run", false, "Show what would be moved without making changes")
+}
+
+func runMigrateDirs(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+
+	src := config.ResolveDirs(home)
+	var dst config.Dirs
+	murHome := ""
+	if to == "xdg" {
+		dst = config.XDGDirs(home)
+	} else {
+		if strings.HasPrefix(to, "~/") {
+			to = filepath.Join(home, to[2:])
+		}
+		murHome, err = filepath.Abs(to)
+		if err != nil {
+			return fmt.Errorf("invalid target %q: %w", to, err)
+		}
+		dst = config.Dirs{Config: murHome, Data: murHome, Cache: murHome, State: murHome}
+	}
+
+	moves, err := config.PlanMigration(src, dst)
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		fmt.Println("✓ Nothing to migrate")
+		return nil
+	}
+
+	fmt.Printf("📦 Migrating %d entries (%s → %s)\n\n", len(moves), config.Layout(home), to)
+	for _, m := range moves {
+		fmt.Printf("   %s → %s\n", m.From, m.To)
+	}
+	fmt.Println()
+
+	if dryRun {
+		fmt.Println("🔍 Dry run - no changes made")
+		return nil
+	}
+
+	if err := config.ApplyMigration(moves); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	fmt.Println("✓ Data moved")
+
+	// Regenerate hooks against the new layout so scripts and settings.json
+	// reference the new paths.
+	if murHome != "" {
+		os.Setenv("MUR_HOME", murHome)
+	}
+	for name, err := range hooks.InstallAllHooksWithOptions(hooks.HookOptions{Force: true}) {
+		if err != nil {
+			fmt.Printf("⚠ Failed to update %s hooks: %v\n", name, err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Migration complete!")
+	if murHome != "" {
+		fmt.Println()
+		fmt.Println("Add this to your shell profile:")
+		fmt.Printf("  export MUR_HOME=%s\n", murHome)
+	}
+	return nil
+}
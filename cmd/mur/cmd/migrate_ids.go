@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var migrateIDsCmd = &cobra.Command{
+	Use:   "ids",
+	Short: "Give every pattern a stable ID",
+	Long: `Give patterns without an ID, or sharing one with another pattern, a
+new stable ID (a ULID) in their frontmatter.
+
+A pattern's ID is its identity: the search index and cloud sync refer to
+it by ID, so renaming a pattern ('mur learn rename') doesn't break them.
+Names remain what you type; commands that take a pattern accept its ID
+too. Patterns created by mur already have an ID; this fixes up patterns
+written by hand or by old versions. Patterns synced from a repo are left
+alone.
+
+Examples:
+  mur migrate ids --dry-run
+  mur migrate ids`,
+	RunE: runMigrateIDs,
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateIDsCmd)
+	migrateIDsCmd.Flags().Bool("dry-run", false, "Show what would change without making changes")
+}
+
+func runMigrateIDs(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	assigned, err := store.AssignIDs(dryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(assigned) == 0 {
+		fmt.Println("✓ Every pattern has a unique ID")
+		return nil
+	}
+	verb := "Assigned"
+	if dryRun {
+		verb = "Would assign"
+	}
+	fmt.Printf("🆔 %s IDs to %d patterns:\n", verb, len(assigned))
+	for _, name := range assigned {
+		fmt.Printf("   %s\n", name)
+	}
+	if dryRun {
+		fmt.Println("\n🔍 Dry run - no changes made")
+	}
+	return nil
+}
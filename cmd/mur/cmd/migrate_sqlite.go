@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var migrateSQLiteCmd = &cobra.Command{
+	Use:   "sqlite",
+	Short: "Index patterns in SQLite so large pattern sets load fast",
+	Long: `Move patterns to storage.backend: sqlite, so commands and the dashboard
+read parsed patterns from an indexed database instead of parsing every
+YAML file, and filter by domain, tag, and status in SQL.
+
+The move takes two steps. The first builds patterns.db from the pattern
+files and turns on storage.backend: dual: patterns are still read from
+the files, every change mur makes is also written to the database, and
+the database is checked against the files (counts and content hashes)
+once an hour. --verify checks it now and switches to sqlite only if
+every row matches its file; otherwise it lists what diverged, and running
+this command again rebuilds the database.
+
+Pattern files stay the source of truth: sync, git, history, and editing
+keep working on them, and files changed outside mur are re-read
+automatically. If the database can't be opened, mur falls back to the
+files. --files goes back in one step, whichever backend is in use.
+
+Examples:
+  mur migrate sqlite            # Build the index and start dual writes
+  mur migrate sqlite --verify   # Check it and switch to sqlite if clean
+  mur migrate sqlite --status   # Show the backend and the last check
+  mur migrate sqlite --files    # Back to reading the files directly`,
+	RunE: runMigrateSQLite,
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateSQLiteCmd)
+	migrateSQLiteCmd.Flags().Bool("files", false, "Switch back to the file backend (storage.backend: files)")
+	migrateSQLiteCmd.Flags().Bool("status", false, "Only show the backend in use and the last check")
+	migrateSQLiteCmd.Flags().Bool("verify", false, "Check the index against the files and switch to sqlite if they match")
+}
+
+func runMigrateSQLite(cmd *cobra.Command, args []string) error {
+	toFiles, _ := cmd.Flags().GetBool("files")
+	statusOnly, _ := cmd.Flags().GetBool("status")
+	verify, _ := cmd.Flags().GetBool("verify")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	if statusOnly {
+		backend, err := store.Backend()
+		fmt.Printf("📦 Storage backend: %s\n", backend)
+		if err != nil {
+			fmt.Printf("   ⚠️  storage.backend is %s, but falling back to files: %v\n", pattern.StorageBackend(cfg), err)
+			return nil
+		}
+		if backend == pattern.BackendFiles {
+			return nil
+		}
+		fmt.Printf("   Index: %s\n", store.IndexPath())
+		if last, err := store.LastParity(); err == nil && last != nil {
+			fmt.Printf("   Last check: %s, %d files, %d rows, %d divergent\n",
+				last.CheckedAt.Local().Format("2006-01-02 15:04"), last.Files, last.Rows, len(last.Divergent))
+		}
+		return nil
+	}
+
+	if toFiles {
+		if cfg.Storage.Backend != "" && cfg.Storage.Backend != pattern.BackendFiles {
+			// Save merges into the existing file, so an empty backend
+			// would leave sqlite behind.
+			cfg.Storage.Backend = pattern.BackendFiles
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("cannot save config: %w", err)
+			}
+		}
+		fmt.Println("✓ Patterns read from files (storage.backend: files)")
+		fmt.Printf("  %s is no longer used and can be deleted\n", store.IndexPath())
+		return nil
+	}
+
+	if verify {
+		return verifySQLite(cfg, store)
+	}
+
+	// Rebuilt from scratch so that rows a failed mirror left behind can't
+	// survive into the check
+	report, err := store.RebuildIndex()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📦 Indexed %d patterns in %s (%d files parsed, %s)\n",
+		report.Patterns, report.Path, report.Reread, report.Duration.Round(time.Millisecond))
+
+	if pattern.StorageBackend(cfg) == pattern.BackendSQLite {
+		fmt.Println("✓ Patterns read from SQLite (storage.backend: sqlite)")
+		return nil
+	}
+	if pattern.StorageBackend(cfg) != pattern.BackendDual {
+		cfg.Storage.Backend = pattern.BackendDual
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+	}
+	fmt.Println("✓ Patterns read from files and mirrored to SQLite (storage.backend: dual)")
+	fmt.Println("  Switch with: mur migrate sqlite --verify")
+	return nil
+}
+
+// verifySQLite checks the index against the pattern files and switches
+// to the sqlite backend after a clean check.
+func verifySQLite(cfg *config.Config, store *pattern.Store) error {
+	backend := pattern.StorageBackend(cfg)
+	if backend == pattern.BackendFiles {
+		return fmt.Errorf("not migrating: run 'mur migrate sqlite' first")
+	}
+	report, err := store.VerifyIndex()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔍 %d pattern files, %d rows, %d stale, %d divergent\n",
+		report.Files, report.Rows, report.Stale, len(report.Divergent))
+	for _, path := range report.Divergent {
+		fmt.Printf("   ✗ %s\n", path)
+	}
+	if !report.Clean() {
+		if len(report.Divergent) == 0 && report.Files == report.Rows {
+			// Only files changed since the last write: re-read now
+			return fmt.Errorf("%d pattern(s) changed outside mur since the last check and were re-read; run --verify again", report.Stale)
+		}
+		return fmt.Errorf("index diverges from the pattern files; staying on %s (rebuild with 'mur migrate sqlite')", backend)
+	}
+
+	if backend != pattern.BackendSQLite {
+		cfg.Storage.Backend = pattern.BackendSQLite
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+	}
+	fmt.Println("✓ Patterns read from SQLite (storage.backend: sqlite)")
+	fmt.Println("  Roll back with: mur migrate sqlite --files")
+	return nil
+}
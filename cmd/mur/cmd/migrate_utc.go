@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+var migrateUTCCmd = &cobra.Command{
+	Use:   "utc",
+	Short: "Store existing timestamps in UTC",
+	Long: `Rewrite the timestamps in your patterns and usage logs to UTC.
+
+mur stores times in UTC and shows them in your display time zone
+(stats.timezone, by default the machine's). Records written by older
+versions carry the offset of the machine that wrote them, so days
+bucketed across machines or a time zone change could be off by one.
+The instants themselves don't change.
+
+Examples:
+  mur migrate utc --dry-run
+  mur migrate utc`,
+	RunE: runMigrateUTC,
+}
+
+func init() {
+	migrateCmd.AddCommand(migrateUTCCmd)
+	migrateUTCCmd.Flags().Bool("dry-run", false, "Show what would change without making changes")
+}
+
+// utcLogs are the JSON lines logs with timestamps, relative to the state
+// directory, and their timestamp fields.
+var utcLogs = []struct {
+	path   string
+	fields []string
+}{
+	{"stats.jsonl", []string{"timestamp"}},
+	{"injections.jsonl", []string{"time"}},
+	{filepath.Join("tracking", "usage.jsonl"), []string{"timestamp"}},
+	{filepath.Join("tracking", "outcomes.jsonl"), []string{"time", "injected_at"}},
+}
+
+func runMigrateUTC(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	verb := "Converted"
+	if dryRun {
+		verb = "Would convert"
+	}
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	changed, err := store.NormalizeTimes(dryRun)
+	if err != nil {
+		return err
+	}
+	total := len(changed)
+	if len(changed) > 0 {
+		fmt.Printf("🕐 %s timestamps in %d patterns\n", verb, len(changed))
+	}
+
+	for _, log := range utcLogs {
+		path := filepath.Join(config.StateDir(home), log.path)
+		n, err := stats.MigrateToUTC(path, log.fields, dryRun)
+		if err != nil {
+			return fmt.Errorf("cannot migrate %s: %w", log.path, err)
+		}
+		total += n
+		if n > 0 {
+			fmt.Printf("🕐 %s %d records in %s\n", verb, n, log.path)
+		}
+	}
+
+	if total == 0 {
+		fmt.Println("✓ All timestamps are already in UTC")
+		return nil
+	}
+	if dryRun {
+		fmt.Println("\n🔍 Dry run - no changes made")
+	}
+	return nil
+}
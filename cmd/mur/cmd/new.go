@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -35,7 +36,7 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	if err := os.MkdirAll(patternsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create patterns directory: %w", err)
 	}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -16,18 +19,32 @@ var notifyCmd = &cobra.Command{
 }
 
 var notifyTestCmd = &cobra.Command{
-	Use:   "test",
-	Short: "Send a test notification",
+	Use:   "test [event]",
+	Short: "Send a test notification, or preview an event's message",
 	Long: `Send a test notification to verify webhook configuration.
 
+With an event (pattern_added, patterns_extracted, pr_created, test), render
+that event's message against sample data and print it instead, so you can
+check a template in ~/.mur/templates/notify/ before it reaches your team.
+Templates are Go templates named <event>.tmpl, or <event>.slack.tmpl and
+<event>.discord.tmpl for one channel.
+
 Examples:
-  mur notify test             # Test all configured webhooks
-  mur notify test --slack     # Test Slack only
-  mur notify test --discord   # Test Discord only`,
+  mur notify test                     # Test all configured webhooks
+  mur notify test --slack             # Test Slack only
+  mur notify test --discord           # Test Discord only
+  mur notify test pr_created          # Preview the PR message
+  mur notify test pr_created --send   # Send it with sample data`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		slackOnly, _ := cmd.Flags().GetBool("slack")
 		discordOnly, _ := cmd.Flags().GetBool("discord")
 
+		if len(args) == 1 {
+			send, _ := cmd.Flags().GetBool("send")
+			return runNotifyPreview(args[0], slackOnly, discordOnly, send)
+		}
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
@@ -107,11 +124,109 @@ Examples:
 	},
 }
 
+var notifyAlertCmd = &cobra.Command{
+	Use:    "alert <notification-json>",
+	Short:  "Show a notification with buttons and run the one clicked",
+	Hidden: true, // Started detached by system notifications
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var n notify.Notification
+		if err := json.Unmarshal([]byte(args[0]), &n); err != nil {
+			return fmt.Errorf("invalid notification: %w", err)
+		}
+		return notify.Alert(cmd.Context(), n)
+	},
+}
+
+// runNotifyPreview renders event against sample data for each channel and
+// prints it, or sends it to the configured webhooks with send.
+func runNotifyPreview(event string, slackOnly, discordOnly, send bool) error {
+	if !slices.Contains(notify.Events(), event) {
+		return fmt.Errorf("unknown event %q (use %s)", event, strings.Join(notify.Events(), ", "))
+	}
+
+	channels := []string{notify.ChannelSlack, notify.ChannelDiscord}
+	if slackOnly {
+		channels = []string{notify.ChannelSlack}
+	} else if discordOnly {
+		channels = []string{notify.ChannelDiscord}
+	}
+	opts := notify.SampleOptions(event)
+
+	if send {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		webhooks := map[string]string{
+			notify.ChannelSlack:   cfg.Notifications.Slack.WebhookURL,
+			notify.ChannelDiscord: cfg.Notifications.Discord.WebhookURL,
+		}
+		sent := 0
+		for _, ch := range channels {
+			url := webhooks[ch]
+			if url == "" {
+				continue
+			}
+			notifyFn := notify.NotifySlack
+			if ch == notify.ChannelDiscord {
+				notifyFn = notify.NotifyDiscord
+			}
+			if err := notifyFn(url, event, opts); err != nil {
+				fmt.Printf("  ✗ %s: %v\n", ch, err)
+				continue
+			}
+			fmt.Printf("  ✓ %s\n", ch)
+			sent++
+		}
+		if sent == 0 {
+			return fmt.Errorf("no notifications were sent successfully")
+		}
+		return nil
+	}
+
+	failed := 0
+	for i, ch := range channels {
+		if i > 0 {
+			fmt.Println()
+		}
+		p, err := notify.Render(event, ch, opts)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", ch, err)
+			fmt.Println("  The built-in message is sent until the template is fixed.")
+			failed++
+			continue
+		}
+		if p.Template == "" {
+			fmt.Printf("📄 %s (built-in message)\n", ch)
+		} else {
+			fmt.Printf("📄 %s (%s)\n", ch, p.Template)
+		}
+		fmt.Println(strings.Repeat("─", 50))
+		if p.Text != "" {
+			fmt.Println(p.Text)
+		} else {
+			fmt.Println(string(p.Payload))
+		}
+		fmt.Println(strings.Repeat("─", 50))
+	}
+
+	if dir, err := notify.TemplatesDir(); err == nil {
+		fmt.Printf("\nOverride with %s/%s.tmpl\n", dir, event)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d template(s) failed to render", failed)
+	}
+	return nil
+}
+
 func init() {
 	notifyCmd.Hidden = true
 	rootCmd.AddCommand(notifyCmd)
 	notifyCmd.AddCommand(notifyTestCmd)
+	notifyCmd.AddCommand(notifyAlertCmd)
 
 	notifyTestCmd.Flags().Bool("slack", false, "Test Slack webhook only")
 	notifyTestCmd.Flags().Bool("discord", false, "Test Discord webhook only")
+	notifyTestCmd.Flags().Bool("send", false, "Send the previewed event with sample data")
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch the context profile injected by hooks",
+	Long: `Switch between context profiles, such as reviewer, implementer and
+oncall, which tailor the patterns hooks inject to what you're doing.
+
+Profiles are defined under context.profiles in config:
+
+  context:
+    profiles:
+      oncall:
+        tags: [incident, debugging, observability]
+        max: 3
+        pinned: [incident-runbook]
+      reviewer:
+        exclude_tags: [scaffolding]
+        pinned_budget: 5
+
+A profile is selected by, in order: 'mur context --profile', $MUR_PROFILE
+(e.g. exported in a hook), 'mur profile use' for today, context.profile.
+Profiles from installed starter kits are named <kit>/<profile> (see 'mur kit').
+
+Examples:
+  mur profile                  # List profiles and show the active one
+  mur profile use oncall       # Use oncall until midnight
+  mur profile use oncall --default
+  mur profile clear            # Back to context.profile`,
+	RunE: runProfileList,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List context profiles",
+	RunE:  runProfileList,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Use a context profile for the rest of the day",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfileUse,
+}
+
+var profileClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Stop using the profile chosen for today",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := inject.ClearProfileOverride(); err != nil {
+			return fmt.Errorf("cannot clear profile: %w", err)
+		}
+		fmt.Println("✓ Cleared today's profile")
+		return nil
+	},
+}
+
+var profileUseDefault bool
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileClearCmd)
+	profileUseCmd.Flags().BoolVar(&profileUseDefault, "default", false, "Save as context.profile instead of only for today")
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	names := inject.ProfileNames(cfg)
+	if len(names) == 0 {
+		fmt.Println("No context profiles configured.")
+		fmt.Println("Add them under context.profiles in config (see 'mur profile --help').")
+		return nil
+	}
+
+	active, err := inject.ResolveProfile(cfg, "")
+	if err != nil {
+		fmt.Printf("⚠ %v\n\n", err)
+	}
+
+	fmt.Println("Context profiles:")
+	for _, name := range names {
+		var p config.ContextProfile
+		if lp, err := inject.LookupProfile(cfg, name, ""); err == nil {
+			p = lp.ContextProfile
+		}
+		marker := " "
+		if active != nil && active.Name == name {
+			marker = "▶"
+		}
+		fmt.Printf("  %s %s", marker, name)
+		if p.Description != "" {
+			fmt.Printf(" - %s", p.Description)
+		}
+		fmt.Println()
+		if summary := profileSummary(p); summary != "" {
+			fmt.Printf("      %s\n", summary)
+		}
+	}
+
+	fmt.Println()
+	switch {
+	case active == nil:
+		fmt.Println("No profile active.")
+	case active.Source == "today":
+		o, _ := inject.ActiveProfileOverride(time.Now())
+		if o != nil {
+			fmt.Printf("Using %s until %s (mur profile use).\n", active.Name, o.Until.Format("2006-01-02 15:04"))
+		}
+	case active.Source == "env":
+		fmt.Printf("Using %s from $%s.\n", active.Name, inject.ProfileEnv)
+	default:
+		fmt.Printf("Using %s (context.profile).\n", active.Name)
+	}
+	return nil
+}
+
+// profileSummary describes what a profile changes, on one line.
+func profileSummary(p config.ContextProfile) string {
+	var parts []string
+	if len(p.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(p.Tags, ", "))
+	}
+	if len(p.ExcludeTags) > 0 {
+		parts = append(parts, "not: "+strings.Join(p.ExcludeTags, ", "))
+	}
+	if p.Max > 0 {
+		parts = append(parts, fmt.Sprintf("max %d", p.Max))
+	}
+	if p.PinnedBudget != 0 {
+		parts = append(parts, fmt.Sprintf("pinned budget %d", p.PinnedBudget))
+	}
+	if len(p.Pinned) > 0 {
+		parts = append(parts, "pins: "+strings.Join(p.Pinned, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func runProfileUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if _, err := inject.LookupProfile(cfg, name, ""); err != nil {
+		return err
+	}
+
+	if profileUseDefault {
+		cfg.Context.Profile = name
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+		fmt.Printf("✓ Default profile set to %s\n", name)
+		return nil
+	}
+
+	until := inject.EndOfDay(time.Now())
+	if err := inject.SetProfileOverride(name, until); err != nil {
+		return fmt.Errorf("cannot set profile: %w", err)
+	}
+	fmt.Printf("✓ Using profile %s until %s\n", name, until.Format("2006-01-02 15:04"))
+	if env := os.Getenv(inject.ProfileEnv); env != "" && env != name {
+		fmt.Printf("⚠ $%s=%s takes precedence in this shell\n", inject.ProfileEnv, env)
+	}
+	return nil
+}
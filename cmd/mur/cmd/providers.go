@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/router"
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Check the AI tools 'mur run' can route to",
+}
+
+var providersStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Probe enabled tools: installed, version, and flags",
+	Long: `Probe each enabled tool in config: is its binary on PATH, what version
+is it, and does its --help still list the flags mur runs it with (e.g.
+gemini -p)? Tools that fail are left out of routing until they pass.
+
+Probes are cached for 24 hours, and redone sooner when a tool's binary is
+upgraded or its flags change in config. 'mur run' uses the cache too.
+
+Examples:
+  mur providers status
+  mur providers status --refresh   # Probe again now
+  mur providers status --json`,
+	Args: cobra.NoArgs,
+	RunE: runProvidersStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+	providersCmd.AddCommand(providersStatusCmd)
+	providersStatusCmd.Flags().Bool("refresh", false, "Ignore cached probes")
+}
+
+func runProvidersStatus(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	out := newPrinter(cmd)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	probes := router.ProbeTools(cmd.Context(), cfg, refresh)
+	if out.JSON() {
+		if probes == nil {
+			probes = []router.Probe{}
+		}
+		return out.WriteJSON(probes)
+	}
+	if len(probes) == 0 {
+		out.Println("No tools enabled in config (tools: in ~/.mur/config.yaml)")
+		return nil
+	}
+
+	failing := 0
+	for _, p := range probes {
+		tier := ""
+		if tool, ok := cfg.GetTool(p.Tool); ok {
+			tier = tool.Tier
+		}
+		status := "ok"
+		if !p.OK {
+			status = "failing"
+			failing++
+		}
+		out.Record("provider", p.Tool, status, p.Version, p.Problem)
+
+		mark := out.Green("✓")
+		if !p.OK {
+			mark = out.Red("✗")
+		}
+		version := p.Version
+		if version == "" && p.OK {
+			version = out.Dim("version unknown")
+		}
+		out.Printf("%s %-10s %-5s %s\n", mark, p.Tool, tier, version)
+		if p.Problem != "" {
+			out.Printf("    %s\n", p.Problem)
+		}
+		if p.Note != "" {
+			out.Printf("    %s\n", out.Dim(p.Note))
+		}
+		out.Printf("    %s\n", out.Dim("probed "+formatAge(p.CheckedAt)))
+	}
+	out.Println()
+
+	if failing > 0 {
+		out.Printf("%d of %d tools are left out of routing. Install them, fix their flags in config, or disable them.\n", failing, len(probes))
+	} else {
+		out.Printf("All %d tools can be routed to.\n", len(probes))
+	}
+	return nil
+}
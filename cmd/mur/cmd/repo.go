@@ -1,9 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 var repoCmd = &cobra.Command{
@@ -72,8 +74,11 @@ func runRepoSet(cmd *cobra.Command, args []string) error {
 	if repoURL == "" {
 		return fmt.Errorf("repo URL is required")
 	}
+	if err := execx.CheckArg(repoURL); err != nil {
+		return fmt.Errorf("invalid repo URL: %w", err)
+	}
 
-	patternsDir := filepath.Join(home, ".mur", "repo")
+	patternsDir := filepath.Join(config.DataDir(home), "repo")
 
 	// Check if patterns dir exists and has content
 	if entries, err := os.ReadDir(patternsDir); err == nil && len(entries) > 0 {
@@ -82,11 +87,9 @@ func runRepoSet(cmd *cobra.Command, args []string) error {
 		if _, err := os.Stat(gitDir); err == nil {
 			// Already a git repo, update remote
 			fmt.Println("Updating remote origin...")
-			cmd := exec.Command("git", "-C", patternsDir, "remote", "set-url", "origin", repoURL)
-			if err := cmd.Run(); err != nil {
+			if _, err := execx.Run(context.Background(), "git", "-C", patternsDir, "remote", "set-url", "origin", repoURL); err != nil {
 				// Try adding remote instead
-				cmd = exec.Command("git", "-C", patternsDir, "remote", "add", "origin", repoURL)
-				_ = cmd.Run()
+				_, _ = execx.Run(context.Background(), "git", "-C", patternsDir, "remote", "add", "origin", repoURL)
 			}
 		} else {
 			// Has content but not a git repo - backup and clone
@@ -96,10 +99,7 @@ func runRepoSet(cmd *cobra.Command, args []string) error {
 
 			// Clone new repo
 			fmt.Println("Cloning repository...")
-			cmd := exec.Command("git", "clone", repoURL, patternsDir)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			if err := cmd.Run(); err != nil {
+			if err := cloneRepo(repoURL, patternsDir, true); err != nil {
 				// Restore backup on failure
 				_ = os.Rename(backupDir, patternsDir)
 				return fmt.Errorf("failed to clone: %w", err)
@@ -109,10 +109,7 @@ func runRepoSet(cmd *cobra.Command, args []string) error {
 		// Empty or doesn't exist - just clone
 		_ = os.MkdirAll(filepath.Dir(patternsDir), 0755)
 		fmt.Println("Cloning repository...")
-		cmd := exec.Command("git", "clone", repoURL, patternsDir)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
+		if err := cloneRepo(repoURL, patternsDir, true); err != nil {
 			return fmt.Errorf("failed to clone: %w", err)
 		}
 	}
@@ -137,7 +134,7 @@ func runRepoStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "repo")
+	patternsDir := filepath.Join(config.DataDir(home), "repo")
 	gitDir := filepath.Join(patternsDir, ".git")
 
 	// Check if it's a git repo
@@ -149,25 +146,23 @@ func runRepoStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get remote URL
-	remoteCmd := exec.Command("git", "-C", patternsDir, "remote", "get-url", "origin")
-	remote, err := remoteCmd.Output()
+	ctx := context.Background()
+	remote, err := execx.Run(ctx, "git", "-C", patternsDir, "remote", "get-url", "origin")
 	if err != nil {
 		fmt.Println("Learning repo: (local only, no remote)")
 	} else {
-		fmt.Printf("Learning repo: %s\n", strings.TrimSpace(string(remote)))
+		fmt.Printf("Learning repo: %s\n", strings.TrimSpace(remote.Stdout))
 	}
 
 	// Get current branch
-	branchCmd := exec.Command("git", "-C", patternsDir, "rev-parse", "--abbrev-ref", "HEAD")
-	branch, _ := branchCmd.Output()
-	fmt.Printf("Branch: %s\n", strings.TrimSpace(string(branch)))
+	branch, _ := execx.Run(ctx, "git", "-C", patternsDir, "rev-parse", "--abbrev-ref", "HEAD")
+	fmt.Printf("Branch: %s\n", strings.TrimSpace(branch.Stdout))
 
 	// Get status
-	statusCmd := exec.Command("git", "-C", patternsDir, "status", "--short")
-	status, _ := statusCmd.Output()
-	if len(status) > 0 {
+	status, _ := execx.Run(ctx, "git", "-C", patternsDir, "status", "--short")
+	if status.Stdout != "" {
 		fmt.Println("Changes:")
-		fmt.Print(string(status))
+		fmt.Print(status.Stdout)
 	} else {
 		fmt.Println("Status: Clean")
 	}
@@ -175,7 +170,7 @@ func runRepoStatus(cmd *cobra.Command, args []string) error {
 	// Count patterns
 	count := 0
 	_ = filepath.Walk(patternsDir, func(path string, info os.FileInfo, err error) error {
-		if err == nil && !info.IsDir() && strings.HasSuffix(path, ".yaml") {
+		if err == nil && !info.IsDir() && pattern.IsPatternFile(path) {
 			count++
 		}
 		return nil
@@ -191,7 +186,7 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "repo")
+	patternsDir := filepath.Join(config.DataDir(home), "repo")
 	gitDir := filepath.Join(patternsDir, ".git")
 
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
@@ -269,12 +264,15 @@ func SetupLearningRepo(home string) error {
 	}
 
 	// Clone the repo
-	patternsDir := filepath.Join(home, ".mur", "repo")
+	patternsDir := filepath.Join(config.DataDir(home), "repo")
 	_ = os.MkdirAll(filepath.Dir(patternsDir), 0755)
 
 	fmt.Println("  Cloning repository...")
-	cmd := exec.Command("git", "clone", repoURL, patternsDir)
-	if err := cmd.Run(); err != nil {
+	if err := execx.CheckArg(repoURL); err != nil {
+		fmt.Printf("  ⚠ Invalid repo URL: %v\n", err)
+		return nil
+	}
+	if err := cloneRepo(repoURL, patternsDir, false); err != nil {
 		fmt.Printf("  ⚠ Clone failed: %v\n", err)
 		return nil
 	}
@@ -285,3 +283,14 @@ func SetupLearningRepo(home string) error {
 	fmt.Println("  ✓ Learning repo configured")
 	return nil
 }
+
+// cloneRepo clones repoURL into dir, streaming git's progress when verbose.
+func cloneRepo(repoURL, dir string, verbose bool) error {
+	c := execx.Command("git", "clone", "--", repoURL, dir)
+	if verbose {
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+	}
+	_, err := c.Run(context.Background())
+	return err
+}
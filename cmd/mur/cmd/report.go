@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Export a learning progress report as static HTML",
+	Long: `Render patterns, usage stats, trends, and costs into a single
+self-contained HTML file (inline CSS, no server needed) that can be
+attached to a sprint review or shared by email. Periods in days or weeks
+are also compared with the period before (see 'mur stats compare').
+
+Examples:
+  mur report --output report.html               # Last 30 days
+  mur report -o sprint.html --period 14d        # Last two weeks
+  mur report -o q1.html --period 12w --open     # Last 12 weeks, then open
+  mur report -o all.html --period all           # Everything`,
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringP("output", "o", "report.html", "Output file path")
+	reportCmd.Flags().String("period", "30d", "Reporting period: Nd, Nw, a duration like 72h, or 'all'")
+	reportCmd.Flags().Bool("open", false, "Open in browser after generating")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	periodStr, _ := cmd.Flags().GetString("period")
+	open, _ := cmd.Flags().GetBool("open")
+
+	since, err := parseReportPeriod(periodStr)
+	if err != nil {
+		return err
+	}
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	patterns, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to load patterns: %w", err)
+	}
+
+	data := buildStaticDashboardData(patterns, since)
+	data.Period = "all time"
+	if !since.IsZero() {
+		loc := stats.DisplayLocation()
+		data.Period = fmt.Sprintf("%s – %s", since.In(loc).Format("2006-01-02"), time.Now().In(loc).Format("2006-01-02"))
+	}
+
+	// Compare with the period before, e.g. the 30 days before the last 30
+	if current, err := stats.ParsePeriod(periodStr, time.Now().In(stats.DisplayLocation())); err == nil {
+		if c, err := comparePeriods(current, current.Previous()); err == nil {
+			data.Comparison = &c
+		}
+	}
+
+	tmpl := template.Must(template.New("report").Funcs(staticDashboardFuncs()).Parse(staticDashboardHTML))
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	fmt.Printf("✓ Report saved to %s (%s, %d patterns, %d runs)\n", output, data.Period, data.TotalPatterns, data.TotalRuns)
+
+	if open {
+		abs, err := filepath.Abs(output)
+		if err != nil {
+			return err
+		}
+		path := filepath.ToSlash(abs)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path // C:/... on Windows
+		}
+		openBrowser((&url.URL{Scheme: "file", Path: path}).String())
+	}
+	return nil
+}
+
+// parseReportPeriod converts a period like "30d", "4w", "72h", or "all"
+// into the start of the reporting window. "all" returns the zero time.
+func parseReportPeriod(s string) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" || s == "all" {
+		return time.Time{}, nil
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && strings.HasSuffix(s, "d") && n > 0 {
+		return time.Now().AddDate(0, 0, -n), nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "w")); err == nil && strings.HasSuffix(s, "w") && n > 0 {
+		return time.Now().AddDate(0, 0, -7*n), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --period %q (use e.g. 30d, 4w, 72h, or all)", s)
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/review"
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review a change against your patterns",
+	Long: `Review a diff against the patterns you've learned: mur finds the
+patterns relevant to the changed files, asks the configured LLM where the
+change goes against them, and lists each finding with the pattern it cites.
+
+Without --diff or --pr, uncommitted changes (git diff HEAD) are reviewed.
+Exits 1 when a finding is an error, so it can gate CI.
+
+Examples:
+  mur review
+  mur review --diff HEAD~1
+  mur review --diff main...feature
+  mur review --pr 123              # Needs the gh CLI
+  mur review --diff HEAD~1 --json`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.Flags().String("diff", "", "Git revision or range to review (as for git diff)")
+	reviewCmd.Flags().Int("pr", 0, "GitHub pull request number to review")
+	reviewCmd.Flags().Int("limit", review.DefaultPatterns, "Most patterns to check against")
+	reviewCmd.Flags().String("provider", "", "LLM provider override (anthropic, openai, ollama, gemini)")
+	reviewCmd.Flags().String("model", "", "LLM model name override")
+	reviewCmd.Flags().String("ollama-url", "", "Ollama API URL override")
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	rev, _ := cmd.Flags().GetString("diff")
+	pr, _ := cmd.Flags().GetInt("pr")
+	limit, _ := cmd.Flags().GetInt("limit")
+	llmProvider, _ := cmd.Flags().GetString("provider")
+	llmModel, _ := cmd.Flags().GetString("model")
+	llmOllamaURL, _ := cmd.Flags().GetString("ollama-url")
+	out := newPrinter(cmd)
+	cmd.SilenceUsage = true
+
+	if rev != "" && pr > 0 {
+		return fmt.Errorf("--diff and --pr can't be combined")
+	}
+	diff, what, err := reviewDiff(cmd.Context(), rev, pr)
+	if err != nil {
+		return err
+	}
+	files := review.ParseDiff(diff)
+	if len(files) == 0 {
+		if out.JSON() {
+			return out.WriteJSON(&review.Result{Files: []review.FileDiff{}, Patterns: []review.Match{}, Findings: []review.Finding{}})
+		}
+		out.Printf("No changes to review in %s\n", what)
+		return nil
+	}
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	patterns, err := review.Relevant(store, files, limit)
+	if err != nil {
+		return fmt.Errorf("cannot load patterns: %w", err)
+	}
+	if len(patterns) == 0 {
+		if out.JSON() {
+			return out.WriteJSON(&review.Result{Files: files, Patterns: []review.Match{}, Findings: []review.Finding{}})
+		}
+		out.Printf("No patterns apply to the %d file(s) in %s; nothing to check against.\n", len(files), what)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	llm, err := session.NewLLMProviderWithOverrides(cfg, llmProvider, llmModel, llmOllamaURL)
+	if err != nil {
+		return fmt.Errorf("LLM setup: %w", err)
+	}
+	if !out.JSON() {
+		out.Printf("Reviewing %d file(s) in %s against %d pattern(s)...\n\n", len(files), what, len(patterns))
+	}
+	result, err := review.Review(llm, files, patterns)
+	if err != nil {
+		return err
+	}
+
+	if out.JSON() {
+		if err := out.WriteJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printReview(out, result)
+	}
+	if result.HasErrors() {
+		cmd.SilenceErrors = true
+		return &exitError{1, fmt.Errorf("review found errors")}
+	}
+	return nil
+}
+
+// reviewDiff returns the diff to review and how to describe it.
+func reviewDiff(ctx context.Context, rev string, pr int) (string, string, error) {
+	if pr > 0 {
+		res, err := execx.Run(ctx, "gh", "pr", "diff", strconv.Itoa(pr))
+		if err != nil {
+			return "", "", fmt.Errorf("cannot get the diff of PR #%d: %w", pr, err)
+		}
+		return res.Stdout, fmt.Sprintf("PR #%d", pr), nil
+	}
+	what := rev
+	if rev == "" {
+		rev, what = "HEAD", "uncommitted changes"
+	}
+	if err := execx.CheckArg(rev); err != nil {
+		return "", "", fmt.Errorf("invalid revision: %w", err)
+	}
+	res, err := execx.Run(ctx, "git", "diff", rev, "--")
+	if err != nil {
+		return "", "", fmt.Errorf("cannot get the diff of %s: %w", what, err)
+	}
+	return res.Stdout, what, nil
+}
+
+func printReview(out *output.Printer, result *review.Result) {
+	for _, f := range result.Findings {
+		out.Record("finding", f.File, strconv.Itoa(f.Line), f.Severity, f.Pattern, f.Message)
+		mark := out.Yellow("⚠")
+		switch f.Severity {
+		case review.SeverityError:
+			mark = out.Red("✗")
+		case review.SeverityInfo:
+			mark = out.Dim("•")
+		}
+		where := f.File
+		if f.Line > 0 {
+			where = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		out.Printf("%s %s %s\n", mark, where, out.Dim("["+f.Pattern+"]"))
+		out.Printf("    %s\n", f.Message)
+		if f.Suggestion != "" {
+			out.Printf("    %s %s\n", out.Dim("→"), f.Suggestion)
+		}
+	}
+	if len(result.Findings) > 0 {
+		out.Println()
+	}
+
+	counts := map[string]int{}
+	for _, f := range result.Findings {
+		counts[f.Severity]++
+	}
+	if len(result.Findings) == 0 {
+		out.Printf("%s No findings against %d pattern(s)\n", out.Green("✓"), len(result.Patterns))
+	} else {
+		out.Printf("%d error(s), %d warning(s), %d note(s) against %d pattern(s)\n",
+			counts[review.SeverityError], counts[review.SeverityWarning], counts[review.SeverityInfo], len(result.Patterns))
+	}
+	names := make([]string, len(result.Patterns))
+	for i, m := range result.Patterns {
+		names[i] = m.Name
+	}
+	out.Println(out.Dim("Checked: " + strings.Join(names, ", ")))
+}
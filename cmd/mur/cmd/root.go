@@ -1,7 +1,21 @@
 package cmd
 
 import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	murhooks "github.com/mur-run/mur-core/internal/hooks"
+	murlog "github.com/mur-run/mur-core/internal/log"
+	"github.com/mur-run/mur-core/internal/netpolicy"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/timing"
 )
 
 var rootCmd = &cobra.Command{
@@ -18,17 +32,131 @@ Quick start:
   mur stats             # View statistics
 
 Learn more: https://github.com/mur-run/mur-core`,
-	Version: Version,
+	Version:           Version,
+	PersistentPreRunE: initLog,
+}
+
+// initLog opens mur's log for the command about to run, at the level of
+// --log-level, $MUR_LOG_LEVEL or logging.level. Without a writable log the
+// command runs anyway and logs nothing.
+func initLog(cmd *cobra.Command, args []string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	cfg, _ := config.Load()
+	opts := murlog.OptionsFromConfig(home, cfg)
+
+	level, _ := cmd.Flags().GetString("log-level")
+	if level == "" {
+		level = os.Getenv("MUR_LOG_LEVEL")
+	}
+	if level != "" {
+		if opts.Level, err = murlog.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+	opts.Command = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if err := murlog.Init(opts); err != nil {
+		return nil
+	}
+	murlog.For("cli").Debug("run", "args", args)
+	return nil
 }
 
-// Execute runs the root command
+// timedCommand is the command 'mur debug timings' ran, which is what its
+// timings are recorded under.
+var timedCommand string
+
+// Execute runs the root command. With MUR_TIMINGS=1 it reports where the
+// time went on stderr and adds it to the timings log; see 'mur debug
+// timings'. Run from a hook installed by another mur release, it runs in
+// safe mode (see executeSafeMode). Every HTTP request is checked against
+// network.mode (see netpolicy).
 func Execute() error {
-	return rootCmd.Execute()
+	http.DefaultTransport = timing.Transport(http.DefaultTransport)
+	netpolicy.Install()
+	done := timing.Track("command")
+	start := time.Now()
+	command := rootCmd.Name()
+	var err error
+	if m := murhooks.CheckStamp(Version); m != nil {
+		err = executeSafeMode(m)
+	} else {
+		var c *cobra.Command
+		c, err = rootCmd.ExecuteC()
+		if c != nil {
+			command = c.CommandPath()
+		}
+	}
+	done()
+	if err != nil {
+		murlog.For("cli").Error("command failed", "err", err, "exit", ExitCode(err))
+	} else {
+		murlog.For("cli").Debug("done", "duration", time.Since(start).Round(time.Millisecond).String())
+	}
+	_ = murlog.Close()
+	if timing.Enabled() {
+		if timedCommand != "" {
+			command = timedCommand
+		}
+		timing.Report(os.Stderr)
+		if path, perr := timingsLogPath(); perr == nil {
+			_ = timing.AppendRun(path, timing.Record(command))
+		}
+	}
+	return err
+}
+
+// timingsLogPath returns the log of timed runs 'mur debug profile report'
+// summarizes.
+func timingsLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.StateDir(home), "timings.jsonl"), nil
+}
+
+// exitError makes the process exit with a specific code, for commands that
+// follow supervisor conventions (see internal/heartbeat).
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the process exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return 1
 }
 
 func init() {
 	rootCmd.SetVersionTemplate("mur version {{.Version}}\n")
+	murhooks.MurVersion = Version
 
-	// Global flags
+	// Global flags (see internal/output)
 	rootCmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only print errors")
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output (also NO_COLOR, CI)")
+	rootCmd.PersistentFlags().Bool("porcelain", false, "stable tab-separated output for scripts (learn list, sync, learn extract, cloud sync)")
+	rootCmd.PersistentFlags().Bool("json", false, "structured JSON output for scripts (list, show, and status commands)")
+	rootCmd.PersistentFlags().String("log-level", "", "level logged to ~/.mur/logs/mur.log: debug, info, warn, error (see mur logs)")
+}
+
+// newPrinter returns the output layer for cmd's global output flags.
+func newPrinter(cmd *cobra.Command) *output.Printer {
+	var opts output.Options
+	opts.Quiet, _ = cmd.Flags().GetBool("quiet")
+	opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+	opts.NoColor, _ = cmd.Flags().GetBool("no-color")
+	opts.Porcelain, _ = cmd.Flags().GetBool("porcelain")
+	opts.JSON, _ = cmd.Flags().GetBool("json")
+	return output.New(opts)
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/router"
+)
+
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Inspect routing decisions",
+	Long: `Inspect the routing decisions 'mur run' has made.
+
+Every run logs the decision with the features it was based on (prompt
+length, complexity, keywords, category, tool use), the tool chosen and
+why, any fallbacks, whether you overrode it with -t, and the outcome
+(estimated cost, duration, success). The prompt itself is never logged.`,
+}
+
+var routeStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize routing decisions",
+	Long: `Summarize logged routing decisions: how prompts were split between
+free and paid tools, how often you overrode the router, and how often it
+had to fall back.
+
+Examples:
+  mur route stats
+  mur route stats --days 7`,
+	RunE: runRouteStats,
+}
+
+var routeExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export anonymized routing decisions for analysis",
+	Long: `Export logged routing decisions as JSON lines or CSV.
+
+The export is anonymized: prompt hashes are dropped and times are rounded
+to the hour. Keywords are kept; they come from mur's fixed list of
+complexity keywords, not from your prompts.
+
+Examples:
+  mur route export > decisions.jsonl
+  mur route export --format csv -o decisions.csv
+  mur route export --days 30`,
+	RunE: runRouteExport,
+}
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+	routeCmd.AddCommand(routeStatsCmd)
+	routeCmd.AddCommand(routeExportCmd)
+	routeStatsCmd.Flags().IntP("days", "d", 30, "Number of days to analyze (0 for all)")
+	routeExportCmd.Flags().IntP("days", "d", 0, "Only decisions from the last N days (0 for all)")
+	routeExportCmd.Flags().String("format", "jsonl", "Output format: jsonl or csv")
+	routeExportCmd.Flags().StringP("output", "o", "", "Write to file instead of stdout")
+}
+
+// loadRouteDecisions loads the decisions from the last days days, or all.
+func loadRouteDecisions(days int) ([]router.Decision, error) {
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	return router.LoadDecisions(since)
+}
+
+func runRouteStats(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+
+	decisions, err := loadRouteDecisions(days)
+	if err != nil {
+		return err
+	}
+	if len(decisions) == 0 {
+		fmt.Println("No routing decisions logged yet.")
+		fmt.Println("Run `mur run -p \"your prompt\"` to start logging.")
+		return nil
+	}
+
+	s := router.SummarizeDecisions(decisions)
+	pct := func(n int) float64 { return float64(n) / float64(s.Total) * 100 }
+
+	fmt.Println("🔀 Routing Decisions")
+	fmt.Println("====================")
+	fmt.Println()
+	fmt.Printf("Decisions:  %d\n", s.Total)
+	fmt.Printf("Overridden: %d (%.0f%%)\n", s.Overrides, pct(s.Overrides))
+	fmt.Printf("Fallbacks:  %d (%.0f%%)\n", s.Fallbacks, pct(s.Fallbacks))
+	fmt.Printf("Failed:     %d (%.0f%%)\n", s.Failures, pct(s.Failures))
+	fmt.Printf("Est. cost:  $%.4f\n", s.TotalCost)
+	fmt.Println()
+
+	var tiers []string
+	for tier := range s.ByTier {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	fmt.Printf("  %-8s %6s %9s %15s %8s %10s\n", "Tier", "Runs", "Avg cx", "Complexity", "Success", "Cost")
+	for _, tier := range tiers {
+		t := s.ByTier[tier]
+		fmt.Printf("  %-8s %6d %9.2f %7.2f – %-5.2f %7.0f%% %10.4f\n",
+			tier, t.Count, t.AvgComplexity(), t.MinComplexity, t.MaxComplexity, t.SuccessRate(), t.CostEstimate)
+	}
+
+	if len(s.Overridden) > 0 {
+		fmt.Println()
+		fmt.Println("Overrides (routed → chosen):")
+		var keys []string
+		for k := range s.Overridden {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return s.Overridden[keys[i]] > s.Overridden[keys[j]] })
+		for _, k := range keys {
+			fmt.Printf("  %-24s %d\n", k, s.Overridden[k])
+		}
+	}
+	return nil
+}
+
+func runRouteExport(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+
+	decisions, err := loadRouteDecisions(days)
+	if err != nil {
+		return err
+	}
+	for i := range decisions {
+		decisions[i] = decisions[i].Anonymized()
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("cannot create %s: %w", output, err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := router.WriteDecisions(w, decisions, format); err != nil {
+		return err
+	}
+	if output != "" {
+		fmt.Fprintf(os.Stderr, "✓ Exported %d decisions to %s\n", len(decisions), output)
+	}
+	return nil
+}
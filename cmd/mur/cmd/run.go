@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -27,7 +29,10 @@ With routing.mode=auto (default), murmur selects the best tool based on
 prompt complexity. Simple questions use free tools; complex tasks use paid.
 
 Patterns are automatically injected based on project context and prompt analysis.
-Use --no-inject to disable pattern injection.
+Use --no-inject to disable pattern injection, or --context-from to choose
+the context yourself: patterns (by name or ID), installed kits, or files,
+injected as they are instead of searching. Prefix a name with pattern:,
+kit:, or file: if it is ambiguous.
 
 Use -t to override automatic selection.
 
@@ -36,7 +41,9 @@ Examples:
   mur run -p "refactor this module"      # Auto-routes to paid tool
   mur run -p "explain x" -t claude       # Force specific tool
   mur run -p "test" --explain            # Show routing decision only
-  mur run -p "fix bug" --no-inject       # Skip pattern injection`,
+  mur run -p "fix bug" --no-inject       # Skip pattern injection
+  mur run -p "add retries" --context-from go-retry,kit:backend
+  mur run -p "review" --context-from ./docs/STYLE.md`,
 	RunE: runExecute,
 }
 
@@ -47,6 +54,7 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	noInject, _ := cmd.Flags().GetBool("no-inject")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	timeoutStr, _ := cmd.Flags().GetString("timeout")
+	contextFrom, _ := cmd.Flags().GetStringSlice("context-from")
 
 	// --timeout: create context with deadline (no default = unlimited)
 	var ctx context.Context
@@ -65,6 +73,9 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	if prompt == "" {
 		return fmt.Errorf("prompt is required. Use -p \"your prompt\"")
 	}
+	if noInject && len(contextFrom) > 0 {
+		return fmt.Errorf("--context-from and --no-inject can't be used together")
+	}
 
 	// Load config
 	cfg, err := config.Load()
@@ -81,22 +92,41 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		workDir, _ := os.Getwd()
 
 		// Initialize pattern store
-		patternsDir := filepath.Join(os.Getenv("HOME"), ".mur", "patterns")
-		store := pattern.NewStore(patternsDir)
+		patternsDir := filepath.Join(config.DataDir(os.Getenv("HOME")), "patterns")
+		store := pattern.NewStore(patternsDir).WithProject(projectPatternsDir(workDir))
 
 		// Create injector and inject patterns
+		profile, err := inject.ResolveProfile(cfg, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+		}
 		injector := inject.NewInjector(store)
-
-		// Try to enable semantic search (non-fatal if it fails)
-		embedCfg := embed.DefaultConfig()
-		if err := injector.WithSemanticSearch(embedCfg); err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "⚠ Semantic search unavailable: %v\n", err)
+		injector.WithPinnedBudget(profile.PinnedBudget(cfg))
+		injector.WithProfile(profile)
+		injector.WithTechStack(cfg.GetTechStack())
+
+		if len(contextFrom) > 0 {
+			// Explicit selection: no search, and a bad reference is an error
+			selected, selectErr := resolveContextFrom(store, contextFrom)
+			if selectErr != nil {
+				return selectErr
 			}
-			// Fall back to keyword matching (built-in)
+			injectionResult, err = injector.InjectSelected(prompt, workDir, selected), nil
+			for _, b := range injectionResult.BlockedPatterns {
+				fmt.Fprintf(os.Stderr, "⚠ %s not injected: high injection risk\n", b.Name)
+			}
+			_ = inject.RecordExplanation(injectionResult.Explanation)
+		} else {
+			// Try to enable semantic search (non-fatal if it fails)
+			embedCfg := embed.SearchConfig(cfg)
+			if err := injector.WithSemanticSearch(embedCfg); err != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "⚠ Semantic search unavailable: %v\n", err)
+				}
+				// Fall back to keyword matching (built-in)
+			}
+			injectionResult, err = injector.Inject(prompt, workDir)
 		}
-
-		injectionResult, err = injector.Inject(prompt, workDir)
 		if err != nil {
 			// Non-fatal: warn but continue
 			if verbose {
@@ -122,24 +152,41 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	var tool string
 	var reason string
 	var complexity float64
+	var decision router.Decision
 	autoRouted := forceTool == ""
 
+	// Tools that are missing or no longer take their configured flags are
+	// left out of routing (probes are cached; see mur providers status)
+	failing := router.FailingTools(router.ProbeTools(ctx, cfg, false))
+
 	if forceTool != "" {
 		// User explicitly chose a tool
 		tool = forceTool
 		reason = "user specified with -t flag"
-		// Still analyze for stats
-		analysis := router.AnalyzePrompt(prompt)
-		complexity = analysis.Complexity
+		if why, ok := failing[tool]; ok {
+			fmt.Fprintf(os.Stderr, "⚠ %s failed its probe: %s (see: mur providers status)\n", tool, why)
+		}
+		// Still route, so the decision log records what was overridden
+		selection, _ := router.SelectToolExcluding(prompt, cfg, failing)
+		decision = router.NewDecision(prompt, selection)
+		decision.Override = true
+		complexity = decision.Complexity
 	} else {
 		// Use router
-		selection, err := router.SelectTool(prompt, cfg)
+		selection, err := router.SelectToolExcluding(prompt, cfg, failing)
 		if err != nil {
 			return fmt.Errorf("routing failed: %w", err)
 		}
+		// Say so when a failing tool would have been chosen
+		if routed, err := router.SelectTool(prompt, cfg); err == nil {
+			if why, ok := failing[routed.Tool]; ok {
+				fmt.Fprintf(os.Stderr, "⚠ Skipping %s: %s; using %s (see: mur providers status)\n", routed.Tool, why, selection.Tool)
+			}
+		}
 		tool = selection.Tool
 		reason = selection.Reason
 		complexity = selection.Analysis.Complexity
+		decision = router.NewDecision(prompt, selection)
 
 		if explain {
 			// Show decision and exit
@@ -173,6 +220,14 @@ func runExecute(cmd *cobra.Command, args []string) error {
 			if selection.Fallback != "" {
 				fmt.Printf("Fallback:   %s\n", selection.Fallback)
 			}
+			if len(selection.Excluded) > 0 {
+				names := make([]string, 0, len(selection.Excluded))
+				for name := range selection.Excluded {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				fmt.Printf("Excluded:   %s (failed probes)\n", strings.Join(names, ", "))
+			}
 			return nil
 		}
 	}
@@ -211,7 +266,7 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	duration := time.Since(startTime)
 
 	// Record stats (ignore errors - stats are non-critical)
-	_ = stats.Record(stats.UsageRecord{
+	record := stats.UsageRecord{
 		Tool:         tool,
 		Timestamp:    startTime,
 		PromptLength: len(prompt),
@@ -222,14 +277,38 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		AutoRouted:   autoRouted,
 		Complexity:   complexity,
 		Success:      runErr == nil,
-	})
+		InputTokens:  stats.EstimateTokens(finalPrompt),
+	}
+	if finalPrompt != prompt && injectionResult != nil {
+		record.PatternsInjected = len(injectionResult.Patterns)
+		record.InjectedTokens = record.InputTokens - stats.EstimateTokens(prompt)
+	}
+	_ = stats.Record(record)
+
+	// Log the routing decision with its features for later tuning
+	decision.Timestamp = startTime
+	decision.Tool = tool
+	decision.Tier = toolCfg.Tier
+	decision.PatternsInjected = record.PatternsInjected
+	decision.InputTokens = record.InputTokens
+	decision.CostEstimate = record.CostEstimate
+	decision.DurationMs = record.DurationMs
+	decision.Success = record.Success
+	_ = router.RecordDecision(decision)
 
 	// Track pattern usage for effectiveness learning
 	if injectionResult != nil && len(injectionResult.Patterns) > 0 {
-		trackingDir := filepath.Join(os.Getenv("HOME"), ".mur", "tracking")
-		patternsDir := filepath.Join(os.Getenv("HOME"), ".mur", "patterns")
+		trackingDir := filepath.Join(config.StateDir(os.Getenv("HOME")), "tracking")
+		patternsDir := filepath.Join(config.DataDir(os.Getenv("HOME")), "patterns")
 		tracker := inject.NewTracker(pattern.NewStore(patternsDir), trackingDir)
-		_ = tracker.RecordUsage(injectionResult.Patterns, injectionResult.Context, prompt, runErr == nil)
+		// Selected files and kit patterns you don't have aren't tracked
+		var tracked []*pattern.Pattern
+		for _, p := range injectionResult.Patterns {
+			if !injectionResult.Manual || p.ID != "" {
+				tracked = append(tracked, p)
+			}
+		}
+		_ = tracker.RecordUsage(tracked, injectionResult.Context, prompt, runErr == nil, injectionResult.Manual)
 	}
 
 	return runErr
@@ -251,4 +330,5 @@ func init() {
 	runCmd.Flags().Bool("no-inject", false, "Disable automatic pattern injection")
 	runCmd.Flags().BoolP("verbose", "V", false, "Show pattern injection details")
 	runCmd.Flags().String("timeout", "", "Timeout duration (e.g. '30s', '5m'). Default: unlimited")
+	runCmd.Flags().StringSlice("context-from", nil, "Inject these patterns, kits, or files instead of searching (repeatable)")
 }
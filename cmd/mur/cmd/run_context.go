@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/kit"
+)
+
+// resolveContextFrom resolves 'mur run --context-from' references to the
+// patterns to inject, in order and without duplicates. A reference is a
+// pattern name or ID, an installed kit (its patterns), or a file (its
+// contents); prefix it with pattern:, kit:, or file: when a name is
+// ambiguous.
+func resolveContextFrom(store *pattern.Store, refs []string) ([]*pattern.Pattern, error) {
+	var patterns []*pattern.Pattern
+	seen := make(map[string]bool)
+	add := func(ps ...*pattern.Pattern) {
+		for _, p := range ps {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				patterns = append(patterns, p)
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		kind, name, ok := strings.Cut(ref, ":")
+		if !ok || (kind != "pattern" && kind != "kit" && kind != "file") {
+			kind, name = "", ref
+		}
+
+		switch kind {
+		case "pattern":
+			p, err := store.Resolve(name)
+			if err != nil {
+				return nil, err
+			}
+			add(p)
+		case "kit":
+			ps, err := kitContext(store, name)
+			if err != nil {
+				return nil, err
+			}
+			add(ps...)
+		case "file":
+			p, err := fileContext(name)
+			if err != nil {
+				return nil, err
+			}
+			add(p)
+		default:
+			if info, err := os.Stat(name); err == nil && !info.IsDir() {
+				p, err := fileContext(name)
+				if err != nil {
+					return nil, err
+				}
+				add(p)
+			} else if p, err := store.Resolve(name); err == nil {
+				add(p)
+			} else if ps, err := kitContext(store, name); err == nil {
+				add(ps...)
+			} else {
+				return nil, fmt.Errorf("--context-from %s: no pattern, installed kit, or file by that name", name)
+			}
+		}
+	}
+	return patterns, nil
+}
+
+// kitContext returns the patterns of an installed kit: your copy of each
+// where you have one, else the kit's own, which isn't trusted until
+// reviewed.
+func kitContext(store *pattern.Store, name string) ([]*pattern.Pattern, error) {
+	ik, err := kit.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(ik.Kit.Patterns) == 0 {
+		return nil, fmt.Errorf("kit %s has no patterns", name)
+	}
+	var patterns []*pattern.Pattern
+	for _, kp := range ik.Kit.Patterns {
+		if p, err := store.Get(kp.Name); err == nil {
+			patterns = append(patterns, p)
+			continue
+		}
+		p := &pattern.Pattern{Name: kp.Name, Description: kp.Description, Content: kp.Content}
+		p.Security.TrustLevel = pattern.TrustCommunity
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// fileContext returns a file's contents as a pattern named after it.
+func fileContext(path string) (*pattern.Pattern, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--context-from: %w", err)
+	}
+	p := &pattern.Pattern{Name: filepath.Base(path), Content: strings.TrimSpace(string(data))}
+	p.Security.TrustLevel = pattern.TrustOwner
+	return p, nil
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,6 +16,9 @@ import (
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/analytics"
 	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	murlog "github.com/mur-run/mur-core/internal/log"
 )
 
 var searchCmd = &cobra.Command{
@@ -24,13 +29,24 @@ var searchCmd = &cobra.Command{
 By default, searches local patterns. Use --community to also search
 community patterns from mur.run.
 
+If the embedding provider is down (e.g. Ollama stopped), local search
+falls back to the last result for the same prompt, or to keyword and tag
+matches, with a one-line notice that results are degraded.
+
+--hybrid adds full-text (BM25) matching over pattern names, tags, and
+content, so exact identifiers like error codes and function names are
+found even when their meaning is far from the query. search.hybrid sets
+the keyword weight and can make hybrid the default.
+
 Examples:
   mur search "Swift async testing"           # Local only
   mur search --community "API retry"         # Local + community
   mur search --community-only "error handling"  # Community only
   mur search --top 5 "Docker best practices"
+  mur search --hybrid "ECONNREFUSED"         # Keyword + semantic
   mur search --json "database optimization"
-  mur search --inject "$PROMPT"              # For hooks`,
+  mur search --inject "$PROMPT"              # For hooks
+  mur search --inject --budget 300 "$PROMPT" # At most ~300 tokens`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSearch,
 }
@@ -42,6 +58,11 @@ var (
 	searchCommunity     bool
 	searchCommunityOnly bool
 	searchLocalOnly     bool
+	searchFormat        string
+	searchTarget        string
+	searchProfile       string
+	searchHybrid        bool
+	searchBudget        int
 )
 
 func init() {
@@ -52,6 +73,11 @@ func init() {
 	searchCmd.Flags().BoolVar(&searchCommunity, "community", false, "Also search community patterns")
 	searchCmd.Flags().BoolVar(&searchCommunityOnly, "community-only", false, "Only search community patterns")
 	searchCmd.Flags().BoolVar(&searchLocalOnly, "local", false, "Only search local patterns (default)")
+	searchCmd.Flags().StringVar(&searchFormat, "format", "", "Inject output format: text, markdown, xml, claude-skill, or a custom template name")
+	searchCmd.Flags().StringVar(&searchTarget, "target", "", "Inject target (e.g. claude, cursor); selects context.targets.<target> from config")
+	searchCmd.Flags().BoolVar(&searchHybrid, "hybrid", false, "Combine keyword (BM25) and semantic matches (default: search.hybrid.enabled)")
+	searchCmd.Flags().IntVar(&searchBudget, "budget", 0, "Inject at most this many tokens (default: search.max_inject_tokens, 0 for no limit)")
+	searchCmd.Flags().StringVar(&searchProfile, "profile", "", "Inject with a context profile (default: $MUR_PROFILE, then mur profile use, then context.profile)")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -62,8 +88,20 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Context profiles only shape what hooks inject
+	var profile *inject.Profile
+	if searchInject {
+		if profile, err = inject.ResolveProfile(cfg, searchProfile); err != nil {
+			fmt.Fprintf(os.Stderr, "[mur] ⚠ %v\n", err)
+			murlog.For("search").Warn("cannot resolve context profile", "profile", searchProfile, "err", err)
+		}
+	}
+
 	// Use config default if not specified
 	topK := searchTopK
+	if topK == 0 && profile != nil {
+		topK = profile.Max
+	}
 	if topK == 0 {
 		topK = cfg.Search.TopK
 	}
@@ -73,20 +111,37 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	var localMatches []embed.PatternMatch
 	var communityResults []cloud.CommunityPattern
+	method, notice := "semantic", ""
 
 	// Search local patterns (unless community-only)
-	if !searchCommunityOnly {
+	hybrid := searchHybrid || cfg.Search.Hybrid.Enabled
+	if !searchCommunityOnly && hybrid {
+		localMatches, method, notice = hybridSearch(cfg, query, topK)
+	} else if !searchCommunityOnly {
 		if cfg.Search.IsEnabled() {
 			indexer, err := embed.NewPatternIndexer(cfg)
-			if err == nil {
-				status := indexer.Status()
-				if status.IndexedCount > 0 {
-					localMatches, _ = indexer.Search(query, topK)
+			if err == nil && indexer.HasEmbeddings() {
+				localMatches, err = indexer.Search(query, topK)
+				var mismatch *embed.IndexMismatchError
+				if errors.As(err, &mismatch) {
+					fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+				}
+				if err != nil {
+					murlog.For("search").Warn("semantic search failed, degrading", "err", err)
+				}
+				if err != nil {
+					localMatches, method, notice = degradedSearch(cfg, query, topK)
+				} else if searchInject {
+					_ = inject.LoadResultCache().Put(query, localMatches)
 				}
 			}
 		}
 	}
 
+	if !searchCommunityOnly {
+		localMatches = mergeProjectMatches(localMatches, query, topK)
+	}
+
 	// Search community (if requested)
 	if searchCommunity || searchCommunityOnly {
 		client, err := cloud.NewClient(cfg.Server.URL)
@@ -103,7 +158,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	// Record analytics for local matches
 	if len(localMatches) > 0 {
 		home, _ := os.UserHomeDir()
-		tracker := analytics.NewTracker(filepath.Join(home, ".mur"))
+		tracker := analytics.NewTracker(config.DataDir(home))
 		for _, m := range localMatches {
 			if m.Score >= cfg.Search.MinScore {
 				_ = tracker.RecordSearch(m.Pattern.ID, m.Pattern.Name, m.Score, query)
@@ -113,26 +168,99 @@ func runSearch(cmd *cobra.Command, args []string) error {
 
 	// Inject mode - output to stderr for hooks
 	if searchInject {
-		if len(localMatches) == 0 && len(communityResults) == 0 {
+		ex := inject.NewExplanation("search --inject")
+		ex.Target = searchTarget
+		ex.Session = os.Getenv("MUR_SESSION_ID")
+		ex.Method = method
+		ex.Max = topK
+		ex.Pinned = profile.PinnedBudget(cfg)
+		if profile != nil {
+			ex.Profile = profile.Name
+		}
+		ex.SetPrompt(query)
+		var projectCtx *inject.ProjectContext
+		if cwd, err := os.Getwd(); err == nil {
+			projectCtx = inject.DetectProject(cwd)
+			ex.SetProject(projectCtx)
+		}
+		defer func() { _ = inject.RecordExplanation(ex) }()
+
+		// Leave out patterns scoped to other projects or languages, or
+		// that the profile doesn't want
+		target := projectCtx.Target()
+		pinned, overBudget := pinnedForInject(cfg, target, profile, ex)
+		localMatches = slices.DeleteFunc(localMatches, func(m embed.PatternMatch) bool {
+			switch {
+			case !m.Pattern.AppliesTo(target):
+				ex.Add(inject.Decision{Name: m.Pattern.Name, Score: m.Score, Reason: inject.ReasonNotApplicable})
+			case !profile.Allows(m.Pattern):
+				ex.Add(inject.Decision{Name: m.Pattern.Name, Score: m.Score, Reason: inject.ReasonProfile})
+			default:
+				return false
+			}
+			return true
+		})
+
+		if len(pinned) == 0 && len(localMatches) == 0 && len(communityResults) == 0 {
 			return nil
 		}
 
-		var names []string
+		data := inject.FormatData{Compact: true}
+		isPinned := make(map[string]bool, len(pinned))
+		for i := range pinned {
+			p := &pinned[i]
+			isPinned[p.Name] = true
+			data.Patterns = append(data.Patterns, inject.FormatPattern{Name: p.Name, Description: p.Description, Pinned: true})
+			ex.Add(inject.Decision{Name: p.Name, Injected: true, Pinned: true, Reason: inject.ReasonPinned,
+				Matched: inject.MatchReasons(p, projectCtx, query)})
+		}
+		for i := range overBudget {
+			ex.Add(inject.Decision{Name: overBudget[i].Name, Pinned: true, Reason: inject.ReasonPinnedBudget})
+		}
 		for _, m := range localMatches {
-			names = append(names, m.Pattern.Name)
+			if isPinned[m.Pattern.Name] {
+				continue
+			}
+			data.Patterns = append(data.Patterns, inject.FormatPattern{Name: m.Pattern.Name, Description: m.Pattern.Description})
+			ex.Add(inject.Decision{Name: m.Pattern.Name, Injected: true, Score: m.Score, Reason: inject.ReasonRelevant,
+				Matched: inject.MatchReasons(m.Pattern, projectCtx, query)})
 		}
 		for _, c := range communityResults {
-			names = append(names, c.Name+" 🌐")
+			ex.Add(inject.Decision{Name: c.Name, Injected: true, Reason: "community match"})
+			data.Patterns = append(data.Patterns, inject.FormatPattern{Name: c.Name, Description: c.Description, Community: true})
 		}
 
-		hint := fmt.Sprintf("[mur] 🎯 Relevant patterns: %s\n", strings.Join(names, ", "))
 		if len(localMatches) > 0 {
-			hint += fmt.Sprintf("[mur] 💡 Consider loading /%s for this task\n", getSkillPath(localMatches[0]))
+			data.Hints = append(data.Hints, fmt.Sprintf("Consider loading /%s for this task", getSkillPath(localMatches[0])))
 		}
 		if len(communityResults) > 0 && len(localMatches) == 0 {
-			hint += fmt.Sprintf("[mur] 💡 Community pattern available: mur community copy \"%s\"\n", communityResults[0].Name)
+			data.Hints = append(data.Hints, fmt.Sprintf("Community pattern available: mur community copy \"%s\"", communityResults[0].Name))
 		}
 
+		format := inject.ResolveFormat(cfg, searchFormat, searchTarget)
+		if notice != "" && format != inject.FormatText {
+			data.Hints = append(data.Hints, notice)
+		}
+		render := func(d inject.FormatData) (string, error) {
+			if format != inject.FormatText {
+				return inject.Render(format, d)
+			}
+			hint := fmt.Sprintf("[mur] 🎯 Relevant patterns: %s\n", strings.Join(d.Names(), ", "))
+			for _, h := range d.Hints {
+				hint += "[mur] 💡 " + h + "\n"
+			}
+			if notice != "" {
+				hint += "[mur] ⚠ " + notice + "\n"
+			}
+			return hint, nil
+		}
+		budget := inject.TokenBudget(cfg, searchBudget)
+		hint, fit, err := inject.FitBudget(data, budget, render)
+		if err != nil {
+			return err
+		}
+		ex.SetBudget(budget, fit)
+
 		// Cache community patterns for future use
 		if len(communityResults) > 0 {
 			go cacheCommunityPatterns(cfg, communityResults)
@@ -150,13 +278,20 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		}
 		localOut := output["local"].([]map[string]interface{})
 		for i, m := range localMatches {
+			source := "local"
+			if m.Pattern.Scope == pattern.ScopeProject {
+				source = "project"
+			}
 			localOut[i] = map[string]interface{}{
 				"name":        m.Pattern.Name,
 				"description": m.Pattern.Description,
 				"score":       m.Score,
-				"source":      "local",
+				"source":      source,
 			}
 		}
+		if notice != "" {
+			output["notice"] = notice
+		}
 		communityOut := output["community"].([]map[string]interface{})
 		for i, c := range communityResults {
 			communityOut[i] = map[string]interface{}{
@@ -174,11 +309,18 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	// Pretty print
 	fmt.Println("🔍 Searching patterns...")
 	fmt.Println()
+	if notice != "" {
+		fmt.Printf("⚠ %s\n\n", notice)
+	}
 
 	if len(localMatches) > 0 {
 		fmt.Println("📍 Local patterns:")
 		for i, m := range localMatches {
-			fmt.Printf("  %d. %s (%.2f)\n", i+1, m.Pattern.Name, m.Score)
+			scope := ""
+			if m.Pattern.Scope == pattern.ScopeProject {
+				scope = " [project]"
+			}
+			fmt.Printf("  %d. %s (%.2f)%s\n", i+1, m.Pattern.Name, m.Score, scope)
 			if m.Pattern.Description != "" {
 				desc := m.Pattern.Description
 				if len(desc) > 60 {
@@ -224,6 +366,143 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// mergeProjectMatches adds keyword matches for query from the patterns of
+// the repository the current directory is in, if it's trusted (see
+// projectPatternsDir), which aren't in the embedding index, to matches. They hide global
+// patterns of the same name. The best topK are kept.
+func mergeProjectMatches(matches []embed.PatternMatch, query string, topK int) []embed.PatternMatch {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return matches
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return matches
+	}
+	project := store.WithProject(projectPatternsDir(cwd)).ListProject()
+	if len(project) == 0 {
+		return matches
+	}
+	shadowed := make(map[string]bool, len(project))
+	for _, p := range project {
+		shadowed[p.Name] = true
+	}
+
+	merged := embed.NewKeywordIndex(project).Search(query, topK)
+	for _, m := range matches {
+		if !shadowed[m.Pattern.Name] {
+			merged = append(merged, m)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	if len(merged) > topK {
+		merged = merged[:topK]
+	}
+	return merged
+}
+
+// degradedSearch finds local patterns for query when semantic search
+// failed, e.g. because Ollama is stopped: the last good result for the
+// same prompt, otherwise keyword and tag matches. It returns the search
+// method used and a one-line notice saying results are degraded.
+func degradedSearch(cfg *config.Config, query string, topK int) ([]embed.PatternMatch, string, string) {
+	provider := cfg.Search.Provider
+	if provider == "" {
+		provider = "ollama"
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, "keyword", ""
+	}
+
+	if r, ok := inject.LoadResultCache().Get(query); ok {
+		if matches := r.Matches(store); len(matches) > 0 {
+			if len(matches) > topK {
+				matches = matches[:topK]
+			}
+			return matches, "cached", fmt.Sprintf("Semantic search unavailable (%s embeddings failed); using cached results for this prompt", provider)
+		}
+	}
+	patterns, _ := store.List()
+	matches := inject.KeywordSearch(patterns, query, topK)
+	return matches, "keyword", fmt.Sprintf("Semantic search unavailable (%s embeddings failed); using keyword matches", provider)
+}
+
+// hybridSearch finds local patterns for query by keyword and, when the
+// embedding index is usable, semantic similarity. It returns the search
+// method used and a notice when semantic matching was left out.
+func hybridSearch(cfg *config.Config, query string, topK int) ([]embed.PatternMatch, string, string) {
+	if !cfg.Search.IsEnabled() {
+		store, err := pattern.DefaultStore()
+		if err != nil {
+			return nil, "keyword", ""
+		}
+		patterns, _ := store.List()
+		return embed.NewKeywordIndex(patterns).Search(query, topK), "keyword", ""
+	}
+	indexer, err := embed.NewPatternIndexer(cfg)
+	if err != nil {
+		return nil, "keyword", ""
+	}
+	matches, err := indexer.HybridSearch(query, topK)
+	var mismatch *embed.IndexMismatchError
+	switch {
+	case errors.As(err, &mismatch):
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+		murlog.For("search").Warn("index mismatch, using keyword matches", "err", err)
+		return matches, "keyword", "Semantic search unavailable (index mismatch); using keyword matches"
+	case err != nil:
+		provider := cfg.Search.Provider
+		if provider == "" {
+			provider = "ollama"
+		}
+		return matches, "keyword", fmt.Sprintf("Semantic search unavailable (%s embeddings failed); using keyword matches", provider)
+	case !indexer.HasEmbeddings():
+		return matches, "keyword", ""
+	}
+	return matches, "hybrid", ""
+}
+
+// pinnedForInject returns the pinned patterns to include in inject mode,
+// including the profile's, limited to the pinned budget, and the ones the
+// budget left out. Pinned patterns that don't apply to target or that the
+// profile leaves out are recorded in ex.
+func pinnedForInject(cfg *config.Config, target pattern.Target, profile *inject.Profile, ex *inject.Explanation) (pinned, overBudget []pattern.Pattern) {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, nil
+	}
+	all, err := store.GetPinned()
+	if err != nil {
+		return nil, nil
+	}
+	if profile != nil {
+		for _, name := range profile.Pinned {
+			if p, err := store.Get(name); err == nil && p.IsActive() && !p.Pinned {
+				p.Pinned = true
+				all = append(all, *p)
+			}
+		}
+	}
+	for _, p := range all {
+		switch {
+		case !p.AppliesTo(target):
+			ex.Add(inject.Decision{Name: p.Name, Pinned: true, Reason: inject.ReasonNotApplicable})
+		case !profile.Allows(&p):
+			ex.Add(inject.Decision{Name: p.Name, Pinned: true, Reason: inject.ReasonProfile})
+		default:
+			pinned = append(pinned, p)
+		}
+	}
+	budget := max(profile.PinnedBudget(cfg), 0)
+	if len(pinned) > budget {
+		return pinned[:budget], pinned[budget:]
+	}
+	return pinned, nil
+}
+
 // getSkillPath returns the skill directory path for a pattern.
 func getSkillPath(m embed.PatternMatch) string {
 	domain := m.Pattern.GetPrimaryDomain()
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/heartbeat"
+	"github.com/mur-run/mur-core/internal/kit"
+	"github.com/mur-run/mur-core/internal/security"
+	"github.com/mur-run/mur-core/internal/server"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Share a single pattern with a read-only link",
+	Long: `Share one pattern with someone who doesn't use mur, as an expiring
+read-only web page served by 'mur serve'.
+
+The link carries its own token, so it works without the dashboard's
+--auth token; everything else on the server stays protected. The pattern
+is captured when the link is created, with the same PII redaction as
+community sharing; patterns that still contain secrets are refused.
+Every access is logged to share-access.log in mur's state directory.
+
+For a colleague on another machine, the server must be reachable:
+  mur serve --auth --bind 0.0.0.0
+
+Examples:
+  mur share link retry-with-backoff
+  mur share link retry-with-backoff --expires 2h --base-url http://10.0.0.5:8742
+  mur share list
+  mur share revoke retry-with-backoff`,
+}
+
+var shareLinkCmd = &cobra.Command{
+	Use:   "link <name>",
+	Short: "Create an expiring read-only link to a pattern",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runShareLink,
+}
+
+var shareListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List share links",
+	RunE:  runShareList,
+}
+
+var shareRevokeCmd = &cobra.Command{
+	Use:   "revoke <token|name>",
+	Short: "Revoke a link by token (or its first 8+ characters), or all links to a pattern",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runShareRevoke,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+	shareCmd.AddCommand(shareLinkCmd)
+	shareCmd.AddCommand(shareListCmd)
+	shareCmd.AddCommand(shareRevokeCmd)
+	shareLinkCmd.Flags().String("expires", "24h", "How long the link works, e.g. 2h, 7d (at most 30d)")
+	shareLinkCmd.Flags().String("base-url", "", "Server URL in the link (default: the running 'mur serve', else http://localhost:8742)")
+	shareListCmd.Flags().Bool("all", false, "Include expired and revoked links")
+}
+
+// maxShareExpiry bounds how long a share link can stay valid.
+const maxShareExpiry = 30 * 24 * time.Hour
+
+func shareStore() (*server.ShareStore, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return server.NewShareStore(config.StateDir(home)), nil
+}
+
+// shareBaseURL returns the address of the running 'mur serve', or its
+// default one.
+func shareBaseURL() string {
+	if b, err := heartbeat.Read(heartbeat.ProcessServe); err == nil && b != nil && b.Addr != "" && b.Status == heartbeat.StatusOK {
+		return b.Addr
+	}
+	return "http://localhost:8742"
+}
+
+func runShareLink(cmd *cobra.Command, args []string) error {
+	out := newPrinter(cmd)
+	expiresStr, _ := cmd.Flags().GetString("expires")
+	baseURL, _ := cmd.Flags().GetString("base-url")
+
+	expires, err := pattern.ParseAge(expiresStr)
+	if err != nil {
+		return err
+	}
+	if expires <= 0 || expires > maxShareExpiry {
+		return fmt.Errorf("--expires must be between 1m and 30d, got %s", expiresStr)
+	}
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	p, err := store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// The page leaves the machine, so it gets the same redaction as
+	// community sharing.
+	pii := security.NewPIIScanner(kit.Privacy(cfg.Privacy))
+	content, findings := pii.ScanAndRedact(p.Content)
+	desc, descFindings := pii.ScanAndRedact(p.Description)
+	if result := security.NewScanner().ScanContent(desc + "\n" + content); !result.Safe {
+		for _, f := range result.Findings {
+			out.Warnf("line %d: %s (%s)\n", f.Line, f.Message, f.Match)
+		}
+		return fmt.Errorf("%s contains secrets; remove them before sharing", p.Name)
+	}
+
+	tags := append([]string{}, p.Tags.Confirmed...)
+	for _, ts := range p.Tags.Inferred {
+		tags = append(tags, ts.Tag)
+	}
+
+	shares, err := shareStore()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	link, err := shares.Create(server.ShareLink{
+		Pattern:     p.Name,
+		PatternID:   p.ID,
+		Description: desc,
+		Content:     content,
+		Tags:        tags,
+		Redactions:  len(findings) + len(descFindings),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(expires),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save share link: %w", err)
+	}
+
+	if baseURL == "" {
+		baseURL = shareBaseURL()
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/s/" + link.Token
+
+	if out.JSON() {
+		return out.WriteJSON(struct {
+			server.ShareLink
+			URL string `json:"url"`
+		}{link, url})
+	}
+	if out.Porcelain() {
+		out.Record("link", link.Pattern, url, link.ExpiresAt.UTC().Format(time.RFC3339))
+		return nil
+	}
+
+	out.Printf("🔗 %s\n", url)
+	out.Printf("   %s, read-only, expires %s\n", link.Pattern, link.ExpiresAt.Format("2006-01-02 15:04"))
+	if link.Redactions > 0 {
+		out.Printf("   🔒 %d PII items redacted\n", link.Redactions)
+	}
+	if b, _ := heartbeat.Read(heartbeat.ProcessServe); b == nil || b.Status != heartbeat.StatusOK {
+		out.Warnf("'mur serve' isn't running; the link works once it is\n")
+	}
+	out.Printf("   Revoke with: mur share revoke %s\n", link.Token[:8])
+	return nil
+}
+
+func runShareList(cmd *cobra.Command, args []string) error {
+	out := newPrinter(cmd)
+	all, _ := cmd.Flags().GetBool("all")
+
+	shares, err := shareStore()
+	if err != nil {
+		return err
+	}
+	links, err := shares.List()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	shown := []server.ShareLink{}
+	for _, l := range links {
+		if all || l.Active(now) {
+			shown = append(shown, l)
+		}
+	}
+
+	if out.JSON() {
+		return out.WriteJSON(shown)
+	}
+	if len(shown) == 0 {
+		out.Println("No active share links.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out.Out(), 0, 0, 2, ' ', 0)
+	if !out.Porcelain() {
+		fmt.Fprintln(w, "TOKEN\tPATTERN\tEXPIRES\tVIEWS\tSTATUS")
+	}
+	for _, l := range shown {
+		status := "active"
+		switch {
+		case l.RevokedAt != nil:
+			status = "revoked"
+		case !l.Active(now):
+			status = "expired"
+		}
+		if out.Porcelain() {
+			out.Record("link", l.Token[:8], l.Pattern, l.ExpiresAt.UTC().Format(time.RFC3339), fmt.Sprint(l.Views), status)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", l.Token[:8], l.Pattern, l.ExpiresAt.Format("2006-01-02 15:04"), l.Views, status)
+	}
+	w.Flush()
+	out.Printf("\nAccess log: %s\n", shares.LogPath())
+	return nil
+}
+
+func runShareRevoke(cmd *cobra.Command, args []string) error {
+	out := newPrinter(cmd)
+	shares, err := shareStore()
+	if err != nil {
+		return err
+	}
+	revoked, err := shares.Revoke(args[0], time.Now())
+	if err != nil {
+		return err
+	}
+	if out.JSON() {
+		return out.WriteJSON(revoked)
+	}
+	for _, l := range revoked {
+		out.Printf("✓ Revoked %s link %s (%d views)\n", l.Pattern, l.Token[:8], l.Views)
+	}
+	return nil
+}
@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/analytics"
+	"github.com/mur-run/mur-core/internal/output"
+	"github.com/mur-run/mur-core/internal/stats"
 )
 
 var statsCmd = &cobra.Command{
@@ -40,21 +42,54 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	dataDir := filepath.Join(home, ".mur")
+	dataDir := config.DataDir(home)
 	store, err := analytics.NewStore(dataDir)
 	if err != nil {
 		return fmt.Errorf("failed to open analytics store: %w", err)
 	}
 	defer store.Close()
 
+	out := newPrinter(cmd)
 	if len(args) > 0 {
-		return showPatternStats(store, args[0])
+		return showPatternStats(out, store, args[0])
 	}
 
-	return showOverallStats(store, statsDays)
+	return showOverallStats(out, store, statsDays)
 }
 
-func showOverallStats(store *analytics.Store, days int) error {
+// patternStatsJSON is a pattern's analytics in 'mur stats --json'.
+type patternStatsJSON struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	UsageCount      int    `json:"usage_count"`
+	HelpfulCount    int    `json:"helpful_count"`
+	NotHelpfulCount int    `json:"not_helpful_count"`
+	SkipCount       int    `json:"skip_count"`
+	// Effectiveness is helpful / rated, nil until the pattern is rated.
+	Effectiveness *float64       `json:"effectiveness"`
+	LastUsed      *time.Time     `json:"last_used"`
+	ByTool        map[string]int `json:"by_tool,omitempty"`
+	ByContext     map[string]int `json:"by_context,omitempty"`
+}
+
+func newPatternStatsJSON(s *analytics.PatternStats) patternStatsJSON {
+	j := patternStatsJSON{
+		ID:              s.PatternID,
+		Name:            s.PatternName,
+		UsageCount:      s.UsageCount,
+		HelpfulCount:    s.HelpfulCount,
+		NotHelpfulCount: s.NotHelpfulCount,
+		SkipCount:       s.SkipCount,
+		LastUsed:        s.LastUsed,
+	}
+	if s.HelpfulCount+s.NotHelpfulCount > 0 {
+		eff := s.Effectiveness
+		j.Effectiveness = &eff
+	}
+	return j
+}
+
+func showOverallStats(out *output.Printer, store *analytics.Store, days int) error {
 	overall, err := store.GetOverallStats(days)
 	if err != nil {
 		return fmt.Errorf("failed to get overall stats: %w", err)
@@ -74,6 +109,40 @@ func showOverallStats(store *analytics.Store, days int) error {
 		}
 	}
 
+	// Patterns needing review (low effectiveness)
+	var needsReview []*analytics.PatternStats
+	for _, s := range allStats {
+		total := s.HelpfulCount + s.NotHelpfulCount
+		if total >= 5 && s.Effectiveness < 0.6 {
+			needsReview = append(needsReview, s)
+		}
+	}
+
+	if out.JSON() {
+		result := struct {
+			Days            int                `json:"days"`
+			TotalPatterns   int                `json:"total_patterns"`
+			ActivePatterns  int                `json:"active_patterns"` // used in the last 7 days
+			TotalInjections int                `json:"total_injections"`
+			Patterns        []patternStatsJSON `json:"patterns"`
+			NeedsReview     []string           `json:"needs_review"`
+		}{
+			Days:            days,
+			TotalPatterns:   overall.TotalPatterns,
+			ActivePatterns:  activeCount,
+			TotalInjections: overall.TotalInjections,
+			Patterns:        []patternStatsJSON{},
+			NeedsReview:     []string{},
+		}
+		for _, s := range allStats {
+			result.Patterns = append(result.Patterns, newPatternStatsJSON(s))
+		}
+		for _, s := range needsReview {
+			result.NeedsReview = append(result.NeedsReview, s.PatternName)
+		}
+		return out.WriteJSON(result)
+	}
+
 	fmt.Printf("\n📊 Pattern Analytics (last %d days)\n", days)
 	fmt.Println("═══════════════════════════════════════════════════════")
 	fmt.Println()
@@ -109,15 +178,6 @@ func showOverallStats(store *analytics.Store, days int) error {
 	w.Flush()
 	fmt.Println()
 
-	// Patterns needing review (low effectiveness)
-	var needsReview []*analytics.PatternStats
-	for _, s := range allStats {
-		total := s.HelpfulCount + s.NotHelpfulCount
-		if total >= 5 && s.Effectiveness < 0.6 {
-			needsReview = append(needsReview, s)
-		}
-	}
-
 	if len(needsReview) > 0 {
 		fmt.Println("Needs Review (low effectiveness):")
 		for _, s := range needsReview {
@@ -133,7 +193,7 @@ func showOverallStats(store *analytics.Store, days int) error {
 	return nil
 }
 
-func showPatternStats(store *analytics.Store, patternName string) error {
+func showPatternStats(out *output.Printer, store *analytics.Store, patternName string) error {
 	// Try to find pattern by name or ID
 	allStats, err := store.GetAllStats(1000)
 	if err != nil {
@@ -172,6 +232,12 @@ func showPatternStats(store *analytics.Store, patternName string) error {
 		return err
 	}
 
+	if out.JSON() {
+		result := newPatternStatsJSON(stats)
+		result.ByTool, result.ByContext = byTool, byContext
+		return out.WriteJSON(result)
+	}
+
 	fmt.Printf("\n📊 %s\n", stats.PatternName)
 	fmt.Println("═══════════════════════════════════════════════════════")
 	fmt.Println()
@@ -252,7 +318,7 @@ func formatTimeAgo(t time.Time) string {
 		}
 		return fmt.Sprintf("%d days ago", days)
 	default:
-		return t.Format("Jan 2, 2006")
+		return t.In(stats.DisplayLocation()).Format("Jan 2, 2006")
 	}
 }
 
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/analytics"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+var statsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare usage, savings, and learning between two periods",
+	Long: `Compare two periods to quantify improvement over time: runs, cost,
+savings, success rate, pattern growth, extraction yield (patterns extracted
+per session), and effectiveness (share of helpful feedback).
+
+Periods: today, yesterday, this-week, last-week, this-month, last-month,
+this-year, last-year, the last N days or weeks (30d, 4w), a month
+(2026-09), or a day (2026-09-14). Give two as current..baseline, or one to
+compare it with the period before it.
+
+Examples:
+  mur stats compare                                 # This week vs last week
+  mur stats compare --period this-month..last-month
+  mur stats compare --period 30d                    # Last 30 days vs the 30 before
+  mur stats compare --period 2026-09..2026-08 --json`,
+	RunE: runStatsCompare,
+}
+
+func init() {
+	statsCmd.AddCommand(statsCompareCmd)
+	statsCompareCmd.Flags().String("period", "this-week", "Periods to compare: current..baseline, or one period")
+	statsCompareCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runStatsCompare(cmd *cobra.Command, args []string) error {
+	periodStr, _ := cmd.Flags().GetString("period")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	current, baseline, err := stats.ParseComparison(periodStr, time.Now().In(stats.DisplayLocation()))
+	if err != nil {
+		return err
+	}
+	c, err := comparePeriods(current, baseline)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(stats.FormatComparison(c))
+	return nil
+}
+
+// comparePeriods measures both periods from the usage log, the pattern
+// store, and pattern feedback, and compares them.
+func comparePeriods(current, baseline stats.Period) (stats.Comparison, error) {
+	start := current.Start
+	if baseline.Start.Before(start) {
+		start = baseline.Start
+	}
+	records, err := stats.Query(stats.QueryFilter{StartTime: start})
+	if err != nil {
+		return stats.Comparison{}, err
+	}
+	patterns, err := listAllPatterns()
+	if err != nil {
+		return stats.Comparison{}, fmt.Errorf("failed to load patterns: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	m := stats.MethodologyFromConfig(cfg)
+
+	cm := stats.MeasurePeriod(current, records, patterns, m)
+	bm := stats.MeasurePeriod(baseline, records, patterns, m)
+
+	// Feedback lives in the analytics store; without it effectiveness is 0
+	if home, err := os.UserHomeDir(); err == nil {
+		if store, err := analytics.NewStore(config.DataDir(home)); err == nil {
+			cm.Helpful, cm.NotHelpful, _ = store.FeedbackBetween(current.Start, current.End)
+			bm.Helpful, bm.NotHelpful, _ = store.FeedbackBetween(baseline.Start, baseline.End)
+			store.Close()
+		}
+	}
+
+	return stats.Compare(current, baseline, cm, bm), nil
+}
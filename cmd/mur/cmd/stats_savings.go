@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+var statsSavingsCmd = &cobra.Command{
+	Use:   "savings",
+	Short: "Show estimated savings and how they are computed",
+	Long: `Estimate what mur saved compared to a counterfactual baseline where
+every run used one model (stats.savings.baseline, default claude-sonnet).
+
+Each 'mur run' is priced twice from the tokens of the prompt actually sent:
+once at the baseline model and once at the model its tool is priced as.
+Savings are broken down by source:
+
+  Auto-routing   runs where mur picked the tool
+  Manual (-t)    runs where you picked the tool
+  Pattern reuse  re-explaining avoided by injected patterns, minus the
+                 cost of the injected tokens
+
+All assumptions (prices, token estimate, output ratio, reuse credit) are
+printed with the result and can be changed under stats.savings in
+~/.mur/config.yaml.
+
+Examples:
+  mur stats savings
+  mur stats savings --baseline claude-opus
+  mur stats savings --days 7 --json`,
+	RunE: runStatsSavings,
+}
+
+func init() {
+	statsCmd.AddCommand(statsSavingsCmd)
+	statsSavingsCmd.Flags().IntP("days", "d", 30, "Number of days to analyze (0 for all)")
+	statsSavingsCmd.Flags().String("baseline", "", "Model every run is compared against (overrides stats.savings.baseline)")
+	statsSavingsCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runStatsSavings(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	baseline, _ := cmd.Flags().GetString("baseline")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	if baseline != "" {
+		cfg.Stats.Savings.Baseline = baseline
+	}
+	m := stats.MethodologyFromConfig(cfg)
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	filter := stats.QueryFilter{}
+	if days > 0 {
+		filter.StartTime = time.Now().AddDate(0, 0, -days)
+	}
+	records, err := stats.Query(filter)
+	if err != nil {
+		return err
+	}
+
+	report := stats.ComputeSavings(records, m)
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(stats.FormatSavings(report))
+	return nil
+}
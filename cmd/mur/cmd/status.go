@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,7 +27,8 @@ var statusCmd = &cobra.Command{
 
 Examples:
   mur status           # Quick overview
-  mur status --verbose # Detailed status`,
+  mur status --verbose # Detailed status
+  mur status --json    # Everything, as JSON`,
 	RunE: runStatus,
 }
 
@@ -37,95 +39,280 @@ func init() {
 	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "V", false, "Show detailed status")
 }
 
+// statusReport is what 'mur status' shows; with --json it is printed as is.
+type statusReport struct {
+	Patterns statusPatterns `json:"patterns"`
+	Cloud    statusCloud    `json:"cloud"`
+	Targets  []statusTarget `json:"targets"`
+	// LastWeek summarizes tool runs of the last 7 days; nil if none.
+	LastWeek *stats.Summary `json:"last_7_days"`
+	Config   statusConfig   `json:"config"`
+	Repo     *statusRepo    `json:"repo"`
+	Hooks    []statusHook   `json:"hooks"`
+}
+
+type statusPatterns struct {
+	Total      int `json:"total"`
+	Active     int `json:"active"`
+	Deprecated int `json:"deprecated"`
+	Usage      int `json:"usage"`
+	// AvgEffectiveness is the mean (0-1) over patterns with any; nil if none.
+	AvgEffectiveness *float64 `json:"avg_effectiveness"`
+}
+
+type statusCloud struct {
+	LoggedIn           bool       `json:"logged_in"`
+	SessionExpired     bool       `json:"session_expired"`
+	Email              string     `json:"email,omitempty"`
+	TrialDaysRemaining *int       `json:"trial_days_remaining,omitempty"`
+	Team               string     `json:"team,omitempty"`
+	LastSync           *time.Time `json:"last_sync,omitempty"`
+}
+
+type statusTarget struct {
+	Name     string     `json:"name"`
+	Path     string     `json:"path"`
+	Synced   bool       `json:"synced"`
+	Files    int        `json:"files"`
+	Modified *time.Time `json:"modified,omitempty"`
+	icon     string
+}
+
+type statusConfig struct {
+	Found        bool               `json:"found"`
+	ToolsEnabled int                `json:"tools_enabled"`
+	Tools        []statusConfigTool `json:"tools"`
+}
+
+type statusConfigTool struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Tier    string `json:"tier"`
+}
+
+type statusRepo struct {
+	Path   string `json:"path"`
+	Remote string `json:"remote,omitempty"`
+}
+
+type statusHook struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
 	}
+	report := collectStatus(home)
 
-	fmt.Println()
-	fmt.Println("🔮 mur status")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	out := newPrinter(cmd)
+	if out.JSON() {
+		return out.WriteJSON(report)
+	}
+	printStatus(report)
+	return nil
+}
+
+// collectStatus gathers the status of patterns, cloud, sync targets,
+// usage, config, learning repo and hooks.
+func collectStatus(home string) statusReport {
+	var r statusReport
 
 	// Patterns
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 	patterns, _ := store.List()
 
-	activeCount := 0
-	deprecatedCount := 0
 	var totalEffectiveness float64
 	effectiveCount := 0
-	var totalUsage int
-
+	r.Patterns.Total = len(patterns)
 	for _, p := range patterns {
 		if p.Lifecycle.Status == "deprecated" {
-			deprecatedCount++
+			r.Patterns.Deprecated++
 		} else {
-			activeCount++
+			r.Patterns.Active++
 		}
 		if p.Learning.Effectiveness > 0 {
 			totalEffectiveness += p.Learning.Effectiveness
 			effectiveCount++
 		}
-		totalUsage += p.Learning.UsageCount
+		r.Patterns.Usage += p.Learning.UsageCount
 	}
-
-	avgEffectiveness := 0.0
 	if effectiveCount > 0 {
-		avgEffectiveness = totalEffectiveness / float64(effectiveCount) * 100
+		avg := totalEffectiveness / float64(effectiveCount)
+		r.Patterns.AvgEffectiveness = &avg
 	}
 
-	fmt.Println()
-	fmt.Println("📚 Patterns")
-	fmt.Printf("   Total: %d (%d active, %d deprecated)\n", len(patterns), activeCount, deprecatedCount)
-	fmt.Printf("   Usage: %d injections\n", totalUsage)
-	if effectiveCount > 0 {
-		fmt.Printf("   Avg Effectiveness: %.0f%%\n", avgEffectiveness)
-	}
-
-	// Cloud status
-	fmt.Println()
-	fmt.Println("☁️  Cloud")
+	// Cloud
+	cfg, cfgErr := config.Load()
 	authStore, authErr := cloud.NewAuthStore()
 	authData, _ := authStore.Load()
-
 	if authErr == nil && authStore.IsLoggedIn() {
+		r.Cloud.LoggedIn = true
 		if authData != nil && authData.User != nil {
-			fmt.Printf("   Logged in as: %s\n", authData.User.Email)
-		} else {
-			fmt.Println("   Logged in (API key)")
+			r.Cloud.Email = authData.User.Email
 		}
 
-		// Show trial status by calling /me API
-		cfg, _ := config.Load()
-		if client, err := cloud.NewClient(func() string {
-			if cfg != nil {
-				return cfg.Server.URL
-			}
-			return ""
-		}()); err == nil {
+		// Trial status comes from the /me API
+		serverURL := ""
+		if cfg != nil {
+			serverURL = cfg.Server.URL
+		}
+		if client, err := cloud.NewClient(serverURL); err == nil {
 			if me, err := client.Me(); err == nil && me.Plan == "trial" {
-				if me.TrialDaysRemaining > 14 {
-					fmt.Printf("   Trial: %d days remaining\n", me.TrialDaysRemaining)
-				} else if me.TrialDaysRemaining > 0 {
-					fmt.Printf("   ⚠️  Trial: %d days remaining! Upgrade: mur billing | Extend: mur referral\n", me.TrialDaysRemaining)
-				} else {
-					fmt.Println("   ⚠️  Trial expired — Free plan (cloud sync disabled)")
-					fmt.Println("   Upgrade: app.mur.run/billing | Extend: mur referral")
+				days := me.TrialDaysRemaining
+				r.Cloud.TrialDaysRemaining = &days
+			}
+		}
+		if cfg != nil {
+			r.Cloud.Team = cfg.Server.Team
+		}
+		syncStatePath := filepath.Join(config.StateDir(home), "sync-state.yaml")
+		if info, err := os.Stat(syncStatePath); err == nil {
+			t := info.ModTime()
+			r.Cloud.LastSync = &t
+		}
+	} else if authData != nil && authData.AccessToken != "" {
+		r.Cloud.SessionExpired = true
+	}
+
+	// Sync targets
+	r.Targets = []statusTarget{
+		{Name: "Claude Code", Path: filepath.Join(config.ClaudeDir(home), "skills", "mur"), icon: "⌨️"},
+		{Name: "Gemini CLI", Path: filepath.Join(home, ".gemini", "skills", "mur"), icon: "⌨️"},
+		{Name: "Codex CLI", Path: filepath.Join(home, ".codex", "instructions.md"), icon: "⌨️"},
+		{Name: "Auggie", Path: filepath.Join(home, ".augment", "skills", "mur"), icon: "⌨️"},
+		{Name: "Aider", Path: filepath.Join(home, ".aider", "mur-patterns.md"), icon: "⌨️"},
+		{Name: "Continue", Path: filepath.Join(home, ".continue", "rules", "mur"), icon: "🖥️"},
+		{Name: "Cursor", Path: filepath.Join(home, ".cursor", "rules", "mur"), icon: "🖥️"},
+		{Name: "Windsurf", Path: filepath.Join(home, ".windsurf", "rules", "mur"), icon: "🖥️"},
+	}
+	for i := range r.Targets {
+		t := &r.Targets[i]
+		info, err := os.Stat(t.Path)
+		if err != nil {
+			continue
+		}
+		t.Synced = true
+		t.Files = 1
+		if info.IsDir() {
+			files, _ := os.ReadDir(t.Path)
+			t.Files = len(files)
+		}
+		mod := info.ModTime()
+		t.Modified = &mod
+	}
+
+	// Usage stats
+	records, _ := stats.Query(stats.QueryFilter{
+		StartTime: time.Now().AddDate(0, 0, -7),
+	})
+	if len(records) > 0 {
+		summary := stats.Summarize(records)
+		r.LastWeek = &summary
+	}
+
+	// Config
+	r.Config.Tools = []statusConfigTool{}
+	if cfgErr == nil {
+		r.Config.Found = true
+		for name, tool := range cfg.Tools {
+			if tool.Enabled {
+				r.Config.ToolsEnabled++
+			}
+			r.Config.Tools = append(r.Config.Tools, statusConfigTool{Name: name, Enabled: tool.Enabled, Tier: tool.Tier})
+		}
+		sort.Slice(r.Config.Tools, func(i, j int) bool { return r.Config.Tools[i].Name < r.Config.Tools[j].Name })
+	}
+
+	// Repo
+	repoPath := filepath.Join(config.DataDir(home), "repo")
+	if info, err := os.Stat(repoPath); err == nil && info.IsDir() {
+		r.Repo = &statusRepo{Path: repoPath}
+		// Try to get remote URL
+		remoteFile := filepath.Join(repoPath, ".git", "config")
+		if content, err := os.ReadFile(remoteFile); err == nil {
+			for _, line := range strings.Split(string(content), "\n") {
+				if strings.Contains(line, "url = ") {
+					r.Repo.Remote = strings.TrimSpace(strings.TrimPrefix(line, "\turl = "))
+					break
 				}
 			}
 		}
+	}
 
-		// Show active team from config
-		if cfg != nil && cfg.Server.Team != "" {
-			fmt.Printf("   Active team: %s\n", cfg.Server.Team)
+	// Hooks
+	hookChecks := []struct {
+		name  string
+		paths []string // check multiple possible locations
+	}{
+		{"Claude Code", []string{
+			filepath.Join(config.ClaudeDir(home), "settings.json"),
+			filepath.Join(config.ClaudeDir(home), "hooks.json"),
+		}},
+		{"Gemini CLI", []string{
+			filepath.Join(home, ".gemini", "settings.json"),
+			filepath.Join(home, ".gemini", "hooks.json"),
+		}},
+		{"Auggie", []string{
+			filepath.Join(home, ".augment", "settings.json"),
+			filepath.Join(home, ".augment", "hooks.json"),
+		}},
+	}
+	for _, h := range hookChecks {
+		found := false
+		for _, p := range h.paths {
+			if data, err := os.ReadFile(p); err == nil && strings.Contains(string(data), "mur") {
+				found = true
+				break
+			}
 		}
+		r.Hooks = append(r.Hooks, statusHook{Name: h.name, Installed: found})
+	}
 
-		// Show last sync time
-		syncStatePath := filepath.Join(home, ".mur", "sync-state.yaml")
-		if info, err := os.Stat(syncStatePath); err == nil {
-			syncAge := time.Since(info.ModTime())
+	return r
+}
+
+func printStatus(r statusReport) {
+	fmt.Println()
+	fmt.Println("🔮 mur status")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	fmt.Println()
+	fmt.Println("📚 Patterns")
+	fmt.Printf("   Total: %d (%d active, %d deprecated)\n", r.Patterns.Total, r.Patterns.Active, r.Patterns.Deprecated)
+	fmt.Printf("   Usage: %d injections\n", r.Patterns.Usage)
+	if r.Patterns.AvgEffectiveness != nil {
+		fmt.Printf("   Avg Effectiveness: %.0f%%\n", *r.Patterns.AvgEffectiveness*100)
+	}
+
+	// Cloud status
+	fmt.Println()
+	fmt.Println("☁️  Cloud")
+	switch {
+	case r.Cloud.LoggedIn:
+		if r.Cloud.Email != "" {
+			fmt.Printf("   Logged in as: %s\n", r.Cloud.Email)
+		} else {
+			fmt.Println("   Logged in (API key)")
+		}
+		if days := r.Cloud.TrialDaysRemaining; days != nil {
+			if *days > 14 {
+				fmt.Printf("   Trial: %d days remaining\n", *days)
+			} else if *days > 0 {
+				fmt.Printf("   ⚠️  Trial: %d days remaining! Upgrade: mur billing | Extend: mur referral\n", *days)
+			} else {
+				fmt.Println("   ⚠️  Trial expired — Free plan (cloud sync disabled)")
+				fmt.Println("   Upgrade: app.mur.run/billing | Extend: mur referral")
+			}
+		}
+		if r.Cloud.Team != "" {
+			fmt.Printf("   Active team: %s\n", r.Cloud.Team)
+		}
+		if r.Cloud.LastSync != nil {
+			syncAge := time.Since(*r.Cloud.LastSync)
 			var syncAgeStr string
 			if syncAge < time.Minute {
 				syncAgeStr = "just now"
@@ -138,15 +325,13 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			}
 			fmt.Printf("   Last sync: %s\n", syncAgeStr)
 		}
-
 		if statusVerbose {
 			fmt.Println("   Commands: mur cloud teams, mur cloud sync")
 		}
-	} else if authData != nil && authData.AccessToken != "" {
-		// Has token but expired
+	case r.Cloud.SessionExpired:
 		fmt.Println("   ⚠️  Session expired")
 		fmt.Println("   Run: mur login")
-	} else {
+	default:
 		fmt.Println("   Not logged in")
 		fmt.Println("   Run: mur login")
 	}
@@ -154,55 +339,24 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Sync targets
 	fmt.Println()
 	fmt.Println("🔄 Sync Targets")
-
-	type syncTarget struct {
-		name string
-		path string
-		icon string
-	}
-
-	targets := []syncTarget{
-		{"Claude Code", filepath.Join(home, ".claude", "skills", "mur"), "⌨️"},
-		{"Gemini CLI", filepath.Join(home, ".gemini", "skills", "mur"), "⌨️"},
-		{"Codex CLI", filepath.Join(home, ".codex", "instructions.md"), "⌨️"},
-		{"Auggie", filepath.Join(home, ".augment", "skills", "mur"), "⌨️"},
-		{"Aider", filepath.Join(home, ".aider", "mur-patterns.md"), "⌨️"},
-		{"Continue", filepath.Join(home, ".continue", "rules", "mur"), "🖥️"},
-		{"Cursor", filepath.Join(home, ".cursor", "rules", "mur"), "🖥️"},
-		{"Windsurf", filepath.Join(home, ".windsurf", "rules", "mur"), "🖥️"},
-	}
-
 	syncedCount := 0
-	for _, t := range targets {
-		info, err := os.Stat(t.path)
-		if err == nil {
+	for _, t := range r.Targets {
+		if t.Synced {
 			syncedCount++
 			if statusVerbose {
-				fileCount := 1
-				if info.IsDir() {
-					files, _ := os.ReadDir(t.path)
-					fileCount = len(files)
-				}
-				lastMod := info.ModTime().Format("Jan 2 15:04")
-				fmt.Printf("   %s %-12s ✓ %d files, %s\n", t.icon, t.name, fileCount, lastMod)
+				fmt.Printf("   %s %-12s ✓ %d files, %s\n", t.icon, t.Name, t.Files, t.Modified.Format("Jan 2 15:04"))
 			}
 		} else if statusVerbose {
-			fmt.Printf("   %s %-12s ✗ not synced\n", t.icon, t.name)
+			fmt.Printf("   %s %-12s ✗ not synced\n", t.icon, t.Name)
 		}
 	}
-
 	if !statusVerbose {
-		fmt.Printf("   %d/%d targets synced\n", syncedCount, len(targets))
+		fmt.Printf("   %d/%d targets synced\n", syncedCount, len(r.Targets))
 		fmt.Println("   Run with --verbose for details")
 	}
 
 	// Usage stats
-	records, _ := stats.Query(stats.QueryFilter{
-		StartTime: time.Now().AddDate(0, 0, -7),
-	})
-
-	if len(records) > 0 {
-		summary := stats.Summarize(records)
+	if summary := r.LastWeek; summary != nil {
 		fmt.Println()
 		fmt.Println("📊 Last 7 Days")
 		fmt.Printf("   Runs: %d\n", summary.TotalRuns)
@@ -210,7 +364,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			fmt.Printf("   Cost: $%.4f\n", summary.EstimatedCost)
 		}
 		if summary.EstimatedSaved > 0 {
-			fmt.Printf("   Saved: $%.4f (via free tools)\n", summary.EstimatedSaved)
+			fmt.Printf("   Saved: $%.4f (vs. all on %s)\n", summary.EstimatedSaved, summary.SavedBaseline)
 		}
 		if summary.AutoRouteStats.Total > 0 {
 			fmt.Printf("   Auto-routed: %d (%.0f%% to free)\n",
@@ -230,95 +384,47 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Config status
 	fmt.Println()
 	fmt.Println("⚙️  Config")
-	cfg, err := config.Load()
-	if err != nil {
+	if !r.Config.Found {
 		fmt.Println("   ⚠️  No config found (using defaults)")
 	} else {
-		// Count enabled tools
-		enabledTools := 0
-		for _, tool := range cfg.Tools {
-			if tool.Enabled {
-				enabledTools++
-			}
-		}
-		fmt.Printf("   %d tools configured\n", enabledTools)
-
+		fmt.Printf("   %d tools configured\n", r.Config.ToolsEnabled)
 		if statusVerbose {
-			for name, tool := range cfg.Tools {
+			for _, tool := range r.Config.Tools {
 				status := "✗"
 				if tool.Enabled {
 					status = "✓"
 				}
-				fmt.Printf("   %s %s (%s)\n", status, name, tool.Tier)
+				fmt.Printf("   %s %s (%s)\n", status, tool.Name, tool.Tier)
 			}
 		}
 	}
 
 	// Repo status
-	repoPath := filepath.Join(home, ".mur", "repo")
-	if info, err := os.Stat(repoPath); err == nil && info.IsDir() {
+	if r.Repo != nil {
 		fmt.Println()
 		fmt.Println("📦 Learning Repo")
-		// Try to get remote URL
-		remoteFile := filepath.Join(repoPath, ".git", "config")
-		if content, err := os.ReadFile(remoteFile); err == nil {
-			lines := strings.Split(string(content), "\n")
-			for _, line := range lines {
-				if strings.Contains(line, "url = ") {
-					url := strings.TrimSpace(strings.TrimPrefix(line, "\turl = "))
-					fmt.Printf("   %s\n", url)
-					break
-				}
-			}
+		if r.Repo.Remote != "" {
+			fmt.Printf("   %s\n", r.Repo.Remote)
 		}
 	}
 
 	// Hooks status
 	fmt.Println()
 	fmt.Println("🪝 Hooks")
-	type hookCheck struct {
-		name  string
-		paths []string // check multiple possible locations
-	}
-	hookChecks := []hookCheck{
-		{"Claude Code", []string{
-			filepath.Join(home, ".claude", "settings.json"),
-			filepath.Join(home, ".claude", "hooks.json"),
-		}},
-		{"Gemini CLI", []string{
-			filepath.Join(home, ".gemini", "settings.json"),
-			filepath.Join(home, ".gemini", "hooks.json"),
-		}},
-		{"Auggie", []string{
-			filepath.Join(home, ".augment", "settings.json"),
-			filepath.Join(home, ".augment", "hooks.json"),
-		}},
-	}
-
 	hooksInstalled := 0
-	for _, h := range hookChecks {
-		found := false
-		for _, p := range h.paths {
-			if data, err := os.ReadFile(p); err == nil {
-				if strings.Contains(string(data), "mur") {
-					found = true
-					break
-				}
-			}
-		}
-		if found {
+	for _, h := range r.Hooks {
+		if h.Installed {
 			hooksInstalled++
 			if statusVerbose {
-				fmt.Printf("   ✓ %s\n", h.name)
+				fmt.Printf("   ✓ %s\n", h.Name)
 			}
 		} else if statusVerbose {
-			fmt.Printf("   ✗ %s (not installed)\n", h.name)
+			fmt.Printf("   ✗ %s (not installed)\n", h.Name)
 		}
 	}
-
 	if !statusVerbose {
 		if hooksInstalled > 0 {
-			fmt.Printf("   %d/%d CLI hooks installed\n", hooksInstalled, len(hookChecks))
+			fmt.Printf("   %d/%d CLI hooks installed\n", hooksInstalled, len(r.Hooks))
 		} else {
 			fmt.Println("   No hooks installed")
 			fmt.Println("   Run: mur init --hooks")
@@ -330,6 +436,4 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Println("Dashboard: mur serve")
 	fmt.Println("Help: mur --help")
 	fmt.Println()
-
-	return nil
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -75,8 +76,8 @@ func suggestScanExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
-	suggestDir := filepath.Join(home, ".mur", "suggestions")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
+	suggestDir := filepath.Join(config.DataDir(home), "suggestions")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := suggest.DefaultExtractorConfig()
@@ -199,8 +200,8 @@ func suggestAcceptExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	home, _ := os.UserHomeDir()
-	patternsDir := filepath.Join(home, ".mur", "patterns")
-	suggestDir := filepath.Join(home, ".mur", "suggestions")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
+	suggestDir := filepath.Join(config.DataDir(home), "suggestions")
 	store := pattern.NewStore(patternsDir)
 
 	cfg := suggest.DefaultExtractorConfig()
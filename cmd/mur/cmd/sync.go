@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,6 +16,10 @@ import (
 	"github.com/mur-run/mur-core/internal/cache"
 	"github.com/mur-run/mur-core/internal/cloud"
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
+	"github.com/mur-run/mur-core/internal/heartbeat"
+	"github.com/mur-run/mur-core/internal/kit"
 	"github.com/mur-run/mur-core/internal/learn"
 	"github.com/mur-run/mur-core/internal/security"
 	"github.com/mur-run/mur-core/internal/sync"
@@ -22,14 +27,20 @@ import (
 
 var (
 	syncPush     bool
-	syncQuiet    bool
 	syncFormat   string
 	syncCleanOld bool
 	syncCloud    bool
 	syncGit      bool
 	syncCLI      bool
 	syncAsync    bool
+	syncCoalesce bool
 	syncTimeout  string
+
+	syncConcurrency   int
+	syncTargetTimeout string
+
+	// syncQuiet is set from the global --quiet and --porcelain flags.
+	syncQuiet bool
 )
 
 var syncCmd = &cobra.Command{
@@ -52,7 +63,18 @@ Examples:
   mur sync --cloud            # Force cloud sync
   mur sync --git              # Force git sync
   mur sync --cli              # Only sync to local CLIs (no remote)
-  mur sync --quiet            # Silent mode`,
+  mur sync --quiet            # Silent mode
+  mur sync --porcelain        # One line per result, for scripts
+
+With --porcelain, prints one line per result:
+  mode<TAB>cloud|git|cli
+  remote<TAB>cloud|git<TAB>ok|failed<TAB>error
+  target<TAB>name<TAB>ok|failed|conflict<TAB>message<TAB>duration_ms
+  skill<TAB>target<TAB>ok|failed<TAB>message
+
+Local CLI targets are synced concurrently, each with its own timeout, so
+one slow or hanging target (a network mount, a locked file) is reported
+as failed without holding up the rest.`,
 	RunE: runSync,
 }
 
@@ -62,11 +84,13 @@ func init() {
 	syncCmd.Flags().BoolVar(&syncGit, "git", false, "Force git sync")
 	syncCmd.Flags().BoolVar(&syncCLI, "cli", false, "Only sync to local CLIs (no remote sync)")
 	syncCmd.Flags().BoolVar(&syncPush, "push", false, "Push local changes to remote (git mode)")
-	syncCmd.Flags().BoolVar(&syncQuiet, "quiet", false, "Silent mode (minimal output)")
 	syncCmd.Flags().StringVar(&syncFormat, "format", "", "CLI sync format: directory (default) or single")
 	syncCmd.Flags().BoolVar(&syncCleanOld, "clean-old", false, "Remove old single-file format files")
 	syncCmd.Flags().BoolVar(&syncAsync, "async", false, "Run in background (detached process, parent exits immediately)")
+	syncCmd.Flags().BoolVar(&syncCoalesce, "coalesce", false, "Fold into a sync already running (as hooks do); see hooks.concurrency")
 	syncCmd.Flags().StringVar(&syncTimeout, "timeout", "", "Timeout duration (e.g. '30s', '2m'). Default: 30s")
+	syncCmd.Flags().IntVar(&syncConcurrency, "concurrency", 0, "CLI targets to sync at once (default: sync.concurrency or 4)")
+	syncCmd.Flags().StringVar(&syncTargetTimeout, "target-timeout", "", "Timeout per CLI target (e.g. '5s'). Default: sync.target_timeout_seconds or 10s")
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
@@ -75,6 +99,22 @@ func runSync(cmd *cobra.Command, args []string) error {
 		return async.RunBackground(os.Args[1:])
 	}
 
+	// --coalesce: overlapping calls (several stop hooks at once) share one
+	// more pass instead of each syncing
+	if syncCoalesce {
+		return runCoalesced(cmd, "sync", newPrinter(cmd).Quiet(), func() error { return syncOnce(cmd, args) })
+	}
+	return syncOnce(cmd, args)
+}
+
+// syncOnce runs one sync as the flags of cmd ask.
+func syncOnce(cmd *cobra.Command, args []string) (err error) {
+	// Record the outcome for `mur daemon health`
+	defer func() { recordSyncHeartbeat(err) }()
+
+	out := newPrinter(cmd)
+	syncQuiet = out.Quiet()
+
 	// --timeout: context with deadline
 	timeoutDur := 30 * time.Second // default
 	if syncTimeout != "" {
@@ -84,7 +124,9 @@ func runSync(cmd *cobra.Command, args []string) error {
 		}
 		timeoutDur = d
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDur)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, timeoutDur)
 	defer cancel()
 
 	home, err := os.UserHomeDir()
@@ -105,6 +147,16 @@ func runSync(cmd *cobra.Command, args []string) error {
 	if syncCleanOld {
 		cfg.Sync.CleanOld = true
 	}
+	if syncConcurrency > 0 {
+		cfg.Sync.Concurrency = syncConcurrency
+	}
+	if syncTargetTimeout != "" {
+		d, err := time.ParseDuration(syncTargetTimeout)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("invalid --target-timeout value %q: must be at least 1s", syncTargetTimeout)
+		}
+		cfg.Sync.TargetTimeoutSeconds = int(d / time.Second)
+	}
 
 	// Determine sync mode
 	useCloud := syncCloud
@@ -135,7 +187,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 		// If not using cloud, check for git repo
 		if !useCloud {
-			patternsDir := filepath.Join(home, ".mur", "repo")
+			patternsDir := filepath.Join(config.DataDir(home), "repo")
 			gitDir := filepath.Join(patternsDir, ".git")
 			if _, err := os.Stat(gitDir); err == nil {
 				useGit = true
@@ -155,16 +207,30 @@ func runSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	switch {
+	case useCloud:
+		out.Record("mode", "cloud")
+	case useGit:
+		out.Record("mode", "git")
+	default:
+		out.Record("mode", "cli")
+	}
+
+	// Purge trashed patterns past storage.trash_days, so remote syncs
+	// below propagate their deletion
+	purgeExpiredTrash()
+
 	// Execute cloud sync
 	if useCloud {
 		if err := ctx.Err(); err != nil {
 			return fmt.Errorf("timeout exceeded: %w", err)
 		}
 		if err := runCloudSync(cmd, cfg); err != nil {
-			if !syncQuiet {
-				fmt.Printf("⚠️  Cloud sync failed: %v\n", err)
-			}
+			out.Printf("%s  Cloud sync failed: %v\n", out.Yellow("⚠️"), err)
+			out.Record("remote", "cloud", "failed", err.Error())
 			// Continue to CLI sync even if cloud fails
+		} else {
+			out.Record("remote", "cloud", "ok", "")
 		}
 		if !syncQuiet {
 			fmt.Println()
@@ -177,9 +243,10 @@ func runSync(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("timeout exceeded: %w", err)
 		}
 		if err := runGitSync(ctx, home, cfg); err != nil {
-			if !syncQuiet {
-				fmt.Printf("⚠️  Git sync failed: %v\n", err)
-			}
+			out.Printf("%s  Git sync failed: %v\n", out.Yellow("⚠️"), err)
+			out.Record("remote", "git", "failed", err.Error())
+		} else {
+			out.Record("remote", "git", "ok", "")
 		}
 		if !syncQuiet {
 			fmt.Println()
@@ -204,20 +271,34 @@ func runSync(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Syncing patterns to CLIs (format: %s)...\n", format)
 	}
 
-	results, err := sync.SyncPatternsWithFormat(cfg)
+	// Ask about the current repository's patterns before they're synced
+	// into its rule files; hooks (--quiet) never ask
+	if cwd, err := os.Getwd(); err == nil && !syncQuiet {
+		projectPatternsDir(cwd)
+	}
+
+	results, err := sync.SyncPatternsWithFormat(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("sync failed: %w", err)
 	}
 
-	if !syncQuiet {
-		for _, r := range results {
-			status := "✓"
-			if !r.Success {
-				status = "✗"
-			}
-			fmt.Printf("  %s %s: %s\n", status, r.Target, r.Message)
+	for _, r := range results {
+		switch {
+		case r.Conflict:
+			// Shown even when quiet: the user has to fix the markers
+			fmt.Fprintf(os.Stderr, "  ⚠ %s: %s\n", r.Target, r.Message)
+			out.Record("target", r.Target, "conflict", r.Message, fmt.Sprint(r.Duration.Milliseconds()))
+		case r.Success:
+			out.Printf("  %s %s: %s%s\n", out.Green("✓"), r.Target, r.Message, formatSyncDuration(r.Duration))
+			out.Record("target", r.Target, "ok", r.Message, fmt.Sprint(r.Duration.Milliseconds()))
+		default:
+			out.Printf("  %s %s: %s%s\n", out.Red("✗"), r.Target, r.Message, formatSyncDuration(r.Duration))
+			out.Record("target", r.Target, "failed", r.Message, fmt.Sprint(r.Duration.Milliseconds()))
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sync interrupted: %w", err)
+	}
 
 	// Ensure OpenClaw built-in skills exist if OpenClaw is enabled
 	if tool, ok := cfg.Tools["openclaw"]; ok && tool.Enabled {
@@ -229,15 +310,15 @@ func runSync(cmd *cobra.Command, args []string) error {
 	// Sync skills (~/.mur/skills/ → CLI tools)
 	skillResults, err := sync.SyncSkills()
 	if err == nil {
-		if !syncQuiet {
-			fmt.Println()
-			fmt.Println("Syncing skills to CLIs...")
-			for _, r := range skillResults {
-				status := "✓"
-				if !r.Success {
-					status = "✗"
-				}
-				fmt.Printf("  %s %s: %s\n", status, r.Target, r.Message)
+		out.Println()
+		out.Println("Syncing skills to CLIs...")
+		for _, r := range skillResults {
+			if r.Success {
+				out.Printf("  %s %s: %s\n", out.Green("✓"), r.Target, r.Message)
+				out.Record("skill", r.Target, "ok", r.Message)
+			} else {
+				out.Printf("  %s %s: %s\n", out.Red("✗"), r.Target, r.Message)
+				out.Record("skill", r.Target, "failed", r.Message)
 			}
 		}
 	} else if !syncQuiet {
@@ -247,6 +328,9 @@ func runSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Catch up on hook extractions deferred on battery or metered networks
+	catchUpDeferredExtraction(cfg)
+
 	// Cleanup community cache (if configured)
 	cacheConfig := cfg.GetCacheConfig()
 	if cacheConfig.Cleanup == "on_sync" {
@@ -266,6 +350,100 @@ func runSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// purgeExpiredTrash permanently deletes patterns that have been in the
+// trash longer than storage.trash_days.
+func purgeExpiredTrash() {
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return
+	}
+	purged, err := store.PurgeExpired(time.Now())
+	if err != nil {
+		if !syncQuiet {
+			fmt.Printf("  ⚠ Trash: %v\n", err)
+		}
+		return
+	}
+	if len(purged) > 0 && !syncQuiet {
+		fmt.Printf("🗑️  Purged %d pattern(s) from the trash\n", len(purged))
+	}
+}
+
+// catchUpDeferredExtraction starts the extraction that hooks deferred, in
+// the background, once mur is no longer on battery or a metered network.
+func catchUpDeferredExtraction(cfg *config.Config) {
+	queue, err := learn.Deferred()
+	if err != nil || len(queue) == 0 {
+		return
+	}
+	if reason := learn.DeferReason(cfg); reason != "" {
+		if !syncQuiet {
+			fmt.Printf("  ⏸ %d deferred extractions still waiting (%s)\n", len(queue), reason)
+		}
+		return
+	}
+	if err := async.RunBackground([]string{"learn", "extract", "--llm", "--auto", "--accept-all", "--quiet", "--coalesce"}); err != nil {
+		if !syncQuiet {
+			fmt.Printf("  ⚠ Deferred extraction: %v\n", err)
+		}
+		return
+	}
+	// Started; don't start it again on the next sync
+	_ = learn.ClearDeferred()
+	if !syncQuiet {
+		fmt.Printf("  ⏵ Catching up on %d deferred extractions in the background\n", len(queue))
+	}
+}
+
+// formatSyncDuration formats how long a target took, for appending to its
+// result line.
+func formatSyncDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	if d < time.Millisecond {
+		return " (<1ms)"
+	}
+	return fmt.Sprintf(" (%s)", d.Round(time.Millisecond))
+}
+
+// recordSyncHeartbeat writes the sync heartbeat. When auto-sync is enabled
+// the scheduled interval is recorded so missed runs show up as stale.
+func recordSyncHeartbeat(syncErr error) {
+	beat := heartbeat.Beat{Process: heartbeat.ProcessSync}
+	if cfg, err := config.Load(); err == nil && cfg.Sync.Auto {
+		interval := cfg.Sync.IntervalMinutes
+		if interval <= 0 {
+			interval = 30
+		}
+		beat.Interval = interval * 60
+	}
+	if syncErr != nil {
+		beat.Status = heartbeat.StatusError
+		beat.Error = syncErr.Error()
+	}
+	_ = heartbeat.Write(beat)
+}
+
+// runCoalesced runs fn as job through async.Coalesce, with at most
+// hooks.concurrency runs at once; a call that finds them all busy leaves
+// its work to them and, unless quiet, says so.
+func runCoalesced(cmd *cobra.Command, job string, quiet bool, fn func() error) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	slots := 1
+	if cfg, err := config.Load(); err == nil && cfg.Hooks.Concurrency > 0 {
+		slots = cfg.Hooks.Concurrency
+	}
+	queued, err := async.Coalesce(filepath.Join(config.StateDir(home), "locks"), job, slots, fn)
+	if queued && !quiet {
+		fmt.Printf("'%s' is already running; it will run once more to cover this call\n", cmd.CommandPath())
+	}
+	return err
+}
+
 // runCloudSync executes cloud sync with mur.run
 func runCloudSync(cmd *cobra.Command, cfg *config.Config) error {
 	client, err := cloud.NewClient(cfg.Server.URL)
@@ -297,7 +475,7 @@ func runCloudSync(cmd *cobra.Command, cfg *config.Config) error {
 
 // runGitSync executes git-based sync
 func runGitSync(ctx context.Context, home string, cfg *config.Config) error {
-	patternsDir := filepath.Join(home, ".mur", "repo")
+	patternsDir := filepath.Join(config.DataDir(home), "repo")
 	gitDir := filepath.Join(patternsDir, ".git")
 
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
@@ -307,12 +485,14 @@ func runGitSync(ctx context.Context, home string, cfg *config.Config) error {
 	if !syncQuiet {
 		fmt.Println("Pulling from remote...")
 	}
-	pullCmd := exec.CommandContext(ctx, "git", "-C", patternsDir, "pull", "--rebase")
+	// Patterns deleted from the repo wait in the trash; keep their
+	// deletion out of the rebase and of commits until they're purged
+	pullCmd := execx.Command("git", "-C", patternsDir, "pull", "--rebase", "--autostash")
 	if !syncQuiet {
 		pullCmd.Stdout = os.Stdout
 		pullCmd.Stderr = os.Stderr
 	}
-	if err := pullCmd.Run(); err != nil {
+	if _, err := pullCmd.Run(ctx); err != nil {
 		if !syncQuiet {
 			fmt.Printf("  ⚠ Pull failed: %v\n", err)
 		}
@@ -328,21 +508,24 @@ func runGitSync(ctx context.Context, home string, cfg *config.Config) error {
 			fmt.Println("Pushing to remote...")
 		}
 
-		addCmd := exec.CommandContext(ctx, "git", "-C", patternsDir, "add", "-A")
-		_ = addCmd.Run()
+		_, _ = execx.Run(ctx, "git", "-C", patternsDir, "add", "-A")
+		if store, err := pattern.DefaultStore(); err == nil {
+			if trashed := store.TrashedFrom(patternsDir); len(trashed) > 0 {
+				args := append([]string{"-C", patternsDir, "reset", "-q", "--"}, trashed...)
+				_, _ = execx.Run(ctx, "git", args...)
+			}
+		}
 
-		diffCmd := exec.CommandContext(ctx, "git", "-C", patternsDir, "diff", "--cached", "--quiet")
-		if diffCmd.Run() != nil {
-			commitCmd := exec.CommandContext(ctx, "git", "-C", patternsDir, "commit", "-m", "mur: sync patterns")
-			_ = commitCmd.Run()
+		if _, err := execx.Run(ctx, "git", "-C", patternsDir, "diff", "--cached", "--quiet"); err != nil {
+			_, _ = execx.Run(ctx, "git", "-C", patternsDir, "commit", "-m", "mur: sync patterns")
 		}
 
-		pushCmd := exec.CommandContext(ctx, "git", "-C", patternsDir, "push")
+		pushCmd := execx.Command("git", "-C", patternsDir, "push")
 		if !syncQuiet {
 			pushCmd.Stdout = os.Stdout
 			pushCmd.Stderr = os.Stderr
 		}
-		if err := pushCmd.Run(); err != nil {
+		if _, err := pushCmd.Run(ctx); err != nil {
 			if !syncQuiet {
 				fmt.Printf("  ⚠ Push failed: %v\n", err)
 			}
@@ -389,7 +572,7 @@ func runCommunityAutoShare(cfg *config.Config) error {
 
 	// Initialize scanners
 	scanner := security.NewScanner()
-	piiScanner := security.NewPIIScanner(cfg.Privacy)
+	piiScanner := security.NewPIIScanner(kit.Privacy(cfg.Privacy))
 
 	// Initialize semantic anonymizer if enabled
 	var anonymizer *security.SemanticAnonymizer
@@ -405,7 +588,7 @@ func runCommunityAutoShare(cfg *config.Config) error {
 			if sa.CacheResults {
 				home, _ := os.UserHomeDir()
 				if home != "" {
-					cacheDir = filepath.Join(home, ".mur", "cache", "anonymization")
+					cacheDir = filepath.Join(config.CacheDir(home), "cache", "anonymization")
 				}
 			}
 			anonymizer = security.NewSemanticAnonymizer(llmClient, cacheDir)
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Keep pattern tags consistent",
+	Long: `List, rename, and merge the tags on your patterns.
+
+Extracted patterns pick up tags that mean the same thing ("golang" and
+"go", "k8s" and "kubernetes"). Rename or merge them here, and add aliases
+to config so new ones are normalized as they are extracted and synced:
+
+  tags:
+    aliases:
+      golang: go
+      k8s: kubernetes
+
+Renames and merges are snapshotted first; undo them with
+'mur learn bulk --undo'.`,
+}
+
+var tagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags in use",
+	Long: `List the tags on your patterns, most used first.
+
+Examples:
+  mur tags list
+  mur tags list --counts
+  mur tags list --counts --json`,
+	Args: cobra.NoArgs,
+	RunE: runTagsList,
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every pattern",
+	Long: `Rename a tag on every pattern that has it, in any case. Patterns
+that already have the new tag keep one.
+
+Examples:
+  mur tags rename golang go
+  mur tags rename K8s kubernetes --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTagsRename,
+}
+
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge [<tag>...] --into <tag>",
+	Short: "Merge tags into one",
+	Long: `Replace several tags with one on every pattern, or with --aliases,
+apply tags.aliases from config to your existing patterns.
+
+Examples:
+  mur tags merge golang go-lang --into go
+  mur tags merge --aliases --dry-run`,
+	RunE: runTagsMerge,
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsListCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+	tagsListCmd.Flags().Bool("counts", false, "Show how many patterns have each tag")
+	tagsRenameCmd.Flags().Bool("dry-run", false, "Show what would change without changing it")
+	tagsMergeCmd.Flags().String("into", "", "Tag to merge into")
+	tagsMergeCmd.Flags().Bool("aliases", false, "Merge by tags.aliases in config")
+	tagsMergeCmd.Flags().Bool("dry-run", false, "Show what would change without changing it")
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	counts, _ := cmd.Flags().GetBool("counts")
+	out := newPrinter(cmd)
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	patterns, err := store.List()
+	if err != nil {
+		return fmt.Errorf("cannot load patterns: %w", err)
+	}
+	tags := pattern.CountTags(patterns)
+	if out.JSON() {
+		return out.WriteJSON(tags)
+	}
+	if len(tags) == 0 {
+		out.Println("No tagged patterns.")
+		return nil
+	}
+
+	cfg, _ := config.Load()
+	aliases := pattern.TagAliasesFromConfig(cfg)
+	for _, t := range tags {
+		out.Record("tag", t.Tag, fmt.Sprint(t.Patterns), fmt.Sprint(t.Confirmed), fmt.Sprint(t.Inferred))
+		note := ""
+		if to := aliases.Canonical(t.Tag); to != t.Tag {
+			note = out.Dim("  (alias of " + to + ")")
+		}
+		if !counts {
+			out.Printf("%s%s\n", t.Tag, note)
+			continue
+		}
+		detail := ""
+		if t.Inferred > 0 {
+			detail = out.Dim(fmt.Sprintf("  %d inferred", t.Inferred))
+		}
+		out.Printf("%5d  %s%s%s\n", t.Patterns, t.Tag, detail, note)
+	}
+	if counts {
+		out.Printf("\n%d tags on %d patterns\n", len(tags), len(patterns))
+	}
+	return nil
+}
+
+func runTagsRename(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return renameTags(cmd, map[string]string{args[0]: args[1]}, dryRun)
+}
+
+func runTagsMerge(cmd *cobra.Command, args []string) error {
+	into, _ := cmd.Flags().GetString("into")
+	useAliases, _ := cmd.Flags().GetBool("aliases")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	renames := map[string]string{}
+	switch {
+	case useAliases && (len(args) > 0 || into != ""):
+		return fmt.Errorf("--aliases can't be combined with tags or --into")
+	case useAliases:
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		for from, to := range pattern.TagAliasesFromConfig(cfg) {
+			renames[from] = to
+		}
+		if len(renames) == 0 {
+			return fmt.Errorf("no tags.aliases in config")
+		}
+	case into == "" || len(args) == 0:
+		return fmt.Errorf("give the tags to merge and --into, e.g. mur tags merge golang go-lang --into go")
+	default:
+		for _, tag := range args {
+			renames[tag] = into
+		}
+	}
+	return renameTags(cmd, renames, dryRun)
+}
+
+// renameTags applies renames to the pattern store and reports the changes.
+func renameTags(cmd *cobra.Command, renames map[string]string, dryRun bool) error {
+	out := newPrinter(cmd)
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+	result, err := store.RenameTags(renames, dryRun)
+	if err != nil {
+		return err
+	}
+	if len(result.Changes) == 0 {
+		from := make([]string, 0, len(renames))
+		for tag := range renames {
+			from = append(from, tag)
+		}
+		sort.Strings(from)
+		out.Printf("No patterns have %s\n", strings.Join(from, ", "))
+		return nil
+	}
+
+	for _, c := range result.Changes {
+		out.Record("retag", c.Pattern, strings.Join(c.Changes, ", "))
+		out.Printf("  %-30s %s\n", c.Pattern, strings.Join(c.Changes, ", "))
+	}
+	out.Println()
+	if dryRun {
+		out.Println("(dry-run mode, no changes made)")
+		return nil
+	}
+	out.Printf("%s Retagged %d pattern(s)\n", out.Green("✓"), len(result.Changes))
+	out.Printf("  Undo with: mur learn bulk --undo --snapshot %s\n", result.SnapshotID)
+	out.Println("  Run 'mur sync' to update AI tools")
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
 )
 
 var transcriptsCmd = &cobra.Command{
@@ -82,7 +84,7 @@ func runTranscripts(cmd *cobra.Command, args []string) error {
 
 	// Collect Claude Code sessions
 	if transcriptsSource == "" || transcriptsSource == "claude" {
-		claudeDir := filepath.Join(home, ".claude", "projects")
+		claudeDir := filepath.Join(config.ClaudeDir(home), "projects")
 		if _, err := os.Stat(claudeDir); err == nil {
 			claudeSessions, err := findClaudeSessions(claudeDir)
 			if err == nil {
@@ -173,7 +175,7 @@ func runTranscriptsShow(cmd *cobra.Command, args []string) error {
 	var sessions []Session
 
 	// Collect from all sources
-	claudeDir := filepath.Join(home, ".claude", "projects")
+	claudeDir := filepath.Join(config.ClaudeDir(home), "projects")
 	if _, err := os.Stat(claudeDir); err == nil {
 		claudeSessions, _ := findClaudeSessions(claudeDir)
 		sessions = append(sessions, claudeSessions...)
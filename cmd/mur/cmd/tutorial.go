@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+	"github.com/mur-run/mur-core/internal/sync"
+	"github.com/mur-run/mur-core/internal/tutorial"
+)
+
+var tutorialCmd = &cobra.Command{
+	Use:   "tutorial",
+	Short: "Interactive walkthrough of the learn → sync → context loop",
+	Long: `Walk through mur's core loop step by step:
+
+  1. Create a pattern by hand
+  2. Extract patterns from an example session transcript
+  3. Sync patterns to AI tools
+  4. Search patterns the way AI tools do
+
+Everything runs in a temporary MUR_HOME and HOME, so your real patterns
+and AI tool configs are never touched.
+
+Examples:
+  mur tutorial           # Interactive, pauses between steps
+  mur tutorial --yes     # Run straight through
+  mur tutorial --keep    # Keep the sandbox to inspect afterwards`,
+	RunE: runTutorial,
+}
+
+func init() {
+	rootCmd.AddCommand(tutorialCmd)
+	tutorialCmd.Flags().BoolP("yes", "y", false, "Don't pause between steps")
+	tutorialCmd.Flags().Bool("keep", false, "Keep the sandbox directory after the tutorial")
+}
+
+func runTutorial(cmd *cobra.Command, args []string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	reader := bufio.NewReader(os.Stdin)
+	pause := func() bool {
+		if yes {
+			fmt.Println()
+			return true
+		}
+		fmt.Print("\nPress Enter to continue (q to quit) ")
+		answer, _ := reader.ReadString('\n')
+		fmt.Println()
+		return strings.TrimSpace(strings.ToLower(answer)) != "q"
+	}
+
+	sandbox, err := tutorial.NewSandbox()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = sandbox.Close(keep)
+		if keep {
+			fmt.Printf("\n📦 Sandbox kept at %s\n", sandbox.Root)
+		}
+	}()
+
+	fmt.Println("🎓 mur tutorial")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("mur learns patterns from your AI sessions, syncs them to your AI")
+	fmt.Println("tools, and injects the relevant ones as context when you work.")
+	fmt.Println()
+	fmt.Printf("Sandbox: %s\n", sandbox.Root)
+	fmt.Println("(your real ~/.mur and AI tool configs are not touched)")
+	if !pause() {
+		return nil
+	}
+
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	// Step 1: create a pattern
+	fmt.Println("Step 1/4 — Create a pattern")
+	fmt.Println("───────────────────────────")
+	fmt.Println("Patterns are YAML files. You can write them yourself with 'mur new'.")
+	fmt.Println()
+	sample := tutorial.SamplePattern()
+	if err := store.Create(sample); err != nil {
+		return fmt.Errorf("create sample pattern: %w", err)
+	}
+	fmt.Printf("✓ Created %s\n", sample.Name)
+	fmt.Printf("  %s\n", filepath.Join(store.Dir(), sample.Name+".yaml"))
+	fmt.Println()
+	fmt.Println("  Real command: mur new <name>")
+	if !pause() {
+		return nil
+	}
+
+	// Step 2: extract from a transcript
+	fmt.Println("Step 2/4 — Learn from a session")
+	fmt.Println("───────────────────────────────")
+	fmt.Println("mur reads your AI session transcripts and extracts reusable lessons.")
+	fmt.Println("Here is a bundled example session about Go error handling:")
+	fmt.Println()
+	transcript, err := sandbox.WriteTranscript()
+	if err != nil {
+		return fmt.Errorf("write example transcript: %w", err)
+	}
+	extracted, err := learn.ExtractFromSession(transcript)
+	if err != nil {
+		return fmt.Errorf("extract patterns: %w", err)
+	}
+	saved := 0
+	for _, ep := range extracted {
+		if saved == 2 {
+			break
+		}
+		if err := learn.Add(ep.Pattern); err != nil {
+			continue
+		}
+		fmt.Printf("✓ Extracted %s (%.0f%% confidence)\n", ep.Pattern.Name, ep.Confidence*100)
+		fmt.Printf("  %s\n", ep.Evidence[0])
+		saved++
+	}
+	if saved == 0 {
+		fmt.Println("⚠ No patterns found in the example session")
+	}
+	// Extraction writes v1 patterns; upgrade them so sync and search see them
+	if _, err := pattern.Migrate(store.Dir(), pattern.MigrateOptions{}); err != nil {
+		return fmt.Errorf("upgrade extracted patterns: %w", err)
+	}
+	fmt.Println()
+	fmt.Println("  Real commands: mur learn extract          # pick sessions interactively")
+	fmt.Println("                 mur learn extract --llm    # higher quality with an LLM")
+	if !pause() {
+		return nil
+	}
+
+	// Step 3: sync to AI tools
+	fmt.Println("Step 3/4 — Sync to your AI tools")
+	fmt.Println("────────────────────────────────")
+	fmt.Println("Sync writes a small index skill into each AI tool so it knows to ask")
+	fmt.Println("mur for patterns. In the sandbox, targets live under the fake HOME.")
+	fmt.Println()
+	results, err := sync.SyncPatternsWithFormat(context.Background(), config.Default())
+	if err != nil {
+		return fmt.Errorf("sync patterns: %w", err)
+	}
+	for i, r := range results {
+		if i == 3 {
+			fmt.Printf("  … and %d more targets\n", len(results)-3)
+			break
+		}
+		status := "✓"
+		if !r.Success {
+			status = "✗"
+		}
+		fmt.Printf("  %s %s: %s\n", status, r.Target, r.Message)
+	}
+	fmt.Printf("\n  e.g. %s\n", filepath.Join(config.ClaudeDir(sandbox.Home), "skills", "mur-index", "SKILL.md"))
+	fmt.Println()
+	fmt.Println("  Real commands: mur sync                   # once")
+	fmt.Println("                 mur sync auto enable       # on a schedule")
+	if !pause() {
+		return nil
+	}
+
+	// Step 4: search
+	fmt.Println("Step 4/4 — Search and context")
+	fmt.Println("─────────────────────────────")
+	fmt.Println("When you prompt an AI tool, mur finds the relevant patterns and")
+	fmt.Println("injects them as context. Searching for \"error\":")
+	fmt.Println()
+	matches, err := store.Search("error")
+	if err != nil {
+		return fmt.Errorf("search patterns: %w", err)
+	}
+	if len(matches) == 0 {
+		fmt.Println("  (no matches)")
+	}
+	for _, p := range matches {
+		fmt.Printf("  🔍 %s\n", p.Name)
+	}
+	fmt.Println()
+	fmt.Println("  Real commands: mur search \"error handling\"  # semantic search")
+	fmt.Println("                 mur context                  # patterns for this project")
+	fmt.Println("                 mur init --hooks             # inject automatically")
+	fmt.Println()
+
+	fmt.Println("✅ Tutorial complete")
+	fmt.Println()
+	fmt.Println("The loop: learn → sync → context. Start for real with:")
+	fmt.Println("  mur init")
+	return nil
+}
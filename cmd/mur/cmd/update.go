@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 	"github.com/mur-run/mur-core/internal/hooks"
 )
 
@@ -128,36 +130,37 @@ func updateBinary() error {
 	// Detect installation method by checking binary path
 	installMethod := detectInstallMethod()
 
-	var cmd *exec.Cmd
+	ctx := context.Background()
+	var cmd *execx.Cmd
 	switch installMethod {
 	case "homebrew":
 		fmt.Println("  📦 Detected Homebrew installation")
 		// Force-refresh the tap to ensure we see the latest release
 		fmt.Println("  ↻ Refreshing tap...")
-		refreshTap := exec.Command("brew", "tap", "--force", "mur-run/tap")
+		refreshTap := execx.Command("brew", "tap", "--force", "mur-run/tap")
 		refreshTap.Stdout = os.Stdout
 		refreshTap.Stderr = os.Stderr
-		if err := refreshTap.Run(); err != nil {
+		if _, err := refreshTap.Run(ctx); err != nil {
 			// Fallback: full brew update (slower but reliable)
 			fmt.Println("  ↻ Full brew update...")
-			fullUpdate := exec.Command("brew", "update")
+			fullUpdate := execx.Command("brew", "update")
 			fullUpdate.Stdout = os.Stdout
 			fullUpdate.Stderr = os.Stderr
-			_ = fullUpdate.Run()
+			_, _ = fullUpdate.Run(ctx)
 		}
-		cmd = exec.Command("brew", "upgrade", "mur")
+		cmd = execx.Command("brew", "upgrade", "mur")
 	case "go":
 		fmt.Println("  🐹 Detected Go installation")
-		cmd = exec.Command("go", "install", "github.com/mur-run/mur-core/cmd/mur@latest")
+		cmd = execx.Command("go", "install", "github.com/mur-run/mur-core/cmd/mur@latest")
 	default:
 		fmt.Println("  🐹 Using Go install (default)")
-		cmd = exec.Command("go", "install", "github.com/mur-run/mur-core/cmd/mur@latest")
+		cmd = execx.Command("go", "install", "github.com/mur-run/mur-core/cmd/mur@latest")
 	}
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
+	if _, err := cmd.Run(ctx); err != nil {
 		if installMethod == "homebrew" {
 			// brew upgrade returns error when already up to date
 			fmt.Println("  ✓ mur is already up to date")
@@ -252,7 +255,7 @@ func updateSkillDefinitions() error {
 		return err
 	}
 
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	skillsDir := filepath.Join(murDir, "skills")
 
 	// Create skills directory
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/selfupdate"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade mur to the latest signed release",
+	Long: `Download the latest mur release from GitHub, verify it, and replace
+the running binary in place.
+
+The archive is checked against the release checksums.txt, and that
+against its signature with the release key built into mur, before
+anything is written. A build without the key (e.g. from source) refuses
+to install unless --insecure is given, which trusts the checksums alone.
+On Windows the old binary is moved aside and removed by the next upgrade.
+
+Set upgrade.disabled: true in ~/.mur/config.yaml to block self-update
+on managed installs.
+
+Examples:
+  mur upgrade                    # Upgrade to latest stable
+  mur upgrade --check            # Only report whether an update exists
+  mur upgrade --channel beta     # Include pre-releases
+  mur upgrade --version 1.14.0   # Install a specific version`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().Bool("check", false, "Check for updates without installing")
+	upgradeCmd.Flags().String("channel", "", "Release channel: stable, beta (default from config or stable)")
+	upgradeCmd.Flags().String("version", "", "Install a specific version instead of the latest")
+	upgradeCmd.Flags().BoolP("force", "f", false, "Reinstall even if already up to date, or when installed by a package manager")
+	upgradeCmd.Flags().Bool("insecure", false, "Install without a release signature check when this build has no release key")
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	checkOnly, _ := cmd.Flags().GetBool("check")
+	channel, _ := cmd.Flags().GetString("channel")
+	version, _ := cmd.Flags().GetString("version")
+	force, _ := cmd.Flags().GetBool("force")
+	insecure, _ := cmd.Flags().GetBool("insecure")
+
+	// Remove any binary left behind by a previous Windows upgrade
+	if runtime.GOOS == "windows" {
+		selfupdate.CleanupOld()
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Upgrade.Disabled && !checkOnly {
+		return fmt.Errorf("self-update is disabled by config (upgrade.disabled); contact your administrator")
+	}
+	if channel == "" {
+		channel = cfg.Upgrade.Channel
+	}
+	if channel != "" && channel != selfupdate.ChannelStable && channel != selfupdate.ChannelBeta {
+		return fmt.Errorf("unknown channel %q (use 'stable' or 'beta')", channel)
+	}
+
+	updater := selfupdate.New(channel, ReleasePublicKey)
+
+	var release *selfupdate.Release
+	if version != "" {
+		release, err = updater.ByTag(version)
+	} else {
+		release, err = updater.Latest()
+	}
+	if err != nil {
+		return err
+	}
+
+	cmp := selfupdate.CompareVersions(release.Version(), Version)
+	fmt.Printf("Current: %s\n", Version)
+	fmt.Printf("Latest:  %s (%s)\n", release.Version(), updater.Channel)
+
+	if checkOnly {
+		if cmp > 0 {
+			fmt.Println("\n⬆ Update available. Run 'mur upgrade' to install.")
+		} else {
+			fmt.Println("\n✓ mur is up to date")
+		}
+		return nil
+	}
+
+	if cmp == 0 && !force {
+		fmt.Println("\n✓ mur is already up to date")
+		return nil
+	}
+	if cmp < 0 && version == "" && !force {
+		fmt.Println("\n✓ Installed version is newer than the latest release")
+		return nil
+	}
+
+	if method := detectInstallMethod(); method == "homebrew" && !force {
+		return fmt.Errorf("mur was installed with Homebrew; run 'brew upgrade mur' (or use --force)")
+	}
+	if ReleasePublicKey == "" && !insecure {
+		return fmt.Errorf("this build has no release key, so the release signature can't be checked; refusing to install (use --insecure to trust the checksums alone)")
+	}
+
+	fmt.Printf("\n⬇ Downloading %s...\n", selfupdate.AssetName())
+	bin, err := updater.Download(release)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+	if ReleasePublicKey != "" {
+		fmt.Println("  ✓ Signature verified")
+	} else {
+		fmt.Println("  ⚠ Signature not checked (--insecure)")
+	}
+	fmt.Println("  ✓ Checksum verified")
+
+	path, err := selfupdate.Apply(bin)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	fmt.Printf("  ✓ Installed %s to %s\n", release.Version(), path)
+	fmt.Println()
+	fmt.Println("✅ Upgrade complete! Run 'mur update hooks' to refresh hook templates.")
+	return nil
+}
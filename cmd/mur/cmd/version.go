@@ -12,6 +12,11 @@ var (
 	Version   = "1.14.12"
 	Commit    = "dev"
 	BuildDate = "unknown"
+
+	// ReleasePublicKey is the base64 ed25519 key used by `mur upgrade` to
+	// verify release signatures, set by the release build. Without it
+	// `mur upgrade` only installs with --insecure.
+	ReleasePublicKey = ""
 )
 
 var versionCmd = &cobra.Command{
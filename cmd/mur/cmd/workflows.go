@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mur-run/mur-core/internal/cloud"
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 	"github.com/mur-run/mur-core/internal/session"
 	"github.com/mur-run/mur-core/internal/workflow"
 )
@@ -29,6 +31,7 @@ Commands:
   mur workflows show <id>                     Show workflow details
   mur workflows create --from-session <id>    Create from a session
   mur workflows run <id>                      Execute workflow locally
+  mur workflows test <id> <spec.yaml>         Test against mocked commands
   mur workflows export <id>                   Export as skill/yaml/md
   mur workflows delete <id>                   Delete a workflow
   mur workflows publish <id>                  Bump published version`,
@@ -43,6 +46,13 @@ var workflowsListCmd = &cobra.Command{
 			return fmt.Errorf("list workflows: %w", err)
 		}
 
+		if out := newPrinter(cmd); out.JSON() {
+			if entries == nil {
+				entries = []workflow.IndexEntry{}
+			}
+			return out.WriteJSON(entries)
+		}
+
 		if len(entries) == 0 {
 			fmt.Println("No workflows found.")
 			fmt.Println("\nCreate one with: mur workflows create --from-session <session-id>")
@@ -83,6 +93,30 @@ var workflowsShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		perm, err := workflowAccess(wf.ID)
+		if err != nil {
+			return err
+		}
+
+		if out := newPrinter(cmd); out.JSON() {
+			result := struct {
+				*workflow.Workflow
+				Metadata *workflow.Metadata  `json:"metadata"`
+				Access   workflow.Permission `json:"access"`
+			}{wf, meta, perm}
+			if !perm.CanViewSteps() {
+				// Execute-only access hides step commands here too
+				steps := make([]session.Step, len(wf.Steps))
+				copy(steps, wf.Steps)
+				for i := range steps {
+					steps[i].Command = ""
+				}
+				hidden := *wf
+				hidden.Steps = steps
+				result.Workflow = &hidden
+			}
+			return out.WriteJSON(result)
+		}
 
 		version := "draft"
 		if meta.PublishedVersion > 0 {
@@ -94,6 +128,9 @@ var workflowsShowCmd = &cobra.Command{
 		fmt.Printf("Version:  %s (revision %d)\n", version, meta.RevisionCount)
 		fmt.Printf("Created:  %s\n", meta.CreatedAt.Format("2006-01-02 15:04"))
 		fmt.Printf("Updated:  %s\n", meta.UpdatedAt.Format("2006-01-02 15:04"))
+		if m, _ := workflow.LoadManifest(wf.ID); m != nil {
+			fmt.Printf("Access:   %s (shared via team %s)\n", perm, m.TeamID)
+		}
 
 		if wf.Trigger != "" {
 			fmt.Printf("\nTrigger: %s\n", wf.Trigger)
@@ -121,12 +158,16 @@ var workflowsShowCmd = &cobra.Command{
 					approval = " [approval required]"
 				}
 				fmt.Printf("  %d. %s%s\n", s.Order, s.Description, approval)
-				if s.Command != "" {
+				if s.Command != "" && perm.CanViewSteps() {
 					fmt.Printf("     $ %s\n", s.Command)
 				}
 			}
 		}
 
+		if !perm.CanViewSteps() {
+			fmt.Printf("\n(step commands hidden: execute-only access)\n")
+		}
+
 		if len(wf.Tools) > 0 {
 			fmt.Printf("\nTools: %s\n", strings.Join(wf.Tools, ", "))
 		}
@@ -203,7 +244,8 @@ var workflowsRunCmd = &cobra.Command{
 	Short: "Execute a workflow locally",
 	Long: `Run a workflow by executing its steps sequentially.
 
-Steps with commands are executed in a shell. Steps requiring approval
+Steps with commands are executed directly, or in a shell when they use
+pipes, redirects, variables, or globs. Steps requiring approval
 will prompt before proceeding. Steps without commands print the
 description for manual execution.`,
 	Args: cobra.ExactArgs(1),
@@ -214,67 +256,48 @@ description for manual execution.`,
 		if err != nil {
 			return err
 		}
+		perm, err := workflowAccess(wf.ID)
+		if err != nil {
+			return err
+		}
+		// Execute-only users run the steps without seeing the commands
+		shown := func(command string) string {
+			if perm.CanViewSteps() {
+				return command
+			}
+			return "(hidden)"
+		}
+
+		vars, err := workflow.ResolveVariables(wf, workflowEnv(wf))
+		if err != nil && !dryRun {
+			return err
+		}
 
 		fmt.Fprintf(os.Stderr, "Running workflow: %s\n\n", wf.Name)
 
-		for _, step := range wf.Steps {
-			fmt.Fprintf(os.Stderr, "Step %d: %s\n", step.Order, step.Description)
-
-			if step.NeedsApproval && !dryRun {
+		runner := &workflow.Runner{
+			Exec: func(ctx context.Context, command string, vars map[string]string) error {
+				_, err := runWorkflowStep(ctx, command, vars)
+				return err
+			},
+			Approve: func(step session.Step) bool {
 				fmt.Fprintf(os.Stderr, "  Requires approval. Proceed? [y/N] ")
 				var answer string
 				fmt.Scanln(&answer)
-				if answer != "y" && answer != "Y" {
-					fmt.Fprintf(os.Stderr, "  Skipped.\n\n")
-					continue
-				}
-			}
-
-			if step.Command != "" {
-				if dryRun {
-					fmt.Fprintf(os.Stderr, "  [dry-run] $ %s\n\n", step.Command)
-					continue
-				}
-
-				fmt.Fprintf(os.Stderr, "  $ %s\n", step.Command)
-				c := exec.Command("sh", "-c", step.Command)
-				c.Stdout = os.Stdout
-				c.Stderr = os.Stderr
-				c.Stdin = os.Stdin
-
-				if err := c.Run(); err != nil {
-					switch step.OnFailure {
-					case "skip":
-						fmt.Fprintf(os.Stderr, "  Failed (skipping): %v\n\n", err)
-						continue
-					case "retry":
-						fmt.Fprintf(os.Stderr, "  Failed: %v\n", err)
-						fmt.Fprintf(os.Stderr, "  Retry? [y/N] ")
-						var answer string
-						fmt.Scanln(&answer)
-						if answer == "y" || answer == "Y" {
-							c2 := exec.Command("sh", "-c", step.Command)
-							c2.Stdout = os.Stdout
-							c2.Stderr = os.Stderr
-							c2.Stdin = os.Stdin
-							if err := c2.Run(); err != nil {
-								return fmt.Errorf("step %d failed on retry: %w", step.Order, err)
-							}
-						} else {
-							return fmt.Errorf("step %d failed: %w", step.Order, err)
-						}
-					default: // abort
-						return fmt.Errorf("step %d failed: %w", step.Order, err)
-					}
-				}
-			} else {
-				if step.Tool != "" {
-					fmt.Fprintf(os.Stderr, "  (manual step, tool: %s)\n", step.Tool)
-				} else {
-					fmt.Fprintf(os.Stderr, "  (manual step)\n")
-				}
-			}
-			fmt.Fprintln(os.Stderr)
+				return answer == "y" || answer == "Y"
+			},
+			Retry: func(step session.Step, err error) bool {
+				fmt.Fprintf(os.Stderr, "  Retry? [y/N] ")
+				var answer string
+				fmt.Scanln(&answer)
+				return answer == "y" || answer == "Y"
+			},
+			Show:   shown,
+			Out:    os.Stderr,
+			DryRun: dryRun,
+		}
+		if _, err := runner.Run(context.Background(), wf, vars); err != nil {
+			return err
 		}
 
 		fmt.Fprintf(os.Stderr, "Workflow complete.\n")
@@ -282,6 +305,31 @@ description for manual execution.`,
 	},
 }
 
+// workflowEnv returns the values of wf's variables set in the environment.
+func workflowEnv(wf *workflow.Workflow) map[string]string {
+	given := make(map[string]string)
+	for _, v := range wf.Variables {
+		if value, ok := os.LookupEnv(workflow.EnvName(v.Name)); ok {
+			given[v.Name] = value
+		}
+	}
+	return given
+}
+
+// runWorkflowStep runs a step's command attached to the terminal, with the
+// workflow's variables in its environment. Commands without shell syntax
+// run directly rather than through sh.
+func runWorkflowStep(ctx context.Context, command string, vars map[string]string) (*execx.Result, error) {
+	c := execx.CommandLine(command)
+	for name, value := range vars {
+		c.Env = append(c.Env, name+"="+value)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run(ctx)
+}
+
 var workflowsExportCmd = &cobra.Command{
 	Use:   "export <id>",
 	Short: "Export a workflow as skill, YAML, or markdown",
@@ -301,6 +349,13 @@ Examples:
 		if err != nil {
 			return err
 		}
+		perm, err := workflowAccess(wf.ID)
+		if err != nil {
+			return err
+		}
+		if !perm.CanViewSteps() {
+			return fmt.Errorf("workflow %s is shared with you as execute-only and cannot be exported", wf.ID)
+		}
 
 		// Convert Workflow back to AnalysisResult for reusing session export functions
 		result := &session.AnalysisResult{
@@ -375,6 +430,9 @@ var workflowsDeleteCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if err := requireWorkflowEdit(wf.ID); err != nil {
+			return err
+		}
 
 		if !force {
 			fmt.Fprintf(os.Stderr, "Delete workflow %q (%s)? [y/N] ", wf.Name, args[0][:8])
@@ -404,6 +462,10 @@ Every edit auto-saves a revision, but only explicit publish creates
 a user-visible version number (v1, v2, v3...).`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireWorkflowEdit(args[0]); err != nil {
+			return err
+		}
+
 		version, err := workflow.Publish(args[0])
 		if err != nil {
 			return err
@@ -436,14 +498,21 @@ Requires Pro plan or higher. Uses the same auth as 'mur cloud login'.`,
 			return fmt.Errorf("no team configured. Run 'mur cloud login' first")
 		}
 
+		if err := client.Require(cloud.FeatureWorkflowSync); err != nil {
+			return err
+		}
+
 		// Pull first
 		fmt.Fprintf(os.Stderr, "Checking for server updates...\n")
 		pullResp, err := client.WorkflowPull(teamID, 0)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  Pull skipped: %v\n", err)
 		} else if len(pullResp.Workflows) > 0 {
-			fmt.Fprintf(os.Stderr, "  Pulled %d workflow(s) from server\n", len(pullResp.Workflows))
-			// TODO: apply pulled workflows to local store
+			applied, err := applyPulledWorkflows(cfg, pullResp)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "  Pulled %d workflow(s) from server\n", applied)
 		}
 
 		// Build known IDs from pull response
@@ -510,6 +579,10 @@ Permission levels:
 			return fmt.Errorf("invalid permission %q (use: read, write, execute-only)", perm)
 		}
 
+		if err := requireWorkflowEdit(args[0]); err != nil {
+			return err
+		}
+
 		// TODO: get current user email from auth
 		grantedBy := "owner"
 
@@ -532,6 +605,17 @@ Steps are concatenated in order, variables deduplicated, tools and tags unioned.
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name, _ := cmd.Flags().GetString("name")
 
+		// Merging copies steps, so every source must be fully visible
+		for _, id := range args {
+			perm, err := workflowAccess(id)
+			if err != nil {
+				return err
+			}
+			if !perm.CanViewSteps() {
+				return fmt.Errorf("workflow %s is shared with you as execute-only and cannot be merged", id)
+			}
+		}
+
 		merged, err := workflow.MergeWorkflows(args, name)
 		if err != nil {
 			return fmt.Errorf("merge workflows: %w", err)
@@ -545,6 +629,91 @@ Steps are concatenated in order, variables deduplicated, tools and tags unioned.
 	},
 }
 
+// pinSigningKey pins the server signing key on first use and rejects a key
+// that differs from the pinned one.
+func pinSigningKey(cfg *config.Config, key string) error {
+	if cfg.Server.PermissionKey == "" && key != "" {
+		cfg.Server.PermissionKey = key
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("save permission key: %w", err)
+		}
+	} else if key != "" && key != cfg.Server.PermissionKey {
+		return fmt.Errorf("server signing key does not match the pinned server.permission_key")
+	}
+	return nil
+}
+
+// applyPulledWorkflows stores pulled workflows whose signed permission
+// manifest verifies, pinning the server signing key on first use.
+func applyPulledWorkflows(cfg *config.Config, resp *workflow.WorkflowPullResponse) (int, error) {
+	if err := pinSigningKey(cfg, resp.SigningKey); err != nil {
+		return 0, fmt.Errorf("%w; refusing to apply pulled workflows", err)
+	}
+
+	manifests := make(map[string]*workflow.PermissionManifest, len(resp.Manifests))
+	for i := range resp.Manifests {
+		manifests[resp.Manifests[i].WorkflowID] = &resp.Manifests[i]
+	}
+
+	email := currentUserEmail()
+	applied := 0
+	for _, w := range resp.Workflows {
+		if w.Deleted {
+			continue
+		}
+		m := manifests[w.ID]
+		if m == nil {
+			fmt.Fprintf(os.Stderr, "  ⚠ %s: no permission manifest, skipped\n", w.Name)
+			continue
+		}
+		if err := m.Verify(cfg.Server.PermissionKey, email, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "  ⚠ %s: %v, skipped\n", w.Name, err)
+			continue
+		}
+		if err := workflow.ApplyPulled(w, m); err != nil {
+			fmt.Fprintf(os.Stderr, "  ⚠ %s: %v\n", w.Name, err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// workflowAccess resolves the current user's permission on a workflow.
+// Workflows pulled from a team are checked against their signed manifest.
+func workflowAccess(id string) (workflow.Permission, error) {
+	key := ""
+	if cfg, err := config.Load(); err == nil {
+		key = cfg.Server.PermissionKey
+	}
+	return workflow.Access(id, key, currentUserEmail())
+}
+
+// requireWorkflowEdit fails unless the current user may modify the workflow.
+func requireWorkflowEdit(id string) error {
+	perm, err := workflowAccess(id)
+	if err != nil {
+		return err
+	}
+	if !perm.CanEdit() {
+		return fmt.Errorf("workflow %s is shared with you as %s; write permission is required", id, perm)
+	}
+	return nil
+}
+
+// currentUserEmail returns the logged-in cloud user's email, if any.
+func currentUserEmail() string {
+	store, err := cloud.NewAuthStore()
+	if err != nil {
+		return ""
+	}
+	auth, err := store.Load()
+	if err != nil || auth == nil || auth.User == nil {
+		return ""
+	}
+	return auth.User.Email
+}
+
 func init() {
 	rootCmd.AddCommand(workflowsCmd)
 	workflowsCmd.AddCommand(workflowsListCmd)
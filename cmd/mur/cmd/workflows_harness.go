@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mur-run/mur-core/internal/workflow"
+)
+
+var workflowsTestCmd = &cobra.Command{
+	Use:   "test <id> <spec.yaml>",
+	Short: "Test a workflow against mocked commands",
+	Long: `Test a workflow without side effects before sharing it.
+
+Each test case runs the workflow with its commands intercepted: every
+command, with variables expanded, must match the next expected one, which
+returns the mocked output and exit code instead of running. Approval and
+retry prompts are answered yes unless the case says otherwise, so
+on_failure paths run exactly as they would for real.
+
+Spec format:
+  tests:
+    - name: deploys to staging
+      variables: {env: staging}
+      expect:
+        - run: make build
+        - run: kubectl apply -f k8s/staging
+    - name: stops when the build fails
+      expect:
+        - match: "^make (build|all)$"
+          output: "error: missing dependency"
+          exit: 2
+      result: failure        # success (default) or failure
+      failed_step: 1
+      approve: false         # answer to approval prompts
+      retry: false           # answer to retry prompts
+
+Examples:
+  mur workflows test a1b2c3d4 deploy.test.yaml
+  mur workflows test a1b2c3d4 deploy.test.yaml --junit report.xml`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWorkflowsTest,
+}
+
+func init() {
+	workflowsCmd.AddCommand(workflowsTestCmd)
+	workflowsTestCmd.Flags().String("junit", "", "Write a JUnit XML report to this file")
+	workflowsTestCmd.Flags().BoolP("verbose", "v", false, "Show each test's run log")
+}
+
+func runWorkflowsTest(cmd *cobra.Command, args []string) error {
+	junitPath, _ := cmd.Flags().GetString("junit")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	wf, _, err := workflow.Get(args[0])
+	if err != nil {
+		return err
+	}
+	perm, err := workflowAccess(wf.ID)
+	if err != nil {
+		return err
+	}
+	if !perm.CanViewSteps() {
+		return fmt.Errorf("workflow %s is shared with you as %s; testing it needs permission to view its steps", wf.ID, perm)
+	}
+	spec, err := workflow.LoadTestSpec(args[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Testing workflow: %s\n\n", wf.Name)
+	results := make([]workflow.TestResult, 0, len(spec.Tests))
+	failed := 0
+	for _, tc := range spec.Tests {
+		r := workflow.RunTest(context.Background(), wf, tc)
+		results = append(results, r)
+		if r.Passed() {
+			fmt.Printf("  ✓ %s\n", r.Name)
+		} else {
+			failed++
+			fmt.Printf("  ✗ %s\n", r.Name)
+			for _, f := range r.Failures {
+				fmt.Printf("      %s\n", f)
+			}
+		}
+		if verbose && r.Log != "" {
+			for _, line := range strings.Split(strings.TrimRight(r.Log, "\n"), "\n") {
+				fmt.Printf("      │ %s\n", line)
+			}
+		}
+	}
+
+	if junitPath != "" {
+		f, err := os.Create(junitPath)
+		if err != nil {
+			return err
+		}
+		if err := workflow.WriteJUnit(f, wf.Name, results); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("\n📄 JUnit report: %s\n", junitPath)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, len(results))
+	}
+	fmt.Printf("✓ %d tests passed\n", len(results))
+	return nil
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage which repositories' patterns are trusted",
+	Long: `A repository can ship patterns of its own in .mur/patterns/. Anyone who
+can push to it decides what they say, so mur only uses them once you've
+trusted the repository.
+
+The first time an interactive command (mur context, mur search, mur run,
+mur sync) meets a repository with patterns, it asks and remembers the
+answer. Hooks never ask: until you decide, the repository's patterns are
+not injected.
+
+Examples:
+  mur workspace list
+  mur workspace trust
+  mur workspace deny ~/src/untrusted-fork
+  mur workspace revoke`,
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trust decisions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		decisions, err := pattern.WorkspaceDecisions()
+		if err != nil {
+			return err
+		}
+		if len(decisions) == 0 {
+			fmt.Println("No trust decisions yet.")
+			return nil
+		}
+		for _, d := range decisions {
+			mark := "✗ denied "
+			if d.Trusted {
+				mark = "✓ trusted"
+			}
+			fmt.Printf("  %s  %s  (%s)\n", mark, d.Root, d.Decided.Local().Format("2006-01-02"))
+		}
+		return nil
+	},
+}
+
+var workspaceTrustCmd = &cobra.Command{
+	Use:   "trust [path]",
+	Short: "Use the patterns of a repository",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setWorkspaceTrust(args, true)
+	},
+}
+
+var workspaceDenyCmd = &cobra.Command{
+	Use:   "deny [path]",
+	Short: "Never use the patterns of a repository",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setWorkspaceTrust(args, false)
+	},
+}
+
+var workspaceRevokeCmd = &cobra.Command{
+	Use:   "revoke [path]",
+	Short: "Forget the trust decision about a repository",
+	Long: `Forget the trust decision about a repository. Its patterns are no longer
+used, and the next interactive command asks again.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := workspaceRoot(args)
+		if err != nil {
+			return err
+		}
+		found, err := pattern.RevokeWorkspaceTrust(root)
+		if err != nil {
+			return err
+		}
+		if !found {
+			fmt.Printf("No trust decision for %s\n", root)
+			return nil
+		}
+		fmt.Printf("✓ Revoked trust in %s\n", root)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceTrustCmd)
+	workspaceCmd.AddCommand(workspaceDenyCmd)
+	workspaceCmd.AddCommand(workspaceRevokeCmd)
+}
+
+// workspaceRoot returns the repository root for the path in args, or the
+// current directory: the root of the repository with patterns it is in,
+// else the path itself, e.g. a repository that no longer has any.
+func workspaceRoot(args []string) (string, error) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if projectDir := pattern.FindProjectDir(dir); projectDir != "" {
+		return pattern.ProjectRoot(projectDir), nil
+	}
+	return filepath.Abs(dir)
+}
+
+func setWorkspaceTrust(args []string, trusted bool) error {
+	root, err := workspaceRoot(args)
+	if err != nil {
+		return err
+	}
+	if err := pattern.SetWorkspaceTrust(root, trusted); err != nil {
+		return err
+	}
+	if trusted {
+		fmt.Printf("✓ Trusted %s: its .mur/patterns/ will be used\n", root)
+	} else {
+		fmt.Printf("✓ Denied %s: its .mur/patterns/ will not be used\n", root)
+	}
+	return nil
+}
+
+// projectPatternsDir returns the patterns directory of the repository dir
+// is in, or "" if it has none or isn't trusted. The first time it meets a
+// repository with patterns, and someone is at the terminal, it asks
+// whether to trust it and remembers the answer; hooks and scripts are
+// never asked, so an undecided repository's patterns aren't used.
+func projectPatternsDir(dir string) string {
+	projectDir := pattern.FindProjectDir(dir)
+	if projectDir == "" {
+		return ""
+	}
+	root := pattern.ProjectRoot(projectDir)
+	trusted, decided := pattern.WorkspaceTrust(root)
+	if !decided && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd())) {
+		trusted = askWorkspaceTrust(root, projectDir)
+	}
+	if !trusted {
+		return ""
+	}
+	return projectDir
+}
+
+// askWorkspaceTrust asks whether to trust the repository at root, on
+// stderr so that command output stays clean, and records the answer.
+// Interrupting the prompt decides nothing.
+func askWorkspaceTrust(root, projectDir string) bool {
+	patterns, _ := pattern.NewStore(projectDir).List()
+	fmt.Fprintf(os.Stderr, "\n%s has %d pattern(s) of its own in %s.\n", root, len(patterns), pattern.ProjectPatternsDir)
+	fmt.Fprintln(os.Stderr, "They come with the code: only trust repositories whose authors you trust.")
+
+	var trusted bool
+	prompt := &survey.Confirm{
+		Message: "Trust this repository and use its patterns?",
+		Default: false,
+	}
+	if err := survey.AskOne(prompt, &trusted, survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)); err != nil {
+		return false
+	}
+	if err := pattern.SetWorkspaceTrust(root, trusted); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Cannot save trust decision: %v\n", err)
+	} else if !trusted {
+		fmt.Fprintln(os.Stderr, "Not using them. Change your mind with: mur workspace trust")
+	}
+	return trusted
+}
@@ -363,6 +363,16 @@ func (s *Store) GetOverallStats(days int) (*OverallStats, error) {
 	return stats, nil
 }
 
+// FeedbackBetween counts helpful and not-helpful feedback given on days
+// in [start, end).
+func (s *Store) FeedbackBetween(start, end time.Time) (helpful, notHelpful int, err error) {
+	err = s.db.QueryRow(`
+		SELECT COALESCE(SUM(helpful_count), 0), COALESCE(SUM(not_helpful_count), 0)
+		FROM pattern_daily_stats WHERE date >= ? AND date < ?
+	`, start.Format("2006-01-02"), end.Format("2006-01-02")).Scan(&helpful, &notHelpful)
+	return helpful, notHelpful, err
+}
+
 // OverallStats holds summary metrics.
 type OverallStats struct {
 	TotalPatterns   int
@@ -0,0 +1,105 @@
+package async
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleAfter is how long a run lock may go untouched before it is taken
+// to belong to a process that died. Holders touch their lock every
+// lockRefresh while they run.
+const (
+	staleAfter  = 5 * time.Minute
+	lockRefresh = time.Minute
+)
+
+// Coalesce runs fn as the job name, with at most slots runs at a time
+// across processes; dir holds the locks. A call that finds every slot
+// busy leaves a request and returns queued without running fn. A run
+// that finishes while requests are waiting runs fn once more, so however
+// many calls overlap, a single extra pass covers them all; fn should
+// therefore do everything pending (all new sessions, all patterns), not
+// just what its own caller saw.
+//
+// The error is the last error fn returned.
+func Coalesce(dir, name string, slots int, fn func() error) (queued bool, err error) {
+	if slots < 1 {
+		slots = 1
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("cannot create lock directory: %w", err)
+	}
+	pending := filepath.Join(dir, name+".pending")
+
+	// Ask for a run before trying the locks, so a holder that releases
+	// its lock and then finds no request can't miss this one.
+	if err := os.WriteFile(pending, nil, 0644); err != nil {
+		return false, fmt.Errorf("cannot queue %s: %w", name, err)
+	}
+	ran := false
+	for {
+		lock, ok := acquire(dir, name, slots)
+		if !ok {
+			return !ran, err
+		}
+		stop := refresh(lock)
+		for os.Remove(pending) == nil {
+			ran = true
+			err = fn()
+		}
+		stop()
+		_ = os.Remove(lock)
+
+		if _, statErr := os.Stat(pending); statErr != nil {
+			return false, err
+		}
+	}
+}
+
+// acquire takes a free slot lock for name, replacing stale ones, and
+// returns its path.
+func acquire(dir, name string, slots int) (string, bool) {
+	for i := range slots {
+		lock := filepath.Join(dir, fmt.Sprintf("%s.%d.lock", name, i))
+		for attempt := 0; attempt < 2; attempt++ {
+			f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+			if err == nil {
+				fmt.Fprintf(f, "%d\n", os.Getpid())
+				f.Close()
+				return lock, true
+			}
+			if !errors.Is(err, os.ErrExist) || !stale(lock) {
+				break
+			}
+			_ = os.Remove(lock)
+		}
+	}
+	return "", false
+}
+
+// stale reports whether lock hasn't been touched for staleAfter.
+func stale(lock string) bool {
+	info, err := os.Stat(lock)
+	return err == nil && time.Since(info.ModTime()) > staleAfter
+}
+
+// refresh touches lock every lockRefresh until the returned func is called.
+func refresh(lock string) func() {
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(lockRefresh)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-t.C:
+				_ = os.Chtimes(lock, now, now)
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,107 @@
+package async
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	dir := t.TempDir()
+	var runs, running, maxRunning atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	fn := func() error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		if runs.Add(1) == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var queued atomic.Int32
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if q, err := Coalesce(dir, "sync", 1, fn); err != nil || q {
+			t.Errorf("first call: queued=%v err=%v", q, err)
+		}
+	}()
+	<-started
+
+	// Overlapping calls while the first pass runs are queued, not run
+	for range 5 {
+		if q, err := Coalesce(dir, "sync", 1, fn); err != nil {
+			t.Error(err)
+		} else if q {
+			queued.Add(1)
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if queued.Load() != 5 {
+		t.Errorf("queued = %d, want 5", queued.Load())
+	}
+	// One pass for the first call and one covering the five queued ones
+	if runs.Load() != 2 {
+		t.Errorf("runs = %d, want 2", runs.Load())
+	}
+	if maxRunning.Load() != 1 {
+		t.Errorf("ran %d at once, want 1", maxRunning.Load())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("left behind %v", entries)
+	}
+}
+
+func TestCoalesceStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "extract.0.lock")
+	os.WriteFile(lock, []byte("99999\n"), 0644)
+
+	ran := false
+	if q, err := Coalesce(dir, "extract", 1, func() error { ran = true; return nil }); err != nil || !q || ran {
+		t.Fatalf("fresh lock: queued=%v ran=%v err=%v", q, ran, err)
+	}
+
+	old := time.Now().Add(-2 * staleAfter)
+	os.Chtimes(lock, old, old)
+	if q, err := Coalesce(dir, "extract", 1, func() error { ran = true; return nil }); err != nil || q || !ran {
+		t.Errorf("stale lock: queued=%v ran=%v err=%v", q, ran, err)
+	}
+}
+
+func TestCoalesceSlots(t *testing.T) {
+	dir := t.TempDir()
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Coalesce(dir, "sync", 2, func() error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+	if q, _ := Coalesce(dir, "sync", 2, func() error { return nil }); !q {
+		t.Error("third call should queue with both slots busy")
+	}
+	close(release)
+	wg.Wait()
+}
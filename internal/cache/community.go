@@ -4,6 +4,7 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"sort"
@@ -284,5 +285,5 @@ func DefaultCommunityCache() (*CommunityCache, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return NewCommunityCache(filepath.Join(home, ".mur"), 7, 50), nil
+	return NewCommunityCache(config.CacheDir(home), 7, 50), nil
 }
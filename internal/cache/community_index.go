@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// CommunityIndexEntry is a community pattern with its embedding.
+type CommunityIndexEntry struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Author      string    `json:"author"`
+	CopyCount   int       `json:"copy_count"`
+	Embedding   []float64 `json:"embedding"`
+}
+
+// CommunityIndex is a local copy of the published community embedding
+// snapshot, used for offline semantic search.
+type CommunityIndex struct {
+	Model       string                `json:"model"`
+	Dimension   int                   `json:"dimension"`
+	GeneratedAt time.Time             `json:"generated_at"` // when the server published the snapshot
+	FetchedAt   time.Time             `json:"fetched_at"`   // when it was downloaded
+	Entries     []CommunityIndexEntry `json:"entries"`
+}
+
+// CommunityMatch is a semantic search hit in the community index.
+type CommunityMatch struct {
+	Entry CommunityIndexEntry
+	Score float64
+}
+
+// CommunityIndexPath returns the default location of the community index.
+func CommunityIndexPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.CacheDir(home), "cache", "community-index.json"), nil
+}
+
+// LoadCommunityIndex reads the index at path. It returns nil, nil if none exists.
+func LoadCommunityIndex(path string) (*CommunityIndex, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var idx CommunityIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("corrupt community index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index to path.
+func (idx *CommunityIndex) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Stale reports whether the index was fetched more than maxAge ago.
+func (idx *CommunityIndex) Stale(maxAge time.Duration) bool {
+	return time.Since(idx.FetchedAt) > maxAge
+}
+
+// Search returns the topK entries most similar to queryVec, skipping entries
+// whose dimension does not match.
+func (idx *CommunityIndex) Search(queryVec []float64, topK int) []CommunityMatch {
+	qNorm := vecNorm(queryVec)
+	if qNorm == 0 {
+		return nil
+	}
+
+	var matches []CommunityMatch
+	for _, e := range idx.Entries {
+		if len(e.Embedding) != len(queryVec) {
+			continue
+		}
+		eNorm := vecNorm(e.Embedding)
+		if eNorm == 0 {
+			continue
+		}
+		var dot float64
+		for i := range queryVec {
+			dot += queryVec[i] * e.Embedding[i]
+		}
+		matches = append(matches, CommunityMatch{Entry: e, Score: dot / (qNorm * eNorm)})
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if topK > 0 && len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches
+}
+
+func vecNorm(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommunityIndexSearch(t *testing.T) {
+	idx := &CommunityIndex{
+		Entries: []CommunityIndexEntry{
+			{ID: "a", Name: "retry-backoff", Embedding: []float64{1, 0, 0}},
+			{ID: "b", Name: "swift-concurrency", Embedding: []float64{0, 1, 0}},
+			{ID: "c", Name: "http-retry", Embedding: []float64{0.9, 0.1, 0}},
+			{ID: "d", Name: "wrong-dim", Embedding: []float64{1, 0}},
+		},
+	}
+
+	matches := idx.Search([]float64{1, 0, 0}, 2)
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Entry.ID != "a" || matches[1].Entry.ID != "c" {
+		t.Errorf("unexpected order: %s, %s", matches[0].Entry.ID, matches[1].Entry.ID)
+	}
+	if matches[0].Score < 0.99 {
+		t.Errorf("expected exact match score ~1, got %f", matches[0].Score)
+	}
+
+	if got := idx.Search([]float64{0, 0, 0}, 2); got != nil {
+		t.Error("zero query vector should return no matches")
+	}
+}
+
+func TestCommunityIndexSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "community-index.json")
+
+	idx, err := LoadCommunityIndex(path)
+	if err != nil || idx != nil {
+		t.Fatalf("missing index should return nil, nil; got %v, %v", idx, err)
+	}
+
+	orig := &CommunityIndex{
+		Model:     "nomic-embed-text",
+		Dimension: 2,
+		FetchedAt: time.Now().Add(-8 * 24 * time.Hour),
+		Entries:   []CommunityIndexEntry{{ID: "a", Name: "p", Embedding: []float64{1, 0}}},
+	}
+	if err := orig.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = LoadCommunityIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Model != orig.Model || len(idx.Entries) != 1 {
+		t.Errorf("round trip mismatch: %+v", idx)
+	}
+	if !idx.Stale(7 * 24 * time.Hour) {
+		t.Error("8-day-old index should be stale")
+	}
+}
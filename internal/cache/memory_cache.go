@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/mur-run/mur-core/internal/config"
 )
 
 // MemoryCache is the top-level in-process cache that holds both patterns
@@ -39,14 +41,14 @@ type MemoryCacheOptions struct {
 // the primary patterns dir and the repo patterns dir.
 func DefaultMemoryCacheOptions() MemoryCacheOptions {
 	home, _ := os.UserHomeDir()
-	dirs := []string{filepath.Join(home, ".mur", "patterns")}
-	repoDir := filepath.Join(home, ".mur", "repo", "patterns")
+	dirs := []string{filepath.Join(config.DataDir(home), "patterns")}
+	repoDir := filepath.Join(config.DataDir(home), "repo", "patterns")
 	if info, err := os.Stat(repoDir); err == nil && info.IsDir() {
 		dirs = append(dirs, repoDir)
 	}
 	return MemoryCacheOptions{
 		PatternsDirs:   dirs,
-		EmbeddingsDir:  filepath.Join(home, ".mur", "embeddings"),
+		EmbeddingsDir:  filepath.Join(config.CacheDir(home), "embeddings"),
 		EmbeddingDim:   768,
 		LazyEmbeddings: true,
 	}
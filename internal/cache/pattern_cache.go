@@ -53,7 +53,7 @@ func (c *PatternCache) Load(dirs ...string) error {
 	return nil
 }
 
-// loadDir reads all .yaml files from a directory into the cache.
+// loadDir reads all pattern files from a directory into the cache.
 // Caller must hold c.mu.
 func (c *PatternCache) loadDir(dir string) error {
 	entries, err := os.ReadDir(dir)
@@ -62,11 +62,11 @@ func (c *PatternCache) loadDir(dir string) error {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !pattern.IsPatternFile(entry.Name()) {
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		data, err := pattern.ReadFile(filepath.Join(dir, entry.Name()))
 		if err != nil {
 			continue
 		}
@@ -3,6 +3,7 @@ package cloud
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"time"
@@ -39,7 +40,7 @@ func NewAuthStore() (*AuthStore, error) {
 		return nil, fmt.Errorf("failed to get home dir: %w", err)
 	}
 
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	if err := os.MkdirAll(murDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create .mur dir: %w", err)
 	}
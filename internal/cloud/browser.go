@@ -1,9 +1,11 @@
 package cloud
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"runtime"
+
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 // OpenURL opens the specified URL in the default browser.
@@ -19,11 +21,12 @@ func OpenURL(url string) error {
 		cmd = "xdg-open"
 		args = []string{url}
 	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start", url}
+		cmd = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler", url}
 	default:
 		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
 
-	return exec.Command(cmd, args...).Start()
+	_, err := execx.Run(context.Background(), cmd, args...)
+	return err
 }
@@ -0,0 +1,83 @@
+package cloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChangelogEntry is an entry of a team pattern's changelog, added by the
+// member who pushed the update.
+type ChangelogEntry struct {
+	At      time.Time `json:"at"`
+	Author  string    `json:"author,omitempty"`
+	Summary string    `json:"summary"`
+	Note    string    `json:"note,omitempty"`
+}
+
+// DiffSummary summarizes how p differs from its merge base, e.g.
+// "content +3 -1 lines, tags". Patterns never synced are "created".
+func DiffSummary(base *SyncBase, p *Pattern) string {
+	if base == nil {
+		return "created"
+	}
+	b := base.pattern()
+	var parts []string
+	for _, field := range syncedFields {
+		if fieldValue(b, field) == fieldValue(p, field) {
+			continue
+		}
+		switch field {
+		case "name":
+			parts = append(parts, "renamed from "+b.Name)
+		case "content":
+			added, removed := lineDiff(b.Content, p.Content)
+			parts = append(parts, fmt.Sprintf("content +%d -%d lines", added, removed))
+		default:
+			parts = append(parts, field)
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lineDiff counts the lines of b not in a, and of a not in b.
+func lineDiff(a, b string) (added, removed int) {
+	count := make(map[string]int)
+	for _, l := range strings.Split(a, "\n") {
+		count[l]++
+	}
+	for _, l := range strings.Split(b, "\n") {
+		if count[l] > 0 {
+			count[l]--
+		} else {
+			added++
+		}
+	}
+	for _, n := range count {
+		removed += n
+	}
+	return added, removed
+}
+
+// UnseenChanges returns the entries of a pulled changelog that the local
+// copy doesn't have yet, oldest first.
+func UnseenChanges(local, pulled []ChangelogEntry) []ChangelogEntry {
+	seen := make(map[string]bool, len(local))
+	for _, e := range local {
+		seen[e.key()] = true
+	}
+	var unseen []ChangelogEntry
+	for _, e := range pulled {
+		if !seen[e.key()] {
+			unseen = append(unseen, e)
+		}
+	}
+	return unseen
+}
+
+func (e ChangelogEntry) key() string {
+	return e.At.UTC().Format(time.RFC3339) + "\x00" + e.Author + "\x00" + e.Summary
+}
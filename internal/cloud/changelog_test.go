@@ -0,0 +1,41 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSummary(t *testing.T) {
+	p := &Pattern{ID: "p1", Name: "go-errors", Content: "a\nb\nc"}
+	ts := &TeamSyncState{Patterns: make(map[string]*SyncBase)}
+	if got := DiffSummary(ts.Base(p), p); got != "created" {
+		t.Errorf("never synced = %q", got)
+	}
+	ts.SetBase(p, 1)
+
+	edited := *p
+	edited.Name = "go-error-wrapping"
+	edited.Content = "a\nc\nd\ne"
+	edited.Tags = map[string]any{"confirmed": []string{"go"}}
+	want := "renamed from go-errors, content +2 -1 lines, tags"
+	if got := DiffSummary(ts.Base(p), &edited); got != want {
+		t.Errorf("DiffSummary = %q, want %q", got, want)
+	}
+}
+
+func TestUnseenChanges(t *testing.T) {
+	at := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	old := ChangelogEntry{At: at, Author: "ana", Summary: "created"}
+	newer := ChangelogEntry{At: at.Add(time.Hour), Author: "bo", Summary: "content +1 -0 lines"}
+
+	// The same instant in another zone is the same entry
+	local := []ChangelogEntry{{At: at.In(time.FixedZone("X", 3600)), Author: "ana", Summary: "created"}}
+	got := UnseenChanges(local, []ChangelogEntry{old, newer})
+	if !reflect.DeepEqual(got, []ChangelogEntry{newer}) {
+		t.Errorf("UnseenChanges = %+v", got)
+	}
+	if got := UnseenChanges(nil, nil); got != nil {
+		t.Errorf("UnseenChanges(nil, nil) = %+v", got)
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
 )
 
 const (
@@ -21,6 +24,11 @@ type Client struct {
 	httpClient *http.Client
 	authStore  *AuthStore
 	deviceInfo *DeviceInfo
+
+	// Cached /api/version handshake (see ServerInfo)
+	serverOnce sync.Once
+	server     *ServerInfo
+	serverErr  error
 }
 
 // NewClient creates a new API client
@@ -384,18 +392,48 @@ type Pattern struct {
 	PatternVersion string `json:"pattern_version,omitempty"`
 	SchemaVersion  int    `json:"schema_version,omitempty"`
 	EmbeddingHash  string `json:"embedding_hash,omitempty"`
+	// Changelog of team updates, oldest first
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
 }
 
 // PullResponse represents pull response
 type PullResponse struct {
 	Patterns []Pattern `json:"patterns"`
 	Version  int64     `json:"version"`
+	// NextCursor continues a paginated pull; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
-// Pull pulls patterns since a version
+// DefaultPullPageSize is how many patterns a pull asks for per page.
+const DefaultPullPageSize = 200
+
+// Pull pulls all patterns since a version, page by page.
 func (c *Client) Pull(teamID string, sinceVersion int64) (*PullResponse, error) {
+	all := &PullResponse{}
+	cursor := ""
+	for {
+		page, err := c.PullPage(teamID, sinceVersion, cursor, DefaultPullPageSize)
+		if err != nil {
+			return nil, err
+		}
+		all.Patterns = append(all.Patterns, page.Patterns...)
+		all.Version = page.Version
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// PullPage pulls one page of at most limit patterns changed since a
+// version. An empty cursor starts the pull; the NextCursor of a page
+// continues it. Servers without pagination return everything at once.
+func (c *Client) PullPage(teamID string, sinceVersion int64, cursor string, limit int) (*PullResponse, error) {
 	var resp PullResponse
-	path := fmt.Sprintf("/api/v1/core/teams/%s/sync/pull?since=%d", teamID, sinceVersion)
+	path := fmt.Sprintf("/api/v1/core/teams/%s/sync/pull?since=%d&limit=%d", teamID, sinceVersion, limit)
+	if cursor != "" {
+		path += "&cursor=" + url.QueryEscape(cursor)
+	}
 	if err := c.get(path, &resp); err != nil {
 		return nil, err
 	}
@@ -441,6 +479,26 @@ func (c *Client) Push(teamID string, req PushRequest) (*PushResponse, error) {
 	return &resp, nil
 }
 
+// PolicyResponse carries the team's signed settings policy (nil if the team
+// has none) and the server's base64 ed25519 public key.
+type PolicyResponse struct {
+	Policy     *config.Policy `json:"policy"`
+	SigningKey string         `json:"signing_key,omitempty"`
+}
+
+// GetTeamPolicy returns the team's managed settings policy
+func (c *Client) GetTeamPolicy(teamID string) (*PolicyResponse, error) {
+	if err := c.Require(FeatureTeamPolicy); err != nil {
+		return nil, err
+	}
+	var resp PolicyResponse
+	path := fmt.Sprintf("/api/v1/core/teams/%s/policy", teamID)
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // === Device Methods ===
 
 // ListDevices returns all devices for the current user
@@ -635,6 +693,35 @@ func (c *Client) GetCommunityPattern(id string) (*CommunityPatternDetail, error)
 	return &resp, nil
 }
 
+// CommunityEmbedding is a public community pattern with its published embedding
+type CommunityEmbedding struct {
+	CommunityPattern
+	Embedding []float64 `json:"embedding"`
+}
+
+// CommunityEmbeddingSnapshot is a periodically published embedding snapshot
+// of public community patterns for a single embedding model
+type CommunityEmbeddingSnapshot struct {
+	Model       string               `json:"model"`
+	Dimension   int                  `json:"dimension"`
+	GeneratedAt time.Time            `json:"generated_at"`
+	Patterns    []CommunityEmbedding `json:"patterns"`
+}
+
+// GetCommunityEmbeddings downloads the latest community embedding snapshot
+// for the given embedding model
+func (c *Client) GetCommunityEmbeddings(model string) (*CommunityEmbeddingSnapshot, error) {
+	if err := c.Require(FeatureEmbeddingSnapshots); err != nil {
+		return nil, err
+	}
+	var resp CommunityEmbeddingSnapshot
+	path := fmt.Sprintf("/api/v1/core/community/embeddings?model=%s", url.QueryEscape(model))
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // CopyPattern copies a community pattern to user's team
 func (c *Client) CopyPattern(patternID, teamID string) (*Pattern, error) {
 	req := map[string]string{"team_id": teamID}
@@ -731,6 +818,9 @@ type TranslatePatternResponse struct {
 
 // TranslatePattern translates pattern content to English using the server's LLM
 func (c *Client) TranslatePattern(req *TranslatePatternRequest) (*TranslatePatternResponse, error) {
+	if err := c.Require(FeatureTranslation); err != nil {
+		return nil, err
+	}
 	var resp TranslatePatternResponse
 	if err := c.post("/api/v1/core/community/translate", req, &resp); err != nil {
 		return nil, err
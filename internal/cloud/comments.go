@@ -0,0 +1,90 @@
+package cloud
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Comment is a comment on a team pattern. Replies have the ID of the
+// comment they answer as ParentID.
+type Comment struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Author    string    `json:"author"` // display name or email
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentsResponse is the server's list of comments on a pattern.
+type CommentsResponse struct {
+	Comments []Comment `json:"comments"`
+}
+
+// PostCommentRequest is a new comment, or with ParentID, a reply.
+type PostCommentRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+func commentsPath(teamID, patternID string) string {
+	return fmt.Sprintf("/api/v1/core/teams/%s/patterns/%s/comments", teamID, url.PathEscape(patternID))
+}
+
+// ListComments returns the comments on a team pattern, oldest first.
+// patternID is the pattern's ID, or its name for patterns pushed without
+// one.
+func (c *Client) ListComments(teamID, patternID string) ([]Comment, error) {
+	if err := c.Require(FeaturePatternComments); err != nil {
+		return nil, err
+	}
+	var resp CommentsResponse
+	if err := c.get(commentsPath(teamID, patternID), &resp); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(resp.Comments, func(i, j int) bool {
+		return resp.Comments[i].CreatedAt.Before(resp.Comments[j].CreatedAt)
+	})
+	return resp.Comments, nil
+}
+
+// PostComment adds a comment to a team pattern, or a reply to the
+// comment with ID parentID, and returns it.
+func (c *Client) PostComment(teamID, patternID, body, parentID string) (*Comment, error) {
+	if err := c.Require(FeaturePatternComments); err != nil {
+		return nil, err
+	}
+	var comment Comment
+	req := PostCommentRequest{Body: body, ParentID: parentID}
+	if err := c.post(commentsPath(teamID, patternID), req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// CommentThread is a comment with its replies.
+type CommentThread struct {
+	Comment
+	Replies []*CommentThread `json:"replies,omitempty"`
+}
+
+// Threads arranges comments into threads, keeping their order. Replies
+// to comments that aren't in the list (deleted ones) become threads of
+// their own.
+func Threads(comments []Comment) []*CommentThread {
+	byID := make(map[string]*CommentThread, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = &CommentThread{Comment: c}
+	}
+	var threads []*CommentThread
+	for _, c := range comments {
+		t := byID[c.ID]
+		if parent, ok := byID[c.ParentID]; ok && c.ParentID != "" && parent != t {
+			parent.Replies = append(parent.Replies, t)
+			continue
+		}
+		threads = append(threads, t)
+	}
+	return threads
+}
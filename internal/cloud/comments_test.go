@@ -0,0 +1,65 @@
+package cloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThreads(t *testing.T) {
+	comments := []Comment{
+		{ID: "1", Body: "first"},
+		{ID: "2", ParentID: "1", Body: "reply"},
+		{ID: "3", Body: "second"},
+		{ID: "4", ParentID: "2", Body: "reply to reply"},
+		{ID: "5", ParentID: "gone", Body: "orphan"},
+	}
+	threads := Threads(comments)
+	if len(threads) != 3 || threads[0].ID != "1" || threads[1].ID != "3" || threads[2].ID != "5" {
+		t.Fatalf("threads = %+v", threads)
+	}
+	if r := threads[0].Replies; len(r) != 1 || r[0].ID != "2" || len(r[0].Replies) != 1 || r[0].Replies[0].ID != "4" {
+		t.Errorf("replies of 1 = %+v", r)
+	}
+}
+
+func TestCommentEndpoints(t *testing.T) {
+	var posted PostCommentRequest
+	now := time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.7"}`))
+	})
+	mux.HandleFunc("/api/v1/core/teams/t1/patterns/p1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			_ = json.NewDecoder(r.Body).Decode(&posted)
+			_ = json.NewEncoder(w).Encode(Comment{ID: "9", ParentID: posted.ParentID, Body: posted.Body})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(CommentsResponse{Comments: []Comment{
+			{ID: "2", Body: "newer", CreatedAt: now},
+			{ID: "1", Body: "older", CreatedAt: now.Add(-time.Hour)},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}}
+
+	comments, err := c.ListComments("t1", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].ID != "1" {
+		t.Errorf("comments = %+v, want oldest first", comments)
+	}
+	comment, err := c.PostComment("t1", "p1", "agreed", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posted.Body != "agreed" || posted.ParentID != "1" || comment.ID != "9" {
+		t.Errorf("posted %+v, got %+v", posted, comment)
+	}
+}
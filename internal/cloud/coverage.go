@@ -0,0 +1,169 @@
+package cloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InventoryEntry is one pattern in a pattern inventory: its name and a
+// hash of its content, never the content itself.
+type InventoryEntry struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+// ContentHash is the hash used in inventories: the first 16 hex digits of
+// the SHA-256 of the trimmed content, as the server computes it for team
+// patterns. Content is trimmed because sync trims it on push.
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Inventory is what a member's client reports for coverage: which team
+// patterns it has and their hashes.
+type Inventory struct {
+	Patterns      []InventoryEntry `json:"patterns"`
+	SyncedVersion int64            `json:"synced_version"` // team version last pulled
+	ReportedAt    time.Time        `json:"reported_at"`
+}
+
+// MemberInventory is a member's last reported inventory.
+type MemberInventory struct {
+	Member string `json:"member"` // display name or email
+	Inventory
+}
+
+// TeamCoverage is the server's coverage data. Patterns is the team's
+// current patterns; Members is only filled for team admins.
+type TeamCoverage struct {
+	Patterns []InventoryEntry  `json:"patterns"`
+	Members  []MemberInventory `json:"members,omitempty"`
+}
+
+// UploadInventory reports this client's inventory to the team. The server
+// keeps one inventory per member and device, replacing the previous one.
+func (c *Client) UploadInventory(teamID string, inv Inventory) error {
+	if err := c.Require(FeatureTeamCoverage); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v1/core/teams/%s/coverage/inventory", teamID)
+	return c.post(path, inv, nil)
+}
+
+// DeleteInventory removes this client's inventory from the team.
+func (c *Client) DeleteInventory(teamID string) error {
+	if err := c.Require(FeatureTeamCoverage); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v1/core/teams/%s/coverage/inventory", teamID)
+	return c.delete(path)
+}
+
+// GetTeamCoverage returns the team's patterns and, for admins, every
+// member's last reported inventory.
+func (c *Client) GetTeamCoverage(teamID string) (*TeamCoverage, error) {
+	if err := c.Require(FeatureTeamCoverage); err != nil {
+		return nil, err
+	}
+	var tc TeamCoverage
+	path := fmt.Sprintf("/api/v1/core/teams/%s/coverage", teamID)
+	if err := c.get(path, &tc); err != nil {
+		return nil, err
+	}
+	return &tc, nil
+}
+
+// TeamInventory builds the inventory to upload from local patterns
+// (name → content). Only patterns the team also has are included, so
+// private pattern names never leave the machine.
+func TeamInventory(local map[string]string, team []InventoryEntry) []InventoryEntry {
+	var entries []InventoryEntry
+	for _, t := range team {
+		if content, ok := local[t.Name]; ok {
+			entries = append(entries, InventoryEntry{Name: t.Name, Hash: ContentHash(content)})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// MemberCoverage is how well one member's store covers the team's patterns.
+type MemberCoverage struct {
+	Member     string    `json:"member"`
+	ReportedAt time.Time `json:"reported_at"`
+	Current    []string  `json:"current"` // same content as the team's
+	Stale      []string  `json:"stale"`   // present, but with different content
+	Missing    []string  `json:"missing"` // not present
+}
+
+// Percent returns the share of team patterns the member has current.
+func (m MemberCoverage) Percent() float64 {
+	total := len(m.Current) + len(m.Stale) + len(m.Missing)
+	if total == 0 {
+		return 100
+	}
+	return float64(len(m.Current)) / float64(total) * 100
+}
+
+// PatternCoverage is how far one team pattern has propagated.
+type PatternCoverage struct {
+	Name    string `json:"name"`
+	Current int    `json:"current"` // members with the team's content
+	Stale   int    `json:"stale"`   // members with other content
+	Missing int    `json:"missing"` // members without it
+}
+
+// CoverageReport compares every member's inventory with the team patterns.
+type CoverageReport struct {
+	Members  []MemberCoverage  `json:"members"`  // least covered first
+	Patterns []PatternCoverage `json:"patterns"` // least propagated first
+}
+
+// ComputeCoverage builds the coverage report for tc.
+func ComputeCoverage(tc *TeamCoverage) CoverageReport {
+	var report CoverageReport
+	perPattern := make(map[string]*PatternCoverage, len(tc.Patterns))
+	for _, p := range tc.Patterns {
+		perPattern[p.Name] = &PatternCoverage{Name: p.Name}
+	}
+
+	for _, m := range tc.Members {
+		have := make(map[string]string, len(m.Patterns))
+		for _, e := range m.Patterns {
+			have[e.Name] = e.Hash
+		}
+		mc := MemberCoverage{Member: m.Member, ReportedAt: m.ReportedAt}
+		for _, p := range tc.Patterns {
+			pc := perPattern[p.Name]
+			hash, ok := have[p.Name]
+			switch {
+			case !ok:
+				mc.Missing = append(mc.Missing, p.Name)
+				pc.Missing++
+			case hash == p.Hash:
+				mc.Current = append(mc.Current, p.Name)
+				pc.Current++
+			default:
+				mc.Stale = append(mc.Stale, p.Name)
+				pc.Stale++
+			}
+		}
+		report.Members = append(report.Members, mc)
+	}
+
+	for _, p := range tc.Patterns {
+		report.Patterns = append(report.Patterns, *perPattern[p.Name])
+	}
+	sort.SliceStable(report.Members, func(i, j int) bool {
+		return report.Members[i].Percent() < report.Members[j].Percent()
+	})
+	sort.SliceStable(report.Patterns, func(i, j int) bool {
+		return report.Patterns[i].Current < report.Patterns[j].Current
+	})
+	return report
+}
@@ -0,0 +1,121 @@
+package cloud
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestTeamInventory(t *testing.T) {
+	local := map[string]string{
+		"go-errors":      "wrap errors\n",
+		"private-notes":  "not for the team",
+		"docker-caching": "old content",
+	}
+	team := []InventoryEntry{
+		{Name: "go-errors", Hash: ContentHash("wrap errors")},
+		{Name: "docker-caching", Hash: ContentHash("new content")},
+		{Name: "k8s-probes", Hash: ContentHash("probes")},
+	}
+
+	inv := TeamInventory(local, team)
+	if len(inv) != 2 || inv[0].Name != "docker-caching" || inv[1].Name != "go-errors" {
+		t.Fatalf("inventory = %+v", inv)
+	}
+	for _, e := range inv {
+		if e.Name == "private-notes" {
+			t.Error("inventory includes a pattern the team doesn't have")
+		}
+	}
+	if inv[1].Hash != team[0].Hash {
+		t.Error("trailing whitespace should not change the hash")
+	}
+}
+
+func TestComputeCoverage(t *testing.T) {
+	a, b := ContentHash("a"), ContentHash("b")
+	tc := &TeamCoverage{
+		Patterns: []InventoryEntry{{Name: "one", Hash: a}, {Name: "two", Hash: b}},
+		Members: []MemberInventory{
+			{Member: "alice", Inventory: Inventory{Patterns: []InventoryEntry{{Name: "one", Hash: a}, {Name: "two", Hash: b}}}},
+			{Member: "bob", Inventory: Inventory{Patterns: []InventoryEntry{{Name: "one", Hash: b}}}},
+		},
+	}
+
+	r := ComputeCoverage(tc)
+	if len(r.Members) != 2 || r.Members[0].Member != "bob" {
+		t.Fatalf("members = %+v, want bob first", r.Members)
+	}
+	bob := r.Members[0]
+	if len(bob.Current) != 0 || len(bob.Stale) != 1 || len(bob.Missing) != 1 || bob.Percent() != 0 {
+		t.Errorf("bob = %+v", bob)
+	}
+	if alice := r.Members[1]; alice.Percent() != 100 {
+		t.Errorf("alice = %+v", alice)
+	}
+	for _, p := range r.Patterns {
+		if p.Name == "two" && (p.Current != 1 || p.Missing != 1) {
+			t.Errorf("two = %+v", p)
+		}
+		if p.Name == "one" && (p.Current != 1 || p.Stale != 1) {
+			t.Errorf("one = %+v", p)
+		}
+	}
+}
+
+func TestCoverageEndpoints(t *testing.T) {
+	var uploaded Inventory
+	deleted := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.6"}`))
+	})
+	mux.HandleFunc("/api/v1/core/teams/t1/coverage", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"patterns":[{"name":"one","hash":"abc"}]}`))
+	})
+	mux.HandleFunc("/api/v1/core/teams/t1/coverage/inventory", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			_ = json.NewDecoder(r.Body).Decode(&uploaded)
+		case "DELETE":
+			deleted = true
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}}
+
+	tc, err := c.GetTeamCoverage("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tc.Patterns) != 1 || len(tc.Members) != 0 {
+		t.Errorf("coverage = %+v", tc)
+	}
+	if err := c.UploadInventory("t1", Inventory{Patterns: []InventoryEntry{{Name: "one", Hash: "abc"}}, SyncedVersion: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaded.Patterns) != 1 || uploaded.SyncedVersion != 7 {
+		t.Errorf("uploaded = %+v", uploaded)
+	}
+	if err := c.DeleteInventory("t1"); err != nil || !deleted {
+		t.Errorf("DeleteInventory: err=%v, deleted=%v", err, deleted)
+	}
+}
+
+func TestCoverageNeedsNewerServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.5"}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}}
+
+	_, err := c.GetTeamCoverage("t1")
+	var unsupported *UnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Feature != FeatureTeamCoverage {
+		t.Errorf("err = %v, want UnsupportedError for team coverage", err)
+	}
+}
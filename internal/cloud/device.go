@@ -1,11 +1,13 @@
 package cloud
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 	"os"
-	"os/exec"
 	"os/user"
 	"path/filepath"
 	"runtime"
@@ -71,8 +73,8 @@ func generateDeviceID() string {
 func getDeviceName() string {
 	// On macOS, try scutil --get ComputerName first
 	if runtime.GOOS == "darwin" {
-		if out, err := exec.Command("scutil", "--get", "ComputerName").Output(); err == nil {
-			name := strings.TrimSpace(string(out))
+		if res, err := execx.Run(context.Background(), "scutil", "--get", "ComputerName"); err == nil {
+			name := strings.TrimSpace(res.Stdout)
 			if name != "" {
 				return name
 			}
@@ -90,7 +92,7 @@ func getDeviceName() string {
 // getMurConfigDir returns the mur config directory
 func getMurConfigDir() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".mur")
+	return config.DataDir(home)
 }
 
 // Device represents a device from the server
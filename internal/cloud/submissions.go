@@ -0,0 +1,103 @@
+package cloud
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Submission review statuses.
+const (
+	SubmissionPending   = "pending"
+	SubmissionApproved  = "approved"
+	SubmissionRejected  = "rejected"
+	SubmissionWithdrawn = "withdrawn"
+)
+
+// Submission is one of the current user's community submissions and its
+// review outcome.
+type Submission struct {
+	ID            string     `json:"id"`
+	PatternID     string     `json:"pattern_id"` // the team pattern that was shared
+	Name          string     `json:"name"`
+	Category      string     `json:"category,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	Status        string     `json:"status"`
+	ReviewerNotes string     `json:"reviewer_notes,omitempty"`
+	Reviewer      string     `json:"reviewer,omitempty"`
+	Revision      int        `json:"revision"` // 1 for the first submission, +1 per resubmission
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// CanResubmit reports whether the submission can be sent for review again.
+func (s *Submission) CanResubmit() bool {
+	return s.Status == SubmissionRejected || s.Status == SubmissionWithdrawn
+}
+
+// SubmissionsResponse is the response from listing submissions.
+type SubmissionsResponse struct {
+	Submissions []Submission `json:"submissions"`
+	Count       int          `json:"count"`
+}
+
+// ListMySubmissions lists the current user's community submissions,
+// optionally only those with status.
+func (c *Client) ListMySubmissions(status string) ([]Submission, error) {
+	if err := c.Require(FeatureSubmissionReview); err != nil {
+		return nil, err
+	}
+	path := "/api/v1/core/community/submissions/mine"
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	var resp SubmissionsResponse
+	if err := c.get(path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Submissions, nil
+}
+
+// GetSubmission returns one of the current user's submissions.
+func (c *Client) GetSubmission(id string) (*Submission, error) {
+	if err := c.Require(FeatureSubmissionReview); err != nil {
+		return nil, err
+	}
+	var sub Submission
+	path := fmt.Sprintf("/api/v1/core/community/submissions/%s", url.PathEscape(id))
+	if err := c.get(path, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// WithdrawSubmission withdraws a submission. Pending submissions leave the
+// review queue; approved ones are unpublished.
+func (c *Client) WithdrawSubmission(id string) error {
+	if err := c.Require(FeatureSubmissionReview); err != nil {
+		return err
+	}
+	return c.delete(fmt.Sprintf("/api/v1/core/community/submissions/%s", url.PathEscape(id)))
+}
+
+// ResubmitRequest sends a rejected or withdrawn submission back for review.
+// The server picks up the current version of the team pattern.
+type ResubmitRequest struct {
+	Note        string   `json:"note,omitempty"` // reply to the reviewer
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// ResubmitSubmission sends a submission back for review.
+func (c *Client) ResubmitSubmission(id string, req *ResubmitRequest) (*Submission, error) {
+	if err := c.Require(FeatureSubmissionReview); err != nil {
+		return nil, err
+	}
+	var sub Submission
+	path := fmt.Sprintf("/api/v1/core/community/submissions/%s/resubmit", url.PathEscape(id))
+	if err := c.post(path, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
@@ -0,0 +1,68 @@
+package cloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubmissions(t *testing.T) {
+	var withdrawn string
+	var resubmit ResubmitRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.5"}`))
+	})
+	mux.HandleFunc("/api/v1/core/community/submissions/mine", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("status"); got != "rejected" {
+			t.Errorf("status query = %q, want rejected", got)
+		}
+		_, _ = w.Write([]byte(`{"submissions":[{"id":"s1","name":"retry","status":"rejected",
+			"reviewer_notes":"remove hostnames","revision":1,"submitted_at":"2026-10-01T00:00:00Z",
+			"reviewed_at":"2026-10-02T00:00:00Z"}],"count":1}`))
+	})
+	mux.HandleFunc("/api/v1/core/community/submissions/s1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			withdrawn = "s1"
+		}
+	})
+	mux.HandleFunc("/api/v1/core/community/submissions/s1/resubmit", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&resubmit)
+		_, _ = w.Write([]byte(`{"id":"s1","name":"retry","status":"pending","revision":2}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}}
+
+	subs, err := c.ListMySubmissions(SubmissionRejected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].ReviewerNotes != "remove hostnames" || subs[0].ReviewedAt == nil || !subs[0].CanResubmit() {
+		t.Errorf("ListMySubmissions = %+v", subs)
+	}
+
+	if err := c.WithdrawSubmission("s1"); err != nil || withdrawn != "s1" {
+		t.Errorf("WithdrawSubmission: err=%v, withdrawn=%q", err, withdrawn)
+	}
+
+	sub, err := c.ResubmitSubmission("s1", &ResubmitRequest{Note: "fixed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resubmit.Note != "fixed" || sub.Status != SubmissionPending || sub.Revision != 2 || sub.CanResubmit() {
+		t.Errorf("ResubmitSubmission sent %+v, got %+v", resubmit, sub)
+	}
+}
+
+func TestSubmissionsNeedNewerServer(t *testing.T) {
+	c, _ := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.4"}`))
+	})
+	_, err := c.ListMySubmissions("")
+	if err == nil || err.Error() != "server 1.4 does not support submission review (requires 1.5 or later)" {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package cloud
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyncState is what the last sync with each team left behind: the team
+// version it reached and, per pattern, the merge base, the pattern as
+// both sides last agreed on it. It lives in sync-state.yaml in the state
+// directory.
+type SyncState struct {
+	Teams map[string]*TeamSyncState `yaml:"teams"`
+}
+
+// TeamSyncState is the sync state for one team.
+type TeamSyncState struct {
+	Version  int64                `yaml:"version"`
+	Patterns map[string]*SyncBase `yaml:"patterns,omitempty"` // by SyncKey
+	// Pull is the checkpoint of a paginated pull that didn't finish
+	Pull *PullCheckpoint `yaml:"pull,omitempty"`
+}
+
+// PullCheckpoint records how far a paginated pull got: the version it
+// pulls changes since, the cursor of the next page, and how many
+// patterns the pages before it applied. Version only moves once the
+// last page is applied, so a pull that is cut short resumes at Cursor.
+type PullCheckpoint struct {
+	Since   int64  `yaml:"since"`
+	Cursor  string `yaml:"cursor"`
+	Applied int    `yaml:"applied"`
+}
+
+// ResumeCursor returns the cursor an interrupted pull of the changes
+// since a version left off at, and how many patterns it applied; "" if
+// there is none to resume.
+func (ts *TeamSyncState) ResumeCursor(since int64) (string, int) {
+	if ts.Pull == nil || ts.Pull.Since != since {
+		return "", 0
+	}
+	return ts.Pull.Cursor, ts.Pull.Applied
+}
+
+// Checkpoint records that a pull of the changes since a version applied
+// another page of n patterns; next is the cursor of the page after it.
+// The last page, with no next cursor, completes the pull at version.
+func (ts *TeamSyncState) Checkpoint(since int64, next string, n int, version int64) {
+	if next == "" {
+		ts.Pull = nil
+		ts.Version = version
+		return
+	}
+	applied := n
+	if ts.Pull != nil && ts.Pull.Since == since {
+		applied += ts.Pull.Applied
+	}
+	ts.Pull = &PullCheckpoint{Since: since, Cursor: next, Applied: applied}
+}
+
+// SyncBase is a pattern as of the last sync: the server version it had,
+// the hash of its synced fields, and the fields themselves for 3-way
+// merges.
+type SyncBase struct {
+	Version     int64          `yaml:"version"`
+	Hash        string         `yaml:"hash"`
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	Content     string         `yaml:"content,omitempty"`
+	Tags        map[string]any `yaml:"tags,omitempty"`
+	Applies     map[string]any `yaml:"applies,omitempty"`
+}
+
+// LoadSyncState reads the sync state at path. A missing file is an empty
+// state; the old format, a version per team, is read as bases-less state.
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{Teams: make(map[string]*TeamSyncState)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid sync state %s: %w", path, err)
+	}
+	if teams, ok := doc["teams"]; ok && teams.Kind == yaml.MappingNode {
+		if err := teams.Decode(&state.Teams); err != nil {
+			return nil, fmt.Errorf("invalid sync state %s: %w", path, err)
+		}
+		if state.Teams == nil {
+			state.Teams = make(map[string]*TeamSyncState)
+		}
+		return state, nil
+	}
+	for team, node := range doc {
+		var version int64
+		if node.Decode(&version) == nil {
+			state.Teams[team] = &TeamSyncState{Version: version}
+		}
+	}
+	return state, nil
+}
+
+// Save writes the sync state to path.
+func (s *SyncState) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Team returns the state for a team, adding it if it has none.
+func (s *SyncState) Team(slug string) *TeamSyncState {
+	ts, ok := s.Teams[slug]
+	if !ok || ts == nil {
+		ts = &TeamSyncState{}
+		s.Teams[slug] = ts
+	}
+	if ts.Patterns == nil {
+		ts.Patterns = make(map[string]*SyncBase)
+	}
+	return ts
+}
+
+// SyncKey is how sync state refers to a pattern: its ID, or for patterns
+// without one its name.
+func SyncKey(p *Pattern) string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return "name:" + p.Name
+}
+
+// Base returns the merge base for a pattern, or nil if it hasn't been
+// synced.
+func (ts *TeamSyncState) Base(p *Pattern) *SyncBase {
+	if b, ok := ts.Patterns[SyncKey(p)]; ok {
+		return b
+	}
+	// Pushed before it had an ID
+	return ts.Patterns["name:"+p.Name]
+}
+
+// SetBase records p, at server version, as both sides' agreed state.
+func (ts *TeamSyncState) SetBase(p *Pattern, version int64) {
+	if p.ID != "" {
+		delete(ts.Patterns, "name:"+p.Name)
+	}
+	ts.Patterns[SyncKey(p)] = &SyncBase{
+		Version:     version,
+		Hash:        PatternHash(p),
+		Name:        p.Name,
+		Description: p.Description,
+		Content:     p.Content,
+		Tags:        p.Tags,
+		Applies:     p.Applies,
+	}
+}
+
+// DropBase forgets a pattern's merge base, so it is pushed as new.
+func (ts *TeamSyncState) DropBase(p *Pattern) {
+	delete(ts.Patterns, SyncKey(p))
+	delete(ts.Patterns, "name:"+p.Name)
+}
+
+// Changed reports whether p differs from its merge base. Patterns never
+// synced have changed.
+func (ts *TeamSyncState) Changed(p *Pattern) bool {
+	b := ts.Base(p)
+	return b == nil || b.Hash != PatternHash(p)
+}
+
+// Changes returns the changeset that brings the server up to date with
+// the local patterns: a create for each pattern never synced, and an
+// update, based on the server version last seen, for each one changed
+// since. Unchanged patterns are left out.
+func (ts *TeamSyncState) Changes(local []*Pattern) []SyncChange {
+	var changes []SyncChange
+	for _, p := range local {
+		b := ts.Base(p)
+		switch {
+		case b == nil:
+			changes = append(changes, SyncChange{Action: "create", ID: p.ID, Pattern: p})
+		case b.Hash != PatternHash(p):
+			p.Version = b.Version
+			changes = append(changes, SyncChange{Action: "update", ID: p.ID, Pattern: p})
+		}
+	}
+	return changes
+}
+
+// syncedFields are the pattern fields sync compares and merges.
+var syncedFields = []string{"name", "description", "content", "tags", "applies"}
+
+// fieldValue returns a synced field of p in a comparable form.
+func fieldValue(p *Pattern, field string) string {
+	var v any
+	switch field {
+	case "name":
+		v = p.Name
+	case "description":
+		v = p.Description
+	case "content":
+		v = p.Content
+	case "tags":
+		v = emptyAsNil(p.Tags)
+	case "applies":
+		v = emptyAsNil(p.Applies)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func emptyAsNil(m map[string]any) map[string]any {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// copyField sets a synced field of dst to src's.
+func copyField(dst, src *Pattern, field string) {
+	switch field {
+	case "name":
+		dst.Name = src.Name
+	case "description":
+		dst.Description = src.Description
+	case "content":
+		dst.Content = src.Content
+	case "tags":
+		dst.Tags = src.Tags
+	case "applies":
+		dst.Applies = src.Applies
+	}
+}
+
+// PatternHash returns a hash of the synced fields of p, so two copies
+// of a pattern compare equal when sync would see no difference.
+func PatternHash(p *Pattern) string {
+	h := sha256.New()
+	for _, field := range syncedFields {
+		fmt.Fprintf(h, "%s=%s\n", field, fieldValue(p, field))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// pattern returns the merge base as a pattern.
+func (b *SyncBase) pattern() *Pattern {
+	return &Pattern{
+		Name:        b.Name,
+		Description: b.Description,
+		Content:     b.Content,
+		Tags:        b.Tags,
+		Applies:     b.Applies,
+	}
+}
+
+// Merge3 merges the local and server changes to a pattern since base,
+// field by field: each side's changes are kept, and a field both sides
+// changed differently is a conflict. It returns the merged pattern, the
+// server's with the local changes applied, or nil and the conflicting
+// fields. Without a base, identical copies merge to the server's and any
+// difference is a conflict.
+func Merge3(base *SyncBase, local, server *Pattern) (*Pattern, []string) {
+	if base == nil {
+		if fields := differingFields(local, server); len(fields) > 0 {
+			return nil, fields
+		}
+		merged := *server
+		return &merged, nil
+	}
+	b := base.pattern()
+	merged := *server
+	var conflicts []string
+	for _, field := range syncedFields {
+		l, s, o := fieldValue(local, field), fieldValue(server, field), fieldValue(b, field)
+		switch {
+		case l == s || l == o:
+			// Server's, or both made the same change
+		case s == o:
+			copyField(&merged, local, field)
+		default:
+			conflicts = append(conflicts, field)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+	return &merged, nil
+}
+
+// differingFields returns the synced fields in which a and b differ.
+func differingFields(a, b *Pattern) []string {
+	var fields []string
+	for _, field := range syncedFields {
+		if fieldValue(a, field) != fieldValue(b, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,181 @@
+package cloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := &Pattern{ID: "p1", Name: "go-errors", Description: "Errors", Content: "Wrap errors.",
+		Tags: map[string]any{"confirmed": []string{"go"}}}
+	ts := &TeamSyncState{Patterns: make(map[string]*SyncBase)}
+	ts.SetBase(base, 3)
+
+	// Local edits the content, the server the description and tags: both kept
+	local := *base
+	local.Content = "Wrap errors with %w."
+	server := *base
+	server.Description = "Error handling"
+	server.Tags = map[string]any{"confirmed": []any{"go", "errors"}}
+	server.Version = 5
+
+	merged, conflicts := Merge3(ts.Base(&local), &local, &server)
+	if merged == nil {
+		t.Fatalf("conflicts = %v, want a clean merge", conflicts)
+	}
+	if merged.Content != local.Content || merged.Description != server.Description || merged.Version != 5 {
+		t.Errorf("merged = %+v", merged)
+	}
+	if fieldValue(merged, "tags") != `{"confirmed":["go","errors"]}` {
+		t.Errorf("merged tags = %s", fieldValue(merged, "tags"))
+	}
+
+	// Same change on both sides is no conflict
+	server.Content = local.Content
+	if merged, _ := Merge3(ts.Base(&local), &local, &server); merged == nil {
+		t.Error("identical changes conflicted")
+	}
+
+	// Different changes to one field are
+	server.Content = "Return errors."
+	merged, conflicts = Merge3(ts.Base(&local), &local, &server)
+	if merged != nil || !reflect.DeepEqual(conflicts, []string{"content"}) {
+		t.Errorf("Merge3 = %v, %v; want a content conflict", merged, conflicts)
+	}
+
+	// Without a base, every difference is a conflict
+	_, conflicts = Merge3(nil, &local, &server)
+	if !reflect.DeepEqual(conflicts, []string{"description", "content", "tags"}) {
+		t.Errorf("conflicts without base = %v", conflicts)
+	}
+
+	// ... but identical copies are in sync
+	same := server
+	same.Version = 9
+	if merged, conflicts := Merge3(nil, &server, &same); merged == nil || len(conflicts) != 0 || merged.Version != 9 {
+		t.Errorf("Merge3(nil, identical) = %v, %v; want the server's", merged, conflicts)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	ts := &TeamSyncState{Patterns: make(map[string]*SyncBase)}
+	synced := &Pattern{ID: "a", Name: "synced", Content: "same"}
+	edited := &Pattern{ID: "b", Name: "edited", Content: "old"}
+	ts.SetBase(synced, 4)
+	ts.SetBase(edited, 7)
+
+	editedNow := *edited
+	editedNow.Content = "new"
+	fresh := &Pattern{ID: "c", Name: "fresh", Content: "hi"}
+	syncedNow := *synced
+
+	changes := ts.Changes([]*Pattern{&syncedNow, &editedNow, fresh})
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want an update and a create", changes)
+	}
+	if changes[0].Action != "update" || changes[0].Pattern.Name != "edited" || changes[0].Pattern.Version != 7 {
+		t.Errorf("changes[0] = %+v, want an update of edited on version 7", changes[0])
+	}
+	if changes[1].Action != "create" || changes[1].ID != "c" {
+		t.Errorf("changes[1] = %+v, want a create of fresh", changes[1])
+	}
+}
+
+func TestSyncStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-state.yaml")
+
+	// The old format: a version per team
+	if err := os.WriteFile(path, []byte("acme: 12\nwidgets: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Team("acme").Version != 12 || state.Team("widgets").Version != 3 {
+		t.Errorf("legacy state = %+v", state.Teams)
+	}
+
+	p := &Pattern{ID: "a", Name: "go-errors", Content: "Wrap errors.",
+		Applies: map[string]any{"languages": []string{"go"}}}
+	state.Team("acme").SetBase(p, 12)
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = LoadSyncState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := state.Team("acme")
+	if ts.Version != 12 || state.Team("widgets").Version != 3 {
+		t.Errorf("versions after reload = %+v", state.Teams)
+	}
+	// Tags and applies come back as []any but hash the same
+	if ts.Changed(p) {
+		t.Errorf("pattern changed after a round trip: base %+v", ts.Base(p))
+	}
+
+	// A missing file is an empty state
+	state, err = LoadSyncState(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil || len(state.Teams) != 0 {
+		t.Errorf("missing file = %+v, %v", state, err)
+	}
+}
+
+func TestPullCheckpoint(t *testing.T) {
+	ts := &TeamSyncState{Version: 4}
+	if cursor, _ := ts.ResumeCursor(4); cursor != "" {
+		t.Fatalf("fresh state resumes at %q", cursor)
+	}
+
+	ts.Checkpoint(4, "c1", 200, 9)
+	ts.Checkpoint(4, "c2", 200, 9)
+	if cursor, applied := ts.ResumeCursor(4); cursor != "c2" || applied != 400 {
+		t.Errorf("ResumeCursor = %q, %d; want c2, 400", cursor, applied)
+	}
+	if ts.Version != 4 {
+		t.Errorf("version moved to %d before the last page", ts.Version)
+	}
+	if cursor, _ := ts.ResumeCursor(0); cursor != "" {
+		t.Errorf("pull since another version resumes at %q", cursor)
+	}
+
+	ts.Checkpoint(4, "", 50, 9)
+	if ts.Pull != nil || ts.Version != 9 {
+		t.Errorf("after the last page: pull %+v, version %d", ts.Pull, ts.Version)
+	}
+}
+
+func TestPullPages(t *testing.T) {
+	var queries []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/core/teams/t1/sync/pull", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		resp := PullResponse{Version: 7, Patterns: []Pattern{{Name: "a"}}, NextCursor: "p2"}
+		if r.URL.Query().Get("cursor") == "p2" {
+			resp = PullResponse{Version: 7, Patterns: []Pattern{{Name: "b"}}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}}
+
+	resp, err := c.Pull("t1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Patterns) != 2 || resp.Version != 7 {
+		t.Errorf("Pull = %+v", resp)
+	}
+	want := []string{"since=3&limit=200", "since=3&limit=200&cursor=p2"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+}
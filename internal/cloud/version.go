@@ -0,0 +1,138 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/selfupdate"
+)
+
+// Feature is a server capability that self-hosted servers may not have yet.
+type Feature string
+
+const (
+	FeatureWorkflowSync       Feature = "workflow_sync"
+	FeatureTranslation        Feature = "translation"
+	FeatureEmbeddingSnapshots Feature = "embedding_snapshots"
+	FeatureTeamPolicy         Feature = "team_policy"
+	FeatureSubmissionReview   Feature = "submission_review"
+	FeatureTeamCoverage       Feature = "team_coverage"
+	FeaturePatternComments    Feature = "pattern_comments"
+)
+
+// featureInfo describes each feature for error messages, and the server
+// version that introduced it, for servers that report a version but no
+// feature list.
+var featureInfo = map[Feature]struct {
+	name  string
+	since string
+}{
+	FeatureWorkflowSync:       {"workflow sync", "1.1"},
+	FeatureTranslation:        {"translation", "1.2"},
+	FeatureEmbeddingSnapshots: {"community embedding snapshots", "1.3"},
+	FeatureTeamPolicy:         {"team policy", "1.4"},
+	FeatureSubmissionReview:   {"submission review", "1.5"},
+	FeatureTeamCoverage:       {"team coverage", "1.6"},
+	FeaturePatternComments:    {"pattern comments", "1.7"},
+}
+
+// LegacyServerVersion is assumed for servers without /api/version.
+const LegacyServerVersion = "1.0"
+
+// ServerInfo is the server's answer to the /api/version handshake.
+type ServerInfo struct {
+	Version  string    `json:"version"`
+	Features []Feature `json:"features,omitempty"`
+	Legacy   bool      `json:"-"` // the server predates the handshake
+}
+
+// Supports reports whether the server has feature. An explicit feature
+// list wins; otherwise the version decides.
+func (s *ServerInfo) Supports(feature Feature) bool {
+	if s.Features != nil {
+		for _, f := range s.Features {
+			if f == feature {
+				return true
+			}
+		}
+		return false
+	}
+	info, ok := featureInfo[feature]
+	return ok && selfupdate.CompareVersions(s.Version, info.since) >= 0
+}
+
+// UnsupportedError is returned for requests the server can't handle.
+type UnsupportedError struct {
+	Server  string
+	Feature Feature
+}
+
+func (e *UnsupportedError) Error() string {
+	info, ok := featureInfo[e.Feature]
+	if !ok {
+		return fmt.Sprintf("server %s does not support %s", e.Server, e.Feature)
+	}
+	return fmt.Sprintf("server %s does not support %s (requires %s or later)", e.Server, info.name, info.since)
+}
+
+// ServerInfo returns the server's version and features, asking once per
+// client. Servers that answer /api/version with 404 predate the handshake
+// and are reported as LegacyServerVersion.
+func (c *Client) ServerInfo() (*ServerInfo, error) {
+	c.serverOnce.Do(func() {
+		c.server, c.serverErr = c.fetchServerInfo()
+	})
+	return c.server, c.serverErr
+}
+
+func (c *Client) fetchServerInfo() (*ServerInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/version", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("version handshake failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return &ServerInfo{Version: LegacyServerVersion, Legacy: true}, nil
+	case resp.StatusCode >= 400:
+		return nil, fmt.Errorf("version handshake failed with status %d", resp.StatusCode)
+	}
+
+	var info ServerInfo
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version: %w", err)
+	}
+	if err := json.Unmarshal(body, &info); err != nil || info.Version == "" {
+		return nil, fmt.Errorf("invalid /api/version response")
+	}
+	return &info, nil
+}
+
+// Require returns an *UnsupportedError if a self-hosted server lacks
+// feature. mur.run always runs the latest API, so it skips the handshake.
+// If the handshake itself fails (e.g. the server is unreachable) the
+// request is allowed, so the caller sees the request's own error.
+func (c *Client) Require(feature Feature) error {
+	if strings.TrimRight(c.baseURL, "/") == DefaultServerURL {
+		return nil
+	}
+	info, err := c.ServerInfo()
+	if err != nil || info.Supports(feature) {
+		return nil
+	}
+	return &UnsupportedError{Server: info.Version, Feature: feature}
+}
@@ -0,0 +1,101 @@
+package cloud
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func testClient(t *testing.T, version http.HandlerFunc) (*Client, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/version" {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		version(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	auth := &AuthStore{path: filepath.Join(t.TempDir(), "auth.json")}
+	return &Client{baseURL: srv.URL, httpClient: srv.Client(), authStore: auth}, &calls
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		feature Feature
+		wantErr string
+	}{
+		{
+			"legacy server without handshake",
+			http.NotFound,
+			FeatureWorkflowSync,
+			"server 1.0 does not support workflow sync (requires 1.1 or later)",
+		},
+		{
+			"version only, new enough",
+			func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"version":"1.2.3"}`)) },
+			FeatureTranslation,
+			"",
+		},
+		{
+			"version only, too old",
+			func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"version":"1.2.3"}`)) },
+			FeatureTeamPolicy,
+			"server 1.2.3 does not support team policy (requires 1.4 or later)",
+		},
+		{
+			"feature list wins over version",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"version":"9.0","features":["translation"]}`))
+			},
+			FeatureWorkflowSync,
+			"server 9.0 does not support workflow sync (requires 1.1 or later)",
+		},
+		{
+			"handshake error lets the request through",
+			func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+			FeatureWorkflowSync,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		c, _ := testClient(t, tt.handler)
+		err := c.Require(tt.feature)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		var unsupported *UnsupportedError
+		if tt.wantErr != "" && !errors.As(err, &unsupported) {
+			t.Errorf("%s: error is not an *UnsupportedError", tt.name)
+		}
+	}
+}
+
+func TestServerInfoCached(t *testing.T) {
+	c, calls := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.3"}`))
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := c.WorkflowSyncStatus("team", 0); err == nil {
+			t.Fatal("expected the stub server's 404 for the status endpoint")
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("handshake made %d times, want 1", *calls)
+	}
+
+	info, err := c.ServerInfo()
+	if err != nil || info.Version != "1.3" || info.Legacy {
+		t.Errorf("ServerInfo() = %+v, %v", info, err)
+	}
+}
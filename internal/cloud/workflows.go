@@ -8,6 +8,9 @@ import (
 
 // WorkflowSyncStatus returns workflow sync status for a team.
 func (c *Client) WorkflowSyncStatus(teamID string, version int64) (*workflow.WorkflowSyncStatus, error) {
+	if err := c.Require(FeatureWorkflowSync); err != nil {
+		return nil, err
+	}
 	var status workflow.WorkflowSyncStatus
 	path := fmt.Sprintf("/api/v1/core/teams/%s/workflows/sync/status?version=%d", teamID, version)
 	if err := c.get(path, &status); err != nil {
@@ -18,6 +21,9 @@ func (c *Client) WorkflowSyncStatus(teamID string, version int64) (*workflow.Wor
 
 // WorkflowPull pulls workflow changes from the server.
 func (c *Client) WorkflowPull(teamID string, sinceVersion int64) (*workflow.WorkflowPullResponse, error) {
+	if err := c.Require(FeatureWorkflowSync); err != nil {
+		return nil, err
+	}
 	var resp workflow.WorkflowPullResponse
 	path := fmt.Sprintf("/api/v1/core/teams/%s/workflows/sync/pull?since=%d", teamID, sinceVersion)
 	if err := c.get(path, &resp); err != nil {
@@ -28,6 +34,9 @@ func (c *Client) WorkflowPull(teamID string, sinceVersion int64) (*workflow.Work
 
 // WorkflowPush pushes workflow changes to the server.
 func (c *Client) WorkflowPush(teamID string, req workflow.WorkflowPushRequest) (*workflow.WorkflowPushResponse, error) {
+	if err := c.Require(FeatureWorkflowSync); err != nil {
+		return nil, err
+	}
 	var resp workflow.WorkflowPushResponse
 	path := fmt.Sprintf("/api/v1/core/teams/%s/workflows/sync/push", teamID)
 	if err := c.post(path, req, &resp); err != nil {
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/timing"
 )
 
 // CurrentSchemaVersion is the latest config schema version.
@@ -33,6 +37,125 @@ type Config struct {
 	Community     CommunityConfig     `yaml:"community,omitempty"`     // Community sharing settings
 	Privacy       PrivacyConfig       `yaml:"privacy,omitempty"`       // Privacy & PII protection settings
 	Consolidation ConsolidationConfig `yaml:"consolidation,omitempty"` // Pattern consolidation settings
+	Upgrade       UpgradeConfig       `yaml:"upgrade,omitempty"`       // Self-update settings
+	Context       ContextConfig       `yaml:"context,omitempty"`       // Injected context output format
+	Stats         StatsConfig         `yaml:"stats,omitempty"`         // Usage statistics settings
+	Storage       StorageConfig       `yaml:"storage,omitempty"`       // On-disk pattern storage
+	Logging       LoggingConfig       `yaml:"logging,omitempty"`       // mur's own log (mur logs)
+	Tags          TagsConfig          `yaml:"tags,omitempty"`          // Tag taxonomy (mur tags)
+	Network       NetworkConfig       `yaml:"network,omitempty"`       // Endpoints mur may connect to
+
+	policy      *Policy        // team policy applied by Load
+	policyLocal map[string]any // local values the policy replaced
+}
+
+// NetworkConfig limits the endpoints mur's HTTP clients connect to. It is
+// enforced by internal/netpolicy.
+type NetworkConfig struct {
+	Mode  string   `yaml:"mode,omitempty"`  // normal (default) | allowlist | offline; localhost is always allowed
+	Allow []string `yaml:"allow,omitempty"` // allowlist mode: api.example.com, *.example.com, host:443, 10.0.0.5, 10.0.0.0/8
+}
+
+// TagsConfig keeps pattern tags consistent.
+type TagsConfig struct {
+	// Aliases maps a tag to the one used instead, e.g. golang: go. Tags of
+	// extracted patterns and of synced rules are normalized with it.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// LoggingConfig controls mur's log file, ~/.mur/logs/mur.log.
+type LoggingConfig struct {
+	Level     string `yaml:"level,omitempty"`       // debug | info (default) | warn | error; --log-level overrides
+	MaxSizeMB int    `yaml:"max_size_mb,omitempty"` // rotate past this size (default: 5)
+	MaxFiles  int    `yaml:"max_files,omitempty"`   // rotated logs kept (default: 3)
+}
+
+// StorageConfig controls how patterns are stored on disk.
+type StorageConfig struct {
+	Backend           string `yaml:"backend,omitempty"`            // files (default) | dual | sqlite
+	Compress          bool   `yaml:"compress"`                     // zstd-compress large pattern files as .yaml.zst
+	CompressThreshold int    `yaml:"compress_threshold,omitempty"` // bytes of YAML above which a pattern is compressed (default: 8192)
+	TrashDays         int    `yaml:"trash_days,omitempty"`         // days deleted patterns stay in the trash (default: 30; -1 deletes permanently)
+	MaxContentBytes   int    `yaml:"max_content_bytes,omitempty"`  // largest pattern content saved as is (default: 50000; -1 for no limit)
+	Oversize          string `yaml:"oversize,omitempty"`           // what to do with larger content: split (default) | truncate | reject
+}
+
+// UnmarshalYAML also accepts a bare backend name, as in "storage: sqlite".
+func (s *StorageConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&s.Backend)
+	}
+	type plain StorageConfig
+	return value.Decode((*plain)(s))
+}
+
+// StatsConfig controls usage statistics.
+type StatsConfig struct {
+	Savings SavingsConfig `yaml:"savings,omitempty"`
+	// Time zone dates are shown in by stats, reports, and the dashboard,
+	// as an IANA name like "Europe/Berlin" (default: the machine's own).
+	// Timestamps are stored in UTC either way.
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// Location returns the display time zone: stats.timezone, or the local
+// zone when unset or "local".
+func (c StatsConfig) Location() (*time.Location, error) {
+	tz := strings.TrimSpace(c.Timezone)
+	if tz == "" || strings.EqualFold(tz, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Local, fmt.Errorf("invalid stats.timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// SavingsConfig sets the counterfactual used to estimate savings
+// (mur stats savings).
+type SavingsConfig struct {
+	Baseline    string                `yaml:"baseline,omitempty"`     // model everything would have run on (default: claude-sonnet)
+	Prices      map[string]ModelPrice `yaml:"prices,omitempty"`       // USD per 1M tokens; adds to or overrides built-in prices
+	Tools       map[string]string     `yaml:"tools,omitempty"`        // model each tool is priced as, e.g. claude: claude-opus
+	ReuseTokens int                   `yaml:"reuse_tokens,omitempty"` // tokens of re-explaining avoided per injected pattern (default: 1000)
+	OutputRatio float64               `yaml:"output_ratio,omitempty"` // output tokens per input token (default: 1.0)
+}
+
+// ModelPrice is a model's price in USD per 1M tokens.
+type ModelPrice struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// ContextConfig controls the context injected by hooks
+// (mur context, mur search --inject).
+type ContextConfig struct {
+	Format  string            `yaml:"format,omitempty"`  // text | markdown | xml | claude-skill | <custom template>
+	Targets map[string]string `yaml:"targets,omitempty"` // per-target format, e.g. claude: xml, cursor: markdown
+
+	PinnedBudget int `yaml:"pinned_budget,omitempty"` // max pinned patterns always injected (default: 3, -1 disables)
+
+	Profile  string                    `yaml:"profile,omitempty"`  // default profile (see Profiles)
+	Profiles map[string]ContextProfile `yaml:"profiles,omitempty"` // named profiles, e.g. reviewer, oncall
+}
+
+// ContextProfile tailors injected context to an activity, such as reviewing
+// or being on call. Selected with mur context --profile, $MUR_PROFILE, or
+// mur profile use.
+type ContextProfile struct {
+	Description  string   `yaml:"description,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`          // only patterns with one of these tags
+	ExcludeTags  []string `yaml:"exclude_tags,omitempty"`  // never patterns with one of these tags
+	Max          int      `yaml:"max,omitempty"`           // relevance-ranked patterns (default: --max)
+	PinnedBudget int      `yaml:"pinned_budget,omitempty"` // overrides context.pinned_budget
+	Pinned       []string `yaml:"pinned,omitempty"`        // pattern names pinned while the profile is active
+}
+
+// UpgradeConfig controls `mur upgrade` self-update.
+type UpgradeConfig struct {
+	Disabled bool   `yaml:"disabled,omitempty"` // Block self-update (for managed installs)
+	Channel  string `yaml:"channel,omitempty"`  // stable | beta (default: stable)
 }
 
 // CacheConfig represents local cache settings for community patterns.
@@ -153,6 +276,19 @@ type ConsolidationConfig struct {
 	GracePeriodDays      int     `yaml:"grace_period_days,omitempty"`
 	MinPatternsBeforeRun int     `yaml:"min_patterns_before_run,omitempty"`
 	NotifyOnRun          bool    `yaml:"notify_on_run,omitempty"`
+	TeamPR               bool    `yaml:"team_pr,omitempty"` // --auto opens a PR in the learning repo instead of rewriting locally
+}
+
+// Period returns how often the schedule runs consolidation: a day, a
+// week (the default), or 30 days.
+func (c ConsolidationConfig) Period() time.Duration {
+	switch c.Schedule {
+	case "daily":
+		return 24 * time.Hour
+	case "monthly":
+		return 30 * 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
 }
 
 // DefaultConsolidationConfig returns default consolidation settings.
@@ -212,18 +348,27 @@ func (c *Config) GetCacheConfig() CommunityCacheConfig {
 
 // ServerConfig represents mur-server cloud sync settings.
 type ServerConfig struct {
-	URL  string `yaml:"url,omitempty"`  // Server URL (default: https://api.mur.run)
-	Team string `yaml:"team,omitempty"` // Active team slug
+	URL           string `yaml:"url,omitempty"`            // Server URL (default: https://api.mur.run)
+	Team          string `yaml:"team,omitempty"`           // Active team slug
+	PermissionKey string `yaml:"permission_key,omitempty"` // Pinned ed25519 key for workflow permission manifests and team policy
+	ShareCoverage bool   `yaml:"share_coverage"`           // Report team pattern names+hashes for 'mur cloud coverage'
+	ChangelogLLM  bool   `yaml:"changelog_llm,omitempty"`  // Add an LLM one-liner to the changelog entries of pushed updates
 }
 
 // NotificationsConfig represents notification settings.
 type NotificationsConfig struct {
-	Enabled    bool          `yaml:"enabled,omitempty"`
-	System     bool          `yaml:"system,omitempty"`      // Enable macOS system notifications
-	OnError    bool          `yaml:"on_error,omitempty"`    // Notify on errors
-	OnPatterns bool          `yaml:"on_patterns,omitempty"` // Notify when patterns are extracted
-	Slack      SlackConfig   `yaml:"slack,omitempty"`
-	Discord    DiscordConfig `yaml:"discord,omitempty"`
+	Enabled    bool `yaml:"enabled,omitempty"`
+	System     bool `yaml:"system,omitempty"`      // Enable macOS system notifications
+	OnError    bool `yaml:"on_error,omitempty"`    // Notify on errors
+	OnPatterns bool `yaml:"on_patterns,omitempty"` // Notify when patterns are extracted
+	// IgnoreFocus sends system notifications during Focus and Do Not
+	// Disturb too; by default only critical ones are
+	IgnoreFocus bool `yaml:"ignore_focus,omitempty"`
+	// CoalesceMinutes holds back a system notification identical to one
+	// shown this recently (default 10; -1 to never hold back)
+	CoalesceMinutes int           `yaml:"coalesce_minutes,omitempty"`
+	Slack           SlackConfig   `yaml:"slack,omitempty"`
+	Discord         DiscordConfig `yaml:"discord,omitempty"`
 }
 
 // SlackConfig represents Slack webhook settings.
@@ -256,6 +401,11 @@ type HooksConfig struct {
 	Stop             []HookGroup `yaml:"Stop,omitempty"`
 	BeforeTool       []HookGroup `yaml:"BeforeTool,omitempty"`
 	AfterTool        []HookGroup `yaml:"AfterTool,omitempty"`
+
+	// Concurrency is how many syncs, and how many extractions, hooks run
+	// at once; hooks that fire while they're all busy are folded into one
+	// more pass (default: 1)
+	Concurrency int `yaml:"concurrency,omitempty"`
 }
 
 // HookGroup represents a group of hooks with a matcher pattern.
@@ -287,19 +437,68 @@ type SyncConfig struct {
 	CleanOld        bool   `yaml:"clean_old,omitempty"`        // remove old single-file format on sync
 	Auto            bool   `yaml:"auto,omitempty"`             // enable automatic sync
 	IntervalMinutes int    `yaml:"interval_minutes,omitempty"` // sync interval in minutes (default: 30)
+
+	Concurrency          int `yaml:"concurrency,omitempty"`            // targets synced at once (default: 4)
+	TargetTimeoutSeconds int `yaml:"target_timeout_seconds,omitempty"` // per-target timeout (default: 10)
+
+	// Largest file written to a target, by target name (e.g. "Claude Code":
+	// 100000); the least effective patterns are left out to fit. Default
+	// 200000 bytes; -1 for no limit
+	TargetMaxBytes map[string]int `yaml:"target_max_bytes,omitempty"`
+
+	// Repositories whose AGENTS.md and llms.txt get a managed block with
+	// the patterns that apply to them
+	ProjectFiles ProjectFilesConfig `yaml:"project_files,omitempty"`
+
+	// Opt-in targets synced too: jetbrains (JetBrains AI Assistant and
+	// Junie). mur init asks
+	Enable []string `yaml:"enable,omitempty"`
+}
+
+// ProjectFilesConfig lists the repositories sync writes project guidance
+// files into, and which files.
+type ProjectFilesConfig struct {
+	Projects []string `yaml:"projects,omitempty"` // repository roots; ~/ allowed
+	Files    []string `yaml:"files,omitempty"`    // default: AGENTS.md, llms.txt, Copilot and Junie files
 }
 
 // SearchConfig represents semantic search settings.
 type SearchConfig struct {
-	Enabled    *bool   `yaml:"enabled,omitempty"`     // nil = use default (true)
-	Provider   string  `yaml:"provider,omitempty"`    // ollama | openai | google | voyage | none
-	Model      string  `yaml:"model,omitempty"`       // embedding model name
-	OllamaURL  string  `yaml:"ollama_url,omitempty"`  // Ollama API URL
-	OpenAIURL  string  `yaml:"openai_url,omitempty"`  // OpenAI-compatible API URL (e.g. OpenRouter)
-	APIKeyEnv  string  `yaml:"api_key_env,omitempty"` // env var name for API key (e.g. OPENAI_API_KEY)
-	TopK       int     `yaml:"top_k,omitempty"`       // default number of results
-	MinScore   float64 `yaml:"min_score,omitempty"`   // minimum similarity score
-	AutoInject *bool   `yaml:"auto_inject,omitempty"` // auto-inject to prompt via hooks (default: true)
+	Enabled    *bool              `yaml:"enabled,omitempty"`     // nil = use default (true)
+	Provider   string             `yaml:"provider,omitempty"`    // ollama | openai | google | voyage | none
+	Model      string             `yaml:"model,omitempty"`       // embedding model name
+	OllamaURL  string             `yaml:"ollama_url,omitempty"`  // Ollama API URL
+	OpenAIURL  string             `yaml:"openai_url,omitempty"`  // OpenAI-compatible API URL (e.g. OpenRouter)
+	APIKeyEnv  string             `yaml:"api_key_env,omitempty"` // env var name for API key (e.g. OPENAI_API_KEY)
+	TopK       int                `yaml:"top_k,omitempty"`       // default number of results
+	MinScore   float64            `yaml:"min_score,omitempty"`   // minimum similarity score
+	AutoInject *bool              `yaml:"auto_inject,omitempty"` // auto-inject to prompt via hooks (default: true)
+	Hybrid     HybridSearchConfig `yaml:"hybrid,omitempty"`
+
+	// MaxInjectTokens caps injected context (mur context, mur search
+	// --inject) at about this many tokens; 0 for no limit
+	MaxInjectTokens int `yaml:"max_inject_tokens,omitempty"`
+}
+
+// HybridSearchConfig configures hybrid search, which combines keyword
+// (BM25) and semantic scores so exact identifiers aren't missed.
+type HybridSearchConfig struct {
+	Enabled       bool    `yaml:"enabled,omitempty"`        // mur search uses hybrid mode without --hybrid
+	KeywordWeight float64 `yaml:"keyword_weight,omitempty"` // share of the keyword score, 0-1 (default: 0.3)
+}
+
+// DefaultHybridKeywordWeight is the keyword share of hybrid scores when
+// search.hybrid.keyword_weight isn't set.
+const DefaultHybridKeywordWeight = 0.3
+
+// HybridKeywordWeight returns the keyword share of hybrid search scores,
+// clamped to 0-1.
+func (s SearchConfig) HybridKeywordWeight() float64 {
+	w := s.Hybrid.KeywordWeight
+	if w <= 0 {
+		return DefaultHybridKeywordWeight
+	}
+	return min(w, 1)
 }
 
 // IsEnabled returns whether search is enabled (default: true).
@@ -318,6 +517,10 @@ func (s SearchConfig) IsAutoInject() bool {
 	return *s.AutoInject
 }
 
+// DefaultEmbeddingsCacheDir is the configured default for embeddings.cache_dir.
+// It resolves to the embeddings directory under the mur cache directory.
+const DefaultEmbeddingsCacheDir = "~/.mur/embeddings"
+
 // EmbeddingsConfig represents embedding cache settings.
 type EmbeddingsConfig struct {
 	CacheEnabled bool   `yaml:"cache_enabled,omitempty"`
@@ -344,15 +547,33 @@ type LearningConfig struct {
 	SyncToTools  bool `yaml:"sync_to_tools,omitempty"`
 	PatternLimit int  `yaml:"pattern_limit,omitempty"`
 	// Learning repo sync settings
-	Repo         string `yaml:"repo,omitempty"`           // git repo URL for syncing patterns
-	Branch       string `yaml:"branch,omitempty"`         // branch name (default: hostname)
-	AutoPush     bool   `yaml:"auto_push,omitempty"`      // auto push after extract
-	PullFromMain bool   `yaml:"pull_from_main,omitempty"` // also pull shared patterns from main
+	Repo         string   `yaml:"repo,omitempty"`           // git repo URL for syncing patterns
+	Branch       string   `yaml:"branch,omitempty"`         // branch name (default: hostname)
+	AutoPush     bool     `yaml:"auto_push,omitempty"`      // auto push after extract
+	PullFromMain bool     `yaml:"pull_from_main,omitempty"` // also pull shared patterns from main
+	PullBranches []string `yaml:"pull_branches,omitempty"`  // other machines' branches to pull from
+	Dedupe       string   `yaml:"dedupe,omitempty"`         // skip | link | merge equivalent patterns on pull (default: link)
+	Domains      []string `yaml:"domains,omitempty"`        // patterns/<domain>/ dirs checked out from the repo (default: tech_stack)
 	// Auto-merge settings
-	AutoMerge      bool    `yaml:"auto_merge,omitempty"`      // enable auto-merge to main
-	MergeThreshold float64 `yaml:"merge_threshold,omitempty"` // confidence threshold for auto-merge (default: 0.8)
+	AutoMerge      bool              `yaml:"auto_merge,omitempty"`      // enable auto-merge to main
+	MergeThreshold float64           `yaml:"merge_threshold,omitempty"` // confidence threshold for auto-merge (default: 0.8)
+	MergePolicy    MergePolicyConfig `yaml:"merge_policy,omitempty"`    // review gate before merging pattern PRs
 	// LLM extraction settings
 	LLM LLMConfig `yaml:"llm,omitempty"`
+	// Hold back hook-triggered cloud LLM extraction until the scheduled sync
+	// (local Ollama extraction still runs)
+	DeferOnBattery bool `yaml:"defer_on_battery,omitempty"` // while on battery
+	DeferOnMetered bool `yaml:"defer_on_metered,omitempty"` // while on a metered network
+	// Share of auto-accepted patterns that should be ones you'd accept;
+	// thresholds are calibrated to it from review history (default: 0.9)
+	TargetPrecision float64 `yaml:"target_precision,omitempty"`
+}
+
+// MergePolicyConfig gates merging of auto-merge pattern PRs.
+type MergePolicyConfig struct {
+	RequireCI    bool   `yaml:"require_ci,omitempty"`    // merge only when all CI checks pass
+	MinApprovals int    `yaml:"min_approvals,omitempty"` // minimum approving reviews
+	Method       string `yaml:"method,omitempty"`        // merge | squash | rebase (default: squash)
 }
 
 // LLMConfig represents LLM settings for pattern extraction.
@@ -363,6 +584,13 @@ type LLMConfig struct {
 	OpenAIURL string `yaml:"openai_url,omitempty"`  // OpenAI-compatible API URL
 	APIKeyEnv string `yaml:"api_key_env,omitempty"` // Env var name for API key
 
+	// Concurrency is how many sessions are extracted at once
+	// (default: 4, or 1 with Ollama).
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// RateLimits caps requests per minute by provider
+	// (default: 50, 15 for gemini, none for ollama).
+	RateLimits map[string]int `yaml:"rate_limits,omitempty"`
+
 	// Premium model for important sessions
 	Premium *LLMProviderConfig `yaml:"premium,omitempty"`
 
@@ -372,7 +600,8 @@ type LLMConfig struct {
 
 // IsZero reports whether the LLM config is empty (enables yaml omitempty on structs).
 func (l LLMConfig) IsZero() bool {
-	return l.Provider == "" && l.Model == "" && l.Premium == nil && l.Routing == nil
+	return l.Provider == "" && l.Model == "" && l.Concurrency == 0 && len(l.RateLimits) == 0 &&
+		l.Premium == nil && l.Routing == nil
 }
 
 // LLMProviderConfig represents a single LLM provider configuration.
@@ -402,11 +631,14 @@ func ConfigPath() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "config.yaml"), nil
+	return filepath.Join(ConfigDir(home), "config.yaml"), nil
 }
 
-// Load reads and parses the config file.
+// Load reads and parses the config file. A stored team policy is applied
+// if its signature verifies.
 func Load() (*Config, error) {
+	defer timing.Track("config.Load")()
+
 	path, err := ConfigPath()
 	if err != nil {
 		return nil, err
@@ -416,7 +648,11 @@ func Load() (*Config, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Return default config if file doesn't exist
-			return defaultConfig(), nil
+			cfg := defaultConfig()
+			if err := cfg.applyStoredPolicy(); err != nil {
+				return nil, err
+			}
+			return cfg, nil
 		}
 		return nil, fmt.Errorf("cannot read config: %w", err)
 	}
@@ -424,7 +660,12 @@ func Load() (*Config, error) {
 	// Parse the config file first to see what's specified
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("cannot parse config: %w", err)
+		return nil, &ParseError{Path: path, Err: err}
+	}
+
+	// Team policy defaults take precedence over built-in ones
+	if err := cfg.applyStoredPolicy(); err != nil {
+		return nil, err
 	}
 
 	// Apply defaults for missing sections
@@ -462,7 +703,7 @@ func (c *Config) applyDefaults() {
 
 	// Embeddings defaults
 	if c.Embeddings.CacheDir == "" {
-		c.Embeddings.CacheDir = "~/.mur/embeddings"
+		c.Embeddings.CacheDir = DefaultEmbeddingsCacheDir
 	}
 	if c.Embeddings.BatchSize == 0 {
 		c.Embeddings.BatchSize = 10
@@ -507,9 +748,10 @@ func (c *Config) Save() error {
 		return fmt.Errorf("cannot create config directory: %w", err)
 	}
 
-	// Marshal current config into a yaml.Node tree
+	// Marshal current config into a yaml.Node tree, leaving team policy
+	// values out of the file
 	var freshDoc yaml.Node
-	freshBytes, err := yaml.Marshal(c)
+	freshBytes, err := c.withoutPolicy()
 	if err != nil {
 		return fmt.Errorf("cannot serialize config: %w", err)
 	}
@@ -763,7 +1005,7 @@ func defaultConfig() *Config {
 		},
 		Embeddings: EmbeddingsConfig{
 			CacheEnabled: true,
-			CacheDir:     "~/.mur/embeddings",
+			CacheDir:     DefaultEmbeddingsCacheDir,
 			BatchSize:    10,
 		},
 		MCP: MCPConfig{
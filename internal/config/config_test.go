@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -113,6 +114,21 @@ func TestGetDefaultTool(t *testing.T) {
 	}
 }
 
+func TestStatsLocation(t *testing.T) {
+	for _, tz := range []string{"", "local", " Local "} {
+		if loc, err := (StatsConfig{Timezone: tz}).Location(); err != nil || loc != time.Local {
+			t.Errorf("Location(%q) = %v, %v; want Local", tz, loc, err)
+		}
+	}
+	loc, err := StatsConfig{Timezone: "Asia/Tokyo"}.Location()
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("Location(Asia/Tokyo) = %v, %v", loc, err)
+	}
+	if loc, err := (StatsConfig{Timezone: "Mars/Olympus"}).Location(); err == nil || loc != time.Local {
+		t.Errorf("Location(Mars/Olympus) = %v, %v; want Local and an error", loc, err)
+	}
+}
+
 func TestSetDefaultTool(t *testing.T) {
 	cfg := &Config{
 		DefaultTool: "claude",
@@ -406,3 +422,21 @@ func TestMarshalDefaultConfigClean(t *testing.T) {
 		}
 	}
 }
+
+func TestStorageBackendShorthand(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want StorageConfig
+	}{
+		{"storage: sqlite\n", StorageConfig{Backend: "sqlite"}},
+		{"storage:\n  backend: sqlite\n  compress: true\n", StorageConfig{Backend: "sqlite", Compress: true}},
+	} {
+		var cfg Config
+		if err := yaml.Unmarshal([]byte(tt.in), &cfg); err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		if cfg.Storage != tt.want {
+			t.Errorf("%q: Storage = %+v, want %+v", tt.in, cfg.Storage, tt.want)
+		}
+	}
+}
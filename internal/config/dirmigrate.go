@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Move describes a single file or directory relocation between layouts.
+type Move struct {
+	From string
+	To   string
+}
+
+// configEntries, cacheEntries, and stateEntries classify top-level entries of
+// a single-directory layout. Anything not listed here is data.
+var (
+	configEntries = map[string]bool{"config.yaml": true}
+	cacheEntries  = map[string]bool{"cache": true, "embeddings": true}
+	stateEntries  = map[string]bool{
+		"stats.jsonl":     true,
+		"session":         true,
+		"sessions":        true,
+		"sync-state.yaml": true,
+		"sync.log":        true,
+		"tracking":        true,
+		"audit":           true,
+	}
+)
+
+// targetFor returns the directory in dst that should hold the named entry.
+func targetFor(name string, dst Dirs) string {
+	switch {
+	case configEntries[name]:
+		return dst.Config
+	case cacheEntries[name]:
+		return dst.Cache
+	case stateEntries[name]:
+		return dst.State
+	default:
+		return dst.Data
+	}
+}
+
+// PlanMigration returns the moves needed to relocate everything in src into
+// dst. Entries already in the right place are skipped. It fails if a target
+// path already exists, so nothing is ever overwritten.
+func PlanMigration(src, dst Dirs) ([]Move, error) {
+	seen := make(map[string]bool)
+	var moves []Move
+
+	for _, dir := range []string{src.Config, src.Data, src.Cache, src.State} {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", dir, err)
+		}
+
+		for _, e := range entries {
+			from := filepath.Join(dir, e.Name())
+			to := filepath.Join(targetFor(e.Name(), dst), e.Name())
+			if from == to {
+				continue
+			}
+			if _, err := os.Lstat(to); err == nil {
+				return nil, fmt.Errorf("target already exists: %s", to)
+			}
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+
+	sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+	return moves, nil
+}
+
+// ApplyMigration performs the planned moves, creating parent directories as
+// needed, and removes source directories left empty afterwards.
+func ApplyMigration(moves []Move) error {
+	emptied := make(map[string]bool)
+	for _, m := range moves {
+		if err := os.MkdirAll(filepath.Dir(m.To), 0755); err != nil {
+			return fmt.Errorf("cannot create %s: %w", filepath.Dir(m.To), err)
+		}
+		if err := os.Rename(m.From, m.To); err != nil {
+			return fmt.Errorf("cannot move %s to %s: %w", m.From, m.To, err)
+		}
+		emptied[filepath.Dir(m.From)] = true
+	}
+
+	for dir := range emptied {
+		// Only succeeds if the directory is now empty
+		_ = os.Remove(dir)
+	}
+	return nil
+}
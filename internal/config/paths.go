@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Directory layouts returned by Layout.
+const (
+	LayoutMurHome = "mur_home" // Everything under $MUR_HOME
+	LayoutLegacy  = "legacy"   // Everything under ~/.mur
+	LayoutXDG     = "xdg"      // Split across XDG base directories
+)
+
+// Dirs holds the resolved mur directories.
+type Dirs struct {
+	Config string // config.yaml
+	Data   string // patterns, repo, workflows, hooks, skills, auth
+	Cache  string // embeddings, community and anonymization caches
+	State  string // stats, session recordings, sync state, logs, audit
+}
+
+// Layout reports which directory layout is in effect for the given home directory.
+//
+// Resolution order:
+//  1. $MUR_HOME, if set, holds everything.
+//  2. An existing ~/.mur keeps the legacy single-directory layout.
+//  3. An existing XDG data directory (after `mur migrate dirs`) uses XDG.
+//  4. Fresh installs use XDG when any XDG_* base variable is set.
+//  5. Otherwise ~/.mur.
+func Layout(home string) string {
+	if os.Getenv("MUR_HOME") != "" {
+		return LayoutMurHome
+	}
+	if isDir(LegacyDir(home)) {
+		return LayoutLegacy
+	}
+	if isDir(XDGDirs(home).Data) {
+		return LayoutXDG
+	}
+	for _, v := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		if os.Getenv(v) != "" {
+			return LayoutXDG
+		}
+	}
+	return LayoutLegacy
+}
+
+// ResolveDirs returns the mur directories for the given home directory.
+func ResolveDirs(home string) Dirs {
+	switch Layout(home) {
+	case LayoutMurHome:
+		return singleDirs(expandHome(os.Getenv("MUR_HOME"), home))
+	case LayoutXDG:
+		return XDGDirs(home)
+	default:
+		return singleDirs(LegacyDir(home))
+	}
+}
+
+// XDGDirs returns the XDG base directory layout for mur, honoring
+// XDG_CONFIG_HOME, XDG_DATA_HOME, XDG_CACHE_HOME, and XDG_STATE_HOME.
+func XDGDirs(home string) Dirs {
+	return Dirs{
+		Config: filepath.Join(xdgBase("XDG_CONFIG_HOME", home, ".config"), "mur"),
+		Data:   filepath.Join(xdgBase("XDG_DATA_HOME", home, ".local", "share"), "mur"),
+		Cache:  filepath.Join(xdgBase("XDG_CACHE_HOME", home, ".cache"), "mur"),
+		State:  filepath.Join(xdgBase("XDG_STATE_HOME", home, ".local", "state"), "mur"),
+	}
+}
+
+// DataDir returns the mur data directory (~/.mur in the legacy layout).
+func DataDir(home string) string {
+	return ResolveDirs(home).Data
+}
+
+// ConfigDir returns the directory holding config.yaml.
+func ConfigDir(home string) string {
+	return ResolveDirs(home).Config
+}
+
+// CacheDir returns the mur cache directory.
+func CacheDir(home string) string {
+	return ResolveDirs(home).Cache
+}
+
+// StateDir returns the mur state directory.
+func StateDir(home string) string {
+	return ResolveDirs(home).State
+}
+
+// ClaudeDir returns the Claude Code config directory, honoring
+// CLAUDE_CONFIG_DIR (default: ~/.claude).
+func ClaudeDir(home string) string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return expandHome(dir, home)
+	}
+	return filepath.Join(home, ".claude")
+}
+
+// MurHome returns the mur data directory for the current user.
+func MurHome() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil && os.Getenv("MUR_HOME") == "" {
+		return "", err
+	}
+	return DataDir(home), nil
+}
+
+// LegacyDir returns the pre-XDG single directory, ~/.mur.
+func LegacyDir(home string) string {
+	return filepath.Join(home, ".mur")
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func singleDirs(dir string) Dirs {
+	return Dirs{Config: dir, Data: dir, Cache: dir, State: dir}
+}
+
+func xdgBase(env, home string, fallback ...string) string {
+	if v := os.Getenv(env); v != "" && filepath.IsAbs(v) {
+		return v
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
+
+func expandHome(path, home string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
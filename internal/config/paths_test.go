@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func clearDirEnv(t *testing.T) {
+	t.Helper()
+	for _, v := range []string{"MUR_HOME", "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(v, "")
+	}
+}
+
+func TestResolveDirsLegacy(t *testing.T) {
+	clearDirEnv(t)
+	home := t.TempDir()
+
+	dirs := ResolveDirs(home)
+	want := filepath.Join(home, ".mur")
+	if dirs.Data != want || dirs.Config != want || dirs.State != want {
+		t.Errorf("ResolveDirs() = %+v, want everything under %s", dirs, want)
+	}
+}
+
+func TestResolveDirsMurHome(t *testing.T) {
+	clearDirEnv(t)
+	home := t.TempDir()
+	t.Setenv("MUR_HOME", "~/custom-mur")
+
+	if got, want := DataDir(home), filepath.Join(home, "custom-mur"); got != want {
+		t.Errorf("DataDir() = %s, want %s", got, want)
+	}
+	if Layout(home) != LayoutMurHome {
+		t.Errorf("Layout() = %s, want %s", Layout(home), LayoutMurHome)
+	}
+}
+
+func TestResolveDirsXDG(t *testing.T) {
+	clearDirEnv(t)
+	home := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+
+	dirs := ResolveDirs(home)
+	if dirs.State != filepath.Join(home, "state", "mur") {
+		t.Errorf("State = %s", dirs.State)
+	}
+	if dirs.Config != filepath.Join(home, ".config", "mur") {
+		t.Errorf("Config = %s", dirs.Config)
+	}
+
+	// An existing ~/.mur wins over XDG variables
+	os.MkdirAll(filepath.Join(home, ".mur"), 0755)
+	if Layout(home) != LayoutLegacy {
+		t.Errorf("Layout() = %s, want %s", Layout(home), LayoutLegacy)
+	}
+}
+
+func TestMigrationLegacyToXDG(t *testing.T) {
+	clearDirEnv(t)
+	home := t.TempDir()
+	legacy := LegacyDir(home)
+	os.MkdirAll(filepath.Join(legacy, "patterns"), 0755)
+	os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(legacy, "stats.jsonl"), []byte(""), 0644)
+	os.MkdirAll(filepath.Join(legacy, "embeddings"), 0755)
+
+	dst := XDGDirs(home)
+	moves, err := PlanMigration(ResolveDirs(home), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 4 {
+		t.Fatalf("expected 4 moves, got %d", len(moves))
+	}
+	if err := ApplyMigration(moves); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{
+		filepath.Join(dst.Config, "config.yaml"),
+		filepath.Join(dst.Data, "patterns"),
+		filepath.Join(dst.State, "stats.jsonl"),
+		filepath.Join(dst.Cache, "embeddings"),
+	} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("missing %s after migration", p)
+		}
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("empty legacy directory should be removed")
+	}
+	if Layout(home) != LayoutXDG {
+		t.Errorf("Layout() after migration = %s, want %s", Layout(home), LayoutXDG)
+	}
+}
+
+func TestPlanMigrationRefusesOverwrite(t *testing.T) {
+	clearDirEnv(t)
+	home := t.TempDir()
+	os.MkdirAll(filepath.Join(LegacyDir(home), "patterns"), 0755)
+	dst := singleDirs(filepath.Join(home, "new"))
+	os.MkdirAll(filepath.Join(dst.Data, "patterns"), 0755)
+
+	if _, err := PlanMigration(ResolveDirs(home), dst); err == nil {
+		t.Error("expected error when target exists")
+	}
+}
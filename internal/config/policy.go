@@ -0,0 +1,356 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is a team-wide settings document signed by the server and pulled
+// on cloud sync. Keys are dotted config paths, e.g.
+// privacy.semantic_anonymization.enabled.
+type Policy struct {
+	Team      string         `json:"team"`
+	Version   int            `json:"version"`
+	IssuedBy  string         `json:"issued_by,omitempty"`
+	IssuedAt  time.Time      `json:"issued_at"`
+	Enforce   map[string]any `json:"enforce,omitempty"`  // override the local value and cannot be changed
+	Defaults  map[string]any `json:"defaults,omitempty"` // apply only where the local config is unset
+	Signature string         `json:"signature"`          // base64 ed25519 over SigningPayload()
+}
+
+// SigningPayload returns the bytes the server signs: the policy as JSON
+// with an empty signature.
+func (p Policy) SigningPayload() ([]byte, error) {
+	p.Signature = ""
+	return json.Marshal(p)
+}
+
+// Verify checks the policy signature against a base64 ed25519 public key.
+func (p *Policy) Verify(publicKey string) error {
+	if strings.TrimSpace(publicKey) == "" {
+		return fmt.Errorf("no server key pinned; run 'mur cloud sync' first")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid server public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(p.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid policy signature encoding: %w", err)
+	}
+	payload, err := p.SigningPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), payload, sig) {
+		return fmt.Errorf("team policy failed signature verification")
+	}
+	for key := range p.Enforce {
+		if _, dup := p.Defaults[key]; dup {
+			return fmt.Errorf("team policy both enforces and defaults %s", key)
+		}
+	}
+	for _, settings := range []map[string]any{p.Enforce, p.Defaults} {
+		for key, v := range settings {
+			if err := checkPolicyKey(key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// policyKeys are the settings a team policy may set: privacy, community
+// sharing, and which providers and models are used. Anything else, such as
+// hook commands, tool binaries, endpoints or server.*, stays the user's
+// own: a policy that sets it is rejected as a whole. An entry ending in
+// ".*" allows every key under it.
+var policyKeys = []string{
+	"privacy.*",
+	"community.share_enabled",
+	"community.auto_share_on_push",
+	"community.share_extracted",
+	"default_tool",
+	"learning.llm.provider",
+	"learning.llm.model",
+	"learning.llm.premium.provider",
+	"learning.llm.premium.model",
+	"search.provider",
+	"search.model",
+}
+
+// checkPolicyKey returns an error unless a policy may set key to v. A map
+// sets each of its keys, so every one of them must be allowed.
+func checkPolicyKey(key string, v any) error {
+	if sub, ok := v.(map[string]any); ok && len(sub) > 0 {
+		for k, v := range sub {
+			if err := checkPolicyKey(key+"."+k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if policyKeyAllowed(key) {
+		return nil
+	}
+	return fmt.Errorf("team policy sets %s, which a policy may not change", key)
+}
+
+func policyKeyAllowed(key string) bool {
+	// Endpoints are never the team's to choose: they decide where data goes
+	last := key[strings.LastIndex(key, ".")+1:]
+	if strings.HasSuffix(last, "_url") || last == "url" {
+		return false
+	}
+	for _, allowed := range policyKeys {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the enforced and default keys, sorted.
+func (p *Policy) Keys() (enforced, defaults []string) {
+	for k := range p.Enforce {
+		enforced = append(enforced, k)
+	}
+	for k := range p.Defaults {
+		defaults = append(defaults, k)
+	}
+	sort.Strings(enforced)
+	sort.Strings(defaults)
+	return enforced, defaults
+}
+
+// PolicyPath returns the path to the stored team policy.
+func PolicyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(ConfigDir(home), "policy.json"), nil
+}
+
+// LoadPolicy returns the stored team policy, or nil if there is none. The
+// signature is not checked; see Policy.Verify.
+func LoadPolicy() (*Policy, error) {
+	path, err := PolicyPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot read team policy: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("cannot parse team policy: %w", err)
+	}
+	return &p, nil
+}
+
+// SavePolicy stores a team policy, replacing any existing one.
+func SavePolicy(p *Policy) error {
+	path, err := PolicyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RemovePolicy deletes the stored team policy, if any.
+func RemovePolicy() error {
+	path, err := PolicyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// policyUnset marks a key the local config did not set.
+type policyUnset struct{}
+
+// applyStoredPolicy applies the stored team policy if its signature
+// verifies against the pinned server key. A missing or invalid policy is
+// ignored; 'mur config policy show' reports why.
+func (c *Config) applyStoredPolicy() error {
+	p, err := LoadPolicy()
+	if err != nil || p == nil || p.Verify(c.Server.PermissionKey) != nil {
+		return nil
+	}
+	return c.applyPolicy(p)
+}
+
+// applyPolicy overlays a verified policy on the config. The local values it
+// replaces are kept so Save doesn't write team settings into config.yaml.
+func (c *Config) applyPolicy(p *Policy) error {
+	tree, err := configTree(c)
+	if err != nil {
+		return err
+	}
+
+	local := make(map[string]any)
+	for key, v := range p.Defaults {
+		if _, ok := getPath(tree, key); ok {
+			continue
+		}
+		local[key] = policyUnset{}
+		setPath(tree, key, v)
+	}
+	for key, v := range p.Enforce {
+		if old, ok := getPath(tree, key); ok {
+			local[key] = old
+		} else {
+			local[key] = policyUnset{}
+		}
+		setPath(tree, key, v)
+	}
+
+	data, err := yaml.Marshal(tree)
+	if err != nil {
+		return fmt.Errorf("cannot apply team policy: %w", err)
+	}
+	var applied Config
+	if err := yaml.Unmarshal(data, &applied); err != nil {
+		return fmt.Errorf("cannot apply team policy: %w", err)
+	}
+	applied.policy = p
+	applied.policyLocal = local
+	*c = applied
+	return nil
+}
+
+// Policy returns the team policy applied to this config, or nil.
+func (c *Config) Policy() *Policy {
+	return c.policy
+}
+
+// Locked reports whether key, or a section containing it, is enforced by
+// the team policy.
+func (c *Config) Locked(key string) bool {
+	if c.policy == nil {
+		return false
+	}
+	for enforced := range c.policy.Enforce {
+		if key == enforced || strings.HasPrefix(key, enforced+".") || strings.HasPrefix(enforced, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyLocal returns the local value a policy key replaced. set is false
+// when the local config left the key unset; applied is false when the policy
+// didn't touch it (a default the local config already sets).
+func (c *Config) PolicyLocal(key string) (v any, set, applied bool) {
+	v, applied = c.policyLocal[key]
+	if !applied {
+		return nil, true, false
+	}
+	if _, unset := v.(policyUnset); unset {
+		return nil, false, true
+	}
+	return v, true, true
+}
+
+// withoutPolicy returns the YAML for c with the policy's keys restored to
+// their local values.
+func (c *Config) withoutPolicy() ([]byte, error) {
+	tree, err := configTree(c)
+	if err != nil {
+		return nil, err
+	}
+	for key, v := range c.policyLocal {
+		if _, unset := v.(policyUnset); unset {
+			deletePath(tree, key)
+		} else {
+			setPath(tree, key, v)
+		}
+	}
+	return yaml.Marshal(tree)
+}
+
+// configTree returns c as a generic YAML map.
+func configTree(c *Config) (map[string]any, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize config: %w", err)
+	}
+	tree := make(map[string]any)
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("cannot parse serialized config: %w", err)
+	}
+	return tree, nil
+}
+
+func getPath(tree map[string]any, key string) (any, bool) {
+	parts := strings.Split(key, ".")
+	cur := tree
+	for i, part := range parts {
+		v, ok := cur[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return v, true
+		}
+		if cur, ok = v.(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func setPath(tree map[string]any, key string, v any) {
+	parts := strings.Split(key, ".")
+	cur := tree
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := cur[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			cur[part] = next
+		}
+		cur = next
+	}
+	cur[parts[len(parts)-1]] = v
+}
+
+func deletePath(tree map[string]any, key string) {
+	parts := strings.Split(key, ".")
+	cur := tree
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := cur[part].(map[string]any)
+		if !ok {
+			return
+		}
+		cur = next
+	}
+	delete(cur, parts[len(parts)-1])
+}
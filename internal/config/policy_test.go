@@ -0,0 +1,196 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedPolicy(t *testing.T, priv ed25519.PrivateKey, p *Policy) {
+	t.Helper()
+	payload, err := p.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+}
+
+// setupPolicy writes a config pinning a fresh server key and returns a
+// policy signed with it.
+func setupPolicy(t *testing.T, configYAML string) *Policy {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("MUR_HOME", dir)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configYAML += "server:\n  permission_key: " + base64.StdEncoding.EncodeToString(pub) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Policy{
+		Team:     "acme",
+		Version:  2,
+		IssuedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Enforce: map[string]any{
+			"privacy.semantic_anonymization.enabled": true,
+			"community.share_enabled":                false,
+		},
+		Defaults: map[string]any{
+			"search.model":          "nomic-embed-text",
+			"learning.llm.provider": "ollama",
+			"privacy.redact_terms":  []any{"acme-internal"},
+		},
+	}
+	signedPolicy(t, priv, p)
+	return p
+}
+
+func TestLoadAppliesPolicy(t *testing.T) {
+	p := setupPolicy(t, "community:\n  share_enabled: true\nprivacy:\n  redact_terms: [mine]\n")
+	if err := SavePolicy(p); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Policy() == nil {
+		t.Fatal("policy not applied")
+	}
+	if !cfg.Privacy.SemanticAnonymization.Enabled || cfg.Community.ShareEnabled {
+		t.Errorf("enforced keys not applied: privacy=%v share=%v", cfg.Privacy.SemanticAnonymization.Enabled, cfg.Community.ShareEnabled)
+	}
+	if cfg.Search.Model != "nomic-embed-text" || cfg.Learning.LLM.Provider != "ollama" {
+		t.Errorf("defaults not applied: model=%q provider=%q", cfg.Search.Model, cfg.Learning.LLM.Provider)
+	}
+	if len(cfg.Privacy.RedactTerms) != 1 || cfg.Privacy.RedactTerms[0] != "mine" {
+		t.Errorf("default overrode local value: redact_terms=%v", cfg.Privacy.RedactTerms)
+	}
+
+	if local, set, applied := cfg.PolicyLocal("community.share_enabled"); !applied || !set || local != true {
+		t.Errorf("PolicyLocal(share_enabled) = %v, %v, %v", local, set, applied)
+	}
+	if _, _, applied := cfg.PolicyLocal("privacy.redact_terms"); applied {
+		t.Error("redact_terms default should not be applied")
+	}
+
+	for key, want := range map[string]bool{
+		"community.share_enabled":                true,
+		"privacy":                                true,
+		"privacy.semantic_anonymization.enabled": true,
+		"privacy.redact_terms":                   false,
+		"search.top_k":                           false,
+	} {
+		if got := cfg.Locked(key); got != want {
+			t.Errorf("Locked(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestSaveKeepsPolicyOutOfConfig(t *testing.T) {
+	p := setupPolicy(t, "community:\n  share_enabled: true\n")
+	if err := SavePolicy(p); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.DefaultTool = "gemini"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemovePolicy(); err != nil {
+		t.Fatal(err)
+	}
+	local, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.DefaultTool != "gemini" {
+		t.Errorf("DefaultTool = %q, want gemini", local.DefaultTool)
+	}
+	if !local.Community.ShareEnabled || local.Privacy.SemanticAnonymization.Enabled || local.Learning.LLM.Provider != "" {
+		t.Errorf("policy values leaked into config.yaml: share=%v anon=%v provider=%q",
+			local.Community.ShareEnabled, local.Privacy.SemanticAnonymization.Enabled, local.Learning.LLM.Provider)
+	}
+}
+
+func TestLoadIgnoresTamperedPolicy(t *testing.T) {
+	p := setupPolicy(t, "")
+	p.Enforce["community.share_enabled"] = true
+	if err := SavePolicy(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Verify(mustLoad(t).Server.PermissionKey); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Verify = %v, want signature error", err)
+	}
+	cfg := mustLoad(t)
+	if cfg.Policy() != nil || cfg.Community.ShareEnabled {
+		t.Error("tampered policy should not be applied")
+	}
+}
+
+func TestPolicyKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	for setting, v := range map[string]any{
+		"hooks.UserPromptSubmit":                    []any{map[string]any{"hooks": []any{map[string]any{"command": "curl evil"}}}},
+		"tools.claude.binary":                       "/tmp/claude",
+		"server.url":                                "https://evil.example",
+		"server.permission_key":                     "AAAA",
+		"network.mode":                              "normal",
+		"privacy.semantic_anonymization.ollama_url": "http://evil.example",
+		"learning.llm":                              map[string]any{"provider": "openai", "openai_url": "http://evil.example"},
+		"search.top_k":                              float64(7),
+	} {
+		p := &Policy{Team: "acme", Enforce: map[string]any{setting: v}}
+		signedPolicy(t, priv, p)
+		if err := p.Verify(key); err == nil || !strings.Contains(err.Error(), "may not change") {
+			t.Errorf("policy setting %s: Verify = %v, want rejected", setting, err)
+		}
+	}
+
+	p := &Policy{Team: "acme", Enforce: map[string]any{
+		"privacy":      map[string]any{"semantic_anonymization": map[string]any{"enabled": true}},
+		"learning.llm": map[string]any{"provider": "ollama", "model": "llama3.2"},
+		"search.model": "nomic-embed-text",
+	}}
+	signedPolicy(t, priv, p)
+	if err := p.Verify(key); err != nil {
+		t.Errorf("Verify(allowed keys) = %v", err)
+	}
+}
+
+func TestLoadPolicyMissing(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+	p, err := LoadPolicy()
+	if err != nil || p != nil {
+		t.Errorf("LoadPolicy = %v, %v; want nil, nil", p, err)
+	}
+}
+
+func mustLoad(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError means the config file exists but isn't valid. Load returns it
+// so callers can point at 'mur config repair'.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("cannot parse config %s: %v (run 'mur config repair' to recover it)", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// RepairReport describes what Repair recovered from a broken config.
+type RepairReport struct {
+	Path   string
+	Backup string // copy of the broken file; empty if nothing was written
+	// Healthy is true when the config already parsed and was left alone.
+	Healthy bool
+	// Recovered lists the settings kept, as sections ("tools") or, when
+	// only part of a section survived, dotted keys ("sync.auto").
+	Recovered []string
+	// Dropped lists what couldn't be kept.
+	Dropped []DroppedSetting
+	// Regenerated lists sections missing after salvage that were filled
+	// in from defaults.
+	Regenerated []string
+}
+
+// DroppedSetting is a setting or line Repair couldn't keep.
+type DroppedSetting struct {
+	Key    string
+	Line   int // line in the broken file
+	Reason string
+}
+
+// Repair rebuilds the config file at path when it doesn't parse. It keeps
+// every top-level section, or failing that every key within a section,
+// that still parses and has the right type, fills in missing sections from
+// defaults, and backs the broken file up next to it first. With dryRun
+// nothing is written.
+func Repair(path string, dryRun bool) (*RepairReport, error) {
+	report := &RepairReport{Path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no config file at %s (run 'mur init' to create one)", path)
+		}
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+	var probe Config
+	if err := yaml.Unmarshal(data, &probe); err == nil {
+		report.Healthy = true
+		return report, nil
+	}
+
+	recovered := salvage(string(data), report)
+	doc := rebuild(recovered, report)
+
+	out, err := encodeNode(doc)
+	if err != nil {
+		return nil, err
+	}
+	var check Config
+	if err := yaml.Unmarshal(out, &check); err != nil {
+		return nil, fmt.Errorf("repaired config still doesn't parse: %w", err)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	report.Backup = path + ".broken-" + time.Now().Format("20060102-150405")
+	if err := os.WriteFile(report.Backup, data, 0600); err != nil {
+		return nil, fmt.Errorf("cannot back up config: %w", err)
+	}
+	header := "# Murmur Configuration\n# Repaired by 'mur config repair'; the original is in " + report.Backup + "\n\n"
+	if err := os.WriteFile(path, append([]byte(header), out...), 0644); err != nil {
+		return nil, fmt.Errorf("cannot write config: %w", err)
+	}
+	return report, nil
+}
+
+// section is one recovered top-level key.
+type section struct {
+	key   string
+	value *yaml.Node
+}
+
+// topLevelKey matches a line starting a top-level "key:" entry.
+var topLevelKey = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_-]*)\s*:(\s|$)`)
+
+// block is a run of lines belonging to one top-level entry.
+type block struct {
+	key   string // empty for a stray line that isn't a setting
+	line  int    // 1-based
+	lines []string
+}
+
+// splitBlocks splits a config into top-level entries by indentation, so
+// one bad entry can't take the rest down with it.
+func splitBlocks(lines []string, offset int) []block {
+	var blocks []block
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		continues := trimmed == "" || strings.HasPrefix(trimmed, "#") || l[0] == ' ' || l[0] == '\t' ||
+			strings.HasPrefix(l, "- ") || l == "-"
+		if continues && len(blocks) > 0 {
+			blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, l)
+			continue
+		}
+		if continues || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		b := block{line: offset + i + 1, lines: []string{l}}
+		if m := topLevelKey.FindStringSubmatch(l); m != nil {
+			b.key = m[1]
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// salvage returns the top-level sections of a broken config that parse
+// and decode into Config, recording what it drops.
+func salvage(data string, report *RepairReport) []section {
+	known := knownSections()
+	var out []section
+	seen := make(map[string]bool)
+
+	for _, b := range splitBlocks(strings.Split(data, "\n"), 0) {
+		if b.key == "" {
+			report.Dropped = append(report.Dropped, DroppedSetting{
+				Key: truncateLine(b.lines[0]), Line: b.line, Reason: "not a setting",
+			})
+			continue
+		}
+		if !known[b.key] {
+			report.Dropped = append(report.Dropped, DroppedSetting{Key: b.key, Line: b.line, Reason: "unknown setting"})
+			continue
+		}
+		if seen[b.key] {
+			report.Dropped = append(report.Dropped, DroppedSetting{Key: b.key, Line: b.line, Reason: "duplicate; kept the first"})
+			continue
+		}
+
+		if value, err := parseBlock(b.key, b.lines); err == nil {
+			if err := decodes(b.key, value); err == nil {
+				seen[b.key] = true
+				out = append(out, section{key: b.key, value: value})
+				report.Recovered = append(report.Recovered, b.key)
+				continue
+			} else if value.Kind != yaml.MappingNode {
+				report.Dropped = append(report.Dropped, DroppedSetting{Key: b.key, Line: b.line, Reason: yamlReason(err)})
+				continue
+			}
+		}
+
+		// Keep what we can of the section, one key at a time
+		if value := salvageChildren(b, report); value != nil {
+			seen[b.key] = true
+			out = append(out, section{key: b.key, value: value})
+		}
+	}
+	return out
+}
+
+// salvageChildren rebuilds a broken section from its keys that parse.
+func salvageChildren(b block, report *RepairReport) *yaml.Node {
+	body := b.lines[1:]
+	indent := ""
+	for _, l := range body {
+		if t := strings.TrimSpace(l); t != "" && !strings.HasPrefix(t, "#") {
+			indent = l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+			break
+		}
+	}
+	inline := strings.TrimSpace(strings.SplitN(b.lines[0], ":", 2)[1])
+	if indent == "" || (inline != "" && !strings.HasPrefix(inline, "#")) {
+		_, err := parseBlock(b.key, b.lines)
+		if err == nil {
+			err = fmt.Errorf("wrong type")
+		}
+		report.Dropped = append(report.Dropped, DroppedSetting{Key: b.key, Line: b.line, Reason: yamlReason(err)})
+		return nil
+	}
+
+	dedented := make([]string, len(body))
+	for i, l := range body {
+		dedented[i] = strings.TrimPrefix(l, indent)
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	seen := make(map[string]bool)
+	for _, child := range splitBlocks(dedented, b.line) {
+		name := b.key + "." + child.key
+		if child.key == "" {
+			report.Dropped = append(report.Dropped, DroppedSetting{
+				Key: b.key + ": " + truncateLine(child.lines[0]), Line: child.line, Reason: "not a setting",
+			})
+			continue
+		}
+		if seen[child.key] {
+			report.Dropped = append(report.Dropped, DroppedSetting{Key: name, Line: child.line, Reason: "duplicate; kept the first"})
+			continue
+		}
+		value, err := parseBlock(child.key, child.lines)
+		if err == nil {
+			single := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{keyNode(child.key), value}}
+			err = decodes(b.key, single)
+		}
+		if err != nil {
+			report.Dropped = append(report.Dropped, DroppedSetting{Key: name, Line: child.line, Reason: yamlReason(err)})
+			continue
+		}
+		seen[child.key] = true
+		mapping.Content = append(mapping.Content, keyNode(child.key), value)
+		report.Recovered = append(report.Recovered, name)
+	}
+	if len(mapping.Content) == 0 {
+		return nil
+	}
+	return mapping
+}
+
+// parseBlock parses the lines of one "key: value" entry and returns the
+// value node.
+func parseBlock(key string, lines []string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode || len(doc.Content[0].Content) != 2 {
+		return nil, fmt.Errorf("not a %s: value entry", key)
+	}
+	return doc.Content[0].Content[1], nil
+}
+
+// decodes checks that value has the right type for the top-level key.
+func decodes(key string, value *yaml.Node) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{keyNode(key), value}}
+	var cfg Config
+	return doc.Decode(&cfg)
+}
+
+// rebuild assembles the repaired document: recovered sections, then any
+// default section that is still missing.
+func rebuild(recovered []section, report *RepairReport) *yaml.Node {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	have := make(map[string]bool)
+	for _, s := range recovered {
+		root.Content = append(root.Content, keyNode(s.key), s.value)
+		have[s.key] = true
+	}
+
+	var defaults yaml.Node
+	if data, err := yaml.Marshal(defaultConfig()); err == nil && yaml.Unmarshal(data, &defaults) == nil && len(defaults.Content) > 0 {
+		m := defaults.Content[0]
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			key := m.Content[i].Value
+			if have[key] {
+				continue
+			}
+			root.Content = append(root.Content, m.Content[i], m.Content[i+1])
+			report.Regenerated = append(report.Regenerated, key)
+		}
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+}
+
+func encodeNode(doc *yaml.Node) ([]byte, error) {
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("cannot encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("cannot encode config: %w", err)
+	}
+	return []byte(sb.String()), nil
+}
+
+func keyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// knownSections returns the top-level keys of Config.
+func knownSections() map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]; name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	return known
+}
+
+var yamlLinePrefix = regexp.MustCompile(`^(yaml: )?(unmarshal errors:\s*)?(line \d+: )?`)
+
+// yamlReason shortens a yaml error to its last line, without the line
+// number, which is relative to the parsed fragment.
+func yamlReason(err error) string {
+	msg := strings.TrimSpace(err.Error())
+	if i := strings.LastIndex(msg, "\n"); i >= 0 {
+		msg = strings.TrimSpace(msg[i+1:])
+	}
+	return yamlLinePrefix.ReplaceAllString(msg, "")
+}
+
+func truncateLine(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 40 {
+		return s[:37] + "..."
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const brokenConfig = `# my config
+default_tool: gemini
+tools:
+  gemini:
+    enabled: true
+    binary: gemini
+	tier: free
+routing:
+  mode: cost-first
+  complexity_threshold: high
+sync:
+  auto: true
+  targets: [claude
+  prefix_domain: false
+tech_stack:
+- go
+- swift
+colour: blue
+default_tool: claude
+@@garbage
+`
+
+func TestRepair(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(brokenConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Repair(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Healthy {
+		t.Fatal("broken config reported healthy")
+	}
+
+	recovered := strings.Join(report.Recovered, ",")
+	for _, want := range []string{"default_tool", "routing.mode", "sync.auto", "sync.prefix_domain", "tech_stack"} {
+		if !strings.Contains(","+recovered+",", ","+want+",") {
+			t.Errorf("%s not recovered (recovered: %s)", want, recovered)
+		}
+	}
+
+	dropped := make(map[string]DroppedSetting)
+	for _, d := range report.Dropped {
+		dropped[d.Key] = d
+	}
+	for _, want := range []string{"tools.gemini", "routing.complexity_threshold", "sync.targets", "colour", "@@garbage"} {
+		if _, ok := dropped[want]; !ok {
+			t.Errorf("%s not reported as dropped (dropped: %+v)", want, report.Dropped)
+		}
+	}
+	if d := dropped["default_tool"]; d.Line != 19 || !strings.Contains(d.Reason, "duplicate") {
+		t.Errorf("second default_tool = %+v", d)
+	}
+	if !strings.Contains(strings.Join(report.Regenerated, ","), "learning") {
+		t.Errorf("regenerated = %v, want learning among them", report.Regenerated)
+	}
+
+	backup, err := os.ReadFile(report.Backup)
+	if err != nil || string(backup) != brokenConfig {
+		t.Errorf("backup not kept intact: %v", err)
+	}
+
+	// The repaired file loads and keeps the recovered values
+	t.Setenv("MUR_HOME", dir)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("repaired config doesn't load: %v", err)
+	}
+	if cfg.DefaultTool != "gemini" || cfg.Routing.Mode != "cost-first" || len(cfg.TechStack) != 2 {
+		t.Errorf("recovered values lost: %+v", cfg)
+	}
+	if _, ok := cfg.Tools["claude"]; !ok {
+		t.Error("tools weren't regenerated from defaults")
+	}
+
+	report, err = Repair(path, false)
+	if err != nil || !report.Healthy {
+		t.Errorf("second repair = %+v, %v; want healthy", report, err)
+	}
+}
+
+func TestLoadReportsParseError(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("MUR_HOME", dir)
+	_ = os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("tools: [\n"), 0644)
+
+	_, err := Load()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("err = %v, want *ParseError", err)
+	}
+	if !strings.Contains(err.Error(), "mur config repair") {
+		t.Errorf("error doesn't mention repair: %v", err)
+	}
+}
+
+func TestRepairDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(path, []byte("default_tool: [\n"), 0644)
+
+	report, err := Repair(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Backup != "" || len(report.Dropped) != 1 {
+		t.Errorf("dry run report = %+v", report)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "default_tool: [\n" {
+		t.Error("dry run changed the file")
+	}
+}
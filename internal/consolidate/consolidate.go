@@ -7,6 +7,7 @@ import (
 	"github.com/mur-run/mur-core/internal/cache"
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/analytics"
+	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/inject"
 	"github.com/mur-run/mur-core/internal/core/pattern"
 )
@@ -28,6 +29,7 @@ type ConsolidationReport struct {
 	HealthScores     []HealthScore   `json:"health_scores"`
 	MergeProposals   []MergeProposal `json:"merge_proposals"`
 	Conflicts        []Conflict      `json:"conflicts"`
+	Decayed          []DecayChange   `json:"decayed,omitempty"`
 	ActionsApplied   int             `json:"actions_applied"`
 	PatternsKept     int             `json:"patterns_kept"`
 	PatternsArchived int             `json:"patterns_archived"`
@@ -45,8 +47,14 @@ type Consolidator struct {
 	injTracker       *inject.Tracker
 	analyticsTracker *analytics.Tracker
 	conflictDetector ConflictDetector
+	vectors          map[string]embed.Vector // pattern ID → embedding
+	merger           Merger
 }
 
+// Merger merges the content of duplicates into the pattern kept, for the
+// llm-merge strategy.
+type Merger func(keep *pattern.Pattern, dups []*pattern.Pattern) (string, error)
+
 // NewConsolidator creates a new Consolidator.
 func NewConsolidator(
 	cfg config.ConsolidationConfig,
@@ -72,6 +80,18 @@ func (c *Consolidator) WithConflictDetector(d ConflictDetector) {
 	c.conflictDetector = d
 }
 
+// WithVectors detects duplicates by these embeddings, by pattern ID,
+// instead of the embedding matrix.
+func (c *Consolidator) WithVectors(vectors map[string]embed.Vector) {
+	c.vectors = vectors
+}
+
+// WithMerger sets how llm-merge proposals are merged in auto mode;
+// without one they're only reported.
+func (c *Consolidator) WithMerger(m Merger) {
+	c.merger = m
+}
+
 // Run executes the full consolidation pipeline.
 func (c *Consolidator) Run(mode Mode, force bool) (*ConsolidationReport, error) {
 	start := time.Now()
@@ -109,7 +129,15 @@ func (c *Consolidator) Run(mode Mode, force bool) (*ConsolidationReport, error)
 	if strategy != "" {
 		detector := NewDuplicateDetector(c.embeddingMatrix, c.cfg.MergeThreshold, strategy)
 		detector.WithHealthScores(healthScores)
-		mergeProposals = detector.Detect(patterns)
+		if c.vectors != nil {
+			vectors := make([]embed.Vector, len(patterns))
+			for i, p := range patterns {
+				vectors[i] = c.vectors[p.ID]
+			}
+			mergeProposals = detector.DetectVectors(patterns, vectors)
+		} else {
+			mergeProposals = detector.Detect(patterns)
+		}
 	}
 
 	// Phase 4: Detect conflicts
@@ -126,11 +154,12 @@ func (c *Consolidator) Run(mode Mode, force bool) (*ConsolidationReport, error)
 		HealthScores:   healthScores,
 		MergeProposals: mergeProposals,
 		Conflicts:      conflicts,
+		Decayed:        Decay(patterns, c.cfg.DecayHalfLifeDays, c.cfg.GracePeriodDays, time.Now()),
 	}
 
 	// Phase 5: Apply actions (only in auto mode)
 	if mode == ModeAuto {
-		c.applyActions(report, patterns, healthScores, mergeProposals)
+		c.applyActions(report, patterns, healthScores, mergeProposals, report.Decayed)
 	}
 
 	// Count action summary
@@ -151,78 +180,92 @@ func (c *Consolidator) Run(mode Mode, force bool) (*ConsolidationReport, error)
 	return report, nil
 }
 
-// applyActions executes safe automatic actions (archive, keep-best merge).
-func (c *Consolidator) applyActions(report *ConsolidationReport, patterns []*pattern.Pattern, scores []HealthScore, proposals []MergeProposal) {
+// applyActions executes safe automatic actions: confidence decay,
+// archiving, and merges by the configured strategy.
+func (c *Consolidator) applyActions(report *ConsolidationReport, patterns []*pattern.Pattern, scores []HealthScore, proposals []MergeProposal, decayed []DecayChange) {
 	patternMap := make(map[string]*pattern.Pattern, len(patterns))
 	for _, p := range patterns {
 		patternMap[p.ID] = p
 	}
 
 	now := time.Now()
+	done := make(map[string]bool) // archived or merged: already saved
 
-	// Apply archive actions
-	if c.cfg.AutoArchive {
-		for _, hs := range scores {
-			if hs.Action == ActionArchive {
-				p, ok := patternMap[hs.PatternID]
-				if !ok {
-					continue
-				}
-				p.Lifecycle.Status = pattern.StatusArchived
-				p.Lifecycle.DeprecationReason = "auto-archived: " + hs.Reason
-				p.Health.Score = hs.Overall
-				p.Health.LastConsolidated = &now
-				if err := c.store.Update(p); err == nil {
-					report.ActionsApplied++
-				}
+	// Apply merges
+	for _, proposal := range proposals {
+		if proposal.KeepID == "" {
+			continue
+		}
+		if proposal.Strategy != StrategyKeepBest && (proposal.Strategy != StrategyLLMMerge || c.merger == nil) {
+			continue
+		}
+		keep, ok := patternMap[proposal.KeepID]
+		if !ok {
+			continue
+		}
+		var dups []*pattern.Pattern
+		for _, id := range proposal.RemoveIDs {
+			if p, ok := patternMap[id]; ok && !p.Pinned {
+				dups = append(dups, p)
 			}
 		}
+		if len(dups) == 0 {
+			continue
+		}
+		if proposal.Strategy == StrategyLLMMerge {
+			content, err := c.merger(keep, dups)
+			if err != nil {
+				continue // leave the group for the next run
+			}
+			keep.Content = content
+		}
+		archived, _ := ArchiveDuplicates(c.store, keep, dups, now)
+		report.ActionsApplied += len(archived)
+		done[keep.ID] = true
+		for _, p := range dups {
+			done[p.ID] = true
+		}
 	}
 
-	// Apply keep-best merges
-	if c.cfg.AutoMerge == "keep-best" {
-		for _, proposal := range proposals {
-			if proposal.Strategy != StrategyKeepBest || proposal.KeepID == "" {
+	// Apply archive actions
+	if c.cfg.AutoArchive {
+		for _, hs := range scores {
+			if hs.Action != ActionArchive || done[hs.PatternID] {
 				continue
 			}
-
-			// Archive the losers
-			for _, removeID := range proposal.RemoveIDs {
-				p, ok := patternMap[removeID]
-				if !ok {
-					continue
-				}
-				p.Lifecycle.Status = pattern.StatusArchived
-				p.Lifecycle.DeprecationReason = fmt.Sprintf("merged: duplicate of %s", proposal.KeepID)
-				p.Relations.Supersedes = "" // the kept pattern supersedes this one
-				p.Health.Score = 0
-				p.Health.LastConsolidated = &now
-				if err := c.store.Update(p); err == nil {
-					report.ActionsApplied++
-				}
+			p, ok := patternMap[hs.PatternID]
+			if !ok || p.Pinned {
+				continue
 			}
-
-			// Update the keeper with relations
-			keeper, ok := patternMap[proposal.KeepID]
-			if ok {
-				keeper.Relations.Related = append(keeper.Relations.Related, proposal.RemoveIDs...)
-				keeper.Health.LastConsolidated = &now
-				_ = c.store.Update(keeper)
+			p.Lifecycle.Status = pattern.StatusArchived
+			p.Lifecycle.DeprecationReason = "auto-archived: " + hs.Reason
+			p.Health.Score = hs.Overall
+			p.Health.LastConsolidated = &now
+			if err := c.store.Update(p); err == nil {
+				report.ActionsApplied++
 			}
+			done[hs.PatternID] = true
 		}
 	}
 
-	// Update health metadata on all patterns
+	// Decay confidence, and record health, on the rest without marking
+	// them updated, so they keep aging
+	var applied []DecayChange
+	for _, d := range decayed {
+		if p, ok := patternMap[d.PatternID]; ok && !done[d.PatternID] {
+			p.Learning.Effectiveness = d.To
+			applied = append(applied, d)
+		}
+	}
+	report.Decayed = applied
 	for _, hs := range scores {
-		if hs.Action == ActionKeep || hs.Action == ActionUpdate {
-			p, ok := patternMap[hs.PatternID]
-			if !ok {
-				continue
-			}
-			p.Health.Score = hs.Overall
-			p.Health.LastConsolidated = &now
-			_ = c.store.Update(p)
+		p, ok := patternMap[hs.PatternID]
+		if !ok || done[hs.PatternID] {
+			continue
 		}
+		p.Health.Score = hs.Overall
+		p.Health.LastConsolidated = &now
+		_ = c.store.SaveMeta(p)
 	}
 }
 
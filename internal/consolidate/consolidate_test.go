@@ -1,13 +1,17 @@
 package consolidate
 
 import (
+	"encoding/json"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/mur-run/mur-core/internal/cache"
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/analytics"
+	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/inject"
 	"github.com/mur-run/mur-core/internal/core/pattern"
 )
@@ -554,3 +558,210 @@ func findSubstring(s, sub string) bool {
 	}
 	return false
 }
+
+func TestDuplicateDetector_DetectVectors(t *testing.T) {
+	d := NewDuplicateDetector(nil, 0.9, StrategyKeepBest)
+	d.WithHealthScores([]HealthScore{{PatternID: "p2", Overall: 0.9}})
+	now := time.Now()
+	patterns := []*pattern.Pattern{
+		makePattern("p1", "wrap-errors", now, 1, nil),
+		makePattern("p2", "go-error-wrapping", now, 1, nil),
+		makePattern("p3", "docker-layers", now, 1, nil),
+		makePattern("p4", "unindexed", now, 1, nil),
+		makePattern("p5", "docker-cache", now, 1, nil),
+	}
+	vectors := []embed.Vector{{1, 0, 0}, {0.99, 0.1, 0}, {0, 1, 0}, nil, {0, 0.95, 0.2}}
+
+	proposals := d.DetectVectors(patterns, vectors)
+	if len(proposals) != 2 {
+		t.Fatalf("proposals = %d, want 2", len(proposals))
+	}
+	first := proposals[0]
+	if len(first.Patterns) != 2 || first.KeepID != "p2" || first.RemoveIDs[0] != "p1" {
+		t.Errorf("first proposal = %+v", first)
+	}
+	if first.Similarity < 0.99 || proposals[1].Similarity >= first.Similarity {
+		t.Errorf("similarities = %.3f, %.3f", first.Similarity, proposals[1].Similarity)
+	}
+}
+
+func TestArchiveDuplicates(t *testing.T) {
+	store := pattern.NewStore(t.TempDir())
+	for _, name := range []string{"keep", "dup-a", "dup-b"} {
+		if err := store.Create(&pattern.Pattern{Name: name, Content: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keep, _ := store.Get("keep")
+	a, _ := store.Get("dup-a")
+	b, _ := store.Get("dup-b")
+
+	archived, err := ArchiveDuplicates(store, keep, []*pattern.Pattern{a, b}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 2 {
+		t.Errorf("archived = %v", archived)
+	}
+	keep, _ = store.Get("keep")
+	if len(keep.Relations.Related) != 2 || keep.Lifecycle.Status == pattern.StatusArchived {
+		t.Errorf("keep = %+v, %+v", keep.Relations, keep.Lifecycle)
+	}
+	a, _ = store.Get("dup-a")
+	if a.Lifecycle.Status != pattern.StatusArchived || a.Lifecycle.DeprecationReason != "duplicate of keep" || a.Relations.Related[0] != "keep" {
+		t.Errorf("dup-a = %+v, %+v", a.Relations, a.Lifecycle)
+	}
+}
+
+func TestDecay(t *testing.T) {
+	now := time.Now()
+	lastUsed := now.Add(-90 * 24 * time.Hour)
+
+	used := makePattern("p1", "used", now.Add(-200*24*time.Hour), 5, &lastUsed)
+	used.Learning.Effectiveness = 0.8
+	fresh := makePattern("p2", "fresh", now.Add(-2*24*time.Hour), 0, nil)
+	fresh.Learning.Effectiveness = 0.5
+	pinned := makePattern("p3", "pinned", now.Add(-200*24*time.Hour), 0, nil)
+	pinned.Learning.Effectiveness = 0.5
+	pinned.Pinned = true
+	floor := makePattern("p4", "floor", now.Add(-3000*24*time.Hour), 0, nil)
+	floor.Learning.Effectiveness = 0.5
+
+	changes := Decay([]*pattern.Pattern{used, fresh, pinned, floor}, 90, 14, now)
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want used and floor", changes)
+	}
+	byName := map[string]DecayChange{}
+	for _, c := range changes {
+		byName[c.Name] = c
+	}
+	if got := byName["used"].To; math.Abs(got-0.4) > 0.001 {
+		t.Errorf("used decayed to %.3f, want 0.4 after one half-life", got)
+	}
+	if got := byName["floor"].To; got != minEffectiveness {
+		t.Errorf("floor decayed to %.3f, want %.2f", got, minEffectiveness)
+	}
+	if used.Learning.Effectiveness != 0.8 {
+		t.Error("Decay should not apply changes")
+	}
+}
+
+func TestConsolidator_RunAuto(t *testing.T) {
+	dir := t.TempDir()
+	store := pattern.NewStore(dir)
+	now := time.Now().UTC()
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+	recent, month := days(1), days(30)
+
+	setup := []struct {
+		name    string
+		created time.Time
+		uses    int
+		last    *time.Time
+		pinned  bool
+	}{
+		{"stale", days(400), 0, nil, false},
+		{"stale-pinned", days(400), 0, nil, true},
+		{"aging", days(100), 50, &month, false},
+		{"dup-a", days(100), 10, &recent, false},
+		{"dup-b", days(100), 40, &recent, false},
+	}
+	for _, s := range setup {
+		if err := store.Create(&pattern.Pattern{Name: s.name, Content: s.name}); err != nil {
+			t.Fatal(err)
+		}
+		p, _ := store.Get(s.name)
+		p.Lifecycle.Created, p.Lifecycle.Updated = s.created, s.created
+		p.Learning.UsageCount, p.Learning.LastUsed = s.uses, s.last
+		p.Learning.Effectiveness = 0.8
+		p.Pinned = s.pinned
+		if err := store.SaveMeta(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pc := cache.NewPatternCache()
+	if err := pc.Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]embed.Vector{
+		"stale":        {0, 1, 0, 0},
+		"stale-pinned": {0, 0, 1, 0},
+		"aging":        {0, 0, 0, 1},
+		"dup-a":        {1, 0, 0, 0},
+		"dup-b":        {1, 0, 0, 0},
+	}
+	vectors := map[string]embed.Vector{}
+	for _, p := range pc.Active() {
+		vectors[p.ID] = byName[p.Name]
+	}
+
+	c := NewConsolidator(defaultCfg(), store, pc, nil, nil, nil)
+	c.WithVectors(vectors)
+	report, err := c.Run(ModeAuto, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.MergeProposals) != 1 {
+		t.Fatalf("merge proposals = %+v, want the dup pair", report.MergeProposals)
+	}
+
+	get := func(name string) *pattern.Pattern {
+		p, err := store.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	if p := get("stale"); p.Lifecycle.Status != pattern.StatusArchived {
+		t.Errorf("stale: status %s, want archived", p.Lifecycle.Status)
+	}
+	if p := get("stale-pinned"); p.Lifecycle.Status != pattern.StatusActive {
+		t.Errorf("stale-pinned: status %s, want active", p.Lifecycle.Status)
+	}
+	if p := get("dup-a"); p.Lifecycle.Status != pattern.StatusArchived || p.Lifecycle.DeprecationReason != "duplicate of dup-b" {
+		t.Errorf("dup-a: %+v, want archived as duplicate of dup-b", p.Lifecycle)
+	}
+	aging := get("aging")
+	if aging.Learning.Effectiveness >= 0.8 || aging.Learning.Effectiveness < 0.6 {
+		t.Errorf("aging: effectiveness %.3f, want decayed by a third of a half-life", aging.Learning.Effectiveness)
+	}
+	if !aging.Lifecycle.Updated.Equal(days(100)) {
+		t.Errorf("aging: marked updated at %v; consolidation should leave it aging", aging.Lifecycle.Updated)
+	}
+	if aging.Health.LastConsolidated == nil {
+		t.Error("aging: LastConsolidated not set")
+	}
+}
+
+func TestSaveReport(t *testing.T) {
+	dir := t.TempDir()
+	p1 := makePattern("p1", "alpha", time.Now(), 0, nil)
+	p2 := makePattern("p2", "beta", time.Now(), 0, nil)
+	r := &ConsolidationReport{
+		Timestamp:    time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Mode:         ModeAuto,
+		HealthScores: []HealthScore{{PatternID: "p1", Action: ActionArchive, Reason: "stale and unused"}, {PatternID: "p2", Action: ActionKeep}},
+		MergeProposals: []MergeProposal{
+			{Patterns: []*pattern.Pattern{p1, p2}, Similarity: 0.9, Strategy: StrategyKeepBest, KeepID: "p2"},
+		},
+	}
+	path, err := SaveReport(dir, r, map[string]string{"p1": "alpha", "p2": "beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "consolidation-20261016-093000.json" {
+		t.Errorf("path = %s", path)
+	}
+	data, _ := os.ReadFile(path)
+	var saved savedReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Actions) != 1 || saved.Actions[0].Pattern != "alpha" {
+		t.Errorf("actions = %+v", saved.Actions)
+	}
+	if len(saved.Merges) != 1 || saved.Merges[0].Keep != "beta" || len(saved.Merges[0].Patterns) != 2 {
+		t.Errorf("merges = %+v", saved.Merges)
+	}
+}
@@ -0,0 +1,70 @@
+package consolidate
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// minEffectiveness is as far as confidence decays; 0 would read as unset
+// and be reset to 0.5.
+const minEffectiveness = 0.05
+
+// DecayChange is the decayed confidence (effectiveness) of one pattern.
+type DecayChange struct {
+	PatternID string  `json:"pattern_id"`
+	Name      string  `json:"name"`
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+}
+
+// Decay halves each pattern's effectiveness every halfLifeDays since it
+// was last used or consolidated, whichever is later, and returns the
+// changes without applying them, largest first. Pinned patterns and
+// unused patterns still in their grace period don't decay.
+func Decay(patterns []*pattern.Pattern, halfLifeDays, graceDays int, now time.Time) []DecayChange {
+	if halfLifeDays <= 0 {
+		return nil
+	}
+	halfLife := time.Duration(halfLifeDays) * 24 * time.Hour
+	grace := time.Duration(graceDays) * 24 * time.Hour
+
+	var changes []DecayChange
+	for _, p := range patterns {
+		if p.Pinned || p.Learning.Effectiveness <= minEffectiveness {
+			continue
+		}
+		if p.Learning.UsageCount == 0 && now.Sub(p.Lifecycle.Created) < grace {
+			continue
+		}
+		since := p.Lifecycle.Created
+		if p.Learning.LastUsed != nil && p.Learning.LastUsed.After(since) {
+			since = *p.Learning.LastUsed
+		}
+		if p.Health.LastConsolidated != nil && p.Health.LastConsolidated.After(since) {
+			since = *p.Health.LastConsolidated
+		}
+		elapsed := now.Sub(since)
+		if elapsed <= 0 {
+			continue
+		}
+
+		from := p.Learning.Effectiveness
+		to := math.Max(from*math.Pow(0.5, float64(elapsed)/float64(halfLife)), minEffectiveness)
+		if to >= from {
+			continue
+		}
+		changes = append(changes, DecayChange{
+			PatternID: p.ID,
+			Name:      p.Name,
+			From:      from,
+			To:        to,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].From-changes[i].To > changes[j].From-changes[j].To
+	})
+	return changes
+}
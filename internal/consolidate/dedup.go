@@ -1,9 +1,13 @@
 package consolidate
 
 import (
+	"fmt"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/mur-run/mur-core/internal/cache"
+	"github.com/mur-run/mur-core/internal/core/embed"
 	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
@@ -57,28 +61,55 @@ func (d *DuplicateDetector) Detect(patterns []*pattern.Pattern) []MergeProposal
 	}
 
 	// Get all pairwise similarities above threshold
-	pairs := d.matrix.AllPairs(d.threshold)
-
-	// Build a map of pattern IDs to patterns
-	patternMap := make(map[string]*pattern.Pattern, len(patterns))
-	for _, p := range patterns {
-		patternMap[p.ID] = p
-	}
-
-	// Group overlapping pairs into clusters using union-find
-	uf := newUnionFind(len(patterns))
 	idxMap := make(map[string]int, len(patterns))
 	for i, p := range patterns {
 		idxMap[p.ID] = i
 	}
-
-	for _, pair := range pairs {
+	var pairs []indexPair
+	for _, pair := range d.matrix.AllPairs(d.threshold) {
 		idxA, okA := idxMap[pair.IDA]
 		idxB, okB := idxMap[pair.IDB]
 		if okA && okB {
-			uf.union(idxA, idxB)
+			pairs = append(pairs, indexPair{idxA, idxB, pair.Similarity})
 		}
 	}
+	return d.propose(patterns, pairs)
+}
+
+// DetectVectors finds duplicates among patterns by the embeddings in
+// vectors, which holds the embedding of each pattern by index, or nil for
+// patterns that aren't indexed.
+func (d *DuplicateDetector) DetectVectors(patterns []*pattern.Pattern, vectors []embed.Vector) []MergeProposal {
+	var pairs []indexPair
+	for i := range patterns {
+		if i >= len(vectors) || vectors[i] == nil {
+			continue
+		}
+		for j := i + 1; j < len(patterns) && j < len(vectors); j++ {
+			if vectors[j] == nil || len(vectors[j]) != len(vectors[i]) {
+				continue
+			}
+			if sim := embed.CosineSimilarity(vectors[i], vectors[j]); sim >= d.threshold {
+				pairs = append(pairs, indexPair{i, j, sim})
+			}
+		}
+	}
+	return d.propose(patterns, pairs)
+}
+
+// indexPair is the similarity of two patterns, by index.
+type indexPair struct {
+	a, b       int
+	similarity float64
+}
+
+// propose groups overlapping pairs into clusters and proposes a merge for
+// each cluster of two or more patterns, most similar first.
+func (d *DuplicateDetector) propose(patterns []*pattern.Pattern, pairs []indexPair) []MergeProposal {
+	uf := newUnionFind(len(patterns))
+	for _, pair := range pairs {
+		uf.union(pair.a, pair.b)
+	}
 
 	// Collect clusters
 	clusters := make(map[int][]int)
@@ -87,9 +118,16 @@ func (d *DuplicateDetector) Detect(patterns []*pattern.Pattern) []MergeProposal
 		clusters[root] = append(clusters[root], i)
 	}
 
+	// Find max pairwise similarity within each cluster
+	maxSim := make(map[int]float64)
+	for _, pair := range pairs {
+		root := uf.find(pair.a)
+		maxSim[root] = max(maxSim[root], pair.similarity)
+	}
+
 	// Build merge proposals for clusters with 2+ members
 	var proposals []MergeProposal
-	for _, members := range clusters {
+	for root, members := range clusters {
 		if len(members) < 2 {
 			continue
 		}
@@ -99,23 +137,13 @@ func (d *DuplicateDetector) Detect(patterns []*pattern.Pattern) []MergeProposal
 			clusterPatterns[i] = patterns[idx]
 		}
 
-		// Find max pairwise similarity within cluster
-		maxSim := 0.0
-		for _, pair := range pairs {
-			_, inA := idxMap[pair.IDA]
-			_, inB := idxMap[pair.IDB]
-			if inA && inB && pair.Similarity > maxSim {
-				maxSim = pair.Similarity
-			}
-		}
-
 		proposal := MergeProposal{
 			Patterns:   clusterPatterns,
-			Similarity: maxSim,
+			Similarity: maxSim[root],
 			Strategy:   d.strategy,
 		}
 
-		if d.strategy == StrategyKeepBest {
+		if d.strategy == StrategyKeepBest || d.strategy == StrategyLLMMerge {
 			d.selectBest(&proposal)
 		}
 
@@ -124,7 +152,10 @@ func (d *DuplicateDetector) Detect(patterns []*pattern.Pattern) []MergeProposal
 
 	// Sort proposals by similarity (highest first)
 	sort.Slice(proposals, func(i, j int) bool {
-		return proposals[i].Similarity > proposals[j].Similarity
+		if proposals[i].Similarity != proposals[j].Similarity {
+			return proposals[i].Similarity > proposals[j].Similarity
+		}
+		return proposals[i].Patterns[0].Name < proposals[j].Patterns[0].Name
 	})
 
 	return proposals
@@ -159,6 +190,38 @@ func (d *DuplicateDetector) selectBest(proposal *MergeProposal) {
 	}
 }
 
+// ArchiveDuplicates keeps keep and archives dups as its duplicates. Each
+// archived pattern records what it duplicates, and keep lists them as
+// related, so either side leads to the other. keep is saved first, so a
+// merged content that doesn't fit leaves everything as it was. It returns
+// the names archived.
+func ArchiveDuplicates(store *pattern.Store, keep *pattern.Pattern, dups []*pattern.Pattern, now time.Time) ([]string, error) {
+	for _, p := range dups {
+		if !slices.Contains(keep.Relations.Related, p.Name) {
+			keep.Relations.Related = append(keep.Relations.Related, p.Name)
+		}
+	}
+	keep.Health.LastConsolidated = &now
+	if err := store.Update(keep); err != nil {
+		return nil, fmt.Errorf("cannot update %s: %w", keep.Name, err)
+	}
+
+	var archived []string
+	for _, p := range dups {
+		p.Lifecycle.Status = pattern.StatusArchived
+		p.Lifecycle.DeprecationReason = "duplicate of " + keep.Name
+		if !slices.Contains(p.Relations.Related, keep.Name) {
+			p.Relations.Related = append(p.Relations.Related, keep.Name)
+		}
+		p.Health.LastConsolidated = &now
+		if err := store.Update(p); err != nil {
+			return archived, fmt.Errorf("cannot archive %s: %w", p.Name, err)
+		}
+		archived = append(archived, p.Name)
+	}
+	return archived, nil
+}
+
 // unionFind is a simple disjoint-set / union-find structure.
 type unionFind struct {
 	parent []int
@@ -1,10 +1,17 @@
 package consolidate
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
+// maxDecayListed is how many decayed patterns FormatReport lists.
+const maxDecayListed = 10
+
 // FormatReport renders a ConsolidationReport for CLI output.
 func FormatReport(r *ConsolidationReport, patternNames map[string]string) string {
 	var b strings.Builder
@@ -22,6 +29,7 @@ func FormatReport(r *ConsolidationReport, patternNames map[string]string) string
 	b.WriteString(fmt.Sprintf("  Archive: %d\n", r.PatternsArchived))
 	b.WriteString(fmt.Sprintf("  Merge:   %d\n", r.PatternsMerged))
 	b.WriteString(fmt.Sprintf("  Update:  %d\n", r.PatternsUpdated))
+	b.WriteString(fmt.Sprintf("  Decay:   %d\n", len(r.Decayed)))
 	if r.Mode == ModeAuto {
 		b.WriteString(fmt.Sprintf("  Actions applied: %d\n", r.ActionsApplied))
 	}
@@ -74,6 +82,20 @@ func FormatReport(r *ConsolidationReport, patternNames map[string]string) string
 		}
 	}
 
+	// Confidence decay
+	if len(r.Decayed) > 0 {
+		b.WriteString("Confidence Decay\n")
+		b.WriteString("----------------\n")
+		for i, d := range r.Decayed {
+			if i == maxDecayListed {
+				b.WriteString(fmt.Sprintf("  ... and %d more\n", len(r.Decayed)-maxDecayListed))
+				break
+			}
+			b.WriteString(fmt.Sprintf("  %s: %.2f → %.2f\n", d.Name, d.From, d.To))
+		}
+		b.WriteString("\n")
+	}
+
 	// Conflicts
 	if len(r.Conflicts) > 0 {
 		b.WriteString("Conflicts\n")
@@ -109,3 +131,103 @@ func actionLabel(a Action) string {
 		return "KEEP   "
 	}
 }
+
+// savedReport is a consolidation report as recorded on disk: the report
+// with patterns referred to by name rather than in full.
+type savedReport struct {
+	Timestamp        time.Time       `json:"timestamp"`
+	Mode             Mode            `json:"mode"`
+	TotalPatterns    int             `json:"total_patterns"`
+	ActionsApplied   int             `json:"actions_applied"`
+	PatternsKept     int             `json:"patterns_kept"`
+	PatternsArchived int             `json:"patterns_archived"`
+	PatternsMerged   int             `json:"patterns_merged"`
+	PatternsUpdated  int             `json:"patterns_updated"`
+	DurationMS       int64           `json:"duration_ms"`
+	Actions          []savedAction   `json:"actions"`
+	Merges           []savedMerge    `json:"merges"`
+	Decayed          []DecayChange   `json:"decayed"`
+	Conflicts        []savedConflict `json:"conflicts"`
+}
+
+type savedAction struct {
+	Pattern string  `json:"pattern"`
+	Action  Action  `json:"action"`
+	Score   float64 `json:"score"`
+	Reason  string  `json:"reason"`
+}
+
+type savedMerge struct {
+	Similarity float64       `json:"similarity"`
+	Strategy   MergeStrategy `json:"strategy"`
+	Keep       string        `json:"keep,omitempty"`
+	Patterns   []string      `json:"patterns"`
+}
+
+type savedConflict struct {
+	Type     ConflictType `json:"type"`
+	PatternA string       `json:"pattern_a"`
+	PatternB string       `json:"pattern_b"`
+	Reason   string       `json:"reason"`
+}
+
+// SaveReport records r as JSON in dir, named after its time, and returns
+// the file's path.
+func SaveReport(dir string, r *ConsolidationReport, patternNames map[string]string) (string, error) {
+	name := func(id string) string {
+		if n := patternNames[id]; n != "" {
+			return n
+		}
+		return id
+	}
+
+	saved := savedReport{
+		Timestamp:        r.Timestamp,
+		Mode:             r.Mode,
+		TotalPatterns:    r.TotalPatterns,
+		ActionsApplied:   r.ActionsApplied,
+		PatternsKept:     r.PatternsKept,
+		PatternsArchived: r.PatternsArchived,
+		PatternsMerged:   r.PatternsMerged,
+		PatternsUpdated:  r.PatternsUpdated,
+		DurationMS:       r.Duration.Milliseconds(),
+		Actions:          []savedAction{},
+		Merges:           []savedMerge{},
+		Decayed:          r.Decayed,
+		Conflicts:        []savedConflict{},
+	}
+	if saved.Decayed == nil {
+		saved.Decayed = []DecayChange{}
+	}
+	for _, hs := range r.HealthScores {
+		if hs.Action != ActionKeep {
+			saved.Actions = append(saved.Actions, savedAction{name(hs.PatternID), hs.Action, hs.Overall, hs.Reason})
+		}
+	}
+	for _, mp := range r.MergeProposals {
+		m := savedMerge{Similarity: mp.Similarity, Strategy: mp.Strategy}
+		if mp.KeepID != "" {
+			m.Keep = name(mp.KeepID)
+		}
+		for _, p := range mp.Patterns {
+			m.Patterns = append(m.Patterns, p.Name)
+		}
+		saved.Merges = append(saved.Merges, m)
+	}
+	for _, c := range r.Conflicts {
+		saved.Conflicts = append(saved.Conflicts, savedConflict{c.Type, c.PatternA.Name, c.PatternB.Name, c.Reason})
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("cannot create reports directory: %w", err)
+	}
+	path := filepath.Join(dir, "consolidation-"+r.Timestamp.Format("20060102-150405")+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("cannot write report: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,207 @@
+package consolidate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// TeamChange is a change a consolidation run proposes for a pattern in
+// the team learning repo.
+type TeamChange struct {
+	Name     string  `json:"name"`
+	ID       string  `json:"id"`
+	Action   Action  `json:"action"` // archive or merge
+	Reason   string  `json:"reason"`
+	Score    float64 `json:"score"`
+	KeepName string  `json:"keep_name,omitempty"` // merges: the pattern kept instead
+	KeepID   string  `json:"keep_id,omitempty"`
+}
+
+// TeamChanges returns the actions in r that --auto would apply locally
+// (archives with auto_archive, keep-best merges), for review in the team
+// repo instead. Merged patterns are not archived twice.
+func TeamChanges(cfg config.ConsolidationConfig, r *ConsolidationReport, names map[string]string) []TeamChange {
+	name := func(id string) string {
+		if n := names[id]; n != "" {
+			return n
+		}
+		return id
+	}
+
+	var changes []TeamChange
+	seen := make(map[string]bool)
+	if cfg.AutoMerge == "keep-best" {
+		for _, mp := range r.MergeProposals {
+			if mp.Strategy != StrategyKeepBest || mp.KeepID == "" {
+				continue
+			}
+			for _, id := range mp.RemoveIDs {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				changes = append(changes, TeamChange{
+					Name:     name(id),
+					ID:       id,
+					Action:   ActionMerge,
+					Reason:   fmt.Sprintf("%.0f%% similar to %s", mp.Similarity*100, name(mp.KeepID)),
+					KeepName: name(mp.KeepID),
+					KeepID:   mp.KeepID,
+				})
+			}
+		}
+	}
+	if cfg.AutoArchive {
+		for _, hs := range r.HealthScores {
+			if hs.Action != ActionArchive || seen[hs.PatternID] {
+				continue
+			}
+			seen[hs.PatternID] = true
+			changes = append(changes, TeamChange{
+				Name:   name(hs.PatternID),
+				ID:     hs.PatternID,
+				Action: ActionArchive,
+				Reason: hs.Reason,
+				Score:  hs.Overall,
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return changes[i].Action < changes[j].Action
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// ApplyTeamChanges makes changes to the pattern files in dir the way
+// --auto changes the local store, and returns those applied. Patterns
+// without a file in dir aren't team patterns and are skipped.
+func ApplyTeamChanges(dir string, changes []TeamChange, now time.Time) ([]TeamChange, error) {
+	var applied []TeamChange
+	related := make(map[string][]string) // keeper name -> merged IDs
+	for _, c := range changes {
+		p, path, err := readTeamPattern(dir, c.Name)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil || p.Lifecycle.Status == pattern.StatusArchived {
+			continue
+		}
+
+		p.Lifecycle.Status = pattern.StatusArchived
+		p.Lifecycle.Updated = now
+		p.Health.LastConsolidated = &now
+		switch c.Action {
+		case ActionMerge:
+			p.Lifecycle.DeprecationReason = "merged: duplicate of " + c.KeepID
+			p.Relations.Supersedes = "" // the kept pattern supersedes this one
+			p.Health.Score = 0
+			related[c.KeepName] = append(related[c.KeepName], c.ID)
+		default:
+			p.Lifecycle.DeprecationReason = "auto-archived: " + c.Reason
+			p.Health.Score = c.Score
+		}
+		if err := writeTeamPattern(path, p); err != nil {
+			return nil, err
+		}
+		applied = append(applied, c)
+	}
+
+	for keepName, ids := range related {
+		p, path, err := readTeamPattern(dir, keepName)
+		if err != nil || p == nil {
+			continue
+		}
+		p.Relations.Related = append(p.Relations.Related, ids...)
+		p.Health.LastConsolidated = &now
+		if err := writeTeamPattern(path, p); err != nil {
+			return nil, err
+		}
+	}
+	return applied, nil
+}
+
+func readTeamPattern(dir, name string) (*pattern.Pattern, string, error) {
+	path := filepath.Join(dir, name+".yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, path, nil
+		}
+		return nil, path, err
+	}
+	var p pattern.Pattern
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, path, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return &p, path, nil
+}
+
+func writeTeamPattern(path string, p *pattern.Pattern) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// FormatPRBody renders the applied changes as the consolidation PR's
+// description.
+func FormatPRBody(r *ConsolidationReport, applied []TeamChange) string {
+	var b strings.Builder
+	b.WriteString("## Pattern consolidation\n\n")
+	b.WriteString(fmt.Sprintf("Proposed by `mur consolidate --pr` on %s from %d active patterns. ",
+		r.Timestamp.Format("2006-01-02"), r.TotalPatterns))
+	b.WriteString("Nothing changes for the team until this PR is merged; ")
+	b.WriteString("close it to reject the whole run, or push to this branch to drop individual changes.\n\n")
+
+	var merges, archives []TeamChange
+	for _, c := range applied {
+		if c.Action == ActionMerge {
+			merges = append(merges, c)
+		} else {
+			archives = append(archives, c)
+		}
+	}
+
+	if len(merges) > 0 {
+		b.WriteString(fmt.Sprintf("### Merged duplicates (%d)\n\n", len(merges)))
+		b.WriteString("Archived in favor of a near-identical pattern, which now lists them as related.\n\n")
+		b.WriteString("| Archived | Kept | Why |\n|---|---|---|\n")
+		for _, c := range merges {
+			b.WriteString(fmt.Sprintf("| `%s` | `%s` | %s |\n", c.Name, c.KeepName, c.Reason))
+		}
+		b.WriteString("\n")
+	}
+	if len(archives) > 0 {
+		b.WriteString(fmt.Sprintf("### Archived (%d)\n\n", len(archives)))
+		b.WriteString("| Pattern | Health | Why |\n|---|---|---|\n")
+		for _, c := range archives {
+			b.WriteString(fmt.Sprintf("| `%s` | %.2f | %s |\n", c.Name, c.Score, c.Reason))
+		}
+		b.WriteString("\n")
+	}
+	if len(r.Conflicts) > 0 {
+		b.WriteString(fmt.Sprintf("### Conflicts to resolve by hand (%d)\n\n", len(r.Conflicts)))
+		for _, c := range r.Conflicts {
+			b.WriteString(fmt.Sprintf("- `%s` ↔ `%s` (%s): %s\n", c.PatternA.Name, c.PatternB.Name, c.Type, c.Reason))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Archived patterns stay in the repo with `lifecycle.status: archived` and the reason in ")
+	b.WriteString("`lifecycle.deprecation_reason`, so reverting a line restores a pattern.\n")
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package consolidate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestTeamChanges(t *testing.T) {
+	now := time.Now()
+	report := &ConsolidationReport{
+		Timestamp:     now,
+		TotalPatterns: 4,
+		HealthScores: []HealthScore{
+			{PatternID: "id-stale", Overall: 0.08, Action: ActionArchive, Reason: "stale and unused"},
+			{PatternID: "id-dup", Overall: 0.4, Action: ActionArchive, Reason: "low score"},
+			{PatternID: "id-keep", Overall: 0.9, Action: ActionKeep},
+		},
+		MergeProposals: []MergeProposal{
+			{Similarity: 0.91, Strategy: StrategyKeepBest, KeepID: "id-keep", RemoveIDs: []string{"id-dup"}},
+		},
+	}
+	names := map[string]string{"id-stale": "stale", "id-dup": "dup", "id-keep": "keep"}
+
+	changes := TeamChanges(defaultCfg(), report, names)
+	if len(changes) != 2 {
+		t.Fatalf("TeamChanges() = %+v, want the archive and the merge", changes)
+	}
+	if changes[0].Name != "stale" || changes[0].Action != ActionArchive {
+		t.Errorf("changes[0] = %+v, want archive of stale", changes[0])
+	}
+	if changes[1].Name != "dup" || changes[1].Action != ActionMerge || changes[1].KeepName != "keep" {
+		t.Errorf("changes[1] = %+v, want dup merged into keep", changes[1])
+	}
+
+	cfg := defaultCfg()
+	cfg.AutoArchive = false
+	cfg.AutoMerge = "off"
+	if got := TeamChanges(cfg, report, names); len(got) != 0 {
+		t.Errorf("with auto actions off, TeamChanges() = %+v", got)
+	}
+
+	// Only patterns with a file in the team repo are changed
+	dir := t.TempDir()
+	for _, p := range []*pattern.Pattern{
+		makePattern("id-dup", "dup", now, 1, nil),
+		makePattern("id-keep", "keep", now, 9, nil),
+	} {
+		data, _ := yaml.Marshal(p)
+		if err := os.WriteFile(filepath.Join(dir, p.Name+".yaml"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	applied, err := ApplyTeamChanges(dir, changes, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0].Name != "dup" {
+		t.Fatalf("ApplyTeamChanges() = %+v, want only dup", applied)
+	}
+
+	var dup, keep pattern.Pattern
+	data, _ := os.ReadFile(filepath.Join(dir, "dup.yaml"))
+	_ = yaml.Unmarshal(data, &dup)
+	data, _ = os.ReadFile(filepath.Join(dir, "keep.yaml"))
+	_ = yaml.Unmarshal(data, &keep)
+	if dup.Lifecycle.Status != pattern.StatusArchived || dup.Lifecycle.DeprecationReason != "merged: duplicate of id-keep" {
+		t.Errorf("dup lifecycle = %+v", dup.Lifecycle)
+	}
+	if len(keep.Relations.Related) != 1 || keep.Relations.Related[0] != "id-dup" {
+		t.Errorf("keep related = %v, want [id-dup]", keep.Relations.Related)
+	}
+
+	body := FormatPRBody(report, applied)
+	if !strings.Contains(body, "| `dup` | `keep` |") || strings.Contains(body, "stale") {
+		t.Errorf("PR body doesn't list just the applied merge:\n%s", body)
+	}
+}
@@ -4,6 +4,7 @@ package audit
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -60,7 +61,7 @@ func DefaultLogger() (*Logger, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return NewLogger(filepath.Join(home, ".mur", "audit")), nil
+	return NewLogger(filepath.Join(config.StateDir(home), "audit")), nil
 }
 
 // logFile returns the path to the current audit log file.
@@ -0,0 +1,154 @@
+package embed
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// BM25 parameters: term frequency saturation and length normalization.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// KeywordIndex is an in-memory inverted index over active patterns,
+// ranked with BM25. Unlike embeddings it matches exact identifiers such
+// as error codes and function names.
+type KeywordIndex struct {
+	patterns []pattern.Pattern
+	postings map[string][]posting // term -> documents containing it
+	lengths  []int
+	avgLen   float64
+}
+
+type posting struct {
+	doc  int
+	freq int
+}
+
+// NewKeywordIndex indexes the names, tags, keywords, descriptions, and
+// content of the active patterns. Names, tags, and keywords count more
+// than body text.
+func NewKeywordIndex(patterns []pattern.Pattern) *KeywordIndex {
+	idx := &KeywordIndex{postings: make(map[string][]posting)}
+	total := 0
+	for _, p := range patterns {
+		if !p.IsActive() {
+			continue
+		}
+		doc := len(idx.patterns)
+		idx.patterns = append(idx.patterns, p)
+
+		freqs := make(map[string]int)
+		n := 0
+		add := func(text string, weight int) {
+			for _, t := range keywordTerms(text) {
+				freqs[t] += weight
+				n += weight
+			}
+		}
+		add(p.Name, 3)
+		add(strings.Join(p.Tags.Confirmed, " "), 2)
+		add(strings.Join(p.Applies.Keywords, " "), 2)
+		add(p.Description, 1)
+		add(p.Content, 1)
+
+		for t, f := range freqs {
+			idx.postings[t] = append(idx.postings[t], posting{doc: doc, freq: f})
+		}
+		idx.lengths = append(idx.lengths, n)
+		total += n
+	}
+	if len(idx.patterns) > 0 {
+		idx.avgLen = float64(total) / float64(len(idx.patterns))
+	}
+	return idx
+}
+
+// Len returns the number of indexed patterns.
+func (idx *KeywordIndex) Len() int {
+	return len(idx.patterns)
+}
+
+// Search returns up to topK patterns matching query, best first. Scores
+// are BM25 scores divided by the best one, so the top match scores 1.
+func (idx *KeywordIndex) Search(query string, topK int) []PatternMatch {
+	scores := make(map[int]float64)
+	seen := make(map[string]bool)
+	n := float64(len(idx.patterns))
+	for _, t := range keywordTerms(query) {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		docs := idx.postings[t]
+		if len(docs) == 0 {
+			continue
+		}
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, d := range docs {
+			tf := float64(d.freq)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.lengths[d.doc])/idx.avgLen)
+			scores[d.doc] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	docs := make([]int, 0, len(scores))
+	for d := range scores {
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if scores[docs[i]] != scores[docs[j]] {
+			return scores[docs[i]] > scores[docs[j]]
+		}
+		return docs[i] < docs[j]
+	})
+	if len(docs) > topK {
+		docs = docs[:topK]
+	}
+
+	best := scores[docs[0]]
+	matches := make([]PatternMatch, 0, len(docs))
+	for _, d := range docs {
+		p := idx.patterns[d]
+		score := scores[d] / best
+		matches = append(matches, PatternMatch{Pattern: &p, Score: score, Confidence: score})
+	}
+	return matches
+}
+
+// keywordTerms splits text into lowercase search terms. Identifiers like
+// "os.ReadFile", "ERR_SSL_PROTOCOL", or "E0308" are kept whole, and their
+// dotted, dashed, or underscored parts are added as terms too, so both
+// "readfile" and "os.readfile" match.
+func keywordTerms(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !isIdentPunct(r)
+	}) {
+		word = strings.TrimFunc(word, isIdentPunct)
+		if word == "" {
+			continue
+		}
+		terms = append(terms, word)
+		if strings.IndexFunc(word, isIdentPunct) < 0 {
+			continue
+		}
+		for _, part := range strings.FieldsFunc(word, isIdentPunct) {
+			terms = append(terms, part)
+		}
+	}
+	return terms
+}
+
+func isIdentPunct(r rune) bool {
+	return r == '.' || r == '-' || r == '_' || r == ':'
+}
@@ -0,0 +1,70 @@
+package embed
+
+import (
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestKeywordIndex(t *testing.T) {
+	patterns := []pattern.Pattern{
+		{Name: "node-connection-refused", Content: "When you see ECONNREFUSED, check the service is listening."},
+		{Name: "go-file-io", Content: "Prefer os.ReadFile over ioutil.ReadFile."},
+		{Name: "retry-with-backoff", Description: "Retry failed network calls", Content: "Back off exponentially between retries."},
+		{Name: "old", Content: "ECONNREFUSED", Lifecycle: pattern.LifecycleMeta{Status: pattern.StatusArchived}},
+	}
+	idx := NewKeywordIndex(patterns)
+	if idx.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3 active patterns", idx.Len())
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"econnrefused", "node-connection-refused"},
+		{"why does os.ReadFile fail", "go-file-io"},
+		{"readfile", "go-file-io"},
+		{"network retry", "retry-with-backoff"},
+	}
+	for _, tt := range tests {
+		got := idx.Search(tt.query, 3)
+		if len(got) == 0 || got[0].Pattern.Name != tt.want {
+			t.Errorf("Search(%q) = %v, want %s first", tt.query, names(got), tt.want)
+			continue
+		}
+		if got[0].Score != 1 {
+			t.Errorf("Search(%q) top score = %v, want 1", tt.query, got[0].Score)
+		}
+	}
+	if got := idx.Search("kubernetes", 3); len(got) != 0 {
+		t.Errorf("Search(kubernetes) = %v, want none", names(got))
+	}
+}
+
+func TestFuseScores(t *testing.T) {
+	a := &pattern.Pattern{ID: "a", Name: "a"}
+	b := &pattern.Pattern{ID: "b", Name: "b"}
+	c := &pattern.Pattern{ID: "c", Name: "c"}
+	semantic := []PatternMatch{{Pattern: a, Score: 0.8}, {Pattern: b, Score: 0.6}, {Pattern: c, Score: 0.2}}
+	keyword := []PatternMatch{{Pattern: b, Score: 1}, {Pattern: c, Score: 0.5}}
+
+	got := FuseScores(semantic, keyword, 0.5, 0.5, 5)
+	// b: 0.5*1 + 0.5*0.6 = 0.8, a: 0.4, c: 0.25+0.1 = 0.35
+	if n := names(got); len(n) != 3 || n[0] != "b" || n[1] != "a" || n[2] != "c" {
+		t.Errorf("FuseScores() = %v, want [b a c]", n)
+	}
+
+	// Below min_score, semantic-only matches are dropped
+	if got := FuseScores(semantic, keyword, 0.5, 0.9, 5); len(got) != 2 {
+		t.Errorf("FuseScores(minScore 0.9) = %v, want only keyword matches", names(got))
+	}
+}
+
+func names(matches []PatternMatch) []string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, m.Pattern.Name)
+	}
+	return out
+}
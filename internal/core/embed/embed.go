@@ -75,6 +75,7 @@ func NewEmbedder(cfg Config) (Embedder, error) {
 			return nil, fmt.Errorf("Voyage API key required: set VOYAGE_API_KEY env var")
 		}
 		e := NewOpenAIEmbedder(apiKey, cfg.Model)
+		e.provider = "voyage"
 		e.baseURL = "https://api.voyageai.com/v1"
 		return e, nil
 
@@ -87,6 +88,7 @@ func NewEmbedder(cfg Config) (Embedder, error) {
 			return nil, fmt.Errorf("Google API key required: set GEMINI_API_KEY env var")
 		}
 		e := NewOpenAIEmbedder(apiKey, cfg.Model)
+		e.provider = "google"
 		e.baseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
 		return e, nil
 
@@ -108,10 +110,11 @@ func NewEmbedder(cfg Config) (Embedder, error) {
 
 // OpenAIEmbedder uses OpenAI's embedding API.
 type OpenAIEmbedder struct {
-	apiKey  string
-	model   string
-	baseURL string
-	client  *http.Client
+	apiKey   string
+	model    string
+	provider string // set for OpenAI-compatible providers
+	baseURL  string
+	client   *http.Client
 }
 
 // NewOpenAIEmbedder creates an OpenAI embedder.
@@ -127,7 +130,15 @@ func NewOpenAIEmbedder(apiKey, model string) *OpenAIEmbedder {
 	}
 }
 
-func (e *OpenAIEmbedder) Name() string { return "openai" }
+func (e *OpenAIEmbedder) Name() string {
+	if e.provider != "" {
+		return e.provider
+	}
+	return "openai"
+}
+
+// Model returns the embedding model name.
+func (e *OpenAIEmbedder) Model() string { return e.model }
 
 func (e *OpenAIEmbedder) Dimension() int {
 	switch e.model {
@@ -225,6 +236,9 @@ func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
 
 func (e *OllamaEmbedder) Name() string { return "ollama" }
 
+// Model returns the embedding model name.
+func (e *OllamaEmbedder) Model() string { return e.model }
+
 // knownDimension returns dimension for well-known models, or 0 if unknown.
 func (e *OllamaEmbedder) knownDimension() int {
 	switch {
@@ -320,6 +334,15 @@ func (e *OllamaEmbedder) EmbedBatch(texts []string) ([]Vector, error) {
 	return vectors, nil
 }
 
+// ModelID identifies the model an embedder produces vectors with, as
+// "provider/model". Vectors from different models can't be compared.
+func ModelID(e Embedder) string {
+	if m, ok := e.(interface{ Model() string }); ok && m.Model() != "" {
+		return e.Name() + "/" + m.Model()
+	}
+	return e.Name()
+}
+
 // ============================================================
 // Vector Operations
 // ============================================================
@@ -354,6 +377,7 @@ type Cache struct {
 	embedder Embedder
 	mu       sync.RWMutex
 	cache    map[string]Vector
+	models   map[string]string // model ID each vector was embedded with
 }
 
 // CacheEntry represents a cached embedding.
@@ -371,6 +395,7 @@ func NewCache(dir string, embedder Embedder) *Cache {
 		dir:      dir,
 		embedder: embedder,
 		cache:    make(map[string]Vector),
+		models:   make(map[string]string),
 	}
 }
 
@@ -400,6 +425,7 @@ func (c *Cache) Load() error {
 
 	for _, e := range entries {
 		c.cache[e.ID] = e.Vector
+		c.models[e.ID] = e.Model
 	}
 
 	return nil
@@ -419,7 +445,7 @@ func (c *Cache) Save() error {
 		entries = append(entries, CacheEntry{
 			ID:        id,
 			Vector:    vec,
-			Model:     c.embedder.Name(),
+			Model:     c.models[id],
 			UpdatedAt: time.Now(),
 		})
 	}
@@ -440,11 +466,37 @@ func (c *Cache) Get(id string) (Vector, bool) {
 	return v, ok
 }
 
+// Len returns the number of cached embeddings.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.cache)
+}
+
 // Set stores an embedding in the cache.
 func (c *Cache) Set(id string, vec Vector) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.cache[id] = vec
+	c.models[id] = ModelID(c.embedder)
+}
+
+// Rekey moves every embedding whose ID starts with from to the same ID
+// starting with to, and returns how many moved.
+func (c *Cache) Rekey(from, to string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	moved := 0
+	for id, vec := range c.cache {
+		if rest, ok := strings.CutPrefix(id, from); ok {
+			delete(c.cache, id)
+			c.cache[to+rest] = vec
+			c.models[to+rest] = c.models[id]
+			delete(c.models, id)
+			moved++
+		}
+	}
+	return moved
 }
 
 // GetOrEmbed gets from cache or embeds the text.
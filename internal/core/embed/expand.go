@@ -2,6 +2,8 @@ package embed
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,45 +16,230 @@ import (
 	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
-// ExpandedQueries stores LLM-generated search queries for patterns.
+// expansionsVersion is the current expanded_queries.json format. Version 1
+// keyed queries by pattern name only, so an edited pattern kept stale
+// queries and another machine's cache couldn't be trusted.
+const expansionsVersion = 2
+
+// ExpansionMode controls when RebuildWithExpansion calls the LLM.
+type ExpansionMode int
+
+const (
+	// ExpandMissing generates queries for every pattern without an
+	// expansion for its current hash and the model in use.
+	ExpandMissing ExpansionMode = iota
+	// ExpandOnlyNew generates queries only for patterns that have never
+	// been expanded; edited patterns reuse their latest expansion.
+	ExpandOnlyNew
+	// ExpandCachedOnly never calls the LLM and uses whatever expansions
+	// are cached, e.g. ones imported from another machine.
+	ExpandCachedOnly
+)
+
+// Expansion is the generated search queries for one version of a pattern.
+type Expansion struct {
+	Pattern string    `json:"pattern"`
+	Hash    string    `json:"hash"`
+	Model   string    `json:"model"`
+	Queries []string  `json:"queries"`
+	Created time.Time `json:"created"`
+}
+
+// ExpandedQueries stores LLM-generated search queries for patterns, keyed
+// by pattern hash and model so the cache can be shared between machines.
 // This is a sidecar file alongside the embedding cache.
 type ExpandedQueries struct {
-	Queries map[string][]string `json:"queries"` // pattern name → generated queries
+	Version    int                   `json:"version"`
+	Expansions map[string]*Expansion `json:"expansions"` // hash:model → expansion
+	// Queries holds version 1 entries (pattern name → queries) until
+	// they're adopted for a pattern's current hash.
+	Queries map[string][]string `json:"queries,omitempty"`
 	path    string
 }
 
+// ExpansionHash returns the hash of everything the expansion prompt sees,
+// so queries are regenerated when any of it changes.
+func ExpansionHash(p pattern.Pattern) string {
+	h := sha256.Sum256([]byte(expansionSummary(p)))
+	return hex.EncodeToString(h[:8])
+}
+
+func expansionKey(hash, model string) string {
+	return hash + ":" + model
+}
+
 // LoadExpandedQueries loads or creates the expanded queries file.
 func LoadExpandedQueries(cacheDir string) *ExpandedQueries {
 	path := filepath.Join(cacheDir, "expanded_queries.json")
-	eq := &ExpandedQueries{
-		Queries: make(map[string][]string),
-		path:    path,
+	eq, err := ReadExpandedQueries(path)
+	if err != nil {
+		eq = &ExpandedQueries{Version: expansionsVersion, Expansions: make(map[string]*Expansion)}
 	}
+	eq.path = path
+	return eq
+}
 
+// ReadExpandedQueries reads an expansions file, such as one exported from
+// another machine.
+func ReadExpandedQueries(path string) (*ExpandedQueries, error) {
 	data, err := os.ReadFile(path)
-	if err == nil {
-		_ = json.Unmarshal(data, eq)
+	if err != nil {
+		return nil, err
 	}
-	return eq
+	return ParseExpandedQueries(data)
+}
+
+// ParseExpandedQueries parses the contents of an expansions file.
+func ParseExpandedQueries(data []byte) (*ExpandedQueries, error) {
+	eq := &ExpandedQueries{}
+	if err := json.Unmarshal(data, eq); err != nil {
+		return nil, fmt.Errorf("invalid expansions file: %w", err)
+	}
+	if eq.Expansions == nil {
+		eq.Expansions = make(map[string]*Expansion)
+	}
+	for key, e := range eq.Expansions {
+		if e == nil || e.Hash == "" || len(e.Queries) == 0 {
+			delete(eq.Expansions, key)
+		}
+	}
+	eq.Version = expansionsVersion
+	return eq, nil
 }
 
 // Save persists the expanded queries to disk.
 func (eq *ExpandedQueries) Save() error {
+	if err := os.MkdirAll(filepath.Dir(eq.path), 0755); err != nil {
+		return err
+	}
+	return eq.WriteFile(eq.path)
+}
+
+// WriteFile writes the expansions to path.
+func (eq *ExpandedQueries) WriteFile(path string) error {
 	data, err := json.MarshalIndent(eq, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(eq.path, data, 0644)
+	return os.WriteFile(path, data, 0644)
+}
+
+// Len returns the number of cached expansions.
+func (eq *ExpandedQueries) Len() int {
+	return len(eq.Expansions)
+}
+
+// Get returns the expanded queries for the pattern as it is now, or nil if
+// none are cached. The given model's expansion is preferred, then any
+// other model's for the same hash.
+func (eq *ExpandedQueries) Get(p pattern.Pattern, model string) []string {
+	hash := ExpansionHash(p)
+	if e, ok := eq.Expansions[expansionKey(hash, model)]; ok {
+		return e.Queries
+	}
+	var best *Expansion
+	for _, e := range eq.Expansions {
+		if e.Hash == hash && (best == nil || e.Created.After(best.Created)) {
+			best = e
+		}
+	}
+	if best != nil {
+		return best.Queries
+	}
+	return nil
 }
 
-// Get returns expanded queries for a pattern, or nil if not yet generated.
-func (eq *ExpandedQueries) Get(name string) []string {
+// Latest returns the most recent expansion of any version of the pattern,
+// matched by name, or nil if it was never expanded.
+func (eq *ExpandedQueries) Latest(name string) []string {
+	var best *Expansion
+	for _, e := range eq.Expansions {
+		if e.Pattern == name && (best == nil || e.Created.After(best.Created)) {
+			best = e
+		}
+	}
+	if best != nil {
+		return best.Queries
+	}
 	return eq.Queries[name]
 }
 
-// GenerateForPattern uses a local LLM to generate likely search queries.
-func (eq *ExpandedQueries) GenerateForPattern(p pattern.Pattern, ollamaURL, model string) error {
-	// Build a concise summary for the LLM
+// Has reports whether the pattern has an expansion for its current hash
+// from model.
+func (eq *ExpandedQueries) Has(p pattern.Pattern, model string) bool {
+	_, ok := eq.Expansions[expansionKey(ExpansionHash(p), model)]
+	return ok
+}
+
+// Set stores queries for the pattern as it is now.
+func (eq *ExpandedQueries) Set(p pattern.Pattern, model string, queries []string) {
+	hash := ExpansionHash(p)
+	eq.Expansions[expansionKey(hash, model)] = &Expansion{
+		Pattern: p.Name,
+		Hash:    hash,
+		Model:   model,
+		Queries: queries,
+		Created: time.Now(),
+	}
+}
+
+// adoptLegacy moves a version 1 entry for the pattern to its current hash,
+// so upgrading doesn't pay for every expansion again.
+func (eq *ExpandedQueries) adoptLegacy(p pattern.Pattern, model string) {
+	queries, ok := eq.Queries[p.Name]
+	if !ok {
+		return
+	}
+	delete(eq.Queries, p.Name)
+	if len(queries) > 0 && eq.Get(p, model) == nil {
+		eq.Set(p, model, queries)
+	}
+}
+
+// Merge adds the expansions in other that aren't cached yet, or that are
+// newer than the cached one for the same hash and model, and returns how
+// many it added.
+func (eq *ExpandedQueries) Merge(other *ExpandedQueries) int {
+	added := 0
+	for key, e := range other.Expansions {
+		if cur, ok := eq.Expansions[key]; ok && !e.Created.After(cur.Created) {
+			continue
+		}
+		eq.Expansions[key] = e
+		added++
+	}
+	return added
+}
+
+// Rename moves the expansions of old to renamed, the same pattern under a
+// new name, and returns how many it moved. Queries generated for the
+// current version are kept for it, since a new name rarely changes what
+// people search for.
+func (eq *ExpandedQueries) Rename(old, renamed pattern.Pattern) int {
+	oldHash, newHash := ExpansionHash(old), ExpansionHash(renamed)
+	moved := 0
+	for key, e := range eq.Expansions {
+		if e.Pattern != old.Name {
+			continue
+		}
+		e.Pattern = renamed.Name
+		if e.Hash == oldHash {
+			delete(eq.Expansions, key)
+			e.Hash = newHash
+			eq.Expansions[expansionKey(newHash, e.Model)] = e
+		}
+		moved++
+	}
+	if queries, ok := eq.Queries[old.Name]; ok {
+		delete(eq.Queries, old.Name)
+		eq.Queries[renamed.Name] = queries
+		moved++
+	}
+	return moved
+}
+
+// expansionSummary is the pattern text the LLM expands.
+func expansionSummary(p pattern.Pattern) string {
 	summary := fmt.Sprintf("Name: %s\nDescription: %s\nTags: %s",
 		p.Name, p.Description, strings.Join(p.Tags.Confirmed, ", "))
 
@@ -63,6 +250,13 @@ func (eq *ExpandedQueries) GenerateForPattern(p pattern.Pattern, ollamaURL, mode
 	if content != "" {
 		summary += "\nContent: " + content
 	}
+	return summary
+}
+
+// GenerateForPattern uses a local LLM to generate likely search queries.
+func (eq *ExpandedQueries) GenerateForPattern(p pattern.Pattern, ollamaURL, model string) error {
+	// Build a concise summary for the LLM
+	summary := expansionSummary(p)
 
 	prompt := fmt.Sprintf(`Generate 5 search queries for this pattern. One per line, no numbering, no explanation.
 
@@ -123,6 +317,6 @@ Queries:`, summary)
 		queries = queries[:7]
 	}
 
-	eq.Queries[p.Name] = queries
+	eq.Set(p, model, queries)
 	return nil
 }
@@ -0,0 +1,133 @@
+package embed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestExpandedQueriesKeyedByHashAndModel(t *testing.T) {
+	dir := t.TempDir()
+	p := pattern.Pattern{Name: "retry-backoff", Content: "Use exponential backoff."}
+
+	eq := LoadExpandedQueries(dir)
+	eq.Set(p, "llama3.2:3b", []string{"how to retry requests"})
+	if err := eq.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	eq = LoadExpandedQueries(dir)
+	if !eq.Has(p, "llama3.2:3b") || eq.Has(p, "qwen2.5:3b") {
+		t.Error("expansion not keyed by model")
+	}
+	if got := eq.Get(p, "qwen2.5:3b"); len(got) != 1 {
+		t.Errorf("Get with another model = %v, want the cached expansion", got)
+	}
+
+	edited := p
+	edited.Content = "Use exponential backoff with jitter."
+	if eq.Get(edited, "llama3.2:3b") != nil {
+		t.Error("edited pattern reused the old expansion")
+	}
+	if got := eq.Latest(edited.Name); len(got) != 1 {
+		t.Errorf("Latest = %v, want the old expansion", got)
+	}
+}
+
+func TestExpansionModes(t *testing.T) {
+	eq := LoadExpandedQueries(t.TempDir())
+	old := pattern.Pattern{Name: "edited", Content: "v1"}
+	eq.Set(old, "m", []string{"old query"})
+	edited := pattern.Pattern{Name: "edited", Content: "v2"}
+	fresh := pattern.Pattern{Name: "fresh", Content: "new"}
+
+	tests := []struct {
+		mode        ExpansionMode
+		p           pattern.Pattern
+		wantExpand  bool
+		wantQueries bool
+	}{
+		{ExpandMissing, edited, true, false},
+		{ExpandMissing, fresh, true, false},
+		{ExpandOnlyNew, edited, false, true},
+		{ExpandOnlyNew, fresh, true, false},
+		{ExpandCachedOnly, edited, false, true},
+		{ExpandCachedOnly, fresh, false, false},
+	}
+	for _, tt := range tests {
+		if got := needsExpansion(eq, tt.p, "m", tt.mode); got != tt.wantExpand {
+			t.Errorf("mode %d, %s: needsExpansion = %v", tt.mode, tt.p.Name, got)
+		}
+		if got := expansionFor(eq, tt.p, "m", tt.mode) != nil; got != tt.wantQueries {
+			t.Errorf("mode %d, %s: has queries = %v", tt.mode, tt.p.Name, got)
+		}
+	}
+}
+
+func TestExpandedQueriesMerge(t *testing.T) {
+	p := pattern.Pattern{Name: "a", Content: "a"}
+	q := pattern.Pattern{Name: "b", Content: "b"}
+
+	local := LoadExpandedQueries(t.TempDir())
+	local.Set(p, "m", []string{"local"})
+
+	remote := LoadExpandedQueries(t.TempDir())
+	remote.Set(q, "m", []string{"remote"})
+	remote.Set(p, "m", []string{"older"})
+	remote.Expansions[expansionKey(ExpansionHash(p), "m")].Created = time.Now().Add(-time.Hour)
+
+	if added := local.Merge(remote); added != 1 {
+		t.Errorf("Merge added %d, want 1", added)
+	}
+	if got := local.Get(p, "m"); got[0] != "local" {
+		t.Errorf("older import replaced newer expansion: %v", got)
+	}
+	if local.Get(q, "m") == nil {
+		t.Error("new expansion not imported")
+	}
+}
+
+func TestExpandedQueriesAdoptsLegacy(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"queries": {"a": ["legacy query"]}}`
+	if err := os.WriteFile(filepath.Join(dir, "expanded_queries.json"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	eq := LoadExpandedQueries(dir)
+	p := pattern.Pattern{Name: "a", Content: "a"}
+	if needsExpansion(eq, p, "m", ExpandOnlyNew) {
+		t.Error("legacy expansion not counted as expanded")
+	}
+	eq.adoptLegacy(p, "m")
+	if !eq.Has(p, "m") || len(eq.Queries) != 0 {
+		t.Errorf("legacy entry not adopted: %+v", eq)
+	}
+}
+
+func TestExpandedQueriesRename(t *testing.T) {
+	old := pattern.Pattern{Name: "debugging-solution-3f2a", Content: "Raise the open file limit."}
+	renamed := old
+	renamed.Name = "launchd-open-file-limit"
+
+	eq := LoadExpandedQueries(t.TempDir())
+	eq.Set(old, "llama3.2:3b", []string{"too many open files"})
+	other := pattern.Pattern{Name: "other", Content: "Something else."}
+	eq.Set(other, "llama3.2:3b", []string{"something"})
+
+	if n := eq.Rename(old, renamed); n != 1 {
+		t.Errorf("Rename moved %d expansions, want 1", n)
+	}
+	if got := eq.Get(renamed, "llama3.2:3b"); len(got) != 1 {
+		t.Errorf("Get after rename = %v, want the moved expansion", got)
+	}
+	if eq.Latest(old.Name) != nil {
+		t.Error("expansion still filed under the old name")
+	}
+	if !eq.Has(other, "llama3.2:3b") {
+		t.Error("rename touched another pattern's expansion")
+	}
+}
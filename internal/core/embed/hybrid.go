@@ -0,0 +1,82 @@
+package embed
+
+import (
+	"sort"
+
+	"github.com/mur-run/mur-core/internal/timing"
+)
+
+// HybridSearch ranks patterns by a weighted sum of their keyword (BM25)
+// and semantic scores, weighted by search.hybrid.keyword_weight. Patterns
+// only one side finds are included, so an exact error code matches even
+// if its embedding is far from the query.
+//
+// Without embeddings, or if embedding the query fails, the keyword
+// matches are returned alone, along with the semantic search error.
+func (idx *PatternIndexer) HybridSearch(query string, topK int) ([]PatternMatch, error) {
+	defer timing.Track("embed.HybridSearch")()
+
+	patterns, err := idx.store.List()
+	if err != nil {
+		return nil, err
+	}
+	keyword := NewKeywordIndex(patterns).Search(query, topK*3)
+
+	var semErr error
+	var semantic []PatternMatch
+	if idx.HasEmbeddings() {
+		semantic, semErr = idx.semanticCandidates(query, topK*3)
+	}
+	if semantic == nil {
+		return FuseScores(nil, keyword, 1, 0, topK), semErr
+	}
+	return FuseScores(semantic, keyword, idx.cfg.Search.HybridKeywordWeight(), idx.cfg.Search.MinScore, topK), nil
+}
+
+// FuseScores combines semantic and keyword matches into up to topK
+// matches scored keywordWeight*keyword + (1-keywordWeight)*semantic.
+// Semantic-only matches need minScore similarity to be included.
+func FuseScores(semantic, keyword []PatternMatch, keywordWeight, minScore float64, topK int) []PatternMatch {
+	type fused struct {
+		match             PatternMatch
+		semantic, keyword float64
+	}
+	byKey := make(map[string]*fused)
+	var order []string
+	entry := func(m PatternMatch) *fused {
+		key := m.Pattern.ID
+		if key == "" {
+			key = m.Pattern.Name
+		}
+		f, ok := byKey[key]
+		if !ok {
+			f = &fused{match: m}
+			byKey[key] = f
+			order = append(order, key)
+		}
+		return f
+	}
+	for _, m := range semantic {
+		entry(m).semantic = m.Score
+	}
+	for _, m := range keyword {
+		entry(m).keyword = m.Score
+	}
+
+	matches := make([]PatternMatch, 0, len(order))
+	for _, key := range order {
+		f := byKey[key]
+		if f.keyword == 0 && f.semantic < minScore {
+			continue
+		}
+		score := keywordWeight*f.keyword + (1-keywordWeight)*f.semantic
+		m := f.match
+		m.Score, m.Confidence = score, score
+		matches = append(matches, m)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,6 +30,8 @@ type IndexStatus struct {
 	EmbeddingModel string
 	OllamaRunning  bool
 	ModelAvailable bool
+	IndexModels    []IndexModel
+	Mismatch       error // *IndexMismatchError if search can't use the index
 }
 
 // NewPatternIndexer creates a new pattern indexer.
@@ -40,45 +41,56 @@ func NewPatternIndexer(cfg *config.Config) (*PatternIndexer, error) {
 		return nil, fmt.Errorf("cannot access pattern store: %w", err)
 	}
 
-	// Expand cache dir
-	cacheDir := cfg.Embeddings.CacheDir
-	if strings.HasPrefix(cacheDir, "~") {
-		home, _ := os.UserHomeDir()
-		cacheDir = filepath.Join(home, cacheDir[2:])
+	// Expand cache dir; the default follows the resolved cache directory
+	cacheDir := indexCacheDir(cfg)
+
+	embedder, err := NewSearchEmbedder(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create embedder based on config
+	cache := NewCache(cacheDir, embedder)
+	_ = cache.Load() // Ignore load errors, start with empty cache
+
+	return &PatternIndexer{
+		cfg:      cfg,
+		embedder: embedder,
+		cache:    cache,
+		store:    store,
+	}, nil
+}
+
+// SearchConfig returns the embedding config set under search, so every
+// caller embeds with the model the index was built with.
+func SearchConfig(cfg *config.Config) Config {
 	apiKey := ""
 	if cfg.Search.APIKeyEnv != "" {
 		apiKey = os.Getenv(cfg.Search.APIKeyEnv)
 	}
-	embedCfg := Config{
+	return Config{
 		Provider:  cfg.Search.Provider,
 		Model:     cfg.Search.Model,
 		Endpoint:  cfg.Search.OllamaURL,
 		APIKey:    apiKey,
 		OpenAIURL: cfg.Search.OpenAIURL,
 	}
-	embedder, err := NewEmbedder(embedCfg)
+}
+
+// NewSearchEmbedder creates the embedder configured under search.
+func NewSearchEmbedder(cfg *config.Config) (Embedder, error) {
+	embedder, err := NewEmbedder(SearchConfig(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("cannot create embedder: %w", err)
 	}
-
-	cache := NewCache(cacheDir, embedder)
-	_ = cache.Load() // Ignore load errors, start with empty cache
-
-	return &PatternIndexer{
-		cfg:      cfg,
-		embedder: embedder,
-		cache:    cache,
-		store:    store,
-	}, nil
+	return embedder, nil
 }
 
 // Status returns the current index status.
 func (idx *PatternIndexer) Status() IndexStatus {
 	status := IndexStatus{
 		EmbeddingModel: idx.cfg.Search.Model,
+		IndexModels:    idx.cache.Models(),
+		Mismatch:       idx.cache.Check(0),
 	}
 
 	// Count patterns
@@ -89,8 +101,7 @@ func (idx *PatternIndexer) Status() IndexStatus {
 
 	// Count indexed (in cache)
 	for _, p := range patterns {
-		cacheKey := idx.cacheKey(p)
-		if _, ok := idx.cache.Get(cacheKey); ok {
+		if idx.indexed(p) {
 			status.IndexedCount++
 		}
 	}
@@ -113,22 +124,80 @@ func (idx *PatternIndexer) Status() IndexStatus {
 	return status
 }
 
-// cacheKey returns the cache key for a pattern.
+// HasEmbeddings reports whether any patterns have been indexed. Unlike
+// Status it does no pattern or network IO, so hooks can call it cheaply.
+func (idx *PatternIndexer) HasEmbeddings() bool {
+	return idx.cache.Len() > 0
+}
+
+// cacheKey returns the cache key for a pattern: its ID (its name, if it
+// has none) and the hash of what was embedded.
 func (idx *PatternIndexer) cacheKey(p pattern.Pattern) string {
-	// Use embedding hash if available, otherwise use name
-	if p.EmbeddingHash != "" {
-		return p.Name + ":" + p.EmbeddingHash
+	hash := p.EmbeddingHash
+	if hash == "" {
+		hash = p.CalculateEmbeddingHash()
+	}
+	return keyRef(p) + ":" + hash
+}
+
+// indexed reports whether p's current text is in the index, under its ID
+// or, not yet adopted, its name.
+func (idx *PatternIndexer) indexed(p pattern.Pattern) bool {
+	key := idx.cacheKey(p)
+	if _, ok := idx.cache.Get(key); ok {
+		return true
+	}
+	_, ok := idx.cache.Get(p.Name + strings.TrimPrefix(key, keyRef(p)))
+	return ok
+}
+
+// Vector returns the embedding of p's current text, if it is indexed.
+func (idx *PatternIndexer) Vector(p pattern.Pattern) (Vector, bool) {
+	key := idx.cacheKey(p)
+	if v, ok := idx.cache.Get(key); ok {
+		return v, true
+	}
+	return idx.cache.Get(p.Name + strings.TrimPrefix(key, keyRef(p)))
+}
+
+// keyRef is the pattern reference cache keys start with.
+func keyRef(p pattern.Pattern) string {
+	if p.ID != "" {
+		return p.ID
 	}
-	return p.Name + ":" + p.CalculateEmbeddingHash()
+	return p.Name
+}
+
+// refFromKey returns the pattern name or ID a cache key starts with.
+func refFromKey(key string) string {
+	ref, _, _ := strings.Cut(key, ":")
+	return ref
+}
+
+// adoptNameKeys moves embeddings cached under pattern names, as indexes
+// before pattern IDs were, to the patterns' IDs, so upgrading doesn't
+// re-embed anything. It returns how many moved.
+func (idx *PatternIndexer) adoptNameKeys(patterns []pattern.Pattern) int {
+	moved := 0
+	for _, p := range patterns {
+		if p.ID != "" && p.ID != p.Name {
+			moved += idx.cache.Rekey(p.Name+":", p.ID+":")
+		}
+	}
+	return moved
 }
 
 // IndexPattern indexes a single pattern.
 func (idx *PatternIndexer) IndexPattern(p pattern.Pattern) error {
+	if err := idx.cache.Check(0); err != nil {
+		return err
+	}
+	idx.adoptNameKeys([]pattern.Pattern{p})
 	return idx.indexPatternWithExpansion(p, nil)
 }
 
-// IndexPatternExpanded indexes a pattern with optional expanded queries.
-func (idx *PatternIndexer) indexPatternWithExpansion(p pattern.Pattern, eq *ExpandedQueries) error {
+// indexPatternWithExpansion indexes a pattern with optional expanded queries.
+func (idx *PatternIndexer) indexPatternWithExpansion(p pattern.Pattern, queries []string) error {
 	cacheKey := idx.cacheKey(p)
 
 	// Skip if already cached with same hash
@@ -140,10 +209,8 @@ func (idx *PatternIndexer) indexPatternWithExpansion(p pattern.Pattern, eq *Expa
 	text := strings.ToLower(buildIndexText(p))
 
 	// Append expanded queries if available
-	if eq != nil {
-		if queries := eq.Get(p.Name); len(queries) > 0 {
-			text += " | search queries: " + strings.Join(queries, " | ")
-		}
+	if len(queries) > 0 {
+		text += " | search queries: " + strings.Join(queries, " | ")
 	}
 
 	// Embed
@@ -163,6 +230,11 @@ func (idx *PatternIndexer) IndexAll(progress func(current, total int)) error {
 	if err != nil {
 		return fmt.Errorf("cannot list patterns: %w", err)
 	}
+	// Adding vectors from another model would mix the index
+	if err := idx.cache.Check(0); err != nil {
+		return err
+	}
+	idx.adoptNameKeys(patterns)
 
 	for i, p := range patterns {
 		if progress != nil {
@@ -188,21 +260,28 @@ func (idx *PatternIndexer) Rebuild(progress func(current, total int)) error {
 }
 
 // RebuildWithExpansion rebuilds the index with LLM-generated query expansion.
-func (idx *PatternIndexer) RebuildWithExpansion(ollamaURL, llmModel string, progress func(current, total int, phase string)) error {
+// mode decides which patterns are sent to the LLM; the rest use cached
+// expansions, if any.
+func (idx *PatternIndexer) RebuildWithExpansion(ollamaURL, llmModel string, mode ExpansionMode, progress func(current, total int, phase string)) error {
 	patterns, err := idx.store.List()
 	if err != nil {
 		return fmt.Errorf("cannot list patterns: %w", err)
 	}
 
 	// Phase 1: Generate expanded queries
-	eq := LoadExpandedQueries(idx.cache.dir)
+	eq := idx.Expansions()
+	for _, p := range patterns {
+		eq.adoptLegacy(p, llmModel)
+	}
+	eq.Queries = nil
+
 	generated := 0
 	for i, p := range patterns {
-		if progress != nil {
+		if progress != nil && mode != ExpandCachedOnly {
 			progress(i+1, len(patterns), "expanding")
 		}
-		if eq.Get(p.Name) != nil {
-			continue // Already expanded
+		if !needsExpansion(eq, p, llmModel, mode) {
+			continue
 		}
 		if err := eq.GenerateForPattern(p, ollamaURL, llmModel); err != nil {
 			// Non-fatal: skip this pattern
@@ -222,7 +301,7 @@ func (idx *PatternIndexer) RebuildWithExpansion(ollamaURL, llmModel string, prog
 		if progress != nil {
 			progress(i+1, len(patterns), "embedding")
 		}
-		if err := idx.indexPatternWithExpansion(p, eq); err != nil {
+		if err := idx.indexPatternWithExpansion(p, expansionFor(eq, p, llmModel, mode)); err != nil {
 			return err
 		}
 	}
@@ -230,43 +309,100 @@ func (idx *PatternIndexer) RebuildWithExpansion(ollamaURL, llmModel string, prog
 	return idx.cache.Save()
 }
 
+// RenamePattern moves the cached embedding and expansions of old to
+// renamed, so a renamed pattern stays searchable without re-embedding.
+// Embeddings keyed by pattern ID don't move.
+func (idx *PatternIndexer) RenamePattern(old, renamed pattern.Pattern) error {
+	if keyRef(old) != keyRef(renamed) && idx.cache.Rekey(keyRef(old)+":", keyRef(renamed)+":") > 0 {
+		if err := idx.cache.Save(); err != nil {
+			return err
+		}
+	}
+	eq := idx.Expansions()
+	if eq.Rename(old, renamed) > 0 {
+		return eq.Save()
+	}
+	return nil
+}
+
+// Expansions loads the expanded queries cached next to the index.
+func (idx *PatternIndexer) Expansions() *ExpandedQueries {
+	return LoadExpandedQueries(idx.cache.dir)
+}
+
+func needsExpansion(eq *ExpandedQueries, p pattern.Pattern, model string, mode ExpansionMode) bool {
+	switch mode {
+	case ExpandCachedOnly:
+		return false
+	case ExpandOnlyNew:
+		return eq.Get(p, model) == nil && eq.Latest(p.Name) == nil
+	default:
+		return !eq.Has(p, model)
+	}
+}
+
+// expansionFor returns the queries to embed with the pattern.
+func expansionFor(eq *ExpandedQueries, p pattern.Pattern, model string, mode ExpansionMode) []string {
+	if queries := eq.Get(p, model); queries != nil {
+		return queries
+	}
+	if mode == ExpandMissing {
+		return nil
+	}
+	// Stale queries from an earlier version beat none at all when the
+	// LLM isn't being asked for fresh ones
+	return eq.Latest(p.Name)
+}
+
 // Search searches for patterns similar to the query.
 func (idx *PatternIndexer) Search(query string, topK int) ([]PatternMatch, error) {
+	// Get more results to filter
+	candidates, err := idx.semanticCandidates(query, topK*3)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]PatternMatch, 0, topK)
+	for _, m := range candidates {
+		if m.Score >= idx.cfg.Search.MinScore {
+			matches = append(matches, m)
+		}
+
+		// Stop once we have enough matches
+		if len(matches) >= topK {
+			break
+		}
+	}
+
+	return matches, nil
+}
+
+// semanticCandidates returns up to n patterns by similarity to the query,
+// without applying search.min_score.
+func (idx *PatternIndexer) semanticCandidates(query string, n int) ([]PatternMatch, error) {
 	// Embed query
 	queryVec, err := idx.embedder.Embed(PrepareQuery(query, idx.embedder))
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
+	if err := idx.cache.Check(len(queryVec)); err != nil {
+		return nil, err
+	}
 
-	// Search cache - get more results to filter
-	results := idx.cache.Search(queryVec, topK*3)
+	results := idx.cache.Search(queryVec, n)
 
 	// Load patterns
 	matches := make([]PatternMatch, 0, len(results))
 	for _, r := range results {
-		// Extract pattern name from cache key
-		name := r.ID
-		if i := strings.Index(name, ":"); i > 0 {
-			name = name[:i]
-		}
-
-		p, err := idx.store.Get(name)
+		p, err := idx.store.Resolve(refFromKey(r.ID))
 		if err != nil {
 			continue
 		}
-
-		if r.Score >= idx.cfg.Search.MinScore {
-			matches = append(matches, PatternMatch{
-				Pattern:    p,
-				Score:      r.Score,
-				Confidence: r.Score, // Use score as confidence for now
-			})
-		}
-
-		// Stop once we have enough matches
-		if len(matches) >= topK {
-			break
-		}
+		matches = append(matches, PatternMatch{
+			Pattern:    p,
+			Score:      r.Score,
+			Confidence: r.Score, // Use score as confidence for now
+		})
 	}
 
 	return matches, nil
@@ -0,0 +1,58 @@
+package embed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestIndexKeysByID(t *testing.T) {
+	cfg := config.Default()
+	cfg.Embeddings.CacheDir = t.TempDir()
+	store := pattern.NewStore(t.TempDir())
+	if err := store.Create(&pattern.Pattern{Name: "go-errors", Content: "Wrap errors with %w."}); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := store.Get("go-errors")
+
+	emb := &fakeEmbedder{provider: "ollama", model: "qwen3-embedding", dim: 4}
+	idx := &PatternIndexer{cfg: cfg, embedder: emb, cache: NewCache(indexCacheDir(cfg), emb), store: store}
+
+	// An index from before pattern IDs keyed embeddings by name
+	legacy := "go-errors:" + p.CalculateEmbeddingHash()
+	idx.cache.Set(legacy, Vector{9, 9, 9, 9})
+	if !idx.indexed(*p) {
+		t.Error("name-keyed embedding not counted as indexed")
+	}
+
+	if err := idx.IndexAll(nil); err != nil {
+		t.Fatal(err)
+	}
+	if idx.cache.Len() != 1 {
+		t.Fatalf("cache has %d entries, want the adopted one", idx.cache.Len())
+	}
+	vec, ok := idx.cache.Get(idx.cacheKey(*p))
+	if !ok || vec[0] != 9 {
+		t.Fatalf("embedding not moved to ID key %s (re-embedded: %v)", idx.cacheKey(*p), ok)
+	}
+	if !strings.HasPrefix(idx.cacheKey(*p), p.ID+":") {
+		t.Errorf("cacheKey = %s, want it to start with the ID", idx.cacheKey(*p))
+	}
+
+	// Renaming doesn't touch ID-keyed embeddings
+	if _, err := store.Rename("go-errors", "go-error-wrapping"); err != nil {
+		t.Fatal(err)
+	}
+	renamed, _ := store.Get("go-error-wrapping")
+	if err := idx.RenamePattern(*p, *renamed); err != nil {
+		t.Fatal(err)
+	}
+	if !idx.indexed(*renamed) {
+		t.Error("renamed pattern no longer indexed")
+	}
+	if got, err := store.Resolve(refFromKey(idx.cacheKey(*renamed))); err != nil || got.Name != "go-error-wrapping" {
+		t.Errorf("cache key resolves to %v, %v", got, err)
+	}
+}
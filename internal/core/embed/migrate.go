@@ -0,0 +1,336 @@
+package embed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+// DefaultModels maps embedding providers to the model used when switching
+// to one without naming a model.
+var DefaultModels = map[string]string{
+	"ollama": "qwen3-embedding",
+	"openai": "text-embedding-3-small",
+	"voyage": "voyage-3",
+	"google": "text-embedding-004",
+}
+
+// embeddingPrices is USD per 1M input tokens by model ID.
+var embeddingPrices = map[string]float64{
+	"openai/text-embedding-3-small": 0.02,
+	"openai/text-embedding-3-large": 0.13,
+	"openai/text-embedding-ada-002": 0.10,
+	"voyage/voyage-3":               0.06,
+	"voyage/voyage-3-lite":          0.02,
+}
+
+// EstimateCost returns the USD cost of embedding tokens with a model, and
+// false when the model's price isn't known. Local models are free.
+func EstimateCost(modelID string, tokens int) (float64, bool) {
+	if strings.HasPrefix(modelID, "ollama/") {
+		return 0, true
+	}
+	price, ok := embeddingPrices[modelID]
+	if !ok {
+		return 0, false
+	}
+	return float64(tokens) * price / 1_000_000, true
+}
+
+// IndexModel describes the vectors one model contributed to an index.
+type IndexModel struct {
+	Model     string `json:"model"` // "provider/model"; just the provider in old indexes
+	Dimension int    `json:"dimension"`
+	Count     int    `json:"count"`
+}
+
+func (m IndexModel) String() string {
+	model := m.Model
+	if model == "" {
+		model = "an unknown model"
+	}
+	return fmt.Sprintf("%s (%d dims)", model, m.Dimension)
+}
+
+// provider returns the provider part of a model ID.
+func provider(modelID string) string {
+	p, _, _ := strings.Cut(modelID, "/")
+	return p
+}
+
+// IndexMismatchError reports an index whose vectors can't be compared with
+// the configured embedder's: built with another model, or a mix of models.
+type IndexMismatchError struct {
+	Index    []IndexModel
+	Current  string // model ID search is configured with
+	QueryDim int    // dimension of the query vector, if one was embedded
+}
+
+func (e *IndexMismatchError) Error() string {
+	fix := fmt.Sprintf("run 'mur embed migrate --to %s' to re-embed all patterns", provider(e.Current))
+	if len(e.Index) > 1 {
+		parts := make([]string, len(e.Index))
+		for i, m := range e.Index {
+			parts[i] = m.String()
+		}
+		return fmt.Sprintf("embedding index mixes %s; %s", strings.Join(parts, " and "), fix)
+	}
+	built := e.Index[0]
+	if e.QueryDim > 0 && e.QueryDim != built.Dimension {
+		return fmt.Sprintf("embedding index was built with %s but %s returns %d dims; %s",
+			built, e.Current, e.QueryDim, fix)
+	}
+	return fmt.Sprintf("embedding index was built with %s but search uses %s; %s, or set search.provider back",
+		built, e.Current, fix)
+}
+
+// Models groups the cached vectors by model and dimension, largest first.
+func (c *Cache) Models() []IndexModel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	type key struct {
+		model string
+		dim   int
+	}
+	counts := make(map[key]int)
+	for id, vec := range c.cache {
+		counts[key{c.models[id], len(vec)}]++
+	}
+	models := make([]IndexModel, 0, len(counts))
+	for k, n := range counts {
+		models = append(models, IndexModel{Model: k.model, Dimension: k.dim, Count: n})
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Count != models[j].Count {
+			return models[i].Count > models[j].Count
+		}
+		return models[i].Model < models[j].Model
+	})
+	return models
+}
+
+// Check returns an *IndexMismatchError unless every cached vector comes
+// from the cache's embedder and, when queryDim > 0, has that dimension.
+// Entries written before models were recorded only name their provider.
+func (c *Cache) Check(queryDim int) error {
+	models := c.Models()
+	if len(models) == 0 {
+		return nil
+	}
+	current := ModelID(c.embedder)
+	mismatch := &IndexMismatchError{Index: models, Current: current, QueryDim: queryDim}
+
+	dims := make(map[int]bool)
+	named := make(map[string]bool)
+	for _, m := range models {
+		dims[m.Dimension] = true
+		switch {
+		case m.Model == "":
+		case strings.Contains(m.Model, "/"):
+			named[m.Model] = true
+			if m.Model != current {
+				return mismatch
+			}
+		case m.Model != provider(current):
+			return mismatch
+		}
+	}
+	if len(dims) > 1 || len(named) > 1 {
+		return mismatch
+	}
+	if queryDim > 0 && !dims[queryDim] {
+		return mismatch
+	}
+	return nil
+}
+
+// Migration records a switch of the index to another embedding model, so
+// it can be rolled back.
+type Migration struct {
+	From      []IndexModel `json:"from"`
+	To        string       `json:"to"`
+	Dimension int          `json:"dimension"`
+	Patterns  int          `json:"patterns"`
+	At        time.Time    `json:"at"`
+}
+
+// MigrationPlan is what re-embedding the index with a model will take.
+type MigrationPlan struct {
+	From      []IndexModel
+	To        string
+	Patterns  int
+	Tokens    int
+	Cost      float64
+	CostKnown bool
+}
+
+// indexCacheDir returns the directory the index of cfg is stored in.
+func indexCacheDir(cfg *config.Config) string {
+	home, _ := os.UserHomeDir()
+	cacheDir := cfg.Embeddings.CacheDir
+	if cacheDir == "" || cacheDir == config.DefaultEmbeddingsCacheDir {
+		return filepath.Join(config.CacheDir(home), "embeddings")
+	}
+	if strings.HasPrefix(cacheDir, "~") {
+		return filepath.Join(home, cacheDir[2:])
+	}
+	return cacheDir
+}
+
+func migrationFile(dir string) string { return filepath.Join(dir, "migration.json") }
+
+func backupFile(c *Cache) string { return c.cacheFile() + ".bak" }
+
+// Check returns an *IndexMismatchError if the index holds vectors the
+// configured model can't be compared with.
+func (idx *PatternIndexer) Check() error {
+	return idx.cache.Check(0)
+}
+
+// IndexModels describes the vectors in the index.
+func (idx *PatternIndexer) IndexModels() []IndexModel {
+	return idx.cache.Models()
+}
+
+// migrationTexts returns the cache keys and texts of every pattern, the
+// way a rebuild without new expansions embeds them.
+func (idx *PatternIndexer) migrationTexts() ([]string, []string, error) {
+	patterns, err := idx.store.List()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot list patterns: %w", err)
+	}
+	eq := idx.Expansions()
+	keys := make([]string, len(patterns))
+	texts := make([]string, len(patterns))
+	for i, p := range patterns {
+		keys[i] = idx.cacheKey(p)
+		texts[i] = strings.ToLower(buildIndexText(p))
+		if queries := expansionFor(eq, p, idx.cfg.Learning.LLM.Model, ExpandCachedOnly); len(queries) > 0 {
+			texts[i] += " | search queries: " + strings.Join(queries, " | ")
+		}
+	}
+	return keys, texts, nil
+}
+
+// PlanMigration estimates re-embedding every pattern with the indexer's
+// model.
+func (idx *PatternIndexer) PlanMigration() (MigrationPlan, error) {
+	_, texts, err := idx.migrationTexts()
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+	plan := MigrationPlan{From: idx.cache.Models(), To: ModelID(idx.embedder), Patterns: len(texts)}
+	for _, t := range texts {
+		plan.Tokens += stats.EstimateTokens(t)
+	}
+	plan.Cost, plan.CostKnown = EstimateCost(plan.To, plan.Tokens)
+	return plan, nil
+}
+
+// Migrate re-embeds every pattern with the indexer's model in batches of
+// batchSize and replaces the index. The old index is kept until the next
+// migration so RollbackMigration can restore it; if embedding fails the
+// index is left untouched.
+func (idx *PatternIndexer) Migrate(batchSize int, progress func(done, total int)) (*Migration, error) {
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+	keys, texts, err := idx.migrationTexts()
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := NewCache(idx.cache.dir, idx.embedder)
+	dim := 0
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		vectors, err := idx.embedder.EmbedBatch(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("embedding patterns %d-%d: %w", start+1, end, err)
+		}
+		if len(vectors) != end-start {
+			return nil, fmt.Errorf("embedding patterns %d-%d: got %d vectors", start+1, end, len(vectors))
+		}
+		for i, vec := range vectors {
+			if dim == 0 {
+				dim = len(vec)
+			}
+			if len(vec) != dim {
+				return nil, fmt.Errorf("%s returned %d and %d dimension vectors", ModelID(idx.embedder), dim, len(vec))
+			}
+			fresh.Set(keys[start+i], vec)
+		}
+		if progress != nil {
+			progress(end, len(texts))
+		}
+	}
+
+	m := &Migration{
+		From:      idx.cache.Models(),
+		To:        ModelID(idx.embedder),
+		Dimension: dim,
+		Patterns:  len(texts),
+		At:        time.Now(),
+	}
+
+	current := idx.cache.cacheFile()
+	hadIndex := false
+	if _, err := os.Stat(current); err == nil {
+		if err := os.Rename(current, backupFile(idx.cache)); err != nil {
+			return nil, fmt.Errorf("cannot back up index: %w", err)
+		}
+		hadIndex = true
+	}
+	if err := fresh.Save(); err != nil {
+		if hadIndex {
+			_ = os.Rename(backupFile(idx.cache), current)
+		}
+		return nil, fmt.Errorf("cannot save index: %w", err)
+	}
+	idx.cache = fresh
+
+	data, _ := json.MarshalIndent(m, "", "  ")
+	if !hadIndex {
+		// Nothing to roll back to
+		_ = os.Remove(migrationFile(idx.cache.dir))
+		return m, nil
+	}
+	if err := os.WriteFile(migrationFile(idx.cache.dir), data, 0644); err != nil {
+		return m, fmt.Errorf("index migrated, but cannot record it for rollback: %w", err)
+	}
+	return m, nil
+}
+
+// RollbackMigration restores the index replaced by the last migration and
+// returns that migration, whose From says which model to configure again.
+func RollbackMigration(cfg *config.Config) (*Migration, error) {
+	dir := indexCacheDir(cfg)
+	data, err := os.ReadFile(migrationFile(dir))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no migration to roll back")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Migration
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", migrationFile(dir), err)
+	}
+
+	c := NewCache(dir, nil)
+	if err := os.Rename(backupFile(c), c.cacheFile()); err != nil {
+		return nil, fmt.Errorf("cannot restore the previous index: %w", err)
+	}
+	if err := os.Remove(migrationFile(dir)); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
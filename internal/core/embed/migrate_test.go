@@ -0,0 +1,161 @@
+package embed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// fakeEmbedder returns dim-sized vectors and counts its requests.
+type fakeEmbedder struct {
+	provider, model string
+	dim             int
+	batches         int
+}
+
+func (f *fakeEmbedder) Name() string   { return f.provider }
+func (f *fakeEmbedder) Model() string  { return f.model }
+func (f *fakeEmbedder) Dimension() int { return f.dim }
+
+func (f *fakeEmbedder) Embed(text string) (Vector, error) {
+	vec := make(Vector, f.dim)
+	vec[len(text)%f.dim] = 1
+	return vec, nil
+}
+
+func (f *fakeEmbedder) EmbedBatch(texts []string) ([]Vector, error) {
+	f.batches++
+	vectors := make([]Vector, len(texts))
+	for i, t := range texts {
+		vectors[i], _ = f.Embed(t)
+	}
+	return vectors, nil
+}
+
+func TestCacheCheck(t *testing.T) {
+	ollama := &fakeEmbedder{provider: "ollama", model: "qwen3-embedding", dim: 4}
+	openai := &fakeEmbedder{provider: "openai", model: "text-embedding-3-small", dim: 6}
+	dir := t.TempDir()
+
+	c := NewCache(dir, ollama)
+	c.Set("a:1", make(Vector, 4))
+	if err := c.Check(4); err != nil {
+		t.Errorf("Check = %v on a single-model index", err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loaded with another configured model
+	c = NewCache(dir, openai)
+	_ = c.Load()
+	var mismatch *IndexMismatchError
+	if err := c.Check(0); !errors.As(err, &mismatch) {
+		t.Fatalf("Check = %v, want a mismatch", err)
+	}
+	if !strings.Contains(mismatch.Error(), "ollama/qwen3-embedding (4 dims)") || !strings.Contains(mismatch.Error(), "--to openai") {
+		t.Errorf("message = %q", mismatch.Error())
+	}
+
+	// Mixed vectors are refused even for one of their models
+	c.Set("b:1", make(Vector, 6))
+	if got := c.Models(); len(got) != 2 {
+		t.Fatalf("Models = %+v", got)
+	}
+	err := c.Check(6)
+	if !errors.As(err, &mismatch) || !strings.Contains(err.Error(), "mixes") {
+		t.Errorf("Check = %v, want mixed", err)
+	}
+
+	// Entries from before models were recorded only name the provider
+	legacy := NewCache(t.TempDir(), ollama)
+	legacy.Set("a:1", make(Vector, 4))
+	legacy.models["a:1"] = "ollama"
+	if err := legacy.Check(4); err != nil {
+		t.Errorf("legacy Check = %v", err)
+	}
+	if err := legacy.Check(8); err == nil {
+		t.Error("dimension change not detected")
+	}
+}
+
+func TestMigrateAndRollback(t *testing.T) {
+	cfg := config.Default()
+	cfg.Embeddings.CacheDir = t.TempDir()
+	store := pattern.NewStore(t.TempDir())
+	for _, name := range []string{"one", "two", "three"} {
+		if err := store.Create(&pattern.Pattern{Name: name, Content: "content of " + name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ollama := &fakeEmbedder{provider: "ollama", model: "qwen3-embedding", dim: 4}
+	idx := &PatternIndexer{cfg: cfg, embedder: ollama, cache: NewCache(indexCacheDir(cfg), ollama), store: store}
+	if err := idx.IndexAll(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	openai := &fakeEmbedder{provider: "openai", model: "text-embedding-3-small", dim: 6}
+	idx = &PatternIndexer{cfg: cfg, embedder: openai, cache: NewCache(indexCacheDir(cfg), openai), store: store}
+	_ = idx.cache.Load()
+	if err := idx.IndexAll(nil); err == nil {
+		t.Fatal("IndexAll mixed models into the index")
+	}
+	if _, err := idx.Search("content", 3); err == nil {
+		t.Error("Search used a mismatched index")
+	}
+
+	plan, err := idx.PlanMigration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Patterns != 3 || plan.Tokens == 0 || !plan.CostKnown || plan.To != "openai/text-embedding-3-small" {
+		t.Errorf("plan = %+v", plan)
+	}
+
+	var calls []int
+	m, err := idx.Migrate(2, func(done, total int) { calls = append(calls, done) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openai.batches != 2 || len(calls) != 2 || calls[1] != 3 {
+		t.Errorf("batches = %d, progress = %v", openai.batches, calls)
+	}
+	if m.Dimension != 6 || len(m.From) != 1 || m.From[0].Model != "ollama/qwen3-embedding" {
+		t.Errorf("migration = %+v", m)
+	}
+	if err := idx.Check(); err != nil {
+		t.Errorf("Check after migrate = %v", err)
+	}
+
+	back, err := RollbackMigration(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.From[0].Model != "ollama/qwen3-embedding" {
+		t.Errorf("rollback = %+v", back)
+	}
+	restored := NewCache(indexCacheDir(cfg), ollama)
+	_ = restored.Load()
+	if err := restored.Check(4); err != nil || restored.Len() != 3 {
+		t.Errorf("restored index: len %d, Check = %v", restored.Len(), err)
+	}
+	if _, err := RollbackMigration(cfg); err == nil {
+		t.Error("rolled back twice")
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	if cost, ok := EstimateCost("openai/text-embedding-3-small", 1_000_000); !ok || cost != 0.02 {
+		t.Errorf("cost = %v, %v", cost, ok)
+	}
+	if cost, ok := EstimateCost("ollama/nomic-embed-text", 1_000_000); !ok || cost != 0 {
+		t.Errorf("ollama cost = %v, %v", cost, ok)
+	}
+	if _, ok := EstimateCost("openai/unknown", 10); ok {
+		t.Error("unknown model priced")
+	}
+}
@@ -3,11 +3,14 @@ package embed
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 
 	"github.com/mur-run/mur-core/internal/cache"
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	murlog "github.com/mur-run/mur-core/internal/log"
+	"github.com/mur-run/mur-core/internal/timing"
 )
 
 // PatternSearcher provides semantic search over patterns.
@@ -28,13 +31,15 @@ type PatternMatch struct {
 
 // NewPatternSearcher creates a new semantic pattern searcher.
 func NewPatternSearcher(store *pattern.Store, cfg Config) (*PatternSearcher, error) {
+	defer timing.Track("embed.NewPatternSearcher")()
+
 	embedder, err := NewEmbedder(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	home, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(home, ".mur", "embeddings")
+	cacheDir := filepath.Join(config.CacheDir(home), "embeddings")
 	cache := NewCache(cacheDir, embedder)
 
 	// Load existing cache
@@ -65,6 +70,15 @@ func NewPatternSearcher(store *pattern.Store, cfg Config) (*PatternSearcher, err
 	return searcher, nil
 }
 
+// HasIndex reports whether the default embeddings cache exists. Hooks check
+// it before creating a PatternSearcher, which would otherwise start
+// indexing in the background.
+func HasIndex() bool {
+	home, _ := os.UserHomeDir()
+	_, err := os.Stat(filepath.Join(config.CacheDir(home), "embeddings", "embeddings.json"))
+	return err == nil
+}
+
 // WithMemoryCache attaches in-process caches so searches use the
 // pre-normalized EmbeddingMatrix (dot-product) instead of per-call
 // cosine similarity, and pattern lookups come from RAM.
@@ -82,6 +96,9 @@ func (s *PatternSearcher) IndexPatterns() error {
 	if err != nil {
 		return err
 	}
+	if err := s.cache.Check(0); err != nil {
+		return err
+	}
 
 	for _, p := range patterns {
 		// Create searchable text from pattern
@@ -92,6 +109,7 @@ func (s *PatternSearcher) IndexPatterns() error {
 		if err != nil {
 			// Log but continue
 			fmt.Fprintf(os.Stderr, "Warning: failed to embed pattern %s: %v\n", p.Name, err)
+			murlog.For("embed").Warn("cannot embed pattern", "pattern", p.Name, "err", err)
 			continue
 		}
 	}
@@ -102,11 +120,18 @@ func (s *PatternSearcher) IndexPatterns() error {
 
 // Search finds patterns semantically similar to the query.
 func (s *PatternSearcher) Search(query string, topK int) ([]PatternMatch, error) {
+	defer timing.Track("embed.Search")()
+
 	// Embed query
+	embedded := timing.Track("embed.Query")
 	queryVec, err := s.embedder.Embed(PrepareQuery(query, s.embedder))
+	embedded()
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
+	if err := s.cache.Check(len(queryVec)); err != nil {
+		return nil, err
+	}
 
 	// Use EmbeddingMatrix (fast path) or disk cache (fallback)
 	if s.matrix != nil && s.matrix.IsLoaded() && s.matrix.Len() > 0 {
@@ -160,23 +185,22 @@ func (s *PatternSearcher) resultsToMatches(results []SearchResult, topK int) ([]
 	return matches, nil
 }
 
-// lookupPattern finds a pattern by ID, preferring the in-process cache.
-func (s *PatternSearcher) lookupPattern(id string) *pattern.Pattern {
+// lookupPattern finds the pattern an embedding cache key refers to,
+// preferring the in-process cache.
+func (s *PatternSearcher) lookupPattern(key string) *pattern.Pattern {
+	ref := refFromKey(key)
 	// Fast path: in-process cache
 	if s.pcache != nil {
-		if p := s.pcache.Get(id); p != nil {
+		if p := s.pcache.Get(ref); p != nil {
 			return p
 		}
 	}
-	// Fallback: read from store
-	patterns, _ := s.store.List()
-	for _, p := range patterns {
-		if p.ID == id {
-			pCopy := p
-			return &pCopy
-		}
+	// Fallback: read from store; keys made before pattern IDs hold names
+	p, err := s.store.Resolve(ref)
+	if err != nil {
+		return nil
 	}
-	return nil
+	return p
 }
 
 // SearchWithContext combines semantic search with context.
@@ -279,7 +303,7 @@ func (s *PatternSearcher) contextBoost(p *pattern.Pattern, ctx *SearchContext) f
 	// Language match
 	for _, lang := range p.Applies.Languages {
 		for _, ctxLang := range ctx.Languages {
-			if lang == ctxLang {
+			if pattern.MatchApplies(lang, ctxLang) {
 				boost += 0.2
 			}
 		}
@@ -295,10 +319,8 @@ func (s *PatternSearcher) contextBoost(p *pattern.Pattern, ctx *SearchContext) f
 	}
 
 	// Project match
-	for _, proj := range p.Applies.Projects {
-		if proj == ctx.ProjectName {
-			boost += 0.3
-		}
+	if _, ok := p.Applies.MatchProject([]string{ctx.ProjectName}); ok {
+		boost += 0.3
 	}
 
 	return boost
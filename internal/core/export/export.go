@@ -0,0 +1,296 @@
+// Package export writes pattern metadata and usage aggregates as NDJSON,
+// one pattern per line, for loading into external BI tools and warehouses.
+//
+// Records are ordered by pattern ID. A page ends after Limit records and
+// returns an opaque cursor; passing it back resumes after the last ID, so a
+// nightly job can page through any number of patterns without holding them
+// all in one response.
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// ErrBadCursor means a cursor wasn't returned by a previous page.
+var ErrBadCursor = errors.New("invalid cursor")
+
+// Record is one exported pattern.
+type Record struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Content       string     `json:"content"`
+	Tags          []string   `json:"tags"`
+	InferredTags  []TagScore `json:"inferred_tags"`
+	Pinned        bool       `json:"pinned"`
+	Status        string     `json:"status"`
+	TrustLevel    string     `json:"trust_level"`
+	Risk          string     `json:"risk"`
+	Source        string     `json:"source"`
+	Reviewed      bool       `json:"reviewed"`
+	Version       string     `json:"version"`
+	SchemaVersion int        `json:"schema_version"`
+	Created       time.Time  `json:"created"`
+	Updated       time.Time  `json:"updated"`
+	Effectiveness float64    `json:"effectiveness"`
+	UsageCount    int        `json:"usage_count"`
+	LastUsed      *time.Time `json:"last_used"`
+	ExtractedFrom string     `json:"extracted_from"`
+	Usage         Usage      `json:"usage"`
+}
+
+// TagScore is an inferred tag and its confidence.
+type TagScore struct {
+	Tag        string  `json:"tag"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Usage aggregates the tracked runs that used a pattern.
+type Usage struct {
+	Uses          int        `json:"uses"`
+	SuccessRate   float64    `json:"success_rate"`
+	Helpful       int        `json:"helpful"`
+	Unhelpful     int        `json:"unhelpful"`
+	Neutral       int        `json:"neutral"`
+	FeedbackScore float64    `json:"feedback_score"` // -1.0 to 1.0
+	Effectiveness float64    `json:"effectiveness"`
+	LastUsed      *time.Time `json:"last_used"`
+}
+
+// Fields returns the top-level field names of a Record, in output order.
+func Fields() []string {
+	t := reflect.TypeOf(Record{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+	return names
+}
+
+// Options selects a page of the export.
+type Options struct {
+	// Fields limits each record to these top-level fields; id is always
+	// included. Empty means all fields.
+	Fields []string
+	// Cursor resumes after the last record of a previous page.
+	Cursor string
+	// Limit is the most records to write; 0 means no limit.
+	Limit int
+	// Since keeps only patterns updated or used after this time.
+	Since time.Time
+}
+
+// Page is one page of the export.
+type Page struct {
+	Records []Record
+	Fields  []string
+	// Next is the cursor for the following page, or "" on the last page.
+	Next string
+}
+
+// Validate checks the fields and cursor.
+func (o Options) Validate() error {
+	known := make(map[string]bool)
+	for _, f := range Fields() {
+		known[f] = true
+	}
+	for _, f := range o.Fields {
+		if !known[f] {
+			return fmt.Errorf("unknown field %q (available: %s)", f, strings.Join(Fields(), ", "))
+		}
+	}
+	if o.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	_, err := decodeCursor(o.Cursor)
+	return err
+}
+
+// ParseFields splits a comma-separated field list.
+func ParseFields(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Load reads every pattern in store, including archived ones, and joins
+// the tracker's usage stats. tracker may be nil.
+func Load(store *pattern.Store, tracker *inject.Tracker) ([]Record, error) {
+	patterns, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load patterns: %w", err)
+	}
+	var stats []inject.EffectivenessStats
+	if tracker != nil {
+		if stats, err = tracker.GetStats(); err != nil {
+			return nil, fmt.Errorf("cannot read usage stats: %w", err)
+		}
+	}
+	return Records(patterns, stats), nil
+}
+
+// Records builds a record for every pattern, joined with the tracker's
+// usage stats, sorted by ID. stats may be nil.
+func Records(patterns []pattern.Pattern, stats []inject.EffectivenessStats) []Record {
+	usage := make(map[string]inject.EffectivenessStats, len(stats))
+	for _, s := range stats {
+		usage[s.PatternID] = s
+	}
+
+	records := make([]Record, 0, len(patterns))
+	for i := range patterns {
+		records = append(records, newRecord(&patterns[i], usage[patterns[i].ID]))
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+func newRecord(p *pattern.Pattern, s inject.EffectivenessStats) Record {
+	r := Record{
+		ID:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		Content:       p.Content,
+		Tags:          p.Tags.Confirmed,
+		Pinned:        p.Pinned,
+		Status:        string(p.Lifecycle.Status),
+		TrustLevel:    string(p.Security.TrustLevel),
+		Risk:          string(p.Security.Risk),
+		Source:        p.Security.Source,
+		Reviewed:      p.Security.Reviewed,
+		Version:       p.Version,
+		SchemaVersion: p.SchemaVersion,
+		Created:       p.Lifecycle.Created,
+		Updated:       p.Lifecycle.Updated,
+		Effectiveness: p.Learning.Effectiveness,
+		UsageCount:    p.Learning.UsageCount,
+		LastUsed:      p.Learning.LastUsed,
+		ExtractedFrom: p.Learning.ExtractedFrom,
+		Usage: Usage{
+			Uses:          s.TotalUses,
+			SuccessRate:   s.SuccessRate,
+			Helpful:       s.HelpfulCount,
+			Unhelpful:     s.UnhelpfulCount,
+			Neutral:       s.NeutralCount,
+			FeedbackScore: s.FeedbackScore,
+			Effectiveness: s.Effectiveness,
+		},
+	}
+	if r.Tags == nil {
+		r.Tags = []string{}
+	}
+	r.InferredTags = make([]TagScore, 0, len(p.Tags.Inferred))
+	for _, ts := range p.Tags.Inferred {
+		r.InferredTags = append(r.InferredTags, TagScore{Tag: ts.Tag, Confidence: ts.Confidence})
+	}
+	if !s.LastUsed.IsZero() {
+		last := s.LastUsed
+		r.Usage.LastUsed = &last
+	}
+	return r
+}
+
+// changedSince reports whether the pattern was updated or used after t.
+func (r *Record) changedSince(t time.Time) bool {
+	if r.Updated.After(t) || r.Created.After(t) {
+		return true
+	}
+	if r.LastUsed != nil && r.LastUsed.After(t) {
+		return true
+	}
+	return r.Usage.LastUsed != nil && r.Usage.LastUsed.After(t)
+}
+
+// Select returns the page of records opts asks for and the cursor for the
+// next page ("" on the last page). records must be sorted by ID, as Records
+// returns them.
+func Select(records []Record, opts Options) (*Page, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	after, _ := decodeCursor(opts.Cursor)
+
+	page := &Page{Fields: opts.Fields}
+	for i := range records {
+		r := &records[i]
+		if after != "" && r.ID <= after {
+			continue
+		}
+		if !opts.Since.IsZero() && !r.changedSince(opts.Since) {
+			continue
+		}
+		if opts.Limit > 0 && len(page.Records) == opts.Limit {
+			page.Next = encodeCursor(page.Records[len(page.Records)-1].ID)
+			break
+		}
+		page.Records = append(page.Records, *r)
+	}
+	return page, nil
+}
+
+// Write writes the page as NDJSON, one record per line, as it encodes them.
+func (p *Page) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for i := range p.Records {
+		var line any = &p.Records[i]
+		if len(p.Fields) > 0 {
+			selected, err := selectFields(&p.Records[i], p.Fields)
+			if err != nil {
+				return err
+			}
+			line = selected
+		}
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("failed to write record %s: %w", p.Records[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// selectFields returns the record with only id and the given fields.
+func selectFields(r *Record, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	out := map[string]json.RawMessage{"id": all["id"]}
+	for _, f := range fields {
+		out[f] = all[f]
+	}
+	return out, nil
+}
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", ErrBadCursor
+	}
+	return string(id), nil
+}
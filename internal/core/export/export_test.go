@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func testRecords() []Record {
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	patterns := []pattern.Pattern{
+		{ID: "c", Name: "gamma", Lifecycle: pattern.LifecycleMeta{Created: old, Updated: old}},
+		{ID: "a", Name: "alpha", Tags: pattern.TagSet{Confirmed: []string{"go"}}, Lifecycle: pattern.LifecycleMeta{Created: old, Updated: old}},
+		{ID: "b", Name: "beta", Lifecycle: pattern.LifecycleMeta{Created: old, Updated: old.AddDate(0, 2, 0)}},
+	}
+	stats := []inject.EffectivenessStats{
+		{PatternID: "a", TotalUses: 4, SuccessRate: 0.75, HelpfulCount: 2, LastUsed: old.AddDate(0, 3, 0)},
+	}
+	return Records(patterns, stats)
+}
+
+func readLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var m map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestWritePages(t *testing.T) {
+	records := testRecords()
+
+	var ids []string
+	var cursor string
+	for pages := 0; pages < 5; pages++ {
+		page, err := Select(records, Options{Cursor: cursor, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := page.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		lines := readLines(t, &buf)
+		if len(lines) != len(page.Records) {
+			t.Errorf("page has %d records, wrote %d lines", len(page.Records), len(lines))
+		}
+		for _, l := range lines {
+			ids = append(ids, l["id"].(string))
+		}
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if got := ids; len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("ids = %v, want [a b c]", got)
+	}
+}
+
+func TestWriteFieldsAndSince(t *testing.T) {
+	records := testRecords()
+
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	page, err := Select(records, Options{Fields: []string{"name", "usage"}, Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := page.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := readLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d records changed since %s, want 2 (a used, b updated)", len(lines), since)
+	}
+	a := lines[0]
+	if len(a) != 3 || a["id"] != "a" || a["name"] != "alpha" {
+		t.Errorf("selected fields = %v", a)
+	}
+	if usage := a["usage"].(map[string]any); usage["uses"] != 4.0 || usage["helpful"] != 2.0 {
+		t.Errorf("usage = %v", usage)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Options{Fields: []string{"name", "nope"}}).Validate(); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if err := (Options{Cursor: "%%%"}).Validate(); !errors.Is(err, ErrBadCursor) {
+		t.Errorf("bad cursor err = %v", err)
+	}
+	if got := ParseFields(" name, ,usage "); len(got) != 2 || got[1] != "usage" {
+		t.Errorf("ParseFields = %q", got)
+	}
+}
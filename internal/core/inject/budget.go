@@ -0,0 +1,213 @@
+package inject
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// SummaryThreshold is the content length above which a pattern counts as
+// long (the L3 threshold sync splits examples at); long content is the
+// first to be summarized when context is over its token budget.
+const SummaryThreshold = 500
+
+// SummaryChars is about the longest a summary of long content gets.
+const SummaryChars = 300
+
+// TokenBudget returns the token budget for injected context: budget if
+// set, else search.max_inject_tokens. 0 means no limit.
+func TokenBudget(cfg *config.Config, budget int) int {
+	if budget > 0 {
+		return budget
+	}
+	if cfg == nil {
+		return 0
+	}
+	return max(cfg.Search.MaxInjectTokens, 0)
+}
+
+type charKind int
+
+const (
+	kindNone   charKind = iota
+	kindLetter          // Latin, Cyrillic, ... words
+	kindDigit
+	kindSpace
+	kindPunct // punctuation and symbols
+	kindWide  // CJK: about a token per character
+)
+
+func classify(r rune) charKind {
+	switch {
+	case unicode.IsSpace(r):
+		return kindSpace
+	case unicode.IsDigit(r):
+		return kindDigit
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return kindWide
+	case unicode.IsLetter(r):
+		return kindLetter
+	default:
+		return kindPunct
+	}
+}
+
+// EstimateTokens approximates how many tokens text takes with BPE
+// tokenizers like cl100k, without shipping a vocabulary. It follows their
+// pre-tokenizer: a word takes its leading space along, camelCase parts
+// and long words split into several tokens, numbers go in groups of three
+// digits, runs of punctuation merge in pairs, and line breaks and
+// indentation are a token each. It errs on the high side for
+// prose, so a budget holds.
+func EstimateTokens(text string) int {
+	tokens, run := 0, 0
+	kind := kindNone
+	breaks, indent := false, 0 // whitespace run: has line breaks or tabs; spaces after them
+	flush := func() {
+		switch kind {
+		case kindLetter:
+			tokens += (run + 5) / 6
+		case kindDigit:
+			tokens += (run + 2) / 3
+		case kindPunct:
+			tokens += (run + 1) / 2
+		case kindSpace:
+			if breaks {
+				tokens++
+			}
+			if indent > 1 {
+				tokens++
+			}
+		}
+		run, breaks, indent = 0, false, 0
+	}
+
+	prevLower := false
+	for _, r := range text {
+		k := classify(r)
+		if k != kind || (k == kindLetter && prevLower && unicode.IsUpper(r)) {
+			flush()
+			kind = k
+		}
+		switch k {
+		case kindWide:
+			tokens++
+		case kindSpace:
+			if r == ' ' {
+				indent++
+			} else {
+				breaks, indent = true, 0
+			}
+		default:
+			run++
+		}
+		prevLower = unicode.IsLower(r)
+	}
+	flush()
+	return tokens
+}
+
+// Summarize returns the gist of a long pattern's content: its first
+// paragraph of prose, cut at a word to about SummaryChars, and where to
+// read the rest.
+func Summarize(name, content string) string {
+	var gist string
+	for _, para := range strings.Split(strings.TrimSpace(content), "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(para, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+				lines = append(lines, line)
+			}
+		}
+		gist = strings.TrimSpace(strings.Join(lines, "\n"))
+		if i := strings.Index(gist, "```"); i >= 0 {
+			gist = strings.TrimSpace(gist[:i])
+		}
+		if gist != "" {
+			break
+		}
+	}
+
+	if len(gist) > SummaryChars {
+		cut := strings.LastIndexAny(gist[:SummaryChars], " \n")
+		if cut <= 0 {
+			cut = SummaryChars
+			for !utf8.RuneStart(gist[cut]) {
+				cut--
+			}
+		}
+		gist = strings.TrimSpace(gist[:cut]) + "…"
+	}
+	more := "(summary; full pattern: mur learn get " + name + ")"
+	if gist == "" {
+		return more
+	}
+	return gist + "\n" + more
+}
+
+// BudgetFit is what FitBudget did to stay within the budget.
+type BudgetFit struct {
+	Tokens     int      // estimated tokens of the output
+	Summarized []string // patterns whose content was replaced by a summary
+	Dropped    []string // patterns left out
+}
+
+// FitBudget renders data and, while the output is over budget tokens,
+// first summarizes long content, starting with the lowest-ranked pattern,
+// then leaves patterns out, the lowest-ranked first and pinned ones last.
+// data.Patterns must be in rank order. If not even one pattern fits the
+// output is empty; with budget <= 0 data is rendered as is.
+func FitBudget(data FormatData, budget int, render func(FormatData) (string, error)) (string, BudgetFit, error) {
+	var fit BudgetFit
+	out, err := render(data)
+	if err != nil {
+		return "", fit, err
+	}
+	fits := func() bool {
+		fit.Tokens = EstimateTokens(out)
+		return budget <= 0 || fit.Tokens <= budget
+	}
+	if fits() {
+		return out, fit, nil
+	}
+
+	data.Patterns = slices.Clone(data.Patterns)
+	if !data.Compact {
+		for i := len(data.Patterns) - 1; i >= 0 && !fits(); i-- {
+			p := &data.Patterns[i]
+			if len(p.Content) <= SummaryThreshold {
+				continue
+			}
+			p.Content = Summarize(p.Name, p.Content)
+			fit.Summarized = append(fit.Summarized, p.Name)
+			if out, err = render(data); err != nil {
+				return "", fit, err
+			}
+		}
+	}
+
+	for !fits() {
+		if len(data.Patterns) == 0 {
+			fit.Tokens = 0
+			return "", fit, nil
+		}
+		i := len(data.Patterns) - 1
+		for j := i; j >= 0; j-- {
+			if !data.Patterns[j].Pinned {
+				i = j
+				break
+			}
+		}
+		name := data.Patterns[i].Name
+		fit.Dropped = append(fit.Dropped, name)
+		fit.Summarized = slices.DeleteFunc(fit.Summarized, func(s string) bool { return s == name })
+		data.Patterns = slices.Delete(data.Patterns, i, i+1)
+		if out, err = render(data); err != nil {
+			return "", fit, err
+		}
+	}
+	return out, fit, nil
+}
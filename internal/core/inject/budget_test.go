@@ -0,0 +1,110 @@
+package inject
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"Hello, world!", 4},
+		{"The quick brown fox jumps over the lazy dog.", 10},
+		{"getUserName", 3},
+		{"1234567", 3},
+		{"a\n\n    b", 4},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	content := "## Retries\n\nRetry idempotent requests with exponential backoff and jitter.\n\n```go\nfor i := range 3 {}\n```\n" + strings.Repeat("More detail. ", 100)
+	got := Summarize("http-retry", content)
+	want := "Retry idempotent requests with exponential backoff and jitter.\n(summary; full pattern: mur learn get http-retry)"
+	if got != want {
+		t.Errorf("Summarize = %q, want %q", got, want)
+	}
+
+	long := Summarize("long", strings.Repeat("word ", 200))
+	if first, _, _ := strings.Cut(long, "\n"); len(first) > SummaryChars+len("…") || !strings.HasSuffix(first, "…") {
+		t.Errorf("long summary not cut at a word: %q", first)
+	}
+}
+
+func TestFitBudget(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+	long := strings.Repeat("Always check the error before using the result. ", 40)
+	data := FormatData{Patterns: []FormatPattern{
+		{Name: "pinned-rule", Content: "Never log secrets.", Pinned: true},
+		{Name: "top", Content: long},
+		{Name: "middle", Content: long},
+		{Name: "bottom", Content: "Prefer table-driven tests."},
+	}}
+	render := func(d FormatData) (string, error) { return Render(FormatText, d) }
+
+	full, fit, err := FitBudget(data, 0, render)
+	if err != nil || len(fit.Summarized)+len(fit.Dropped) != 0 {
+		t.Fatalf("no budget: fit = %+v, err = %v", fit, err)
+	}
+	if fit.Tokens != EstimateTokens(full) {
+		t.Errorf("Tokens = %d, want %d", fit.Tokens, EstimateTokens(full))
+	}
+
+	// Summaries make room: the lowest-ranked long pattern goes first
+	out, fit, err := FitBudget(data, fit.Tokens-100, render)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(fit.Summarized, []string{"middle"}) || len(fit.Dropped) != 0 {
+		t.Errorf("fit = %+v, want middle summarized", fit)
+	}
+	if EstimateTokens(out) > fit.Tokens || strings.Count(out, long) != 1 {
+		t.Errorf("top pattern not kept in full:\n%s", out)
+	}
+
+	// A tight budget leaves out the lowest-ranked patterns, pinned last
+	out, fit, err = FitBudget(data, 60, render)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Tokens > 60 || !strings.Contains(out, "pinned-rule") || strings.Contains(out, "bottom") {
+		t.Errorf("fit = %+v:\n%s", fit, out)
+	}
+	if !slices.Contains(fit.Dropped, "bottom") || slices.Contains(fit.Summarized, "middle") {
+		t.Errorf("fit = %+v, want bottom and middle dropped", fit)
+	}
+
+	// Nothing fits
+	out, fit, _ = FitBudget(data, 5, render)
+	if out != "" || len(fit.Dropped) != len(data.Patterns) {
+		t.Errorf("budget 5: out = %q, fit = %+v", out, fit)
+	}
+	if data.Patterns[2].Content != long {
+		t.Error("FitBudget changed the caller's patterns")
+	}
+}
+
+func TestExplanationSetBudget(t *testing.T) {
+	ex := NewExplanation("context")
+	ex.Add(Decision{Name: "a", Injected: true, Reason: ReasonRelevant})
+	ex.Add(Decision{Name: "b", Injected: true, Reason: ReasonRelevant})
+	ex.SetBudget(100, BudgetFit{Tokens: 90, Summarized: []string{"a"}, Dropped: []string{"b"}})
+	if ex.Budget != 100 || ex.Tokens != 90 {
+		t.Errorf("budget = %d, tokens = %d", ex.Budget, ex.Tokens)
+	}
+	if !ex.Patterns[0].Summarized || !ex.Patterns[0].Injected {
+		t.Errorf("a = %+v, want injected and summarized", ex.Patterns[0])
+	}
+	if ex.Patterns[1].Injected || ex.Patterns[1].Reason != ReasonTokenBudget {
+		t.Errorf("b = %+v, want left out over the token budget", ex.Patterns[1])
+	}
+}
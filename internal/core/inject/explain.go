@@ -0,0 +1,330 @@
+package inject
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// Explanation records what one injection chose and why, so odd AI
+// behaviour can be traced back to the context it was given
+// ('mur context --explain-last', the dashboard's Injections page).
+type Explanation struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`           // "context", "search --inject", or "run --context-from"
+	Session string    `json:"session,omitempty"` // AI tool session, when the hook passes it
+	Target  string    `json:"target,omitempty"`
+	Profile string    `json:"profile,omitempty"` // context profile in effect
+	Dir     string    `json:"dir,omitempty"`
+	Prompt  string    `json:"prompt,omitempty"` // truncated
+	Project Project   `json:"project"`
+	// Method is how relevance was scored: "semantic", "keyword",
+	// "cached" (a semantic result reused while embeddings are down), or
+	// "manual" (chosen with 'mur run --context-from')
+	Method   string     `json:"method"`
+	Max      int        `json:"max"`                    // relevance-ranked patterns allowed
+	Pinned   int        `json:"pinned_budget"`          // pinned patterns allowed
+	Budget   int        `json:"token_budget,omitempty"` // token limit, if any
+	Tokens   int        `json:"tokens,omitempty"`       // estimated tokens injected
+	Patterns []Decision `json:"patterns"`
+}
+
+// Project is the detected project context an injection matched against.
+type Project struct {
+	Type       string   `json:"type,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Remote     string   `json:"remote,omitempty"`
+	Languages  []string `json:"languages,omitempty"`
+	Frameworks []string `json:"frameworks,omitempty"`
+}
+
+// Decision is the outcome for one candidate pattern.
+type Decision struct {
+	Name     string   `json:"name"`
+	Injected bool     `json:"injected"`
+	Pinned   bool     `json:"pinned,omitempty"`
+	Score    float64  `json:"score,omitempty"`   // similarity (semantic) or relevance (keyword)
+	Matched  []string `json:"matched,omitempty"` // project tags, languages, and keywords that matched
+	Reason   string   `json:"reason"`
+	// Summarized is set when long content was cut to a summary to stay
+	// within the token budget
+	Summarized bool `json:"summarized,omitempty"`
+}
+
+// Decision reasons.
+const (
+	ReasonPinned        = "pinned"
+	ReasonRelevant      = "relevant"
+	ReasonBelowScore    = "below similarity threshold"
+	ReasonNotTopN       = "outside the top candidates"
+	ReasonOverMax       = "over the pattern limit"
+	ReasonPinnedBudget  = "over the pinned budget"
+	ReasonBlocked       = "blocked: high injection risk"
+	ReasonNotApplicable = "applies to other projects or languages"
+	ReasonProfile       = "left out by the context profile"
+	ReasonSelected      = "selected with --context-from"
+	ReasonTokenBudget   = "over the token budget"
+)
+
+// NewExplanation starts an explanation for an injection by command.
+func NewExplanation(command string) *Explanation {
+	return &Explanation{Time: time.Now().UTC(), Command: command}
+}
+
+// SetPrompt records a preview of the prompt.
+func (e *Explanation) SetPrompt(prompt string) {
+	if len(prompt) > 200 {
+		prompt = prompt[:200] + "..."
+	}
+	e.Prompt = prompt
+}
+
+// SetProject records the detected project context.
+func (e *Explanation) SetProject(ctx *ProjectContext) {
+	if ctx == nil {
+		return
+	}
+	e.Dir = ctx.RootDir
+	e.Project = Project{
+		Type:       ctx.ProjectType,
+		Name:       ctx.ProjectName,
+		Remote:     ctx.Remote,
+		Languages:  ctx.Languages,
+		Frameworks: ctx.Frameworks,
+	}
+}
+
+// Add records a decision, replacing an earlier one for the same pattern.
+func (e *Explanation) Add(d Decision) {
+	if e == nil {
+		return
+	}
+	for i := range e.Patterns {
+		if e.Patterns[i].Name == d.Name {
+			if d.Matched == nil {
+				d.Matched = e.Patterns[i].Matched
+			}
+			if d.Score == 0 {
+				d.Score = e.Patterns[i].Score
+			}
+			e.Patterns[i] = d
+			return
+		}
+	}
+	e.Patterns = append(e.Patterns, d)
+}
+
+// Drop marks an injected pattern as left out for reason.
+func (e *Explanation) Drop(name, reason string) {
+	if e == nil {
+		return
+	}
+	for i := range e.Patterns {
+		if e.Patterns[i].Name == name {
+			e.Patterns[i].Injected = false
+			e.Patterns[i].Reason = reason
+			return
+		}
+	}
+}
+
+func (e *Explanation) isInjected(name string) bool {
+	for _, d := range e.Patterns {
+		if d.Name == name {
+			return d.Injected
+		}
+	}
+	return false
+}
+
+// SetBudget records the token budget and what fitting the context into
+// it did.
+func (e *Explanation) SetBudget(budget int, fit BudgetFit) {
+	if e == nil {
+		return
+	}
+	e.Budget, e.Tokens = budget, fit.Tokens
+	for _, name := range fit.Dropped {
+		e.Drop(name, ReasonTokenBudget)
+	}
+	for i := range e.Patterns {
+		if slices.Contains(fit.Summarized, e.Patterns[i].Name) {
+			e.Patterns[i].Summarized = true
+		}
+	}
+}
+
+// Injected returns the decisions for patterns that were injected.
+func (e *Explanation) Injected() []Decision {
+	var out []Decision
+	for _, d := range e.Patterns {
+		if d.Injected {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// matchReasons lists what about the project and prompt made p relevant.
+func matchReasons(p *pattern.Pattern, ctx *ProjectContext, promptLower string) []string {
+	var matched []string
+	add := func(s string) {
+		for _, m := range matched {
+			if m == s {
+				return
+			}
+		}
+		matched = append(matched, s)
+	}
+
+	for _, tag := range p.Tags.Confirmed {
+		tagLower := strings.ToLower(tag)
+		if ctx.ProjectType != "" && strings.Contains(tagLower, ctx.ProjectType) {
+			add("tag:" + tag)
+		}
+		for _, v := range append(append([]string{}, ctx.Languages...), ctx.Frameworks...) {
+			if strings.Contains(tagLower, strings.ToLower(v)) {
+				add("tag:" + tag)
+			}
+		}
+	}
+	for _, kw := range p.Applies.Keywords {
+		if promptLower != "" && strings.Contains(promptLower, strings.ToLower(kw)) {
+			add("keyword:" + kw)
+		}
+	}
+	for _, lang := range p.Applies.Languages {
+		for _, ctxLang := range ctx.Languages {
+			if pattern.MatchApplies(lang, ctxLang) {
+				add("language:" + lang)
+			}
+		}
+	}
+	for _, fw := range p.Applies.Frameworks {
+		for _, ctxFw := range ctx.Frameworks {
+			if strings.EqualFold(fw, ctxFw) {
+				add("framework:" + fw)
+			}
+		}
+	}
+	if proj, ok := p.Applies.MatchProject(ctx.Target().Project); ok {
+		add("project:" + proj)
+	}
+	return matched
+}
+
+// MatchReasons is matchReasons for callers outside the injector, such as
+// 'mur search --inject'.
+func MatchReasons(p *pattern.Pattern, ctx *ProjectContext, prompt string) []string {
+	if ctx == nil {
+		return nil
+	}
+	return matchReasons(p, ctx, strings.ToLower(prompt))
+}
+
+// DetectProject returns the project context for workDir.
+func DetectProject(workDir string) *ProjectContext {
+	return (&Injector{}).detectContext(workDir)
+}
+
+// The explanation log is cut back to the most recent maxExplanations
+// records whenever it grows past maxExplanationBytes.
+const (
+	maxExplanations     = 200
+	maxExplanationBytes = 1 << 20
+)
+
+// explanationsPath returns where explanations are logged.
+func explanationsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.StateDir(home), "injections.jsonl"), nil
+}
+
+// RecordExplanation appends e to the explanation log.
+func RecordExplanation(e *Explanation) error {
+	path, err := explanationsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open injection log: %w", err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("cannot write injection log: %w", err)
+	}
+
+	// Trim only once the log grows large, so most writes just append
+	if info, err := os.Stat(path); err != nil || info.Size() < maxExplanationBytes {
+		return nil
+	}
+	lines, err := readLines(path)
+	if err != nil || len(lines) <= maxExplanations {
+		return err
+	}
+	keep := strings.Join(lines[len(lines)-maxExplanations:], "\n") + "\n"
+	return os.WriteFile(path, []byte(keep), 0644)
+}
+
+// RecentExplanations returns up to n explanations, newest first.
+func RecentExplanations(n int) ([]Explanation, error) {
+	path, err := explanationsPath()
+	if err != nil {
+		return nil, err
+	}
+	lines, err := readLines(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Explanation
+	for i := len(lines) - 1; i >= 0 && len(out) < n; i-- {
+		var e Explanation
+		if json.Unmarshal([]byte(lines[i]), &e) == nil {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,99 @@
+package inject
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestLimitRecordsOverMax(t *testing.T) {
+	ex := &Explanation{}
+	r := &InjectionResult{Explanation: ex}
+	for _, name := range []string{"checklist", "a", "b", "c"} {
+		p := &pattern.Pattern{Name: name, Pinned: name == "checklist"}
+		r.Patterns = append(r.Patterns, p)
+		reason := ReasonRelevant
+		if p.Pinned {
+			reason = ReasonPinned
+		}
+		ex.Add(Decision{Name: name, Injected: true, Pinned: p.Pinned, Score: 0.5, Reason: reason})
+	}
+
+	r.Limit(2)
+
+	if ex.Max != 2 {
+		t.Errorf("Max = %d, want 2", ex.Max)
+	}
+	var injected []string
+	for _, d := range ex.Injected() {
+		injected = append(injected, d.Name)
+	}
+	if fmt.Sprint(injected) != "[checklist a b]" {
+		t.Errorf("injected = %v, want [checklist a b]", injected)
+	}
+	last := ex.Patterns[3]
+	if last.Name != "c" || last.Injected || last.Reason != ReasonOverMax || last.Score != 0.5 {
+		t.Errorf("dropped decision = %+v", last)
+	}
+}
+
+func TestAddKeepsScoreAndMatches(t *testing.T) {
+	ex := &Explanation{}
+	ex.Add(Decision{Name: "a", Score: 0.7, Matched: []string{"tag:go"}, Reason: ReasonRelevant, Injected: true})
+	ex.Add(Decision{Name: "a", Pinned: true, Reason: ReasonPinned, Injected: true})
+
+	if len(ex.Patterns) != 1 {
+		t.Fatalf("got %d decisions, want 1", len(ex.Patterns))
+	}
+	d := ex.Patterns[0]
+	if !d.Pinned || d.Score != 0.7 || len(d.Matched) != 1 || d.Reason != ReasonPinned {
+		t.Errorf("merged decision = %+v", d)
+	}
+}
+
+func TestRecordExplanation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("MUR_HOME", dir)
+
+	if got, err := RecentExplanations(5); err != nil || len(got) != 0 {
+		t.Fatalf("empty log = %v, %v", got, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		ex := NewExplanation("context")
+		ex.Session = fmt.Sprintf("s%d", i)
+		ex.Add(Decision{Name: "p", Injected: true, Reason: ReasonRelevant})
+		if err := RecordExplanation(ex); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := RecentExplanations(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Session != "s2" || got[1].Session != "s1" {
+		t.Errorf("RecentExplanations(2) = %+v, want s2, s1", got)
+	}
+	if len(got[0].Patterns) != 1 || got[0].Patterns[0].Reason != ReasonRelevant {
+		t.Errorf("decisions not round-tripped: %+v", got[0].Patterns)
+	}
+}
+
+func TestMatchReasons(t *testing.T) {
+	p := &pattern.Pattern{
+		Tags:    pattern.TagSet{Confirmed: []string{"golang", "docker"}},
+		Applies: pattern.ApplyConditions{Languages: []string{"Go"}, Keywords: []string{"flaky"}},
+	}
+	ctx := &ProjectContext{ProjectType: "go", Languages: []string{"go"}}
+
+	got := fmt.Sprint(MatchReasons(p, ctx, "Fix the FLAKY test"))
+	if got != "[tag:golang keyword:flaky language:Go]" {
+		t.Errorf("MatchReasons = %s", got)
+	}
+	if MatchReasons(p, nil, "flaky") != nil {
+		t.Error("no project context should match nothing")
+	}
+}
@@ -0,0 +1,212 @@
+package inject
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// Search degrades to these when the embedding provider can't be reached.
+const (
+	// keywordMinScore is the least a keyword match must score to be used.
+	keywordMinScore = 0.3
+	// maxCachedResults bounds the last-known-good result cache.
+	maxCachedResults = 200
+	// cachedResultTTL is how long a cached result stays usable.
+	cachedResultTTL = 14 * 24 * time.Hour
+)
+
+// keywordStopwords are prompt words too common to match patterns on.
+var keywordStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "this": true, "that": true,
+	"from": true, "into": true, "how": true, "what": true, "why": true, "can": true,
+	"you": true, "please": true, "should": true, "would": true, "could": true,
+	"use": true, "using": true, "make": true, "add": true, "fix": true, "get": true,
+	"all": true, "are": true, "not": true, "but": true, "have": true, "when": true,
+}
+
+// promptWords returns the distinct words of a prompt worth matching on.
+func promptWords(prompt string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(prompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) >= 3 && !keywordStopwords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// KeywordSearch matches patterns to a prompt by their trigger keywords,
+// tags and name and description words, for when semantic search is
+// unavailable. Matches come best first with a 0-1 score.
+func KeywordSearch(patterns []pattern.Pattern, prompt string, topK int) []embed.PatternMatch {
+	promptLower := strings.ToLower(prompt)
+	words := promptWords(prompt)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var matches []embed.PatternMatch
+	for i := range patterns {
+		p := &patterns[i]
+		if !p.IsActive() {
+			continue
+		}
+		var score float64
+		for _, kw := range p.Applies.Keywords {
+			if kw = strings.ToLower(kw); kw != "" && strings.Contains(promptLower, kw) {
+				score += 0.4
+			}
+		}
+		for _, tag := range p.Tags.Confirmed {
+			if words[strings.ToLower(tag)] {
+				score += 0.3
+			}
+		}
+		for w := range promptWords(strings.ReplaceAll(p.Name, "-", " ")) {
+			if words[w] {
+				score += 0.25
+			}
+		}
+		var desc float64
+		for w := range promptWords(p.Description) {
+			if words[w] {
+				desc += 0.1
+			}
+		}
+		score += min(desc, 0.3)
+
+		if score >= keywordMinScore {
+			pCopy := *p
+			score = min(score, 1)
+			matches = append(matches, embed.PatternMatch{Pattern: &pCopy, Score: score, Confidence: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches
+}
+
+// CachedResult is a semantic search result remembered for its prompt.
+type CachedResult struct {
+	Names  []string  `json:"names"`
+	Scores []float64 `json:"scores"`
+	At     time.Time `json:"at"`
+}
+
+// ResultCache keeps the last good semantic search result per prompt, so
+// an identical prompt still finds its patterns while the embedding
+// provider is down.
+type ResultCache struct {
+	path    string
+	Results map[string]CachedResult `json:"results"` // by prompt hash
+}
+
+// ResultCachePath returns the last-known-good search result cache.
+func ResultCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.CacheDir(home), "search-results.json"), nil
+}
+
+// LoadResultCache reads the result cache; a missing or unreadable cache
+// is empty.
+func LoadResultCache() *ResultCache {
+	c := &ResultCache{Results: make(map[string]CachedResult)}
+	path, err := ResultCachePath()
+	if err != nil {
+		return c
+	}
+	c.path = path
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, c)
+	}
+	if c.Results == nil {
+		c.Results = make(map[string]CachedResult)
+	}
+	return c
+}
+
+func promptKey(prompt string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(strings.ToLower(prompt)), " ")))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Get returns the cached result for prompt, if there is a recent one.
+func (c *ResultCache) Get(prompt string) (CachedResult, bool) {
+	r, ok := c.Results[promptKey(prompt)]
+	if !ok || time.Since(r.At) > cachedResultTTL {
+		return CachedResult{}, false
+	}
+	return r, true
+}
+
+// Put remembers the result of a successful search for prompt and saves
+// the cache. It only writes when the result changed.
+func (c *ResultCache) Put(prompt string, matches []embed.PatternMatch) error {
+	r := CachedResult{At: time.Now()}
+	for _, m := range matches {
+		r.Names = append(r.Names, m.Pattern.Name)
+		r.Scores = append(r.Scores, m.Score)
+	}
+	key := promptKey(prompt)
+	if old, ok := c.Results[key]; ok && slices.Equal(old.Names, r.Names) && time.Since(old.At) < cachedResultTTL/2 {
+		return nil
+	}
+	c.Results[key] = r
+	for len(c.Results) > maxCachedResults {
+		oldest := ""
+		for k, v := range c.Results {
+			if oldest == "" || v.At.Before(c.Results[oldest].At) {
+				oldest = k
+			}
+		}
+		delete(c.Results, oldest)
+	}
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// Matches resolves a cached result against the store, skipping patterns
+// that are gone or no longer active.
+func (r CachedResult) Matches(store *pattern.Store) []embed.PatternMatch {
+	var matches []embed.PatternMatch
+	for i, name := range r.Names {
+		p, err := store.Get(name)
+		if err != nil || !p.IsActive() {
+			continue
+		}
+		score := 0.0
+		if i < len(r.Scores) {
+			score = r.Scores[i]
+		}
+		matches = append(matches, embed.PatternMatch{Pattern: p, Score: score, Confidence: score})
+	}
+	return matches
+}
@@ -0,0 +1,82 @@
+package inject
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestKeywordSearch(t *testing.T) {
+	patterns := []pattern.Pattern{
+		{Name: "go-error-wrapping", Description: "Wrap errors with %w", Tags: pattern.TagSet{Confirmed: []string{"go"}}},
+		{Name: "swift-async-tests", Applies: pattern.ApplyConditions{Keywords: []string{"xctest"}}},
+		{Name: "docker-layer-cache", Description: "Order Dockerfile steps for caching"},
+		{Name: "go-error-retired", Lifecycle: pattern.LifecycleMeta{Status: pattern.StatusDeprecated}},
+	}
+
+	got := KeywordSearch(patterns, "Why does wrapping this Go error lose the stack?", 5)
+	if len(got) != 1 || got[0].Pattern.Name != "go-error-wrapping" {
+		t.Fatalf("matches = %v, want go-error-wrapping", names(got))
+	}
+	if got[0].Score <= 0 || got[0].Score > 1 {
+		t.Errorf("score = %v, want 0-1", got[0].Score)
+	}
+
+	if got := KeywordSearch(patterns, "my XCTest hangs on an async call", 5); len(got) != 1 || got[0].Pattern.Name != "swift-async-tests" {
+		t.Errorf("trigger keyword matches = %v", names(got))
+	}
+	if got := KeywordSearch(patterns, "please fix this", 5); len(got) != 0 {
+		t.Errorf("stopword-only prompt matched %v", names(got))
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	store := pattern.NewStore(t.TempDir()).WithCompression(0)
+	for _, name := range []string{"keep", "gone"} {
+		if err := store.Create(&pattern.Pattern{Name: name, Content: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keep, _ := store.Get("keep")
+	gone, _ := store.Get("gone")
+
+	c := LoadResultCache()
+	if _, ok := c.Get("deploy to staging"); ok {
+		t.Fatal("empty cache has a result")
+	}
+	if err := c.Put("deploy to staging", []embed.PatternMatch{{Pattern: keep, Score: 0.8}, {Pattern: gone, Score: 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Purge("gone")
+
+	// Reloaded, and the same prompt up to case and spacing
+	r, ok := LoadResultCache().Get("  Deploy to   staging ")
+	if !ok {
+		t.Fatal("cached result not found")
+	}
+	matches := r.Matches(store)
+	if len(matches) != 1 || matches[0].Pattern.Name != "keep" || matches[0].Score != 0.8 {
+		t.Errorf("matches = %v", names(matches))
+	}
+
+	c = LoadResultCache()
+	key := promptKey("deploy to staging")
+	stale := c.Results[key]
+	stale.At = time.Now().Add(-cachedResultTTL - time.Hour)
+	c.Results[key] = stale
+	if _, ok := c.Get("deploy to staging"); ok {
+		t.Error("expired result used")
+	}
+}
+
+func names(matches []embed.PatternMatch) []string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, m.Pattern.Name)
+	}
+	return out
+}
@@ -0,0 +1,213 @@
+package inject
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/kit"
+)
+
+// Built-in context output formats.
+const (
+	FormatText        = "text"         // Plain text (default)
+	FormatMarkdown    = "markdown"     // Markdown rules, e.g. for Cursor
+	FormatXML         = "xml"          // XML-tagged sections, e.g. for Claude
+	FormatClaudeSkill = "claude-skill" // Skill-style frontmatter preamble
+)
+
+// defaultTargetFormats maps injection targets to the format they parse best.
+// Only used when a target is given and no format is configured for it.
+var defaultTargetFormats = map[string]string{
+	"claude":    FormatXML,
+	"cursor":    FormatMarkdown,
+	TargetPaste: FormatMarkdown,
+}
+
+// TargetPaste is the injection target for context pasted by hand into
+// tools without hooks (web chats, IDE chat panels).
+const TargetPaste = "paste"
+
+// PastePreamble introduces pasted context, so a chat without a system
+// prompt knows what the block is and doesn't reply to it.
+const PastePreamble = "Context from mur: patterns learned in my previous sessions. Apply them where relevant; no need to acknowledge them.\n\n"
+
+// FormatPattern is a pattern as seen by context templates.
+type FormatPattern struct {
+	Name        string
+	Description string
+	Content     string
+	Community   bool
+	Pinned      bool
+}
+
+// FormatData is the input to a context template.
+type FormatData struct {
+	Project     string
+	ProjectType string
+	Compact     bool // names only
+	Patterns    []FormatPattern
+	Hints       []string // extra one-line suggestions
+}
+
+// Names returns the pattern names, marking pinned and community patterns.
+func (d FormatData) Names() []string {
+	names := make([]string, 0, len(d.Patterns))
+	for _, p := range d.Patterns {
+		switch {
+		case p.Pinned:
+			names = append(names, p.Name+" 📌")
+		case p.Community:
+			names = append(names, p.Name+" 🌐")
+		default:
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+var builtinTemplates = map[string]string{
+	FormatText: `{{if .Compact}}[mur] Relevant patterns: {{join .Names ", "}}
+{{range .Hints}}[mur] 💡 {{.}}
+{{end}}{{else}}
+─── Relevant Patterns (mur) ───
+{{if .ProjectType}}Project: {{.Project}} ({{.ProjectType}})
+{{end}}
+{{range .Patterns}}## {{.Name}}{{if .Pinned}} 📌{{end}}
+{{if .Description}}*{{.Description}}*
+{{end}}{{.Content}}
+
+{{end}}{{range .Hints}}💡 {{.}}
+{{end}}────────────────────────────────
+
+{{end}}`,
+
+	FormatMarkdown: `{{if .Compact}}- **Relevant patterns (mur):** {{join .Names ", "}}
+{{range .Hints}}- {{.}}
+{{end}}{{else}}# Relevant Patterns (mur)
+{{if .ProjectType}}
+Project: **{{.Project}}** ({{.ProjectType}})
+{{end}}{{range .Patterns}}
+## {{.Name}}{{if .Pinned}} 📌{{end}}
+{{if .Description}}
+> {{.Description}}
+{{end}}
+{{.Content}}
+{{end}}{{if .Hints}}
+{{range .Hints}}- {{.}}
+{{end}}{{end}}{{end}}`,
+
+	FormatXML: `{{if .Compact}}<mur_patterns>{{join .Names ", "}}</mur_patterns>
+{{range .Hints}}<mur_hint>{{.}}</mur_hint>
+{{end}}{{else}}<mur_patterns{{if .ProjectType}} project="{{attr .Project}}" type="{{attr .ProjectType}}"{{end}}>
+{{range .Patterns}}<pattern name="{{attr .Name}}"{{if .Pinned}} pinned="true"{{end}}{{if .Community}} source="community"{{end}}>
+{{if .Description}}<description>{{.Description}}</description>
+{{end}}<content>
+{{.Content}}
+</content>
+</pattern>
+{{end}}{{range .Hints}}<mur_hint>{{.}}</mur_hint>
+{{end}}</mur_patterns>
+{{end}}`,
+
+	FormatClaudeSkill: `---
+name: mur-context
+description: Patterns learned from previous sessions{{if .ProjectType}} that apply to {{.Project}} ({{.ProjectType}}){{end}}
+---
+
+# mur context
+
+Apply these patterns when they are relevant to the current task; ignore them otherwise.
+{{if .Compact}}
+Relevant patterns: {{join .Names ", "}}
+{{else}}{{range .Patterns}}
+## {{.Name}}{{if .Pinned}} (always apply){{end}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{.Content}}
+{{end}}{{end}}{{range .Hints}}
+- {{.}}{{end}}
+`,
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"attr": html.EscapeString,
+}
+
+// BuiltinFormats returns the names of the built-in context formats.
+func BuiltinFormats() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TemplatesDir returns the directory for user template overrides
+// (~/.mur/templates/context/<format>.tmpl).
+func TemplatesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.DataDir(home), "templates", "context"), nil
+}
+
+// ResolveFormat picks the output format: an explicit format wins, then the
+// configured per-target format, then the built-in target default, then the
+// configured default, then text.
+func ResolveFormat(cfg *config.Config, format, target string) string {
+	if format != "" {
+		return format
+	}
+	if target != "" {
+		if f := cfg.Context.Targets[target]; f != "" {
+			return f
+		}
+	}
+	if cfg.Context.Format != "" {
+		return cfg.Context.Format
+	}
+	if f := defaultTargetFormats[target]; f != "" {
+		return f
+	}
+	return FormatText
+}
+
+// Render formats data with the named format. A user template at
+// TemplatesDir()/<format>.tmpl overrides the built-in one and may also
+// define entirely new formats; <kit>/<template> names a template from an
+// installed kit.
+func Render(format string, data FormatData) (string, error) {
+	src, ok := builtinTemplates[format]
+	if dir, err := TemplatesDir(); err == nil {
+		if custom, err := os.ReadFile(filepath.Join(dir, format+".tmpl")); err == nil {
+			src, ok = string(custom), true
+		}
+	}
+	if !ok {
+		src, ok = kit.Template(format)
+	}
+	if !ok {
+		return "", fmt.Errorf("unknown format %q (built-in: %s)", format, strings.Join(BuiltinFormats(), ", "))
+	}
+
+	tmpl, err := template.New(format).Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", format, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", format, err)
+	}
+	return sb.String(), nil
+}
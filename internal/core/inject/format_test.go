@@ -0,0 +1,110 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func testFormatData() FormatData {
+	return FormatData{
+		Project:     "mur-core",
+		ProjectType: "go",
+		Patterns: []FormatPattern{
+			{Name: "go-errors", Description: "Wrap errors with %w", Content: "Use fmt.Errorf", Pinned: true},
+			{Name: "retry & backoff", Content: "Retry with jitter", Community: true},
+		},
+	}
+}
+
+func TestRenderBuiltinFormats(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatText, []string{"─── Relevant Patterns (mur) ───", "Project: mur-core (go)", "## go-errors 📌", "*Wrap errors with %w*"}},
+		{FormatMarkdown, []string{"# Relevant Patterns (mur)", "## go-errors", "> Wrap errors with %w"}},
+		{FormatXML, []string{`<mur_patterns project="mur-core" type="go">`, `<pattern name="go-errors" pinned="true">`, `<pattern name="retry &amp; backoff" source="community">`, "<content>\nUse fmt.Errorf\n</content>"}},
+		{FormatClaudeSkill, []string{"name: mur-context", "apply to mur-core (go)", "## go-errors"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			out, err := Render(tt.format, testFormatData())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("output missing %q:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCompactText(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+
+	data := testFormatData()
+	data.Compact = true
+	out, err := Render(FormatText, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "[mur] Relevant patterns: go-errors 📌, retry & backoff 🌐\n" {
+		t.Errorf("unexpected compact output: %q", out)
+	}
+}
+
+func TestRenderUserTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MUR_HOME", home)
+
+	dir := filepath.Join(home, "templates", "context")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "rules.tmpl"), []byte(`{{range .Patterns}}RULE {{.Name}}
+{{end}}`), 0644)
+
+	out, err := Render("rules", testFormatData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "RULE go-errors\nRULE retry & backoff\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if _, err := Render("nope", testFormatData()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	cfg := &config.Config{}
+	if got := ResolveFormat(cfg, "", ""); got != FormatText {
+		t.Errorf("default = %s, want text", got)
+	}
+	if got := ResolveFormat(cfg, "", "claude"); got != FormatXML {
+		t.Errorf("claude default = %s, want xml", got)
+	}
+	if got := ResolveFormat(cfg, "", TargetPaste); got != FormatMarkdown {
+		t.Errorf("paste default = %s, want markdown", got)
+	}
+
+	cfg.Context.Format = FormatMarkdown
+	cfg.Context.Targets = map[string]string{"claude": FormatClaudeSkill}
+	if got := ResolveFormat(cfg, "", "claude"); got != FormatClaudeSkill {
+		t.Errorf("configured target = %s, want claude-skill", got)
+	}
+	if got := ResolveFormat(cfg, "", "gemini"); got != FormatMarkdown {
+		t.Errorf("configured default = %s, want markdown", got)
+	}
+	if got := ResolveFormat(cfg, FormatXML, "claude"); got != FormatXML {
+		t.Errorf("explicit flag = %s, want xml", got)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mur-run/mur-core/internal/cache"
+	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/core/audit"
 	"github.com/mur-run/mur-core/internal/core/classifier"
 	"github.com/mur-run/mur-core/internal/core/embed"
@@ -18,9 +20,16 @@ import (
 	"github.com/mur-run/mur-core/internal/security"
 )
 
+// DefaultPinnedBudget is the maximum number of pinned patterns injected
+// alongside the relevance-ranked ones.
+const DefaultPinnedBudget = 3
+
+// maxRelevant is how many relevance-ranked patterns Inject returns.
+const maxRelevant = 5
+
 // InjectionResult holds the result of pattern injection.
 type InjectionResult struct {
-	// Patterns to inject
+	// Patterns to inject, pinned ones first
 	Patterns []*pattern.Pattern
 	// Formatted prompt with patterns
 	FormattedPrompt string
@@ -30,6 +39,10 @@ type InjectionResult struct {
 	Classifications []classifier.DomainScore
 	// Patterns that were blocked by injection scanning
 	BlockedPatterns []BlockedPattern
+	// Why each candidate pattern was or wasn't injected
+	Explanation *Explanation
+	// Patterns were chosen by the user, not by search
+	Manual bool
 }
 
 // BlockedPattern records a pattern that was blocked by the injection scanner.
@@ -47,6 +60,9 @@ type ProjectContext struct {
 	ProjectType string
 	// Project name (from go.mod, package.json, etc.)
 	ProjectName string
+	// Git remote of the project as host/owner/repo, e.g.
+	// github.com/acme/billing-service
+	Remote string
 	// Current file being worked on
 	CurrentFile string
 	// Languages detected
@@ -55,6 +71,20 @@ type ProjectContext struct {
 	Frameworks []string
 }
 
+// Target returns what a pattern's applies conditions are matched against.
+func (c *ProjectContext) Target() pattern.Target {
+	if c == nil {
+		return pattern.Target{}
+	}
+	var ids []string
+	for _, id := range []string{c.ProjectName, c.Remote, filepath.ToSlash(c.RootDir)} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return pattern.Target{Project: ids, Languages: c.Languages}
+}
+
 // Injector handles pattern injection based on context.
 type Injector struct {
 	store            *pattern.Store
@@ -63,6 +93,9 @@ type Injector struct {
 	cache            *cache.MemoryCache         // Optional in-process cache
 	injectionScanner *security.InjectionScanner // Injection scanner
 	auditLogger      *audit.Logger              // Optional audit logger
+	pinnedBudget     int                        // Max pinned patterns per injection
+	profile          *Profile                   // Optional context profile
+	techStack        []string                   // Optional tech_stack from config
 }
 
 // NewInjector creates a new pattern injector.
@@ -71,9 +104,37 @@ func NewInjector(store *pattern.Store) *Injector {
 		store:            store,
 		classifier:       classifier.NewHybridClassifier(),
 		injectionScanner: security.NewInjectionScanner(),
+		pinnedBudget:     DefaultPinnedBudget,
 	}
 }
 
+// PinnedBudget returns the configured pinned budget, or the default.
+// A negative value disables pinned injection.
+func PinnedBudget(cfg *config.Config) int {
+	if cfg.Context.PinnedBudget != 0 {
+		return cfg.Context.PinnedBudget
+	}
+	return DefaultPinnedBudget
+}
+
+// WithTechStack sets the user's tech_stack. Outside a detected project,
+// patterns for those languages and tools rank higher.
+func (inj *Injector) WithTechStack(stack []string) {
+	inj.techStack = stack
+}
+
+// WithPinnedBudget sets how many pinned patterns are always injected.
+// Pinned patterns don't count towards the relevance limit.
+func (inj *Injector) WithPinnedBudget(n int) {
+	inj.pinnedBudget = n
+}
+
+// WithProfile limits injection to what the context profile allows and adds
+// its pinned patterns. Budgets are left to the caller.
+func (inj *Injector) WithProfile(p *Profile) {
+	inj.profile = p
+}
+
 // WithAuditLogger attaches an audit logger to the injector.
 func (inj *Injector) WithAuditLogger(logger *audit.Logger) {
 	inj.auditLogger = logger
@@ -99,6 +160,12 @@ func (inj *Injector) WithSemanticSearch(cfg embed.Config) error {
 func (inj *Injector) Inject(prompt string, workDir string) (*InjectionResult, error) {
 	// 1. Detect project context
 	ctx := inj.detectContext(workDir)
+	ex := &Explanation{Time: time.Now().UTC(), Max: maxRelevant, Pinned: max(inj.pinnedBudget, 0)}
+	ex.SetProject(ctx)
+	ex.SetPrompt(prompt)
+	if inj.profile != nil {
+		ex.Profile = inj.profile.Name
+	}
 
 	// 2. Classify the prompt + context
 	classInput := classifier.ClassifyInput{
@@ -114,11 +181,29 @@ func (inj *Injector) Inject(prompt string, workDir string) (*InjectionResult, er
 	classifications := inj.classifier.Classify(classInput)
 
 	// 3. Find matching patterns
-	patterns, err := inj.findMatchingPatterns(ctx, classifications, prompt)
+	patterns, err := inj.findMatchingPatterns(ctx, classifications, prompt, ex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find patterns: %w", err)
 	}
 
+	// 3b. Pinned patterns go first, whatever their relevance
+	pinned, err := inj.findPinnedPatterns(ctx, classifications, prompt, ex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pinned patterns: %w", err)
+	}
+	if len(pinned) > 0 {
+		seen := make(map[string]bool, len(pinned))
+		for _, p := range pinned {
+			seen[p.Name] = true
+		}
+		for _, p := range patterns {
+			if !seen[p.Name] {
+				pinned = append(pinned, p)
+			}
+		}
+		patterns = pinned
+	}
+
 	// 4. Scan patterns for injection attacks and filter out high-risk ones
 	var safePatterns []*pattern.Pattern
 	var blocked []BlockedPattern
@@ -132,6 +217,7 @@ func (inj *Injector) Inject(prompt string, workDir string) (*InjectionResult, er
 				Risk:     risk,
 				Findings: findings,
 			})
+			ex.Drop(p.Name, ReasonBlocked)
 			continue
 		}
 		safePatterns = append(safePatterns, p)
@@ -161,6 +247,7 @@ func (inj *Injector) Inject(prompt string, workDir string) (*InjectionResult, er
 		Context:         ctx,
 		Classifications: classifications,
 		BlockedPatterns: blocked,
+		Explanation:     ex,
 	}, nil
 }
 
@@ -204,14 +291,21 @@ func (inj *Injector) detectContext(workDir string) *ProjectContext {
 		ctx.ProjectName = info.name
 		ctx.Languages = append(ctx.Languages, "python")
 		ctx.Frameworks = append(ctx.Frameworks, info.frameworks...)
+	} else if name, ok := detectRustProject(ctx.RootDir); ok {
+		ctx.ProjectType = "rust"
+		ctx.ProjectName = name
+		ctx.Languages = append(ctx.Languages, "rust")
 	}
+	ctx.Remote = detectGitRemote(ctx.RootDir)
 
 	return ctx
 }
 
 // findMatchingPatterns finds patterns that match the context and classifications.
-func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classifier.DomainScore, prompt string) ([]*pattern.Pattern, error) {
-	maxPatterns := 5
+func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classifier.DomainScore, prompt string, ex *Explanation) ([]*pattern.Pattern, error) {
+	maxPatterns := maxRelevant
+	promptLower := strings.ToLower(prompt)
+	target := ctx.Target()
 
 	// Try semantic search first if available
 	if inj.searcher != nil {
@@ -225,16 +319,33 @@ func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classif
 
 		matches, err := inj.searcher.SearchWithContext(prompt, searchCtx, maxPatterns)
 		if err == nil && len(matches) > 0 {
-			// Use semantic results
-			result := make([]*pattern.Pattern, 0, len(matches))
+			// Use semantic results, after the project's own patterns,
+			// which aren't in the embedding index
+			ex.Method = "semantic"
+			result, shadowed := inj.projectMatches(ctx, classes, promptLower, ex)
 			for _, m := range matches {
+				if shadowed[m.Pattern.Name] {
+					continue
+				}
+				if !m.Pattern.AppliesTo(target) {
+					ex.Add(Decision{Name: m.Pattern.Name, Score: m.Confidence, Reason: ReasonNotApplicable})
+					continue
+				}
+				if !inj.profile.Allows(m.Pattern) {
+					ex.Add(Decision{Name: m.Pattern.Name, Score: m.Confidence, Reason: ReasonProfile})
+					continue
+				}
+				d := Decision{Name: m.Pattern.Name, Score: m.Confidence, Matched: matchReasons(m.Pattern, ctx, promptLower), Reason: ReasonBelowScore}
 				if m.Confidence > 0.3 { // Minimum semantic threshold
 					result = append(result, m.Pattern)
+					d.Injected, d.Reason = true, ReasonRelevant
 				}
+				ex.Add(d)
 			}
 			if len(result) > 0 {
 				return result, nil
 			}
+			ex.Patterns = nil
 		}
 		// Fall through to keyword matching if semantic fails
 	}
@@ -247,11 +358,27 @@ func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classif
 	}
 
 	var scored []scoredPattern
-	promptLower := strings.ToLower(prompt)
+	ex.Method = "keyword"
 
 	if inj.cache != nil {
-		// Read from in-process cache (no disk I/O)
+		// Read from in-process cache (no disk I/O), which only holds
+		// global patterns
+		project := inj.store.ListProject()
+		shadowed := make(map[string]bool, len(project))
+		for _, p := range project {
+			shadowed[p.Name] = true
+			if !p.IsActive() || !p.AppliesTo(target) || !inj.profile.Allows(&p) {
+				continue
+			}
+			score := inj.scorePattern(&p, ctx, classes, promptLower)
+			if score > 0.1 {
+				scored = append(scored, scoredPattern{p, score})
+			}
+		}
 		for _, p := range inj.cache.Patterns.Active() {
+			if shadowed[p.Name] || !p.AppliesTo(target) || !inj.profile.Allows(p) {
+				continue
+			}
 			score := inj.scorePattern(p, ctx, classes, promptLower)
 			if score > 0.1 {
 				scored = append(scored, scoredPattern{*p, score})
@@ -263,7 +390,7 @@ func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classif
 			return nil, err
 		}
 		for _, p := range allPatterns {
-			if !p.IsActive() {
+			if !p.IsActive() || !p.AppliesTo(target) || !inj.profile.Allows(&p) {
 				continue
 			}
 			score := inj.scorePattern(&p, ctx, classes, promptLower)
@@ -289,9 +416,141 @@ func (inj *Injector) findMatchingPatterns(ctx *ProjectContext, classes []classif
 		result[i] = &pCopy
 	}
 
+	// Explain the injected patterns and the runners-up
+	for i := 0; i < len(scored) && i < 2*maxRelevant; i++ {
+		d := Decision{Name: scored[i].pattern.Name, Score: scored[i].score, Matched: matchReasons(&scored[i].pattern, ctx, promptLower), Reason: ReasonNotTopN}
+		if i < maxPatterns {
+			d.Injected, d.Reason = true, ReasonRelevant
+		}
+		ex.Add(d)
+	}
+
 	return result, nil
 }
 
+// projectMatches returns the relevant patterns of the project's own
+// directory (see pattern.Store.WithProject), best first, and the names of
+// all of them, which hide global patterns of the same name.
+func (inj *Injector) projectMatches(ctx *ProjectContext, classes []classifier.DomainScore, promptLower string, ex *Explanation) ([]*pattern.Pattern, map[string]bool) {
+	target := ctx.Target()
+	project := inj.store.ListProject()
+	names := make(map[string]bool, len(project))
+	scores := make(map[string]float64, len(project))
+	var result []*pattern.Pattern
+	for i := range project {
+		p := &project[i]
+		names[p.Name] = true
+		if !p.IsActive() || !p.AppliesTo(target) || !inj.profile.Allows(p) {
+			continue
+		}
+		if score := inj.scorePattern(p, ctx, classes, promptLower); score > 0.1 {
+			scores[p.Name] = score
+			result = append(result, p)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return scores[result[i].Name] > scores[result[j].Name]
+	})
+	if len(result) > maxRelevant {
+		result = result[:maxRelevant]
+	}
+	for _, p := range result {
+		ex.Add(Decision{Name: p.Name, Score: scores[p.Name], Matched: matchReasons(p, ctx, promptLower), Injected: true, Reason: ReasonRelevant})
+	}
+	return result, names
+}
+
+// findPinnedPatterns returns up to pinnedBudget pinned patterns, the most
+// relevant first when there are more pinned patterns than the budget.
+func (inj *Injector) findPinnedPatterns(ctx *ProjectContext, classes []classifier.DomainScore, prompt string, ex *Explanation) ([]*pattern.Pattern, error) {
+	if inj.pinnedBudget <= 0 {
+		return nil, nil
+	}
+
+	var candidates []*pattern.Pattern
+	if inj.cache != nil {
+		for _, p := range inj.cache.Patterns.Active() {
+			if p.Pinned || inj.profile.Pins(p.Name) {
+				pCopy := *p
+				candidates = append(candidates, &pCopy)
+			}
+		}
+	} else {
+		pinned, err := inj.store.GetPinned()
+		if err != nil {
+			return nil, err
+		}
+		for i := range pinned {
+			candidates = append(candidates, &pinned[i])
+		}
+		if inj.profile != nil {
+			for _, name := range inj.profile.Pinned {
+				if p, err := inj.store.Get(name); err == nil && p.IsActive() && !p.Pinned {
+					candidates = append(candidates, p)
+				}
+			}
+		}
+	}
+	for _, p := range candidates {
+		p.Pinned = true // the profile's pins count as pinned from here on
+	}
+
+	// Pinned or not, a pattern scoped to other projects or left out by
+	// the profile stays out
+	target := ctx.Target()
+	applicable := candidates[:0]
+	for _, p := range candidates {
+		switch {
+		case !p.AppliesTo(target):
+			ex.Add(Decision{Name: p.Name, Pinned: true, Reason: ReasonNotApplicable})
+		case !inj.profile.Allows(p):
+			ex.Add(Decision{Name: p.Name, Pinned: true, Reason: ReasonProfile})
+		default:
+			applicable = append(applicable, p)
+		}
+	}
+	candidates = applicable
+
+	promptLower := strings.ToLower(prompt)
+	if len(candidates) > inj.pinnedBudget {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return inj.scorePattern(candidates[i], ctx, classes, promptLower) > inj.scorePattern(candidates[j], ctx, classes, promptLower)
+		})
+		for _, p := range candidates[inj.pinnedBudget:] {
+			if ex.isInjected(p.Name) {
+				continue // still injected on relevance
+			}
+			ex.Add(Decision{Name: p.Name, Pinned: true, Matched: matchReasons(p, ctx, promptLower), Reason: ReasonPinnedBudget})
+		}
+		candidates = candidates[:inj.pinnedBudget]
+	}
+	for _, p := range candidates {
+		ex.Add(Decision{Name: p.Name, Pinned: true, Injected: true, Matched: matchReasons(p, ctx, promptLower), Reason: ReasonPinned})
+	}
+
+	return candidates, nil
+}
+
+// Limit keeps every pinned pattern plus at most max others.
+func (r *InjectionResult) Limit(max int) {
+	if r.Explanation != nil {
+		r.Explanation.Max = max
+	}
+	kept := r.Patterns[:0]
+	others := 0
+	for _, p := range r.Patterns {
+		if !p.Pinned {
+			if others == max {
+				r.Explanation.Drop(p.Name, ReasonOverMax)
+				continue
+			}
+			others++
+		}
+		kept = append(kept, p)
+	}
+	r.Patterns = kept
+}
+
 // scorePattern calculates a relevance score for a pattern.
 func (inj *Injector) scorePattern(p *pattern.Pattern, ctx *ProjectContext, classes []classifier.DomainScore, promptLower string) float64 {
 	var score float64
@@ -344,7 +603,7 @@ func (inj *Injector) scorePattern(p *pattern.Pattern, ctx *ProjectContext, class
 	// 4. Language/framework matching from ApplyConditions
 	for _, lang := range p.Applies.Languages {
 		for _, ctxLang := range ctx.Languages {
-			if strings.EqualFold(lang, ctxLang) {
+			if pattern.MatchApplies(lang, ctxLang) {
 				score += 0.25
 			}
 		}
@@ -357,17 +616,20 @@ func (inj *Injector) scorePattern(p *pattern.Pattern, ctx *ProjectContext, class
 		}
 	}
 
-	// 5. Project matching
-	for _, proj := range p.Applies.Projects {
-		if matched, _ := filepath.Match(proj, ctx.ProjectName); matched {
-			score += 0.4
-		}
+	// 5. Project matching; the project's own patterns match it
+	if _, ok := p.Applies.MatchProject(ctx.Target().Project); ok || p.Scope == pattern.ScopeProject {
+		score += 0.4
 	}
 
-	// 6. Trust level bonus
+	// 6. Tech stack, when the project itself tells us nothing
+	if len(ctx.Languages) == 0 && len(inj.techStack) > 0 {
+		score += stackBoost(p, inj.techStack)
+	}
+
+	// 7. Trust level bonus
 	score *= (1.0 + p.Security.TrustLevel.Score()*0.2)
 
-	// 7. Effectiveness bonus
+	// 8. Effectiveness bonus
 	score *= (1.0 + p.Learning.Effectiveness*0.3)
 
 	return score
@@ -445,6 +707,82 @@ func findProjectRoot(dir string) string {
 	return dir
 }
 
+// detectRustProject returns the package name from Cargo.toml.
+func detectRustProject(root string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(root, "Cargo.toml"))
+	if err != nil {
+		return "", false
+	}
+	inPackage := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inPackage && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`), true
+		}
+	}
+	return "", true
+}
+
+// detectGitRemote returns the origin remote of the repository at root as
+// host/owner/repo, read from .git/config without running git.
+func detectGitRemote(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	var section, first, origin string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "url" || !strings.HasPrefix(section, "[remote ") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if first == "" {
+			first = value
+		}
+		if section == `[remote "origin"]` {
+			origin = value
+		}
+	}
+	if origin == "" {
+		origin = first
+	}
+	return normalizeRemote(origin)
+}
+
+// normalizeRemote turns git@github.com:acme/api.git and
+// https://github.com/acme/api.git into github.com/acme/api.
+func normalizeRemote(url string) string {
+	if url == "" {
+		return ""
+	}
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if at := strings.Index(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+			url = url[at+1:]
+		}
+		if host, rest, ok := strings.Cut(url, "/"); ok {
+			if h, _, hasPort := strings.Cut(host, ":"); hasPort {
+				host = h
+			}
+			url = host + "/" + rest
+		}
+	} else if at := strings.Index(url, "@"); at >= 0 {
+		url = strings.Replace(url[at+1:], ":", "/", 1)
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	return url
+}
+
 func detectGoProject(root string) *goProjectInfo {
 	goMod := filepath.Join(root, "go.mod")
 	data, err := os.ReadFile(goMod)
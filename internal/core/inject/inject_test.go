@@ -0,0 +1,134 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestInjectionResultLimitKeepsPinned(t *testing.T) {
+	r := &InjectionResult{Patterns: []*pattern.Pattern{
+		{Name: "checklist", Pinned: true},
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c"},
+	}}
+
+	r.Limit(2)
+
+	var names []string
+	for _, p := range r.Patterns {
+		names = append(names, p.Name)
+	}
+	if len(names) != 3 || names[0] != "checklist" || names[1] != "a" || names[2] != "b" {
+		t.Errorf("Limit(2) = %v, want [checklist a b]", names)
+	}
+}
+
+func TestPinnedBudget(t *testing.T) {
+	cfg := &config.Config{}
+	if got := PinnedBudget(cfg); got != DefaultPinnedBudget {
+		t.Errorf("default = %d, want %d", got, DefaultPinnedBudget)
+	}
+	cfg.Context.PinnedBudget = 5
+	if got := PinnedBudget(cfg); got != 5 {
+		t.Errorf("configured = %d, want 5", got)
+	}
+}
+
+func TestDetectProjectRemote(t *testing.T) {
+	root := t.TempDir()
+	_ = os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/billing\n"), 0644)
+	_ = os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	_ = os.WriteFile(filepath.Join(root, ".git", "config"), []byte(`[core]
+	bare = false
+[remote "upstream"]
+	url = https://github.com/other/billing.git
+[remote "origin"]
+	url = git@github.com:acme/billing-service.git
+`), 0644)
+
+	ctx := DetectProject(root)
+	if ctx.ProjectName != "example.com/billing" || ctx.Remote != "github.com/acme/billing-service" {
+		t.Errorf("detected %+v", ctx)
+	}
+
+	for url, want := range map[string]string{
+		"https://github.com/acme/api.git":           "github.com/acme/api",
+		"ssh://git@gitlab.example.com:2222/a/b.git": "gitlab.example.com/a/b",
+		"https://user@bitbucket.org/acme/web":       "bitbucket.org/acme/web",
+	} {
+		if got := normalizeRemote(url); got != want {
+			t.Errorf("normalizeRemote(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestFindPinnedPatternsSkipsOtherProjects(t *testing.T) {
+	store := pattern.NewStore(t.TempDir())
+	for _, p := range []*pattern.Pattern{
+		{Name: "everywhere", Content: "always", Pinned: true},
+		{Name: "services-only", Content: "services", Pinned: true,
+			Applies: pattern.ApplyConditions{Projects: []string{"github.com/acme/*-service"}}},
+	} {
+		if err := store.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inj := NewInjector(store)
+
+	names := func(ctx *ProjectContext) []string {
+		ex := NewExplanation("context")
+		got, err := inj.findPinnedPatterns(ctx, nil, "", ex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, p := range got {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+
+	if got := names(&ProjectContext{Remote: "github.com/acme/billing-service"}); len(got) != 2 {
+		t.Errorf("in a matching repo got %v, want both", got)
+	}
+	if got := names(&ProjectContext{Remote: "github.com/acme/website"}); len(got) != 1 || got[0] != "everywhere" {
+		t.Errorf("in another repo got %v, want [everywhere]", got)
+	}
+}
+
+func TestFindMatchingPatternsIncludesProjectPatterns(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), pattern.ProjectPatternsDir)
+	for _, p := range []*pattern.Pattern{
+		{Name: "api-errors", Content: "Return problem+json from handlers."},
+		{Name: "shared", Content: "project version"},
+	} {
+		if err := pattern.NewStore(projectDir).Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := pattern.NewStore(t.TempDir())
+	if err := store.Create(&pattern.Pattern{Name: "shared", Content: "global version", Tags: pattern.TagSet{Confirmed: []string{"go"}}}); err != nil {
+		t.Fatal(err)
+	}
+	inj := NewInjector(store.WithProject(projectDir))
+
+	got, err := inj.findMatchingPatterns(&ProjectContext{Languages: []string{"go"}}, nil, "", NewExplanation("context"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*pattern.Pattern)
+	for _, p := range got {
+		byName[p.Name] = p
+	}
+	if byName["api-errors"] == nil {
+		t.Errorf("project pattern without tags not injected: %v", got)
+	}
+	if p := byName["shared"]; p == nil || p.Content != "project version" {
+		t.Errorf("shared = %+v, want the project's version", p)
+	}
+}
@@ -0,0 +1,227 @@
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// Outcome is how an injected pattern fared in the work that followed.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success" // used, and the work succeeded
+	OutcomeFailure Outcome = "failure" // used, but the work failed or was corrected
+	OutcomeIgnored Outcome = "ignored" // injected but not used
+)
+
+// ParseOutcome parses an outcome name.
+func ParseOutcome(s string) (Outcome, error) {
+	switch o := Outcome(strings.ToLower(strings.TrimSpace(s))); o {
+	case OutcomeSuccess, OutcomeFailure, OutcomeIgnored:
+		return o, nil
+	}
+	return "", fmt.Errorf("invalid outcome %q (use success, failure, or ignored)", s)
+}
+
+// EffectivenessAlpha is how much weight each outcome gets in a pattern's
+// effectiveness; 0.2 means roughly the last ten outcomes count.
+const EffectivenessAlpha = 0.2
+
+// value is the effectiveness an outcome pulls towards. An ignored
+// pattern cost context without helping, so it counts a little against.
+func (o Outcome) value() float64 {
+	switch o {
+	case OutcomeSuccess:
+		return 1
+	case OutcomeIgnored:
+		return 0.3
+	default:
+		return 0
+	}
+}
+
+// UpdateEffectiveness returns effectiveness moved towards outcome by an
+// exponentially weighted moving average. Patterns without a score start
+// from a neutral 0.5.
+func UpdateEffectiveness(effectiveness float64, o Outcome) float64 {
+	if effectiveness <= 0 {
+		effectiveness = 0.5
+	}
+	e := EffectivenessAlpha*o.value() + (1-EffectivenessAlpha)*effectiveness
+	return math.Round(e*1e4) / 1e4
+}
+
+// OutcomeRecord is one outcome applied to a pattern's effectiveness.
+type OutcomeRecord struct {
+	Time        time.Time `json:"time"`
+	PatternID   string    `json:"pattern_id,omitempty"`
+	PatternName string    `json:"pattern_name"`
+	Outcome     Outcome   `json:"outcome"`
+	Source      string    `json:"source"`               // "cli" or "hook"
+	Session     string    `json:"session,omitempty"`    // AI tool session, for hook outcomes
+	InjectedAt  time.Time `json:"injected_at,omitzero"` // the injection judged, for hook outcomes
+	Before      float64   `json:"before"`
+	After       float64   `json:"after"`
+}
+
+// outcomesFile returns the path to the outcome log.
+func (t *Tracker) outcomesFile() string {
+	return filepath.Join(t.dataDir, "outcomes.jsonl")
+}
+
+// RecordOutcome applies an outcome to the effectiveness of the pattern
+// named (or with the ID) rec.PatternName, saves the pattern, and logs the
+// outcome. It returns the logged record.
+func (t *Tracker) RecordOutcome(rec OutcomeRecord) (*OutcomeRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, err := t.store.Resolve(rec.PatternName)
+	if err != nil {
+		return nil, err
+	}
+	rec.PatternID, rec.PatternName = p.ID, p.Name
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	rec.Before = p.Learning.Effectiveness
+	rec.After = UpdateEffectiveness(p.Learning.Effectiveness, rec.Outcome)
+	p.Learning.Effectiveness = rec.After
+	if err := t.store.Update(p); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(t.dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create tracking directory: %w", err)
+	}
+	f, err := os.OpenFile(t.outcomesFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open outcome log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := json.NewEncoder(f).Encode(rec); err != nil {
+		return nil, fmt.Errorf("cannot write outcome: %w", err)
+	}
+	return &rec, nil
+}
+
+// JudgedInjections returns the times of the injections in session that
+// already have outcomes, so the stop hook judges each injection once.
+func (t *Tracker) JudgedInjections(session string) (map[time.Time]bool, error) {
+	judged := make(map[time.Time]bool)
+	lines, err := readLines(t.outcomesFile())
+	if os.IsNotExist(err) {
+		return judged, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		var rec OutcomeRecord
+		if json.Unmarshal([]byte(line), &rec) == nil && rec.Session == session && !rec.InjectedAt.IsZero() {
+			judged[rec.InjectedAt.UTC()] = true
+		}
+	}
+	return judged, nil
+}
+
+// JudgeOutcome decides how an injected pattern fared in a turn: ignored
+// if the turn's work (assistant text and tool input) shows no sign of it,
+// otherwise success or failure as the turn went.
+func JudgeOutcome(p *pattern.Pattern, work string, failed bool) Outcome {
+	if !PatternUsed(p, work) {
+		return OutcomeIgnored
+	}
+	if failed {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// PatternUsed reports whether work shows signs of p: its name, or the
+// distinctive terms of its content (identifiers, commands, and flags).
+// Without distinctive terms, a third of its long words must appear.
+func PatternUsed(p *pattern.Pattern, work string) bool {
+	work = strings.ToLower(work)
+	if work == "" {
+		return false
+	}
+	if strings.Contains(work, strings.ToLower(p.Name)) {
+		return true
+	}
+
+	terms, fallback := distinctiveTerms(p.Content), false
+	if len(terms) == 0 {
+		terms, fallback = longWords(p.Content), true
+	}
+	if len(terms) == 0 {
+		return false
+	}
+	hits := 0
+	for _, term := range terms {
+		if strings.Contains(work, term) {
+			hits++
+		}
+	}
+	if fallback {
+		return hits >= 2 && hits*3 >= len(terms)
+	}
+	return hits >= min(2, len(terms))
+}
+
+var termRe = regexp.MustCompile(`-{0,2}[A-Za-z_][A-Za-z0-9_.\-/:]*[A-Za-z0-9_]`)
+
+// distinctiveTerms returns the terms in content that look like code:
+// snake_case, camelCase, dotted or slashed names, and flags.
+func distinctiveTerms(content string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range termRe.FindAllString(content, -1) {
+		if len(term) < 4 || !looksLikeCode(term) {
+			continue
+		}
+		lower := strings.ToLower(strings.TrimRight(term, ".:/"))
+		if strings.HasPrefix(lower, "http") || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		terms = append(terms, lower)
+	}
+	return terms
+}
+
+func looksLikeCode(term string) bool {
+	if strings.HasPrefix(term, "-") || strings.ContainsAny(strings.TrimRight(term, "."), "_./:") {
+		return true
+	}
+	// camelCase: an upper-case letter after a lower-case one
+	for i := 1; i < len(term); i++ {
+		if unicode.IsUpper(rune(term[i])) && unicode.IsLower(rune(term[i-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// longWords returns the distinct words of eight or more letters in
+// content, a rough fingerprint of prose-only patterns.
+func longWords(content string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(content), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len(w) >= 8 && !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
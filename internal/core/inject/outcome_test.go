@@ -0,0 +1,77 @@
+package inject
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestUpdateEffectiveness(t *testing.T) {
+	e := UpdateEffectiveness(0, OutcomeSuccess) // unscored starts at 0.5
+	if math.Abs(e-0.6) > 1e-9 {
+		t.Errorf("success from neutral = %v, want 0.6", e)
+	}
+	if e := UpdateEffectiveness(0.6, OutcomeFailure); math.Abs(e-0.48) > 1e-9 {
+		t.Errorf("failure from 0.6 = %v, want 0.48", e)
+	}
+	if e := UpdateEffectiveness(0.5, OutcomeIgnored); e >= 0.5 {
+		t.Errorf("ignored should count a little against, got %v", e)
+	}
+	if _, err := ParseOutcome("meh"); err == nil {
+		t.Error("ParseOutcome accepted an unknown outcome")
+	}
+}
+
+func TestPatternUsed(t *testing.T) {
+	p := &pattern.Pattern{
+		Name:    "go-error-wrapping",
+		Content: "Wrap errors with fmt.Errorf and %w, and compare them with errors.Is rather than ==.",
+	}
+	if !PatternUsed(p, "I'll return fmt.Errorf(\"open: %w\", err) and check errors.Is(err, fs.ErrNotExist)") {
+		t.Error("work using the pattern's identifiers not detected")
+	}
+	if PatternUsed(p, "Renamed the handler and updated the README.") {
+		t.Error("unrelated work detected as using the pattern")
+	}
+	if !PatternUsed(p, "following go-error-wrapping") {
+		t.Error("naming the pattern should count as using it")
+	}
+
+	prose := &pattern.Pattern{Name: "review-etiquette", Content: "Acknowledge reviewer suggestions explicitly before implementing alternatives."}
+	if !PatternUsed(prose, "I'll acknowledge the suggestions explicitly, then try alternatives") {
+		t.Error("prose pattern not detected by its long words")
+	}
+}
+
+func TestRecordOutcome(t *testing.T) {
+	dir := t.TempDir()
+	store := pattern.NewStore(filepath.Join(dir, "patterns")).WithCompression(0)
+	if err := store.Create(&pattern.Pattern{Name: "retry-backoff", Content: "Use time.Sleep with jitter"}); err != nil {
+		t.Fatal(err)
+	}
+	tracker := NewTracker(store, filepath.Join(dir, "tracking"))
+
+	injected := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec, err := tracker.RecordOutcome(OutcomeRecord{PatternName: "retry-backoff", Outcome: OutcomeSuccess, Source: "hook", Session: "s1", InjectedAt: injected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := store.Get("retry-backoff")
+	if rec.Before != 0.5 || p.Learning.Effectiveness != rec.After || rec.After <= rec.Before {
+		t.Errorf("record = %+v, saved effectiveness %v", rec, p.Learning.Effectiveness)
+	}
+
+	judged, err := tracker.JudgedInjections("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !judged[injected] {
+		t.Errorf("judged = %v, want the injection at %v", judged, injected)
+	}
+	if other, _ := tracker.JudgedInjections("s2"); len(other) != 0 {
+		t.Errorf("other session judged = %v", other)
+	}
+}
@@ -0,0 +1,196 @@
+package inject
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/kit"
+)
+
+// ProfileEnv selects a context profile, e.g. from a hook script.
+const ProfileEnv = "MUR_PROFILE"
+
+// Profile is a named context profile from config.
+type Profile struct {
+	Name string
+	config.ContextProfile
+	// Source says where the profile was selected: "flag", "env", "today"
+	// (mur profile use), or "config".
+	Source string
+}
+
+// ResolveProfile returns the profile in effect: the name given (from
+// --profile), then $MUR_PROFILE, then the one chosen with 'mur profile use'
+// for today, then context.profile. It returns nil when none is selected.
+func ResolveProfile(cfg *config.Config, name string) (*Profile, error) {
+	source := "flag"
+	if name == "" {
+		name, source = os.Getenv(ProfileEnv), "env"
+	}
+	if name == "" {
+		if o, err := ActiveProfileOverride(time.Now()); err == nil && o != nil {
+			name, source = o.Profile, "today"
+		}
+	}
+	if name == "" {
+		name, source = cfg.Context.Profile, "config"
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return LookupProfile(cfg, name, source)
+}
+
+// LookupProfile returns the named profile, from config or, named
+// <kit>/<profile>, from an installed kit.
+func LookupProfile(cfg *config.Config, name, source string) (*Profile, error) {
+	p, ok := cfg.Context.Profiles[name]
+	if !ok && strings.Contains(name, "/") {
+		p, ok = kit.Profiles()[name]
+	}
+	if !ok {
+		names := ProfileNames(cfg)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown context profile %q (none configured under context.profiles)", name)
+		}
+		return nil, fmt.Errorf("unknown context profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return &Profile{Name: name, ContextProfile: p, Source: source}, nil
+}
+
+// ProfileNames returns the configured profile names and those of installed
+// kits, sorted.
+func ProfileNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Context.Profiles))
+	for name := range cfg.Context.Profiles {
+		names = append(names, name)
+	}
+	for name := range kit.Profiles() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Allows reports whether the profile lets the pattern be injected. The
+// profile's own pinned patterns always are.
+func (p *Profile) Allows(pt *pattern.Pattern) bool {
+	if p == nil || p.Pins(pt.Name) {
+		return true
+	}
+	for _, tag := range p.ExcludeTags {
+		if pt.HasTag(tag) {
+			return false
+		}
+	}
+	if len(p.Tags) == 0 {
+		return true
+	}
+	for _, tag := range p.Tags {
+		if pt.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pins reports whether the profile pins the named pattern.
+func (p *Profile) Pins(name string) bool {
+	if p == nil {
+		return false
+	}
+	for _, n := range p.Pinned {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PinnedBudget returns the profile's pinned budget, or the configured one.
+func (p *Profile) PinnedBudget(cfg *config.Config) int {
+	if p != nil && p.ContextProfile.PinnedBudget != 0 {
+		return p.ContextProfile.PinnedBudget
+	}
+	return PinnedBudget(cfg)
+}
+
+// ProfileOverride is a profile chosen with 'mur profile use', in effect
+// until Until.
+type ProfileOverride struct {
+	Profile string    `json:"profile"`
+	Until   time.Time `json:"until"`
+}
+
+func profileOverridePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.StateDir(home), "context_profile.json"), nil
+}
+
+// ActiveProfileOverride returns the profile chosen with 'mur profile use',
+// or nil if none is in effect at now.
+func ActiveProfileOverride(now time.Time) (*ProfileOverride, error) {
+	path, err := profileOverridePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var o ProfileOverride
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if o.Profile == "" || !now.Before(o.Until) {
+		return nil, nil
+	}
+	return &o, nil
+}
+
+// SetProfileOverride makes name the default profile until until.
+func SetProfileOverride(name string, until time.Time) error {
+	path, err := profileOverridePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ProfileOverride{Profile: name, Until: until}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ClearProfileOverride drops the profile chosen with 'mur profile use'.
+func ClearProfileOverride() error {
+	path, err := profileOverridePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// EndOfDay returns the next local midnight after t.
+func EndOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,129 @@
+package inject
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func profileConfig() *config.Config {
+	return &config.Config{Context: config.ContextConfig{
+		Profile: "implementer",
+		Profiles: map[string]config.ContextProfile{
+			"implementer": {},
+			"oncall":      {Tags: []string{"incident"}, Pinned: []string{"runbook"}, PinnedBudget: 5},
+			"reviewer":    {ExcludeTags: []string{"scaffolding"}},
+		},
+	}}
+}
+
+func TestResolveProfile(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+	cfg := profileConfig()
+
+	resolve := func(flag string) string {
+		t.Helper()
+		p, err := ResolveProfile(cfg, flag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p.Name + "/" + p.Source
+	}
+
+	if got := resolve(""); got != "implementer/config" {
+		t.Errorf("default = %s", got)
+	}
+	if err := SetProfileOverride("reviewer", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolve(""); got != "reviewer/today" {
+		t.Errorf("after profile use = %s", got)
+	}
+	t.Setenv(ProfileEnv, "oncall")
+	if got := resolve(""); got != "oncall/env" {
+		t.Errorf("with $%s = %s", ProfileEnv, got)
+	}
+	if got := resolve("reviewer"); got != "reviewer/flag" {
+		t.Errorf("with --profile = %s", got)
+	}
+	if _, err := ResolveProfile(cfg, "nope"); err == nil {
+		t.Error("unknown profile accepted")
+	}
+
+	t.Setenv(ProfileEnv, "")
+	if err := SetProfileOverride("reviewer", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolve(""); got != "implementer/config" {
+		t.Errorf("after today's profile expired = %s", got)
+	}
+	if p := (*Profile)(nil); p.PinnedBudget(cfg) != DefaultPinnedBudget || !p.Allows(&pattern.Pattern{}) {
+		t.Error("nil profile should change nothing")
+	}
+}
+
+func TestProfileAllows(t *testing.T) {
+	cfg := profileConfig()
+	oncall, _ := LookupProfile(cfg, "oncall", "")
+	reviewer, _ := LookupProfile(cfg, "reviewer", "")
+
+	incident := &pattern.Pattern{Name: "pager", Tags: pattern.TagSet{Confirmed: []string{"Incident"}}}
+	scaffold := &pattern.Pattern{Name: "new-service", Tags: pattern.TagSet{Inferred: []pattern.TagScore{{Tag: "scaffolding", Confidence: 0.9}}}}
+	runbook := &pattern.Pattern{Name: "runbook"}
+
+	for _, tt := range []struct {
+		profile *Profile
+		p       *pattern.Pattern
+		want    bool
+	}{
+		{oncall, incident, true},
+		{oncall, scaffold, false},
+		{oncall, runbook, true}, // pinned by the profile
+		{reviewer, incident, true},
+		{reviewer, scaffold, false},
+	} {
+		if got := tt.profile.Allows(tt.p); got != tt.want {
+			t.Errorf("%s allows %s = %v, want %v", tt.profile.Name, tt.p.Name, got, tt.want)
+		}
+	}
+	if oncall.PinnedBudget(cfg) != 5 {
+		t.Errorf("pinned budget = %d, want 5", oncall.PinnedBudget(cfg))
+	}
+}
+
+func TestFindPinnedPatternsWithProfile(t *testing.T) {
+	store := pattern.NewStore(t.TempDir())
+	for _, p := range []*pattern.Pattern{
+		{Name: "style-guide", Content: "style", Pinned: true, Tags: pattern.TagSet{Confirmed: []string{"style"}}},
+		{Name: "incident-checklist", Content: "pinned", Pinned: true, Tags: pattern.TagSet{Confirmed: []string{"incident"}}},
+		{Name: "runbook", Content: "runbook"},
+	} {
+		if err := store.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oncall, _ := LookupProfile(profileConfig(), "oncall", "")
+	inj := NewInjector(store)
+	inj.WithProfile(oncall)
+
+	ex := NewExplanation("context")
+	got, err := inj.findPinnedPatterns(&ProjectContext{}, nil, "", ex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, p := range got {
+		names[p.Name] = p.Pinned
+	}
+	if len(got) != 2 || !names["incident-checklist"] || !names["runbook"] {
+		t.Errorf("pinned = %v, want incident-checklist and the profile's runbook", names)
+	}
+	for _, d := range ex.Patterns {
+		if d.Name == "style-guide" && d.Reason != ReasonProfile {
+			t.Errorf("style-guide reason = %q", d.Reason)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package inject
+
+import (
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/security"
+)
+
+// MethodManual is the Explanation method for context the user chose
+// ('mur run --context-from') instead of search.
+const MethodManual = "manual"
+
+// InjectSelected injects exactly the given patterns, skipping search,
+// scoring, and the pattern limit. High-risk untrusted patterns are still
+// blocked. The explanation records the selection as manual, so its
+// outcomes can be told apart from search's.
+func (inj *Injector) InjectSelected(prompt, workDir string, patterns []*pattern.Pattern) *InjectionResult {
+	ctx := inj.detectContext(workDir)
+	ex := &Explanation{
+		Time:    time.Now().UTC(),
+		Command: "run --context-from",
+		Method:  MethodManual,
+		Max:     len(patterns),
+	}
+	ex.SetProject(ctx)
+	ex.SetPrompt(prompt)
+
+	var safe []*pattern.Pattern
+	var blocked []BlockedPattern
+	for _, p := range patterns {
+		risk, findings := inj.injectionScanner.Scan(p.Content)
+		p.Security.InjectionRisk = string(risk)
+		if risk == security.InjectionRiskHigh && !p.IsTrusted() {
+			blocked = append(blocked, BlockedPattern{Name: p.Name, Risk: risk, Findings: findings})
+			ex.Add(Decision{Name: p.Name, Reason: ReasonBlocked})
+			continue
+		}
+		ex.Add(Decision{Name: p.Name, Injected: true, Reason: ReasonSelected})
+		safe = append(safe, p)
+	}
+
+	return &InjectionResult{
+		Patterns:        safe,
+		FormattedPrompt: inj.formatPrompt(prompt, safe),
+		Context:         ctx,
+		BlockedPatterns: blocked,
+		Explanation:     ex,
+		Manual:          true,
+	}
+}
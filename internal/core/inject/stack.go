@@ -0,0 +1,154 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// StackItem is a language, framework, or tool found by DetectStack.
+type StackItem struct {
+	Name  string `json:"name"`
+	Repos int    `json:"repos"` // how many projects use it
+}
+
+// maxStackProjects bounds how many projects DetectStack looks at, so
+// pointing it at a huge directory stays quick.
+const maxStackProjects = 500
+
+// stackSkipDirs are directories DetectStack never descends into.
+var stackSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "target": true, "build": true,
+	"dist": true, "venv": true, "Pods": true, "DerivedData": true,
+}
+
+// toolFingerprints map files at a project root to the tools they imply,
+// beyond the languages and frameworks project detection finds.
+var toolFingerprints = []struct {
+	file, tool string
+}{
+	{"Dockerfile", "docker"},
+	{"docker-compose.yml", "docker"},
+	{"compose.yaml", "docker"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"build.gradle.kts", "kotlin"},
+	{"Gemfile", "ruby"},
+	{"composer.json", "php"},
+	{"main.tf", "terraform"},
+	{"Chart.yaml", "kubernetes"},
+	{"kustomization.yaml", "kubernetes"},
+}
+
+// DetectStack looks for projects in roots, up to depth directories down,
+// and returns the languages, frameworks, and tools they use, most used
+// first. A project is a directory with a .git directory or a manifest
+// like go.mod or package.json; projects aren't searched for nested ones.
+func DetectStack(roots []string, depth int) []StackItem {
+	counts := make(map[string]int)
+	projects := 0
+	seen := make(map[string]bool)
+
+	var scan func(dir string, level int)
+	scan = func(dir string, level int) {
+		if projects >= maxStackProjects || seen[dir] {
+			return
+		}
+		seen[dir] = true
+		if isProjectRoot(dir) {
+			projects++
+			for _, tech := range projectStack(dir) {
+				counts[tech]++
+			}
+			return
+		}
+		if level >= depth {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !e.IsDir() || strings.HasPrefix(name, ".") || stackSkipDirs[name] {
+				continue
+			}
+			scan(filepath.Join(dir, name), level+1)
+		}
+	}
+	for _, root := range roots {
+		if abs, err := filepath.Abs(root); err == nil {
+			scan(abs, 0)
+		}
+	}
+
+	items := make([]StackItem, 0, len(counts))
+	for name, n := range counts {
+		items = append(items, StackItem{Name: name, Repos: n})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Repos != items[j].Repos {
+			return items[i].Repos > items[j].Repos
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// isProjectRoot reports whether dir has one of the markers findProjectRoot
+// looks for.
+func isProjectRoot(dir string) bool {
+	for _, marker := range []string{".git", "go.mod", "Package.swift", "package.json", "pyproject.toml", "Cargo.toml"} {
+		if fileExists(filepath.Join(dir, marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectStack returns the languages, frameworks, and tools of the
+// project at root, without duplicates.
+func projectStack(root string) []string {
+	ctx := (&Injector{}).detectContext(root)
+	var stack []string
+	add := func(tech string) {
+		for _, t := range stack {
+			if t == tech {
+				return
+			}
+		}
+		stack = append(stack, tech)
+	}
+	for _, lang := range ctx.Languages {
+		add(lang)
+	}
+	for _, fw := range ctx.Frameworks {
+		add(fw)
+	}
+	for _, fp := range toolFingerprints {
+		if fileExists(filepath.Join(root, fp.file)) {
+			add(fp.tool)
+		}
+	}
+	return stack
+}
+
+// stackBoost scores how well p fits the user's tech_stack, by its tags
+// and the languages and frameworks it applies to.
+func stackBoost(p *pattern.Pattern, stack []string) float64 {
+	names := append(append(append([]string{}, p.Tags.Confirmed...), p.Applies.Languages...), p.Applies.Frameworks...)
+	var boost float64
+	for _, tech := range stack {
+		for _, name := range names {
+			if strings.EqualFold(name, tech) {
+				boost += 0.15
+				break
+			}
+		}
+	}
+	return min(boost, 0.3)
+}
@@ -0,0 +1,46 @@
+package inject
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectStack(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("api/go.mod", "module example.com/api\n")
+	write("api/Dockerfile", "FROM golang\n")
+	write("worker/go.mod", "module example.com/worker\n")
+	write("clients/web/package.json", `{"name": "web", "dependencies": {"react": "18"}}`)
+	write("clients/web/tsconfig.json", "{}")
+	// Dependencies of a project aren't projects of their own
+	write("clients/web/node_modules/left-pad/package.json", `{"name": "left-pad"}`)
+	write("deep/a/b/c/go.mod", "module too/deep\n")
+
+	items := DetectStack([]string{root}, 2)
+	got := make(map[string]int)
+	for _, it := range items {
+		got[it.Name] = it.Repos
+	}
+	want := map[string]int{"go": 2, "docker": 1, "typescript": 1, "react": 1}
+	if len(got) != len(want) {
+		t.Errorf("DetectStack() = %+v, want %v", items, want)
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("%s: %d repos, want %d", name, got[name], n)
+		}
+	}
+	if len(items) > 0 && items[0].Name != "go" {
+		t.Errorf("first item = %s, want go (most repos)", items[0].Name)
+	}
+}
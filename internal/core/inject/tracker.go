@@ -4,6 +4,7 @@ package inject
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,6 +29,8 @@ type UsageRecord struct {
 	PromptPreview string `json:"prompt_preview,omitempty"`
 	// Whether the run succeeded
 	Success bool `json:"success"`
+	// Chosen by the user (mur run --context-from), not by search
+	Manual bool `json:"manual,omitempty"`
 	// User feedback (if provided)
 	Feedback *Feedback `json:"feedback,omitempty"`
 }
@@ -47,6 +50,7 @@ type EffectivenessStats struct {
 	PatternID   string  `json:"pattern_id"`
 	PatternName string  `json:"pattern_name"`
 	TotalUses   int     `json:"total_uses"`
+	ManualUses  int     `json:"manual_uses"` // of TotalUses, chosen with --context-from
 	SuccessRate float64 `json:"success_rate"`
 	// Feedback stats
 	HelpfulCount   int     `json:"helpful_count"`
@@ -81,8 +85,8 @@ func DefaultTracker() (*Tracker, error) {
 		return nil, err
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "patterns")
-	dataDir := filepath.Join(home, ".mur", "tracking")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
+	dataDir := filepath.Join(config.StateDir(home), "tracking")
 
 	return &Tracker{
 		store:   pattern.NewStore(patternsDir),
@@ -95,8 +99,9 @@ func (t *Tracker) usageFile() string {
 	return filepath.Join(t.dataDir, "usage.jsonl")
 }
 
-// RecordUsage records that patterns were used in a run.
-func (t *Tracker) RecordUsage(patterns []*pattern.Pattern, ctx *ProjectContext, prompt string, success bool) error {
+// RecordUsage records that patterns were used in a run; manual marks
+// patterns the user chose instead of search.
+func (t *Tracker) RecordUsage(patterns []*pattern.Pattern, ctx *ProjectContext, prompt string, success, manual bool) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -124,9 +129,10 @@ func (t *Tracker) RecordUsage(patterns []*pattern.Pattern, ctx *ProjectContext,
 		record := UsageRecord{
 			PatternID:     p.ID,
 			PatternName:   p.Name,
-			Timestamp:     time.Now(),
+			Timestamp:     time.Now().UTC(),
 			PromptPreview: promptPreview,
 			Success:       success,
+			Manual:        manual,
 		}
 		if ctx != nil {
 			record.ProjectType = ctx.ProjectType
@@ -183,7 +189,7 @@ func (t *Tracker) RecordFeedback(patternName string, rating int, comment string)
 	records[targetIdx].Feedback = &Feedback{
 		Rating:    rating,
 		Comment:   comment,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().UTC(),
 	}
 
 	// Rewrite file
@@ -211,6 +217,9 @@ func (t *Tracker) GetStats() ([]EffectivenessStats, error) {
 		}
 
 		stats.TotalUses++
+		if r.Manual {
+			stats.ManualUses++
+		}
 		if r.Success {
 			stats.SuccessRate += 1.0
 		}
@@ -0,0 +1,154 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// regexPrefix marks an applies entry as a regular expression instead of
+// a glob, e.g. "re:^github\.com/acme/(api|web)$".
+const regexPrefix = "re:"
+
+// Target is where a pattern might be used: the current project and its
+// detected languages.
+type Target struct {
+	// Project identifies the project in every way an applies entry may
+	// name it: module or package name, git remote (host/owner/repo), and
+	// root directory.
+	Project   []string
+	Languages []string
+}
+
+// AppliesTo reports whether the pattern may be used in t. A pattern that
+// lists projects needs one to match the project; one that lists languages
+// needs one to match a detected language, unless none were detected.
+func (p *Pattern) AppliesTo(t Target) bool {
+	if len(p.Applies.Projects) > 0 {
+		if _, ok := p.Applies.MatchProject(t.Project); !ok {
+			return false
+		}
+	}
+	if len(p.Applies.Languages) > 0 && len(t.Languages) > 0 {
+		if _, ok := p.Applies.MatchLanguage(t.Languages); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ProjectScoped reports whether the pattern only applies to some projects,
+// or comes from a project's own patterns, and so doesn't belong in rules
+// synced to every project.
+func (p *Pattern) ProjectScoped() bool {
+	return len(p.Applies.Projects) > 0 || p.Scope == ScopeProject
+}
+
+// MatchProject returns the first Projects entry that matches one of the
+// project identifiers.
+func (a ApplyConditions) MatchProject(ids []string) (string, bool) {
+	return matchAny(a.Projects, ids)
+}
+
+// MatchLanguage returns the first Languages entry that matches one of the
+// detected languages.
+func (a ApplyConditions) MatchLanguage(langs []string) (string, bool) {
+	return matchAny(a.Languages, langs)
+}
+
+func matchAny(exprs, values []string) (string, bool) {
+	for _, expr := range exprs {
+		for _, v := range values {
+			if v != "" && MatchApplies(expr, v) {
+				return expr, true
+			}
+		}
+	}
+	return "", false
+}
+
+// MatchApplies reports whether s matches an applies entry, ignoring case.
+// An entry is a glob, where * and ? stop at "/" and ** doesn't, or a
+// regular expression prefixed with "re:". A glob starting with "~/" is
+// relative to the home directory. Invalid entries match nothing.
+func MatchApplies(expr, s string) bool {
+	re, err := compileApplies(expr)
+	return err == nil && re.MatchString(s)
+}
+
+var appliesCache sync.Map // expr -> *regexp.Regexp or error
+
+func compileApplies(expr string) (*regexp.Regexp, error) {
+	if v, ok := appliesCache.Load(expr); ok {
+		if re, ok := v.(*regexp.Regexp); ok {
+			return re, nil
+		}
+		return nil, v.(error)
+	}
+
+	var re *regexp.Regexp
+	var err error
+	if src, ok := strings.CutPrefix(expr, regexPrefix); ok {
+		re, err = regexp.Compile("(?i)" + src)
+	} else {
+		re, err = regexp.Compile("(?i)^" + globToRegexp(expandHome(expr)) + "$")
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid applies entry %q: %w", expr, err)
+		appliesCache.Store(expr, err)
+		return nil, err
+	}
+	appliesCache.Store(expr, re)
+	return re, nil
+}
+
+// ValidateApplies returns an error for an entry that can't be compiled.
+func ValidateApplies(expr string) error {
+	_, err := compileApplies(expr)
+	return err
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+func expandHome(glob string) string {
+	if rest, ok := strings.CutPrefix(glob, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.ToSlash(filepath.Join(home, rest))
+		}
+	}
+	return glob
+}
@@ -0,0 +1,58 @@
+package pattern
+
+import "testing"
+
+func TestMatchApplies(t *testing.T) {
+	tests := []struct {
+		expr, s string
+		want    bool
+	}{
+		{"github.com/acme/*-service", "github.com/acme/billing-service", true},
+		{"github.com/acme/*-service", "github.com/acme/billing-api", false},
+		{"github.com/acme/*", "github.com/acme/team/nested", false},
+		{"github.com/acme/**", "github.com/acme/team/nested", true},
+		{"GitHub.com/Acme/API", "github.com/acme/api", true},
+		{"api-v?", "api-v2", true},
+		{"api-v[0-9]", "api-vx", false},
+		{"re:^github\\.com/acme/(api|web)$", "github.com/acme/web", true},
+		{"re:^github\\.com/acme/(api|web)$", "github.com/acme/webapp", false},
+		{"re:script$", "TypeScript", true},
+		{"type*", "typescript", true},
+		{"re:(", "anything", false},
+	}
+	for _, tt := range tests {
+		if got := MatchApplies(tt.expr, tt.s); got != tt.want {
+			t.Errorf("MatchApplies(%q, %q) = %v, want %v", tt.expr, tt.s, got, tt.want)
+		}
+	}
+	if ValidateApplies("re:(") == nil {
+		t.Error("invalid regex validated")
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	scoped := &Pattern{Applies: ApplyConditions{
+		Projects:  []string{"github.com/acme/*-service"},
+		Languages: []string{"go"},
+	}}
+	service := Target{Project: []string{"billing", "github.com/acme/billing-service", "/src/billing"}, Languages: []string{"go"}}
+
+	if !scoped.AppliesTo(service) {
+		t.Error("doesn't apply to a matching project")
+	}
+	if scoped.AppliesTo(Target{Project: []string{"github.com/acme/website"}, Languages: []string{"go"}}) {
+		t.Error("applies to another project")
+	}
+	if scoped.AppliesTo(Target{}) {
+		t.Error("project-scoped pattern applies outside any project")
+	}
+	if scoped.AppliesTo(Target{Project: service.Project, Languages: []string{"python"}}) {
+		t.Error("applies to another language")
+	}
+	if !scoped.AppliesTo(Target{Project: service.Project}) {
+		t.Error("undetected languages should not exclude the pattern")
+	}
+	if !(&Pattern{}).AppliesTo(Target{}) {
+		t.Error("unscoped pattern should apply everywhere")
+	}
+}
@@ -0,0 +1,541 @@
+package pattern
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Filter is a parsed bulk filter expression such as "domain=go",
+// "confidence>=0.8", or "last_used>30d". "tag:docker" is shorthand for
+// "tag=docker".
+type Filter struct {
+	Field string
+	Op    string // = != > >= < <=
+	Value string
+}
+
+// filterOps is ordered so two-character operators are tried first.
+var filterOps = []string{">=", "<=", "!=", "=", ">", "<", ":"}
+
+// FilterFields lists the fields accepted in filter expressions.
+var FilterFields = []string{"name", "domain", "category", "tag", "status", "trust", "confidence", "effectiveness", "usage", "last_used", "age"}
+
+// ParseFilter parses a "field<op>value" expression.
+func ParseFilter(expr string) (Filter, error) {
+	for _, op := range filterOps {
+		if i := strings.Index(expr, op); i > 0 {
+			f := Filter{
+				Field: strings.ToLower(strings.TrimSpace(expr[:i])),
+				Op:    op,
+				Value: strings.TrimSpace(expr[i+len(op):]),
+			}
+			if f.Op == ":" {
+				f.Op = "="
+			}
+			if !containsString(FilterFields, f.Field) {
+				return Filter{}, fmt.Errorf("unknown filter field %q (valid: %s)", f.Field, strings.Join(FilterFields, ", "))
+			}
+			return f, nil
+		}
+	}
+	return Filter{}, fmt.Errorf("invalid filter %q (expected field=value, e.g. domain=go)", expr)
+}
+
+// Match reports whether p satisfies the filter at time now.
+func (f Filter) Match(p *Pattern, now time.Time) (bool, error) {
+	switch f.Field {
+	case "name":
+		ok, err := filepath.Match(f.Value, p.Name)
+		if err != nil {
+			return false, fmt.Errorf("invalid name glob %q: %w", f.Value, err)
+		}
+		return f.equality(ok)
+	case "domain":
+		return f.equality(strings.EqualFold(p.GetPrimaryDomain(), f.Value))
+	case "category", "tag":
+		return f.equality(p.HasTag(f.Value))
+	case "status":
+		status := p.Lifecycle.Status
+		if status == "" {
+			status = StatusActive
+		}
+		return f.equality(string(status) == strings.ToLower(f.Value))
+	case "trust":
+		return f.equality(string(p.Security.TrustLevel) == strings.ToLower(f.Value))
+	case "confidence":
+		return f.compareFloat(p.Learning.OriginalConfidence)
+	case "effectiveness":
+		return f.compareFloat(p.Learning.Effectiveness)
+	case "usage":
+		return f.compareFloat(float64(p.Learning.UsageCount))
+	case "last_used":
+		// Never-used patterns count from creation
+		since := p.Lifecycle.Created
+		if p.Learning.LastUsed != nil {
+			since = *p.Learning.LastUsed
+		}
+		return f.compareAge(now.Sub(since))
+	case "age":
+		return f.compareAge(now.Sub(p.Lifecycle.Created))
+	}
+	return false, fmt.Errorf("unknown filter field %q", f.Field)
+}
+
+func (f Filter) equality(eq bool) (bool, error) {
+	switch f.Op {
+	case "=":
+		return eq, nil
+	case "!=":
+		return !eq, nil
+	}
+	return false, fmt.Errorf("operator %s not supported for %s (use = or !=)", f.Op, f.Field)
+}
+
+func (f Filter) compareFloat(v float64) (bool, error) {
+	want, err := strconv.ParseFloat(f.Value, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid number for %s: %q", f.Field, f.Value)
+	}
+	return compare(v, f.Op, want), nil
+}
+
+func (f Filter) compareAge(age time.Duration) (bool, error) {
+	want, err := ParseAge(f.Value)
+	if err != nil {
+		return false, err
+	}
+	return compare(age.Hours(), f.Op, want.Hours()), nil
+}
+
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+	return false
+}
+
+// ParseAge parses an age like "30d", "2w", or a Go duration like "36h".
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w, or 36h)", s)
+}
+
+// HasTag reports whether the pattern carries tag as a confirmed or inferred tag.
+func (p *Pattern) HasTag(tag string) bool {
+	for _, t := range p.Tags.Confirmed {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	for _, ts := range p.Tags.Inferred {
+		if strings.EqualFold(ts.Tag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns all patterns matching where (nil matches every pattern).
+func (s *Store) Select(where Expr, now time.Time) ([]Pattern, error) {
+	return s.Query(Query{Where: where}, now)
+}
+
+// BulkOptions describes a bulk operation over selected patterns.
+type BulkOptions struct {
+	Filters    []Filter
+	Where      Expr              // combined with Filters; all must match
+	Names      []string          // when set, only these patterns
+	Set        map[string]string // status, trust, confidence, effectiveness, pinned, shared
+	AddTags    []string
+	RemoveTags []string
+	RenameTags map[string]string // old tag (any case) → new tag
+	Delete     bool
+	ExportDir  string
+	DryRun     bool
+}
+
+// Mutates reports whether the options change patterns on disk.
+func (o BulkOptions) Mutates() bool {
+	return len(o.Set) > 0 || len(o.AddTags) > 0 || len(o.RemoveTags) > 0 || len(o.RenameTags) > 0 || o.Delete
+}
+
+// BulkChange lists the changes made (or planned) to one pattern.
+type BulkChange struct {
+	Pattern string
+	Changes []string
+}
+
+// BulkResult is the outcome of a bulk operation.
+type BulkResult struct {
+	Matched    int
+	Changes    []BulkChange
+	SnapshotID string // set when a mutating operation was applied
+	Exported   int
+}
+
+// SettableFields lists the fields accepted by BulkOptions.Set.
+var SettableFields = []string{"status", "trust", "confidence", "effectiveness", "pinned", "shared"}
+
+// Bulk applies opts to every pattern matching opts.Filters and opts.Where. Before any
+// mutation, the affected pattern files are snapshotted so the operation
+// can be reverted with UndoBulk.
+func (s *Store) Bulk(opts BulkOptions) (*BulkResult, error) {
+	if err := validateSet(opts.Set); err != nil {
+		return nil, err
+	}
+
+	where := opts.Where
+	for _, f := range opts.Filters {
+		where = And(where, f)
+	}
+	selected, err := s.Select(where, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Names) > 0 {
+		selected = slices.DeleteFunc(selected, func(p Pattern) bool {
+			return !containsString(opts.Names, p.Name)
+		})
+	}
+
+	result := &BulkResult{Matched: len(selected)}
+	if len(selected) == 0 {
+		return result, nil
+	}
+
+	// Plan changes
+	updated := make([]Pattern, len(selected))
+	for i := range selected {
+		p := selected[i]
+		change := BulkChange{Pattern: p.Name, Changes: applyBulk(&p, opts)}
+		updated[i] = p
+		if len(change.Changes) > 0 {
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	if opts.DryRun {
+		if opts.ExportDir != "" {
+			result.Exported = len(selected)
+		}
+		return result, nil
+	}
+
+	// Export before mutating, so exports reflect the state that was selected
+	if opts.ExportDir != "" {
+		if err := os.MkdirAll(opts.ExportDir, 0755); err != nil {
+			return nil, fmt.Errorf("cannot create export directory: %w", err)
+		}
+		for i := range selected {
+			data, err := yaml.Marshal(&selected[i])
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(opts.ExportDir, selected[i].Name+".yaml"), data, 0644); err != nil {
+				return nil, fmt.Errorf("cannot export %s: %w", selected[i].Name, err)
+			}
+			result.Exported++
+		}
+	}
+
+	if !opts.Mutates() || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	id, err := s.snapshot(selected)
+	if err != nil {
+		return nil, fmt.Errorf("cannot snapshot patterns before bulk change: %w", err)
+	}
+	result.SnapshotID = id
+
+	for i := range updated {
+		if opts.Delete {
+			if err := s.Delete(updated[i].Name); err != nil {
+				return result, err
+			}
+			continue
+		}
+		if err := s.Update(&updated[i]); err != nil {
+			return result, fmt.Errorf("cannot update %s: %w", updated[i].Name, err)
+		}
+	}
+	return result, nil
+}
+
+func validateSet(set map[string]string) error {
+	for field, value := range set {
+		switch field {
+		case "status":
+			switch LifecycleStatus(value) {
+			case StatusActive, StatusDeprecated, StatusArchived:
+			default:
+				return fmt.Errorf("invalid status %q (use active, deprecated, or archived)", value)
+			}
+		case "trust":
+			if TrustLevel(value).Score() == 0 && TrustLevel(value) != TrustUntrusted {
+				return fmt.Errorf("invalid trust level %q", value)
+			}
+		case "confidence", "effectiveness":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v < 0 || v > 1 {
+				return fmt.Errorf("invalid %s %q (use a number between 0 and 1)", field, value)
+			}
+		case "pinned", "shared":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("invalid %s %q (use true or false)", field, value)
+			}
+		default:
+			return fmt.Errorf("cannot set field %q (settable: %s)", field, strings.Join(SettableFields, ", "))
+		}
+	}
+	return nil
+}
+
+// applyBulk mutates p according to opts and describes what changed.
+func applyBulk(p *Pattern, opts BulkOptions) []string {
+	if opts.Delete {
+		return []string{"delete"}
+	}
+
+	var changes []string
+	fields := make([]string, 0, len(opts.Set))
+	for field := range opts.Set {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		value := opts.Set[field]
+		switch field {
+		case "status":
+			if p.Lifecycle.Status != LifecycleStatus(value) {
+				changes = append(changes, fmt.Sprintf("status: %s → %s", p.Lifecycle.Status, value))
+				p.Lifecycle.Status = LifecycleStatus(value)
+			}
+		case "trust":
+			if p.Security.TrustLevel != TrustLevel(value) {
+				changes = append(changes, fmt.Sprintf("trust: %s → %s", p.Security.TrustLevel, value))
+				p.Security.TrustLevel = TrustLevel(value)
+			}
+		case "confidence":
+			v, _ := strconv.ParseFloat(value, 64)
+			if p.Learning.OriginalConfidence != v {
+				changes = append(changes, fmt.Sprintf("confidence: %.2f → %.2f", p.Learning.OriginalConfidence, v))
+				p.Learning.OriginalConfidence = v
+			}
+		case "effectiveness":
+			v, _ := strconv.ParseFloat(value, 64)
+			if p.Learning.Effectiveness != v {
+				changes = append(changes, fmt.Sprintf("effectiveness: %.2f → %.2f", p.Learning.Effectiveness, v))
+				p.Learning.Effectiveness = v
+			}
+		case "pinned":
+			v, _ := strconv.ParseBool(value)
+			if p.Pinned != v {
+				changes = append(changes, fmt.Sprintf("pinned: %t → %t", p.Pinned, v))
+				p.Pinned = v
+			}
+		case "shared":
+			v, _ := strconv.ParseBool(value)
+			if p.TeamShared != v {
+				changes = append(changes, fmt.Sprintf("shared: %t → %t", p.TeamShared, v))
+				p.TeamShared = v
+			}
+		}
+	}
+
+	for _, tag := range opts.AddTags {
+		if !containsFold(p.Tags.Confirmed, tag) {
+			p.Tags.Confirmed = append(p.Tags.Confirmed, tag)
+			changes = append(changes, "+tag "+tag)
+		}
+	}
+
+	for _, tag := range opts.RemoveTags {
+		if !p.HasTag(tag) {
+			continue
+		}
+		var confirmed []string
+		for _, t := range p.Tags.Confirmed {
+			if !strings.EqualFold(t, tag) {
+				confirmed = append(confirmed, t)
+			}
+		}
+		var inferred []TagScore
+		for _, ts := range p.Tags.Inferred {
+			if !strings.EqualFold(ts.Tag, tag) {
+				inferred = append(inferred, ts)
+			}
+		}
+		p.Tags.Confirmed, p.Tags.Inferred = confirmed, inferred
+		changes = append(changes, "-tag "+tag)
+	}
+
+	if len(opts.RenameTags) > 0 {
+		renames := make(TagAliases, len(opts.RenameTags))
+		for from, to := range opts.RenameTags {
+			renames[strings.ToLower(from)] = to
+		}
+		for _, c := range p.NormalizeTags(renames) {
+			changes = append(changes, "tag "+c)
+		}
+	}
+
+	return changes
+}
+
+// bulkManifest records where each snapshotted pattern file came from.
+type bulkManifest struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Files     map[string]string `json:"files"` // snapshot file name → original path
+}
+
+// BulkSnapshotsDir returns where bulk snapshots are kept, next to the
+// patterns directory.
+func (s *Store) BulkSnapshotsDir() string {
+	return filepath.Join(filepath.Dir(s.baseDir), "snapshots", "bulk")
+}
+
+// snapshot copies the current files of patterns into a new snapshot.
+func (s *Store) snapshot(patterns []Pattern) (string, error) {
+	id := time.Now().Format("20060102-150405.000")
+	dir := filepath.Join(s.BulkSnapshotsDir(), id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	manifest := bulkManifest{CreatedAt: time.Now(), Files: make(map[string]string)}
+	for _, p := range patterns {
+		src := s.patternPath(p.Name)
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return "", err
+		}
+		name := p.Name + ".yaml"
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return "", err
+		}
+		manifest.Files[name] = src
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ListBulkSnapshots returns snapshot IDs, oldest first.
+func (s *Store) ListBulkSnapshots() ([]string, error) {
+	entries, err := os.ReadDir(s.BulkSnapshotsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			ids = append(ids, e.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// UndoBulk restores the patterns saved in a snapshot (the latest if id is
+// empty) and removes the snapshot. It returns the number of restored patterns.
+func (s *Store) UndoBulk(id string) (int, error) {
+	if id == "" {
+		ids, err := s.ListBulkSnapshots()
+		if err != nil {
+			return 0, err
+		}
+		if len(ids) == 0 {
+			return 0, fmt.Errorf("no bulk snapshots to undo")
+		}
+		id = ids[len(ids)-1]
+	}
+
+	dir := filepath.Join(s.BulkSnapshotsDir(), id)
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return 0, fmt.Errorf("snapshot not found: %s", id)
+	}
+	var manifest bulkManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return 0, fmt.Errorf("corrupt snapshot manifest: %w", err)
+	}
+
+	restored := 0
+	for name, dst := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return restored, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return restored, err
+		}
+		if err := os.WriteFile(dst, content, 0644); err != nil {
+			return restored, err
+		}
+		s.mirror(dst)
+		restored++
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return restored, err
+	}
+	return restored, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
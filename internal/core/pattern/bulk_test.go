@@ -0,0 +1,217 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Filter
+		wantErr bool
+	}{
+		{"domain=go", Filter{"domain", "=", "go"}, false},
+		{"confidence>=0.8", Filter{"confidence", ">=", "0.8"}, false},
+		{"last_used > 30d", Filter{"last_used", ">", "30d"}, false},
+		{"tag!=legacy", Filter{"tag", "!=", "legacy"}, false},
+		{"color=red", Filter{}, true},
+		{"domain", Filter{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFilter(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFilter(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Now()
+	lastUsed := now.Add(-40 * 24 * time.Hour)
+	p := &Pattern{
+		Name:      "go-errors",
+		Tags:      TagSet{Confirmed: []string{"api"}, Inferred: []TagScore{{Tag: "lesson", Confidence: 0.9}}},
+		Learning:  LearningMeta{OriginalConfidence: 0.85, UsageCount: 3, LastUsed: &lastUsed},
+		Lifecycle: LifecycleMeta{Created: now.Add(-100 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"domain=go", true},
+		{"name=go-*", true},
+		{"category=lesson", true},
+		{"tag=API", true},
+		{"tag=swift", false},
+		{"status=active", true},
+		{"confidence>=0.8", true},
+		{"confidence<0.8", false},
+		{"usage=0", false},
+		{"last_used>30d", true},
+		{"last_used>6w", false},
+		{"age>12w", true},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := f.Match(p, now)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	f, _ := ParseFilter("domain>go")
+	if _, err := f.Match(p, now); err == nil {
+		t.Error("expected error for ordering operator on domain")
+	}
+}
+
+func TestBulkArchiveAndUndo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "patterns")
+	store := NewStore(dir)
+
+	for _, name := range []string{"go-errors", "go-testing", "swift-actors"} {
+		if err := store.Create(&Pattern{Name: name, Content: "content for " + name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	domainGo, _ := ParseFilter("domain=go")
+	opts := BulkOptions{
+		Filters: []Filter{domainGo},
+		Set:     map[string]string{"status": "archived"},
+		AddTags: []string{"legacy"},
+		DryRun:  true,
+	}
+
+	result, err := store.Bulk(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 2 || len(result.Changes) != 2 {
+		t.Fatalf("dry run matched %d, changes %d; want 2, 2", result.Matched, len(result.Changes))
+	}
+	if p, _ := store.Get("go-errors"); p.Lifecycle.Status != StatusActive {
+		t.Error("dry run must not modify patterns")
+	}
+
+	opts.DryRun = false
+	result, err = store.Bulk(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SnapshotID == "" {
+		t.Fatal("expected a snapshot ID")
+	}
+	p, _ := store.Get("go-errors")
+	if p.Lifecycle.Status != StatusArchived || !p.HasTag("legacy") {
+		t.Errorf("bulk change not applied: status=%s tags=%v", p.Lifecycle.Status, p.Tags.Confirmed)
+	}
+	if p, _ := store.Get("swift-actors"); p.Lifecycle.Status != StatusActive {
+		t.Error("non-matching pattern was modified")
+	}
+
+	restored, err := store.UndoBulk("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 2 {
+		t.Errorf("restored %d, want 2", restored)
+	}
+	p, _ = store.Get("go-errors")
+	if p.Lifecycle.Status != StatusActive || p.HasTag("legacy") {
+		t.Error("undo did not restore original pattern")
+	}
+	if ids, _ := store.ListBulkSnapshots(); len(ids) != 0 {
+		t.Errorf("snapshot should be removed after undo, have %v", ids)
+	}
+}
+
+func TestBulkDeleteAndExport(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(filepath.Join(root, "patterns"))
+	store.Create(&Pattern{Name: "old-one", Content: "x"})
+	store.Create(&Pattern{Name: "keep-me", Content: "y"})
+
+	byName, _ := ParseFilter("name=old-*")
+	exportDir := filepath.Join(root, "export")
+	result, err := store.Bulk(BulkOptions{Filters: []Filter{byName}, Delete: true, ExportDir: exportDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Exported != 1 {
+		t.Errorf("exported %d, want 1", result.Exported)
+	}
+	if _, err := os.Stat(filepath.Join(exportDir, "old-one.yaml")); err != nil {
+		t.Error("exported file missing")
+	}
+	if store.Exists("old-one") || !store.Exists("keep-me") {
+		t.Error("delete affected the wrong patterns")
+	}
+
+	if _, err := store.UndoBulk(result.SnapshotID); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Exists("old-one") {
+		t.Error("undo did not restore deleted pattern")
+	}
+}
+
+func TestBulkNamesAndPinned(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "patterns"))
+	for _, name := range []string{"a", "b", "c"} {
+		if err := store.Create(&Pattern{Name: name, Content: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.Bulk(BulkOptions{Names: []string{"a", "c", "missing"}, Set: map[string]string{"pinned": "true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 2 || len(result.Changes) != 2 {
+		t.Fatalf("matched %d, changes %d; want 2, 2", result.Matched, len(result.Changes))
+	}
+	for name, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if p, _ := store.Get(name); p.Pinned != want {
+			t.Errorf("%s pinned = %t, want %t", name, p.Pinned, want)
+		}
+	}
+
+	if _, err := store.UndoBulk(result.SnapshotID); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := store.Get("a"); p.Pinned {
+		t.Error("undo did not unpin a")
+	}
+}
+
+func TestBulkRejectsInvalidSet(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, set := range []map[string]string{
+		{"status": "gone"},
+		{"confidence": "2"},
+		{"pinned": "maybe"},
+		{"content": "x"},
+	} {
+		if _, err := store.Bulk(BulkOptions{Set: set}); err == nil {
+			t.Errorf("expected error for %v", set)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package pattern
+
+import "time"
+
+// MaxChangelog is how many changelog entries a pattern keeps; the oldest
+// go first.
+const MaxChangelog = 20
+
+// ChangeEntry is an entry of a team pattern's changelog: what an update
+// pushed to the team changed, who pushed it, and optionally why.
+type ChangeEntry struct {
+	At      time.Time `yaml:"at"`
+	Author  string    `yaml:"author,omitempty"`
+	Summary string    `yaml:"summary"`        // e.g. "content +3 -1 lines, tags"
+	Note    string    `yaml:"note,omitempty"` // one-line explanation
+}
+
+// TrimChangelog returns the last MaxChangelog entries.
+func TrimChangelog(entries []ChangeEntry) []ChangeEntry {
+	if len(entries) > MaxChangelog {
+		return entries[len(entries)-MaxChangelog:]
+	}
+	return entries
+}
+
+// AddChange appends an entry to a pattern's changelog. The pattern isn't
+// otherwise touched: no revision is kept and its updated time stays.
+func (s *Store) AddChange(name string, e ChangeEntry) error {
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	p.Changelog = TrimChangelog(append(p.Changelog, e))
+	return s.save(p)
+}
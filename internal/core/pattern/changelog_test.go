@@ -0,0 +1,34 @@
+package pattern
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAddChange(t *testing.T) {
+	store := NewStore(t.TempDir()).WithCompression(0)
+	if err := store.Create(&Pattern{Name: "go-errors", Content: "wrap with %w"}); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := store.Get("go-errors")
+
+	for i := 0; i < MaxChangelog+2; i++ {
+		if err := store.AddChange("go-errors", ChangeEntry{At: time.Now().UTC(), Summary: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := store.Get("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Changelog) != MaxChangelog || p.Changelog[0].Summary != "2" {
+		t.Errorf("changelog has %d entries starting at %q; want %d from 2", len(p.Changelog), p.Changelog[0].Summary, MaxChangelog)
+	}
+	if !p.Lifecycle.Updated.Equal(before.Lifecycle.Updated) {
+		t.Error("adding a changelog entry changed the updated time")
+	}
+	if revs, _ := store.History("go-errors"); len(revs) != 0 {
+		t.Errorf("adding a changelog entry made %d revisions", len(revs))
+	}
+}
@@ -0,0 +1,240 @@
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/zstd"
+)
+
+// CompressedExt is the extension of a zstd-compressed pattern file.
+const CompressedExt = ".yaml.zst"
+
+// DefaultCompressThreshold is the YAML size, in bytes, above which a
+// pattern is compressed when storage.compress is on.
+const DefaultCompressThreshold = 8192
+
+// IsPatternFile reports whether a file name is a pattern, plain or
+// compressed.
+func IsPatternFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, CompressedExt)
+}
+
+// PatternFileName returns the pattern name for a pattern file name.
+func PatternFileName(name string) string {
+	if n, ok := strings.CutSuffix(name, CompressedExt); ok {
+		return n
+	}
+	return strings.TrimSuffix(name, ".yaml")
+}
+
+// ReadFile reads a pattern file, decompressing it if it is a .yaml.zst.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(path, data)
+}
+
+// Decode returns the YAML of the pattern file named name, whose contents
+// are data: data itself, or decompressed if it is a .yaml.zst, e.g. read
+// from git.
+func Decode(name string, data []byte) ([]byte, error) {
+	if !strings.HasSuffix(name, CompressedExt) {
+		return data, nil
+	}
+	out, err := io.ReadAll(zstd.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress %s: %w", filepath.Base(name), err)
+	}
+	return out, nil
+}
+
+// WriteFile writes a pattern's YAML to path, compressed if it is a
+// .yaml.zst.
+func WriteFile(path string, data []byte) error {
+	if strings.HasSuffix(path, CompressedExt) {
+		data = zstd.Compress(data)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// OtherVariant returns the other file a pattern can be stored in next to
+// path: the .yaml.zst of a .yaml, and the other way round. Whoever writes
+// one removes the other, which would otherwise shadow it or come back.
+func OtherVariant(path string) string {
+	if base, ok := strings.CutSuffix(path, CompressedExt); ok {
+		return base + ".yaml"
+	}
+	return strings.TrimSuffix(path, ".yaml") + CompressedExt
+}
+
+// CompressThreshold returns the size above which patterns are stored
+// compressed, or 0 when compression is off.
+func CompressThreshold(cfg *config.Config) int {
+	if cfg == nil || !cfg.Storage.Compress {
+		return 0
+	}
+	if cfg.Storage.CompressThreshold > 0 {
+		return cfg.Storage.CompressThreshold
+	}
+	return DefaultCompressThreshold
+}
+
+// WithCompression makes the store compress patterns whose YAML is larger
+// than threshold bytes; 0 stores everything plain. Without it the store
+// follows storage.compress in config.
+func (s *Store) WithCompression(threshold int) *Store {
+	s.threshold = threshold
+	s.thresholdSet = true
+	return s
+}
+
+func (s *Store) compressThreshold() int {
+	if !s.thresholdSet {
+		cfg, _ := config.Load()
+		s.threshold = CompressThreshold(cfg)
+		s.thresholdSet = true
+	}
+	return s.threshold
+}
+
+// encodeFile returns the file a pattern's YAML goes to next to path and
+// its contents, compressed if it is over the threshold, plus the other
+// variant, which must not be left behind.
+func (s *Store) encodeFile(path string, data []byte) (target, stale string, out []byte) {
+	base := strings.TrimSuffix(strings.TrimSuffix(path, CompressedExt), ".yaml")
+	plain, compressed := base+".yaml", base+CompressedExt
+
+	if t := s.compressThreshold(); t > 0 && len(data) > t {
+		return compressed, plain, zstd.Compress(data)
+	}
+	return plain, compressed, data
+}
+
+// writeFile writes a pattern's YAML next to path, compressed if it is
+// over the threshold, and removes the other variant.
+func (s *Store) writeFile(path string, data []byte) error {
+	target, stale, data := s.encodeFile(path, data)
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Remove(stale); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.mirror(target, stale)
+	return nil
+}
+
+// StorageReport summarizes pattern files on disk.
+type StorageReport struct {
+	Files      int   // pattern files
+	Compressed int   // of which .yaml.zst
+	Changed    int   // files compressed or decompressed by Recompress
+	RawBytes   int64 // YAML size of all patterns
+	DiskBytes  int64 // size on disk
+	// Largest lists the biggest patterns by YAML size, largest first.
+	Largest []StorageEntry
+}
+
+// StorageEntry is one pattern file in a StorageReport.
+type StorageEntry struct {
+	Name       string
+	RawBytes   int64
+	DiskBytes  int64
+	Compressed bool
+}
+
+// Saved returns the bytes saved by compression.
+func (r *StorageReport) Saved() int64 {
+	return r.RawBytes - r.DiskBytes
+}
+
+// Usage reports how much space the store's own patterns take. Patterns
+// from the synced repo are left out; they're managed by git.
+func (s *Store) Usage() (*StorageReport, error) {
+	return s.recompress(-1, true)
+}
+
+// Recompress rewrites the store's patterns so those over threshold bytes
+// are compressed and the rest are plain; a threshold of 0 decompresses
+// everything. With dryRun it only reports what would change.
+func (s *Store) Recompress(threshold int, dryRun bool) (*StorageReport, error) {
+	return s.recompress(threshold, dryRun)
+}
+
+// recompress walks baseDir; a negative threshold leaves files as they are.
+func (s *Store) recompress(threshold int, dryRun bool) (*StorageReport, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if os.IsNotExist(err) {
+		return &StorageReport{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read patterns: %w", err)
+	}
+
+	w := &Store{baseDir: s.baseDir, localOnly: true}
+	w.WithCompression(threshold)
+
+	r := &StorageReport{}
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(s.baseDir, entry.Name())
+		data, err := ReadFile(path)
+		if err != nil {
+			return r, err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return r, err
+		}
+
+		compressed := strings.HasSuffix(path, CompressedExt)
+		disk := info.Size()
+		if threshold >= 0 {
+			want := threshold > 0 && len(data) > threshold
+			if want != compressed {
+				r.Changed++
+				compressed = want
+				if compressed {
+					disk = int64(len(zstd.Compress(data)))
+				} else {
+					disk = int64(len(data))
+				}
+				if !dryRun {
+					if err := w.writeFile(path, data); err != nil {
+						return r, fmt.Errorf("cannot rewrite %s: %w", entry.Name(), err)
+					}
+				}
+			}
+		}
+
+		r.Files++
+		if compressed {
+			r.Compressed++
+		}
+		r.RawBytes += int64(len(data))
+		r.DiskBytes += disk
+		r.Largest = append(r.Largest, StorageEntry{
+			Name:       PatternFileName(entry.Name()),
+			RawBytes:   int64(len(data)),
+			DiskBytes:  disk,
+			Compressed: compressed,
+		})
+	}
+
+	sort.Slice(r.Largest, func(i, j int) bool { return r.Largest[i].RawBytes > r.Largest[j].RawBytes })
+	if len(r.Largest) > 10 {
+		r.Largest = r.Largest[:10]
+	}
+	return r, nil
+}
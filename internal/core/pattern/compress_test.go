@@ -0,0 +1,102 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreCompressesLargePatterns(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir).WithCompression(1024)
+
+	small := &Pattern{Name: "small", Content: "Keep functions short."}
+	large := &Pattern{Name: "large", Content: strings.Repeat("Wrap errors with fmt.Errorf and %w.\n", 200)}
+	for _, p := range []*Pattern{small, large} {
+		if err := store.Create(p); err != nil {
+			t.Fatalf("Create(%s): %v", p.Name, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "small.yaml")); err != nil {
+		t.Errorf("small pattern not stored plain: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "large"+CompressedExt)); err != nil {
+		t.Errorf("large pattern not stored compressed: %v", err)
+	}
+
+	got, err := store.Get("large")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Content != large.Content {
+		t.Error("compressed pattern content changed")
+	}
+	if list, _ := store.List(); len(list) != 2 {
+		t.Errorf("List returned %d patterns, want 2", len(list))
+	}
+
+	// Shrinking a pattern stores it plain again.
+	got.Content = "short now"
+	if err := store.Update(got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "large"+CompressedExt)); !os.IsNotExist(err) {
+		t.Error("stale compressed file left behind")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "large.yaml")); err != nil {
+		t.Errorf("shrunk pattern not stored plain: %v", err)
+	}
+
+	if err := store.Delete("small"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestStoreRecompress(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir).WithCompression(0)
+	for i, name := range []string{"a", "b", "c"} {
+		p := &Pattern{Name: name, Content: strings.Repeat("Prefer table-driven tests. ", 100*i+1)}
+		if err := store.Create(p); err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+	}
+
+	dry, err := store.Recompress(2048, true)
+	if err != nil {
+		t.Fatalf("Recompress dry run: %v", err)
+	}
+	if dry.Changed != 2 || dry.Files != 3 {
+		t.Errorf("dry run changed %d of %d, want 2 of 3", dry.Changed, dry.Files)
+	}
+	if usage, _ := store.Usage(); usage.Compressed != 0 {
+		t.Error("dry run compressed files")
+	}
+
+	report, err := store.Recompress(2048, false)
+	if err != nil {
+		t.Fatalf("Recompress: %v", err)
+	}
+	if report.Compressed != 2 || report.Saved() <= 0 {
+		t.Errorf("Recompress = %d compressed, %d saved", report.Compressed, report.Saved())
+	}
+	if report.DiskBytes != dry.DiskBytes {
+		t.Errorf("dry run predicted %d bytes, got %d", dry.DiskBytes, report.DiskBytes)
+	}
+	if report.Largest[0].Name != "c" {
+		t.Errorf("largest = %s, want c", report.Largest[0].Name)
+	}
+
+	back, err := store.Recompress(0, false)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if back.Changed != 2 || back.Compressed != 0 || back.DiskBytes != back.RawBytes {
+		t.Errorf("decompress = %+v", back)
+	}
+	if p, err := store.Get("c"); err != nil || !strings.HasPrefix(p.Content, "Prefer") {
+		t.Errorf("Get after decompress: %v", err)
+	}
+}
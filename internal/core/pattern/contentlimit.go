@@ -0,0 +1,146 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mur-run/mur-core/internal/config"
+	murlog "github.com/mur-run/mur-core/internal/log"
+)
+
+// DefaultMaxContentBytes is the largest pattern content stored as is when
+// storage.max_content_bytes isn't set.
+const DefaultMaxContentBytes = 50000
+
+// What the store does with content over the limit (storage.oversize).
+const (
+	OversizeSplit    = "split"    // move the overflow to an L3 examples file
+	OversizeTruncate = "truncate" // drop the overflow
+	OversizeReject   = "reject"   // refuse to save
+)
+
+// ErrContentTooLarge is returned when content over the limit is rejected.
+var ErrContentTooLarge = errors.New("pattern content too large")
+
+// ExamplesFile is the L3 resource oversized content is split into.
+const ExamplesFile = "examples.md"
+
+// truncatedNote ends content cut to the limit.
+const truncatedNote = "\n\n*(truncated)*\n"
+
+// ContentLimit returns the largest content stored as is (0 for no limit)
+// and what happens to larger content.
+func ContentLimit(cfg *config.Config) (int, string) {
+	limit, action := DefaultMaxContentBytes, OversizeSplit
+	if cfg == nil {
+		return limit, action
+	}
+	if cfg.Storage.MaxContentBytes != 0 {
+		limit = cfg.Storage.MaxContentBytes
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	switch cfg.Storage.Oversize {
+	case OversizeTruncate, OversizeReject:
+		action = cfg.Storage.Oversize
+	}
+	return limit, action
+}
+
+// WithContentLimit makes the store handle content larger than limit bytes
+// with action; 0 stores content of any size. Without it the store follows
+// storage.max_content_bytes and storage.oversize in config.
+func (s *Store) WithContentLimit(limit int, action string) *Store {
+	s.limit, s.oversize = limit, action
+	s.limitSet = true
+	return s
+}
+
+func (s *Store) contentLimit() (int, string) {
+	if !s.limitSet {
+		cfg, _ := config.Load()
+		s.limit, s.oversize = ContentLimit(cfg)
+		s.limitSet = true
+	}
+	return s.limit, s.oversize
+}
+
+// ResourcesDir returns where a pattern's L3 resources are kept.
+func (s *Store) ResourcesDir(name string) string {
+	return filepath.Join(s.baseDir, ".resources", name)
+}
+
+// fitContent applies the content limit to p before it is saved.
+func (s *Store) fitContent(p *Pattern) error {
+	content, split, err := s.FitContent(p.Name, p.Content)
+	if err != nil {
+		return err
+	}
+	p.Content = content
+	if split {
+		p.Resources.HasExamples = true
+	}
+	return nil
+}
+
+// FitContent returns the content of pattern name cut to the store's
+// content limit. Split content keeps what fits and moves the rest to the
+// pattern's examples file (split reports this); truncated content loses
+// the rest. Either way a warning goes to stderr.
+func (s *Store) FitContent(name, content string) (string, bool, error) {
+	limit, action := s.contentLimit()
+	if limit <= 0 || len(content) <= limit {
+		return content, false, nil
+	}
+	size := len(content)
+
+	switch action {
+	case OversizeReject:
+		murlog.For("pattern").Warn("content rejected", "pattern", name, "size", size, "limit", limit)
+		return "", false, fmt.Errorf("%w: %s is %d bytes, limit %d (storage.max_content_bytes)", ErrContentTooLarge, name, size, limit)
+
+	case OversizeTruncate:
+		content = cutContent(content, limit-len(truncatedNote)) + truncatedNote
+		fmt.Fprintf(os.Stderr, "warning: %s: content truncated from %d to %d bytes (storage.max_content_bytes)\n", name, size, len(content))
+		murlog.For("pattern").Warn("content truncated", "pattern", name, "size", size, "limit", limit)
+		return content, false, nil
+	}
+
+	dir := s.ResourcesDir(name)
+	path := filepath.Join(dir, ExamplesFile)
+	note := fmt.Sprintf("\n\n*(continued in %s)*\n", path)
+	head := cutContent(content, limit-len(note))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("cannot create resources directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content[len(head):]), 0644); err != nil {
+		return "", false, fmt.Errorf("cannot write %s: %w", ExamplesFile, err)
+	}
+	fmt.Fprintf(os.Stderr, "warning: %s: content is %d bytes; moved %d bytes over the limit to %s\n", name, size, size-len(head), path)
+	murlog.For("pattern").Warn("content split", "pattern", name, "size", size, "limit", limit, "examples", path)
+	return head + note, true, nil
+}
+
+// cutContent returns the start of s up to n bytes, ending at a line break
+// when there is one in the second half, and never inside a UTF-8 rune.
+func cutContent(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	head := s[:n]
+	if i := strings.LastIndexByte(head, '\n'); i >= n/2 {
+		return head[:i+1]
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,79 @@
+package pattern
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreSplitsOversizedContent(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir).WithContentLimit(1000, OversizeSplit)
+
+	content := strings.Repeat("Retry idempotent requests with backoff.\n", 100)
+	p := &Pattern{Name: "runaway", Content: content}
+	if err := store.Create(p); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := store.Get("runaway")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.Content) > 1000 {
+		t.Errorf("content is %d bytes, want at most 1000", len(got.Content))
+	}
+	if !got.Resources.HasExamples {
+		t.Error("HasExamples not set")
+	}
+	path := filepath.Join(store.ResourcesDir("runaway"), ExamplesFile)
+	if !strings.Contains(got.Content, path) {
+		t.Errorf("content doesn't point to %s", path)
+	}
+	rest, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("examples not written: %v", err)
+	}
+	head, _, _ := strings.Cut(got.Content, "\n\n*(continued")
+	if head+string(rest) != content {
+		t.Error("split content doesn't add up to the original")
+	}
+	if list, _ := store.List(); len(list) != 1 {
+		t.Errorf("List returned %d patterns, want 1", len(list))
+	}
+}
+
+func TestStoreTruncatesAndRejects(t *testing.T) {
+	content := strings.Repeat("日本語のテキスト。", 200)
+
+	store := NewStore(t.TempDir()).WithContentLimit(500, OversizeTruncate)
+	p := &Pattern{Name: "long", Content: "short"}
+	if err := store.Create(p); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	p.Content = content
+	if err := store.Update(p); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ := store.Get("long")
+	if len(got.Content) > 500 || !strings.HasSuffix(got.Content, truncatedNote) {
+		t.Errorf("content not truncated: %d bytes", len(got.Content))
+	}
+	if !strings.HasPrefix(content, strings.TrimSuffix(got.Content, truncatedNote)) {
+		t.Error("truncation split a rune or changed the content")
+	}
+
+	store = NewStore(t.TempDir()).WithContentLimit(500, OversizeReject)
+	err := store.Create(&Pattern{Name: "long", Content: content})
+	if !errors.Is(err, ErrContentTooLarge) {
+		t.Errorf("Create = %v, want ErrContentTooLarge", err)
+	}
+
+	// No limit
+	store = NewStore(t.TempDir()).WithContentLimit(0, OversizeReject)
+	if err := store.Create(&Pattern{Name: "long", Content: content}); err != nil {
+		t.Errorf("Create without limit: %v", err)
+	}
+}
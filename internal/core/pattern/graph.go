@@ -0,0 +1,265 @@
+package pattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph edge kinds.
+const (
+	EdgeRelated    = "related"
+	EdgeSupersedes = "supersedes"
+	EdgeConflicts  = "conflicts"
+	EdgeTags       = "tags" // shared (non-domain) tags
+)
+
+// Graph node kinds.
+const (
+	NodePattern = "pattern"
+	NodeCluster = "cluster" // patterns of one domain collapsed for level of detail
+)
+
+// DefaultGraphNodes is how many patterns a graph shows before collapsing
+// the rest into one cluster node per domain.
+const DefaultGraphNodes = 300
+
+// maxTagFanout skips tags carried by more patterns than this when linking
+// patterns by shared tags; such tags connect everything and reveal nothing.
+const maxTagFanout = 30
+
+// GraphNode is a pattern, or a cluster of collapsed patterns, in a Graph.
+type GraphNode struct {
+	ID            string   `json:"id"`
+	Kind          string   `json:"kind"`
+	Label         string   `json:"label"`
+	Domain        string   `json:"domain"`
+	Usage         int      `json:"usage"`
+	Effectiveness float64  `json:"effectiveness,omitempty"`
+	Status        string   `json:"status,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Count         int      `json:"count,omitempty"`  // patterns in a cluster
+	Orphan        bool     `json:"orphan,omitempty"` // no relations or shared tags
+}
+
+// GraphEdge links two nodes. Supersedes edges point from the newer pattern
+// to the one it replaces; the other kinds are undirected.
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+	Weight int    `json:"weight"` // shared tags, or links merged into a cluster
+}
+
+// Graph is the pattern store as nodes and edges.
+type Graph struct {
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+	Total     int         `json:"total"`     // patterns in the graph
+	Collapsed int         `json:"collapsed"` // of those, folded into clusters
+	Orphans   int         `json:"orphans"`
+}
+
+// GraphOptions controls what BuildGraph includes.
+type GraphOptions struct {
+	// MaxNodes is how many patterns are shown individually, the most used
+	// first; the rest are collapsed into a cluster per domain. 0 means
+	// DefaultGraphNodes.
+	MaxNodes int
+	// MinSharedTags is how many tags two patterns must share to be
+	// linked; 0 disables tag edges.
+	MinSharedTags int
+}
+
+// BuildGraph builds the relationship graph of patterns: relations and
+// shared tags as edges, sized by usage and grouped by domain. Orphans are
+// determined on the full graph, before any collapsing.
+func BuildGraph(patterns []Pattern, opts GraphOptions) Graph {
+	if opts.MaxNodes <= 0 {
+		opts.MaxNodes = DefaultGraphNodes
+	}
+
+	byName := make(map[string]*Pattern, len(patterns))
+	for i := range patterns {
+		byName[patterns[i].Name] = &patterns[i]
+	}
+
+	type edgeKey struct{ a, b, kind string }
+	weights := make(map[edgeKey]int)
+	link := func(a, b, kind string, w int) {
+		if a == b || byName[a] == nil || byName[b] == nil {
+			return
+		}
+		if kind != EdgeSupersedes && a > b {
+			a, b = b, a
+		}
+		k := edgeKey{a, b, kind}
+		if weights[k] == 0 || kind == EdgeTags {
+			weights[k] += w
+		}
+	}
+
+	tagged := make(map[string][]string) // tag -> pattern names
+	for i := range patterns {
+		p := &patterns[i]
+		if p.Relations.Supersedes != "" {
+			link(p.Name, p.Relations.Supersedes, EdgeSupersedes, 1)
+		}
+		for _, r := range p.Relations.Related {
+			link(p.Name, r, EdgeRelated, 1)
+		}
+		for _, c := range p.Relations.ConflictsWith {
+			link(p.Name, c, EdgeConflicts, 1)
+		}
+		if opts.MinSharedTags > 0 {
+			for _, t := range graphTags(p) {
+				if !isDomainTag(t) {
+					tagged[t] = append(tagged[t], p.Name)
+				}
+			}
+		}
+	}
+	if opts.MinSharedTags > 0 {
+		shared := make(map[edgeKey]int)
+		for _, names := range tagged {
+			if len(names) > maxTagFanout {
+				continue
+			}
+			for i := range names {
+				for j := i + 1; j < len(names); j++ {
+					a, b := names[i], names[j]
+					if a > b {
+						a, b = b, a
+					}
+					shared[edgeKey{a, b, EdgeTags}]++
+				}
+			}
+		}
+		for k, n := range shared {
+			if n >= opts.MinSharedTags {
+				link(k.a, k.b, EdgeTags, n)
+			}
+		}
+	}
+
+	degree := make(map[string]int)
+	for k := range weights {
+		degree[k.a]++
+		degree[k.b]++
+	}
+
+	// Level of detail: the most used patterns stay, the rest are clustered
+	ranked := make([]*Pattern, len(patterns))
+	for i := range patterns {
+		ranked[i] = &patterns[i]
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Learning.UsageCount != b.Learning.UsageCount {
+			return a.Learning.UsageCount > b.Learning.UsageCount
+		}
+		if degree[a.Name] != degree[b.Name] {
+			return degree[a.Name] > degree[b.Name]
+		}
+		return a.Name < b.Name
+	})
+
+	g := Graph{Total: len(patterns)}
+	nodeOf := make(map[string]string, len(patterns))
+	clusters := make(map[string]int) // domain -> index in g.Nodes
+	for i, p := range ranked {
+		domain := p.GetPrimaryDomain()
+		orphan := degree[p.Name] == 0
+		if orphan {
+			g.Orphans++
+		}
+		if i < opts.MaxNodes {
+			nodeOf[p.Name] = p.Name
+			g.Nodes = append(g.Nodes, GraphNode{
+				ID:            p.Name,
+				Kind:          NodePattern,
+				Label:         p.Name,
+				Domain:        domain,
+				Usage:         p.Learning.UsageCount,
+				Effectiveness: p.Learning.Effectiveness,
+				Status:        string(p.Lifecycle.Status),
+				Tags:          graphTags(p),
+				Orphan:        orphan,
+			})
+			continue
+		}
+		id := "cluster:" + domain
+		idx, ok := clusters[domain]
+		if !ok {
+			idx = len(g.Nodes)
+			clusters[domain] = idx
+			g.Nodes = append(g.Nodes, GraphNode{ID: id, Kind: NodeCluster, Domain: domain})
+		}
+		g.Nodes[idx].Count++
+		g.Nodes[idx].Usage += p.Learning.UsageCount
+		nodeOf[p.Name] = id
+		g.Collapsed++
+	}
+	for _, idx := range clusters {
+		n := &g.Nodes[idx]
+		n.Label = fmt.Sprintf("%s (%d patterns)", n.Domain, n.Count)
+		if n.Count == 1 {
+			n.Label = n.Domain + " (1 pattern)"
+		}
+	}
+
+	merged := make(map[edgeKey]int)
+	for k, w := range weights {
+		a, b := nodeOf[k.a], nodeOf[k.b]
+		if a == b {
+			continue
+		}
+		if k.kind != EdgeSupersedes && a > b {
+			a, b = b, a
+		}
+		merged[edgeKey{a, b, k.kind}] += w
+	}
+	for k, w := range merged {
+		g.Edges = append(g.Edges, GraphEdge{Source: k.a, Target: k.b, Kind: k.kind, Weight: w})
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Kind < b.Kind
+	})
+	if g.Nodes == nil {
+		g.Nodes = []GraphNode{}
+	}
+	if g.Edges == nil {
+		g.Edges = []GraphEdge{}
+	}
+	return g
+}
+
+// graphTags returns the confirmed and high-confidence inferred tags,
+// lowercased and without duplicates.
+func graphTags(p *Pattern) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	add := func(t string) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	for _, t := range p.Tags.Confirmed {
+		add(t)
+	}
+	for _, ts := range p.Tags.Inferred {
+		if ts.Confidence >= 0.7 {
+			add(ts.Tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,110 @@
+package pattern
+
+import "testing"
+
+func graphFixture() []Pattern {
+	tagged := func(tags ...string) TagSet { return TagSet{Confirmed: tags} }
+	return []Pattern{
+		{Name: "go-errors", Tags: tagged("go", "errors", "wrapping"), Learning: LearningMeta{UsageCount: 9},
+			Relations: Relations{Related: []string{"go-panics", "missing"}}},
+		{Name: "go-panics", Tags: tagged("go", "errors", "wrapping"), Learning: LearningMeta{UsageCount: 5},
+			Relations: Relations{Related: []string{"go-errors"}}},
+		{Name: "go-errors-v2", Tags: tagged("go"), Learning: LearningMeta{UsageCount: 3},
+			Relations: Relations{Supersedes: "go-errors"}},
+		{Name: "swift-actors", Tags: tagged("swift", "concurrency"), Learning: LearningMeta{UsageCount: 1}},
+		{Name: "swift-tasks", Tags: tagged("swift", "concurrency"), Learning: LearningMeta{UsageCount: 0}},
+		{Name: "lonely", Tags: tagged("go"), Learning: LearningMeta{UsageCount: 2}},
+	}
+}
+
+func findEdge(g Graph, source, target, kind string) *GraphEdge {
+	for i, e := range g.Edges {
+		if e.Source == source && e.Target == target && e.Kind == kind {
+			return &g.Edges[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildGraph(t *testing.T) {
+	g := BuildGraph(graphFixture(), GraphOptions{MinSharedTags: 1})
+
+	if g.Total != 6 || len(g.Nodes) != 6 || g.Collapsed != 0 {
+		t.Fatalf("Total = %d, nodes = %d, collapsed = %d", g.Total, len(g.Nodes), g.Collapsed)
+	}
+	if g.Nodes[0].ID != "go-errors" || g.Nodes[0].Domain != "go" {
+		t.Errorf("first node = %+v, want most used go-errors", g.Nodes[0])
+	}
+
+	// Related both ways is one undirected edge; the missing target is dropped
+	if e := findEdge(g, "go-errors", "go-panics", EdgeRelated); e == nil || e.Weight != 1 {
+		t.Errorf("related edge = %+v", e)
+	}
+	if e := findEdge(g, "go-errors-v2", "go-errors", EdgeSupersedes); e == nil {
+		t.Error("supersedes edge missing")
+	}
+	// Shared tags exclude domain tags: errors and wrapping, not go
+	if e := findEdge(g, "go-errors", "go-panics", EdgeTags); e == nil || e.Weight != 2 {
+		t.Errorf("tag edge = %+v, want weight 2", e)
+	}
+	if e := findEdge(g, "swift-actors", "swift-tasks", EdgeTags); e == nil {
+		t.Error("concurrency tag edge missing")
+	}
+	if len(g.Edges) != 4 {
+		t.Errorf("edges = %+v, want 4", g.Edges)
+	}
+
+	if g.Orphans != 1 {
+		t.Errorf("Orphans = %d, want 1", g.Orphans)
+	}
+	for _, n := range g.Nodes {
+		if n.Orphan != (n.ID == "lonely") {
+			t.Errorf("%s orphan = %v", n.ID, n.Orphan)
+		}
+	}
+
+	// Without tag edges the swift patterns are orphans too
+	if g := BuildGraph(graphFixture(), GraphOptions{}); g.Orphans != 3 {
+		t.Errorf("Orphans without tag edges = %d, want 3", g.Orphans)
+	}
+	if g := BuildGraph(graphFixture(), GraphOptions{MinSharedTags: 2}); findEdge(g, "swift-actors", "swift-tasks", EdgeTags) != nil {
+		t.Error("tag edge below MinSharedTags kept")
+	}
+}
+
+func TestBuildGraphCollapses(t *testing.T) {
+	g := BuildGraph(graphFixture(), GraphOptions{MaxNodes: 2, MinSharedTags: 1})
+
+	if g.Collapsed != 4 {
+		t.Errorf("Collapsed = %d, want 4", g.Collapsed)
+	}
+	// go-errors, go-panics, then a go cluster and a swift cluster
+	if len(g.Nodes) != 4 {
+		t.Fatalf("nodes = %+v", g.Nodes)
+	}
+	var goCluster *GraphNode
+	for i, n := range g.Nodes {
+		if n.ID == "cluster:go" {
+			goCluster = &g.Nodes[i]
+		}
+	}
+	if goCluster == nil || goCluster.Kind != NodeCluster || goCluster.Count != 2 || goCluster.Usage != 5 {
+		t.Fatalf("go cluster = %+v", goCluster)
+	}
+	if goCluster.Label != "go (2 patterns)" {
+		t.Errorf("Label = %q", goCluster.Label)
+	}
+	// Edges into collapsed patterns point at their cluster
+	if findEdge(g, "cluster:go", "go-errors", EdgeSupersedes) == nil {
+		t.Errorf("edges = %+v, want supersedes from the go cluster", g.Edges)
+	}
+	// Edges inside a cluster disappear
+	for _, e := range g.Edges {
+		if e.Source == e.Target {
+			t.Errorf("self edge %+v", e)
+		}
+	}
+	if g.Orphans != 1 {
+		t.Errorf("Orphans = %d, want 1 (counted before collapsing)", g.Orphans)
+	}
+}
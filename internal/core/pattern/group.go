@@ -0,0 +1,143 @@
+package pattern
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Categories are the kinds of pattern. v1 patterns had one as a field;
+// in v2 it is one of the pattern's tags.
+var Categories = []string{"pattern", "decision", "lesson", "reference", "template", "debug"}
+
+// GroupLevels are what patterns can be grouped by, in the default order.
+var GroupLevels = []string{"domain", "category", "tag"}
+
+// GetCategory returns the pattern's category tag, or "pattern" if it has
+// none.
+func (p *Pattern) GetCategory() string {
+	for _, c := range Categories {
+		if p.HasTag(c) {
+			return c
+		}
+	}
+	return "pattern"
+}
+
+// GetPrimaryTag returns the pattern's most telling tag besides its domain
+// and category: the first confirmed one, else the most confident inferred
+// one. It returns "untagged" if there is none.
+func (p *Pattern) GetPrimaryTag() string {
+	skip := func(tag string) bool {
+		tag = strings.ToLower(tag)
+		return tag == "" || tag == p.GetPrimaryDomain() || slices.Contains(Categories, tag)
+	}
+	for _, t := range p.Tags.Confirmed {
+		if !skip(t) {
+			return strings.ToLower(t)
+		}
+	}
+	best := -1.0
+	primary := ""
+	for _, ts := range p.Tags.Inferred {
+		if !skip(ts.Tag) && ts.Confidence > best {
+			best, primary = ts.Confidence, strings.ToLower(ts.Tag)
+		}
+	}
+	if primary == "" {
+		return "untagged"
+	}
+	return primary
+}
+
+// GroupKey returns the group p falls in at level (see GroupLevels).
+func GroupKey(p *Pattern, level string) string {
+	switch level {
+	case "domain":
+		return p.GetPrimaryDomain()
+	case "category":
+		return p.GetCategory()
+	case "tag":
+		return p.GetPrimaryTag()
+	}
+	return ""
+}
+
+// ParseGroupLevels parses a comma-separated list of group levels.
+func ParseGroupLevels(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return GroupLevels, nil
+	}
+	var levels []string
+	for _, l := range strings.Split(spec, ",") {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if !slices.Contains(GroupLevels, l) {
+			return nil, fmt.Errorf("unknown group level %q (valid: %s)", l, strings.Join(GroupLevels, ", "))
+		}
+		if slices.Contains(levels, l) {
+			return nil, fmt.Errorf("group level %q given twice", l)
+		}
+		levels = append(levels, l)
+	}
+	return levels, nil
+}
+
+// Group is a set of patterns sharing a domain, category or tag, with
+// roll-ups over all the patterns in it.
+type Group struct {
+	Level         string // domain, category or tag
+	Name          string
+	Count         int       // patterns in the group, including subgroups
+	Effectiveness float64   // mean effectiveness
+	Uses          int       // total usage count
+	Groups        []Group   // subgroups at the next level
+	Patterns      []Pattern // at the last level only
+}
+
+// GroupPatterns groups patterns by levels, outermost first. Each pattern
+// is in exactly one group per level. Groups come largest first; patterns
+// in a group keep their order.
+func GroupPatterns(patterns []Pattern, levels []string) []Group {
+	if len(levels) == 0 {
+		return nil
+	}
+	level := levels[0]
+	index := make(map[string]int)
+	var groups []Group
+	var members [][]Pattern
+	for _, p := range patterns {
+		key := GroupKey(&p, level)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{Level: level, Name: key})
+			members = append(members, nil)
+		}
+		members[i] = append(members[i], p)
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		g.Count = len(members[i])
+		var eff float64
+		for _, p := range members[i] {
+			eff += p.Learning.Effectiveness
+			g.Uses += p.Learning.UsageCount
+		}
+		g.Effectiveness = eff / float64(g.Count)
+		if len(levels) > 1 {
+			g.Groups = GroupPatterns(members[i], levels[1:])
+		} else {
+			g.Patterns = members[i]
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
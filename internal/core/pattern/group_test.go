@@ -0,0 +1,66 @@
+package pattern
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupPatterns(t *testing.T) {
+	patterns := []Pattern{
+		{Name: "go-errors", Tags: TagSet{Confirmed: []string{"go", "errors"}, Inferred: []TagScore{{Tag: "lesson", Confidence: 0.9}}},
+			Learning: LearningMeta{Effectiveness: 0.8, UsageCount: 4}},
+		{Name: "go-context", Tags: TagSet{Inferred: []TagScore{{Tag: "lesson", Confidence: 0.6}, {Tag: "concurrency", Confidence: 0.8}}},
+			Learning: LearningMeta{Effectiveness: 0.6, UsageCount: 1}},
+		{Name: "go-modules", Learning: LearningMeta{Effectiveness: 0.4}},
+		{Name: "swift-actors", Tags: TagSet{Confirmed: []string{"concurrency"}}, Learning: LearningMeta{Effectiveness: 0.5}},
+	}
+
+	if got := patterns[0].GetCategory(); got != "lesson" {
+		t.Errorf("GetCategory = %q, want lesson", got)
+	}
+	if got := patterns[2].GetCategory(); got != "pattern" {
+		t.Errorf("GetCategory without a category tag = %q, want pattern", got)
+	}
+	if got := patterns[0].GetPrimaryTag(); got != "errors" {
+		t.Errorf("GetPrimaryTag = %q, want errors (not the domain)", got)
+	}
+	if got := patterns[1].GetPrimaryTag(); got != "concurrency" {
+		t.Errorf("GetPrimaryTag = %q, want concurrency (not the category)", got)
+	}
+
+	groups := GroupPatterns(patterns, []string{"domain", "category"})
+	if len(groups) != 2 || groups[0].Name != "go" || groups[1].Name != "swift" {
+		t.Fatalf("domain groups = %+v", groups)
+	}
+	g := groups[0]
+	if g.Count != 3 || g.Uses != 5 || g.Effectiveness < 0.599 || g.Effectiveness > 0.601 {
+		t.Errorf("go roll-up = %d patterns, %d uses, %.3f effective", g.Count, g.Uses, g.Effectiveness)
+	}
+	var sub []string
+	for _, s := range g.Groups {
+		sub = append(sub, s.Name)
+		if len(s.Patterns) != s.Count {
+			t.Errorf("%s has %d patterns, count %d", s.Name, len(s.Patterns), s.Count)
+		}
+	}
+	if !reflect.DeepEqual(sub, []string{"lesson", "pattern"}) {
+		t.Errorf("go categories = %v", sub)
+	}
+	if g.Patterns != nil {
+		t.Error("an inner group holds patterns directly")
+	}
+}
+
+func TestParseGroupLevels(t *testing.T) {
+	if got, err := ParseGroupLevels(""); err != nil || !reflect.DeepEqual(got, GroupLevels) {
+		t.Errorf("default levels = %v, %v", got, err)
+	}
+	if got, err := ParseGroupLevels("tag, Domain"); err != nil || !reflect.DeepEqual(got, []string{"tag", "domain"}) {
+		t.Errorf("levels = %v, %v", got, err)
+	}
+	for _, bad := range []string{"color", "tag,tag"} {
+		if _, err := ParseGroupLevels(bad); err == nil {
+			t.Errorf("ParseGroupLevels(%q) succeeded", bad)
+		}
+	}
+}
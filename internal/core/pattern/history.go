@@ -0,0 +1,166 @@
+package pattern
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxRevisions is how many earlier versions of a pattern are kept; the
+// oldest go first.
+const MaxRevisions = 20
+
+// Revision is an earlier version of a pattern, saved when it was replaced.
+type Revision struct {
+	Rev         int       `json:"rev"`
+	SavedAt     time.Time `json:"saved_at"`         // when it was replaced
+	Reason      string    `json:"reason,omitempty"` // what replaced it: update, learn, edit, rollback
+	Description string    `json:"description,omitempty"`
+	Size        int       `json:"size"` // YAML bytes
+}
+
+// HistoryDir returns where earlier versions of patterns are kept, one
+// directory per pattern.
+func (s *Store) HistoryDir() string {
+	return filepath.Join(s.baseDir, ".history")
+}
+
+func (s *Store) historyPath(name string) string {
+	return filepath.Join(s.HistoryDir(), name)
+}
+
+// History returns the saved versions of a pattern, newest first.
+func (s *Store) History(name string) ([]Revision, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	revs, err := s.loadRevisions(name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Rev > revs[j].Rev })
+	return revs, nil
+}
+
+func (s *Store) loadRevisions(name string) ([]Revision, error) {
+	data, err := os.ReadFile(filepath.Join(s.historyPath(name), "revisions.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read pattern history: %w", err)
+	}
+	var revs []Revision
+	if err := json.Unmarshal(data, &revs); err != nil {
+		return nil, fmt.Errorf("cannot parse pattern history: %w", err)
+	}
+	return revs, nil
+}
+
+// RevisionData returns the YAML of a saved version.
+func (s *Store) RevisionData(name string, rev int) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.historyPath(name), strconv.Itoa(rev)+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no revision %d of %s (see 'mur learn history %s')", rev, name, name)
+	}
+	return data, err
+}
+
+// SaveRevision keeps data, the YAML of a pattern about to be replaced, as
+// its next revision and returns the revision number. The same content as
+// the latest revision isn't saved twice.
+func (s *Store) SaveRevision(name string, data []byte, reason string) (int, error) {
+	if err := validateName(name); err != nil {
+		return 0, err
+	}
+	revs, err := s.loadRevisions(name)
+	if err != nil {
+		return 0, err
+	}
+	dir := s.historyPath(name)
+	next := 1
+	if len(revs) > 0 {
+		last := revs[len(revs)-1]
+		if prev, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(last.Rev)+".yaml")); err == nil && bytes.Equal(prev, data) {
+			return last.Rev, nil
+		}
+		next = last.Rev + 1
+	}
+
+	rev := Revision{Rev: next, SavedAt: time.Now(), Reason: reason, Size: len(data)}
+	var p Pattern
+	if yaml.Unmarshal(data, &p) == nil {
+		rev.Description = p.Description
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("cannot create pattern history: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(next)+".yaml"), data, 0644); err != nil {
+		return 0, fmt.Errorf("cannot save revision: %w", err)
+	}
+	revs = append(revs, rev)
+	for len(revs) > MaxRevisions {
+		_ = os.Remove(filepath.Join(dir, strconv.Itoa(revs[0].Rev)+".yaml"))
+		revs = revs[1:]
+	}
+	manifest, err := json.MarshalIndent(revs, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "revisions.json"), manifest, 0644); err != nil {
+		return 0, fmt.Errorf("cannot save pattern history: %w", err)
+	}
+	return next, nil
+}
+
+// saveCurrentRevision keeps the pattern's file as it is now as a revision.
+func (s *Store) saveCurrentRevision(name, reason string) error {
+	data, err := ReadFile(s.userPatternPath(name))
+	if err != nil {
+		return nil // nothing to keep
+	}
+	_, err = s.SaveRevision(name, data, reason)
+	return err
+}
+
+// Rollback restores a saved version of a pattern. The version it replaces
+// is kept as a new revision, so a rollback can be undone too.
+func (s *Store) Rollback(name string, rev int) (*Pattern, error) {
+	data, err := s.RevisionData(name, rev)
+	if err != nil {
+		return nil, err
+	}
+	var p Pattern
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("cannot parse revision %d: %w", rev, err)
+	}
+	existing, err := s.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Name = name
+	if p.ID == "" {
+		p.ID = existing.ID
+	}
+	p.Lifecycle.Updated = time.Now()
+	p.UpdateHash()
+
+	if err := s.saveCurrentRevision(name, "rollback"); err != nil {
+		return nil, err
+	}
+	if err := s.save(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,75 @@
+package pattern
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStoreHistoryRollback(t *testing.T) {
+	store := NewStore(t.TempDir()).WithCompression(0)
+	if err := store.Create(&Pattern{Name: "go-errors", Description: "good", Content: "wrap with %w"}); err != nil {
+		t.Fatal(err)
+	}
+	if revs, _ := store.History("go-errors"); len(revs) != 0 {
+		t.Fatalf("new pattern has %d revisions", len(revs))
+	}
+
+	// Usage bookkeeping doesn't make a revision; new content does
+	if err := store.SetPinned("go-errors", true); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := store.Get("go-errors")
+	p.Description = "worse"
+	p.Content = "just return err"
+	if err := store.Update(p); err != nil {
+		t.Fatal(err)
+	}
+	revs, err := store.History("go-errors")
+	if err != nil || len(revs) != 1 {
+		t.Fatalf("History = %v, %v; want one revision", revs, err)
+	}
+	if revs[0].Rev != 1 || revs[0].Description != "good" || revs[0].Reason != "update" {
+		t.Errorf("revision = %+v", revs[0])
+	}
+
+	restored, err := store.Rollback("go-errors", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Content != "wrap with %w" || restored.ID != p.ID || !restored.VerifyHash() {
+		t.Errorf("restored = %+v", restored)
+	}
+	if got, _ := store.Get("go-errors"); got.Content != "wrap with %w" {
+		t.Errorf("stored content = %q after rollback", got.Content)
+	}
+	revs, _ = store.History("go-errors")
+	if len(revs) != 2 || revs[0].Rev != 2 || revs[0].Description != "worse" || revs[0].Reason != "rollback" {
+		t.Errorf("after rollback history = %+v, want the replaced version as rev 2", revs)
+	}
+	if _, err := store.Rollback("go-errors", 9); err == nil {
+		t.Error("rollback to a missing revision succeeded")
+	}
+
+	// The same content isn't saved twice, and old revisions are pruned
+	data, _ := store.RevisionData("go-errors", 2)
+	if rev, _ := store.SaveRevision("go-errors", data, "edit"); rev != 2 {
+		t.Errorf("duplicate saved as rev %d", rev)
+	}
+	for i := 0; i < MaxRevisions+3; i++ {
+		p, _ := store.Get("go-errors")
+		p.Content = fmt.Sprintf("version %d", i)
+		_ = store.Update(p)
+	}
+	revs, _ = store.History("go-errors")
+	if len(revs) != MaxRevisions || revs[len(revs)-1].Rev == 1 {
+		t.Errorf("kept %d revisions from rev %d", len(revs), revs[len(revs)-1].Rev)
+	}
+
+	// History follows a rename
+	if _, err := store.Rename("go-errors", "go-error-wrapping"); err != nil {
+		t.Fatal(err)
+	}
+	if revs, _ := store.History("go-error-wrapping"); len(revs) != MaxRevisions {
+		t.Errorf("renamed pattern has %d revisions", len(revs))
+	}
+}
@@ -0,0 +1,145 @@
+package pattern
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A pattern's ID is its identity; its name is a display field that
+// 'mur learn rename' may change. Embeddings and cloud sync refer to
+// patterns by ID. New patterns get a ULID (time-ordered, so IDs sort by
+// creation); the UUIDs of older patterns remain valid IDs.
+
+// crockford is the ULID alphabet.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a new pattern ID: a ULID, 26 characters encoding a 48-bit
+// millisecond timestamp and 80 random bits.
+func NewID() string {
+	return newULID(time.Now())
+}
+
+func newULID(t time.Time) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(t.UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits in 26 base-32 digits, most significant first
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// GetByID returns the pattern with the given ID.
+func (s *Store) GetByID(id string) (*Pattern, error) {
+	if id == "" {
+		return nil, fmt.Errorf("pattern ID cannot be empty")
+	}
+	patterns, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range patterns {
+		if patterns[i].ID == id {
+			return &patterns[i], nil
+		}
+	}
+	return nil, fmt.Errorf("pattern not found: %s", id)
+}
+
+// Resolve returns the pattern a reference names: a pattern name, or
+// failing that a pattern ID. Commands that take a pattern argument use it
+// so either works.
+func (s *Store) Resolve(ref string) (*Pattern, error) {
+	if p, err := s.Get(ref); err == nil {
+		return p, nil
+	}
+	if p, err := s.GetByID(ref); err == nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("pattern not found: %s", ref)
+}
+
+// AssignIDs gives every pattern in the store's own directory that has no
+// ID, or shares its ID with another pattern, a new one, and returns their
+// names. Patterns that already have a unique ID keep it. With dryRun
+// nothing is written.
+func (s *Store) AssignIDs(dryRun bool) ([]string, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot read patterns: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var assigned []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(s.baseDir, entry.Name())
+		data, err := ReadFile(path)
+		if err != nil {
+			return assigned, err
+		}
+		var p Pattern
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		if p.ID != "" && !seen[p.ID] {
+			seen[p.ID] = true
+			continue
+		}
+
+		p.ID = NewID()
+		seen[p.ID] = true
+		assigned = append(assigned, p.Name)
+		if dryRun {
+			continue
+		}
+		out, err := setID(data, p.ID)
+		if err != nil {
+			return assigned, fmt.Errorf("cannot update pattern %s: %w", p.Name, err)
+		}
+		if err := s.writeFile(path, out); err != nil {
+			return assigned, fmt.Errorf("cannot write pattern %s: %w", p.Name, err)
+		}
+	}
+	return assigned, nil
+}
+
+// setID sets the id key of a pattern file, keeping the rest of the file
+// (key order, comments) as it was.
+func setID(data []byte, id string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("not a YAML mapping")
+	}
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == "id" {
+			m.Content[i+1].SetString(id)
+			return yaml.Marshal(&doc)
+		}
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: "id"}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Value: id}
+	m.Content = append([]*yaml.Node{key, val}, m.Content...)
+	return yaml.Marshal(&doc)
+}
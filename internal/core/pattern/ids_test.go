@@ -0,0 +1,109 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewID(t *testing.T) {
+	id := NewID()
+	if len(id) != 26 || strings.Trim(id, crockford) != "" {
+		t.Fatalf("NewID() = %q, want 26 Crockford base-32 characters", id)
+	}
+	if NewID() == id {
+		t.Error("NewID() repeated an ID")
+	}
+
+	// ULIDs sort by creation time
+	earlier := newULID(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := newULID(time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC))
+	if earlier >= later {
+		t.Errorf("%s should sort before %s", earlier, later)
+	}
+	if got := newULID(time.UnixMilli(0))[:10]; got != "0000000000" {
+		t.Errorf("timestamp of epoch = %s", got)
+	}
+}
+
+func TestIDSurvivesRename(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Create(&Pattern{Name: "go-errors", Content: "Wrap errors."}); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := store.Get("go-errors")
+	if p.ID == "" {
+		t.Fatal("Create didn't assign an ID")
+	}
+
+	// Update without an ID keeps it
+	if err := store.Update(&Pattern{Name: "go-errors", Content: "Wrap errors with %w."}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Rename("go-errors", "go-error-wrapping"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetByID(p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "go-error-wrapping" {
+		t.Errorf("GetByID after rename = %s", got.Name)
+	}
+	for _, ref := range []string{"go-error-wrapping", p.ID} {
+		if r, err := store.Resolve(ref); err != nil || r.ID != p.ID {
+			t.Errorf("Resolve(%s) = %v, %v", ref, r, err)
+		}
+	}
+	if _, err := store.Resolve("go-errors"); err == nil {
+		t.Error("Resolve found the old name")
+	}
+}
+
+func TestAssignIDs(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	files := map[string]string{
+		"no-id.yaml":  "name: no-id\ncontent: a\n",
+		"first.yaml":  "id: dup\nname: first\ncontent: b\n",
+		"second.yaml": "id: dup\nname: second\ncontent: c\n",
+		"unique.yaml": "id: 01J0000000000000000000000\nname: unique\ncontent: d\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assigned, err := store.AssignIDs(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 2 {
+		t.Errorf("dry run assigned %v, want no-id and one of the duplicates", assigned)
+	}
+	if p, _ := store.Get("no-id"); p.ID != "" {
+		t.Error("dry run wrote an ID")
+	}
+
+	if _, err := store.AssignIDs(false); err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	patterns, _ := store.List()
+	for _, p := range patterns {
+		if p.ID == "" || ids[p.ID] {
+			t.Errorf("%s has ID %q", p.Name, p.ID)
+		}
+		ids[p.ID] = true
+	}
+	if p, _ := store.Get("unique"); p.ID != "01J0000000000000000000000" {
+		t.Errorf("unique ID changed to %s", p.ID)
+	}
+	if again, _ := store.AssignIDs(false); len(again) != 0 {
+		t.Errorf("second run assigned %v", again)
+	}
+}
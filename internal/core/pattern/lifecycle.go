@@ -303,7 +303,8 @@ func (m *LifecycleManager) GetArchived() ([]Pattern, error) {
 	return archived, nil
 }
 
-// Cleanup permanently deletes archived patterns older than the given duration.
+// Cleanup deletes archived patterns older than the given duration, moving
+// them to the trash.
 func (m *LifecycleManager) Cleanup(olderThan time.Duration) (int, error) {
 	if m.cfg.DryRun {
 		return 0, nil
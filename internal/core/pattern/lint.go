@@ -64,6 +64,17 @@ func NewLinter() *Linter {
 	}
 }
 
+// WithMaxContent sets the content length above which patterns are
+// errors, normally storage.max_content_bytes; 0 means no maximum.
+func (l *Linter) WithMaxContent(n int) *Linter {
+	for _, r := range l.rules {
+		if cl, ok := r.(*ContentLengthRule); ok {
+			cl.MaxLength = n
+		}
+	}
+	return l
+}
+
 // Lint checks a single pattern.
 func (l *Linter) Lint(p *Pattern) []LintIssue {
 	var issues []LintIssue
@@ -161,10 +172,11 @@ func DefaultLintRules() []LintRule {
 	return []LintRule{
 		&RequiredFieldsRule{},
 		&SchemaVersionRule{},
-		&ContentLengthRule{MinLength: 10, MaxLength: 50000},
+		&ContentLengthRule{MinLength: 10, MaxLength: DefaultMaxContentBytes},
 		&TagsRule{},
 		&LifecycleRule{},
 		&TrustLevelRule{},
+		&AppliesRule{},
 	}
 }
 
@@ -233,12 +245,17 @@ func (r *SchemaVersionRule) Check(p *Pattern) []LintIssue {
 	return issues
 }
 
-// ContentLengthRule checks content length.
+// ContentLengthRule checks content length. Content over MaxLength is an
+// error, and content near it is reported; 0 means no maximum.
 type ContentLengthRule struct {
 	MinLength int
 	MaxLength int
 }
 
+// nearLimit is the share of ContentLengthRule.MaxLength above which
+// content size is reported.
+const nearLimit = 0.8
+
 func (r *ContentLengthRule) Name() string { return "content-length" }
 
 func (r *ContentLengthRule) Check(p *Pattern) []LintIssue {
@@ -253,13 +270,22 @@ func (r *ContentLengthRule) Check(p *Pattern) []LintIssue {
 		})
 	}
 
-	if len(p.Content) > r.MaxLength {
+	switch {
+	case r.MaxLength <= 0:
+	case len(p.Content) > r.MaxLength:
 		issues = append(issues, LintIssue{
 			Pattern:  p.Name,
 			Field:    "content",
 			Severity: SeverityError,
 			Message:  fmt.Sprintf("Content exceeds maximum length (%d chars, max: %d)", len(p.Content), r.MaxLength),
 		})
+	case float64(len(p.Content)) > nearLimit*float64(r.MaxLength):
+		issues = append(issues, LintIssue{
+			Pattern:  p.Name,
+			Field:    "content",
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("Content is %d chars, %.0f%% of the maximum (%d)", len(p.Content), 100*float64(len(p.Content))/float64(r.MaxLength), r.MaxLength),
+		})
 	}
 
 	return issues
@@ -377,3 +403,34 @@ func (r *TrustLevelRule) Check(p *Pattern) []LintIssue {
 
 	return issues
 }
+
+// AppliesRule checks that applies globs and regular expressions compile.
+type AppliesRule struct{}
+
+func (r *AppliesRule) Name() string { return "applies" }
+
+func (r *AppliesRule) Check(p *Pattern) []LintIssue {
+	var issues []LintIssue
+
+	fields := []struct {
+		name  string
+		exprs []string
+	}{
+		{"applies.projects", p.Applies.Projects},
+		{"applies.languages", p.Applies.Languages},
+	}
+	for _, f := range fields {
+		for _, expr := range f.exprs {
+			if err := ValidateApplies(expr); err != nil {
+				issues = append(issues, LintIssue{
+					Pattern:  p.Name,
+					Field:    f.name,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("%v (it never matches)", err),
+				})
+			}
+		}
+	}
+
+	return issues
+}
@@ -4,24 +4,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
 )
 
 // V1Pattern represents the old pattern schema (v1).
 type V1Pattern struct {
-	Name        string  `yaml:"name"`
-	Description string  `yaml:"description"`
-	Content     string  `yaml:"content"`
-	Domain      string  `yaml:"domain"`
-	Category    string  `yaml:"category"`
-	Confidence  float64 `yaml:"confidence"`
-	TeamShared  bool    `yaml:"team_shared"`
-	CreatedAt   string  `yaml:"created_at"`
-	UpdatedAt   string  `yaml:"updated_at"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Content     string     `yaml:"content"`
+	Domain      string     `yaml:"domain"`
+	Category    string     `yaml:"category"`
+	Confidence  float64    `yaml:"confidence"`
+	TeamShared  bool       `yaml:"team_shared"`
+	Validated   *bool      `yaml:"validated,omitempty"`
+	Source      *SourceRef `yaml:"source,omitempty"`
+	CreatedAt   string     `yaml:"created_at"`
+	UpdatedAt   string     `yaml:"updated_at"`
 }
 
 // MigrationResult holds the result of migrating patterns.
@@ -67,7 +67,7 @@ func Migrate(patternsDir string, options MigrateOptions) (*MigrationResult, erro
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
 			continue
 		}
 
@@ -75,7 +75,7 @@ func Migrate(patternsDir string, options MigrateOptions) (*MigrationResult, erro
 		result.TotalPatterns++
 
 		// Read file
-		data, err := os.ReadFile(filePath)
+		data, err := ReadFile(filePath)
 		if err != nil {
 			result.ErrorCount++
 			result.Errors = append(result.Errors, MigrationError{
@@ -139,7 +139,7 @@ func Migrate(patternsDir string, options MigrateOptions) (*MigrationResult, erro
 		}
 
 		if !options.DryRun {
-			if err := os.WriteFile(filePath, v2Data, 0644); err != nil {
+			if err := WriteFile(filePath, v2Data); err != nil {
 				result.ErrorCount++
 				result.Errors = append(result.Errors, MigrationError{
 					File:    entry.Name(),
@@ -164,6 +164,13 @@ type MigrateOptions struct {
 	DefaultOwner string
 }
 
+// FromV1 converts a v1 pattern to v2 in memory, as Migrate would, so
+// listings can treat both schemas alike.
+func FromV1(v1 V1Pattern) *Pattern {
+	p, _ := migrateV1ToV2(v1, MigrateOptions{})
+	return p
+}
+
 // migrateV1ToV2 converts a v1 pattern to v2.
 func migrateV1ToV2(v1 V1Pattern, options MigrateOptions) (*Pattern, error) {
 	now := time.Now()
@@ -203,7 +210,7 @@ func migrateV1ToV2(v1 V1Pattern, options MigrateOptions) (*Pattern, error) {
 	}
 
 	p := &Pattern{
-		ID:          uuid.New().String(),
+		ID:          NewID(),
 		Name:        v1.Name,
 		Description: v1.Description,
 		Content:     v1.Content,
@@ -220,6 +227,8 @@ func migrateV1ToV2(v1 V1Pattern, options MigrateOptions) (*Pattern, error) {
 			Effectiveness:      v1.Confidence,
 			UsageCount:         0, // Reset usage count
 			OriginalConfidence: v1.Confidence,
+			Validated:          v1.Validated,
+			Source:             v1.Source,
 		},
 		Lifecycle: LifecycleMeta{
 			Status:  StatusActive,
@@ -242,7 +251,7 @@ func migrateV1ToV2(v1 V1Pattern, options MigrateOptions) (*Pattern, error) {
 
 // DetectVersion returns the schema version of a pattern file.
 func DetectVersion(filePath string) (int, error) {
-	data, err := os.ReadFile(filePath)
+	data, err := ReadFile(filePath)
 	if err != nil {
 		return 0, err
 	}
@@ -275,7 +284,7 @@ func NeedsMigration(patternsDir string) (bool, int, error) {
 
 	v1Count := 0
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
 			continue
 		}
 
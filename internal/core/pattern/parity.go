@@ -0,0 +1,300 @@
+package pattern
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Moving from files to sqlite goes through the dual backend: reads still
+// come from the files, every write the store makes is mirrored to
+// patterns.db, and the rows are checked against the files (VerifyIndex)
+// once an hour while listing and by 'mur migrate sqlite --verify', which
+// only switches to sqlite after a clean check. 'mur migrate sqlite
+// --files' goes back in one step: the files were never changed.
+
+// parityInterval is how often listing with the dual backend checks the
+// index against the files.
+const parityInterval = time.Hour
+
+// mirrorIndex returns the index writes are mirrored to, or nil when the
+// store only uses files.
+func (s *Store) mirrorIndex() *patternIndex {
+	if backend, _ := s.Backend(); backend != BackendDual && backend != BackendSQLite {
+		return nil
+	}
+	idx, _ := openIndex(s.IndexPath())
+	return idx
+}
+
+// mirror updates the index rows of the pattern files at paths after the
+// store wrote, moved, or removed them. The files are the source of truth,
+// so a failure doesn't fail the write: the row is left behind the file
+// and VerifyIndex reports it.
+func (s *Store) mirror(paths ...string) {
+	idx := s.mirrorIndex()
+	if idx == nil {
+		return
+	}
+	for _, path := range paths {
+		if path == "" || !IsPatternFile(filepath.Base(path)) {
+			continue
+		}
+		dir := s.indexDir(filepath.Dir(path))
+		if dir == "" {
+			continue
+		}
+		_ = idx.put(dir, path)
+	}
+}
+
+// indexDir returns the directory of s.dirs() that is dir, as the index
+// keys its rows, or "" if dir isn't one of them.
+func (s *Store) indexDir(dir string) string {
+	for _, d := range s.dirs() {
+		if filepath.Clean(d) == dir {
+			return d
+		}
+	}
+	return ""
+}
+
+// put brings the row of the pattern file at path in line with the file,
+// deleting it when the file is gone or can't be parsed.
+func (idx *patternIndex) put(dir, path string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	tx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var p Pattern
+	info, err := os.Stat(path)
+	if err == nil {
+		var data []byte
+		if data, err = ReadFile(path); err == nil {
+			err = yaml.Unmarshal(data, &p)
+		}
+	}
+	if err != nil {
+		if _, err := tx.Exec("DELETE FROM patterns WHERE path = ?", path); err != nil {
+			return err
+		}
+	} else if err := putRow(tx, dir, path, info, &p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ParityReport is the result of checking the index against the files.
+type ParityReport struct {
+	Dirs      []string
+	Files     int      // parsed pattern files
+	Rows      int      // index rows after the check
+	Stale     int      // rows behind their file, re-read by the check
+	Divergent []string // files whose up-to-date row has other contents
+	CheckedAt time.Time
+}
+
+// Clean reports whether every row matched its file: the check found
+// nothing to re-read and nothing divergent, and the counts agree.
+func (r *ParityReport) Clean() bool {
+	return r.Stale == 0 && len(r.Divergent) == 0 && r.Files == r.Rows
+}
+
+// VerifyIndex checks the rows of patterns.db against the pattern files:
+// a row whose file has the size and modification time it recorded must
+// hold the same pattern (compared by hash), since the sqlite backend
+// never re-reads it. Rows behind their file are counted as stale and
+// re-read, and the per-directory counts are compared afterwards. The
+// result is recorded for 'mur migrate sqlite --status'.
+func (s *Store) VerifyIndex() (*ParityReport, error) {
+	idx, err := openIndex(s.IndexPath())
+	if err != nil {
+		return nil, err
+	}
+	report := &ParityReport{CheckedAt: time.Now()}
+	for _, dir := range s.dirs() {
+		r, err := idx.verify(dir, report.CheckedAt)
+		if err != nil {
+			return nil, fmt.Errorf("verify %s: %w", dir, err)
+		}
+		report.Dirs = append(report.Dirs, dir)
+		report.Files += r.Files
+		report.Rows += r.Rows
+		report.Stale += r.Stale
+		report.Divergent = append(report.Divergent, r.Divergent...)
+	}
+	return report, nil
+}
+
+// verify checks the rows of dir against its files and records the result.
+func (idx *patternIndex) verify(dir string, now time.Time) (*ParityReport, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	rows, err := idx.rows(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ParityReport{Dirs: []string{dir}, CheckedAt: now}
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		data, err := ReadFile(path)
+		var p Pattern
+		if err == nil {
+			err = yaml.Unmarshal(data, &p)
+		}
+		if err != nil {
+			// Skipped by the index as when listing the files
+			continue
+		}
+		report.Files++
+
+		r, ok := rows[path]
+		if !ok || r.size != info.Size() || r.modTime != info.ModTime().UnixNano() {
+			report.Stale++
+			continue
+		}
+		want, err := json.Marshal(&p)
+		if err != nil {
+			return nil, err
+		}
+		if sha256.Sum256(want) != sha256.Sum256(r.data) {
+			report.Divergent = append(report.Divergent, path)
+		}
+	}
+	for path := range rows {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			report.Stale++
+		}
+	}
+
+	if _, err := idx.refresh(dir); err != nil {
+		return nil, err
+	}
+	if err := idx.db.QueryRow("SELECT COUNT(*) FROM patterns WHERE dir = ?", dir).Scan(&report.Rows); err != nil {
+		return nil, err
+	}
+	sort.Strings(report.Divergent)
+	_, err = idx.db.Exec(`INSERT OR REPLACE INTO parity (dir, checked_at, files, rows, divergent)
+		VALUES (?, ?, ?, ?, ?)`,
+		dir, now.UnixNano(), report.Files, report.Rows, strings.Join(report.Divergent, "\n"))
+	return report, err
+}
+
+// rows returns what the index holds for dir, by path.
+func (idx *patternIndex) rows(dir string) (map[string]indexRow, error) {
+	rows, err := idx.db.Query("SELECT path, size, mod_time, data FROM patterns WHERE dir = ?", dir)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	known := make(map[string]indexRow)
+	for rows.Next() {
+		var path string
+		var r indexRow
+		if err := rows.Scan(&path, &r.size, &r.modTime, &r.data); err != nil {
+			return nil, err
+		}
+		known[path] = r
+	}
+	return known, rows.Err()
+}
+
+// lastParity returns the last recorded check of dir, or nil if there
+// was none.
+func (idx *patternIndex) lastParity(dir string) (*ParityReport, error) {
+	var checkedAt int64
+	var divergent string
+	report := &ParityReport{Dirs: []string{dir}}
+	err := idx.db.QueryRow("SELECT checked_at, files, rows, divergent FROM parity WHERE dir = ?", dir).
+		Scan(&checkedAt, &report.Files, &report.Rows, &divergent)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.CheckedAt = time.Unix(0, checkedAt)
+	if divergent != "" {
+		report.Divergent = strings.Split(divergent, "\n")
+	}
+	return report, nil
+}
+
+// LastParity returns the last recorded check of every directory of the
+// store, combined, or nil if one of them was never checked.
+func (s *Store) LastParity() (*ParityReport, error) {
+	idx, err := openIndex(s.IndexPath())
+	if err != nil {
+		return nil, err
+	}
+	report := &ParityReport{}
+	for _, dir := range s.dirs() {
+		r, err := idx.lastParity(dir)
+		if err != nil || r == nil {
+			return nil, err
+		}
+		report.Dirs = append(report.Dirs, dir)
+		report.Files += r.Files
+		report.Rows += r.Rows
+		report.Divergent = append(report.Divergent, r.Divergent...)
+		if report.CheckedAt.IsZero() || r.CheckedAt.Before(report.CheckedAt) {
+			report.CheckedAt = r.CheckedAt
+		}
+	}
+	return report, nil
+}
+
+// checkParity verifies dir against the index when the store mirrors to it
+// and the last check is older than parityInterval.
+func (s *Store) checkParity(dir string) {
+	if backend, _ := s.Backend(); backend != BackendDual {
+		return
+	}
+	idx, err := openIndex(s.IndexPath())
+	if err != nil {
+		return
+	}
+	if last, err := idx.lastParity(dir); err != nil || (last != nil && time.Since(last.CheckedAt) < parityInterval) {
+		return
+	}
+	_, _ = idx.verify(dir, time.Now())
+}
+
+// RebuildIndex drops every row and builds patterns.db again from the
+// pattern files.
+func (s *Store) RebuildIndex() (*IndexReport, error) {
+	idx, err := openIndex(s.IndexPath())
+	if err != nil {
+		return nil, err
+	}
+	idx.mu.Lock()
+	_, err = idx.db.Exec("DELETE FROM pattern_tags; DELETE FROM patterns; DELETE FROM parity")
+	idx.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.Reindex()
+}
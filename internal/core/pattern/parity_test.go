@@ -0,0 +1,88 @@
+package pattern
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDualBackend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "patterns")
+	store := NewStore(dir).WithCompression(0).WithBackend(BackendDual)
+
+	for _, p := range []*Pattern{
+		{Name: "go-errors", Content: "Wrap errors."},
+		{Name: "go-tests", Content: "Table tests."},
+	} {
+		if err := store.Create(p); err != nil {
+			t.Fatalf("Create(%s): %v", p.Name, err)
+		}
+	}
+	if backend, err := store.Backend(); backend != BackendDual || err != nil {
+		t.Fatalf("Backend() = %s, %v", backend, err)
+	}
+
+	// Writes are mirrored without a refresh
+	idx, err := openIndex(store.IndexPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := idx.rows(dir)
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("rows after Create = %d, %v; want 2", len(rows), err)
+	}
+	if err := store.Delete("go-tests"); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := idx.rows(dir); len(rows) != 1 {
+		t.Errorf("rows after Delete = %d, want 1", len(rows))
+	}
+
+	report, err := store.VerifyIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Clean() || report.Files != 1 || report.Rows != 1 {
+		t.Errorf("VerifyIndex() = %+v, want clean with 1 pattern", report)
+	}
+
+	// A row that looks up to date but holds something else diverges
+	db, err := sql.Open("sqlite", store.IndexPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`UPDATE patterns SET data = '{"name":"go-errors"}'`); err != nil {
+		t.Fatal(err)
+	}
+	report, err = store.VerifyIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Clean() || len(report.Divergent) != 1 || report.Divergent[0] != filepath.Join(dir, "go-errors.yaml") {
+		t.Errorf("VerifyIndex() after corrupting a row = %+v", report)
+	}
+	last, err := store.LastParity()
+	if err != nil || last == nil || len(last.Divergent) != 1 {
+		t.Errorf("LastParity() = %+v, %v", last, err)
+	}
+
+	// A file changed outside the store is stale, not divergent, and re-read
+	if err := os.WriteFile(filepath.Join(dir, "go-more.yaml"), []byte("name: go-more\ncontent: More.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RebuildIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go-extra.yaml"), []byte("name: go-extra\ncontent: Extra.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = store.VerifyIndex()
+	if report.Clean() || report.Stale != 1 || len(report.Divergent) != 0 {
+		t.Errorf("VerifyIndex() with an outside change = %+v", report)
+	}
+	if report, _ = store.VerifyIndex(); !report.Clean() || report.Rows != 3 {
+		t.Errorf("VerifyIndex() after re-reading = %+v", report)
+	}
+}
@@ -209,3 +209,95 @@ func TestStore_Count(t *testing.T) {
 		t.Errorf("Count = %d, want 1", count)
 	}
 }
+
+func TestStore_SetPinned(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for _, name := range []string{"zeta", "alpha", "beta"} {
+		if err := store.Create(&Pattern{Name: name, Content: name, SchemaVersion: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"zeta", "alpha"} {
+		if err := store.SetPinned(name, true); err != nil {
+			t.Fatalf("SetPinned(%s) failed: %v", name, err)
+		}
+	}
+
+	pinned, err := store.GetPinned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pinned) != 2 || pinned[0].Name != "alpha" || pinned[1].Name != "zeta" {
+		t.Fatalf("GetPinned = %v, want [alpha zeta]", pinned)
+	}
+
+	if err := store.SetPinned("zeta", false); err != nil {
+		t.Fatal(err)
+	}
+	if pinned, _ := store.GetPinned(); len(pinned) != 1 {
+		t.Errorf("after unpin, %d pinned, want 1", len(pinned))
+	}
+
+	if err := store.SetPinned("missing", true); err == nil {
+		t.Error("expected error pinning unknown pattern")
+	}
+}
+
+func TestStore_SaveMeta(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Create(&Pattern{Name: "alpha", Content: "alpha", SchemaVersion: 2}); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := store.Get("alpha")
+	updated := p.Lifecycle.Updated
+
+	time.Sleep(10 * time.Millisecond)
+	p.Health.Score = 0.42
+	if err := store.SaveMeta(p); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := store.Get("alpha")
+	if got.Health.Score != 0.42 {
+		t.Errorf("Health.Score = %v, want 0.42", got.Health.Score)
+	}
+	if !got.Lifecycle.Updated.Equal(updated) {
+		t.Errorf("Updated changed from %v to %v", updated, got.Lifecycle.Updated)
+	}
+
+	if err := store.SaveMeta(&Pattern{Name: "missing"}); err == nil {
+		t.Error("expected error saving unknown pattern")
+	}
+}
+
+func TestStore_List_SeesExternalEdits(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	if err := store.Create(&Pattern{Name: "edited", Description: "before", Content: "x", SchemaVersion: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if patterns, _ := store.List(); len(patterns) != 1 || patterns[0].Description != "before" {
+		t.Fatalf("List = %v", patterns)
+	}
+
+	// Rewrite the file behind the store's back, as an editor or git pull would.
+	path := filepath.Join(dir, "edited.yaml")
+	if err := os.WriteFile(path, []byte("name: edited\ndescription: after\ncontent: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, _ := store.List()
+	if len(patterns) != 1 || patterns[0].Description != "after" {
+		t.Errorf("List after edit = %v, want description %q", patterns, "after")
+	}
+
+	// Callers may modify what List returns without affecting later calls.
+	patterns[0].Description = "mutated"
+	if again, _ := store.List(); again[0].Description != "after" {
+		t.Errorf("List returned a shared pattern: %q", again[0].Description)
+	}
+}
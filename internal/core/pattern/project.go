@@ -0,0 +1,107 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// ProjectPatternsDir is where a repository keeps patterns of its own,
+// relative to its root. They are committed with the code and only used
+// inside that repository.
+const ProjectPatternsDir = ".mur/patterns"
+
+// ScopeProject is the Scope of patterns read from a project's
+// ProjectPatternsDir.
+const ScopeProject = "project"
+
+// FindProjectDir returns the ProjectPatternsDir of the repository dir is
+// in, looking in dir and its parents up to the git root, or "" if there
+// is none. The global ~/.mur/patterns never counts.
+func FindProjectDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	home, _ := os.UserHomeDir()
+	global := filepath.Join(config.DataDir(home), "patterns")
+
+	for {
+		candidate := filepath.Join(dir, ProjectPatternsDir)
+		if candidate != global {
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				return candidate
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil || dir == home {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// DefaultStoreFor returns the default store together with the patterns of
+// the repository workDir is in, if it has any and is trusted (see
+// TrustedProjectDir).
+func DefaultStoreFor(workDir string) (*Store, error) {
+	s, err := DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	return s.WithProject(TrustedProjectDir(workDir)), nil
+}
+
+// WithProject adds a project's patterns directory to the store. Its
+// patterns are listed first, with Scope set to ScopeProject, and hide
+// global patterns of the same name. The store never writes to it: new
+// patterns, usage and effectiveness go to the base directory, and saving
+// a project pattern fails. Create them with NewStore(dir) to add one to
+// the project.
+func (s *Store) WithProject(dir string) *Store {
+	s.projectDir = dir
+	return s
+}
+
+// ProjectDir returns the project patterns directory added with
+// WithProject, or "".
+func (s *Store) ProjectDir() string {
+	return s.projectDir
+}
+
+// ListProject returns the patterns of the project directory only; none
+// for a nil store.
+func (s *Store) ListProject() []Pattern {
+	if s == nil || s.projectDir == "" {
+		return nil
+	}
+	patterns := s.listFromDir(s.projectDir)
+	for i := range patterns {
+		fromProject(&patterns[i])
+	}
+	return patterns
+}
+
+// fromProject marks p as read from a project directory. Whatever trust
+// level and hash its file declares, anyone who can push to the repository
+// wrote it: it counts as a community pattern at most.
+func fromProject(p *Pattern) {
+	p.Scope = ScopeProject
+	if p.Security.TrustLevel != TrustUntrusted {
+		p.Security.TrustLevel = TrustCommunity
+	}
+	p.Security.Hash = ""
+}
+
+// errProjectPattern is the error of writing the project pattern name.
+func (s *Store) errProjectPattern(name string) error {
+	return fmt.Errorf("pattern %s belongs to the repository (%s); edit it there", name, s.projectDir)
+}
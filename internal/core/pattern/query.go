@@ -0,0 +1,383 @@
+package pattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/timing"
+)
+
+// Expr is a boolean filter over patterns: a single Filter, or filters
+// combined with and, or, and not.
+type Expr interface {
+	Match(p *Pattern, now time.Time) (bool, error)
+}
+
+type andExpr []Expr
+
+func (e andExpr) Match(p *Pattern, now time.Time) (bool, error) {
+	for _, x := range e {
+		if ok, err := x.Match(p, now); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type orExpr []Expr
+
+func (e orExpr) Match(p *Pattern, now time.Time) (bool, error) {
+	for _, x := range e {
+		if ok, err := x.Match(p, now); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+type notExpr struct{ x Expr }
+
+func (e notExpr) Match(p *Pattern, now time.Time) (bool, error) {
+	ok, err := e.x.Match(p, now)
+	return !ok, err
+}
+
+// And combines exprs so all must match. Nil exprs are skipped; with none
+// left the result is nil, which matches everything.
+func And(exprs ...Expr) Expr {
+	var all andExpr
+	for _, x := range exprs {
+		if x != nil {
+			all = append(all, x)
+		}
+	}
+	switch len(all) {
+	case 0:
+		return nil
+	case 1:
+		return all[0]
+	}
+	return all
+}
+
+// ParseWhere parses a filter expression such as
+//
+//	confidence>0.7 and tag:docker and last_used<30d
+//	(domain=go or domain=rust) and not status=archived
+//
+// Comparisons use the fields and operators of ParseFilter, plus
+// "field:value" as shorthand for "field=value". Values with spaces can be
+// quoted. "and" binds tighter than "or". An empty expression returns nil,
+// which matches every pattern.
+func ParseWhere(s string) (Expr, error) {
+	tokens, err := tokenizeWhere(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	p := &whereParser{tokens: tokens}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid query: unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type whereToken struct {
+	text   string
+	op     bool // comparison operator
+	quoted bool // quoted value, never a keyword or parenthesis
+}
+
+func (t whereToken) is(word string) bool {
+	return !t.op && !t.quoted && strings.EqualFold(t.text, word)
+}
+
+// whereOps is ordered so two-character operators are tried first.
+var whereOps = []string{">=", "<=", "!=", "=", ">", "<", ":"}
+
+func tokenizeWhere(s string) ([]whereToken, error) {
+	var tokens []whereToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, whereToken{text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query: unterminated %c", c)
+			}
+			tokens = append(tokens, whereToken{text: s[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		case strings.IndexByte("<>=!:", c) >= 0:
+			op := ""
+			for _, o := range whereOps {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("invalid query: unexpected %q", string(c))
+			}
+			tokens = append(tokens, whereToken{text: op, op: true})
+			i += len(op)
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\n()\"'<>=!:", s[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, whereToken{text: s[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type whereParser struct {
+	tokens []whereToken
+	pos    int
+}
+
+func (p *whereParser) peek() (whereToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return whereToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *whereParser) or() (Expr, error) {
+	var alts orExpr
+	for {
+		x, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, x)
+		if t, ok := p.peek(); !ok || !t.is("or") {
+			break
+		}
+		p.pos++
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return alts, nil
+}
+
+func (p *whereParser) and() (Expr, error) {
+	var all andExpr
+	for {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, x)
+		if t, ok := p.peek(); !ok || !t.is("and") {
+			break
+		}
+		p.pos++
+	}
+	if len(all) == 1 {
+		return all[0], nil
+	}
+	return all, nil
+}
+
+func (p *whereParser) unary() (Expr, error) {
+	t, ok := p.peek()
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("invalid query: unexpected end, expected a comparison such as confidence>0.7")
+	case t.is("not"):
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	case t.is("("):
+		p.pos++
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || !t.is(")") {
+			return nil, fmt.Errorf("invalid query: missing )")
+		}
+		p.pos++
+		return x, nil
+	}
+	return p.comparison()
+}
+
+func (p *whereParser) comparison() (Expr, error) {
+	if p.pos+3 > len(p.tokens) {
+		rest := make([]string, 0, 3)
+		for _, t := range p.tokens[p.pos:] {
+			rest = append(rest, t.text)
+		}
+		return nil, fmt.Errorf("invalid query: incomplete comparison %q (expected field<op>value, e.g. tag:docker)", strings.Join(rest, " "))
+	}
+	field, op, value := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	if field.op || field.quoted || !op.op || value.op || value.is("(") || value.is(")") {
+		return nil, fmt.Errorf("invalid query: expected field<op>value near %q", field.text+op.text+value.text)
+	}
+	p.pos += 3
+
+	f := Filter{Field: strings.ToLower(field.text), Op: op.text, Value: value.text}
+	if f.Op == ":" {
+		f.Op = "="
+	}
+	if !containsString(FilterFields, f.Field) {
+		return nil, fmt.Errorf("unknown filter field %q (valid: %s)", f.Field, strings.Join(FilterFields, ", "))
+	}
+	// Surface bad operators and values now rather than on the first pattern
+	if _, err := f.Match(&Pattern{}, time.Time{}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SortFields lists the fields patterns can be sorted by.
+var SortFields = []string{"name", "domain", "confidence", "effectiveness", "usage", "last_used", "created", "updated"}
+
+// Query selects, orders, and limits patterns.
+type Query struct {
+	Where Expr   // nil matches every pattern
+	Sort  string // one of SortFields; empty keeps store order
+	Desc  bool
+	Limit int // 0 for no limit
+}
+
+// ParseSort parses an ordering such as "effectiveness desc",
+// "effectiveness:desc", or "-effectiveness" (descending). Ascending is the
+// default.
+func ParseSort(spec string) (field string, desc bool, err error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return "", false, nil
+	}
+	if strings.HasPrefix(spec, "-") {
+		spec, desc = spec[1:], true
+	}
+	field, dir, _ := strings.Cut(strings.NewReplacer(":", " ").Replace(spec), " ")
+	switch strings.TrimSpace(dir) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return "", false, fmt.Errorf("invalid sort direction %q (use asc or desc)", strings.TrimSpace(dir))
+	}
+	if !containsString(SortFields, field) {
+		return "", false, fmt.Errorf("unknown sort field %q (valid: %s)", field, strings.Join(SortFields, ", "))
+	}
+	return field, desc, nil
+}
+
+// ParseQuery builds a Query from a where expression, a sort spec (see
+// ParseSort), and a limit.
+func ParseQuery(where, sortSpec string, limit int) (Query, error) {
+	if limit < 0 {
+		return Query{}, fmt.Errorf("invalid limit %d", limit)
+	}
+	expr, err := ParseWhere(where)
+	if err != nil {
+		return Query{}, err
+	}
+	field, desc, err := ParseSort(sortSpec)
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{Where: expr, Sort: field, Desc: desc, Limit: limit}, nil
+}
+
+// Apply filters, sorts, and limits patterns at time now.
+func (q Query) Apply(patterns []Pattern, now time.Time) ([]Pattern, error) {
+	var selected []Pattern
+	for i := range patterns {
+		if q.Where != nil {
+			ok, err := q.Where.Match(&patterns[i], now)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		selected = append(selected, patterns[i])
+	}
+
+	if q.Sort != "" {
+		sort.SliceStable(selected, func(i, j int) bool {
+			c := compareField(&selected[i], &selected[j], q.Sort)
+			if q.Desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	if q.Limit > 0 && len(selected) > q.Limit {
+		selected = selected[:q.Limit]
+	}
+	return selected, nil
+}
+
+// Query returns the stored patterns selected by q.
+func (s *Store) Query(q Query, now time.Time) ([]Pattern, error) {
+	defer timing.Track("patterns.Query")()
+	return q.Apply(s.listMatching(indexFilterFor(q.Where)), now)
+}
+
+// compareField orders a and b by field: negative if a comes first.
+func compareField(a, b *Pattern, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "domain":
+		return strings.Compare(a.GetPrimaryDomain(), b.GetPrimaryDomain())
+	case "confidence":
+		return compareFloat(a.Learning.OriginalConfidence, b.Learning.OriginalConfidence)
+	case "effectiveness":
+		return compareFloat(a.Learning.Effectiveness, b.Learning.Effectiveness)
+	case "usage":
+		return a.Learning.UsageCount - b.Learning.UsageCount
+	case "last_used":
+		return lastUsed(a).Compare(lastUsed(b))
+	case "created":
+		return a.Lifecycle.Created.Compare(b.Lifecycle.Created)
+	case "updated":
+		return a.Lifecycle.Updated.Compare(b.Lifecycle.Updated)
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// lastUsed returns when p was last used; never-used patterns count from
+// creation, as in filters.
+func lastUsed(p *Pattern) time.Time {
+	if p.Learning.LastUsed != nil {
+		return *p.Learning.LastUsed
+	}
+	return p.Lifecycle.Created
+}
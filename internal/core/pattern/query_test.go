@@ -0,0 +1,120 @@
+package pattern
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWhere(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-3 * 24 * time.Hour)
+	p := &Pattern{
+		Name:      "docker-layer-cache",
+		Tags:      TagSet{Confirmed: []string{"docker", "ci"}},
+		Learning:  LearningMeta{OriginalConfidence: 0.8, Effectiveness: 0.6, LastUsed: &recent},
+		Lifecycle: LifecycleMeta{Status: StatusActive, Created: now.Add(-60 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		where string
+		want  bool
+	}{
+		{"", true},
+		{"confidence>0.7 and tag:docker and last_used<30d", true},
+		{"confidence > 0.7 AND tag : docker", true},
+		{"confidence>0.9 and tag:docker", false},
+		{"confidence>0.9 or tag:docker", true},
+		{"tag:go or tag:rust and confidence>0.5", false},
+		{"(tag:go or tag:docker) and confidence>0.5", true},
+		{"not status=archived", true},
+		{"not (tag:ci and effectiveness>=0.6)", false},
+		{`name="docker-*"`, true},
+		{"name='go *'", false},
+	}
+	for _, tt := range tests {
+		expr, err := ParseWhere(tt.where)
+		if err != nil {
+			t.Errorf("ParseWhere(%q): %v", tt.where, err)
+			continue
+		}
+		got := true
+		if expr != nil {
+			if got, err = expr.Match(p, now); err != nil {
+				t.Errorf("%q: %v", tt.where, err)
+				continue
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.where, got, tt.want)
+		}
+	}
+
+	invalid := map[string]string{
+		"confidence>":              "incomplete",
+		"color=red":                "unknown filter field",
+		"confidence>high":          "invalid number",
+		"domain>go":                "not supported",
+		"tag:go and":               "unexpected end",
+		"(tag:go or tag:rust":      "missing )",
+		"tag:go tag:rust":          "unexpected",
+		`name="unterminated`:       "unterminated",
+		"last_used<soon":           "invalid age",
+		"tag:go or or tag:rust":    "expected field<op>value",
+		"confidence>0.5 and (and)": "expected field<op>value",
+	}
+	for where, want := range invalid {
+		if _, err := ParseWhere(where); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseWhere(%q) error = %v, want %q", where, err, want)
+		}
+	}
+}
+
+func TestQueryApply(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	used := func(ago time.Duration) *time.Time { t := now.Add(-ago); return &t }
+	patterns := []Pattern{
+		{Name: "a", Tags: TagSet{Confirmed: []string{"docker"}}, Learning: LearningMeta{Effectiveness: 0.4, LastUsed: used(2 * day)}},
+		{Name: "b", Tags: TagSet{Confirmed: []string{"docker"}}, Learning: LearningMeta{Effectiveness: 0.9, LastUsed: used(40 * day)}},
+		{Name: "c", Tags: TagSet{Confirmed: []string{"docker"}}, Learning: LearningMeta{Effectiveness: 0.7, LastUsed: used(5 * day)}},
+		{Name: "d", Tags: TagSet{Confirmed: []string{"go"}}, Learning: LearningMeta{Effectiveness: 1.0, LastUsed: used(day)}},
+	}
+
+	names := func(q Query) string {
+		t.Helper()
+		got, err := q.Apply(patterns, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, p := range got {
+			out = append(out, p.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	q, err := ParseQuery("tag:docker", "effectiveness desc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(q); got != "b,c,a" {
+		t.Errorf("sorted by effectiveness desc = %s", got)
+	}
+
+	q, _ = ParseQuery("tag:docker and last_used<30d", "-effectiveness", 1)
+	if got := names(q); got != "c" {
+		t.Errorf("recent docker, top 1 = %s", got)
+	}
+
+	q, _ = ParseQuery("", "last_used:asc", 0)
+	if got := names(q); got != "b,c,a,d" {
+		t.Errorf("sorted by last_used = %s", got)
+	}
+
+	for _, spec := range []string{"color", "effectiveness sideways"} {
+		if _, _, err := ParseSort(spec); err == nil {
+			t.Errorf("ParseSort(%q) should fail", spec)
+		}
+	}
+}
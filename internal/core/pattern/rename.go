@@ -0,0 +1,208 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenameResult describes a completed rename.
+type RenameResult struct {
+	From, To string
+	// Renamed is the pattern as it was saved under its new name.
+	Renamed *Pattern
+	// Previous is the pattern as it was before the rename.
+	Previous *Pattern
+	// Referrers lists the patterns whose relations named the old name.
+	Referrers []string
+}
+
+// Rename gives a pattern a new name and rewrites every other pattern whose
+// relations (supersedes, related, conflicts_with) refer to it. The new
+// files are written beside the old ones first and then moved into place;
+// if anything fails the store is left as it was. Only patterns in the
+// store's own directory can be renamed, not ones from the synced repo.
+func (s *Store) Rename(from, to string) (*RenameResult, error) {
+	if err := validateName(from); err != nil {
+		return nil, err
+	}
+	if err := validateName(to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("pattern is already named %s", to)
+	}
+	oldPath, ok := findPatternFile(s.baseDir, from)
+	if !ok {
+		if s.Exists(from) {
+			return nil, fmt.Errorf("pattern %s comes from the synced repo; rename it there", from)
+		}
+		return nil, fmt.Errorf("pattern not found: %s", from)
+	}
+	if s.Exists(to) {
+		return nil, fmt.Errorf("pattern already exists: %s", to)
+	}
+
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read patterns: %w", err)
+	}
+
+	result := &RenameResult{From: from, To: to}
+	var writes []renameWrite
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(s.baseDir, entry.Name())
+		data, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var p Pattern
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			if path == oldPath {
+				return nil, fmt.Errorf("cannot parse pattern: %w", err)
+			}
+			continue
+		}
+
+		dest := path
+		switch {
+		case path == oldPath:
+			var prev Pattern
+			_ = yaml.Unmarshal(data, &prev)
+			result.Previous = &prev
+			p.Name = to
+			p.Relations.rename(from, to)
+			p.Lifecycle.Updated = time.Now()
+			dest = filepath.Join(s.baseDir, to+".yaml")
+			result.Renamed = &p
+		case p.Relations.rename(from, to):
+			result.Referrers = append(result.Referrers, p.Name)
+		default:
+			continue
+		}
+
+		out, err := yaml.Marshal(&p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot serialize pattern: %w", err)
+		}
+		w := renameWrite{from: path}
+		w.to, w.stale, w.data = s.encodeFile(dest, out)
+		if path != w.to {
+			w.remove = path
+		}
+		writes = append(writes, w)
+	}
+
+	if err := commitRenameWrites(writes); err != nil {
+		return nil, fmt.Errorf("cannot rename pattern: %w", err)
+	}
+	for _, w := range writes {
+		s.mirror(w.to, w.from, w.stale, w.remove)
+	}
+	// The history moves with the pattern
+	if _, err := os.Stat(s.historyPath(from)); err == nil {
+		_ = os.Rename(s.historyPath(from), s.historyPath(to))
+	}
+	return result, nil
+}
+
+// rename replaces from with to in the relations and reports whether any
+// referred to from.
+func (r *Relations) rename(from, to string) bool {
+	changed := false
+	if r.Supersedes == from {
+		r.Supersedes = to
+		changed = true
+	}
+	for _, list := range [][]string{r.Related, r.ConflictsWith} {
+		for i, name := range list {
+			if name == from {
+				list[i] = to
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// renameWrite is one file rewritten by Rename.
+type renameWrite struct {
+	from   string // file read
+	to     string // file written
+	stale  string // other variant of to, removed if present
+	remove string // from, when the pattern moves to a new file
+	data   []byte
+}
+
+// commitRenameWrites stages every write in a temporary file, then moves
+// them into place. A failure before the first move leaves nothing behind;
+// one after restores the files already replaced.
+func commitRenameWrites(writes []renameWrite) error {
+	var temps []string
+	cleanup := func() {
+		for _, t := range temps {
+			_ = os.Remove(t)
+		}
+	}
+	for _, w := range writes {
+		tmp := w.to + ".renaming"
+		if err := os.WriteFile(tmp, w.data, 0644); err != nil {
+			cleanup()
+			return err
+		}
+		temps = append(temps, tmp)
+	}
+
+	originals := make(map[string][]byte)
+	for _, w := range writes {
+		for _, path := range []string{w.to, w.from, w.stale} {
+			if _, ok := originals[path]; ok {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				cleanup()
+				return err
+			}
+			originals[path] = data
+		}
+	}
+
+	for i, w := range writes {
+		if err := os.Rename(temps[i], w.to); err != nil {
+			cleanup()
+			restoreOriginals(originals)
+			return err
+		}
+	}
+	for _, w := range writes {
+		for _, path := range []string{w.stale, w.remove} {
+			if path == "" || path == w.to {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				restoreOriginals(originals)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreOriginals puts back the files as they were before a rename; a
+// nil entry is a file that didn't exist.
+func restoreOriginals(originals map[string][]byte) {
+	for path, data := range originals {
+		if data == nil {
+			_ = os.Remove(path)
+			continue
+		}
+		_ = os.WriteFile(path, data, 0644)
+	}
+}
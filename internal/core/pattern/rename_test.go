@@ -0,0 +1,92 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreRename(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir).WithCompression(1024)
+
+	patterns := []*Pattern{
+		{Name: "debugging-solution-3f2a", Content: strings.Repeat("Raise the open file limit in the launchd plist.\n", 50)},
+		{Name: "launchd-basics", Content: "Use launchctl bootstrap.", Relations: Relations{Related: []string{"debugging-solution-3f2a", "other"}}},
+		{Name: "old-ulimit", Content: "ulimit -n", Relations: Relations{Supersedes: "debugging-solution-3f2a"}},
+		{Name: "unrelated", Content: "Nothing to see."},
+	}
+	for _, p := range patterns {
+		if err := store.Create(p); err != nil {
+			t.Fatalf("Create(%s): %v", p.Name, err)
+		}
+	}
+	before, _ := store.Get("debugging-solution-3f2a")
+
+	result, err := store.Rename("debugging-solution-3f2a", "launchd-open-file-limit")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if len(result.Referrers) != 2 {
+		t.Errorf("Referrers = %v, want 2", result.Referrers)
+	}
+	if result.Previous.Name != "debugging-solution-3f2a" || result.Renamed.Name != "launchd-open-file-limit" {
+		t.Errorf("Previous = %s, Renamed = %s", result.Previous.Name, result.Renamed.Name)
+	}
+
+	if store.Exists("debugging-solution-3f2a") {
+		t.Error("old name still exists")
+	}
+	got, err := store.Get("launchd-open-file-limit")
+	if err != nil {
+		t.Fatalf("Get new name: %v", err)
+	}
+	if got.ID != before.ID || got.Content != before.Content {
+		t.Error("rename changed the pattern's ID or content")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "launchd-open-file-limit"+CompressedExt)); err != nil {
+		t.Errorf("renamed pattern not stored compressed: %v", err)
+	}
+
+	related, _ := store.Get("launchd-basics")
+	if related.Relations.Related[0] != "launchd-open-file-limit" || related.Relations.Related[1] != "other" {
+		t.Errorf("Related = %v", related.Relations.Related)
+	}
+	superseding, _ := store.Get("old-ulimit")
+	if superseding.Relations.Supersedes != "launchd-open-file-limit" {
+		t.Errorf("Supersedes = %s", superseding.Relations.Supersedes)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 4 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("pattern dir holds %v, want 4 pattern files", names)
+	}
+}
+
+func TestStoreRenameRejects(t *testing.T) {
+	store := NewStore(t.TempDir()).WithCompression(0)
+	for _, name := range []string{"a", "b"} {
+		if err := store.Create(&Pattern{Name: name, Content: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct{ from, to string }{
+		{"a", "b"},
+		{"a", "a"},
+		{"missing", "c"},
+		{"a", "not valid"},
+	} {
+		if _, err := store.Rename(tt.from, tt.to); err == nil {
+			t.Errorf("Rename(%q, %q) succeeded", tt.from, tt.to)
+		}
+	}
+	if !store.Exists("a") || !store.Exists("b") {
+		t.Error("failed rename changed the store")
+	}
+}
@@ -24,6 +24,13 @@ type Pattern struct {
 	// Multi-dimensional tags (replaces fixed domain/category)
 	Tags TagSet `yaml:"tags"`
 
+	// Pinned patterns are always injected, regardless of relevance
+	Pinned bool `yaml:"pinned,omitempty"`
+
+	// Shared with the team: team sync pushes it to the team repo. It says
+	// nothing about how far the pattern is trusted (Security.TrustLevel).
+	TeamShared bool `yaml:"team_shared,omitempty"`
+
 	// Application conditions
 	Applies ApplyConditions `yaml:"applies,omitempty"`
 
@@ -53,6 +60,12 @@ type Pattern struct {
 
 	// Embedding hash for semantic search cache (SHA256 of content, first 16 chars)
 	EmbeddingHash string `yaml:"embedding_hash,omitempty"`
+
+	// Team changelog, oldest first; synced with the pattern
+	Changelog []ChangeEntry `yaml:"changelog,omitempty"`
+
+	// ScopeProject when read from a project's .mur/patterns/; not stored
+	Scope string `yaml:"-"`
 }
 
 // Relations tracks relationships between patterns.
@@ -178,6 +191,24 @@ type LearningMeta struct {
 	ExtractedFrom string `yaml:"extracted_from,omitempty"`
 	// Original confidence from extraction
 	OriginalConfidence float64 `yaml:"original_confidence,omitempty"`
+	// Whether the pattern's shell/Go code blocks parsed at extraction
+	// (nil when there was no code to check)
+	Validated *bool `yaml:"validated,omitempty"`
+	// Where in the session transcript the pattern was extracted from
+	Source *SourceRef `yaml:"source,omitempty"`
+}
+
+// SourceRef points back to the transcript excerpt a pattern was extracted
+// from. Line and Offset locate it quickly; Timestamp finds it again if the
+// transcript was rewritten, and Excerpt is shown if it's gone.
+type SourceRef struct {
+	Session   string    `yaml:"session" json:"session"`
+	Path      string    `yaml:"path" json:"path"`                             // transcript file at extraction time
+	Line      int       `yaml:"line,omitempty" json:"line,omitempty"`         // 1-based JSONL line
+	EndLine   int       `yaml:"end_line,omitempty" json:"end_line,omitempty"` // last line, for multi-step excerpts
+	Offset    int64     `yaml:"offset,omitempty" json:"offset,omitempty"`     // byte offset of Line
+	Timestamp time.Time `yaml:"timestamp,omitempty" json:"timestamp,omitempty"`
+	Excerpt   string    `yaml:"excerpt,omitempty" json:"excerpt,omitempty"`
 }
 
 // LifecycleStatus represents the lifecycle status of a pattern.
@@ -0,0 +1,448 @@
+package pattern
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/timing"
+)
+
+// Storage backends (storage.backend in config).
+const (
+	BackendFiles  = "files"
+	BackendSQLite = "sqlite"
+	// BackendDual reads the files and mirrors every write to the SQLite
+	// index while a move to sqlite is verified (see VerifyIndex).
+	BackendDual = "dual"
+)
+
+// indexSchemaVersion is stored as PRAGMA user_version; a database with
+// another version is rebuilt from the pattern files.
+const indexSchemaVersion = 1
+
+// With the sqlite backend, pattern files stay the source of truth (sync,
+// git, history, and editors work on them), and the store keeps every
+// parsed pattern in patterns.db next to the patterns directory. Listing
+// only stats the files and decodes rows instead of parsing YAML, and
+// Query narrows candidates by domain, tag, and status with indexed SQL.
+// Files changed behind the store's back are noticed by their size and
+// modification time and re-read. If the database can't be used, the
+// store falls back to reading the files.
+const indexSchema = `
+CREATE TABLE IF NOT EXISTS patterns (
+	path     TEXT PRIMARY KEY,
+	dir      TEXT NOT NULL,
+	name     TEXT NOT NULL,
+	size     INTEGER NOT NULL,
+	mod_time INTEGER NOT NULL,
+	domain   TEXT NOT NULL,
+	status   TEXT NOT NULL,
+	data     BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_patterns_dir ON patterns(dir);
+CREATE INDEX IF NOT EXISTS idx_patterns_domain ON patterns(domain);
+CREATE INDEX IF NOT EXISTS idx_patterns_status ON patterns(status);
+
+CREATE TABLE IF NOT EXISTS pattern_tags (
+	path TEXT NOT NULL REFERENCES patterns(path) ON DELETE CASCADE,
+	tag  TEXT NOT NULL,
+	PRIMARY KEY (path, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_pattern_tags_tag ON pattern_tags(tag);
+
+CREATE TABLE IF NOT EXISTS parity (
+	dir        TEXT PRIMARY KEY,
+	checked_at INTEGER NOT NULL,
+	files      INTEGER NOT NULL,
+	rows       INTEGER NOT NULL,
+	divergent  TEXT NOT NULL
+);
+`
+
+// StorageBackend returns the backend configured in cfg.
+func StorageBackend(cfg *config.Config) string {
+	if cfg != nil {
+		switch strings.ToLower(cfg.Storage.Backend) {
+		case BackendSQLite:
+			return BackendSQLite
+		case BackendDual:
+			return BackendDual
+		}
+	}
+	return BackendFiles
+}
+
+// WithBackend makes the store use backend (BackendFiles, BackendDual or
+// BackendSQLite).
+// Without it the store follows storage.backend in config.
+func (s *Store) WithBackend(backend string) *Store {
+	s.backend = backend
+	s.backendSet = true
+	return s
+}
+
+// IndexPath returns the SQLite database used by the sqlite backend.
+func (s *Store) IndexPath() string {
+	return filepath.Join(filepath.Dir(s.baseDir), "patterns.db")
+}
+
+// Backend returns the backend in use, and for a configured sqlite or dual
+// backend that can't be used, BackendFiles with the reason.
+func (s *Store) Backend() (string, error) {
+	if !s.backendSet {
+		cfg, _ := config.Load()
+		s.backend = StorageBackend(cfg)
+		s.backendSet = true
+	}
+	if s.backend != BackendSQLite && s.backend != BackendDual {
+		return BackendFiles, nil
+	}
+	if _, err := openIndex(s.IndexPath()); err != nil {
+		return BackendFiles, err
+	}
+	return s.backend, nil
+}
+
+// index returns the store's SQLite index, or nil when it uses files.
+func (s *Store) index() *patternIndex {
+	if backend, _ := s.Backend(); backend != BackendSQLite {
+		return nil
+	}
+	idx, _ := openIndex(s.IndexPath())
+	return idx
+}
+
+// patternIndex is an open patterns.db.
+type patternIndex struct {
+	db *sql.DB
+	mu sync.Mutex // serializes refreshes within the process
+}
+
+// indexes holds open databases by path for the life of the process, and
+// the error for those that failed to open.
+var indexes = struct {
+	sync.Mutex
+	open map[string]*patternIndex
+	errs map[string]error
+}{open: make(map[string]*patternIndex), errs: make(map[string]error)}
+
+func openIndex(path string) (*patternIndex, error) {
+	indexes.Lock()
+	defer indexes.Unlock()
+	if idx, ok := indexes.open[path]; ok {
+		return idx, nil
+	}
+	if err, ok := indexes.errs[path]; ok {
+		return nil, err
+	}
+	idx, err := newIndex(path)
+	if err != nil {
+		err = fmt.Errorf("pattern index %s: %w", path, err)
+		indexes.errs[path] = err
+		return nil, err
+	}
+	indexes.open[path] = idx
+	return idx, nil
+}
+
+func newIndex(path string) (*patternIndex, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if version != indexSchemaVersion {
+		// Only a cache of the files: rebuild rather than migrate
+		if _, err := db.Exec("DROP TABLE IF EXISTS pattern_tags; DROP TABLE IF EXISTS patterns"); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	if _, err := db.Exec(indexSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", indexSchemaVersion)); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &patternIndex{db: db}, nil
+}
+
+// indexRow is what the index knows about a pattern file.
+type indexRow struct {
+	size    int64
+	modTime int64
+	data    []byte
+}
+
+// refresh brings the rows for dir up to date with its pattern files and
+// reports how many were re-read from disk.
+func (idx *patternIndex) refresh(dir string) (int, error) {
+	defer timing.Track("patterns.index.refresh")()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	rows, err := idx.db.Query("SELECT path, size, mod_time FROM patterns WHERE dir = ?", dir)
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[string]indexRow)
+	for rows.Next() {
+		var path string
+		var r indexRow
+		if err := rows.Scan(&path, &r.size, &r.modTime); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		known[path] = r
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var tx *sql.Tx
+	begin := func() error {
+		if tx == nil {
+			tx, err = idx.db.Begin()
+		}
+		return err
+	}
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	reread := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		r, ok := known[path]
+		delete(known, path)
+		if ok && r.size == info.Size() && r.modTime == info.ModTime().UnixNano() {
+			continue
+		}
+
+		if err := begin(); err != nil {
+			return 0, err
+		}
+		data, err := ReadFile(path)
+		var p Pattern
+		if err == nil {
+			err = yaml.Unmarshal(data, &p)
+		}
+		if err != nil {
+			// Unreadable files are skipped, as when listing the files
+			if _, err := tx.Exec("DELETE FROM patterns WHERE path = ?", path); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := putRow(tx, dir, path, info, &p); err != nil {
+			return 0, err
+		}
+		reread++
+	}
+
+	for path := range known {
+		if err := begin(); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM patterns WHERE path = ?", path); err != nil {
+			return 0, err
+		}
+	}
+
+	if tx == nil {
+		return 0, nil
+	}
+	err = tx.Commit()
+	tx = nil
+	return reread, err
+}
+
+func putRow(tx *sql.Tx, dir, path string, info os.FileInfo, p *Pattern) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	status := string(p.Lifecycle.Status)
+	if status == "" {
+		status = string(StatusActive)
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO patterns (path, dir, name, size, mod_time, domain, status, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		path, dir, p.Name, info.Size(), info.ModTime().UnixNano(), p.GetPrimaryDomain(), status, data); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM pattern_tags WHERE path = ?", path); err != nil {
+		return err
+	}
+	tags := append([]string{}, p.Tags.Confirmed...)
+	for _, ts := range p.Tags.Inferred {
+		tags = append(tags, ts.Tag)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO pattern_tags (path, tag) VALUES (?, ?)", path, strings.ToLower(tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexFilter narrows an index lookup; empty fields match everything.
+type indexFilter struct {
+	Domain string
+	Tags   []string
+	Status string
+}
+
+// list returns the indexed patterns of dir that match f, in file name
+// order like os.ReadDir.
+func (idx *patternIndex) list(dir string, f indexFilter) ([]Pattern, error) {
+	defer timing.Track("patterns.index.list")()
+	query := "SELECT data FROM patterns WHERE dir = ?"
+	args := []any{dir}
+	if f.Domain != "" {
+		query += " AND domain = ?"
+		args = append(args, strings.ToLower(f.Domain))
+	}
+	if f.Status != "" {
+		query += " AND status = ?"
+		args = append(args, strings.ToLower(f.Status))
+	}
+	for _, tag := range f.Tags {
+		query += " AND path IN (SELECT path FROM pattern_tags WHERE tag = ?)"
+		args = append(args, strings.ToLower(tag))
+	}
+	query += " ORDER BY path"
+
+	rows, err := idx.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var patterns []Pattern
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var p Pattern
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, rows.Err()
+}
+
+// indexedList lists dir through the index, or reports false to fall back
+// to the files.
+func (s *Store) indexedList(dir string, f indexFilter) ([]Pattern, bool) {
+	idx := s.index()
+	if idx == nil {
+		return nil, false
+	}
+	if _, err := idx.refresh(dir); err != nil {
+		return nil, false
+	}
+	patterns, err := idx.list(dir, f)
+	if err != nil {
+		return nil, false
+	}
+	return patterns, true
+}
+
+// indexFilterFor returns the conditions of where that every match must
+// meet and the index can check: domain, tag, category, and status
+// equalities joined by and. where itself is still applied afterwards.
+func indexFilterFor(where Expr) indexFilter {
+	var f indexFilter
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch x := e.(type) {
+		case andExpr:
+			for _, y := range x {
+				walk(y)
+			}
+		case Filter:
+			if x.Op != "=" && x.Op != ":" {
+				return
+			}
+			switch x.Field {
+			case "domain":
+				f.Domain = x.Value
+			case "tag", "category":
+				f.Tags = append(f.Tags, x.Value)
+			case "status":
+				f.Status = x.Value
+			}
+		}
+	}
+	walk(where)
+	return f
+}
+
+// IndexReport is the result of Reindex.
+type IndexReport struct {
+	Path     string
+	Patterns int // patterns in the index
+	Reread   int // pattern files parsed to build or update it
+	Duration time.Duration
+}
+
+// Reindex builds or updates the sqlite backend's database from the
+// pattern files, whatever backend is configured ('mur migrate sqlite').
+func (s *Store) Reindex() (*IndexReport, error) {
+	start := time.Now()
+	idx, err := openIndex(s.IndexPath())
+	if err != nil {
+		return nil, err
+	}
+	report := &IndexReport{Path: s.IndexPath()}
+	for _, dir := range s.dirs() {
+		n, err := idx.refresh(dir)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", dir, err)
+		}
+		report.Reread += n
+		patterns, err := idx.list(dir, indexFilter{})
+		if err != nil {
+			return nil, err
+		}
+		report.Patterns += len(patterns)
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
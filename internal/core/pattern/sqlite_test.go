@@ -0,0 +1,106 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteBackend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "patterns")
+	store := NewStore(dir).WithCompression(0).WithBackend(BackendSQLite)
+
+	for _, p := range []*Pattern{
+		{Name: "go-errors", Content: "Wrap errors.", Tags: TagSet{Confirmed: []string{"go", "errors"}}},
+		{Name: "go-tests", Content: "Table tests.", Tags: TagSet{Confirmed: []string{"go", "Testing"}}},
+		{Name: "docker-cache", Content: "Order layers.", Tags: TagSet{Confirmed: []string{"docker"}}},
+	} {
+		if err := store.Create(p); err != nil {
+			t.Fatalf("Create(%s): %v", p.Name, err)
+		}
+	}
+
+	if backend, err := store.Backend(); backend != BackendSQLite || err != nil {
+		t.Fatalf("Backend() = %s, %v", backend, err)
+	}
+	if _, err := os.Stat(store.IndexPath()); err != nil {
+		t.Fatalf("index not created: %v", err)
+	}
+
+	names := func(patterns []Pattern) []string {
+		var out []string
+		for _, p := range patterns {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	list, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(list); len(got) != 3 || got[0] != "docker-cache" {
+		t.Errorf("List() = %v, want all three in file order", got)
+	}
+
+	where, err := ParseWhere("domain=go and tag:testing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Query(Query{Where: where}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := names(got); len(n) != 1 || n[0] != "go-tests" {
+		t.Errorf("Query(domain=go and tag:testing) = %v, want [go-tests]", n)
+	}
+	where, _ = ParseWhere("tag:docker or tag:errors")
+	if got, _ := store.Query(Query{Where: where}, time.Now()); len(got) != 2 {
+		t.Errorf("or-query returned %v, want 2 patterns", names(got))
+	}
+
+	// Files changed or removed outside the store are picked up
+	p, _ := store.Get("go-errors")
+	p.Lifecycle.Status = StatusDeprecated
+	if err := store.save(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "docker-cache.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	active, err := store.GetActive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := names(active); len(n) != 1 || n[0] != "go-tests" {
+		t.Errorf("GetActive() = %v, want [go-tests]", n)
+	}
+
+	report, err := store.Reindex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Patterns != 2 || report.Reread != 0 {
+		t.Errorf("Reindex() = %d patterns, %d re-read; want 2, 0", report.Patterns, report.Reread)
+	}
+}
+
+func TestSQLiteBackendFallsBackToFiles(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "patterns")
+	// A directory where the database should be can't be opened as one
+	if err := os.MkdirAll(filepath.Join(base, "patterns.db"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(dir).WithCompression(0).WithBackend(BackendSQLite)
+	if err := store.Create(&Pattern{Name: "a", Content: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if backend, err := store.Backend(); backend != BackendFiles || err == nil {
+		t.Errorf("Backend() = %s, %v; want files with an error", backend, err)
+	}
+	if list, _ := store.List(); len(list) != 1 {
+		t.Errorf("List() returned %d patterns, want 1", len(list))
+	}
+}
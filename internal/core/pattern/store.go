@@ -2,20 +2,38 @@ package pattern
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/timing"
 )
 
 // Store provides pattern storage operations.
 type Store struct {
-	baseDir   string
-	localOnly bool // when true, don't fall back to ~/.mur/repo/patterns/
+	baseDir    string
+	localOnly  bool   // when true, don't fall back to ~/.mur/repo/patterns/
+	projectDir string // the current repository's .mur/patterns/, if any
+
+	threshold    int  // compress YAML larger than this; 0 = never
+	thresholdSet bool // threshold given or loaded from config
+
+	retention    time.Duration // keep deleted patterns this long; < 0 = delete permanently
+	retentionSet bool          // retention given or loaded from config
+
+	backend    string // BackendFiles, BackendDual or BackendSQLite
+	backendSet bool   // backend given or loaded from config
+
+	limit    int    // largest content stored as is; 0 = no limit
+	oversize string // what happens to larger content, e.g. OversizeSplit
+	limitSet bool   // limit given or loaded from config
 }
 
 // NewStore creates a new Store with the given base directory.
@@ -23,7 +41,7 @@ type Store struct {
 // to ensure test isolation.
 func NewStore(baseDir string) *Store {
 	home, _ := os.UserHomeDir()
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	localOnly := !strings.HasPrefix(baseDir, murDir)
 	return &Store{baseDir: baseDir, localOnly: localOnly}
 }
@@ -34,7 +52,7 @@ func DefaultStore() (*Store, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return NewStore(filepath.Join(home, ".mur", "patterns")), nil
+	return NewStore(filepath.Join(config.DataDir(home), "patterns")), nil
 }
 
 // Dir returns the patterns directory path.
@@ -47,26 +65,47 @@ func (s *Store) EnsureDir() error {
 	return os.MkdirAll(s.baseDir, 0755)
 }
 
-// patternPath returns the file path for a pattern.
-// Checks baseDir and, unless localOnly, repo/patterns/.
+// patternPath returns the file a pattern is read from: the project
+// directory's, else userPatternPath's.
 func (s *Store) patternPath(name string) string {
+	if s.projectDir != "" {
+		if path, ok := findPatternFile(s.projectDir, name); ok {
+			return path
+		}
+	}
+	return s.userPatternPath(name)
+}
+
+// userPatternPath returns the file a pattern is written to.
+// Checks baseDir and, unless localOnly, repo/patterns/; never the project
+// directory, which only changes through the repository.
+func (s *Store) userPatternPath(name string) string {
 	// First check baseDir (~/.mur/patterns/)
-	path := filepath.Join(s.baseDir, name+".yaml")
-	if _, err := os.Stat(path); err == nil {
+	if path, ok := findPatternFile(s.baseDir, name); ok {
 		return path
 	}
 
 	if !s.localOnly {
 		// Check repo patterns (~/.mur/repo/patterns/)
 		home, _ := os.UserHomeDir()
-		repoPath := filepath.Join(home, ".mur", "repo", "patterns", name+".yaml")
-		if _, err := os.Stat(repoPath); err == nil {
-			return repoPath
+		if path, ok := findPatternFile(filepath.Join(config.DataDir(home), "repo", "patterns"), name); ok {
+			return path
 		}
 	}
 
 	// Default to baseDir
-	return path
+	return filepath.Join(s.baseDir, name+".yaml")
+}
+
+// findPatternFile returns the plain or compressed file for name in dir.
+func findPatternFile(dir, name string) (string, bool) {
+	for _, ext := range []string{".yaml", CompressedExt} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
 }
 
 // validateName checks if a pattern name is valid.
@@ -86,26 +125,49 @@ func validateName(name string) error {
 
 // List returns all patterns.
 func (s *Store) List() ([]Pattern, error) {
-	var patterns []Pattern
-
-	// Check for patterns in baseDir (~/.mur/patterns/)
-	if _, err := os.Stat(s.baseDir); err == nil {
-		patterns = append(patterns, s.listFromDir(s.baseDir)...)
-	}
+	defer timing.Track("patterns.List")()
+	return s.listMatching(indexFilter{}), nil
+}
 
+// dirs returns the directories patterns are listed from: baseDir
+// (~/.mur/patterns/) and, unless localOnly, the repo's patterns
+// (~/.mur/repo/patterns/).
+func (s *Store) dirs() []string {
+	dirs := []string{s.baseDir}
 	if !s.localOnly {
-		// Also check repo patterns (~/.mur/repo/patterns/)
 		home, _ := os.UserHomeDir()
-		repoDir := filepath.Join(home, ".mur", "repo", "patterns")
-		if info, err := os.Stat(repoDir); err == nil && info.IsDir() {
-			patterns = append(patterns, s.listFromDir(repoDir)...)
-		}
+		dirs = append(dirs, filepath.Join(config.DataDir(home), "repo", "patterns"))
 	}
+	return dirs
+}
 
-	return patterns, nil
+// listMatching returns the patterns of all dirs. With the sqlite backend
+// only those matching f; otherwise f is left for the caller to apply.
+func (s *Store) listMatching(f indexFilter) []Pattern {
+	patterns := s.ListProject()
+	shadowed := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		shadowed[p.Name] = true
+	}
+	for _, dir := range s.dirs() {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		s.checkParity(dir)
+		list, ok := s.indexedList(dir, f)
+		if !ok {
+			list = s.listFromDir(dir)
+		}
+		for _, p := range list {
+			if !shadowed[p.Name] {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	return patterns
 }
 
-// listFromDir reads patterns from a specific directory.
+// listFromDir reads patterns from the files in a specific directory.
 func (s *Store) listFromDir(dir string) []Pattern {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -114,12 +176,21 @@ func (s *Store) listFromDir(dir string) []Pattern {
 
 	var patterns []Pattern
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
 			continue
 		}
 
 		path := filepath.Join(dir, entry.Name())
-		data, err := os.ReadFile(path)
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if p, ok := parsed.get(path, info); ok {
+			patterns = append(patterns, p)
+			continue
+		}
+
+		data, err := ReadFile(path)
 		if err != nil {
 			continue
 		}
@@ -128,12 +199,46 @@ func (s *Store) listFromDir(dir string) []Pattern {
 		if err := yaml.Unmarshal(data, &p); err != nil {
 			continue
 		}
+		parsed.put(path, info, p)
 		patterns = append(patterns, p)
 	}
 
 	return patterns
 }
 
+// parsed caches decoded pattern files for the life of the process, so
+// commands that list patterns several times (e.g. the context hook) only
+// parse the YAML once. Entries are keyed by path and revalidated against
+// the file's size and modification time.
+var parsed = &parseCache{entries: make(map[string]parsedFile)}
+
+type parseCache struct {
+	mu      sync.Mutex
+	entries map[string]parsedFile
+}
+
+type parsedFile struct {
+	size    int64
+	modTime time.Time
+	pattern Pattern
+}
+
+func (c *parseCache) get(path string, info os.FileInfo) (Pattern, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.entries[path]
+	if !ok || f.size != info.Size() || !f.modTime.Equal(info.ModTime()) {
+		return Pattern{}, false
+	}
+	return f.pattern, true
+}
+
+func (c *parseCache) put(path string, info os.FileInfo, p Pattern) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = parsedFile{size: info.Size(), modTime: info.ModTime(), pattern: p}
+}
+
 // LoadVerified returns a pattern with hash integrity verification.
 // If the hash doesn't match, the pattern is returned with warnings and TrustLevel set to untrusted.
 func (s *Store) LoadVerified(name string) (*Pattern, error) {
@@ -157,7 +262,7 @@ func (s *Store) Get(name string) (*Pattern, error) {
 	}
 
 	path := s.patternPath(name)
-	data, err := os.ReadFile(path)
+	data, err := ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("pattern not found: %s", name)
@@ -169,6 +274,9 @@ func (s *Store) Get(name string) (*Pattern, error) {
 	if err := yaml.Unmarshal(data, &p); err != nil {
 		return nil, fmt.Errorf("cannot parse pattern: %w", err)
 	}
+	if s.projectDir != "" && filepath.Dir(path) == s.projectDir {
+		fromProject(&p)
+	}
 
 	return &p, nil
 }
@@ -189,9 +297,9 @@ func (s *Store) Create(p *Pattern) error {
 	}
 
 	// Set defaults
-	now := time.Now()
+	now := time.Now().UTC()
 	if p.ID == "" {
-		p.ID = uuid.New().String()
+		p.ID = NewID()
 	}
 	if p.Lifecycle.Created.IsZero() {
 		p.Lifecycle.Created = now
@@ -211,6 +319,10 @@ func (s *Store) Create(p *Pattern) error {
 	}
 	p.SchemaVersion = SchemaVersion
 
+	if err := s.fitContent(p); err != nil {
+		return err
+	}
+
 	// Calculate hash
 	p.UpdateHash()
 
@@ -228,35 +340,36 @@ func (s *Store) Update(p *Pattern) error {
 	if err != nil {
 		return fmt.Errorf("pattern not found: %s", p.Name)
 	}
+	if existing.Scope == ScopeProject {
+		return s.errProjectPattern(p.Name)
+	}
 
-	// Preserve creation time
+	// Preserve identity and creation time
+	if p.ID == "" {
+		p.ID = existing.ID
+	}
 	p.Lifecycle.Created = existing.Lifecycle.Created
-	p.Lifecycle.Updated = time.Now()
+	p.Lifecycle.Updated = time.Now().UTC()
 
-	// Recalculate hash if content changed
 	if p.Content != existing.Content {
-		p.UpdateHash()
-	}
-
-	return s.save(p)
-}
-
-// Delete removes a pattern.
-func (s *Store) Delete(name string) error {
-	if err := validateName(name); err != nil {
-		return err
+		if err := s.fitContent(p); err != nil {
+			return err
+		}
 	}
 
-	path := s.patternPath(name)
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("pattern not found: %s", name)
+	// Recalculate hash if content changed
+	if p.Content != existing.Content {
+		p.UpdateHash()
 	}
 
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("cannot delete pattern: %w", err)
+	// Keep the replaced version; see 'mur learn history'
+	if p.Content != existing.Content || p.Description != existing.Description {
+		if err := s.saveCurrentRevision(p.Name, "update"); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return s.save(p)
 }
 
 // Search returns patterns matching the query.
@@ -298,10 +411,7 @@ func (s *Store) Search(query string) ([]Pattern, error) {
 
 // GetByTag returns patterns with the given tag.
 func (s *Store) GetByTag(tag string) ([]Pattern, error) {
-	patterns, err := s.List()
-	if err != nil {
-		return nil, err
-	}
+	patterns := s.listMatching(indexFilter{Tags: []string{tag}})
 
 	tag = strings.ToLower(tag)
 	var results []Pattern
@@ -326,21 +436,51 @@ func (s *Store) GetByTag(tag string) ([]Pattern, error) {
 
 // GetActive returns only active patterns.
 func (s *Store) GetActive() ([]Pattern, error) {
-	patterns, err := s.List()
+	patterns := s.listMatching(indexFilter{Status: string(StatusActive)})
+
+	var results []Pattern
+	for _, p := range patterns {
+		if p.IsActive() {
+			results = append(results, p)
+		}
+	}
+
+	return results, nil
+}
+
+// GetPinned returns active pinned patterns sorted by name.
+func (s *Store) GetPinned() ([]Pattern, error) {
+	patterns, err := s.GetActive()
 	if err != nil {
 		return nil, err
 	}
 
 	var results []Pattern
 	for _, p := range patterns {
-		if p.IsActive() {
+		if p.Pinned {
 			results = append(results, p)
 		}
 	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
 
 	return results, nil
 }
 
+// SetPinned pins or unpins a pattern.
+func (s *Store) SetPinned(name string, pinned bool) error {
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	if p.Pinned == pinned {
+		return nil
+	}
+	p.Pinned = pinned
+	return s.Update(p)
+}
+
 // RecordUsage records that a pattern was used.
 func (s *Store) RecordUsage(name string) error {
 	p, err := s.Get(name)
@@ -348,22 +488,37 @@ func (s *Store) RecordUsage(name string) error {
 		return err
 	}
 
-	now := time.Now()
+	now := time.Now().UTC()
 	p.Learning.UsageCount++
 	p.Learning.LastUsed = &now
 
 	return s.save(p)
 }
 
-// save writes a pattern to disk.
+// SaveMeta writes changes to a pattern's bookkeeping (health, learning
+// stats) without marking it updated, so its freshness isn't reset.
+func (s *Store) SaveMeta(p *Pattern) error {
+	if err := validateName(p.Name); err != nil {
+		return err
+	}
+	if !s.Exists(p.Name) {
+		return fmt.Errorf("pattern not found: %s", p.Name)
+	}
+	return s.save(p)
+}
+
+// save writes a pattern to disk, never to the project directory.
 func (s *Store) save(p *Pattern) error {
-	path := s.patternPath(p.Name)
+	if p.Scope == ScopeProject {
+		return s.errProjectPattern(p.Name)
+	}
+	path := s.userPatternPath(p.Name)
 	data, err := yaml.Marshal(p)
 	if err != nil {
 		return fmt.Errorf("cannot serialize pattern: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := s.writeFile(path, data); err != nil {
 		return fmt.Errorf("cannot write pattern: %w", err)
 	}
 
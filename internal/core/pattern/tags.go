@@ -0,0 +1,199 @@
+package pattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// TagAliases maps a tag, in lower case, to the tag used instead.
+type TagAliases map[string]string
+
+// TagAliasesFromConfig returns the aliases set in tags.aliases.
+func TagAliasesFromConfig(cfg *config.Config) TagAliases {
+	if cfg == nil {
+		return nil
+	}
+	return NewTagAliases(cfg.Tags.Aliases)
+}
+
+// NewTagAliases returns aliases from a map of alias to tag, matching
+// aliases case-insensitively.
+func NewTagAliases(m map[string]string) TagAliases {
+	if len(m) == 0 {
+		return nil
+	}
+	a := make(TagAliases, len(m))
+	for from, to := range m {
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if from != "" && to != "" && !strings.EqualFold(from, to) {
+			a[strings.ToLower(from)] = to
+		}
+	}
+	return a
+}
+
+// Canonical returns the tag to use for tag. Chains (k8s → kube →
+// kubernetes) are followed; a cycle stops where it would repeat.
+func (a TagAliases) Canonical(tag string) string {
+	seen := map[string]bool{}
+	for {
+		key := strings.ToLower(tag)
+		to, ok := a[key]
+		if !ok || seen[key] {
+			return tag
+		}
+		seen[key] = true
+		tag = to
+	}
+}
+
+// NormalizeTags renames p's tags by aliases and drops the duplicates that
+// leaves, keeping the highest confidence of inferred tags. It reports the
+// renames made, e.g. "golang → go".
+func (p *Pattern) NormalizeTags(aliases TagAliases) []string {
+	if len(aliases) == 0 {
+		return nil
+	}
+	var changes []string
+	rename := func(tag string) string {
+		to := aliases.Canonical(tag)
+		if to != tag {
+			change := tag + " → " + to
+			if !containsString(changes, change) {
+				changes = append(changes, change)
+			}
+		}
+		return to
+	}
+
+	var confirmed []string
+	for _, t := range p.Tags.Confirmed {
+		if t = rename(t); !containsFold(confirmed, t) {
+			confirmed = append(confirmed, t)
+		}
+	}
+	var inferred []TagScore
+	index := map[string]int{}
+	for _, ts := range p.Tags.Inferred {
+		ts.Tag = rename(ts.Tag)
+		key := strings.ToLower(ts.Tag)
+		if i, ok := index[key]; ok {
+			inferred[i].Confidence = max(inferred[i].Confidence, ts.Confidence)
+			continue
+		}
+		index[key] = len(inferred)
+		inferred = append(inferred, ts)
+	}
+	var negative []string
+	for _, t := range p.Tags.Negative {
+		if t = rename(t); !containsFold(negative, t) {
+			negative = append(negative, t)
+		}
+	}
+
+	if len(changes) > 0 || len(confirmed) != len(p.Tags.Confirmed) || len(inferred) != len(p.Tags.Inferred) || len(negative) != len(p.Tags.Negative) {
+		p.Tags.Confirmed, p.Tags.Inferred, p.Tags.Negative = confirmed, inferred, negative
+	}
+	return changes
+}
+
+// NormalizeTagList renames tags by aliases and drops duplicates, keeping
+// the first spelling of each.
+func NormalizeTagList(tags []string, aliases TagAliases) []string {
+	if len(aliases) == 0 {
+		return tags
+	}
+	var out []string
+	for _, t := range tags {
+		if t = aliases.Canonical(t); !containsFold(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// TagCount is how many patterns carry a tag.
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Patterns  int    `json:"patterns"`
+	Confirmed int    `json:"confirmed"` // patterns where a user confirmed it
+	Inferred  int    `json:"inferred"`  // patterns where it was only inferred
+}
+
+// CountTags counts the tags of patterns, case-insensitively, most used
+// first. A tag is counted under its most common spelling.
+func CountTags(patterns []Pattern) []TagCount {
+	counts := map[string]*TagCount{}
+	spellings := map[string]map[string]int{}
+	add := func(tag string, confirmed bool) {
+		key := strings.ToLower(tag)
+		c, ok := counts[key]
+		if !ok {
+			c = &TagCount{}
+			counts[key] = c
+			spellings[key] = map[string]int{}
+		}
+		spellings[key][tag]++
+		c.Patterns++
+		if confirmed {
+			c.Confirmed++
+		} else {
+			c.Inferred++
+		}
+	}
+	for i := range patterns {
+		seen := map[string]bool{}
+		for _, t := range patterns[i].Tags.Confirmed {
+			if key := strings.ToLower(t); !seen[key] {
+				seen[key] = true
+				add(t, true)
+			}
+		}
+		for _, ts := range patterns[i].Tags.Inferred {
+			if key := strings.ToLower(ts.Tag); !seen[key] {
+				seen[key] = true
+				add(ts.Tag, false)
+			}
+		}
+	}
+
+	list := make([]TagCount, 0, len(counts))
+	for key, c := range counts {
+		best := ""
+		for spelling, n := range spellings[key] {
+			if best == "" || n > spellings[key][best] || (n == spellings[key][best] && spelling < best) {
+				best = spelling
+			}
+		}
+		c.Tag = best
+		list = append(list, *c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Patterns != list[j].Patterns {
+			return list[i].Patterns > list[j].Patterns
+		}
+		return list[i].Tag < list[j].Tag
+	})
+	return list
+}
+
+// RenameTags renames tags (old → new, old matched in any case) on every
+// pattern that has them, merging tags a pattern then has twice. Renames
+// chain like aliases do. It is a bulk operation: the patterns are
+// snapshotted first so 'mur learn bulk --undo' restores them.
+func (s *Store) RenameTags(renames map[string]string, dryRun bool) (*BulkResult, error) {
+	var where orExpr
+	for from, to := range renames {
+		if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return nil, fmt.Errorf("tag name is empty")
+		}
+		where = append(where, Filter{Field: "tag", Op: "=", Value: from})
+	}
+	if len(where) == 0 {
+		return &BulkResult{}, nil
+	}
+	return s.Bulk(BulkOptions{Where: where, RenameTags: renames, DryRun: dryRun})
+}
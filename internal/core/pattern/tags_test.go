@@ -0,0 +1,89 @@
+package pattern
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTagAliasesCanonical(t *testing.T) {
+	a := NewTagAliases(map[string]string{"golang": "go", "K8s": "kube", "kube": "kubernetes", "x": "y", "y": "x", "Go": "go"})
+	for tag, want := range map[string]string{
+		"Golang": "go", "k8s": "kubernetes", "go": "go", "rust": "rust", "x": "x",
+	} {
+		if got := a.Canonical(tag); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	p := &Pattern{Tags: TagSet{
+		Confirmed: []string{"golang", "go", "testing"},
+		Inferred:  []TagScore{{Tag: "k8s", Confidence: 0.9}, {Tag: "kubernetes", Confidence: 0.5}},
+	}}
+	changes := p.NormalizeTags(NewTagAliases(map[string]string{"golang": "go", "k8s": "kubernetes"}))
+	if len(changes) != 2 {
+		t.Errorf("changes = %v", changes)
+	}
+	if !slices.Equal(p.Tags.Confirmed, []string{"go", "testing"}) {
+		t.Errorf("confirmed = %v", p.Tags.Confirmed)
+	}
+	if len(p.Tags.Inferred) != 1 || p.Tags.Inferred[0] != (TagScore{Tag: "kubernetes", Confidence: 0.9}) {
+		t.Errorf("inferred = %v", p.Tags.Inferred)
+	}
+
+	if got := NormalizeTagList([]string{"Golang", "go", "api"}, NewTagAliases(map[string]string{"golang": "go"})); !slices.Equal(got, []string{"go", "api"}) {
+		t.Errorf("NormalizeTagList = %v", got)
+	}
+}
+
+func TestCountTags(t *testing.T) {
+	counts := CountTags([]Pattern{
+		{Tags: TagSet{Confirmed: []string{"go", "api"}, Inferred: []TagScore{{Tag: "Go"}}}},
+		{Tags: TagSet{Inferred: []TagScore{{Tag: "go"}}}},
+	})
+	want := []TagCount{
+		{Tag: "go", Patterns: 2, Confirmed: 1, Inferred: 1},
+		{Tag: "api", Patterns: 1, Confirmed: 1},
+	}
+	if !slices.Equal(counts, want) {
+		t.Errorf("CountTags = %+v, want %+v", counts, want)
+	}
+}
+
+func TestRenameTagsAndUndo(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "patterns"))
+	for name, tags := range map[string][]string{
+		"one":   {"golang", "testing"},
+		"two":   {"Go-Lang", "go"},
+		"three": {"rust"},
+	} {
+		if err := store.Create(&Pattern{Name: name, Content: name, Tags: TagSet{Confirmed: tags}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := store.RenameTags(map[string]string{"golang": "go", "go-lang": "go"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 2 || len(result.Changes) != 2 || result.SnapshotID == "" {
+		t.Fatalf("result = %+v", result)
+	}
+	two, err := store.Get("two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(two.Tags.Confirmed, []string{"go"}) {
+		t.Errorf("two's tags = %v, want [go]", two.Tags.Confirmed)
+	}
+
+	if _, err := store.UndoBulk(result.SnapshotID); err != nil {
+		t.Fatal(err)
+	}
+	one, _ := store.Get("one")
+	if !slices.Equal(one.Tags.Confirmed, []string{"golang", "testing"}) {
+		t.Errorf("undo left %v", one.Tags.Confirmed)
+	}
+}
@@ -0,0 +1,88 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// timeKeys are the pattern file keys that hold timestamps.
+var timeKeys = map[string]bool{"created": true, "updated": true, "last_used": true}
+
+// NormalizeTimes rewrites the timestamps of every pattern in the store's
+// own directory to UTC, and returns the names of the patterns changed.
+// Patterns written before timestamps were stored in UTC carry the offset
+// of the machine that wrote them. With dryRun nothing is written.
+func (s *Store) NormalizeTimes(dryRun bool) ([]string, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot read patterns: %w", err)
+	}
+
+	var changed []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsPatternFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(s.baseDir, entry.Name())
+		data, err := ReadFile(path)
+		if err != nil {
+			return changed, err
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			continue
+		}
+		if !timesToUTC(&doc) {
+			continue
+		}
+
+		name := PatternFileName(entry.Name())
+		changed = append(changed, name)
+		if dryRun {
+			continue
+		}
+		out, err := yaml.Marshal(&doc)
+		if err != nil {
+			return changed, fmt.Errorf("cannot update pattern %s: %w", name, err)
+		}
+		if err := s.writeFile(path, out); err != nil {
+			return changed, fmt.Errorf("cannot write pattern %s: %w", name, err)
+		}
+	}
+	return changed, nil
+}
+
+// timesToUTC converts the timestamps under node to UTC in place, keeping
+// the rest of the document as it was, and reports whether any changed.
+func timesToUTC(node *yaml.Node) bool {
+	changed := false
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v := node.Content[i+1]
+			if !timeKeys[node.Content[i].Value] || v.Kind != yaml.ScalarNode {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, v.Value)
+			if err != nil {
+				continue
+			}
+			if utc := t.UTC().Format(time.RFC3339Nano); utc != v.Value {
+				v.Value = utc
+				changed = true
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if timesToUTC(child) {
+			changed = true
+		}
+	}
+	return changed
+}
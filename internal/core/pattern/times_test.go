@@ -0,0 +1,60 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTimes(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	old := `name: go-errors
+# written before times were UTC
+content: Wrap errors.
+learning:
+    last_used: 2026-03-09T20:00:00-05:00
+lifecycle:
+    created: 2026-03-01T09:00:00+09:00
+    updated: 2026-03-02T00:00:00Z
+`
+	if err := os.WriteFile(filepath.Join(dir, "go-errors.yaml"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(&Pattern{Name: "fresh", Content: "New."}); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := store.NormalizeTimes(true)
+	if err != nil || len(changed) != 1 || changed[0] != "go-errors" {
+		t.Fatalf("dry run = %v, %v; want [go-errors]", changed, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "go-errors.yaml")); string(data) != old {
+		t.Error("dry run changed the file")
+	}
+
+	if _, err := store.NormalizeTimes(false); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "go-errors.yaml"))
+	if !strings.Contains(string(data), "# written before times were UTC") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+	p, err := store.Get("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Lifecycle.Created.Location() != time.UTC || !p.Lifecycle.Created.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("created = %v", p.Lifecycle.Created)
+	}
+	if p.Learning.LastUsed == nil || !p.Learning.LastUsed.Equal(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)) || p.Learning.LastUsed.Location() != time.UTC {
+		t.Errorf("last_used = %v", p.Learning.LastUsed)
+	}
+
+	if changed, _ := store.NormalizeTimes(false); len(changed) != 0 {
+		t.Errorf("second run changed %v", changed)
+	}
+}
@@ -0,0 +1,417 @@
+package pattern
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// DefaultTrashDays is how long deleted patterns stay in the trash when
+// storage.trash_days isn't set.
+const DefaultTrashDays = 30
+
+// tombstoneTTL is how long a purged pattern is remembered so sync targets
+// that haven't run yet still learn about the deletion.
+const tombstoneTTL = 90 * 24 * time.Hour
+
+// Sync targets that deletions propagate to once they are purged.
+const (
+	PropagateCloud = "cloud"
+	PropagateRepo  = "learning-repo"
+)
+
+// TrashEntry is a deleted pattern kept for restoring.
+type TrashEntry struct {
+	ID        string    `json:"-"`
+	Name      string    `json:"name"`
+	PatternID string    `json:"pattern_id,omitempty"`
+	From      string    `json:"from"` // original path
+	DeletedAt time.Time `json:"deleted_at"`
+	Reason    string    `json:"reason,omitempty"`
+	// Remote is set for deletions pulled from a sync remote; purging them
+	// doesn't propagate the deletion back.
+	Remote bool `json:"remote,omitempty"`
+}
+
+// ExpiresAt returns when the entry is purged under retention.
+func (e TrashEntry) ExpiresAt(retention time.Duration) time.Time {
+	return e.DeletedAt.Add(retention)
+}
+
+// Tombstone records a purged pattern until every sync target has
+// propagated its deletion.
+type Tombstone struct {
+	Name       string    `json:"name"`
+	PatternID  string    `json:"pattern_id,omitempty"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgedAt   time.Time `json:"purged_at"`
+	Propagated []string  `json:"propagated,omitempty"` // sync targets done
+}
+
+// TrashRetention returns how long deleted patterns stay in the trash, or
+// a negative duration when deletes are permanent (storage.trash_days < 0).
+func TrashRetention(cfg *config.Config) time.Duration {
+	days := DefaultTrashDays
+	if cfg != nil && cfg.Storage.TrashDays != 0 {
+		days = cfg.Storage.TrashDays
+	}
+	if days < 0 {
+		return -1
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// WithTrashRetention sets how long deleted patterns are kept; a negative
+// duration deletes permanently. Without it the store follows
+// storage.trash_days in config.
+func (s *Store) WithTrashRetention(d time.Duration) *Store {
+	s.retention = d
+	s.retentionSet = true
+	return s
+}
+
+// TrashRetention returns the store's trash retention.
+func (s *Store) TrashRetention() time.Duration {
+	if !s.retentionSet {
+		cfg, _ := config.Load()
+		s.retention = TrashRetention(cfg)
+		s.retentionSet = true
+	}
+	return s.retention
+}
+
+// TrashDir returns where deleted patterns are kept.
+func (s *Store) TrashDir() string {
+	return filepath.Join(s.baseDir, ".trash")
+}
+
+func (s *Store) tombstonesPath() string {
+	return filepath.Join(s.TrashDir(), "tombstones.json")
+}
+
+// Delete moves a pattern to the trash, where it can be restored until the
+// retention window passes. Sync targets learn of the deletion only once
+// it is purged.
+func (s *Store) Delete(name string) error {
+	return s.remove(name, "", false, false)
+}
+
+// DeleteRemote moves a pattern that was deleted on a sync remote to the
+// trash. Purging it doesn't propagate the deletion back.
+func (s *Store) DeleteRemote(name, reason string) error {
+	return s.remove(name, reason, true, false)
+}
+
+// Purge deletes a pattern permanently, skipping the trash; sync targets
+// propagate the deletion on their next run.
+func (s *Store) Purge(name string) error {
+	return s.remove(name, "", false, true)
+}
+
+func (s *Store) remove(name, reason string, remote, purge bool) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	path := s.userPatternPath(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("pattern not found: %s", name)
+	}
+	entry := TrashEntry{Name: name, From: path, DeletedAt: time.Now(), Reason: reason, Remote: remote}
+	if data, err := ReadFile(path); err == nil {
+		var p Pattern
+		if yaml.Unmarshal(data, &p) == nil {
+			entry.PatternID = p.ID
+		}
+	}
+
+	if purge || s.TrashRetention() < 0 {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("cannot delete pattern: %w", err)
+		}
+		s.mirror(path)
+		if !remote {
+			return s.addTombstones([]TrashEntry{entry}, time.Now())
+		}
+		return nil
+	}
+
+	entry.ID = entry.DeletedAt.Format("20060102-150405.000000") + "-" + name
+	dir := filepath.Join(s.TrashDir(), entry.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create trash: %w", err)
+	}
+	if err := moveFile(path, filepath.Join(dir, filepath.Base(path))); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("cannot move pattern to trash: %w", err)
+	}
+	s.mirror(path)
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644)
+}
+
+// Trash returns the deleted patterns, newest first.
+func (s *Store) Trash() ([]TrashEntry, error) {
+	entries, err := os.ReadDir(s.TrashDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var trash []TrashEntry
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.TrashDir(), e.Name(), "manifest.json"))
+		if err != nil {
+			continue
+		}
+		var entry TrashEntry
+		if json.Unmarshal(data, &entry) != nil {
+			continue
+		}
+		entry.ID = e.Name()
+		trash = append(trash, entry)
+	}
+	sort.Slice(trash, func(i, j int) bool { return trash[i].DeletedAt.After(trash[j].DeletedAt) })
+	return trash, nil
+}
+
+// InTrash reports whether a deleted pattern named name is in the trash.
+func (s *Store) InTrash(name string) bool {
+	trash, _ := s.Trash()
+	for _, e := range trash {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Restore moves the most recently deleted pattern named name back to
+// where it was deleted from.
+func (s *Store) Restore(name string) (*TrashEntry, error) {
+	trash, err := s.Trash()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range trash {
+		if e.Name != name {
+			continue
+		}
+		if s.Exists(name) {
+			return nil, fmt.Errorf("a pattern named %s already exists; rename or delete it first", name)
+		}
+		dir := filepath.Join(s.TrashDir(), e.ID)
+		if err := os.MkdirAll(filepath.Dir(e.From), 0755); err != nil {
+			return nil, err
+		}
+		if err := moveFile(filepath.Join(dir, filepath.Base(e.From)), e.From); err != nil {
+			return nil, fmt.Errorf("cannot restore %s: %w", name, err)
+		}
+		s.mirror(e.From)
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	}
+	return nil, fmt.Errorf("%s is not in the trash", name)
+}
+
+// EmptyTrash permanently deletes trashed patterns deleted more than
+// olderThan ago (all of them when olderThan is 0) and returns them. Their
+// deletions then propagate to sync targets.
+func (s *Store) EmptyTrash(olderThan time.Duration, now time.Time) ([]TrashEntry, error) {
+	trash, err := s.Trash()
+	if err != nil {
+		return nil, err
+	}
+	var purged []TrashEntry
+	for _, e := range trash {
+		if olderThan > 0 && now.Sub(e.DeletedAt) < olderThan {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.TrashDir(), e.ID)); err != nil {
+			return purged, fmt.Errorf("cannot purge %s: %w", e.Name, err)
+		}
+		purged = append(purged, e)
+	}
+
+	var local []TrashEntry
+	for _, e := range purged {
+		if !e.Remote {
+			local = append(local, e)
+		}
+	}
+	if err := s.addTombstones(local, now); err != nil {
+		return purged, err
+	}
+	return purged, nil
+}
+
+// PurgeExpired empties the trash of patterns past the retention window.
+// With permanent deletes it purges whatever was trashed before.
+func (s *Store) PurgeExpired(now time.Time) ([]TrashEntry, error) {
+	retention := s.TrashRetention()
+	if retention < 0 {
+		retention = 0
+	}
+	return s.EmptyTrash(retention, now)
+}
+
+// PendingDeletions returns purged patterns whose deletion target hasn't
+// propagated yet. A pattern created again under the same name since is
+// not pending.
+func (s *Store) PendingDeletions(target string) ([]Tombstone, error) {
+	tombstones, err := s.tombstones()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Tombstone
+	for _, t := range tombstones {
+		if !containsString(t.Propagated, target) && !s.Exists(t.Name) {
+			pending = append(pending, t)
+		}
+	}
+	return pending, nil
+}
+
+// MarkPropagated records that target has propagated the deletion of names.
+func (s *Store) MarkPropagated(target string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	tombstones, err := s.tombstones()
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(names))
+	for _, n := range names {
+		done[n] = true
+	}
+	for i := range tombstones {
+		if done[tombstones[i].Name] && !containsString(tombstones[i].Propagated, target) {
+			tombstones[i].Propagated = append(tombstones[i].Propagated, target)
+		}
+	}
+	return s.saveTombstones(tombstones, time.Now())
+}
+
+// Purged reports whether name was recently purged, so syncs don't bring
+// it back from a target that hasn't propagated the deletion yet.
+func (s *Store) Purged(name string) bool {
+	tombstones, _ := s.tombstones()
+	for _, t := range tombstones {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) tombstones() ([]Tombstone, error) {
+	data, err := os.ReadFile(s.tombstonesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tombstones []Tombstone
+	if err := json.Unmarshal(data, &tombstones); err != nil {
+		return nil, fmt.Errorf("corrupt trash tombstones: %w", err)
+	}
+	return tombstones, nil
+}
+
+func (s *Store) addTombstones(entries []TrashEntry, now time.Time) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tombstones, err := s.tombstones()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// A newer deletion of the same name replaces the old one
+		kept := tombstones[:0]
+		for _, t := range tombstones {
+			if t.Name != e.Name {
+				kept = append(kept, t)
+			}
+		}
+		tombstones = append(kept, Tombstone{Name: e.Name, PatternID: e.PatternID, DeletedAt: e.DeletedAt, PurgedAt: now})
+	}
+	return s.saveTombstones(tombstones, now)
+}
+
+// saveTombstones writes the tombstones, dropping expired ones.
+func (s *Store) saveTombstones(tombstones []Tombstone, now time.Time) error {
+	var kept []Tombstone
+	for _, t := range tombstones {
+		if now.Sub(t.PurgedAt) < tombstoneTTL {
+			kept = append(kept, t)
+		}
+	}
+	if err := os.MkdirAll(s.TrashDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.tombstonesPath(), data, 0644)
+}
+
+// TrashedFrom returns the original paths of trashed patterns that were
+// deleted from dir, e.g. a git working tree whose sync must not commit
+// the deletions yet.
+func (s *Store) TrashedFrom(dir string) []string {
+	trash, _ := s.Trash()
+	var paths []string
+	for _, e := range trash {
+		if rel, err := filepath.Rel(dir, e.From); err == nil && !strings.HasPrefix(rel, "..") {
+			paths = append(paths, e.From)
+		}
+	}
+	return paths
+}
+
+// moveFile renames src to dst, copying when they are on different devices.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
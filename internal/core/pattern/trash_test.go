@@ -0,0 +1,146 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTrashStore(t *testing.T) *Store {
+	t.Helper()
+	store := NewStore(t.TempDir()).WithCompression(0).WithTrashRetention(30 * 24 * time.Hour)
+	for _, name := range []string{"keep", "oops"} {
+		if err := store.Create(&Pattern{Name: name, Content: "content of " + name}); err != nil {
+			t.Fatalf("Create(%s): %v", name, err)
+		}
+	}
+	return store
+}
+
+func TestStoreDeleteRestore(t *testing.T) {
+	store := newTrashStore(t)
+	before, _ := store.Get("oops")
+
+	if err := store.Delete("oops"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if store.Exists("oops") {
+		t.Fatal("deleted pattern still listed")
+	}
+	trash, err := store.Trash()
+	if err != nil || len(trash) != 1 {
+		t.Fatalf("Trash = %v, %v", trash, err)
+	}
+	if trash[0].Name != "oops" || trash[0].PatternID != before.ID {
+		t.Errorf("entry = %+v", trash[0])
+	}
+	if !store.InTrash("oops") || store.InTrash("keep") {
+		t.Error("InTrash wrong")
+	}
+	// Trashing isn't a deletion sync targets see yet
+	if pending, _ := store.PendingDeletions(PropagateCloud); len(pending) != 0 {
+		t.Errorf("pending = %v before purge", pending)
+	}
+
+	if _, err := store.Restore("oops"); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	got, err := store.Get("oops")
+	if err != nil || got.Content != before.Content {
+		t.Fatalf("restored = %v, %v", got, err)
+	}
+	if trash, _ := store.Trash(); len(trash) != 0 {
+		t.Errorf("trash after restore = %v", trash)
+	}
+	if _, err := store.Restore("oops"); err == nil {
+		t.Error("restoring a pattern not in the trash succeeded")
+	}
+
+	// Restoring over a pattern created since fails
+	_ = store.Delete("oops")
+	_ = store.Create(&Pattern{Name: "oops", Content: "new"})
+	if _, err := store.Restore("oops"); err == nil {
+		t.Error("restore overwrote an existing pattern")
+	}
+}
+
+func TestStoreEmptyTrashPropagates(t *testing.T) {
+	store := newTrashStore(t)
+	_ = store.Delete("oops")
+	if err := store.DeleteRemote("keep", "deleted on the team server"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is past the retention window yet
+	now := time.Now()
+	if purged, _ := store.PurgeExpired(now); len(purged) != 0 {
+		t.Errorf("purged %v within the window", purged)
+	}
+
+	purged, err := store.PurgeExpired(now.Add(31 * 24 * time.Hour))
+	if err != nil || len(purged) != 2 {
+		t.Fatalf("PurgeExpired = %v, %v", purged, err)
+	}
+	if entries, _ := os.ReadDir(store.TrashDir()); len(entries) != 1 {
+		t.Errorf("trash dir = %v, want only the tombstones", entries)
+	}
+
+	// Only the local deletion propagates, once per target
+	pending, _ := store.PendingDeletions(PropagateCloud)
+	if len(pending) != 1 || pending[0].Name != "oops" {
+		t.Fatalf("pending = %+v, want oops", pending)
+	}
+	if !store.Purged("oops") {
+		t.Error("Purged(oops) = false")
+	}
+	if err := store.MarkPropagated(PropagateCloud, []string{"oops"}); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := store.PendingDeletions(PropagateCloud); len(pending) != 0 {
+		t.Errorf("cloud still pending: %v", pending)
+	}
+	if pending, _ := store.PendingDeletions(PropagateRepo); len(pending) != 1 {
+		t.Errorf("repo pending = %v, want oops", pending)
+	}
+
+	// A new pattern under the purged name isn't deleted remotely
+	_ = store.Create(&Pattern{Name: "oops", Content: "again"})
+	if pending, _ := store.PendingDeletions(PropagateRepo); len(pending) != 0 {
+		t.Errorf("pending = %v after re-creating oops", pending)
+	}
+}
+
+func TestStorePurgeSkipsTrash(t *testing.T) {
+	store := newTrashStore(t)
+	if err := store.Purge("oops"); err != nil {
+		t.Fatal(err)
+	}
+	if trash, _ := store.Trash(); len(trash) != 0 {
+		t.Errorf("trash = %v after purge", trash)
+	}
+	if pending, _ := store.PendingDeletions(PropagateCloud); len(pending) != 1 {
+		t.Errorf("pending = %v, want oops", pending)
+	}
+
+	// Permanent deletes when the trash is off
+	store.WithTrashRetention(-1)
+	_ = store.Delete("keep")
+	if _, err := os.Stat(filepath.Join(store.Dir(), "keep.yaml")); !os.IsNotExist(err) {
+		t.Error("keep.yaml still exists")
+	}
+	if trash, _ := store.Trash(); len(trash) != 0 {
+		t.Errorf("trash = %v with retention off", trash)
+	}
+}
+
+func TestStoreTrashedFrom(t *testing.T) {
+	store := newTrashStore(t)
+	_ = store.Delete("oops")
+	if got := store.TrashedFrom(store.Dir()); len(got) != 1 || filepath.Base(got[0]) != "oops.yaml" {
+		t.Errorf("TrashedFrom = %v", got)
+	}
+	if got := store.TrashedFrom(t.TempDir()); len(got) != 0 {
+		t.Errorf("TrashedFrom(other) = %v", got)
+	}
+}
@@ -0,0 +1,139 @@
+package pattern
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// A repository's .mur/patterns/ comes with the code, so anyone who can
+// push to it decides what they say. Like VS Code's workspace trust, mur
+// only uses them once you've said the repository is trusted; the
+// decisions are kept in ~/.mur/workspaces.json, keyed by repository root.
+
+// WorkspaceDecision is a trust decision about one repository.
+type WorkspaceDecision struct {
+	Root    string    `json:"root"`
+	Trusted bool      `json:"trusted"`
+	Decided time.Time `json:"decided"`
+}
+
+// workspacesPath returns the file trust decisions are kept in.
+func workspacesPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(config.DataDir(home), "workspaces.json")
+}
+
+// ProjectRoot returns the root of the repository whose patterns are in
+// projectDir, a ProjectPatternsDir.
+func ProjectRoot(projectDir string) string {
+	return filepath.Dir(filepath.Dir(projectDir))
+}
+
+// WorkspaceDecisions returns the recorded trust decisions, by root.
+func WorkspaceDecisions() ([]WorkspaceDecision, error) {
+	data, err := os.ReadFile(workspacesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var decisions []WorkspaceDecision
+	if err := json.Unmarshal(data, &decisions); err != nil {
+		return nil, fmt.Errorf("corrupt workspace trust file: %w", err)
+	}
+	sort.Slice(decisions, func(i, j int) bool { return decisions[i].Root < decisions[j].Root })
+	return decisions, nil
+}
+
+// WorkspaceTrust returns whether the repository at root is trusted, and
+// whether that was decided at all. An unreadable trust file trusts nothing.
+func WorkspaceTrust(root string) (trusted, decided bool) {
+	decisions, err := WorkspaceDecisions()
+	if err != nil {
+		return false, false
+	}
+	for _, d := range decisions {
+		if d.Root == root {
+			return d.Trusted, true
+		}
+	}
+	return false, false
+}
+
+// SetWorkspaceTrust records that the repository at root is trusted or
+// not, replacing an earlier decision.
+func SetWorkspaceTrust(root string, trusted bool) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	decisions, err := WorkspaceDecisions()
+	if err != nil {
+		return err
+	}
+	decisions = dropDecision(decisions, root)
+	decisions = append(decisions, WorkspaceDecision{Root: root, Trusted: trusted, Decided: time.Now().UTC()})
+	return saveWorkspaceDecisions(decisions)
+}
+
+// RevokeWorkspaceTrust forgets the decision about the repository at root,
+// so its patterns aren't used until it's trusted again. It reports
+// whether there was one.
+func RevokeWorkspaceTrust(root string) (bool, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return false, err
+	}
+	decisions, err := WorkspaceDecisions()
+	if err != nil {
+		return false, err
+	}
+	kept := dropDecision(decisions, root)
+	if len(kept) == len(decisions) {
+		return false, nil
+	}
+	return true, saveWorkspaceDecisions(kept)
+}
+
+func dropDecision(decisions []WorkspaceDecision, root string) []WorkspaceDecision {
+	var kept []WorkspaceDecision
+	for _, d := range decisions {
+		if d.Root != root {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+func saveWorkspaceDecisions(decisions []WorkspaceDecision) error {
+	path := workspacesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(decisions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// TrustedProjectDir returns FindProjectDir(dir) if that repository is
+// trusted, else "": patterns of an untrusted or undecided repository are
+// never used.
+func TrustedProjectDir(dir string) string {
+	projectDir := FindProjectDir(dir)
+	if projectDir == "" {
+		return ""
+	}
+	if trusted, _ := WorkspaceTrust(ProjectRoot(projectDir)); !trusted {
+		return ""
+	}
+	return projectDir
+}
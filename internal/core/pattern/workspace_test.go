@@ -0,0 +1,64 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceTrust(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	projectDir := filepath.Join(repo, ProjectPatternsDir)
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStore(projectDir).Create(&Pattern{Name: "repo-rule", Content: "from the repo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undecided: never used
+	if trusted, decided := WorkspaceTrust(repo); trusted || decided {
+		t.Errorf("WorkspaceTrust before a decision = %v, %v", trusted, decided)
+	}
+	if got := TrustedProjectDir(repo); got != "" {
+		t.Errorf("TrustedProjectDir(undecided) = %q, want empty", got)
+	}
+	store, err := DefaultStoreFor(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.ProjectDir() != "" || len(store.ListProject()) != 0 {
+		t.Errorf("DefaultStoreFor(undecided) loaded %q", store.ProjectDir())
+	}
+
+	if err := SetWorkspaceTrust(repo, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := TrustedProjectDir(repo); got != projectDir {
+		t.Errorf("TrustedProjectDir(trusted) = %q, want %q", got, projectDir)
+	}
+
+	// A later decision replaces the earlier one
+	if err := SetWorkspaceTrust(repo, false); err != nil {
+		t.Fatal(err)
+	}
+	if trusted, decided := WorkspaceTrust(repo); trusted || !decided {
+		t.Errorf("WorkspaceTrust after deny = %v, %v", trusted, decided)
+	}
+	decisions, err := WorkspaceDecisions()
+	if err != nil || len(decisions) != 1 {
+		t.Fatalf("decisions = %+v, err = %v", decisions, err)
+	}
+
+	found, err := RevokeWorkspaceTrust(repo)
+	if err != nil || !found {
+		t.Fatalf("RevokeWorkspaceTrust = %v, %v", found, err)
+	}
+	if _, decided := WorkspaceTrust(repo); decided {
+		t.Error("decision kept after revoke")
+	}
+	if found, _ := RevokeWorkspaceTrust(repo); found {
+		t.Error("revoked a decision twice")
+	}
+}
@@ -0,0 +1,135 @@
+// Package daemon schedules the background work 'mur daemon run' does:
+// pattern sync, consolidation, and learning-repo sync, each on its own
+// interval, with the time of each job's last run kept across restarts.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Jobs the daemon runs.
+const (
+	JobSync         = "sync"          // mur sync
+	JobConsolidate  = "consolidate"   // mur consolidate --auto
+	JobLearningRepo = "learning-repo" // mur learn repo-sync
+)
+
+// DefaultSyncInterval is used when sync.interval_minutes is unset.
+const DefaultSyncInterval = 30 * time.Minute
+
+// Job is a mur command the daemon runs every so often.
+type Job struct {
+	Name  string
+	Every time.Duration
+	Args  []string // arguments to mur
+}
+
+// Jobs returns the jobs the config enables: sync while sync.auto is on,
+// consolidation on consolidation.schedule while it is enabled, and, when
+// a learning repo is set up, a repo push and pull at the sync interval.
+func Jobs(cfg *config.Config, learningRepo bool) []Job {
+	every := DefaultSyncInterval
+	if cfg.Sync.IntervalMinutes > 0 {
+		every = time.Duration(cfg.Sync.IntervalMinutes) * time.Minute
+	}
+
+	var jobs []Job
+	if cfg.Sync.Auto {
+		jobs = append(jobs, Job{Name: JobSync, Every: every, Args: []string{"sync", "--quiet"}})
+	}
+	if cfg.Consolidation.Enabled {
+		jobs = append(jobs, Job{Name: JobConsolidate, Every: cfg.Consolidation.Period(), Args: []string{"consolidate", "--auto"}})
+	}
+	if learningRepo {
+		jobs = append(jobs, Job{Name: JobLearningRepo, Every: every, Args: []string{"learn", "repo-sync"}})
+	}
+	return jobs
+}
+
+// State is when each job last ran.
+type State struct {
+	LastRun map[string]time.Time `json:"last_run"`
+}
+
+// StatePath returns where the daemon keeps its state.
+func StatePath(home string) string {
+	return filepath.Join(config.StateDir(home), "daemon-state.json")
+}
+
+// LogPath returns the daemon log.
+func LogPath(home string) string {
+	return filepath.Join(config.StateDir(home), "logs", "daemon.log")
+}
+
+// LoadState reads the state at path; a missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{LastRun: make(map[string]time.Time)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid daemon state %s: %w", path, err)
+	}
+	if s.LastRun == nil {
+		s.LastRun = make(map[string]time.Time)
+	}
+	return s, nil
+}
+
+// Save writes the state to path.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Due returns the jobs that never ran or last ran at least their
+// interval before now, the longest overdue first.
+func (s *State) Due(jobs []Job, now time.Time) []Job {
+	var due []Job
+	for _, j := range jobs {
+		if last, ok := s.LastRun[j.Name]; !ok || now.Sub(last) >= j.Every {
+			due = append(due, j)
+		}
+	}
+	sort.SliceStable(due, func(a, b int) bool {
+		return s.LastRun[due[a].Name].Before(s.LastRun[due[b].Name])
+	})
+	return due
+}
+
+// Ran records that a job ran at t.
+func (s *State) Ran(name string, t time.Time) {
+	s.LastRun[name] = t.UTC()
+}
+
+// NewLogger opens the log at path for appending and returns a logger
+// writing JSON lines to it.
+func NewLogger(path string) (*slog.Logger, io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open daemon log: %w", err)
+	}
+	return slog.New(slog.NewJSONHandler(f, nil)), f, nil
+}
@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func jobNames(jobs []Job) []string {
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.Name)
+	}
+	return names
+}
+
+func TestJobs(t *testing.T) {
+	cfg := &config.Config{}
+	if jobs := Jobs(cfg, false); len(jobs) != 0 {
+		t.Fatalf("nothing enabled, got %v", jobNames(jobs))
+	}
+
+	cfg.Sync.Auto = true
+	cfg.Sync.IntervalMinutes = 15
+	cfg.Consolidation.Enabled = true
+	cfg.Consolidation.Schedule = "daily"
+	jobs := Jobs(cfg, true)
+	if len(jobs) != 3 {
+		t.Fatalf("jobs = %v", jobNames(jobs))
+	}
+	want := map[string]time.Duration{JobSync: 15 * time.Minute, JobConsolidate: 24 * time.Hour, JobLearningRepo: 15 * time.Minute}
+	for _, j := range jobs {
+		if j.Every != want[j.Name] {
+			t.Errorf("%s every %s, want %s", j.Name, j.Every, want[j.Name])
+		}
+	}
+
+	cfg.Sync.IntervalMinutes = 0
+	if jobs := Jobs(cfg, false); jobs[0].Every != DefaultSyncInterval {
+		t.Errorf("default sync interval = %s", jobs[0].Every)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	jobs := []Job{
+		{Name: "a", Every: time.Hour},
+		{Name: "b", Every: time.Hour},
+		{Name: "c", Every: time.Hour},
+	}
+	s := &State{LastRun: map[string]time.Time{
+		"a": now.Add(-30 * time.Minute), // not yet
+		"b": now.Add(-2 * time.Hour),
+	}}
+	due := s.Due(jobs, now)
+	// c never ran, so it is the most overdue
+	if got := jobNames(due); len(got) != 2 || got[0] != "c" || got[1] != "b" {
+		t.Errorf("Due = %v, want [c b]", got)
+	}
+
+	s.Ran("b", now)
+	s.Ran("c", now)
+	if due := s.Due(jobs, now.Add(time.Minute)); len(due) != 0 {
+		t.Errorf("Due after running = %v", jobNames(due))
+	}
+}
+
+func TestStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "daemon-state.json")
+	s, err := LoadState(path)
+	if err != nil || len(s.LastRun) != 0 {
+		t.Fatalf("LoadState(missing) = %+v, %v", s, err)
+	}
+	at := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Ran(JobSync, at)
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err = LoadState(path)
+	if err != nil || !s.LastRun[JobSync].Equal(at) {
+		t.Errorf("reloaded = %+v, %v", s, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadState(path); err == nil {
+		t.Error("corrupt state loaded without error")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "daemon.log")
+	logger, closer, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("job ran", "job", JobSync, "duration_ms", 12)
+	closer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("log line isn't JSON: %s", data)
+	}
+	if line["msg"] != "job ran" || line["job"] != JobSync {
+		t.Errorf("log line = %v", line)
+	}
+}
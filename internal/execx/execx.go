@@ -0,0 +1,275 @@
+// Package execx runs external programs for mur.
+//
+// Programs mur runs on its own behalf (git, gh, the browser opener, ...)
+// must be on an allowlist and run without a shell, so a pattern name, repo
+// URL, or branch can never be interpreted as shell syntax. Command lines the
+// user authored, like workflow steps, are exempt from the allowlist but
+// still skip the shell when they don't use shell syntax.
+//
+// git and gh reach remotes on their own, so under a restricted
+// network.mode their remotes are checked against the policy first.
+//
+// Every run returns a Result with the captured output and exit code, so
+// callers can report failures precisely and tests can inspect them.
+package execx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrNotAllowed means a program isn't on the allowlist.
+var ErrNotAllowed = errors.New("program not allowed")
+
+// ErrUnsafeArg means an argument could be mistaken for an option or
+// carries control characters.
+var ErrUnsafeArg = errors.New("unsafe argument")
+
+// allowed lists the programs mur runs itself.
+var allowed = map[string]bool{
+	"git":               true,
+	"gh":                true,
+	"brew":              true,
+	"go":                true,
+	"launchctl":         true,
+	"systemctl":         true,
+	"schtasks":          true,
+	"open":              true,
+	"xdg-open":          true,
+	"rundll32":          true,
+	"osascript":         true,
+	"terminal-notifier": true,
+	"alerter":           true,
+	"defaults":          true,
+	"pbcopy":            true,
+	"xclip":             true,
+	"xsel":              true,
+	"clip":              true,
+	"tmux":              true,
+	"sysctl":            true,
+	"scutil":            true,
+	"pmset":             true,
+	"busctl":            true,
+}
+
+// Allowed reports whether mur may run the program name.
+func Allowed(name string) bool {
+	return allowed[name]
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	Program  string        `json:"program"`
+	Args     []string      `json:"args"`
+	Shell    bool          `json:"shell,omitempty"` // ran with sh -c
+	Stdout   string        `json:"stdout"`
+	Stderr   string        `json:"stderr"`
+	ExitCode int           `json:"exit_code"` // -1 if it didn't start or was killed
+	Duration time.Duration `json:"duration"`
+}
+
+// Output returns stdout and stderr together.
+func (r *Result) Output() string {
+	return r.Stdout + r.Stderr
+}
+
+// ExitError is returned when a program ran but exited non-zero.
+type ExitError struct {
+	Result *Result
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s exited with status %d", e.Result.Program, e.Result.ExitCode)
+	if line := firstLine(e.Result.Stderr); line != "" {
+		msg += ": " + line
+	}
+	return msg
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// Cmd describes one program run.
+type Cmd struct {
+	Name  string
+	Args  []string
+	Dir   string
+	Env   []string // added to the current environment
+	Stdin io.Reader
+
+	// Stdout and Stderr, if set, also receive output as it's written,
+	// e.g. os.Stdout for interactive commands. It is captured either way.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	Timeout time.Duration // 0 means no timeout beyond ctx
+
+	user  bool // user-authored: not subject to the allowlist
+	shell bool // needs sh -c
+}
+
+// Command returns a Cmd for a program mur runs itself.
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{Name: name, Args: args}
+}
+
+// CommandLine returns a Cmd for a command line the user wrote, such as a
+// workflow step. It runs directly when it uses no shell syntax (pipes,
+// redirects, variables, globs, ...), and with sh -c otherwise.
+func CommandLine(line string) *Cmd {
+	if argv, ok := Split(line); ok {
+		return &Cmd{Name: argv[0], Args: argv[1:], user: true}
+	}
+	return &Cmd{Name: "sh", Args: []string{"-c", line}, user: true, shell: true}
+}
+
+// Run runs the command and waits for it. The error is ErrNotAllowed,
+// ErrUnsafeArg, or for git and gh a netpolicy.ErrBlocked error before
+// anything runs, an *ExitError for a non-zero exit, or the error that
+// kept the program from running. The Result is always set.
+func (c *Cmd) Run(ctx context.Context) (*Result, error) {
+	res := &Result{Program: c.Name, Args: c.Args, Shell: c.shell, ExitCode: -1}
+
+	if !c.user && !Allowed(c.Name) {
+		return res, fmt.Errorf("%w: %s", ErrNotAllowed, c.Name)
+	}
+	for _, a := range c.Args {
+		if strings.ContainsRune(a, 0) {
+			return res, fmt.Errorf("%w: NUL byte in argument to %s", ErrUnsafeArg, c.Name)
+		}
+	}
+	if err := c.checkNetwork(ctx); err != nil {
+		return res, err
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = tee(&stdout, c.Stdout)
+	cmd.Stderr = tee(&stderr, c.Stderr)
+
+	start := time.Now()
+	err := cmd.Run()
+	res.Duration = time.Since(start)
+	res.Stdout = stdout.String()
+	res.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		res.ExitCode = 0
+		return res, nil
+	case ctx.Err() != nil:
+		return res, fmt.Errorf("%s: %w", c.Name, ctx.Err())
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+		return res, &ExitError{Result: res}
+	default:
+		return res, fmt.Errorf("cannot run %s: %w", c.Name, err)
+	}
+}
+
+func tee(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(buf, w)
+}
+
+// Run runs a program mur runs itself, capturing its output.
+func Run(ctx context.Context, name string, args ...string) (*Result, error) {
+	return Command(name, args...).Run(ctx)
+}
+
+// CheckArg returns ErrUnsafeArg if s, a value from the user or a remote
+// (a repo URL, a branch), can't safely be passed as a positional argument:
+// it is empty, starts with "-" and so would be read as an option, or
+// contains control characters.
+func CheckArg(s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: empty value", ErrUnsafeArg)
+	}
+	if strings.HasPrefix(s, "-") {
+		return fmt.Errorf("%w: %q starts with '-'", ErrUnsafeArg, s)
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("%w: %q contains control characters", ErrUnsafeArg, s)
+		}
+	}
+	return nil
+}
+
+// shellSyntax are characters that need a shell to mean what the user
+// intended.
+const shellSyntax = "|&;<>()$`\\*?[]{}~#!\n"
+
+// Split splits a command line into a program and its arguments when it
+// uses nothing but words and simple quoting. ok is false when the line
+// needs a shell: pipes, redirects, variables, globs, escapes, leading
+// VAR=value assignments, or unbalanced quotes.
+func Split(line string) (argv []string, ok bool) {
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			if quote == '"' && strings.ContainsRune("$`\\!", r) {
+				return nil, false
+			}
+			cur.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				argv = append(argv, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune(shellSyntax, r):
+			return nil, false
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, false
+	}
+	if inWord {
+		argv = append(argv, cur.String())
+	}
+	if len(argv) == 0 || strings.Contains(argv[0], "=") {
+		return nil, false
+	}
+	return argv, true
+}
@@ -0,0 +1,94 @@
+package execx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string // nil means a shell is needed
+	}{
+		{"go test ./...", []string{"go", "test", "./..."}},
+		{`git commit -m "fix the build"`, []string{"git", "commit", "-m", "fix the build"}},
+		{`echo 'a  b' ""`, []string{"echo", "a  b", ""}},
+		{"  make   build  ", []string{"make", "build"}},
+		{"go test ./... | tee out.log", nil},
+		{"make && make install", nil},
+		{"echo $HOME", nil},
+		{`echo "$HOME"`, nil},
+		{"rm *.tmp", nil},
+		{"GOOS=linux go build", nil},
+		{"echo 'unterminated", nil},
+		{`echo a\ b`, nil},
+		{"cat < in.txt", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, ok := Split(tt.line)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("Split(%q) = %q, want shell", tt.line, got)
+			}
+			continue
+		}
+		if !ok || strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("Split(%q) = %q, %v; want %q", tt.line, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCheckArg(t *testing.T) {
+	for _, ok := range []string{"git@github.com:me/patterns.git", "https://example.com/r.git", "main"} {
+		if err := CheckArg(ok); err != nil {
+			t.Errorf("CheckArg(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "--upload-pack=touch /tmp/x", "-b", "main\nrm -rf /", "a\x00b"} {
+		if err := CheckArg(bad); !errors.Is(err, ErrUnsafeArg) {
+			t.Errorf("CheckArg(%q) = %v, want ErrUnsafeArg", bad, err)
+		}
+	}
+}
+
+func TestRunAllowlist(t *testing.T) {
+	res, err := Run(context.Background(), "sh", "-c", "echo hi")
+	if !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("err = %v, want ErrNotAllowed", err)
+	}
+	if res.ExitCode != -1 || res.Stdout != "" {
+		t.Errorf("disallowed program ran: %+v", res)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	ctx := context.Background()
+
+	c := CommandLine(`printf '%s-%s' one "two"`)
+	if c.shell || c.Name != "printf" {
+		t.Fatalf("simple line uses the shell: %+v", c)
+	}
+	res, err := c.Run(ctx)
+	if err != nil || res.Stdout != "one-two" || res.ExitCode != 0 {
+		t.Errorf("Run = %+v, %v", res, err)
+	}
+
+	res, err = CommandLine("echo out; echo err >&2; exit 3").Run(ctx)
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("err = %v, want *ExitError", err)
+	}
+	if !res.Shell || res.ExitCode != 3 || res.Stdout != "out\n" || res.Stderr != "err\n" {
+		t.Errorf("Run = %+v", res)
+	}
+	if !strings.Contains(err.Error(), "status 3: err") {
+		t.Errorf("error = %q", err)
+	}
+
+	if _, err := CommandLine("no-such-program-mur-test").Run(ctx); err == nil || errors.As(err, &exitErr) {
+		t.Errorf("missing program err = %v", err)
+	}
+}
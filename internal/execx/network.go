@@ -0,0 +1,329 @@
+package execx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/netpolicy"
+)
+
+// git and gh connect to remotes themselves, past the HTTP transport
+// netpolicy guards. Under a restricted network.mode, Run checks the
+// remotes a git or gh command will reach against the policy first and
+// refuses it with an error wrapping netpolicy.ErrBlocked.
+
+// gitValueOpts are git's global options that take a separate value.
+var gitValueOpts = map[string]bool{
+	"-C": true, "-c": true, "--git-dir": true, "--work-tree": true,
+	"--namespace": true, "--super-prefix": true, "--config-env": true,
+}
+
+// gitSubValueOpts are options of the network subcommands that take a
+// separate value, so it isn't mistaken for the remote.
+var gitSubValueOpts = map[string]bool{
+	"-b": true, "--branch": true, "-o": true, "--origin": true, "-u": true,
+	"--upload-pack": true, "--receive-pack": true, "--exec": true,
+	"--reference": true, "--reference-if-able": true, "--separate-git-dir": true,
+	"--depth": true, "--deepen": true, "--shallow-since": true, "--shallow-exclude": true,
+	"-j": true, "--jobs": true, "--filter": true, "--template": true, "--config": true,
+	"--server-option": true, "--bundle-uri": true, "--negotiation-tip": true,
+	"--refmap": true, "-s": true, "--strategy": true, "-X": true, "--strategy-option": true,
+	"--push-option": true, "--repo": true,
+}
+
+// checkNetwork returns an error wrapping netpolicy.ErrBlocked if c is a git
+// or gh command that would reach a remote the policy doesn't allow.
+func (c *Cmd) checkNetwork(ctx context.Context) error {
+	if c.user || (c.Name != "git" && c.Name != "gh") {
+		return nil
+	}
+	p, err := netpolicy.For(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	if p.Mode == netpolicy.ModeNormal || p.Mode == "" {
+		return nil
+	}
+	if c.Name == "gh" {
+		return c.checkGH(ctx, p)
+	}
+	return c.checkGit(ctx, p)
+}
+
+func (c *Cmd) checkGit(ctx context.Context, p netpolicy.Policy) error {
+	// Global options, which the lookups of remote URLs get too
+	var globals []string
+	dir := c.Dir
+	i := 0
+	for ; i < len(c.Args) && strings.HasPrefix(c.Args[i], "-"); i++ {
+		a := c.Args[i]
+		globals = append(globals, a)
+		if gitValueOpts[a] && i+1 < len(c.Args) {
+			i++
+			globals = append(globals, c.Args[i])
+			if a == "-C" {
+				dir = joinDir(dir, c.Args[i])
+			}
+		}
+	}
+	if i == len(c.Args) {
+		return nil
+	}
+	sub, args := c.Args[i], c.Args[i+1:]
+	opts, positional := splitArgs(args)
+
+	var remotes []string
+	push := false
+	switch sub {
+	case "clone":
+		if opts["--recurse-submodules"] || opts["--recursive"] {
+			return fmt.Errorf("git clone: %w: submodule URLs can't be checked (network.mode: %s)", netpolicy.ErrBlocked, p.Mode)
+		}
+		if len(positional) > 0 {
+			remotes = positional[:1]
+		}
+	case "fetch", "pull", "push", "ls-remote":
+		if sub == "ls-remote" && opts["--get-url"] {
+			return nil
+		}
+		push = sub == "push"
+		switch {
+		case opts["--all"] || len(positional) == 0:
+			names, err := c.gitLines(ctx, dir, globals, "remote")
+			if err != nil {
+				return err
+			}
+			remotes = names
+		case opts["--multiple"]:
+			remotes = positional
+		default:
+			remotes = positional[:1]
+		}
+	case "remote":
+		if len(positional) == 0 {
+			return nil
+		}
+		switch positional[0] {
+		case "update":
+			names, err := c.gitLines(ctx, dir, globals, "remote")
+			if err != nil {
+				return err
+			}
+			remotes = names
+		case "show", "prune", "set-head":
+			if opts["-n"] {
+				return nil
+			}
+			remotes = positional[1:]
+		}
+	case "submodule":
+		if len(positional) > 0 && positional[0] == "update" && !opts["--no-fetch"] && !opts["-N"] {
+			return fmt.Errorf("git submodule: %w: submodule URLs can't be checked (network.mode: %s)", netpolicy.ErrBlocked, p.Mode)
+		}
+	case "archive":
+		for _, a := range args {
+			if remote, ok := strings.CutPrefix(a, "--remote="); ok {
+				remotes = append(remotes, remote)
+			}
+		}
+	}
+
+	for _, remote := range remotes {
+		urls, err := c.remoteURLs(ctx, dir, globals, remote, push)
+		if err != nil {
+			return err
+		}
+		for _, u := range urls {
+			if err := checkRemote(ctx, p, u); err != nil {
+				return fmt.Errorf("git %s: %w", sub, err)
+			}
+		}
+	}
+	return nil
+}
+
+// splitArgs returns the options of a subcommand's arguments, without
+// their values, and its positional arguments.
+func splitArgs(args []string) (opts map[string]bool, positional []string) {
+	opts = make(map[string]bool)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			return opts, append(positional, args[i+1:]...)
+		case strings.HasPrefix(a, "-"):
+			name, _, hasValue := strings.Cut(a, "=")
+			opts[name] = true
+			if !hasValue && gitSubValueOpts[name] {
+				i++
+			}
+		default:
+			positional = append(positional, a)
+		}
+	}
+	return opts, positional
+}
+
+// remoteURLs returns the URLs git uses for remote, a configured remote or
+// a URL, with url.<base>.insteadOf applied.
+func (c *Cmd) remoteURLs(ctx context.Context, dir string, globals []string, remote string, push bool) ([]string, error) {
+	if push {
+		if urls, err := c.gitLines(ctx, dir, globals, "remote", "get-url", "--push", "--all", remote); err == nil && len(urls) > 0 {
+			return urls, nil
+		}
+	}
+	return c.gitLines(ctx, dir, globals, "ls-remote", "--get-url", remote)
+}
+
+// gitLines runs a local git command, without the checks of Run, and
+// returns its output lines.
+func (c *Cmd) gitLines(ctx context.Context, dir string, globals []string, args ...string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", append(append([]string{}, globals...), args...)...)
+	cmd.Dir = dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up git remotes: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+func joinDir(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// CheckRemote returns an error wrapping netpolicy.ErrBlocked if the
+// policy for ctx doesn't allow git to reach remote, a git URL. Local
+// paths are always allowed.
+func CheckRemote(ctx context.Context, remote string) error {
+	p, err := netpolicy.For(ctx)
+	if err != nil {
+		return err
+	}
+	if p.Mode == netpolicy.ModeNormal || p.Mode == "" {
+		return nil
+	}
+	return checkRemote(ctx, p, remote)
+}
+
+func checkRemote(ctx context.Context, p netpolicy.Policy, remote string) error {
+	u, err := remoteURL(remote)
+	if err != nil {
+		return fmt.Errorf("%w: %v (network.mode: %s)", netpolicy.ErrBlocked, err, p.Mode)
+	}
+	if u == nil {
+		return nil
+	}
+	return p.Check(ctx, u)
+}
+
+// remoteURL returns where the git URL remote connects to, or nil for a
+// local repository.
+func remoteURL(remote string) (*url.URL, error) {
+	if i := strings.Index(remote, "::"); i > 0 && !strings.ContainsAny(remote[:i], "/:[") {
+		return nil, fmt.Errorf("remote helper %q can't be checked", remote[:i])
+	}
+	if scheme, _, ok := strings.Cut(remote, "://"); ok {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(scheme) {
+		case "file":
+			return nil, nil
+		case "http", "https", "git":
+			return u, nil
+		case "ssh", "git+ssh", "ssh+git":
+			u.Scheme = "ssh"
+			return u, nil
+		}
+		return nil, fmt.Errorf("unknown git transport %q", scheme)
+	}
+
+	// scp-like [user@]host:path, where no slash comes before the colon;
+	// a drive letter is a local Windows path
+	colon := strings.Index(remote, ":")
+	if colon <= 1 || strings.Contains(remote[:colon], "/") {
+		return nil, nil
+	}
+	host := remote[:colon]
+	if open := strings.Index(remote, "["); open >= 0 && open < colon {
+		// [user@host:port]:path or user@[host]:path
+		end := strings.Index(remote, "]:")
+		if end < open {
+			return nil, fmt.Errorf("invalid git URL %q", remote)
+		}
+		host = remote[open+1 : end]
+	}
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return &url.URL{Scheme: "ssh", Host: host}, nil
+}
+
+func (c *Cmd) checkGH(ctx context.Context, p netpolicy.Policy) error {
+	if len(c.Args) == 0 {
+		return nil
+	}
+	switch c.Args[0] {
+	case "--version", "version", "help", "--help", "completion":
+		return nil
+	}
+	host := ghHost(c.Env)
+	for i, a := range c.Args {
+		var value string
+		switch {
+		case (a == "-R" || a == "--repo" || a == "--hostname" || a == "-h") && i+1 < len(c.Args):
+			value = c.Args[i+1]
+		case strings.HasPrefix(a, "--repo=") || strings.HasPrefix(a, "--hostname="):
+			_, value, _ = strings.Cut(a, "=")
+		default:
+			continue
+		}
+		if a == "--hostname" || a == "-h" || strings.HasPrefix(a, "--hostname=") {
+			host = value
+		} else if parts := strings.Split(value, "/"); len(parts) == 3 {
+			host = parts[0]
+		}
+	}
+	hosts := []string{host}
+	if strings.EqualFold(host, "github.com") {
+		hosts = append(hosts, "api.github.com")
+	}
+	for _, h := range hosts {
+		if err := p.Check(ctx, &url.URL{Scheme: "https", Host: h}); err != nil {
+			return fmt.Errorf("gh: %w", err)
+		}
+	}
+	return nil
+}
+
+// ghHost returns the GitHub host gh talks to by default.
+func ghHost(env []string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		if h, ok := strings.CutPrefix(env[i], "GH_HOST="); ok && h != "" {
+			return h
+		}
+	}
+	if h := os.Getenv("GH_HOST"); h != "" {
+		return h
+	}
+	return "github.com"
+}
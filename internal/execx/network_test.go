@@ -0,0 +1,97 @@
+package execx
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/netpolicy"
+)
+
+func TestRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		host   string // "" for a local repository
+		bad    bool
+	}{
+		{"https://github.com/me/patterns.git", "github.com", false},
+		{"ssh://git@gitlab.example.com:2222/me/p.git", "gitlab.example.com", false},
+		{"git@github.com:me/patterns.git", "github.com", false},
+		{"[git@10.0.0.5:22]:me/p.git", "10.0.0.5", false},
+		{"git@[::1]:me/p.git", "::1", false},
+		{"/srv/git/patterns.git", "", false},
+		{"../patterns", "", false},
+		{"file:///srv/git/patterns.git", "", false},
+		{`C:\repos\patterns`, "", false},
+		{"ext::ssh -o ProxyCommand=x host", "", true},
+		{"ftp://example.com/p.git", "", true},
+	}
+	for _, tt := range tests {
+		u, err := remoteURL(tt.remote)
+		if (err != nil) != tt.bad {
+			t.Errorf("remoteURL(%q) error = %v, want error %v", tt.remote, err, tt.bad)
+			continue
+		}
+		host := ""
+		if u != nil {
+			host = u.Hostname()
+		}
+		if host != tt.host {
+			t.Errorf("remoteURL(%q) host = %q, want %q", tt.remote, host, tt.host)
+		}
+	}
+}
+
+func TestRunNetworkPolicy(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "https://github.com/me/patterns.git"},
+		{"remote", "add", "mirror", "git@git.internal.example:me/patterns.git"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	offline := netpolicy.WithPolicy(context.Background(), netpolicy.Policy{Mode: netpolicy.ModeOffline})
+	allowlist := netpolicy.WithPolicy(context.Background(), netpolicy.Policy{Mode: netpolicy.ModeAllowlist, Allow: []string{"github.com"}})
+	tests := []struct {
+		ctx     context.Context
+		name    string
+		args    []string
+		blocked bool
+	}{
+		{offline, "git", []string{"-C", repo, "status"}, false},
+		{offline, "git", []string{"-C", repo, "ls-remote", "--get-url", "origin"}, false},
+		{offline, "git", []string{"-C", repo, "fetch", "origin"}, true},
+		{offline, "git", []string{"clone", "--depth", "1", "https://github.com/me/patterns.git", "x"}, true},
+		{offline, "git", []string{"clone", repo, "x"}, false},
+		{offline, "gh", []string{"pr", "list"}, true},
+		{offline, "gh", []string{"--version"}, false},
+		{allowlist, "git", []string{"-C", repo, "push", "origin", "main"}, false},
+		{allowlist, "git", []string{"-C", repo, "push", "mirror", "main"}, true},
+		{allowlist, "git", []string{"-C", repo, "pull"}, true}, // every remote is checked
+		{allowlist, "git", []string{"clone", "https://github.com/me/p", "x"}, false},
+		{allowlist, "git", []string{"-c", "url.git@git.internal.example:.insteadOf=https://github.com/", "clone", "https://github.com/me/p", "x"}, true},
+		{allowlist, "gh", []string{"pr", "create", "--title", "t"}, true}, // api.github.com isn't allowed
+		{netpolicy.WithPolicy(context.Background(), netpolicy.Policy{Mode: netpolicy.ModeAllowlist, Allow: []string{"github.com", "api.github.com"}}), "gh", []string{"pr", "list"}, false},
+		{allowlist, "gh", []string{"pr", "list", "-R", "ghe.example.com/me/p"}, true},
+	}
+	for _, tt := range tests {
+		err := Command(tt.name, tt.args...).checkNetwork(tt.ctx)
+		if blocked := errors.Is(err, netpolicy.ErrBlocked); blocked != tt.blocked {
+			t.Errorf("%s %v: err = %v, want blocked %v", tt.name, tt.args, err, tt.blocked)
+		}
+	}
+
+	// Run refuses before starting anything
+	res, err := Command("git", "-C", repo, "fetch", "mirror").Run(offline)
+	if !errors.Is(err, netpolicy.ErrBlocked) || res.ExitCode != -1 {
+		t.Errorf("Run(git fetch) = %+v, %v; want blocked", res, err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package heartbeat records liveness of mur background processes (scheduled
+// sync, dashboard server) and evaluates their health for supervisors.
+package heartbeat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Processes that write heartbeats.
+const (
+	ProcessSync   = "sync"   // scheduled auto-sync (mur sync)
+	ProcessServe  = "serve"  // dashboard server (mur serve)
+	ProcessDaemon = "daemon" // background scheduler (mur daemon run)
+)
+
+// Beat statuses.
+const (
+	StatusOK      = "ok"
+	StatusError   = "error"
+	StatusStopped = "stopped"
+)
+
+// Health states.
+const (
+	StateHealthy   = "healthy"
+	StateUnhealthy = "unhealthy"
+	StateStopped   = "stopped"
+	StateUnknown   = "unknown"
+)
+
+// Exit codes used by health checks and supervised processes. Supervisors
+// should restart on any non-zero code except ExitConfig.
+const (
+	ExitHealthy   = 0
+	ExitUnhealthy = 1  // failing, stale, or stopped
+	ExitUnknown   = 3  // no heartbeat recorded
+	ExitConfig    = 78 // EX_CONFIG: misconfigured, restarting will not help
+)
+
+// staleGrace is added to twice the expected interval before a beat is stale.
+const staleGrace = time.Minute
+
+// Beat is the last recorded sign of life of a process.
+type Beat struct {
+	Process  string    `json:"process"`
+	PID      int       `json:"pid"`
+	Time     time.Time `json:"time"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Addr     string    `json:"addr,omitempty"`
+	Interval int       `json:"interval_seconds,omitempty"` // expected seconds between beats; 0 = no staleness check
+}
+
+// Health is the evaluated state of a process.
+type Health struct {
+	Process string `json:"process"`
+	State   string `json:"state"`
+	Reason  string `json:"reason,omitempty"`
+	Beat    *Beat  `json:"beat,omitempty"`
+}
+
+// ExitCode maps the health state to a supervisor-friendly exit code.
+func (h Health) ExitCode() int {
+	switch h.State {
+	case StateHealthy:
+		return ExitHealthy
+	case StateUnknown:
+		return ExitUnknown
+	default:
+		return ExitUnhealthy
+	}
+}
+
+// Dir returns the heartbeat directory (~/.mur/heartbeat).
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "heartbeat"), nil
+}
+
+func beatPath(process string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, process+".json"), nil
+}
+
+// Write records a beat, filling in the time and PID when unset.
+func Write(b Beat) error {
+	if b.Time.IsZero() {
+		b.Time = time.Now()
+	}
+	if b.PID == 0 {
+		b.PID = os.Getpid()
+	}
+	if b.Status == "" {
+		b.Status = StatusOK
+	}
+
+	path, err := beatPath(b.Process)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create heartbeat dir: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so readers never see a partial beat
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write heartbeat: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read returns the last beat of a process, or nil if none was recorded.
+func Read(process string) (*Beat, error) {
+	path, err := beatPath(process)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var b Beat
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse heartbeat %s: %w", process, err)
+	}
+	return &b, nil
+}
+
+// Check evaluates the health of a process from its last beat.
+func Check(process string, now time.Time) Health {
+	h := Health{Process: process}
+
+	b, err := Read(process)
+	if err != nil {
+		h.State = StateUnhealthy
+		h.Reason = err.Error()
+		return h
+	}
+	if b == nil {
+		h.State = StateUnknown
+		h.Reason = "no heartbeat recorded"
+		return h
+	}
+	h.Beat = b
+
+	age := now.Sub(b.Time)
+	switch {
+	case b.Status == StatusStopped:
+		h.State = StateStopped
+		h.Reason = fmt.Sprintf("stopped %s ago", formatAge(age))
+	case b.Status == StatusError:
+		h.State = StateUnhealthy
+		h.Reason = "last run failed: " + b.Error
+	case b.Interval > 0 && age > 2*time.Duration(b.Interval)*time.Second+staleGrace:
+		h.State = StateUnhealthy
+		h.Reason = fmt.Sprintf("stale: last beat %s ago, expected every %s", formatAge(age), time.Duration(b.Interval)*time.Second)
+	default:
+		h.State = StateHealthy
+		h.Reason = fmt.Sprintf("last beat %s ago", formatAge(age))
+	}
+	return h
+}
+
+// CheckAll evaluates every known process.
+func CheckAll(now time.Time) []Health {
+	return []Health{Check(ProcessSync, now), Check(ProcessServe, now), Check(ProcessDaemon, now)}
+}
+
+// Overall returns the exit code for a set of health results. Processes that
+// never ran or were stopped on purpose are ignored; if nothing is running at
+// all the result is ExitUnknown.
+func Overall(results []Health) int {
+	code := ExitUnknown
+	for _, h := range results {
+		switch h.State {
+		case StateUnknown, StateStopped:
+			continue
+		case StateHealthy:
+			if code == ExitUnknown {
+				code = ExitHealthy
+			}
+		default:
+			return ExitUnhealthy
+		}
+	}
+	return code
+}
+
+func formatAge(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
@@ -0,0 +1,66 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+	now := time.Now()
+
+	if h := Check(ProcessSync, now); h.State != StateUnknown || h.ExitCode() != ExitUnknown {
+		t.Errorf("missing beat = %s (%d), want unknown", h.State, h.ExitCode())
+	}
+
+	tests := []struct {
+		name string
+		beat Beat
+		want string
+	}{
+		{"fresh", Beat{Time: now.Add(-10 * time.Minute), Interval: 1800}, StateHealthy},
+		{"no interval", Beat{Time: now.Add(-72 * time.Hour)}, StateHealthy},
+		{"stale", Beat{Time: now.Add(-2 * time.Hour), Interval: 1800}, StateUnhealthy},
+		{"failed", Beat{Time: now, Status: StatusError, Error: "boom"}, StateUnhealthy},
+		{"stopped", Beat{Time: now, Status: StatusStopped}, StateStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beat.Process = ProcessSync
+			if err := Write(tt.beat); err != nil {
+				t.Fatal(err)
+			}
+			h := Check(ProcessSync, now)
+			if h.State != tt.want {
+				t.Errorf("state = %s (%s), want %s", h.State, h.Reason, tt.want)
+			}
+			if h.Beat == nil || h.Beat.PID == 0 {
+				t.Error("beat should record the writer PID")
+			}
+		})
+	}
+}
+
+func TestOverall(t *testing.T) {
+	healthy := Health{State: StateHealthy}
+	unhealthy := Health{State: StateUnhealthy}
+	unknown := Health{State: StateUnknown}
+	stopped := Health{State: StateStopped}
+
+	tests := []struct {
+		results []Health
+		want    int
+	}{
+		{[]Health{unknown, unknown}, ExitUnknown},
+		{[]Health{healthy, unknown}, ExitHealthy},
+		{[]Health{healthy, stopped}, ExitHealthy},
+		{[]Health{healthy, unhealthy}, ExitUnhealthy},
+		{[]Health{stopped, unknown}, ExitUnknown},
+	}
+	for i, tt := range tests {
+		if got := Overall(tt.results); got != tt.want {
+			t.Errorf("case %d: Overall = %d, want %d", i, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package hooks
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 )
@@ -34,7 +35,7 @@ func InstallAuggieHooks() error {
 		return fmt.Errorf("auggie not configured (~/.augment not found)")
 	}
 
-	murDir := filepath.Join(home, ".mur")
+	murDir := config.DataDir(home)
 	promptScriptPath := filepath.Join(murDir, "hooks", "on-prompt.sh")
 	stopScriptPath := filepath.Join(murDir, "hooks", "on-stop.sh")
 
@@ -0,0 +1,242 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// MinimalPath is the PATH a hook may get when the AI tool was started
+// from the Dock or a desktop launcher rather than a login shell, where
+// nothing from ~/.zshrc or ~/.bashrc (Homebrew, ~/go/bin) has been added.
+const MinimalPath = "/usr/bin:/bin:/usr/sbin:/sbin"
+
+// MurBinary returns the path hooks should run mur by: the running binary,
+// by its PATH entry or Homebrew's bin link when those lead to it, so the
+// path survives 'brew upgrade' moving the Cellar directory.
+func MurBinary() string {
+	path, _ := findMurBinary()
+	return path
+}
+
+// stableBinary returns the path to run exe by, if exe is a mur binary.
+func stableBinary(exe string) (string, bool) {
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err != nil || strings.TrimSuffix(filepath.Base(resolved), ".exe") != "mur" {
+		return "", false
+	}
+	if path, err := exec.LookPath("mur"); err == nil && sameBinary(path, resolved) {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs, true
+		}
+	}
+	if i := strings.Index(resolved, "/Cellar/"); i > 0 {
+		link := filepath.Join(resolved[:i], "bin", "mur")
+		if sameBinary(link, resolved) {
+			return link, true
+		}
+	}
+	return resolved, true
+}
+
+func sameBinary(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	return err == nil && os.SameFile(ia, ib)
+}
+
+// OnMinimalPath reports whether a hook run with MinimalPath finds mur
+// without an absolute path.
+func OnMinimalPath() bool {
+	for _, dir := range filepath.SplitList(MinimalPath) {
+		if info, err := os.Stat(filepath.Join(dir, "mur")); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BinaryRef is a command in an installed hook that runs mur.
+type BinaryRef struct {
+	File    string // hook script or tool config
+	Path    string // mur binary it runs; "mur" when looked up on PATH
+	Problem string // why the hook may fail to run it; empty when fine
+}
+
+// absMurRe matches an absolute path to a mur binary, ending where a
+// shell word or JSON string would.
+var absMurRe = regexp.MustCompile(`/[^\s"'` + "`" + `;|&()<>=]*/mur(?:\.exe)?(?:[\s"'` + "`" + `;|&()<>]|$)`)
+
+// bareMurRe matches mur run by name, as hooks fall back to when no
+// binary was found at install time.
+var bareMurRe = regexp.MustCompile(`(^|[\s("'` + "`" + `;|&=])mur ((?:context|sync|learn|session|feedback|search|inject|hook)\b)`)
+
+// hasBareMur reports whether a command outside shell comments runs mur by
+// name.
+func hasBareMur(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		if !isComment(line) && bareMurRe.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// CheckBinaryRefs returns the mur commands in installed hooks and
+// whether each will run: the binary must exist and be the mur that is
+// running now, and mur run by name must be on MinimalPath.
+func CheckBinaryRefs() []BinaryRef {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	current, _ := filepath.EvalSymlinks(MurBinary())
+	return checkBinaryRefsIn(hookFiles(home), current, OnMinimalPath())
+}
+
+func checkBinaryRefsIn(files []string, current string, bareOK bool) []BinaryRef {
+	var refs []BinaryRef
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, path := range murPaths(string(data)) {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			ref := BinaryRef{File: file, Path: path}
+			info, err := os.Stat(path)
+			switch {
+			case err != nil:
+				ref.Problem = "not found (mur moved or was upgraded)"
+			case info.Mode()&0111 == 0:
+				ref.Problem = "not executable"
+			case current != "" && !sameBinary(path, current):
+				ref.Problem = "not the mur you are running (" + current + ")"
+			}
+			refs = append(refs, ref)
+		}
+		if hasBareMur(string(data)) {
+			ref := BinaryRef{File: file, Path: "mur"}
+			if !bareOK {
+				ref.Problem = "run from PATH, which hooks started outside a login shell may not have"
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// murPaths returns the absolute mur paths in s.
+func murPaths(s string) []string {
+	var paths []string
+	for _, m := range absMurRe.FindAllString(s, -1) {
+		m = strings.TrimRight(m, " \t\n\"'`;|&()<>")
+		if info, err := os.Stat(m); err == nil && info.IsDir() {
+			continue // e.g. a data directory named mur
+		}
+		paths = append(paths, m)
+	}
+	return paths
+}
+
+// hookFiles returns the files mur installs hooks in: its hook scripts and
+// the hook configs of tools. Scripts users keep their own changes in
+// (*.local.sh) are left out.
+func hookFiles(home string) []string {
+	var files []string
+	scripts, _ := filepath.Glob(filepath.Join(config.DataDir(home), "hooks", "*.sh"))
+	for _, s := range scripts {
+		if !strings.HasSuffix(s, ".local.sh") {
+			files = append(files, s)
+		}
+	}
+	for _, s := range statusIn(home) {
+		// Not only where Installed: a settings file can hold just the
+		// search hook, and files without mur commands yield no refs
+		if info, err := os.Stat(s.Path); err == nil && !info.IsDir() {
+			files = append(files, s.Path)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// RepairBinaryRefs points the hooks whose mur commands won't run at bin,
+// keeping everything else in the files, and returns the files changed.
+func RepairBinaryRefs(bin string) ([]string, error) {
+	if !filepath.IsAbs(bin) {
+		return nil, fmt.Errorf("cannot find the mur binary to point hooks at (got %q)", bin)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	current, _ := filepath.EvalSymlinks(bin)
+	return repairBinaryRefsIn(checkBinaryRefsIn(hookFiles(home), current, OnMinimalPath()), bin)
+}
+
+func repairBinaryRefsIn(refs []BinaryRef, bin string) ([]string, error) {
+	byFile := map[string][]string{}
+	var order []string
+	for _, r := range refs {
+		if r.Problem == "" {
+			continue
+		}
+		if _, ok := byFile[r.File]; !ok {
+			order = append(order, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r.Path)
+	}
+
+	var changed []string
+	for _, file := range order {
+		info, err := os.Stat(file)
+		if err != nil {
+			return changed, err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return changed, err
+		}
+		s := string(data)
+		for _, old := range byFile[file] {
+			if old == "mur" {
+				lines := strings.Split(s, "\n")
+				for i, line := range lines {
+					if !isComment(line) {
+						lines[i] = bareMurRe.ReplaceAllString(line, "${1}"+strings.ReplaceAll(bin, "$", "$$")+" ${2}")
+					}
+				}
+				s = strings.Join(lines, "\n")
+				continue
+			}
+			re := regexp.MustCompile(regexp.QuoteMeta(old) + `([\s"'` + "`" + `;|&()<>]|$)`)
+			s = re.ReplaceAllString(s, strings.ReplaceAll(bin, "$", "$$")+"${1}")
+		}
+		if s == string(data) {
+			continue
+		}
+		if err := os.WriteFile(file, []byte(s), info.Mode().Perm()); err != nil {
+			return changed, fmt.Errorf("cannot rewrite %s: %w", file, err)
+		}
+		changed = append(changed, file)
+	}
+	return changed, nil
+}
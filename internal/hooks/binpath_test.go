@@ -0,0 +1,83 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckAndRepairBinaryRefs(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin", "mur")
+	if err := os.MkdirAll(filepath.Dir(bin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	moved := "/opt/homebrew/Cellar/mur/0.0.1/bin/mur"
+	dataDir := filepath.Join(dir, "share", "mur")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := filepath.Join(dir, "on-stop.sh")
+	writeFile(t, script, "#!/bin/bash\n[ -f "+dataDir+"/x ] && true\n("+moved+" sync --quiet &)\n"+bin+" feedback --hook\n")
+	settings := filepath.Join(dir, "hooks.json")
+	writeFile(t, settings, `{"command": "mur sync --quiet 2>/dev/null || true"}`)
+	ok := filepath.Join(dir, "ok.sh")
+	writeFile(t, ok, "# see 'mur context --explain-last'\n"+bin+" context\n")
+
+	refs := checkBinaryRefsIn([]string{script, settings, ok}, bin, false)
+	problems := map[string]string{}
+	for _, r := range refs {
+		if r.Problem != "" {
+			problems[filepath.Base(r.File)+" "+r.Path] = r.Problem
+		}
+	}
+	if len(refs) != 4 || len(problems) != 2 {
+		t.Fatalf("refs = %+v", refs)
+	}
+	if _, found := problems["on-stop.sh "+moved]; !found {
+		t.Errorf("moved binary not reported: %v", problems)
+	}
+	if _, found := problems["hooks.json mur"]; !found {
+		t.Errorf("bare mur not reported: %v", problems)
+	}
+
+	changed, err := repairBinaryRefsIn(refs, bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 {
+		t.Errorf("changed = %v, want 2 files", changed)
+	}
+	data, _ := os.ReadFile(script)
+	if strings.Contains(string(data), moved) || !strings.Contains(string(data), "("+bin+" sync --quiet &)") {
+		t.Errorf("script not repaired:\n%s", data)
+	}
+	if !strings.Contains(string(data), dataDir+"/x") {
+		t.Error("repair touched a path that isn't the binary")
+	}
+	if info, _ := os.Stat(script); info.Mode().Perm() != 0755 {
+		t.Errorf("script mode = %v, want 0755", info.Mode().Perm())
+	}
+	data, _ = os.ReadFile(settings)
+	if string(data) != `{"command": "`+bin+` sync --quiet 2>/dev/null || true"}` {
+		t.Errorf("settings = %s", data)
+	}
+
+	for _, r := range checkBinaryRefsIn([]string{script, settings, ok}, bin, false) {
+		if r.Problem != "" {
+			t.Errorf("after repair: %+v", r)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@ package hooks
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -42,14 +43,15 @@ func ClaudeCodeInstalled() bool {
 	}
 
 	// Check if .claude directory exists
-	claudeDir := filepath.Join(home, ".claude")
+	claudeDir := config.ClaudeDir(home)
 	_, err = os.Stat(claudeDir)
 	return err == nil
 }
 
 // InstallClaudeCodeHooks installs mur hooks for Claude Code.
 //
-// Instead of hardcoding commands, this creates shell scripts in ~/.mur/hooks/
+// Instead of hardcoding commands, this creates shell scripts in the mur data
+// directory (~/.mur/hooks/ by default)
 // and points Claude Code's settings.json at them. If scripts already exist,
 // they are preserved (user customizations are kept). Settings.json hooks are
 // merged — existing non-mur hooks are not removed.
@@ -69,8 +71,10 @@ func InstallClaudeCodeHooksWithOptions(opts HookOptions) error {
 		murBin = "mur"
 	}
 
-	hooksDir := filepath.Join(home, ".mur", "hooks")
-	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	hooksDir := filepath.Join(config.DataDir(home), "hooks")
+	activeSession := filepath.Join(config.StateDir(home), "session", "active.json")
+	localStopScript := filepath.Join(hooksDir, "on-stop.local.sh")
+	settingsPath := filepath.Join(config.ClaudeDir(home), "settings.json")
 
 	// Ensure hooks directory exists
 	if err := os.MkdirAll(hooksDir, 0755); err != nil {
@@ -78,13 +82,13 @@ func InstallClaudeCodeHooksWithOptions(opts HookOptions) error {
 	}
 
 	// Install /mur:in and /mur:out as Claude Code slash commands
-	commandsDir := filepath.Join(home, ".claude", "commands", "mur")
+	commandsDir := filepath.Join(config.ClaudeDir(home), "commands", "mur")
 	if err := os.MkdirAll(commandsDir, 0755); err != nil {
 		return fmt.Errorf("cannot create commands directory: %w", err)
 	}
 
 	// Clean up old flat files (mur:in.md, mur:out.md) from v1.14.0
-	oldCommandsDir := filepath.Join(home, ".claude", "commands")
+	oldCommandsDir := filepath.Join(config.ClaudeDir(home), "commands")
 	for _, old := range []string{"mur:in.md", "mur:out.md"} {
 		oldPath := filepath.Join(oldCommandsDir, old)
 		if _, err := os.Stat(oldPath); err == nil {
@@ -148,24 +152,28 @@ If --analyze fails due to missing API key, tell the user to set ANTHROPIC_API_KE
 	if ShouldUpgradeHook(stopScript, opts.Force) {
 		content := fmt.Sprintf(`#!/bin/bash
 # mur-managed-hook v%d
+%s
 # Read hook input from stdin (Claude Code passes JSON)
 INPUT=$(cat /dev/stdin 2>/dev/null || echo '{}')
 
 # Record stop event to active session (if recording)
-if [ -f ~/.mur/session/active.json ]; then
+if [ -f %q ]; then
   STOP_REASON=$(echo "$INPUT" | jq -r '.stop_reason // "turn_end"' 2>/dev/null)
   %s session record --type assistant --content "[stop: $STOP_REASON]" 2>/dev/null || true
 fi
 
 # All background — don't block Claude Code
-(%s sync --quiet 2>/dev/null &)
-(%s learn extract --llm --auto --accept-all --quiet 2>/dev/null &)
+(%s sync --quiet --coalesce 2>/dev/null &)
+(%s learn extract --llm --auto --accept-all --quiet --coalesce 2>/dev/null &)
+
+# Judge how this session's injected patterns fared (updates effectiveness)
+(echo "$INPUT" | %s feedback --hook >/dev/null 2>&1 &)
 
 # Load user customizations if they exist
-[ -f ~/.mur/hooks/on-stop.local.sh ] && source ~/.mur/hooks/on-stop.local.sh
+[ -f %q ] && source %q
 
 exit 0
-`, CurrentHookVersion, murBin, murBin, murBin)
+`, CurrentHookVersion, stampLine(), activeSession, murBin, murBin, murBin, murBin, localStopScript, localStopScript)
 		if err := os.WriteFile(stopScript, []byte(content), 0755); err != nil {
 			return fmt.Errorf("cannot write on-stop.sh: %w", err)
 		}
@@ -178,20 +186,23 @@ exit 0
 	if ShouldUpgradeHook(promptScript, opts.Force) {
 		content := fmt.Sprintf(`#!/bin/bash
 # mur-managed-hook v%d
+%s
 # Read hook input from stdin (Claude Code passes JSON)
 INPUT=$(cat /dev/stdin 2>/dev/null || echo '{}')
 
 # Inject context-aware patterns based on current project
-%s context --compact 2>/dev/null || true
+# (the session id lets 'mur context --explain-last' group injections)
+export MUR_SESSION_ID=$(echo "$INPUT" | jq -r '.session_id // empty' 2>/dev/null)
+%s context --compact --target claude 2>/dev/null || true
 
 # Record user prompt to active session (if recording)
-if [ -f ~/.mur/session/active.json ]; then
+if [ -f %q ]; then
   PROMPT=$(echo "$INPUT" | jq -r '.prompt // empty' 2>/dev/null)
   if [ -n "$PROMPT" ]; then
     %s session record --type user --content "$PROMPT" 2>/dev/null || true
   fi
 fi
-`, CurrentHookVersion, murBin, murBin)
+`, CurrentHookVersion, stampLine(), murBin, activeSession, murBin)
 		if err := os.WriteFile(promptScript, []byte(content), 0755); err != nil {
 			return fmt.Errorf("cannot write on-prompt.sh: %w", err)
 		}
@@ -205,8 +216,9 @@ fi
 	if ShouldUpgradeHook(onToolScript, opts.Force) {
 		content := fmt.Sprintf(`#!/bin/bash
 # mur-managed-hook v%d
+%s
 # Record tool usage to active session (if recording)
-if [ -f ~/.mur/session/active.json ]; then
+if [ -f %q ]; then
   INPUT=$(cat /dev/stdin 2>/dev/null || echo '{}')
   TOOL=$(echo "$INPUT" | jq -r '.tool_name // empty' 2>/dev/null)
   TOOL_INPUT=$(echo "$INPUT" | jq -c '.tool_input // {}' 2>/dev/null)
@@ -214,7 +226,7 @@ if [ -f ~/.mur/session/active.json ]; then
     %s session record --type tool_call --tool "$TOOL" --content "$TOOL_INPUT" 2>/dev/null || true
   fi
 fi
-`, CurrentHookVersion, murBin)
+`, CurrentHookVersion, stampLine(), activeSession, murBin)
 		if err := os.WriteFile(onToolScript, []byte(content), 0755); err != nil {
 			return fmt.Errorf("cannot write on-tool.sh: %w", err)
 		}
@@ -225,7 +237,7 @@ fi
 
 	reminderFile := filepath.Join(hooksDir, "on-prompt-reminder.md")
 	if _, err := os.Stat(reminderFile); os.IsNotExist(err) {
-		content := fmt.Sprintf("[ContinuousLearning] If during this task you discover something non-obvious (a debugging technique, a workaround, a pattern), save it:\n\n  %s learn add --name \"pattern-name\" --content \"description\"\n\nOr create a file in ~/.mur/patterns/\n\nOnly save if: it required discovery, it helps future tasks, and it's verified.\n", murBin)
+		content := fmt.Sprintf("[ContinuousLearning] If during this task you discover something non-obvious (a debugging technique, a workaround, a pattern), save it:\n\n  %s learn add --name \"pattern-name\" --content \"description\"\n\nOr create a file in %s\n\nOnly save if: it required discovery, it helps future tasks, and it's verified.\n", murBin, filepath.Join(config.DataDir(home), "patterns")+string(filepath.Separator))
 		if err := os.WriteFile(reminderFile, []byte(content), 0644); err != nil {
 			return fmt.Errorf("cannot write on-prompt-reminder.md: %w", err)
 		}
@@ -263,7 +275,7 @@ fi
 	if opts.EnableSearch {
 		promptHooks = append(promptHooks, ClaudeCodeHook{
 			Type:    "command",
-			Command: fmt.Sprintf("%s search --inject \"$PROMPT\" 2>/dev/null || true", murBin),
+			Command: fmt.Sprintf("%s=%s %s search --inject --target claude \"$PROMPT\" 2>/dev/null || true", StampEnv, MurVersion, murBin),
 		})
 	}
 	promptMatcher := ClaudeCodeHookMatcher{
@@ -288,7 +300,7 @@ fi
 	rawSettings["hooks"] = mustMarshal(existingHooks)
 
 	// Ensure .claude directory exists
-	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0755); err != nil {
+	if err := os.MkdirAll(config.ClaudeDir(home), 0755); err != nil {
 		return fmt.Errorf("cannot create .claude directory: %w", err)
 	}
 
@@ -302,7 +314,7 @@ fi
 	}
 
 	fmt.Printf("✓ Installed Claude Code hooks at %s\n", settingsPath)
-	fmt.Println("  + Stop hook → on-stop.sh (learn + sync)")
+	fmt.Println("  + Stop hook → on-stop.sh (learn + sync + pattern feedback)")
 	fmt.Println("  + Prompt hook → on-prompt-reminder.md")
 	fmt.Println("  + PostToolUse hook → on-tool.sh (record tool calls)")
 	fmt.Println("  + Slash commands → /mur:in, /mur:out (session recording)")
@@ -337,6 +349,9 @@ func mergeMurMatcherSet(existing json.RawMessage, murMatchers ...ClaudeCodeHookM
 func isMurMatcher(m ClaudeCodeHookMatcher) bool {
 	for _, h := range m.Hooks {
 		if strings.Contains(h.Command, ".mur/") ||
+			strings.Contains(h.Command, "/hooks/on-stop.sh") ||
+			strings.Contains(h.Command, "/hooks/on-tool.sh") ||
+			strings.Contains(h.Command, "/hooks/on-prompt-reminder.md") ||
 			strings.Contains(h.Command, "mur ") ||
 			strings.HasPrefix(h.Command, "mur\t") {
 			return true
@@ -357,7 +372,7 @@ func UninstallClaudeCodeHooks() error {
 		return fmt.Errorf("cannot determine home directory: %w", err)
 	}
 
-	settingsPath := filepath.Join(home, ".claude", "settings.json")
+	settingsPath := filepath.Join(config.ClaudeDir(home), "settings.json")
 
 	// Read existing settings
 	data, err := os.ReadFile(settingsPath)
@@ -13,7 +13,7 @@ import (
 
 // CurrentHookVersion is the version of mur-managed hook scripts.
 // Bump this when the hook template changes to trigger auto-upgrade.
-const CurrentHookVersion = 3
+const CurrentHookVersion = 9
 
 var hookVersionRe = regexp.MustCompile(`#\s*mur-managed-hook\s+v(\d+)`)
 
@@ -44,7 +44,8 @@ func shouldUpgradeHook(path string) bool {
 
 // ShouldUpgradeHook returns true if the hook file should be overwritten.
 // If force is true, always returns true (for --force flag).
-// Otherwise returns true only if the file doesn't exist or has an older version.
+// Otherwise returns true only if the file doesn't exist, has an older version,
+// or is stamped by a different mur release than the running one.
 func ShouldUpgradeHook(path string, force bool) bool {
 	if force {
 		return true
@@ -52,7 +53,11 @@ func ShouldUpgradeHook(path string, force bool) bool {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return true
 	}
-	return parseHookVersion(path) < CurrentHookVersion
+	if parseHookVersion(path) < CurrentHookVersion {
+		return true
+	}
+	stamp := parseHookStamp(path)
+	return stamp != "" && !SameRelease(stamp, MurVersion)
 }
 
 // ParseHookVersion reads the version tag from a hook file (exported for use by init).
@@ -62,7 +67,14 @@ func ParseHookVersion(path string) int {
 
 // findMurBinary finds the mur binary path.
 func findMurBinary() (string, error) {
-	// First try to find in PATH
+	// The running binary, by a path that outlives upgrades
+	if exe, err := os.Executable(); err == nil {
+		if path, ok := stableBinary(exe); ok {
+			return path, nil
+		}
+	}
+
+	// Then try to find in PATH
 	if path, err := exec.LookPath("mur"); err == nil {
 		return path, nil
 	}
@@ -145,6 +157,16 @@ func InstallAllHooksWithOptions(opts HookOptions) map[string]error {
 		}
 	}
 
+	// Windsurf
+	if WindsurfInstalled() {
+		results["Windsurf"] = InstallWindsurfHooks()
+	}
+
+	// Zed (tasks; Zed has no agent hooks)
+	if ZedInstalled() {
+		results["Zed"] = InstallZedHooks()
+	}
+
 	// Aider (optional - creates templates)
 	if AiderInstalled() {
 		if err := InstallAiderHooks(); err != nil {
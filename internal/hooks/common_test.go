@@ -50,7 +50,7 @@ func TestShouldUpgradeHook(t *testing.T) {
 
 	// Current version
 	cur := filepath.Join(dir, "current.sh")
-	os.WriteFile(cur, []byte("#!/bin/bash\n# mur-managed-hook v3\n"), 0644)
+	os.WriteFile(cur, []byte("#!/bin/bash\n# mur-managed-hook v9\n"), 0644)
 	if shouldUpgradeHook(cur) {
 		t.Error("should NOT upgrade current version")
 	}
@@ -61,7 +61,7 @@ func TestShouldUpgradeHookForce(t *testing.T) {
 
 	// Current version, no force — should not upgrade
 	cur := filepath.Join(dir, "current.sh")
-	os.WriteFile(cur, []byte("#!/bin/bash\n# mur-managed-hook v3\n"), 0644)
+	os.WriteFile(cur, []byte("#!/bin/bash\n# mur-managed-hook v9\n"), 0644)
 	if ShouldUpgradeHook(cur, false) {
 		t.Error("should NOT upgrade current version without force")
 	}
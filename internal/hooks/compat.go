@@ -0,0 +1,120 @@
+package hooks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// StampEnv is the environment variable through which hook scripts tell
+// mur which version installed them.
+const StampEnv = "MUR_HOOK_STAMP"
+
+// MurVersion is the version of the running mur binary. Hook scripts are
+// stamped with it; the command package sets it at startup.
+var MurVersion = "dev"
+
+var hookStampRe = regexp.MustCompile(`^export\s+` + StampEnv + `=(\S+)`)
+
+// stampLine is the line hook scripts use to pass their stamp to mur.
+func stampLine() string {
+	return fmt.Sprintf("export %s=%s", StampEnv, MurVersion)
+}
+
+// StampLine returns the stamp line for hook scripts (exported for use by init).
+func StampLine() string {
+	return stampLine()
+}
+
+// parseHookStamp reads the first 5 lines of a hook script looking for its
+// stamp line and returns the stamped version, or "" if there is none.
+func parseHookStamp(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < 5 && scanner.Scan(); i++ {
+		if m := hookStampRe.FindStringSubmatch(scanner.Text()); len(m) == 2 {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// SameRelease reports whether two versions share major and minor, e.g.
+// 1.14.2 and 1.14.9. Versions that aren't numbered releases (like "dev")
+// match anything, so development builds never trigger safe mode.
+func SameRelease(a, b string) bool {
+	am, ok := majorMinor(a)
+	if !ok {
+		return true
+	}
+	bm, ok := majorMinor(b)
+	if !ok {
+		return true
+	}
+	return am == bm
+}
+
+var majorMinorRe = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:[.\-+]|$)`)
+
+// majorMinor returns "major.minor" of a version.
+func majorMinor(v string) (string, bool) {
+	m := majorMinorRe.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return "", false
+	}
+	return m[1] + "." + m[2], true
+}
+
+// Mismatch describes mur being run from a hook installed by a different
+// release, typically after an upgrade under a running AI session.
+type Mismatch struct {
+	Stamp   string // version that installed the hook
+	Running string // version of the running binary
+}
+
+// CheckStamp returns the mismatch when mur was invoked from a hook whose
+// stamp has a different major or minor version than running, or nil when
+// it wasn't invoked from a stamped hook or the versions are compatible.
+func CheckStamp(running string) *Mismatch {
+	stamp := os.Getenv(StampEnv)
+	if stamp == "" || SameRelease(stamp, running) {
+		return nil
+	}
+	return &Mismatch{Stamp: stamp, Running: running}
+}
+
+// Hint is the upgrade hint shown for the mismatch.
+func (m *Mismatch) Hint() string {
+	return fmt.Sprintf("mur %s is running hooks installed by mur %s; they run in safe mode until refreshed with 'mur init --hooks'", m.Running, m.Stamp)
+}
+
+// HintOnce returns the upgrade hint the first time it is asked for this
+// pair of versions and "" after that, so a busy session isn't flooded.
+func (m *Mismatch) HintOnce() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(config.StateDir(home), "hooks-compat")
+	key := m.Stamp + " " + m.Running
+	if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == key {
+		return ""
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return ""
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0644); err != nil {
+		return ""
+	}
+	return m.Hint()
+}
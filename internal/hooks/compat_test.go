@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSameRelease(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.14.2", "1.14.9", true},
+		{"1.14.2", "1.15.0", false},
+		{"v1.14.2", "1.14.0-rc1", true},
+		{"2.0.0", "1.14.12", false},
+		{"dev", "1.15.0", true},
+		{"1.15.0", "", true},
+	}
+	for _, tt := range tests {
+		if got := SameRelease(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameRelease(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckStamp(t *testing.T) {
+	t.Setenv(StampEnv, "")
+	if m := CheckStamp("1.15.0"); m != nil {
+		t.Errorf("unstamped invocation: got %+v, want nil", m)
+	}
+
+	t.Setenv(StampEnv, "1.15.3")
+	if m := CheckStamp("1.15.0"); m != nil {
+		t.Errorf("same release: got %+v, want nil", m)
+	}
+
+	t.Setenv(StampEnv, "1.14.12")
+	m := CheckStamp("1.15.0")
+	if m == nil || m.Stamp != "1.14.12" || m.Running != "1.15.0" {
+		t.Fatalf("mismatch = %+v", m)
+	}
+}
+
+func TestMismatchHintOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	m := &Mismatch{Stamp: "1.14.12", Running: "1.15.0"}
+	if m.HintOnce() == "" {
+		t.Fatal("first hint should be shown")
+	}
+	if hint := m.HintOnce(); hint != "" {
+		t.Errorf("second hint = %q, want none", hint)
+	}
+
+	next := &Mismatch{Stamp: "1.15.0", Running: "1.16.0"}
+	if next.HintOnce() == "" {
+		t.Error("hint for a new pair of versions should be shown")
+	}
+}
+
+func TestShouldUpgradeHookStamp(t *testing.T) {
+	old := MurVersion
+	defer func() { MurVersion = old }()
+	MurVersion = "1.15.0"
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		os.WriteFile(p, []byte(content), 0644)
+		return p
+	}
+
+	stale := write("stale.sh", "#!/bin/bash\n# mur-managed-hook v9\nexport MUR_HOOK_STAMP=1.14.12\n")
+	if parseHookStamp(stale) != "1.14.12" {
+		t.Errorf("parseHookStamp = %q", parseHookStamp(stale))
+	}
+	if !ShouldUpgradeHook(stale, false) {
+		t.Error("should upgrade hook stamped by another release")
+	}
+
+	current := write("current.sh", "#!/bin/bash\n# mur-managed-hook v9\nexport MUR_HOOK_STAMP=1.15.2\n")
+	if ShouldUpgradeHook(current, false) {
+		t.Error("should NOT upgrade hook stamped by the same release")
+	}
+}
@@ -51,7 +51,7 @@ func InstallCopilotHooks() error {
 			}},
 			"sessionEnd": {{
 				Type:    "command",
-				Bash:    fmt.Sprintf("%s learn extract --llm --auto --accept-all --quiet 2>/dev/null || true", murPath),
+				Bash:    fmt.Sprintf("%s learn extract --llm --auto --accept-all --quiet --coalesce 2>/dev/null || true", murPath),
 				Timeout: 300,
 			}},
 		},
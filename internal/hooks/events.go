@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Event is a mur hook event. The names are the ones used in the hooks
+// section of config.yaml (Claude Code's names).
+type Event string
+
+// mur hook events.
+const (
+	EventUserPromptSubmit Event = "UserPromptSubmit" // before a prompt is sent: inject patterns
+	EventStop             Event = "Stop"             // after a response: sync and learn
+	EventBeforeTool       Event = "BeforeTool"
+	EventAfterTool        Event = "AfterTool"
+)
+
+// Events lists the mur hook events in order.
+var Events = []Event{EventUserPromptSubmit, EventStop, EventBeforeTool, EventAfterTool}
+
+// ToolEvents maps mur's hook events onto a tool's own events.
+type ToolEvents struct {
+	Tool   string
+	Native map[Event]string // mur event -> the tool's event; missing events are unsupported
+	Manual bool             // the tool has no lifecycle events; Native names tasks the user runs
+}
+
+// Name returns the tool's name for e, and whether the tool supports it.
+func (t ToolEvents) Name(e Event) (string, bool) {
+	name, ok := t.Native[e]
+	return name, ok
+}
+
+// Supported returns the mur events the tool supports, in order.
+func (t ToolEvents) Supported() []Event {
+	var out []Event
+	for _, e := range Events {
+		if _, ok := t.Native[e]; ok {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Describe summarizes the mapping, e.g.
+// "UserPromptSubmit→pre_user_prompt, Stop→post_cascade_response".
+func (t ToolEvents) Describe() string {
+	var parts []string
+	for _, e := range t.Supported() {
+		parts = append(parts, fmt.Sprintf("%s→%s", e, t.Native[e]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// toolEvents is the event mapping for each tool mur installs hooks for.
+var toolEvents = []ToolEvents{
+	{Tool: "Claude Code", Native: map[Event]string{
+		EventUserPromptSubmit: "UserPromptSubmit",
+		EventStop:             "Stop",
+		EventBeforeTool:       "PreToolUse",
+		EventAfterTool:        "PostToolUse",
+	}},
+	{Tool: "Gemini CLI", Native: map[Event]string{
+		EventUserPromptSubmit: "BeforeAgent",
+		EventStop:             "SessionEnd",
+		EventBeforeTool:       "BeforeTool",
+		EventAfterTool:        "AfterTool",
+	}},
+	{Tool: "OpenCode", Native: map[Event]string{
+		EventUserPromptSubmit: "before",
+		EventStop:             "after",
+	}},
+	{Tool: "GitHub Copilot", Native: map[Event]string{
+		EventUserPromptSubmit: "sessionStart",
+		EventStop:             "sessionEnd",
+	}},
+	{Tool: "Auggie", Native: map[Event]string{
+		EventUserPromptSubmit: "SessionStart",
+		EventStop:             "Stop",
+		EventBeforeTool:       "PreToolUse",
+		EventAfterTool:        "PostToolUse",
+	}},
+	{Tool: "OpenClaw", Native: map[Event]string{
+		EventUserPromptSubmit: "agent:bootstrap",
+	}},
+	{Tool: "Windsurf", Native: map[Event]string{
+		EventUserPromptSubmit: "pre_user_prompt",
+		EventStop:             "post_cascade_response",
+		EventBeforeTool:       "pre_run_command",
+		EventAfterTool:        "post_run_command",
+	}},
+	{Tool: "Zed", Manual: true, Native: map[Event]string{
+		EventUserPromptSubmit: zedTaskContext,
+		EventStop:             zedTaskLearn,
+	}},
+}
+
+// EventsFor returns the event mapping for a tool.
+func EventsFor(tool string) (ToolEvents, bool) {
+	for _, t := range toolEvents {
+		if strings.EqualFold(t.Tool, tool) {
+			return t, true
+		}
+	}
+	return ToolEvents{}, false
+}
@@ -0,0 +1,148 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEventsFor(t *testing.T) {
+	ws, ok := EventsFor("windsurf")
+	if !ok {
+		t.Fatal("no mapping for Windsurf")
+	}
+	if name, _ := ws.Name(EventUserPromptSubmit); name != "pre_user_prompt" {
+		t.Errorf("Windsurf UserPromptSubmit = %q", name)
+	}
+	if name, _ := ws.Name(EventStop); name != "post_cascade_response" {
+		t.Errorf("Windsurf Stop = %q", name)
+	}
+
+	zed, _ := EventsFor("Zed")
+	if !zed.Manual {
+		t.Error("Zed should be manual")
+	}
+	if _, ok := zed.Name(EventBeforeTool); ok {
+		t.Error("Zed shouldn't support BeforeTool")
+	}
+	if got := zed.Describe(); got != "UserPromptSubmit→"+zedTaskContext+", Stop→"+zedTaskLearn {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	if _, ok := EventsFor("notepad"); ok {
+		t.Error("unexpected mapping for unknown tool")
+	}
+}
+
+func TestInstallWindsurfHooksMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "windsurf", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := `{"hooks": {"pre_user_prompt": [{"command": "lint-prompt"}]}, "other": true}`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Installing twice must not duplicate mur's hooks
+	for i := 0; i < 2; i++ {
+		if err := installWindsurfHooks(path, "/usr/local/bin/mur"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got struct {
+		Hooks map[string][]map[string]interface{} `json:"hooks"`
+		Other bool                                `json:"other"`
+	}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Other {
+		t.Error("lost unrelated settings")
+	}
+	pre := got.Hooks["pre_user_prompt"]
+	if len(pre) != 2 || pre[0]["command"] != "lint-prompt" {
+		t.Errorf("pre_user_prompt = %v, want user hook then mur's", pre)
+	} else if cmd, _ := pre[1]["command"].(string); !strings.Contains(cmd, "sync --cli --quiet --coalesce") || !strings.HasSuffix(cmd, "&)") {
+		t.Errorf("mur's pre_user_prompt hook = %q, want a backgrounded local sync", cmd)
+	}
+	if len(got.Hooks["post_cascade_response"]) != 1 {
+		t.Errorf("post_cascade_response = %v", got.Hooks["post_cascade_response"])
+	}
+	if !hasWindsurfHooks(path) {
+		t.Error("hasWindsurfHooks = false after install")
+	}
+	if _, err := os.Stat(path + ".backup"); err != nil {
+		t.Error("no backup of existing hooks.json")
+	}
+}
+
+func TestInstallZedHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zed", "tasks.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`[{"label": "test", "command": "go test ./..."}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := installZedHooks(path, "mur"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var tasks []ZedTask
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[0].Label != "test" {
+		t.Errorf("tasks = %+v, want the user's task and mur's two", tasks)
+	}
+	if !hasZedTasks(path) {
+		t.Error("hasZedTasks = false after install")
+	}
+
+	// Zed allows comments in tasks.json; mur won't rewrite such a file
+	commented := []byte("// my tasks\n[]")
+	if err := os.WriteFile(path, commented, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := installZedHooks(path, "mur"); err == nil {
+		t.Error("expected error for tasks.json with comments")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(commented) {
+		t.Error("tasks.json with comments was modified")
+	}
+}
+
+func TestStatusIn(t *testing.T) {
+	home := t.TempDir()
+	if err := installWindsurfHooks(windsurfHooksPath(home), "mur"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".config", "zed"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	byTool := map[string]ToolStatus{}
+	for _, s := range statusIn(home) {
+		byTool[s.Tool] = s
+	}
+	if len(byTool) != len(toolEvents) {
+		t.Errorf("status for %d tools, want %d", len(byTool), len(toolEvents))
+	}
+	if s := byTool["Windsurf"]; !s.Detected || !s.Installed {
+		t.Errorf("Windsurf = %+v, want detected and installed", s)
+	}
+	if s := byTool["Zed"]; !s.Detected || s.Installed {
+		t.Errorf("Zed = %+v, want detected, not installed", s)
+	}
+	if s := byTool["Gemini CLI"]; s.Detected || s.Installed {
+		t.Errorf("Gemini CLI = %+v, want not detected", s)
+	}
+}
@@ -63,7 +63,7 @@ func InstallGeminiHooks(enableSearch bool) error {
 	hooks["exit"] = []GeminiHook{
 		{
 			Type:    "command",
-			Command: fmt.Sprintf("%s learn extract --auto --quiet 2>/dev/null || true", murBin),
+			Command: fmt.Sprintf("%s=%s %s learn extract --auto --quiet --coalesce 2>/dev/null || true", StampEnv, MurVersion, murBin),
 		},
 	}
 
@@ -72,7 +72,7 @@ func InstallGeminiHooks(enableSearch bool) error {
 		hooks["prompt"] = []GeminiHook{
 			{
 				Type:    "command",
-				Command: fmt.Sprintf("%s search --inject \"$PROMPT\" 2>/dev/null || true", murBin),
+				Command: fmt.Sprintf("%s=%s %s search --inject \"$PROMPT\" 2>/dev/null || true", StampEnv, MurVersion, murBin),
 			},
 		}
 	}
@@ -57,7 +57,7 @@ func InstallOpenCodeHooks() error {
 				Inject: "# Learned Patterns\nApply these patterns when relevant:\n\n{stdout}",
 			}},
 			After: []OpenCodeHook{{
-				Run: fmt.Sprintf("%s learn extract --llm --auto --accept-all --quiet 2>/dev/null || true", murPath),
+				Run: fmt.Sprintf("%s learn extract --llm --auto --accept-all --quiet --coalesce 2>/dev/null || true", murPath),
 			}},
 		},
 	}
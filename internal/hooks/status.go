@@ -0,0 +1,78 @@
+// Package hooks provides hook installation for AI CLI tools.
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// ToolStatus reports mur's hooks for one tool.
+type ToolStatus struct {
+	Tool      string
+	Detected  bool   // the tool's config directory exists
+	Installed bool   // mur hooks are in the tool's config
+	Path      string // where the hooks live
+	Events    ToolEvents
+}
+
+// Status reports mur's hooks for every tool in the event mapping.
+func Status() []ToolStatus {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return statusIn(home)
+}
+
+func statusIn(home string) []ToolStatus {
+	type probe struct {
+		dir   string                               // detected when this exists
+		check func() (installed bool, path string) // where mur's hooks are
+	}
+	settingsHas := func(path string) func() (bool, string) {
+		return func() (bool, string) {
+			data, err := os.ReadFile(path)
+			return err == nil && strings.Contains(string(data), "on-prompt.sh"), path
+		}
+	}
+	exists := func(path string) func() (bool, string) {
+		return func() (bool, string) {
+			_, err := os.Stat(path)
+			return err == nil, path
+		}
+	}
+	probes := map[string]probe{
+		"Claude Code":    {config.ClaudeDir(home), settingsHas(filepath.Join(config.ClaudeDir(home), "settings.json"))},
+		"Gemini CLI":     {filepath.Join(home, ".gemini"), settingsHas(filepath.Join(home, ".gemini", "settings.json"))},
+		"OpenCode":       {filepath.Join(home, ".config", "opencode"), exists(filepath.Join(home, ".config", "opencode", "plugins", "mur", "plugin.yaml"))},
+		"GitHub Copilot": {filepath.Join(home, ".github"), exists(filepath.Join(home, ".github", "hooks", "mur.json"))},
+		"Auggie":         {filepath.Join(home, ".augment"), settingsHas(filepath.Join(home, ".augment", "settings.json"))},
+		"OpenClaw":       {filepath.Join(home, ".openclaw"), exists(filepath.Join(home, ".openclaw", "hooks", "mur-patterns", "HOOK.md"))},
+		"Windsurf": {filepath.Join(home, ".codeium", "windsurf"), func() (bool, string) {
+			path := windsurfHooksPath(home)
+			return hasWindsurfHooks(path), path
+		}},
+		"Zed": {filepath.Join(home, ".config", "zed"), func() (bool, string) {
+			path := zedTasksPath(home)
+			return hasZedTasks(path), path
+		}},
+	}
+
+	var out []ToolStatus
+	for _, ev := range toolEvents {
+		p := probes[ev.Tool]
+		_, err := os.Stat(p.dir)
+		installed, path := p.check()
+		out = append(out, ToolStatus{
+			Tool:      ev.Tool,
+			Detected:  err == nil,
+			Installed: installed,
+			Path:      path,
+			Events:    ev,
+		})
+	}
+	return out
+}
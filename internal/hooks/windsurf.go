@@ -0,0 +1,161 @@
+// Package hooks provides hook installation for AI CLI tools.
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WindsurfHook is a single Cascade hook command in Windsurf's hooks.json.
+type WindsurfHook struct {
+	Command    string `json:"command"`
+	ShowOutput bool   `json:"show_output"`
+}
+
+// windsurfHooksPath returns the user-level Cascade hooks file.
+func windsurfHooksPath(home string) string {
+	return filepath.Join(home, ".codeium", "windsurf", "hooks.json")
+}
+
+// WindsurfInstalled checks if Windsurf is configured.
+func WindsurfInstalled() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(home, ".codeium", "windsurf"))
+	return err == nil
+}
+
+// InstallWindsurfHooks installs mur hooks for Windsurf's Cascade agent.
+//
+// Cascade hooks can't add text to a prompt, so the UserPromptSubmit hook
+// (pre_user_prompt) refreshes ~/.windsurf/rules/mur-patterns.md, which
+// Cascade reads as rules, and the Stop hook (post_cascade_response) learns
+// from the session. Existing non-mur hooks are kept.
+func InstallWindsurfHooks() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	murBin, err := findMurBinary()
+	if err != nil {
+		murBin = "mur"
+	}
+	return installWindsurfHooks(windsurfHooksPath(home), murBin)
+}
+
+func installWindsurfHooks(hooksPath, murBin string) error {
+	events, _ := EventsFor("Windsurf")
+	// Both backgrounded, so Cascade isn't held up; the rules refresh only
+	// syncs the local tools and folds into a sync already running
+	murHooks := map[Event]WindsurfHook{
+		EventUserPromptSubmit: {Command: fmt.Sprintf("(%s sync --cli --quiet --coalesce 2>/dev/null &)", murBin)},
+		EventStop:             {Command: fmt.Sprintf("(%s learn extract --llm --auto --accept-all --quiet --coalesce 2>/dev/null &)", murBin)},
+	}
+
+	// Load existing hooks
+	settings := make(map[string]interface{})
+	if data, err := os.ReadFile(hooksPath); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("cannot parse %s: %w", hooksPath, err)
+		}
+		_ = os.WriteFile(hooksPath+".backup", data, 0644)
+	}
+
+	existing, _ := settings["hooks"].(map[string]interface{})
+	if existing == nil {
+		existing = make(map[string]interface{})
+	}
+	for event, hook := range murHooks {
+		name, _ := events.Name(event)
+		entries, _ := existing[name].([]interface{})
+		kept := make([]interface{}, 0, len(entries)+1)
+		for _, e := range entries {
+			if !isMurWindsurfHook(e) {
+				kept = append(kept, e)
+			}
+		}
+		existing[name] = append(kept, hook)
+	}
+	settings["hooks"] = existing
+
+	if err := os.MkdirAll(filepath.Dir(hooksPath), 0755); err != nil {
+		return fmt.Errorf("cannot create hooks directory: %w", err)
+	}
+	data, err := marshalConfig(settings)
+	if err != nil {
+		return fmt.Errorf("cannot marshal hooks: %w", err)
+	}
+	if err := os.WriteFile(hooksPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write hooks: %w", err)
+	}
+
+	fmt.Printf("✓ Installed Windsurf hooks at %s\n", hooksPath)
+	return nil
+}
+
+// marshalConfig is json.MarshalIndent without HTML escaping, so shell
+// commands keep their & and > readable.
+func marshalConfig(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isMurWindsurfHook reports whether a hooks.json entry runs mur.
+func isMurWindsurfHook(entry interface{}) bool {
+	m, _ := entry.(map[string]interface{})
+	cmd, _ := m["command"].(string)
+	return isMurCommand(cmd)
+}
+
+// isMurCommand reports whether a hook command line runs mur.
+func isMurCommand(cmd string) bool {
+	for _, f := range strings.Fields(strings.TrimLeft(cmd, "(")) {
+		if filepath.Base(f) == "mur" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWindsurfHooks checks if mur hooks are installed for Windsurf.
+func CheckWindsurfHooks() (installed bool, path string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, ""
+	}
+	path = windsurfHooksPath(home)
+	return hasWindsurfHooks(path), path
+}
+
+func hasWindsurfHooks(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var settings struct {
+		Hooks map[string][]interface{} `json:"hooks"`
+	}
+	if json.Unmarshal(data, &settings) != nil {
+		return false
+	}
+	for _, entries := range settings.Hooks {
+		for _, e := range entries {
+			if isMurWindsurfHook(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Package hooks provides hook installation for AI CLI tools.
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Zed task labels mur installs, one per mapped event.
+const (
+	zedTaskContext = "mur: copy patterns for prompt"
+	zedTaskLearn   = "mur: learn from session"
+)
+
+// ZedTask is a task in Zed's tasks.json.
+type ZedTask struct {
+	Label   string   `json:"label"`
+	Command string   `json:"command"`
+	Reveal  string   `json:"reveal,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// zedTasksPath returns Zed's user-level tasks file.
+func zedTasksPath(home string) string {
+	return filepath.Join(home, ".config", "zed", "tasks.json")
+}
+
+// ZedInstalled checks if Zed is configured.
+func ZedInstalled() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(home, ".config", "zed"))
+	return err == nil
+}
+
+// InstallZedHooks installs mur tasks for Zed.
+//
+// Zed's agent has no lifecycle hooks, so mur's events become tasks run
+// from the command palette (task: spawn): one copies the patterns for the
+// current project to the clipboard for pasting into the agent panel, the
+// other learns from the session. Existing tasks are kept; a tasks.json
+// that isn't plain JSON (Zed allows comments) is left alone.
+func InstallZedHooks() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	murBin, err := findMurBinary()
+	if err != nil {
+		murBin = "mur"
+	}
+	return installZedHooks(zedTasksPath(home), murBin)
+}
+
+func installZedHooks(tasksPath, murBin string) error {
+	murTasks := []ZedTask{
+		{
+			Label:   zedTaskContext,
+			Command: fmt.Sprintf("%s context --target zed --copy", murBin),
+			Reveal:  "never",
+			Tags:    []string{"mur"},
+		},
+		{
+			Label:   zedTaskLearn,
+			Command: fmt.Sprintf("%s learn extract --llm --auto --accept-all --coalesce && %s sync --quiet --coalesce", murBin, murBin),
+			Tags:    []string{"mur"},
+		},
+	}
+
+	var tasks []interface{}
+	if data, err := os.ReadFile(tasksPath); err == nil {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return fmt.Errorf("cannot parse %s (remove comments or add the mur tasks by hand): %w", tasksPath, err)
+		}
+		_ = os.WriteFile(tasksPath+".backup", data, 0644)
+	}
+
+	// Replace earlier mur tasks, keep the rest
+	kept := make([]interface{}, 0, len(tasks)+len(murTasks))
+	for _, t := range tasks {
+		if !isMurZedTask(t) {
+			kept = append(kept, t)
+		}
+	}
+	for _, t := range murTasks {
+		kept = append(kept, t)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tasksPath), 0755); err != nil {
+		return fmt.Errorf("cannot create zed config directory: %w", err)
+	}
+	data, err := marshalConfig(kept)
+	if err != nil {
+		return fmt.Errorf("cannot marshal tasks: %w", err)
+	}
+	if err := os.WriteFile(tasksPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write tasks: %w", err)
+	}
+
+	fmt.Printf("✓ Installed Zed tasks at %s\n", tasksPath)
+	return nil
+}
+
+// isMurZedTask reports whether a tasks.json entry is one of mur's.
+func isMurZedTask(task interface{}) bool {
+	m, _ := task.(map[string]interface{})
+	label, _ := m["label"].(string)
+	return label == zedTaskContext || label == zedTaskLearn
+}
+
+// CheckZedHooks checks if mur tasks are installed for Zed.
+func CheckZedHooks() (installed bool, path string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, ""
+	}
+	path = zedTasksPath(home)
+	return hasZedTasks(path), path
+}
+
+func hasZedTasks(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var tasks []interface{}
+	if json.Unmarshal(data, &tasks) != nil {
+		return false
+	}
+	for _, t := range tasks {
+		if isMurZedTask(t) {
+			return true
+		}
+	}
+	return false
+}
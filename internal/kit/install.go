@@ -0,0 +1,274 @@
+package kit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/selfupdate"
+)
+
+// Installed records how a kit was installed.
+type Installed struct {
+	Manifest     Manifest  `yaml:"kit"`
+	Source       string    `yaml:"source"`
+	InstalledAt  time.Time `yaml:"installed_at"`
+	HooksEnabled bool      `yaml:"hooks_enabled,omitempty"`
+	Staged       []string  `yaml:"staged,omitempty"` // patterns staged for review
+}
+
+// InstalledKit is an installed kit and its contents.
+type InstalledKit struct {
+	Installed
+	Kit *Kit
+}
+
+// InstallOptions control Install.
+type InstallOptions struct {
+	Source     string
+	AllowHooks bool // enable the kit's hooks; they run commands in AI tools
+	Force      bool // reinstall the same version or downgrade
+	Staged     []string
+}
+
+// Dir returns the directory kits are installed in (~/.mur/kits/).
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.DataDir(home), "kits"), nil
+}
+
+// Install installs k, replacing an older version of it. Installing the
+// same or a newer installed version again needs Force.
+func Install(k *Kit, opts InstallOptions) (*Installed, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if prev, err := Get(k.Manifest.Name); err == nil && !opts.Force {
+		switch c := selfupdate.CompareVersions(k.Manifest.Version, prev.Manifest.Version); {
+		case c == 0:
+			return nil, fmt.Errorf("kit %s %s is already installed (use --force to reinstall)", k.Manifest.Name, prev.Manifest.Version)
+		case c < 0:
+			return nil, fmt.Errorf("kit %s %s is newer than %s (use --force to downgrade)", k.Manifest.Name, prev.Manifest.Version, k.Manifest.Version)
+		}
+	}
+
+	inst := &Installed{
+		Manifest:     k.Manifest,
+		Source:       opts.Source,
+		InstalledAt:  time.Now().UTC().Truncate(time.Second),
+		HooksEnabled: opts.AllowHooks && HookCount(k.Hooks) > 0,
+		Staged:       opts.Staged,
+	}
+	kitData, err := k.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	instData, err := yaml.Marshal(inst)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write next to the kit and swap it in, so a failed install leaves the
+	// previous version in place.
+	final := filepath.Join(dir, k.Manifest.Name)
+	tmp := filepath.Join(dir, "."+k.Manifest.Name+".tmp")
+	_ = os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create kit directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "kit.yaml"), kitData, 0644); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("cannot write kit: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "install.yaml"), instData, 0644); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("cannot write kit: %w", err)
+	}
+	if err := os.RemoveAll(final); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("cannot replace kit: %w", err)
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		return nil, fmt.Errorf("cannot install kit: %w", err)
+	}
+	return inst, nil
+}
+
+// Get returns an installed kit.
+func Get(name string) (*InstalledKit, error) {
+	if !kitNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid kit name %q", name)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return load(filepath.Join(dir, name))
+}
+
+func load(dir string) (*InstalledKit, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "install.yaml"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("kit not installed: %s", filepath.Base(dir))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ik InstalledKit
+	if err := yaml.Unmarshal(data, &ik.Installed); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", filepath.Join(dir, "install.yaml"), err)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "kit.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if ik.Kit, err = Parse(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, "kit.yaml"), err)
+	}
+	return &ik, nil
+}
+
+// List returns the installed kits, sorted by name.
+func List() ([]*InstalledKit, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var kits []*InstalledKit
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if ik, err := load(filepath.Join(dir, e.Name())); err == nil {
+			kits = append(kits, ik)
+		}
+	}
+	sort.Slice(kits, func(i, j int) bool { return kits[i].Manifest.Name < kits[j].Manifest.Name })
+	return kits, nil
+}
+
+// Remove uninstalls a kit. Patterns it staged are left for the caller to
+// discard.
+func Remove(name string) (*InstalledKit, error) {
+	ik, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	dir, _ := Dir()
+	if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+		return nil, fmt.Errorf("cannot remove kit: %w", err)
+	}
+	return ik, nil
+}
+
+// Profiles returns the context profiles of installed kits, named
+// <kit>/<profile>.
+func Profiles() map[string]config.ContextProfile {
+	kits, _ := List()
+	out := make(map[string]config.ContextProfile)
+	for _, ik := range kits {
+		for name, p := range ik.Kit.Profiles {
+			out[ik.Manifest.Name+"/"+name] = p
+		}
+	}
+	return out
+}
+
+// Template returns the source of a kit's context template, for a format
+// named <kit>/<template>.
+func Template(format string) (string, bool) {
+	name, tmpl, ok := strings.Cut(format, "/")
+	if !ok {
+		return "", false
+	}
+	ik, err := Get(name)
+	if err != nil {
+		return "", false
+	}
+	src, ok := ik.Kit.Templates[tmpl]
+	return src, ok
+}
+
+// Templates returns the context template formats of installed kits.
+func Templates() []string {
+	kits, _ := List()
+	var out []string
+	for _, ik := range kits {
+		for _, name := range sortedKeys(ik.Kit.Templates) {
+			out = append(out, ik.Manifest.Name+"/"+name)
+		}
+	}
+	return out
+}
+
+// Privacy adds the guardrails of installed kits to p: their redact terms,
+// and replacements for strings p doesn't already replace.
+func Privacy(p config.PrivacyConfig) config.PrivacyConfig {
+	kits, _ := List()
+	for _, ik := range kits {
+		g := ik.Kit.Guardrails
+		if !g.any() {
+			continue
+		}
+		for _, term := range g.RedactTerms {
+			if !contains(p.RedactTerms, term) {
+				p.RedactTerms = append(p.RedactTerms, term)
+			}
+		}
+		for from, to := range g.Replacements {
+			if _, ok := p.Replacements[from]; ok {
+				continue
+			}
+			replacements := make(map[string]string, len(p.Replacements)+1)
+			for k, v := range p.Replacements {
+				replacements[k] = v
+			}
+			replacements[from] = to
+			p.Replacements = replacements
+		}
+	}
+	return p
+}
+
+// Hooks adds the hooks of installed kits that were installed with hooks
+// allowed to h.
+func Hooks(h config.HooksConfig) config.HooksConfig {
+	kits, _ := List()
+	for _, ik := range kits {
+		if !ik.HooksEnabled {
+			continue
+		}
+		kh := ik.Kit.Hooks
+		h.UserPromptSubmit = append(h.UserPromptSubmit[:len(h.UserPromptSubmit):len(h.UserPromptSubmit)], kh.UserPromptSubmit...)
+		h.Stop = append(h.Stop[:len(h.Stop):len(h.Stop)], kh.Stop...)
+		h.BeforeTool = append(h.BeforeTool[:len(h.BeforeTool):len(h.BeforeTool)], kh.BeforeTool...)
+		h.AfterTool = append(h.AfterTool[:len(h.AfterTool):len(h.AfterTool)], kh.AfterTool...)
+	}
+	return h
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
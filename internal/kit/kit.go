@@ -0,0 +1,315 @@
+// Package kit bundles a learning setup into a shareable starter kit:
+// context profiles, context templates, guardrails (privacy redactions),
+// hook matchers, and patterns, with a versioned manifest (mur kit).
+//
+// Installed kits live in their own directory under ~/.mur/kits/ and are
+// read alongside the user's config instead of being merged into it, so
+// installing one never overwrites anything and removing it is clean. Kit
+// profiles and templates are namespaced as <kit>/<name>.
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// FileExt is the extension of exported kit files.
+const FileExt = ".kit.yaml"
+
+// maxKitSize caps a kit file, local or downloaded.
+const maxKitSize = 5 << 20
+
+var (
+	kitNameRe  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+	itemNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+	versionRe  = regexp.MustCompile(`^v?\d+(\.\d+){0,2}(-[0-9A-Za-z.-]+)?$`)
+)
+
+// Manifest identifies a kit and its version.
+type Manifest struct {
+	Name        string    `yaml:"name"`
+	Version     string    `yaml:"version"`
+	Description string    `yaml:"description,omitempty"`
+	Author      string    `yaml:"author,omitempty"`
+	Created     time.Time `yaml:"created"`
+	MurVersion  string    `yaml:"mur_version,omitempty"` // mur version that exported it
+}
+
+// Guardrails are privacy redactions applied before patterns leave the
+// machine (see privacy.redact_terms and privacy.replacements).
+type Guardrails struct {
+	RedactTerms  []string          `yaml:"redact_terms,omitempty"`
+	Replacements map[string]string `yaml:"replacements,omitempty"`
+}
+
+// Pattern is a pattern shipped in a kit. It is staged for review on
+// install, never added directly.
+type Pattern struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Content     string   `yaml:"content"`
+	Domain      string   `yaml:"domain,omitempty"`
+	Category    string   `yaml:"category,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+}
+
+// Kit is a starter kit file.
+type Kit struct {
+	Manifest   Manifest                         `yaml:"kit"`
+	Profiles   map[string]config.ContextProfile `yaml:"profiles,omitempty"`
+	Templates  map[string]string                `yaml:"templates,omitempty"` // context format name -> template source
+	Guardrails Guardrails                       `yaml:"guardrails,omitempty"`
+	Hooks      config.HooksConfig               `yaml:"hooks,omitempty"` // hook matchers synced to AI tools
+	Patterns   []Pattern                        `yaml:"patterns,omitempty"`
+}
+
+// Parse reads and validates a kit file.
+func Parse(data []byte) (*Kit, error) {
+	var k Kit
+	if err := yaml.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("not a kit file: %w", err)
+	}
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Marshal encodes the kit file.
+func (k *Kit) Marshal() ([]byte, error) {
+	return yaml.Marshal(k)
+}
+
+// Validate checks the manifest and the names of everything in the kit.
+func (k *Kit) Validate() error {
+	m := k.Manifest
+	if !kitNameRe.MatchString(m.Name) {
+		return fmt.Errorf("invalid kit name %q (lowercase letters, digits, and dashes, up to 40)", m.Name)
+	}
+	if !versionRe.MatchString(m.Version) {
+		return fmt.Errorf("invalid kit version %q (e.g. 1.0.0)", m.Version)
+	}
+	for name := range k.Profiles {
+		if !itemNameRe.MatchString(name) {
+			return fmt.Errorf("invalid profile name %q", name)
+		}
+	}
+	for name := range k.Templates {
+		if !itemNameRe.MatchString(name) {
+			return fmt.Errorf("invalid template name %q", name)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, p := range k.Patterns {
+		if !itemNameRe.MatchString(p.Name) {
+			return fmt.Errorf("invalid pattern name %q", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("pattern %q appears twice", p.Name)
+		}
+		seen[p.Name] = true
+		if strings.TrimSpace(p.Content) == "" {
+			return fmt.Errorf("pattern %q has no content", p.Name)
+		}
+	}
+	if k.Empty() {
+		return errors.New("kit is empty")
+	}
+	return nil
+}
+
+// Empty reports whether the kit bundles nothing.
+func (k *Kit) Empty() bool {
+	return len(k.Profiles) == 0 && len(k.Templates) == 0 && len(k.Patterns) == 0 &&
+		!k.Guardrails.any() && HookCount(k.Hooks) == 0
+}
+
+func (g Guardrails) any() bool {
+	return len(g.RedactTerms) > 0 || len(g.Replacements) > 0
+}
+
+// Contents describes what the kit bundles, e.g. "2 profiles".
+func (k *Kit) Contents() []string {
+	var out []string
+	add := func(n int, one, many string) {
+		switch {
+		case n == 1:
+			out = append(out, "1 "+one)
+		case n > 1:
+			out = append(out, fmt.Sprintf("%d %s", n, many))
+		}
+	}
+	add(len(k.Profiles), "profile", "profiles")
+	add(len(k.Templates), "template", "templates")
+	add(len(k.Guardrails.RedactTerms)+len(k.Guardrails.Replacements), "guardrail", "guardrails")
+	add(HookCount(k.Hooks), "hook", "hooks")
+	add(len(k.Patterns), "pattern", "patterns")
+	return out
+}
+
+// ProfileNames returns the names of the kit's profiles, sorted.
+func (k *Kit) ProfileNames() []string { return sortedKeys(k.Profiles) }
+
+// TemplateNames returns the names of the kit's templates, sorted.
+func (k *Kit) TemplateNames() []string { return sortedKeys(k.Templates) }
+
+// HookCount counts the hook commands in h.
+func HookCount(h config.HooksConfig) int {
+	n := 0
+	for _, groups := range [][]config.HookGroup{h.UserPromptSubmit, h.Stop, h.BeforeTool, h.AfterTool} {
+		for _, g := range groups {
+			n += len(g.Hooks)
+		}
+	}
+	return n
+}
+
+// HookCommands lists the kit's hook commands as "event [matcher]: command",
+// for review before they are enabled.
+func (k *Kit) HookCommands() []string {
+	var out []string
+	for _, ev := range []struct {
+		name   string
+		groups []config.HookGroup
+	}{
+		{"UserPromptSubmit", k.Hooks.UserPromptSubmit},
+		{"Stop", k.Hooks.Stop},
+		{"BeforeTool", k.Hooks.BeforeTool},
+		{"AfterTool", k.Hooks.AfterTool},
+	} {
+		for _, g := range ev.groups {
+			event := ev.name
+			if g.Matcher != "" {
+				event += " [" + g.Matcher + "]"
+			}
+			for _, h := range g.Hooks {
+				out = append(out, event+": "+h.Command)
+			}
+		}
+	}
+	return out
+}
+
+// ExportOptions selects what Build puts in a kit. Nil name lists mean
+// everything of that kind.
+type ExportOptions struct {
+	Manifest     Manifest
+	Profiles     []string
+	Templates    []string
+	TemplatesDir string // user context templates (~/.mur/templates/context)
+	Guardrails   bool
+	Hooks        bool
+	Patterns     []Pattern
+}
+
+// Build assembles a kit from the user's config and templates.
+func Build(cfg *config.Config, opts ExportOptions) (*Kit, error) {
+	k := &Kit{Manifest: opts.Manifest, Patterns: opts.Patterns}
+	if k.Manifest.Created.IsZero() {
+		k.Manifest.Created = time.Now().UTC().Truncate(time.Second)
+	}
+
+	profiles := opts.Profiles
+	if profiles == nil {
+		for name := range cfg.Context.Profiles {
+			profiles = append(profiles, name)
+		}
+	}
+	for _, name := range profiles {
+		p, ok := cfg.Context.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("no context profile %q in config", name)
+		}
+		if k.Profiles == nil {
+			k.Profiles = make(map[string]config.ContextProfile)
+		}
+		k.Profiles[name] = p
+	}
+
+	templates := opts.Templates
+	if templates == nil && opts.TemplatesDir != "" {
+		entries, _ := os.ReadDir(opts.TemplatesDir)
+		for _, e := range entries {
+			if name, ok := strings.CutSuffix(e.Name(), ".tmpl"); ok && !e.IsDir() {
+				templates = append(templates, name)
+			}
+		}
+	}
+	for _, name := range templates {
+		data, err := os.ReadFile(filepath.Join(opts.TemplatesDir, name+".tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("no context template %q: %w", name, err)
+		}
+		if k.Templates == nil {
+			k.Templates = make(map[string]string)
+		}
+		k.Templates[name] = string(data)
+	}
+
+	if opts.Guardrails {
+		k.Guardrails = Guardrails{RedactTerms: cfg.Privacy.RedactTerms, Replacements: cfg.Privacy.Replacements}
+	}
+	if opts.Hooks {
+		k.Hooks = cfg.Hooks
+	}
+
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Fetch reads a kit file from a path or an http(s) URL.
+func Fetch(src string) ([]byte, error) {
+	var r io.Reader
+	if strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot download kit: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("cannot download kit: %s", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxKitSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read kit: %w", err)
+	}
+	if len(data) > maxKitSize {
+		return nil, fmt.Errorf("kit is larger than %d MB", maxKitSize>>20)
+	}
+	return data, nil
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,155 @@
+package kit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func testKit(version string) *Kit {
+	return &Kit{
+		Manifest:  Manifest{Name: "team", Version: version},
+		Profiles:  map[string]config.ContextProfile{"oncall": {Description: "Incidents", Tags: []string{"incident"}}},
+		Templates: map[string]string{"terse": "{{range .Patterns}}{{.Name}}{{end}}"},
+		Guardrails: Guardrails{
+			RedactTerms:  []string{"acme"},
+			Replacements: map[string]string{"acme.internal": "example.com", "secret": "kit"},
+		},
+		Hooks: config.HooksConfig{Stop: []config.HookGroup{{Hooks: []config.Hook{{Type: "command", Command: "echo done"}}}}},
+	}
+}
+
+func TestParseValidates(t *testing.T) {
+	data, err := testKit("1.0.0").Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if k.Manifest.Name != "team" || len(k.Profiles) != 1 || HookCount(k.Hooks) != 1 {
+		t.Errorf("parsed kit = %+v", k)
+	}
+
+	for name, body := range map[string]string{
+		"empty":       "kit: {name: team, version: 1.0.0}\n",
+		"bad name":    "kit: {name: Team Kit, version: 1.0.0}\ntemplates: {t: x}\n",
+		"bad version": "kit: {name: team, version: latest}\ntemplates: {t: x}\n",
+		"bad profile": "kit: {name: team, version: 1.0.0}\nprofiles: {../x: {}}\n",
+		"no content":  "kit: {name: team, version: 1.0.0}\npatterns: [{name: p}]\n",
+	} {
+		if _, err := Parse([]byte(body)); err == nil {
+			t.Errorf("%s: Parse accepted %q", name, body)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "terse.tmpl"), []byte("T"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Context.Profiles = map[string]config.ContextProfile{"a": {}, "b": {}}
+	cfg.Privacy.RedactTerms = []string{"acme"}
+
+	k, err := Build(cfg, ExportOptions{Manifest: Manifest{Name: "team", Version: "1.0.0"}, TemplatesDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(k.Profiles) != 2 || k.Templates["terse"] != "T" || len(k.Guardrails.RedactTerms) != 0 {
+		t.Errorf("default build = %+v", k)
+	}
+
+	k, err = Build(cfg, ExportOptions{Manifest: Manifest{Name: "team", Version: "1.0.0"}, Profiles: []string{"a"}, Templates: []string{}, Guardrails: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(k.Profiles) != 1 || len(k.Templates) != 0 || len(k.Guardrails.RedactTerms) != 1 {
+		t.Errorf("selective build = %+v", k)
+	}
+
+	if _, err := Build(cfg, ExportOptions{Manifest: Manifest{Name: "team", Version: "1.0.0"}, Profiles: []string{"missing"}}); err == nil {
+		t.Error("Build accepted a missing profile")
+	}
+}
+
+func TestInstallVersions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	if _, err := Install(testKit("1.0.0"), InstallOptions{Source: "a.kit.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(testKit("1.0.0"), InstallOptions{}); err == nil {
+		t.Error("reinstalling the same version succeeded without Force")
+	}
+	if _, err := Install(testKit("0.9.0"), InstallOptions{}); err == nil {
+		t.Error("downgrade succeeded without Force")
+	}
+	if _, err := Install(testKit("1.1.0"), InstallOptions{}); err != nil {
+		t.Errorf("upgrade: %v", err)
+	}
+	if _, err := Install(testKit("0.9.0"), InstallOptions{Force: true}); err != nil {
+		t.Errorf("forced downgrade: %v", err)
+	}
+
+	ik, err := Get("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ik.Manifest.Version != "0.9.0" {
+		t.Errorf("installed version = %s", ik.Manifest.Version)
+	}
+	kits, _ := List()
+	if len(kits) != 1 {
+		t.Errorf("List = %d kits", len(kits))
+	}
+
+	if _, err := Remove("team"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("team"); err == nil {
+		t.Error("kit still installed after Remove")
+	}
+}
+
+func TestOverlays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	if _, err := Install(testKit("1.0.0"), InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := Profiles()["team/oncall"]; !ok {
+		t.Errorf("Profiles = %v", Profiles())
+	}
+	if src, ok := Template("team/terse"); !ok || src == "" {
+		t.Error("Template(team/terse) not found")
+	}
+	if _, ok := Template("terse"); ok {
+		t.Error("Template found an unnamespaced format")
+	}
+
+	p := Privacy(config.PrivacyConfig{RedactTerms: []string{"acme"}, Replacements: map[string]string{"secret": "mine"}})
+	if len(p.RedactTerms) != 1 {
+		t.Errorf("redact terms = %v", p.RedactTerms)
+	}
+	if p.Replacements["secret"] != "mine" || p.Replacements["acme.internal"] != "example.com" {
+		t.Errorf("replacements = %v", p.Replacements)
+	}
+
+	if h := Hooks(config.HooksConfig{}); HookCount(h) != 0 {
+		t.Error("hooks applied without AllowHooks")
+	}
+	if _, err := Install(testKit("1.0.0"), InstallOptions{AllowHooks: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if h := Hooks(config.HooksConfig{}); HookCount(h) != 1 {
+		t.Errorf("hooks = %+v", h)
+	}
+}
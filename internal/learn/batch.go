@@ -0,0 +1,165 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+const (
+	// llmRetries is how many times a rate-limited or overloaded LLM call
+	// is retried.
+	llmRetries = 3
+	// llmBackoff is the wait before the first retry; it doubles each time.
+	llmBackoff = 2 * time.Second
+)
+
+// DefaultRateLimit returns the requests per minute extraction sends to a
+// provider unless configured otherwise. Local Ollama isn't limited.
+func DefaultRateLimit(p LLMProvider) int {
+	switch p {
+	case LLMOllama:
+		return 0
+	case LLMGemini:
+		return 15
+	default:
+		return 50
+	}
+}
+
+// DefaultConcurrency returns how many sessions are extracted at once
+// unless configured otherwise. Ollama serves one request at a time by
+// default, so more workers would only queue.
+func DefaultConcurrency(p LLMProvider) int {
+	if p == LLMOllama {
+		return 1
+	}
+	return 4
+}
+
+// RateLimiter spaces out calls to one LLM provider and retries the ones
+// that were rate limited or hit an overloaded API, backing off
+// exponentially. It is shared by all workers calling that provider. A nil
+// limiter calls straight through.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // between call starts; 0 means no limit
+	next     time.Time     // earliest start of the next call
+	backoff  time.Duration // first retry wait
+	sleep    func(time.Duration)
+}
+
+// NewRateLimiter returns a limiter allowing perMinute calls a minute, or
+// any number when perMinute is 0.
+func NewRateLimiter(perMinute int) *RateLimiter {
+	l := &RateLimiter{backoff: llmBackoff, sleep: time.Sleep}
+	if perMinute > 0 {
+		l.interval = time.Minute / time.Duration(perMinute)
+	}
+	return l
+}
+
+// Do calls complete once a slot is free, retrying temporary API errors.
+func (l *RateLimiter) Do(complete func() (string, error)) (string, error) {
+	if l == nil {
+		return complete()
+	}
+	delay := l.backoff
+	for attempt := 0; ; attempt++ {
+		l.wait()
+		resp, err := complete()
+		var apiErr *session.APIError
+		if err == nil || attempt == llmRetries || !errors.As(err, &apiErr) || !apiErr.Temporary() {
+			return resp, err
+		}
+		// Hold back every worker, not only this one: the limit is per
+		// account, and the others would hit it too.
+		wait := delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+		l.pause(wait)
+		delay *= 2
+	}
+}
+
+// wait blocks until the next call may start and reserves its slot.
+func (l *RateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+	if d := time.Until(start); d > 0 {
+		l.sleep(d)
+	}
+}
+
+// pause pushes the next call back by at least d.
+func (l *RateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	if until := time.Now().Add(d); until.After(l.next) {
+		l.next = until
+	}
+	l.mu.Unlock()
+}
+
+// BatchResult is the outcome of extracting one session.
+type BatchResult struct {
+	Session  *Session
+	Patterns []ExtractedPattern
+	Err      error
+}
+
+// ExtractBatch runs extract on sessions with up to workers at once and
+// hands each result to handle in session order, on the calling goroutine,
+// so handle may print and prompt. It stops starting extractions once ctx
+// is done or handle returns false; calls already running are abandoned.
+func ExtractBatch(ctx context.Context, sessions []*Session, workers int, extract func(*Session) ([]ExtractedPattern, error), handle func(BatchResult) bool) {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// One buffered channel per session keeps results in order without
+	// blocking workers on a slow handler.
+	results := make([]chan BatchResult, len(sessions))
+	for i := range results {
+		results[i] = make(chan BatchResult, 1)
+	}
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range sessions {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for w := 0; w < workers && w < len(sessions); w++ {
+		go func() {
+			for i := range jobs {
+				patterns, err := extract(sessions[i])
+				results[i] <- BatchResult{Session: sessions[i], Patterns: patterns, Err: err}
+			}
+		}()
+	}
+
+	for i := range sessions {
+		select {
+		case r := <-results[i]:
+			if !handle(r) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
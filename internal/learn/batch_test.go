@@ -0,0 +1,106 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+func TestExtractBatch(t *testing.T) {
+	var sessions []*Session
+	for i := 0; i < 8; i++ {
+		sessions = append(sessions, &Session{ID: fmt.Sprint(i)})
+	}
+
+	var running, peak int32
+	extract := func(s *Session) ([]ExtractedPattern, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		// Earlier sessions take longer, so they finish out of order
+		time.Sleep(time.Duration(8-int(s.ID[0]-'0')) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if s.ID == "3" {
+			return nil, errors.New("boom")
+		}
+		return []ExtractedPattern{{Pattern: Pattern{Name: "p" + s.ID}}}, nil
+	}
+
+	var order []string
+	ExtractBatch(context.Background(), sessions, 3, extract, func(r BatchResult) bool {
+		order = append(order, r.Session.ID)
+		if (r.Err != nil) != (r.Session.ID == "3") {
+			t.Errorf("session %s: err = %v", r.Session.ID, r.Err)
+		}
+		return true
+	})
+	if fmt.Sprint(order) != "[0 1 2 3 4 5 6 7]" {
+		t.Errorf("results handled in order %v", order)
+	}
+	if peak < 2 || peak > 3 {
+		t.Errorf("peak concurrency = %d, want 2-3", peak)
+	}
+
+	order = nil
+	ExtractBatch(context.Background(), sessions, 2, extract, func(r BatchResult) bool {
+		order = append(order, r.Session.ID)
+		return r.Err == nil
+	})
+	if fmt.Sprint(order) != "[0 1 2 3]" {
+		t.Errorf("stopping at the failure handled %v", order)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(600) // one call per 100ms
+	var slept time.Duration
+	l.sleep = func(d time.Duration) { slept += d }
+	l.backoff = time.Millisecond
+
+	calls := 0
+	resp, err := l.Do(func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", &session.APIError{Provider: "anthropic", Status: 429}
+		}
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" || calls != 3 {
+		t.Fatalf("Do = %q, %v after %d calls; want ok after 3", resp, err, calls)
+	}
+	if slept < 150*time.Millisecond {
+		t.Errorf("calls weren't spaced out: slept %v", slept)
+	}
+
+	calls = 0
+	_, err = l.Do(func() (string, error) {
+		calls++
+		return "", &session.APIError{Provider: "openai", Status: 401}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("auth error retried: %d calls, err %v", calls, err)
+	}
+
+	calls = 0
+	_, err = l.Do(func() (string, error) {
+		calls++
+		return "", &session.APIError{Provider: "gemini", Status: 503}
+	})
+	if err == nil || calls != llmRetries+1 {
+		t.Errorf("overloaded API: %d calls, want %d", calls, llmRetries+1)
+	}
+
+	var none *RateLimiter
+	if resp, _ := none.Do(func() (string, error) { return "direct", nil }); resp != "direct" {
+		t.Error("nil limiter didn't call through")
+	}
+}
@@ -0,0 +1,251 @@
+package learn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Calibration defaults.
+const (
+	DefaultMinConfidence   = 0.6 // auto-accept threshold without enough history
+	DefaultTargetPrecision = 0.9
+	// calibrationMinSamples is how many reviewed patterns a provider or
+	// category needs before its threshold is calibrated.
+	calibrationMinSamples = 10
+	// calibrationMinAbove is how many reviews a threshold must be backed
+	// by, so one lucky accept doesn't set it.
+	calibrationMinAbove    = 5
+	minCalibratedThreshold = 0.3
+	maxCalibratedThreshold = 0.95
+)
+
+// Review history providers other than LLMs.
+const (
+	ReviewKeyword = "keyword" // keyword extraction without an LLM
+	ReviewImport  = "import"  // staged by mur import rules or mur learn cross
+)
+
+// ReviewDecision records a pattern someone accepted or rejected when
+// reviewing extracted or staged patterns.
+type ReviewDecision struct {
+	At         time.Time `json:"at"`
+	Provider   string    `json:"provider"` // extraction provider: ollama, claude, ..., keyword, import
+	Category   string    `json:"category,omitempty"`
+	Confidence float64   `json:"confidence"`
+	Accepted   bool      `json:"accepted"`
+}
+
+// ReviewHistoryPath returns the review history file.
+func ReviewHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "review-history.jsonl"), nil
+}
+
+// RecordReview appends a review decision to the history. Patterns without
+// a confidence say nothing about thresholds and are not recorded.
+func RecordReview(d ReviewDecision) error {
+	if d.Confidence <= 0 {
+		return nil
+	}
+	if d.At.IsZero() {
+		d.At = time.Now()
+	}
+	path, err := ReviewHistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReviewHistory returns every recorded review decision, oldest first.
+func ReviewHistory() ([]ReviewDecision, error) {
+	path, err := ReviewHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []ReviewDecision
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var d ReviewDecision
+		if json.Unmarshal(scanner.Bytes(), &d) == nil {
+			history = append(history, d)
+		}
+	}
+	return history, scanner.Err()
+}
+
+// Calibration is the auto-accept threshold for a provider and category.
+type Calibration struct {
+	Provider  string
+	Category  string // "" when calibrated across the provider's categories
+	Threshold float64
+	// Samples and Accepted count the reviews the threshold was computed
+	// from; Precision is the acceptance rate at or above it.
+	Samples    int
+	Accepted   int
+	Precision  float64
+	Calibrated bool // false when there was too little history
+	Fixed      bool // set explicitly with --min-confidence
+}
+
+// Calibrate returns the lowest threshold at which at least target of the
+// reviewed patterns were accepted. With too few reviews it returns
+// DefaultMinConfidence, uncalibrated.
+func Calibrate(history []ReviewDecision, target float64) Calibration {
+	c := Calibration{Threshold: DefaultMinConfidence, Samples: len(history)}
+	for _, d := range history {
+		if d.Accepted {
+			c.Accepted++
+		}
+	}
+	if len(history) < calibrationMinSamples {
+		return c
+	}
+
+	sorted := append([]ReviewDecision(nil), history...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Confidence > sorted[j].Confidence })
+
+	// Walk down from the most confident review; the last confidence where
+	// everything at or above it still meets the target is the threshold.
+	c.Calibrated = true
+	c.Threshold = maxCalibratedThreshold
+	c.Precision = 0
+	accepted := 0
+	for i, d := range sorted {
+		if d.Accepted {
+			accepted++
+		}
+		n := i + 1
+		if i+1 < len(sorted) && sorted[i+1].Confidence == d.Confidence {
+			continue // Ties are accepted or skipped together
+		}
+		precision := float64(accepted) / float64(n)
+		if n >= calibrationMinAbove && precision >= target {
+			c.Threshold = d.Confidence
+			c.Precision = precision
+		}
+	}
+	if c.Threshold < minCalibratedThreshold {
+		c.Threshold = minCalibratedThreshold
+	} else if c.Threshold > maxCalibratedThreshold {
+		c.Threshold = maxCalibratedThreshold
+	}
+	return c
+}
+
+// Thresholds picks the auto-accept threshold for extracted patterns from
+// the review history: per provider and category when there's enough of
+// it, else per provider, else DefaultMinConfidence.
+type Thresholds struct {
+	fixed   float64
+	target  float64
+	history []ReviewDecision
+	cache   map[[2]string]Calibration
+}
+
+// LoadThresholds reads the review history. A fixed threshold above zero,
+// from --min-confidence, overrides calibration.
+func LoadThresholds(cfg *config.Config, fixed float64) *Thresholds {
+	t := &Thresholds{fixed: fixed, target: DefaultTargetPrecision, cache: make(map[[2]string]Calibration)}
+	if cfg != nil && cfg.Learning.TargetPrecision > 0 {
+		t.target = cfg.Learning.TargetPrecision
+	}
+	if fixed <= 0 {
+		t.history, _ = ReviewHistory()
+	}
+	return t
+}
+
+// Target returns the precision thresholds are calibrated to.
+func (t *Thresholds) Target() float64 { return t.target }
+
+// For returns the threshold for patterns of category from provider.
+func (t *Thresholds) For(provider, category string) Calibration {
+	if t.fixed > 0 {
+		return Calibration{Provider: provider, Category: category, Threshold: t.fixed, Fixed: true}
+	}
+	key := [2]string{provider, category}
+	if c, ok := t.cache[key]; ok {
+		return c
+	}
+
+	var byProvider, byCategory []ReviewDecision
+	for _, d := range t.history {
+		if d.Provider != provider {
+			continue
+		}
+		byProvider = append(byProvider, d)
+		if d.Category == category {
+			byCategory = append(byCategory, d)
+		}
+	}
+	c := Calibrate(byCategory, t.target)
+	c.Category = category
+	if category == "" || !c.Calibrated {
+		c = Calibrate(byProvider, t.target)
+	}
+	c.Provider = provider
+	t.cache[key] = c
+	return c
+}
+
+// All returns the calibration of every provider and, where calibrated on
+// their own, category with review history, sorted by provider.
+func (t *Thresholds) All() []Calibration {
+	seen := make(map[[2]string]bool)
+	var out []Calibration
+	for _, d := range t.history {
+		for _, key := range [][2]string{{d.Provider, ""}, {d.Provider, d.Category}} {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			c := t.For(key[0], key[1])
+			if key[1] != "" && c.Category == "" {
+				continue // Falls back to the provider-wide threshold
+			}
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
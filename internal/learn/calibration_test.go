@@ -0,0 +1,95 @@
+package learn
+
+import (
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// reviews returns decisions at each confidence, accepted where marked.
+func reviews(provider, category string, confidences []float64, accepted []bool) []ReviewDecision {
+	out := make([]ReviewDecision, len(confidences))
+	for i, c := range confidences {
+		out[i] = ReviewDecision{Provider: provider, Category: category, Confidence: c, Accepted: accepted[i]}
+	}
+	return out
+}
+
+func TestCalibrate(t *testing.T) {
+	if c := Calibrate(reviews("ollama", "", []float64{0.9, 0.8}, []bool{true, true}), 0.9); c.Calibrated || c.Threshold != DefaultMinConfidence {
+		t.Errorf("few samples = %+v", c)
+	}
+
+	// Everything at 0.7 and above was accepted; below that mostly not.
+	mixed := reviews("ollama", "",
+		[]float64{0.95, 0.9, 0.85, 0.8, 0.75, 0.7, 0.65, 0.6, 0.55, 0.5, 0.45, 0.4},
+		[]bool{true, true, true, true, true, true, false, true, false, false, false, false})
+	c := Calibrate(mixed, 0.9)
+	if !c.Calibrated || c.Threshold != 0.7 || c.Precision != 1 || c.Samples != 12 || c.Accepted != 7 {
+		t.Errorf("mixed = %+v", c)
+	}
+	// A lower target lets the rejected 0.65 in.
+	if c := Calibrate(mixed, 0.8); c.Threshold != 0.6 {
+		t.Errorf("target 0.8 threshold = %v, want 0.6", c.Threshold)
+	}
+
+	// Ties stand or fall together.
+	tied := reviews("claude", "", []float64{0.9, 0.9, 0.9, 0.9, 0.9, 0.8, 0.8, 0.8, 0.8, 0.8},
+		[]bool{true, true, true, true, true, true, true, false, false, false})
+	if c := Calibrate(tied, 0.9); c.Threshold != 0.9 {
+		t.Errorf("tied threshold = %v, want 0.9", c.Threshold)
+	}
+
+	// Nothing ever reaches the target.
+	rejected := reviews("claude", "", []float64{0.9, 0.8, 0.7, 0.6, 0.5, 0.9, 0.8, 0.7, 0.6, 0.5},
+		[]bool{false, false, false, false, false, false, false, false, false, true})
+	if c := Calibrate(rejected, 0.9); !c.Calibrated || c.Threshold != maxCalibratedThreshold {
+		t.Errorf("unreachable = %+v", c)
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	// Twelve pattern reviews for ollama: accepted at 0.7 and above.
+	for i, conf := range []float64{0.95, 0.9, 0.85, 0.8, 0.75, 0.7, 0.65, 0.6, 0.55, 0.5, 0.45, 0.4} {
+		if err := RecordReview(ReviewDecision{Provider: "ollama", Category: "pattern", Confidence: conf, Accepted: i < 6}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Three debug reviews aren't enough on their own.
+	for _, conf := range []float64{0.5, 0.6, 0.7} {
+		_ = RecordReview(ReviewDecision{Provider: "ollama", Category: "debug", Confidence: conf, Accepted: true})
+	}
+	// No confidence, nothing to learn from.
+	_ = RecordReview(ReviewDecision{Provider: "ollama", Category: "debug", Accepted: false})
+
+	history, err := ReviewHistory()
+	if err != nil || len(history) != 15 {
+		t.Fatalf("history = %d entries, %v", len(history), err)
+	}
+
+	th := LoadThresholds(&config.Config{Learning: config.LearningConfig{TargetPrecision: 0.95}}, 0)
+	if th.Target() != 0.95 {
+		t.Errorf("target = %v", th.Target())
+	}
+	if c := th.For("ollama", "pattern"); c.Category != "pattern" || c.Threshold != 0.7 {
+		t.Errorf("pattern = %+v", c)
+	}
+	// debug falls back to all of ollama's reviews
+	if c := th.For("ollama", "debug"); c.Category != "" || !c.Calibrated {
+		t.Errorf("debug = %+v", c)
+	}
+	if c := th.For("claude", "pattern"); c.Calibrated || c.Threshold != DefaultMinConfidence {
+		t.Errorf("claude = %+v", c)
+	}
+	if all := th.All(); len(all) != 2 || all[0].Category != "" || all[1].Category != "pattern" {
+		t.Errorf("All = %+v", all)
+	}
+
+	fixed := LoadThresholds(nil, 0.8)
+	if c := fixed.For("ollama", "pattern"); !c.Fixed || c.Threshold != 0.8 {
+		t.Errorf("fixed = %+v", c)
+	}
+}
@@ -4,7 +4,9 @@ package learn
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -18,6 +20,7 @@ import (
 // CLISource represents an AI CLI tool as a learning source.
 type CLISource struct {
 	Name        string
+	Key         string // short name for --source, e.g. "gemini"
 	SessionDir  string // Path to session/history directory
 	FilePattern string // Glob pattern for session files
 	Parser      SessionParser
@@ -44,42 +47,49 @@ func DefaultCLISources() []CLISource {
 	return []CLISource{
 		{
 			Name:        "Claude Code",
-			SessionDir:  filepath.Join(home, ".claude", "projects"),
+			Key:         "claude",
+			SessionDir:  filepath.Join(config.ClaudeDir(home), "projects"),
 			FilePattern: "*/conversation.jsonl",
 			Parser:      &ClaudeParser{},
 		},
 		{
 			Name:        "Gemini CLI",
+			Key:         "gemini",
 			SessionDir:  filepath.Join(home, ".gemini", "history"),
 			FilePattern: "*.json",
 			Parser:      &GeminiParser{},
 		},
 		{
 			Name:        "Auggie",
+			Key:         "auggie",
 			SessionDir:  filepath.Join(home, ".augment", "sessions"),
 			FilePattern: "*.json",
 			Parser:      &AuggieParser{},
 		},
 		{
 			Name:        "Codex",
+			Key:         "codex",
 			SessionDir:  filepath.Join(home, ".codex", "history"),
 			FilePattern: "*.jsonl",
 			Parser:      &CodexParser{},
 		},
 		{
 			Name:        "Aider",
+			Key:         "aider",
 			SessionDir:  filepath.Join(home, ".aider", "history"),
 			FilePattern: "*.md",
 			Parser:      &AiderParser{},
 		},
 		{
 			Name:        "Continue",
+			Key:         "continue",
 			SessionDir:  filepath.Join(home, ".continue", "sessions"),
 			FilePattern: "*.json",
 			Parser:      &ContinueParser{},
 		},
 		{
 			Name:        "OpenClaw",
+			Key:         "openclaw",
 			SessionDir:  filepath.Join(home, ".openclaw", "agents", "main", "sessions"),
 			FilePattern: "*.jsonl",
 			Parser:      &OpenClawParser{},
@@ -87,17 +97,21 @@ func DefaultCLISources() []CLISource {
 	}
 }
 
+// ErrNoHistory is reported for a source whose CLI left no session history.
+var ErrNoHistory = errors.New("session directory not found")
+
 // CrossCLILearner extracts patterns from multiple CLI sources.
 type CrossCLILearner struct {
 	sources   []CLISource
 	extractor *suggest.Extractor
 	store     *pattern.Store
+	since     time.Time
 }
 
 // NewCrossCLILearner creates a new cross-CLI learner.
 func NewCrossCLILearner(store *pattern.Store) *CrossCLILearner {
 	home, _ := os.UserHomeDir()
-	suggestDir := filepath.Join(home, ".mur", "suggestions")
+	suggestDir := filepath.Join(config.DataDir(home), "suggestions")
 
 	return &CrossCLILearner{
 		sources:   DefaultCLISources(),
@@ -106,6 +120,22 @@ func NewCrossCLILearner(store *pattern.Store) *CrossCLILearner {
 	}
 }
 
+// Since limits learning to session files changed, and entries written,
+// at or after t.
+func (l *CrossCLILearner) Since(t time.Time) *CrossCLILearner {
+	l.since = t
+	return l
+}
+
+// SourceKeys returns the short names accepted by LearnFromSource.
+func SourceKeys() []string {
+	var keys []string
+	for _, s := range DefaultCLISources() {
+		keys = append(keys, s.Key)
+	}
+	return keys
+}
+
 // LearnResult holds the result of learning from CLI sources.
 type LearnResult struct {
 	Source      string
@@ -130,7 +160,7 @@ func (l *CrossCLILearner) LearnFromAll() ([]LearnResult, error) {
 // LearnFromSource extracts patterns from a specific CLI source.
 func (l *CrossCLILearner) LearnFromSource(name string) (*LearnResult, error) {
 	for _, source := range l.sources {
-		if strings.EqualFold(source.Name, name) {
+		if strings.EqualFold(source.Name, name) || strings.EqualFold(source.Key, name) {
 			result := l.learnFromSource(source)
 			return &result, result.Error
 		}
@@ -146,7 +176,7 @@ func (l *CrossCLILearner) learnFromSource(source CLISource) LearnResult {
 
 	// Check if directory exists
 	if _, err := os.Stat(source.SessionDir); os.IsNotExist(err) {
-		result.Error = fmt.Errorf("session directory not found: %s", source.SessionDir)
+		result.Error = fmt.Errorf("%w: %s", ErrNoHistory, source.SessionDir)
 		return result
 	}
 
@@ -158,16 +188,24 @@ func (l *CrossCLILearner) learnFromSource(source CLISource) LearnResult {
 		return result
 	}
 
-	result.FilesRead = len(files)
-
 	// Parse all sessions
 	var allEntries []SessionEntry
 	for _, f := range files {
+		if !l.since.IsZero() {
+			if info, err := os.Stat(f); err != nil || info.ModTime().Before(l.since) {
+				continue
+			}
+		}
+		result.FilesRead++
 		entries, err := source.Parser.Parse(f)
 		if err != nil {
 			continue
 		}
-		allEntries = append(allEntries, entries...)
+		for _, e := range entries {
+			if e.Timestamp.IsZero() || !e.Timestamp.Before(l.since) {
+				allEntries = append(allEntries, e)
+			}
+		}
 	}
 
 	result.Entries = len(allEntries)
@@ -0,0 +1,55 @@
+package learn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestCrossCLILearnerSourceKeyAndSince(t *testing.T) {
+	dir := t.TempDir()
+	session := `{"messages": [
+		{"role": "user", "content": "The docker build fails with permission denied on the socket"},
+		{"role": "assistant", "content": "Add your user to the docker group with usermod -aG docker, then log out and back in so the new group applies to your shell."},
+		{"role": "user", "content": "thanks, that works"}
+	]}`
+	for _, name := range []string{"old.json", "new.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(session), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.json"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewCrossCLILearner(pattern.NewStore(t.TempDir()))
+	l.sources = []CLISource{
+		{Name: "Gemini CLI", Key: "gemini", SessionDir: dir, FilePattern: "*.json", Parser: &GeminiParser{}},
+		{Name: "Aider", Key: "aider", SessionDir: filepath.Join(dir, "missing"), FilePattern: "*.md", Parser: &AiderParser{}},
+	}
+
+	r, err := l.LearnFromSource("gemini")
+	if err != nil {
+		t.Fatalf("LearnFromSource: %v", err)
+	}
+	if r.FilesRead != 2 || len(r.Suggestions) == 0 {
+		t.Errorf("read %d files, %d suggestions; want 2 files and some suggestions", r.FilesRead, len(r.Suggestions))
+	}
+
+	l.Since(time.Now().Add(-7 * 24 * time.Hour))
+	if r, _ := l.LearnFromSource("Gemini CLI"); r.FilesRead != 1 {
+		t.Errorf("with Since read %d files, want 1", r.FilesRead)
+	}
+
+	if _, err := l.LearnFromSource("aider"); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("missing history error = %v, want ErrNoHistory", err)
+	}
+	if _, err := l.LearnFromSource("nope"); err == nil {
+		t.Error("unknown source accepted")
+	}
+}
@@ -0,0 +1,101 @@
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/sysinfo"
+)
+
+// DeferredExtraction is a hook-triggered LLM extraction that was held back
+// to save battery or metered data. The scheduled 'mur sync' catches up once
+// the conditions clear.
+type DeferredExtraction struct {
+	DeferredAt time.Time `json:"deferred_at"`
+	Reason     string    `json:"reason"`
+	Provider   string    `json:"provider"`
+}
+
+// DeferredPath returns the catch-up queue file.
+func DeferredPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "extract-deferred.json"), nil
+}
+
+// DeferReason returns why cloud LLM extraction should wait under the
+// learning.defer_on_* settings ("on battery", "on a metered network"), or
+// "" when it can run now.
+func DeferReason(cfg *config.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	if cfg.Learning.DeferOnBattery && sysinfo.OnBattery() {
+		return "on battery"
+	}
+	if cfg.Learning.DeferOnMetered && sysinfo.OnMeteredNetwork() {
+		return "on a metered network"
+	}
+	return ""
+}
+
+// Deferred returns the queued extractions, oldest first.
+func Deferred() ([]DeferredExtraction, error) {
+	path, err := DeferredPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var queue []DeferredExtraction
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return queue, nil
+}
+
+// Defer queues an extraction for the catch-up run.
+func Defer(d DeferredExtraction) error {
+	queue, err := Deferred()
+	if err != nil {
+		queue = nil // Start over rather than lose the new entry
+	}
+	queue = append(queue, d)
+
+	path, err := DeferredPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ClearDeferred empties the queue. An extraction run covers every recent
+// session, so one that runs to the end catches up all deferred ones.
+func ClearDeferred() error {
+	path, err := DeferredPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
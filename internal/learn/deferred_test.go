@@ -0,0 +1,45 @@
+package learn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func TestDeferredQueue(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	if queue, err := Deferred(); err != nil || len(queue) != 0 {
+		t.Fatalf("empty queue = %v, %v", queue, err)
+	}
+	for _, reason := range []string{"on battery", "on a metered network"} {
+		if err := Defer(DeferredExtraction{DeferredAt: time.Now(), Reason: reason, Provider: "claude"}); err != nil {
+			t.Fatalf("Defer: %v", err)
+		}
+	}
+	queue, err := Deferred()
+	if err != nil || len(queue) != 2 || queue[0].Reason != "on battery" {
+		t.Fatalf("queue = %+v, %v", queue, err)
+	}
+
+	if err := ClearDeferred(); err != nil {
+		t.Fatalf("ClearDeferred: %v", err)
+	}
+	if queue, _ := Deferred(); len(queue) != 0 {
+		t.Errorf("queue not cleared: %+v", queue)
+	}
+	if err := ClearDeferred(); err != nil {
+		t.Errorf("ClearDeferred on empty queue: %v", err)
+	}
+}
+
+func TestDeferReasonDisabled(t *testing.T) {
+	if reason := DeferReason(nil); reason != "" {
+		t.Errorf("nil config deferred: %q", reason)
+	}
+	if reason := DeferReason(&config.Config{}); reason != "" {
+		t.Errorf("default config deferred: %q", reason)
+	}
+}
@@ -0,0 +1,150 @@
+package learn
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// ReadPatternFile returns the YAML of a pattern as stored, decompressed.
+func ReadPatternFile(name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	path, err := patternPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := pattern.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("pattern not found: %s", name)
+	}
+	return data, err
+}
+
+// EditProblems checks a pattern file edited by hand, before and after the
+// edit, and describes what is wrong with the edited one. Patterns in the
+// flat format 'mur learn add' writes and in the v2 schema are both
+// checked. A domain or category outside the usual ones is only a problem
+// if the edit introduced it.
+func EditProblems(name string, before, after []byte) []string {
+	var fields map[string]any
+	if err := yaml.Unmarshal(after, &fields); err != nil {
+		return []string{fmt.Sprintf("invalid YAML: %v", err)}
+	}
+	if fields == nil {
+		return []string{"the file is empty"}
+	}
+	var old map[string]any
+	_ = yaml.Unmarshal(before, &old)
+
+	var problems []string
+	switch got, _ := fields["name"].(string); {
+	case got == "":
+		problems = append(problems, "name: missing")
+	case got != name:
+		problems = append(problems, fmt.Sprintf("name: changed to %q; rename with 'mur learn rename %s %s'", got, name, got))
+	}
+	if content, _ := fields["content"].(string); strings.TrimSpace(content) == "" {
+		problems = append(problems, "content: missing")
+	}
+
+	for _, f := range []struct {
+		key   string
+		valid []string
+	}{
+		{"domain", ValidDomains()},
+		{"category", ValidCategories()},
+	} {
+		v, ok := fields[f.key]
+		if !ok {
+			continue
+		}
+		s, isString := v.(string)
+		if !isString {
+			problems = append(problems, fmt.Sprintf("%s: must be a string", f.key))
+			continue
+		}
+		if s == "" || slices.Contains(f.valid, s) || s == old[f.key] {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s: %q is not one of %s", f.key, s, strings.Join(f.valid, ", ")))
+	}
+
+	checkRange := func(key string, v any) {
+		var f float64
+		switch n := v.(type) {
+		case int:
+			f = float64(n)
+		case float64:
+			f = n
+		default:
+			problems = append(problems, fmt.Sprintf("%s: must be a number", key))
+			return
+		}
+		if f < 0 || f > 1 {
+			problems = append(problems, fmt.Sprintf("%s: %v is outside 0-1", key, f))
+		}
+	}
+	if v, ok := fields["confidence"]; ok {
+		checkRange("confidence", v)
+	}
+	if learning, ok := fields["learning"].(map[string]any); ok {
+		if v, ok := learning["effectiveness"]; ok {
+			checkRange("learning.effectiveness", v)
+		}
+	}
+	return problems
+}
+
+// SaveEdited stores a pattern edited by hand and keeps the version it
+// replaces as an "edit" revision. It reports whether the content changed,
+// which means the pattern's embedding is stale. after must have passed
+// EditProblems.
+func SaveEdited(name string, before, after []byte) (contentChanged bool, err error) {
+	dir, err := PatternsDir()
+	if err != nil {
+		return false, err
+	}
+	store := pattern.NewStore(dir)
+
+	var fields map[string]any
+	if err := yaml.Unmarshal(after, &fields); err != nil {
+		return false, fmt.Errorf("cannot parse pattern: %w", err)
+	}
+	if _, v2 := fields["schema_version"]; !v2 {
+		var p, old Pattern
+		if err := yaml.Unmarshal(after, &p); err != nil {
+			return false, fmt.Errorf("cannot parse pattern: %w", err)
+		}
+		_ = yaml.Unmarshal(before, &old)
+		if _, err := store.SaveRevision(name, before, "edit"); err != nil {
+			return false, err
+		}
+		return p.Content != old.Content, Add(p)
+	}
+
+	var p, old pattern.Pattern
+	if err := yaml.Unmarshal(after, &p); err != nil {
+		return false, fmt.Errorf("cannot parse pattern: %w", err)
+	}
+	_ = yaml.Unmarshal(before, &old)
+	contentChanged = p.Content != old.Content
+	if contentChanged && p.EmbeddingHash != "" {
+		p.UpdateEmbeddingHash()
+	}
+	// Update keeps the pre-edit file as a revision too; the duplicate
+	// isn't saved twice.
+	if _, err := store.SaveRevision(name, before, "edit"); err != nil {
+		return false, err
+	}
+	if err := store.Update(&p); err != nil {
+		return false, err
+	}
+	return contentChanged, nil
+}
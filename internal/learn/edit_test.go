@@ -0,0 +1,77 @@
+package learn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestEditProblems(t *testing.T) {
+	before := []byte("name: go-errors\ncontent: wrap with %w\ndomain: web\ncategory: debug\nconfidence: 0.8\n")
+
+	tests := []struct {
+		name  string
+		after string
+		want  string // substring of the only problem; empty for none
+	}{
+		{"valid", "name: go-errors\ncontent: wrap errors\ndomain: dev\ncategory: lesson\nconfidence: 1\n", ""},
+		{"unusual values kept", "name: go-errors\ncontent: wrap errors\ndomain: web\ncategory: debug\n", ""},
+		{"v2", "name: go-errors\ncontent: x\nschema_version: 2\nlearning:\n  effectiveness: 0.7\n", ""},
+		{"bad yaml", "name: [go-errors\n", "invalid YAML"},
+		{"renamed", "name: go-wrapping\ncontent: x\n", "mur learn rename go-errors go-wrapping"},
+		{"no content", "name: go-errors\ncontent: '  '\n", "content: missing"},
+		{"new domain", "name: go-errors\ncontent: x\ndomain: cooking\n", `domain: "cooking"`},
+		{"new category", "name: go-errors\ncontent: x\ncategory: misc\n", `category: "misc"`},
+		{"confidence range", "name: go-errors\ncontent: x\nconfidence: 1.5\n", "outside 0-1"},
+		{"confidence type", "name: go-errors\ncontent: x\nconfidence: high\n", "must be a number"},
+		{"effectiveness range", "name: go-errors\ncontent: x\nlearning:\n  effectiveness: -1\n", "learning.effectiveness"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EditProblems("go-errors", before, []byte(tt.after))
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("problems = %q, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("problems = %q, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveEdited(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	if err := Add(Pattern{Name: "go-errors", Content: "wrap with %w", Domain: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := ReadPatternFile("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := strings.Replace(string(before), "wrap with %w", "wrap with %w, not %v", 1)
+	after = strings.Replace(after, "confidence: 0.5", "confidence: 0.9", 1)
+
+	changed, err := SaveEdited("go-errors", before, []byte(after))
+	if err != nil || !changed {
+		t.Fatalf("SaveEdited = %v, %v; want content changed", changed, err)
+	}
+	p, err := Get("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Content != "wrap with %w, not %v" || p.Confidence != 0.9 {
+		t.Errorf("saved pattern = %+v", p)
+	}
+
+	dir, _ := PatternsDir()
+	revs, err := pattern.NewStore(dir).History("go-errors")
+	if err != nil || len(revs) != 1 || revs[0].Reason != "edit" {
+		t.Errorf("history = %+v, %v; want one edit revision", revs, err)
+	}
+}
@@ -12,10 +12,11 @@ import (
 
 // ExtractedPattern represents a potential pattern found in a session.
 type ExtractedPattern struct {
-	Pattern    Pattern  // The pattern to potentially save
-	Source     string   // Session ID
-	Evidence   []string // Relevant snippets that support this pattern
-	Confidence float64  // Extraction confidence
+	Pattern      Pattern       // The pattern to potentially save
+	Source       string        // Session ID
+	Evidence     []string      // Relevant snippets that support this pattern
+	Confidence   float64       // Extraction confidence
+	CodeProblems []CodeProblem // Code blocks that failed to parse
 }
 
 // PatternMatcher defines how to detect a pattern type.
@@ -86,7 +87,7 @@ func ExtractFromSession(sessionPath string) ([]ExtractedPattern, error) {
 		return nil, err
 	}
 
-	return ExtractFromMessages(session.AssistantMessages(), session.ShortID())
+	return ExtractFromSessionData(session)
 }
 
 // JSONPattern represents a pattern in JSON format from Claude's response.
@@ -286,6 +287,7 @@ func ExtractFromMessages(messages []SessionMessage, sourceID string) ([]Extracte
 			hash := hashContent(ep.Pattern.Content)
 			if !seen[hash] {
 				seen[hash] = true
+				ep.Pattern.Source = msg.sourceRef(sourceID, msg.Content)
 				extracted = append(extracted, ep)
 			}
 		}
@@ -337,6 +339,7 @@ func ExtractFromMessages(messages []SessionMessage, sourceID string) ([]Extracte
 					Domain:      matcher.Domain,
 					Category:    matcher.Category,
 					Confidence:  confidence,
+					Source:      msg.sourceRef(sourceID, para),
 					CreatedAt:   time.Now().Format(time.RFC3339),
 					UpdatedAt:   time.Now().Format(time.RFC3339),
 				}
@@ -0,0 +1,162 @@
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// extractStateMaxAge is how long a session's watermark is kept after it
+// was last extracted; RecentSessions never goes back further.
+const extractStateMaxAge = 30 * 24 * time.Hour
+
+// SessionMark is how far extraction has read a session file.
+type SessionMark struct {
+	Line     int       `json:"line"`     // last processed line of the transcript
+	Last     time.Time `json:"last"`     // timestamp of the last processed message
+	Messages int       `json:"messages"` // messages processed
+	Size     int64     `json:"size"`     // file size when processed
+	At       time.Time `json:"at"`       // when it was processed
+}
+
+// ExtractState is the extraction watermark: per session file, the last
+// message 'mur learn extract --auto' processed, so later runs only read
+// what was added since.
+type ExtractState struct {
+	path     string
+	Sessions map[string]SessionMark `json:"sessions"` // by transcript path
+}
+
+// ExtractStatePath returns the extraction state file.
+func ExtractStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "extract-state.json"), nil
+}
+
+// LoadExtractState reads the extraction watermark. A missing file means
+// nothing was processed yet.
+func LoadExtractState() (*ExtractState, error) {
+	path, err := ExtractStatePath()
+	if err != nil {
+		return nil, err
+	}
+	st := &ExtractState{path: path, Sessions: make(map[string]SessionMark)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("cannot read extraction state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return st, fmt.Errorf("cannot parse extraction state: %w", err)
+	}
+	if st.Sessions == nil {
+		st.Sessions = make(map[string]SessionMark)
+	}
+	return st, nil
+}
+
+// Save writes the watermark, dropping sessions that are gone or were
+// last processed longer ago than any run looks back.
+func (st *ExtractState) Save() error {
+	for path, m := range st.Sessions {
+		if _, err := os.Stat(path); err != nil || time.Since(m.At) > extractStateMaxAge {
+			delete(st.Sessions, path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(st.path, append(data, '\n'), 0644)
+}
+
+// Unprocessed returns the part of s added since it was last marked: its
+// new messages and tool calls. The result has no messages when there is
+// nothing new. A transcript that shrank was rewritten; its mark is
+// dropped and it is read again from the start.
+func (st *ExtractState) Unprocessed(s *Session) *Session {
+	m, ok := st.Sessions[s.Path]
+	if !ok {
+		return s
+	}
+	if info, err := os.Stat(s.Path); err == nil && info.Size() < m.Size {
+		delete(st.Sessions, s.Path)
+		return s
+	}
+
+	window := *s
+	window.Messages = nil
+	for i, msg := range s.Messages {
+		if m.after(i, s.Path, msg.Pos, msg.Timestamp) {
+			window.Messages = append(window.Messages, msg)
+		}
+	}
+	window.ToolEvents = nil
+	for _, ev := range s.ToolEvents {
+		if m.after(-1, s.Path, ev.Pos, ev.Timestamp) {
+			window.ToolEvents = append(window.ToolEvents, ev)
+		}
+	}
+	return &window
+}
+
+// after reports whether a message or tool call at pos comes after the
+// mark. Lines only compare within the session's own transcript; subagent
+// transcripts go by timestamp, and transcripts without either by index.
+func (m SessionMark) after(i int, path string, pos Position, ts time.Time) bool {
+	switch {
+	case pos.Path == path && pos.Line > 0:
+		return pos.Line > m.Line
+	case !ts.IsZero():
+		return ts.After(m.Last)
+	default:
+		return i >= m.Messages
+	}
+}
+
+// Mark records that s, a session or the window Unprocessed returned for
+// it, has been processed.
+func (st *ExtractState) Mark(s *Session) {
+	m := st.Sessions[s.Path]
+	m.Messages += len(s.Messages)
+	m.At = time.Now()
+	if info, err := os.Stat(s.Path); err == nil {
+		m.Size = info.Size()
+	}
+	for _, msg := range s.Messages {
+		m.advance(s.Path, msg.Pos, msg.Timestamp)
+	}
+	for _, ev := range s.ToolEvents {
+		m.advance(s.Path, ev.Pos, ev.Timestamp)
+	}
+	st.Sessions[s.Path] = m
+}
+
+func (m *SessionMark) advance(path string, pos Position, ts time.Time) {
+	if pos.Path == path && pos.Line > m.Line {
+		m.Line = pos.Line
+	}
+	if ts.After(m.Last) {
+		m.Last = ts
+	}
+}
+
+// Reset forgets every mark, so all sessions are read in full.
+func (st *ExtractState) Reset() {
+	st.Sessions = make(map[string]SessionMark)
+}
+
+// Len returns the number of sessions with a watermark.
+func (st *ExtractState) Len() int { return len(st.Sessions) }
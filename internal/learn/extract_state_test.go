@@ -0,0 +1,64 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractStateWatermark(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	path := filepath.Join(t.TempDir(), "proj", "abc12345.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	appendMessages(t, path, 4)
+
+	st, err := LoadExtractState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := st.Unprocessed(s); len(w.Messages) != 4 {
+		t.Fatalf("first run window = %d messages, want all 4", len(w.Messages))
+	}
+	st.Mark(s)
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later run only sees what was appended
+	appendMessages(t, path, 3)
+	st, _ = LoadExtractState()
+	s, _ = LoadSession(path)
+	w := st.Unprocessed(s)
+	if len(w.Messages) != 3 || w.Messages[0].Pos.Line != 5 {
+		t.Fatalf("window = %d messages from line %d, want 3 from line 5", len(w.Messages), w.Messages[0].Pos.Line)
+	}
+	st.Mark(w)
+	if w := st.Unprocessed(s); len(w.Messages) != 0 {
+		t.Errorf("nothing new, but window has %d messages", len(w.Messages))
+	}
+	if m := st.Sessions[path]; m.Messages != 7 || m.Line != 7 {
+		t.Errorf("mark = %+v, want 7 messages to line 7", m)
+	}
+
+	// A rewritten (shorter) transcript is read again from the start
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	appendMessages(t, path, 2)
+	s, _ = LoadSession(path)
+	if w := st.Unprocessed(s); len(w.Messages) != 2 {
+		t.Errorf("rewritten transcript window = %d messages, want 2", len(w.Messages))
+	}
+
+	st.Reset()
+	if st.Len() != 0 {
+		t.Error("Reset kept marks")
+	}
+}
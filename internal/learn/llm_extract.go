@@ -30,6 +30,12 @@ type LLMExtractOptions struct {
 	OpenAIURL   string // default: https://api.openai.com/v1 (or any compatible endpoint)
 	GeminiKey   string // from env GEMINI_API_KEY
 	MaxPatterns int    // max patterns to extract per session
+	// Rejected patterns are listed in the prompt, and suggestions that
+	// repeat them are dropped. May be nil.
+	Rejected *Rejections
+	// Limiter paces calls to the provider and retries rate-limited ones.
+	// May be nil.
+	Limiter *RateLimiter
 }
 
 // DefaultLLMOptions returns sensible defaults.
@@ -140,6 +146,14 @@ func ExtractWithLLM(session *Session, opts LLMExtractOptions) ([]ExtractedPatter
 		transcript.WriteString(fmt.Sprintf("### %s:\n%s\n\n", role, msg.Content))
 	}
 
+	// Commands that failed and how they were fixed, including by subagents.
+	// Kept last so truncation doesn't drop it.
+	if activity := session.ToolActivity(); activity != "" {
+		transcript.WriteString("### Tool activity (failed command → fix → success):\n")
+		transcript.WriteString(activity)
+		transcript.WriteString("\n")
+	}
+
 	// Truncate if too long (keep last 20k chars for context)
 	text := transcript.String()
 	if len(text) > 20000 {
@@ -153,9 +167,15 @@ func ExtractWithLLM(session *Session, opts LLMExtractOptions) ([]ExtractedPatter
 	}
 
 	// Compose full prompt with extraction instructions + transcript
-	fullPrompt := extractionPrompt + "\n\n---\n\nExtract patterns from this coding session:\n\n" + text
+	fullPrompt := extractionPrompt
+	if opts.Rejected != nil {
+		if section := opts.Rejected.PromptSection(); section != "" {
+			fullPrompt += "\n\n" + section
+		}
+	}
+	fullPrompt += "\n\n---\n\nExtract patterns from this coding session:\n\n" + text
 
-	response, err := provider.Complete(fullPrompt)
+	response, err := opts.Limiter.Do(func() (string, error) { return provider.Complete(fullPrompt) })
 	if err != nil {
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
@@ -168,6 +188,12 @@ func ExtractWithLLM(session *Session, opts LLMExtractOptions) ([]ExtractedPatter
 		patterns = parseJSONArray(response, session.ShortID())
 	}
 
+	if opts.Rejected != nil {
+		patterns = opts.Rejected.Filter(patterns)
+	}
+
+	ValidateExtracted(patterns)
+	session.attachSource(patterns)
 	return patterns, nil
 }
 
@@ -0,0 +1,53 @@
+package learn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// mergePrompt asks the LLM to fold duplicate patterns into one.
+const mergePrompt = `You maintain a developer's library of coding patterns. The patterns
+below say the same thing in different words. Merge them into one pattern
+that keeps every distinct, useful detail (commands, versions, caveats,
+examples) once, and drops repetition.
+
+Write it in the style of the first pattern. Output only the merged
+pattern content as markdown, with no preamble and no code fence around
+the whole answer.`
+
+// MergeWithLLM asks an LLM to merge the content of keep and dups into one
+// pattern's content.
+func MergeWithLLM(keep *pattern.Pattern, dups []*pattern.Pattern, opts LLMExtractOptions) (string, error) {
+	provider, err := llmProviderFromOptions(opts)
+	if err != nil {
+		return "", fmt.Errorf("LLM setup failed: %w", err)
+	}
+	var sb strings.Builder
+	sb.WriteString(mergePrompt)
+	for i, p := range append([]*pattern.Pattern{keep}, dups...) {
+		fmt.Fprintf(&sb, "\n\n--- Pattern %d: %s ---\n", i+1, p.Name)
+		if p.Description != "" {
+			fmt.Fprintf(&sb, "Description: %s\n", p.Description)
+		}
+		sb.WriteString(p.Content)
+	}
+	response, err := provider.Complete(sb.String())
+	if err != nil {
+		return "", fmt.Errorf("LLM call failed: %w", err)
+	}
+	return parseMergedContent(response)
+}
+
+// parseMergedContent strips a code fence wrapped around the whole answer.
+func parseMergedContent(response string) (string, error) {
+	content := strings.TrimSpace(response)
+	if strings.HasPrefix(content, "```") && strings.HasSuffix(content, "```") && len(content) > 6 {
+		content = strings.TrimSpace(strings.TrimSuffix(content[strings.IndexByte(content+"\n", '\n'):], "```"))
+	}
+	if content == "" {
+		return "", fmt.Errorf("LLM returned no merged content")
+	}
+	return content, nil
+}
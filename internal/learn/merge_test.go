@@ -0,0 +1,22 @@
+package learn
+
+import "testing"
+
+func TestParseMergedContent(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"# Wrap errors\n\nUse %w.\n", "# Wrap errors\n\nUse %w."},
+		{"```markdown\n# Wrap errors\n\n```go\nfmt.Errorf(\"x: %w\", err)\n```\n```", "# Wrap errors\n\n```go\nfmt.Errorf(\"x: %w\", err)\n```"},
+		{"```go\nx := 1\n```", "x := 1"},
+	}
+	for _, tt := range tests {
+		got, err := parseMergedContent(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseMergedContent(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseMergedContent("  \n"); err == nil {
+		t.Error("expected an error for an empty response")
+	}
+}
@@ -0,0 +1,131 @@
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// genericNameWords are words extraction falls back on when it can't tell
+// what a pattern is about. A name made only of these (and numbers or
+// hashes) says nothing about the pattern.
+var genericNameWords = map[string]bool{
+	"pattern": true, "patterns": true, "lesson": true, "decision": true,
+	"template": true, "debug": true, "debugging": true, "solution": true,
+	"fix": true, "issue": true, "problem": true, "error": true,
+	"rule": true, "note": true, "tip": true, "misc": true,
+	"general": true, "untitled": true, "new": true, "the": true,
+	"a": true, "an": true, "to": true, "of": true, "and": true,
+}
+
+// hashPartRe matches a name part that looks like a hash or counter, such
+// as the "3f2a" in "debugging-solution-3f2a".
+var hashPartRe = regexp.MustCompile(`^(?:[0-9]+|[0-9a-f]*[0-9][0-9a-f]*)$`)
+
+// IsPoorName reports whether a pattern name says little about the
+// pattern: it ends in a hash or counter, or is made only of generic words
+// like "debugging" and "solution".
+func IsPoorName(name string) bool {
+	parts := strings.Split(strings.ToLower(name), "-")
+	if len(parts) > 1 && len(parts[len(parts)-1]) >= 4 && hashPartRe.MatchString(parts[len(parts)-1]) {
+		return true
+	}
+	for _, part := range parts {
+		if part != "" && !genericNameWords[part] && !hashPartRe.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeName turns s into a valid kebab-case pattern name of at most
+// 48 characters, or "" if nothing usable is left.
+func NormalizeName(s string) string {
+	name := strings.Trim(nonSlugRe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	for len(name) > 48 {
+		i := strings.LastIndexByte(name[:48], '-')
+		if i <= 0 {
+			name = name[:48]
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
+
+// namingPrompt asks the LLM for names for one pattern.
+const namingPrompt = `You name entries in a developer's library of coding patterns.
+
+Suggest 3 names for the pattern below, best first. Each name:
+- is kebab-case, 2-5 words, at most 48 characters
+- says what the pattern is about: the tool, error, or technique, e.g. "swiftui-sheet-in-menubarextra" or "go-wrap-errors-with-context"
+- avoids filler words like "pattern", "solution", "debugging", "how-to", and numbers or hashes
+
+Output only a JSON array of strings.`
+
+// SuggestNamesWithLLM asks an LLM for up to three names for p, best
+// first. Names already taken (per taken) and names equal to the current
+// one are dropped.
+func SuggestNamesWithLLM(p *pattern.Pattern, opts LLMExtractOptions, taken func(string) bool) ([]string, error) {
+	provider, err := llmProviderFromOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("LLM setup failed: %w", err)
+	}
+	response, err := provider.Complete(namingPrompt + "\n\n---\n\n" + namingSummary(p))
+	if err != nil {
+		return nil, fmt.Errorf("LLM call failed: %w", err)
+	}
+	return parseNameSuggestions(response, p.Name, taken)
+}
+
+// namingSummary is what the LLM sees of a pattern.
+func namingSummary(p *pattern.Pattern) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Current name: %s\n", p.Name)
+	if p.Description != "" {
+		fmt.Fprintf(&sb, "Description: %s\n", p.Description)
+	}
+	if tags := p.Tags.Confirmed; len(tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n", strings.Join(tags, ", "))
+	}
+	content := p.Content
+	if len(content) > 2000 {
+		content = content[:2000]
+	}
+	sb.WriteString("Content:\n" + content)
+	return sb.String()
+}
+
+// parseNameSuggestions parses the JSON array returned for namingPrompt.
+func parseNameSuggestions(response, current string, taken func(string) bool) ([]string, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("LLM response has no JSON array")
+	}
+	var raw []string
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("cannot parse LLM response: %w", err)
+	}
+
+	var names []string
+	seen := map[string]bool{current: true}
+	for _, r := range raw {
+		name := NormalizeName(r)
+		if name == "" || seen[name] || (taken != nil && taken(name)) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if len(names) == 3 {
+			break
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("LLM suggested no usable names")
+	}
+	return names, nil
+}
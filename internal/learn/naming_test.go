@@ -0,0 +1,64 @@
+package learn
+
+import "testing"
+
+func TestIsPoorName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"debugging-solution-3f2a", true},
+		{"pattern-a1b2c3", true},
+		{"lesson-2", true},
+		{"debugging-solution", true},
+		{"swift-async-error-handling", false},
+		{"go-wrap-errors", false},
+		{"ipv6-dual-stack", false},
+		{"use-sha256-for-cache-keys", false},
+	}
+	for _, tt := range tests {
+		if got := IsPoorName(tt.name); got != tt.want {
+			t.Errorf("IsPoorName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Go Wrap Errors", "go-wrap-errors"},
+		{"  swiftui_sheet--in MenuBarExtra!", "swiftui-sheet-in-menubarextra"},
+		{"a-very-long-name-that-keeps-going-well-past-the-limit-of-names", "a-very-long-name-that-keeps-going-well-past-the"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.in); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseNameSuggestions(t *testing.T) {
+	taken := func(name string) bool { return name == "launchd-ulimit" }
+	response := "Here you go:\n[\"Launchd Open File Limit\", \"launchd-ulimit\", \"debugging-solution-3f2a\", \"launchd-open-file-limit\", \"raise-maxfiles\", \"extra-one\"]"
+
+	got, err := parseNameSuggestions(response, "debugging-solution-3f2a", taken)
+	if err != nil {
+		t.Fatalf("parseNameSuggestions: %v", err)
+	}
+	want := []string{"launchd-open-file-limit", "raise-maxfiles", "extra-one"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+
+	if _, err := parseNameSuggestions(`["debugging-solution-3f2a"]`, "debugging-solution-3f2a", nil); err == nil {
+		t.Error("expected an error when no usable names are left")
+	}
+}
@@ -3,27 +3,31 @@ package learn
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
 // Pattern represents a learned pattern.
 type Pattern struct {
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	Content     string   `yaml:"content"`
-	Domain      string   `yaml:"domain"`      // dev, devops, business
-	Category    string   `yaml:"category"`    // pattern, decision, lesson
-	Tags        []string `yaml:"tags"`        // pattern tags for categorization
-	Confidence  float64  `yaml:"confidence"`  // 0.0 - 1.0
-	TeamShared  bool     `yaml:"team_shared"` // share to team repo
-	CreatedAt   string   `yaml:"created_at"`
-	UpdatedAt   string   `yaml:"updated_at"`
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description"`
+	Content     string             `yaml:"content"`
+	Domain      string             `yaml:"domain"`              // dev, devops, business
+	Category    string             `yaml:"category"`            // pattern, decision, lesson
+	Tags        []string           `yaml:"tags"`                // pattern tags for categorization
+	Confidence  float64            `yaml:"confidence"`          // 0.0 - 1.0
+	TeamShared  bool               `yaml:"team_shared"`         // share to team repo
+	Validated   *bool              `yaml:"validated,omitempty"` // code blocks parse; unset if none were checked
+	Source      *pattern.SourceRef `yaml:"source,omitempty"`    // transcript excerpt it was extracted from
+	CreatedAt   string             `yaml:"created_at"`
+	UpdatedAt   string             `yaml:"updated_at"`
 }
 
 // ValidDomains returns the list of valid domains.
@@ -42,7 +46,7 @@ func PatternsDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "patterns"), nil
+	return filepath.Join(config.DataDir(home), "patterns"), nil
 }
 
 // ensureDir creates the patterns directory if it doesn't exist.
@@ -54,13 +58,25 @@ func ensureDir() error {
 	return os.MkdirAll(dir, 0755)
 }
 
-// patternPath returns the file path for a pattern.
+// patternPath returns the file path for a pattern, which is compressed
+// if only a .yaml.zst exists.
 func patternPath(name string) (string, error) {
 	dir, err := PatternsDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, name+".yaml"), nil
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if zst := filepath.Join(dir, name+pattern.CompressedExt); fileExists(zst) {
+			return zst, nil
+		}
+	}
+	return path, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
 
 // validateName checks if a pattern name is valid.
@@ -91,7 +107,7 @@ func List() ([]Pattern, error) {
 
 	// Also check ~/.mur/repo/patterns/
 	home, _ := os.UserHomeDir()
-	repoDir := filepath.Join(home, ".mur", "repo", "patterns")
+	repoDir := filepath.Join(config.DataDir(home), "repo", "patterns")
 	patterns = append(patterns, listFromDir(repoDir)...)
 
 	return patterns, nil
@@ -110,12 +126,12 @@ func listFromDir(dir string) []Pattern {
 
 	var patterns []Pattern
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !pattern.IsPatternFile(entry.Name()) {
 			continue
 		}
 
 		path := filepath.Join(dir, entry.Name())
-		data, err := os.ReadFile(path)
+		data, err := pattern.ReadFile(path)
 		if err != nil {
 			continue
 		}
@@ -141,7 +157,7 @@ func Get(name string) (*Pattern, error) {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(path)
+	data, err := pattern.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("pattern not found: %s", name)
@@ -169,10 +185,10 @@ func Add(p Pattern) error {
 
 	// Set timestamps
 	now := time.Now().Format(time.RFC3339)
+	existing, getErr := Get(p.Name)
 	if p.CreatedAt == "" {
 		// Check if updating existing pattern
-		existing, err := Get(p.Name)
-		if err == nil {
+		if getErr == nil {
 			p.CreatedAt = existing.CreatedAt
 		} else {
 			p.CreatedAt = now
@@ -193,24 +209,52 @@ func Add(p Pattern) error {
 		p.Category = "pattern"
 	}
 
+	// golang → go and the like (tags.aliases)
+	if cfg, err := config.Load(); err == nil {
+		p.Tags = pattern.NormalizeTagList(p.Tags, pattern.TagAliasesFromConfig(cfg))
+	}
+
 	path, err := patternPath(p.Name)
 	if err != nil {
 		return err
 	}
 
+	// Oversized content is split or truncated (storage.max_content_bytes)
+	if getErr != nil || existing.Content != p.Content {
+		if p.Content, _, err = pattern.NewStore(filepath.Dir(path)).FitContent(p.Name, p.Content); err != nil {
+			return err
+		}
+	}
+
 	data, err := yaml.Marshal(p)
 	if err != nil {
 		return fmt.Errorf("cannot serialize pattern: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// Keep the version being replaced; see 'mur learn history'
+	if getErr == nil && (existing.Content != p.Content || existing.Description != p.Description) {
+		if old, err := pattern.ReadFile(path); err == nil {
+			if _, err := pattern.NewStore(filepath.Dir(path)).SaveRevision(p.Name, old, "learn"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Written plain; 'mur migrate compress' compresses it again if needed.
+	plain := filepath.Join(filepath.Dir(path), p.Name+".yaml")
+	if err := os.WriteFile(plain, data, 0644); err != nil {
 		return fmt.Errorf("cannot write pattern: %w", err)
 	}
+	if plain != path {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove compressed pattern: %w", err)
+		}
+	}
 
 	return nil
 }
 
-// Delete removes a pattern.
+// Delete moves a pattern to the trash (see pattern.Store.Delete).
 func Delete(name string) error {
 	if err := validateName(name); err != nil {
 		return err
@@ -225,9 +269,5 @@ func Delete(name string) error {
 		return fmt.Errorf("pattern not found: %s", name)
 	}
 
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("cannot delete pattern: %w", err)
-	}
-
-	return nil
+	return pattern.NewStore(filepath.Dir(path)).Delete(name)
 }
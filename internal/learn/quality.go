@@ -22,6 +22,7 @@ type ExtractionConfig struct {
 	MinContentLength    int      // Minimum pattern content length
 	RequireProblemSolve bool     // Require problem/solution structure
 	RejectKeywords      []string // Keywords that indicate generic content
+	RejectInvalidCode   bool     // Reject patterns whose shell/Go blocks don't parse
 }
 
 // DefaultExtractionConfig returns sensible defaults.
@@ -32,6 +33,7 @@ func DefaultExtractionConfig() ExtractionConfig {
 		MaxAssistantRatio:   0.85,
 		MinContentLength:    100,
 		RequireProblemSolve: true,
+		RejectInvalidCode:   true,
 		RejectKeywords: []string{
 			"how to",
 			"tutorial",
@@ -153,6 +155,13 @@ func ValidatePattern(p Pattern, cfg ExtractionConfig) (bool, string) {
 		}
 	}
 
+	// Check that code blocks parse
+	if cfg.RejectInvalidCode {
+		if _, problems := CheckCode(p.Content); len(problems) > 0 {
+			return false, "code does not parse: " + problems[0].String()
+		}
+	}
+
 	// Check for overly generic pattern names
 	genericNames := []string{
 		"testing-pattern",
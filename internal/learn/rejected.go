@@ -0,0 +1,251 @@
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+const (
+	// maxRejections caps the rejected-pattern list; the least recently
+	// rejected entries go first.
+	maxRejections = 500
+	// maxPromptRejections is how many rejected topics the extraction
+	// prompt lists, most recent first.
+	maxPromptRejections = 30
+	// rejectionMatch is the token overlap (Jaccard) at which an extracted
+	// pattern counts as a repeat of a rejected one.
+	rejectionMatch = 0.7
+)
+
+// fingerprintStopwords carry no topic: LLMs add them to names freely.
+var fingerprintStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "for": true,
+	"in": true, "on": true, "to": true, "with": true, "how": true, "use": true,
+	"using": true, "pattern": true, "tip": true, "best": true, "practice": true,
+}
+
+// RejectedPattern is a pattern someone rejected when reviewing extracted
+// patterns, kept so extraction stops proposing it.
+type RejectedPattern struct {
+	Fingerprint string    `json:"fingerprint"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Rejected    int       `json:"rejected"` // times it was rejected
+	Filtered    int       `json:"filtered"` // repeat suggestions dropped since
+	LastAt      time.Time `json:"last_at"`
+}
+
+// Rejections is the list of rejected patterns. Extraction summarizes it
+// in the LLM prompt and drops suggestions that still match it. It is safe
+// for use by concurrent extractions.
+type Rejections struct {
+	mu      sync.Mutex
+	path    string
+	entries []RejectedPattern
+}
+
+// RejectionsPath returns the rejected-pattern list file.
+func RejectionsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "rejected-patterns.json"), nil
+}
+
+// LoadRejections reads the rejected-pattern list. A missing file is an
+// empty list.
+func LoadRejections() (*Rejections, error) {
+	path, err := RejectionsPath()
+	if err != nil {
+		return nil, err
+	}
+	r := &Rejections{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("cannot read rejected patterns: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.entries); err != nil {
+		return r, fmt.Errorf("cannot parse rejected patterns: %w", err)
+	}
+	return r, nil
+}
+
+// Save writes the list back.
+func (r *Rejections) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RecordRejection adds p to the rejected-pattern list on disk.
+func RecordRejection(p Pattern) error {
+	r, err := LoadRejections()
+	if err != nil {
+		return err
+	}
+	r.Add(p)
+	return r.Save()
+}
+
+// Add records that p was rejected.
+func (r *Rejections) Add(p Pattern) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if e := r.match(p); e != nil {
+		e.Rejected++
+		e.LastAt = now
+		return
+	}
+	r.entries = append(r.entries, RejectedPattern{
+		Fingerprint: Fingerprint(p),
+		Name:        p.Name,
+		Description: p.Description,
+		Tags:        p.Tags,
+		Rejected:    1,
+		LastAt:      now,
+	})
+	if len(r.entries) > maxRejections {
+		sort.SliceStable(r.entries, func(i, j int) bool { return r.entries[i].LastAt.After(r.entries[j].LastAt) })
+		r.entries = r.entries[:maxRejections]
+	}
+}
+
+// Filter drops the patterns that repeat a rejected one and counts them.
+func (r *Rejections) Filter(patterns []ExtractedPattern) []ExtractedPattern {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := patterns[:0]
+	for _, ep := range patterns {
+		if e := r.match(ep.Pattern); e != nil {
+			e.Filtered++
+			continue
+		}
+		kept = append(kept, ep)
+	}
+	return kept
+}
+
+// match returns the rejected entry p repeats, if any.
+func (r *Rejections) match(p Pattern) *RejectedPattern {
+	fp := Fingerprint(p)
+	if fp == "" {
+		return nil
+	}
+	tokens := tokenSet(fp)
+	for i := range r.entries {
+		e := &r.entries[i]
+		if e.Fingerprint == fp || jaccard(tokens, tokenSet(e.Fingerprint)) >= rejectionMatch {
+			return e
+		}
+	}
+	return nil
+}
+
+// PromptSection returns the part of the extraction prompt that lists
+// recently rejected topics, or "" when there are none.
+func (r *Rejections) PromptSection() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return ""
+	}
+	recent := append([]RejectedPattern(nil), r.entries...)
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].LastAt.After(recent[j].LastAt) })
+	if len(recent) > maxPromptRejections {
+		recent = recent[:maxPromptRejections]
+	}
+
+	var b strings.Builder
+	b.WriteString("## PREVIOUSLY REJECTED\n")
+	b.WriteString("The user rejected these patterns before. Do not propose them again, or close variants of them:\n")
+	for _, e := range recent {
+		b.WriteString("- " + e.Name)
+		if e.Description != "" {
+			b.WriteString(": " + truncate(e.Description, 80))
+		}
+		if len(e.Tags) > 0 {
+			b.WriteString(" (" + strings.Join(e.Tags, ", ") + ")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Len returns the number of rejected patterns.
+func (r *Rejections) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Counts returns how many times patterns were rejected and how many
+// repeat suggestions were filtered out. A nil list has none.
+func (r *Rejections) Counts() (rejected, filtered int) {
+	if r == nil {
+		return 0, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		rejected += e.Rejected
+		filtered += e.Filtered
+	}
+	return rejected, filtered
+}
+
+// Fingerprint identifies a pattern's topic by its name: the distinct name
+// words, without stopwords or plural s, sorted. Rewordings like
+// "go-errors-wrapping" and "wrapping-go-error" share a fingerprint.
+func Fingerprint(p Pattern) string {
+	words := strings.FieldsFunc(strings.ToLower(p.Name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, w := range words {
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		if fingerprintStopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		tokens = append(tokens, w)
+	}
+	sort.Strings(tokens)
+	return strings.Join(tokens, "-")
+}
+
+// tokenSet returns the words of a fingerprint.
+func tokenSet(fp string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range strings.Split(fp, "-") {
+		set[t] = true
+	}
+	return set
+}
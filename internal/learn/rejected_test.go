@@ -0,0 +1,67 @@
+package learn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		same bool
+	}{
+		{"go-errors-wrapping", "wrapping-go-error", true},
+		{"how-to-use-go-error-wrapping", "go-error-wrapping", true},
+		{"swift-async-tests", "swift-async-test", true},
+		{"go-error-wrapping", "rust-error-handling", false},
+	} {
+		got := Fingerprint(Pattern{Name: tt.a}) == Fingerprint(Pattern{Name: tt.b})
+		if got != tt.same {
+			t.Errorf("Fingerprint(%s) == Fingerprint(%s): %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestRejections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	if err := RecordRejection(Pattern{Name: "go-error-wrapping", Description: "Wrap errors with %w", Tags: []string{"go"}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = RecordRejection(Pattern{Name: "wrapping-go-errors"}) // the same topic again
+
+	r, err := LoadRejections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected, _ := r.Counts(); r.Len() != 1 || rejected != 2 {
+		t.Fatalf("Len = %d, rejected = %d, want one topic rejected twice", r.Len(), rejected)
+	}
+
+	section := r.PromptSection()
+	if !strings.Contains(section, "go-error-wrapping: Wrap errors with %w (go)") {
+		t.Errorf("prompt section = %q", section)
+	}
+
+	kept := r.Filter([]ExtractedPattern{
+		{Pattern: Pattern{Name: "go-errors-wrapping-pattern"}},
+		{Pattern: Pattern{Name: "go-error-wrapping-sentinel"}}, // 3 of 4 words: still a repeat
+		{Pattern: Pattern{Name: "sqlite-busy-timeout"}},
+	})
+	if len(kept) != 1 || kept[0].Pattern.Name != "sqlite-busy-timeout" {
+		t.Errorf("kept = %v", kept)
+	}
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = LoadRejections()
+	if _, filtered := r.Counts(); filtered != 2 {
+		t.Errorf("filtered = %d after reload, want 2", filtered)
+	}
+
+	var none *Rejections
+	if none.Len() != 0 {
+		t.Error("nil list isn't empty")
+	}
+}
@@ -0,0 +1,470 @@
+package learn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/sync"
+)
+
+// RuleFile is an instruction file users keep for their AI tools, such as
+// CLAUDE.md or .cursorrules, that can seed the pattern store.
+type RuleFile struct {
+	Path    string
+	Kind    string // CLAUDE.md, AGENTS.md, .cursorrules, ...
+	Project string // project directory name; empty for global files
+}
+
+// ruleFileNames are looked for in each project directory.
+var ruleFileNames = []string{
+	"CLAUDE.md",
+	"AGENTS.md",
+	".cursorrules",
+	".windsurfrules",
+	filepath.Join(".github", "copilot-instructions.md"),
+}
+
+// ruleDirs hold one rule per file.
+var ruleDirs = []string{
+	filepath.Join(".cursor", "rules"),
+	filepath.Join(".windsurf", "rules"),
+}
+
+// skipScanDirs are never descended into when looking for projects.
+var skipScanDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "target": true, "dist": true,
+	"build": true, "Pods": true, "DerivedData": true, "venv": true,
+}
+
+// FindRuleFiles returns the rule files in each root and in directories up
+// to depth levels below it (depth 0 checks only the roots themselves).
+// Files mur writes itself (mur-*.md) are skipped.
+func FindRuleFiles(roots []string, depth int) []RuleFile {
+	var found []RuleFile
+	seen := make(map[string]bool)
+	add := func(path, kind, project string) {
+		abs, err := filepath.Abs(path)
+		if err != nil || seen[abs] {
+			return
+		}
+		if info, err := os.Stat(abs); err != nil || info.IsDir() || info.Size() == 0 {
+			return
+		}
+		seen[abs] = true
+		found = append(found, RuleFile{Path: abs, Kind: kind, Project: project})
+	}
+
+	var scan func(dir string, level int)
+	scan = func(dir string, level int) {
+		project := filepath.Base(dir)
+		for _, name := range ruleFileNames {
+			add(filepath.Join(dir, name), filepath.Base(name), project)
+		}
+		for _, rd := range ruleDirs {
+			entries, _ := os.ReadDir(filepath.Join(dir, rd))
+			for _, e := range entries {
+				name := e.Name()
+				if e.IsDir() || strings.HasPrefix(name, "mur-") {
+					continue
+				}
+				if ext := filepath.Ext(name); ext == ".md" || ext == ".mdc" {
+					add(filepath.Join(dir, rd, name), filepath.ToSlash(rd), project)
+				}
+			}
+		}
+		if level >= depth {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") && !skipScanDirs[e.Name()] {
+				scan(filepath.Join(dir, e.Name()), level+1)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		scan(root, 0)
+	}
+	return found
+}
+
+// GlobalRuleFiles returns user-wide rule files, such as ~/.claude/CLAUDE.md.
+func GlobalRuleFiles(home string) []RuleFile {
+	var files []RuleFile
+	path := filepath.Join(config.ClaudeDir(home), "CLAUDE.md")
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		files = append(files, RuleFile{Path: path, Kind: "CLAUDE.md"})
+	}
+	return files
+}
+
+// KnownProjectDirs returns the working directories of projects that have
+// Claude Code sessions, newest first.
+func KnownProjectDirs() []string {
+	projectsDir, err := ClaudeProjectsDir()
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return nil
+	}
+
+	type project struct {
+		dir string
+		mod time.Time
+	}
+	var projects []project
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		transcript, mod := newestTranscript(filepath.Join(projectsDir, e.Name()))
+		if transcript == "" {
+			continue
+		}
+		dir := transcriptCwd(transcript)
+		if dir == "" || seen[dir] {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		seen[dir] = true
+		projects = append(projects, project{dir, mod})
+	}
+
+	sort.Slice(projects, func(i, j int) bool { return projects[i].mod.After(projects[j].mod) })
+	dirs := make([]string, len(projects))
+	for i, p := range projects {
+		dirs[i] = p.dir
+	}
+	return dirs
+}
+
+func newestTranscript(dir string) (string, time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", time.Time{}
+	}
+	var newest string
+	var mod time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		if info, err := e.Info(); err == nil && info.ModTime().After(mod) {
+			newest, mod = filepath.Join(dir, e.Name()), info.ModTime()
+		}
+	}
+	return newest, mod
+}
+
+// transcriptCwd returns the working directory recorded in a transcript.
+func transcriptCwd(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for i := 0; i < 50 && scanner.Scan(); i++ {
+		var line struct {
+			Cwd string `json:"cwd"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &line) == nil && line.Cwd != "" {
+			return line.Cwd
+		}
+	}
+	return ""
+}
+
+// RuleSection is one heading's worth of a rule file.
+type RuleSection struct {
+	Heading string
+	Body    string
+	Line    int // 1-based line of the heading
+}
+
+var headingRe = regexp.MustCompile(`^(#{1,4})\s+(.+?)\s*#*\s*$`)
+
+// SplitRules splits rule file content into sections at markdown headings,
+// ignoring headings inside code blocks and anything between mur's own
+// markers. Content before the first heading becomes a section named
+// after fallback. Sections too short to be a useful pattern are dropped.
+func SplitRules(content, fallback string) []RuleSection {
+	content = stripManagedBlock(content)
+	content, description := stripFrontmatter(content)
+	if description != "" {
+		fallback = description
+	}
+
+	var sections []RuleSection
+	cur := RuleSection{Heading: fallback, Line: 1}
+	var body []string
+	flush := func() {
+		cur.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		if len(cur.Body) >= 40 {
+			sections = append(sections, cur)
+		}
+		body = nil
+	}
+
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil && !inFence {
+			flush()
+			cur = RuleSection{Heading: m[2], Line: i + 1}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// stripManagedBlock blanks out the part of a file mur itself writes,
+// keeping line numbers intact.
+func stripManagedBlock(content string) string {
+	start := strings.Index(content, sync.BlockStart)
+	end := strings.Index(content, sync.BlockEnd)
+	if start < 0 || end < start {
+		return content
+	}
+	end += len(sync.BlockEnd)
+	return content[:start] + blankLines(content[start:end]) + content[end:]
+}
+
+// stripFrontmatter blanks out YAML frontmatter (as in Cursor's .mdc
+// rules), returning its description if it has one.
+func stripFrontmatter(content string) (string, string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return content, ""
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end < 0 {
+		return content, ""
+	}
+	end += 4 + len("\n---")
+	var description string
+	for _, line := range strings.Split(content[:end], "\n") {
+		if v, ok := strings.CutPrefix(line, "description:"); ok {
+			description = strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	return blankLines(content[:end]) + content[end:], description
+}
+
+func blankLines(s string) string {
+	return strings.Repeat("\n", strings.Count(s, "\n"))
+}
+
+// RuleCandidate is a pattern proposed from a rule file.
+type RuleCandidate struct {
+	Pattern     Pattern
+	Origin      string // rule file path
+	Line        int
+	DuplicateOf string // set by DedupeCandidates
+}
+
+// CandidatesFromSections turns sections into candidates without an LLM:
+// one pattern per heading.
+func CandidatesFromSections(file RuleFile, sections []RuleSection) []RuleCandidate {
+	var out []RuleCandidate
+	for _, s := range sections {
+		out = append(out, RuleCandidate{
+			Pattern: newRulePattern(file, ruleName(file.Project, s.Heading), s.Heading,
+				"# "+s.Heading+"\n\n"+s.Body, nil),
+			Origin: file.Path,
+			Line:   s.Line,
+		})
+	}
+	return out
+}
+
+func newRulePattern(file RuleFile, name, title, content string, tags []string) Pattern {
+	now := time.Now().Format(time.RFC3339)
+	if file.Project != "" {
+		tags = deduplicateTags(tags, []string{file.Project})
+	}
+	return Pattern{
+		Name:        name,
+		Description: title,
+		Content:     strings.TrimSpace(content),
+		Domain:      "dev",
+		Category:    "decision",
+		Tags:        tags,
+		Confidence:  0.8, // written by the user, not inferred
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+var nonSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ruleName builds a pattern name from a project and heading.
+func ruleName(project, heading string) string {
+	name := strings.Trim(nonSlugRe.ReplaceAllString(strings.ToLower(project+" "+heading), "-"), "-")
+	if len(name) > 64 {
+		name = strings.TrimRight(name[:64], "-")
+	}
+	if name == "" {
+		name = "rule"
+	}
+	return name
+}
+
+// rulesPrompt asks the LLM to split a rule file into standalone patterns.
+const rulesPrompt = `You are converting a developer's AI instruction file (such as CLAUDE.md or .cursorrules) into standalone, reusable patterns.
+
+Split the file into one pattern per distinct convention, rule, or decision. Group rules that only make sense together; skip boilerplate, greetings, and instructions about the AI's tone or persona. Keep the author's wording, code, and commands.
+
+Output a JSON array. Each object has:
+- name: kebab-case identifier (e.g., "use-table-driven-tests")
+- title: short human-readable title
+- rule: the rule itself, as markdown
+- when: one sentence on when the rule applies (optional)
+- tags: 2-5 relevant tags (language, framework, tool)
+- category: "pattern", "decision", "lesson", or "template"
+
+If nothing is worth keeping, output [].`
+
+// SplitRulesWithLLM asks an LLM to split a rule file into candidates.
+func SplitRulesWithLLM(file RuleFile, content string, opts LLMExtractOptions) ([]RuleCandidate, error) {
+	content = strings.TrimSpace(stripManagedBlock(content))
+	if len(content) > 20000 {
+		content = content[:20000]
+	}
+
+	provider, err := llmProviderFromOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("LLM setup failed: %w", err)
+	}
+	response, err := provider.Complete(rulesPrompt + "\n\n---\n\nFile " + file.Kind + ":\n\n" + content)
+	if err != nil {
+		return nil, fmt.Errorf("LLM call failed: %w", err)
+	}
+	return parseRuleCandidates(file, response)
+}
+
+// parseRuleCandidates parses the JSON array returned for rulesPrompt.
+func parseRuleCandidates(file RuleFile, response string) ([]RuleCandidate, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("LLM response has no JSON array")
+	}
+	var rules []struct {
+		Name     string   `json:"name"`
+		Title    string   `json:"title"`
+		Rule     string   `json:"rule"`
+		When     string   `json:"when"`
+		Tags     []string `json:"tags"`
+		Category string   `json:"category"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse LLM response: %w", err)
+	}
+
+	var out []RuleCandidate
+	for _, r := range rules {
+		if strings.TrimSpace(r.Rule) == "" {
+			continue
+		}
+		name := ruleName(file.Project, r.Name)
+		if r.Name == "" {
+			name = ruleName(file.Project, r.Title)
+		}
+		content := r.Rule
+		if r.Title != "" {
+			content = "# " + r.Title + "\n\n" + r.Rule
+		}
+		if r.When != "" {
+			content += "\n\n**Applies when:** " + r.When
+		}
+		p := newRulePattern(file, name, r.Title, content, r.Tags)
+		if r.Category != "" {
+			p.Category = r.Category
+		}
+		out = append(out, RuleCandidate{Pattern: p, Origin: file.Path})
+	}
+	return out, nil
+}
+
+// duplicateThreshold is the word overlap above which two patterns are
+// considered the same rule.
+const duplicateThreshold = 0.7
+
+// DedupeCandidates splits candidates into new ones and duplicates, setting
+// DuplicateOf on the latter. A candidate duplicates an existing pattern
+// (name → content) or an earlier candidate with the same name or mostly
+// the same words, so a rule copied across projects is proposed once.
+func DedupeCandidates(candidates []RuleCandidate, existing map[string]string) (fresh, dups []RuleCandidate) {
+	type known struct {
+		name  string
+		words map[string]bool
+	}
+	var all []known
+	for name, content := range existing {
+		all = append(all, known{name, wordSet(content)})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+
+	for _, c := range candidates {
+		words := wordSet(c.Pattern.Content)
+		for _, k := range all {
+			if k.name == c.Pattern.Name || jaccard(words, k.words) >= duplicateThreshold {
+				c.DuplicateOf = k.name
+				break
+			}
+		}
+		if c.DuplicateOf != "" {
+			dups = append(dups, c)
+			continue
+		}
+		all = append(all, known{c.Pattern.Name, words})
+		fresh = append(fresh, c)
+	}
+	return fresh, dups
+}
+
+var ruleWordRe = regexp.MustCompile(`[a-zA-Z0-9_]{3,}`)
+
+func wordSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range ruleWordRe.FindAllString(strings.ToLower(s), -1) {
+		set[w] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for w := range a {
+		if b[w] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
@@ -0,0 +1,184 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitRules(t *testing.T) {
+	content := `---
+description: Go conventions for this repo
+globs: "**/*.go"
+---
+Always run gofmt and go vet before committing any change.
+
+## Testing
+Use table-driven tests with t.Run subtests for every exported function.
+
+` + "```go" + `
+# not a heading, just a comment in a code block
+` + "```" + `
+
+## Short
+tiny
+
+<!-- mur:start -->
+## Pattern written by mur sync
+This text belongs to mur and must never be imported back.
+<!-- mur:end -->
+
+### Errors
+Wrap errors with fmt.Errorf and %w so callers can use errors.Is.
+`
+	sections := SplitRules(content, "CLAUDE.md")
+
+	var headings []string
+	for _, s := range sections {
+		headings = append(headings, s.Heading)
+	}
+	if got := strings.Join(headings, "|"); got != "Go conventions for this repo|Testing|Errors" {
+		t.Fatalf("headings = %s", got)
+	}
+	if sections[1].Line != 7 || sections[2].Line != 22 {
+		t.Errorf("lines = %d, %d, want 7, 22", sections[1].Line, sections[2].Line)
+	}
+	if !strings.Contains(sections[1].Body, "# not a heading") {
+		t.Errorf("code block comment split the section: %q", sections[1].Body)
+	}
+	for _, s := range sections {
+		if strings.Contains(s.Body, "belongs to mur") {
+			t.Errorf("managed block imported in %q", s.Heading)
+		}
+	}
+}
+
+func TestDedupeCandidates(t *testing.T) {
+	rule := func(name, content string) RuleCandidate {
+		return RuleCandidate{Pattern: Pattern{Name: name, Content: content}}
+	}
+	existing := map[string]string{
+		"go-error-wrapping": "Wrap errors with fmt.Errorf and %w so callers can use errors.Is",
+	}
+	candidates := []RuleCandidate{
+		rule("api-errors", "Wrap errors with fmt.Errorf and %w so callers can use errors.Is"),
+		rule("api-testing", "Use table-driven tests with t.Run subtests for every exported function"),
+		rule("web-testing", "Use table-driven tests with t.Run subtests for every exported function."),
+		rule("web-deploy", "Deploy with make release after the staging smoke tests pass"),
+		rule("go-error-wrapping", "Something else entirely"),
+	}
+
+	fresh, dups := DedupeCandidates(candidates, existing)
+
+	var names []string
+	for _, c := range fresh {
+		names = append(names, c.Pattern.Name)
+	}
+	if got := strings.Join(names, ","); got != "api-testing,web-deploy" {
+		t.Errorf("fresh = %s", got)
+	}
+	want := map[string]string{"api-errors": "go-error-wrapping", "web-testing": "api-testing", "go-error-wrapping": "go-error-wrapping"}
+	for _, d := range dups {
+		if want[d.Pattern.Name] != d.DuplicateOf {
+			t.Errorf("%s duplicate of %q, want %q", d.Pattern.Name, d.DuplicateOf, want[d.Pattern.Name])
+		}
+	}
+	if len(dups) != len(want) {
+		t.Errorf("got %d duplicates, want %d", len(dups), len(want))
+	}
+}
+
+func TestParseRuleCandidates(t *testing.T) {
+	file := RuleFile{Path: "/p/api/CLAUDE.md", Kind: "CLAUDE.md", Project: "api"}
+	response := `Here you go:
+[{"name": "table-tests", "title": "Table-driven tests", "rule": "Use t.Run subtests.", "when": "Writing Go tests", "tags": ["go"], "category": "pattern"},
+ {"name": "empty", "title": "No rule"}]`
+
+	got, err := parseRuleCandidates(file, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d candidates, want 1", len(got))
+	}
+	p := got[0].Pattern
+	if p.Name != "api-table-tests" || p.Category != "pattern" || got[0].Origin != file.Path {
+		t.Errorf("candidate = %+v", got[0])
+	}
+	if !strings.Contains(p.Content, "**Applies when:** Writing Go tests") || strings.Join(p.Tags, ",") != "go,api" {
+		t.Errorf("content %q, tags %v", p.Content, p.Tags)
+	}
+
+	if _, err := parseRuleCandidates(file, "I couldn't find any rules."); err == nil {
+		t.Error("expected an error without a JSON array")
+	}
+}
+
+func TestFindRuleFiles(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("api/CLAUDE.md", "# API")
+	write("api/.cursor/rules/go.mdc", "rule")
+	write("api/.cursor/rules/mur-patterns.md", "written by mur sync")
+	write("web/.cursorrules", "rule")
+	write("web/node_modules/pkg/CLAUDE.md", "vendored")
+	write("web/empty/AGENTS.md", "")
+
+	var got []string
+	for _, f := range FindRuleFiles([]string{root}, 2) {
+		rel, _ := filepath.Rel(root, f.Path)
+		got = append(got, f.Project+":"+filepath.ToSlash(rel))
+	}
+	if s := strings.Join(got, " "); s != "api:api/CLAUDE.md api:api/.cursor/rules/go.mdc web:web/.cursorrules" {
+		t.Errorf("found %s", s)
+	}
+	if n := len(FindRuleFiles([]string{root}, 0)); n != 0 {
+		t.Errorf("depth 0 found %d files in the root, want 0", n)
+	}
+}
+
+func TestStaging(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("MUR_HOME", dir)
+
+	c := RuleCandidate{
+		Pattern: Pattern{Name: "api-table-tests", Description: "Table-driven tests", Content: "Use t.Run subtests."},
+		Origin:  "/p/api/CLAUDE.md",
+		Line:    7,
+	}
+	if err := Stage(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := Stage(RuleCandidate{Pattern: Pattern{Name: "drop-me", Content: "x"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	staged, err := ListStaged()
+	if err != nil || len(staged) != 2 {
+		t.Fatalf("ListStaged = %d, %v", len(staged), err)
+	}
+
+	if err := AcceptStaged("api-table-tests"); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := Get("api-table-tests"); err != nil || p.Content != "Use t.Run subtests." {
+		t.Errorf("accepted pattern = %+v, %v", p, err)
+	}
+	if err := RejectStaged("drop-me"); err != nil {
+		t.Fatal(err)
+	}
+	if staged, _ := ListStaged(); len(staged) != 0 {
+		t.Errorf("%d patterns still staged", len(staged))
+	}
+	if err := RejectStaged("drop-me"); err == nil {
+		t.Error("rejecting twice should fail")
+	}
+}
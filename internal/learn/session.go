@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
 )
 
 // Session represents a Claude Code session.
@@ -17,24 +19,35 @@ type Session struct {
 	Project      string
 	Path         string
 	Messages     []SessionMessage
-	ToolUseCount int // Number of tool_use blocks in the session
+	ToolEvents   []ToolEvent // Tool calls and results, including subagents'
+	ToolUseCount int         // Number of tool_use blocks in the session
 	CreatedAt    time.Time
 }
 
 // SessionMessage represents a message in a session.
 type SessionMessage struct {
-	Type      string // "user", "assistant", "progress", etc.
-	Role      string // "user", "assistant"
-	Content   string // Text content
+	Type      string   // "user", "assistant", "progress", etc.
+	Role      string   // "user", "assistant"
+	Content   string   // Text content
+	Subagent  bool     // Written by a subagent (sidechain)
+	Pos       Position // Where the message is in its transcript
 	Timestamp time.Time
 }
 
+// Position locates a JSONL line in a transcript file.
+type Position struct {
+	Path   string
+	Line   int   // 1-based
+	Offset int64 // byte offset of the line
+}
+
 // jsonlMessage represents the raw JSONL message structure from Claude Code.
 type jsonlMessage struct {
-	Type      string          `json:"type"`
-	Message   json.RawMessage `json:"message,omitempty"`
-	Timestamp string          `json:"timestamp,omitempty"`
-	SessionID string          `json:"sessionId,omitempty"`
+	Type        string          `json:"type"`
+	Message     json.RawMessage `json:"message,omitempty"`
+	Timestamp   string          `json:"timestamp,omitempty"`
+	SessionID   string          `json:"sessionId,omitempty"`
+	IsSidechain bool            `json:"isSidechain,omitempty"`
 }
 
 // messageContent represents the message field structure.
@@ -49,7 +62,7 @@ func ClaudeProjectsDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".claude", "projects"), nil
+	return filepath.Join(config.ClaudeDir(home), "projects"), nil
 }
 
 // ListSessions returns available sessions from Claude Code and OpenClaw.
@@ -110,6 +123,10 @@ func listClaudeSessions() ([]Session, error) {
 			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
 				continue
 			}
+			// Subagent transcripts belong to their parent session
+			if strings.HasPrefix(entry.Name(), "agent-") {
+				continue
+			}
 
 			sessionID := strings.TrimSuffix(entry.Name(), ".jsonl")
 			sessionPath := filepath.Join(projectPath, entry.Name())
@@ -166,11 +183,30 @@ func LoadSession(idOrPath string) (*Session, error) {
 	}
 
 	// Parse the JSONL file
-	messages, toolUseCount, err := parseJSONL(sessionPath)
+	messages, events, toolUseCount, err := parseJSONL(sessionPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse session: %w", err)
 	}
 
+	// Add subagent transcripts
+	for _, agentPath := range subagentTranscripts(sessionPath, sessionID) {
+		agentMessages, agentEvents, agentToolUses, err := parseJSONL(agentPath)
+		if err != nil {
+			continue
+		}
+		agent := strings.TrimSuffix(filepath.Base(agentPath), ".jsonl")
+		for i := range agentMessages {
+			agentMessages[i].Subagent = true
+		}
+		for i := range agentEvents {
+			agentEvents[i].Subagent = true
+			agentEvents[i].Agent = agent
+		}
+		messages = append(messages, agentMessages...)
+		events = append(events, agentEvents...)
+		toolUseCount += agentToolUses
+	}
+
 	info, err := os.Stat(sessionPath)
 	if err != nil {
 		return nil, err
@@ -181,6 +217,7 @@ func LoadSession(idOrPath string) (*Session, error) {
 		Project:      project,
 		Path:         sessionPath,
 		Messages:     messages,
+		ToolEvents:   events,
 		ToolUseCount: toolUseCount,
 		CreatedAt:    info.ModTime(),
 	}, nil
@@ -206,15 +243,16 @@ func RecentSessions(days int) ([]Session, error) {
 }
 
 // parseJSONL parses a Claude Code session JSONL file.
-// Returns messages and tool use count.
-func parseJSONL(path string) ([]SessionMessage, int, error) {
+// Returns messages, tool events, and tool use count.
+func parseJSONL(path string) ([]SessionMessage, []ToolEvent, int, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, 0, err
 	}
 	defer func() { _ = file.Close() }()
 
 	var messages []SessionMessage
+	tools := newToolEventCollector()
 	toolUseCount := 0
 	scanner := bufio.NewScanner(file)
 
@@ -222,6 +260,20 @@ func parseJSONL(path string) ([]SessionMessage, int, error) {
 	buf := make([]byte, 0, 1024*1024)
 	scanner.Buffer(buf, 10*1024*1024) // 10MB max per line
 
+	// Track line numbers and byte offsets so extracted patterns can point
+	// back at the exact message.
+	var pos Position
+	next := Position{Path: path, Line: 1}
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			pos = next
+			next.Line++
+			next.Offset += int64(advance)
+		}
+		return advance, token, err
+	})
+
 	for scanner.Scan() {
 		line := scanner.Bytes()
 		if len(line) == 0 {
@@ -257,6 +309,7 @@ func parseJSONL(path string) ([]SessionMessage, int, error) {
 			role = content.Role
 			text = extractText(content.Content)
 			timestamp, _ = time.Parse(time.RFC3339, msg.Timestamp)
+			tools.add(content.Content, msg.IsSidechain, timestamp, pos)
 
 		} else if msg.Type == "user" || msg.Type == "assistant" {
 			// Handle Claude Code format: type="user" or type="assistant"
@@ -273,6 +326,7 @@ func parseJSONL(path string) ([]SessionMessage, int, error) {
 			}
 
 			text = extractText(content.Content)
+			tools.add(content.Content, msg.IsSidechain, timestamp, pos)
 		} else {
 			continue
 		}
@@ -292,11 +346,13 @@ func parseJSONL(path string) ([]SessionMessage, int, error) {
 			Type:      msg.Type,
 			Role:      role,
 			Content:   text,
+			Subagent:  msg.IsSidechain,
+			Pos:       pos,
 			Timestamp: timestamp,
 		})
 	}
 
-	return messages, toolUseCount, scanner.Err()
+	return messages, tools.events, toolUseCount, scanner.Err()
 }
 
 // contentBlockExt extends contentBlock with thinking support.
@@ -0,0 +1,263 @@
+package learn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// sourceExcerptMax is how much of the source text a pattern keeps, to show
+// when the transcript is no longer available.
+const sourceExcerptMax = 600
+
+// sourceRef points at the message in its transcript.
+func (m SessionMessage) sourceRef(session, text string) *pattern.SourceRef {
+	if m.Pos.Path == "" {
+		return nil
+	}
+	return &pattern.SourceRef{
+		Session:   session,
+		Path:      m.Pos.Path,
+		Line:      m.Pos.Line,
+		Offset:    m.Pos.Offset,
+		Timestamp: m.Timestamp,
+		Excerpt:   truncateText(strings.TrimSpace(text), sourceExcerptMax),
+	}
+}
+
+// sourceRef points at the failing call through the successful retry.
+func (f CommandFix) sourceRef(session string) *pattern.SourceRef {
+	if f.Pos.Path == "" {
+		return nil
+	}
+	excerpt := "$ " + f.Command + "\n" + f.Error
+	if f.Retry != "" {
+		excerpt += "\n$ " + f.Retry
+	}
+	return &pattern.SourceRef{
+		Session:   session,
+		Path:      f.Pos.Path,
+		Line:      f.Pos.Line,
+		EndLine:   f.EndLine,
+		Offset:    f.Pos.Offset,
+		Timestamp: f.Timestamp,
+		Excerpt:   truncateText(excerpt, sourceExcerptMax),
+	}
+}
+
+// attachSource records the full session ID on extracted patterns' source
+// references. Patterns extracted from the whole session (by an LLM) point
+// at the transcript without a line.
+func (s *Session) attachSource(extracted []ExtractedPattern) {
+	for i := range extracted {
+		ref := extracted[i].Pattern.Source
+		switch {
+		case ref != nil:
+			ref.Session = s.ID
+		case s.Path != "":
+			extracted[i].Pattern.Source = &pattern.SourceRef{Session: s.ID, Path: s.Path}
+		}
+	}
+}
+
+// SourceEntry is a message or tool call from a pattern's source transcript.
+type SourceEntry struct {
+	Line      int       `json:"line"`
+	Role      string    `json:"role"` // user, assistant, or tool
+	Text      string    `json:"text"`
+	Subagent  bool      `json:"subagent,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Match     bool      `json:"match,omitempty"` // part of the referenced excerpt
+}
+
+// SourceExcerpt is the transcript context around a SourceRef.
+type SourceExcerpt struct {
+	Path    string        `json:"path,omitempty"` // where the transcript was found
+	Found   bool          `json:"found"`          // the referenced lines were located
+	Note    string        `json:"note,omitempty"` // why the excerpt may not be exact
+	Entries []SourceEntry `json:"entries,omitempty"`
+}
+
+// ReadSource loads the transcript entries around ref, with up to context
+// entries either side. A transcript that moved is looked up by session ID,
+// and one that was rewritten (e.g. rotated or compacted) is searched by the
+// message timestamp. When the excerpt can't be found, Found is false and
+// callers fall back on ref.Excerpt.
+func ReadSource(ref *pattern.SourceRef, context int) (*SourceExcerpt, error) {
+	path := locateTranscript(ref)
+	if path == "" {
+		return &SourceExcerpt{Note: "transcript not found; it may have been rotated or deleted"}, nil
+	}
+	out := &SourceExcerpt{Path: path}
+	if path != ref.Path {
+		out.Note = "transcript moved since extraction"
+	}
+	if ref.Line == 0 {
+		out.Note = "extracted from the whole session"
+		return out, nil
+	}
+
+	messages, events, _, err := parseJSONL(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read transcript: %w", err)
+	}
+	entries := sourceEntries(messages, events)
+
+	start, end := ref.Line, ref.EndLine
+	if end < start {
+		end = start
+	}
+	if !ref.Timestamp.IsZero() && !timestampAt(path, ref.Offset).Equal(ref.Timestamp) {
+		// Lines shifted: find the message by its timestamp instead
+		line := relocate(entries, ref.Timestamp, ref.Line)
+		if line == 0 {
+			out.Note = "transcript changed since extraction"
+			return out, nil
+		}
+		end += line - start
+		start = line
+		out.Note = "transcript changed since extraction; found by timestamp"
+	}
+
+	first, last := -1, -1
+	for i, e := range entries {
+		if e.Line >= start && e.Line <= end {
+			entries[i].Match = true
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		if out.Note == "" {
+			out.Note = "transcript changed since extraction"
+		}
+		return out, nil
+	}
+
+	first = max(first-context, 0)
+	last = min(last+context, len(entries)-1)
+	out.Found = true
+	out.Entries = entries[first : last+1]
+	return out, nil
+}
+
+// sourceEntries merges messages and tool calls in transcript order.
+func sourceEntries(messages []SessionMessage, events []ToolEvent) []SourceEntry {
+	var entries []SourceEntry
+	for _, m := range messages {
+		entries = append(entries, SourceEntry{
+			Line:      m.Pos.Line,
+			Role:      m.Role,
+			Text:      m.Content,
+			Subagent:  m.Subagent,
+			Timestamp: m.Timestamp,
+		})
+	}
+	for _, e := range events {
+		text := e.Tool + ": " + e.Input
+		if e.Output != "" {
+			text += "\n→ " + truncateText(strings.TrimSpace(e.Output), 300)
+		}
+		entries = append(entries, SourceEntry{
+			Line:      e.Pos.Line,
+			Role:      "tool",
+			Text:      text,
+			Subagent:  e.Subagent,
+			Timestamp: e.Timestamp,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Line < entries[j].Line
+	})
+	return entries
+}
+
+// relocate returns the line of the entry with timestamp ts nearest to the
+// original line, or 0 if there is none.
+func relocate(entries []SourceEntry, ts time.Time, line int) int {
+	best := 0
+	for _, e := range entries {
+		if !e.Timestamp.Equal(ts) {
+			continue
+		}
+		if best == 0 || abs(e.Line-line) < abs(best-line) {
+			best = e.Line
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// timestampAt returns the timestamp of the JSONL line at offset, or the
+// zero time if it can't be read.
+func timestampAt(path string, offset int64) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.Seek(offset, 0); err != nil {
+		return time.Time{}
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return time.Time{}
+	}
+	var msg jsonlMessage
+	if json.Unmarshal(line, &msg) != nil {
+		return time.Time{}
+	}
+	ts, _ := time.Parse(time.RFC3339, msg.Timestamp)
+	return ts
+}
+
+// locateTranscript returns the transcript file for ref: its recorded path,
+// or, if that is gone, the same file under wherever the session is now.
+func locateTranscript(ref *pattern.SourceRef) string {
+	if ref.Path != "" {
+		if _, err := os.Stat(ref.Path); err == nil {
+			return ref.Path
+		}
+	}
+	if ref.Session == "" {
+		return ""
+	}
+
+	sessions, _ := ListSessions()
+	base := filepath.Base(ref.Path)
+	for _, s := range sessions {
+		if s.ID != ref.Session {
+			continue
+		}
+		candidates := []string{s.Path}
+		if ref.Path != "" && base != filepath.Base(s.Path) {
+			candidates = []string{
+				filepath.Join(strings.TrimSuffix(s.Path, ".jsonl"), "subagents", base),
+				filepath.Join(filepath.Dir(s.Path), base),
+			}
+		}
+		for _, p := range candidates {
+			if _, err := os.Stat(p); err == nil {
+				return p
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractedSourceRef(t *testing.T) {
+	sessionPath := writeTranscripts(t)
+	s, err := LoadSession(sessionPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := s.Messages[1]; m.Pos.Path != sessionPath || m.Pos.Line != 2 || m.Pos.Offset != int64(len(strings.SplitAfter(mainTranscript, "\n")[0])) {
+		t.Errorf("message position = %+v", m.Pos)
+	}
+
+	extracted, err := ExtractFromSessionData(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var goFix, npmFix *ExtractedPattern
+	for i := range extracted {
+		switch {
+		case strings.HasPrefix(extracted[i].Pattern.Name, "fix-go-test"):
+			goFix = &extracted[i]
+		case strings.HasPrefix(extracted[i].Pattern.Name, "fix-npm-run"):
+			npmFix = &extracted[i]
+		}
+	}
+	if goFix == nil || npmFix == nil {
+		t.Fatalf("fix patterns not extracted: %+v", extracted)
+	}
+
+	ref := goFix.Pattern.Source
+	if ref == nil || ref.Session != "s1" || ref.Path != sessionPath || ref.Line != 2 || ref.EndLine != 6 {
+		t.Fatalf("go fix source = %+v", ref)
+	}
+	if !strings.Contains(ref.Excerpt, "undefined: sqlOpen") {
+		t.Errorf("excerpt = %q", ref.Excerpt)
+	}
+	if ref := npmFix.Pattern.Source; ref == nil || filepath.Base(ref.Path) != "agent-build.jsonl" || ref.Line != 1 {
+		t.Errorf("npm fix source = %+v", ref)
+	}
+}
+
+func TestReadSource(t *testing.T) {
+	sessionPath := writeTranscripts(t)
+	s, err := LoadSession(sessionPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fix := ExtractFromCommandFixes(CommandFixes(s.ToolEvents), s.ShortID())[0]
+	ref := fix.Pattern.Source
+
+	ex, err := ReadSource(ref, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Found || ex.Note != "" {
+		t.Fatalf("ReadSource = %+v", ex)
+	}
+	if first := ex.Entries[0]; first.Line != 1 || first.Match || first.Text != "fix the build" {
+		t.Errorf("context entry = %+v", first)
+	}
+	var matched []string
+	for _, e := range ex.Entries {
+		if e.Match {
+			matched = append(matched, e.Text)
+		}
+	}
+	if len(matched) < 3 || !strings.Contains(matched[1], "go test ./...\n→ # example/store") {
+		t.Errorf("matched entries = %q", matched)
+	}
+
+	// The transcript is rewritten with earlier history trimmed and a
+	// summary prepended: found again by timestamp
+	lines := strings.SplitAfter(mainTranscript, "\n")
+	rewritten := `{"type":"summary","summary":"Build fixes"}` + "\n" + `{"type":"summary","summary":"more"}` + "\n" + strings.Join(lines[1:], "")
+	if err := os.WriteFile(sessionPath, []byte(rewritten), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ex, err = ReadSource(ref, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ex.Found || !strings.Contains(ex.Note, "found by timestamp") || ex.Entries[0].Line != 3 {
+		t.Errorf("after rewrite: %+v", ex)
+	}
+
+	// The transcript is deleted: callers fall back on the saved excerpt
+	t.Setenv("HOME", t.TempDir())
+	if err := os.Remove(sessionPath); err != nil {
+		t.Fatal(err)
+	}
+	ex, err = ReadSource(ref, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Found || !strings.Contains(ex.Note, "not found") {
+		t.Errorf("after delete: %+v", ex)
+	}
+}
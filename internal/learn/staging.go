@@ -0,0 +1,184 @@
+package learn
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/core/suggest"
+)
+
+// StagedPattern is a pattern proposed by an import or mined from another
+// CLI's history ('mur learn cross'), waiting for review
+// ('mur import review') before it joins the pattern store.
+type StagedPattern struct {
+	Pattern    `yaml:",inline"`
+	Origin     string `yaml:"origin"` // file or history directory the pattern came from
+	OriginLine int    `yaml:"origin_line,omitempty"`
+	StagedAt   string `yaml:"staged_at"`
+}
+
+// StagingDir returns the path to ~/.mur/staged/
+func StagingDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.DataDir(home), "staged"), nil
+}
+
+func stagedPath(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	dir, err := StagingDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// Stage saves a candidate for review, replacing an earlier one with the
+// same name.
+func Stage(c RuleCandidate) error {
+	path, err := stagedPath(c.Pattern.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create staging directory: %w", err)
+	}
+	data, err := yaml.Marshal(StagedPattern{
+		Pattern:    c.Pattern,
+		Origin:     c.Origin,
+		OriginLine: c.Line,
+		StagedAt:   time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot serialize pattern: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("cannot write staged pattern: %w", err)
+	}
+	return nil
+}
+
+// IsStaged reports whether a pattern with this name is waiting for review.
+func IsStaged(name string) bool {
+	path, err := stagedPath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// SuggestionCandidate turns a suggestion mined from a CLI's history into a
+// candidate for review; origin is where the history lives.
+func SuggestionCandidate(s suggest.Suggestion, origin string) RuleCandidate {
+	now := time.Now().Format(time.RFC3339)
+	category := "pattern"
+	if strings.HasSuffix(s.Name, "-solution") {
+		category = "lesson"
+	}
+	return RuleCandidate{
+		Pattern: Pattern{
+			Name:        NormalizeName(s.Name),
+			Description: s.Description,
+			Content:     strings.TrimSpace(s.Content),
+			Domain:      "dev",
+			Category:    category,
+			Tags:        s.Tags,
+			Confidence:  s.Confidence,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Origin: origin,
+	}
+}
+
+// ListStaged returns staged patterns, oldest first.
+func ListStaged() ([]StagedPattern, error) {
+	dir, err := StagingDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read staging directory: %w", err)
+	}
+
+	var staged []StagedPattern
+	for _, e := range entries {
+		if e.IsDir() || !pattern.IsPatternFile(e.Name()) {
+			continue
+		}
+		data, err := pattern.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var sp StagedPattern
+		if yaml.Unmarshal(data, &sp) != nil || sp.Name == "" {
+			continue
+		}
+		staged = append(staged, sp)
+	}
+	sort.SliceStable(staged, func(i, j int) bool {
+		if staged[i].StagedAt != staged[j].StagedAt {
+			return staged[i].StagedAt < staged[j].StagedAt
+		}
+		if staged[i].Origin != staged[j].Origin {
+			return staged[i].Origin < staged[j].Origin
+		}
+		return staged[i].OriginLine < staged[j].OriginLine
+	})
+	return staged, nil
+}
+
+// AcceptStaged moves a staged pattern into the pattern store.
+func AcceptStaged(name string) error {
+	path, err := stagedPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no staged pattern: %s", name)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read staged pattern: %w", err)
+	}
+	var sp StagedPattern
+	if err := yaml.Unmarshal(data, &sp); err != nil {
+		return fmt.Errorf("cannot parse staged pattern: %w", err)
+	}
+	sp.Pattern.CreatedAt = ""
+	if err := Add(sp.Pattern); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// RejectStaged discards a staged pattern.
+func RejectStaged(name string) error {
+	path, err := stagedPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return fmt.Errorf("no staged pattern: %s", name)
+	} else if err != nil {
+		return fmt.Errorf("cannot remove staged pattern: %w", err)
+	}
+	return nil
+}
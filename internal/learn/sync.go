@@ -7,9 +7,16 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/sync"
 	"github.com/mur-run/mur-core/internal/team"
 )
 
+// legacyCodexSection starts the patterns section older versions appended
+// to Codex's instructions.md before it was a managed block.
+const legacyCodexSection = "\n\n## Learned Patterns ("
+
 // SyncResult holds the result of a pattern sync operation.
 type SyncResult struct {
 	Target  string
@@ -81,7 +88,7 @@ func SyncPatterns() ([]SyncResult, error) {
 
 // syncToClaudeCode syncs patterns to ~/.claude/skills/learned-{name}/SKILL.md
 func syncToClaudeCode(home string, patterns []Pattern) SyncResult {
-	skillsDir := filepath.Join(home, ".claude", "skills")
+	skillsDir := filepath.Join(config.ClaudeDir(home), "skills")
 
 	// Ensure skills directory exists
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
@@ -196,7 +203,7 @@ func syncToCodex(home string, patterns []Pattern) SyncResult {
 
 	// Build patterns section
 	var sb strings.Builder
-	sb.WriteString("\n\n## Learned Patterns (murmur-ai)\n\n")
+	sb.WriteString("## Learned Patterns (murmur-ai)\n\n")
 	for _, p := range patterns {
 		sb.WriteString(fmt.Sprintf("### %s\n\n", p.Name))
 		if p.Description != "" {
@@ -206,25 +213,13 @@ func syncToCodex(home string, patterns []Pattern) SyncResult {
 		sb.WriteString("\n\n")
 	}
 
+	// Only the mur block is rewritten; the user's instructions are kept
 	instructionsPath := filepath.Join(codexDir, "instructions.md")
-
-	// Read existing content
-	existing, _ := os.ReadFile(instructionsPath)
-	existingStr := string(existing)
-
-	// Remove old patterns section if present
-	if idx := strings.Index(existingStr, "\n\n## Learned Patterns (murmur-ai)"); idx != -1 {
-		existingStr = existingStr[:idx]
-	}
-
-	// Append new patterns section
-	newContent := existingStr + sb.String()
-
-	if err := os.WriteFile(instructionsPath, []byte(newContent), 0644); err != nil {
+	if err := sync.WriteManagedBlock(instructionsPath, sb.String(), legacyCodexSection); err != nil {
 		return SyncResult{
 			Target:  "Codex",
 			Success: false,
-			Message: fmt.Sprintf("cannot write instructions.md: %v", err),
+			Message: fmt.Sprintf("cannot update instructions.md: %v", err),
 		}
 	}
 
@@ -426,7 +421,7 @@ func CleanupSyncedPatterns() error {
 	}
 
 	// Clean up Claude Code
-	claudeSkills := filepath.Join(home, ".claude", "skills")
+	claudeSkills := filepath.Join(config.ClaudeDir(home), "skills")
 	if entries, err := os.ReadDir(claudeSkills); err == nil {
 		for _, entry := range entries {
 			if entry.IsDir() && strings.HasPrefix(entry.Name(), "learned-") {
@@ -487,10 +482,11 @@ func syncToTeamRepo(patterns []Pattern) SyncResult {
 			continue
 		}
 
-		dstPath := filepath.Join(teamPatternsDir, p.Name+".yaml")
+		dstPath := filepath.Join(teamPatternsDir, filepath.Base(srcPath))
 		if err := copyFile(srcPath, dstPath); err != nil {
 			continue
 		}
+		_ = os.Remove(pattern.OtherVariant(dstPath))
 		synced++
 	}
 
@@ -534,22 +530,22 @@ func SyncFromTeamRepo() ([]SyncResult, error) {
 
 	imported := 0
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !pattern.IsPatternFile(entry.Name()) {
 			continue
 		}
 
 		srcPath := filepath.Join(teamPatternsDir, entry.Name())
-		name := strings.TrimSuffix(entry.Name(), ".yaml")
-
-		dstPath, err := patternPath(name)
+		dir, err := PatternsDir()
 		if err != nil {
 			continue
 		}
 
-		// Copy team pattern to local
+		// Copy team pattern to local, as plain or compressed as it is
+		dstPath := filepath.Join(dir, entry.Name())
 		if err := copyFile(srcPath, dstPath); err != nil {
 			continue
 		}
+		_ = os.Remove(pattern.OtherVariant(dstPath))
 		imported++
 	}
 
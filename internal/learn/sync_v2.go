@@ -3,11 +3,13 @@ package learn
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/sync"
 	"github.com/mur-run/mur-core/internal/team"
 )
 
@@ -18,7 +20,7 @@ func SyncPatternsV2() ([]SyncResult, error) {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
 	}
 
-	patternsDir := filepath.Join(home, ".mur", "patterns")
+	patternsDir := filepath.Join(config.DataDir(home), "patterns")
 	store := pattern.NewStore(patternsDir)
 
 	patterns, err := store.GetActive()
@@ -65,7 +67,7 @@ func SyncPatternsV2() ([]SyncResult, error) {
 
 // syncToClaudeCodeV2 syncs patterns to ~/.claude/skills/learned-{name}/SKILL.md
 func syncToClaudeCodeV2(home string, patterns []pattern.Pattern) SyncResult {
-	skillsDir := filepath.Join(home, ".claude", "skills")
+	skillsDir := filepath.Join(config.ClaudeDir(home), "skills")
 
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
 		return SyncResult{
@@ -176,7 +178,7 @@ func syncToCodexV2(home string, patterns []pattern.Pattern) SyncResult {
 
 	// Build patterns section
 	var sb strings.Builder
-	sb.WriteString("\n\n## Learned Patterns (mur)\n\n")
+	sb.WriteString("## Learned Patterns (mur)\n\n")
 	for _, p := range patterns {
 		sb.WriteString(fmt.Sprintf("### %s\n\n", p.Name))
 		if p.Description != "" {
@@ -186,23 +188,13 @@ func syncToCodexV2(home string, patterns []pattern.Pattern) SyncResult {
 		sb.WriteString("\n\n")
 	}
 
+	// Only the mur block is rewritten; the user's instructions are kept
 	instructionsPath := filepath.Join(codexDir, "instructions.md")
-
-	existing, _ := os.ReadFile(instructionsPath)
-	existingStr := string(existing)
-
-	// Remove old patterns section
-	if idx := strings.Index(existingStr, "\n\n## Learned Patterns"); idx != -1 {
-		existingStr = existingStr[:idx]
-	}
-
-	newContent := existingStr + sb.String()
-
-	if err := os.WriteFile(instructionsPath, []byte(newContent), 0644); err != nil {
+	if err := sync.WriteManagedBlock(instructionsPath, sb.String(), legacyCodexSection); err != nil {
 		return SyncResult{
 			Target:  "Codex",
 			Success: false,
-			Message: fmt.Sprintf("cannot write instructions.md: %v", err),
+			Message: fmt.Sprintf("cannot update instructions.md: %v", err),
 		}
 	}
 
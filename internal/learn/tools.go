@@ -0,0 +1,418 @@
+package learn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ToolEvent is a tool call and its result from a session transcript.
+type ToolEvent struct {
+	ID        string
+	Tool      string // Bash, Edit, Write, Task, ...
+	Input     string // command for Bash, file path for edits, summary otherwise
+	Output    string // tool result text
+	IsError   bool
+	Subagent  bool     // run by a subagent
+	Agent     string   // subagent transcript name, or "sidechain" for inline subagent messages
+	Pos       Position // where the tool_use is in its transcript
+	Timestamp time.Time
+}
+
+// Failed reports whether the tool call errored or the command exited non-zero.
+func (e ToolEvent) Failed() bool {
+	return e.IsError || exitCodeRe.MatchString(e.Output)
+}
+
+var exitCodeRe = regexp.MustCompile(`(?i)exit (code|status):? [1-9]`)
+
+// toolBlock is a tool_use or tool_result content block.
+type toolBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// toolEventCollector pairs tool_use blocks with their tool_result blocks.
+type toolEventCollector struct {
+	events []ToolEvent
+	byID   map[string]int
+}
+
+func newToolEventCollector() *toolEventCollector {
+	return &toolEventCollector{byID: make(map[string]int)}
+}
+
+// add records the tool blocks in a message's content.
+func (c *toolEventCollector) add(raw json.RawMessage, sidechain bool, ts time.Time, pos Position) {
+	var blocks []toolBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return // plain string content
+	}
+
+	for _, b := range blocks {
+		switch b.Type {
+		case "tool_use":
+			ev := ToolEvent{
+				ID:        b.ID,
+				Tool:      b.Name,
+				Input:     summarizeToolInput(b.Input),
+				Subagent:  sidechain,
+				Pos:       pos,
+				Timestamp: ts,
+			}
+			if sidechain {
+				ev.Agent = "sidechain"
+			}
+			c.byID[b.ID] = len(c.events)
+			c.events = append(c.events, ev)
+		case "tool_result":
+			i, ok := c.byID[b.ToolUseID]
+			if !ok {
+				continue
+			}
+			c.events[i].Output = truncateText(extractText(b.Content), 2000)
+			c.events[i].IsError = b.IsError
+		}
+	}
+}
+
+// summarizeToolInput keeps the part of a tool input that matters for
+// extraction: the command, the file edited, or the subagent task.
+func summarizeToolInput(raw json.RawMessage) string {
+	var input map[string]any
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return ""
+	}
+	for _, key := range []string{"command", "file_path", "notebook_path", "description", "pattern", "url"} {
+		if v, ok := input[key].(string); ok && v != "" {
+			return truncateText(v, 300)
+		}
+	}
+	return truncateText(string(raw), 200)
+}
+
+// subagentTranscripts finds transcripts written by a session's subagents:
+// <session>/subagents/*.jsonl, and agent-*.jsonl files next to the session
+// that reference its ID.
+func subagentTranscripts(sessionPath, sessionID string) []string {
+	paths, _ := filepath.Glob(filepath.Join(strings.TrimSuffix(sessionPath, ".jsonl"), "subagents", "*.jsonl"))
+
+	siblings, _ := filepath.Glob(filepath.Join(filepath.Dir(sessionPath), "agent-*.jsonl"))
+	for _, p := range siblings {
+		if p != sessionPath && transcriptSessionID(p) == sessionID {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// transcriptSessionID returns the sessionId of the first line that has one.
+func transcriptSessionID(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for i := 0; i < 5 && scanner.Scan(); i++ {
+		var msg jsonlMessage
+		if json.Unmarshal(scanner.Bytes(), &msg) == nil && msg.SessionID != "" {
+			return msg.SessionID
+		}
+	}
+	return ""
+}
+
+// CommandFix is a command that failed, the error it hit, and what was done
+// before the same command succeeded.
+type CommandFix struct {
+	Command  string   // the failing command
+	Error    string   // the most telling error line(s)
+	Fix      []string // edits and commands run in between
+	Retry    string   // the command that then succeeded
+	Subagent bool
+
+	Pos       Position // the first failing call
+	EndLine   int      // transcript line of the successful retry
+	Timestamp time.Time
+}
+
+// CommandFixes finds failed → fixed → succeeded sequences of shell commands.
+// Each agent's events are followed separately.
+func CommandFixes(events []ToolEvent) []CommandFix {
+	var order []string
+	byAgent := make(map[string][]ToolEvent)
+	for _, e := range events {
+		if _, ok := byAgent[e.Agent]; !ok {
+			order = append(order, e.Agent)
+		}
+		byAgent[e.Agent] = append(byAgent[e.Agent], e)
+	}
+
+	var fixes []CommandFix
+	for _, agent := range order {
+		fixes = append(fixes, commandFixes(byAgent[agent])...)
+	}
+	return fixes
+}
+
+func commandFixes(events []ToolEvent) []CommandFix {
+	var fixes []CommandFix
+	var pending *CommandFix
+
+	for _, e := range events {
+		switch e.Tool {
+		case "Bash":
+			key := commandKey(e.Input)
+			if key == "" {
+				continue
+			}
+			if pending != nil && key == commandKey(pending.Command) {
+				if e.Failed() {
+					// Still failing: keep the latest error and keep collecting
+					if msg := errorSummary(e.Output); msg != "" {
+						pending.Error = msg
+					}
+					continue
+				}
+				if len(pending.Fix) > 0 || e.Input != pending.Command {
+					pending.Retry = e.Input
+					if e.Pos.Path == pending.Pos.Path {
+						pending.EndLine = e.Pos.Line
+					}
+					fixes = append(fixes, *pending)
+				}
+				pending = nil
+				continue
+			}
+			if e.Failed() {
+				if pending == nil {
+					pending = &CommandFix{
+						Command:   e.Input,
+						Error:     errorSummary(e.Output),
+						Subagent:  e.Subagent,
+						Pos:       e.Pos,
+						Timestamp: e.Timestamp,
+					}
+				}
+				continue
+			}
+			if pending != nil {
+				pending.Fix = appendUniqueString(pending.Fix, fmt.Sprintf("Ran `%s`", truncateText(e.Input, 120)))
+			}
+		case "Edit", "MultiEdit", "Write", "NotebookEdit":
+			if pending != nil && e.Input != "" && !e.Failed() {
+				pending.Fix = appendUniqueString(pending.Fix, "Edited "+e.Input)
+			}
+		}
+	}
+
+	return fixes
+}
+
+// commandKey identifies "the same command" across retries: the program and
+// its subcommand, ignoring flags, paths, and env assignments.
+func commandKey(cmd string) string {
+	// Only the last command of a chain is what's being retried
+	for _, sep := range []string{"&&", ";", "||"} {
+		if i := strings.LastIndex(cmd, sep); i >= 0 {
+			cmd = cmd[i+len(sep):]
+		}
+	}
+
+	var parts []string
+	for _, f := range strings.Fields(cmd) {
+		if strings.Contains(f, "=") && len(parts) == 0 {
+			continue // FOO=bar prefix
+		}
+		if strings.HasPrefix(f, "-") || strings.ContainsAny(f, "/.|>") {
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		parts = append(parts, f)
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+var errorLineRe = regexp.MustCompile(`(?i)\b(error|fail(ed|ure)?|panic|cannot|undefined|not found|denied|fatal|exception|traceback)\b`)
+
+// errorSummary picks the lines that explain a failure.
+func errorSummary(output string) string {
+	var lines []string
+	var last string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		last = line
+		if errorLineRe.MatchString(line) && !exitCodeRe.MatchString(line) {
+			lines = append(lines, line)
+			if len(lines) == 3 {
+				break
+			}
+		}
+	}
+	if len(lines) == 0 && last != "" {
+		lines = []string{last}
+	}
+	return truncateText(strings.Join(lines, "\n"), 300)
+}
+
+func appendUniqueString(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// devopsCommands are programs whose fixes are filed under the devops domain.
+var devopsCommands = map[string]bool{
+	"docker": true, "kubectl": true, "helm": true, "terraform": true,
+	"ansible": true, "gcloud": true, "aws": true, "az": true,
+}
+
+// ExtractFromCommandFixes turns command/error/fix triples into patterns.
+func ExtractFromCommandFixes(fixes []CommandFix, sourceID string) []ExtractedPattern {
+	var extracted []ExtractedPattern
+	seen := make(map[string]bool)
+
+	for _, f := range fixes {
+		key := commandKey(f.Command)
+		if key == "" || f.Error == "" {
+			continue
+		}
+
+		words := strings.Fields(key)
+		for _, w := range extractSignificantWords(f.Error) {
+			if len(words) == 4 {
+				break
+			}
+			words = appendUniqueString(words, w)
+		}
+		name := "fix-" + strings.Join(words, "-")
+		name = regexp.MustCompile(`[^a-z0-9-]`).ReplaceAllString(strings.ToLower(name), "")
+		if len(name) > 40 {
+			name = strings.TrimRight(name[:40], "-")
+		}
+		if !isValidPatternName(name) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var sb strings.Builder
+		sb.WriteString("## Problem\n")
+		fmt.Fprintf(&sb, "`%s` failed:\n\n", f.Command)
+		for _, line := range strings.Split(f.Error, "\n") {
+			sb.WriteString("    " + line + "\n")
+		}
+		sb.WriteString("\n## Fix\n")
+		for _, step := range f.Fix {
+			sb.WriteString("- " + step + "\n")
+		}
+		if len(f.Fix) == 0 {
+			fmt.Fprintf(&sb, "- Ran `%s` instead\n", f.Retry)
+		}
+		sb.WriteString("\n## Verification\n")
+		fmt.Fprintf(&sb, "`%s` succeeded afterwards.", f.Retry)
+
+		confidence := 0.55
+		if errorLineRe.MatchString(f.Error) {
+			confidence += 0.1
+		}
+		if len(f.Fix) > 0 {
+			confidence += 0.1
+		}
+
+		program := strings.Fields(key)[0]
+		domain := "dev"
+		if devopsCommands[program] {
+			domain = "devops"
+		}
+		tags := []string{program}
+		if f.Subagent {
+			tags = append(tags, "subagent")
+		}
+
+		now := time.Now().Format(time.RFC3339)
+		extracted = append(extracted, ExtractedPattern{
+			Pattern: Pattern{
+				Name:        name,
+				Description: fmt.Sprintf("Fix for `%s` failing: %s", key, truncateText(strings.SplitN(f.Error, "\n", 2)[0], 80)),
+				Content:     sb.String(),
+				Domain:      domain,
+				Category:    "lesson",
+				Tags:        tags,
+				Confidence:  confidence,
+				Source:      f.sourceRef(sourceID),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			},
+			Source:     sourceID,
+			Evidence:   []string{truncateEvidence("$ "+f.Command+"\n"+f.Error, 200)},
+			Confidence: confidence,
+		})
+	}
+
+	return extracted
+}
+
+// ExtractFromSessionData extracts patterns from a loaded session's messages
+// and from the command/error/fix sequences in its tool calls.
+func ExtractFromSessionData(s *Session) ([]ExtractedPattern, error) {
+	extracted, err := ExtractFromMessages(s.AssistantMessages(), s.ShortID())
+	if err != nil {
+		return nil, err
+	}
+
+	extracted = append(extracted, ExtractFromCommandFixes(CommandFixes(s.ToolEvents), s.ShortID())...)
+	sortByConfidence(extracted)
+	if len(extracted) > 10 {
+		extracted = extracted[:10]
+	}
+	ValidateExtracted(extracted)
+	s.attachSource(extracted)
+	return extracted, nil
+}
+
+// ToolActivity summarizes command fixes and failed tool calls for LLM
+// extraction, most recent last.
+func (s *Session) ToolActivity() string {
+	fixes := CommandFixes(s.ToolEvents)
+	if len(fixes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, f := range fixes {
+		who := ""
+		if f.Subagent {
+			who = " (subagent)"
+		}
+		fmt.Fprintf(&sb, "- Command%s: %s\n  Error: %s\n", who, f.Command, strings.ReplaceAll(f.Error, "\n", " | "))
+		if len(f.Fix) > 0 {
+			fmt.Fprintf(&sb, "  Fix: %s\n", strings.Join(f.Fix, "; "))
+		}
+		fmt.Fprintf(&sb, "  Then succeeded: %s\n", f.Retry)
+	}
+	return sb.String()
+}
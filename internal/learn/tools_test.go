@@ -0,0 +1,130 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mainTranscript = `{"type":"user","sessionId":"s1","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"fix the build"}}
+{"type":"assistant","sessionId":"s1","timestamp":"2026-01-01T00:00:01Z","message":{"role":"assistant","content":[{"type":"text","text":"Running the tests."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","sessionId":"s1","timestamp":"2026-01-01T00:00:02Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"# example/store\nstore/db.go:12:2: undefined: sqlOpen\nFAIL\texample/store [build failed]"}]}}
+{"type":"assistant","sessionId":"s1","timestamp":"2026-01-01T00:00:03Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"store/db.go","old_string":"sqlOpen","new_string":"sql.Open"}}]}}
+{"type":"user","sessionId":"s1","timestamp":"2026-01-01T00:00:04Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]}}
+{"type":"assistant","sessionId":"s1","timestamp":"2026-01-01T00:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go test ./... 2>&1 | tail -5"}}]}}
+{"type":"user","sessionId":"s1","timestamp":"2026-01-01T00:00:06Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":"ok  \texample/store\t0.01s"}]}}
+`
+
+const agentTranscript = `{"type":"assistant","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"a1","name":"Bash","input":{"command":"npm run build"}}]}}
+{"type":"user","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:01Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"a1","content":"Error: Cannot find module 'left-pad'\nExit code 1"}]}}
+{"type":"assistant","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:02Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"a2","name":"Bash","input":{"command":"npm install left-pad"}}]}}
+{"type":"user","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:03Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"a2","content":"added 1 package"}]}}
+{"type":"assistant","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:04Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"a3","name":"Bash","input":{"command":"npm run build"}}]}}
+{"type":"user","sessionId":"s1","isSidechain":true,"timestamp":"2026-01-01T00:01:05Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"a3","content":"built in 2s"}]}}
+`
+
+func writeTranscripts(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := os.MkdirAll(filepath.Join(dir, "s1", "subagents"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sessionPath := filepath.Join(dir, "s1.jsonl")
+	if err := os.WriteFile(sessionPath, []byte(mainTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "s1", "subagents", "agent-build.jsonl"), []byte(agentTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return sessionPath
+}
+
+func TestLoadSessionToolEvents(t *testing.T) {
+	s, err := LoadSession(writeTranscripts(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.ToolEvents) != 6 {
+		t.Fatalf("got %d tool events, want 6", len(s.ToolEvents))
+	}
+	first := s.ToolEvents[0]
+	if first.Tool != "Bash" || first.Input != "go test ./..." || !first.Failed() || !strings.Contains(first.Output, "undefined: sqlOpen") {
+		t.Errorf("first event = %+v", first)
+	}
+	if s.ToolEvents[1].Input != "store/db.go" {
+		t.Errorf("edit input = %q, want file path", s.ToolEvents[1].Input)
+	}
+	if agent := s.ToolEvents[3]; !agent.Subagent || agent.Agent != "agent-build" {
+		t.Errorf("subagent event = %+v", agent)
+	}
+	if s.ToolUseCount != 6 {
+		t.Errorf("ToolUseCount = %d, want 6", s.ToolUseCount)
+	}
+}
+
+func TestCommandFixes(t *testing.T) {
+	s, err := LoadSession(writeTranscripts(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixes := CommandFixes(s.ToolEvents)
+	if len(fixes) != 2 {
+		t.Fatalf("got %d fixes, want 2: %+v", len(fixes), fixes)
+	}
+
+	goFix := fixes[0]
+	if goFix.Command != "go test ./..." || !strings.Contains(goFix.Error, "undefined: sqlOpen") {
+		t.Errorf("go fix = %+v", goFix)
+	}
+	if len(goFix.Fix) != 1 || goFix.Fix[0] != "Edited store/db.go" {
+		t.Errorf("go fix steps = %v", goFix.Fix)
+	}
+
+	npmFix := fixes[1]
+	if !npmFix.Subagent || npmFix.Error != "Error: Cannot find module 'left-pad'" {
+		t.Errorf("npm fix = %+v", npmFix)
+	}
+	if len(npmFix.Fix) != 1 || npmFix.Fix[0] != "Ran `npm install left-pad`" {
+		t.Errorf("npm fix steps = %v", npmFix.Fix)
+	}
+}
+
+func TestExtractFromCommandFixes(t *testing.T) {
+	extracted := ExtractFromCommandFixes([]CommandFix{{
+		Command: "go test ./...",
+		Error:   "store/db.go:12:2: undefined: sqlOpen",
+		Fix:     []string{"Edited store/db.go"},
+		Retry:   "go test ./...",
+	}}, "s1")
+
+	if len(extracted) != 1 {
+		t.Fatalf("got %d patterns, want 1", len(extracted))
+	}
+	p := extracted[0].Pattern
+	if !strings.HasPrefix(p.Name, "fix-go-test") || p.Category != "lesson" {
+		t.Errorf("pattern = %s (%s)", p.Name, p.Category)
+	}
+	for _, want := range []string{"`go test ./...` failed", "undefined: sqlOpen", "- Edited store/db.go", "succeeded afterwards"} {
+		if !strings.Contains(p.Content, want) {
+			t.Errorf("content missing %q:\n%s", want, p.Content)
+		}
+	}
+}
+
+func TestCommandKey(t *testing.T) {
+	tests := []struct{ cmd, want string }{
+		{"go test ./...", "go test"},
+		{"go test -run TestX ./store", "go test"},
+		{"cd web && npm run build 2>&1", "npm run"},
+		{"CGO_ENABLED=0 go build ./cmd/mur", "go build"},
+		{"./scripts/release.sh", ""},
+	}
+	for _, tt := range tests {
+		if got := commandKey(tt.cmd); got != tt.want {
+			t.Errorf("commandKey(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package learn
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Turn is one user prompt and the work that followed it, up to the next
+// prompt.
+type Turn struct {
+	Prompt string
+	Start  time.Time
+	Work   string // assistant text and tool input, including subagents'
+	// Failed is set when the turn's last tool call failed, or the next
+	// prompt reads as a correction ("no, ...", "still failing").
+	Failed bool
+}
+
+// correctionRe matches prompts that say the previous turn went wrong.
+var correctionRe = regexp.MustCompile(`(?i)^\W*(no\b|nope\b|wrong\b|that'?s (not|wrong)|(that|it) (didn'?t|doesn'?t|does not|did not) work|(it'?s )?still (fail|broken|not working|the same)|not working\b|revert\b|undo\b)`)
+
+// IsCorrection reports whether prompt tells the assistant its last
+// answer was wrong.
+func IsCorrection(prompt string) bool {
+	return correctionRe.MatchString(prompt)
+}
+
+// Turns splits the session into turns by the main session's user
+// prompts, in time order. Messages and tool calls without timestamps are
+// left out.
+func (s *Session) Turns() []Turn {
+	var turns []Turn
+	for _, m := range s.Messages {
+		if m.Role == "user" && !m.Subagent && !m.Timestamp.IsZero() {
+			turns = append(turns, Turn{Prompt: m.Content, Start: m.Timestamp})
+		}
+	}
+	sort.SliceStable(turns, func(i, j int) bool { return turns[i].Start.Before(turns[j].Start) })
+
+	// turnAt returns the index of the turn running at t, or -1
+	turnAt := func(t time.Time) int {
+		i := sort.Search(len(turns), func(i int) bool { return turns[i].Start.After(t) })
+		return i - 1
+	}
+
+	work := make([]strings.Builder, len(turns))
+	for _, m := range s.Messages {
+		if m.Role != "assistant" || m.Timestamp.IsZero() {
+			continue
+		}
+		if i := turnAt(m.Timestamp); i >= 0 {
+			work[i].WriteString(m.Content)
+			work[i].WriteString("\n")
+		}
+	}
+	lastTool := make([]time.Time, len(turns))
+	for _, e := range s.ToolEvents {
+		if e.Timestamp.IsZero() {
+			continue
+		}
+		i := turnAt(e.Timestamp)
+		if i < 0 {
+			continue
+		}
+		work[i].WriteString(e.Input)
+		work[i].WriteString("\n")
+		if !e.Subagent && !e.Timestamp.Before(lastTool[i]) {
+			lastTool[i] = e.Timestamp
+			turns[i].Failed = e.Failed()
+		}
+	}
+
+	for i := range turns {
+		turns[i].Work = work[i].String()
+		if i+1 < len(turns) && IsCorrection(turns[i+1].Prompt) {
+			turns[i].Failed = true
+		}
+	}
+	return turns
+}
@@ -0,0 +1,55 @@
+package learn
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTurns(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2026, 1, 1, 0, 0, sec, 0, time.UTC) }
+	s := &Session{
+		Messages: []SessionMessage{
+			{Role: "user", Content: "fix the build", Timestamp: at(0)},
+			{Role: "assistant", Content: "Using errors.Is here.", Timestamp: at(1)},
+			{Role: "user", Content: "No, that's the wrong file", Timestamp: at(10)},
+			{Role: "assistant", Content: "Sorry, fixing store.go", Timestamp: at(11)},
+			{Role: "user", Content: "thanks, now add a test", Timestamp: at(20)},
+			{Role: "assistant", Content: "Added.", Timestamp: at(21)},
+			{Role: "assistant", Content: "subagent notes", Subagent: true, Timestamp: at(22)},
+		},
+		ToolEvents: []ToolEvent{
+			{Tool: "Bash", Input: "go build ./...", Timestamp: at(12)},
+			{Tool: "Bash", Input: "go test ./store", IsError: true, Timestamp: at(23)},
+		},
+	}
+
+	turns := s.Turns()
+	if len(turns) != 3 {
+		t.Fatalf("got %d turns, want 3", len(turns))
+	}
+	if !turns[0].Failed {
+		t.Error("turn followed by a correction should fail")
+	}
+	if turns[1].Failed || !strings.Contains(turns[1].Work, "go build ./...") {
+		t.Errorf("turn 2 = %+v", turns[1])
+	}
+	if !turns[2].Failed || !strings.Contains(turns[2].Work, "subagent notes") {
+		t.Errorf("turn ending in a failed command = %+v", turns[2])
+	}
+}
+
+func TestIsCorrection(t *testing.T) {
+	for prompt, want := range map[string]bool{
+		"no, use the other API":    true,
+		"That didn't work":         true,
+		"still failing on CI":      true,
+		"revert that":              true,
+		"now add a test":           false,
+		"notify me when it's done": false,
+	} {
+		if got := IsCorrection(prompt); got != want {
+			t.Errorf("IsCorrection(%q) = %v, want %v", prompt, got, want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package learn
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// Code blocks in extracted patterns are parsed, never executed. Shell blocks
+// go through mvdan.cc/sh's Bash parser; Go blocks through go/parser plus a
+// printf arity check in the spirit of `go vet`. Zsh blocks aren't checked,
+// since no parser here accepts zsh's syntax.
+
+var fencedBlockRe = regexp.MustCompile("(?m)^[ \t]*```[ \t]*([A-Za-z0-9_+-]*)[^\n]*\n([\\s\\S]*?)^[ \t]*```")
+
+// CodeBlock is a fenced code block found in pattern content.
+type CodeBlock struct {
+	Lang string
+	Code string
+}
+
+// CodeProblem describes a code block that failed to parse.
+type CodeProblem struct {
+	Lang  string
+	Block int // 1-based index among the pattern's code blocks
+	Err   string
+}
+
+func (p CodeProblem) String() string {
+	return fmt.Sprintf("%s block %d: %s", p.Lang, p.Block, p.Err)
+}
+
+// CodeBlocks returns the fenced code blocks in text with their language tag.
+func CodeBlocks(text string) []CodeBlock {
+	var blocks []CodeBlock
+	for _, m := range fencedBlockRe.FindAllStringSubmatch(text, -1) {
+		blocks = append(blocks, CodeBlock{Lang: strings.ToLower(m[1]), Code: m[2]})
+	}
+	return blocks
+}
+
+// CheckCode parses the shell and Go blocks in content. checked is false when
+// there was nothing it knows how to parse.
+func CheckCode(content string) (checked bool, problems []CodeProblem) {
+	for i, b := range CodeBlocks(content) {
+		var err error
+		switch b.Lang {
+		case "sh", "bash", "shell":
+			err = checkShell(shellCommands(b.Code))
+		case "go", "golang":
+			err = checkGo(b.Code)
+		default:
+			continue
+		}
+		checked = true
+		if err != nil {
+			problems = append(problems, CodeProblem{Lang: b.Lang, Block: i + 1, Err: err.Error()})
+		}
+	}
+	return checked, problems
+}
+
+// ValidateExtracted sets Pattern.Validated on each pattern with checkable
+// code and records what failed to parse.
+func ValidateExtracted(patterns []ExtractedPattern) {
+	for i := range patterns {
+		checked, problems := CheckCode(patterns[i].Pattern.Content)
+		if !checked {
+			continue
+		}
+		ok := len(problems) == 0
+		patterns[i].Pattern.Validated = &ok
+		patterns[i].CodeProblems = problems
+	}
+}
+
+// shellCommands strips "$ " prompts from transcript-style blocks, dropping
+// the output lines between them.
+func shellCommands(code string) string {
+	lines := strings.Split(code, "\n")
+	prompted := false
+	for _, l := range lines {
+		if t := strings.TrimSpace(l); t != "" {
+			prompted = strings.HasPrefix(t, "$ ")
+			break
+		}
+	}
+	if !prompted {
+		return code
+	}
+
+	var cmds []string
+	for _, l := range lines {
+		if t := strings.TrimSpace(l); strings.HasPrefix(t, "$ ") {
+			cmds = append(cmds, strings.TrimPrefix(t, "$ "))
+		}
+	}
+	return strings.Join(cmds, "\n")
+}
+
+var (
+	goDeclRe = regexp.MustCompile(`^(func|type|import)\b`)
+	goPosRe  = regexp.MustCompile(`^(\d+):(\d+): (.*)$`)
+)
+
+// checkGo parses src as a file, as declarations or as statements, whichever
+// fits. When none do, the error comes from the form src most looks like.
+func checkGo(src string) error {
+	trimmed := strings.TrimSpace(src)
+	if strings.HasPrefix(trimmed, "package ") {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+		if err != nil {
+			return goParseError(err, 0)
+		}
+		return vetGo(fset, f, 0)
+	}
+
+	attempts := []struct {
+		src    string
+		offset int // lines added before src
+	}{
+		{"package snippet\n" + src, 1},
+		{"package snippet\nfunc _() {\n" + src + "\n}", 2},
+	}
+	var errs [2]error
+	for i, a := range attempts {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", a.src, parser.SkipObjectResolution)
+		if err == nil {
+			return vetGo(fset, f, a.offset)
+		}
+		errs[i] = goParseError(err, a.offset)
+	}
+	if goDeclRe.MatchString(trimmed) {
+		return errs[0]
+	}
+	return errs[1]
+}
+
+func goParseError(err error, offset int) error {
+	msg := err.Error()
+	if m := goPosRe.FindStringSubmatch(strings.SplitN(msg, "\n", 2)[0]); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return fmt.Errorf("line %d: %s", line-offset, m[3])
+	}
+	return err
+}
+
+var printfFuncs = map[string]int{
+	"fmt.Printf":  0,
+	"fmt.Sprintf": 0,
+	"fmt.Errorf":  0,
+	"fmt.Fprintf": 1,
+	"log.Printf":  0,
+	"log.Fatalf":  0,
+	"t.Errorf":    0,
+	"t.Fatalf":    0,
+	"t.Logf":      0,
+}
+
+// vetGo reports printf calls whose verb count doesn't match their arguments.
+func vetGo(fset *token.FileSet, f *ast.File, offset int) error {
+	var vetErr error
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || vetErr != nil || call.Ellipsis.IsValid() {
+			return vetErr == nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		name := pkg.Name + "." + sel.Sel.Name
+		idx, ok := printfFuncs[name]
+		if !ok || len(call.Args) <= idx {
+			return true
+		}
+		lit, ok := call.Args[idx].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		format, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		want, got := countVerbs(format), len(call.Args)-idx-1
+		if want >= 0 && want != got {
+			line := fset.Position(call.Pos()).Line - offset
+			vetErr = fmt.Errorf("line %d: %s format has %d verbs but %d args", line, name, want, got)
+		}
+		return true
+	})
+	return vetErr
+}
+
+// countVerbs counts the arguments a printf format consumes, or -1 when it
+// uses features (explicit indexes, * widths) this check doesn't model.
+func countVerbs(format string) int {
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		switch format[i] {
+		case '%':
+		case '[', '*':
+			return -1
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// checkShell parses src as a Bash script.
+func checkShell(src string) error {
+	_, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(src), "")
+	var perr syntax.ParseError
+	if errors.As(err, &perr) {
+		return fmt.Errorf("line %d: %s", perr.Pos.Line(), perr.Text)
+	}
+	var lerr syntax.LangError
+	if errors.As(err, &lerr) {
+		return fmt.Errorf("line %d: %s is not supported by bash", lerr.Pos.Line(), lerr.Feature)
+	}
+	return err
+}
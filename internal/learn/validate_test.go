@@ -0,0 +1,138 @@
+package learn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckShell(t *testing.T) {
+	valid := []string{
+		"go test ./... 2>&1 | tail -5",
+		"if [ -f go.mod ]; then\n  go build ./...\nelif true; then :\nelse\n  echo 'no module'\nfi",
+		"for f in *.go; do\n  gofmt -l \"$f\"\ndone",
+		"f() { echo `pwd`; }\nf # call it's function",
+		"$ npm install left-pad\nadded 1 package (\n$ npm run build",
+		"FOO=1 BAR=2 if_cmd --flag \\\n  --other",
+
+		// heredocs
+		"cat <<EOF > out.txt\nit's fine: \"unbalanced\n(\nEOF\necho done",
+		"cat <<-'END'\n\tliteral $(not run)\n\tEND",
+		"cat <<A; cat <<\"B\"\none\nA\n$two\nB",
+		"kubectl apply -f - <<YAML\nkind: ConfigMap\ndata: {key: \"$(date)\"}\nYAML",
+
+		// case
+		"case \"$1\" in\n  start|run) echo go ;;\n  (stop) exit 1 ;;\n  *) echo \"usage: $0 {start|stop}\"\nesac",
+		"case $x in a) ;& b) echo ;;& *) esac",
+		"x=$(case $1 in (a) echo a;; esac)",
+
+		// arithmetic
+		"echo ${HOME:-/tmp} $((1 + 2)) $'a\\'b'",
+		"(( i++ )); for ((i = 0; i < 3; i++)); do echo $((i << 2)); done",
+		"n=$(( (RANDOM % 10) + ${#arr[@]} ))",
+		"let 'x = 2 * 3'",
+
+		// process substitution
+		"diff <(sort a) <(sort b) &> /dev/null &",
+		"tee >(gzip > out.gz) < in | wc -l",
+		"while read -r l; do echo \"$l\"; done < <(git ls-files)",
+
+		// nested quotes
+		"x=$(if true; then echo \"$(date +%s)\"; fi) && echo \"$x\"",
+		"echo \"outer $(echo \"inner $(echo 'deep \"quote\"')\")\"",
+		"echo \"${var//\\\"/\\'}\" '\"' \"'\"",
+		"jq -r '.items[] | \"\\(.name): \\(.value)\"' data.json",
+		"echo \"$(printf '%s' \"it's\")\" `echo \\`echo nested\\``",
+	}
+	for _, src := range valid {
+		if err := checkShell(shellCommands(src)); err != nil {
+			t.Errorf("checkShell(%q) = %v, want nil", src, err)
+		}
+	}
+
+	invalid := []struct{ src, want string }{
+		{"echo 'unterminated", "line 1: reached EOF without closing quote '"},
+		{"echo \"unterminated", `closing quote "`},
+		{"if true; then\n  echo hi\n", `must end with "fi"`},
+		{"for f in *; do echo $f", `must end with "done"`},
+		{"echo $(date", "without matching ( with )"},
+		{"echo hi\nfi", `line 2: "fi" can only be used to end an if`},
+		{"ls |", "| must be followed by a statement"},
+		{"make && ; echo", "&& must be followed by a statement"},
+		{"echo )", "encountered )"},
+		{"cat <<EOF\nbody", "unclosed here-document"},
+		{"{ echo hi", "without matching { with }"},
+		{"case x in\n  a) echo\n", `must end with "esac"`},
+	}
+	for _, tt := range invalid {
+		err := checkShell(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("checkShell(%q) = %v, want error containing %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestCheckGo(t *testing.T) {
+	valid := []string{
+		"package main\n\nfunc main() {}",
+		"func wrap(err error) error {\n\treturn fmt.Errorf(\"open: %w\", err)\n}",
+		"if err != nil {\n\treturn err\n}",
+		"fmt.Printf(\"%d%% of %s\\n\", n, name)",
+		"fmt.Printf(\"%[1]d %[1]d\", n)",
+	}
+	for _, src := range valid {
+		if err := checkGo(src); err != nil {
+			t.Errorf("checkGo(%q) = %v, want nil", src, err)
+		}
+	}
+
+	invalid := []struct{ src, want string }{
+		{"func main() {\n\tx := \n}", "line 3"},
+		{"if err != nil {\n\treturn err\n", "line"},
+		{"x := 1\nfmt.Printf(\"%s %d\\n\", x)", "line 2: fmt.Printf format has 2 verbs but 1 args"},
+		{"package main\nfunc {", "line 2"},
+	}
+	for _, tt := range invalid {
+		err := checkGo(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("checkGo(%q) = %v, want error containing %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestValidateExtracted(t *testing.T) {
+	patterns := []ExtractedPattern{
+		{Pattern: Pattern{Name: "ok", Content: "Run:\n```bash\ngo test ./...\n```\n"}},
+		{Pattern: Pattern{Name: "broken", Content: "```sh\necho 'oops\n```\n```go\nx := 1\n```\n"}},
+		{Pattern: Pattern{Name: "prose", Content: "No code here.\n```yaml\nkey: [\n```\n"}},
+	}
+	ValidateExtracted(patterns)
+
+	if v := patterns[0].Pattern.Validated; v == nil || !*v {
+		t.Errorf("ok: Validated = %v, want true", v)
+	}
+	if v := patterns[1].Pattern.Validated; v == nil || *v {
+		t.Errorf("broken: Validated = %v, want false", v)
+	}
+	if probs := patterns[1].CodeProblems; len(probs) != 1 || probs[0].Lang != "sh" || probs[0].Block != 1 {
+		t.Errorf("broken: problems = %+v", probs)
+	}
+	if patterns[2].Pattern.Validated != nil {
+		t.Error("prose: Validated should be unset when no code was checked")
+	}
+}
+
+func TestValidatePatternRejectsInvalidCode(t *testing.T) {
+	cfg := DefaultExtractionConfig()
+	cfg.RequireProblemSolve = false
+	cfg.MinContentLength = 0
+
+	p := Pattern{Name: "docker-cleanup", Content: "```bash\ndocker ps -aq | xargs docker rm |\n```"}
+	if ok, reason := ValidatePattern(p, cfg); ok || !strings.Contains(reason, "code does not parse") {
+		t.Errorf("ValidatePattern = %v, %q; want rejection", ok, reason)
+	}
+
+	cfg.RejectInvalidCode = false
+	if ok, reason := ValidatePattern(p, cfg); !ok {
+		t.Errorf("with RejectInvalidCode off: rejected (%s)", reason)
+	}
+}
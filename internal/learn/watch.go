@@ -0,0 +1,173 @@
+package learn
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchOptions controls incremental extraction from sessions that are still growing.
+type WatchOptions struct {
+	Interval     time.Duration // How often session files are polled (default: 10s)
+	MinMessages  int           // New messages required to trigger extraction (default: 20)
+	IdleAfter    time.Duration // Extract pending messages once a session is idle this long (default: 2m)
+	ActiveWithin time.Duration // Only watch sessions modified within this window (default: 1h)
+}
+
+// DefaultWatchOptions returns sensible watch thresholds.
+func DefaultWatchOptions() WatchOptions {
+	return WatchOptions{
+		Interval:     10 * time.Second,
+		MinMessages:  20,
+		IdleAfter:    2 * time.Minute,
+		ActiveWithin: time.Hour,
+	}
+}
+
+// WatchExtractFunc extracts patterns from a window of new session messages.
+type WatchExtractFunc func(window *Session) ([]ExtractedPattern, error)
+
+// WatchBatch is the result of one incremental extraction.
+type WatchBatch struct {
+	Session  *Session           // Window of messages that were extracted
+	Reason   string             // "messages" or "idle"
+	Patterns []ExtractedPattern // Patterns found in the window
+	Err      error              // Extraction error, if any
+}
+
+// watchedSession tracks extraction progress for one session file.
+type watchedSession struct {
+	size       int64     // Last observed file size
+	lastGrowth time.Time // When the file last grew
+	processed  int       // Messages already extracted
+	pending    int       // Messages seen but not yet extracted
+}
+
+// SessionWatcher tails active session files and extracts patterns
+// incrementally as they grow, instead of waiting for the Stop hook.
+type SessionWatcher struct {
+	opts    WatchOptions
+	extract WatchExtractFunc
+	list    func() ([]Session, error)
+	state   map[string]*watchedSession
+}
+
+// NewSessionWatcher creates a watcher over Claude Code and OpenClaw sessions.
+// Zero-valued options fall back to DefaultWatchOptions.
+func NewSessionWatcher(opts WatchOptions, extract WatchExtractFunc) *SessionWatcher {
+	defaults := DefaultWatchOptions()
+	if opts.Interval <= 0 {
+		opts.Interval = defaults.Interval
+	}
+	if opts.MinMessages <= 0 {
+		opts.MinMessages = defaults.MinMessages
+	}
+	if opts.IdleAfter <= 0 {
+		opts.IdleAfter = defaults.IdleAfter
+	}
+	if opts.ActiveWithin <= 0 {
+		opts.ActiveWithin = defaults.ActiveWithin
+	}
+	if extract == nil {
+		extract = func(s *Session) ([]ExtractedPattern, error) {
+			return ExtractFromSessionData(s)
+		}
+	}
+	return &SessionWatcher{
+		opts:    opts,
+		extract: extract,
+		list:    ListSessions,
+		state:   make(map[string]*watchedSession),
+	}
+}
+
+// Run polls sessions until ctx is cancelled, calling onBatch for every extraction.
+func (w *SessionWatcher) Run(ctx context.Context, onBatch func(WatchBatch)) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, b := range w.Poll(time.Now()) {
+			onBatch(b)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every active session once and extracts from those that crossed
+// the message-count or idle threshold.
+func (w *SessionWatcher) Poll(now time.Time) []WatchBatch {
+	sessions, err := w.list()
+	if err != nil {
+		return nil
+	}
+
+	var batches []WatchBatch
+	for _, s := range sessions {
+		if now.Sub(s.CreatedAt) > w.opts.ActiveWithin {
+			continue
+		}
+
+		info, err := os.Stat(s.Path)
+		if err != nil {
+			continue
+		}
+
+		st, ok := w.state[s.Path]
+		if !ok {
+			st = &watchedSession{}
+			w.state[s.Path] = st
+		}
+
+		grew := info.Size() != st.size
+		if !grew && st.pending == 0 {
+			continue
+		}
+
+		if grew {
+			st.size = info.Size()
+			st.lastGrowth = now
+		}
+
+		full, err := LoadSession(s.Path)
+		if err != nil {
+			continue
+		}
+		if len(full.Messages) < st.processed {
+			// File was truncated or rewritten; start over
+			st.processed = 0
+		}
+		st.pending = len(full.Messages) - st.processed
+
+		reason := ""
+		switch {
+		case st.pending >= w.opts.MinMessages:
+			reason = "messages"
+		case st.pending > 0 && now.Sub(st.lastGrowth) >= w.opts.IdleAfter:
+			reason = "idle"
+		default:
+			continue
+		}
+
+		window := *full
+		window.Messages = full.Messages[st.processed:]
+		patterns, err := w.extract(&window)
+
+		st.processed = len(full.Messages)
+		st.pending = 0
+
+		batches = append(batches, WatchBatch{
+			Session:  &window,
+			Reason:   reason,
+			Patterns: patterns,
+			Err:      err,
+		})
+	}
+
+	return batches
+}
@@ -0,0 +1,75 @@
+package learn
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendMessages(t *testing.T, path string, n int) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for i := 0; i < n; i++ {
+		line := fmt.Sprintf(`{"type":"assistant","timestamp":"2026-01-01T00:00:00Z","message":{"role":"assistant","content":"message %d"}}`, i)
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSessionWatcherThresholds(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proj", "abc12345.jsonl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	appendMessages(t, path, 3)
+
+	var windows []int
+	w := NewSessionWatcher(WatchOptions{MinMessages: 5, IdleAfter: time.Minute}, func(s *Session) ([]ExtractedPattern, error) {
+		windows = append(windows, len(s.Messages))
+		return nil, nil
+	})
+	w.list = func() ([]Session, error) {
+		return []Session{{ID: "abc12345", Project: "proj", Path: path, CreatedAt: time.Now()}}, nil
+	}
+
+	now := time.Now()
+
+	// Below the message threshold and not idle: nothing extracted
+	if batches := w.Poll(now); len(batches) != 0 {
+		t.Fatalf("expected no batches, got %d", len(batches))
+	}
+
+	// Crossing the message threshold extracts all pending messages
+	appendMessages(t, path, 3)
+	batches := w.Poll(now.Add(time.Second))
+	if len(batches) != 1 || batches[0].Reason != "messages" {
+		t.Fatalf("expected one 'messages' batch, got %+v", batches)
+	}
+
+	// Only the new messages are extracted after going idle
+	appendMessages(t, path, 2)
+	if batches := w.Poll(now.Add(2 * time.Second)); len(batches) != 0 {
+		t.Fatalf("expected no batches before idle, got %d", len(batches))
+	}
+	batches = w.Poll(now.Add(2 * time.Minute))
+	if len(batches) != 1 || batches[0].Reason != "idle" {
+		t.Fatalf("expected one 'idle' batch, got %+v", batches)
+	}
+
+	if len(windows) != 2 || windows[0] != 6 || windows[1] != 2 {
+		t.Errorf("unexpected extraction windows: %v", windows)
+	}
+
+	// Nothing new: nothing extracted
+	if batches := w.Poll(now.Add(10 * time.Minute)); len(batches) != 0 {
+		t.Errorf("expected no batches, got %d", len(batches))
+	}
+}
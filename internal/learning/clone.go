@@ -0,0 +1,185 @@
+package learning
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// GeneralDomain is the domain directory every sparse checkout includes.
+const GeneralDomain = "general"
+
+// CloneOptions controls how InitRepo clones the learning repo. By default
+// the clone is shallow, since syncing only needs the latest patterns, and
+// history is fetched later if a merge or push needs it (see Deepen).
+//
+// Shared repos with many patterns can group them by domain in
+// patterns/<domain>/ directories. With Domains set, only those directories
+// are checked out (sparse checkout) and their files downloaded; patterns
+// directly in patterns/ are always checked out.
+type CloneOptions struct {
+	Full    bool     // whole history and every domain
+	Domains []string // domain directories to check out; empty for all
+}
+
+// SparseDomains returns the domains to check out from the learning repo:
+// learning.domains, else the tech stack, plus GeneralDomain. None (all
+// domains) when neither is set.
+func SparseDomains(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	domains := cfg.Learning.Domains
+	if len(domains) == 0 {
+		domains = cfg.GetTechStack()
+	}
+	return normalizeDomains(domains)
+}
+
+// normalizeDomains lowercases and sorts domains and adds GeneralDomain;
+// none stays none.
+func normalizeDomains(domains []string) []string {
+	var out []string
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" && !strings.ContainsAny(d, `/\`) && d != ".." && !slices.Contains(out, d) {
+			out = append(out, d)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	if !slices.Contains(out, GeneralDomain) {
+		out = append(out, GeneralDomain)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// cloneArgs returns the git arguments to clone repoURL into dir.
+func cloneArgs(repoURL, dir string, opts CloneOptions) []string {
+	args := []string{"clone"}
+	if !opts.Full {
+		args = append(args, "--depth", "1")
+		if len(opts.Domains) > 0 {
+			// Files outside the checked-out domains are only downloaded
+			// if something reads them
+			args = append(args, "--filter=blob:none", "--sparse")
+		}
+	}
+	return append(args, "--", repoURL, dir)
+}
+
+// clone clones repoURL into dir as opts says.
+func clone(repoURL, dir string, opts CloneOptions) error {
+	opts.Domains = normalizeDomains(opts.Domains)
+	c := execx.Command("git", cloneArgs(repoURL, dir, opts)...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if _, err := c.Run(context.Background()); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	if opts.Full || len(opts.Domains) == 0 {
+		return nil
+	}
+	return setSparseDomains(dir, opts.Domains)
+}
+
+// setSparseDomains checks out only the given domain directories of the
+// repo at dir, or everything again when there are none.
+func setSparseDomains(dir string, domains []string) error {
+	if len(domains) == 0 {
+		if out, err := gitOutput(dir, "sparse-checkout", "disable"); err != nil {
+			return fmt.Errorf("git sparse-checkout disable failed: %s", out)
+		}
+		return nil
+	}
+	args := []string{"sparse-checkout", "set", "--cone"}
+	for _, d := range domains {
+		args = append(args, path.Join("patterns", d))
+	}
+	if out, err := gitOutput(dir, args...); err != nil {
+		return fmt.Errorf("git sparse-checkout set failed: %s", out)
+	}
+	return nil
+}
+
+// CheckedOutDomains returns the domains a sparse learning repo checkout
+// has, or nil when it has all of them.
+func CheckedOutDomains(dir string) []string {
+	if out, err := gitOutput(dir, "config", "--get", "core.sparseCheckout"); err != nil || out != "true" {
+		return nil
+	}
+	out, err := gitOutput(dir, "sparse-checkout", "list")
+	if err != nil {
+		return nil
+	}
+	var domains []string
+	for _, line := range strings.Split(out, "\n") {
+		if d, ok := strings.CutPrefix(strings.TrimSpace(line), "patterns/"); ok && d != "" {
+			domains = append(domains, d)
+		}
+	}
+	slices.Sort(domains)
+	return domains
+}
+
+// updateSparseDomains keeps a sparse checkout in step with SparseDomains,
+// e.g. after the tech stack changed. Full checkouts are left alone.
+func updateSparseDomains(dir string, cfg *config.Config) error {
+	current := CheckedOutDomains(dir)
+	if current == nil {
+		return nil
+	}
+	wanted := SparseDomains(cfg)
+	if slices.Equal(current, wanted) {
+		return nil
+	}
+	return setSparseDomains(dir, wanted)
+}
+
+// inDomains reports whether a repo path is checked out with domains: any
+// path when domains is nil, and files directly in patterns/.
+func inDomains(file string, domains []string) bool {
+	if domains == nil {
+		return true
+	}
+	rest, ok := strings.CutPrefix(file, "patterns/")
+	if !ok {
+		return true
+	}
+	domain, _, nested := strings.Cut(rest, "/")
+	return !nested || slices.Contains(domains, domain)
+}
+
+// IsShallow reports whether the learning repo at dir is a shallow clone.
+func IsShallow(dir string) bool {
+	out, err := gitOutput(dir, "rev-parse", "--is-shallow-repository")
+	return err == nil && out == "true"
+}
+
+// Deepen fetches the history a shallow clone of the learning repo left
+// out. With a sparse checkout only commits and trees are fetched, not the
+// files of other domains. Nothing to do for a full clone.
+func Deepen() error {
+	if !IsInitialized() {
+		return fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
+	}
+	dir, err := RepoDir()
+	if err != nil {
+		return err
+	}
+	if !IsShallow(dir) {
+		return nil
+	}
+	if out, err := gitOutput(dir, "fetch", "--unshallow", "origin"); err != nil {
+		return fmt.Errorf("git fetch --unshallow failed: %s", out)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package learning
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func TestSparseDomains(t *testing.T) {
+	if got := SparseDomains(&config.Config{}); got != nil {
+		t.Errorf("no tech stack: %v, want all domains", got)
+	}
+	cfg := &config.Config{TechStack: []string{"Go", "docker", "go"}}
+	if got := SparseDomains(cfg); !slices.Equal(got, []string{"docker", "general", "go"}) {
+		t.Errorf("from tech stack: %v", got)
+	}
+	cfg.Learning.Domains = []string{"swift", "../x"}
+	if got := SparseDomains(cfg); !slices.Equal(got, []string{"general", "swift"}) {
+		t.Errorf("learning.domains: %v", got)
+	}
+}
+
+func TestCloneArgs(t *testing.T) {
+	tests := []struct {
+		opts CloneOptions
+		want []string
+	}{
+		{CloneOptions{}, []string{"clone", "--depth", "1", "--", "url", "dir"}},
+		{CloneOptions{Domains: []string{"go"}}, []string{"clone", "--depth", "1", "--filter=blob:none", "--sparse", "--", "url", "dir"}},
+		{CloneOptions{Full: true, Domains: []string{"go"}}, []string{"clone", "--", "url", "dir"}},
+	}
+	for _, tt := range tests {
+		if got := cloneArgs("url", "dir", tt.opts); !slices.Equal(got, tt.want) {
+			t.Errorf("cloneArgs(%+v) = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestInDomains(t *testing.T) {
+	domains := []string{"general", "go"}
+	for file, want := range map[string]bool{
+		"patterns/flat.yaml":      true,
+		"patterns/go/a.yaml":      true,
+		"patterns/swift/b.yaml":   false,
+		"content-registry.yaml":   true,
+		"patterns/general/c.yaml": true,
+	} {
+		if got := inDomains(file, domains); got != want {
+			t.Errorf("inDomains(%q) = %v, want %v", file, got, want)
+		}
+	}
+	if !inDomains("patterns/swift/b.yaml", nil) {
+		t.Error("a full checkout has every domain")
+	}
+}
+
+func TestSparseShallowClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// A shared repo with a commit per pattern, grouped by domain
+	src := t.TempDir()
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := gitOutput(dir, args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(src, "init", "-q", "-b", "main")
+	git(src, "config", "uploadpack.allowFilter", "true")
+	for _, file := range []string{"patterns/flat.yaml", "patterns/go/errors.yaml", "patterns/swift/async.yaml", "patterns/general/naming.yaml"} {
+		path := filepath.Join(src, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("name: p\ncontent: c\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(src, "add", "-A")
+		git(src, "commit", "-q", "-m", "add "+file)
+	}
+
+	dir := filepath.Join(t.TempDir(), "learning-repo")
+	if err := clone("file://"+src, dir, CloneOptions{Domains: []string{"go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !IsShallow(dir) {
+		t.Error("clone isn't shallow")
+	}
+	if got := CheckedOutDomains(dir); !slices.Equal(got, []string{"general", "go"}) {
+		t.Errorf("CheckedOutDomains = %v", got)
+	}
+	for file, want := range map[string]bool{
+		"patterns/flat.yaml":           true,
+		"patterns/go/errors.yaml":      true,
+		"patterns/general/naming.yaml": true,
+		"patterns/swift/async.yaml":    false,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, file)); (err == nil) != want {
+			t.Errorf("%s checked out = %v, want %v", file, err == nil, want)
+		}
+	}
+
+	candidates, err := workingTreeCandidates(dir, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, c := range candidates {
+		files = append(files, c.File)
+	}
+	slices.Sort(files)
+	if !slices.Equal(files, []string{"errors.yaml", "flat.yaml", "naming.yaml"}) {
+		t.Errorf("candidates = %v", files)
+	}
+
+	// A changed tech stack changes the checkout
+	cfg := &config.Config{TechStack: []string{"swift"}}
+	if err := updateSparseDomains(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "patterns/swift/async.yaml")); err != nil {
+		t.Errorf("swift not checked out after the tech stack changed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "patterns/go/errors.yaml")); err == nil {
+		t.Error("go still checked out after the tech stack changed")
+	}
+
+	// Deepening fetches the history
+	t.Setenv("MUR_HOME", filepath.Dir(dir))
+	if got, _ := RepoDir(); got != dir {
+		t.Fatalf("RepoDir = %s, want the test clone %s", got, dir)
+	}
+	if err := Deepen(); err != nil {
+		t.Fatal(err)
+	}
+	if IsShallow(dir) {
+		t.Error("still shallow after Deepen")
+	}
+	if out, _ := gitOutput(dir, "rev-list", "--count", "HEAD"); out != "4" {
+		t.Errorf("commits after Deepen = %s, want 4", out)
+	}
+}
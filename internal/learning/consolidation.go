@@ -0,0 +1,141 @@
+package learning
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// ConsolidationBranch is the branch consolidation runs push their changes
+// to. Each run replaces it, so there is at most one open consolidation PR.
+const ConsolidationBranch = "mur/consolidation"
+
+// ConsolidationPR is the result of OpenConsolidationPR.
+type ConsolidationPR struct {
+	Base    string // branch the PR targets (main or master)
+	Branch  string
+	Changed int    // patterns changed; 0 means no PR was opened
+	Body    string // PR description
+	URL     string // empty in dry-run mode
+	Updated bool   // an open PR for the branch was updated instead of created
+}
+
+// OpenConsolidationPR checks out the team's main branch in a temporary
+// worktree, lets apply change the pattern files in its patterns/
+// directory, and opens (or updates) a PR with the result. apply returns
+// the PR body and the number of patterns changed. The local checkout and
+// pattern store aren't touched.
+func OpenConsolidationPR(title string, apply func(patternsDir string) (string, int, error), dryRun bool) (*ConsolidationPR, error) {
+	if !IsInitialized() {
+		return nil, fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
+	}
+	if err := execx.CheckArg(title); err != nil {
+		return nil, fmt.Errorf("invalid PR title: %w", err)
+	}
+	if !dryRun {
+		if _, err := exec.LookPath("gh"); err != nil {
+			return nil, fmt.Errorf("gh CLI not found (install: https://cli.github.com/)")
+		}
+	}
+
+	dir, err := RepoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if out, err := gitOutput(dir, "fetch", "origin"); err != nil {
+		return nil, fmt.Errorf("git fetch failed: %s", out)
+	}
+	base := ""
+	for _, b := range []string{"main", "master"} {
+		if _, err := gitOutput(dir, "rev-parse", "--verify", "--quiet", "origin/"+b); err == nil {
+			base = b
+			break
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("learning repo has no origin/main or origin/master to consolidate")
+	}
+	for _, branch := range []string{base, ConsolidationBranch} {
+		if err := execx.CheckArg(branch); err != nil {
+			return nil, fmt.Errorf("invalid branch: %w", err)
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "mur-consolidation-")
+	if err != nil {
+		return nil, err
+	}
+	// git worktree add wants to create the directory itself
+	worktree := filepath.Join(tmp, "repo")
+	defer func() {
+		_, _ = gitOutput(dir, "worktree", "remove", "--force", worktree)
+		_ = os.RemoveAll(tmp)
+	}()
+	if out, err := gitOutput(dir, "worktree", "add", "-B", ConsolidationBranch, worktree, "origin/"+base); err != nil {
+		return nil, fmt.Errorf("git worktree add failed: %s", out)
+	}
+
+	body, changed, err := apply(filepath.Join(worktree, "patterns"))
+	if err != nil {
+		return nil, err
+	}
+	result := &ConsolidationPR{Base: base, Branch: ConsolidationBranch, Changed: changed, Body: body}
+	if changed == 0 || dryRun {
+		return result, nil
+	}
+
+	if out, err := gitOutput(worktree, "add", "-A", "patterns"); err != nil {
+		return nil, fmt.Errorf("git add failed: %s", out)
+	}
+	if out, err := gitOutput(worktree, "commit", "-m", title); err != nil {
+		return nil, fmt.Errorf("git commit failed: %s", out)
+	}
+	if out, err := gitOutput(worktree, "push", "--force", "origin", ConsolidationBranch); err != nil {
+		return nil, fmt.Errorf("git push failed: %s", out)
+	}
+
+	res, err := run(worktree, "gh", "pr", "create",
+		"--title", title,
+		"--body", body,
+		"--base", base,
+		"--head", ConsolidationBranch,
+		"--label", "consolidation",
+	)
+	if err == nil {
+		result.URL = strings.TrimSpace(res.Stdout)
+		return result, nil
+	}
+	if !strings.Contains(res.Output(), "already exists") {
+		return nil, fmt.Errorf("gh pr create failed: %s", res.Output())
+	}
+
+	// The branch was force-pushed, so the open PR already shows this
+	// run's changes; refresh its description to match.
+	if res, err := run(worktree, "gh", "pr", "edit", ConsolidationBranch, "--title", title, "--body", body); err != nil {
+		return nil, fmt.Errorf("gh pr edit failed: %s", res.Output())
+	}
+	if res, err := run(worktree, "gh", "pr", "view", ConsolidationBranch, "--json", "url", "--jq", ".url"); err == nil {
+		result.URL = strings.TrimSpace(res.Stdout)
+	}
+	result.Updated = true
+	return result, nil
+}
+
+// gitOutput runs git in dir and returns its combined output.
+func gitOutput(dir string, args ...string) (string, error) {
+	res, err := run(dir, "git", args...)
+	return strings.TrimSpace(res.Output()), err
+}
+
+// run runs a program mur runs itself in dir.
+func run(dir, name string, args ...string) (*execx.Result, error) {
+	c := execx.Command(name, args...)
+	c.Dir = dir
+	return c.Run(context.Background())
+}
@@ -0,0 +1,455 @@
+package learning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// Dedupe modes for patterns that are equivalent to a local one.
+const (
+	DedupeSkip  = "skip"  // don't import the duplicate
+	DedupeLink  = "link"  // don't import it, record its name as an alias
+	DedupeMerge = "merge" // fold its tags/description into the local copy and link it
+	DedupeOff   = "off"   // import duplicates as separate patterns
+)
+
+// RegistryFile is the content-hash registry kept at the root of the
+// learning repo, shared by every machine that pushes to it.
+const RegistryFile = "content-registry.yaml"
+
+// ValidDedupeModes returns the accepted dedupe modes.
+func ValidDedupeModes() []string {
+	return []string{DedupeSkip, DedupeLink, DedupeMerge, DedupeOff}
+}
+
+// ResolveDedupeMode returns the flag value, else learning.dedupe, else link.
+func ResolveDedupeMode(cfg *config.Config, flag string) (string, error) {
+	mode := flag
+	if mode == "" {
+		mode = cfg.Learning.Dedupe
+	}
+	if mode == "" {
+		return DedupeLink, nil
+	}
+	for _, m := range ValidDedupeModes() {
+		if mode == m {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid dedupe mode %q (use: %s)", mode, strings.Join(ValidDedupeModes(), ", "))
+}
+
+// ContentHash identifies a pattern by its content, ignoring case and
+// whitespace differences, so copies saved under different names match.
+func ContentHash(content string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// RegistryEntry records every name a piece of content is known by.
+type RegistryEntry struct {
+	Name    string   `yaml:"name"`              // first name registered
+	Aliases []string `yaml:"aliases,omitempty"` // equivalent patterns under other names
+	Sources []string `yaml:"sources,omitempty"` // branches the content was seen on
+}
+
+// Registry maps content hashes to pattern names.
+type Registry struct {
+	Entries map[string]*RegistryEntry `yaml:"entries"`
+}
+
+// LoadRegistry reads the registry from a learning repo checkout.
+// A missing file yields an empty registry.
+func LoadRegistry(repoDir string) (*Registry, error) {
+	reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+	data, err := os.ReadFile(filepath.Join(repoDir, RegistryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", RegistryFile, err)
+	}
+	if reg.Entries == nil {
+		reg.Entries = make(map[string]*RegistryEntry)
+	}
+	return reg, nil
+}
+
+// Save writes the registry to a learning repo checkout.
+func (r *Registry) Save(repoDir string) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(repoDir, RegistryFile), data, 0644)
+}
+
+// Register records that name holds the content with the given hash.
+func (r *Registry) Register(hash, name, source string) {
+	e, ok := r.Entries[hash]
+	if !ok {
+		e = &RegistryEntry{Name: name}
+		r.Entries[hash] = e
+	}
+	if name != e.Name {
+		e.Aliases = appendUnique(e.Aliases, name)
+	}
+	if source != "" {
+		e.Sources = appendUnique(e.Sources, source)
+	}
+}
+
+// Names returns the canonical name and aliases for a hash.
+func (r *Registry) Names(hash string) []string {
+	e, ok := r.Entries[hash]
+	if !ok {
+		return nil
+	}
+	return append([]string{e.Name}, e.Aliases...)
+}
+
+// lookupName returns the hash registered for a name or alias.
+func (r *Registry) lookupName(name string) (string, bool) {
+	for hash, e := range r.Entries {
+		if e.Name == name {
+			return hash, true
+		}
+		for _, a := range e.Aliases {
+			if a == name {
+				return hash, true
+			}
+		}
+	}
+	return "", false
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	list = append(list, s)
+	sort.Strings(list)
+	return list
+}
+
+// PullOptions configures Pull.
+type PullOptions struct {
+	Branches []string // other machines' branches to import from, besides main
+	Dedupe   string   // skip | link | merge | off (default: link)
+}
+
+// Deduped describes a pulled pattern that matched a local one.
+type Deduped struct {
+	Name        string // name in the pulled branch
+	Source      string // branch it came from
+	DuplicateOf string // local pattern with the same content
+	Action      string // skip | link | merge
+}
+
+// PullResult reports what a pull imported and deduplicated.
+type PullResult struct {
+	Imported []string
+	Deduped  []Deduped
+}
+
+// candidate is a pattern file read from a branch of the learning repo.
+type candidate struct {
+	Source string
+	File   string // name.yaml or name.yaml.zst
+	Data   []byte // the YAML, decompressed
+}
+
+// patternHeader holds the fields dedupe needs; it parses v1 and v2 files.
+type patternHeader struct {
+	Name    string `yaml:"name"`
+	Content string `yaml:"content"`
+}
+
+// importPatterns copies candidates into patternsDir, deduplicating them
+// against local patterns by content hash and against names in the registry.
+func importPatterns(candidates []candidate, patternsDir string, reg *Registry, mode string) (*PullResult, error) {
+	result := &PullResult{}
+
+	// Index local patterns, plain or compressed, by content hash
+	local := make(map[string]string) // hash -> name
+	files := make(map[string]string) // name -> file
+	entries, err := os.ReadDir(patternsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !pattern.IsPatternFile(entry.Name()) {
+			continue
+		}
+		name := pattern.PatternFileName(entry.Name())
+		files[name] = entry.Name()
+		data, err := pattern.ReadFile(filepath.Join(patternsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var h patternHeader
+		if yaml.Unmarshal(data, &h) != nil || h.Content == "" {
+			continue
+		}
+		hash := ContentHash(h.Content)
+		if _, ok := local[hash]; !ok {
+			local[hash] = name
+		}
+	}
+
+	// Don't bring back patterns deleted here
+	deleted := make(map[string]bool)
+	store := pattern.NewStore(patternsDir)
+	trash, _ := store.Trash()
+	for _, e := range trash {
+		deleted[e.Name] = true
+	}
+
+	for _, c := range candidates {
+		name := pattern.PatternFileName(c.File)
+
+		// Don't overwrite existing local patterns (local wins)
+		if files[name] != "" || deleted[name] || store.Purged(name) {
+			continue
+		}
+
+		var h patternHeader
+		if err := yaml.Unmarshal(c.Data, &h); err != nil {
+			continue
+		}
+		hash := ContentHash(h.Content)
+
+		if mode != DedupeOff {
+			if dup := duplicateOf(name, hash, h.Content, local, files, reg); dup != "" {
+				switch mode {
+				case DedupeMerge:
+					if err := mergeInto(filepath.Join(patternsDir, files[dup]), c.Data); err != nil {
+						return nil, fmt.Errorf("merge %s into %s: %w", name, dup, err)
+					}
+					fallthrough
+				case DedupeLink:
+					if h.Content != "" {
+						reg.Register(hash, dup, "")
+						reg.Register(hash, name, c.Source)
+					}
+				}
+				result.Deduped = append(result.Deduped, Deduped{Name: name, Source: c.Source, DuplicateOf: dup, Action: mode})
+				continue
+			}
+		}
+
+		// Candidates are decoded: a pulled .yaml.zst lands as plain YAML
+		if err := os.WriteFile(filepath.Join(patternsDir, name+".yaml"), c.Data, 0644); err != nil {
+			continue
+		}
+		files[name] = name + ".yaml"
+		if h.Content != "" {
+			if _, ok := local[hash]; !ok {
+				local[hash] = name
+			}
+			reg.Register(hash, name, c.Source)
+		}
+		result.Imported = append(result.Imported, name)
+	}
+
+	return result, nil
+}
+
+// duplicateOf returns the local pattern equivalent to a pulled one: same
+// content, or a name the registry already links to a local pattern.
+func duplicateOf(name, hash, content string, local, files map[string]string, reg *Registry) string {
+	if content != "" {
+		if dup, ok := local[hash]; ok {
+			return dup
+		}
+	}
+	if regHash, ok := reg.lookupName(name); ok {
+		for _, n := range reg.Names(regHash) {
+			if n != name && files[n] != "" {
+				return n
+			}
+		}
+	}
+	return ""
+}
+
+// mergeInto folds tags and a missing description from an incoming pattern
+// into the local pattern file, plain or compressed, keeping the local
+// file's layout.
+func mergeInto(path string, incoming []byte) error {
+	data, err := pattern.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var dst, src yaml.Node
+	if err := yaml.Unmarshal(data, &dst); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(incoming, &src); err != nil {
+		return err
+	}
+	if len(dst.Content) == 0 || len(src.Content) == 0 {
+		return nil
+	}
+	d, s := dst.Content[0], src.Content[0]
+
+	// Tags: a list in v1, confirmed/inferred lists in v2
+	if dt, st := mappingValue(d, "tags"), mappingValue(s, "tags"); dt != nil && st != nil {
+		if dt.Kind == yaml.SequenceNode && st.Kind == yaml.SequenceNode {
+			mergeSequence(dt, st)
+		} else if dt.Kind == yaml.MappingNode && st.Kind == yaml.MappingNode {
+			for _, key := range []string{"confirmed", "inferred"} {
+				if dl, sl := mappingValue(dt, key), mappingValue(st, key); dl != nil && sl != nil {
+					mergeSequence(dl, sl)
+				}
+			}
+		}
+	}
+
+	if sd := mappingValue(s, "description"); sd != nil && sd.Value != "" {
+		if dd := mappingValue(d, "description"); dd == nil {
+			d.Content = append(d.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "description"},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: sd.Value})
+		} else if dd.Value == "" {
+			dd.Value = sd.Value
+		}
+	}
+
+	if dc, sc := mappingValue(d, "confidence"), mappingValue(s, "confidence"); dc != nil && sc != nil {
+		dv, err1 := strconv.ParseFloat(dc.Value, 64)
+		sv, err2 := strconv.ParseFloat(sc.Value, 64)
+		if err1 == nil && err2 == nil && sv > dv {
+			dc.Value = sc.Value
+		}
+	}
+
+	out, err := yaml.Marshal(&dst)
+	if err != nil {
+		return err
+	}
+	return pattern.WriteFile(path, out)
+}
+
+// mappingValue returns the value node for key in a mapping node.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mergeSequence appends scalars from src that dst doesn't have.
+func mergeSequence(dst, src *yaml.Node) {
+	have := make(map[string]bool)
+	for _, n := range dst.Content {
+		have[n.Value] = true
+	}
+	for _, n := range src.Content {
+		if n.Kind == yaml.ScalarNode && !have[n.Value] {
+			dst.Content = append(dst.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: n.Value})
+			have[n.Value] = true
+		}
+	}
+	// An empty flow list ([]) stays flow style otherwise
+	dst.Style = 0
+}
+
+// workingTreeCandidates reads pattern files from the checked-out branch:
+// those in patterns/ and its domain directories.
+func workingTreeCandidates(repoDir, source string) ([]candidate, error) {
+	dir := filepath.Join(repoDir, "patterns")
+	var out []candidate
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !pattern.IsPatternFile(entry.Name()) {
+			return nil
+		}
+		data, err := pattern.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		out = append(out, candidate{Source: source, File: entry.Name(), Data: data})
+		return nil
+	})
+	return out, err
+}
+
+// branchCandidates fetches a remote branch and reads its pattern files
+// without merging it into the local branch.
+func branchCandidates(repoDir, branch string) ([]candidate, error) {
+	if err := execx.CheckArg(branch); err != nil {
+		return nil, fmt.Errorf("invalid branch: %w", err)
+	}
+	if out, err := gitOutput(repoDir, "fetch", "origin", branch); err != nil {
+		return nil, fmt.Errorf("git fetch %s failed: %s", branch, out)
+	}
+
+	ctx := context.Background()
+	ref := "origin/" + branch
+	lsTree := execx.Command("git", "ls-tree", "-r", "--name-only", ref, "patterns/")
+	lsTree.Dir = repoDir
+	res, err := lsTree.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree %s failed: %w", ref, err)
+	}
+
+	// Like the working tree, only the domains checked out; reading the
+	// others would download their files
+	domains := CheckedOutDomains(repoDir)
+	var out []candidate
+	for _, path := range strings.Split(strings.TrimSpace(res.Stdout), "\n") {
+		if !pattern.IsPatternFile(path) || strings.Contains(path, "/.") || !inDomains(path, domains) {
+			continue
+		}
+		show := execx.Command("git", "show", ref+":"+path)
+		show.Dir = repoDir
+		res, err := show.Run(ctx)
+		if err != nil {
+			continue
+		}
+		data, err := pattern.Decode(path, []byte(res.Stdout))
+		if err != nil {
+			continue
+		}
+		out = append(out, candidate{Source: branch, File: filepath.Base(path), Data: data})
+	}
+	return out, nil
+}
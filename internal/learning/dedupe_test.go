@@ -0,0 +1,245 @@
+package learning
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestContentHashNormalizes(t *testing.T) {
+	a := ContentHash("Use fmt.Errorf\n  with %w")
+	b := ContentHash("  use fmt.errorf with   %w\n")
+	if a != b {
+		t.Error("hashes should ignore case and whitespace")
+	}
+	if a == ContentHash("use errors.New") {
+		t.Error("different content should hash differently")
+	}
+}
+
+func TestResolveDedupeMode(t *testing.T) {
+	cfg := &config.Config{}
+	if mode, _ := ResolveDedupeMode(cfg, ""); mode != DedupeLink {
+		t.Errorf("default = %s, want link", mode)
+	}
+	cfg.Learning.Dedupe = DedupeSkip
+	if mode, _ := ResolveDedupeMode(cfg, ""); mode != DedupeSkip {
+		t.Errorf("config = %s, want skip", mode)
+	}
+	if mode, _ := ResolveDedupeMode(cfg, DedupeMerge); mode != DedupeMerge {
+		t.Errorf("flag = %s, want merge", mode)
+	}
+	if _, err := ResolveDedupeMode(cfg, "bogus"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
+func writePattern(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportPatternsDedupe(t *testing.T) {
+	local := "name: go-errors\ncontent: Wrap errors with %w\ntags: [go]\n"
+	dup := []byte("name: golang-error-wrapping\ndescription: Error wrapping\ncontent: wrap errors   with %w\ntags: [go, errors]\n")
+	fresh := []byte("name: retry\ncontent: Retry with jitter\n")
+
+	tests := []struct {
+		mode     string
+		imported int
+		deduped  int
+	}{
+		{DedupeSkip, 1, 1},
+		{DedupeLink, 1, 1},
+		{DedupeMerge, 1, 1},
+		{DedupeOff, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			dir := t.TempDir()
+			writePattern(t, dir, "go-errors", local)
+			reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+
+			candidates := []candidate{
+				{Source: "main", File: "golang-error-wrapping.yaml", Data: dup},
+				{Source: "laptop", File: "retry.yaml", Data: fresh},
+			}
+			result, err := importPatterns(candidates, dir, reg, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Imported) != tt.imported || len(result.Deduped) != tt.deduped {
+				t.Fatalf("imported %v, deduped %v", result.Imported, result.Deduped)
+			}
+			if tt.deduped > 0 {
+				d := result.Deduped[0]
+				if d.Name != "golang-error-wrapping" || d.DuplicateOf != "go-errors" || d.Action != tt.mode {
+					t.Errorf("unexpected dedupe %+v", d)
+				}
+				if _, err := os.Stat(filepath.Join(dir, "golang-error-wrapping.yaml")); !os.IsNotExist(err) {
+					t.Error("duplicate should not be written")
+				}
+			}
+
+			names := reg.Names(ContentHash("Wrap errors with %w"))
+			linked := len(names) == 2 && names[0] == "go-errors" && names[1] == "golang-error-wrapping"
+			if wantLink := tt.mode == DedupeLink || tt.mode == DedupeMerge; linked != wantLink {
+				t.Errorf("registry names = %v, linked = %v, want %v", names, linked, wantLink)
+			}
+
+			data, _ := os.ReadFile(filepath.Join(dir, "go-errors.yaml"))
+			merged := strings.Contains(string(data), "- errors") && strings.Contains(string(data), "description: Error wrapping")
+			if merged != (tt.mode == DedupeMerge) {
+				t.Errorf("local pattern after %s:\n%s", tt.mode, data)
+			}
+		})
+	}
+}
+
+func TestImportPatternsLinkedAlias(t *testing.T) {
+	dir := t.TempDir()
+	writePattern(t, dir, "go-errors", "name: go-errors\ncontent: Wrap errors with %w\n")
+
+	// Another machine pushed the same lesson under a different name and
+	// has since edited it, so only the registry connects the two.
+	reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+	reg.Register(ContentHash("Wrap errors with %w"), "go-errors", "desktop")
+	reg.Register(ContentHash("Wrap errors with %w"), "golang-error-wrapping", "laptop")
+
+	candidates := []candidate{{
+		Source: "laptop",
+		File:   "golang-error-wrapping.yaml",
+		Data:   []byte("name: golang-error-wrapping\ncontent: Wrap errors with %w and add context\n"),
+	}}
+	result, err := importPatterns(candidates, dir, reg, DedupeLink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 0 || len(result.Deduped) != 1 || result.Deduped[0].DuplicateOf != "go-errors" {
+		t.Errorf("imported %v, deduped %+v", result.Imported, result.Deduped)
+	}
+}
+
+func TestImportPatternsWithinPull(t *testing.T) {
+	dir := t.TempDir()
+	reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+
+	// main and another branch carry the same pattern under two names
+	candidates := []candidate{
+		{Source: "main", File: "a.yaml", Data: []byte("name: a\ncontent: same lesson\n")},
+		{Source: "laptop", File: "b.yaml", Data: []byte("name: b\ncontent: Same  lesson\n")},
+	}
+	result, err := importPatterns(candidates, dir, reg, DedupeSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 1 || result.Imported[0] != "a" || len(result.Deduped) != 1 {
+		t.Errorf("imported %v, deduped %+v", result.Imported, result.Deduped)
+	}
+}
+
+func TestImportPatternsSkipsDeleted(t *testing.T) {
+	dir := t.TempDir()
+	writePattern(t, dir, "trashed", "name: trashed\ncontent: deleted by mistake\n")
+	writePattern(t, dir, "purged", "name: purged\ncontent: gone for good\n")
+	store := pattern.NewStore(dir).WithTrashRetention(24 * time.Hour)
+	if err := store.Delete("trashed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Purge("purged"); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := []candidate{
+		{Source: "main", File: "trashed.yaml", Data: []byte("name: trashed\ncontent: deleted by mistake\n")},
+		{Source: "main", File: "purged.yaml", Data: []byte("name: purged\ncontent: gone for good\n")},
+	}
+	reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+	result, err := importPatterns(candidates, dir, reg, DedupeSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 0 {
+		t.Errorf("imported %v, want deleted patterns left out", result.Imported)
+	}
+}
+
+func TestPullKeepsCompressedLocalPattern(t *testing.T) {
+	dir := t.TempDir()
+	repo := t.TempDir()
+	local := filepath.Join(dir, "big"+pattern.CompressedExt)
+	if err := pattern.WriteFile(local, []byte("name: big\ncontent: local edit\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := pattern.WriteFile(filepath.Join(dir, "go-errors"+pattern.CompressedExt), []byte("name: go-errors\ncontent: Wrap errors with %w\ntags: [go]\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The repo has the old version of big and, compressed, a copy of go-errors
+	if err := os.MkdirAll(filepath.Join(repo, "patterns"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writePattern(t, filepath.Join(repo, "patterns"), "big", "name: big\ncontent: before the edit\n")
+	if err := pattern.WriteFile(filepath.Join(repo, "patterns", "golang-errors"+pattern.CompressedExt), []byte("name: golang-errors\ncontent: wrap errors with %w\ntags: [errors]\n")); err != nil {
+		t.Fatal(err)
+	}
+	candidates, err := workingTreeCandidates(repo, "main")
+	if err != nil || len(candidates) != 2 {
+		t.Fatalf("candidates = %+v, err = %v", candidates, err)
+	}
+
+	reg := &Registry{Entries: make(map[string]*RegistryEntry)}
+	result, err := importPatterns(candidates, dir, reg, DedupeMerge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 0 || len(result.Deduped) != 1 || result.Deduped[0].DuplicateOf != "go-errors" {
+		t.Fatalf("imported %v, deduped %+v", result.Imported, result.Deduped)
+	}
+
+	// No plain big.yaml shadowing the local edit
+	if _, err := os.Stat(filepath.Join(dir, "big.yaml")); !os.IsNotExist(err) {
+		t.Error("pull wrote big.yaml next to the compressed local pattern")
+	}
+	p, err := pattern.NewStore(dir).Get("big")
+	if err != nil || p.Content != "local edit" {
+		t.Errorf("Get(big) = %+v, %v; want the local edit", p, err)
+	}
+
+	// Merged into the compressed file, which stays compressed
+	data, err := pattern.ReadFile(filepath.Join(dir, "go-errors"+pattern.CompressedExt))
+	if err != nil || !strings.Contains(string(data), "- errors") {
+		t.Errorf("merged go-errors = %q, %v", data, err)
+	}
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	reg, err := LoadRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.Register("abc", "one", "desktop")
+	reg.Register("abc", "two", "laptop")
+	reg.Register("abc", "one", "laptop")
+	if err := reg.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := loaded.Entries["abc"]
+	if e == nil || e.Name != "one" || len(e.Aliases) != 1 || len(e.Sources) != 2 {
+		t.Errorf("entry = %+v", e)
+	}
+}
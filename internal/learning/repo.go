@@ -2,14 +2,20 @@
 package learning
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/execx"
 	"github.com/mur-run/mur-core/internal/learn"
 )
 
@@ -19,7 +25,7 @@ func RepoDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "learning-repo"), nil
+	return filepath.Join(config.DataDir(home), "learning-repo"), nil
 }
 
 // IsInitialized checks if the learning repo has been initialized.
@@ -57,8 +63,8 @@ func GetBranch() (string, error) {
 	return DefaultBranch(), nil
 }
 
-// InitRepo clones the learning repo and sets up the branch.
-func InitRepo(repoURL string) error {
+// InitRepo clones the learning repo as opts says and sets up the branch.
+func InitRepo(repoURL string, opts CloneOptions) error {
 	dir, err := RepoDir()
 	if err != nil {
 		return err
@@ -75,11 +81,11 @@ func InitRepo(repoURL string) error {
 	}
 
 	// Clone the repo
-	cmd := exec.Command("git", "clone", repoURL, dir)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git clone failed: %w", err)
+	if err := execx.CheckArg(repoURL); err != nil {
+		return fmt.Errorf("invalid repo URL: %w", err)
+	}
+	if err := clone(repoURL, dir, opts); err != nil {
+		return err
 	}
 
 	// Get branch name
@@ -89,11 +95,14 @@ func InitRepo(repoURL string) error {
 	}
 
 	// Create and checkout the branch
-	cmd = exec.Command("git", "checkout", "-B", branch)
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := execx.CheckArg(branch); err != nil {
+		return fmt.Errorf("invalid branch: %w", err)
+	}
+	checkout := execx.Command("git", "checkout", "-B", branch)
+	checkout.Dir = dir
+	checkout.Stdout = os.Stdout
+	checkout.Stderr = os.Stderr
+	if _, err := checkout.Run(context.Background()); err != nil {
 		return fmt.Errorf("git checkout failed: %w", err)
 	}
 
@@ -106,6 +115,10 @@ func InitRepo(repoURL string) error {
 	if cfg.Learning.Branch == "" {
 		cfg.Learning.Branch = branch
 	}
+	// Domains chosen for this clone stick, instead of the tech stack's
+	if domains := normalizeDomains(opts.Domains); domains != nil && !opts.Full && !slices.Equal(domains, SparseDomains(cfg)) {
+		cfg.Learning.Domains = domains
+	}
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("cannot save config: %w", err)
 	}
@@ -139,114 +152,169 @@ func Push() error {
 		return fmt.Errorf("cannot sync patterns: %w", err)
 	}
 
+	if err := execx.CheckArg(branch); err != nil {
+		return fmt.Errorf("invalid branch: %w", err)
+	}
+
 	// Check if there are changes
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = dir
-	output, err := cmd.Output()
+	ctx := context.Background()
+	status := execx.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	res, err := status.Run(ctx)
 	if err != nil {
 		return fmt.Errorf("git status failed: %w", err)
 	}
 
-	if len(strings.TrimSpace(string(output))) == 0 {
+	if len(strings.TrimSpace(res.Stdout)) == 0 {
 		return nil // No changes to push
 	}
 
 	// Add all changes
-	cmd = exec.Command("git", "add", "-A")
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
+	add := execx.Command("git", "add", "-A")
+	add.Dir = dir
+	if _, err := add.Run(ctx); err != nil {
 		return fmt.Errorf("git add failed: %w", err)
 	}
 
 	// Commit
 	hostname, _ := os.Hostname()
 	commitMsg := fmt.Sprintf("Update patterns from %s", hostname)
-	cmd = exec.Command("git", "commit", "-m", commitMsg)
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
+	commit := execx.Command("git", "commit", "-m", commitMsg)
+	commit.Dir = dir
+	if res, err := commit.Run(ctx); err != nil {
 		// Ignore if nothing to commit
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if res.ExitCode == 1 {
 			return nil
 		}
 		return fmt.Errorf("git commit failed: %w", err)
 	}
 
-	// Push to origin
-	cmd = exec.Command("git", "push", "-u", "origin", branch)
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git push failed: %w", err)
+	// Push to origin; a shallow clone may first need its history
+	push := func() error {
+		c := execx.Command("git", "push", "-u", "origin", branch)
+		c.Dir = dir
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		_, err := c.Run(ctx)
+		return err
+	}
+	if err := push(); err != nil {
+		if !IsShallow(dir) || Deepen() != nil || push() != nil {
+			return fmt.Errorf("git push failed: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// Pull fetches and merges patterns from the main branch.
-func Pull() error {
+// Pull fetches and merges patterns from the main branch, reads patterns
+// from any extra branches, and imports them with content-hash dedupe.
+func Pull(opts PullOptions) (*PullResult, error) {
 	if !IsInitialized() {
-		return fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
+		return nil, fmt.Errorf("learning repo not initialized (run: mur learn init <repo-url>)")
 	}
 
 	dir, err := RepoDir()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Check out the domains currently wanted
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	if err := updateSparseDomains(dir, cfg); err != nil {
+		return nil, err
 	}
 
 	// Fetch from origin
-	cmd := exec.Command("git", "fetch", "origin", "main")
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	fetch := func(branch string) error {
+		c := execx.Command("git", "fetch", "origin", branch)
+		c.Dir = dir
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		_, err := c.Run(context.Background())
+		return err
+	}
+	if err := fetch("main"); err != nil {
 		// main might not exist yet, try master
-		cmd = exec.Command("git", "fetch", "origin", "master")
-		cmd.Dir = dir
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("git fetch failed: %w", err)
+		if err := fetch("master"); err != nil {
+			return nil, fmt.Errorf("git fetch failed: %w", err)
 		}
 	}
 
-	// Try to merge from origin/main (or origin/master)
-	cmd = exec.Command("git", "merge", "origin/main", "--no-edit", "--allow-unrelated-histories")
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
-		// Try master instead
-		cmd = exec.Command("git", "merge", "origin/master", "--no-edit", "--allow-unrelated-histories")
-		cmd.Dir = dir
-		if err := cmd.Run(); err != nil {
-			// If merge fails, it might just mean no main branch exists yet
-			return nil
+	// Try to merge from origin/main (or origin/master). A shallow clone
+	// may lack the history to find where they forked; fetch it and retry.
+	merged := mergeMain(dir)
+	if !merged && IsShallow(dir) && Deepen() == nil {
+		merged = mergeMain(dir)
+	}
+
+	var candidates []candidate
+	if merged {
+		candidates, err = workingTreeCandidates(dir, "main")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	own, _ := GetBranch()
+	for _, branch := range opts.Branches {
+		if branch == own {
+			continue
+		}
+		bc, err := branchCandidates(dir, branch)
+		if err != nil {
+			return nil, err
 		}
+		candidates = append(candidates, bc...)
 	}
 
 	// Import patterns from repo to local
-	if err := syncPatternsFromRepo(); err != nil {
-		return fmt.Errorf("cannot import patterns: %w", err)
+	result, err := syncPatternsFromRepo(candidates, opts.Dedupe)
+	if err != nil {
+		return nil, fmt.Errorf("cannot import patterns: %w", err)
 	}
 
-	return nil
+	return result, nil
+}
+
+// mergeMain merges origin/main, or origin/master, into the current branch.
+// It fails if there is neither, e.g. when no main branch exists yet.
+func mergeMain(dir string) bool {
+	for _, ref := range []string{"origin/main", "origin/master"} {
+		if _, err := gitOutput(dir, "merge", ref, "--no-edit", "--allow-unrelated-histories"); err == nil {
+			return true
+		}
+		_, _ = gitOutput(dir, "merge", "--abort") // don't leave conflicts behind
+	}
+	return false
 }
 
-// Sync pushes to own branch and pulls from main.
-func Sync() error {
+// Sync pushes to own branch and pulls from main and learning.pull_branches.
+// The pull result is nil when nothing was pulled.
+func Sync() (*PullResult, error) {
 	// First push local changes
 	if err := Push(); err != nil {
-		return fmt.Errorf("push failed: %w", err)
+		return nil, fmt.Errorf("push failed: %w", err)
 	}
 
 	// Then pull from main
 	cfg, err := config.Load()
-	if err == nil && cfg.Learning.PullFromMain {
-		if err := Pull(); err != nil {
-			return fmt.Errorf("pull failed: %w", err)
+	if err == nil && (cfg.Learning.PullFromMain || len(cfg.Learning.PullBranches) > 0) {
+		mode, err := ResolveDedupeMode(cfg, "")
+		if err != nil {
+			return nil, err
 		}
+		result, err := Pull(PullOptions{Branches: cfg.Learning.PullBranches, Dedupe: mode})
+		if err != nil {
+			return nil, fmt.Errorf("pull failed: %w", err)
+		}
+		return result, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 // syncPatternsToRepo copies local patterns to the repo directory.
@@ -275,8 +343,14 @@ func syncPatternsToRepo() error {
 		return err
 	}
 
+	reg, err := LoadRegistry(repoDir)
+	if err != nil {
+		return err
+	}
+	branch, _ := GetBranch()
+
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		if entry.IsDir() || !pattern.IsPatternFile(entry.Name()) {
 			continue
 		}
 
@@ -286,56 +360,69 @@ func syncPatternsToRepo() error {
 		if err := copyFile(srcPath, dstPath); err != nil {
 			continue // Skip files we can't copy
 		}
+		// The pattern may have been (de)compressed since the last push
+		_ = os.Remove(pattern.OtherVariant(dstPath))
+
+		// Register the content so other machines can spot copies of it
+		if data, err := pattern.ReadFile(srcPath); err == nil {
+			var h patternHeader
+			if yaml.Unmarshal(data, &h) == nil && h.Content != "" {
+				reg.Register(ContentHash(h.Content), pattern.PatternFileName(entry.Name()), branch)
+			}
+		}
 	}
 
-	return nil
+	// Deletions reach the repo once the pattern is purged from the trash
+	store := pattern.NewStore(patternsDir)
+	if pending, err := store.PendingDeletions(pattern.PropagateRepo); err == nil {
+		var names []string
+		for _, t := range pending {
+			path := filepath.Join(repoPatternsDir, t.Name+".yaml")
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				continue
+			}
+			if err := os.Remove(pattern.OtherVariant(path)); err != nil && !os.IsNotExist(err) {
+				continue
+			}
+			names = append(names, t.Name)
+		}
+		_ = store.MarkPropagated(pattern.PropagateRepo, names)
+	}
+
+	return reg.Save(repoDir)
 }
 
-// syncPatternsFromRepo imports patterns from repo to local.
-func syncPatternsFromRepo() error {
+// syncPatternsFromRepo imports pulled patterns to local and records them
+// in the repo's content-hash registry.
+func syncPatternsFromRepo(candidates []candidate, mode string) (*PullResult, error) {
 	repoDir, err := RepoDir()
 	if err != nil {
-		return err
-	}
-
-	repoPatternsDir := filepath.Join(repoDir, "patterns")
-	if _, err := os.Stat(repoPatternsDir); os.IsNotExist(err) {
-		return nil // No patterns in repo
+		return nil, err
 	}
 
 	patternsDir, err := learn.PatternsDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := os.MkdirAll(patternsDir, 0755); err != nil {
-		return err
+		return nil, err
 	}
 
-	entries, err := os.ReadDir(repoPatternsDir)
+	reg, err := LoadRegistry(repoDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
-			continue
-		}
-
-		srcPath := filepath.Join(repoPatternsDir, entry.Name())
-		dstPath := filepath.Join(patternsDir, entry.Name())
-
-		// Don't overwrite existing local patterns (local wins)
-		if _, err := os.Stat(dstPath); err == nil {
-			continue
-		}
-
-		if err := copyFile(srcPath, dstPath); err != nil {
-			continue
-		}
+	result, err := importPatterns(candidates, patternsDir, reg, mode)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if err := reg.Save(repoDir); err != nil {
+		return nil, fmt.Errorf("cannot save registry: %w", err)
+	}
+	return result, nil
 }
 
 // copyFile copies a file from src to dst.
@@ -434,9 +521,12 @@ func CreatePatternPR(pattern learn.Pattern, dryRun bool) (string, error) {
 	if _, err := exec.LookPath("gh"); err != nil {
 		return "", fmt.Errorf("gh CLI not found (install: https://cli.github.com/)")
 	}
+	if err := execx.CheckArg(branch); err != nil {
+		return "", fmt.Errorf("invalid branch: %w", err)
+	}
 
 	// Create PR using gh CLI
-	cmd := exec.Command("gh", "pr", "create",
+	create := execx.Command("gh", "pr", "create",
 		"--title", title,
 		"--body", body,
 		"--base", "main",
@@ -444,19 +534,19 @@ func CreatePatternPR(pattern learn.Pattern, dryRun bool) (string, error) {
 		"--label", "auto-merge",
 		"--label", "pattern",
 	)
-	cmd.Dir = dir
+	create.Dir = dir
 
-	output, err := cmd.CombinedOutput()
+	res, err := create.Run(context.Background())
 	if err != nil {
 		// Check if PR already exists
-		if strings.Contains(string(output), "already exists") {
+		if strings.Contains(res.Output(), "already exists") {
 			return "", fmt.Errorf("PR already exists for this branch")
 		}
-		return "", fmt.Errorf("gh pr create failed: %s", string(output))
+		return "", fmt.Errorf("gh pr create failed: %s", res.Output())
 	}
 
 	// Extract PR URL from output
-	prURL := strings.TrimSpace(string(output))
+	prURL := strings.TrimSpace(res.Stdout)
 	return prURL, nil
 }
 
@@ -0,0 +1,271 @@
+package learning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// CI check states reported by PatternPR.Checks.
+const (
+	ChecksNone    = "none"
+	ChecksPending = "pending"
+	ChecksFailure = "failure"
+	ChecksSuccess = "success"
+)
+
+// MergePolicy gates merging of pattern PRs.
+type MergePolicy struct {
+	RequireCI    bool
+	MinApprovals int
+	Method       string // merge | squash | rebase
+}
+
+// Enabled reports whether the policy has any gate configured.
+// Merging without a gate is refused.
+func (p MergePolicy) Enabled() bool {
+	return p.RequireCI || p.MinApprovals > 0
+}
+
+// MergePolicyFromConfig builds a MergePolicy from learning.merge_policy.
+func MergePolicyFromConfig(cfg *config.Config) MergePolicy {
+	mp := cfg.Learning.MergePolicy
+	method := mp.Method
+	if method == "" {
+		method = "squash"
+	}
+	return MergePolicy{
+		RequireCI:    mp.RequireCI,
+		MinApprovals: mp.MinApprovals,
+		Method:       method,
+	}
+}
+
+// PatternPR is an open pattern PR on the forge.
+type PatternPR struct {
+	Number    int
+	Title     string
+	URL       string
+	Branch    string
+	Approvals int
+	Checks    string // none | pending | failure | success
+	Draft     bool
+
+	// ChangesRequested counts reviewers whose latest review requests
+	// changes; any blocks merging.
+	ChangesRequested int
+}
+
+// MergeDecision is the policy outcome for a single PR.
+type MergeDecision struct {
+	PR     PatternPR
+	Ready  bool
+	Reason string
+	Merged bool
+	Error  error
+}
+
+// ghPR mirrors the fields requested from `gh pr list --json`.
+type ghPR struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	HeadRefName string `json:"headRefName"`
+	IsDraft     bool   `json:"isDraft"`
+	Reviews     []struct {
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		State string `json:"state"`
+	} `json:"reviews"`
+	StatusCheckRollup []struct {
+		Status     string `json:"status"`     // CheckRun: QUEUED | IN_PROGRESS | COMPLETED
+		Conclusion string `json:"conclusion"` // CheckRun: SUCCESS | FAILURE | ...
+		State      string `json:"state"`      // StatusContext: SUCCESS | PENDING | FAILURE | ERROR
+	} `json:"statusCheckRollup"`
+}
+
+// ListPatternPRs returns open PRs labeled "pattern" in the learning repo.
+func ListPatternPRs() ([]PatternPR, error) {
+	if !IsInitialized() {
+		return nil, fmt.Errorf("learning repo not initialized")
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("gh CLI not found (install: https://cli.github.com/)")
+	}
+
+	dir, err := RepoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	list := execx.Command("gh", "pr", "list",
+		"--label", "pattern",
+		"--state", "open",
+		"--json", "number,title,url,headRefName,isDraft,reviews,statusCheckRollup",
+	)
+	list.Dir = dir
+
+	res, err := list.Run(context.Background())
+	if err != nil {
+		if res.ExitCode > 0 {
+			return nil, fmt.Errorf("gh pr list failed: %s", strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("gh pr list failed: %w", err)
+	}
+
+	return parsePatternPRs([]byte(res.Stdout))
+}
+
+// parsePatternPRs converts `gh pr list --json` output into PatternPRs.
+func parsePatternPRs(data []byte) ([]PatternPR, error) {
+	var raw []ghPR
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("cannot parse gh output: %w", err)
+	}
+
+	prs := make([]PatternPR, 0, len(raw))
+	for _, r := range raw {
+		// Only the latest review per author counts
+		latest := make(map[string]string)
+		for _, rv := range r.Reviews {
+			if rv.State == "COMMENTED" {
+				continue
+			}
+			latest[rv.Author.Login] = rv.State
+		}
+		approvals, changesRequested := 0, 0
+		for _, state := range latest {
+			switch state {
+			case "APPROVED":
+				approvals++
+			case "CHANGES_REQUESTED":
+				changesRequested++
+			}
+		}
+
+		checks := ChecksNone
+		if len(r.StatusCheckRollup) > 0 {
+			checks = ChecksSuccess
+			for _, c := range r.StatusCheckRollup {
+				state := c.State
+				if state == "" {
+					if c.Status != "" && c.Status != "COMPLETED" {
+						state = "PENDING"
+					} else {
+						state = c.Conclusion
+					}
+				}
+				switch state {
+				case "SUCCESS", "NEUTRAL", "SKIPPED":
+				case "PENDING", "EXPECTED", "":
+					if checks != ChecksFailure {
+						checks = ChecksPending
+					}
+				default:
+					checks = ChecksFailure
+				}
+			}
+		}
+
+		prs = append(prs, PatternPR{
+			Number:    r.Number,
+			Title:     r.Title,
+			URL:       r.URL,
+			Branch:    r.HeadRefName,
+			Approvals: approvals,
+			Checks:    checks,
+			Draft:     r.IsDraft,
+
+			ChangesRequested: changesRequested,
+		})
+	}
+	return prs, nil
+}
+
+// Evaluate reports whether a PR satisfies the policy, with a reason when it does not.
+func (p MergePolicy) Evaluate(pr PatternPR) (bool, string) {
+	if !p.Enabled() {
+		return false, "no merge policy configured"
+	}
+	if pr.Draft {
+		return false, "draft"
+	}
+	if pr.ChangesRequested > 0 {
+		return false, fmt.Sprintf("changes requested by %d reviewer(s)", pr.ChangesRequested)
+	}
+	if p.RequireCI {
+		switch pr.Checks {
+		case ChecksNone:
+			return false, "no CI checks reported"
+		case ChecksPending:
+			return false, "CI pending"
+		case ChecksFailure:
+			return false, "CI failed"
+		}
+	}
+	if pr.Approvals < p.MinApprovals {
+		return false, fmt.Sprintf("%d/%d approvals", pr.Approvals, p.MinApprovals)
+	}
+	return true, "ready"
+}
+
+// PatternPRStatus lists open pattern PRs and evaluates each against the policy.
+func PatternPRStatus(policy MergePolicy) ([]MergeDecision, error) {
+	prs, err := ListPatternPRs()
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := make([]MergeDecision, 0, len(prs))
+	for _, pr := range prs {
+		ready, reason := policy.Evaluate(pr)
+		decisions = append(decisions, MergeDecision{PR: pr, Ready: ready, Reason: reason})
+	}
+	return decisions, nil
+}
+
+// MergeReadyPRs merges every open pattern PR that satisfies the policy.
+func MergeReadyPRs(policy MergePolicy, dryRun bool) ([]MergeDecision, error) {
+	if !policy.Enabled() {
+		return nil, fmt.Errorf("no merge policy configured (set learning.merge_policy.require_ci or min_approvals)")
+	}
+
+	switch policy.Method {
+	case "merge", "squash", "rebase":
+	default:
+		return nil, fmt.Errorf("unknown merge method %q (use merge, squash, or rebase)", policy.Method)
+	}
+
+	decisions, err := PatternPRStatus(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := RepoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range decisions {
+		d := &decisions[i]
+		if !d.Ready || dryRun {
+			continue
+		}
+
+		merge := execx.Command("gh", "pr", "merge", strconv.Itoa(d.PR.Number), "--"+policy.Method)
+		merge.Dir = dir
+		if res, err := merge.Run(context.Background()); err != nil {
+			d.Error = fmt.Errorf("gh pr merge failed: %s", strings.TrimSpace(res.Output()))
+			continue
+		}
+		d.Merged = true
+	}
+	return decisions, nil
+}
@@ -0,0 +1,103 @@
+package learning
+
+import "testing"
+
+func TestParsePatternPRs(t *testing.T) {
+	data := []byte(`[
+  {
+    "number": 12,
+    "title": "Add pattern: go-errors",
+    "url": "https://github.com/acme/patterns/pull/12",
+    "headRefName": "laptop",
+    "isDraft": false,
+    "reviews": [
+      {"author": {"login": "alice"}, "state": "APPROVED"},
+      {"author": {"login": "bob"}, "state": "CHANGES_REQUESTED"},
+      {"author": {"login": "bob"}, "state": "APPROVED"},
+      {"author": {"login": "carol"}, "state": "APPROVED"},
+      {"author": {"login": "carol"}, "state": "COMMENTED"},
+      {"author": {"login": "dave"}, "state": "APPROVED"},
+      {"author": {"login": "dave"}, "state": "DISMISSED"}
+    ],
+    "statusCheckRollup": [
+      {"status": "COMPLETED", "conclusion": "SUCCESS"},
+      {"state": "SUCCESS"}
+    ]
+  },
+  {
+    "number": 13,
+    "title": "Add pattern: flaky",
+    "reviews": [],
+    "statusCheckRollup": [
+      {"status": "IN_PROGRESS", "conclusion": ""},
+      {"status": "COMPLETED", "conclusion": "FAILURE"}
+    ]
+  },
+  {"number": 14, "title": "Add pattern: pending", "statusCheckRollup": [{"state": "PENDING"}]},
+  {"number": 15, "title": "Add pattern: no-ci"},
+  {
+    "number": 16,
+    "title": "Add pattern: disputed",
+    "reviews": [
+      {"author": {"login": "alice"}, "state": "APPROVED"},
+      {"author": {"login": "erin"}, "state": "APPROVED"},
+      {"author": {"login": "erin"}, "state": "CHANGES_REQUESTED"},
+      {"author": {"login": "erin"}, "state": "COMMENTED"}
+    ]
+  }
+]`)
+
+	prs, err := parsePatternPRs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 5 {
+		t.Fatalf("expected 5 PRs, got %d", len(prs))
+	}
+
+	if prs[0].Approvals != 3 || prs[0].ChangesRequested != 0 {
+		t.Errorf("PR 12 approvals = %d, changes requested = %d; want 3 (alice, bob, carol), 0", prs[0].Approvals, prs[0].ChangesRequested)
+	}
+	// erin's approval was replaced by a request for changes still open
+	if prs[4].Approvals != 1 || prs[4].ChangesRequested != 1 {
+		t.Errorf("PR 16 approvals = %d, changes requested = %d; want 1, 1", prs[4].Approvals, prs[4].ChangesRequested)
+	}
+	if ready, reason := (MergePolicy{MinApprovals: 1}).Evaluate(prs[4]); ready {
+		t.Errorf("PR 16 ready with changes requested (%s)", reason)
+	}
+	want := []string{ChecksSuccess, ChecksFailure, ChecksPending, ChecksNone, ChecksNone}
+	for i, w := range want {
+		if prs[i].Checks != w {
+			t.Errorf("PR %d checks = %s, want %s", prs[i].Number, prs[i].Checks, w)
+		}
+	}
+}
+
+func TestMergePolicyEvaluate(t *testing.T) {
+	green := PatternPR{Approvals: 2, Checks: ChecksSuccess}
+
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		pr     PatternPR
+		ready  bool
+	}{
+		{"no policy", MergePolicy{}, green, false},
+		{"ci and approvals met", MergePolicy{RequireCI: true, MinApprovals: 2}, green, true},
+		{"not enough approvals", MergePolicy{MinApprovals: 3}, green, false},
+		{"ci pending", MergePolicy{RequireCI: true}, PatternPR{Checks: ChecksPending}, false},
+		{"ci missing", MergePolicy{RequireCI: true}, PatternPR{Checks: ChecksNone}, false},
+		{"ci not required", MergePolicy{MinApprovals: 1}, PatternPR{Approvals: 1, Checks: ChecksFailure}, true},
+		{"draft", MergePolicy{MinApprovals: 1}, PatternPR{Approvals: 1, Draft: true}, false},
+		{"changes requested", MergePolicy{RequireCI: true, MinApprovals: 2}, PatternPR{Approvals: 2, Checks: ChecksSuccess, ChangesRequested: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, reason := tt.policy.Evaluate(tt.pr)
+			if ready != tt.ready {
+				t.Errorf("Evaluate() = %v (%s), want %v", ready, reason, tt.ready)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package log is mur's structured log: JSON lines in
+// ~/.mur/logs/mur.log, rotated by size, read back with 'mur logs'.
+// Commands run from hooks have nobody watching their stderr, so anything
+// worth finding later is logged here as well.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Defaults for the logging settings in config.
+const (
+	DefaultLevel    = slog.LevelInfo
+	DefaultMaxBytes = 5 << 20 // rotate mur.log past 5 MB
+	DefaultMaxFiles = 3       // rotated logs kept: mur.log.1 .. mur.log.3
+)
+
+// FileName is the log file in the logs directory.
+const FileName = "mur.log"
+
+// Path returns the log file.
+func Path(home string) string {
+	return filepath.Join(config.StateDir(home), "logs", FileName)
+}
+
+// Options configures Init.
+type Options struct {
+	Path     string
+	Level    slog.Level
+	MaxBytes int64 // rotate past this size; 0 never rotates
+	MaxFiles int   // rotated files kept
+	Command  string
+}
+
+// OptionsFromConfig returns the logging settings in cfg (which may be
+// nil), with defaults for unset values.
+func OptionsFromConfig(home string, cfg *config.Config) Options {
+	opts := Options{
+		Path:     Path(home),
+		Level:    DefaultLevel,
+		MaxBytes: DefaultMaxBytes,
+		MaxFiles: DefaultMaxFiles,
+	}
+	if cfg == nil {
+		return opts
+	}
+	if l, err := ParseLevel(cfg.Logging.Level); err == nil && cfg.Logging.Level != "" {
+		opts.Level = l
+	}
+	if cfg.Logging.MaxSizeMB > 0 {
+		opts.MaxBytes = int64(cfg.Logging.MaxSizeMB) << 20
+	}
+	if cfg.Logging.MaxFiles > 0 {
+		opts.MaxFiles = cfg.Logging.MaxFiles
+	}
+	return opts
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return DefaultLevel, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", s)
+	}
+	return l, nil
+}
+
+var (
+	mu      sync.Mutex
+	handler slog.Handler = slog.DiscardHandler
+	closer  io.Closer
+)
+
+// Init opens the log file and makes it where For's loggers write, until
+// Close. Before Init, and if it fails, log entries are dropped.
+func Init(opts Options) error {
+	w, err := OpenRotating(opts.Path, opts.MaxBytes, opts.MaxFiles)
+	if err != nil {
+		return err
+	}
+	var h slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: opts.Level})
+	if opts.Command != "" {
+		h = h.WithAttrs([]slog.Attr{slog.String("cmd", opts.Command), slog.Int("pid", os.Getpid())})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if closer != nil {
+		_ = closer.Close()
+	}
+	handler, closer = h, w
+	return nil
+}
+
+// Close closes the log file; later entries are dropped.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	handler = slog.DiscardHandler
+	if closer == nil {
+		return nil
+	}
+	err := closer.Close()
+	closer = nil
+	return err
+}
+
+// For returns a logger for a part of mur, e.g. For("sync"). Get it when
+// logging, not at package init, so it writes to the file Init opened.
+func For(component string) *slog.Logger {
+	mu.Lock()
+	h := handler
+	mu.Unlock()
+	return slog.New(h).With("component", component)
+}
+
+// Enabled reports whether entries at level are written.
+func Enabled(level slog.Level) bool {
+	mu.Lock()
+	h := handler
+	mu.Unlock()
+	return h.Enabled(context.Background(), level)
+}
@@ -0,0 +1,120 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", FileName)
+	w, err := OpenRotating(path, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	line := []byte(strings.Repeat("x", 39) + "\n")
+	for i := 0; i < 10; i++ {
+		if _, err := w.Write(line); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(p), err)
+		}
+		if info.Size() > 100 {
+			t.Errorf("%s is %d bytes, want at most 100", filepath.Base(p), info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("kept more than 2 rotated files")
+	}
+}
+
+func TestInitAndTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	For("test").Info("dropped before Init")
+
+	if err := Init(Options{Path: path, Level: slog.LevelInfo, Command: "sync"}); err != nil {
+		t.Fatal(err)
+	}
+	For("sync").Debug("below level")
+	For("sync").Info("synced", "targets", 3)
+	For("hooks").Error("hook failed", "err", "exit status 1")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	For("test").Info("dropped after Close")
+
+	entries, err := Tail(path, 0, Filter{Level: slog.LevelDebug})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Msg != "synced" || e.Component != "sync" || e.Command != "sync" || e.Attrs["targets"] != float64(3) {
+		t.Errorf("entry = %+v", e)
+	}
+	if s := entries[1].String(); !strings.Contains(s, "ERROR hooks: hook failed err=\"exit status 1\" (mur sync)") {
+		t.Errorf("String() = %q", s)
+	}
+
+	entries, _ = Tail(path, 0, Filter{Level: slog.LevelWarn})
+	if len(entries) != 1 || entries[0].Msg != "hook failed" {
+		t.Errorf("level filter: %+v", entries)
+	}
+	entries, _ = Tail(path, 1, Filter{Component: "sync"})
+	if len(entries) != 1 || entries[0].Msg != "synced" {
+		t.Errorf("component filter: %+v", entries)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"msg":"old"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	got := make(chan string, 4)
+	go func() {
+		_ = Follow(ctx, path, Filter{Level: slog.LevelDebug}, func(e Entry) { got <- e.Msg })
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"msg":"new"}` + "\n")
+	_ = f.Close()
+
+	select {
+	case msg := <-got:
+		if msg != "new" {
+			t.Errorf("followed %q, want new", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("new entry not followed")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if l, err := ParseLevel("debug"); err != nil || l != slog.LevelDebug {
+		t.Errorf("debug = %v, %v", l, err)
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("accepted unknown level")
+	}
+}
@@ -0,0 +1,171 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a line of the log.
+type Entry struct {
+	Time      time.Time
+	Level     slog.Level
+	Msg       string
+	Component string
+	Command   string
+	Attrs     map[string]any // everything else
+}
+
+// ParseEntry parses a JSON log line.
+func ParseEntry(line []byte) (Entry, error) {
+	var m map[string]any
+	if err := json.Unmarshal(line, &m); err != nil {
+		return Entry{}, err
+	}
+	var e Entry
+	if s, ok := m[slog.TimeKey].(string); ok {
+		e.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	if s, ok := m[slog.LevelKey].(string); ok {
+		_ = e.Level.UnmarshalText([]byte(s))
+	}
+	e.Msg, _ = m[slog.MessageKey].(string)
+	e.Component, _ = m["component"].(string)
+	e.Command, _ = m["cmd"].(string)
+	for _, k := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, "component", "cmd", "pid"} {
+		delete(m, k)
+	}
+	e.Attrs = m
+	return e, nil
+}
+
+// String formats the entry for reading, e.g.
+// "2026-10-16 09:12:03 ERROR sync: target failed target=Cursor".
+func (e Entry) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Time.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, " %-5s ", e.Level)
+	if e.Component != "" {
+		sb.WriteString(e.Component + ": ")
+	}
+	sb.WriteString(e.Msg)
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fmt.Sprint(e.Attrs[k])
+		if strings.ContainsAny(v, " \t\"") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&sb, " %s=%s", k, v)
+	}
+	if e.Command != "" {
+		fmt.Fprintf(&sb, " (mur %s)", e.Command)
+	}
+	return sb.String()
+}
+
+// Filter selects entries.
+type Filter struct {
+	Level     slog.Level // at least this level
+	Component string     // if set, only this component
+}
+
+func (f Filter) match(e Entry) bool {
+	return e.Level >= f.Level && (f.Component == "" || e.Component == f.Component)
+}
+
+// Tail returns the last n entries of the log at path that match f,
+// oldest first, reading the newest rotated file too when path has fewer.
+func Tail(path string, n int, f Filter) ([]Entry, error) {
+	var entries []Entry
+	for _, p := range []string{path + ".1", path} {
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, parseLines(data, f)...)
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+func parseLines(data []byte, f Filter) []Entry {
+	var entries []Entry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		e, err := ParseEntry(sc.Bytes())
+		if err == nil && f.match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Follow calls fn with each entry matching f appended to the log at path
+// from now on, until ctx is done. It picks up the new file after a
+// rotation.
+func Follow(ctx context.Context, path string, f Filter, fn func(Entry)) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	tick := time.NewTicker(500 * time.Millisecond)
+	defer tick.Stop()
+	var partial []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() < offset {
+			offset, partial = 0, nil // rotated
+		}
+		if info.Size() == offset {
+			continue
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			continue
+		}
+		data, err := io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+
+		data = append(partial, data...)
+		end := bytes.LastIndexByte(data, '\n') + 1
+		partial = append([]byte(nil), data[end:]...)
+		for _, e := range parseLines(data[:end], f) {
+			fn(e)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Rotating is a log file that is renamed to <path>.1 (shifting older
+// ones up to <path>.<maxFiles>) once a write would take it past maxBytes.
+// Several mur processes may append to the same file; each checks the
+// size on disk, so whichever crosses the limit rotates.
+type Rotating struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenRotating opens path for appending, creating its directory.
+func OpenRotating(path string, maxBytes int64, maxFiles int) (*Rotating, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("cannot create log directory: %w", err)
+	}
+	r := &Rotating{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rotating) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open log: %w", err)
+	}
+	r.f = f
+	return nil
+}
+
+// Write appends p, rotating first if needed.
+func (r *Rotating) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.maxBytes > 0 {
+		// Another process may have rotated the file from under us
+		info, err := os.Stat(r.path)
+		if err != nil || !sameFile(r.f, info) {
+			_ = r.f.Close()
+			if err := r.open(); err != nil {
+				r.f = nil
+				return 0, err
+			}
+			info, err = r.f.Stat()
+			if err != nil {
+				return 0, err
+			}
+		}
+		if info.Size() > 0 && info.Size()+int64(len(p)) > r.maxBytes {
+			if err := r.rotate(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return r.f.Write(p)
+}
+
+// rotate shifts the rotated files up, drops the oldest, and starts a new
+// file.
+func (r *Rotating) rotate() error {
+	_ = r.f.Close()
+	r.f = nil
+	if r.maxFiles > 0 {
+		_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles))
+		for i := r.maxFiles - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		_ = os.Rename(r.path, r.path+".1")
+	} else {
+		_ = os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// Close closes the file.
+func (r *Rotating) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func sameFile(f *os.File, info os.FileInfo) bool {
+	cur, err := f.Stat()
+	return err == nil && os.SameFile(cur, info)
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/security"
+)
+
+// PatternArgs are the arguments of add_pattern.
+type PatternArgs struct {
+	Name        string   `json:"name"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// AddPattern creates a pattern from add_pattern arguments. Content with
+// secrets or a high prompt-injection risk is refused, since patterns are
+// injected into later prompts. The pattern's source is "mcp", so it can be
+// told apart from ones the user wrote.
+func AddPattern(store *pattern.Store, args PatternArgs) (*pattern.Pattern, error) {
+	args.Name = strings.TrimSpace(args.Name)
+	if args.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if strings.TrimSpace(args.Content) == "" {
+		return nil, errors.New("content is required")
+	}
+	if store.Exists(args.Name) {
+		return nil, fmt.Errorf("pattern already exists: %s (choose another name)", args.Name)
+	}
+
+	text := args.Description + "\n" + args.Content
+	if result := security.NewScanner().ScanContent(text); !result.Safe {
+		return nil, errors.New("content contains secrets; remove them and try again")
+	}
+	risk, findings := security.NewInjectionScanner().Scan(text)
+	if risk == security.InjectionRiskHigh {
+		return nil, fmt.Errorf("content looks like a prompt injection: %s", findings[0].Message)
+	}
+
+	p := &pattern.Pattern{
+		Name:        args.Name,
+		Description: strings.TrimSpace(args.Description),
+		Content:     args.Content,
+		Tags:        pattern.TagSet{Confirmed: args.Tags},
+	}
+	p.Security.Source = "mcp"
+	p.Security.InjectionRisk = string(risk)
+	if err := store.Create(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,415 @@
+// Package mcp implements a Model Context Protocol server over stdio, so
+// MCP clients like Claude Code can search and read patterns as tools and
+// resources instead of relying on prompt-injection hooks.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mur-run/mur-core/internal/core/embed"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/workflow"
+)
+
+// ProtocolVersion is the MCP revision the server implements.
+const ProtocolVersion = "2024-11-05"
+
+// patternURIPrefix prefixes the resource URI of each pattern.
+const patternURIPrefix = "mur://patterns/"
+
+// SearchFunc finds up to limit patterns for query, best first.
+type SearchFunc func(query string, limit int) ([]embed.PatternMatch, error)
+
+// Options configures a Server.
+type Options struct {
+	Version  string     // reported as serverInfo.version
+	Search   SearchFunc // nil searches by keyword only
+	ReadOnly bool       // leave out add_pattern
+	// Workflows lists workflows for list_workflows (default: workflow.List)
+	Workflows func() ([]workflow.IndexEntry, error)
+}
+
+// Server answers MCP requests about the patterns in a store.
+type Server struct {
+	store *pattern.Store
+	opts  Options
+
+	mu  sync.Mutex // serializes writes to out
+	out io.Writer
+}
+
+// NewServer returns a server for the patterns in store.
+func NewServer(store *pattern.Store, opts Options) *Server {
+	if opts.Search == nil {
+		opts.Search = func(query string, limit int) ([]embed.PatternMatch, error) {
+			patterns, err := store.List()
+			if err != nil {
+				return nil, err
+			}
+			return embed.NewKeywordIndex(patterns).Search(query, limit), nil
+		}
+	}
+	if opts.Workflows == nil {
+		opts.Workflows = workflow.List
+	}
+	return &Server{store: store, opts: opts}
+}
+
+// request is a JSON-RPC 2.0 request or notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Serve reads newline-delimited JSON-RPC messages from in and writes
+// responses to out until in is closed.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			s.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error"}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			if len(req.ID) > 0 {
+				s.write(response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{codeInvalidRequest, "invalid request"}})
+			}
+			continue
+		}
+
+		result, rerr := s.handle(req)
+		// Notifications get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+		if rerr == nil && result == nil {
+			resp.Result = struct{}{}
+		}
+		s.write(resp)
+	}
+	return sc.Err()
+}
+
+func (s *Server) write(resp response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.out.Write(append(data, '\n'))
+}
+
+func (s *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": "mur", "version": s.opts.Version},
+			"instructions": "mur is the user's knowledge base of coding patterns learned from past sessions. " +
+				"Search it before starting a task and follow matching patterns.",
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.tools()}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params"}
+		}
+		return s.callTool(params.Name, params.Arguments)
+	case "resources/list":
+		return s.listResources()
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params"}
+		}
+		return s.readResource(params.URI)
+	default:
+		if strings.HasPrefix(req.Method, "notifications/") {
+			return nil, nil
+		}
+		return nil, &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+}
+
+// tool describes an MCP tool.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func schema(required []string, props map[string]any) map[string]any {
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (s *Server) tools() []tool {
+	str := func(desc string) map[string]any { return map[string]any{"type": "string", "description": desc} }
+	tools := []tool{
+		{
+			Name:        "search_patterns",
+			Description: "Search the user's learned coding patterns by meaning and keywords. Returns names, descriptions, and scores.",
+			InputSchema: schema([]string{"query"}, map[string]any{
+				"query": str("What you're working on, or an error message or identifier"),
+				"limit": map[string]any{"type": "integer", "description": "Maximum results (default 5)"},
+			}),
+		},
+		{
+			Name:        "get_pattern",
+			Description: "Get the full content of a pattern by name or ID.",
+			InputSchema: schema([]string{"name"}, map[string]any{"name": str("Pattern name or ID")}),
+		},
+		{
+			Name:        "list_workflows",
+			Description: "List the user's saved workflows: reusable multi-step procedures extracted from past sessions.",
+			InputSchema: schema(nil, map[string]any{}),
+		},
+	}
+	if !s.opts.ReadOnly {
+		tools = append(tools, tool{
+			Name: "add_pattern",
+			Description: "Save a new reusable pattern (a lesson, convention, or fix worth remembering) to the user's knowledge base. " +
+				"Fails if the name is taken.",
+			InputSchema: schema([]string{"name", "content"}, map[string]any{
+				"name":        str("Lowercase kebab-case name, e.g. retry-with-backoff"),
+				"content":     str("The pattern itself, in Markdown"),
+				"description": str("One line saying when the pattern applies"),
+				"tags":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			}),
+		})
+	}
+	return tools
+}
+
+// toolResult is the result of tools/call. Tool failures are reported in
+// the result with isError, so the model sees them, rather than as
+// JSON-RPC errors.
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func textResult(text string) toolResult {
+	return toolResult{Content: []textContent{{Type: "text", Text: text}}}
+}
+
+func errorResult(err error) toolResult {
+	r := textResult(err.Error())
+	r.IsError = true
+	return r
+}
+
+func jsonResult(v any) toolResult {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errorResult(err)
+	}
+	return textResult(string(data))
+}
+
+func (s *Server) callTool(name string, raw json.RawMessage) (any, *rpcError) {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	switch name {
+	case "search_patterns":
+		var args struct {
+			Query string `json:"query"`
+			Limit int    `json:"limit"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil || strings.TrimSpace(args.Query) == "" {
+			return errorResult(errors.New("query is required")), nil
+		}
+		return s.searchPatterns(args.Query, args.Limit), nil
+	case "get_pattern":
+		var args struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &args); err != nil || args.Name == "" {
+			return errorResult(errors.New("name is required")), nil
+		}
+		p, err := s.store.Resolve(args.Name)
+		if err != nil {
+			return errorResult(fmt.Errorf("pattern not found: %s", args.Name)), nil
+		}
+		return textResult(formatPattern(p)), nil
+	case "list_workflows":
+		entries, err := s.opts.Workflows()
+		if err != nil {
+			return errorResult(err), nil
+		}
+		if entries == nil {
+			entries = []workflow.IndexEntry{}
+		}
+		return jsonResult(entries), nil
+	case "add_pattern":
+		if s.opts.ReadOnly {
+			break
+		}
+		var args PatternArgs
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return errorResult(fmt.Errorf("invalid arguments: %w", err)), nil
+		}
+		p, err := AddPattern(s.store, args)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return textResult(fmt.Sprintf("Saved pattern %s (%s)", p.Name, p.ID)), nil
+	}
+	return nil, &rpcError{codeInvalidParams, "unknown tool: " + name}
+}
+
+// searchResult is one search_patterns result.
+type searchResult struct {
+	Name        string   `json:"name"`
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Score       float64  `json:"score"`
+}
+
+func (s *Server) searchPatterns(query string, limit int) toolResult {
+	if limit <= 0 {
+		limit = 5
+	}
+	limit = min(limit, 50)
+	matches, err := s.opts.Search(query, limit)
+	if err != nil {
+		return errorResult(err)
+	}
+	results := make([]searchResult, 0, len(matches))
+	for _, m := range matches {
+		if !m.Pattern.IsActive() {
+			continue
+		}
+		results = append(results, searchResult{
+			Name:        m.Pattern.Name,
+			ID:          m.Pattern.ID,
+			Description: m.Pattern.Description,
+			Tags:        m.Pattern.Tags.Confirmed,
+			Score:       float64(int(m.Score*1000)) / 1000,
+		})
+	}
+	return jsonResult(results)
+}
+
+// formatPattern renders p as Markdown for get_pattern and resources/read.
+func formatPattern(p *pattern.Pattern) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", p.Name)
+	if p.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", p.Description)
+	}
+	if len(p.Tags.Confirmed) > 0 {
+		fmt.Fprintf(&b, "Tags: %s\n\n", strings.Join(p.Tags.Confirmed, ", "))
+	}
+	if p.Lifecycle.Status != "" && p.Lifecycle.Status != pattern.StatusActive {
+		fmt.Fprintf(&b, "Status: %s\n\n", p.Lifecycle.Status)
+	}
+	b.WriteString(strings.TrimSpace(p.Content))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (s *Server) listResources() (any, *rpcError) {
+	patterns, err := s.store.GetActive()
+	if err != nil {
+		return nil, &rpcError{codeInvalidRequest, err.Error()}
+	}
+	resources := make([]map[string]any, 0, len(patterns))
+	for _, p := range patterns {
+		r := map[string]any{
+			"uri":      patternURIPrefix + p.Name,
+			"name":     p.Name,
+			"mimeType": "text/markdown",
+		}
+		if p.Description != "" {
+			r["description"] = p.Description
+		}
+		resources = append(resources, r)
+	}
+	return map[string]any{"resources": resources}, nil
+}
+
+func (s *Server) readResource(uri string) (any, *rpcError) {
+	name, ok := strings.CutPrefix(uri, patternURIPrefix)
+	if !ok || name == "" {
+		return nil, &rpcError{codeInvalidParams, "unknown resource: " + uri}
+	}
+	p, err := s.store.Resolve(name)
+	if err != nil {
+		return nil, &rpcError{codeInvalidParams, "pattern not found: " + name}
+	}
+	return map[string]any{
+		"contents": []map[string]any{{
+			"uri":      uri,
+			"mimeType": "text/markdown",
+			"text":     formatPattern(p),
+		}},
+	}, nil
+}
@@ -0,0 +1,446 @@
+// Package netpolicy enforces network.mode, which says what mur may connect
+// to. Every HTTP client in mur (cloud sync, LLMs, embeddings,
+// notifications, updates) sends its requests through http.DefaultTransport,
+// which Install guards, so a request the policy doesn't allow fails before
+// anything is dialed. A name allowed only because of the addresses it
+// resolves to is resolved once, and those addresses are dialed. git and
+// gh, which connect on their own, are checked by execx before they run.
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Mode is a network.mode setting.
+type Mode string
+
+const (
+	ModeNormal    Mode = "normal"    // any endpoint (default)
+	ModeAllowlist Mode = "allowlist" // localhost and the endpoints in network.allow
+	ModeOffline   Mode = "offline"   // localhost only, e.g. Ollama
+)
+
+// ErrBlocked is the error of a request the policy doesn't allow.
+var ErrBlocked = errors.New("blocked by network policy")
+
+// Policy is what mur may connect to.
+type Policy struct {
+	Mode  Mode
+	Allow []string // allowlist mode: hosts, *.domains, host:port, IPs and CIDRs
+}
+
+// FromConfig returns the policy cfg sets, or an error if network.mode or
+// an entry of network.allow isn't valid.
+func FromConfig(cfg *config.Config) (Policy, error) {
+	p := Policy{Mode: ModeNormal}
+	if cfg == nil {
+		return p, nil
+	}
+	if mode := strings.ToLower(strings.TrimSpace(cfg.Network.Mode)); mode != "" {
+		p.Mode = Mode(mode)
+	}
+	switch p.Mode {
+	case ModeNormal, ModeAllowlist, ModeOffline:
+	default:
+		return p, fmt.Errorf("unknown network.mode %q (want normal, allowlist or offline)", cfg.Network.Mode)
+	}
+	p.Allow = cfg.Network.Allow
+	if _, err := parseRules(p.Allow); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// rule is one network.allow entry.
+type rule struct {
+	host     string       // lowercased name, without "*." for wildcards
+	wildcard bool         // *.example.com: any subdomain of host
+	prefix   netip.Prefix // an IP or CIDR entry instead of a name
+	port     string       // only this port, if set
+}
+
+func parseRules(entries []string) ([]rule, error) {
+	var rules []rule
+	for _, entry := range entries {
+		r, err := parseRule(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func parseRule(entry string) (rule, error) {
+	var r rule
+	if prefix, err := netip.ParsePrefix(entry); err == nil {
+		r.prefix = prefix.Masked()
+		return r, nil
+	}
+	invalid := fmt.Errorf("invalid network.allow entry %q (want a host, *.domain, host:port, IP or CIDR)", entry)
+	host := entry
+	if h, port, err := net.SplitHostPort(entry); err == nil {
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return r, invalid
+		}
+		host, r.port = h, port
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		r.prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		return r, nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	host, r.wildcard = strings.CutPrefix(host, "*.")
+	if host == "" || strings.ContainsAny(host, "/*:@ ") {
+		return r, invalid
+	}
+	r.host = host
+	return r, nil
+}
+
+// Check returns an ErrBlocked error unless the policy allows a request to
+// u. Localhost is always allowed. In allowlist mode a name that no entry
+// allows is resolved and allowed if all its addresses are in IP entries.
+func (p Policy) Check(ctx context.Context, u *url.URL) error {
+	_, err := p.resolve(ctx, u)
+	return err
+}
+
+// resolve is Check that also returns the addresses a request to u must be
+// sent to: those of a name the policy allows only because of them. They
+// are nil when any address will do.
+func (p Policy) resolve(ctx context.Context, u *url.URL) ([]netip.Addr, error) {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if p.Mode == ModeNormal || p.Mode == "" || isLoopback(host) {
+		return nil, nil
+	}
+	if p.Mode == ModeAllowlist {
+		port := u.Port()
+		if port == "" {
+			port = defaultPort(u.Scheme)
+		}
+		rules, err := parseRules(p.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBlocked, err)
+		}
+		if addrs, ok := allowed(ctx, rules, host, port); ok {
+			return addrs, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s (network.mode: %s)", ErrBlocked, u.Host, p.Mode)
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.Unmap().IsLoopback()
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "https", "wss":
+		return "443"
+	case "http", "ws":
+		return "80"
+	case "ssh":
+		return "22"
+	case "git":
+		return "9418"
+	}
+	return ""
+}
+
+// allowed reports whether rules allow host:port and, if they do because
+// of the addresses host resolves to, returns them.
+func allowed(ctx context.Context, rules []rule, host, port string) ([]netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return nil, allowedAddr(rules, addr.Unmap(), port)
+	}
+	ipRules := false
+	for _, r := range rules {
+		if r.port != "" && r.port != port {
+			continue
+		}
+		switch {
+		case r.prefix.IsValid():
+			ipRules = true
+		case r.wildcard:
+			if strings.HasSuffix(host, "."+r.host) {
+				return nil, true
+			}
+		case host == r.host:
+			return nil, true
+		}
+	}
+	if !ipRules {
+		return nil, false
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return nil, false
+	}
+	for i, addr := range addrs {
+		if !allowedAddr(rules, addr.Unmap(), port) {
+			return nil, false
+		}
+		addrs[i] = addr.Unmap()
+	}
+	return addrs, true
+}
+
+func allowedAddr(rules []rule, addr netip.Addr, port string) bool {
+	for _, r := range rules {
+		if r.prefix.IsValid() && (r.port == "" || r.port == port) && r.prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	mu      sync.Mutex
+	current *Policy
+	loadErr error
+)
+
+// Set makes p the policy in force instead of the config's.
+func Set(p Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	current, loadErr = &p, nil
+}
+
+// Current returns the policy in force: the one Set, else the config's,
+// loaded on first use. If the config can't be loaded or its policy isn't
+// valid, the error says why and the policy is offline: mur doesn't guess
+// what a broken config allows.
+func Current() (Policy, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil && loadErr == nil {
+		cfg, err := config.Load()
+		if err != nil {
+			loadErr = fmt.Errorf("cannot load config: %w", err)
+		} else if p, err := FromConfig(cfg); err != nil {
+			loadErr = err
+		} else {
+			current = &p
+		}
+	}
+	if loadErr != nil {
+		return Policy{Mode: ModeOffline}, loadErr
+	}
+	return *current, nil
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a context whose requests are checked against p
+// instead of the policy in force.
+func WithPolicy(ctx context.Context, p Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// Transport wraps an HTTP transport so that requests the policy doesn't
+// allow fail with ErrBlocked instead of being sent.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return transport{rt}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+// For returns the policy requests made with ctx are checked against: the
+// one WithPolicy set, else the policy in force. If that can't be loaded,
+// the error wraps ErrBlocked.
+func For(ctx context.Context) (Policy, error) {
+	if p, ok := ctx.Value(policyKey{}).(Policy); ok {
+		return p, nil
+	}
+	p, err := Current()
+	if err != nil {
+		return p, fmt.Errorf("%w: %v", ErrBlocked, err)
+	}
+	return p, nil
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p, err := For(req.Context())
+	var addrs []netip.Addr
+	if err == nil {
+		addrs, err = p.resolve(req.Context(), req.URL)
+	}
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	if addrs != nil {
+		// Dial what was checked, not what the name resolves to by then
+		port := req.URL.Port()
+		if port == "" {
+			port = defaultPort(req.URL.Scheme)
+		}
+		pin := pinned{address: net.JoinHostPort(req.URL.Hostname(), port), addrs: addrs}
+		req = req.WithContext(context.WithValue(req.Context(), pinnedKey{}, pin))
+	}
+	return t.next.RoundTrip(req)
+}
+
+type pinnedKey struct{}
+
+// pinned are the addresses a connection to address must use.
+type pinned struct {
+	address string
+	addrs   []netip.Addr
+}
+
+// baseDial is how dialContext connects: the dialer http.DefaultTransport
+// had before Install.
+var baseDial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+
+// dialContext dials the addresses the policy checked for a request, if it
+// pinned them, so that a name can't resolve to one address when checked
+// and to another when dialed.
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	pin, ok := ctx.Value(pinnedKey{}).(pinned)
+	if !ok || pin.address != address {
+		return baseDial(ctx, network, address)
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	var firstErr error
+	for _, addr := range pin.addrs {
+		conn, err := baseDial(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// Install guards http.DefaultTransport with the policy and makes it dial
+// the addresses the policy checked. Installing twice does nothing.
+func Install() {
+	if Installed() {
+		return
+	}
+	if base, ok := innermost(http.DefaultTransport).(*http.Transport); ok && !pinning(base) {
+		if base.DialContext != nil {
+			baseDial = base.DialContext
+		}
+		base.DialContext = dialContext
+	}
+	http.DefaultTransport = Transport(http.DefaultTransport)
+}
+
+// innermost returns the transport rt, and the transports wrapping it
+// (with an Unwrap method), send requests through.
+func innermost(rt http.RoundTripper) http.RoundTripper {
+	for {
+		switch t := rt.(type) {
+		case transport:
+			rt = t.next
+		case interface{ Unwrap() http.RoundTripper }:
+			rt = t.Unwrap()
+		default:
+			return rt
+		}
+	}
+}
+
+// pinning reports whether t dials with dialContext and nothing else.
+func pinning(t *http.Transport) bool {
+	return t.DialContext != nil && t.Dial == nil && t.DialTLS == nil && t.DialTLSContext == nil &&
+		reflect.ValueOf(t.DialContext).Pointer() == reflect.ValueOf(dialContext).Pointer()
+}
+
+// guarded returns an error unless requests sent through rt are checked
+// against the policy and dialed by dialContext.
+func guarded(rt http.RoundTripper) error {
+	checked := false
+	for {
+		switch t := rt.(type) {
+		case transport:
+			checked = true
+			rt = t.next
+		case interface{ Unwrap() http.RoundTripper }:
+			rt = t.Unwrap()
+		case *http.Transport:
+			if !checked {
+				return errors.New("doesn't check requests against the policy")
+			}
+			if !pinning(t) {
+				return errors.New("has a dialer of its own")
+			}
+			return nil
+		default:
+			return fmt.Errorf("has a transport of its own (%T)", rt)
+		}
+	}
+}
+
+// Installed reports whether http.DefaultTransport is guarded.
+func Installed() bool {
+	_, ok := http.DefaultTransport.(transport)
+	return ok
+}
+
+// Verify checks that requests of mur's HTTP clients, and of clients, are
+// checked against the policy. Each must send requests through the guarded
+// http.DefaultTransport, or another transport Transport wraps that dials
+// like it; a custom http.Transport or dialer fails. Then a request that
+// offline mode blocks, to an address that can't be reached anyway
+// (TEST-NET-1), must fail with ErrBlocked.
+func Verify(clients ...*http.Client) error {
+	if !Installed() {
+		return errors.New("http.DefaultTransport isn't guarded")
+	}
+	if err := guarded(http.DefaultTransport); err != nil {
+		return fmt.Errorf("http.DefaultTransport %w", err)
+	}
+	for _, c := range append([]*http.Client{http.DefaultClient}, clients...) {
+		if c.Transport == nil {
+			continue // http.DefaultTransport
+		}
+		if err := guarded(c.Transport); err != nil {
+			return fmt.Errorf("an HTTP client %w", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(WithPolicy(context.Background(), Policy{Mode: ModeOffline}), 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://192.0.2.1/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrBlocked) {
+		return fmt.Errorf("a request got past the policy (%v)", err)
+	}
+	return nil
+}
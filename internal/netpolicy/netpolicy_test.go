@@ -0,0 +1,202 @@
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	p, err := FromConfig(&config.Config{})
+	if err != nil || p.Mode != ModeNormal {
+		t.Errorf("default = %+v, %v; want normal", p, err)
+	}
+	cfg := &config.Config{Network: config.NetworkConfig{Mode: "Offline"}}
+	if p, err := FromConfig(cfg); err != nil || p.Mode != ModeOffline {
+		t.Errorf("offline = %+v, %v", p, err)
+	}
+	for _, network := range []config.NetworkConfig{
+		{Mode: "airplane"},
+		{Mode: "allowlist", Allow: []string{"https://api.example.com"}},
+	} {
+		if _, err := FromConfig(&config.Config{Network: network}); err == nil {
+			t.Errorf("%+v: expected an error", network)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	allowlist := Policy{Mode: ModeAllowlist, Allow: []string{"api.anthropic.com", "*.mur.run", "hooks.slack.com:443", "10.0.0.0/8", "192.168.1.5"}}
+	tests := []struct {
+		policy Policy
+		url    string
+		want   bool
+	}{
+		{Policy{Mode: ModeNormal}, "https://api.openai.com/v1", true},
+		{Policy{Mode: ModeOffline}, "https://api.openai.com/v1", false},
+		{Policy{Mode: ModeOffline}, "http://localhost:11434/api/tags", true},
+		{Policy{Mode: ModeOffline}, "http://127.0.0.1:8080", true},
+		{Policy{Mode: ModeOffline}, "http://[::1]:8080", true},
+		{allowlist, "https://api.anthropic.com/v1/messages", true},
+		{allowlist, "https://API.Anthropic.com./v1/messages", true},
+		{allowlist, "https://api.openai.com/v1", false},
+		{allowlist, "https://cloud.mur.run/api", true},
+		{allowlist, "https://mur.run/api", false},
+		{allowlist, "https://hooks.slack.com/services/x", true},
+		{allowlist, "http://hooks.slack.com/services/x", false},
+		{allowlist, "http://10.1.2.3:11434", true},
+		{allowlist, "http://192.168.1.5", true},
+		{allowlist, "http://192.168.1.6", false},
+		{allowlist, "http://localhost:11434", true},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		err := tt.policy.Check(context.Background(), u)
+		if (err == nil) != tt.want {
+			t.Errorf("%s %s: err = %v, want allowed %v", tt.policy.Mode, tt.url, err, tt.want)
+		}
+		if err != nil && !errors.Is(err, ErrBlocked) {
+			t.Errorf("%s: %v isn't ErrBlocked", tt.url, err)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	client := &http.Client{Transport: Transport(http.DefaultTransport)}
+
+	Set(Policy{Mode: ModeOffline})
+	t.Cleanup(func() { Set(Policy{Mode: ModeNormal}) })
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("localhost blocked offline: %v", err)
+	}
+	resp.Body.Close()
+
+	_, err = client.Post("http://192.0.2.1/hook", "application/json", strings.NewReader("{}"))
+	if !errors.Is(err, ErrBlocked) || !strings.Contains(err.Error(), "blocked by network policy: 192.0.2.1 (network.mode: offline)") {
+		t.Errorf("err = %v, want blocked", err)
+	}
+
+	// A request's own policy wins
+	req, _ := http.NewRequestWithContext(WithPolicy(context.Background(), Policy{Mode: ModeNormal}), http.MethodGet, srv.URL, nil)
+	if resp, err := client.Do(req); err != nil {
+		t.Errorf("WithPolicy: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+func TestVerify(t *testing.T) {
+	orig := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = orig })
+	if err := Verify(); err == nil {
+		t.Error("Verify passed without Install")
+	}
+	Install()
+	Install()
+	if _, twice := http.DefaultTransport.(transport).next.(transport); twice {
+		t.Error("installed twice")
+	}
+	if err := Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := Verify(&http.Client{}, &http.Client{Transport: Transport(http.DefaultTransport)}); err != nil {
+		t.Errorf("Verify(guarded clients): %v", err)
+	}
+
+	dialer := &net.Dialer{}
+	for name, c := range map[string]*http.Client{
+		"custom transport":        {Transport: &http.Transport{}},
+		"custom dialer":           {Transport: Transport(&http.Transport{DialContext: dialer.DialContext})},
+		"unchecked pinning dials": {Transport: &http.Transport{DialContext: dialContext}},
+		"other round tripper":     {Transport: Transport(roundTripFunc(http.DefaultTransport.RoundTrip))},
+	} {
+		if err := Verify(c); err == nil {
+			t.Errorf("Verify passed a client with a %s", name)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestDialPinned(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	// The checked address is dialed; the name is never resolved again
+	address := net.JoinHostPort("pinned.invalid", port)
+	ctx := context.WithValue(context.Background(), pinnedKey{}, pinned{address: address, addrs: []netip.Addr{netip.MustParseAddr("127.0.0.1")}})
+	conn, err := dialContext(ctx, "tcp", address)
+	if err != nil {
+		t.Fatalf("dial pinned: %v", err)
+	}
+	conn.Close()
+
+	if _, err := dialContext(context.Background(), "tcp", address); err == nil {
+		t.Error("dialed an unresolvable name without a pin")
+	}
+}
+
+// bypass matches code that could reach the network without going through
+// http.DefaultTransport.
+var bypass = regexp.MustCompile(`Transport:|http\.Transport\{|DefaultTransport\s*=|\bnet\.Dial|tls\.Dial|websocket\.Dialer|"curl"|"wget"`)
+
+// TestNoBypass keeps every HTTP client on http.DefaultTransport, which is
+// what Install guards. The one place that sets DefaultTransport is where
+// mur installs the guard.
+func TestNoBypass(t *testing.T) {
+	root := filepath.Join("..", "..")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) && path != root {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, "internal/netpolicy/") || rel == "internal/timing/net.go" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			if !bypass.MatchString(line) {
+				continue
+			}
+			if rel == "cmd/mur/cmd/root.go" && strings.Contains(line, "http.DefaultTransport = timing.Transport(") {
+				continue
+			}
+			t.Errorf("%s:%d bypasses the network policy: %s", rel, i+1, strings.TrimSpace(line))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // discordMessage represents a Discord webhook message.
 type discordMessage struct {
-	Embeds []discordEmbed `json:"embeds"`
+	Content string         `json:"content,omitempty"` // templated messages
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
 }
 
 type discordEmbed struct {
@@ -40,9 +41,13 @@ const (
 	colorGray   = 0x95A5A6 // Test
 )
 
-// NotifyDiscord sends a notification to a Discord webhook.
+// NotifyDiscord sends a notification to a Discord webhook. Templates work
+// as for NotifySlack.
 func NotifyDiscord(webhookURL string, event string, opts Options) error {
-	msg := buildDiscordMessage(event, opts)
+	msg, tmplErr := discordPayload(event, opts)
+	if tmplErr != nil {
+		msg = buildDiscordMessage(event, opts)
+	}
 
 	payload, err := json.Marshal(msg)
 	if err != nil {
@@ -61,9 +66,25 @@ func NotifyDiscord(webhookURL string, event string, opts Options) error {
 		return fmt.Errorf("discord returned status %d", resp.StatusCode)
 	}
 
+	if tmplErr != nil {
+		return fmt.Errorf("sent the built-in message instead: %w", tmplErr)
+	}
 	return nil
 }
 
+// discordPayload returns the templated message for event, or the built-in
+// one when there is no template.
+func discordPayload(event string, opts Options) (discordMessage, error) {
+	text, ok, err := renderTemplate(event, ChannelDiscord, opts)
+	if err != nil {
+		return discordMessage{}, err
+	}
+	if !ok {
+		return buildDiscordMessage(event, opts), nil
+	}
+	return discordMessage{Content: text}, nil
+}
+
 func buildDiscordMessage(event string, opts Options) discordMessage {
 	embed := discordEmbed{
 		Title:     formatTitle(event),
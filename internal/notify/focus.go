@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// FocusActive reports whether a macOS Focus mode or Do Not Disturb is
+// on. macOS 12 and later record manually enabled Focus modes in
+// ~/Library/DoNotDisturb/DB/Assertions.json; older versions keep a
+// doNotDisturb default. Scheduled Focus modes aren't detected.
+func FocusActive() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	if data, err := os.ReadFile(filepath.Join(home, "Library", "DoNotDisturb", "DB", "Assertions.json")); err == nil {
+		return focusFromAssertions(data)
+	}
+	res, err := execx.Run(context.Background(), "defaults", "-currentHost", "read", "com.apple.notificationcenterui", "doNotDisturb")
+	return err == nil && strings.TrimSpace(res.Stdout) == "1"
+}
+
+// focusFromAssertions reports whether a Focus assertions file holds an
+// active assertion.
+func focusFromAssertions(data []byte) bool {
+	var doc struct {
+		Data []struct {
+			StoreAssertionRecords []json.RawMessage `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(data, &doc) != nil {
+		return false
+	}
+	for _, d := range doc.Data {
+		if len(d.StoreAssertionRecords) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldSend reports whether a notification with key may be shown at
+// now: not if the same one was shown within window. It records the
+// notification as shown at path, forgetting ones older than a day.
+func shouldSend(path, key string, window time.Duration, now time.Time) bool {
+	recent := make(map[string]time.Time)
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &recent)
+	}
+	if last, ok := recent[key]; ok && now.Sub(last) < window {
+		return false
+	}
+
+	recent[key] = now.UTC()
+	for k, t := range recent {
+		if now.Sub(t) > 24*time.Hour {
+			delete(recent, k)
+		}
+	}
+	if data, err := json.Marshal(recent); err == nil {
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		_ = os.WriteFile(path, data, 0644)
+	}
+	return true
+}
@@ -10,7 +10,8 @@ import (
 
 // slackMessage represents a Slack message with blocks.
 type slackMessage struct {
-	Blocks []slackBlock `json:"blocks"`
+	Text   string       `json:"text,omitempty"` // templated messages
+	Blocks []slackBlock `json:"blocks,omitempty"`
 }
 
 type slackBlock struct {
@@ -29,9 +30,14 @@ type slackTextField struct {
 	Text string `json:"text"`
 }
 
-// NotifySlack sends a notification to a Slack webhook.
+// NotifySlack sends a notification to a Slack webhook. A template in
+// TemplatesDir() replaces the built-in message; if it fails to render, the
+// built-in message is sent and the template error returned.
 func NotifySlack(webhookURL string, event string, opts Options) error {
-	msg := buildSlackMessage(event, opts)
+	msg, tmplErr := slackPayload(event, opts)
+	if tmplErr != nil {
+		msg = buildSlackMessage(event, opts)
+	}
 
 	payload, err := json.Marshal(msg)
 	if err != nil {
@@ -49,9 +55,25 @@ func NotifySlack(webhookURL string, event string, opts Options) error {
 		return fmt.Errorf("slack returned status %d", resp.StatusCode)
 	}
 
+	if tmplErr != nil {
+		return fmt.Errorf("sent the built-in message instead: %w", tmplErr)
+	}
 	return nil
 }
 
+// slackPayload returns the templated message for event, or the built-in
+// one when there is no template.
+func slackPayload(event string, opts Options) (slackMessage, error) {
+	text, ok, err := renderTemplate(event, ChannelSlack, opts)
+	if err != nil {
+		return slackMessage{}, err
+	}
+	if !ok {
+		return buildSlackMessage(event, opts), nil
+	}
+	return slackMessage{Text: text}, nil
+}
+
 func buildSlackMessage(event string, opts Options) slackMessage {
 	blocks := []slackBlock{
 		{
@@ -2,12 +2,19 @@
 package notify
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/mur-run/mur-core/internal/async"
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 // Level represents notification severity.
@@ -20,47 +27,100 @@ const (
 	LevelCritical Level = "critical"
 )
 
+// DefaultCoalesceWindow is how long an identical system notification is
+// held back after it was shown (notifications.coalesce_minutes).
+const DefaultCoalesceWindow = 10 * time.Minute
+
+// Notification is a system notification.
+type Notification struct {
+	Title   string
+	Message string
+	Level   Level
+	// Group names the notification; a newer one in the same group
+	// replaces it in Notification Center. Default "mur".
+	Group string
+	// Actions are buttons; notifiers without buttons run the first one
+	// when the notification is clicked. Dismiss is always offered.
+	Actions []Action
+}
+
+// Action is a notification button and the command it runs.
+type Action struct {
+	Label   string
+	Command []string
+}
+
+// TerminalAction returns an action that opens Terminal and runs a shell
+// command there, for commands that show or ask something.
+func TerminalAction(label, command string) Action {
+	return Action{Label: label, Command: []string{
+		"osascript",
+		"-e", fmt.Sprintf(`tell application "Terminal" to do script "%s"`, appleScriptString(command)),
+		"-e", `tell application "Terminal" to activate`,
+	}}
+}
+
 // SystemNotify sends a system notification (macOS Notification Center).
 func SystemNotify(title, message string, level Level) error {
+	return Send(Notification{Title: title, Message: message, Level: level})
+}
+
+// Send sends a system notification (macOS Notification Center). While a
+// Focus mode or Do Not Disturb is on, only critical notifications are
+// sent (unless notifications.ignore_focus is set), and a notification
+// identical to one shown within the coalesce window is dropped.
+func Send(n Notification) error {
+	window := DefaultCoalesceWindow
+	respectFocus := true
 	cfg, err := config.Load()
 	if err != nil {
 		// If config fails, still try to notify for errors
-		if level != LevelError && level != LevelCritical {
+		if n.Level != LevelError && n.Level != LevelCritical {
 			return nil
 		}
-	} else if !cfg.Notifications.System {
-		return nil
+	} else {
+		if !cfg.Notifications.System {
+			return nil
+		}
+		if cfg.Notifications.CoalesceMinutes != 0 {
+			window = time.Duration(cfg.Notifications.CoalesceMinutes) * time.Minute
+		}
+		respectFocus = !cfg.Notifications.IgnoreFocus
 	}
 
 	if runtime.GOOS != "darwin" {
 		return nil // Only macOS supported for now
 	}
+	if n.Group == "" {
+		n.Group = "mur"
+	}
 
-	// Choose sound based on level
-	sound := "default"
-	switch level {
-	case LevelError, LevelCritical:
-		sound = "Basso"
-	case LevelWarning:
-		sound = "Purr"
-	case LevelInfo:
-		sound = "Pop"
+	if respectFocus && n.Level != LevelCritical && FocusActive() {
+		return nil
+	}
+	if window > 0 {
+		if path, err := coalescePath(); err == nil && !shouldSend(path, n.Group+"\x00"+n.Title+"\x00"+n.Message, window, time.Now()) {
+			return nil
+		}
 	}
 
-	// Try terminal-notifier first (better UX, supports click actions)
-	if _, err := exec.LookPath("terminal-notifier"); err == nil {
-		args := []string{
-			"-title", title,
-			"-message", message,
-			"-sound", sound,
-			"-group", "mur",
+	sound := soundFor(n.Level)
+
+	// alerter shows action buttons and reports which was clicked. It waits
+	// for the user, so a detached mur runs it and then the action.
+	if len(n.Actions) > 0 && execx.Allowed("alerter") {
+		if _, err := exec.LookPath("alerter"); err == nil {
+			if data, err := json.Marshal(n); err == nil && async.RunBackground([]string{"notify", "alert", string(data)}) == nil {
+				return nil
+			}
 		}
+	}
 
-		// Add sender for icon
-		args = append(args, "-sender", "com.apple.Terminal")
+	ctx := context.Background()
 
-		cmd := exec.Command("terminal-notifier", args...)
-		if err := cmd.Run(); err == nil {
+	// Try terminal-notifier next (better UX, supports click actions)
+	if _, err := exec.LookPath("terminal-notifier"); err == nil {
+		if _, err := execx.Run(ctx, "terminal-notifier", terminalNotifierArgs(n, sound)...); err == nil {
 			return nil
 		}
 		// Fall through to osascript if terminal-notifier fails
@@ -69,13 +129,88 @@ func SystemNotify(title, message string, level Level) error {
 	// Fallback to osascript
 	script := fmt.Sprintf(
 		`display notification %q with title %q sound name %q`,
-		escapeAppleScript(message),
-		escapeAppleScript(title),
+		escapeAppleScript(n.Message),
+		escapeAppleScript(n.Title),
 		sound,
 	)
 
-	cmd := exec.Command("osascript", "-e", script)
-	return cmd.Run()
+	_, err = execx.Run(ctx, "osascript", "-e", script)
+	return err
+}
+
+// soundFor returns the notification sound for level.
+func soundFor(level Level) string {
+	switch level {
+	case LevelError, LevelCritical:
+		return "Basso"
+	case LevelWarning:
+		return "Purr"
+	case LevelInfo:
+		return "Pop"
+	}
+	return "default"
+}
+
+// terminalNotifierArgs returns the terminal-notifier arguments for n.
+// Clicking the notification runs its first action.
+func terminalNotifierArgs(n Notification, sound string) []string {
+	args := []string{
+		"-title", n.Title,
+		"-message", n.Message,
+		"-sound", sound,
+		"-group", n.Group,
+	}
+	if len(n.Actions) > 0 {
+		args = append(args, "-execute", shellJoin(n.Actions[0].Command))
+	}
+
+	// Add sender for icon
+	return append(args, "-sender", "com.apple.Terminal")
+}
+
+// Alert shows n with alerter, waits for the user, and runs the command
+// of the button clicked. Neither goes through a shell.
+func Alert(ctx context.Context, n Notification) error {
+	if n.Group == "" {
+		n.Group = "mur"
+	}
+	labels := alerterLabels(n)
+	res, err := execx.Run(ctx, "alerter", alerterArgs(n, labels, soundFor(n.Level))...)
+	if err != nil {
+		return err
+	}
+	choice := strings.TrimSpace(res.Stdout)
+	for i, a := range n.Actions {
+		if labels[i] == choice && len(a.Command) > 0 {
+			_, err := execx.Run(ctx, a.Command[0], a.Command[1:]...)
+			return err
+		}
+	}
+	return nil // dismissed or timed out
+}
+
+// alerterLabels returns the button labels of n: alerter takes them as a
+// comma-separated list.
+func alerterLabels(n Notification) []string {
+	labels := make([]string, len(n.Actions))
+	for i, a := range n.Actions {
+		labels[i] = strings.ReplaceAll(a.Label, ",", " ")
+	}
+	return labels
+}
+
+// alerterArgs returns the alerter arguments that show n with the buttons
+// labels.
+func alerterArgs(n Notification, labels []string, sound string) []string {
+	return []string{
+		"-title", n.Title,
+		"-message", n.Message,
+		"-sound", sound,
+		"-group", n.Group,
+		"-actions", strings.Join(labels, ","),
+		"-closeLabel", "Dismiss",
+		"-timeout", "600",
+	}
 }
 
 // NotifyError sends an error notification.
@@ -85,11 +220,11 @@ func NotifyError(message string) error {
 
 // NotifyCritical sends a critical error notification.
 func NotifyCritical(title, message string) error {
-	return SystemNotify(title, message, LevelCritical)
+	return Send(Notification{Title: title, Message: message, Level: LevelCritical, Group: "mur-critical"})
 }
 
-// NotifySuccess sends a success notification.
-func NotifySuccess(message string) error {
+// NotifySuccess sends a success notification, with optional actions.
+func NotifySuccess(message string, actions ...Action) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil
@@ -97,14 +232,12 @@ func NotifySuccess(message string) error {
 	if !cfg.Notifications.OnPatterns {
 		return nil
 	}
-	return SystemNotify("mur: Success", message, LevelInfo)
+	return Send(Notification{Title: "mur: Success", Message: message, Level: LevelInfo, Group: "mur-success", Actions: actions})
 }
 
 // escapeAppleScript escapes a string for use in AppleScript.
 func escapeAppleScript(s string) string {
-	// Escape backslashes and quotes
-	s = strings.ReplaceAll(s, "\\", "\\\\")
-	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = appleScriptString(s)
 	// Truncate long messages
 	if len(s) > 200 {
 		s = s[:197] + "..."
@@ -112,7 +245,37 @@ func escapeAppleScript(s string) string {
 	return s
 }
 
+// appleScriptString escapes backslashes and quotes for an AppleScript
+// string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes each argument for sh and joins them.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
 // IsSystemNotifyAvailable returns true if system notifications are available.
 func IsSystemNotifyAvailable() bool {
 	return runtime.GOOS == "darwin"
 }
+
+// coalescePath returns where recently shown notifications are kept.
+func coalescePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config.StateDir(home), "notify-recent.json"), nil
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify-recent.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Minute
+
+	if !shouldSend(path, "a", window, now) {
+		t.Fatal("first notification held back")
+	}
+	if shouldSend(path, "a", window, now.Add(5*time.Minute)) {
+		t.Error("repeat within the window sent")
+	}
+	if !shouldSend(path, "b", window, now.Add(5*time.Minute)) {
+		t.Error("different notification held back")
+	}
+	if !shouldSend(path, "a", window, now.Add(11*time.Minute)) {
+		t.Error("repeat after the window held back")
+	}
+}
+
+func TestFocusFromAssertions(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"active", `{"data":[{"storeAssertionRecords":[{"assertionDetails":{"assertionDetailsModeIdentifier":"com.apple.focus.work"}}]}]}`, true},
+		{"none", `{"data":[{"storeAssertionRecords":[]}]}`, false},
+		{"empty", `{"data":[]}`, false},
+		{"invalid", `not json`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := focusFromAssertions([]byte(tt.data)); got != tt.want {
+				t.Errorf("focusFromAssertions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalNotifierArgs(t *testing.T) {
+	n := Notification{Title: "mur", Message: "3 new", Group: "mur-success",
+		Actions: []Action{{Label: "Open", Command: []string{"mur", "learn", "list"}}}}
+	args := strings.Join(terminalNotifierArgs(n, "Pop"), " ")
+	for _, want := range []string{"-group mur-success", "-execute 'mur' 'learn' 'list'"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args %q missing %q", args, want)
+		}
+	}
+
+	n.Actions = nil
+	if args := strings.Join(terminalNotifierArgs(n, "Pop"), " "); strings.Contains(args, "-execute") {
+		t.Errorf("args %q run a command without an action", args)
+	}
+}
+
+func TestAlerterArgs(t *testing.T) {
+	n := Notification{Title: "it's", Message: "done; rm -rf ~", Group: "mur",
+		Actions: []Action{{Label: "Open, review", Command: []string{"echo", "opened"}}}}
+	labels := alerterLabels(n)
+	if len(labels) != 1 || labels[0] != "Open  review" {
+		t.Errorf("labels = %q", labels)
+	}
+	args := alerterArgs(n, labels, "Pop")
+	for i, want := range []string{"-title", "it's", "-message", "done; rm -rf ~"} {
+		if args[i] != want {
+			t.Errorf("args[%d] = %q, want %q", i, args[i], want)
+		}
+	}
+	if got := strings.Join(args, " "); !strings.Contains(got, "-actions Open  review -closeLabel Dismiss") {
+		t.Errorf("args = %q", got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+	out, err := exec.Command("sh", "-c", "printf %s "+shellQuote(`a "b" 'c' $d`)).Output()
+	if err != nil {
+		t.Skip("no sh")
+	}
+	if string(out) != `a "b" 'c' $d` {
+		t.Errorf("round trip = %q", out)
+	}
+}
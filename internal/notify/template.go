@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Notification channels, used to pick a channel-specific template.
+const (
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+)
+
+// Events returns the event types that can be templated.
+func Events() []string {
+	return []string{EventPatternAdded, EventPatternsExtracted, EventPRCreated, EventTest}
+}
+
+// TemplateData is the input to a notification template.
+type TemplateData struct {
+	Options
+	Event   string
+	Channel string // slack or discord
+}
+
+// Percent returns the confidence as a whole percentage.
+func (d TemplateData) Percent() int {
+	return int(d.Confidence*100 + 0.5)
+}
+
+var templateFuncs = template.FuncMap{
+	"truncate": func(max int, s string) string { return truncate(s, max) },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+// TemplatesDir returns the directory for notification templates
+// (~/.mur/templates/notify/).
+func TemplatesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.DataDir(home), "templates", "notify"), nil
+}
+
+// TemplatePath returns the template that overrides the built-in message for
+// event on channel: <event>.<channel>.tmpl, then <event>.tmpl. It returns
+// "" when there is none.
+func TemplatePath(event, channel string) string {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{event + "." + channel + ".tmpl", event + ".tmpl"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// renderTemplate renders the user template for event on channel. ok is
+// false when there is no template, and the built-in message should be used.
+func renderTemplate(event, channel string, opts Options) (text string, ok bool, err error) {
+	path := TemplatePath(event, channel)
+	if path == "" {
+		return "", false, nil
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(src))
+	if err != nil {
+		return "", false, fmt.Errorf("invalid template %s: %w", filepath.Base(path), err)
+	}
+
+	var sb strings.Builder
+	data := TemplateData{Options: opts, Event: event, Channel: channel}
+	if data.Title == "" {
+		data.Title = formatTitle(event)
+	}
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", false, fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	return strings.TrimSpace(sb.String()), true, nil
+}
+
+// SampleOptions returns example data for event, for previewing templates.
+func SampleOptions(event string) Options {
+	opts := Options{
+		PatternName: "api-retry-backoff",
+		Confidence:  0.85,
+		Preview:     "Retry failed API calls with exponential backoff and jitter; give up after 5 attempts and surface the last error.",
+		Source:      "a1b2c3d4",
+	}
+	switch event {
+	case EventPatternsExtracted:
+		opts.Count = 3
+	case EventPRCreated:
+		opts.PRURL = "https://github.com/acme/patterns/pull/42"
+	case EventTest:
+		opts.PatternName = "test-pattern"
+	}
+	return opts
+}
+
+// Preview is a notification as it would be sent to one channel.
+type Preview struct {
+	Channel  string
+	Template string // template path; empty for the built-in message
+	Text     string // rendered template
+	Payload  []byte // webhook JSON body
+}
+
+// Render builds the message for event on channel without sending it.
+func Render(event, channel string, opts Options) (*Preview, error) {
+	var msg any
+	var err error
+	switch channel {
+	case ChannelSlack:
+		msg, err = slackPayload(event, opts)
+	case ChannelDiscord:
+		msg, err = discordPayload(event, opts)
+	default:
+		return nil, fmt.Errorf("unknown channel %q (use slack or discord)", channel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // keep Slack's <url|text> links readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(msg); err != nil {
+		return nil, fmt.Errorf("failed to marshal %s message: %w", channel, err)
+	}
+	p := &Preview{Channel: channel, Template: TemplatePath(event, channel), Payload: bytes.TrimSpace(buf.Bytes())}
+	switch m := msg.(type) {
+	case slackMessage:
+		p.Text = m.Text
+	case discordMessage:
+		p.Text = m.Content
+	}
+	return p, nil
+}
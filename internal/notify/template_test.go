@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, name, content string) {
+	t.Helper()
+	dir, err := TemplatesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.MkdirAll(dir, 0755)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderTemplates(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+
+	opts := SampleOptions(EventPRCreated)
+	p, err := Render(EventPRCreated, ChannelSlack, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Template != "" || p.Text != "" || !strings.Contains(string(p.Payload), `"blocks"`) {
+		t.Errorf("without templates got %+v", p)
+	}
+
+	writeTemplate(t, "pr_created.tmpl", "{{.PatternName}} {{.Percent}}% {{.PRURL}} via {{.Channel}}\n")
+	writeTemplate(t, "pr_created.discord.tmpl", "[{{upper .Event}}]({{.PRURL}}) {{truncate 8 .Preview}}")
+
+	p, err = Render(EventPRCreated, ChannelSlack, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "api-retry-backoff 85% " + opts.PRURL + " via slack"; p.Text != want {
+		t.Errorf("slack text = %q, want %q", p.Text, want)
+	}
+	if !strings.Contains(string(p.Payload), `"text": "api-retry-backoff`) || strings.Contains(string(p.Payload), "blocks") {
+		t.Errorf("slack payload = %s", p.Payload)
+	}
+
+	p, err = Render(EventPRCreated, ChannelDiscord, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[PR_CREATED](" + opts.PRURL + ") Retry..."; p.Text != want {
+		t.Errorf("discord text = %q, want %q", p.Text, want)
+	}
+	if !strings.HasSuffix(p.Template, "pr_created.discord.tmpl") {
+		t.Errorf("discord template = %s", p.Template)
+	}
+}
+
+func TestNotifySlackFallsBackOnBrokenTemplate(t *testing.T) {
+	t.Setenv("MUR_HOME", t.TempDir())
+	writeTemplate(t, "pattern_added.tmpl", "{{.NoSuchField}}")
+
+	var got slackMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	err := NotifySlack(srv.URL, EventPatternAdded, SampleOptions(EventPatternAdded))
+	if err == nil || !strings.Contains(err.Error(), "built-in message") {
+		t.Errorf("err = %v, want a template error", err)
+	}
+	if len(got.Blocks) == 0 {
+		t.Error("built-in message wasn't sent")
+	}
+}
@@ -0,0 +1,186 @@
+// Package output is the mur CLI's shared output layer. It turns the global
+// --quiet, --verbose, --no-color, --porcelain, and --json flags and the
+// NO_COLOR, CI, and TERM environment variables into a Printer, so every
+// command treats them the same way.
+//
+// Human output (Printf, Println) is dropped by --quiet, --porcelain, and
+// --json. Warnings go to stderr unless --quiet. With --porcelain, commands
+// that support it write Records instead: one tab-separated line per item,
+// starting with the record kind, in a format that is stable across
+// releases (fields may be added at the end, never removed or reordered).
+// With --json they write one JSON document with WriteJSON; field names
+// are snake_case and, like records, only ever added to.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Options are the global output flags.
+type Options struct {
+	Quiet     bool // only errors and warnings the user must act on
+	Verbose   bool // extra detail; ignored with Quiet or Porcelain
+	NoColor   bool
+	Porcelain bool // stable machine-readable records on stdout
+	JSON      bool // one JSON document on stdout; takes precedence over Porcelain
+}
+
+// Printer writes command output according to Options.
+type Printer struct {
+	out, err io.Writer
+	opts     Options
+	color    bool
+}
+
+// New returns a Printer writing to stdout and stderr.
+func New(opts Options) *Printer {
+	return NewWriter(opts, os.Stdout, os.Stderr)
+}
+
+// NewWriter returns a Printer writing to out and err. Color is used only
+// when out is a terminal and neither --no-color, --porcelain, NO_COLOR,
+// CI, nor TERM=dumb turn it off.
+func NewWriter(opts Options, out, err io.Writer) *Printer {
+	if opts.JSON {
+		opts.Porcelain = false
+	}
+	p := &Printer{out: out, err: err, opts: opts}
+	p.color = !opts.NoColor && !opts.Porcelain && !opts.JSON && colorEnv() && isTerminal(out)
+	return p
+}
+
+// colorEnv reports whether the environment allows color
+// (https://no-color.org; CI logs often don't render escapes).
+func colorEnv() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if os.Getenv("CI") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return true
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Quiet reports whether human output is suppressed, by --quiet,
+// --porcelain, or --json.
+func (p *Printer) Quiet() bool { return p.opts.Quiet || p.opts.Porcelain || p.opts.JSON }
+
+// Verbose reports whether extra detail should be shown.
+func (p *Printer) Verbose() bool { return p.opts.Verbose && !p.Quiet() }
+
+// Porcelain reports whether the command should write Records.
+func (p *Printer) Porcelain() bool { return p.opts.Porcelain }
+
+// JSON reports whether the command should write its result with
+// WriteJSON.
+func (p *Printer) JSON() bool { return p.opts.JSON }
+
+// Color reports whether output may contain ANSI colors.
+func (p *Printer) Color() bool { return p.color }
+
+// Out returns the writer for human output, or io.Discard when it is
+// suppressed.
+func (p *Printer) Out() io.Writer {
+	if p.Quiet() {
+		return io.Discard
+	}
+	return p.out
+}
+
+// Printf writes human output.
+func (p *Printer) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(p.Out(), format, args...)
+}
+
+// Println writes a line of human output.
+func (p *Printer) Println(args ...interface{}) {
+	fmt.Fprintln(p.Out(), args...)
+}
+
+// Verbosef writes detail shown only with --verbose.
+func (p *Printer) Verbosef(format string, args ...interface{}) {
+	if p.Verbose() {
+		fmt.Fprintf(p.out, format, args...)
+	}
+}
+
+// Warnf writes a warning to stderr, unless --quiet. Warnings are kept
+// with --porcelain, since they don't touch stdout.
+func (p *Printer) Warnf(format string, args ...interface{}) {
+	if !p.opts.Quiet {
+		fmt.Fprintf(p.err, format, args...)
+	}
+}
+
+// Record writes a porcelain record: kind and fields, tab-separated, with
+// backslashes, tabs, and newlines in fields escaped as \\, \t, and \n.
+// It writes nothing without --porcelain.
+func (p *Printer) Record(kind string, fields ...string) {
+	if !p.opts.Porcelain {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(kind)
+	for _, f := range fields {
+		b.WriteByte('\t')
+		b.WriteString(escaper.Replace(f))
+	}
+	b.WriteByte('\n')
+	_, _ = io.WriteString(p.out, b.String())
+}
+
+// WriteJSON writes v as indented JSON to stdout, whatever the other
+// flags. Commands call it when JSON() is set.
+func (p *Printer) WriteJSON(v interface{}) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return nil
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// ANSI colors for status marks and emphasis.
+const (
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	dim    = "\033[2m"
+	bold   = "\033[1m"
+	reset  = "\033[0m"
+)
+
+func (p *Printer) paint(code, s string) string {
+	if !p.color {
+		return s
+	}
+	return code + s + reset
+}
+
+// Green colors s for success, e.g. a ✓ mark.
+func (p *Printer) Green(s string) string { return p.paint(green, s) }
+
+// Red colors s for failure.
+func (p *Printer) Red(s string) string { return p.paint(red, s) }
+
+// Yellow colors s for warnings and skips.
+func (p *Printer) Yellow(s string) string { return p.paint(yellow, s) }
+
+// Dim de-emphasizes s.
+func (p *Printer) Dim(s string) string { return p.paint(dim, s) }
+
+// Bold emphasizes s.
+func (p *Printer) Bold(s string) string { return p.paint(bold, s) }
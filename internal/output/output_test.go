@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		opts             Options
+		wantOut, wantErr string
+		verbose          bool
+	}{
+		{"normal", Options{}, "hello\n", "careful\n", false},
+		{"verbose", Options{Verbose: true}, "hello\ndetail\n", "careful\n", true},
+		{"quiet", Options{Quiet: true, Verbose: true}, "", "", false},
+		{"porcelain", Options{Porcelain: true, Verbose: true}, "pattern\tgo-errors\n", "careful\n", false},
+		{"json wins over porcelain", Options{JSON: true, Porcelain: true}, "", "careful\n", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			p := NewWriter(tt.opts, &out, &errOut)
+			p.Println("hello")
+			p.Verbosef("detail\n")
+			p.Warnf("careful\n")
+			p.Record("pattern", "go-errors")
+
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if errOut.String() != tt.wantErr {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantErr)
+			}
+			if p.Verbose() != tt.verbose {
+				t.Errorf("Verbose() = %v", p.Verbose())
+			}
+		})
+	}
+}
+
+func TestRecordEscapes(t *testing.T) {
+	var out bytes.Buffer
+	p := NewWriter(Options{Porcelain: true}, &out, &out)
+	p.Record("sync", "claude", "ok", "line one\nline\ttwo \\ three", "")
+	want := "sync\tclaude\tok\tline one\\nline\\ttwo \\\\ three\t\n"
+	if out.String() != want {
+		t.Errorf("record = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	p := NewWriter(Options{JSON: true, Quiet: true}, &out, &out)
+	if !p.JSON() || !p.Quiet() {
+		t.Fatalf("JSON() = %v, Quiet() = %v", p.JSON(), p.Quiet())
+	}
+	if err := p.WriteJSON(map[string]int{"patterns": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"patterns\": 2\n}\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestColor(t *testing.T) {
+	t.Setenv("CI", "")
+	t.Setenv("TERM", "xterm")
+
+	// A buffer is never a terminal.
+	p := NewWriter(Options{}, &bytes.Buffer{}, &bytes.Buffer{})
+	if p.Color() || p.Green("✓") != "✓" {
+		t.Error("color enabled for a non-terminal")
+	}
+
+	p.color = true
+	if p.Green("✓") == "✓" {
+		t.Error("Green didn't color")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if colorEnv() {
+		t.Error("NO_COLOR set (even empty) should disable color")
+	}
+}
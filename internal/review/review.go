@@ -0,0 +1,298 @@
+// Package review checks code changes against learned patterns: it finds
+// the patterns relevant to the files a diff touches and asks an LLM where
+// the change goes against them.
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/classifier"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// DefaultPatterns is how many patterns a review checks against.
+const DefaultPatterns = 8
+
+// maxDiffBytes bounds the diff sent to the LLM.
+const maxDiffBytes = 60000
+
+// maxPatternBytes bounds each pattern's content in the prompt.
+const maxPatternBytes = 2000
+
+// Severities of findings, most serious first.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// LLM is what a review needs from an LLM provider.
+type LLM interface {
+	Complete(prompt string) (string, error)
+}
+
+// FileDiff is the part of a unified diff for one file.
+type FileDiff struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"-"`
+}
+
+// Match is a pattern relevant to the change and the files it applies to.
+type Match struct {
+	Pattern pattern.Pattern `json:"-"`
+	Name    string          `json:"name"`
+	Score   float64         `json:"score"`
+	Files   []string        `json:"files"`
+}
+
+// Finding is one place the change goes against a pattern.
+type Finding struct {
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Pattern    string `json:"pattern"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Result is the outcome of a review.
+type Result struct {
+	Files    []FileDiff `json:"files"`
+	Patterns []Match    `json:"patterns"`
+	Findings []Finding  `json:"findings"`
+}
+
+// HasErrors reports whether any finding is an error.
+func (r *Result) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDiff splits a unified diff (git diff, gh pr diff) by file. Deleted
+// files are left out: there is nothing left in them to review.
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var body strings.Builder
+	flush := func() {
+		if cur != nil && cur.Path != "" {
+			cur.Diff = body.String()
+			files = append(files, *cur)
+		}
+		cur = nil
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			cur = &FileDiff{}
+			if _, b, ok := strings.Cut(strings.TrimSpace(line), " b/"); ok {
+				cur.Path = b
+			}
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
+			if name == "/dev/null" {
+				cur.Path = ""
+			} else {
+				cur.Path = strings.TrimPrefix(name, "b/")
+			}
+		case strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "+"):
+			cur.Added++
+		case strings.HasPrefix(line, "-"):
+			cur.Removed++
+		}
+		if cur != nil {
+			body.WriteString(line)
+		}
+	}
+	flush()
+	return files
+}
+
+// Relevant returns up to limit patterns relevant to files, best first.
+// Each file is matched on its path and its added lines, and a pattern
+// matched by several files keeps its best score.
+func Relevant(store *pattern.Store, files []FileDiff, limit int) ([]Match, error) {
+	if limit <= 0 {
+		limit = DefaultPatterns
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+
+	retriever := classifier.NewRetriever(store)
+	byName := map[string]*Match{}
+	for _, f := range files {
+		matches, err := retriever.Retrieve(classifier.ClassifyInput{
+			Content:     addedLines(f.Diff),
+			CurrentFile: f.Path,
+			Files:       paths,
+		}, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, pm := range matches {
+			m, ok := byName[pm.Pattern.Name]
+			if !ok {
+				m = &Match{Pattern: pm.Pattern, Name: pm.Pattern.Name}
+				byName[pm.Pattern.Name] = m
+			}
+			m.Score = max(m.Score, pm.Score)
+			m.Files = append(m.Files, f.Path)
+		}
+	}
+
+	list := make([]Match, 0, len(byName))
+	for _, m := range byName {
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// addedLines returns the lines a file diff adds, without the "+".
+func addedLines(diff string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++ ") {
+			b.WriteString(line[1:])
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+const reviewPrompt = `You are reviewing a code change against a team's own conventions.
+Check the diff below ONLY against the patterns listed. Don't report
+general style issues, bugs, or anything no pattern covers.
+
+For each place an added line goes against a pattern, report a finding:
+- file: the file path as it appears in the diff
+- line: the line number in the new file, or 0 if unsure
+- severity: "error" if the pattern is clearly broken, "warning" if it
+  probably is, "info" for a suggestion the pattern supports
+- message: what goes against the pattern, in one sentence
+- pattern: the name of the pattern, exactly as listed
+- suggestion: how to follow the pattern instead (optional)
+
+Respond with ONLY a JSON object:
+{"findings": [{"file": "...", "line": 0, "severity": "...", "message": "...", "pattern": "...", "suggestion": "..."}]}
+If the change follows the patterns, respond with {"findings": []}.
+
+## Patterns
+%s
+## Diff
+%s`
+
+// Review asks llm to check files against patterns. Findings citing a
+// pattern that wasn't given, or a file not in the diff, are dropped.
+func Review(llm LLM, files []FileDiff, patterns []Match) (*Result, error) {
+	result := &Result{Files: files, Patterns: patterns, Findings: []Finding{}}
+	if len(files) == 0 || len(patterns) == 0 {
+		return result, nil
+	}
+
+	raw, err := llm.Complete(buildPrompt(files, patterns))
+	if err != nil {
+		return nil, fmt.Errorf("LLM request failed: %w", err)
+	}
+	findings, err := parseFindings(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, m := range patterns {
+		names[m.Name] = true
+	}
+	paths := map[string]bool{}
+	for _, f := range files {
+		paths[f.Path] = true
+	}
+	for _, f := range findings {
+		if !names[f.Pattern] || !paths[f.File] || strings.TrimSpace(f.Message) == "" {
+			continue
+		}
+		switch f.Severity = strings.ToLower(f.Severity); f.Severity {
+		case SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			f.Severity = SeverityWarning
+		}
+		if f.Line < 0 {
+			f.Line = 0
+		}
+		result.Findings = append(result.Findings, f)
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return result, nil
+}
+
+func buildPrompt(files []FileDiff, patterns []Match) string {
+	var p strings.Builder
+	for _, m := range patterns {
+		fmt.Fprintf(&p, "### %s\n", m.Name)
+		if m.Pattern.Description != "" {
+			fmt.Fprintf(&p, "%s\n\n", m.Pattern.Description)
+		}
+		content := m.Pattern.Content
+		if len(content) > maxPatternBytes {
+			content = content[:maxPatternBytes] + "\n[...]"
+		}
+		fmt.Fprintf(&p, "%s\n\n", strings.TrimSpace(content))
+	}
+
+	var d strings.Builder
+	for _, f := range files {
+		if d.Len()+len(f.Diff) > maxDiffBytes {
+			fmt.Fprintf(&d, "[diff of %s left out: too long]\n", f.Path)
+			continue
+		}
+		d.WriteString(f.Diff)
+	}
+	return fmt.Sprintf(reviewPrompt, p.String(), d.String())
+}
+
+// parseFindings reads the findings from the LLM's response, which may
+// wrap the JSON in prose or a code fence.
+func parseFindings(raw string) ([]Finding, error) {
+	start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON object found in LLM response")
+	}
+	var resp struct {
+		Findings []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &resp); err != nil {
+		return nil, fmt.Errorf("invalid JSON in LLM response: %w", err)
+	}
+	return resp.Findings, nil
+}
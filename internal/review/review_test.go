@@ -0,0 +1,126 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+const sampleDiff = `diff --git a/internal/api/handler.go b/internal/api/handler.go
+index 1111111..2222222 100644
+--- a/internal/api/handler.go
++++ b/internal/api/handler.go
+@@ -10,3 +10,4 @@ func Handle() error {
+ 	x := 1
+-	return nil
++	panic("boom")
++	return fmt.Errorf("failed")
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/README.md b/README.md
+new file mode 100644
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1 @@
++# Title
+`
+
+func TestParseDiff(t *testing.T) {
+	files := ParseDiff(sampleDiff)
+	if len(files) != 2 {
+		t.Fatalf("files = %+v", files)
+	}
+	if f := files[0]; f.Path != "internal/api/handler.go" || f.Added != 2 || f.Removed != 1 {
+		t.Errorf("first file = %+v", f)
+	}
+	if !strings.Contains(files[0].Diff, `panic("boom")`) || strings.Contains(files[0].Diff, "old.txt") {
+		t.Errorf("first diff = %q", files[0].Diff)
+	}
+	if f := files[1]; f.Path != "README.md" || f.Added != 1 {
+		t.Errorf("second file = %+v", f)
+	}
+	if got := addedLines(files[0].Diff); got != "\tpanic(\"boom\")\n\treturn fmt.Errorf(\"failed\")\n" {
+		t.Errorf("addedLines = %q", got)
+	}
+}
+
+type fakeLLM struct {
+	reply  string
+	prompt string
+}
+
+func (f *fakeLLM) Complete(prompt string) (string, error) {
+	f.prompt = prompt
+	return f.reply, nil
+}
+
+func TestReview(t *testing.T) {
+	files := ParseDiff(sampleDiff)
+	patterns := []Match{{Name: "no-panic", Pattern: pattern.Pattern{Name: "no-panic", Content: "Return errors, never panic."}}}
+	llm := &fakeLLM{reply: "Here you go:\n```json\n" + `{"findings": [
+		{"file": "internal/api/handler.go", "line": 11, "severity": "ERROR", "message": "panics", "pattern": "no-panic"},
+		{"file": "README.md", "line": 1, "severity": "bogus", "message": "odd title", "pattern": "no-panic"},
+		{"file": "internal/api/handler.go", "line": 12, "severity": "info", "message": "made up", "pattern": "invented"},
+		{"file": "elsewhere.go", "line": 1, "severity": "error", "message": "not in diff", "pattern": "no-panic"}
+	]}` + "\n```"}
+
+	result, err := Review(llm, files, patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(llm.prompt, "### no-panic") || !strings.Contains(llm.prompt, `+	panic("boom")`) {
+		t.Errorf("prompt is missing the pattern or diff:\n%s", llm.prompt)
+	}
+	want := []Finding{
+		{File: "README.md", Line: 1, Severity: SeverityWarning, Message: "odd title", Pattern: "no-panic"},
+		{File: "internal/api/handler.go", Line: 11, Severity: SeverityError, Message: "panics", Pattern: "no-panic"},
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("findings = %+v", result.Findings)
+	}
+	for i := range want {
+		if result.Findings[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, result.Findings[i], want[i])
+		}
+	}
+	if !result.HasErrors() {
+		t.Error("HasErrors = false")
+	}
+
+	if _, err := Review(&fakeLLM{reply: "looks fine to me"}, files, patterns); err == nil {
+		t.Error("expected an error for a response without JSON")
+	}
+}
+
+func TestRelevant(t *testing.T) {
+	store := pattern.NewStore(filepath.Join(t.TempDir(), "patterns"))
+	for _, p := range []*pattern.Pattern{
+		{Name: "go-errors", Content: "Wrap errors with fmt.Errorf and %w.", Applies: pattern.ApplyConditions{FilePatterns: []string{"*.go"}}},
+		{Name: "docs-style", Content: "Use sentence case in markdown headings.", Applies: pattern.ApplyConditions{FilePatterns: []string{"*.md"}}},
+	} {
+		if err := store.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := Relevant(store, ParseDiff(sampleDiff), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]string{}
+	for _, m := range matches {
+		files[m.Name] = m.Files
+	}
+	if got := files["go-errors"]; len(got) == 0 || got[0] != "internal/api/handler.go" {
+		t.Errorf("go-errors files = %v (matches %+v)", got, matches)
+	}
+	if got := files["docs-style"]; len(got) == 0 || got[0] != "README.md" {
+		t.Errorf("docs-style files = %v (matches %+v)", got, matches)
+	}
+}
@@ -0,0 +1,296 @@
+package router
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Decision is one routing decision with the features it was based on and
+// how it turned out. The prompt itself is never stored.
+type Decision struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Prompt features
+	PromptLength int      `json:"prompt_length"`
+	PromptHash   string   `json:"prompt_hash,omitempty"` // spots repeated prompts; dropped on export
+	Complexity   float64  `json:"complexity"`
+	Keywords     []string `json:"keywords,omitempty"`
+	NeedsToolUse bool     `json:"needs_tool_use"`
+	Category     string   `json:"category"`
+
+	// What the router chose, and why
+	Mode            string   `json:"mode"`
+	Threshold       float64  `json:"threshold"`
+	RoutedTool      string   `json:"routed_tool,omitempty"`
+	Reason          string   `json:"reason,omitempty"`
+	FallbackReasons []string `json:"fallback_reasons,omitempty"`
+
+	// What actually ran, and how it went
+	Tool             string  `json:"tool"`
+	Tier             string  `json:"tier"`
+	Override         bool    `json:"override"` // tool picked with -t instead of the router
+	PatternsInjected int     `json:"patterns_injected,omitempty"`
+	InputTokens      int     `json:"input_tokens,omitempty"`
+	CostEstimate     float64 `json:"cost_estimate"`
+	DurationMs       int64   `json:"duration_ms"`
+	Success          bool    `json:"success"`
+}
+
+// NewDecision starts a decision record for prompt from the router's
+// selection. sel may be nil when routing was skipped or failed; the prompt
+// features are then analyzed here.
+func NewDecision(prompt string, sel *ToolSelection) Decision {
+	sum := sha256.Sum256([]byte(prompt))
+	d := Decision{
+		Timestamp:  time.Now(),
+		PromptHash: hex.EncodeToString(sum[:8]),
+	}
+	analysis := AnalyzePrompt(prompt)
+	if sel != nil {
+		analysis = sel.Analysis
+		d.Mode = sel.Mode
+		d.Threshold = sel.Threshold
+		d.RoutedTool = sel.Tool
+		d.Reason = sel.Reason
+		d.FallbackReasons = sel.FallbackReasons
+	}
+	d.PromptLength = analysis.Length
+	d.Complexity = analysis.Complexity
+	d.Keywords = analysis.Keywords
+	d.NeedsToolUse = analysis.NeedsToolUse
+	d.Category = analysis.Category
+	return d
+}
+
+// Anonymized returns the decision without anything that could tie it to
+// a prompt: the prompt hash is dropped and the time rounded to the hour.
+// Keywords come from a fixed vocabulary and are kept.
+func (d Decision) Anonymized() Decision {
+	d.PromptHash = ""
+	d.Timestamp = d.Timestamp.UTC().Truncate(time.Hour)
+	return d
+}
+
+// DecisionsPath returns the path of the routing decision log.
+func DecisionsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "routing-decisions.jsonl"), nil
+}
+
+// RecordDecision appends a decision to the decision log.
+func RecordDecision(d Decision) error {
+	path, err := DecisionsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create state directory: %w", err)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("cannot serialize decision: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open decision log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("cannot write decision: %w", err)
+	}
+	return nil
+}
+
+// LoadDecisions reads the decision log, oldest first, skipping decisions
+// before since (if set) and malformed lines.
+func LoadDecisions(since time.Time) ([]Decision, error) {
+	path, err := DecisionsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open decision log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var decisions []Decision
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var d Decision
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			continue
+		}
+		if !since.IsZero() && d.Timestamp.Before(since) {
+			continue
+		}
+		decisions = append(decisions, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading decision log: %w", err)
+	}
+	return decisions, nil
+}
+
+// DecisionSummary aggregates routing decisions.
+type DecisionSummary struct {
+	Total      int
+	Overrides  int // runs where the user picked the tool
+	Fallbacks  int // decisions that fell back from the preferred choice
+	Failures   int
+	TotalCost  float64
+	ByTier     map[string]TierSummary
+	Overridden map[string]int // "routed → chosen" for overrides
+}
+
+// TierSummary aggregates the decisions that ran a tool of one tier.
+type TierSummary struct {
+	Count         int
+	Successes     int
+	SumComplexity float64
+	MinComplexity float64
+	MaxComplexity float64
+	CostEstimate  float64
+}
+
+// AvgComplexity returns the mean complexity of the tier's prompts.
+func (t TierSummary) AvgComplexity() float64 {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.SumComplexity / float64(t.Count)
+}
+
+// SuccessRate returns the share of successful runs, 0-100.
+func (t TierSummary) SuccessRate() float64 {
+	if t.Count == 0 {
+		return 0
+	}
+	return float64(t.Successes) / float64(t.Count) * 100
+}
+
+// SummarizeDecisions aggregates decisions by tier.
+func SummarizeDecisions(decisions []Decision) DecisionSummary {
+	s := DecisionSummary{
+		ByTier:     make(map[string]TierSummary),
+		Overridden: make(map[string]int),
+	}
+	for _, d := range decisions {
+		s.Total++
+		s.TotalCost += d.CostEstimate
+		if !d.Success {
+			s.Failures++
+		}
+		if len(d.FallbackReasons) > 0 {
+			s.Fallbacks++
+		}
+		if d.Override {
+			s.Overrides++
+			if d.RoutedTool != "" && d.RoutedTool != d.Tool {
+				s.Overridden[d.RoutedTool+" → "+d.Tool]++
+			}
+		}
+
+		tier := d.Tier
+		if tier == "" {
+			tier = "unknown"
+		}
+		t := s.ByTier[tier]
+		if t.Count == 0 || d.Complexity < t.MinComplexity {
+			t.MinComplexity = d.Complexity
+		}
+		if t.Count == 0 || d.Complexity > t.MaxComplexity {
+			t.MaxComplexity = d.Complexity
+		}
+		t.Count++
+		if d.Success {
+			t.Successes++
+		}
+		t.SumComplexity += d.Complexity
+		t.CostEstimate += d.CostEstimate
+		s.ByTier[tier] = t
+	}
+	return s
+}
+
+// decisionColumns are the CSV columns written by WriteDecisions.
+var decisionColumns = []string{
+	"timestamp", "prompt_length", "complexity", "keywords", "needs_tool_use", "category",
+	"mode", "threshold", "routed_tool", "fallback_reasons",
+	"tool", "tier", "override", "patterns_injected", "input_tokens",
+	"cost_estimate", "duration_ms", "success",
+}
+
+// WriteDecisions writes decisions as JSON lines ("jsonl") or CSV ("csv").
+// Callers wanting an anonymized export pass Anonymized decisions.
+func WriteDecisions(w io.Writer, decisions []Decision, format string) error {
+	switch format {
+	case "", "jsonl":
+		enc := json.NewEncoder(w)
+		for _, d := range decisions {
+			if err := enc.Encode(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(decisionColumns); err != nil {
+			return err
+		}
+		for _, d := range decisions {
+			keywords := append([]string(nil), d.Keywords...)
+			sort.Strings(keywords)
+			row := []string{
+				d.Timestamp.UTC().Format(time.RFC3339),
+				strconv.Itoa(d.PromptLength),
+				strconv.FormatFloat(d.Complexity, 'f', 3, 64),
+				strings.Join(keywords, ";"),
+				strconv.FormatBool(d.NeedsToolUse),
+				d.Category,
+				d.Mode,
+				strconv.FormatFloat(d.Threshold, 'f', 2, 64),
+				d.RoutedTool,
+				strings.Join(d.FallbackReasons, ";"),
+				d.Tool,
+				d.Tier,
+				strconv.FormatBool(d.Override),
+				strconv.Itoa(d.PatternsInjected),
+				strconv.Itoa(d.InputTokens),
+				strconv.FormatFloat(d.CostEstimate, 'f', 6, 64),
+				strconv.FormatInt(d.DurationMs, 10),
+				strconv.FormatBool(d.Success),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q (use jsonl or csv)", format)
+	}
+}
@@ -0,0 +1,152 @@
+package router
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func TestSelectToolFallbackReasons(t *testing.T) {
+	cfg := &config.Config{
+		Routing: config.RoutingConfig{Mode: "auto"},
+		Tools: map[string]config.Tool{
+			"gemini": {Enabled: true, Binary: "gemini", Tier: "free"},
+		},
+	}
+
+	sel, err := SelectTool("refactor the entire architecture and optimize performance", cfg)
+	if err != nil {
+		t.Fatalf("SelectTool error: %v", err)
+	}
+	if sel.Tool != "gemini" {
+		t.Errorf("tool = %s, want gemini", sel.Tool)
+	}
+	if sel.Mode != "auto" || sel.Threshold != 0.5 {
+		t.Errorf("mode, threshold = %s, %.2f; want auto, 0.50", sel.Mode, sel.Threshold)
+	}
+	if len(sel.FallbackReasons) != 1 || sel.FallbackReasons[0] != "no paid tool enabled" {
+		t.Errorf("fallback reasons = %v", sel.FallbackReasons)
+	}
+
+	sel, err = SelectTool("what is git?", cfg)
+	if err != nil {
+		t.Fatalf("SelectTool error: %v", err)
+	}
+	if len(sel.FallbackReasons) != 0 {
+		t.Errorf("fallback reasons = %v, want none", sel.FallbackReasons)
+	}
+}
+
+func TestRecordAndLoadDecisions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+
+	prompt := "refactor the payment module"
+	old := NewDecision(prompt, nil)
+	old.Timestamp = time.Now().AddDate(0, 0, -10)
+	old.Tool, old.Tier = "gemini", "free"
+
+	d := NewDecision(prompt, &ToolSelection{Tool: "claude", Analysis: AnalyzePrompt(prompt), Mode: "auto", Threshold: 0.5})
+	d.Tool, d.Tier, d.Success = "claude", "paid", true
+
+	for _, rec := range []Decision{old, d} {
+		if err := RecordDecision(rec); err != nil {
+			t.Fatalf("RecordDecision: %v", err)
+		}
+	}
+
+	all, err := LoadDecisions(time.Time{})
+	if err != nil {
+		t.Fatalf("LoadDecisions: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("loaded %d decisions, want 2", len(all))
+	}
+	recent, err := LoadDecisions(time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("LoadDecisions: %v", err)
+	}
+	if len(recent) != 1 || recent[0].RoutedTool != "claude" {
+		t.Fatalf("recent = %+v", recent)
+	}
+	got := recent[0]
+	if got.PromptLength != len(prompt) || got.Category == "" || len(got.Keywords) == 0 || got.PromptHash == "" {
+		t.Errorf("features not recorded: %+v", got)
+	}
+}
+
+func TestDecisionAnonymized(t *testing.T) {
+	d := NewDecision("fix bug in /home/alice/secret-project", nil)
+	d.Timestamp = time.Date(2026, 3, 4, 15, 42, 7, 0, time.UTC)
+
+	a := d.Anonymized()
+	if a.PromptHash != "" {
+		t.Errorf("prompt hash kept: %q", a.PromptHash)
+	}
+	if !a.Timestamp.Equal(time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("timestamp = %v, want rounded to the hour", a.Timestamp)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDecisions(&buf, []Decision{a}, "jsonl"); err != nil {
+		t.Fatalf("WriteDecisions: %v", err)
+	}
+	if strings.Contains(buf.String(), "alice") || strings.Contains(buf.String(), "prompt_hash") {
+		t.Errorf("export leaks prompt details: %s", buf.String())
+	}
+}
+
+func TestSummarizeDecisions(t *testing.T) {
+	decisions := []Decision{
+		{Tool: "gemini", Tier: "free", Complexity: 0.1, Success: true},
+		{Tool: "gemini", Tier: "free", Complexity: 0.3, Success: false},
+		{Tool: "claude", Tier: "paid", Complexity: 0.2, Success: true, Override: true, RoutedTool: "gemini", CostEstimate: 0.01},
+		{Tool: "gemini", Tier: "free", Complexity: 0.7, Success: true, FallbackReasons: []string{"no paid tool enabled"}},
+	}
+	s := SummarizeDecisions(decisions)
+
+	if s.Total != 4 || s.Overrides != 1 || s.Fallbacks != 1 || s.Failures != 1 {
+		t.Errorf("summary = %+v", s)
+	}
+	if s.Overridden["gemini → claude"] != 1 {
+		t.Errorf("overridden = %v", s.Overridden)
+	}
+	free := s.ByTier["free"]
+	if free.Count != 3 || free.MinComplexity != 0.1 || free.MaxComplexity != 0.7 {
+		t.Errorf("free tier = %+v", free)
+	}
+	if rate := free.SuccessRate(); rate < 66 || rate > 67 {
+		t.Errorf("free success rate = %.1f", rate)
+	}
+}
+
+func TestWriteDecisionsCSV(t *testing.T) {
+	decisions := []Decision{{
+		Timestamp: time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC),
+		Keywords:  []string{"refactor", "module"},
+		Tool:      "claude",
+		Tier:      "paid",
+	}}
+	var buf bytes.Buffer
+	if err := WriteDecisions(&buf, decisions, "csv"); err != nil {
+		t.Fatalf("WriteDecisions: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(decisionColumns) {
+		t.Fatalf("rows = %v", rows)
+	}
+	if rows[1][3] != "module;refactor" {
+		t.Errorf("keywords = %q", rows[1][3])
+	}
+
+	if err := WriteDecisions(&buf, decisions, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,239 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// ProbeTTL is how long a probe result is trusted while the tool's binary
+// and flags stay the same.
+const ProbeTTL = 24 * time.Hour
+
+// probeTimeout bounds each command a probe runs.
+const probeTimeout = 5 * time.Second
+
+// Probe is what mur found out about a configured tool by running it.
+type Probe struct {
+	Tool      string    `json:"tool"`
+	Binary    string    `json:"binary"`
+	Flags     []string  `json:"flags,omitempty"`
+	Path      string    `json:"path,omitempty"`    // resolved binary; empty if not found
+	ModTime   time.Time `json:"mod_time,omitzero"` // of Path, to notice upgrades
+	Version   string    `json:"version,omitempty"`
+	OK        bool      `json:"ok"`
+	Problem   string    `json:"problem,omitempty"` // why routing skips the tool
+	Note      string    `json:"note,omitempty"`    // what couldn't be checked
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Age returns how long ago the tool was probed.
+func (p Probe) Age(now time.Time) time.Duration {
+	return now.Sub(p.CheckedAt)
+}
+
+// ProbesPath returns the path of the probe cache.
+func ProbesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(config.StateDir(home), "tool-probes.json"), nil
+}
+
+// ProbeTools probes every enabled tool, reusing cached results that are
+// younger than ProbeTTL for an unchanged binary and flags unless refresh
+// is set, and saves what it probed. Results are sorted by tool.
+func ProbeTools(ctx context.Context, cfg *config.Config, refresh bool) []Probe {
+	path, err := ProbesPath()
+	if err != nil {
+		return nil
+	}
+	return probeToolsAt(ctx, path, cfg, refresh, time.Now())
+}
+
+func probeToolsAt(ctx context.Context, path string, cfg *config.Config, refresh bool, now time.Time) []Probe {
+	cached := loadProbes(path)
+
+	names := GetAvailableTools(cfg)
+	sort.Strings(names)
+	probes := make([]Probe, len(names))
+	var wg sync.WaitGroup
+	changed := false
+	for i, name := range names {
+		tool := cfg.Tools[name]
+		if p, ok := cached[name]; ok && !refresh && p.fresh(tool, now) {
+			probes[i] = p
+			continue
+		}
+		changed = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probes[i] = probeTool(ctx, name, tool, now)
+		}()
+	}
+	wg.Wait()
+
+	if changed {
+		for _, p := range probes {
+			cached[p.Tool] = p
+		}
+		_ = saveProbes(path, cached)
+	}
+	return probes
+}
+
+// fresh reports whether p still describes tool: probed recently, with the
+// same binary at the same path, unmodified, and the same flags.
+func (p Probe) fresh(tool config.Tool, now time.Time) bool {
+	if p.Age(now) > ProbeTTL || p.Binary != tool.Binary || !slices.Equal(p.Flags, tool.Flags) {
+		return false
+	}
+	path, _ := exec.LookPath(tool.Binary)
+	if path != p.Path {
+		return false
+	}
+	if path == "" {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.ModTime().Equal(p.ModTime)
+}
+
+// probeTool checks that tool's binary is installed, reads its version,
+// and checks its help mentions each configured flag, so a tool that
+// dropped or renamed a flag isn't routed to.
+func probeTool(ctx context.Context, name string, tool config.Tool, now time.Time) Probe {
+	p := Probe{Tool: name, Binary: tool.Binary, Flags: tool.Flags, CheckedAt: now}
+	path, err := exec.LookPath(tool.Binary)
+	if err != nil {
+		p.Problem = tool.Binary + " not found in PATH"
+		return p
+	}
+	p.Path = path
+	if info, err := os.Stat(path); err == nil {
+		p.ModTime = info.ModTime()
+	}
+
+	if out, err := probeOutput(ctx, path, "--version"); err == nil {
+		p.Version = firstLine(out)
+		if len(p.Version) > 60 {
+			p.Version = p.Version[:57] + "..."
+		}
+	}
+
+	help, err := probeOutput(ctx, path, "--help")
+	if err != nil || strings.TrimSpace(help) == "" {
+		if len(tool.Flags) > 0 {
+			p.Note = "no --help output; flags not checked"
+		}
+		p.OK = true
+		return p
+	}
+	var missing []string
+	for _, flag := range tool.Flags {
+		if strings.HasPrefix(flag, "-") && !helpMentions(help, flag) {
+			missing = append(missing, flag)
+		}
+	}
+	if len(missing) > 0 {
+		p.Problem = fmt.Sprintf("%s --help doesn't list %s", tool.Binary, strings.Join(missing, ", "))
+		return p
+	}
+	p.OK = true
+	return p
+}
+
+// probeOutput runs path with arg and returns its output. Tools print help
+// on stdout or stderr, and some exit non-zero after printing it, so both
+// streams count when anything was printed.
+func probeOutput(ctx context.Context, path, arg string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, arg)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil && len(strings.TrimSpace(string(out))) == 0 {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// helpMentions reports whether help lists flag as an option, e.g. "-p,"
+// or "[--print]", and not just as part of a longer flag.
+func helpMentions(help, flag string) bool {
+	name, _, _ := strings.Cut(flag, "=")
+	re := regexp.MustCompile(`(^|[\s,\[|(])` + regexp.QuoteMeta(name) + `($|[\s,=\]|)<])`)
+	return re.MatchString(help)
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// FailingTools returns the tools routing should skip and why.
+func FailingTools(probes []Probe) map[string]string {
+	failing := map[string]string{}
+	for _, p := range probes {
+		if !p.OK {
+			failing[p.Tool] = p.Problem
+		}
+	}
+	return failing
+}
+
+func loadProbes(path string) map[string]Probe {
+	probes := map[string]Probe{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return probes
+	}
+	var list []Probe
+	if json.Unmarshal(data, &list) != nil {
+		return probes
+	}
+	for _, p := range list {
+		probes[p.Tool] = p
+	}
+	return probes
+}
+
+func saveProbes(path string, probes map[string]Probe) error {
+	list := make([]Probe, 0, len(probes))
+	for _, p := range probes {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Tool < list[j].Tool })
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write atomically; concurrent runs may probe at the same time
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("cannot write probe cache: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
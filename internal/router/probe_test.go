@@ -0,0 +1,93 @@
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// fakeTool writes a script that prints version and help like a CLI tool.
+func fakeTool(t *testing.T, dir, name, help string) {
+	t.Helper()
+	script := "#!/bin/sh\ncase \"$1\" in\n--version) echo \"" + name + " 1.2.3\" ;;\n--help) cat <<'EOF'\n" + help + "\nEOF\n;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProbeTools(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+"/usr/bin:/bin") // the scripts need cat
+	fakeTool(t, bin, "good", "Usage: good [options]\n  -p, --print  Print the response")
+	fakeTool(t, bin, "changed", "Usage: changed [--prompt-file]\n  --model")
+
+	cfg := &config.Config{Tools: map[string]config.Tool{
+		"good":    {Enabled: true, Binary: "good", Flags: []string{"-p"}},
+		"changed": {Enabled: true, Binary: "changed", Flags: []string{"-p"}},
+		"missing": {Enabled: true, Binary: "missing"},
+		"off":     {Enabled: false, Binary: "off"},
+	}}
+	cache := filepath.Join(t.TempDir(), "tool-probes.json")
+	now := time.Now()
+
+	probes := probeToolsAt(context.Background(), cache, cfg, false, now)
+	if len(probes) != 3 {
+		t.Fatalf("probes = %+v, want 3 enabled tools", probes)
+	}
+	byTool := map[string]Probe{}
+	for _, p := range probes {
+		byTool[p.Tool] = p
+	}
+	if p := byTool["good"]; !p.OK || p.Version != "good 1.2.3" {
+		t.Errorf("good = %+v", p)
+	}
+	if p := byTool["changed"]; p.OK || p.Problem == "" {
+		t.Errorf("changed should fail on its missing -p flag: %+v", p)
+	}
+	if p := byTool["missing"]; p.OK {
+		t.Errorf("missing should fail: %+v", p)
+	}
+
+	failing := FailingTools(probes)
+	if len(failing) != 2 {
+		t.Errorf("failing = %v", failing)
+	}
+	sel, err := SelectToolExcluding("what is git?", cfg, failing)
+	if err != nil || sel.Tool != "good" {
+		t.Errorf("selected %+v, %v; want good", sel, err)
+	}
+
+	// Cached while the binary is unchanged, probed again once it changes
+	later := now.Add(time.Hour)
+	if p := probeToolsAt(context.Background(), cache, cfg, false, later)[1]; !p.CheckedAt.Equal(now) {
+		t.Errorf("good re-probed despite the cache: %v", p.CheckedAt)
+	}
+	fakeTool(t, bin, "changed", "Usage: changed [options]\n  -p  Prompt")
+	mtime := now.Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(bin, "changed"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if p := probeToolsAt(context.Background(), cache, cfg, false, later)[0]; !p.OK {
+		t.Errorf("upgraded tool not re-probed: %+v", p)
+	}
+}
+
+func TestHelpMentions(t *testing.T) {
+	help := "Usage: tool [-i] [--print]\n  -p, --prompt <text>\n  --model=NAME\n  --print-json"
+	for flag, want := range map[string]bool{
+		"-p": true, "-i": true, "--print": true, "--model": true, "--prompt=x": true,
+		"--print-j": false, "-x": false, "--json": false,
+	} {
+		if got := helpMentions(help, flag); got != want {
+			t.Errorf("helpMentions(%q) = %v, want %v", flag, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mur-run/mur-core/internal/config"
 )
@@ -12,10 +13,25 @@ type ToolSelection struct {
 	Reason   string         // Human-readable explanation
 	Analysis PromptAnalysis // The prompt analysis that led to this decision
 	Fallback string         // Alternative tool if selected unavailable
+	// Mode and Threshold are the routing settings the decision was made with.
+	Mode      string
+	Threshold float64
+	// FallbackReasons explains each time the preferred choice wasn't
+	// available and a later one was used.
+	FallbackReasons []string
+	// Excluded are the enabled tools that were left out, and why.
+	Excluded map[string]string
 }
 
 // SelectTool chooses the best tool for the given prompt based on config.
 func SelectTool(prompt string, cfg *config.Config) (*ToolSelection, error) {
+	return SelectToolExcluding(prompt, cfg, nil)
+}
+
+// SelectToolExcluding is SelectTool without the tools in excluded (tool
+// name to reason), such as those whose probes failed. The default tool
+// of manual mode is excluded too, falling back to another tool.
+func SelectToolExcluding(prompt string, cfg *config.Config, excluded map[string]string) (*ToolSelection, error) {
 	analysis := AnalyzePrompt(prompt)
 
 	mode := cfg.Routing.Mode
@@ -29,49 +45,70 @@ func SelectTool(prompt string, cfg *config.Config) (*ToolSelection, error) {
 	}
 
 	// Get available tools
-	available := GetAvailableTools(cfg)
+	var available []string
+	var fallbacks []string
+	for _, name := range GetAvailableTools(cfg) {
+		if why, ok := excluded[name]; ok {
+			fallbacks = append(fallbacks, fmt.Sprintf("%s excluded: %s", name, why))
+			continue
+		}
+		available = append(available, name)
+	}
 	if len(available) == 0 {
+		if len(fallbacks) > 0 {
+			return nil, fmt.Errorf("no enabled tools available (%s)", strings.Join(fallbacks, "; "))
+		}
 		return nil, fmt.Errorf("no enabled tools available")
 	}
 
 	var selected string
 	var reason string
+	byTier := func(tier string) string {
+		name := selectByTier(available, cfg, tier)
+		if name == "" {
+			fallbacks = append(fallbacks, fmt.Sprintf("no %s tool enabled", tier))
+		}
+		return name
+	}
 
 	switch mode {
 	case "manual":
 		// Use default_tool always
 		selected = cfg.GetDefaultTool()
 		reason = "manual mode: using default tool"
+		if _, ok := excluded[selected]; ok {
+			selected = ""
+		}
 
 	case "cost-first":
 		// Prefer free tools unless complexity is very high (>0.8)
 		if analysis.Complexity > 0.8 || analysis.NeedsToolUse {
-			selected = selectByTier(available, cfg, "paid")
+			selected = byTier("paid")
 			if selected != "" {
 				reason = fmt.Sprintf("cost-first: complexity %.2f > 0.8 or needs tool use, using paid tool", analysis.Complexity)
 			}
 		}
 		if selected == "" {
-			selected = selectByTier(available, cfg, "free")
+			selected = byTier("free")
 			reason = fmt.Sprintf("cost-first: complexity %.2f, using free tool", analysis.Complexity)
 		}
 
 	case "quality-first":
 		// Prefer paid/powerful tools unless very simple (<0.2)
 		if analysis.Complexity < 0.2 && !analysis.NeedsToolUse {
-			selected = selectByTier(available, cfg, "free")
+			selected = byTier("free")
 			if selected != "" {
 				reason = fmt.Sprintf("quality-first: complexity %.2f < 0.2 and no tool use needed, using free tool", analysis.Complexity)
 			}
 		}
 		if selected == "" {
-			selected = selectByTier(available, cfg, "paid")
+			selected = byTier("paid")
 			reason = fmt.Sprintf("quality-first: complexity %.2f, using paid tool", analysis.Complexity)
 		}
 
 	default: // "auto"
 		if analysis.Complexity >= threshold || analysis.NeedsToolUse {
-			selected = selectByTier(available, cfg, "paid")
+			selected = byTier("paid")
 			if selected != "" {
 				if analysis.NeedsToolUse {
 					reason = "auto: needs tool use, using paid tool"
@@ -81,7 +118,7 @@ func SelectTool(prompt string, cfg *config.Config) (*ToolSelection, error) {
 			}
 		}
 		if selected == "" {
-			selected = selectByTier(available, cfg, "free")
+			selected = byTier("free")
 			if selected != "" {
 				reason = fmt.Sprintf("auto: complexity %.2f < %.2f threshold, using free tool", analysis.Complexity, threshold)
 			}
@@ -91,7 +128,12 @@ func SelectTool(prompt string, cfg *config.Config) (*ToolSelection, error) {
 	// Final fallback to any available tool
 	if selected == "" && len(available) > 0 {
 		selected = available[0]
-		reason = fmt.Sprintf("%s (fallback to first available)", reason)
+		if reason == "" {
+			reason = "fallback to first available"
+		} else {
+			reason = fmt.Sprintf("%s (fallback to first available)", reason)
+		}
+		fallbacks = append(fallbacks, "fell back to first available tool")
 	}
 
 	if selected == "" {
@@ -103,6 +145,11 @@ func SelectTool(prompt string, cfg *config.Config) (*ToolSelection, error) {
 		Reason:   reason,
 		Analysis: analysis,
 		Fallback: findFallback(selected, available),
+
+		Mode:            mode,
+		Threshold:       threshold,
+		FallbackReasons: fallbacks,
+		Excluded:        excluded,
 	}, nil
 }
 
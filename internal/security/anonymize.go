@@ -7,12 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	murlog "github.com/mur-run/mur-core/internal/log"
 )
 
 // AnonymizationChange represents a single change made by LLM anonymization.
@@ -119,8 +120,8 @@ func parseLLMResponse(response, originalContent string) (string, []Anonymization
 	if originalContent != "" && cleaned != "" {
 		ratio := levenshteinRatio(originalContent, cleaned)
 		if ratio > maxDivergenceRatio {
-			log.Printf("[anonymize] WARNING: LLM response diverges %.0f%% from original (threshold %.0f%%), returning original",
-				ratio*100, maxDivergenceRatio*100)
+			murlog.For("anonymize").Warn("LLM response diverges from original, keeping original",
+				"divergence", fmt.Sprintf("%.0f%%", ratio*100), "threshold", fmt.Sprintf("%.0f%%", maxDivergenceRatio*100))
 			return originalContent, nil
 		}
 	}
@@ -0,0 +1,65 @@
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Apply atomically replaces the running executable with bin.
+// The new binary is written next to the old one first, so the final
+// swap is a same-filesystem rename.
+func Apply(bin []byte) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("cannot find mur binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(exe); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".mur-upgrade-*")
+	if err != nil {
+		return "", fmt.Errorf("cannot write to %s (try running with elevated permissions): %w", filepath.Dir(exe), err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("cannot write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("cannot write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("cannot set permissions: %w", err)
+	}
+
+	// Platform-specific swap is in replace_unix.go / replace_windows.go
+	if err := swap(exe, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return exe, nil
+}
+
+// CleanupOld removes a binary left behind by a previous upgrade.
+// Only Windows leaves one, since a running executable cannot be deleted there.
+func CleanupOld() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	_ = os.Remove(exe + ".old")
+}
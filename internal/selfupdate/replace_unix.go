@@ -0,0 +1,17 @@
+//go:build !windows
+
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+)
+
+// swap renames newPath over exe. rename(2) is atomic, and the running
+// process keeps its handle to the old inode.
+func swap(exe, newPath string) error {
+	if err := os.Rename(newPath, exe); err != nil {
+		return fmt.Errorf("cannot replace binary: %w", err)
+	}
+	return nil
+}
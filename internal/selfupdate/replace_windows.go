@@ -0,0 +1,26 @@
+//go:build windows
+
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+)
+
+// swap moves the running exe aside before moving newPath into place,
+// since Windows refuses to overwrite an executable that is in use.
+// The old binary is removed by CleanupOld on the next upgrade.
+func swap(exe, newPath string) error {
+	old := exe + ".old"
+	_ = os.Remove(old)
+
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("cannot move current binary aside: %w", err)
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// Roll back so the user still has a working mur
+		_ = os.Rename(old, exe)
+		return fmt.Errorf("cannot replace binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+// Package selfupdate downloads, verifies, and installs mur release binaries.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultReleasesURL is the GitHub API endpoint listing mur releases.
+	DefaultReleasesURL = "https://api.github.com/repos/mur-run/mur-core/releases"
+
+	// ChannelStable only considers full releases.
+	ChannelStable = "stable"
+	// ChannelBeta also considers pre-releases.
+	ChannelBeta = "beta"
+
+	checksumsAsset = "checksums.txt"
+	signatureAsset = "checksums.txt.sig"
+)
+
+// Release is a GitHub release.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Name       string  `json:"name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	HTMLURL    string  `json:"html_url"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Asset returns the asset with the given name.
+func (r *Release) Asset(name string) (*Asset, bool) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// Updater checks for and installs new releases.
+type Updater struct {
+	ReleasesURL string
+	Channel     string
+	// PublicKey is a base64 ed25519 key. When set, checksums.txt must carry
+	// a valid detached signature (checksums.txt.sig).
+	PublicKey  string
+	httpClient *http.Client
+}
+
+// New creates an Updater for the given channel.
+func New(channel, publicKey string) *Updater {
+	if channel == "" {
+		channel = ChannelStable
+	}
+	return &Updater{
+		ReleasesURL: DefaultReleasesURL,
+		Channel:     channel,
+		PublicKey:   publicKey,
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// Latest returns the newest release on the updater's channel.
+func (u *Updater) Latest() (*Release, error) {
+	var releases []Release
+	if err := u.getJSON(u.ReleasesURL, &releases); err != nil {
+		return nil, err
+	}
+
+	var best *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if r.Prerelease && u.Channel != ChannelBeta {
+			continue
+		}
+		if best == nil || CompareVersions(r.Version(), best.Version()) > 0 {
+			best = r
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no releases found on %s channel", u.Channel)
+	}
+	return best, nil
+}
+
+// ByTag returns a specific release.
+func (u *Updater) ByTag(tag string) (*Release, error) {
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	var r Release
+	if err := u.getJSON(u.ReleasesURL+"/tags/"+tag, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// AssetName returns the archive name for the current platform, matching
+// the goreleaser name_template.
+func AssetName() string {
+	ext := "tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("mur-%s-%s.%s", runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// Download fetches the platform archive from a release, verifies it against
+// the release checksums (and signature, if a public key is configured), and
+// returns the extracted mur binary.
+func (u *Updater) Download(r *Release) ([]byte, error) {
+	name := AssetName()
+	archive, ok := r.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no asset for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := r.Asset(checksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install unverified binary", r.TagName, checksumsAsset)
+	}
+
+	sumsData, err := u.get(sums.BrowserDownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot download checksums: %w", err)
+	}
+
+	if u.PublicKey != "" {
+		sig, ok := r.Asset(signatureAsset)
+		if !ok {
+			return nil, fmt.Errorf("release %s is not signed", r.TagName)
+		}
+		sigData, err := u.get(sig.BrowserDownloadURL)
+		if err != nil {
+			return nil, fmt.Errorf("cannot download signature: %w", err)
+		}
+		if err := VerifySignature(u.PublicKey, sumsData, sigData); err != nil {
+			return nil, err
+		}
+	}
+
+	expected, err := ParseChecksums(sumsData, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := u.get(archive.BrowserDownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot download %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+
+	return extractBinary(name, data)
+}
+
+// ParseChecksums finds the SHA256 for name in a goreleaser checksums.txt.
+func ParseChecksums(data []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// VerifySignature checks a base64 ed25519 signature over data.
+func VerifySignature(publicKey string, data, sig []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key")
+	}
+	rawSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, rawSig) {
+		return fmt.Errorf("signature verification failed for %s", checksumsAsset)
+	}
+	return nil
+}
+
+// CompareVersions compares two semantic versions (without "v" prefix).
+// Returns -1, 0, or 1. A pre-release sorts before its release.
+func CompareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < 3; i++ {
+		var an, bn int
+		if i < len(aParts) {
+			an, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bn, _ = strconv.Atoi(bParts[i])
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// extractBinary pulls the mur executable out of a release archive.
+func extractBinary(archiveName string, data []byte) ([]byte, error) {
+	binName := "mur"
+	if runtime.GOOS == "windows" {
+		binName = "mur.exe"
+	}
+
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot open archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binName {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in archive", binName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binName {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", binName)
+}
+
+func (u *Updater) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "mur-upgrade")
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (u *Updater) getJSON(url string, v interface{}) error {
+	data, err := u.get(url)
+	if err != nil {
+		return fmt.Errorf("cannot query releases: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("cannot parse releases: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.14.12", "1.14.12", 0},
+		{"v1.15.0", "1.14.12", 1},
+		{"1.9.0", "1.10.0", -1},
+		{"2.0.0-beta.1", "2.0.0", -1},
+		{"2.0.0-beta.2", "2.0.0-beta.1", 1},
+		{"2.0.0-beta.1", "1.99.0", 1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	data := []byte(`0a1b2c  mur-darwin-arm64.tar.gz
+DEADBEEF  mur-linux-amd64.tar.gz
+`)
+
+	sum, err := ParseChecksums(data, "mur-linux-amd64.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != "deadbeef" {
+		t.Errorf("expected lowercase checksum, got %q", sum)
+	}
+
+	if _, err := ParseChecksums(data, "mur-windows-amd64.zip"); err == nil {
+		t.Error("expected error for missing asset")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(pub)
+	data := []byte("abc  mur-linux-amd64.tar.gz\n")
+	sig := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)))
+
+	if err := VerifySignature(key, data, sig); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := VerifySignature(key, []byte("tampered"), sig); err == nil {
+		t.Error("tampered data accepted")
+	}
+}
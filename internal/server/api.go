@@ -0,0 +1,325 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/export"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+// maxAPIBody caps request bodies of the write endpoints.
+const maxAPIBody = 1 << 20
+
+// NewAPI returns the versioned REST API that 'mur serve' mounts under
+// /api/v1/, for editor plugins and other tools that manage patterns and
+// workflows without shelling out to the CLI:
+//
+//	GET    /api/v1/patterns[?where=&sort=&limit=]
+//	POST   /api/v1/patterns
+//	GET    /api/v1/patterns/{name}
+//	PUT    /api/v1/patterns/{name}
+//	DELETE /api/v1/patterns/{name}[?purge=true]
+//	POST   /api/v1/bulk, POST /api/v1/bulk/undo
+//	GET    /api/v1/workflows, POST /api/v1/workflows
+//	GET    /api/v1/workflows/{id}, PUT, DELETE, .../publish, .../export
+//	GET    /api/v1/stats
+//
+// Patterns are returned as export.Record, the same fields as the NDJSON
+// export. tracker may be nil.
+func NewAPI(store *pattern.Store, tracker *inject.Tracker) http.Handler {
+	s := &Server{mux: http.NewServeMux(), store: store, tracker: tracker}
+	s.mux.HandleFunc("/api/v1/patterns", s.handleAPIPatterns)
+	s.mux.HandleFunc("/api/v1/patterns/", s.handleAPIPattern)
+	s.mux.HandleFunc("/api/v1/bulk", s.handleAPIBulk)
+	s.mux.HandleFunc("/api/v1/bulk/undo", s.handleAPIBulkUndo)
+	s.mux.HandleFunc("/api/v1/workflows", s.handleWorkflows)
+	s.mux.HandleFunc("/api/v1/workflows/", s.handleWorkflowByID)
+	s.mux.HandleFunc("/api/v1/stats", s.handleAPIStats)
+	return GuardWrites(s.mux)
+}
+
+// GuardWrites keeps web pages from changing patterns through the API
+// while the dashboard runs: writes must come from the same origin, if a
+// browser sent them, and POST and PUT bodies must be JSON, which a
+// cross-origin page can't send without a CORS preflight the API never
+// grants.
+func GuardWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+			writeJSON(w, http.StatusForbidden, APIResponse{Error: "cross-origin writes are not allowed"})
+			return
+		}
+		if r.Method == http.MethodPost || r.Method == http.MethodPut {
+			if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+				writeJSON(w, http.StatusUnsupportedMediaType, APIResponse{Error: "Content-Type must be application/json"})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxAPIBody)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PatternInput is the body of POST and PUT /api/v1/patterns requests.
+// Fields left out of a PUT keep their current values.
+type PatternInput struct {
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Content     *string   `json:"content"`
+	Tags        *[]string `json:"tags"`
+	Pinned      *bool     `json:"pinned"`
+	Status      *string   `json:"status"` // active, deprecated, or archived
+}
+
+// apply sets the fields given in the input on p.
+func (in PatternInput) apply(p *pattern.Pattern) error {
+	if in.Description != nil {
+		p.Description = *in.Description
+	}
+	if in.Content != nil {
+		p.Content = *in.Content
+	}
+	if in.Tags != nil {
+		p.Tags.Confirmed = *in.Tags
+	}
+	if in.Pinned != nil {
+		p.Pinned = *in.Pinned
+	}
+	if in.Status != nil {
+		switch status := pattern.LifecycleStatus(*in.Status); status {
+		case pattern.StatusActive, pattern.StatusDeprecated, pattern.StatusArchived:
+			p.Lifecycle.Status = status
+		default:
+			return fmt.Errorf("invalid status %q (use active, deprecated, or archived)", *in.Status)
+		}
+	}
+	if strings.TrimSpace(p.Content) == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+// decodeInput reads a PatternInput body.
+func decodeInput(r *http.Request) (PatternInput, error) {
+	var in PatternInput
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		return in, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return in, nil
+}
+
+// usage returns the tracker's usage stats, if there is a tracker.
+func (s *Server) usage() []inject.EffectivenessStats {
+	if s.tracker == nil {
+		return nil
+	}
+	stats, _ := s.tracker.GetStats()
+	return stats
+}
+
+// record returns p as the API represents it, with its usage stats.
+func (s *Server) record(p *pattern.Pattern) export.Record {
+	return export.Records([]pattern.Pattern{*p}, s.usage())[0]
+}
+
+// handleAPIPatterns serves /api/v1/patterns.
+func (s *Server) handleAPIPatterns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleAPIPatternsList(w, r)
+	case http.MethodPost:
+		s.handleAPIPatternsCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+	}
+}
+
+// GET /api/v1/patterns: where, sort, and limit take the same syntax as
+// 'mur learn list'.
+func (s *Server) handleAPIPatternsList(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit := 0
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, APIResponse{Error: fmt.Sprintf("invalid limit %q", v)})
+			return
+		}
+		limit = n
+	}
+	q, err := pattern.ParseQuery(params.Get("where"), params.Get("sort"), limit)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	patterns, err := s.store.Query(q, time.Now())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		return
+	}
+
+	// Records sorts by ID; keep the query's order.
+	byID := make(map[string]export.Record, len(patterns))
+	for _, rec := range export.Records(patterns, s.usage()) {
+		byID[rec.ID] = rec
+	}
+	records := make([]export.Record, 0, len(patterns))
+	for _, p := range patterns {
+		records = append(records, byID[p.ID])
+	}
+	writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: records})
+}
+
+// POST /api/v1/patterns
+func (s *Server) handleAPIPatternsCreate(w http.ResponseWriter, r *http.Request) {
+	in, err := decodeInput(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	if in.Name == "" {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: "name is required"})
+		return
+	}
+	if s.store.Exists(in.Name) {
+		writeJSON(w, http.StatusConflict, APIResponse{Error: "pattern already exists: " + in.Name})
+		return
+	}
+
+	p := &pattern.Pattern{Name: in.Name}
+	if err := in.apply(p); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	if err := s.store.Create(p); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	w.Header().Set("Location", "/api/v1/patterns/"+p.Name)
+	writeJSON(w, http.StatusCreated, APIResponse{Success: true, Data: s.record(p)})
+}
+
+// handleAPIPattern serves /api/v1/patterns/{name}; the pattern's ID works
+// in place of its name.
+func (s *Server) handleAPIPattern(w http.ResponseWriter, r *http.Request) {
+	ref := strings.TrimPrefix(r.URL.Path, "/api/v1/patterns/")
+	if ref == "" || strings.Contains(ref, "/") {
+		writeJSON(w, http.StatusNotFound, APIResponse{Error: "not found"})
+		return
+	}
+	p, err := s.store.Resolve(ref)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, APIResponse{Error: "pattern not found: " + ref})
+		return
+	}
+	name := p.Name
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: s.record(p)})
+
+	case http.MethodPut:
+		in, err := decodeInput(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if in.Name != "" && in.Name != name {
+			writeJSON(w, http.StatusBadRequest, APIResponse{Error: "name can't be changed here; use 'mur learn rename'"})
+			return
+		}
+		if err := in.apply(p); err != nil {
+			writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if err := s.store.Update(p); err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: s.record(p)})
+
+	case http.MethodDelete:
+		// Like 'mur learn delete': trashed unless purged.
+		purge := r.URL.Query().Get("purge") == "true"
+		remove := s.store.Delete
+		if purge {
+			remove = s.store.Purge
+		}
+		if err := remove(name); err != nil {
+			writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: map[string]interface{}{
+			"deleted": name,
+			"purged":  purge,
+		}})
+
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+	}
+}
+
+// APIStats is the body of GET /api/v1/stats.
+type APIStats struct {
+	Patterns PatternCounts `json:"patterns"`
+	Usage    stats.Summary `json:"usage"`
+}
+
+// PatternCounts counts patterns by lifecycle status.
+type PatternCounts struct {
+	Total      int `json:"total"`
+	Active     int `json:"active"`
+	Deprecated int `json:"deprecated"`
+	Archived   int `json:"archived"`
+	Pinned     int `json:"pinned"`
+}
+
+// GET /api/v1/stats
+func (s *Server) handleAPIStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+		return
+	}
+	patterns, err := s.store.List()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		return
+	}
+	records, err := stats.Query(stats.QueryFilter{})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		return
+	}
+
+	out := APIStats{Usage: stats.Summarize(records)}
+	for _, p := range patterns {
+		out.Patterns.Total++
+		switch p.Lifecycle.Status {
+		case pattern.StatusDeprecated:
+			out.Patterns.Deprecated++
+		case pattern.StatusArchived:
+			out.Patterns.Archived++
+		default:
+			out.Patterns.Active++
+		}
+		if p.Pinned {
+			out.Patterns.Pinned++
+		}
+	}
+	writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: out})
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// call sends a request to the API and decodes its response.
+func call(t *testing.T, api http.Handler, method, path, body string) (int, APIResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	var resp APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: %v: %s", method, path, err, rec.Body)
+	}
+	return rec.Code, resp
+}
+
+func TestAPIPatternsCRUD(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	store := pattern.NewStore(t.TempDir())
+	api := NewAPI(store, nil)
+
+	code, _ := call(t, api, "POST", "/api/v1/patterns", `{"name": "go-errors", "content": "Wrap errors with %w", "tags": ["go"]}`)
+	if code != http.StatusCreated {
+		t.Fatalf("create = %d", code)
+	}
+	if code, _ := call(t, api, "POST", "/api/v1/patterns", `{"name": "go-errors", "content": "again"}`); code != http.StatusConflict {
+		t.Errorf("duplicate create = %d", code)
+	}
+	if code, _ := call(t, api, "POST", "/api/v1/patterns", `{"name": "empty"}`); code != http.StatusBadRequest {
+		t.Errorf("create without content = %d", code)
+	}
+
+	// PUT changes only the fields sent
+	code, resp := call(t, api, "PUT", "/api/v1/patterns/go-errors", `{"description": "Error wrapping", "pinned": true}`)
+	if code != http.StatusOK {
+		t.Fatalf("update = %d: %s", code, resp.Error)
+	}
+	p, err := store.Get("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Content != "Wrap errors with %w" || p.Description != "Error wrapping" || !p.Pinned || len(p.Tags.Confirmed) != 1 {
+		t.Errorf("updated pattern = %+v", p)
+	}
+	if code, _ := call(t, api, "PUT", "/api/v1/patterns/go-errors", `{"status": "gone"}`); code != http.StatusBadRequest {
+		t.Errorf("invalid status = %d", code)
+	}
+
+	code, resp = call(t, api, "GET", "/api/v1/patterns?where=tag=go", "")
+	list, _ := resp.Data.([]interface{})
+	if code != http.StatusOK || len(list) != 1 || list[0].(map[string]interface{})["name"] != "go-errors" {
+		t.Errorf("list = %d %+v", code, resp.Data)
+	}
+
+	if code, _ := call(t, api, "DELETE", "/api/v1/patterns/go-errors", ""); code != http.StatusOK {
+		t.Errorf("delete = %d", code)
+	}
+	if !store.InTrash("go-errors") {
+		t.Error("deleted pattern not in trash")
+	}
+	if code, _ := call(t, api, "GET", "/api/v1/patterns/go-errors", ""); code != http.StatusNotFound {
+		t.Errorf("get deleted = %d", code)
+	}
+}
+
+func TestAPIGuardsWrites(t *testing.T) {
+	api := NewAPI(pattern.NewStore(t.TempDir()), nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/patterns", strings.NewReader(`{"name": "x", "content": "y"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain write = %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/patterns/x", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	rec = httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("cross-origin write = %d", rec.Code)
+	}
+}
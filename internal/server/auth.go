@@ -0,0 +1,114 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AuthCookie is the cookie holding the session token in the browser.
+const AuthCookie = "mur_token"
+
+// NewToken returns a random session token for RequireToken.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RequireToken rejects requests without the session token, except the
+// /healthz and /readyz probes. Clients send it as "Authorization: Bearer
+// <token>"; browsers open the dashboard once with ?token=<token>, which
+// sets a same-site cookie and redirects to the URL without it, so the
+// token doesn't stay in the address bar or history.
+func RequireToken(token string, next http.Handler) http.Handler {
+	valid := func(got string) bool {
+		return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if q := r.URL.Query(); q.Has("token") {
+			if !valid(q.Get("token")) {
+				unauthorized(w, r)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     AuthCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			q.Del("token")
+			u := *r.URL
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.RequestURI(), http.StatusSeeOther)
+			return
+		}
+
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && valid(bearer) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if c, err := r.Cookie(AuthCookie); err == nil && valid(c.Value) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		unauthorized(w, r)
+	})
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="mur"`)
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		writeJSON(w, http.StatusUnauthorized, APIResponse{Error: "missing or invalid token (see the URL printed by 'mur serve --auth')"})
+		return
+	}
+	http.Error(w, "Unauthorized: open the dashboard with the URL printed by 'mur serve --auth'", http.StatusUnauthorized)
+}
+
+// IsLoopback reports whether host (a bind address, without port) only
+// accepts connections from this machine.
+func IsLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// RequireLocalHost rejects requests whose Host header isn't 127.0.0.1,
+// localhost or [::1] with port, whatever their method. A server on
+// loopback is only reachable from this machine, but a web page can point
+// a name it controls at 127.0.0.1 (DNS rebinding) and then send same-origin
+// requests with its own name as the Host; this keeps those out.
+func RequireLocalHost(port int, next http.Handler) http.Handler {
+	allowed := make(map[string]bool)
+	for _, host := range []string{"127.0.0.1", "localhost", "[::1]"} {
+		allowed[host+":"+strconv.Itoa(port)] = true
+		if port == 80 {
+			allowed[host] = true
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed[strings.ToLower(r.Host)] {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSON(w, http.StatusMisdirectedRequest, APIResponse{Error: "unknown host " + strconv.Quote(r.Host)})
+				return
+			}
+			http.Error(w, "Unknown host: open the dashboard at http://127.0.0.1:"+strconv.Itoa(port), http.StatusMisdirectedRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	token, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := RequireToken(token, ok)
+
+	do := func(path string, setup func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if setup != nil {
+			setup(req)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tt := range []struct {
+		name  string
+		path  string
+		setup func(*http.Request)
+		want  int
+	}{
+		{"no token", "/api/v1/patterns", nil, http.StatusUnauthorized},
+		{"health", "/healthz", nil, http.StatusOK},
+		{"bearer", "/api/v1/patterns", func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }, http.StatusOK},
+		{"wrong bearer", "/api/sync", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+		{"cookie", "/", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: AuthCookie, Value: token}) }, http.StatusOK},
+		{"wrong query", "/?token=nope", nil, http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(tt.path, tt.setup); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// ?token= sets the cookie and redirects without the token
+	rec := do("/graph?token="+token+"&max=10", nil)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want redirect", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/graph?max=10" {
+		t.Errorf("Location = %q", loc)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != token || !cookies[0].HttpOnly {
+		t.Errorf("cookies = %v", cookies)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for host, want := range map[string]bool{
+		"127.0.0.1": true,
+		"::1":       true,
+		"localhost": true,
+		"0.0.0.0":   false,
+		"":          false,
+		"192.0.2.7": false,
+	} {
+		if got := IsLoopback(host); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestRequireLocalHost(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := RequireLocalHost(8742, ok)
+	for _, tt := range []struct {
+		method, host, path string
+		want               int
+	}{
+		{"GET", "127.0.0.1:8742", "/api/v1/patterns", http.StatusOK},
+		{"GET", "localhost:8742", "/", http.StatusOK},
+		{"POST", "[::1]:8742", "/api/sync", http.StatusOK},
+		{"GET", "LOCALHOST:8742", "/", http.StatusOK},
+		{"GET", "rebind.attacker.example:8742", "/api/v1/patterns", http.StatusMisdirectedRequest},
+		{"POST", "rebind.attacker.example:8742", "/api/pattern/x", http.StatusMisdirectedRequest},
+		{"GET", "127.0.0.1:9999", "/healthz", http.StatusMisdirectedRequest},
+		{"GET", "localhost", "/", http.StatusMisdirectedRequest},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s with Host %s: status = %d, want %d", tt.method, tt.path, tt.host, rec.Code, tt.want)
+		}
+	}
+}
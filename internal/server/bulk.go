@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// BulkInput is the body of POST /api/v1/bulk: an action and the names of
+// the patterns to apply it to.
+//
+//	archive      set status to archived
+//	tag, untag   add or remove Tag
+//	pin, unpin   always inject them, or stop
+//	share        mark them shared, so team sync pushes them; trust is unchanged
+type BulkInput struct {
+	Patterns []string `json:"patterns"`
+	Action   string   `json:"action"`
+	Tag      string   `json:"tag,omitempty"`
+}
+
+// BulkOutput is the result of a bulk action. Snapshot is what POST
+// /api/v1/bulk/undo takes to revert it; it is empty when nothing changed.
+type BulkOutput struct {
+	Matched  int    `json:"matched"`
+	Changed  int    `json:"changed"`
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// bulkOptions returns the pattern.BulkOptions for the input.
+func (in BulkInput) bulkOptions() (pattern.BulkOptions, error) {
+	if len(in.Patterns) == 0 {
+		return pattern.BulkOptions{}, fmt.Errorf("no patterns selected")
+	}
+	opts := pattern.BulkOptions{Names: in.Patterns}
+	tag := strings.TrimSpace(in.Tag)
+	switch in.Action {
+	case "archive":
+		opts.Set = map[string]string{"status": string(pattern.StatusArchived)}
+	case "pin", "unpin":
+		opts.Set = map[string]string{"pinned": fmt.Sprint(in.Action == "pin")}
+	case "share":
+		opts.Set = map[string]string{"shared": "true"}
+	case "tag", "untag":
+		if tag == "" {
+			return opts, fmt.Errorf("tag required")
+		}
+		if in.Action == "tag" {
+			opts.AddTags = []string{tag}
+		} else {
+			opts.RemoveTags = []string{tag}
+		}
+	default:
+		return opts, fmt.Errorf("unknown action %q (use archive, tag, untag, pin, unpin, or share)", in.Action)
+	}
+	return opts, nil
+}
+
+// POST /api/v1/bulk
+func (s *Server) handleAPIBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+		return
+	}
+	var in BulkInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: "invalid JSON: " + err.Error()})
+		return
+	}
+	opts, err := in.bulkOptions()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	result, err := s.store.Bulk(opts)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: BulkOutput{
+		Matched:  result.Matched,
+		Changed:  len(result.Changes),
+		Snapshot: result.SnapshotID,
+	}})
+}
+
+// POST /api/v1/bulk/undo with {"snapshot": id} restores the patterns a
+// bulk action changed, like 'mur learn bulk --undo'.
+func (s *Server) handleAPIBulkUndo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+		return
+	}
+	var in struct {
+		Snapshot string `json:"snapshot"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: "invalid JSON: " + err.Error()})
+		return
+	}
+	if in.Snapshot == "" || strings.ContainsAny(in.Snapshot, `/\`) || strings.Contains(in.Snapshot, "..") {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: "snapshot required"})
+		return
+	}
+	restored, err := s.store.UndoBulk(in.Snapshot)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, APIResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: map[string]int{"restored": restored}})
+}
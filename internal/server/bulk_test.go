@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestAPIBulkAndUndo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	store := pattern.NewStore(filepath.Join(t.TempDir(), "patterns"))
+	for _, name := range []string{"a", "b", "c"} {
+		if err := store.Create(&pattern.Pattern{Name: name, Content: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api := NewAPI(store, nil)
+
+	code, resp := call(t, api, "POST", "/api/v1/bulk", `{"patterns": ["a", "b"], "action": "archive"}`)
+	if code != http.StatusOK {
+		t.Fatalf("archive = %d: %s", code, resp.Error)
+	}
+	out := resp.Data.(map[string]interface{})
+	snapshot, _ := out["snapshot"].(string)
+	if out["changed"] != float64(2) || snapshot == "" {
+		t.Fatalf("archive result = %+v", out)
+	}
+	for name, want := range map[string]pattern.LifecycleStatus{"a": pattern.StatusArchived, "b": pattern.StatusArchived, "c": pattern.StatusActive} {
+		if p, _ := store.Get(name); p.Lifecycle.Status != want {
+			t.Errorf("%s status = %s, want %s", name, p.Lifecycle.Status, want)
+		}
+	}
+
+	code, resp = call(t, api, "POST", "/api/v1/bulk/undo", `{"snapshot": "`+snapshot+`"}`)
+	if code != http.StatusOK {
+		t.Fatalf("undo = %d: %s", code, resp.Error)
+	}
+	if p, _ := store.Get("a"); p.Lifecycle.Status != pattern.StatusActive {
+		t.Errorf("after undo a status = %s", p.Lifecycle.Status)
+	}
+
+	if code, resp := call(t, api, "POST", "/api/v1/bulk", `{"patterns": ["c"], "action": "tag", "tag": "legacy"}`); code != http.StatusOK {
+		t.Errorf("tag = %d: %s", code, resp.Error)
+	}
+	if p, _ := store.Get("c"); !p.HasTag("legacy") {
+		t.Error("tag not added")
+	}
+	if code, _ := call(t, api, "POST", "/api/v1/bulk", `{"patterns": ["c"], "action": "share"}`); code != http.StatusOK {
+		t.Errorf("share = %d", code)
+	}
+	if p, _ := store.Get("c"); !p.TeamShared || p.Security.TrustLevel != pattern.TrustOwner {
+		t.Errorf("share: shared = %v, trust = %s; want shared with trust unchanged", p.TeamShared, p.Security.TrustLevel)
+	}
+
+	for _, body := range []string{
+		`{"patterns": [], "action": "archive"}`,
+		`{"patterns": ["a"], "action": "explode"}`,
+		`{"patterns": ["a"], "action": "tag"}`,
+	} {
+		if code, _ := call(t, api, "POST", "/api/v1/bulk", body); code != http.StatusBadRequest {
+			t.Errorf("%s = %d, want 400", body, code)
+		}
+	}
+	if code, _ := call(t, api, "POST", "/api/v1/bulk/undo", `{"snapshot": "../x"}`); code != http.StatusBadRequest {
+		t.Errorf("undo with a path = %d, want 400", code)
+	}
+}
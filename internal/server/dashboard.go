@@ -0,0 +1,500 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/stats"
+	mursync "github.com/mur-run/mur-core/internal/sync"
+)
+
+// DashboardOptions configures the routes RegisterDashboard adds.
+type DashboardOptions struct {
+	Store   *pattern.Store
+	Tracker *inject.Tracker // may be nil
+	Version string          // shown in the dashboard footer
+
+	// Source returns where a pattern was extracted from, for /source/.
+	Source func(name string) (*pattern.SourceRef, error)
+
+	// APIOnly leaves out the HTML pages.
+	APIOnly bool
+}
+
+// dashboard serves the pages and JSON endpoints of 'mur serve' outside
+// the versioned API.
+type dashboard struct {
+	store   *pattern.Store
+	tracker *inject.Tracker
+	version string
+	source  func(name string) (*pattern.SourceRef, error)
+}
+
+// RegisterDashboard adds the dashboard to m:
+//
+//	GET  /, /source/{name}, /graph, /injections    (HTML, unless APIOnly)
+//	GET  /api/patterns[?where=&sort=&limit=], /api/stats, /api/injections
+//	GET  /api/v1/graph, /api/v1/export/patterns.ndjson
+//	POST /api/sync
+func RegisterDashboard(m *Mux, opts DashboardOptions) {
+	d := &dashboard{store: opts.Store, tracker: opts.Tracker, version: opts.Version, source: opts.Source}
+	if !opts.APIOnly {
+		m.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			d.serveDashboard(w, r)
+		})
+		m.HandleFunc("/source/", d.serveSource)
+		m.HandleFunc("/graph", serveGraphPage)
+		m.HandleFunc("/injections", serveInjectionsPage)
+	}
+	m.HandleFunc("/api/patterns", d.servePatterns)
+	m.HandleFunc("/api/stats", d.serveStats)
+	m.HandleFunc("/api/injections", serveInjections)
+	m.HandleFunc("/api/v1/graph", d.serveGraph)
+	m.HandleFunc("/api/v1/export/patterns.ndjson", d.serveExport)
+	m.HandleFunc("/api/sync", handleSyncAction)
+}
+
+// DashboardData holds data for the dashboard template
+type DashboardData struct {
+	// Patterns
+	Patterns       []PatternView
+	TotalPatterns  int
+	ActivePatterns int
+	TopPatterns    []PatternView
+	RecentPatterns []PatternView
+
+	// Usage Stats
+	TotalUsage     int
+	TotalRuns      int
+	AvgEffective   float64
+	EstimatedCost  float64
+	EstimatedSaved float64
+	DailyTrend     []DailyPoint
+	TrendMax       int
+	ToolBreakdown  []ToolUsage
+	AutoRouteStats AutoRouteView
+
+	// Sync Status
+	SyncTargets []SyncTarget
+
+	// Meta
+	Period      string            // Report period label (static reports only)
+	NewPatterns int               // Patterns created within Period
+	Comparison  *stats.Comparison // Period vs the one before (static reports only)
+	LastSync    string
+	GeneratedAt string
+	Version     string
+}
+
+// PatternView is a simplified pattern for display
+type PatternView struct {
+	Name          string
+	Description   string
+	Tags          []string
+	Domain        string
+	Effectiveness float64
+	UsageCount    int
+	LastUsed      string
+	CreatedAt     string
+	Status        string
+	Source        string
+	Pinned        bool
+	Groups        map[string]string // group per pattern.GroupLevels level
+}
+
+// DailyPoint for trend chart
+type DailyPoint struct {
+	Date  string
+	Day   string
+	Count int
+}
+
+// ToolUsage for breakdown
+type ToolUsage struct {
+	Name       string
+	Count      int
+	Percentage float64
+	Cost       float64
+	AvgTimeMs  int64
+	Tier       string
+}
+
+// AutoRouteView for auto-routing stats
+type AutoRouteView struct {
+	Total     int
+	ToFree    int
+	ToPaid    int
+	FreeRatio float64
+}
+
+// SyncTarget for sync status
+type SyncTarget struct {
+	Name      string
+	Type      string // "cli" or "ide"
+	Path      string
+	Exists    bool
+	FileCount int
+	LastMod   string
+	Installed bool // Tool is installed on system
+}
+
+func (d *dashboard) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	patterns, err := d.store.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := d.buildData(patterns)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	funcMap := template.FuncMap{
+		"mul": func(a, b float64) float64 { return a * b },
+		"sub": func(a, b float64) float64 { return a - b },
+		"div": func(a, b int) float64 {
+			if b == 0 {
+				return 0
+			}
+			return float64(a) / float64(b) * 100
+		},
+		"printf": fmt.Sprintf,
+	}
+
+	tmpl := template.Must(template.New("dashboard").Funcs(funcMap).Parse(dashboardHTML))
+	if err := tmpl.Execute(w, data); err != nil {
+		fmt.Printf("Template error: %v\n", err)
+	}
+}
+
+// servePatterns lists patterns as JSON. The optional where, sort, and limit
+// query parameters take the same syntax as 'mur learn list'.
+func (d *dashboard) servePatterns(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit := 0
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	q, err := pattern.ParseQuery(params.Get("where"), params.Get("sort"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	patterns, err := d.store.Query(q, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	views := make([]PatternView, 0, len(patterns))
+	for _, p := range patterns {
+		views = append(views, PatternToView(&p))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(views)
+}
+
+// serveStats returns the dashboard data as JSON.
+func (d *dashboard) serveStats(w http.ResponseWriter, r *http.Request) {
+	patterns, err := d.store.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := d.buildData(patterns)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+// handleSyncAction syncs patterns to the local tools.
+func handleSyncAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Sync in process: remote sync may prompt, which a request can't answer
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	results, err := mursync.SyncLocal(r.Context(), cfg)
+
+	result := map[string]interface{}{
+		"success": err == nil,
+		"output":  "",
+		"results": results,
+	}
+	if err != nil {
+		result["output"] = err.Error()
+	} else {
+		var failed []string
+		for _, r := range results {
+			if !r.Success {
+				failed = append(failed, r.Target+": "+r.Message)
+			}
+		}
+		if len(failed) > 0 {
+			result["success"] = false
+			result["output"] = strings.Join(failed, "; ")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// buildData collects the data the dashboard shows.
+func (d *dashboard) buildData(patterns []pattern.Pattern) DashboardData {
+	data := DashboardData{
+		Patterns:    make([]PatternView, 0, len(patterns)),
+		GeneratedAt: time.Now().In(stats.DisplayLocation()).Format("2006-01-02 15:04:05"),
+		Version:     d.version,
+	}
+
+	var totalEffectiveness float64
+	effectiveCount := 0
+
+	for _, p := range patterns {
+		view := PatternToView(&p)
+		data.Patterns = append(data.Patterns, view)
+		data.TotalUsage += view.UsageCount
+
+		if view.Effectiveness > 0 {
+			totalEffectiveness += view.Effectiveness
+			effectiveCount++
+		}
+
+		if view.Status == "active" || view.Status == "" {
+			data.ActivePatterns++
+		}
+	}
+
+	data.TotalPatterns = len(patterns)
+	if effectiveCount > 0 {
+		data.AvgEffective = totalEffectiveness / float64(effectiveCount) * 100
+	}
+
+	// Sort for top patterns (by effectiveness)
+	sorted := make([]PatternView, len(data.Patterns))
+	copy(sorted, data.Patterns)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Effectiveness > sorted[j].Effectiveness
+	})
+	if len(sorted) > 5 {
+		sorted = sorted[:5]
+	}
+	data.TopPatterns = sorted
+
+	// Sort for recent patterns (by created date)
+	recent := make([]PatternView, len(data.Patterns))
+	copy(recent, data.Patterns)
+	sort.Slice(recent, func(i, j int) bool {
+		return recent[i].CreatedAt > recent[j].CreatedAt
+	})
+	if len(recent) > 5 {
+		recent = recent[:5]
+	}
+	data.RecentPatterns = recent
+
+	// Load usage stats
+	records, _ := stats.Query(stats.QueryFilter{})
+	if len(records) > 0 {
+		summary := stats.Summarize(records)
+		data.TotalRuns = summary.TotalRuns
+		data.EstimatedCost = summary.EstimatedCost
+		data.EstimatedSaved = summary.EstimatedSaved
+
+		data.AutoRouteStats = AutoRouteView{
+			Total:     summary.AutoRouteStats.Total,
+			ToFree:    summary.AutoRouteStats.ToFree,
+			ToPaid:    summary.AutoRouteStats.ToPaid,
+			FreeRatio: summary.AutoRouteStats.FreeRatio,
+		}
+
+		// Daily trend
+		for _, d := range summary.DailyTrend {
+			date, _ := time.Parse("2006-01-02", d.Date)
+			data.DailyTrend = append(data.DailyTrend, DailyPoint{
+				Date:  d.Date,
+				Day:   date.Format("Mon"),
+				Count: d.Count,
+			})
+		}
+
+		// Tool breakdown
+		for name, ts := range summary.ByTool {
+			tier := "paid"
+			if name == "gemini" || name == "auggie" {
+				tier = "free"
+			}
+			data.ToolBreakdown = append(data.ToolBreakdown, ToolUsage{
+				Name:       name,
+				Count:      ts.Count,
+				Percentage: float64(ts.Count) / float64(summary.TotalRuns) * 100,
+				Cost:       ts.TotalCost,
+				AvgTimeMs:  ts.AvgTimeMs,
+				Tier:       tier,
+			})
+		}
+		// Sort by count descending
+		sort.Slice(data.ToolBreakdown, func(i, j int) bool {
+			return data.ToolBreakdown[i].Count > data.ToolBreakdown[j].Count
+		})
+	}
+
+	// Sync targets
+	data.SyncTargets = getSyncTargets()
+
+	return data
+}
+
+// getSyncTargets returns the tools patterns sync to and what each has.
+func getSyncTargets() []SyncTarget {
+	home, _ := os.UserHomeDir()
+
+	// Strict tool detection: check for actual binaries or app bundles
+	claudeInstalled := commandExists("claude") || fileExists(filepath.Join(home, ".npm-global", "bin", "claude"))
+	geminiInstalled := commandExists("gemini") || fileExists(filepath.Join(home, ".npm-global", "bin", "gemini"))
+	codexInstalled := commandExists("codex") || fileExists(filepath.Join(home, ".npm-global", "bin", "codex"))
+	auggieInstalled := commandExists("auggie") || fileExists(filepath.Join(home, ".npm-global", "bin", "auggie"))
+	aiderInstalled := commandExists("aider")
+	// IDEs: check for app bundle or settings that indicate actual usage
+	cursorInstalled := fileExists("/Applications/Cursor.app") || fileExists(filepath.Join(home, "Applications", "Cursor.app"))
+	windsurfInstalled := fileExists("/Applications/Windsurf.app") || fileExists(filepath.Join(home, "Applications", "Windsurf.app"))
+	continueInstalled := fileExists(filepath.Join(home, ".continue", "config.json"))
+	jetbrainsInstalled := mursync.JetBrainsInstalled()
+	vscodeInstalled := commandExists("code") || fileExists("/Applications/Visual Studio Code.app") || fileExists(filepath.Join(home, "Applications", "Visual Studio Code.app"))
+
+	targets := []SyncTarget{
+		// CLIs
+		{Name: "Claude Code", Type: "cli", Path: filepath.Join(config.ClaudeDir(home), "skills", "mur-index"), Installed: claudeInstalled},
+		{Name: "Gemini CLI", Type: "cli", Path: filepath.Join(home, ".gemini", "skills", "mur-index"), Installed: geminiInstalled},
+		{Name: "Codex CLI", Type: "cli", Path: filepath.Join(home, ".codex", "instructions.md"), Installed: codexInstalled},
+		{Name: "Auggie", Type: "cli", Path: filepath.Join(home, ".augment", "skills", "mur-index"), Installed: auggieInstalled},
+		{Name: "Aider", Type: "cli", Path: filepath.Join(home, ".aider", "conventions.md"), Installed: aiderInstalled},
+		// IDEs
+		{Name: "Continue", Type: "ide", Path: filepath.Join(home, ".continue", "rules", "mur-index"), Installed: continueInstalled},
+		{Name: "Cursor", Type: "ide", Path: filepath.Join(home, ".cursor", "rules", "mur-index"), Installed: cursorInstalled},
+		{Name: "Windsurf", Type: "ide", Path: filepath.Join(home, ".windsurf", "rules", "mur-index"), Installed: windsurfInstalled},
+		{Name: "VS Code Copilot", Type: "ide", Path: filepath.Join(home, ".vscode", "copilot", "mur-index.instructions.md"), Installed: vscodeInstalled},
+		{Name: "JetBrains AI Assistant", Type: "ide", Path: filepath.Join(home, ".aiassistant", "rules", "mur-index.md"), Installed: jetbrainsInstalled},
+		{Name: "Junie", Type: "ide", Path: filepath.Join(home, ".junie", "guidelines.md"), Installed: jetbrainsInstalled},
+	}
+
+	for i := range targets {
+		info, err := os.Stat(targets[i].Path)
+		if err == nil {
+			targets[i].Exists = true
+			targets[i].LastMod = info.ModTime().Format("Jan 2 15:04")
+			if info.IsDir() {
+				// Count pattern directories in parent (skills/rules dir)
+				parentDir := filepath.Dir(targets[i].Path)
+				entries, _ := os.ReadDir(parentDir)
+				count := 0
+				for _, e := range entries {
+					if e.IsDir() {
+						count++
+					}
+				}
+				targets[i].FileCount = count
+			} else {
+				targets[i].FileCount = 1
+			}
+		}
+	}
+
+	return targets
+}
+
+// PatternToView returns the dashboard's view of p.
+func PatternToView(p *pattern.Pattern) PatternView {
+	var tags []string
+	tags = append(tags, p.Tags.Confirmed...)
+	for _, t := range p.Tags.Inferred {
+		if t.Confidence >= 0.7 {
+			tags = append(tags, t.Tag)
+		}
+	}
+
+	lastUsed := "Never"
+	if p.Learning.LastUsed != nil {
+		lastUsed = p.Learning.LastUsed.In(stats.DisplayLocation()).Format("2006-01-02")
+	}
+
+	createdAt := ""
+	if !p.Lifecycle.Created.IsZero() {
+		createdAt = p.Lifecycle.Created.In(stats.DisplayLocation()).Format("2006-01-02")
+	}
+
+	// Extract domain from tags if available
+	domain := ""
+	for _, t := range p.Tags.Confirmed {
+		if t == "go" || t == "swift" || t == "python" || t == "node" || t == "rust" {
+			domain = t
+			break
+		}
+	}
+	if domain == "" {
+		for _, t := range p.Tags.Inferred {
+			if t.Confidence >= 0.7 && (t.Tag == "go" || t.Tag == "swift" || t.Tag == "python" || t.Tag == "node" || t.Tag == "rust") {
+				domain = t.Tag
+				break
+			}
+		}
+	}
+
+	return PatternView{
+		Name:          p.Name,
+		Description:   p.Description,
+		Tags:          tags,
+		Domain:        domain,
+		Effectiveness: p.Learning.Effectiveness,
+		UsageCount:    p.Learning.UsageCount,
+		LastUsed:      lastUsed,
+		CreatedAt:     createdAt,
+		Status:        string(p.Lifecycle.Status),
+		Source:        "",
+		Pinned:        p.Pinned,
+		Groups: map[string]string{
+			"domain":   pattern.GroupKey(p, "domain"),
+			"category": pattern.GroupKey(p, "category"),
+			"tag":      pattern.GroupKey(p, "tag"),
+		},
+	}
+}
+
+func commandExists(cmd string) bool {
+	_, err := exec.LookPath(cmd)
+	return err == nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
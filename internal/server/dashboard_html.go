@@ -0,0 +1,1568 @@
+package server
+
+// Dashboard HTML template with enhanced features
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>MUR Core Dashboard</title>
+    <style>
+        :root {
+            --bg-primary: #0f172a;
+            --bg-secondary: #1e293b;
+            --bg-tertiary: #334155;
+            --text-primary: #f1f5f9;
+            --text-secondary: #94a3b8;
+            --text-muted: #64748b;
+            --accent: #38bdf8;
+            --accent-hover: #0ea5e9;
+            --success: #4ade80;
+            --success-bg: #065f46;
+            --warning: #fbbf24;
+            --warning-bg: #78350f;
+            --error: #f87171;
+            --border: #334155;
+        }
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            background: var(--bg-primary);
+            color: var(--text-primary);
+            min-height: 100vh;
+            line-height: 1.5;
+        }
+        .container { max-width: 1400px; margin: 0 auto; padding: 2rem; }
+        
+        /* Header */
+        header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 2rem;
+            padding-bottom: 1rem;
+            border-bottom: 1px solid var(--border);
+        }
+        .logo {
+            font-size: 1.5rem;
+            font-weight: bold;
+            color: var(--accent);
+        }
+        .logo span { color: var(--text-primary); }
+        .header-right { display: flex; align-items: center; gap: 1rem; }
+        .version {
+            background: var(--bg-tertiary);
+            padding: 0.25rem 0.5rem;
+            border-radius: 0.25rem;
+            font-size: 0.75rem;
+            color: var(--text-secondary);
+        }
+        .generated { color: var(--text-muted); font-size: 0.875rem; }
+        
+        /* Grid Layout */
+        .grid { display: grid; gap: 1.5rem; }
+        .grid-2 { grid-template-columns: repeat(2, 1fr); }
+        .grid-3 { grid-template-columns: repeat(3, 1fr); }
+        .grid-4 { grid-template-columns: repeat(4, 1fr); }
+        @media (max-width: 1200px) { .grid-4 { grid-template-columns: repeat(2, 1fr); } }
+        @media (max-width: 768px) { 
+            .grid-2, .grid-3, .grid-4 { grid-template-columns: 1fr; }
+        }
+        
+        /* Cards */
+        .card {
+            background: var(--bg-secondary);
+            border-radius: 0.75rem;
+            padding: 1.5rem;
+            border: 1px solid var(--border);
+        }
+        .card-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 1rem;
+        }
+        .card-title {
+            font-size: 0.875rem;
+            color: var(--text-secondary);
+            text-transform: uppercase;
+            letter-spacing: 0.05em;
+        }
+        
+        /* Stats */
+        .stat-value {
+            font-size: 2.5rem;
+            font-weight: bold;
+            color: var(--accent);
+            line-height: 1;
+        }
+        .stat-value.green { color: var(--success); }
+        .stat-value.yellow { color: var(--warning); }
+        .stat-label {
+            font-size: 0.875rem;
+            color: var(--text-secondary);
+            margin-top: 0.5rem;
+        }
+        .stat-change {
+            font-size: 0.75rem;
+            margin-top: 0.25rem;
+        }
+        .stat-change.up { color: var(--success); }
+        .stat-change.down { color: var(--error); }
+        
+        /* Section */
+        .section { margin-bottom: 2rem; }
+        .section-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 1rem;
+        }
+        .section-title {
+            font-size: 1.25rem;
+            color: var(--text-primary);
+            display: flex;
+            align-items: center;
+            gap: 0.5rem;
+        }
+        
+        /* Patterns */
+        .patterns-grid { display: grid; gap: 1rem; }
+        .pattern-card {
+            background: var(--bg-secondary);
+            border-radius: 0.75rem;
+            padding: 1.25rem;
+            border: 1px solid var(--border);
+            transition: all 0.2s;
+            cursor: pointer;
+        }
+        .pattern-card:hover { 
+            border-color: var(--accent);
+            transform: translateY(-2px);
+        }
+        .pattern-card.selected {
+            border-color: var(--accent);
+            background: rgba(56, 189, 248, 0.08);
+        }
+        .pattern-card.focused { outline: 2px solid var(--accent); outline-offset: 2px; }
+        .pattern-card:focus { outline: none; }
+        .pattern-card.focused:focus { outline: 2px solid var(--accent); }
+        .pattern-select {
+            margin-right: 0.5rem;
+            accent-color: var(--accent);
+            cursor: pointer;
+            vertical-align: middle;
+        }
+        .bulk-bar {
+            display: none;
+            align-items: center;
+            gap: 0.5rem;
+            flex-wrap: wrap;
+            position: sticky;
+            top: 0.5rem;
+            z-index: 10;
+            margin-bottom: 1rem;
+            padding: 0.75rem 1rem;
+            background: var(--bg-secondary);
+            border: 1px solid var(--accent);
+            border-radius: 0.5rem;
+        }
+        .bulk-bar.active { display: flex; }
+        .bulk-bar .btn { padding: 0.5rem 1rem; }
+        .bulk-count { font-weight: 600; margin-right: auto; }
+        .kbd-hint {
+            color: var(--text-muted);
+            font-size: 0.75rem;
+            margin-bottom: 1rem;
+        }
+        .pattern-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: flex-start;
+            margin-bottom: 0.75rem;
+        }
+        .pattern-name {
+            font-weight: 600;
+            color: var(--text-primary);
+            font-size: 1rem;
+        }
+        .pattern-effectiveness {
+            background: var(--success-bg);
+            color: var(--success);
+            padding: 0.25rem 0.5rem;
+            border-radius: 0.375rem;
+            font-size: 0.75rem;
+            font-weight: 600;
+        }
+        .pattern-effectiveness.low {
+            background: var(--warning-bg);
+            color: var(--warning);
+        }
+        .pattern-description {
+            color: var(--text-secondary);
+            font-size: 0.875rem;
+            margin-bottom: 0.75rem;
+            display: -webkit-box;
+            -webkit-line-clamp: 2;
+            -webkit-box-orient: vertical;
+            overflow: hidden;
+        }
+        .pattern-tags { display: flex; gap: 0.5rem; flex-wrap: wrap; }
+        .tag {
+            background: var(--bg-tertiary);
+            color: var(--text-secondary);
+            padding: 0.25rem 0.5rem;
+            border-radius: 0.25rem;
+            font-size: 0.75rem;
+        }
+        .tag.domain {
+            background: rgba(56, 189, 248, 0.2);
+            color: var(--accent);
+        }
+        .pattern-meta {
+            margin-top: 0.75rem;
+            display: flex;
+            gap: 1rem;
+            color: var(--text-muted);
+            font-size: 0.75rem;
+        }
+        
+        /* Search */
+        .search-container { position: relative; margin-bottom: 1rem; }
+        .search-box {
+            background: var(--bg-secondary);
+            border: 1px solid var(--border);
+            border-radius: 0.5rem;
+            padding: 0.75rem 1rem 0.75rem 2.5rem;
+            color: var(--text-primary);
+            width: 100%;
+            font-size: 1rem;
+        }
+        .search-box:focus {
+            outline: none;
+            border-color: var(--accent);
+        }
+        .search-box::placeholder { color: var(--text-muted); }
+        .search-icon {
+            position: absolute;
+            left: 0.75rem;
+            top: 50%;
+            transform: translateY(-50%);
+            color: var(--text-muted);
+        }
+        
+        /* Filters */
+        .filters {
+            display: flex;
+            gap: 0.5rem;
+            margin-bottom: 1rem;
+            flex-wrap: wrap;
+        }
+        .filter-btn {
+            background: var(--bg-tertiary);
+            border: 1px solid var(--border);
+            border-radius: 0.375rem;
+            padding: 0.5rem 1rem;
+            color: var(--text-secondary);
+            font-size: 0.875rem;
+            cursor: pointer;
+            transition: all 0.2s;
+        }
+        .filter-btn:hover, .filter-btn.active {
+            background: var(--accent);
+            color: white;
+            border-color: var(--accent);
+        }
+        .group-select {
+            margin-left: auto;
+            background: var(--bg-tertiary);
+            border: 1px solid var(--border);
+            border-radius: 0.375rem;
+            padding: 0.5rem 0.75rem;
+            color: var(--text-secondary);
+            font-size: 0.875rem;
+        }
+        .patterns-grid.grouped { display: block; }
+        .pattern-group { margin-bottom: 1rem; }
+        .pattern-group summary {
+            cursor: pointer;
+            padding: 0.5rem 0;
+            font-weight: 600;
+        }
+        .pattern-group .group-meta {
+            margin-left: 0.5rem;
+            color: var(--text-secondary);
+            font-size: 0.875rem;
+            font-weight: normal;
+        }
+        .pattern-group .patterns-grid { margin-top: 0.5rem; }
+        
+        /* Bar Chart */
+        .bar-chart { display: flex; flex-direction: column; gap: 0.75rem; }
+        .bar-item { display: flex; align-items: center; gap: 1rem; }
+        .bar-label {
+            width: 80px;
+            font-size: 0.875rem;
+            color: var(--text-secondary);
+        }
+        .bar-container {
+            flex: 1;
+            height: 24px;
+            background: var(--bg-tertiary);
+            border-radius: 0.25rem;
+            overflow: hidden;
+        }
+        .bar-fill {
+            height: 100%;
+            background: linear-gradient(90deg, var(--accent), #818cf8);
+            border-radius: 0.25rem;
+            transition: width 0.5s ease;
+        }
+        .bar-fill.free { background: linear-gradient(90deg, var(--success), #22d3ee); }
+        .bar-value {
+            width: 60px;
+            text-align: right;
+            font-size: 0.875rem;
+            color: var(--text-secondary);
+        }
+        
+        /* Sparkline */
+        .sparkline {
+            display: flex;
+            align-items: flex-end;
+            gap: 4px;
+            height: 60px;
+            padding: 0.5rem 0;
+        }
+        .spark-bar {
+            flex: 1;
+            background: var(--accent);
+            border-radius: 2px;
+            transition: height 0.3s ease;
+            min-height: 4px;
+        }
+        .spark-bar:hover { background: var(--accent-hover); }
+        .spark-labels {
+            display: flex;
+            justify-content: space-between;
+            font-size: 0.75rem;
+            color: var(--text-muted);
+            margin-top: 0.5rem;
+        }
+        
+        /* Sync Status */
+        .sync-grid { display: grid; grid-template-columns: repeat(2, 1fr); gap: 0.75rem; }
+        @media (max-width: 768px) { .sync-grid { grid-template-columns: 1fr; } }
+        .sync-item {
+            display: flex;
+            align-items: center;
+            gap: 0.75rem;
+            padding: 0.75rem;
+            background: var(--bg-tertiary);
+            border-radius: 0.5rem;
+        }
+        .sync-icon {
+            width: 32px;
+            height: 32px;
+            border-radius: 0.375rem;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            font-size: 1rem;
+        }
+        .sync-icon.cli { background: rgba(56, 189, 248, 0.2); }
+        .sync-icon.ide { background: rgba(168, 85, 247, 0.2); }
+        .sync-info { flex: 1; }
+        .sync-name { font-size: 0.875rem; color: var(--text-primary); }
+        .sync-detail { font-size: 0.75rem; color: var(--text-muted); }
+        .sync-status {
+            width: 8px;
+            height: 8px;
+            border-radius: 50%;
+            background: var(--success);
+        }
+        .sync-status.inactive { background: var(--text-muted); }
+        
+        /* Buttons */
+        .btn {
+            background: var(--accent);
+            color: white;
+            border: none;
+            border-radius: 0.5rem;
+            padding: 0.75rem 1.5rem;
+            font-size: 0.875rem;
+            font-weight: 500;
+            cursor: pointer;
+            transition: all 0.2s;
+            display: inline-flex;
+            align-items: center;
+            gap: 0.5rem;
+        }
+        .btn:hover { background: var(--accent-hover); }
+        .btn:disabled { opacity: 0.5; cursor: not-allowed; }
+        .btn-secondary {
+            background: var(--bg-tertiary);
+            color: var(--text-secondary);
+        }
+        .btn-secondary:hover { background: var(--border); }
+        
+        /* Quick Actions */
+        .quick-actions {
+            display: flex;
+            gap: 0.75rem;
+            flex-wrap: wrap;
+        }
+        
+        /* Empty State */
+        .empty-state {
+            text-align: center;
+            padding: 3rem;
+            color: var(--text-muted);
+        }
+        .empty-state-icon { font-size: 3rem; margin-bottom: 1rem; }
+        
+        /* Modal */
+        .modal-overlay {
+            display: none;
+            position: fixed;
+            top: 0;
+            left: 0;
+            right: 0;
+            bottom: 0;
+            background: rgba(0, 0, 0, 0.7);
+            z-index: 1000;
+            align-items: center;
+            justify-content: center;
+        }
+        .modal-overlay.active { display: flex; }
+        .modal {
+            background: var(--bg-secondary);
+            border-radius: 1rem;
+            padding: 2rem;
+            max-width: 600px;
+            width: 90%;
+            max-height: 80vh;
+            overflow-y: auto;
+            border: 1px solid var(--border);
+        }
+        .modal-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 1.5rem;
+        }
+        .modal-title { font-size: 1.25rem; font-weight: 600; }
+        .modal-close {
+            background: none;
+            border: none;
+            color: var(--text-muted);
+            font-size: 1.5rem;
+            cursor: pointer;
+        }
+        .modal-close:hover { color: var(--text-primary); }
+        
+        /* Pattern editor */
+        .btn-danger {
+            background: transparent;
+            color: var(--error);
+            border: 1px solid var(--error);
+        }
+        .btn-danger:hover { background: rgba(248, 113, 113, 0.15); }
+        .modal-actions {
+            display: flex;
+            gap: 0.75rem;
+            justify-content: flex-end;
+            margin-top: 1.5rem;
+        }
+        .modal.wide { max-width: 800px; }
+        .form-field { margin-bottom: 1rem; }
+        .form-field label {
+            display: block;
+            font-size: 0.875rem;
+            color: var(--text-secondary);
+            margin-bottom: 0.375rem;
+        }
+        .form-input {
+            width: 100%;
+            background: var(--bg-tertiary);
+            border: 1px solid var(--border);
+            border-radius: 0.5rem;
+            padding: 0.625rem 0.75rem;
+            color: var(--text-primary);
+            font-size: 0.875rem;
+            font-family: inherit;
+        }
+        .form-input:focus { outline: none; border-color: var(--accent); }
+        .form-input:read-only { opacity: 0.6; }
+        textarea.form-input {
+            min-height: 240px;
+            resize: vertical;
+            font-family: ui-monospace, SFMono-Regular, Menlo, monospace;
+        }
+        .tag-editor {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 0.375rem;
+            align-items: center;
+            background: var(--bg-tertiary);
+            border: 1px solid var(--border);
+            border-radius: 0.5rem;
+            padding: 0.375rem 0.5rem;
+        }
+        .tag-editor:focus-within { border-color: var(--accent); }
+        .tag-editor input {
+            flex: 1;
+            min-width: 120px;
+            background: none;
+            border: none;
+            color: var(--text-primary);
+            font-size: 0.875rem;
+            padding: 0.25rem;
+        }
+        .tag-editor input:focus { outline: none; }
+        .tag-remove {
+            background: none;
+            border: none;
+            color: inherit;
+            cursor: pointer;
+            margin-left: 0.25rem;
+            padding: 0;
+        }
+        .markdown-preview {
+            min-height: 240px;
+            background: var(--bg-tertiary);
+            border-radius: 0.5rem;
+            padding: 1rem;
+            font-size: 0.875rem;
+            line-height: 1.6;
+        }
+        .markdown-preview h1, .markdown-preview h2, .markdown-preview h3,
+        .markdown-preview h4, .markdown-preview h5, .markdown-preview h6 { margin: 0.75rem 0 0.5rem; }
+        .markdown-preview p, .markdown-preview ul, .markdown-preview ol,
+        .markdown-preview blockquote { margin-bottom: 0.75rem; }
+        .markdown-preview ul, .markdown-preview ol { padding-left: 1.5rem; }
+        .markdown-preview blockquote {
+            border-left: 3px solid var(--border);
+            padding-left: 0.75rem;
+            color: var(--text-secondary);
+        }
+        .markdown-preview code {
+            background: var(--bg-secondary);
+            padding: 0.1rem 0.3rem;
+            border-radius: 0.25rem;
+        }
+        .markdown-preview pre {
+            background: var(--bg-secondary);
+            padding: 0.75rem;
+            border-radius: 0.5rem;
+            overflow-x: auto;
+            margin-bottom: 0.75rem;
+        }
+        .markdown-preview pre code { background: none; padding: 0; }
+        .markdown-preview a { color: var(--accent); }
+        
+        /* Tabs */
+        .tabs {
+            display: flex;
+            gap: 0;
+            border-bottom: 1px solid var(--border);
+            margin-bottom: 1rem;
+        }
+        .tab {
+            padding: 0.75rem 1.5rem;
+            color: var(--text-secondary);
+            cursor: pointer;
+            border-bottom: 2px solid transparent;
+            transition: all 0.2s;
+        }
+        .tab:hover { color: var(--text-primary); }
+        .tab.active {
+            color: var(--accent);
+            border-bottom-color: var(--accent);
+        }
+        
+        /* Footer */
+        footer {
+            text-align: center;
+            padding: 2rem;
+            color: var(--text-muted);
+            font-size: 0.875rem;
+        }
+        footer a { color: var(--accent); text-decoration: none; }
+        footer a:hover { text-decoration: underline; }
+        
+        /* Toast */
+        .toast {
+            position: fixed;
+            bottom: 2rem;
+            right: 2rem;
+            background: var(--bg-secondary);
+            border: 1px solid var(--border);
+            border-radius: 0.5rem;
+            padding: 1rem 1.5rem;
+            display: flex;
+            align-items: center;
+            gap: 0.75rem;
+            box-shadow: 0 10px 40px rgba(0, 0, 0, 0.3);
+            transform: translateY(100px);
+            opacity: 0;
+            transition: all 0.3s ease;
+            z-index: 1001;
+        }
+        .toast.show { transform: translateY(0); opacity: 1; }
+        .toast-action {
+            background: none;
+            border: 1px solid var(--accent);
+            border-radius: 0.375rem;
+            color: var(--accent);
+            padding: 0.25rem 0.75rem;
+            font-size: 0.875rem;
+            cursor: pointer;
+        }
+        .toast-action:hover { background: rgba(56, 189, 248, 0.15); }
+        .toast.success { border-color: var(--success); }
+        .toast.error { border-color: var(--error); }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <header>
+            <div class="logo">MUR<span> Core Dashboard</span></div>
+            <div class="header-right">
+                <a href="/graph" class="version">Graph</a>
+                <a href="/injections" class="version">Injections</a>
+                <span class="version">v{{.Version}}</span>
+                <span class="generated" data-live="generated">{{.GeneratedAt}}</span>
+            </div>
+        </header>
+        
+        <!-- Replaced in place on live updates (see refreshDashboard) -->
+        <div data-live="overview">
+        <!-- Stats Overview -->
+        <div class="section">
+            <div class="grid grid-4">
+                <div class="card">
+                    <div class="card-title">Total Patterns</div>
+                    <div class="stat-value">{{.TotalPatterns}}</div>
+                    <div class="stat-label">{{.ActivePatterns}} active</div>
+                </div>
+                <div class="card">
+                    <div class="card-title">Total Usage</div>
+                    <div class="stat-value">{{.TotalUsage}}</div>
+                    <div class="stat-label">pattern injections</div>
+                </div>
+                <div class="card">
+                    <div class="card-title">Avg Effectiveness</div>
+                    <div class="stat-value green">{{printf "%.0f" .AvgEffective}}%</div>
+                    <div class="stat-label">across all patterns</div>
+                </div>
+                <div class="card">
+                    <div class="card-title">Estimated Saved</div>
+                    <div class="stat-value yellow">${{printf "%.2f" .EstimatedSaved}}</div>
+                    <div class="stat-label">by using free tools</div>
+                </div>
+            </div>
+        </div>
+        
+        <!-- Charts Row -->
+        <div class="section">
+            <div class="grid grid-2">
+                <!-- Daily Trend -->
+                <div class="card">
+                    <div class="card-header">
+                        <span class="card-title">📈 Usage Trend (7 Days)</span>
+                    </div>
+                    {{if .DailyTrend}}
+                    <div class="sparkline" id="sparkline">
+                        {{range .DailyTrend}}
+                        <div class="spark-bar" style="height: 4px;" data-count="{{.Count}}" title="{{.Day}}: {{.Count}}"></div>
+                        {{end}}
+                    </div>
+                    <div class="spark-labels">
+                        {{range .DailyTrend}}<span>{{.Day}}</span>{{end}}
+                    </div>
+                    {{else}}
+                    <div class="empty-state" style="padding: 1rem;">
+                        <p>No usage data yet</p>
+                    </div>
+                    {{end}}
+                </div>
+                
+                <!-- Tool Breakdown -->
+                <div class="card">
+                    <div class="card-header">
+                        <span class="card-title">🔧 Tool Usage</span>
+                    </div>
+                    {{if .ToolBreakdown}}
+                    <div class="bar-chart">
+                        {{range .ToolBreakdown}}
+                        <div class="bar-item">
+                            <span class="bar-label">{{.Name}}</span>
+                            <div class="bar-container">
+                                <div class="bar-fill {{if eq .Tier "free"}}free{{end}}" style="width: {{printf "%.0f" .Percentage}}%;"></div>
+                            </div>
+                            <span class="bar-value">{{.Count}}</span>
+                        </div>
+                        {{end}}
+                    </div>
+                    {{else}}
+                    <div class="empty-state" style="padding: 1rem;">
+                        <p>No tool usage recorded</p>
+                    </div>
+                    {{end}}
+                </div>
+            </div>
+        </div>
+        
+        <!-- Sync Status & Quick Actions -->
+        <div class="section">
+            <div class="grid grid-2">
+                <!-- Sync Status -->
+                <div class="card">
+                    <div class="card-header">
+                        <span class="card-title">🔄 Sync Status</span>
+                        <button class="btn btn-secondary" onclick="triggerSync()" id="syncBtn">
+                            Sync Now
+                        </button>
+                    </div>
+                    <div class="sync-grid">
+                        {{range .SyncTargets}}
+                        {{if .Installed}}
+                        <div class="sync-item">
+                            <div class="sync-icon {{.Type}}">
+                                {{if eq .Type "cli"}}⌨️{{else}}🖥️{{end}}
+                            </div>
+                            <div class="sync-info">
+                                <div class="sync-name">{{.Name}}</div>
+                                <div class="sync-detail">
+                                    {{if .Exists}}{{.FileCount}} files • {{.LastMod}}{{else}}Not synced{{end}}
+                                </div>
+                            </div>
+                            <div class="sync-status {{if not .Exists}}inactive{{end}}"></div>
+                        </div>
+                        {{end}}
+                        {{end}}
+                    </div>
+                </div>
+                
+                <!-- Auto-Routing Stats -->
+                <div class="card">
+                    <div class="card-header">
+                        <span class="card-title">🔀 Auto-Routing</span>
+                    </div>
+                    {{if gt .AutoRouteStats.Total 0}}
+                    <div style="display: flex; gap: 2rem; margin-bottom: 1rem;">
+                        <div>
+                            <div class="stat-value" style="font-size: 1.5rem;">{{.AutoRouteStats.Total}}</div>
+                            <div class="stat-label">Total Routed</div>
+                        </div>
+                        <div>
+                            <div class="stat-value green" style="font-size: 1.5rem;">{{printf "%.0f" .AutoRouteStats.FreeRatio}}%</div>
+                            <div class="stat-label">To Free Tools</div>
+                        </div>
+                    </div>
+                    <div class="bar-chart">
+                        <div class="bar-item">
+                            <span class="bar-label">Free</span>
+                            <div class="bar-container">
+                                <div class="bar-fill free" style="width: {{printf "%.0f" .AutoRouteStats.FreeRatio}}%;"></div>
+                            </div>
+                            <span class="bar-value">{{.AutoRouteStats.ToFree}}</span>
+                        </div>
+                        <div class="bar-item">
+                            <span class="bar-label">Paid</span>
+                            <div class="bar-container">
+                                <div class="bar-fill" style="width: {{printf "%.0f" (sub 100 .AutoRouteStats.FreeRatio)}}%;"></div>
+                            </div>
+                            <span class="bar-value">{{.AutoRouteStats.ToPaid}}</span>
+                        </div>
+                    </div>
+                    {{else}}
+                    <div class="empty-state" style="padding: 1rem;">
+                        <p>No auto-routing data yet</p>
+                        <p style="font-size: 0.75rem; margin-top: 0.5rem;">Use <code>mur run</code> to start</p>
+                    </div>
+                    {{end}}
+                </div>
+            </div>
+        </div>
+        
+        {{if .TopPatterns}}
+        <!-- Top Patterns -->
+        <div class="section">
+            <div class="section-header">
+                <h2 class="section-title">⭐ Top Patterns</h2>
+            </div>
+            <div class="patterns-grid" style="grid-template-columns: repeat(auto-fill, minmax(300px, 1fr));">
+                {{range .TopPatterns}}
+                <div class="pattern-card" onclick="showPattern('{{.Name}}')">
+                    <div class="pattern-header">
+                        <span class="pattern-name">{{if .Pinned}}📌 {{end}}{{.Name}}</span>
+                        {{if gt .Effectiveness 0.0}}
+                        <span class="pattern-effectiveness {{if lt .Effectiveness 0.5}}low{{end}}">{{printf "%.0f" (mul .Effectiveness 100)}}%</span>
+                        {{end}}
+                    </div>
+                    {{if .Description}}
+                    <div class="pattern-description">{{.Description}}</div>
+                    {{end}}
+                    <div class="pattern-tags">
+                        {{if .Domain}}<span class="tag domain">{{.Domain}}</span>{{end}}
+                        {{range .Tags}}
+                        <span class="tag">{{.}}</span>
+                        {{end}}
+                    </div>
+                    <div class="pattern-meta">
+                        <span>📊 {{.UsageCount}} uses</span>
+                        <span>🕐 {{.LastUsed}}</span>
+                    </div>
+                </div>
+                {{end}}
+            </div>
+        </div>
+        {{end}}
+        </div>
+        
+        <!-- All Patterns -->
+        <div class="section">
+            <div class="section-header">
+                <h2 class="section-title">📚 All Patterns</h2>
+                <button class="btn btn-secondary" onclick="openEditor()">+ New Pattern</button>
+            </div>
+            
+            <div class="search-container">
+                <span class="search-icon">🔍</span>
+                <input type="text" class="search-box" placeholder="Search patterns by name, tag, or domain..." id="search">
+            </div>
+            
+            <div class="filters" id="filters">
+                <button class="filter-btn active" data-filter="all">All</button>
+                <button class="filter-btn" data-filter="active">Active</button>
+                <button class="filter-btn" data-filter="deprecated">Deprecated</button>
+                <button class="filter-btn" data-filter="pinned">📌 Pinned</button>
+                <button class="filter-btn" data-filter="go">Go</button>
+                <button class="filter-btn" data-filter="swift">Swift</button>
+                <button class="filter-btn" data-filter="general">General</button>
+                <select class="group-select" id="group-by" title="Group patterns">
+                    <option value="">No grouping</option>
+                    <option value="domain">Group by domain</option>
+                    <option value="category">Group by category</option>
+                    <option value="tag">Group by tag</option>
+                </select>
+            </div>
+            <p class="kbd-hint">Keys: / search · j/k move · x select · Shift+A select all shown · Enter open · a archive · t tag · p pin · s share · u undo · Esc clear</p>
+            
+            <div class="bulk-bar" id="bulk-bar">
+                <span class="bulk-count" id="bulk-count">0 selected</span>
+                <button class="btn btn-secondary" onclick="bulkAction('archive')" title="a">Archive</button>
+                <button class="btn btn-secondary" onclick="bulkTag()" title="t">Add tag</button>
+                <button class="btn btn-secondary" onclick="bulkAction('pin')" title="p">📌 Pin</button>
+                <button class="btn btn-secondary" onclick="bulkAction('unpin')">Unpin</button>
+                <button class="btn btn-secondary" onclick="bulkAction('share')" title="s">Share to team</button>
+                <button class="btn btn-secondary" onclick="clearSelection()" title="Esc">Clear</button>
+            </div>
+            
+            <div data-live="patterns">
+            {{if .Patterns}}
+            <div class="patterns-grid" id="patterns-list" style="grid-template-columns: repeat(auto-fill, minmax(300px, 1fr));">
+                {{range .Patterns}}
+                <div class="pattern-card" 
+                     data-name="{{.Name}}" 
+                     data-tags="{{range .Tags}}{{.}} {{end}}"
+                     data-domain="{{.Domain}}"
+                     data-status="{{.Status}}"
+                     data-pinned="{{.Pinned}}"
+                     data-effectiveness="{{.Effectiveness}}"
+                     data-uses="{{.UsageCount}}"
+                     data-group-domain="{{index .Groups "domain"}}"
+                     data-group-category="{{index .Groups "category"}}"
+                     data-group-tag="{{index .Groups "tag"}}"
+                     tabindex="-1"
+                     onclick="showPattern('{{.Name}}')">
+                    <div class="pattern-header">
+                        <span class="pattern-name"><input type="checkbox" class="pattern-select" aria-label="Select {{.Name}}" onclick="event.stopPropagation(); toggleSelected(this.closest('.pattern-card'))">{{if .Pinned}}📌 {{end}}{{.Name}}</span>
+                        {{if gt .Effectiveness 0.0}}
+                        <span class="pattern-effectiveness {{if lt .Effectiveness 0.5}}low{{end}}">{{printf "%.0f" (mul .Effectiveness 100)}}%</span>
+                        {{end}}
+                    </div>
+                    {{if .Description}}
+                    <div class="pattern-description">{{.Description}}</div>
+                    {{end}}
+                    <div class="pattern-tags">
+                        {{if .Domain}}<span class="tag domain">{{.Domain}}</span>{{end}}
+                        {{range .Tags}}
+                        <span class="tag">{{.}}</span>
+                        {{end}}
+                    </div>
+                    <div class="pattern-meta">
+                        <span>📊 {{.UsageCount}} uses</span>
+                        {{if .CreatedAt}}<span>📅 {{.CreatedAt}}</span>{{end}}
+                    </div>
+                </div>
+                {{end}}
+            </div>
+            {{else}}
+            <div class="empty-state">
+                <div class="empty-state-icon">📭</div>
+                <p>No patterns yet</p>
+                <p style="margin-top: 0.5rem; font-size: 0.875rem;">Click <strong>+ New Pattern</strong> or run <code>mur learn add</code> to create your first pattern</p>
+            </div>
+            {{end}}
+            </div>
+        </div>
+        
+        <footer>
+            <p>mur — Continuous learning for AI assistants</p>
+            <p style="margin-top: 0.5rem;">
+                <a href="https://github.com/mur-run/mur-core">GitHub</a> · 
+                <a href="https://mur.run">Documentation</a>
+            </p>
+        </footer>
+    </div>
+    
+    <!-- Pattern Detail Modal -->
+    <div class="modal-overlay" id="patternModal">
+        <div class="modal">
+            <div class="modal-header">
+                <h3 class="modal-title" id="modalTitle">Pattern Details</h3>
+                <button class="modal-close" onclick="closeModal()">&times;</button>
+            </div>
+            <div id="modalContent">Loading...</div>
+        </div>
+    </div>
+    
+    <!-- Pattern Editor Modal -->
+    <div class="modal-overlay" id="editorModal">
+        <div class="modal wide">
+            <div class="modal-header">
+                <h3 class="modal-title" id="editorTitle">New Pattern</h3>
+                <button class="modal-close" onclick="closeEditor()">&times;</button>
+            </div>
+            <form id="editorForm" onsubmit="savePattern(event)">
+                <div class="form-field">
+                    <label for="editorName">Name</label>
+                    <input class="form-input" id="editorName" required placeholder="go-error-wrapping" autocomplete="off">
+                </div>
+                <div class="form-field">
+                    <label for="editorDescription">Description</label>
+                    <input class="form-input" id="editorDescription" placeholder="One line on when this applies" autocomplete="off">
+                </div>
+                <div class="form-field">
+                    <label for="editorTagInput">Tags</label>
+                    <div class="tag-editor" onclick="document.getElementById('editorTagInput').focus()">
+                        <span id="editorTags"></span>
+                        <input id="editorTagInput" placeholder="Add a tag, then Enter" autocomplete="off">
+                    </div>
+                </div>
+                <div class="form-field">
+                    <div class="tabs">
+                        <div class="tab active" data-pane="write" onclick="showEditorPane('write')">Write</div>
+                        <div class="tab" data-pane="preview" onclick="showEditorPane('preview')">Preview</div>
+                    </div>
+                    <textarea class="form-input" id="editorContent" placeholder="Markdown: what to do, and why"></textarea>
+                    <div class="markdown-preview" id="editorPreview" style="display: none;"></div>
+                </div>
+                <div class="modal-actions">
+                    <button type="button" class="btn btn-secondary" onclick="closeEditor()">Cancel</button>
+                    <button type="submit" class="btn" id="editorSave">Save</button>
+                </div>
+            </form>
+        </div>
+    </div>
+    
+    <!-- Toast -->
+    <div class="toast" id="toast">
+        <span id="toastIcon">✓</span>
+        <span id="toastMessage">Action completed</span>
+        <button class="toast-action" id="toastAction" hidden></button>
+    </div>
+    
+    <script>
+        // Sparkline animation
+        function animateSparkline() {
+            const bars = document.querySelectorAll('.spark-bar');
+            const maxCount = Math.max(...Array.from(bars).map(b => parseInt(b.dataset.count) || 0), 1);
+            
+            setTimeout(() => {
+                bars.forEach(bar => {
+                    const count = parseInt(bar.dataset.count) || 0;
+                    const height = Math.max((count / maxCount) * 100, 8);
+                    bar.style.height = height + '%';
+                });
+            }, 100);
+        }
+        document.addEventListener('DOMContentLoaded', animateSparkline);
+        
+        // Search
+        const search = document.getElementById('search');
+        
+        search?.addEventListener('input', (e) => {
+            const query = e.target.value.toLowerCase();
+            filterPatterns(query, getCurrentFilter());
+        });
+        
+        // Filters
+        document.querySelectorAll('.filter-btn').forEach(btn => {
+            btn.addEventListener('click', () => {
+                document.querySelectorAll('.filter-btn').forEach(b => b.classList.remove('active'));
+                btn.classList.add('active');
+                filterPatterns(search?.value?.toLowerCase() || '', btn.dataset.filter);
+            });
+        });
+        
+        function getCurrentFilter() {
+            return document.querySelector('.filter-btn.active')?.dataset.filter || 'all';
+        }
+        
+        function filterPatterns(query, filter) {
+            document.querySelectorAll('#patterns-list .pattern-card').forEach(card => {
+                const name = card.dataset.name?.toLowerCase() || '';
+                const tags = card.dataset.tags?.toLowerCase() || '';
+                const domain = card.dataset.domain?.toLowerCase() || '';
+                const status = card.dataset.status?.toLowerCase() || 'active';
+                
+                let matchesQuery = !query || name.includes(query) || tags.includes(query) || domain.includes(query);
+                let matchesFilter = filter === 'all' ||
+                    (filter === 'active' && (status === 'active' || !status)) ||
+                    (filter === 'deprecated' && status === 'deprecated') ||
+                    (filter === 'pinned' && card.dataset.pinned === 'true') ||
+                    domain.includes(filter);
+                
+                card.style.display = (matchesQuery && matchesFilter) ? 'block' : 'none';
+            });
+            document.querySelectorAll('#patterns-list .pattern-group').forEach(group => {
+                const shown = Array.from(group.querySelectorAll('.pattern-card')).some(c => c.style.display !== 'none');
+                group.style.display = shown ? '' : 'none';
+            });
+        }
+        
+        // Grouping: cards move into a collapsible section per domain,
+        // category or tag, largest first, headed by the group's pattern
+        // count, mean effectiveness and uses. Large groups start collapsed.
+        const groupCollapseAt = 24;
+        const groupBy = document.getElementById('group-by');
+        if (groupBy) {
+            groupBy.value = localStorage.getItem('murGroupBy') || '';
+            groupBy.addEventListener('change', () => {
+                localStorage.setItem('murGroupBy', groupBy.value);
+                groupPatterns();
+                filterPatterns(search?.value?.toLowerCase() || '', getCurrentFilter());
+            });
+        }
+        
+        function groupPatterns() {
+            const list = document.getElementById('patterns-list');
+            if (!list) return;
+            const cards = Array.from(list.querySelectorAll('.pattern-card'));
+            cards.forEach((card, i) => { if (card.dataset.order === undefined) card.dataset.order = i; });
+            cards.sort((a, b) => a.dataset.order - b.dataset.order);
+            list.replaceChildren();
+            
+            const level = groupBy?.value || '';
+            list.classList.toggle('grouped', level !== '');
+            if (!level) {
+                list.append(...cards);
+                return;
+            }
+            
+            const groups = new Map();
+            cards.forEach(card => {
+                const key = card.dataset['group' + level[0].toUpperCase() + level.slice(1)] || 'other';
+                if (!groups.has(key)) groups.set(key, []);
+                groups.get(key).push(card);
+            });
+            const sorted = Array.from(groups.entries()).sort((a, b) => b[1].length - a[1].length || a[0].localeCompare(b[0]));
+            for (const [name, members] of sorted) {
+                const eff = members.reduce((sum, c) => sum + (parseFloat(c.dataset.effectiveness) || 0), 0) / members.length;
+                const uses = members.reduce((sum, c) => sum + (parseInt(c.dataset.uses) || 0), 0);
+                const group = document.createElement('details');
+                group.className = 'pattern-group';
+                group.open = members.length <= groupCollapseAt;
+                const summary = document.createElement('summary');
+                summary.textContent = name;
+                const meta = document.createElement('span');
+                meta.className = 'group-meta';
+                meta.textContent = members.length + (members.length === 1 ? ' pattern' : ' patterns') +
+                    ' · ' + (eff * 100).toFixed(0) + '% effective · ' + uses + ' uses';
+                summary.append(meta);
+                const grid = document.createElement('div');
+                grid.className = 'patterns-grid';
+                grid.style.gridTemplateColumns = list.style.gridTemplateColumns;
+                grid.append(...members);
+                group.append(summary, grid);
+                list.append(group);
+            }
+        }
+        groupPatterns();
+        
+        // Bulk actions: select cards with their checkbox or x, then the
+        // bar applies an action to all of them through /api/v1/bulk. The
+        // server snapshots the patterns first; the toast's Undo (or u)
+        // restores the snapshot through /api/v1/bulk/undo.
+        const selected = new Set();
+        let focusedName = null;
+        let lastSnapshot = null;
+        const bulkDone = { archive: 'Archived', tag: 'Tagged', pin: 'Pinned', unpin: 'Unpinned', share: 'Shared' };
+        
+        function visibleCards() {
+            // Hidden by a filter or inside a collapsed group: no offsetParent
+            return Array.from(document.querySelectorAll('#patterns-list .pattern-card')).filter(c => c.offsetParent !== null);
+        }
+        
+        function toggleSelected(card) {
+            const name = card.dataset.name;
+            if (selected.has(name)) selected.delete(name); else selected.add(name);
+            renderSelection();
+        }
+        
+        function clearSelection() {
+            selected.clear();
+            renderSelection();
+        }
+        
+        // renderSelection marks the selected and focused cards, dropping
+        // names that are no longer listed, and shows the bar.
+        function renderSelection() {
+            const listed = new Set();
+            document.querySelectorAll('#patterns-list .pattern-card').forEach(card => {
+                const name = card.dataset.name;
+                listed.add(name);
+                card.classList.toggle('selected', selected.has(name));
+                card.classList.toggle('focused', name === focusedName);
+                const box = card.querySelector('.pattern-select');
+                if (box) box.checked = selected.has(name);
+            });
+            selected.forEach(name => { if (!listed.has(name)) selected.delete(name); });
+            document.getElementById('bulk-bar').classList.toggle('active', selected.size > 0);
+            document.getElementById('bulk-count').textContent = selected.size + ' selected';
+        }
+        
+        async function bulkAction(action, tag) {
+            if (!selected.size) return;
+            try {
+                const res = await fetch('/api/v1/bulk', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ patterns: Array.from(selected), action, tag }),
+                });
+                const result = await res.json();
+                if (!result.success) throw new Error(result.error || res.statusText);
+                const { changed, snapshot } = result.data;
+                lastSnapshot = snapshot || lastSnapshot;
+                if (action === 'archive') clearSelection();
+                const message = changed
+                    ? bulkDone[action] + ' ' + changed + (changed === 1 ? ' pattern' : ' patterns') + (tag ? ' with ' + tag : '')
+                    : 'Nothing to change';
+                showToast(message, 'success', snapshot ? { label: 'Undo', run: () => undoBulk(snapshot) } : null);
+                refreshDashboard();
+            } catch (err) {
+                showToast('Bulk action failed: ' + err.message, 'error');
+            }
+        }
+        
+        function bulkTag() {
+            if (!selected.size) return;
+            const tag = prompt('Tag to add to ' + selected.size + (selected.size === 1 ? ' pattern' : ' patterns') + ':');
+            if (tag && tag.trim()) bulkAction('tag', tag.trim());
+        }
+        
+        async function undoBulk(snapshot = lastSnapshot) {
+            if (!snapshot) return;
+            try {
+                const res = await fetch('/api/v1/bulk/undo', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ snapshot }),
+                });
+                const result = await res.json();
+                if (!result.success) throw new Error(result.error || res.statusText);
+                if (snapshot === lastSnapshot) lastSnapshot = null;
+                showToast('Restored ' + result.data.restored + (result.data.restored === 1 ? ' pattern' : ' patterns'), 'success');
+                refreshDashboard();
+            } catch (err) {
+                showToast('Undo failed: ' + err.message, 'error');
+            }
+        }
+        
+        function focusCard(card) {
+            if (!card) return;
+            focusedName = card.dataset.name;
+            renderSelection();
+            card.focus({ preventScroll: true });
+            card.scrollIntoView({ block: 'nearest' });
+        }
+        
+        // Keyboard: / search, j/k (or arrows once a card is focused) move,
+        // x or Space select, Shift+A select all shown, Enter open, a/t/p/s
+        // act on the selection, u undo, Esc clear. Ignored while typing or
+        // with a modal open.
+        document.addEventListener('keydown', (e) => {
+            if (e.ctrlKey || e.metaKey || e.altKey) return;
+            if (e.target.closest('input, textarea, select, button') || document.querySelector('.modal-overlay.active')) return;
+            const cards = visibleCards();
+            const current = cards.findIndex(c => c.dataset.name === focusedName);
+            const arrow = e.key.startsWith('Arrow');
+            if (arrow && current < 0) return;
+            switch (e.key) {
+                case '/': search?.focus(); break;
+                case 'j': case 'ArrowDown': case 'ArrowRight':
+                    focusCard(cards[Math.min(current + 1, cards.length - 1)]); break;
+                case 'k': case 'ArrowUp': case 'ArrowLeft':
+                    focusCard(cards[Math.max(current - 1, 0)]); break;
+                case 'x': case ' ':
+                    if (current < 0) return;
+                    toggleSelected(cards[current]); break;
+                case 'A':
+                    cards.forEach(c => selected.add(c.dataset.name));
+                    renderSelection(); break;
+                case 'Enter':
+                    if (current < 0) return;
+                    showPattern(focusedName); break;
+                case 'a': bulkAction('archive'); break;
+                case 't': bulkTag(); break;
+                case 'p': bulkAction('pin'); break;
+                case 's': bulkAction('share'); break;
+                case 'u': undoBulk(); break;
+                case 'Escape': clearSelection(); break;
+                default: return;
+            }
+            e.preventDefault();
+        });
+        
+        // Modal
+        let currentPattern = null;
+        
+        async function showPattern(name) {
+            const modal = document.getElementById('patternModal');
+            const title = document.getElementById('modalTitle');
+            const content = document.getElementById('modalContent');
+            
+            modal.classList.add('active');
+            title.textContent = name;
+            content.innerHTML = 'Loading...';
+            currentPattern = null;
+            
+            try {
+                const res = await fetch('/api/pattern/' + encodeURIComponent(name));
+                const pattern = await res.json();
+                if (!res.ok) throw new Error(pattern.error || res.statusText);
+                currentPattern = pattern;
+                const tags = (pattern.tags || []).map(t => '<span class="tag">' + escapeHtml(t) + '</span>').join(' ');
+                
+                content.innerHTML = ` + "`" + `
+                    <div style="margin-bottom: 1rem;">
+                        <strong>Description:</strong><br>
+                        ${escapeHtml(pattern.description || 'No description')}
+                    </div>
+                    <div style="margin-bottom: 1rem;">
+                        <strong>Tags:</strong> ${tags || 'none'}<br>
+                        <strong>Status:</strong> ${escapeHtml(pattern.status || 'active')}<br>
+                        <strong>Effectiveness:</strong> ${((pattern.effectiveness || 0) * 100).toFixed(0)}%<br>
+                        <strong>Usage Count:</strong> ${pattern.usage_count || 0}
+                    </div>
+                    <div style="margin-bottom: 1rem;">
+                        <strong>Content:</strong>
+                        <div class="markdown-preview" style="margin-top: 0.5rem; min-height: 0;">${renderMarkdown(pattern.content || 'No content')}</div>
+                    </div>
+                    <div class="modal-actions">
+                        <button class="btn btn-danger" onclick="deletePattern()">Delete</button>
+                        <button class="btn" onclick="openEditor(currentPattern)">Edit</button>
+                    </div>
+                ` + "`" + `;
+            } catch (err) {
+                content.innerHTML = 'Error loading pattern: ' + escapeHtml(err.message);
+            }
+        }
+        
+        function closeModal() {
+            document.getElementById('patternModal').classList.remove('active');
+        }
+        
+        document.getElementById('patternModal').addEventListener('click', (e) => {
+            if (e.target.classList.contains('modal-overlay')) closeModal();
+        });
+        
+        document.addEventListener('keydown', (e) => {
+            if (e.key === 'Escape') {
+                closeModal();
+                closeEditor();
+            }
+        });
+        
+        // Editor: POST creates, PUT saves, DELETE trashes, all on
+        // /api/pattern/<name>. The live refresh picks up the change.
+        let editing = null;
+        let editorTags = [];
+        
+        function openEditor(pattern) {
+            editing = pattern ? pattern.name : null;
+            const name = document.getElementById('editorName');
+            document.getElementById('editorTitle').textContent = pattern ? 'Edit ' + pattern.name : 'New Pattern';
+            name.value = pattern ? pattern.name : '';
+            name.readOnly = !!pattern;
+            document.getElementById('editorDescription').value = pattern?.description || '';
+            document.getElementById('editorContent').value = pattern?.content || '';
+            document.getElementById('editorTagInput').value = '';
+            editorTags = [...(pattern?.tags || [])];
+            renderTags();
+            showEditorPane('write');
+            
+            closeModal();
+            document.getElementById('editorModal').classList.add('active');
+            (pattern ? document.getElementById('editorContent') : name).focus();
+        }
+        
+        function closeEditor() {
+            document.getElementById('editorModal').classList.remove('active');
+        }
+        
+        function showEditorPane(pane) {
+            const textarea = document.getElementById('editorContent');
+            const preview = document.getElementById('editorPreview');
+            document.querySelectorAll('#editorModal .tab').forEach(tab => {
+                tab.classList.toggle('active', tab.dataset.pane === pane);
+            });
+            if (pane === 'preview') {
+                preview.innerHTML = renderMarkdown(textarea.value) || '<span style="color: var(--text-muted);">Nothing to preview</span>';
+            }
+            textarea.style.display = pane === 'write' ? 'block' : 'none';
+            preview.style.display = pane === 'preview' ? 'block' : 'none';
+        }
+        
+        function renderTags() {
+            document.getElementById('editorTags').innerHTML = editorTags.map((tag, i) =>
+                '<span class="tag">' + escapeHtml(tag) +
+                '<button type="button" class="tag-remove" onclick="removeTag(' + i + ')" title="Remove">&times;</button></span>'
+            ).join(' ');
+        }
+        
+        function addTag(value) {
+            const tag = value.trim();
+            if (tag && !editorTags.includes(tag)) editorTags.push(tag);
+            renderTags();
+        }
+        
+        function removeTag(i) {
+            editorTags.splice(i, 1);
+            renderTags();
+        }
+        
+        const tagInput = document.getElementById('editorTagInput');
+        tagInput.addEventListener('keydown', (e) => {
+            if (e.key === 'Enter' || e.key === ',') {
+                e.preventDefault();
+                addTag(tagInput.value);
+                tagInput.value = '';
+            } else if (e.key === 'Backspace' && !tagInput.value && editorTags.length) {
+                editorTags.pop();
+                renderTags();
+            }
+        });
+        tagInput.addEventListener('blur', () => {
+            addTag(tagInput.value);
+            tagInput.value = '';
+        });
+        
+        async function savePattern(e) {
+            e.preventDefault();
+            addTag(tagInput.value);
+            tagInput.value = '';
+            
+            const name = document.getElementById('editorName').value.trim();
+            const btn = document.getElementById('editorSave');
+            btn.disabled = true;
+            try {
+                const res = await fetch('/api/pattern/' + encodeURIComponent(name), {
+                    method: editing ? 'PUT' : 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({
+                        description: document.getElementById('editorDescription').value.trim(),
+                        content: document.getElementById('editorContent').value,
+                        tags: editorTags,
+                    }),
+                });
+                const result = await res.json();
+                if (!result.success) throw new Error(result.error || res.statusText);
+                closeEditor();
+                showToast((editing ? 'Saved ' : 'Created ') + name, 'success');
+                refreshDashboard();
+            } catch (err) {
+                showToast('Save failed: ' + err.message, 'error');
+            } finally {
+                btn.disabled = false;
+            }
+        }
+        
+        async function deletePattern() {
+            const name = currentPattern?.name;
+            if (!name || !confirm('Delete ' + name + '? It moves to the trash; mur learn trash restore brings it back.')) return;
+            try {
+                const res = await fetch('/api/pattern/' + encodeURIComponent(name), { method: 'DELETE' });
+                const result = await res.json();
+                if (!result.success) throw new Error(result.error || res.statusText);
+                closeModal();
+                showToast('Deleted ' + name, 'success');
+                refreshDashboard();
+            } catch (err) {
+                showToast('Delete failed: ' + err.message, 'error');
+            }
+        }
+        
+        // Markdown preview: headings, lists, quotes, code, emphasis, and
+        // links. The text is escaped first, so content can't add markup.
+        const TICK = String.fromCharCode(96);
+        
+        function renderInline(text) {
+            const spans = [];
+            text = text.replace(new RegExp(TICK + '([^' + TICK + ']+)' + TICK, 'g'), (m, code) => {
+                spans.push('<code>' + code + '</code>');
+                return '\u0001' + (spans.length - 1) + '\u0001';
+            });
+            text = text
+                .replace(/\*\*(.+?)\*\*/g, '<strong>$1</strong>')
+                .replace(/\*(\S[^*]*?)\*/g, '<em>$1</em>')
+                .replace(/\[([^\]]+)\]\((https?:[^\s)"']+)\)/g, '<a href="$2" target="_blank" rel="noopener">$1</a>');
+            return text.replace(/\u0001(\d+)\u0001/g, (m, i) => spans[i]);
+        }
+        
+        function renderMarkdown(text) {
+            const fence = TICK.repeat(3);
+            const out = [];
+            let para = [], list = null, code = null;
+            const flushPara = () => {
+                if (para.length) out.push('<p>' + renderInline(para.join(' ')) + '</p>');
+                para = [];
+            };
+            const closeList = () => {
+                if (list) out.push('</' + list + '>');
+                list = null;
+            };
+            
+            for (const line of escapeHtml(text).split('\n')) {
+                if (code !== null) {
+                    if (line.trim().startsWith(fence)) {
+                        out.push('<pre><code>' + code.join('\n') + '</code></pre>');
+                        code = null;
+                    } else {
+                        code.push(line);
+                    }
+                    continue;
+                }
+                let m;
+                if (line.trim().startsWith(fence)) {
+                    flushPara(); closeList();
+                    code = [];
+                } else if (!line.trim()) {
+                    flushPara(); closeList();
+                } else if ((m = line.match(/^(#{1,6})\s+(.*)$/))) {
+                    flushPara(); closeList();
+                    out.push('<h' + m[1].length + '>' + renderInline(m[2]) + '</h' + m[1].length + '>');
+                } else if ((m = line.match(/^\s*(?:[-*+]|(\d+)\.)\s+(.*)$/))) {
+                    const kind = m[1] ? 'ol' : 'ul';
+                    flushPara();
+                    if (list !== kind) {
+                        closeList();
+                        out.push('<' + kind + '>');
+                        list = kind;
+                    }
+                    out.push('<li>' + renderInline(m[2]) + '</li>');
+                } else if ((m = line.match(/^&gt;\s?(.*)$/))) {
+                    flushPara(); closeList();
+                    out.push('<blockquote>' + renderInline(m[1]) + '</blockquote>');
+                } else {
+                    closeList();
+                    para.push(line.trim());
+                }
+            }
+            if (code !== null) out.push('<pre><code>' + code.join('\n') + '</code></pre>');
+            flushPara(); closeList();
+            return out.join('');
+        }
+        
+        // Sync
+        async function triggerSync() {
+            const btn = document.getElementById('syncBtn');
+            btn.disabled = true;
+            btn.textContent = 'Syncing...';
+            
+            try {
+                const res = await fetch('/api/sync', { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: '{}' });
+                const result = await res.json();
+                if (result.success) {
+                    showToast('Sync completed! Refreshing...', 'success');
+                    setTimeout(() => window.location.reload(), 1500);
+                } else {
+                    showToast('Sync failed: ' + (result.output || 'Unknown error'), 'error');
+                    btn.disabled = false;
+                    btn.textContent = 'Sync Now';
+                }
+            } catch (err) {
+                showToast('Sync error: ' + err.message, 'error');
+                btn.disabled = false;
+                btn.textContent = 'Sync Now';
+            }
+        }
+        
+        // Toast, optionally with an action button such as Undo, which
+        // stays up longer
+        let toastTimer = null;
+        function showToast(message, type = 'success', action = null) {
+            const toast = document.getElementById('toast');
+            const icon = document.getElementById('toastIcon');
+            const msg = document.getElementById('toastMessage');
+            const button = document.getElementById('toastAction');
+            
+            icon.textContent = type === 'success' ? '✓' : '✗';
+            msg.textContent = message;
+            button.hidden = !action;
+            button.textContent = action ? action.label : '';
+            button.onclick = action ? () => { toast.classList.remove('show'); action.run(); } : null;
+            toast.className = 'toast ' + type + ' show';
+            
+            clearTimeout(toastTimer);
+            toastTimer = setTimeout(() => { toast.classList.remove('show'); }, action ? 8000 : 3000);
+        }
+        
+        // Live updates: /ws says when patterns or stats changed on disk;
+        // re-render the page and swap in its [data-live] regions, keeping
+        // the search, filter, and open modal.
+        let refreshTimer = null;
+        async function refreshDashboard() {
+            try {
+                const res = await fetch('/');
+                if (!res.ok) return;
+                const doc = new DOMParser().parseFromString(await res.text(), 'text/html');
+                document.querySelectorAll('[data-live]').forEach(el => {
+                    const fresh = doc.querySelector('[data-live="' + el.dataset.live + '"]');
+                    if (fresh) el.replaceWith(fresh);
+                });
+                animateSparkline();
+                groupPatterns();
+                filterPatterns(search?.value?.toLowerCase() || '', getCurrentFilter());
+                renderSelection();
+            } catch (err) {
+                // Keep the current view; the next event retries.
+            }
+        }
+        
+        function connectLive(delay = 1000) {
+            const ws = new WebSocket((location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + '/ws');
+            ws.onopen = () => { delay = 1000; };
+            ws.onmessage = () => {
+                // Hooks write several files at once; refresh once per burst.
+                clearTimeout(refreshTimer);
+                refreshTimer = setTimeout(refreshDashboard, 300);
+            };
+            ws.onclose = () => setTimeout(() => connectLive(Math.min(delay * 2, 30000)), delay);
+        }
+        connectLive();
+        
+        // Utils
+        function escapeHtml(text) {
+            const div = document.createElement('div');
+            div.textContent = text;
+            return div.innerHTML;
+        }
+    </script>
+</body>
+</html>
+`
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func newTestDashboard(t *testing.T, apiOnly bool) *Mux {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	m := NewMux()
+	RegisterDashboard(m, DashboardOptions{
+		Store:   pattern.NewStore(t.TempDir()),
+		Version: "test",
+		Source:  func(string) (*pattern.SourceRef, error) { return nil, nil },
+		APIOnly: apiOnly,
+	})
+	return m
+}
+
+func TestDashboardRoutesGuardWrites(t *testing.T) {
+	m := newTestDashboard(t, false)
+	for _, path := range []string{"/", "/api/sync", "/api/patterns", "/api/v1/export/patterns.ndjson"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://evil.example")
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("cross-origin POST %s = %d, want 403", path, rec.Code)
+		}
+
+		req = httptest.NewRequest("POST", path, strings.NewReader("x"))
+		req.Header.Set("Content-Type", "text/plain")
+		rec = httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("text/plain POST %s = %d, want 415", path, rec.Code)
+		}
+	}
+}
+
+func TestDashboardRoutes(t *testing.T) {
+	tests := []struct {
+		apiOnly bool
+		path    string
+		want    int
+	}{
+		{false, "/", http.StatusOK},
+		{false, "/nope", http.StatusNotFound},
+		{false, "/source/missing", http.StatusOK},
+		{false, "/graph", http.StatusOK},
+		{true, "/", http.StatusNotFound},
+		{true, "/graph", http.StatusNotFound},
+		{true, "/api/patterns?where=tag:go", http.StatusOK},
+		{true, "/api/patterns?limit=x", http.StatusBadRequest},
+		{true, "/api/v1/graph", http.StatusOK},
+		{true, "/api/v1/graph?max=0", http.StatusBadRequest},
+		{true, "/api/v1/export/patterns.ndjson", http.StatusOK},
+		{true, "/api/v1/export/patterns.ndjson?limit=100000", http.StatusBadRequest},
+		{true, "/api/v1/export/patterns.ndjson?updated_since=yesterday", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		m := newTestDashboard(t, tt.apiOnly)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s (api only: %v) = %d, want %d: %s", tt.path, tt.apiOnly, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
+
+func TestGroupInjections(t *testing.T) {
+	now := time.Now()
+	explanations := []inject.Explanation{
+		{Session: "b", Time: now},
+		{Session: "a", Time: now.Add(-time.Minute)},
+		{Session: "b", Time: now.Add(-2 * time.Minute)},
+		{Session: "c", Time: now.Add(-3 * time.Minute)},
+	}
+	sessions := groupInjections(explanations, 2)
+	if len(sessions) != 2 || sessions[0].Session != "b" || sessions[1].Session != "a" {
+		t.Fatalf("sessions = %+v", sessions)
+	}
+	if len(sessions[0].Injections) != 2 || !sessions[0].Last.Equal(now) {
+		t.Errorf("session b = %+v", sessions[0])
+	}
+}
+
+func TestParseExportSince(t *testing.T) {
+	if _, err := ParseExportSince("2026-01-02"); err != nil {
+		t.Error(err)
+	}
+	if got, err := ParseExportSince("2026-01-02T03:04:05Z"); err != nil || got.Hour() != 3 {
+		t.Errorf("RFC 3339: %v, %v", got, err)
+	}
+	if _, err := ParseExportSince("last week"); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
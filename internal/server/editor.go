@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/export"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// NewPatternEditor returns the handler behind the dashboard's pattern
+// forms, mounted at /api/pattern/:
+//
+//	GET    /api/pattern/{name}
+//	POST   /api/pattern/{name}
+//	PUT    /api/pattern/{name}
+//	DELETE /api/pattern/{name}
+//
+// GET returns the pattern as an export.Record, which the detail view and
+// edit form render. POST and PUT take a PatternInput, with the name coming
+// from the path, and answer with the saved record in an APIResponse;
+// DELETE moves the pattern to the trash. The pattern's ID works in place
+// of its name except for POST. Writes are guarded like the REST API's.
+func NewPatternEditor(store *pattern.Store) http.Handler {
+	return GuardWrites(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ref := strings.TrimPrefix(r.URL.Path, "/api/pattern/")
+		if ref == "" || strings.Contains(ref, "/") {
+			writeJSON(w, http.StatusBadRequest, APIResponse{Error: "pattern name required"})
+			return
+		}
+		if r.Method == http.MethodPost {
+			createPattern(w, r, store, ref)
+			return
+		}
+
+		p, err := store.Resolve(ref)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, APIResponse{Error: "pattern not found: " + ref})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(editorRecord(p))
+
+		case http.MethodPut:
+			in, err := decodeInput(r)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+				return
+			}
+			if in.Name != "" && in.Name != p.Name {
+				writeJSON(w, http.StatusBadRequest, APIResponse{Error: "name can't be changed here; use 'mur learn rename'"})
+				return
+			}
+			if err := in.apply(p); err != nil {
+				writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+				return
+			}
+			if err := store.Update(p); err != nil {
+				writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: editorRecord(p)})
+
+		case http.MethodDelete:
+			if err := store.Delete(p.Name); err != nil {
+				writeJSON(w, http.StatusInternalServerError, APIResponse{Error: err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: map[string]string{"deleted": p.Name}})
+
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
+		}
+	}))
+}
+
+// createPattern handles POST /api/pattern/{name}.
+func createPattern(w http.ResponseWriter, r *http.Request, store *pattern.Store, name string) {
+	in, err := decodeInput(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	if in.Name != "" && in.Name != name {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: "name in body doesn't match the path"})
+		return
+	}
+	if store.Exists(name) {
+		writeJSON(w, http.StatusConflict, APIResponse{Error: "pattern already exists: " + name})
+		return
+	}
+
+	p := &pattern.Pattern{Name: name}
+	if err := in.apply(p); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	if err := store.Create(p); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, APIResponse{Success: true, Data: editorRecord(p)})
+}
+
+// editorRecord returns p with the JSON field names the dashboard reads.
+func editorRecord(p *pattern.Pattern) export.Record {
+	return export.Records([]pattern.Pattern{*p}, nil)[0]
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/core/export"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestPatternEditor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MUR_HOME", "")
+	store := pattern.NewStore(t.TempDir())
+	editor := NewPatternEditor(store)
+
+	code, resp := call(t, editor, "POST", "/api/pattern/go-errors", `{"description": "Error wrapping", "content": "Wrap errors with %w", "tags": ["go", "errors"]}`)
+	if code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", code, resp.Error)
+	}
+	if code, _ := call(t, editor, "POST", "/api/pattern/go-errors", `{"content": "again"}`); code != http.StatusConflict {
+		t.Errorf("duplicate create = %d", code)
+	}
+	if code, _ := call(t, editor, "POST", "/api/pattern/other", `{"name": "go-errors", "content": "x"}`); code != http.StatusBadRequest {
+		t.Errorf("mismatched name = %d", code)
+	}
+
+	// GET returns the pattern's record, by name or ID
+	p, err := store.Get("go-errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []string{"go-errors", p.ID} {
+		rec := httptest.NewRecorder()
+		editor.ServeHTTP(rec, httptest.NewRequest("GET", "/api/pattern/"+ref, nil))
+		var got export.Record
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.Name != "go-errors" || len(got.Tags) != 2 {
+			t.Errorf("GET %s = %d %s", ref, rec.Code, rec.Body)
+		}
+	}
+
+	// PUT replaces the tags and keeps fields it doesn't send
+	code, resp = call(t, editor, "PUT", "/api/pattern/go-errors", `{"content": "Wrap errors with %w.", "tags": ["go"]}`)
+	if code != http.StatusOK {
+		t.Fatalf("update = %d: %s", code, resp.Error)
+	}
+	p, _ = store.Get("go-errors")
+	if p.Content != "Wrap errors with %w." || p.Description != "Error wrapping" || len(p.Tags.Confirmed) != 1 {
+		t.Errorf("updated pattern = %+v", p)
+	}
+	if code, _ := call(t, editor, "PUT", "/api/pattern/go-errors", `{"name": "renamed", "content": "x"}`); code != http.StatusBadRequest {
+		t.Errorf("rename through PUT = %d", code)
+	}
+	if code, _ := call(t, editor, "PUT", "/api/pattern/go-errors", `{"content": "  "}`); code != http.StatusBadRequest {
+		t.Errorf("empty content = %d", code)
+	}
+
+	if code, _ := call(t, editor, "DELETE", "/api/pattern/go-errors", ""); code != http.StatusOK {
+		t.Errorf("delete = %d", code)
+	}
+	if !store.InTrash("go-errors") {
+		t.Error("deleted pattern not in trash")
+	}
+	if code, _ := call(t, editor, "DELETE", "/api/pattern/go-errors", ""); code != http.StatusNotFound {
+		t.Errorf("delete again = %d", code)
+	}
+}
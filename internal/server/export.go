@@ -0,0 +1,86 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/export"
+)
+
+// Export page sizes for /api/v1/export/patterns.ndjson.
+const (
+	exportDefaultLimit = 1000
+	exportMaxLimit     = 10000
+)
+
+// serveExport streams patterns as NDJSON for BI tools. The cursor for the
+// next page is in the X-Next-Cursor header and a Link rel="next" header;
+// both are absent on the last page.
+func (d *dashboard) serveExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := export.Options{
+		Fields: export.ParseFields(q.Get("fields")),
+		Cursor: q.Get("cursor"),
+		Limit:  exportDefaultLimit,
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > exportMaxLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", exportMaxLimit), http.StatusBadRequest)
+			return
+		}
+		opts.Limit = n
+	}
+	if v := q.Get("updated_since"); v != "" {
+		since, err := ParseExportSince(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Since = since
+	}
+	if err := opts.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := export.Load(d.store, d.tracker)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page, err := export.Select(records, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if page.Next != "" {
+		w.Header().Set("X-Next-Cursor", page.Next)
+		next := *r.URL
+		nq := next.Query()
+		nq.Set("cursor", page.Next)
+		next.RawQuery = nq.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+	}
+	_ = page.Write(w)
+}
+
+// ParseExportSince accepts RFC 3339 timestamps and plain dates.
+func ParseExportSince(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use 2006-01-02 or RFC 3339)", v)
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// Graph limits for /api/v1/graph.
+const graphMaxNodes = 2000
+
+// serveGraph returns the pattern graph as JSON. Query parameters: where
+// (the 'mur learn list' filter syntax), max (patterns shown individually;
+// the rest collapse into one node per domain), and min_shared (tags two
+// patterns must share to be linked, 0 for none).
+func (d *dashboard) serveGraph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	opts := pattern.GraphOptions{MaxNodes: pattern.DefaultGraphNodes, MinSharedTags: 1}
+	if v := params.Get("max"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > graphMaxNodes {
+			http.Error(w, fmt.Sprintf("max must be between 1 and %d", graphMaxNodes), http.StatusBadRequest)
+			return
+		}
+		opts.MaxNodes = n
+	}
+	if v := params.Get("min_shared"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "min_shared must be 0 or more", http.StatusBadRequest)
+			return
+		}
+		opts.MinSharedTags = n
+	}
+	q, err := pattern.ParseQuery(params.Get("where"), "", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	patterns, err := d.store.Query(q, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pattern.BuildGraph(patterns, opts))
+}
+
+// serveGraphPage shows the pattern graph. The layout runs in the browser
+// against /api/v1/graph, passing its query string through.
+func serveGraphPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = graphTemplate.Execute(w, r.URL.Query().Get("where"))
+}
+
+var graphTemplate = template.Must(template.New("graph").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Pattern graph</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 0; color: #222; }
+header { padding: 0.75rem 1rem; border-bottom: 1px solid #eee; display: flex; gap: 1rem; align-items: center; flex-wrap: wrap; }
+header form { display: flex; gap: 0.5rem; align-items: center; }
+header input[name=where] { width: 22rem; }
+.meta { color: #666; font-size: 0.9rem; }
+#graph { display: block; width: 100vw; height: calc(100vh - 3.5rem); cursor: grab; }
+#info { position: fixed; right: 1rem; top: 4.5rem; width: 18rem; background: #fff; border: 1px solid #ddd; border-radius: 0.5rem; padding: 0.75rem; font-size: 0.9rem; display: none; }
+#legend { position: fixed; left: 1rem; bottom: 1rem; background: rgba(255,255,255,0.9); font-size: 0.8rem; padding: 0.5rem; border-radius: 0.5rem; }
+#legend span { display: inline-block; margin-right: 0.75rem; }
+</style>
+</head>
+<body>
+<header>
+<a href="/">← Dashboard</a>
+<form>
+<input name="where" value="{{.}}" placeholder="filter, e.g. domain=go and not status=archived">
+<label>max <input name="max" type="number" min="1" value="300" style="width:4.5rem"></label>
+<label>shared tags <input name="min_shared" type="number" min="0" value="1" style="width:3rem"></label>
+<button>Apply</button>
+</form>
+<span class="meta" id="summary">Loading…</span>
+</header>
+<canvas id="graph"></canvas>
+<div id="info"></div>
+<div id="legend">
+<span>── related</span><span style="color:#6366f1">⋯ supersedes</span><span style="color:#dc2626">── conflicts</span><span style="color:#bbb">── shared tags</span><span>◯ orphan</span><span>size = usage, color = domain</span>
+</div>
+<script>
+(function () {
+  const params = new URLSearchParams(location.search);
+  const form = document.querySelector("form");
+  for (const k of ["max", "min_shared"]) if (params.has(k)) form.elements[k].value = params.get(k);
+
+  const canvas = document.getElementById("graph");
+  const ctx = canvas.getContext("2d");
+  const info = document.getElementById("info");
+  let nodes = [], edges = [], byId = {};
+  let view = { x: 0, y: 0, k: 1 }, drag = null, hover = null, alpha = 1;
+
+  function color(domain) {
+    let h = 0;
+    for (const c of domain) h = (h * 31 + c.charCodeAt(0)) % 360;
+    return "hsl(" + h + ", 65%, 55%)";
+  }
+  function radius(n) { return 4 + Math.sqrt(n.usage) * 2 + (n.kind === "cluster" ? 6 : 0); }
+
+  function resize() {
+    canvas.width = canvas.clientWidth * devicePixelRatio;
+    canvas.height = canvas.clientHeight * devicePixelRatio;
+  }
+
+  fetch("/api/v1/graph?" + params.toString())
+    .then(r => r.ok ? r.json() : r.text().then(t => Promise.reject(t)))
+    .then(g => {
+      nodes = g.nodes;
+      edges = g.edges.filter(e => e.source !== e.target);
+      nodes.forEach((n, i) => {
+        const a = i * 2.399963;
+        const d = 10 * Math.sqrt(i + 1);
+        n.x = Math.cos(a) * d; n.y = Math.sin(a) * d; n.vx = 0; n.vy = 0;
+        byId[n.id] = n;
+      });
+      let summary = g.total + " patterns, " + edges.length + " links, " + g.orphans + " orphans";
+      if (g.collapsed) summary += " · " + g.collapsed + " less used grouped by domain (click a group to expand)";
+      document.getElementById("summary").textContent = summary;
+      resize();
+      view.x = canvas.width / 2; view.y = canvas.height / 2;
+      requestAnimationFrame(tick);
+    })
+    .catch(err => { document.getElementById("summary").textContent = "Error: " + err; });
+
+  // Force simulation: repulsion between all nodes (grid-bucketed for large
+  // graphs), springs along edges, and gravity toward the center
+  function step() {
+    const cell = 120, grid = new Map();
+    for (const n of nodes) {
+      const key = Math.floor(n.x / cell) + "," + Math.floor(n.y / cell);
+      if (!grid.has(key)) grid.set(key, []);
+      grid.get(key).push(n);
+    }
+    for (const n of nodes) {
+      const gx = Math.floor(n.x / cell), gy = Math.floor(n.y / cell);
+      for (let dx = -1; dx <= 1; dx++) for (let dy = -1; dy <= 1; dy++) {
+        for (const m of grid.get((gx + dx) + "," + (gy + dy)) || []) {
+          if (m === n) continue;
+          let x = n.x - m.x, y = n.y - m.y, d2 = x * x + y * y || 0.01;
+          const f = 800 / d2;
+          n.vx += x * f * alpha; n.vy += y * f * alpha;
+        }
+      }
+      n.vx -= n.x * 0.002 * alpha; n.vy -= n.y * 0.002 * alpha;
+    }
+    for (const e of edges) {
+      const a = byId[e.source], b = byId[e.target];
+      if (!a || !b) continue;
+      const x = b.x - a.x, y = b.y - a.y, d = Math.sqrt(x * x + y * y) || 1;
+      const rest = e.kind === "tags" ? 90 : 60;
+      const f = (d - rest) / d * 0.02 * alpha * Math.min(e.weight, 3);
+      a.vx += x * f; a.vy += y * f; b.vx -= x * f; b.vy -= y * f;
+    }
+    for (const n of nodes) {
+      if (n === drag) continue;
+      n.vx *= 0.6; n.vy *= 0.6;
+      n.x += n.vx; n.y += n.vy;
+    }
+    alpha = Math.max(alpha * 0.995, 0.02);
+  }
+
+  function draw() {
+    ctx.setTransform(1, 0, 0, 1, 0, 0);
+    ctx.clearRect(0, 0, canvas.width, canvas.height);
+    ctx.setTransform(view.k, 0, 0, view.k, view.x, view.y);
+    for (const e of edges) {
+      const a = byId[e.source], b = byId[e.target];
+      if (!a || !b) continue;
+      ctx.beginPath();
+      ctx.setLineDash(e.kind === "supersedes" ? [4, 3] : []);
+      ctx.strokeStyle = { related: "#555", supersedes: "#6366f1", conflicts: "#dc2626", tags: "#ccc" }[e.kind] || "#999";
+      ctx.lineWidth = Math.min(e.weight, 4) / view.k;
+      ctx.moveTo(a.x, a.y); ctx.lineTo(b.x, b.y); ctx.stroke();
+    }
+    ctx.setLineDash([]);
+    for (const n of nodes) {
+      const r = radius(n);
+      ctx.beginPath();
+      ctx.arc(n.x, n.y, r, 0, 2 * Math.PI);
+      ctx.fillStyle = n.orphan ? "#fff" : color(n.domain);
+      ctx.globalAlpha = n.status && n.status !== "active" ? 0.4 : 1;
+      ctx.fill();
+      ctx.lineWidth = (n.kind === "cluster" ? 3 : 1.5) / view.k;
+      ctx.strokeStyle = n.orphan ? color(n.domain) : "#fff";
+      ctx.stroke();
+      ctx.globalAlpha = 1;
+      if (n === hover || n.kind === "cluster" || view.k > 1.5) {
+        ctx.fillStyle = "#222";
+        ctx.font = (11 / view.k) + "px sans-serif";
+        ctx.fillText(n.label, n.x + r + 2, n.y + 4 / view.k);
+      }
+    }
+  }
+
+  function tick() {
+    step(); draw();
+    requestAnimationFrame(tick);
+  }
+
+  function toGraph(ev) {
+    const b = canvas.getBoundingClientRect();
+    return {
+      x: ((ev.clientX - b.left) * devicePixelRatio - view.x) / view.k,
+      y: ((ev.clientY - b.top) * devicePixelRatio - view.y) / view.k,
+    };
+  }
+  function nodeAt(p) {
+    for (let i = nodes.length - 1; i >= 0; i--) {
+      const n = nodes[i], r = radius(n) + 2;
+      if ((n.x - p.x) ** 2 + (n.y - p.y) ** 2 <= r * r) return n;
+    }
+    return null;
+  }
+
+  function show(n) {
+    if (n.kind === "cluster") {
+      const where = new URLSearchParams(location.search);
+      where.set("where", "domain=" + n.domain);
+      location.search = where.toString();
+      return;
+    }
+    const esc = s => String(s).replace(/[&<>"]/g, c => ({ "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;" })[c]);
+    const links = edges.filter(e => e.source === n.id || e.target === n.id)
+      .map(e => esc(e.kind) + ": " + esc(e.source === n.id ? e.target : e.source));
+    info.innerHTML = "<strong>" + esc(n.label) + "</strong><br>" +
+      "<span class=meta>" + esc(n.domain) + (n.status ? " · " + esc(n.status) : "") + " · used " + n.usage + "×" +
+      (n.effectiveness ? " · " + Math.round(n.effectiveness * 100) + "% effective" : "") + "</span>" +
+      (n.tags ? "<p>" + n.tags.map(esc).join(", ") + "</p>" : "") +
+      (links.length ? "<p>" + links.join("<br>") + "</p>" : "<p class=meta>Orphan: no relations or shared tags</p>") +
+      "<p><a href=\"/source/" + encodeURIComponent(n.id) + "\">Source</a> · <a href=\"/api/pattern/" + encodeURIComponent(n.id) + "\">JSON</a></p>";
+    info.style.display = "block";
+  }
+
+  let pan = null, moved = false;
+  canvas.addEventListener("mousedown", ev => {
+    moved = false;
+    const n = nodeAt(toGraph(ev));
+    if (n) drag = n; else pan = { x: ev.clientX, y: ev.clientY };
+  });
+  canvas.addEventListener("mousemove", ev => {
+    const p = toGraph(ev);
+    if (drag) { drag.x = p.x; drag.y = p.y; alpha = Math.max(alpha, 0.3); moved = true; return; }
+    if (pan) {
+      view.x += (ev.clientX - pan.x) * devicePixelRatio; view.y += (ev.clientY - pan.y) * devicePixelRatio;
+      pan = { x: ev.clientX, y: ev.clientY }; moved = true; return;
+    }
+    hover = nodeAt(p);
+    canvas.style.cursor = hover ? "pointer" : "grab";
+  });
+  addEventListener("mouseup", ev => {
+    if (!moved) {
+      const n = drag || (ev.target === canvas && nodeAt(toGraph(ev)));
+      if (n) show(n); else if (ev.target === canvas) info.style.display = "none";
+    }
+    drag = null; pan = null;
+  });
+  canvas.addEventListener("wheel", ev => {
+    ev.preventDefault();
+    const b = canvas.getBoundingClientRect();
+    const mx = (ev.clientX - b.left) * devicePixelRatio, my = (ev.clientY - b.top) * devicePixelRatio;
+    const k = Math.min(Math.max(view.k * Math.exp(-ev.deltaY * 0.001), 0.1), 8);
+    view.x = mx - (mx - view.x) * k / view.k; view.y = my - (my - view.y) * k / view.k;
+    view.k = k;
+  }, { passive: false });
+  addEventListener("resize", resize);
+})();
+</script>
+</body>
+</html>
+`))
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os/exec"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/mur-run/mur-core/internal/config"
 	"github.com/mur-run/mur-core/internal/learn"
 	"github.com/mur-run/mur-core/internal/stats"
+	mursync "github.com/mur-run/mur-core/internal/sync"
 )
 
 // APIResponse is the standard API response format.
@@ -187,28 +189,50 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // SyncResult represents the result of a sync operation.
 type SyncResult struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Targets []SyncTargetResult `json:"targets,omitempty"`
+}
+
+// SyncTargetResult is the outcome for one AI tool.
+type SyncTargetResult struct {
+	Target  string `json:"target"`
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 }
 
-// handleSync triggers sync to AI CLI tools.
+// handleSync syncs patterns and skills to AI CLI tools, in process.
 func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, APIResponse{Error: "method not allowed"})
 		return
 	}
 
-	// Run mur sync command
-	cmd := exec.Command("mur", "sync")
-	output, err := cmd.CombinedOutput()
-
-	result := SyncResult{
-		Success: err == nil,
-		Message: string(output),
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
 	}
 
+	results, err := mursync.SyncLocal(r.Context(), cfg)
 	if err != nil {
-		result.Message = err.Error() + ": " + string(output)
+		result := SyncResult{Message: err.Error()}
+		writeJSON(w, http.StatusOK, APIResponse{Success: false, Data: result})
+		return
+	}
+
+	result := SyncResult{Success: true}
+	failed := 0
+	for _, t := range results {
+		result.Targets = append(result.Targets, SyncTargetResult{Target: t.Target, Success: t.Success, Message: t.Message})
+		if !t.Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		result.Success = false
+		result.Message = fmt.Sprintf("%d of %d targets failed", failed, len(results))
+	} else {
+		result.Message = fmt.Sprintf("Synced %d targets", len(results))
 	}
 
 	writeJSON(w, http.StatusOK, APIResponse{Success: result.Success, Data: result})
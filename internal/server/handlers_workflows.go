@@ -22,9 +22,13 @@ func (s *Server) handleWorkflows(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleWorkflowByID dispatches /api/workflows/{id} requests.
+// handleWorkflowByID dispatches /api/workflows/{id} and
+// /api/v1/workflows/{id} requests.
 func (s *Server) handleWorkflowByID(w http.ResponseWriter, r *http.Request) {
-	id := strings.TrimPrefix(r.URL.Path, "/api/workflows/")
+	id, ok := strings.CutPrefix(r.URL.Path, "/api/v1/workflows/")
+	if !ok {
+		id = strings.TrimPrefix(r.URL.Path, "/api/workflows/")
+	}
 
 	// Check for sub-routes: /api/workflows/{id}/publish, /api/workflows/{id}/export
 	if strings.Contains(id, "/") {
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/stats"
+)
+
+// InjectionSession groups the recorded injections of one AI tool session.
+type InjectionSession struct {
+	Session    string               `json:"session"`
+	Last       time.Time            `json:"last"`
+	Injections []inject.Explanation `json:"injections"`
+}
+
+// groupInjections groups explanations (newest first) by session, keeping
+// sessions in order of their most recent injection.
+func groupInjections(explanations []inject.Explanation, maxSessions int) []InjectionSession {
+	var sessions []InjectionSession
+	index := make(map[string]int)
+	for _, e := range explanations {
+		i, ok := index[e.Session]
+		if !ok {
+			if len(sessions) >= maxSessions {
+				continue
+			}
+			i = len(sessions)
+			index[e.Session] = i
+			sessions = append(sessions, InjectionSession{Session: e.Session, Last: e.Time})
+		}
+		sessions[i].Injections = append(sessions[i].Injections, e)
+	}
+	return sessions
+}
+
+// serveInjections returns recent injection explanations grouped by session.
+func serveInjections(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	explanations, err := inject.RecentExplanations(limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sessions := groupInjections(explanations, limit)
+	if sessions == nil {
+		sessions = []InjectionSession{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(sessions)
+}
+
+// serveInjectionsPage shows the most recent sessions, with a drill-down
+// into why each injection chose its patterns.
+func serveInjectionsPage(w http.ResponseWriter, r *http.Request) {
+	explanations, err := inject.RecentExplanations(200)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = injectionsTemplate.Execute(w, groupInjections(explanations, 10))
+}
+
+var injectionsTemplate = template.Must(template.New("injections").Funcs(template.FuncMap{
+	"when":  func(t time.Time) string { return t.In(stats.DisplayLocation()).Format("Jan 2 15:04:05") },
+	"score": func(f float64) string { return fmt.Sprintf("%.2f", f) },
+	"join":  strings.Join,
+	"count": func(e inject.Explanation) int { return len(e.Injected()) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Recent injections</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; max-width: 900px; margin: 2rem auto; color: #222; }
+.meta { color: #666; font-size: 0.9rem; }
+details { margin: 0.5rem 0; }
+details.session { border-left: 3px solid #6366f1; padding: 0.25rem 1rem; }
+summary { cursor: pointer; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1rem; font-size: 0.9rem; }
+th, td { text-align: left; padding: 0.25rem 0.5rem; border-bottom: 1px solid #eee; vertical-align: top; }
+tr.skipped td { color: #999; }
+</style>
+</head>
+<body>
+<p><a href="/">← Dashboard</a></p>
+<h1>Recent injections</h1>
+<p class="meta">Why mur added each pattern to the AI's context. Also: <code>mur context --explain-last</code></p>
+{{range $i, $s := .}}
+<details class="session"{{if eq $i 0}} open{{end}}>
+<summary><strong>{{if $s.Session}}Session {{$s.Session}}{{else}}No session id{{end}}</strong>
+<span class="meta">· {{len $s.Injections}} injection(s) · last {{when $s.Last}}</span></summary>
+{{range $s.Injections}}
+<details>
+<summary>{{when .Time}} · {{.Command}} · {{count .}} injected{{if .Project.Name}} · {{.Project.Name}}{{end}}</summary>
+<p class="meta">
+{{if .Project.Type}}Project type {{.Project.Type}}{{end}}{{if .Project.Languages}} · languages {{join .Project.Languages ", "}}{{end}}{{if .Project.Frameworks}} · frameworks {{join .Project.Frameworks ", "}}{{end}}<br>
+Scoring {{.Method}}, up to {{.Max}} relevant + {{.Pinned}} pinned{{if .Prompt}}<br>Prompt: {{.Prompt}}{{end}}
+</p>
+<table>
+<tr><th>Pattern</th><th>Score</th><th>Matched</th><th>Decision</th></tr>
+{{range .Patterns}}
+<tr{{if not .Injected}} class="skipped"{{end}}>
+<td>{{if .Pinned}}📌 {{end}}{{.Name}}</td>
+<td>{{if .Score}}{{score .Score}}{{end}}</td>
+<td>{{join .Matched ", "}}</td>
+<td>{{if .Injected}}✓{{else}}✗{{end}} {{.Reason}}</td>
+</tr>
+{{end}}
+</table>
+</details>
+{{end}}
+</details>
+{{else}}
+<p>No injections recorded yet. They are recorded each time a hook runs <code>mur context</code> or <code>mur search --inject</code>.</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Live update event types sent over /ws.
+const (
+	EventPatterns = "patterns.changed"
+	EventStats    = "stats.changed"
+)
+
+// liveWriteTimeout bounds a write to one browser, so a stalled client
+// can't hold up the others.
+const liveWriteTimeout = 5 * time.Second
+
+// LiveEvent is a message pushed to dashboard browsers when something they
+// show changed on disk.
+type LiveEvent struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+}
+
+// Live pushes change events to dashboard browsers over a WebSocket, so
+// patterns extracted by hooks and new usage stats appear without a
+// reload. Like 'mur learn watch', it polls: each watched path is
+// fingerprinted by the names, sizes, and modification times below it.
+type Live struct {
+	interval time.Duration
+	watches  []liveWatch
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*websocket.Conn]bool
+}
+
+type liveWatch struct {
+	event string
+	path  string
+	last  uint64
+}
+
+// NewLive creates a live update hub that polls every interval (default
+// 2s). Add paths with Watch, then start it with Run.
+func NewLive(interval time.Duration) *Live {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	return &Live{
+		interval: interval,
+		clients:  make(map[*websocket.Conn]bool),
+		// The default CheckOrigin rejects cross-origin pages.
+		upgrader: websocket.Upgrader{},
+	}
+}
+
+// Watch sends event whenever the file or directory at path changes.
+func (l *Live) Watch(event, path string) {
+	l.watches = append(l.watches, liveWatch{event: event, path: path, last: fingerprint(path)})
+}
+
+// Run polls the watched paths until ctx is done.
+func (l *Live) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			l.closeAll()
+			return
+		case <-ticker.C:
+			l.poll()
+		}
+	}
+}
+
+// poll broadcasts an event for each watched path that changed since the
+// last poll.
+func (l *Live) poll() {
+	for i := range l.watches {
+		w := &l.watches[i]
+		if fp := fingerprint(w.path); fp != w.last {
+			w.last = fp
+			l.Broadcast(LiveEvent{Type: w.event, Time: time.Now().UTC()})
+		}
+	}
+}
+
+// fingerprint hashes the names, sizes, and modification times of path and
+// everything below it. A missing path has fingerprint 0.
+func fingerprint(path string) uint64 {
+	if _, err := os.Stat(path); err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		_, _ = h.Write([]byte(p))
+		var buf [16]byte
+		binary.LittleEndian.PutUint64(buf[:8], uint64(info.Size()))
+		binary.LittleEndian.PutUint64(buf[8:], uint64(info.ModTime().UnixNano()))
+		_, _ = h.Write(buf[:])
+		return nil
+	})
+	return h.Sum64()
+}
+
+// Broadcast sends ev to every connected browser, dropping the ones that
+// can't receive it.
+func (l *Live) Broadcast(ev LiveEvent) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for conn := range l.clients {
+		_ = conn.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			conn.Close()
+			delete(l.clients, conn)
+		}
+	}
+}
+
+// Clients returns the number of connected browsers.
+func (l *Live) Clients() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// ServeHTTP upgrades a /ws request and keeps the connection until the
+// browser goes away. Browsers only listen; anything they send is ignored.
+func (l *Live) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("websocket upgrade: %v", err)
+		return
+	}
+	l.mu.Lock()
+	l.clients[conn] = true
+	l.mu.Unlock()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	l.mu.Lock()
+	delete(l.clients, conn)
+	l.mu.Unlock()
+	conn.Close()
+}
+
+func (l *Live) closeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for conn := range l.clients {
+		conn.Close()
+		delete(l.clients, conn)
+	}
+}
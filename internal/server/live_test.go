@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestLivePushesChanges(t *testing.T) {
+	dir := t.TempDir()
+	statsFile := filepath.Join(t.TempDir(), "stats.jsonl")
+
+	live := NewLive(10 * time.Millisecond)
+	live.Watch(EventPatterns, dir)
+	live.Watch(EventStats, statsFile)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go live.Run(ctx)
+
+	srv := httptest.NewServer(live)
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for live.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	next := func() string {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ev LiveEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			t.Fatal(err)
+		}
+		return ev.Type
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go-errors.yaml"), []byte("name: go-errors"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev != EventPatterns {
+		t.Errorf("event = %s, want %s", ev, EventPatterns)
+	}
+
+	if err := os.WriteFile(statsFile, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev != EventStats {
+		t.Errorf("event = %s, want %s", ev, EventStats)
+	}
+}
+
+func TestLiveRejectsCrossOrigin(t *testing.T) {
+	srv := httptest.NewServer(NewLive(0))
+	defer srv.Close()
+
+	header := map[string][]string{"Origin": {"https://evil.example"}}
+	if _, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header); err == nil {
+		t.Error("cross-origin WebSocket was accepted")
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import "net/http"
+
+// Mux routes requests like http.ServeMux, but every handler registered
+// on it goes through GuardWrites, so no route can opt out of the
+// same-origin and JSON checks for writes.
+type Mux struct {
+	mux *http.ServeMux
+}
+
+// NewMux returns an empty Mux.
+func NewMux() *Mux {
+	return &Mux{mux: http.NewServeMux()}
+}
+
+// Handle registers h, behind GuardWrites, for pattern.
+func (m *Mux) Handle(pattern string, h http.Handler) {
+	m.mux.Handle(pattern, GuardWrites(h))
+}
+
+// HandleFunc registers f, behind GuardWrites, for pattern.
+func (m *Mux) HandleFunc(pattern string, f func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(f))
+}
+
+// ServeHTTP dispatches r to the handler registered for its path.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mux.ServeHTTP(w, r)
+}
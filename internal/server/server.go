@@ -6,6 +6,9 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
 )
 
 // Config holds server configuration.
@@ -17,6 +20,10 @@ type Config struct {
 type Server struct {
 	config Config
 	mux    *http.ServeMux
+
+	// store and tracker back the /api/v1/ routes (see NewAPI).
+	store   *pattern.Store
+	tracker *inject.Tracker
 }
 
 // New creates a new server with the given config.
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShareLink is a read-only, expiring link to one pattern. The pattern is
+// captured (already redacted) when the link is created, so later edits
+// are not visible through it and nothing unredacted is ever served.
+type ShareLink struct {
+	Token       string     `json:"token"`
+	Pattern     string     `json:"pattern"`
+	PatternID   string     `json:"pattern_id,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Content     string     `json:"content"`
+	Tags        []string   `json:"tags,omitempty"`
+	Redactions  int        `json:"redactions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	Views       int        `json:"views"`
+	LastViewed  *time.Time `json:"last_viewed,omitempty"`
+}
+
+// Active reports whether the link can still be opened at now.
+func (l *ShareLink) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// ShareStore keeps share links in a JSON file and appends every access
+// to a log next to it.
+type ShareStore struct {
+	path    string
+	logPath string
+	mu      sync.Mutex
+}
+
+// NewShareStore returns a store keeping links in dir/share-links.json and
+// the access log in dir/share-access.log.
+func NewShareStore(dir string) *ShareStore {
+	return &ShareStore{
+		path:    filepath.Join(dir, "share-links.json"),
+		logPath: filepath.Join(dir, "share-access.log"),
+	}
+}
+
+// LogPath returns the path of the access log.
+func (s *ShareStore) LogPath() string { return s.logPath }
+
+// List returns all links, including expired and revoked ones.
+func (s *ShareStore) List() ([]ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *ShareStore) load() ([]ShareLink, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var links []ShareLink
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	return links, nil
+}
+
+func (s *ShareStore) save(links []ShareLink) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Links are bearer secrets: readable only by the user, written atomically
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Create stores link with a new token and returns it. Links that expired
+// more than a week ago are dropped on the way.
+func (s *ShareStore) Create(link ShareLink) (ShareLink, error) {
+	token, err := NewToken()
+	if err != nil {
+		return ShareLink{}, err
+	}
+	link.Token = token
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.load()
+	if err != nil {
+		return ShareLink{}, err
+	}
+	kept := links[:0]
+	for _, l := range links {
+		if link.CreatedAt.Sub(l.ExpiresAt) < 7*24*time.Hour {
+			kept = append(kept, l)
+		}
+	}
+	if err := s.save(append(kept, link)); err != nil {
+		return ShareLink{}, err
+	}
+	return link, nil
+}
+
+// ErrShareNotFound is returned by Revoke when nothing matches.
+var ErrShareNotFound = errors.New("share link not found")
+
+// Revoke revokes the active link with token ref (or a prefix of at least
+// 8 characters), or every active link to the pattern named ref. It
+// returns the revoked links.
+func (s *ShareStore) Revoke(ref string, now time.Time) ([]ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var revoked []ShareLink
+	for i := range links {
+		l := &links[i]
+		if !l.Active(now) {
+			continue
+		}
+		byToken := len(ref) >= 8 && strings.HasPrefix(l.Token, ref)
+		if byToken || l.Pattern == ref || (l.PatternID != "" && l.PatternID == ref) {
+			t := now
+			l.RevokedAt = &t
+			revoked = append(revoked, *l)
+		}
+	}
+	if len(revoked) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrShareNotFound, ref)
+	}
+	return revoked, s.save(links)
+}
+
+// open returns the active link with token and counts the view.
+func (s *ShareStore) open(token string, now time.Time) (*ShareLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		l := &links[i]
+		if subtle.ConstantTimeCompare([]byte(l.Token), []byte(token)) != 1 {
+			continue
+		}
+		if !l.Active(now) {
+			return nil, nil
+		}
+		t := now
+		l.Views++
+		l.LastViewed = &t
+		if err := s.save(links); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+	return nil, nil
+}
+
+// logAccess appends one tab-separated line per request: time, token
+// prefix, pattern, HTTP status, client address and user agent.
+func (s *ShareStore) logAccess(now time.Time, token, pattern string, status int, r *http.Request) {
+	if len(token) > 8 {
+		token = token[:8]
+	}
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ua := strings.NewReplacer("\t", " ", "\n", " ").Replace(r.UserAgent())
+	line := fmt.Sprintf("%s\t%s\t%s\t%d\t%s\t%s\n", now.UTC().Format(time.RFC3339), token, pattern, status, addr, ua)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.WriteString(line)
+}
+
+// ShareHandler serves share links at /s/{token} as a read-only HTML page.
+// It must be mounted outside RequireToken: the link's token is the only
+// credential. Unknown, expired and revoked links all answer 404.
+func ShareHandler(store *ShareStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		token := strings.TrimPrefix(r.URL.Path, "/s/")
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+
+		link, err := store.open(token, now)
+		if err != nil {
+			store.logAccess(now, token, "", http.StatusInternalServerError, r)
+			http.Error(w, "share links unavailable", http.StatusInternalServerError)
+			return
+		}
+		if token == "" || link == nil {
+			store.logAccess(now, token, "", http.StatusNotFound, r)
+			http.Error(w, "This link has expired or was revoked.", http.StatusNotFound)
+			return
+		}
+		store.logAccess(now, token, link.Pattern, http.StatusOK, r)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		_ = shareTemplate.Execute(w, link)
+	})
+}
+
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Pattern}} · mur</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 760px; margin: 40px auto; padding: 0 20px; color: #1f2328; }
+h1 { font-size: 1.6em; margin-bottom: 4px; }
+.desc { color: #57606a; margin-top: 0; }
+.tag { display: inline-block; background: #eef1f4; border-radius: 10px; padding: 1px 8px; margin-right: 4px; font-size: 0.85em; }
+pre { background: #f6f8fa; border-radius: 6px; padding: 16px; white-space: pre-wrap; word-wrap: break-word; }
+footer { color: #8c959f; font-size: 0.85em; margin-top: 24px; }
+</style>
+</head>
+<body>
+<h1>{{.Pattern}}</h1>
+{{if .Description}}<p class="desc">{{.Description}}</p>{{end}}
+{{range .Tags}}<span class="tag">{{.}}</span>{{end}}
+<pre>{{.Content}}</pre>
+<footer>Shared read-only from mur · expires {{.ExpiresAt.Format "2006-01-02 15:04 MST"}}</footer>
+</body>
+</html>
+`))
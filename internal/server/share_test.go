@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareHandler(t *testing.T) {
+	dir := t.TempDir()
+	store := NewShareStore(dir)
+	now := time.Now()
+
+	link, err := store.Create(ShareLink{
+		Pattern:   "retry-with-backoff",
+		Content:   "Retry <b>with</b> backoff",
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := store.Create(ShareLink{
+		Pattern:   "old",
+		Content:   "old",
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := ShareHandler(store)
+	get := func(token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/s/"+token, nil))
+		return rec
+	}
+
+	rec := get(link.Token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("active link: status %d", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "Retry &lt;b&gt;with&lt;/b&gt; backoff") {
+		t.Errorf("content not escaped in page:\n%s", body)
+	}
+	for _, token := range []string{"", "nope", expired.Token} {
+		if rec := get(token); rec.Code != http.StatusNotFound {
+			t.Errorf("token %q: status %d, want 404", token, rec.Code)
+		}
+	}
+
+	if _, err := store.Revoke(link.Token[:8], now); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(link.Token); rec.Code != http.StatusNotFound {
+		t.Errorf("revoked link: status %d, want 404", rec.Code)
+	}
+	if _, err := store.Revoke("retry-with-backoff", now); !errors.Is(err, ErrShareNotFound) {
+		t.Errorf("revoking twice: err = %v, want ErrShareNotFound", err)
+	}
+
+	links, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 || links[0].Views != 1 || links[0].RevokedAt == nil {
+		t.Errorf("links = %+v, want first viewed once and revoked", links)
+	}
+
+	log, err := os.ReadFile(store.LogPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(log), "\n"); lines != 5 {
+		t.Errorf("access log has %d lines, want 5:\n%s", lines, log)
+	}
+}
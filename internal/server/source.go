@@ -0,0 +1,73 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+// serveSource shows the session excerpt a pattern was extracted from
+// (see 'mur learn source').
+func (d *dashboard) serveSource(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/source/")
+	ref, err := d.source(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	data := struct {
+		Name    string
+		Ref     *pattern.SourceRef
+		Excerpt *learn.SourceExcerpt
+	}{Name: name, Ref: ref}
+	if ref != nil {
+		if data.Excerpt, err = learn.ReadSource(ref, 3); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = sourceTemplate.Execute(w, data)
+}
+
+var sourceTemplate = template.Must(template.New("source").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Source of {{.Name}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; max-width: 900px; margin: 2rem auto; color: #222; }
+.meta { color: #666; font-size: 0.9rem; }
+.entry { border-left: 3px solid #ddd; margin: 1rem 0; padding: 0.25rem 1rem; }
+.entry.match { border-color: #6366f1; background: #f5f5ff; }
+.who { font-size: 0.8rem; color: #666; }
+pre { white-space: pre-wrap; word-break: break-word; margin: 0.25rem 0; }
+</style>
+</head>
+<body>
+<p><a href="/">← Dashboard</a></p>
+<h1>{{.Name}}</h1>
+{{if not .Ref}}
+<p>No source recorded. Only patterns extracted from sessions link back to them.</p>
+{{else}}
+<p class="meta">Session {{.Ref.Session}} · {{if .Excerpt.Path}}{{.Excerpt.Path}}{{else}}{{.Ref.Path}}{{end}}{{if .Ref.Line}}:{{.Ref.Line}}{{end}}</p>
+{{if .Excerpt.Note}}<p class="meta">{{.Excerpt.Note}}</p>{{end}}
+{{if .Excerpt.Found}}
+{{range .Excerpt.Entries}}
+<div class="entry{{if .Match}} match{{end}}">
+<div class="who">line {{.Line}} · {{.Role}}{{if .Subagent}} (subagent){{end}}</div>
+<pre>{{.Text}}</pre>
+</div>
+{{end}}
+{{else if .Ref.Excerpt}}
+<h3>Saved excerpt</h3>
+<pre>{{.Ref.Excerpt}}</pre>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"strings"
@@ -176,7 +177,7 @@ func DefaultSkillsOutputDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "skills"), nil
+	return filepath.Join(config.DataDir(home), "skills"), nil
 }
 
 // --- internal helpers ---
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/mur-run/mur-core/internal/config"
+	murlog "github.com/mur-run/mur-core/internal/log"
 )
 
 // LLMProvider sends a prompt to an LLM and returns the completion text.
@@ -18,6 +19,23 @@ type LLMProvider interface {
 	Complete(prompt string) (string, error)
 }
 
+// APIError is a non-200 response from an LLM API.
+type APIError struct {
+	Provider string // anthropic | openai | ollama | gemini
+	Status   int
+	Body     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (%d): %s", e.Provider, e.Status, e.Body)
+}
+
+// Temporary reports whether the request may succeed if retried: the API
+// was rate limited (429) or overloaded (5xx).
+func (e *APIError) Temporary() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
 // fallbackProvider wraps a primary and fallback LLMProvider. If the primary
 // fails, it automatically retries with the fallback provider.
 type fallbackProvider struct {
@@ -30,6 +48,7 @@ func (f *fallbackProvider) Complete(prompt string) (string, error) {
 	result, err := f.primary.Complete(prompt)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "  ⚠ %s failed (%v), falling back...\n", f.primaryName, err)
+		murlog.For("llm").Warn("provider failed, falling back", "provider", f.primaryName, "err", err)
 		return f.fallback.Complete(prompt)
 	}
 	return result, nil
@@ -301,7 +320,7 @@ func (p *anthropicProvider) Complete(prompt string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("anthropic API error (%d): %s", resp.StatusCode, string(respBody))
+		return "", &APIError{Provider: "anthropic", Status: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
@@ -362,7 +381,7 @@ func (p *openaiProvider) Complete(prompt string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("openai API error (%d): %s", resp.StatusCode, string(respBody))
+		return "", &APIError{Provider: "openai", Status: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
@@ -424,7 +443,7 @@ func (p *ollamaProvider) Complete(prompt string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama API error (%d): %s", resp.StatusCode, string(respBody))
+		return "", &APIError{Provider: "ollama", Status: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
@@ -484,7 +503,7 @@ func (p *geminiProvider) Complete(prompt string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("gemini API error (%d): %s", resp.StatusCode, string(respBody))
+		return "", &APIError{Provider: "gemini", Status: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
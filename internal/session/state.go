@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/mur-run/mur-core/internal/config"
 )
 
 // RecordingState represents the current recording state persisted as active.json.
@@ -27,7 +29,7 @@ func sessionDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "session"), nil
+	return filepath.Join(config.StateDir(home), "session"), nil
 }
 
 // recordingsDirFunc is the function used to resolve the recordings directory.
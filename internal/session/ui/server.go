@@ -10,7 +10,6 @@ import (
 	"log"
 	"net"
 	"net/http"
-	"os/exec"
 	"runtime"
 	"sync"
 	"time"
@@ -18,6 +17,7 @@ import (
 	"github.com/gorilla/websocket"
 	"gopkg.in/yaml.v3"
 
+	"github.com/mur-run/mur-core/internal/execx"
 	"github.com/mur-run/mur-core/internal/session"
 	mursync "github.com/mur-run/mur-core/internal/sync"
 )
@@ -269,16 +269,19 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 }
 
 func openBrowser(url string) {
-	var cmd *exec.Cmd
+	var name string
+	var args []string
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("open", url)
+		name, args = "open", []string{url}
 	case "linux":
-		cmd = exec.Command("xdg-open", url)
+		name, args = "xdg-open", []string{url}
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler", url}
 	default:
 		return
 	}
-	_ = cmd.Start()
+	go func() {
+		_, _ = execx.Run(context.Background(), name, args...)
+	}()
 }
@@ -4,6 +4,7 @@ package sessions
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"sort"
@@ -28,7 +29,7 @@ func historyPath() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "sessions", "history.json"), nil
+	return filepath.Join(config.StateDir(home), "sessions", "history.json"), nil
 }
 
 // loadHistory reads the history file and returns all records.
@@ -0,0 +1,328 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// Period is the time range [Start, End) a comparison side covers.
+type Period struct {
+	Label string    `json:"label"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	unit  string    // calendar unit (day, week, month, year), or "" for N days
+}
+
+var (
+	lastNRe = regexp.MustCompile(`^(\d+)([dw])$`)
+	monthRe = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	dayRe   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// ParsePeriod parses a period relative to now: today, yesterday,
+// this-week, last-week, this-month, last-month, this-year, last-year, the
+// last N days or weeks (30d, 4w), a month (2026-09), or a day (2026-09-14).
+// Weeks start on Monday.
+func ParsePeriod(s string, now time.Time) (Period, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	year := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+
+	p := Period{Label: s}
+	switch s {
+	case "today":
+		p.Start, p.unit = day, "day"
+	case "yesterday":
+		p.Start, p.unit = day.AddDate(0, 0, -1), "day"
+	case "this-week":
+		p.Start, p.unit = week, "week"
+	case "last-week":
+		p.Start, p.unit = week.AddDate(0, 0, -7), "week"
+	case "this-month":
+		p.Start, p.unit = month, "month"
+	case "last-month":
+		p.Start, p.unit = month.AddDate(0, -1, 0), "month"
+	case "this-year":
+		p.Start, p.unit = year, "year"
+	case "last-year":
+		p.Start, p.unit = year.AddDate(-1, 0, 0), "year"
+	default:
+		switch {
+		case lastNRe.MatchString(s):
+			m := lastNRe.FindStringSubmatch(s)
+			n, _ := strconv.Atoi(m[1])
+			if n == 0 {
+				return Period{}, fmt.Errorf("invalid period %q", s)
+			}
+			if m[2] == "w" {
+				n *= 7
+			}
+			p.Start, p.End = now.AddDate(0, 0, -n), now
+			p.Label = fmt.Sprintf("last %d days", n)
+			return p, nil
+		case monthRe.MatchString(s):
+			t, err := time.ParseInLocation("2006-01", s, now.Location())
+			if err != nil {
+				return Period{}, fmt.Errorf("invalid period %q: %w", s, err)
+			}
+			p.Start, p.unit = t, "month"
+		case dayRe.MatchString(s):
+			t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+			if err != nil {
+				return Period{}, fmt.Errorf("invalid period %q: %w", s, err)
+			}
+			p.Start, p.unit = t, "day"
+		default:
+			return Period{}, fmt.Errorf("invalid period %q (use e.g. this-month, last-week, 30d, 2026-09)", s)
+		}
+	}
+	p.End = p.advance(p.Start, 1)
+	return p, nil
+}
+
+// advance moves t by n of the period's units, or by its length for N days.
+func (p Period) advance(t time.Time, n int) time.Time {
+	switch p.unit {
+	case "day":
+		return t.AddDate(0, 0, n)
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "year":
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.Add(time.Duration(n) * p.End.Sub(p.Start))
+	}
+}
+
+// Previous returns the period of the same kind just before p: the previous
+// calendar month for a month, the preceding 30 days for the last 30 days.
+func (p Period) Previous() Period {
+	prev := Period{unit: p.unit}
+	prev.Start = p.advance(p.Start, -1)
+	prev.End = p.Start
+	switch p.Label {
+	case "today":
+		prev.Label = "yesterday"
+	case "this-week", "this-month", "this-year":
+		prev.Label = "last-" + strings.TrimPrefix(p.Label, "this-")
+	default:
+		prev.Label = "previous " + p.describe()
+	}
+	return prev
+}
+
+// describe names p's length for labels.
+func (p Period) describe() string {
+	if p.unit != "" {
+		return p.unit
+	}
+	return fmt.Sprintf("%d days", int(math.Round(p.End.Sub(p.Start).Hours()/24)))
+}
+
+// Contains reports whether t falls within the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// ParseComparison parses "current..baseline", e.g.
+// "this-month..last-month". A single period is compared with the one
+// before it.
+func ParseComparison(s string, now time.Time) (current, baseline Period, err error) {
+	a, b, found := strings.Cut(s, "..")
+	if current, err = ParsePeriod(a, now); err != nil {
+		return Period{}, Period{}, err
+	}
+	if !found {
+		return current, current.Previous(), nil
+	}
+	if baseline, err = ParsePeriod(b, now); err != nil {
+		return Period{}, Period{}, err
+	}
+	return current, baseline, nil
+}
+
+// PeriodMetrics are the measures compared between periods.
+type PeriodMetrics struct {
+	Runs          int     `json:"runs"`
+	Cost          float64 `json:"cost"`
+	Saved         float64 `json:"saved"`
+	SuccessRate   float64 `json:"success_rate"`   // percent of runs
+	NewPatterns   int     `json:"new_patterns"`   // created during the period
+	TotalPatterns int     `json:"total_patterns"` // existing at its end
+	Extracted     int     `json:"extracted"`      // new patterns extracted from sessions
+	Sessions      int     `json:"sessions"`       // sessions they were extracted from
+	Helpful       int     `json:"helpful"`        // feedback during the period
+	NotHelpful    int     `json:"not_helpful"`
+}
+
+// Yield returns the extracted patterns per session.
+func (m PeriodMetrics) Yield() float64 {
+	if m.Sessions == 0 {
+		return 0
+	}
+	return float64(m.Extracted) / float64(m.Sessions)
+}
+
+// Effectiveness returns the percent of feedback that was helpful.
+func (m PeriodMetrics) Effectiveness() float64 {
+	rated := m.Helpful + m.NotHelpful
+	if rated == 0 {
+		return 0
+	}
+	return float64(m.Helpful) / float64(rated) * 100
+}
+
+// MeasurePeriod computes the metrics for p from usage records and
+// patterns. Feedback counts come from the analytics store and are filled
+// in by the caller.
+func MeasurePeriod(p Period, records []UsageRecord, patterns []pattern.Pattern, m Methodology) PeriodMetrics {
+	var pm PeriodMetrics
+
+	var inPeriod []UsageRecord
+	successes := 0
+	for _, r := range records {
+		if !p.Contains(r.Timestamp) {
+			continue
+		}
+		inPeriod = append(inPeriod, r)
+		pm.Runs++
+		pm.Cost += r.CostEstimate
+		if r.Success {
+			successes++
+		}
+	}
+	if pm.Runs > 0 {
+		pm.SuccessRate = float64(successes) / float64(pm.Runs) * 100
+		pm.Saved = ComputeSavings(inPeriod, m).Total
+	}
+
+	sessions := make(map[string]bool)
+	for _, pat := range patterns {
+		created := pat.Lifecycle.Created
+		if created.IsZero() || !created.Before(p.End) {
+			continue
+		}
+		pm.TotalPatterns++
+		if created.Before(p.Start) {
+			continue
+		}
+		pm.NewPatterns++
+		session := pat.Learning.ExtractedFrom
+		if src := pat.Learning.Source; src != nil && src.Session != "" {
+			session = src.Session
+		}
+		if session != "" {
+			pm.Extracted++
+			sessions[session] = true
+		}
+	}
+	pm.Sessions = len(sessions)
+	return pm
+}
+
+// Delta is one metric compared between periods.
+type Delta struct {
+	Metric   string   `json:"metric"`
+	Current  float64  `json:"current"`
+	Baseline float64  `json:"baseline"`
+	Change   float64  `json:"change"`
+	Percent  *float64 `json:"percent,omitempty"` // relative change; unset when the baseline is 0
+	unit     string   // "", "$", "%", or "x" for per-session ratios
+}
+
+// Comparison compares the metrics of two periods.
+type Comparison struct {
+	Current         Period        `json:"current"`
+	Baseline        Period        `json:"baseline"`
+	CurrentMetrics  PeriodMetrics `json:"current_metrics"`
+	BaselineMetrics PeriodMetrics `json:"baseline_metrics"`
+	Deltas          []Delta       `json:"deltas"`
+}
+
+// Compare builds the comparison of current against baseline.
+func Compare(current, baseline Period, cm, bm PeriodMetrics) Comparison {
+	c := Comparison{Current: current, Baseline: baseline, CurrentMetrics: cm, BaselineMetrics: bm}
+	add := func(metric, unit string, cur, base float64) {
+		d := Delta{Metric: metric, Current: cur, Baseline: base, Change: cur - base, unit: unit}
+		if base != 0 {
+			pct := (cur - base) / math.Abs(base) * 100
+			d.Percent = &pct
+		}
+		c.Deltas = append(c.Deltas, d)
+	}
+	add("Runs", "", float64(cm.Runs), float64(bm.Runs))
+	add("Cost", "$", cm.Cost, bm.Cost)
+	add("Saved", "$", cm.Saved, bm.Saved)
+	add("Success rate", "%", cm.SuccessRate, bm.SuccessRate)
+	add("New patterns", "", float64(cm.NewPatterns), float64(bm.NewPatterns))
+	add("Total patterns", "", float64(cm.TotalPatterns), float64(bm.TotalPatterns))
+	add("Extraction yield", "x", cm.Yield(), bm.Yield())
+	add("Effectiveness", "%", cm.Effectiveness(), bm.Effectiveness())
+	return c
+}
+
+// Value formats v in the delta's unit.
+func (d Delta) Value(v float64) string {
+	switch d.unit {
+	case "$":
+		return formatUSD(v)
+	case "%":
+		return fmt.Sprintf("%.0f%%", v)
+	case "x":
+		return fmt.Sprintf("%.1f/session", v)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// ChangeString formats the change with its sign and, when known, the
+// relative change.
+func (d Delta) ChangeString() string {
+	if d.Change == 0 {
+		return "—"
+	}
+	var change string
+	switch d.unit {
+	case "$":
+		change = formatUSD(math.Abs(d.Change))
+	case "%":
+		change = fmt.Sprintf("%.0f pts", math.Abs(d.Change))
+	case "x":
+		change = fmt.Sprintf("%.1f", math.Abs(d.Change))
+	default:
+		change = fmt.Sprintf("%.0f", math.Abs(d.Change))
+	}
+	sign := "+"
+	if d.Change < 0 {
+		sign = "-"
+	}
+	if d.Percent != nil && d.unit != "%" {
+		return fmt.Sprintf("%s%s (%+.0f%%)", sign, change, *d.Percent)
+	}
+	return sign + change
+}
+
+// FormatComparison formats c as a table.
+func FormatComparison(c Comparison) string {
+	var sb strings.Builder
+	span := func(p Period) string {
+		return fmt.Sprintf("%s – %s", p.Start.Format("2006-01-02"), p.End.Add(-time.Second).Format("2006-01-02"))
+	}
+	fmt.Fprintf(&sb, "📊 %s vs %s\n", c.Current.Label, c.Baseline.Label)
+	fmt.Fprintf(&sb, "   %s vs %s\n\n", span(c.Current), span(c.Baseline))
+	fmt.Fprintf(&sb, "  %-18s %16s %16s  %s\n", "Metric", c.Current.Label, c.Baseline.Label, "Change")
+	for _, d := range c.Deltas {
+		fmt.Fprintf(&sb, "  %-18s %16s %16s  %s\n", d.Metric, d.Value(d.Current), d.Value(d.Baseline), d.ChangeString())
+	}
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC) // a Thursday
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		in         string
+		start, end time.Time
+	}{
+		{"today", day(2026, 10, 15), day(2026, 10, 16)},
+		{"this-week", day(2026, 10, 12), day(2026, 10, 19)},
+		{"last-week", day(2026, 10, 5), day(2026, 10, 12)},
+		{"this-month", day(2026, 10, 1), day(2026, 11, 1)},
+		{"last-month", day(2026, 9, 1), day(2026, 10, 1)},
+		{"2026-02", day(2026, 2, 1), day(2026, 3, 1)},
+		{"2026-09-14", day(2026, 9, 14), day(2026, 9, 15)},
+		{"2w", now.AddDate(0, 0, -14), now},
+	}
+	for _, tt := range tests {
+		p, err := ParsePeriod(tt.in, now)
+		if err != nil {
+			t.Errorf("ParsePeriod(%q): %v", tt.in, err)
+			continue
+		}
+		if !p.Start.Equal(tt.start) || !p.End.Equal(tt.end) {
+			t.Errorf("ParsePeriod(%q) = %v – %v, want %v – %v", tt.in, p.Start, p.End, tt.start, tt.end)
+		}
+	}
+
+	for _, bad := range []string{"", "0d", "next-month", "2026-13"} {
+		if _, err := ParsePeriod(bad, now); err == nil {
+			t.Errorf("ParsePeriod(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestParseComparison(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	cur, base, err := ParseComparison("this-month", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The previous calendar month, not the 31 days before
+	if base.Label != "last-month" || !base.Start.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !base.End.Equal(cur.Start) {
+		t.Errorf("baseline = %+v", base)
+	}
+
+	cur, base, err = ParseComparison("30d", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !base.End.Equal(cur.Start) || base.End.Sub(base.Start) != cur.End.Sub(cur.Start) {
+		t.Errorf("baseline %v – %v doesn't precede %v – %v", base.Start, base.End, cur.Start, cur.End)
+	}
+
+	_, base, err = ParseComparison("2026-03..2025-03", now)
+	if err != nil || base.Start.Year() != 2025 {
+		t.Errorf("explicit baseline = %+v, %v", base, err)
+	}
+}
+
+func TestMeasureAndCompare(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	cur, base, _ := ParseComparison("this-month..last-month", now)
+
+	records := []UsageRecord{
+		{Tool: "claude", Timestamp: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), CostEstimate: 0.02, Success: true},
+		{Tool: "gemini", Timestamp: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), Success: false},
+		{Tool: "claude", Timestamp: time.Date(2026, 9, 20, 0, 0, 0, 0, time.UTC), CostEstimate: 0.04, Success: true},
+	}
+	created := func(m time.Month, d int) pattern.LifecycleMeta {
+		return pattern.LifecycleMeta{Created: time.Date(2026, m, d, 0, 0, 0, 0, time.UTC)}
+	}
+	patterns := []pattern.Pattern{
+		{Name: "old", Lifecycle: created(8, 1)},
+		{Name: "sept", Lifecycle: created(9, 5), Learning: pattern.LearningMeta{ExtractedFrom: "s1"}},
+		{Name: "oct-a", Lifecycle: created(10, 1), Learning: pattern.LearningMeta{Source: &pattern.SourceRef{Session: "s2"}}},
+		{Name: "oct-b", Lifecycle: created(10, 2), Learning: pattern.LearningMeta{ExtractedFrom: "s2"}},
+		{Name: "oct-manual", Lifecycle: created(10, 3)},
+	}
+
+	cm := MeasurePeriod(cur, records, patterns, DefaultMethodology())
+	bm := MeasurePeriod(base, records, patterns, DefaultMethodology())
+	if cm.Runs != 2 || cm.SuccessRate != 50 || cm.NewPatterns != 3 || cm.TotalPatterns != 5 {
+		t.Errorf("current = %+v", cm)
+	}
+	if cm.Extracted != 2 || cm.Sessions != 1 || cm.Yield() != 2 {
+		t.Errorf("current extraction = %+v", cm)
+	}
+	if bm.Runs != 1 || bm.NewPatterns != 1 || bm.TotalPatterns != 2 || bm.Yield() != 1 {
+		t.Errorf("baseline = %+v", bm)
+	}
+
+	cm.Helpful, cm.NotHelpful = 3, 1
+	c := Compare(cur, base, cm, bm)
+	byMetric := make(map[string]Delta)
+	for _, d := range c.Deltas {
+		byMetric[d.Metric] = d
+	}
+	if d := byMetric["Runs"]; d.Change != 1 || d.Percent == nil || *d.Percent != 100 {
+		t.Errorf("runs delta = %+v", d)
+	}
+	if d := byMetric["Effectiveness"]; d.Current != 75 || d.Percent != nil {
+		t.Errorf("effectiveness delta = %+v", d)
+	}
+
+	out := FormatComparison(c)
+	if !strings.Contains(out, "this-month vs last-month") || !strings.Contains(out, "Extraction yield") {
+		t.Errorf("table:\n%s", out)
+	}
+}
@@ -0,0 +1,284 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// Savings defaults.
+const (
+	DefaultBaseline    = "claude-sonnet"
+	DefaultReuseTokens = 1000
+	DefaultOutputRatio = 1.0
+	CharsPerToken      = 4
+	FreeModel          = "free"
+)
+
+// DefaultPrices are list prices in USD per 1M tokens.
+var DefaultPrices = map[string]config.ModelPrice{
+	"claude-opus":   {Input: 15, Output: 75},
+	"claude-sonnet": {Input: 3, Output: 15},
+	"claude-haiku":  {Input: 0.8, Output: 4},
+	"gpt-4o":        {Input: 2.5, Output: 10},
+	"gemini-pro":    {Input: 1.25, Output: 10},
+	FreeModel:       {},
+}
+
+// defaultToolModels maps paid tools to the model they are priced as.
+// Tools with tier "free" are priced as free.
+var defaultToolModels = map[string]string{
+	"claude": "claude-sonnet",
+	"codex":  "gpt-4o",
+}
+
+// EstimateTokens approximates the token count of text.
+func EstimateTokens(text string) int {
+	return (len(text) + CharsPerToken - 1) / CharsPerToken
+}
+
+// Methodology holds the assumptions savings are computed under.
+type Methodology struct {
+	Baseline    string                       `json:"baseline"`
+	Prices      map[string]config.ModelPrice `json:"prices"`
+	ToolModels  map[string]string            `json:"tool_models"`
+	ReuseTokens int                          `json:"reuse_tokens"`
+	OutputRatio float64                      `json:"output_ratio"`
+}
+
+// DefaultMethodology returns the built-in assumptions.
+func DefaultMethodology() Methodology {
+	return MethodologyFromConfig(&config.Config{})
+}
+
+// LoadMethodology reads stats.savings from the config, falling back to the
+// defaults when the config can't be loaded.
+func LoadMethodology() Methodology {
+	cfg, err := config.Load()
+	if err != nil {
+		return DefaultMethodology()
+	}
+	return MethodologyFromConfig(cfg)
+}
+
+// MethodologyFromConfig applies stats.savings settings to the defaults.
+func MethodologyFromConfig(cfg *config.Config) Methodology {
+	sc := cfg.Stats.Savings
+	m := Methodology{
+		Baseline:    sc.Baseline,
+		Prices:      make(map[string]config.ModelPrice),
+		ToolModels:  make(map[string]string),
+		ReuseTokens: sc.ReuseTokens,
+		OutputRatio: sc.OutputRatio,
+	}
+	if m.Baseline == "" {
+		m.Baseline = DefaultBaseline
+	}
+	if m.ReuseTokens <= 0 {
+		m.ReuseTokens = DefaultReuseTokens
+	}
+	if m.OutputRatio <= 0 {
+		m.OutputRatio = DefaultOutputRatio
+	}
+
+	for name, p := range DefaultPrices {
+		m.Prices[name] = p
+	}
+	for name, p := range sc.Prices {
+		m.Prices[name] = p
+	}
+
+	for tool, model := range defaultToolModels {
+		m.ToolModels[tool] = model
+	}
+	for tool, t := range cfg.Tools {
+		if t.Tier == "free" {
+			m.ToolModels[tool] = FreeModel
+		}
+	}
+	for tool, model := range sc.Tools {
+		m.ToolModels[tool] = model
+	}
+	return m
+}
+
+// Validate checks that every referenced model has a price.
+func (m Methodology) Validate() error {
+	if _, ok := m.Prices[m.Baseline]; !ok {
+		return fmt.Errorf("no price for baseline model %q (add it under stats.savings.prices)", m.Baseline)
+	}
+	for tool, model := range m.ToolModels {
+		if _, ok := m.Prices[model]; !ok {
+			return fmt.Errorf("no price for model %q used by tool %s (add it under stats.savings.prices)", model, tool)
+		}
+	}
+	return nil
+}
+
+// modelFor returns the model a run is priced as. Unmapped tools are priced
+// as free if the run was on a free tier, otherwise as the baseline.
+func (m Methodology) modelFor(r UsageRecord) string {
+	if model, ok := m.ToolModels[r.Tool]; ok {
+		return model
+	}
+	if r.Tier == "free" {
+		return FreeModel
+	}
+	return m.Baseline
+}
+
+// cost prices input tokens plus the assumed output at a model's rates.
+func (m Methodology) cost(model string, inputTokens int) float64 {
+	p := m.Prices[model]
+	output := float64(inputTokens) * m.OutputRatio
+	return (float64(inputTokens)*p.Input + output*p.Output) / 1e6
+}
+
+// SavingsBucket aggregates savings from one source.
+type SavingsBucket struct {
+	Runs     int     `json:"runs"`
+	Baseline float64 `json:"baseline_cost"`
+	Actual   float64 `json:"actual_cost"`
+	Saved    float64 `json:"saved"`
+}
+
+func (b *SavingsBucket) add(baseline, actual float64) {
+	b.Runs++
+	b.Baseline += baseline
+	b.Actual += actual
+	b.Saved += baseline - actual
+}
+
+// SavingsReport breaks estimated savings down by source.
+type SavingsReport struct {
+	Methodology  Methodology              `json:"methodology"`
+	Runs         int                      `json:"runs"`
+	AutoRoute    SavingsBucket            `json:"auto_route"`    // tool picked by auto-routing
+	Manual       SavingsBucket            `json:"manual"`        // tool picked with -t
+	PatternReuse SavingsBucket            `json:"pattern_reuse"` // re-explaining avoided minus injection overhead
+	ByTool       map[string]SavingsBucket `json:"by_tool"`
+	ToolModels   map[string]string        `json:"tool_models"` // model each tool's runs were priced as
+	Total        float64                  `json:"total_saved"`
+	LegacyRuns   int                      `json:"legacy_runs"` // runs recorded without token counts
+}
+
+// ComputeSavings prices every run at the baseline and at the model actually
+// used. Routing savings use the prompt as typed; injected pattern tokens are
+// charged against pattern reuse, which is credited ReuseTokens of baseline
+// input per injected pattern.
+func ComputeSavings(records []UsageRecord, m Methodology) SavingsReport {
+	report := SavingsReport{
+		Methodology: m,
+		ByTool:      make(map[string]SavingsBucket),
+		ToolModels:  make(map[string]string),
+	}
+
+	for _, r := range records {
+		report.Runs++
+
+		input := r.InputTokens - r.InjectedTokens
+		if r.InputTokens == 0 {
+			input = (r.PromptLength + CharsPerToken - 1) / CharsPerToken
+			report.LegacyRuns++
+		}
+
+		model := m.modelFor(r)
+		report.ToolModels[r.Tool] = model
+		baseline := m.cost(m.Baseline, input)
+		actual := m.cost(model, input)
+		if r.AutoRouted {
+			report.AutoRoute.add(baseline, actual)
+		} else {
+			report.Manual.add(baseline, actual)
+		}
+		tb := report.ByTool[r.Tool]
+		tb.add(baseline, actual)
+		report.ByTool[r.Tool] = tb
+
+		if r.PatternsInjected > 0 {
+			avoided := float64(r.PatternsInjected*m.ReuseTokens) * m.Prices[m.Baseline].Input / 1e6
+			overhead := float64(r.InjectedTokens) * m.Prices[model].Input / 1e6
+			report.PatternReuse.add(avoided, overhead)
+		}
+	}
+
+	report.Total = report.AutoRoute.Saved + report.Manual.Saved + report.PatternReuse.Saved
+	return report
+}
+
+// FormatSavings renders a savings report with its assumptions.
+func FormatSavings(r SavingsReport) string {
+	var sb strings.Builder
+	m := r.Methodology
+
+	sb.WriteString("💰 Estimated Savings\n")
+	sb.WriteString("====================\n\n")
+
+	if r.Runs == 0 {
+		sb.WriteString("No usage data recorded yet.\n")
+		sb.WriteString("Run `mur run -p \"your prompt\"` to start tracking.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("Assumptions\n")
+	sb.WriteString("-----------\n")
+	bp := m.Prices[m.Baseline]
+	sb.WriteString(fmt.Sprintf("Baseline:      every run on %s ($%.2f in / $%.2f out per 1M tokens)\n", m.Baseline, bp.Input, bp.Output))
+	sb.WriteString(fmt.Sprintf("Tokens:        prompt actually sent, ~%d chars per token\n", CharsPerToken))
+	sb.WriteString(fmt.Sprintf("Output:        %.1f output tokens per input token\n", m.OutputRatio))
+	sb.WriteString(fmt.Sprintf("Pattern reuse: %d tokens of re-explaining avoided per injected pattern\n", m.ReuseTokens))
+
+	var tools []string
+	for tool := range r.ByTool {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		model := r.ToolModels[tool]
+		if _, mapped := m.ToolModels[tool]; !mapped && model == m.Baseline {
+			model += " (unmapped, set stats.savings.tools)"
+		}
+		sb.WriteString(fmt.Sprintf("Tool pricing:  %-8s → %s\n", tool, model))
+	}
+	if r.LegacyRuns > 0 {
+		sb.WriteString(fmt.Sprintf("Note:          %d older runs lack token counts; estimated from prompt length\n", r.LegacyRuns))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("Breakdown\n")
+	sb.WriteString("---------\n")
+	sb.WriteString(fmt.Sprintf("%-16s %6s %12s %12s %12s\n", "SOURCE", "RUNS", "BASELINE", "ACTUAL", "SAVED"))
+	rows := []struct {
+		name string
+		b    SavingsBucket
+	}{
+		{"Auto-routing", r.AutoRoute},
+		{"Manual (-t)", r.Manual},
+		{"Pattern reuse", r.PatternReuse},
+	}
+	for _, row := range rows {
+		sb.WriteString(fmt.Sprintf("%-16s %6d %12s %12s %12s\n", row.name, row.b.Runs,
+			formatUSD(row.b.Baseline), formatUSD(row.b.Actual), formatUSD(row.b.Saved)))
+	}
+	sb.WriteString(fmt.Sprintf("%-16s %6d %12s %12s %12s\n", "Total", r.Runs, "", "", formatUSD(r.Total)))
+	sb.WriteString("\n")
+
+	sb.WriteString("By Tool\n")
+	sb.WriteString("-------\n")
+	for _, tool := range tools {
+		b := r.ByTool[tool]
+		sb.WriteString(fmt.Sprintf("%-16s %6d %12s %12s %12s\n", tool, b.Runs,
+			formatUSD(b.Baseline), formatUSD(b.Actual), formatUSD(b.Saved)))
+	}
+
+	return sb.String()
+}
+
+func formatUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.4f", -v)
+	}
+	return fmt.Sprintf("$%.4f", v)
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMethodologyFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		Tools: map[string]config.Tool{"gemini": {Tier: "free"}},
+		Stats: config.StatsConfig{Savings: config.SavingsConfig{
+			Baseline: "claude-opus",
+			Prices:   map[string]config.ModelPrice{"local-llama": {}},
+			Tools:    map[string]string{"ollama": "local-llama"},
+		}},
+	}
+	m := MethodologyFromConfig(cfg)
+
+	if m.Baseline != "claude-opus" || m.ReuseTokens != DefaultReuseTokens || m.OutputRatio != DefaultOutputRatio {
+		t.Errorf("methodology = %+v", m)
+	}
+	if m.ToolModels["gemini"] != FreeModel || m.ToolModels["claude"] != "claude-sonnet" || m.ToolModels["ollama"] != "local-llama" {
+		t.Errorf("tool models = %v", m.ToolModels)
+	}
+	if err := m.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	m.Baseline = "unknown-model"
+	if err := m.Validate(); err == nil {
+		t.Error("expected error for unpriced baseline")
+	}
+}
+
+func TestComputeSavings(t *testing.T) {
+	m := MethodologyFromConfig(&config.Config{
+		Stats: config.StatsConfig{Savings: config.SavingsConfig{Baseline: "claude-opus"}},
+	})
+
+	records := []UsageRecord{
+		// Auto-routed to a free tool: saves the full baseline cost
+		{Tool: "gemini", Tier: "free", AutoRouted: true, InputTokens: 1000},
+		// Forced to claude (sonnet) with two injected patterns
+		{Tool: "claude", Tier: "paid", InputTokens: 1500, InjectedTokens: 500, PatternsInjected: 2},
+		// Recorded before token counts existed
+		{Tool: "gemini", Tier: "free", AutoRouted: true, PromptLength: 4000},
+	}
+	r := ComputeSavings(records, m)
+
+	opus := (1000*15.0 + 1000*75.0) / 1e6
+	if r.AutoRoute.Runs != 2 || !approx(r.AutoRoute.Saved, 2*opus) || r.AutoRoute.Actual != 0 {
+		t.Errorf("auto-route = %+v", r.AutoRoute)
+	}
+
+	sonnet := (1000*3.0 + 1000*15.0) / 1e6
+	if r.Manual.Runs != 1 || !approx(r.Manual.Saved, opus-sonnet) {
+		t.Errorf("manual = %+v", r.Manual)
+	}
+
+	avoided := 2 * 1000 * 15.0 / 1e6
+	overhead := 500 * 3.0 / 1e6
+	if r.PatternReuse.Runs != 1 || !approx(r.PatternReuse.Saved, avoided-overhead) {
+		t.Errorf("pattern reuse = %+v", r.PatternReuse)
+	}
+
+	if r.LegacyRuns != 1 || !approx(r.Total, r.AutoRoute.Saved+r.Manual.Saved+r.PatternReuse.Saved) {
+		t.Errorf("report = %+v", r)
+	}
+	if r.ToolModels["gemini"] != FreeModel || r.ToolModels["claude"] != "claude-sonnet" {
+		t.Errorf("tool models = %v", r.ToolModels)
+	}
+}
+
+func TestFormatSavingsShowsAssumptions(t *testing.T) {
+	r := ComputeSavings([]UsageRecord{{Tool: "claude", InputTokens: 100}}, DefaultMethodology())
+	out := FormatSavings(r)
+	for _, want := range []string{"Baseline:      every run on claude-sonnet", "Pattern reuse:", "claude   → claude-sonnet", "Auto-routing", "Manual (-t)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"sort"
@@ -24,6 +25,10 @@ type UsageRecord struct {
 	AutoRouted   bool      `json:"auto_routed"`
 	Complexity   float64   `json:"complexity"`
 	Success      bool      `json:"success"`
+	// Token counts of the prompt actually sent (including injected patterns)
+	InputTokens      int `json:"input_tokens,omitempty"`
+	InjectedTokens   int `json:"injected_tokens,omitempty"`
+	PatternsInjected int `json:"patterns_injected,omitempty"`
 }
 
 // QueryFilter specifies criteria for filtering records.
@@ -63,6 +68,7 @@ type Summary struct {
 	ByTool         map[string]ToolStats `json:"by_tool"`
 	EstimatedCost  float64              `json:"estimated_cost"`
 	EstimatedSaved float64              `json:"estimated_saved"`
+	SavedBaseline  string               `json:"saved_baseline"` // model EstimatedSaved is measured against
 	AutoRouteStats AutoRouteStats       `json:"auto_route_stats"`
 	DailyTrend     []DailyStats         `json:"daily_trend"`
 	Period         string               `json:"period"`
@@ -90,7 +96,7 @@ func StatsPath() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "stats.jsonl"), nil
+	return filepath.Join(config.StateDir(home), "stats.jsonl"), nil
 }
 
 // Record appends a usage record to the stats file.
@@ -113,6 +119,9 @@ func Record(record UsageRecord) error {
 	}
 	defer func() { _ = f.Close() }()
 
+	// Stored in UTC, shown in the display time zone
+	record.Timestamp = record.Timestamp.UTC()
+
 	// Write JSON line
 	data, err := json.Marshal(record)
 	if err != nil {
@@ -155,6 +164,7 @@ func Query(filter QueryFilter) ([]UsageRecord, error) {
 			// Skip malformed lines
 			continue
 		}
+		record.Timestamp = record.Timestamp.UTC()
 
 		// Apply filters
 		if filter.Tool != "" && record.Tool != filter.Tool {
@@ -193,17 +203,17 @@ func Summarize(records []UsageRecord) Summary {
 	// Track successes per tool for success rate
 	successCount := make(map[string]int)
 	dailyCounts := make(map[string]int)
+	loc := DisplayLocation()
+
+	// Savings against the configured baseline (see ComputeSavings)
+	savings := ComputeSavings(records, LoadMethodology())
+	summary.EstimatedSaved = savings.Total
+	summary.SavedBaseline = savings.Methodology.Baseline
 
 	for _, r := range records {
 		summary.TotalRuns++
 		summary.EstimatedCost += r.CostEstimate
 
-		// Track what would have been paid if free tools weren't used
-		if r.Tier == "free" {
-			// Estimate what Claude would have cost
-			summary.EstimatedSaved += EstimateCost("claude", r.PromptLength)
-		}
-
 		// Tool stats
 		ts := summary.ByTool[r.Tool]
 		ts.Count++
@@ -224,9 +234,8 @@ func Summarize(records []UsageRecord) Summary {
 			}
 		}
 
-		// Daily counts
-		dateKey := r.Timestamp.Format("2006-01-02")
-		dailyCounts[dateKey]++
+		// Daily counts, by day in the display time zone
+		dailyCounts[dayKey(r.Timestamp, loc)]++
 	}
 
 	// Calculate averages and success rates
@@ -244,17 +253,40 @@ func Summarize(records []UsageRecord) Summary {
 	}
 
 	// Build daily trend (last 7 days)
-	today := time.Now().Truncate(24 * time.Hour)
-	for i := 6; i >= 0; i-- {
-		date := today.AddDate(0, 0, -i)
+	summary.DailyTrend = buildDailyTrend(dailyCounts, 7, time.Now().In(loc))
+
+	return summary
+}
+
+// DailyTrend returns per-day run counts for the last N days, oldest first.
+// Days are calendar days in the display time zone.
+func DailyTrend(records []UsageRecord, days int) []DailyStats {
+	return dailyTrend(records, days, time.Now().In(DisplayLocation()))
+}
+
+// dailyTrend is DailyTrend for the days up to now, in now's time zone.
+func dailyTrend(records []UsageRecord, days int, now time.Time) []DailyStats {
+	dailyCounts := make(map[string]int)
+	for _, r := range records {
+		dailyCounts[dayKey(r.Timestamp, now.Location())]++
+	}
+	return buildDailyTrend(dailyCounts, days, now)
+}
+
+// buildDailyTrend expands day counts into a contiguous series ending on
+// now's day, in now's time zone.
+func buildDailyTrend(dailyCounts map[string]int, days int, now time.Time) []DailyStats {
+	var trend []DailyStats
+	for i := days - 1; i >= 0; i-- {
+		// Noon, so DST changes can't move the date
+		date := time.Date(now.Year(), now.Month(), now.Day()-i, 12, 0, 0, 0, now.Location())
 		dateKey := date.Format("2006-01-02")
-		summary.DailyTrend = append(summary.DailyTrend, DailyStats{
+		trend = append(trend, DailyStats{
 			Date:  dateKey,
 			Count: dailyCounts[dateKey],
 		})
 	}
-
-	return summary
+	return trend
 }
 
 // Reset clears all stats.
@@ -287,7 +319,7 @@ func FormatSummary(s Summary) string {
 	// Overview
 	sb.WriteString(fmt.Sprintf("Total Runs: %d\n", s.TotalRuns))
 	sb.WriteString(fmt.Sprintf("Estimated Cost: $%.4f\n", s.EstimatedCost))
-	sb.WriteString(fmt.Sprintf("Estimated Saved: $%.4f (by using free tools)\n", s.EstimatedSaved))
+	sb.WriteString(fmt.Sprintf("Estimated Saved: $%.4f (vs. all on %s; see `mur stats savings`)\n", s.EstimatedSaved, s.SavedBaseline))
 	sb.WriteString("\n")
 
 	// Tool breakdown
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// DisplayLocation returns the time zone dates are shown in
+// (stats.timezone, by default the machine's own). The setting is read
+// once per process.
+func DisplayLocation() *time.Location {
+	return displayLocation()
+}
+
+var displayLocation = sync.OnceValue(func() *time.Location {
+	cfg, err := config.Load()
+	if err != nil {
+		return time.Local
+	}
+	loc, _ := cfg.Stats.Location()
+	return loc
+})
+
+// dayKey returns the calendar day t falls on in loc, as 2006-01-02.
+func dayKey(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02")
+}
+
+// MigrateToUTC rewrites the RFC 3339 timestamps in the named fields of
+// every line of a JSON lines file to UTC, so records written on machines
+// in different time zones sort and bucket alike. Other fields and lines
+// that aren't JSON objects are kept as they are. It returns how many
+// lines changed; with dryRun, nothing is written.
+func MigrateToUTC(path string, fields []string, dryRun bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var out bytes.Buffer
+	changed := 0
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if newLine, ok := lineToUTC(line, fields); ok {
+			out.WriteString(newLine)
+			changed++
+			continue
+		}
+		out.WriteString(line)
+	}
+	if changed == 0 || dryRun {
+		return changed, nil
+	}
+
+	// Write beside the original and rename, so a crash can't lose records
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Write(out.Bytes()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return 0, fmt.Errorf("cannot replace %s: %w", path, err)
+	}
+	return changed, nil
+}
+
+// lineToUTC returns line with fields converted to UTC, and whether any
+// of them needed it.
+func lineToUTC(line string, fields []string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return line, false
+	}
+	var record map[string]json.RawMessage
+	if json.Unmarshal([]byte(trimmed), &record) != nil {
+		return line, false
+	}
+
+	changed := false
+	for _, field := range fields {
+		raw, ok := record[field]
+		if !ok {
+			continue
+		}
+		var t time.Time
+		if json.Unmarshal(raw, &t) != nil || t.IsZero() || t.Location() == time.UTC {
+			continue
+		}
+		utc, err := json.Marshal(t.UTC())
+		if err != nil {
+			continue
+		}
+		record[field] = utc
+		changed = true
+	}
+	if !changed {
+		return line, false
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return line, false
+	}
+	return string(data) + "\n", true
+}
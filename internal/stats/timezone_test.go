@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDailyTrendDisplayZone(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	newYork := time.FixedZone("EST", -5*3600)
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, tokyo)
+
+	records := []UsageRecord{
+		// 2026-03-09 23:30 UTC is the morning of the 10th in Tokyo
+		{Timestamp: time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC)},
+		// 2026-03-09 20:00 in New York is 10:00 on the 10th in Tokyo
+		{Timestamp: time.Date(2026, 3, 9, 20, 0, 0, 0, newYork)},
+		// 2026-03-09 02:00 UTC is 11:00 on the 9th in Tokyo
+		{Timestamp: time.Date(2026, 3, 9, 2, 0, 0, 0, time.UTC)},
+	}
+
+	trend := dailyTrend(records, 3, now)
+	want := []DailyStats{{"2026-03-08", 0}, {"2026-03-09", 1}, {"2026-03-10", 2}}
+	if len(trend) != len(want) {
+		t.Fatalf("got %d days, want %d", len(trend), len(want))
+	}
+	for i := range want {
+		if trend[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, trend[i], want[i])
+		}
+	}
+}
+
+func TestRecordStoresUTC(t *testing.T) {
+	_, cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	local := time.Date(2026, 3, 9, 20, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	if err := Record(UsageRecord{Tool: "claude", Timestamp: local}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := StatsPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"timestamp":"2026-03-10T01:00:00Z"`) {
+		t.Errorf("stats file = %s, want the timestamp in UTC", data)
+	}
+}
+
+func TestMigrateToUTC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	input := `{"tool":"claude","timestamp":"2026-03-09T20:00:00-05:00"}
+{"tool":"gemini","timestamp":"2026-03-10T01:00:00Z"}
+not json
+{"time":"2026-03-09T20:00:00+09:00","injected_at":"2026-03-09T19:59:00+09:00"}
+`
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run counts without writing
+	n, err := MigrateToUTC(path, []string{"timestamp", "time", "injected_at"}, true)
+	if err != nil || n != 2 {
+		t.Fatalf("dry run = %d, %v; want 2 lines", n, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != input {
+		t.Error("dry run changed the file")
+	}
+
+	n, err = MigrateToUTC(path, []string{"timestamp", "time", "injected_at"}, false)
+	if err != nil || n != 2 {
+		t.Fatalf("MigrateToUTC = %d, %v; want 2 lines", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), data)
+	}
+	for i, want := range []string{
+		`"timestamp":"2026-03-10T01:00:00Z"`,
+		`{"tool":"gemini","timestamp":"2026-03-10T01:00:00Z"}`,
+		`not json`,
+		`"injected_at":"2026-03-09T10:59:00Z"`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %s, want %s", i, lines[i], want)
+		}
+	}
+	if !strings.Contains(lines[0], `"tool":"claude"`) {
+		t.Errorf("line 0 lost a field: %s", lines[0])
+	}
+
+	// Already migrated
+	if n, _ := MigrateToUTC(path, []string{"timestamp"}, false); n != 0 {
+		t.Errorf("second run changed %d lines", n)
+	}
+	// A missing file is nothing to do
+	if n, err := MigrateToUTC(filepath.Join(t.TempDir(), "none.jsonl"), []string{"timestamp"}, false); n != 0 || err != nil {
+		t.Errorf("missing file = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,61 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// VS Code's Copilot reads custom instructions from *.instructions.md files,
+// whose frontmatter says which files they apply to, instead of skills.
+
+// instructionsSuffix ends the files VS Code reads as Copilot instructions.
+const instructionsSuffix = ".instructions.md"
+
+// isInstructionsFile reports whether name is a Copilot instructions file.
+func isInstructionsFile(name string) bool {
+	return strings.HasSuffix(name, instructionsSuffix)
+}
+
+// instructionsFrontmatter starts an instructions file that applies to
+// every file in the workspace.
+func instructionsFrontmatter(description string) string {
+	return fmt.Sprintf("---\napplyTo: \"**\"\ndescription: %s\n---\n\n", description)
+}
+
+// asInstructions makes render's output an instructions file.
+func asInstructions(render func([]pattern.Pattern) string, description string) func([]pattern.Pattern) string {
+	return func(patterns []pattern.Pattern) string {
+		return instructionsFrontmatter(description) + render(patterns)
+	}
+}
+
+// skillRenderer returns how the single format renders patterns for target.
+func skillRenderer(target PatternTarget) func([]pattern.Pattern) string {
+	if isInstructionsFile(target.FileName) {
+		return asInstructions(generatePatternSkill, "Patterns learned by mur from previous development sessions")
+	}
+	return generatePatternSkill
+}
+
+// indexContent returns the directory format index for target: the
+// mur-index skill, the same text as instructions, or as plain markdown for
+// rule directories that don't read skill frontmatter.
+func indexContent(target PatternTarget, patternCount int) string {
+	index := generateLightweightIndex(patternCount)
+	file := target.indexFile()
+	if filepath.Base(file) == "SKILL.md" {
+		return index
+	}
+	_, body, ok := strings.Cut(strings.TrimPrefix(index, "---\n"), "\n---\n")
+	if !ok {
+		return index
+	}
+	body = strings.TrimLeft(body, "\n")
+	if isInstructionsFile(file) {
+		return instructionsFrontmatter("Search mur's learned patterns before solving problems") + body
+	}
+	return body
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// JetBrains is the sync.enable entry for the JetBrains targets: AI
+// Assistant's rules (.aiassistant/rules) and Junie's guidelines
+// (.junie/guidelines.md). Both IDE assistants read them from the project
+// they work on, so they're opt-in rather than written for everyone.
+const JetBrains = "jetbrains"
+
+// jetbrainsLaunchers are the command-line launchers the Toolbox App and
+// the IDEs install.
+var jetbrainsLaunchers = []string{"idea", "goland", "pycharm", "webstorm", "phpstorm", "rubymine", "clion", "rider", "rustrover"}
+
+// JetBrainsInstalled reports whether a JetBrains IDE looks installed: a
+// launcher on the PATH, or the settings directory IDEs create on first run.
+func JetBrainsInstalled() bool {
+	for _, name := range jetbrainsLaunchers {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	dirs := []string{filepath.Join(home, ".config", "JetBrains")}
+	switch runtime.GOOS {
+	case "darwin":
+		dirs = append(dirs, filepath.Join(home, "Library", "Application Support", "JetBrains"))
+	case "windows":
+		dirs = append(dirs, filepath.Join(os.Getenv("APPDATA"), "JetBrains"))
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			// One directory per IDE and version, e.g. GoLand2025.2
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") && e.Name() != "consentOptions" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func targetNames(targets []PatternTarget) []string {
+	var names []string
+	for _, t := range targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestSyncTargetsOptIn(t *testing.T) {
+	names := targetNames(SyncTargets(&config.Config{}))
+	if slices.Contains(names, "Junie") || slices.Contains(names, "JetBrains AI Assistant") || !slices.Contains(names, "Cursor") {
+		t.Errorf("default targets = %v", names)
+	}
+	cfg := &config.Config{Sync: config.SyncConfig{Enable: []string{"JetBrains"}}}
+	names = targetNames(SyncTargets(cfg))
+	if !slices.Contains(names, "Junie") || !slices.Contains(names, "JetBrains AI Assistant") {
+		t.Errorf("with sync.enable jetbrains = %v", names)
+	}
+}
+
+func TestRenderJetBrains(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MUR_HOME", "")
+	patterns := []pattern.Pattern{{Name: "go-errors", Content: "Wrap errors with %w."}}
+
+	// AI Assistant rules are plain markdown, without skill frontmatter
+	r, err := RenderPatterns(&config.Config{}, "jetbrains", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != filepath.Join(home, ".aiassistant", "rules", "mur-index.md") || !strings.HasPrefix(r.Content, "# mur-index\n") {
+		t.Errorf("rendered = %+v", r)
+	}
+
+	// Junie's guidelines are the user's file: patterns go in a managed block
+	path := filepath.Join(home, ".junie", "guidelines.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Guidelines\n\nUse gofmt.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = RenderPatterns(&config.Config{}, "junie", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != path || !strings.HasPrefix(r.Content, "# Guidelines\n\nUse gofmt.\n\n"+BlockStart) || !strings.Contains(r.Content, "## go-errors") {
+		t.Errorf("rendered = %+v", r)
+	}
+}
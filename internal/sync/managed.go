@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers around the part of a single-file target (e.g. Codex's
+// instructions.md, Aider's conventions.md) that mur manages. Everything
+// outside them belongs to the user and is preserved on sync.
+const (
+	BlockStart = "<!-- mur:start -->"
+	BlockEnd   = "<!-- mur:end -->"
+)
+
+// ErrDamagedMarkers means a file's mur markers are unpaired, out of order, or
+// repeated, so mur can't tell which part of the file it owns.
+var ErrDamagedMarkers = errors.New("mur markers damaged")
+
+// MergeManagedBlock returns existing with the content between the mur
+// markers replaced by body. Without markers the block is appended. legacy,
+// if set, marks where older versions of mur wrote unmanaged content: when
+// the file has no markers, everything from legacy to the end is replaced.
+func MergeManagedBlock(existing, body, legacy string) (string, error) {
+	block := BlockStart + "\n" + strings.TrimSpace(body) + "\n" + BlockEnd + "\n"
+
+	starts := strings.Count(existing, BlockStart)
+	ends := strings.Count(existing, BlockEnd)
+	switch {
+	case starts == 0 && ends == 0:
+		if legacy != "" {
+			if i := strings.Index(existing, legacy); i >= 0 {
+				existing = existing[:i]
+			}
+		}
+		existing = strings.TrimRight(existing, " \t\n")
+		if existing == "" {
+			return block, nil
+		}
+		return existing + "\n\n" + block, nil
+
+	case starts == 1 && ends == 1:
+		i := strings.Index(existing, BlockStart)
+		j := strings.Index(existing, BlockEnd)
+		if j < i {
+			return "", fmt.Errorf("%w: %s comes before %s", ErrDamagedMarkers, BlockEnd, BlockStart)
+		}
+		rest := strings.TrimPrefix(existing[j+len(BlockEnd):], "\n")
+		return existing[:i] + block + rest, nil
+
+	case starts == 0:
+		return "", fmt.Errorf("%w: %s without %s", ErrDamagedMarkers, BlockEnd, BlockStart)
+	case ends == 0:
+		return "", fmt.Errorf("%w: %s without %s", ErrDamagedMarkers, BlockStart, BlockEnd)
+	default:
+		return "", fmt.Errorf("%w: %d start and %d end markers", ErrDamagedMarkers, starts, ends)
+	}
+}
+
+// WriteManagedBlock updates the mur block in the file at path, creating the
+// file if needed. A file with damaged markers is left untouched.
+func WriteManagedBlock(path, body, legacy string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	merged, err := MergeManagedBlock(string(existing), body, legacy)
+	if err != nil {
+		return fmt.Errorf("%s: %w; fix or remove the markers and sync again", path, err)
+	}
+	// Only the timestamp changed; leave the file alone
+	if contentHash(merged) == contentHash(string(existing)) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(merged), 0644)
+}
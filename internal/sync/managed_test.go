@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeManagedBlock(t *testing.T) {
+	block := BlockStart + "\nnew patterns\n" + BlockEnd + "\n"
+
+	tests := []struct {
+		name, existing, legacy, want string
+	}{
+		{"empty file", "", "", block},
+		{"appends to user content", "# My rules\n\nBe terse.\n", "", "# My rules\n\nBe terse.\n\n" + block},
+		{
+			"replaces only the block",
+			"# My rules\n" + BlockStart + "\nold\n" + BlockEnd + "\n\n## More of mine\n",
+			"",
+			"# My rules\n" + block + "\n## More of mine\n",
+		},
+		{
+			"replaces legacy section",
+			"# My rules\n\n## Learned Patterns (murmur-ai)\n\nold",
+			"\n\n## Learned Patterns (",
+			"# My rules\n\n" + block,
+		},
+		{
+			"replaces legacy whole file",
+			"# Learned Patterns\n\n*Auto-generated by [mur](x)*\nold",
+			"# Learned Patterns\n\n*Auto-generated by [mur]",
+			block,
+		},
+	}
+	for _, tt := range tests {
+		got, err := MergeManagedBlock(tt.existing, "new patterns\n", tt.legacy)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+		// Syncing again changes nothing
+		if again, _ := MergeManagedBlock(got, "new patterns\n", tt.legacy); again != got {
+			t.Errorf("%s: not idempotent:\n%q", tt.name, again)
+		}
+	}
+
+	damaged := []string{
+		"mine\n" + BlockStart + "\nold\n",
+		"mine\nold\n" + BlockEnd + "\n",
+		BlockEnd + "\nold\n" + BlockStart + "\n",
+		BlockStart + "\na\n" + BlockEnd + "\n" + BlockStart + "\nb\n" + BlockEnd + "\n",
+	}
+	for _, existing := range damaged {
+		if _, err := MergeManagedBlock(existing, "new", ""); !errors.Is(err, ErrDamagedMarkers) {
+			t.Errorf("MergeManagedBlock(%q) = %v, want ErrDamagedMarkers", existing, err)
+		}
+	}
+}
+
+func TestSyncSingleFilePreservesUserContent(t *testing.T) {
+	home := t.TempDir()
+	target := PatternTarget{Name: "Aider", SkillsDir: ".aider", FileName: "conventions.md"}
+	path := filepath.Join(home, ".aider", "conventions.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Team conventions\n\nUse tabs.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := syncSingleFile(home, target, nil, 0); !r.Success {
+		t.Fatalf("sync failed: %s", r.Message)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Team conventions\n\nUse tabs.\n\n"+BlockStart) {
+		t.Errorf("user content not preserved:\n%s", data)
+	}
+
+	// Damaged markers: the file is left alone and the conflict reported
+	damaged := strings.Replace(string(data), BlockEnd, "", 1)
+	if err := os.WriteFile(path, []byte(damaged), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := syncSingleFile(home, target, nil, 0)
+	if r.Success || !r.Conflict || !strings.Contains(r.Message, "without") {
+		t.Errorf("result = %+v, want conflict", r)
+	}
+	if data, _ := os.ReadFile(path); string(data) != damaged {
+		t.Error("file with damaged markers was modified")
+	}
+}
@@ -0,0 +1,165 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+)
+
+// manifest records what mur last wrote into one target directory, so a
+// sync rewrites only files whose rendered content changed and deletes only
+// files it wrote before that are no longer produced. Editors and CLIs
+// watching skills directories reindex on every write, which adds up with
+// large stores.
+//
+// Manifests live in the state dir, one per kind of sync and target
+// directory (the pattern and skills syncs share ~/.claude/skills).
+type manifest struct {
+	Files map[string]string `json:"files"` // path relative to the target dir → content hash
+
+	path    string
+	dir     string
+	seen    map[string]bool
+	changed bool
+	isNew   bool
+}
+
+// loadManifest returns the manifest for kind ("patterns", "skills") in
+// targetDir. A missing or unreadable manifest starts empty, so the first
+// sync writes everything.
+func loadManifest(home, kind, targetDir string) *manifest {
+	rel, err := filepath.Rel(home, targetDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = targetDir
+	}
+	name := kind + "-" + strings.Trim(nonFileRe.ReplaceAllString(rel, "-"), "-") + ".json"
+	m := &manifest{
+		path: filepath.Join(config.StateDir(home), "sync", name),
+		dir:  targetDir,
+		seen: make(map[string]bool),
+	}
+	if data, err := os.ReadFile(m.path); err == nil {
+		_ = json.Unmarshal(data, m)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+		m.isNew = true
+	}
+	return m
+}
+
+var nonFileRe = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// updatedRe matches the "Updated:" timestamps in generated files, which
+// change every sync without the content changing.
+var updatedRe = regexp.MustCompile(`Updated: \d{4}-\d{2}-\d{2} \d{2}:\d{2}`)
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(updatedRe.ReplaceAllString(content, "Updated:")))
+	return hex.EncodeToString(sum[:])
+}
+
+func linkHash(src string) string {
+	return "link:" + src
+}
+
+// upToDate reports whether rel was last synced with hash and is still
+// there, and marks it as produced by this sync.
+func (m *manifest) upToDate(rel, hash string) bool {
+	m.seen[rel] = true
+	if m.Files[rel] != hash {
+		return false
+	}
+	_, err := os.Lstat(filepath.Join(m.dir, rel))
+	return err == nil
+}
+
+// record notes that rel was written with hash.
+func (m *manifest) record(rel, hash string) {
+	m.seen[rel] = true
+	if m.Files[rel] != hash {
+		m.Files[rel] = hash
+		m.changed = true
+	}
+}
+
+// writeFile writes content to rel unless it is unchanged since the last
+// sync. It reports whether the file was written.
+func (m *manifest) writeFile(rel, content string) (bool, error) {
+	hash := contentHash(content)
+	if m.upToDate(rel, hash) {
+		return false, nil
+	}
+	path := filepath.Join(m.dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, err
+	}
+	m.record(rel, hash)
+	return true, nil
+}
+
+// prune deletes files recorded by earlier syncs that this sync didn't
+// produce. A file that was edited or replaced since mur wrote it is left
+// alone and forgotten. It returns how many files were deleted.
+func (m *manifest) prune() int {
+	removed := 0
+	for rel, hash := range m.Files {
+		if m.seen[rel] {
+			continue
+		}
+		delete(m.Files, rel)
+		m.changed = true
+
+		path := filepath.Join(m.dir, rel)
+		if !m.matches(path, hash) {
+			continue
+		}
+		if err := os.RemoveAll(path); err == nil {
+			removed++
+			// Drop the directory a per-skill file lived in once it's empty
+			if dir := filepath.Dir(path); dir != m.dir {
+				_ = os.Remove(dir)
+			}
+		}
+	}
+	return removed
+}
+
+// matches reports whether path still holds what mur recorded for it.
+func (m *manifest) matches(path, hash string) bool {
+	if src, ok := strings.CutPrefix(hash, "link:"); ok {
+		target, err := os.Readlink(path)
+		return err == nil && target == src
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && contentHash(string(data)) == hash
+}
+
+// save writes the manifest if it changed.
+func (m *manifest) save() error {
+	if !m.changed {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("create sync state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sync manifest: %w", err)
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write sync manifest: %w", err)
+	}
+	return os.Rename(tmp, m.path)
+}
@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManifestWriteFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MUR_HOME", filepath.Join(home, ".mur"))
+	dir := filepath.Join(home, ".claude", "skills")
+
+	m := loadManifest(home, "patterns", dir)
+	if !m.isNew {
+		t.Error("first manifest should be new")
+	}
+	written, err := m.writeFile("mur-index/SKILL.md", "3 patterns. Updated: 2026-01-01 10:00")
+	if err != nil || !written {
+		t.Fatalf("first write = %v, %v", written, err)
+	}
+	if err := m.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the timestamp changed
+	m = loadManifest(home, "patterns", dir)
+	if m.isNew {
+		t.Error("saved manifest loaded as new")
+	}
+	if written, _ := m.writeFile("mur-index/SKILL.md", "3 patterns. Updated: 2026-02-02 11:11"); written {
+		t.Error("rewrote a file whose content only differs in its timestamp")
+	}
+	if written, _ := m.writeFile("mur-index/SKILL.md", "4 patterns. Updated: 2026-02-02 11:11"); !written {
+		t.Error("didn't write changed content")
+	}
+
+	// A file deleted behind mur's back is written again
+	_ = os.Remove(filepath.Join(dir, "mur-index", "SKILL.md"))
+	if written, _ := m.writeFile("mur-index/SKILL.md", "4 patterns. Updated: 2026-02-02 11:11"); !written {
+		t.Error("didn't restore a deleted file")
+	}
+}
+
+func TestManifestPrune(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MUR_HOME", filepath.Join(home, ".mur"))
+	dir := filepath.Join(home, ".cursor", "rules")
+
+	m := loadManifest(home, "patterns", dir)
+	for _, name := range []string{"keep.md", "gone.md", "edited.md"} {
+		if _, err := m.writeFile(name, "content of "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.save(); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "edited.md"), []byte("the user's own notes"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "user.md"), []byte("never written by mur"), 0644)
+
+	m = loadManifest(home, "patterns", dir)
+	if _, err := m.writeFile("keep.md", "content of keep.md"); err != nil {
+		t.Fatal(err)
+	}
+	if removed := m.prune(); removed != 1 {
+		t.Errorf("prune removed %d files, want 1", removed)
+	}
+	for name, want := range map[string]bool{"keep.md": true, "gone.md": false, "edited.md": true, "user.md": true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", name, err == nil, want)
+		}
+	}
+	if len(m.Files) != 1 {
+		t.Errorf("manifest tracks %d files after prune, want 1", len(m.Files))
+	}
+}
+
+func TestSyncSkillsPrunesRemovedSkills(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MUR_HOME", filepath.Join(home, ".mur"))
+
+	skillsDir := filepath.Join(home, ".mur", "skills")
+	_ = os.MkdirAll(skillsDir, 0755)
+	for _, name := range []string{"a.md", "b.md"} {
+		_ = os.WriteFile(filepath.Join(skillsDir, name), []byte("# "+name), 0644)
+	}
+	if _, err := SyncSkills(); err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(home, ".claude", "skills")
+	_ = os.WriteFile(filepath.Join(target, "mine.md"), []byte("user skill"), 0644)
+	linked, _ := os.Lstat(filepath.Join(target, "a.md"))
+
+	time.Sleep(10 * time.Millisecond)
+	_ = os.Remove(filepath.Join(skillsDir, "b.md"))
+	if _, err := SyncSkills(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Lstat(filepath.Join(target, "b.md")); !os.IsNotExist(err) {
+		t.Error("removed skill is still linked")
+	}
+	if _, err := os.Stat(filepath.Join(target, "mine.md")); err != nil {
+		t.Error("user's own skill was deleted")
+	}
+	if info, err := os.Lstat(filepath.Join(target, "a.md")); err != nil || !info.ModTime().Equal(linked.ModTime()) {
+		t.Error("unchanged skill was relinked")
+	}
+}
@@ -2,9 +2,12 @@
 package sync
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -110,6 +113,27 @@ type PatternTarget struct {
 	SkillsDir string // relative to home
 	FileName  string // the skill file name
 	Format    string // "markdown" or "yaml"
+	IndexFile string // directory format index in SkillsDir, if not mur-index/SKILL.md
+	RepoRules string // where a repository's own patterns go in it, if not SkillsDir/mur-project.md
+	OptIn     string // only synced when sync.enable lists this, e.g. "jetbrains"
+}
+
+// indexFile returns the file the directory format writes for target,
+// relative to its SkillsDir.
+func (t PatternTarget) indexFile() string {
+	if t.IndexFile != "" {
+		return t.IndexFile
+	}
+	return filepath.Join("mur-index", "SKILL.md")
+}
+
+// repoRulesFile returns the file a repository's own patterns are written
+// to for target, relative to the repository root.
+func (t PatternTarget) repoRulesFile() string {
+	if t.RepoRules != "" {
+		return t.RepoRules
+	}
+	return filepath.Join(t.SkillsDir, ProjectRulesFile)
 }
 
 // DefaultPatternTargets returns all supported CLI targets.
@@ -127,11 +151,44 @@ func DefaultPatternTargets() []PatternTarget {
 		{Name: "Cursor", SkillsDir: ".cursor/rules", FileName: "mur-patterns.md", Format: "markdown"},
 		{Name: "Windsurf", SkillsDir: ".windsurf/rules", FileName: "mur-patterns.md", Format: "markdown"},
 		{Name: "GitHub Copilot", SkillsDir: ".github", FileName: "copilot-instructions.md", Format: "markdown"},
+		{Name: "VS Code Copilot", SkillsDir: ".vscode/copilot", FileName: "mur-patterns.instructions.md", Format: "markdown",
+			IndexFile: "mur-index.instructions.md", RepoRules: ".github/instructions/mur-project.instructions.md"},
+		{Name: "JetBrains AI Assistant", SkillsDir: ".aiassistant/rules", FileName: "mur-patterns.md", Format: "markdown",
+			IndexFile: "mur-index.md", OptIn: JetBrains},
+		{Name: "Junie", SkillsDir: ".junie", FileName: "guidelines.md", Format: "markdown", OptIn: JetBrains},
+	}
+}
+
+// SyncTargets returns the targets sync writes to: the default ones, and
+// the opt-in ones sync.enable lists. A nil cfg reads sync.enable from the
+// config file.
+func SyncTargets(cfg *config.Config) []PatternTarget {
+	if cfg == nil {
+		cfg, _ = config.Load()
+	}
+	var enable []string
+	if cfg != nil {
+		enable = cfg.Sync.Enable
+	}
+	var targets []PatternTarget
+	for _, target := range DefaultPatternTargets() {
+		if target.OptIn == "" || slices.ContainsFunc(enable, func(e string) bool { return strings.EqualFold(e, target.OptIn) }) {
+			targets = append(targets, target)
+		}
 	}
+	return targets
 }
 
 // SyncPatternsToAllCLIs syncs patterns from ~/.mur/patterns/ to all CLI skill directories.
 func SyncPatternsToAllCLIs() ([]SyncResult, error) {
+	return syncPatternsSingle(context.Background(), RunOptions{}, nil)
+}
+
+// syncPatternsSingle writes all active patterns into each target's skill
+// file, syncing targets concurrently. The least effective patterns are
+// left out of targets whose size limit in cfg (which may be nil) they
+// would exceed.
+func syncPatternsSingle(ctx context.Context, opts RunOptions, cfg *config.Config) ([]SyncResult, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
@@ -148,6 +205,8 @@ func SyncPatternsToAllCLIs() ([]SyncResult, error) {
 		return nil, fmt.Errorf("cannot load patterns: %w", err)
 	}
 
+	patterns = globalPatterns(patterns)
+	normalizeTags(patterns, cfg)
 	if len(patterns) == 0 {
 		return []SyncResult{{
 			Target:  "patterns",
@@ -161,50 +220,82 @@ func SyncPatternsToAllCLIs() ([]SyncResult, error) {
 		return patterns[i].Learning.Effectiveness > patterns[j].Learning.Effectiveness
 	})
 
-	// Generate skill content
-	content := generatePatternSkill(patterns)
-
 	// Sync to each target
-	var results []SyncResult
-	for _, target := range DefaultPatternTargets() {
-		targetDir := filepath.Join(home, target.SkillsDir)
-		targetPath := filepath.Join(targetDir, target.FileName)
-
-		// Create directory if needed
-		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			results = append(results, SyncResult{
-				Target:  target.Name,
-				Success: false,
-				Message: fmt.Sprintf("Cannot create directory: %v", err),
-			})
-			continue
+	return runTargets(ctx, SyncTargets(cfg), opts, func(target PatternTarget) SyncResult {
+		limit := TargetMaxBytes(cfg, target.Name)
+		// Shared files like Codex's instructions.md only get a managed block
+		if !supportsDirectoryFormat(target) {
+			return syncSingleFile(home, target, patterns, limit)
 		}
+		return syncSkillFile(home, target, patterns, limit)
+	}), nil
+}
 
-		// For Codex, append to existing instructions.md
-		if target.Name == "Codex" {
-			content = generateCodexInstructions(patterns, targetPath)
+// globalPatterns leaves out patterns scoped to some projects. Synced rules
+// are read in every project, so those reach only the projects they match
+// through context injection instead.
+func globalPatterns(patterns []pattern.Pattern) []pattern.Pattern {
+	global := make([]pattern.Pattern, 0, len(patterns))
+	for _, p := range patterns {
+		if !p.ProjectScoped() {
+			global = append(global, p)
 		}
+	}
+	return global
+}
 
-		// Write skill file
-		if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
-			results = append(results, SyncResult{
-				Target:  target.Name,
-				Success: false,
-				Message: fmt.Sprintf("Cannot write file: %v", err),
-			})
-			continue
-		}
+// normalizeTags renames the tags of patterns being synced by tags.aliases
+// in cfg (which may be nil). The stored patterns keep theirs.
+func normalizeTags(patterns []pattern.Pattern, cfg *config.Config) {
+	aliases := pattern.TagAliasesFromConfig(cfg)
+	for i := range patterns {
+		patterns[i].NormalizeTags(aliases)
+	}
+}
+
+// syncSkillFile writes the merged pattern skill into a target's skills
+// directory, skipping the write when the patterns haven't changed. Only
+// the first patterns that fit in limit bytes are written.
+func syncSkillFile(home string, target PatternTarget, patterns []pattern.Pattern, limit int) SyncResult {
+	content, synced := fitPatterns(patterns, limit, skillRenderer(target))
+	targetDir := filepath.Join(home, target.SkillsDir)
+	m := loadManifest(home, "patterns", targetDir)
 
-		results = append(results, SyncResult{
+	// Write skill file
+	written, err := m.writeFile(target.FileName, content)
+	if err != nil {
+		return SyncResult{
 			Target:  target.Name,
-			Success: true,
-			Message: fmt.Sprintf("Synced %d patterns", len(patterns)),
-		})
+			Success: false,
+			Message: fmt.Sprintf("Cannot write file: %v", err),
+		}
+	}
+
+	// Remove files from the other format, e.g. mur-index/SKILL.md
+	m.prune()
+	if err := m.save(); err != nil {
+		return SyncResult{Target: target.Name, Success: false, Message: err.Error()}
 	}
 
-	return results, nil
+	message := syncedMessage("Synced", synced, len(patterns), limit)
+	if !written {
+		message = fmt.Sprintf("Up to date (%d patterns)", synced)
+	}
+	return SyncResult{
+		Target:  target.Name,
+		Success: true,
+		Message: message,
+	}
 }
 
+// legacySkillHeader starts single files that older versions of mur wrote
+// in full, before managed blocks.
+const legacySkillHeader = "# Learned Patterns\n\n*Auto-generated by [mur]"
+
+// legacyCodexSection starts the unmanaged section older versions of
+// 'mur learn sync' appended to Codex's instructions.md.
+const legacyCodexSection = "\n\n## Learned Patterns ("
+
 // generatePatternSkill generates a markdown skill file from patterns.
 func generatePatternSkill(patterns []pattern.Pattern) string {
 	var sb strings.Builder
@@ -254,25 +345,10 @@ func generatePatternSkill(patterns []pattern.Pattern) string {
 	return sb.String()
 }
 
-// generateCodexInstructions generates Codex-specific instructions format.
-func generateCodexInstructions(patterns []pattern.Pattern, existingPath string) string {
+// generateCodexInstructions generates the Codex instructions.md block.
+func generateCodexInstructions(patterns []pattern.Pattern) string {
 	var sb strings.Builder
 
-	// Read existing content if file exists
-	if data, err := os.ReadFile(existingPath); err == nil {
-		content := string(data)
-		// Remove old mur section if present
-		if idx := strings.Index(content, "<!-- mur:start -->"); idx != -1 {
-			if endIdx := strings.Index(content, "<!-- mur:end -->"); endIdx != -1 {
-				content = content[:idx] + content[endIdx+len("<!-- mur:end -->"):]
-			}
-		}
-		sb.WriteString(strings.TrimSpace(content))
-		sb.WriteString("\n\n")
-	}
-
-	// Add mur section
-	sb.WriteString("<!-- mur:start -->\n")
 	sb.WriteString("## Learned Patterns (mur)\n\n")
 
 	for _, p := range patterns {
@@ -283,8 +359,6 @@ func generateCodexInstructions(patterns []pattern.Pattern, existingPath string)
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString("<!-- mur:end -->\n")
-
 	return sb.String()
 }
 
@@ -306,26 +380,51 @@ func SyncPatternsToTarget(targetName string) (*SyncResult, error) {
 	return nil, fmt.Errorf("unknown target: %s", targetName)
 }
 
-// SyncPatternsWithFormat syncs patterns using the specified format.
-func SyncPatternsWithFormat(cfg *config.Config) ([]SyncResult, error) {
+// SyncPatternsWithFormat syncs patterns using the specified format. Targets
+// are synced concurrently within the limits set by cfg.Sync; cancelling ctx
+// stops targets that haven't finished. The project files in
+// sync.project_files are synced last, then the rules of the repository
+// the current directory is in (see SyncProjectRules).
+func SyncPatternsWithFormat(ctx context.Context, cfg *config.Config) ([]SyncResult, error) {
 	format := SyncFormat(cfg.Sync.Format)
 	if format == "" {
 		format = FormatDirectory // default
 	}
 
+	var results []SyncResult
+	var err error
 	switch format {
 	case FormatDirectory:
-		return SyncPatternsDirectory(cfg)
+		results, err = SyncPatternsDirectory(ctx, cfg)
 	case FormatSingle:
-		return SyncPatternsToAllCLIs()
+		results, err = syncPatternsSingle(ctx, RunOptionsFromConfig(cfg), cfg)
 	default:
 		return nil, fmt.Errorf("unknown sync format: %s", format)
 	}
+	if err != nil || ctx.Err() != nil {
+		return results, err
+	}
+
+	projects, err := SyncProjectFiles(cfg)
+	if err != nil {
+		return results, err
+	}
+	results = append(results, projects...)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return results, nil
+	}
+	rules, err := SyncProjectRules(cfg, cwd)
+	if err != nil {
+		return results, err
+	}
+	return append(results, rules...), nil
 }
 
 // SyncPatternsDirectory syncs a lightweight mur-index skill that instructs AI to use `mur search`.
 // Patterns stay in ~/.mur/patterns/ and are loaded on-demand via semantic search.
-func SyncPatternsDirectory(cfg *config.Config) ([]SyncResult, error) {
+func SyncPatternsDirectory(ctx context.Context, cfg *config.Config) ([]SyncResult, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine home directory: %w", err)
@@ -343,25 +442,27 @@ func SyncPatternsDirectory(cfg *config.Config) ([]SyncResult, error) {
 	}
 
 	patternCount := len(patterns)
+	patterns = globalPatterns(patterns)
+	normalizeTags(patterns, cfg)
+
+	// Single-file targets only get a managed block once there are patterns
+	var targets []PatternTarget
+	for _, target := range SyncTargets(cfg) {
+		if supportsDirectoryFormat(target) || len(patterns) > 0 {
+			targets = append(targets, target)
+		}
+	}
 
 	// Sync to each target
-	var results []SyncResult
-	for _, target := range DefaultPatternTargets() {
+	return runTargets(ctx, targets, RunOptionsFromConfig(cfg), func(target PatternTarget) SyncResult {
 		// For single-file targets, use legacy format
 		if !supportsDirectoryFormat(target) {
-			if patternCount > 0 {
-				result := syncSingleFile(home, target, patterns)
-				results = append(results, result)
-			}
-			continue
+			return syncSingleFile(home, target, patterns, TargetMaxBytes(cfg, target.Name))
 		}
 
 		// For directory-supporting targets, create lightweight mur-index
-		result := syncMurIndex(home, target, patternCount, cfg)
-		results = append(results, result)
-	}
-
-	return results, nil
+		return syncMurIndex(home, target, patternCount, cfg)
+	}), nil
 }
 
 // supportsDirectoryFormat returns true if the target supports directory-based skills.
@@ -371,6 +472,7 @@ func supportsDirectoryFormat(target PatternTarget) bool {
 		"Codex":          true, // Uses single instructions.md
 		"Aider":          true, // Uses single conventions.md
 		"GitHub Copilot": true, // Uses single copilot-instructions.md
+		"Junie":          true, // Uses single guidelines.md
 	}
 	return !noDirectory[target.Name]
 }
@@ -378,39 +480,42 @@ func supportsDirectoryFormat(target PatternTarget) bool {
 // syncMurIndex creates a lightweight mur-index skill that instructs AI to use `mur search`.
 func syncMurIndex(home string, target PatternTarget, patternCount int, cfg *config.Config) SyncResult {
 	targetDir := filepath.Join(home, target.SkillsDir)
-	indexDir := filepath.Join(targetDir, "mur-index")
+	m := loadManifest(home, "patterns", targetDir)
 
 	// Clean old single-file format
 	oldFile := filepath.Join(targetDir, target.FileName)
 	_ = os.Remove(oldFile)
 
-	// Clean old pattern directories (legacy format)
-	cleanOldPatternDirsInTarget(targetDir)
-
-	// Create mur-index directory
-	if err := os.MkdirAll(indexDir, 0755); err != nil {
-		return SyncResult{
-			Target:  target.Name,
-			Success: false,
-			Message: fmt.Sprintf("Cannot create mur-index directory: %v", err),
-		}
+	// Clean old pattern directories (legacy format). They predate the
+	// manifest, so once a target has one there's nothing left to clean.
+	if m.isNew {
+		cleanOldPatternDirsInTarget(targetDir)
 	}
 
 	// Generate lightweight SKILL.md
-	skillContent := generateLightweightIndex(patternCount)
-	skillPath := filepath.Join(indexDir, "SKILL.md")
-	if err := os.WriteFile(skillPath, []byte(skillContent), 0644); err != nil {
+	skillContent := indexContent(target, patternCount)
+	written, err := m.writeFile(target.indexFile(), skillContent)
+	if err != nil {
 		return SyncResult{
 			Target:  target.Name,
 			Success: false,
-			Message: fmt.Sprintf("Cannot write SKILL.md: %v", err),
+			Message: fmt.Sprintf("Cannot write %s: %v", filepath.Base(target.indexFile()), err),
 		}
 	}
 
+	m.prune()
+	if err := m.save(); err != nil {
+		return SyncResult{Target: target.Name, Success: false, Message: err.Error()}
+	}
+
+	message := fmt.Sprintf("Synced mur-index (%d patterns available)", patternCount)
+	if !written {
+		message = fmt.Sprintf("Up to date (%d patterns available)", patternCount)
+	}
 	return SyncResult{
 		Target:  target.Name,
 		Success: true,
-		Message: fmt.Sprintf("Synced mur-index (%d patterns available)", patternCount),
+		Message: message,
 	}
 }
 
@@ -464,27 +569,27 @@ When you discover a non-obvious pattern, workaround, or fix during development:
 		time.Now().Format("2006-01-02 15:04"))
 }
 
-// syncSingleFile syncs patterns as a single file (legacy format).
-func syncSingleFile(home string, target PatternTarget, patterns []pattern.Pattern) SyncResult {
-	targetDir := filepath.Join(home, target.SkillsDir)
-	targetPath := filepath.Join(targetDir, target.FileName)
+// syncSingleFile syncs patterns into a managed block of a single file the
+// user also edits (e.g. Codex's instructions.md), leaving the rest alone.
+// Only the first patterns that fit in limit bytes go in the block.
+func syncSingleFile(home string, target PatternTarget, patterns []pattern.Pattern, limit int) SyncResult {
+	targetPath := filepath.Join(home, target.SkillsDir, target.FileName)
 
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return SyncResult{
-			Target:  target.Name,
-			Success: false,
-			Message: fmt.Sprintf("Cannot create directory: %v", err),
-		}
-	}
-
-	var content string
+	render, legacy := generatePatternSkill, legacySkillHeader
 	if target.Name == "Codex" {
-		content = generateCodexInstructions(patterns, targetPath)
-	} else {
-		content = generatePatternSkill(patterns)
+		render, legacy = generateCodexInstructions, legacyCodexSection
 	}
-
-	if err := os.WriteFile(targetPath, []byte(content), 0644); err != nil {
+	content, synced := fitPatterns(patterns, limit, render)
+
+	if err := WriteManagedBlock(targetPath, content, legacy); err != nil {
+		if errors.Is(err, ErrDamagedMarkers) {
+			return SyncResult{
+				Target:   target.Name,
+				Success:  false,
+				Message:  fmt.Sprintf("Skipped, conflict: %v", err),
+				Conflict: true,
+			}
+		}
 		return SyncResult{
 			Target:  target.Name,
 			Success: false,
@@ -495,7 +600,7 @@ func syncSingleFile(home string, target PatternTarget, patterns []pattern.Patter
 	return SyncResult{
 		Target:  target.Name,
 		Success: true,
-		Message: fmt.Sprintf("Synced %d patterns (single file)", len(patterns)),
+		Message: syncedMessage("Synced", synced, len(patterns), limit) + " (single file)",
 	}
 }
 
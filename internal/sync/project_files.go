@@ -0,0 +1,192 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/inject"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// DefaultProjectFiles are the files written into each project in
+// sync.project_files when it doesn't list any.
+var DefaultProjectFiles = []string{"AGENTS.md", "llms.txt", ".github/copilot-instructions.md", ".junie/guidelines.md"}
+
+// SyncProjectFiles writes the patterns that apply to each repository in
+// sync.project_files into a managed block of its AGENTS.md, llms.txt,
+// .github/copilot-instructions.md and .junie/guidelines.md, so agents
+// without mur find them too. A file is only created once some pattern
+// applies, and only in a directory the repository has (no .github, no
+// Copilot instructions); one that exists is kept up to date either way.
+func SyncProjectFiles(cfg *config.Config) ([]SyncResult, error) {
+	if cfg == nil || len(cfg.Sync.ProjectFiles.Projects) == 0 {
+		return nil, nil
+	}
+	store, err := pattern.DefaultStore()
+	if err != nil {
+		return nil, fmt.Errorf("cannot access pattern store: %w", err)
+	}
+	patterns, err := store.GetActive()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load patterns: %w", err)
+	}
+	normalizeTags(patterns, cfg)
+	return syncProjectFiles(cfg, patterns), nil
+}
+
+func syncProjectFiles(cfg *config.Config, patterns []pattern.Pattern) []SyncResult {
+	files := cfg.Sync.ProjectFiles.Files
+	if len(files) == 0 {
+		files = DefaultProjectFiles
+	}
+	home, _ := os.UserHomeDir()
+
+	var results []SyncResult
+	for _, root := range cfg.Sync.ProjectFiles.Projects {
+		if rest, ok := strings.CutPrefix(root, "~/"); ok && home != "" {
+			root = filepath.Join(home, rest)
+		}
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			results = append(results, SyncResult{
+				Target:  root,
+				Message: "Project directory not found",
+			})
+			continue
+		}
+
+		ctx := inject.DetectProject(root)
+		relevant := ProjectPatterns(patterns, ctx)
+		name := ctx.ProjectName
+		if name == "" {
+			name = filepath.Base(root)
+		}
+		for _, file := range files {
+			path := filepath.Join(root, file)
+			if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+				continue
+			}
+			results = append(results, syncProjectFile(cfg, path, name, relevant))
+		}
+	}
+	return results
+}
+
+// syncProjectFile writes patterns into the managed block of one project
+// file.
+func syncProjectFile(cfg *config.Config, path, project string, patterns []pattern.Pattern) SyncResult {
+	target := fmt.Sprintf("%s (%s)", filepath.Base(path), project)
+
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if len(patterns) == 0 {
+			return SyncResult{Target: target, Success: true, Message: "No patterns apply"}
+		}
+		existing = []byte(projectFileHeader(path, project))
+	} else if err != nil {
+		return SyncResult{Target: target, Message: fmt.Sprintf("Cannot read file: %v", err)}
+	}
+
+	render := renderAgentsFile
+	if strings.EqualFold(filepath.Base(path), "llms.txt") {
+		render = renderLLMsTxt
+	}
+	limit := TargetMaxBytes(cfg, filepath.Base(path))
+	content, synced := fitPatterns(patterns, limit, render)
+
+	merged, err := MergeManagedBlock(string(existing), content, "")
+	if err != nil {
+		if errors.Is(err, ErrDamagedMarkers) {
+			return SyncResult{
+				Target:   target,
+				Message:  fmt.Sprintf("Skipped, conflict: %s: %v; fix or remove the markers and sync again", path, err),
+				Conflict: true,
+			}
+		}
+		return SyncResult{Target: target, Message: err.Error()}
+	}
+	if merged != string(existing) {
+		if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+			return SyncResult{Target: target, Message: fmt.Sprintf("Cannot write file: %v", err)}
+		}
+	}
+	return SyncResult{
+		Target:  target,
+		Success: true,
+		Message: syncedMessage("Synced", synced, len(patterns), limit),
+	}
+}
+
+// ProjectPatterns returns the patterns relevant to the project in ctx:
+// those whose applies conditions allow it and that are scoped to it or
+// match its type, languages, or frameworks by applies conditions or tags.
+// Patterns scoped to the project come first, then the most effective.
+func ProjectPatterns(patterns []pattern.Pattern, ctx *inject.ProjectContext) []pattern.Pattern {
+	target := ctx.Target()
+	var relevant []pattern.Pattern
+	for i := range patterns {
+		p := &patterns[i]
+		if p.AppliesTo(target) && len(inject.MatchReasons(p, ctx, "")) > 0 {
+			relevant = append(relevant, *p)
+		}
+	}
+	sort.SliceStable(relevant, func(i, j int) bool {
+		a, b := relevant[i], relevant[j]
+		if a.ProjectScoped() != b.ProjectScoped() {
+			return a.ProjectScoped()
+		}
+		if a.Learning.Effectiveness != b.Learning.Effectiveness {
+			return a.Learning.Effectiveness > b.Learning.Effectiveness
+		}
+		return a.Name < b.Name
+	})
+	return relevant
+}
+
+// projectFileHeader is what a new project file starts with, above the
+// managed block. llms.txt needs a title and summary first.
+func projectFileHeader(path, project string) string {
+	if strings.EqualFold(filepath.Base(path), "llms.txt") {
+		return fmt.Sprintf("# %s\n\n> Guidance for AI agents working on %s.\n", project, project)
+	}
+	return ""
+}
+
+// renderAgentsFile renders patterns in full for AGENTS.md.
+func renderAgentsFile(patterns []pattern.Pattern) string {
+	var sb strings.Builder
+	sb.WriteString("## Learned Patterns\n\n")
+	sb.WriteString("*Maintained by mur from the patterns that apply to this project; run `mur sync` to update. Edits inside this block are overwritten.*\n")
+	writePatternSections(&sb, patterns)
+	return sb.String()
+}
+
+// writePatternSections writes each pattern in full as a ### section.
+func writePatternSections(sb *strings.Builder, patterns []pattern.Pattern) {
+	for _, p := range patterns {
+		fmt.Fprintf(sb, "\n### %s\n\n", p.Name)
+		if p.Description != "" {
+			fmt.Fprintf(sb, "%s\n\n", p.Description)
+		}
+		sb.WriteString(strings.TrimSpace(p.Content))
+		sb.WriteString("\n")
+	}
+}
+
+// renderLLMsTxt renders patterns as an llms.txt section: one line each.
+func renderLLMsTxt(patterns []pattern.Pattern) string {
+	var sb strings.Builder
+	sb.WriteString("## Patterns\n\n")
+	for _, p := range patterns {
+		summary := p.Description
+		if summary == "" {
+			summary, _, _ = strings.Cut(strings.TrimSpace(p.Content), "\n")
+		}
+		fmt.Fprintf(&sb, "- %s: %s\n", p.Name, summary)
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestSyncProjectFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/billing\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	agents := filepath.Join(root, "AGENTS.md")
+	if err := os.WriteFile(agents, []byte("# Billing\n\nRun make test before pushing.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns := []pattern.Pattern{
+		{Name: "go-errors", Description: "Wrap errors with %w", Content: "Use fmt.Errorf.", Tags: pattern.TagSet{Confirmed: []string{"go"}}, Learning: pattern.LearningMeta{Effectiveness: 0.6}},
+		{Name: "billing-ids", Content: "Invoice IDs are ULIDs.\nNever parse them.", Applies: pattern.ApplyConditions{Projects: []string{"example.com/billing"}}, Learning: pattern.LearningMeta{Effectiveness: 0.5}},
+		{Name: "other-project", Content: "x", Applies: pattern.ApplyConditions{Projects: []string{"example.com/other"}}},
+		{Name: "swift-ui", Content: "y", Tags: pattern.TagSet{Confirmed: []string{"swiftui"}}},
+		{Name: "untagged", Content: "z"},
+	}
+	cfg := &config.Config{}
+	cfg.Sync.ProjectFiles.Projects = []string{root}
+
+	results := syncProjectFiles(cfg, patterns)
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want AGENTS.md and llms.txt", results)
+	}
+	for _, r := range results {
+		if !r.Success || !strings.Contains(r.Message, "Synced 2 patterns") {
+			t.Errorf("%s: %s", r.Target, r.Message)
+		}
+	}
+
+	data, _ := os.ReadFile(agents)
+	got := string(data)
+	if !strings.HasPrefix(got, "# Billing\n\nRun make test before pushing.\n") {
+		t.Errorf("user content not kept:\n%s", got)
+	}
+	if !strings.Contains(got, BlockStart) || strings.Index(got, "### billing-ids") > strings.Index(got, "### go-errors") {
+		t.Errorf("want a managed block with the project-scoped pattern first:\n%s", got)
+	}
+	for _, name := range []string{"other-project", "swift-ui", "untagged"} {
+		if strings.Contains(got, name) {
+			t.Errorf("%s doesn't apply to the project but was synced", name)
+		}
+	}
+
+	data, _ = os.ReadFile(filepath.Join(root, "llms.txt"))
+	llms := string(data)
+	if !strings.HasPrefix(llms, "# example.com/billing\n\n> ") || !strings.Contains(llms, "- billing-ids: Invoice IDs are ULIDs.\n") {
+		t.Errorf("llms.txt:\n%s", llms)
+	}
+
+	// Syncing again changes nothing
+	syncProjectFiles(cfg, patterns)
+	if again, _ := os.ReadFile(agents); string(again) != got {
+		t.Errorf("not idempotent:\n%s", again)
+	}
+
+	// A repository on GitHub also gets Copilot instructions, one set up
+	// for Junie its guidelines
+	for _, dir := range []string{".github", ".junie"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if results := syncProjectFiles(cfg, patterns); len(results) != 4 || !results[2].Success || !results[3].Success {
+		t.Fatalf("results = %+v, want copilot-instructions.md and guidelines.md too", results)
+	}
+	data, _ = os.ReadFile(filepath.Join(root, ".junie", "guidelines.md"))
+	if !strings.Contains(string(data), "### go-errors") {
+		t.Errorf("guidelines.md:\n%s", data)
+	}
+	data, _ = os.ReadFile(filepath.Join(root, ".github", "copilot-instructions.md"))
+	if !strings.HasPrefix(string(data), BlockStart) || !strings.Contains(string(data), "### billing-ids") {
+		t.Errorf("copilot-instructions.md:\n%s", data)
+	}
+}
+
+func TestSyncProjectFilesNoPatterns(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Sync.ProjectFiles.Projects = []string{root, filepath.Join(root, "missing")}
+	cfg.Sync.ProjectFiles.Files = []string{"AGENTS.md"}
+
+	results := syncProjectFiles(cfg, []pattern.Pattern{{Name: "untagged", Content: "z"}})
+	if len(results) != 2 || !results[0].Success || results[1].Success {
+		t.Fatalf("results = %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Error("AGENTS.md created with no patterns to put in it")
+	}
+}
@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// ProjectRulesFile is the file a repository's own patterns are written to
+// in each of its rule directories, e.g. .cursor/rules/mur-project.md.
+// Targets with their own place in a repository set RepoRules instead.
+const ProjectRulesFile = "mur-project.md"
+
+// SyncProjectRules writes the patterns of the repository workDir is in
+// (its .mur/patterns/) into the repository's own rule directories, so they
+// are committed and shared with it. Only tools the repository is set up
+// for get them: those whose directory (.cursor, .windsurf, .github, ...)
+// exists.
+// Outside a trusted repository with patterns there is nothing to do.
+func SyncProjectRules(cfg *config.Config, workDir string) ([]SyncResult, error) {
+	dir := pattern.TrustedProjectDir(workDir)
+	if dir == "" {
+		return nil, nil
+	}
+	patterns, err := pattern.NewStore(dir).GetActive()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load project patterns: %w", err)
+	}
+	normalizeTags(patterns, cfg)
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return patterns[i].Learning.Effectiveness > patterns[j].Learning.Effectiveness
+	})
+
+	root := filepath.Dir(filepath.Dir(dir))
+	var results []SyncResult
+	for _, target := range DefaultPatternTargets() {
+		if !supportsDirectoryFormat(target) {
+			continue
+		}
+		tool, _, _ := strings.Cut(filepath.ToSlash(target.repoRulesFile()), "/")
+		if info, err := os.Stat(filepath.Join(root, tool)); err != nil || !info.IsDir() {
+			continue
+		}
+		results = append(results, syncProjectRules(cfg, root, target, patterns))
+	}
+	return results, nil
+}
+
+// syncProjectRules writes patterns to one target's rules file in the
+// repository at root, or removes the file once there are none.
+func syncProjectRules(cfg *config.Config, root string, target PatternTarget, patterns []pattern.Pattern) SyncResult {
+	name := fmt.Sprintf("%s (%s)", target.Name, filepath.Base(root))
+	path := filepath.Join(root, target.repoRulesFile())
+
+	if len(patterns) == 0 {
+		if err := os.Remove(path); err == nil {
+			return SyncResult{Target: name, Success: true, Message: "Removed, no project patterns"}
+		} else if !os.IsNotExist(err) {
+			return SyncResult{Target: name, Message: fmt.Sprintf("Cannot remove file: %v", err)}
+		}
+		return SyncResult{Target: name, Success: true, Message: "No project patterns"}
+	}
+
+	limit := TargetMaxBytes(cfg, target.Name)
+	render := renderProjectRules
+	if isInstructionsFile(path) {
+		render = asInstructions(renderProjectRules, "Patterns of this repository, from .mur/patterns/")
+	}
+	content, synced := fitPatterns(patterns, limit, render)
+	if existing, err := os.ReadFile(path); err == nil && string(existing) == content {
+		return SyncResult{Target: name, Success: true, Message: fmt.Sprintf("Up to date (%d patterns)", synced)}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return SyncResult{Target: name, Message: fmt.Sprintf("Cannot create directory: %v", err)}
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return SyncResult{Target: name, Message: fmt.Sprintf("Cannot write file: %v", err)}
+	}
+	return SyncResult{
+		Target:  name,
+		Success: true,
+		Message: syncedMessage("Synced", synced, len(patterns), limit),
+	}
+}
+
+// renderProjectRules renders a repository's own patterns in full. The
+// whole file is mur's, so it says where to edit them instead.
+func renderProjectRules(patterns []pattern.Pattern) string {
+	var sb strings.Builder
+	sb.WriteString("# Project Patterns\n\n")
+	sb.WriteString("*Generated by mur from this repository's .mur/patterns/; edit the patterns there and run `mur sync` to update.*\n")
+	writePatternSections(&sb, patterns)
+	return sb.String()
+}
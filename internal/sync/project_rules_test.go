@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestSyncProjectRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	for _, dir := range []string{".git", ".cursor", "internal/api"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{}
+
+	results, err := SyncProjectRules(cfg, filepath.Join(root, "internal/api"))
+	if err != nil || len(results) != 0 {
+		t.Fatalf("without .mur/patterns: results = %+v, err = %v", results, err)
+	}
+
+	store := pattern.NewStore(filepath.Join(root, pattern.ProjectPatternsDir))
+	if err := store.Create(&pattern.Pattern{Name: "api-errors", Description: "Return problem+json", Content: "Use writeProblem."}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An untrusted repository's patterns are never written anywhere
+	results, err = SyncProjectRules(cfg, filepath.Join(root, "internal/api"))
+	if err != nil || len(results) != 0 {
+		t.Fatalf("untrusted repository: results = %+v, err = %v", results, err)
+	}
+	if err := pattern.SetWorkspaceTrust(root, true); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err = SyncProjectRules(cfg, filepath.Join(root, "internal/api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Success || !strings.HasPrefix(results[0].Target, "Cursor") {
+		t.Fatalf("results = %+v, want Cursor only", results)
+	}
+	rules := filepath.Join(root, ".cursor", "rules", ProjectRulesFile)
+	data, err := os.ReadFile(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "### api-errors") {
+		t.Errorf("rules file:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".windsurf")); !os.IsNotExist(err) {
+		t.Errorf("created rules for a tool the repository doesn't use")
+	}
+
+	// A repository on GitHub gets VS Code Copilot instructions
+	if err := os.Mkdir(filepath.Join(root, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = SyncProjectRules(cfg, root)
+	if len(results) != 2 || !results[1].Success || !strings.HasPrefix(results[1].Target, "VS Code Copilot") {
+		t.Fatalf("results = %+v, want Cursor and VS Code Copilot", results)
+	}
+	instructions := filepath.Join(root, ".github", "instructions", "mur-project.instructions.md")
+	data, err = os.ReadFile(instructions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "---\napplyTo: \"**\"\n") || !strings.Contains(string(data), "### api-errors") {
+		t.Errorf("instructions file:\n%s", data)
+	}
+
+	results, _ = SyncProjectRules(cfg, root)
+	if len(results) != 2 || !strings.HasPrefix(results[0].Message, "Up to date") || !strings.HasPrefix(results[1].Message, "Up to date") {
+		t.Errorf("second sync = %+v, want up to date", results)
+	}
+
+	if err := os.Remove(filepath.Join(root, pattern.ProjectPatternsDir, "api-errors.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncProjectRules(cfg, root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(rules); !os.IsNotExist(err) {
+		t.Errorf("rules file kept after the last project pattern was removed")
+	}
+	if _, err := os.Stat(instructions); !os.IsNotExist(err) {
+		t.Errorf("instructions file kept after the last project pattern was removed")
+	}
+}
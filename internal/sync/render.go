@@ -0,0 +1,125 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// targetAliases maps short target names, as used by 'mur context --target',
+// to pattern targets whose name isn't a single word.
+var targetAliases = map[string]string{
+	"claude":      "Claude Code",
+	"gemini":      "Gemini CLI",
+	"copilot":     "GitHub Copilot",
+	"vscode":      "VS Code Copilot",
+	"jetbrains":   "JetBrains AI Assistant",
+	"aiassistant": "JetBrains AI Assistant",
+}
+
+// FindPatternTarget returns the pattern target with the given name or short
+// name (e.g. "claude", "cursor"), ignoring case.
+func FindPatternTarget(name string) (PatternTarget, error) {
+	if full, ok := targetAliases[strings.ToLower(name)]; ok {
+		name = full
+	}
+	var names []string
+	for _, target := range DefaultPatternTargets() {
+		if strings.EqualFold(target.Name, name) {
+			return target, nil
+		}
+		names = append(names, target.Name)
+	}
+	return PatternTarget{}, fmt.Errorf("unknown target: %s (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Rendered is what sync would write for a target.
+type Rendered struct {
+	Target   string
+	Path     string   // file that would be written
+	Content  string   // its exact content after the write
+	Included bool     // whether the patterns appear in Content
+	Excluded []string // project-scoped patterns sync leaves out
+}
+
+// RenderPatterns renders patterns for one target exactly as sync would
+// write them with cfg's sync format, without writing anything. Single-file
+// targets are rendered merged into the file's current content. In directory
+// format, targets that take skill directories only get the mur-index skill,
+// which lists no patterns; they reach those targets through context
+// injection instead.
+func RenderPatterns(cfg *config.Config, targetName string, patterns []pattern.Pattern) (*Rendered, error) {
+	target, err := FindPatternTarget(targetName)
+	if err != nil {
+		return nil, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine home directory: %w", err)
+	}
+
+	r := &Rendered{Target: target.Name}
+	global := globalPatterns(patterns)
+	for _, p := range patterns {
+		if p.ProjectScoped() {
+			r.Excluded = append(r.Excluded, p.Name)
+		}
+	}
+
+	format := SyncFormat(cfg.Sync.Format)
+	if format == "" {
+		format = FormatDirectory
+	}
+	switch format {
+	case FormatSingle:
+		// Single format sorts by effectiveness; directory format keeps store order
+		sort.Slice(global, func(i, j int) bool {
+			return global[i].Learning.Effectiveness > global[j].Learning.Effectiveness
+		})
+	case FormatDirectory:
+	default:
+		return nil, fmt.Errorf("unknown sync format: %s", format)
+	}
+
+	switch {
+	case !supportsDirectoryFormat(target):
+		r.Path = filepath.Join(home, target.SkillsDir, target.FileName)
+		render, legacy := generatePatternSkill, legacySkillHeader
+		if target.Name == "Codex" {
+			render, legacy = generateCodexInstructions, legacyCodexSection
+		}
+		content, _ := fitPatterns(global, TargetMaxBytes(cfg, target.Name), render)
+		existing, err := os.ReadFile(r.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if r.Content, err = MergeManagedBlock(string(existing), content, legacy); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Path, err)
+		}
+		r.Included = len(global) > 0
+
+	case format == FormatSingle:
+		r.Path = filepath.Join(home, target.SkillsDir, target.FileName)
+		r.Content, _ = fitPatterns(global, TargetMaxBytes(cfg, target.Name), skillRenderer(target))
+		r.Included = len(global) > 0
+
+	default:
+		// The index only mentions how many patterns the store has
+		store, err := pattern.DefaultStore()
+		if err != nil {
+			return nil, fmt.Errorf("cannot access pattern store: %w", err)
+		}
+		active, err := store.GetActive()
+		if err != nil {
+			return nil, fmt.Errorf("cannot load patterns: %w", err)
+		}
+		r.Path = filepath.Join(home, target.SkillsDir, target.indexFile())
+		r.Content = indexContent(target, len(active))
+	}
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestFindPatternTarget(t *testing.T) {
+	for name, want := range map[string]string{
+		"claude":      "Claude Code",
+		"Claude Code": "Claude Code",
+		"cursor":      "Cursor",
+		"COPILOT":     "GitHub Copilot",
+		"vscode":      "VS Code Copilot",
+	} {
+		target, err := FindPatternTarget(name)
+		if err != nil || target.Name != want {
+			t.Errorf("FindPatternTarget(%q) = %q, %v; want %q", name, target.Name, err, want)
+		}
+	}
+	if _, err := FindPatternTarget("vim"); err == nil {
+		t.Error("expected error for unknown target")
+	}
+}
+
+func TestRenderPatterns(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MUR_HOME", "")
+
+	patterns := []pattern.Pattern{
+		{Name: "retry-with-backoff", Description: "Retry transient errors", Content: "Use exponential backoff."},
+		{Name: "billing-only", Content: "Billing rule.", Applies: pattern.ApplyConditions{Projects: []string{"billing"}}},
+	}
+
+	// Single-file target: rendered merged into the user's file
+	path := filepath.Join(home, ".aider", "conventions.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Team conventions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := RenderPatterns(&config.Config{}, "aider", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != path || !r.Included {
+		t.Errorf("rendered = %+v", r)
+	}
+	if !strings.HasPrefix(r.Content, "# Team conventions\n\n"+BlockStart) || !strings.Contains(r.Content, "## retry-with-backoff") {
+		t.Errorf("content:\n%s", r.Content)
+	}
+	if strings.Contains(r.Content, "billing-only") || len(r.Excluded) != 1 {
+		t.Errorf("project-scoped pattern not excluded: %v", r.Excluded)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# Team conventions\n" {
+		t.Error("render wrote to the target file")
+	}
+
+	// Directory format: skill targets only get the index
+	r, err = RenderPatterns(&config.Config{}, "claude", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Included || filepath.Base(filepath.Dir(r.Path)) != "mur-index" {
+		t.Errorf("rendered = %+v", r)
+	}
+
+	// Single format: the merged skill file
+	cfg := &config.Config{Sync: config.SyncConfig{Format: "single"}}
+	r, err = RenderPatterns(cfg, "cursor", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != filepath.Join(home, ".cursor", "rules", "mur-patterns.md") || !strings.Contains(r.Content, "Use exponential backoff.") {
+		t.Errorf("rendered = %+v", r)
+	}
+
+	// VS Code Copilot takes instructions files, not skills
+	r, err = RenderPatterns(cfg, "vscode", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != filepath.Join(home, ".vscode", "copilot", "mur-patterns.instructions.md") ||
+		!strings.HasPrefix(r.Content, "---\napplyTo: \"**\"\n") || !strings.Contains(r.Content, "## retry-with-backoff") {
+		t.Errorf("rendered = %+v", r)
+	}
+	r, err = RenderPatterns(&config.Config{}, "vscode", patterns)
+	if err != nil {
+		t.Fatalf("RenderPatterns: %v", err)
+	}
+	if r.Path != filepath.Join(home, ".vscode", "copilot", "mur-index.instructions.md") ||
+		!strings.HasPrefix(r.Content, "---\napplyTo: \"**\"\n") || strings.Contains(r.Content, "name: mur-index") || !strings.Contains(r.Content, "# mur-index") {
+		t.Errorf("rendered = %+v", r)
+	}
+}
@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/config"
+	murlog "github.com/mur-run/mur-core/internal/log"
+)
+
+// Defaults for syncing pattern targets.
+const (
+	DefaultConcurrency   = 4
+	DefaultTargetTimeout = 10 * time.Second
+)
+
+// RunOptions bounds how targets are synced: how many at once, and how long
+// each may take before it's reported as timed out.
+type RunOptions struct {
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// RunOptionsFromConfig returns the sync.concurrency and
+// sync.target_timeout_seconds settings, with defaults for unset values.
+func RunOptionsFromConfig(cfg *config.Config) RunOptions {
+	opts := RunOptions{
+		Concurrency: cfg.Sync.Concurrency,
+		Timeout:     time.Duration(cfg.Sync.TargetTimeoutSeconds) * time.Second,
+	}
+	return opts.withDefaults()
+}
+
+func (o RunOptions) withDefaults() RunOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTargetTimeout
+	}
+	return o
+}
+
+// runTargets syncs each target with fn, at most opts.Concurrency at a time.
+// A target that panics, outlives opts.Timeout, or hasn't started when ctx is
+// done gets a failed result; the others carry on. Results are in target
+// order and record how long each target took.
+//
+// File writes can't be interrupted, so a timed-out target keeps running in
+// the background and its late result is discarded.
+func runTargets(ctx context.Context, targets []PatternTarget, opts RunOptions, fn func(PatternTarget) SyncResult) []SyncResult {
+	opts = opts.withDefaults()
+	results := make([]SyncResult, len(targets))
+	slots := make(chan struct{}, opts.Concurrency)
+	done := make(chan struct{})
+
+	for i, target := range targets {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = SyncResult{Target: target.Name, Message: fmt.Sprintf("Cancelled: %v", ctx.Err())}
+				return
+			}
+			defer func() { <-slots }()
+			results[i] = runTarget(ctx, target, opts.Timeout, fn)
+		}()
+	}
+	for range targets {
+		<-done
+	}
+	return results
+}
+
+// runTarget runs fn for one target, giving up after timeout or when ctx is
+// done.
+func runTarget(ctx context.Context, target PatternTarget, timeout time.Duration, fn func(PatternTarget) SyncResult) SyncResult {
+	if err := ctx.Err(); err != nil {
+		return SyncResult{Target: target.Name, Message: fmt.Sprintf("Cancelled: %v", err)}
+	}
+	start := time.Now()
+	ch := make(chan SyncResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- SyncResult{Target: target.Name, Message: fmt.Sprintf("Failed: %v", r)}
+			}
+		}()
+		ch <- fn(target)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var result SyncResult
+	select {
+	case result = <-ch:
+	case <-timer.C:
+		result = SyncResult{Target: target.Name, Message: fmt.Sprintf("Timed out after %s", timeout)}
+	case <-ctx.Done():
+		result = SyncResult{Target: target.Name, Message: fmt.Sprintf("Cancelled: %v", ctx.Err())}
+	}
+	result.Duration = time.Since(start)
+	if result.Success {
+		murlog.For("sync").Debug("target synced", "target", target.Name, "result", result.Message, "duration", result.Duration.Round(time.Millisecond).String())
+	} else {
+		murlog.For("sync").Error("target failed", "target", target.Name, "result", result.Message)
+	}
+	return result
+}
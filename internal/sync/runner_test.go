@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunTargets(t *testing.T) {
+	targets := []PatternTarget{{Name: "fast"}, {Name: "hangs"}, {Name: "panics"}, {Name: "fails"}, {Name: "slow"}}
+	hang := make(chan struct{})
+	defer close(hang)
+
+	var running, peak atomic.Int32
+	opts := RunOptions{Concurrency: 2, Timeout: 100 * time.Millisecond}
+	start := time.Now()
+	results := runTargets(context.Background(), targets, opts, func(target PatternTarget) SyncResult {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		switch target.Name {
+		case "hangs":
+			<-hang
+		case "panics":
+			panic("boom")
+		case "fails":
+			return SyncResult{Target: target.Name, Message: "Cannot write file"}
+		case "slow":
+			time.Sleep(20 * time.Millisecond)
+		}
+		return SyncResult{Target: target.Name, Success: true, Message: "ok"}
+	})
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("hanging target held up the sync for %s", elapsed)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d targets ran at once, want at most 2", p)
+	}
+
+	want := map[string]string{
+		"fast":   "ok",
+		"hangs":  "Timed out after 100ms",
+		"panics": "Failed: boom",
+		"fails":  "Cannot write file",
+		"slow":   "ok",
+	}
+	for i, r := range results {
+		if r.Target != targets[i].Name {
+			t.Errorf("results[%d] = %s, want %s", i, r.Target, targets[i].Name)
+		}
+		if r.Message != want[r.Target] || r.Success != (r.Message == "ok") {
+			t.Errorf("%s: %+v", r.Target, r)
+		}
+	}
+	if d := results[4].Duration; d < 20*time.Millisecond {
+		t.Errorf("slow target duration = %s", d)
+	}
+}
+
+func TestRunTargetsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	targets := []PatternTarget{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	results := runTargets(ctx, targets, RunOptions{Concurrency: 1, Timeout: time.Minute}, func(target PatternTarget) SyncResult {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return SyncResult{Target: target.Name, Success: true}
+	})
+	for _, r := range results {
+		if r.Success || !strings.HasPrefix(r.Message, "Cancelled") {
+			t.Errorf("%s: %+v, want cancelled", r.Target, r)
+		}
+	}
+}
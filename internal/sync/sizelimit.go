@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// DefaultTargetMaxBytes is the largest pattern file written to a target
+// when sync.target_max_bytes doesn't name it. Much larger files have kept
+// CLIs from loading their skills at all.
+const DefaultTargetMaxBytes = 200000
+
+// TargetMaxBytes returns the largest pattern content written to a target,
+// or 0 for no limit. Target names in sync.target_max_bytes match without
+// regard to case; -1 there turns the limit off.
+func TargetMaxBytes(cfg *config.Config, target string) int {
+	limit := DefaultTargetMaxBytes
+	if cfg != nil {
+		for name, n := range cfg.Sync.TargetMaxBytes {
+			if strings.EqualFold(name, target) && n != 0 {
+				limit = n
+			}
+		}
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// fitPatterns renders as many of patterns, from the front, as fit in
+// limit bytes, and returns the content and how many made it in. Callers
+// sort the patterns they most want kept first; 0 means no limit.
+func fitPatterns(patterns []pattern.Pattern, limit int, render func([]pattern.Pattern) string) (string, int) {
+	content := render(patterns)
+	if limit <= 0 || len(content) <= limit {
+		return content, len(patterns)
+	}
+	// Largest n in [0, len(patterns)) whose rendering fits
+	n := sort.Search(len(patterns), func(n int) bool {
+		return len(render(patterns[:n+1])) > limit
+	})
+	return render(patterns[:n]), n
+}
+
+// syncedMessage describes how many patterns went to a target, noting
+// those the size limit left out.
+func syncedMessage(verb string, synced, total, limit int) string {
+	if synced < total {
+		return fmt.Sprintf("%s %d of %d patterns (size limit %d bytes)", verb, synced, total, limit)
+	}
+	return fmt.Sprintf("%s %d patterns", verb, synced)
+}
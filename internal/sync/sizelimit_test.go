@@ -0,0 +1,60 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+func TestTargetMaxBytes(t *testing.T) {
+	if got := TargetMaxBytes(nil, "Claude Code"); got != DefaultTargetMaxBytes {
+		t.Errorf("default = %d, want %d", got, DefaultTargetMaxBytes)
+	}
+	cfg := &config.Config{Sync: config.SyncConfig{TargetMaxBytes: map[string]int{
+		"claude code": 1000,
+		"Aider":       -1,
+	}}}
+	if got := TargetMaxBytes(cfg, "Claude Code"); got != 1000 {
+		t.Errorf("Claude Code = %d, want 1000", got)
+	}
+	if got := TargetMaxBytes(cfg, "Aider"); got != 0 {
+		t.Errorf("Aider = %d, want 0 (no limit)", got)
+	}
+	if got := TargetMaxBytes(cfg, "Cursor"); got != DefaultTargetMaxBytes {
+		t.Errorf("Cursor = %d, want default", got)
+	}
+}
+
+func TestFitPatterns(t *testing.T) {
+	var patterns []pattern.Pattern
+	for i := 0; i < 20; i++ {
+		patterns = append(patterns, pattern.Pattern{
+			Name:    fmt.Sprintf("p%02d", i),
+			Content: strings.Repeat("x", 400),
+		})
+	}
+	full := generatePatternSkill(patterns)
+
+	content, n := fitPatterns(patterns, 0, generatePatternSkill)
+	if n != 20 || content != full {
+		t.Errorf("no limit: %d patterns", n)
+	}
+
+	limit := len(full) / 2
+	content, n = fitPatterns(patterns, limit, generatePatternSkill)
+	if len(content) > limit {
+		t.Errorf("content is %d bytes, limit %d", len(content), limit)
+	}
+	if n == 0 || n >= 20 {
+		t.Fatalf("fit %d patterns", n)
+	}
+	if len(generatePatternSkill(patterns[:n+1])) <= limit {
+		t.Errorf("fit %d patterns, but %d fit", n, n+1)
+	}
+	if !strings.Contains(content, "p00") || strings.Contains(content, "p19") {
+		t.Error("patterns not kept from the front")
+	}
+}
@@ -5,6 +5,7 @@ package sync
 import (
 	"bufio"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"io"
 	"os"
 	"path/filepath"
@@ -43,7 +44,7 @@ func SkillsSourceDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "skills"), nil
+	return filepath.Join(config.DataDir(home), "skills"), nil
 }
 
 // SuperpowersSkillsDir returns the path to Superpowers plugin skills.
@@ -52,7 +53,7 @@ func SuperpowersSkillsDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".claude", "plugins", "using-superpowers", "skills"), nil
+	return filepath.Join(config.ClaudeDir(home), "plugins", "using-superpowers", "skills"), nil
 }
 
 // ListSkills returns all available skills from ~/.mur/skills/.
@@ -225,18 +226,28 @@ func SyncSkills() ([]SyncResult, error) {
 
 	var results []SyncResult
 	for _, target := range DefaultSkillsTargets() {
-		result := syncSkillsToTarget(home, skillsDir, target, skillFiles)
+		m := loadManifest(home, "skills", filepath.Join(home, target.SkillsDir))
+		result := syncSkillsToTarget(home, skillsDir, target, skillFiles, m)
 		if !result.Success {
 			results = append(results, result)
 			continue
 		}
 
 		// Also sync workflow skill directories
-		wfResult := syncWorkflowDirsToTarget(home, skillsDir, target, workflowDirs)
+		wfResult := syncWorkflowDirsToTarget(home, skillsDir, target, workflowDirs, m)
 		totalCount := len(skillFiles) + len(workflowDirs)
 		if wfResult.Success {
 			result.Message = fmt.Sprintf("synced %d skills (%d files, %d workflows)",
 				totalCount, len(skillFiles), len(workflowDirs))
+
+			// Only prune once both kinds are synced, or one would
+			// remove the other's links
+			if removed := m.prune(); removed > 0 {
+				result.Message += fmt.Sprintf(", removed %d", removed)
+			}
+		}
+		if err := m.save(); err != nil {
+			result = SyncResult{Target: target.Name, Success: false, Message: err.Error()}
 		}
 		results = append(results, result)
 	}
@@ -244,8 +255,9 @@ func SyncSkills() ([]SyncResult, error) {
 	return results, nil
 }
 
-// syncSkillsToTarget syncs skills to a single CLI target.
-func syncSkillsToTarget(home, skillsDir string, target SkillsTarget, skillFiles []string) SyncResult {
+// syncSkillsToTarget syncs skills to a single CLI target, recording each
+// one in m.
+func syncSkillsToTarget(home, skillsDir string, target SkillsTarget, skillFiles []string, m *manifest) SyncResult {
 	targetDir := filepath.Join(home, target.SkillsDir)
 
 	// Ensure target directory exists
@@ -267,9 +279,14 @@ func syncSkillsToTarget(home, skillsDir string, target SkillsTarget, skillFiles
 		if info, err := os.Lstat(dstPath); err == nil {
 			if info.Mode()&os.ModeSymlink != 0 {
 				if linkTarget, err := os.Readlink(dstPath); err == nil && linkTarget == srcPath {
+					m.record(filename, linkHash(srcPath))
 					copied++
 					continue
 				}
+			} else if data, err := os.ReadFile(srcPath); err == nil && m.upToDate(filename, contentHash(string(data))) {
+				// Unchanged copy from an earlier fallback
+				copied++
+				continue
 			}
 			os.Remove(dstPath)
 		}
@@ -283,6 +300,11 @@ func syncSkillsToTarget(home, skillsDir string, target SkillsTarget, skillFiles
 					Message: fmt.Sprintf("cannot sync %s: %v", filename, err),
 				}
 			}
+			if data, err := os.ReadFile(dstPath); err == nil {
+				m.record(filename, contentHash(string(data)))
+			}
+		} else {
+			m.record(filename, linkHash(srcPath))
 		}
 		copied++
 	}
@@ -297,7 +319,7 @@ func syncSkillsToTarget(home, skillsDir string, target SkillsTarget, skillFiles
 // syncWorkflowDirsToTarget symlinks workflow skill directories to a CLI target.
 // Each workflow directory is symlinked so edits in ~/.mur/skills/ are instantly
 // reflected without re-running sync.
-func syncWorkflowDirsToTarget(home, skillsDir string, target SkillsTarget, workflowDirs []string) SyncResult {
+func syncWorkflowDirsToTarget(home, skillsDir string, target SkillsTarget, workflowDirs []string, m *manifest) SyncResult {
 	targetDir := filepath.Join(home, target.SkillsDir)
 
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
@@ -319,6 +341,7 @@ func syncWorkflowDirsToTarget(home, skillsDir string, target SkillsTarget, workf
 				// Already a symlink — check if it points to the right place
 				target, err := os.Readlink(dstDir)
 				if err == nil && target == srcDir {
+					m.record(dirName, linkHash(srcDir))
 					linked++
 					continue
 				}
@@ -333,6 +356,7 @@ func syncWorkflowDirsToTarget(home, skillsDir string, target SkillsTarget, workf
 				Message: fmt.Sprintf("cannot symlink workflow skill %s: %v", dirName, err),
 			}
 		}
+		m.record(dirName, linkHash(srcDir))
 		linked++
 	}
 
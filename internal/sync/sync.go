@@ -2,12 +2,15 @@
 package sync
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/kit"
 )
 
 // CLITarget represents an AI CLI tool that can receive synced config.
@@ -38,9 +41,11 @@ func CodexInstructionsPath() (string, error) {
 
 // SyncResult holds the result of a sync operation for one target.
 type SyncResult struct {
-	Target  string
-	Success bool
-	Message string
+	Target   string        `json:"target"`
+	Success  bool          `json:"success"`
+	Message  string        `json:"message"`
+	Conflict bool          `json:"conflict,omitempty"` // the target file's mur markers are damaged; it was left as is
+	Duration time.Duration `json:"duration,omitempty"` // how long the target took (pattern targets only)
 }
 
 // SyncMCP syncs MCP server configuration to all CLI tools.
@@ -161,6 +166,24 @@ func SyncAll() (map[string][]SyncResult, error) {
 	return results, nil
 }
 
+// SyncLocal syncs patterns and skills to the local CLI tools, the part of
+// 'mur sync' that needs no remote. Used by the dashboards, which run it in
+// process rather than shelling out.
+func SyncLocal(ctx context.Context, cfg *config.Config) ([]SyncResult, error) {
+	results, err := SyncPatternsWithFormat(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Skills are optional
+	if skillResults, err := SyncSkills(); err == nil {
+		for _, r := range skillResults {
+			r.Target += " skills"
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
 // eventMapping defines how murmur events map to CLI-specific events.
 var eventMapping = map[string]map[string]string{
 	"Claude Code": {
@@ -193,8 +216,10 @@ func SyncHooks() ([]SyncResult, error) {
 		return nil, fmt.Errorf("failed to load murmur config: %w", err)
 	}
 
-	// Check if any hooks are configured
-	if !hasHooks(cfg.Hooks) {
+	// Hooks from config plus those of starter kits installed with hooks
+	// allowed
+	hooks := kit.Hooks(cfg.Hooks)
+	if !hasHooks(hooks) {
 		return nil, fmt.Errorf("no hooks configured in ~/.mur/config.yaml")
 	}
 
@@ -205,7 +230,7 @@ func SyncHooks() ([]SyncResult, error) {
 
 	var results []SyncResult
 	for _, target := range DefaultTargets() {
-		result := syncHooksToTarget(home, target, cfg.Hooks)
+		result := syncHooksToTarget(home, target, hooks)
 		results = append(results, result)
 	}
 
@@ -0,0 +1,93 @@
+package sysinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/execx"
+)
+
+// powerSupplyDir is where Linux exposes power supplies.
+var powerSupplyDir = "/sys/class/power_supply"
+
+// OnBattery reports whether the machine is running on battery. It returns
+// false when the power source can't be determined (desktops, Windows).
+func OnBattery() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		res, err := execx.Run(context.Background(), "pmset", "-g", "batt")
+		if err != nil {
+			return false
+		}
+		return parsePmsetBattery(res.Stdout)
+	case "linux":
+		return onBatterySysfs(powerSupplyDir)
+	default:
+		return false
+	}
+}
+
+// parsePmsetBattery parses `pmset -g batt`, whose first line is e.g.
+// "Now drawing from 'Battery Power'".
+func parsePmsetBattery(out string) bool {
+	first, _, _ := strings.Cut(out, "\n")
+	return strings.Contains(first, "'Battery Power'")
+}
+
+// onBatterySysfs reports whether a Linux machine has a battery and no
+// online mains adapter.
+func onBatterySysfs(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	read := func(supply, file string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, supply, file))
+		return strings.TrimSpace(string(data))
+	}
+
+	hasBattery, mainsOnline := false, false
+	for _, e := range entries {
+		switch read(e.Name(), "type") {
+		case "Battery":
+			// Peripherals like mice report scope Device
+			if read(e.Name(), "scope") != "Device" {
+				hasBattery = true
+			}
+		case "Mains", "USB":
+			if read(e.Name(), "online") == "1" {
+				mainsOnline = true
+			}
+		}
+	}
+	return hasBattery && !mainsOnline
+}
+
+// OnMeteredNetwork reports whether the active connection is metered, e.g.
+// a mobile hotspot. Only NetworkManager exposes this; elsewhere it returns
+// false.
+func OnMeteredNetwork() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	res, err := execx.Run(context.Background(), "busctl", "get-property",
+		"org.freedesktop.NetworkManager", "/org/freedesktop/NetworkManager",
+		"org.freedesktop.NetworkManager", "Metered")
+	if err != nil {
+		return false
+	}
+	return parseNMMetered(res.Stdout)
+}
+
+// parseNMMetered parses NetworkManager's Metered property as printed by
+// busctl, e.g. "u 1". Values 1 (yes) and 3 (guessed yes) are metered.
+func parseNMMetered(out string) bool {
+	fields := strings.Fields(out)
+	if len(fields) != 2 || fields[0] != "u" {
+		return false
+	}
+	return fields[1] == "1" || fields[1] == "3"
+}
@@ -0,0 +1,69 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePmsetBattery(t *testing.T) {
+	battery := "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=1234)\t87%; discharging; 5:12 remaining present: true\n"
+	ac := "Now drawing from 'AC Power'\n -InternalBattery-0 (id=1234)\t100%; charged; 0:00 remaining present: true\n"
+	if !parsePmsetBattery(battery) {
+		t.Error("battery not detected")
+	}
+	if parsePmsetBattery(ac) {
+		t.Error("AC detected as battery")
+	}
+}
+
+func TestOnBatterySysfs(t *testing.T) {
+	supply := func(dir, name string, files map[string]string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for f, v := range files {
+			if err := os.WriteFile(filepath.Join(dir, name, f), []byte(v+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	laptop := t.TempDir()
+	supply(laptop, "BAT0", map[string]string{"type": "Battery"})
+	supply(laptop, "AC", map[string]string{"type": "Mains", "online": "0"})
+	if !onBatterySysfs(laptop) {
+		t.Error("unplugged laptop not on battery")
+	}
+	supply(laptop, "AC", map[string]string{"type": "Mains", "online": "1"})
+	if onBatterySysfs(laptop) {
+		t.Error("plugged-in laptop on battery")
+	}
+
+	// A desktop whose only battery is a wireless mouse
+	desktop := t.TempDir()
+	supply(desktop, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device"})
+	if onBatterySysfs(desktop) {
+		t.Error("peripheral battery counted")
+	}
+
+	if onBatterySysfs(filepath.Join(t.TempDir(), "missing")) {
+		t.Error("missing sysfs reported battery")
+	}
+}
+
+func TestParseNMMetered(t *testing.T) {
+	for out, want := range map[string]bool{
+		"u 1\n": true,
+		"u 3\n": true,
+		"u 2\n": false,
+		"u 4\n": false,
+		"u 0\n": false,
+		"":      false,
+	} {
+		if got := parseNMMetered(out); got != want {
+			t.Errorf("parseNMMetered(%q) = %v, want %v", out, got, want)
+		}
+	}
+}
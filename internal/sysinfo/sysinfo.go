@@ -2,39 +2,40 @@
 package sysinfo
 
 import (
-	"fmt"
+	"context"
 	"net/http"
-	"os/exec"
+	"os"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 // SystemRAMGB returns total system RAM in GB, or 0 if detection fails.
 func SystemRAMGB() int {
 	switch runtime.GOOS {
 	case "darwin":
-		out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+		res, err := execx.Run(context.Background(), "sysctl", "-n", "hw.memsize")
 		if err != nil {
 			return 0
 		}
-		bytes, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+		bytes, err := strconv.ParseUint(strings.TrimSpace(res.Stdout), 10, 64)
 		if err != nil {
 			return 0
 		}
 		return int(bytes / (1024 * 1024 * 1024))
 	case "linux":
-		out, err := exec.Command("grep", "MemTotal", "/proc/meminfo").Output()
+		data, err := os.ReadFile("/proc/meminfo")
 		if err != nil {
 			return 0
 		}
 		var kb uint64
-		if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "MemTotal: %d kB", &kb); err != nil {
-			// Try space-separated fallback
-			fields := strings.Fields(string(out))
-			if len(fields) >= 2 {
+		for _, line := range strings.Split(string(data), "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "MemTotal:" {
 				kb, _ = strconv.ParseUint(fields[1], 10, 64)
+				break
 			}
 		}
 		if kb > 0 {
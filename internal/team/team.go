@@ -2,13 +2,14 @@
 package team
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/mur-run/mur-core/internal/config"
+	"github.com/mur-run/mur-core/internal/execx"
 )
 
 // TeamStatus represents the current state of the team repo.
@@ -28,7 +29,7 @@ func TeamDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "team"), nil
+	return filepath.Join(config.DataDir(home), "team"), nil
 }
 
 // IsInitialized checks if the team repo is configured and cloned.
@@ -77,11 +78,14 @@ func Clone(repoURL string) error {
 	}
 
 	// Clone the repo
-	args := []string{"clone", "--branch", branch, repoURL, dir}
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	for _, v := range []string{repoURL, branch} {
+		if err := execx.CheckArg(v); err != nil {
+			return err
+		}
+	}
+	res, err := execx.Run(context.Background(), "git", "clone", "--branch", branch, "--", repoURL, dir)
 	if err != nil {
-		return fmt.Errorf("git clone failed: %s\n%w", string(output), err)
+		return fmt.Errorf("git clone failed: %s\n%w", res.Output(), err)
 	}
 
 	// Update config with repo URL
@@ -287,8 +291,8 @@ func runGit(args ...string) (string, error) {
 		return "", err
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+	c := execx.Command("git", args...)
+	c.Dir = dir
+	res, err := c.Run(context.Background())
+	return res.Output(), err
 }
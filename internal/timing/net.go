@@ -0,0 +1,23 @@
+package timing
+
+import "net/http"
+
+// Transport wraps an HTTP transport so that each request is tracked as a
+// "net <method> <host>" phase while recording is on.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return transport{rt}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+// Unwrap returns the transport requests are sent through.
+func (t transport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer Track("net " + req.Method + " " + req.URL.Host)()
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,148 @@
+package timing
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxRuns is how many timed runs the metrics log keeps.
+const maxRuns = 500
+
+// Run is the timings of one command, as kept in the metrics log.
+type Run struct {
+	Command string        `json:"command"`
+	At      time.Time     `json:"at"`
+	Total   time.Duration `json:"total"`
+	Spans   []Span        `json:"spans"`
+}
+
+// Record returns the recorded phases as a run of command.
+func Record(command string) Run {
+	return Run{Command: command, At: time.Now(), Total: Since(), Spans: Spans()}
+}
+
+// AppendRun adds run to the metrics log at path, keeping the latest
+// maxRuns runs.
+func AppendRun(path string, run Run) error {
+	runs, err := LoadRuns(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > maxRuns {
+		runs = runs[len(runs)-maxRuns:]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, r := range runs {
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// LoadRuns reads the metrics log, skipping lines it can't parse.
+func LoadRuns(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var runs []Run
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var r Run
+		if json.Unmarshal(sc.Bytes(), &r) == nil {
+			runs = append(runs, r)
+		}
+	}
+	return runs, sc.Err()
+}
+
+// PhaseStats summarizes one phase across runs.
+type PhaseStats struct {
+	Name   string        `json:"name"`
+	Count  int           `json:"count"`
+	Total  time.Duration `json:"total"`
+	Mean   time.Duration `json:"mean"`
+	P95    time.Duration `json:"p95"`
+	Max    time.Duration `json:"max"`
+	Allocs uint64        `json:"allocs"` // mean bytes allocated
+}
+
+// Summarize aggregates the phases of runs by name, the phases that took
+// the most time overall first.
+func Summarize(runs []Run) []PhaseStats {
+	var samples []sample
+	for _, r := range runs {
+		for _, s := range r.Spans {
+			if s.Depth == 0 && s.Name == "command" {
+				continue // the whole run; see SummarizeCommands
+			}
+			samples = append(samples, sample{s.Name, s.Duration, s.Allocs})
+		}
+	}
+	return summarize(samples)
+}
+
+// SummarizeCommands aggregates the total time of runs by command.
+func SummarizeCommands(runs []Run) []PhaseStats {
+	var samples []sample
+	for _, r := range runs {
+		var allocs uint64
+		for _, s := range r.Spans {
+			if s.Depth == 0 && s.Name == "command" {
+				allocs = s.Allocs
+			}
+		}
+		samples = append(samples, sample{r.Command, r.Total, allocs})
+	}
+	return summarize(samples)
+}
+
+type sample struct {
+	name   string
+	d      time.Duration
+	allocs uint64
+}
+
+func summarize(samples []sample) []PhaseStats {
+	durations := make(map[string][]time.Duration)
+	allocs := make(map[string]uint64)
+	for _, s := range samples {
+		durations[s.name] = append(durations[s.name], s.d)
+		allocs[s.name] += s.allocs
+	}
+
+	stats := make([]PhaseStats, 0, len(durations))
+	for name, ds := range durations {
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+		st := PhaseStats{Name: name, Count: len(ds), Max: ds[len(ds)-1], P95: ds[(len(ds)*95-1)/100]}
+		for _, d := range ds {
+			st.Total += d
+		}
+		st.Mean = st.Total / time.Duration(len(ds))
+		st.Allocs = allocs[name] / uint64(len(ds))
+		stats = append(stats, st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
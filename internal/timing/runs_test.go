@@ -0,0 +1,52 @@
+package timing
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunsLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timings.jsonl")
+	for i := 0; i < maxRuns+5; i++ {
+		run := Run{Command: "mur context", Total: 20 * time.Millisecond, Spans: []Span{
+			{Name: "command", Duration: 20 * time.Millisecond, Allocs: 4096},
+			{Name: "config.Load", Duration: time.Duration(i%10+1) * time.Millisecond, Depth: 1, Allocs: 2048},
+			{Name: "patterns.List", Duration: 5 * time.Millisecond, Depth: 1},
+		}}
+		if i%2 == 0 {
+			run.Command = "mur search"
+			run.Total = 40 * time.Millisecond
+		}
+		if err := AppendRun(path, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := LoadRuns(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != maxRuns {
+		t.Fatalf("log kept %d runs, want %d", len(runs), maxRuns)
+	}
+
+	phases := Summarize(runs)
+	if len(phases) != 2 {
+		t.Fatalf("phases = %+v, want config.Load and patterns.List", phases)
+	}
+	load := phases[0]
+	if load.Name != "config.Load" || load.Count != maxRuns || load.Max != 10*time.Millisecond || load.Allocs != 2048 {
+		t.Errorf("config.Load stats = %+v", load)
+	}
+	if load.P95 != 10*time.Millisecond || load.Mean < 5*time.Millisecond || load.Mean > 6*time.Millisecond {
+		t.Errorf("config.Load mean %v, p95 %v", load.Mean, load.P95)
+	}
+
+	commands := SummarizeCommands(runs)
+	if len(commands) != 2 || commands[0].Name != "mur search" || commands[0].Mean != 40*time.Millisecond {
+		t.Errorf("commands = %+v, want mur search first", commands)
+	}
+	if commands[1].Allocs != 4096 {
+		t.Errorf("command allocs = %d, want 4096", commands[1].Allocs)
+	}
+}
@@ -0,0 +1,115 @@
+// Package timing records how long command phases take, for
+// `mur debug timings` and MUR_TIMINGS=1.
+package timing
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// start approximates process start: package initialization runs before main.
+var start = time.Now()
+
+var (
+	mu      sync.Mutex
+	enabled = os.Getenv("MUR_TIMINGS") != ""
+	spans   []Span
+)
+
+// Span is one timed phase.
+type Span struct {
+	Name     string        `json:"name"`
+	Start    time.Duration `json:"start"` // offset from process start
+	Duration time.Duration `json:"duration"`
+	Depth    int           `json:"depth"`
+	Allocs   uint64        `json:"allocs"` // bytes allocated during the phase
+}
+
+var depth int
+
+// Enable turns on recording.
+func Enable() {
+	mu.Lock()
+	enabled = true
+	mu.Unlock()
+}
+
+// Enabled reports whether phases are being recorded.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Track starts timing a phase and returns the function that ends it:
+//
+//	defer timing.Track("config.Load")()
+func Track(name string) func() {
+	mu.Lock()
+	if !enabled {
+		mu.Unlock()
+		return func() {}
+	}
+	t0, a0 := time.Now(), allocated()
+	i := len(spans)
+	spans = append(spans, Span{Name: name, Start: t0.Sub(start), Depth: depth})
+	depth++
+	mu.Unlock()
+
+	return func() {
+		d, a := time.Since(t0), allocated()
+		mu.Lock()
+		spans[i].Duration = d
+		spans[i].Allocs = a - a0
+		depth--
+		mu.Unlock()
+	}
+}
+
+// allocated returns the bytes allocated on the heap since process start.
+// Unlike runtime.ReadMemStats it doesn't stop the world.
+func allocated() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// Since returns the time elapsed since process start.
+func Since() time.Duration {
+	return time.Since(start)
+}
+
+// Spans returns the recorded phases in start order.
+func Spans() []Span {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Span(nil), spans...)
+}
+
+// Report writes the recorded phases and total elapsed time.
+func Report(w io.Writer) {
+	fmt.Fprintln(w, "⏱  Timings")
+	for _, s := range Spans() {
+		indent := ""
+		for i := 0; i < s.Depth; i++ {
+			indent += "  "
+		}
+		fmt.Fprintf(w, "   %8s  +%-8s %8s  %s%s\n", ms(s.Duration), ms(s.Start), kb(s.Allocs), indent, s.Name)
+	}
+	fmt.Fprintf(w, "   %8s  total\n", ms(Since()))
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
+
+func kb(n uint64) string {
+	return fmt.Sprintf("%dKB", (n+512)/1024)
+}
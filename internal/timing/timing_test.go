@@ -0,0 +1,59 @@
+package timing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func reset(on bool) {
+	mu.Lock()
+	enabled = on
+	spans = nil
+	depth = 0
+	mu.Unlock()
+}
+
+func TestTrackDisabled(t *testing.T) {
+	reset(false)
+	Track("ignored")()
+	if got := Spans(); len(got) != 0 {
+		t.Errorf("recorded %d spans while disabled", len(got))
+	}
+}
+
+func TestTrackNested(t *testing.T) {
+	reset(false)
+	Enable()
+	defer reset(false)
+
+	outer := Track("command")
+	Track("config.Load")()
+	Track("patterns.List")()
+	outer()
+
+	spans := Spans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	if spans[0].Name != "command" || spans[0].Depth != 0 {
+		t.Errorf("spans[0] = %+v", spans[0])
+	}
+	for _, s := range spans[1:] {
+		if s.Depth != 1 {
+			t.Errorf("%s depth = %d, want 1", s.Name, s.Depth)
+		}
+		if s.Duration > spans[0].Duration {
+			t.Errorf("%s outlasted its parent", s.Name)
+		}
+	}
+
+	var buf bytes.Buffer
+	Report(&buf)
+	out := buf.String()
+	for _, want := range []string{"Timings", "command", "\n   ", "  config.Load", "total"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
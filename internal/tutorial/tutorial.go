@@ -0,0 +1,122 @@
+// Package tutorial provides the sandbox and bundled data for `mur tutorial`.
+package tutorial
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mur-run/mur-core/internal/core/pattern"
+)
+
+// ExampleTranscript is a short Claude Code session used for the simulated
+// extraction step.
+//
+//go:embed example-session.jsonl
+var ExampleTranscript []byte
+
+// sandboxEnv lists the variables the sandbox overrides. Everything mur reads
+// or writes resolves from these, so the tutorial never touches real data.
+var sandboxEnv = []string{"HOME", "MUR_HOME", "CLAUDE_CONFIG_DIR", "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"}
+
+// Sandbox is a temporary home directory with its own mur data.
+type Sandbox struct {
+	Root    string // temp directory holding everything
+	Home    string // fake $HOME (AI tool targets are synced here)
+	MurHome string // $MUR_HOME inside Home
+
+	saved map[string]*string
+}
+
+// NewSandbox creates a temp directory and points HOME and MUR_HOME at it.
+// Call Close to restore the environment.
+func NewSandbox() (*Sandbox, error) {
+	root, err := os.MkdirTemp("", "mur-tutorial-")
+	if err != nil {
+		return nil, fmt.Errorf("create sandbox: %w", err)
+	}
+
+	s := &Sandbox{
+		Root:    root,
+		Home:    filepath.Join(root, "home"),
+		MurHome: filepath.Join(root, "home", ".mur"),
+		saved:   make(map[string]*string),
+	}
+	if err := os.MkdirAll(s.MurHome, 0755); err != nil {
+		os.RemoveAll(root)
+		return nil, fmt.Errorf("create sandbox: %w", err)
+	}
+
+	for _, key := range sandboxEnv {
+		if v, ok := os.LookupEnv(key); ok {
+			s.saved[key] = &v
+		} else {
+			s.saved[key] = nil
+		}
+		os.Unsetenv(key)
+	}
+	os.Setenv("HOME", s.Home)
+	os.Setenv("MUR_HOME", s.MurHome)
+
+	return s, nil
+}
+
+// Close restores the environment. Unless keep is set, the sandbox is removed.
+func (s *Sandbox) Close(keep bool) error {
+	for key, v := range s.saved {
+		if v == nil {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, *v)
+		}
+	}
+	if keep {
+		return nil
+	}
+	return os.RemoveAll(s.Root)
+}
+
+// WriteTranscript places the example transcript where Claude Code keeps its
+// sessions inside the sandbox and returns its path.
+func (s *Sandbox) WriteTranscript() (string, error) {
+	dir := filepath.Join(s.Home, ".claude", "projects", "tutorial-project")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "tutorial-example.jsonl")
+	if err := os.WriteFile(path, ExampleTranscript, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// SamplePattern returns the pattern created in the first tutorial step.
+func SamplePattern() *pattern.Pattern {
+	return &pattern.Pattern{
+		Name:        "go-table-driven-tests",
+		Description: "Write Go tests as table-driven subtests",
+		Content: `Use a slice of test cases and t.Run for each one:
+
+    tests := []struct {
+        name string
+        in   string
+        want int
+    }{
+        {"empty", "", 0},
+        {"word", "mur", 3},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Len(tt.in); got != tt.want {
+                t.Errorf("Len(%q) = %d, want %d", tt.in, got, tt.want)
+            }
+        })
+    }`,
+		Tags: pattern.TagSet{Confirmed: []string{"go", "testing"}},
+		Lifecycle: pattern.LifecycleMeta{
+			Created: time.Now(),
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package tutorial
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/learn"
+)
+
+func TestSandboxRestoresEnvironment(t *testing.T) {
+	t.Setenv("HOME", "/real/home")
+	t.Setenv("MUR_HOME", "/real/mur")
+	t.Setenv("CLAUDE_CONFIG_DIR", "/real/claude")
+
+	s, err := NewSandbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if home, _ := os.UserHomeDir(); home != s.Home {
+		t.Errorf("HOME = %s, want sandbox %s", home, s.Home)
+	}
+	if os.Getenv("MUR_HOME") != s.MurHome {
+		t.Errorf("MUR_HOME = %s, want %s", os.Getenv("MUR_HOME"), s.MurHome)
+	}
+	if _, ok := os.LookupEnv("CLAUDE_CONFIG_DIR"); ok {
+		t.Error("CLAUDE_CONFIG_DIR should be unset inside the sandbox")
+	}
+
+	if err := s.Close(false); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("HOME") != "/real/home" || os.Getenv("MUR_HOME") != "/real/mur" || os.Getenv("CLAUDE_CONFIG_DIR") != "/real/claude" {
+		t.Error("environment not restored after Close")
+	}
+	if _, err := os.Stat(s.Root); !os.IsNotExist(err) {
+		t.Error("sandbox directory should be removed")
+	}
+}
+
+func TestExampleTranscriptExtracts(t *testing.T) {
+	s, err := NewSandbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(false)
+
+	path, err := s.WriteTranscript()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(path, s.Home) {
+		t.Errorf("transcript %s written outside the sandbox", path)
+	}
+
+	extracted, err := learn.ExtractFromSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extracted) == 0 {
+		t.Error("example transcript should yield at least one pattern")
+	}
+}
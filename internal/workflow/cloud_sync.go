@@ -55,6 +55,11 @@ type WorkflowConflict struct {
 type WorkflowPullResponse struct {
 	Workflows []WorkflowSyncPayload `json:"workflows"`
 	Version   int64                 `json:"version"`
+
+	// Manifests carries the signed permission of the requesting user on each
+	// pulled workflow; SigningKey is the server's base64 ed25519 public key.
+	Manifests  []PermissionManifest `json:"manifests,omitempty"`
+	SigningKey string               `json:"signing_key,omitempty"`
 }
 
 // WorkflowSyncStatus represents the sync status for workflows.
@@ -100,6 +105,10 @@ func BuildChangesFromLocal(knownIDs map[string]bool) ([]WorkflowSyncChange, erro
 		if err != nil {
 			continue
 		}
+		// Pulled workflows are only pushed back when the grant allows edits
+		if m, _ := LoadManifest(entry.ID); m != nil && !m.Permission.CanEdit() {
+			continue
+		}
 		payload := BuildSyncPayload(wf, meta)
 
 		action := "create"
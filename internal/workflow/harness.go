@@ -0,0 +1,239 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+// TestSpec is a mock spec for 'mur workflows test': test cases that run a
+// workflow with its commands intercepted.
+type TestSpec struct {
+	Tests []TestCase `yaml:"tests"`
+}
+
+// TestCase runs the workflow once and checks the commands it ran, in
+// order, and how the run ended.
+type TestCase struct {
+	Name      string            `yaml:"name"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+	Approve   *bool             `yaml:"approve,omitempty"` // answer to approval prompts (default yes)
+	Retry     *bool             `yaml:"retry,omitempty"`   // answer to retry prompts (default yes)
+	Expect    []MockCommand     `yaml:"expect"`
+	// Result is success (default) or failure; FailedStep, if set, is the
+	// step a failure must come from.
+	Result     string `yaml:"result,omitempty"`
+	FailedStep int    `yaml:"failed_step,omitempty"`
+}
+
+// MockCommand is one expected command and what it pretends to do.
+type MockCommand struct {
+	Run    string `yaml:"run,omitempty"`   // exact command, with variables expanded
+	Match  string `yaml:"match,omitempty"` // or a regular expression it must match
+	Output string `yaml:"output,omitempty"`
+	Exit   int    `yaml:"exit,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// matches reports whether command is the one expected.
+func (m MockCommand) matches(command string) bool {
+	if m.re != nil {
+		return m.re.MatchString(command)
+	}
+	return m.Run == command
+}
+
+func (m MockCommand) String() string {
+	if m.re != nil {
+		return "/" + m.Match + "/"
+	}
+	return fmt.Sprintf("%q", m.Run)
+}
+
+// TestResult is the outcome of one test case.
+type TestResult struct {
+	Name     string
+	Failures []string
+	Duration time.Duration
+	Log      string // the run's progress output and mocked command output
+}
+
+// Passed reports whether the test case passed.
+func (r TestResult) Passed() bool { return len(r.Failures) == 0 }
+
+// LoadTestSpec reads and validates a mock spec.
+func LoadTestSpec(path string) (*TestSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec TestSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if len(spec.Tests) == 0 {
+		return nil, fmt.Errorf("%s has no tests", path)
+	}
+	for i := range spec.Tests {
+		tc := &spec.Tests[i]
+		if tc.Name == "" {
+			tc.Name = fmt.Sprintf("test %d", i+1)
+		}
+		switch tc.Result {
+		case "":
+			tc.Result = "success"
+		case "success", "failure":
+		default:
+			return nil, fmt.Errorf("%s: result must be success or failure, not %q", tc.Name, tc.Result)
+		}
+		for j := range tc.Expect {
+			m := &tc.Expect[j]
+			switch {
+			case m.Run != "" && m.Match != "":
+				return nil, fmt.Errorf("%s: expect %d sets both run and match", tc.Name, j+1)
+			case m.Match != "":
+				if m.re, err = regexp.Compile(m.Match); err != nil {
+					return nil, fmt.Errorf("%s: expect %d: %w", tc.Name, j+1, err)
+				}
+			case m.Run == "":
+				return nil, fmt.Errorf("%s: expect %d needs run or match", tc.Name, j+1)
+			}
+		}
+	}
+	return &spec, nil
+}
+
+// RunTest runs wf with every command answered by the next expected mock
+// instead of executed, and reports where the run strayed from tc.
+func RunTest(ctx context.Context, wf *Workflow, tc TestCase) TestResult {
+	start := time.Now()
+	res := TestResult{Name: tc.Name}
+	fail := func(format string, args ...any) {
+		res.Failures = append(res.Failures, fmt.Sprintf(format, args...))
+	}
+
+	var log bytes.Buffer
+	next := 0
+	runner := &Runner{
+		Exec: func(ctx context.Context, command string, vars map[string]string) error {
+			command = Expand(command, vars)
+			if next >= len(tc.Expect) {
+				fail("unexpected command %q", command)
+				return fmt.Errorf("unexpected command")
+			}
+			m := tc.Expect[next]
+			next++
+			if !m.matches(command) {
+				fail("command %d: ran %q, expected %s", next, command, m)
+			}
+			if m.Output != "" {
+				fmt.Fprintf(&log, "%s\n", m.Output)
+			}
+			if m.Exit != 0 {
+				return fmt.Errorf("exited with status %d", m.Exit)
+			}
+			return nil
+		},
+		Approve: func(session.Step) bool { return tc.Approve == nil || *tc.Approve },
+		Retry:   func(session.Step, error) bool { return tc.Retry == nil || *tc.Retry },
+		Out:     &log,
+	}
+
+	vars, err := ResolveVariables(wf, tc.Variables)
+	if err == nil {
+		_, err = runner.Run(ctx, wf, vars)
+	}
+
+	for _, m := range tc.Expect[next:] {
+		fail("expected command %s was never run", m)
+	}
+
+	var stepErr *StepError
+	switch {
+	case err == nil && tc.Result == "failure":
+		fail("workflow succeeded, expected it to fail")
+	case err != nil && tc.Result == "success":
+		fail("workflow failed: %v", err)
+	case err != nil && tc.FailedStep > 0:
+		if !errors.As(err, &stepErr) {
+			fail("workflow failed before step %d: %v", tc.FailedStep, err)
+		} else if stepErr.Order != tc.FailedStep {
+			fail("step %d failed, expected step %d", stepErr.Order, tc.FailedStep)
+		}
+	}
+
+	res.Duration = time.Since(start)
+	res.Log = log.String()
+	return res
+}
+
+// junitSuite is the JUnit XML report format CI systems read.
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML test suite named after the
+// workflow.
+func WriteJUnit(w io.Writer, suite string, results []TestResult) error {
+	s := junitSuite{Name: suite, Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		c := junitCase{
+			Name:      r.Name,
+			ClassName: suite,
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+			SystemOut: r.Log,
+		}
+		if !r.Passed() {
+			s.Failures++
+			body := ""
+			for _, f := range r.Failures {
+				body += f + "\n"
+			}
+			c.Failure = &junitFailure{Message: r.Failures[0], Body: body}
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	s.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+func deployWorkflow() *Workflow {
+	return &Workflow{
+		ID:   "deploy",
+		Name: "deploy",
+		Variables: []session.Variable{
+			{Name: "env", Required: true},
+			{Name: "tag", Default: "latest"},
+		},
+		Steps: []session.Step{
+			{Order: 1, Description: "build", Command: "make build", OnFailure: "abort"},
+			{Order: 2, Description: "lint", Command: "make lint", OnFailure: "skip"},
+			{Order: 3, Description: "push", Command: "docker push app:${TAG}", OnFailure: "retry"},
+			{Order: 4, Description: "apply", Command: "kubectl apply -f k8s/$ENV", NeedsApproval: true, OnFailure: "abort"},
+		},
+	}
+}
+
+func writeSpec(t *testing.T, spec string) *TestSpec {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadTestSpec(path)
+	if err != nil {
+		t.Fatalf("LoadTestSpec: %v", err)
+	}
+	return s
+}
+
+func TestRunTest(t *testing.T) {
+	spec := writeSpec(t, `
+tests:
+  - name: happy path
+    variables: {env: staging}
+    expect:
+      - run: make build
+      - run: make lint
+        exit: 1
+      - run: docker push app:latest
+      - match: "^kubectl apply -f k8s/staging$"
+  - name: push retried then aborted
+    variables: {ENV: prod}
+    expect:
+      - run: make build
+      - run: make lint
+      - run: docker push app:latest
+        exit: 1
+      - run: docker push app:latest
+        exit: 1
+    result: failure
+    failed_step: 3
+  - name: approval declined
+    variables: {env: prod, tag: v2}
+    approve: false
+    expect:
+      - run: make build
+      - run: make lint
+      - run: docker push app:v2
+  - name: wrong order
+    variables: {env: prod}
+    expect:
+      - run: make lint
+      - run: make build
+      - run: docker push app:latest
+  - name: missing variable
+    expect:
+      - run: make build
+`)
+	wf := deployWorkflow()
+	var results []TestResult
+	for _, tc := range spec.Tests {
+		results = append(results, RunTest(context.Background(), wf, tc))
+	}
+
+	for _, r := range results[:3] {
+		if !r.Passed() {
+			t.Errorf("%s failed: %v\n%s", r.Name, r.Failures, r.Log)
+		}
+	}
+
+	order := results[3]
+	if order.Passed() || !strings.Contains(order.Failures[0], `ran "make build", expected "make lint"`) {
+		t.Errorf("wrong order failures = %v", order.Failures)
+	}
+	// The approval step ran but wasn't expected
+	if !strings.Contains(strings.Join(order.Failures, "\n"), `unexpected command "kubectl apply -f k8s/prod"`) {
+		t.Errorf("wrong order failures = %v", order.Failures)
+	}
+
+	missing := results[4]
+	if len(missing.Failures) != 2 || !strings.Contains(missing.Failures[1], "ENV") {
+		t.Errorf("missing variable failures = %v", missing.Failures)
+	}
+}
+
+func TestLoadTestSpecValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	for _, bad := range []string{
+		"tests: []",
+		"tests:\n  - expect:\n      - match: '('",
+		"tests:\n  - expect:\n      - output: hi",
+		"tests:\n  - result: maybe",
+	} {
+		_ = os.WriteFile(path, []byte(bad), 0644)
+		if _, err := LoadTestSpec(path); err == nil {
+			t.Errorf("LoadTestSpec(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var b strings.Builder
+	err := WriteJUnit(&b, "deploy", []TestResult{
+		{Name: "ok"},
+		{Name: "bad", Failures: []string{"step 2 failed, expected step 1"}, Log: "Step 1: build"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`<testsuite name="deploy" tests="2" failures="1"`,
+		`<testcase name="ok" classname="deploy"`,
+		`<failure message="step 2 failed, expected step 1">`,
+		`<system-out>Step 1: build</system-out>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %s:\n%s", want, out)
+		}
+	}
+}
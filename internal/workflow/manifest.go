@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PermissionManifest is the server-signed grant that comes with a workflow
+// pulled from a team. It pins the permission of one user on one workflow and
+// is what local commands enforce.
+type PermissionManifest struct {
+	WorkflowID string     `json:"workflow_id"`
+	TeamID     string     `json:"team_id"`
+	UserEmail  string     `json:"user_email"`
+	Permission Permission `json:"permission"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	ExpiresAt  time.Time  `json:"expires_at,omitempty"`
+	Signature  string     `json:"signature"` // base64 ed25519 over SigningPayload()
+}
+
+// CanViewSteps reports whether the permission allows seeing step commands.
+func (p Permission) CanViewSteps() bool {
+	return p == PermissionRead || p == PermissionWrite
+}
+
+// CanEdit reports whether the permission allows modifying the workflow.
+func (p Permission) CanEdit() bool {
+	return p == PermissionWrite
+}
+
+// SigningPayload returns the bytes the server signs: the manifest as JSON
+// with an empty signature.
+func (m PermissionManifest) SigningPayload() ([]byte, error) {
+	m.Signature = ""
+	return json.Marshal(m)
+}
+
+// Verify checks the manifest signature against a base64 ed25519 public key
+// and that it was issued to userEmail and has not expired.
+func (m *PermissionManifest) Verify(publicKey, userEmail string, now time.Time) error {
+	if strings.TrimSpace(publicKey) == "" {
+		return fmt.Errorf("no permission key pinned; run 'mur workflows sync' first")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid permission public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature encoding: %w", err)
+	}
+	payload, err := m.SigningPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), payload, sig) {
+		return fmt.Errorf("permission manifest for %s failed signature verification", m.WorkflowID)
+	}
+	if !ValidPermission(string(m.Permission)) {
+		return fmt.Errorf("permission manifest for %s has invalid permission %q", m.WorkflowID, m.Permission)
+	}
+	if !strings.EqualFold(m.UserEmail, userEmail) {
+		return fmt.Errorf("permission manifest for %s was issued to %s, not the current user", m.WorkflowID, m.UserEmail)
+	}
+	if !m.ExpiresAt.IsZero() && now.After(m.ExpiresAt) {
+		return fmt.Errorf("permission manifest for %s expired on %s; run 'mur workflows sync'", m.WorkflowID, m.ExpiresAt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// manifestPath returns the path to a workflow's permission manifest.
+func manifestPath(workflowID string) (string, error) {
+	dir, err := workflowDir(workflowID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "manifest.json"), nil
+}
+
+// LoadManifest returns the stored permission manifest for a workflow, or nil
+// if the workflow was created locally.
+func LoadManifest(workflowID string) (*PermissionManifest, error) {
+	path, err := manifestPath(workflowID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m PermissionManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse permission manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func saveManifest(m *PermissionManifest) error {
+	path, err := manifestPath(m.WorkflowID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Access returns the effective permission of userEmail on a workflow.
+// Workflows created locally grant full access. Workflows pulled from the
+// cloud are limited to their manifest, which must verify against publicKey.
+func Access(workflowID, publicKey, userEmail string) (Permission, error) {
+	m, err := LoadManifest(workflowID)
+	if err != nil {
+		return "", err
+	}
+	if m == nil {
+		return PermissionWrite, nil
+	}
+	if m.WorkflowID != workflowID {
+		return "", fmt.Errorf("permission manifest does not belong to workflow %s", workflowID)
+	}
+	if err := m.Verify(publicKey, userEmail, time.Now()); err != nil {
+		return "", err
+	}
+	return m.Permission, nil
+}
+
+// ApplyPulled stores a workflow pulled from the cloud together with its
+// permission manifest, replacing any existing local copy.
+func ApplyPulled(payload WorkflowSyncPayload, m *PermissionManifest) error {
+	if m == nil || m.WorkflowID != payload.ID {
+		return fmt.Errorf("workflow %s has no permission manifest", payload.ID)
+	}
+
+	var wf Workflow
+	if err := yaml.Unmarshal(payload.WorkflowData, &wf); err != nil {
+		return fmt.Errorf("parse pulled workflow %s: %w", payload.ID, err)
+	}
+	wf.ID = payload.ID
+
+	dir, err := workflowDir(wf.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "revisions"), 0755); err != nil {
+		return fmt.Errorf("create workflow directory: %w", err)
+	}
+	if err := writeWorkflowFile(dir, &wf); err != nil {
+		return err
+	}
+
+	meta, err := readMetadata(dir)
+	if err != nil {
+		meta = &Metadata{ID: wf.ID, CreatedAt: payload.CreatedAt}
+	}
+	meta.Name = wf.Name
+	meta.UpdatedAt = payload.UpdatedAt
+	meta.PublishedVersion = payload.PublishedVer
+	meta.RevisionCount++
+	if err := writeMetadata(dir, meta); err != nil {
+		return err
+	}
+	if err := saveManifest(m); err != nil {
+		return err
+	}
+
+	return updateIndex(&wf, meta)
+}
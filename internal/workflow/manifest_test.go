@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func signManifest(t *testing.T, priv ed25519.PrivateKey, m *PermissionManifest) {
+	t.Helper()
+	payload, err := m.SigningPayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+}
+
+func TestAccess_PulledWorkflow(t *testing.T) {
+	setWorkflowsDir(t)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	wf := sampleWorkflow("wf-pulled")
+	data, _ := yaml.Marshal(wf)
+	payload := WorkflowSyncPayload{ID: wf.ID, Name: wf.Name, WorkflowData: data, UpdatedAt: time.Now()}
+
+	m := &PermissionManifest{
+		WorkflowID: wf.ID,
+		TeamID:     "acme",
+		UserEmail:  "alice@example.com",
+		Permission: PermissionExecuteOnly,
+		IssuedAt:   time.Now(),
+	}
+	signManifest(t, priv, m)
+
+	if err := ApplyPulled(payload, m); err != nil {
+		t.Fatalf("ApplyPulled() error: %v", err)
+	}
+	if got, _, err := Get(wf.ID); err != nil || len(got.Steps) != 2 {
+		t.Fatalf("pulled workflow not stored: %v", err)
+	}
+
+	perm, err := Access(wf.ID, key, "Alice@example.com")
+	if err != nil {
+		t.Fatalf("Access() error: %v", err)
+	}
+	if perm != PermissionExecuteOnly || perm.CanViewSteps() || perm.CanEdit() {
+		t.Errorf("permission = %q, want execute-only without view/edit", perm)
+	}
+
+	if _, err := Access(wf.ID, key, "bob@example.com"); err == nil {
+		t.Error("expected error for a different user")
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, err := Access(wf.ID, base64.StdEncoding.EncodeToString(otherPub), "alice@example.com"); err == nil {
+		t.Error("expected error for a different signing key")
+	}
+
+	// Tampering with the stored grant must break verification
+	m.Permission = PermissionWrite
+	if err := saveManifest(m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Access(wf.ID, key, "alice@example.com"); err == nil {
+		t.Error("expected error for a tampered manifest")
+	}
+}
+
+func TestAccess_LocalAndExpired(t *testing.T) {
+	setWorkflowsDir(t)
+
+	if err := Create(sampleWorkflow("wf-local")); err != nil {
+		t.Fatal(err)
+	}
+	perm, err := Access("wf-local", "", "")
+	if err != nil || perm != PermissionWrite {
+		t.Errorf("local workflow access = %q, %v; want write", perm, err)
+	}
+
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	m := &PermissionManifest{
+		WorkflowID: "wf-old",
+		UserEmail:  "alice@example.com",
+		Permission: PermissionRead,
+		ExpiresAt:  time.Now().Add(-time.Hour),
+	}
+	signManifest(t, priv, m)
+	if err := m.Verify(base64.StdEncoding.EncodeToString(pub), "alice@example.com", time.Now()); err == nil {
+		t.Error("expected error for an expired manifest")
+	}
+	if err := ApplyPulled(WorkflowSyncPayload{ID: "wf-none"}, nil); err == nil {
+		t.Error("expected error when the manifest is missing")
+	}
+}
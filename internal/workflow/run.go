@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/session"
+)
+
+// Step statuses reported by Runner.
+const (
+	StepRan     = "ran"
+	StepSkipped = "skipped"
+	StepFailed  = "failed"
+	StepManual  = "manual"
+)
+
+// StepResult is what happened to one step of a run.
+type StepResult struct {
+	Order   int
+	Command string
+	Status  string
+	Err     error
+}
+
+// StepError is returned when a failing step aborts a run.
+type StepError struct {
+	Order int
+	Err   error
+}
+
+func (e *StepError) Error() string { return fmt.Sprintf("step %d failed: %v", e.Order, e.Err) }
+
+func (e *StepError) Unwrap() error { return e.Err }
+
+// Runner executes a workflow's steps in order, handling approval and
+// on_failure. Commands and prompts go through its callbacks, so 'mur
+// workflows run' executes them and 'mur workflows test' mocks them.
+type Runner struct {
+	// Exec runs a step's command with the resolved variables, which the
+	// command reads as $NAME (see EnvName).
+	Exec func(ctx context.Context, command string, vars map[string]string) error
+	// Approve answers approval prompts; nil approves every step.
+	Approve func(step session.Step) bool
+	// Retry answers whether to retry a step that failed with on_failure
+	// retry; nil never retries.
+	Retry func(step session.Step, err error) bool
+	// Show returns how a command is logged, e.g. hidden from
+	// execute-only users; nil logs it as is.
+	Show func(command string) string
+	// Out receives progress; nil discards it.
+	Out    io.Writer
+	DryRun bool
+}
+
+// EnvName returns the environment variable a workflow variable is passed
+// to commands as, the same as in exported run.sh scripts.
+func EnvName(name string) string {
+	return strings.ToUpper(name)
+}
+
+// ResolveVariables returns the value of each workflow variable: given by
+// name or EnvName, else its default. A required variable without a
+// value is an error.
+func ResolveVariables(wf *Workflow, given map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(wf.Variables))
+	var missing []string
+	for _, v := range wf.Variables {
+		value, ok := given[v.Name]
+		if !ok {
+			value, ok = given[EnvName(v.Name)]
+		}
+		if !ok || value == "" {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			missing = append(missing, EnvName(v.Name))
+			continue
+		}
+		vars[EnvName(v.Name)] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("required variables not set: %s", strings.Join(missing, ", "))
+	}
+	return vars, nil
+}
+
+// Expand substitutes $NAME and ${NAME} for the variables in vars the way
+// the shell would, leaving other references alone.
+func Expand(command string, vars map[string]string) string {
+	return os.Expand(command, func(name string) string {
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return "${" + name + "}"
+	})
+}
+
+// Run runs the steps of wf. The returned error is a *StepError when a step
+// failed and its on_failure didn't let the run continue.
+func (r *Runner) Run(ctx context.Context, wf *Workflow, vars map[string]string) ([]StepResult, error) {
+	out := r.Out
+	if out == nil {
+		out = io.Discard
+	}
+	show := r.Show
+	if show == nil {
+		show = func(command string) string { return command }
+	}
+
+	var results []StepResult
+	for _, step := range wf.Steps {
+		fmt.Fprintf(out, "Step %d: %s\n", step.Order, step.Description)
+		res := StepResult{Order: step.Order, Command: step.Command}
+
+		if step.NeedsApproval && !r.DryRun && r.Approve != nil && !r.Approve(step) {
+			fmt.Fprintf(out, "  Skipped.\n\n")
+			res.Status = StepSkipped
+			results = append(results, res)
+			continue
+		}
+
+		if step.Command == "" {
+			if step.Tool != "" {
+				fmt.Fprintf(out, "  (manual step, tool: %s)\n\n", step.Tool)
+			} else {
+				fmt.Fprintf(out, "  (manual step)\n\n")
+			}
+			res.Status = StepManual
+			results = append(results, res)
+			continue
+		}
+
+		if r.DryRun {
+			fmt.Fprintf(out, "  [dry-run] $ %s\n\n", show(step.Command))
+			res.Status = StepSkipped
+			results = append(results, res)
+			continue
+		}
+
+		fmt.Fprintf(out, "  $ %s\n", show(step.Command))
+		res.Status = StepRan
+		if err := r.Exec(ctx, step.Command, vars); err != nil {
+			res.Status, res.Err = StepFailed, err
+			switch step.OnFailure {
+			case "skip":
+				fmt.Fprintf(out, "  Failed (skipping): %v\n\n", err)
+				results = append(results, res)
+				continue
+			case "retry":
+				fmt.Fprintf(out, "  Failed: %v\n", err)
+				if r.Retry == nil || !r.Retry(step, err) {
+					return append(results, res), &StepError{Order: step.Order, Err: err}
+				}
+				if err := r.Exec(ctx, step.Command, vars); err != nil {
+					res.Err = err
+					return append(results, res), &StepError{Order: step.Order, Err: fmt.Errorf("on retry: %w", err)}
+				}
+				res.Status, res.Err = StepRan, nil
+			default: // abort
+				return append(results, res), &StepError{Order: step.Order, Err: err}
+			}
+		}
+		fmt.Fprintln(out)
+		results = append(results, res)
+	}
+	return results, nil
+}
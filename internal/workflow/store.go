@@ -3,6 +3,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/mur-run/mur-core/internal/config"
 	"os"
 	"path/filepath"
 	"sort"
@@ -20,7 +21,7 @@ func defaultWorkflowsDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("cannot determine home directory: %w", err)
 	}
-	return filepath.Join(home, ".mur", "workflows"), nil
+	return filepath.Join(config.DataDir(home), "workflows"), nil
 }
 
 func workflowsDir() (string, error) {
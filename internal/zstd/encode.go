@@ -0,0 +1,385 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Compress returns src as a single zstd frame with a content checksum.
+//
+// It is a small, fast compressor for mur's own files rather than a general
+// one: matches come from a single-entry hash table, literals are stored
+// raw, and sequences use the predefined FSE tables, so no tables are ever
+// written. Text such as pattern YAML typically shrinks to a third or less.
+// The output is standard zstd and the zstd command line tool reads it.
+func Compress(src []byte) []byte {
+	dst := make([]byte, 0, len(src)/2+32)
+	dst = appendFrameHeader(dst, uint64(len(src)))
+
+	e := encoder{table: make([]int32, 1<<hashLog)}
+	for i := range e.table {
+		e.table[i] = -1
+	}
+	if len(src) == 0 {
+		dst = appendBlockHeader(dst, true, blockRaw, 0)
+	}
+	for start := 0; start < len(src); start += maxBlockSize {
+		end := min(start+maxBlockSize, len(src))
+		dst = e.appendBlock(dst, src, start, end, end == len(src))
+	}
+
+	var xh xxhash64
+	xh.reset()
+	xh.update(src)
+	return binary.LittleEndian.AppendUint32(dst, uint32(xh.digest()))
+}
+
+const (
+	frameMagic   = 0xfd2fb528
+	maxBlockSize = 128 << 10
+	hashLog      = 16
+	minMatch     = 4
+	// maxOffset keeps offset codes within the predefined table.
+	maxOffset = 1<<28 - 4
+
+	blockRaw        = 0
+	blockCompressed = 2
+)
+
+func appendFrameHeader(dst []byte, size uint64) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, frameMagic)
+
+	// Single segment, so the window is the content size and there is no
+	// window descriptor; the content size field is as small as fits.
+	const singleSegment, checksum = 1 << 5, 1 << 2
+	switch {
+	case size < 256:
+		dst = append(dst, singleSegment|checksum, byte(size))
+	case size < 65536+256:
+		dst = append(dst, 1<<6|singleSegment|checksum)
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(size-256))
+	case size <= 1<<32-1:
+		dst = append(dst, 2<<6|singleSegment|checksum)
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(size))
+	default:
+		dst = append(dst, 3<<6|singleSegment|checksum)
+		dst = binary.LittleEndian.AppendUint64(dst, size)
+	}
+	return dst
+}
+
+func appendBlockHeader(dst []byte, last bool, blockType, size int) []byte {
+	h := uint32(size)<<3 | uint32(blockType)<<1
+	if last {
+		h |= 1
+	}
+	return append(dst, byte(h), byte(h>>8), byte(h>>16))
+}
+
+// sequence is a run of literals followed by a match.
+type sequence struct {
+	litLen   uint32
+	matchLen uint32
+	offset   uint32
+}
+
+type encoder struct {
+	// table maps a hash of four bytes to the last position they were seen.
+	table []int32
+	seqs  []sequence
+	lits  []byte
+}
+
+func hash4(b []byte) uint32 {
+	return (binary.LittleEndian.Uint32(b) * 2654435761) >> (32 - hashLog)
+}
+
+// appendBlock compresses src[start:end], finding matches anywhere in
+// src[:end], and falls back to a raw block when that doesn't pay off.
+func (e *encoder) appendBlock(dst, src []byte, start, end int, last bool) []byte {
+	e.seqs = e.seqs[:0]
+	e.lits = e.lits[:0]
+
+	anchor := start
+	for i := start; i+minMatch <= end; {
+		h := hash4(src[i:])
+		cand := int(e.table[h])
+		e.table[h] = int32(i)
+		if cand < 0 || i-cand > maxOffset || binary.LittleEndian.Uint32(src[cand:]) != binary.LittleEndian.Uint32(src[i:]) {
+			i++
+			continue
+		}
+
+		// Extend the match both ways, staying within the block
+		n := minMatch
+		for i+n < end && src[cand+n] == src[i+n] {
+			n++
+		}
+		for i > anchor && cand > 0 && src[cand-1] == src[i-1] {
+			i--
+			cand--
+			n++
+		}
+
+		e.lits = append(e.lits, src[anchor:i]...)
+		e.seqs = append(e.seqs, sequence{litLen: uint32(i - anchor), matchLen: uint32(n), offset: uint32(i - cand)})
+		for j := i + 1; j < i+n && j+minMatch <= end; j += 2 {
+			e.table[hash4(src[j:])] = int32(j)
+		}
+		i += n
+		anchor = i
+	}
+	e.lits = append(e.lits, src[anchor:end]...)
+
+	body := appendLiterals(nil, e.lits)
+	body = appendSequences(body, e.seqs)
+	if len(e.seqs) == 0 || len(body) >= end-start {
+		dst = appendBlockHeader(dst, last, blockRaw, end-start)
+		return append(dst, src[start:end]...)
+	}
+	dst = appendBlockHeader(dst, last, blockCompressed, len(body))
+	return append(dst, body...)
+}
+
+// appendLiterals appends a raw literals section.
+func appendLiterals(dst, lits []byte) []byte {
+	n := len(lits)
+	switch {
+	case n < 32:
+		dst = append(dst, byte(n<<3))
+	case n < 4096:
+		dst = append(dst, byte(n<<4)|1<<2, byte(n>>4))
+	default:
+		dst = append(dst, byte(n<<4)|3<<2, byte(n>>4), byte(n>>12))
+	}
+	return append(dst, lits...)
+}
+
+// appendSequences appends a sequences section using the predefined FSE
+// tables for every code.
+func appendSequences(dst []byte, seqs []sequence) []byte {
+	n := len(seqs)
+	switch {
+	case n < 128:
+		dst = append(dst, byte(n))
+	case n < 0x7f00:
+		dst = append(dst, byte(n>>8)+128, byte(n))
+	default:
+		dst = append(dst, 255)
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(n-0x7f00))
+	}
+	if n == 0 {
+		return dst
+	}
+	dst = append(dst, 0) // predefined mode for literal lengths, offsets and match lengths
+
+	type coded struct {
+		ll, of, ml             uint8
+		llExtra, ofExtra, mlEx uint32
+		llBits, ofBits, mlBits uint8
+	}
+	codes := make([]coded, n)
+	for i, s := range seqs {
+		c := &codes[i]
+		c.ll, c.llExtra, c.llBits = literalLengthCode(s.litLen)
+		c.ml, c.mlEx, c.mlBits = matchLengthCode(s.matchLen)
+		// Offset values 1-3 are repeat offsets, which this encoder
+		// never uses
+		ofValue := s.offset + 3
+		c.of = uint8(bits.Len32(ofValue) - 1)
+		c.ofBits = c.of
+		c.ofExtra = ofValue - 1<<c.of
+	}
+
+	// Sequences are written last to first, so the decoder, reading the
+	// bitstream backwards, sees them in order
+	var w bitWriter
+	last := codes[n-1]
+	llState := predefinedLiteralCTable.init(last.ll)
+	mlState := predefinedMatchCTable.init(last.ml)
+	ofState := predefinedOffsetCTable.init(last.of)
+	w.add(last.llExtra, last.llBits)
+	w.add(last.mlEx, last.mlBits)
+	w.add(last.ofExtra, last.ofBits)
+	for i := n - 2; i >= 0; i-- {
+		c := codes[i]
+		ofState = predefinedOffsetCTable.encode(&w, ofState, c.of)
+		mlState = predefinedMatchCTable.encode(&w, mlState, c.ml)
+		llState = predefinedLiteralCTable.encode(&w, llState, c.ll)
+		w.add(c.llExtra, c.llBits)
+		w.add(c.mlEx, c.mlBits)
+		w.add(c.ofExtra, c.ofBits)
+	}
+	predefinedMatchCTable.flush(&w, mlState)
+	predefinedOffsetCTable.flush(&w, ofState)
+	predefinedLiteralCTable.flush(&w, llState)
+	return w.close(dst)
+}
+
+func literalLengthCode(ll uint32) (code uint8, extra uint32, nbits uint8) {
+	if ll < literalLengthOffset {
+		return uint8(ll), 0, 0
+	}
+	return lengthCode(ll, literalLengthBase, literalLengthOffset)
+}
+
+func matchLengthCode(ml uint32) (code uint8, extra uint32, nbits uint8) {
+	if ml < matchLengthOffset+3 {
+		return uint8(ml - 3), 0, 0
+	}
+	return lengthCode(ml, matchLengthBase, matchLengthOffset)
+}
+
+// lengthCode finds the last baseline at or below v in a decoder baseline
+// table, whose entries are baseline | bits<<24.
+func lengthCode(v uint32, base []uint32, first int) (code uint8, extra uint32, nbits uint8) {
+	i := len(base) - 1
+	for i > 0 && base[i]&0xffffff > v {
+		i--
+	}
+	b := base[i] & 0xffffff
+	return uint8(first + i), v - b, uint8(base[i] >> 24)
+}
+
+// bitWriter collects bits least significant first, as the decoder's
+// reverseBitReader expects once the stream is closed.
+type bitWriter struct {
+	out   []byte
+	acc   uint64
+	count uint8
+}
+
+func (w *bitWriter) add(v uint32, n uint8) {
+	if n == 0 {
+		return
+	}
+	w.acc |= uint64(v&(1<<n-1)) << w.count
+	w.count += n
+	for w.count >= 8 {
+		w.out = append(w.out, byte(w.acc))
+		w.acc >>= 8
+		w.count -= 8
+	}
+}
+
+// close appends the stream, ending it with a 1 bit and zero padding so the
+// decoder can find where it starts.
+func (w *bitWriter) close(dst []byte) []byte {
+	w.add(1, 1)
+	if w.count > 0 {
+		w.out = append(w.out, byte(w.acc))
+	}
+	return append(dst, w.out...)
+}
+
+// fseCTable is an FSE encoding table built from a normalized distribution,
+// spread the same way as the decoder's.
+type fseCTable struct {
+	tableLog   uint8
+	stateTable []uint16
+	// Per symbol transforms, as in the reference encoder
+	deltaNbBits    []uint32
+	deltaFindState []int32
+}
+
+func newFSECTable(norm []int16, tableLog uint8) *fseCTable {
+	size := 1 << tableLog
+	mask := size - 1
+	symbols := make([]uint8, size)
+
+	// Symbols with probability "less than 1" take the last cells
+	high := size - 1
+	for s, c := range norm {
+		if c == -1 {
+			symbols[high] = uint8(s)
+			high--
+		}
+	}
+	step := size>>1 + size>>3 + 3
+	pos := 0
+	for s, c := range norm {
+		for i := 0; i < int(c); i++ {
+			symbols[pos] = uint8(s)
+			pos = (pos + step) & mask
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+
+	cumul := make([]int, len(norm)+1)
+	for s, c := range norm {
+		if c == -1 {
+			c = 1
+		}
+		cumul[s+1] = cumul[s] + int(c)
+	}
+	t := &fseCTable{
+		tableLog:       tableLog,
+		stateTable:     make([]uint16, size),
+		deltaNbBits:    make([]uint32, len(norm)),
+		deltaFindState: make([]int32, len(norm)),
+	}
+	next := append([]int(nil), cumul...)
+	for u, s := range symbols {
+		t.stateTable[next[s]] = uint16(size + u)
+		next[s]++
+	}
+
+	total := 0
+	for s, c := range norm {
+		switch c {
+		case 0:
+			t.deltaNbBits[s] = uint32(tableLog+1)<<16 - uint32(size)
+		case -1, 1:
+			t.deltaNbBits[s] = uint32(tableLog)<<16 - uint32(size)
+			t.deltaFindState[s] = int32(total - 1)
+			total++
+		default:
+			maxBitsOut := uint32(tableLog) - uint32(bits.Len16(uint16(c-1))-1)
+			t.deltaNbBits[s] = maxBitsOut<<16 - uint32(c)<<maxBitsOut
+			t.deltaFindState[s] = int32(total - int(c))
+			total += int(c)
+		}
+	}
+	return t
+}
+
+// init returns the first state, for the last symbol written.
+func (t *fseCTable) init(sym uint8) uint32 {
+	nbBitsOut := (t.deltaNbBits[sym] + 1<<15) >> 16
+	value := nbBitsOut<<16 - t.deltaNbBits[sym]
+	return uint32(t.stateTable[int32(value>>nbBitsOut)+t.deltaFindState[sym]])
+}
+
+// encode writes the bits that lead from the state for sym to state and
+// returns the state for sym.
+func (t *fseCTable) encode(w *bitWriter, state uint32, sym uint8) uint32 {
+	nbBitsOut := uint8((state + t.deltaNbBits[sym]) >> 16)
+	w.add(state, nbBitsOut)
+	return uint32(t.stateTable[int32(state>>nbBitsOut)+t.deltaFindState[sym]])
+}
+
+// flush writes the final state, which the decoder reads first.
+func (t *fseCTable) flush(w *bitWriter, state uint32) {
+	w.add(state, t.tableLog)
+}
+
+// Predefined distributions, RFC 8878 section 3.1.1.3.2.2.
+var (
+	predefinedLiteralCTable = newFSECTable([]int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}, 6)
+	predefinedMatchCTable = newFSECTable([]int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}, 6)
+	predefinedOffsetCTable = newFSECTable([]int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}, 5)
+)
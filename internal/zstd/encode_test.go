@@ -0,0 +1,65 @@
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func decompress(t *testing.T, data []byte) []byte {
+	t.Helper()
+	out, err := io.ReadAll(NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	return out
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := make([]byte, 5000)
+	rng.Read(random)
+
+	var yaml strings.Builder
+	for i := 0; i < 4000; i++ {
+		fmt.Fprintf(&yaml, "- name: pattern-%d\n  content: |\n    Wrap errors with fmt.Errorf and %%w (%d).\n", i, i%7)
+	}
+
+	cases := map[string][]byte{
+		"empty":  nil,
+		"short":  []byte("hi"),
+		"runs":   bytes.Repeat([]byte("a"), 300000),
+		"random": random,
+		"yaml":   []byte(yaml.String()),
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := decompress(t, Compress(src))
+			if !bytes.Equal(got, src) {
+				t.Fatalf("round trip changed %d bytes into %d", len(src), len(got))
+			}
+		})
+	}
+
+	if c := Compress([]byte(yaml.String())); len(c)*3 > yaml.Len() {
+		t.Errorf("yaml compressed to %d of %d bytes", len(c), yaml.Len())
+	}
+}
+
+func TestCompressLengthCodes(t *testing.T) {
+	// Literal and match lengths across every code, separated by noise
+	rng := rand.New(rand.NewSource(2))
+	var src []byte
+	for _, n := range []int{1, 15, 16, 17, 63, 64, 65, 1000, 5000, 70000} {
+		noise := make([]byte, n)
+		rng.Read(noise)
+		src = append(src, noise...)
+		src = append(src, bytes.Repeat([]byte("xyz"), n)...)
+	}
+	if got := decompress(t, Compress(src)); !bytes.Equal(got, src) {
+		t.Fatal("round trip mismatch")
+	}
+}
@@ -0,0 +1,62 @@
+// Command sign-checksums signs a release's checksums.txt for mur upgrade.
+// GoReleaser runs it from the signs step:
+//
+//	go run ./scripts/sign-checksums checksums.txt checksums.txt.sig
+//
+// The key is MUR_RELEASE_SIGNING_KEY, a base64 ed25519 private key (or
+// its 32-byte seed). The signature is written base64-encoded, the format
+// selfupdate.VerifySignature reads, and checked against the public key
+// before the release goes out.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mur-run/mur-core/internal/selfupdate"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "sign-checksums: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: sign-checksums <checksums.txt> <signature>")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(os.Getenv("MUR_RELEASE_SIGNING_KEY")))
+	if err != nil {
+		return fmt.Errorf("invalid MUR_RELEASE_SIGNING_KEY: %w", err)
+	}
+	var key ed25519.PrivateKey
+	switch len(raw) {
+	case ed25519.SeedSize:
+		key = ed25519.NewKeyFromSeed(raw)
+	case ed25519.PrivateKeySize:
+		key = ed25519.PrivateKey(raw)
+	default:
+		return fmt.Errorf("MUR_RELEASE_SIGNING_KEY is not set or not an ed25519 key")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+
+	// The key built into mur must accept it, or every upgrade would fail
+	public := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	if want := strings.TrimSpace(os.Getenv("MUR_RELEASE_PUBLIC_KEY")); want != public {
+		return fmt.Errorf("MUR_RELEASE_PUBLIC_KEY doesn't match the signing key")
+	}
+	if err := selfupdate.VerifySignature(public, data, []byte(sig)); err != nil {
+		return err
+	}
+	return os.WriteFile(args[1], []byte(sig+"\n"), 0644)
+}